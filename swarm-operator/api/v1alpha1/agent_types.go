@@ -49,6 +49,10 @@ const (
 	AdaptivePattern    CognitivePattern = "adaptive"
 )
 
+// AppliedConfigGenerationAnnotation is set by agents on their pod to the
+// generation of the live config they applied
+const AppliedConfigGenerationAnnotation = "swarm.claudeflow.io/applied-config-generation"
+
 // AgentSpec defines the desired state of Agent
 type AgentSpec struct {
 	// Type defines the agent type
@@ -74,6 +78,15 @@ type AgentSpec struct {
 
 	// CommunicationEndpoints for inter-agent communication
 	CommunicationEndpoints CommunicationSpec `json:"communication,omitempty"`
+
+	// Unschedulable stops new tasks from being assigned to the agent. The
+	// operator sets it when it drains the agent for scale-down.
+	Unschedulable bool `json:"unschedulable,omitempty"`
+
+	// External marks an agent launched outside the cluster that registered
+	// itself. The operator runs no Deployment for it and dispatches tasks
+	// to its communication address.
+	External bool `json:"external,omitempty"`
 }
 
 // TaskAffinityRule defines task affinity rules
@@ -108,6 +121,10 @@ type CommunicationSpec struct {
 
 	// BroadcastEnabled allows broadcasting to all peers
 	BroadcastEnabled bool `json:"broadcastEnabled,omitempty"`
+
+	// Address is the host:port external agents receive tasks on
+	// +optional
+	Address string `json:"address,omitempty"`
 }
 
 // AgentStatus defines the observed state of Agent
@@ -136,6 +153,48 @@ type AgentStatus struct {
 
 	// CommunicationStatus with peers
 	CommunicationStatus map[string]PeerStatus `json:"communicationStatus,omitempty"`
+
+	// Pool is the agent-type StatefulSet running this agent in pooled mode
+	Pool string `json:"pool,omitempty"`
+
+	// PoolSlot is the pod ordinal in Pool assigned to this agent
+	PoolSlot *int32 `json:"poolSlot,omitempty"`
+
+	// Drain reports the progress of draining the agent before it is
+	// removed
+	Drain *AgentDrainStatus `json:"drain,omitempty"`
+
+	// Config reports the live config published to the agent and the
+	// generation its pods applied
+	Config *AgentConfigStatus `json:"config,omitempty"`
+}
+
+// AgentConfigStatus is the rollout of the live config of an agent
+type AgentConfigStatus struct {
+	// Generation of the published config
+	Generation int64 `json:"generation"`
+
+	// AppliedGeneration is the oldest generation applied by a pod of the
+	// agent, 0 while none applied a config
+	AppliedGeneration int64 `json:"appliedGeneration"`
+
+	// PublishedTime is when the config generation was published
+	PublishedTime *metav1.Time `json:"publishedTime,omitempty"`
+}
+
+// AgentDrainStatus is the progress of an agent drain
+type AgentDrainStatus struct {
+	// StartTime is when the agent stopped taking new tasks
+	StartTime metav1.Time `json:"startTime"`
+
+	// Deadline is when tasks still running are reassigned
+	Deadline metav1.Time `json:"deadline"`
+
+	// RemainingTasks the agent still runs
+	RemainingTasks int32 `json:"remainingTasks"`
+
+	// ReassignedTasks were taken away from the agent at the deadline
+	ReassignedTasks []string `json:"reassignedTasks,omitempty"`
 }
 
 // TaskReference references a task being processed
@@ -189,6 +248,7 @@ type PeerStatus struct {
 // +kubebuilder:printcolumn:name="Swarm",type="string",JSONPath=".spec.swarmCluster"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
 // +kubebuilder:printcolumn:name="Tasks",type="integer",JSONPath=".status.completedTasks"
+// +kubebuilder:printcolumn:name="Unschedulable",type="boolean",JSONPath=".spec.unschedulable",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Agent is the Schema for the agents API
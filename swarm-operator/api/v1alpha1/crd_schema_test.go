@@ -0,0 +1,335 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSchema(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CRD Schema Suite")
+}
+
+const (
+	crdBases     = "../../config/crd/bases"
+	enhancedCRD  = "../../deploy/crds/enhanced-swarmtask-crd.yaml"
+	durationRule = `^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+)
+
+type object = map[string]interface{}
+
+// readDocuments reads the YAML documents of a file
+func readDocuments(path string) []object {
+	data, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+	var docs []object
+	for _, doc := range bytes.Split(data, []byte("\n---")) {
+		var obj object
+		Expect(yaml.Unmarshal(doc, &obj)).To(Succeed(), path)
+		if obj != nil {
+			docs = append(docs, obj)
+		}
+	}
+	return docs
+}
+
+// crdSchemas returns the v1alpha1 schema of every CRD in the files by kind
+func crdSchemas(paths ...string) map[string]object {
+	schemas := map[string]object{}
+	for _, path := range paths {
+		for _, crd := range readDocuments(path) {
+			spec := crd["spec"].(object)
+			kind := spec["names"].(object)["kind"].(string)
+			for _, version := range spec["versions"].([]interface{}) {
+				version := version.(object)
+				if version["name"] == "v1alpha1" {
+					schemas[kind] = version["schema"].(object)["openAPIV3Schema"].(object)
+				}
+			}
+		}
+	}
+	return schemas
+}
+
+// validate reports where value breaks the structural parts of schema the
+// API server enforces at admission: unknown fields, types, required
+// fields, enums, patterns and minimum lengths. CEL rules are left to the
+// API server.
+func validate(value interface{}, schema object, path string) []string {
+	if schema["x-kubernetes-int-or-string"] == true || schema["x-kubernetes-preserve-unknown-fields"] == true && schema["type"] == nil {
+		return nil
+	}
+	var errs []string
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(object)
+		if !ok {
+			return []string{path + ": must be an object"}
+		}
+		properties, _ := schema["properties"].(object)
+		additional, _ := schema["additionalProperties"].(object)
+		for key, child := range obj {
+			if path == "" && (key == "apiVersion" || key == "kind" || key == "metadata") {
+				continue
+			}
+			if property, ok := properties[key].(object); ok {
+				errs = append(errs, validate(child, property, path+"."+key)...)
+			} else if additional != nil {
+				errs = append(errs, validate(child, additional, path+"."+key)...)
+			} else if schema["x-kubernetes-preserve-unknown-fields"] != true {
+				errs = append(errs, path+"."+key+": unknown field")
+			}
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, key := range required {
+			if _, ok := obj[key.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.%s: required", path, key))
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{path + ": must be an array"}
+		}
+		for i, item := range items {
+			errs = append(errs, validate(item, schema["items"].(object), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{path + ": must be a string"}
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			errs = append(errs, fmt.Sprintf("%s: %q does not match %s", path, s, pattern))
+		}
+		if minLength, ok := schema["minLength"].(float64); ok && float64(len(s)) < minLength {
+			errs = append(errs, path+": too short")
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return []string{path + ": must be a number"}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{path + ": must be a boolean"}
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		allowed := false
+		for _, e := range enum {
+			allowed = allowed || e == value
+		}
+		if !allowed {
+			errs = append(errs, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+	return errs
+}
+
+// walk calls fn with every schema nested in schema and its path
+func walk(schema object, path string, fn func(schema object, path string)) {
+	fn(schema, path)
+	if properties, ok := schema["properties"].(object); ok {
+		for key, property := range properties {
+			walk(property.(object), path+"."+key, fn)
+		}
+	}
+	if items, ok := schema["items"].(object); ok {
+		walk(items, path+"[]", fn)
+	}
+	if additional, ok := schema["additionalProperties"].(object); ok {
+		walk(additional, path+"{}", fn)
+	}
+}
+
+// celEnv mirrors the libraries the API server offers CRD validation rules
+func celEnv() *cel.Env {
+	env, err := cel.NewEnv(cel.Variable("self", cel.DynType), cel.Variable("oldSelf", cel.DynType), ext.Strings())
+	Expect(err).NotTo(HaveOccurred())
+	return env
+}
+
+var _ = Describe("CRD schemas", func() {
+	var bases []string
+
+	BeforeEach(func() {
+		var err error
+		bases, err = filepath.Glob(filepath.Join(crdBases, "*.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("are generated for every kind of the API", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		schemas := crdSchemas(bases...)
+		for gvk := range scheme.AllKnownTypes() {
+			list := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+			if gvk.GroupVersion() == GroupVersion && scheme.Recognizes(list) {
+				Expect(schemas).To(HaveKey(gvk.Kind), "make manifests has not been run for %s", gvk.Kind)
+			}
+		}
+	})
+
+	It("accept the samples", func() {
+		schemas := crdSchemas(bases...)
+		samples, err := filepath.Glob("../../config/samples/swarm_v1alpha1_*.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		for _, sample := range samples {
+			for _, obj := range readDocuments(sample) {
+				schema, ok := schemas[obj["kind"].(string)]
+				Expect(ok).To(BeTrue(), sample)
+				Expect(validate(obj, schema, "")).To(BeEmpty(), sample)
+			}
+		}
+	})
+
+	It("reject misspelled fields and tasks without a description", func() {
+		schema := crdSchemas(bases...)["SwarmTask"]
+		task := object{"spec": object{
+			"swarmCluster":      "swarm",
+			"type":              "development",
+			"persistantVolumes": []interface{}{},
+		}}
+		Expect(validate(task, schema, "")).To(ConsistOf(
+			".spec.persistantVolumes: unknown field",
+			".spec.description: required",
+		))
+	})
+
+	It("only preserve unknown fields of raw pod template patches", func() {
+		for kind, schema := range crdSchemas(bases...) {
+			walk(schema, kind, func(schema object, path string) {
+				if schema["x-kubernetes-preserve-unknown-fields"] == true {
+					Expect(path).To(HaveSuffix(".podTemplateOverrides"))
+				}
+			})
+		}
+	})
+
+	It("compile their validation rules", func() {
+		env := celEnv()
+		rules := 0
+		for kind, schema := range crdSchemas(bases...) {
+			walk(schema, kind, func(schema object, path string) {
+				validations, _ := schema["x-kubernetes-validations"].([]interface{})
+				for _, validation := range validations {
+					rule := validation.(object)["rule"].(string)
+					_, issues := env.Compile(rule)
+					Expect(issues.Err()).NotTo(HaveOccurred(), "%s: %s", path, rule)
+					rules++
+				}
+			})
+		}
+		Expect(rules).To(BeNumerically(">", 5))
+	})
+
+	It("require a cluster on tasks but not on task templates", func() {
+		schemas := crdSchemas(bases...)
+		rule := "has(self.swarmCluster) || has(self.clusterSelector)"
+		spec := schemas["SwarmTask"]["properties"].(object)["spec"].(object)
+		Expect(spec["x-kubernetes-validations"]).To(ContainElement(HaveKeyWithValue("rule", rule)))
+		Expect(spec["required"]).To(ContainElement("description"))
+
+		template := schemas["SwarmTaskBatch"]["properties"].(object)["spec"].(object)["properties"].(object)["template"].(object)
+		Expect(template["x-kubernetes-validations"]).To(BeNil())
+	})
+
+	It("only accept durations CEL can parse", func() {
+		env := celEnv()
+		ast, issues := env.Compile("!has(self.duration) || !has(self.renewBefore) || duration(self.renewBefore) < duration(self.duration)")
+		Expect(issues.Err()).NotTo(HaveOccurred())
+		program, err := env.Program(ast)
+		Expect(err).NotTo(HaveOccurred())
+
+		pattern := regexp.MustCompile(durationRule)
+		for _, d := range []string{"90s", "1h30m", "1.5h", "500ms", "10us", "250ns"} {
+			Expect(pattern.MatchString(d)).To(BeTrue(), d)
+			out, _, err := program.Eval(map[string]interface{}{
+				"self": object{"duration": "2160h", "renewBefore": d},
+			})
+			Expect(err).NotTo(HaveOccurred(), d)
+			Expect(out.Value()).To(BeTrue(), d)
+		}
+		Expect(pattern.MatchString("1µs")).To(BeFalse())
+		Expect(pattern.MatchString("1d")).To(BeFalse())
+
+		// Every duration field of the API uses the same pattern
+		for kind, schema := range crdSchemas(bases...) {
+			walk(schema, kind, func(schema object, path string) {
+				if p, ok := schema["pattern"].(string); ok && strings.Contains(p, "(ns|") {
+					Expect(p).To(Equal(durationRule), path)
+				}
+			})
+		}
+	})
+})
+
+var _ = Describe("Enhanced SwarmTask CRD", func() {
+	var schema object
+
+	BeforeEach(func() {
+		schema = crdSchemas(enhancedCRD)["SwarmTask"]
+		Expect(schema).NotTo(BeNil())
+	})
+
+	It("is fully structural", func() {
+		walk(schema, "SwarmTask", func(schema object, path string) {
+			Expect(schema).NotTo(HaveKey("x-kubernetes-preserve-unknown-fields"), path)
+		})
+	})
+
+	It("rejects misspelled fields and malformed sizes", func() {
+		task := object{"spec": object{
+			"task":              "Deploy the infrastructure",
+			"timeout":           "2h",
+			"persistantVolumes": []interface{}{},
+			"persistentVolumes": []interface{}{
+				object{"name": "state", "mountPath": "/state", "size": "50 GB"},
+			},
+			"resources": object{"limits": object{"memory": "8Gi"}},
+		}}
+		Expect(validate(task, schema, "")).To(ConsistOf(
+			".spec.persistantVolumes: unknown field",
+			ContainSubstring(`.spec.persistentVolumes[0].size: "50 GB" does not match`),
+		))
+
+		task["spec"].(object)["timeout"] = "2 hours"
+		Expect(validate(task, schema, "")).To(ContainElement(ContainSubstring(`.spec.timeout: "2 hours" does not match`)))
+	})
+
+	It("accepts the examples", func() {
+		for _, task := range readDocuments("../../swarm-operator/examples/enhanced-task-examples.yaml") {
+			if task["kind"] == "SwarmTask" {
+				Expect(validate(task, schema, "")).To(BeEmpty(), task["metadata"].(object)["name"])
+			}
+		}
+	})
+})
@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwarmChaosSpec defines the desired state of SwarmChaos. Exactly one fault
+// is set. The operator only runs experiments when started with
+// --enable-chaos, against clusters annotated with
+// swarm.claudeflow.io/allow-chaos: "true".
+// +kubebuilder:validation:XValidation:rule="(has(self.podKill) ? 1 : 0) + (has(self.memoryLatency) ? 1 : 0) + (has(self.hiveMindPartition) ? 1 : 0) == 1",message="exactly one of podKill, memoryLatency and hiveMindPartition must be set"
+type SwarmChaosSpec struct {
+	// SwarmCluster the experiment runs against
+	SwarmCluster string `json:"swarmCluster"`
+
+	// Schedule is a cron expression the experiment runs on. Without a
+	// schedule it runs once.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Suspend stops new runs, a run in progress still finishes
+	Suspend bool `json:"suspend,omitempty"`
+
+	// PodKill deletes a share of the agent pods
+	PodKill *PodKillChaos `json:"podKill,omitempty"`
+
+	// MemoryLatency delays the network traffic of the memory store pods
+	MemoryLatency *MemoryLatencyChaos `json:"memoryLatency,omitempty"`
+
+	// HiveMindPartition cuts hive-mind replicas off the network
+	HiveMindPartition *HiveMindPartitionChaos `json:"hiveMindPartition,omitempty"`
+
+	// RecoveryTimeout is how long the cluster has to return to Healthy
+	// once the fault ends before the run fails
+	// +kubebuilder:default="10m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	RecoveryTimeout string `json:"recoveryTimeout,omitempty"`
+
+	// RecoveryObjective fails runs the cluster took longer to recover
+	// from, catching resilience regressions
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	RecoveryObjective string `json:"recoveryObjective,omitempty"`
+
+	// HistoryLimit is the number of runs kept in status
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+}
+
+// PodKillChaos deletes agent pods and measures how long the swarm takes to
+// replace them
+type PodKillChaos struct {
+	// Percent of the agent pods to delete, at least one pod is deleted
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// AgentTypes limits the experiment to the pods of these agent types
+	// +optional
+	AgentTypes []AgentType `json:"agentTypes,omitempty"`
+}
+
+// MemoryLatencyChaos adds latency to the network of the memory store pods
+// through a netem qdisc set up by an ephemeral container. The container
+// removes the qdisc itself when the duration is up.
+type MemoryLatencyChaos struct {
+	// Latency added to every packet, e.g. "200ms"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	Latency string `json:"latency"`
+
+	// Duration of the fault
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	Duration string `json:"duration,omitempty"`
+
+	// Image of the ephemeral container, which needs tc
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// HiveMindPartitionChaos isolates hive-mind replicas with a NetworkPolicy
+// denying all of their traffic
+type HiveMindPartitionChaos struct {
+	// Replicas to cut off
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Duration of the partition
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	Duration string `json:"duration,omitempty"`
+}
+
+// SwarmChaosStatus defines the observed state of SwarmChaos
+type SwarmChaosStatus struct {
+	// Runs are the latest runs, oldest first
+	Runs []ChaosRun `json:"runs,omitempty"`
+
+	// LastRunTime is when the latest run started
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// NextRunTime is when the schedule starts the next run
+	NextRunTime *metav1.Time `json:"nextRunTime,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ChaosRun is a single run of an experiment
+type ChaosRun struct {
+	// StartTime is when the fault was injected
+	StartTime metav1.Time `json:"startTime"`
+
+	// FaultEndTime is when the fault was lifted, recovery counts from it
+	FaultEndTime *metav1.Time `json:"faultEndTime,omitempty"`
+
+	// RecoveredTime is when the cluster was Healthy again
+	RecoveredTime *metav1.Time `json:"recoveredTime,omitempty"`
+
+	// RecoverySeconds is how long the cluster took to recover
+	RecoverySeconds *int64 `json:"recoverySeconds,omitempty"`
+
+	// Phase of the run
+	// +kubebuilder:validation:Enum=Injecting;Recovering;Recovered;Failed
+	Phase string `json:"phase"`
+
+	// Targets are the pods the fault was injected into
+	Targets []string `json:"targets,omitempty"`
+
+	// ReadyBefore counts the ready pods of the targeted component before
+	// the fault. The cluster has recovered once as many are ready again.
+	ReadyBefore int32 `json:"readyBefore,omitempty"`
+
+	// Message provides additional information
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=sch
+//+kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.swarmCluster`
+//+kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+//+kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=`.status.lastRunTime`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SwarmChaos is the Schema for the swarmchaos API. It injects faults into a
+// non-production swarm and records how long the swarm takes to recover.
+type SwarmChaos struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwarmChaosSpec   `json:"spec,omitempty"`
+	Status SwarmChaosStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwarmChaosList contains a list of SwarmChaos
+type SwarmChaosList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwarmChaos `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwarmChaos{}, &SwarmChaosList{})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,40 +33,1005 @@ const (
 	RingTopology SwarmTopology = "ring"
 	// StarTopology has a central coordinator with all agents connecting to it
 	StarTopology SwarmTopology = "star"
+	// CustomTopology calculates peers with a registered strategy or the
+	// adjacency rules in spec.customTopology
+	CustomTopology SwarmTopology = "custom"
 )
 
+// AgentDeploymentMode defines how agent pods are managed
+type AgentDeploymentMode string
+
+const (
+	// PerAgentDeploymentMode runs every Agent in its own Deployment
+	PerAgentDeploymentMode AgentDeploymentMode = "PerAgent"
+	// PooledDeploymentMode runs one StatefulSet per agent type and maps each
+	// Agent to a pod slot (ordinal) of that StatefulSet
+	PooledDeploymentMode AgentDeploymentMode = "Pooled"
+)
+
+// QueenMode defines how the swarm is coordinated
+type QueenMode string
+
+const (
+	// DistributedQueenMode lets agents coordinate among themselves
+	DistributedQueenMode QueenMode = "distributed"
+	// CentralizedQueenMode coordinates the swarm through a single queen, a
+	// coordinator agent the operator reserves resources for and replaces
+	// when its pod fails
+	CentralizedQueenMode QueenMode = "centralized"
+)
+
+// QueenLabel marks the Agent elected queen, and its pods, with "true"
+const QueenLabel = "swarm.claudeflow.io/queen"
+
 // SwarmClusterSpec defines the desired state of SwarmCluster
+// +kubebuilder:validation:XValidation:rule="!has(self.minAgents) || !has(self.maxAgents) || self.minAgents <= self.maxAgents",message="minAgents must not exceed maxAgents"
 type SwarmClusterSpec struct {
-	// Topology defines the communication pattern between agents
-	// +kubebuilder:validation:Enum=mesh;hierarchical;ring;star
-	// +kubebuilder:default=mesh
-	Topology SwarmTopology `json:"topology"`
+	// Profile names a SwarmProfile whose presets fill in every field left
+	// unset here
+	Profile string `json:"profile,omitempty"`
+
+	// Topology defines the communication pattern between agents.
+	// Defaults to the profile's topology, or mesh.
+	// +kubebuilder:validation:Enum=mesh;hierarchical;ring;star;custom
+	Topology SwarmTopology `json:"topology,omitempty"`
+
+	// CustomTopology configures peer calculation for the custom topology
+	CustomTopology *CustomTopologySpec `json:"customTopology,omitempty"`
+
+	// TopologyMigration paces the move of running agents to a new topology
+	// after spec.topology changes
+	TopologyMigration *TopologyMigrationSpec `json:"topologyMigration,omitempty"`
+
+	// QueenMode selects distributed coordination or a centralized queen
+	// +kubebuilder:validation:Enum=distributed;centralized
+	// +kubebuilder:default=distributed
+	QueenMode QueenMode `json:"queenMode,omitempty"`
 
-	// MaxAgents is the maximum number of agents in the swarm
+	// Queen reserves resources for the queen in centralized queen mode
+	Queen *QueenSpec `json:"queen,omitempty"`
+
+	// MaxAgents is the maximum number of agents in the swarm.
+	// Defaults to the profile's maxAgents, or 5.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=100
-	// +kubebuilder:default=5
-	MaxAgents int32 `json:"maxAgents"`
+	MaxAgents int32 `json:"maxAgents,omitempty"`
 
-	// MinAgents is the minimum number of agents in the swarm
+	// MinAgents is the minimum number of agents in the swarm.
+	// Defaults to the profile's minAgents, or 1.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=100
-	// +kubebuilder:default=1
 	MinAgents int32 `json:"minAgents,omitempty"`
 
-	// Strategy defines how agents are selected and distributed
+	// Strategy defines how agents are selected and distributed.
+	// Defaults to the profile's strategy, or balanced.
 	// +kubebuilder:validation:Enum=balanced;specialized;adaptive
-	// +kubebuilder:default=balanced
 	Strategy string `json:"strategy,omitempty"`
 
 	// AgentTemplate defines the template for creating agents
 	AgentTemplate AgentTemplateSpec `json:"agentTemplate,omitempty"`
 
+	// AgentTypes override the image, resources and environment of the
+	// agent template for the agents of a type, e.g. a data science image
+	// for analysts. Keys are agent types.
+	AgentTypes map[AgentType]AgentTypeOverride `json:"agentTypes,omitempty"`
+
+	// CapabilityPlacement schedules agents onto node pools by capability.
+	// Keys are agent capabilities, an agent with several mapped capabilities
+	// gets all of their constraints.
+	CapabilityPlacement map[string]AgentPlacement `json:"capabilityPlacement,omitempty"`
+
+	// ToolBundles are toolchains, e.g. terraform, kubectl or language
+	// runtimes, agents mount on demand instead of shipping them all in the
+	// agent image
+	// +listType=map
+	// +listMapKey=name
+	ToolBundles []ToolBundle `json:"toolBundles,omitempty"`
+
+	// CapabilityToolBundles names the tool bundles each capability needs.
+	// Agents only get the bundles of their own capabilities.
+	CapabilityToolBundles map[string][]string `json:"capabilityToolBundles,omitempty"`
+
+	// AgentDeploymentMode selects between one Deployment per Agent and
+	// pooled per-agent-type StatefulSets, which scale to far more agents.
+	// Defaults to the profile's mode, or PerAgent.
+	// +kubebuilder:validation:Enum=PerAgent;Pooled
+	AgentDeploymentMode AgentDeploymentMode `json:"agentDeploymentMode,omitempty"`
+
+	// DrainTimeout bounds how long scale-down and deletion wait for a
+	// draining agent to finish its tasks. Tasks still running afterwards
+	// are reassigned and resume from their latest checkpoint.
+	// +kubebuilder:default="10m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	DrainTimeout string `json:"drainTimeout,omitempty"`
+
 	// TaskDistribution defines how tasks are distributed among agents
 	TaskDistribution TaskDistributionSpec `json:"taskDistribution,omitempty"`
 
 	// AutoScaling defines auto-scaling behavior
 	AutoScaling *AutoScalingSpec `json:"autoScaling,omitempty"`
+
+	// DeadLetter configures handling of tasks that exhaust their retries
+	DeadLetter *DeadLetterSpec `json:"deadLetter,omitempty"`
+
+	// Archive records every finished task of the swarm so its history
+	// outlives the SwarmTask
+	Archive *TaskArchiveSpec `json:"archive,omitempty"`
+
+	// MaintenanceWindows freeze the swarm while one of them is open: no new
+	// task Jobs are dispatched and auto-scaling only scales down
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// AgentSchedules restrict agent types to their active hours, outside
+	// of which their agents scale to zero once idle. Types without a
+	// schedule run around the clock. Annotating the cluster with
+	// swarm.claudeflow.io/schedule-override-until set to an RFC 3339 time
+	// runs every type until then, e.g. during incident response.
+	AgentSchedules []AgentSchedule `json:"agentSchedules,omitempty"`
+
+	// ExecutorImageRollout canaries a new default executor image on a
+	// growing share of new tasks, pausing when it fails more often than the
+	// current image
+	ExecutorImageRollout *ExecutorImageRollout `json:"executorImageRollout,omitempty"`
+
+	// Notifications posts task and cluster lifecycle events to chat or webhook sinks
+	Notifications *NotificationsSpec `json:"notifications,omitempty"`
+
+	// Memory configures the shared memory backend of the swarm
+	Memory MemorySpec `json:"memory,omitempty"`
+
+	// Monitoring configures metrics scraping of agent pods
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// TLS enables mutual TLS between agents, the hive-mind and the memory
+	// service
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// NamespaceConfig places swarm and hive-mind components in dedicated
+	// namespaces and optionally provisions them
+	NamespaceConfig *NamespaceConfig `json:"namespaceConfig,omitempty"`
+
+	// HiveMind runs the hive-mind sync service, partitioned across replicas
+	HiveMind *HiveMindSpec `json:"hiveMind,omitempty"`
+
+	// MessageBus runs a NATS broker agents use to message their topology
+	// peers
+	MessageBus *MessageBusSpec `json:"messageBus,omitempty"`
+
+	// WarmPool keeps idle executor pods that tasks start in instead of
+	// waiting for a new pod to be scheduled and pull its image
+	WarmPool *WarmPoolSpec `json:"warmPool,omitempty"`
+
+	// ImageConfig sets the pull secrets, pull policy and registry mirrors
+	// of every pod the operator creates for the cluster: agents, task Jobs,
+	// the hive-mind, the message bus and memory stores
+	ImageConfig *ImageConfig `json:"imageConfig,omitempty"`
+
+	// Health weighs the signals of the cluster health score and sets the
+	// scores below which the cluster is Degraded or Unhealthy
+	Health *HealthSpec `json:"health,omitempty"`
+
+	// Registration lets agents launched outside the cluster register with
+	// it and receive tasks
+	Registration *AgentRegistrationSpec `json:"registration,omitempty"`
+
+	// ServiceMesh declares the mesh injecting proxies into the cluster's
+	// task pods, so task Jobs complete although the proxy never exits on
+	// its own
+	ServiceMesh *ServiceMeshSpec `json:"serviceMesh,omitempty"`
+
+	// SharedWorkspace mounts one volume into every agent and task pod of
+	// the cluster, so agents hand off files without the memory store
+	SharedWorkspace *SharedWorkspaceSpec `json:"sharedWorkspace,omitempty"`
+}
+
+// SharedWorkspaceSpec is the volume shared by the agents and tasks of a
+// cluster. Without nfs or csi the operator provisions a ReadWriteMany
+// PersistentVolumeClaim for it.
+// +kubebuilder:validation:XValidation:rule="!(has(self.nfs) && has(self.csi))",message="nfs and csi are mutually exclusive"
+type SharedWorkspaceSpec struct {
+	// MountPath is where agent and task containers see the workspace
+	// +kubebuilder:default="/shared"
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Size of the provisioned claim
+	// +kubebuilder:default="10Gi"
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	Size string `json:"size,omitempty"`
+
+	// StorageClassName of the provisioned claim, which must support
+	// ReadWriteMany. The default storage class when empty.
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// NFS mounts an NFS export instead of provisioning a claim
+	NFS *corev1.NFSVolumeSource `json:"nfs,omitempty"`
+
+	// CSI mounts a volume of a CSI driver instead of provisioning a claim,
+	// such as an object store file system like ObjectFS or JuiceFS
+	CSI *corev1.CSIVolumeSource `json:"csi,omitempty"`
+}
+
+// ServiceMeshProvider is a service mesh task pods may be part of
+type ServiceMeshProvider string
+
+const (
+	IstioMesh   ServiceMeshProvider = "istio"
+	LinkerdMesh ServiceMeshProvider = "linkerd"
+)
+
+// ServiceMeshMode is how task Jobs get along with the mesh proxy
+type ServiceMeshMode string
+
+const (
+	// AutoMeshMode runs the proxy as a native sidecar on Kubernetes 1.29
+	// and later, and as QuitOnExitMeshMode before
+	AutoMeshMode ServiceMeshMode = "Auto"
+	// NativeSidecarMeshMode runs the proxy as a native sidecar container,
+	// which the kubelet stops once the task container exits
+	NativeSidecarMeshMode ServiceMeshMode = "NativeSidecar"
+	// QuitOnExitMeshMode has the executor ask the proxy to exit once the
+	// task finishes, through SWARM_MESH_QUIT_URL
+	QuitOnExitMeshMode ServiceMeshMode = "QuitOnExit"
+	// DisabledMeshMode keeps task pods out of the mesh
+	DisabledMeshMode ServiceMeshMode = "Disabled"
+)
+
+// ServiceMeshSpec configures how task pods join the service mesh
+type ServiceMeshSpec struct {
+	// Provider is the mesh injecting proxies into the namespaces tasks
+	// run in
+	// +kubebuilder:validation:Enum=istio;linkerd
+	Provider ServiceMeshProvider `json:"provider"`
+
+	// Mode is how task Jobs complete despite the proxy
+	// +kubebuilder:validation:Enum=Auto;NativeSidecar;QuitOnExit;Disabled
+	// +kubebuilder:default=Auto
+	Mode ServiceMeshMode `json:"mode,omitempty"`
+}
+
+// AgentRegistrationSpec configures the self-registration of external
+// agents. An agent registers by POSTing to the registration API of the
+// operator with the cluster's registration token, and appears as an Agent
+// with spec.external set. The operator dispatches tasks to the address it
+// registered and marks it Failed once its heartbeats stop.
+type AgentRegistrationSpec struct {
+	// Enabled accepts registrations and generates the registration token
+	Enabled bool `json:"enabled,omitempty"`
+
+	// HeartbeatTimeout is how long an external agent may go without a
+	// heartbeat before it is marked Failed
+	// +kubebuilder:default="2m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	HeartbeatTimeout string `json:"heartbeatTimeout,omitempty"`
+}
+
+// TopologyMigrationSpec paces a topology change. Agents restart with their
+// new peers, so they move in batches while the agents not moved yet keep
+// their old peers.
+type TopologyMigrationSpec struct {
+	// BatchSize is how many agents get their new peers at a time
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=2
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// BatchInterval is the least time between two batches. A batch also
+	// waits for the agents of the previous one to be ready again.
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	BatchInterval string `json:"batchInterval,omitempty"`
+}
+
+// QueenSpec keeps the queen from being evicted or starved. The queen is a
+// coordinator agent elected by the operator; it runs with Guaranteed QoS
+// and a PriorityClass above task pods, optionally on a dedicated node pool.
+type QueenSpec struct {
+	// Resources of the queen, requested and limited alike. Defaults to the
+	// agent template resources, or 1 CPU and 1Gi of memory.
+	Resources ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName of the queen pod. Defaults to swarm-queen, which
+	// the operator creates above the priority of every task.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// NodeSelector pins the queen to a dedicated node pool
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations let the queen onto tainted dedicated nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// FailoverAfter is how long the queen pod may stay NotReady before
+	// another coordinator agent is elected
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	FailoverAfter string `json:"failoverAfter,omitempty"`
+}
+
+// HealthSpec configures the health score of a cluster. The score is the
+// weighted mean of its signals, each scored from 0 to 100. Signals without
+// data, e.g. hive-mind sync lag without a hive-mind, are left out.
+// +kubebuilder:validation:XValidation:rule="!has(self.unhealthyThreshold) || !has(self.degradedThreshold) || self.unhealthyThreshold <= self.degradedThreshold",message="unhealthyThreshold must not exceed degradedThreshold"
+type HealthSpec struct {
+	// Weights of the signals, the defaults apply to those left out
+	Weights HealthWeights `json:"weights,omitempty"`
+
+	// DegradedThreshold is the score below which the cluster is Degraded
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=80
+	DegradedThreshold int32 `json:"degradedThreshold,omitempty"`
+
+	// UnhealthyThreshold is the score below which the cluster is Unhealthy
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=50
+	UnhealthyThreshold int32 `json:"unhealthyThreshold,omitempty"`
+
+	// FailureWindow is how far back finished tasks count towards the
+	// failure rate
+	// +kubebuilder:default="1h"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	FailureWindow string `json:"failureWindow,omitempty"`
+
+	// MemoryLatencyTarget is the memory backend response time that still
+	// scores 100. Twice the target scores 50.
+	// +kubebuilder:default="100ms"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	MemoryLatencyTarget string `json:"memoryLatencyTarget,omitempty"`
+
+	// SyncLagTarget is the hive-mind sync lag that still scores 100. Twice
+	// the target scores 50.
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	SyncLagTarget string `json:"syncLagTarget,omitempty"`
+}
+
+// HealthWeights are the relative weights of the health signals. A weight of
+// 0 leaves the signal out.
+type HealthWeights struct {
+	// AgentReadiness is the share of the desired agents that are ready,
+	// 30 by default
+	// +kubebuilder:validation:Minimum=0
+	AgentReadiness *int32 `json:"agentReadiness,omitempty"`
+
+	// HeartbeatFreshness is how recently live agents sent a heartbeat,
+	// 20 by default
+	// +kubebuilder:validation:Minimum=0
+	HeartbeatFreshness *int32 `json:"heartbeatFreshness,omitempty"`
+
+	// TaskFailureRate is the share of tasks finished within the failure
+	// window that succeeded, 25 by default
+	// +kubebuilder:validation:Minimum=0
+	TaskFailureRate *int32 `json:"taskFailureRate,omitempty"`
+
+	// MemoryLatency is the response time of the memory backend, 15 by
+	// default
+	// +kubebuilder:validation:Minimum=0
+	MemoryLatency *int32 `json:"memoryLatency,omitempty"`
+
+	// HiveMindSyncLag is how far the hive-mind replicas trail, 10 by
+	// default
+	// +kubebuilder:validation:Minimum=0
+	HiveMindSyncLag *int32 `json:"hiveMindSyncLag,omitempty"`
+}
+
+// ImageConfig controls how the images of generated pods are pulled
+type ImageConfig struct {
+	// PullSecrets are added to the imagePullSecrets of every pod. They must
+	// exist in the namespace the pods run in.
+	PullSecrets []corev1.LocalObjectReference `json:"pullSecrets,omitempty"`
+
+	// PullPolicy of every container, the Kubernetes default when empty
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
+
+	// Mirrors rewrite images of a registry to a mirror. The first mirror
+	// of the image's registry applies.
+	Mirrors []RegistryMirror `json:"mirrors,omitempty"`
+}
+
+// RegistryMirror pulls the images of a registry from a mirror, e.g.
+// docker.io/library/busybox:latest from mirror.example.com/dockerhub/library/busybox:latest
+type RegistryMirror struct {
+	// Registry host the images are rewritten for. Images without a
+	// registry are from docker.io.
+	// +kubebuilder:validation:MinLength=1
+	Registry string `json:"registry"`
+
+	// Mirror host and optional path prefix the images are pulled from
+	// +kubebuilder:validation:MinLength=1
+	Mirror string `json:"mirror"`
+}
+
+// WarmPoolSpec sizes the warm pool of executor pods. A task claims an idle
+// pod when its pod would be identical apart from its environment, otherwise
+// it starts a Job as usual. Claimed pods belong to the task's Job and are
+// replaced rather than returned to the pool, so no task sees the workspace
+// of another.
+// +kubebuilder:validation:XValidation:rule="!has(self.minSize) || !has(self.maxSize) || self.minSize <= self.maxSize",message="minSize must not exceed maxSize"
+type WarmPoolSpec struct {
+	// Enabled keeps the pool
+	Enabled bool `json:"enabled"`
+
+	// MinSize is the number of idle pods kept without any demand
+	// +kubebuilder:validation:Minimum=0
+	MinSize int32 `json:"minSize,omitempty"`
+
+	// MaxSize caps the pool however many tasks start
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	MaxSize int32 `json:"maxSize,omitempty"`
+
+	// DemandWindow is how far back task starts are counted. The pool keeps
+	// as many idle pods as tasks started within the window, between MinSize
+	// and MaxSize.
+	// +kubebuilder:default="10m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	DemandWindow string `json:"demandWindow,omitempty"`
+}
+
+// MessageBusSpec configures the cluster's message bus. Every agent gets a
+// broker user that may subscribe to its own inbox and the queue of its type,
+// and publish to the inboxes of its topology peers.
+type MessageBusSpec struct {
+	// Enabled runs the message bus
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Image of the NATS server
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Replicas of the broker. More than one forms a NATS cluster.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// JetStream enables persistent streams and work queues, stored on an
+	// emptyDir unless Storage is set
+	// +optional
+	JetStream bool `json:"jetStream,omitempty"`
+
+	// Storage is the size of the JetStream volume claim of each replica,
+	// e.g. "10Gi"
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	Storage string `json:"storage,omitempty"`
+
+	// Resources of the broker container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// HiveMindSpec configures the hive-mind sync service. Agents are hashed
+// into partitions and each partition is owned by one replica, so adding
+// replicas spreads the sync load.
+type HiveMindSpec struct {
+	// Enabled runs the hive-mind sync service
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Image of the hive-mind sync service
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Replicas of the sync service. Partitions are rebalanced as replicas
+	// become ready or go away.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Partitions is the number of hash partitions agents are spread over.
+	// Keep it above the largest replica count; changing it moves most
+	// agents to another partition.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +kubebuilder:default=16
+	Partitions int32 `json:"partitions,omitempty"`
+
+	// SyncInterval between agent state synchronizations, e.g. "30s"
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	SyncInterval string `json:"syncInterval,omitempty"`
+
+	// Resources of the sync service container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// FailureDomains spreads the replicas over zones so losing one zone
+	// leaves the others serving. Replicas are spread softly when unset.
+	// +optional
+	FailureDomains *FailureDomainSpec `json:"failureDomains,omitempty"`
+}
+
+// FailureDomainSpec spreads the replicas of a StatefulSet over failure
+// domains
+type FailureDomainSpec struct {
+	// TopologyKey is the node label telling failure domains apart
+	// +kubebuilder:default="topology.kubernetes.io/zone"
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// MaxSkew is the largest difference in replicas between two domains
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable keeps replicas pending rather than skewing them
+	// with DoNotSchedule; ScheduleAnyway only prefers spreading
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +kubebuilder:default=ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+
+	// RequiredAntiAffinity never places two replicas in the same domain.
+	// Replicas beyond the number of domains stay pending.
+	// +optional
+	RequiredAntiAffinity bool `json:"requiredAntiAffinity,omitempty"`
+
+	// Zones restricts replicas to these zones. With a storage class that
+	// binds volumes on first consumer, their volume claims are provisioned
+	// in the zone of the replica.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+}
+
+// NamespaceConfig defines namespace allocation for different components
+type NamespaceConfig struct {
+	// SwarmNamespace for general swarm agents (default: claude-flow-swarm)
+	SwarmNamespace string `json:"swarmNamespace,omitempty"`
+
+	// HiveMindNamespace for hive-mind components (default: claude-flow-hivemind)
+	HiveMindNamespace string `json:"hiveMindNamespace,omitempty"`
+
+	// AllowedNamespaces may reach swarm pods when network isolation is
+	// enabled, e.g. the operator's own namespace
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// CreateNamespaces creates the swarm and hive-mind namespaces if they
+	// don't exist and deletes them with the cluster
+	CreateNamespaces bool `json:"createNamespaces,omitempty"`
+
+	// Labels are added to the provisioned namespaces next to the standard
+	// labels
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ResourceQuota is applied to every provisioned namespace
+	ResourceQuota *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+
+	// LimitRange is applied to every provisioned namespace
+	LimitRange *corev1.LimitRangeSpec `json:"limitRange,omitempty"`
+
+	// NetworkIsolation only admits traffic into the provisioned namespaces
+	// from the swarm's own namespaces and AllowedNamespaces
+	NetworkIsolation bool `json:"networkIsolation,omitempty"`
+}
+
+// TLSSpec configures the certificates of the swarm's mTLS mesh
+// +kubebuilder:validation:XValidation:rule="!has(self.duration) || !has(self.renewBefore) || duration(self.renewBefore) < duration(self.duration)",message="renewBefore must be shorter than duration"
+type TLSSpec struct {
+	// Enabled issues certificates to all swarm components and requires TLS
+	// on their connections
+	Enabled bool `json:"enabled"`
+
+	// Issuer of the certificates. SelfSigned runs a per-cluster CA in the
+	// operator, CertManager requests them from cert-manager.
+	// +kubebuilder:validation:Enum=SelfSigned;CertManager
+	// +kubebuilder:default=SelfSigned
+	Issuer string `json:"issuer,omitempty"`
+
+	// IssuerRef is the cert-manager issuer used with the CertManager issuer
+	IssuerRef *CertManagerIssuerRef `json:"issuerRef,omitempty"`
+
+	// Duration of the issued certificates
+	// +kubebuilder:default="2160h"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	Duration string `json:"duration,omitempty"`
+
+	// RenewBefore is how long before expiry certificates are rotated
+	// +kubebuilder:default="360h"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	RenewBefore string `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerRef references a cert-manager Issuer or ClusterIssuer
+type CertManagerIssuerRef struct {
+	// Name of the issuer
+	Name string `json:"name"`
+
+	// Kind of the issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	Kind string `json:"kind,omitempty"`
+}
+
+// MemorySpec defines the swarm memory backend
+type MemorySpec struct {
+	// Type of memory backend
+	// +kubebuilder:validation:Enum=sqlite;redis;hazelcast;etcd
+	Type string `json:"type,omitempty"`
+
+	// Size of the memory storage
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	Size string `json:"size,omitempty"`
+
+	// EnableMemoryStore creates a SwarmMemoryStore for the sqlite backend
+	EnableMemoryStore bool `json:"enableMemoryStore,omitempty"`
+
+	// SQLiteConfig tunes the sqlite memory store
+	SQLiteConfig *SQLiteMemoryConfig `json:"sqliteConfig,omitempty"`
+}
+
+// SQLiteMemoryConfig defines SQLite-specific memory configuration
+type SQLiteMemoryConfig struct {
+	// CacheSize is the maximum number of entries to cache
+	CacheSize int `json:"cacheSize,omitempty"`
+
+	// CacheMemoryMB is the maximum memory for caching
+	CacheMemoryMB int `json:"cacheMemoryMB,omitempty"`
+
+	// EnableWAL enables Write-Ahead Logging
+	EnableWAL bool `json:"enableWAL,omitempty"`
+
+	// EnableVacuum enables automatic vacuuming
+	EnableVacuum bool `json:"enableVacuum,omitempty"`
+
+	// GCInterval for garbage collection
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	GCInterval string `json:"gcInterval,omitempty"`
+
+	// BackupInterval for automatic backups
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	BackupInterval string `json:"backupInterval,omitempty"`
+}
+
+// MonitoringSpec configures metrics scraping of agent pods
+type MonitoringSpec struct {
+	// Enabled adds Prometheus scrape annotations to agent pods
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MetricsPort the agents expose metrics on
+	// +kubebuilder:default=9090
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// MetricsPath the agents expose metrics on
+	// +kubebuilder:default="/metrics"
+	MetricsPath string `json:"metricsPath,omitempty"`
+
+	// SLOs are the task latency objectives of the cluster. Compliance and
+	// error budget burn are published as metrics and reported in status.
+	SLOs []TaskSLO `json:"slos,omitempty"`
+
+	// AlertRules generates a PrometheusRule with multi-window burn-rate
+	// alerts for the SLOs. Requires the Prometheus operator.
+	AlertRules bool `json:"alertRules,omitempty"`
+
+	// RuleLabels are added to the generated PrometheusRule, e.g. to match
+	// the ruleSelector of the Prometheus instance
+	RuleLabels map[string]string `json:"ruleLabels,omitempty"`
+
+	// DashboardEnabled generates PodMonitors and ServiceMonitors for the
+	// agents, hive-mind, memory and the operator, and Grafana dashboard
+	// ConfigMaps for the cluster. The monitors require the Prometheus
+	// operator.
+	DashboardEnabled bool `json:"dashboardEnabled,omitempty"`
+
+	// MonitorLabels are added to the generated PodMonitors and
+	// ServiceMonitors, e.g. to match the monitor selectors of the
+	// Prometheus instance
+	MonitorLabels map[string]string `json:"monitorLabels,omitempty"`
+
+	// DashboardLabels are added to the dashboard ConfigMaps. They default
+	// to grafana_dashboard: "1", which the Grafana sidecar discovers.
+	DashboardLabels map[string]string `json:"dashboardLabels,omitempty"`
+
+	// TaskTelemetry tags the traces and metrics of task executors with
+	// the task they run
+	TaskTelemetry *TaskTelemetrySpec `json:"taskTelemetry,omitempty"`
+}
+
+// TaskTelemetrySpec configures the telemetry environment and annotations
+// the operator adds to task pods
+type TaskTelemetrySpec struct {
+	// Enabled sets OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES and
+	// SWARM_EXEMPLAR_LABELS on the task container, and the Prometheus
+	// scrape annotations on task pods. Variables the task sets itself are
+	// kept.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ServiceName is the OpenTelemetry service name of task executors
+	// +kubebuilder:default="swarm-task"
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// OTLPEndpoint is set as OTEL_EXPORTER_OTLP_ENDPOINT, e.g.
+	// http://otel-collector.monitoring:4317
+	// +optional
+	OTLPEndpoint string `json:"otlpEndpoint,omitempty"`
+
+	// ResourceAttributes are added to the OpenTelemetry resource
+	// attributes of every task, e.g. deployment.environment
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+
+	// ExemplarLabels are added to the labels executors attach to metric
+	// exemplars, next to task, task_namespace, cluster and correlation_id
+	ExemplarLabels map[string]string `json:"exemplarLabels,omitempty"`
+}
+
+// SLOStage is the part of a task's life an SLO measures
+type SLOStage string
+
+const (
+	// DispatchStage runs from the creation of a task until its Job runs
+	DispatchStage SLOStage = "dispatch"
+
+	// CompletionStage runs from the creation of a task until it completes.
+	// Tasks that fail for good count against the objective.
+	CompletionStage SLOStage = "completion"
+)
+
+// TaskSLO is a latency objective, e.g. 95% of high-priority tasks
+// dispatched within 30s
+type TaskSLO struct {
+	// Name identifies the SLO in metrics, status and alerts
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Stage of the task the latency is measured to
+	// +kubebuilder:validation:Enum=dispatch;completion
+	// +kubebuilder:default=completion
+	Stage SLOStage `json:"stage,omitempty"`
+
+	// Priorities of the tasks the SLO applies to. Empty applies it to all
+	// tasks.
+	Priorities []TaskPriority `json:"priorities,omitempty"`
+
+	// Threshold a task has to reach the stage within, e.g. "30s"
+	// +kubebuilder:validation:Required
+	Threshold metav1.Duration `json:"threshold"`
+
+	// Objective is the percentage of tasks that have to meet the
+	// threshold, e.g. "95" or "99.9"
+	// +kubebuilder:validation:Pattern=`^[0-9]{1,2}(\.[0-9]+)?$`
+	// +kubebuilder:default="99"
+	Objective string `json:"objective,omitempty"`
+
+	// Window compliance and the remaining error budget are computed over
+	// +kubebuilder:default="24h"
+	Window metav1.Duration `json:"window,omitempty"`
+}
+
+// CustomTopologySpec defines a custom peer layout, either through a Go
+// strategy registered with the operator or through adjacency rules
+type CustomTopologySpec struct {
+	// Strategy names a peer strategy registered with topology.Register.
+	// Takes precedence over Rules.
+	Strategy string `json:"strategy,omitempty"`
+
+	// Rules connect agents matching the left selector to agents matching the
+	// right one, e.g. "type=coordinator <-> *" or "* -> same:topology.kubernetes.io/zone".
+	// Selectors are comma-separated terms that must all match:
+	// "*", "type=<agent type>", "label:<key>=<value>", and on the right
+	// side "same:<label key>" or "!same:<label key>" relative to the left agent.
+	// "->" connects one way, "<->" both ways.
+	Rules []string `json:"rules,omitempty"`
+
+	// MaxPeers caps the peers of each agent, 0 means no limit
+	// +kubebuilder:validation:Minimum=0
+	MaxPeers int32 `json:"maxPeers,omitempty"`
+}
+
+// NotificationEvent is a lifecycle event that can be sent to a sink
+// +kubebuilder:validation:Enum=TaskCompleted;TaskFailed;TaskDeadLettered;ClusterDegraded
+type NotificationEvent string
+
+const (
+	// TaskCompletedEvent is sent when a task completes successfully
+	TaskCompletedEvent NotificationEvent = "TaskCompleted"
+	// TaskFailedEvent is sent when a task fails without a retry policy
+	TaskFailedEvent NotificationEvent = "TaskFailed"
+	// TaskDeadLetteredEvent is sent when a task exhausts its retries
+	TaskDeadLetteredEvent NotificationEvent = "TaskDeadLettered"
+	// ClusterDegradedEvent is sent when the cluster becomes degraded
+	ClusterDegradedEvent NotificationEvent = "ClusterDegraded"
+)
+
+// NotificationsSpec configures lifecycle notifications
+type NotificationsSpec struct {
+	// Sinks receive the notifications
+	Sinks []NotificationSink `json:"sinks,omitempty"`
+}
+
+// NotificationSink is a destination for lifecycle notifications
+type NotificationSink struct {
+	// Name identifies the sink in events and logs
+	Name string `json:"name"`
+
+	// Type selects the payload format
+	// +kubebuilder:validation:Enum=slack;teams;webhook
+	// +kubebuilder:default=webhook
+	Type string `json:"type,omitempty"`
+
+	// URL to post to. Use URLSecretRef for URLs that embed credentials,
+	// such as Slack and Teams incoming webhooks.
+	URL string `json:"url,omitempty"`
+
+	// URLSecretRef references a Secret key holding the URL. The Secret is
+	// read from the namespace of the SwarmCluster.
+	URLSecretRef *SecretKeyRef `json:"urlSecretRef,omitempty"`
+
+	// TokenSecretRef references a bearer token sent with each request. The
+	// Secret is read from the namespace of the SwarmCluster.
+	TokenSecretRef *SecretKeyRef `json:"tokenSecretRef,omitempty"`
+
+	// Events to send; all events are sent when empty
+	Events []NotificationEvent `json:"events,omitempty"`
+
+	// Template is a Go text/template for the message text. It is executed
+	// with the event, exposing .Type, .Cluster, .Namespace, .Task, .Phase,
+	// .Message and .Time.
+	Template string `json:"template,omitempty"`
+}
+
+// DeadLetterSpec configures where dead-lettered tasks are reported
+type DeadLetterSpec struct {
+	// SinkURL receives a JSON POST with the failure digest of every
+	// task in this swarm that is moved to DeadLettered
+	SinkURL string `json:"sinkURL,omitempty"`
+
+	// SinkSecretRef references a bearer token sent to the sink. The Secret
+	// is read from the namespace of the dead-lettered task.
+	SinkSecretRef *SecretKeyRef `json:"sinkSecretRef,omitempty"`
+}
+
+// TaskArchiveSpec configures where finished tasks are archived
+type TaskArchiveSpec struct {
+	// Sink receiving the task records. "memory" stores them as SwarmMemory
+	// entries next to the task, "webhook" posts them as JSON, e.g. to a
+	// service loading them into Postgres or S3.
+	// +kubebuilder:validation:Enum=memory;webhook
+	// +kubebuilder:default=memory
+	Sink string `json:"sink,omitempty"`
+
+	// URL the webhook sink posts records to
+	URL string `json:"url,omitempty"`
+
+	// SecretRef references a bearer token sent to the webhook sink
+	SecretRef *SecretKeyRef `json:"secretRef,omitempty"`
+
+	// RetentionDays keeps memory records for this many days, 0 keeps them
+	// forever
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3650
+	// +kubebuilder:default=30
+	RetentionDays int32 `json:"retentionDays,omitempty"`
+}
+
+// MaintenanceWindow is a recurring freeze period of the swarm
+type MaintenanceWindow struct {
+	// Name identifies the window in conditions and events
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Schedule is a five field cron expression (minute hour day-of-month
+	// month day-of-week) at which the window opens
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// Duration the window stays open, e.g. "2h"
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone the schedule is evaluated in, as an IANA name
+	// +kubebuilder:default=UTC
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// SuspendRunning checkpoints and suspends running tasks when the window
+	// opens, they resume from the checkpoint once it closes
+	SuspendRunning bool `json:"suspendRunning,omitempty"`
+}
+
+// AgentSchedule is a recurring period in which agents of a type run. A
+// type with several schedules runs while any of them is active.
+type AgentSchedule struct {
+	// AgentType the schedule applies to
+	// +kubebuilder:validation:Required
+	AgentType AgentType `json:"agentType"`
+
+	// Schedule is a five field cron expression (minute hour day-of-month
+	// month day-of-week) at which the active hours start, e.g.
+	// "0 8 * * 1-5" for weekday mornings
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// Duration of the active hours, e.g. "10h"
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone the schedule is evaluated in, as an IANA name
+	// +kubebuilder:default=UTC
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ExecutorImageRollout is a progressive rollout of a default executor image
+type ExecutorImageRollout struct {
+	// Image being rolled out. Tasks setting spec.executorImage keep theirs.
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Steps are the shares of new tasks receiving the image, each held for
+	// its duration before moving to the next. The last step is held until
+	// the rollout is removed.
+	// +kubebuilder:validation:MinItems=1
+	Steps []RolloutStep `json:"steps"`
+
+	// MaxFailureRateIncrease pauses the rollout once the image's failure
+	// rate exceeds the current image's by more than this many percentage
+	// points
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	MaxFailureRateIncrease int32 `json:"maxFailureRateIncrease,omitempty"`
+
+	// MinSamples is the number of finished canary Jobs needed before failure
+	// rates are compared
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=5
+	MinSamples int32 `json:"minSamples,omitempty"`
+}
+
+// RolloutStep is one stage of an executor image rollout
+type RolloutStep struct {
+	// Percent of new tasks running the new image
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// Duration the step is held, e.g. "1h"
+	Duration metav1.Duration `json:"duration,omitempty"`
+}
+
+// AgentPlacement constrains where the pods of agents with a capability run
+type AgentPlacement struct {
+	// NodeSelector labels the node must carry
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations for the taints of the node pool
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeAffinity rules, required terms of several capabilities must all
+	// be met
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+}
+
+// ToolBundle is a toolchain agents mount read-only, pulled as an OCI image
+// or served from a pre-populated volume
+// +kubebuilder:validation:XValidation:rule="has(self.image) != has(self.persistentVolumeClaim)",message="a tool bundle needs exactly one of image and persistentVolumeClaim"
+type ToolBundle struct {
+	// Name of the bundle, referenced from capabilityToolBundles
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=50
+	Name string `json:"name"`
+
+	// Image is an OCI image holding the bundle under path. An init
+	// container copies it into the pod, so the image needs a cp binary.
+	Image string `json:"image,omitempty"`
+
+	// Path of the bundle inside the image
+	// +kubebuilder:default="/bundle"
+	Path string `json:"path,omitempty"`
+
+	// PersistentVolumeClaim serves the bundle from an existing claim, e.g.
+	// a ReadOnlyMany image cache shared by all agents
+	PersistentVolumeClaim *ToolBundleClaim `json:"persistentVolumeClaim,omitempty"`
+
+	// MountPath where agents find the bundle. Defaults to
+	// /opt/tools/<name>.
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// ToolBundleClaim is a claim holding a tool bundle
+type ToolBundleClaim struct {
+	// ClaimName of the claim in the agents' namespace
+	ClaimName string `json:"claimName"`
+
+	// SubPath of the bundle within the claim
+	SubPath string `json:"subPath,omitempty"`
 }
 
 // AgentTemplateSpec defines the template for creating agents
@@ -78,18 +1044,60 @@ type AgentTemplateSpec struct {
 
 	// CognitivePatterns defines the thinking patterns for agents
 	CognitivePatterns []string `json:"cognitivePatterns,omitempty"`
+
+	// Image for the agent container
+	// +kubebuilder:default="claudeflow/swarm-agent:latest"
+	Image string `json:"image,omitempty"`
+
+	// InitContainers are injected into every agent pod and run before the agent
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Sidecars are injected into every agent pod alongside the agent container
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Volumes are added to every agent pod for use by sidecars and init containers
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// LiveConfig publishes the cognitive pattern, capabilities and peers of
+	// each agent to the swarm memory, where its pods watch for changes,
+	// instead of passing them as environment variables. Changing them then
+	// retunes agents without restarting their pods.
+	LiveConfig bool `json:"liveConfig,omitempty"`
+}
+
+// AgentTypeOverride replaces parts of the agent template for one agent
+// type. Unset fields keep the template's.
+type AgentTypeOverride struct {
+	// Image for the agent container. It must come from a registry the
+	// cluster's tenant allows.
+	Image string `json:"image,omitempty"`
+
+	// Resources replace the template's quantity by quantity. Like the
+	// template's they are copied into agents when they are created.
+	Resources *ResourceRequirements `json:"resources,omitempty"`
+
+	// Env is added to the agent container. Names starting with SWARM_ are
+	// reserved for the operator.
+	Env []corev1.EnvVar `json:"env,omitempty"`
 }
 
 // ResourceRequirements defines resource requirements
 type ResourceRequirements struct {
 	// CPU requirement in millicores
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
 	CPU string `json:"cpu,omitempty"`
 
 	// Memory requirement
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
 	Memory string `json:"memory,omitempty"`
 
 	// Storage requirement
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
 	Storage string `json:"storage,omitempty"`
+
+	// GPU count, requested as nvidia.com/gpu
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	GPU string `json:"gpu,omitempty"`
 }
 
 // TaskDistributionSpec defines how tasks are distributed
@@ -108,6 +1116,118 @@ type TaskDistributionSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=300
 	TaskTimeout int32 `json:"taskTimeout,omitempty"`
+
+	// AssignmentMode selects how tasks reach agents. Job runs every task in
+	// its own executor Job; Push sends the task to an agent's AssignTask
+	// gRPC endpoint and waits for the agent to ack it.
+	// +kubebuilder:validation:Enum=Job;Push
+	// +kubebuilder:default=Job
+	AssignmentMode string `json:"assignmentMode,omitempty"`
+
+	// AckTimeoutSeconds is how long an agent has to ack a pushed task
+	// before it is offered to the next agent
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	AckTimeoutSeconds int32 `json:"ackTimeoutSeconds,omitempty"`
+
+	// Placement hints the scheduler which nodes task Jobs should run on
+	Placement *TaskPlacementSpec `json:"placement,omitempty"`
+
+	// Policies are CEL expressions over the task and each candidate agent
+	// that filter and score agents before the algorithm picks among the
+	// best scoring ones
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=16
+	Policies []SchedulingPolicy `json:"policies,omitempty"`
+
+	// FairQueuing interleaves the dispatch of waiting tasks across teams or
+	// users so one of them cannot fill the cluster with a burst of tasks
+	FairQueuing *FairQueuingSpec `json:"fairQueuing,omitempty"`
+}
+
+// FairQueuingSpec limits the tasks of a cluster in flight and hands the
+// free slots to the waiting tasks by weighted fair queuing over a task label
+type FairQueuingSpec struct {
+	// Enabled turns fair queuing on
+	Enabled bool `json:"enabled"`
+
+	// KeyLabel is the task label that names the queue of a task, e.g.
+	// swarm.claudeflow.io/team. Tasks without it share the "default" queue.
+	// +kubebuilder:validation:MinLength=1
+	KeyLabel string `json:"keyLabel"`
+
+	// Weights of the queues by label value. A queue with twice the weight
+	// of another gets twice its dispatch slots while both have tasks waiting.
+	// +optional
+	Weights map[string]int32 `json:"weights,omitempty"`
+
+	// DefaultWeight of the queues missing from weights
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	DefaultWeight int32 `json:"defaultWeight,omitempty"`
+
+	// MaxInFlight is how many tasks of the cluster may be dispatched and
+	// unfinished at once. Defaults to maxAgents times maxTasksPerAgent.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxInFlight int32 `json:"maxInFlight,omitempty"`
+}
+
+// SchedulingPolicy is a CEL expression evaluated for every candidate agent
+// of a task. The expression sees the variables task (name, namespace, type,
+// priority from 2 for low to 10 for critical, capabilities, labels) and
+// agent (name, type, phase, capabilities, labels, pool, currentTasks,
+// currentTaskTypes, completedTasks, failedTasks, successRate, cpuUsage,
+// memoryUsage).
+type SchedulingPolicy struct {
+	// Name identifies the policy in events and metrics
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Expression is the CEL expression, e.g.
+	// "agent.labels['node-pool'] == 'spot' ? 2 : 0"
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=4096
+	Expression string `json:"expression"`
+
+	// Mode "Score" adds the result, a number or a bool counting as 1 or 0,
+	// times Weight to the agent's score. "Require" excludes agents for
+	// which the expression, which must be a bool, is false.
+	// +kubebuilder:validation:Enum=Score;Require
+	// +kubebuilder:default=Score
+	Mode string `json:"mode,omitempty"`
+
+	// Weight multiplies the result of a Score policy
+	// +kubebuilder:default=1
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// TaskPlacementSpec configures node hints for task Jobs
+type TaskPlacementSpec struct {
+	// Strategy "BinPack" prefers the fullest node that fits a task, keeping
+	// emptier nodes free for large tasks. "Default" leaves placement to the
+	// scheduler.
+	// +kubebuilder:validation:Enum=Default;BinPack
+	// +kubebuilder:default=Default
+	Strategy string `json:"strategy,omitempty"`
+
+	// LargeTaskCPU marks tasks requesting at least this much CPU as large,
+	// e.g. "4"
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	LargeTaskCPU string `json:"largeTaskCPU,omitempty"`
+
+	// LargeTaskMemory marks tasks requesting at least this much memory as
+	// large, e.g. "16Gi"
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	LargeTaskMemory string `json:"largeTaskMemory,omitempty"`
+
+	// ReservedNodes is the number of emptiest nodes small tasks avoid while
+	// another node fits them
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	ReservedNodes int32 `json:"reservedNodes,omitempty"`
 }
 
 // AutoScalingSpec defines auto-scaling configuration
@@ -129,6 +1249,40 @@ type AutoScalingSpec struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:default=20
 	ScaleDownThreshold int32 `json:"scaleDownThreshold,omitempty"`
+
+	// TopologyRatios switches to per-agent-type scaling on pending SwarmTasks
+	// and task latency instead of cluster-wide CPU. Keys are agent types;
+	// types without an entry are left alone.
+	TopologyRatios map[string]AgentTypeScaling `json:"topologyRatios,omitempty"`
+
+	// TargetQueueDepth is the number of pending tasks a single agent is
+	// expected to absorb; each agent type is sized to ceil(queue / target)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=2
+	TargetQueueDepth int32 `json:"targetQueueDepth,omitempty"`
+
+	// TargetTaskLatency is the p95 task duration (e.g. "10m") above which an
+	// agent type with queued tasks gets one more agent than it has
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	TargetTaskLatency string `json:"targetTaskLatency,omitempty"`
+
+	// ScaleDownStabilization is how long after the last scaling event agents
+	// may be removed again
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	ScaleDownStabilization string `json:"scaleDownStabilization,omitempty"`
+}
+
+// AgentTypeScaling bounds the number of agents of one type
+// +kubebuilder:validation:XValidation:rule="!has(self.minAgents) || !has(self.maxAgents) || self.minAgents <= self.maxAgents",message="minAgents must not exceed maxAgents"
+type AgentTypeScaling struct {
+	// MinAgents of this type, kept even when the queue is empty
+	// +kubebuilder:validation:Minimum=0
+	MinAgents int32 `json:"minAgents,omitempty"`
+
+	// MaxAgents of this type, defaults to the cluster's maxAgents
+	// +kubebuilder:validation:Minimum=0
+	MaxAgents int32 `json:"maxAgents,omitempty"`
 }
 
 // ScalingMetric defines a metric for auto-scaling
@@ -164,6 +1318,281 @@ type SwarmClusterStatus struct {
 
 	// TopologyStatus contains topology-specific status information
 	TopologyStatus map[string]string `json:"topologyStatus,omitempty"`
+
+	// TopologyMigration tracks the move of the agents to a new topology
+	TopologyMigration *TopologyMigrationStatus `json:"topologyMigration,omitempty"`
+
+	// AgentTypeScaling reports the last queue-based scaling decision per
+	// agent type
+	AgentTypeScaling []AgentTypeScalingStatus `json:"agentTypeScaling,omitempty"`
+
+	// AgentSchedules reports whether each scheduled agent type is within
+	// its active hours
+	AgentSchedules []AgentScheduleStatus `json:"agentSchedules,omitempty"`
+
+	// Simulation summarizes the changes the operator would make while the
+	// cluster is annotated with swarm.claudeflow.io/simulate: "true"
+	Simulation *SimulationStatus `json:"simulation,omitempty"`
+
+	// ExecutorRollout tracks the executor image rollout
+	ExecutorRollout *ExecutorRolloutStatus `json:"executorRollout,omitempty"`
+
+	// HiveMindStatus reports the hive-mind replicas and which replica owns
+	// each partition
+	HiveMindStatus *HiveMindStatus `json:"hiveMindStatus,omitempty"`
+
+	// MessageBus reports the health of the message bus
+	MessageBus *MessageBusStatus `json:"messageBus,omitempty"`
+
+	// SLOs reports the compliance and error budget of each task SLO
+	SLOs []SLOStatus `json:"slos,omitempty"`
+
+	// WarmPool reports the size of the warm pool
+	WarmPool *WarmPoolStatus `json:"warmPool,omitempty"`
+
+	// HealthScore is the weighted health of the cluster from 0 to 100
+	HealthScore *int32 `json:"healthScore,omitempty"`
+
+	// HealthSignals are the signals the health score was computed from
+	HealthSignals []HealthSignalStatus `json:"healthSignals,omitempty"`
+
+	// Queen reports the elected queen in centralized queen mode
+	Queen *QueenStatus `json:"queen,omitempty"`
+
+	// Registration reports where external agents find the registration
+	// token
+	Registration *AgentRegistrationStatus `json:"registration,omitempty"`
+}
+
+// TopologyMigrationStatus is the progress of a topology change
+type TopologyMigrationStatus struct {
+	// From is the topology the agents are moving away from
+	From SwarmTopology `json:"from,omitempty"`
+
+	// To is the topology the agents are moving to
+	To SwarmTopology `json:"to"`
+
+	// StartTime is when the migration started
+	StartTime metav1.Time `json:"startTime"`
+
+	// LastBatchTime is when the last batch of agents got their new peers
+	LastBatchTime *metav1.Time `json:"lastBatchTime,omitempty"`
+
+	// LastBatch names the agents of the last batch
+	LastBatch []string `json:"lastBatch,omitempty"`
+
+	// MigratedAgents counts the agents that got their new peers
+	MigratedAgents int32 `json:"migratedAgents,omitempty"`
+
+	// RemainingAgents counts the agents still on their old peers
+	RemainingAgents int32 `json:"remainingAgents,omitempty"`
+}
+
+// AgentRegistrationStatus is the registration state of a cluster
+type AgentRegistrationStatus struct {
+	// TokenSecret is the Secret holding the registration token under the
+	// key "token"
+	TokenSecret string `json:"tokenSecret"`
+
+	// ExternalAgents counts the registered external agents
+	ExternalAgents int32 `json:"externalAgents,omitempty"`
+}
+
+// QueenStatus is the elected queen of a cluster
+type QueenStatus struct {
+	// Agent that is the queen
+	Agent string `json:"agent"`
+
+	// ElectedTime is when the agent became the queen
+	ElectedTime metav1.Time `json:"electedTime"`
+
+	// Failovers counts the queens replaced after their pod failed
+	Failovers int32 `json:"failovers,omitempty"`
+}
+
+// AgentScheduleStatus is the schedule state of one agent type
+type AgentScheduleStatus struct {
+	// AgentType the state applies to
+	AgentType AgentType `json:"agentType"`
+
+	// Active is true while agents of the type run
+	Active bool `json:"active"`
+
+	// Reason the type is active or not: ActiveHours, OffHours, Override
+	// or InvalidSchedule
+	Reason string `json:"reason"`
+
+	// NextTransition is when the type is next scaled up or down
+	NextTransition *metav1.Time `json:"nextTransition,omitempty"`
+}
+
+// HealthSignalStatus is the score of one health signal
+type HealthSignalStatus struct {
+	// Name of the signal, e.g. agentReadiness
+	Name string `json:"name"`
+
+	// Score from 0 to 100
+	Score int32 `json:"score"`
+
+	// Weight the score counted with
+	Weight int32 `json:"weight"`
+
+	// Message describes the observation the score is based on
+	Message string `json:"message,omitempty"`
+}
+
+// WarmPoolStatus is the observed state of the warm pool
+type WarmPoolStatus struct {
+	// Desired number of idle pods
+	Desired int32 `json:"desired"`
+
+	// Ready pods waiting for a task
+	Ready int32 `json:"ready"`
+
+	// Pending pods that are not ready yet
+	Pending int32 `json:"pending"`
+
+	// RecentStarts is the number of tasks started within the demand window
+	RecentStarts int32 `json:"recentStarts"`
+}
+
+// SLOStatus is the state of a task SLO over its window
+type SLOStatus struct {
+	// Name of the SLO
+	Name string `json:"name"`
+
+	// Events is the number of tasks measured in the window
+	Events int32 `json:"events"`
+
+	// Compliance is the percentage of measured tasks that met the threshold
+	Compliance string `json:"compliance"`
+
+	// ErrorBudgetRemaining is the percentage of the window's error budget
+	// left, negative once it is overspent
+	ErrorBudgetRemaining string `json:"errorBudgetRemaining"`
+
+	// BurnRate is how fast the error budget was spent over the last hour.
+	// At 1 the budget lasts exactly the window.
+	BurnRate string `json:"burnRate"`
+}
+
+// HiveMindStatus is the observed state of the hive-mind sync service
+type HiveMindStatus struct {
+	// Replicas and ReadyReplicas of the sync service
+	Replicas      int32 `json:"replicas"`
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// Partitions lists the owner of every partition
+	Partitions []HiveMindPartition `json:"partitions,omitempty"`
+
+	// LastRebalanceTime is when partition ownership last changed
+	LastRebalanceTime *metav1.Time `json:"lastRebalanceTime,omitempty"`
+
+	// SyncLagSeconds is how far the furthest replica trails its peers,
+	// unset when no replica reported it
+	SyncLagSeconds *int64 `json:"syncLagSeconds,omitempty"`
+}
+
+// MessageBusStatus is the state of the message bus
+type MessageBusStatus struct {
+	// URL agents connect to
+	URL string `json:"url"`
+
+	// Replicas and ReadyReplicas of the broker
+	Replicas      int32 `json:"replicas"`
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// Users is the number of broker users, one per agent or agent pool
+	Users int32 `json:"users"`
+
+	// Routes is the number of peer inboxes agents may publish to
+	Routes int32 `json:"routes"`
+}
+
+// HiveMindPartition is the ownership of one partition
+type HiveMindPartition struct {
+	// Partition number
+	Partition int32 `json:"partition"`
+
+	// Owner is the sync service pod serving the partition
+	Owner string `json:"owner"`
+
+	// Agents is the number of agents hashed into the partition
+	Agents int32 `json:"agents"`
+}
+
+// ExecutorRolloutStatus is the progress of an executor image rollout
+type ExecutorRolloutStatus struct {
+	// Image the status refers to, a new image restarts the rollout
+	Image string `json:"image"`
+
+	// Phase of the rollout. A Paused rollout sends new tasks to the current
+	// image again, change the image to roll out a fix.
+	// +kubebuilder:validation:Enum=Progressing;Paused;Completed
+	Phase string `json:"phase"`
+
+	// Step is the index of the current step
+	Step int32 `json:"step"`
+
+	// Percent of new tasks currently receiving the image
+	Percent int32 `json:"percent"`
+
+	// StepStartedAt is when the current step began
+	StepStartedAt *metav1.Time `json:"stepStartedAt,omitempty"`
+
+	// CanaryJobs and CanaryFailures count finished Jobs on the new image
+	CanaryJobs     int32 `json:"canaryJobs,omitempty"`
+	CanaryFailures int32 `json:"canaryFailures,omitempty"`
+
+	// BaselineJobs and BaselineFailures count finished Jobs on other images
+	BaselineJobs     int32 `json:"baselineJobs,omitempty"`
+	BaselineFailures int32 `json:"baselineFailures,omitempty"`
+
+	// Message explains the phase
+	Message string `json:"message,omitempty"`
+}
+
+// SimulationStatus summarizes a dry-run of the cluster spec
+type SimulationStatus struct {
+	// ObservedGeneration is the spec generation that was simulated
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// SimulatedAt is when the report was computed
+	SimulatedAt metav1.Time `json:"simulatedAt"`
+
+	// Creates is the number of objects that would be created
+	Creates int32 `json:"creates"`
+
+	// Updates is the number of objects that would be changed
+	Updates int32 `json:"updates"`
+
+	// Deletes is the number of objects that would be deleted
+	Deletes int32 `json:"deletes"`
+
+	// ReportConfigMap names the ConfigMap listing every change
+	ReportConfigMap string `json:"reportConfigMap,omitempty"`
+}
+
+// AgentTypeScalingStatus is the scaling state of one agent type
+type AgentTypeScalingStatus struct {
+	// Type of the agents
+	Type AgentType `json:"type"`
+
+	// Agents of this type when the decision was made
+	Agents int32 `json:"agents"`
+
+	// DesiredAgents of this type
+	DesiredAgents int32 `json:"desiredAgents"`
+
+	// QueueDepth is the number of pending tasks waiting for this type
+	QueueDepth int32 `json:"queueDepth"`
+
+	// P95Latency of recent tasks run by this type, empty without samples
+	P95Latency string `json:"p95Latency,omitempty"`
+
+	// LimitedBy names what holds DesiredAgents below the demand of the
+	// type, a ResourceQuota of the namespace or the free node capacity
+	LimitedBy string `json:"limitedBy,omitempty"`
 }
 
 // TaskStatistics contains task execution statistics
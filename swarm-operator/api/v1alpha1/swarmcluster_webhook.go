@@ -0,0 +1,262 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/claude-flow/swarm-operator/pkg/policy"
+	"github.com/claude-flow/swarm-operator/pkg/schedule"
+)
+
+// SetupWebhookWithManager registers the SwarmCluster webhooks with the
+// manager
+func (r *SwarmCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-swarm-claudeflow-io-v1alpha1-swarmcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=swarm.claudeflow.io,resources=swarmclusters,verbs=create;update,versions=v1alpha1,name=vswarmcluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SwarmCluster{}
+
+// ValidateCreate implements webhook.Validator
+func (r *SwarmCluster) ValidateCreate() (admission.Warnings, error) {
+	return nil, r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (r *SwarmCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	return nil, r.validate()
+}
+
+// ValidateDelete implements webhook.Validator
+func (r *SwarmCluster) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *SwarmCluster) validate() error {
+	allErrs := ValidateSchedulingPolicies(r.Spec.TaskDistribution.Policies,
+		field.NewPath("spec", "taskDistribution", "policies"))
+	allErrs = append(allErrs, validateToolBundles(r.Spec.ToolBundles, r.Spec.CapabilityToolBundles, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateAgentSchedules(r.Spec.AgentSchedules, field.NewPath("spec", "agentSchedules"))...)
+	allErrs = append(allErrs, validateAgentTypeOverrides(r.Spec.AgentTypes, field.NewPath("spec", "agentTypes"))...)
+	allErrs = append(allErrs, validateFairQueuing(r.Spec.TaskDistribution.FairQueuing, field.NewPath("spec", "taskDistribution", "fairQueuing"))...)
+	allErrs = append(allErrs, validateSharedWorkspace(r.Spec.SharedWorkspace, field.NewPath("spec", "sharedWorkspace"))...)
+	if r.Spec.Monitoring != nil {
+		allErrs = append(allErrs, validateTaskTelemetry(r.Spec.Monitoring.TaskTelemetry, field.NewPath("spec", "monitoring", "taskTelemetry"))...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "SwarmCluster"},
+		r.Name, allErrs)
+}
+
+// ValidateSchedulingPolicies compiles the CEL expressions of the policies
+// and checks that their result types suit their modes
+func ValidateSchedulingPolicies(policies []SchedulingPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, p := range policies {
+		idxPath := fldPath.Index(i)
+		if seen[p.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), p.Name))
+		}
+		seen[p.Name] = true
+
+		if p.Weight < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), p.Weight, "must not be negative"))
+		}
+		if err := policy.Check(p.Expression, p.Mode); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("expression"), p.Expression, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateFairQueuing checks that every queue weight is positive
+func validateFairQueuing(fairQueuing *FairQueuingSpec, fldPath *field.Path) field.ErrorList {
+	if fairQueuing == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	for key, weight := range fairQueuing.Weights {
+		if weight < 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("weights").Key(key), weight, "must be at least 1"))
+		}
+	}
+	return allErrs
+}
+
+// validateSharedWorkspace checks that the workspace is mounted at an
+// absolute path of its own and comes from one backend
+func validateSharedWorkspace(workspace *SharedWorkspaceSpec, fldPath *field.Path) field.ErrorList {
+	if workspace == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	if workspace.MountPath != "" && (!path.IsAbs(workspace.MountPath) || path.Clean(workspace.MountPath) == "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("mountPath"), workspace.MountPath, "must be an absolute path other than /"))
+	}
+	if workspace.NFS != nil && workspace.CSI != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("csi"), "may not be set together with nfs"))
+	}
+	return allErrs
+}
+
+// exemplarLabelName matches Prometheus label names
+var exemplarLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// validateTaskTelemetry checks that resource attribute keys fit into
+// OTEL_RESOURCE_ATTRIBUTES and exemplar labels are Prometheus label names
+func validateTaskTelemetry(telemetry *TaskTelemetrySpec, fldPath *field.Path) field.ErrorList {
+	if telemetry == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	for key := range telemetry.ResourceAttributes {
+		if key == "" || strings.ContainsAny(key, ",= ") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("resourceAttributes").Key(key), key,
+				"must not be empty or contain commas, equal signs or spaces"))
+		}
+	}
+	for key := range telemetry.ExemplarLabels {
+		if !exemplarLabelName.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("exemplarLabels").Key(key), key,
+				"must be a Prometheus label name"))
+		}
+	}
+	return allErrs
+}
+
+// validateToolBundles checks that bundle names and mount paths are unique
+// and that capabilities only reference defined bundles
+func validateToolBundles(bundles []ToolBundle, capabilities map[string][]string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{}
+	mountPaths := map[string]bool{}
+	for i, bundle := range bundles {
+		idxPath := fldPath.Child("toolBundles").Index(i)
+		if names[bundle.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), bundle.Name))
+		}
+		names[bundle.Name] = true
+		if bundle.MountPath != "" {
+			if mountPaths[bundle.MountPath] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("mountPath"), bundle.MountPath))
+			}
+			mountPaths[bundle.MountPath] = true
+		}
+	}
+
+	for capability, refs := range capabilities {
+		for i, name := range refs {
+			if !names[name] {
+				allErrs = append(allErrs, field.NotFound(fldPath.Child("capabilityToolBundles").Key(capability).Index(i), name))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateAgentSchedules checks that the schedules and time zones parse
+func validateAgentSchedules(schedules []AgentSchedule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, s := range schedules {
+		if _, err := schedule.NewWindow(s.Schedule, s.Duration.Duration, s.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), s.Schedule, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// agentTypes lists the known agent types
+var agentTypes = []string{
+	string(ResearcherAgent), string(CoderAgent), string(AnalystAgent), string(OptimizerAgent),
+	string(CoordinatorAgent), string(ArchitectAgent), string(TesterAgent), string(ReviewerAgent),
+	string(DocumenterAgent), string(MonitorAgent), string(SpecialistAgent),
+}
+
+// validateAgentTypeOverrides checks that overrides name known agent types,
+// parse as quantities and leave the operator's variables alone
+func validateAgentTypeOverrides(overrides map[AgentType]AgentTypeOverride, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	keys := make([]string, 0, len(overrides))
+	for agentType := range overrides {
+		keys = append(keys, string(agentType))
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		override := overrides[AgentType(key)]
+		path := fldPath.Key(key)
+		if !containsAgentType(agentTypes, key) {
+			allErrs = append(allErrs, field.NotSupported(path, key, agentTypes))
+		}
+		if res := override.Resources; res != nil {
+			for _, q := range []struct{ name, value string }{
+				{"cpu", res.CPU}, {"memory", res.Memory}, {"storage", res.Storage}, {"gpu", res.GPU},
+			} {
+				if q.value == "" {
+					continue
+				}
+				if _, err := resource.ParseQuantity(q.value); err != nil {
+					allErrs = append(allErrs, field.Invalid(path.Child("resources", q.name), q.value, err.Error()))
+				}
+			}
+		}
+		names := map[string]bool{}
+		for i, e := range override.Env {
+			envPath := path.Child("env").Index(i).Child("name")
+			if strings.HasPrefix(e.Name, "SWARM_") {
+				allErrs = append(allErrs, field.Forbidden(envPath, fmt.Sprintf("%s is reserved for the operator", e.Name)))
+			}
+			if names[e.Name] {
+				allErrs = append(allErrs, field.Duplicate(envPath, e.Name))
+			}
+			names[e.Name] = true
+		}
+	}
+	return allErrs
+}
+
+func containsAgentType(types []string, agentType string) bool {
+	for _, t := range types {
+		if t == agentType {
+			return true
+		}
+	}
+	return false
+}
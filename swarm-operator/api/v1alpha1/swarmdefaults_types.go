@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwarmDefaultsName is the name of the SwarmDefaults that applies to its
+// namespace
+const SwarmDefaultsName = "default"
+
+// SwarmDefaultsSpec holds the defaults of the tasks a namespace is consulted
+// for. Tasks resolve each setting through a chain of namespaces: the task's
+// own, the swarm namespace of its cluster's namespaceConfig and the
+// operator's swarm namespace. The first namespace providing a setting wins.
+// +kubebuilder:validation:XValidation:rule="!has(self.credentials) || self.credentials.all(k, k in ['github-credentials', 'aws-credentials', 'azure-credentials', 'gcp-credentials'])",message="credentials must be one of github-credentials, aws-credentials, azure-credentials and gcp-credentials"
+type SwarmDefaultsSpec struct {
+	// Credentials maps the well-known credential secrets to the secrets of
+	// this namespace that provide them, e.g. github-credentials:
+	// team-a-github. The well-known secrets themselves are used when not
+	// mapped.
+	// +optional
+	Credentials map[string]string `json:"credentials,omitempty"`
+
+	// ExecutorImage runs the tasks that set no executorImage
+	// +optional
+	ExecutorImage string `json:"executorImage,omitempty"`
+
+	// ExecutorWindowsImage runs the Windows tasks that set no executorImage
+	// +optional
+	ExecutorWindowsImage string `json:"executorWindowsImage,omitempty"`
+
+	// NodeSelector is merged into the node selector of task pods. Closer
+	// namespaces win on conflicting keys.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=sdef
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="the SwarmDefaults of a namespace must be named default"
+//+kubebuilder:printcolumn:name="Executor Image",type=string,JSONPath=`.spec.executorImage`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SwarmDefaults is the Schema for the swarmdefaults API. The one named
+// default holds the task defaults of its namespace.
+type SwarmDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SwarmDefaultsSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwarmDefaultsList contains a list of SwarmDefaults
+type SwarmDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwarmDefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwarmDefaults{}, &SwarmDefaultsList{})
+}
@@ -13,6 +13,9 @@ const (
 	MemoryTypePattern     MemoryType = "pattern"
 	MemoryTypeDecision    MemoryType = "decision"
 	MemoryTypeCheckpoint  MemoryType = "checkpoint"
+	MemoryTypeTaskResult  MemoryType = "task-result"
+	MemoryTypeTaskArchive MemoryType = "task-archive"
+	MemoryTypeAgentConfig MemoryType = "agent-config"
 )
 
 // SwarmMemorySpec defines the desired state of SwarmMemory
@@ -52,11 +55,20 @@ type SwarmMemorySpec struct {
 
 	// Priority for cache retention (0-100)
 	Priority int32 `json:"priority,omitempty"`
+
+	// ExpiryPolicy decides what happens once the TTL elapses. Delete removes
+	// the entry, Retain keeps it in the Expired phase for inspection. The
+	// value is dropped from the memory backend either way.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Delete
+	// +optional
+	ExpiryPolicy string `json:"expiryPolicy,omitempty"`
 }
 
 // SwarmMemoryStatus defines the observed state of SwarmMemory
 type SwarmMemoryStatus struct {
 	// Phase of the memory entry
+	// +kubebuilder:validation:Enum=Pending;Active;Expired
 	Phase string `json:"phase,omitempty"`
 
 	// Size of the stored value in bytes
@@ -86,6 +98,9 @@ type SwarmMemoryStatus struct {
 	// StorageBackend where this is stored
 	StorageBackend string `json:"storageBackend,omitempty"`
 
+	// ObservedGeneration is the spec generation last written to the backend
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions for the memory entry
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -44,6 +45,7 @@ type SwarmMemoryStoreSpec struct {
 
 	// StorageSize is the persistent storage size for SQLite
 	// +kubebuilder:default="10Gi"
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
 	StorageSize string `json:"storageSize,omitempty"`
 
 	// StorageClass for the PVC
@@ -67,9 +69,11 @@ type SwarmMemoryStoreSpec struct {
 
 	// GCInterval is the garbage collection interval
 	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
 	GCInterval string `json:"gcInterval,omitempty"`
 
 	// BackupInterval for automatic backups
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
 	BackupInterval string `json:"backupInterval,omitempty"`
 
 	// BackupRetention is how many backups to keep
@@ -97,6 +101,202 @@ type SwarmMemoryStoreSpec struct {
 	// EnableVacuum enables automatic database vacuuming
 	// +kubebuilder:default=true
 	EnableVacuum bool `json:"enableVacuum,omitempty"`
+
+	// AgentCache injects a caching memory proxy sidecar into every agent of
+	// the referenced SwarmCluster
+	AgentCache *AgentCacheSpec `json:"agentCache,omitempty"`
+
+	// TLSSecretName names the Secret with tls.crt, tls.key and ca.crt the
+	// memory service requires mTLS with. Empty serves plaintext.
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Replication adds read replicas that follow the primary and take
+	// over when it fails
+	Replication *MemoryReplicationSpec `json:"replication,omitempty"`
+
+	// FailureDomains spreads the primary and its followers over zones.
+	// They are spread softly when unset.
+	FailureDomains *FailureDomainSpec `json:"failureDomains,omitempty"`
+
+	// VectorIndex stores embeddings of entries and patterns and serves
+	// similarity search over them
+	VectorIndex *VectorIndexSpec `json:"vectorIndex,omitempty"`
+
+	// Maintenance checks the integrity of the SQLite database, compacts it
+	// and keeps verified backups in recurring maintenance windows
+	Maintenance *MemoryMaintenanceSpec `json:"maintenance,omitempty"`
+
+	// Encryption encrypts the SQLite database and its backups at rest
+	// with SQLCipher
+	Encryption *MemoryEncryptionSpec `json:"encryption,omitempty"`
+}
+
+// MemoryEncryptionSpec encrypts a SQLite memory store with a key from a
+// Secret. Changing the key version rotates the key: the memory service is
+// stopped while Jobs re-encrypt the database of every memory pod and its
+// backups.
+type MemoryEncryptionSpec struct {
+	// Enabled encrypts the database. Disabling it decrypts the database
+	// again.
+	Enabled bool `json:"enabled"`
+
+	// KeySecretName names the Secret in the store's namespace holding the
+	// database keys, one key per version
+	// +kubebuilder:validation:Required
+	KeySecretName string `json:"keySecretName"`
+
+	// KeyVersion is the key of the Secret the database is encrypted with
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]{0,14}[a-z0-9])?$`
+	// +kubebuilder:default=v1
+	KeyVersion string `json:"keyVersion,omitempty"`
+
+	// KMS makes the keys of the Secret data keys wrapped by a key
+	// management service, which the memory pods unwrap at startup
+	KMS *MemoryKMSSpec `json:"kms,omitempty"`
+}
+
+// MemoryKMSSpec selects the key encryption key the database keys are
+// wrapped with
+type MemoryKMSSpec struct {
+	// Provider of the key encryption key
+	// +kubebuilder:validation:Enum=aws-kms;gcp-kms;azure-keyvault;vault-transit
+	Provider string `json:"provider"`
+
+	// KeyURI identifies the key encryption key, e.g. an AWS KMS key ARN
+	// +kubebuilder:validation:Required
+	KeyURI string `json:"keyURI"`
+}
+
+// MemoryMaintenanceSpec schedules the maintenance Jobs of a SQLite memory
+// store. A Job runs once in every window: it runs PRAGMA integrity_check,
+// then VACUUM (with enableVacuum) and ANALYZE, and backs up the verified
+// database to the store volume.
+type MemoryMaintenanceSpec struct {
+	// Enabled turns on maintenance Jobs
+	Enabled bool `json:"enabled"`
+
+	// Schedule is a five field cron expression at which the maintenance
+	// window opens
+	// +kubebuilder:default="0 3 * * *"
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration the window stays open. No Job starts after it closes.
+	// +kubebuilder:default="1h"
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// TimeZone the schedule is evaluated in, as an IANA name
+	// +kubebuilder:default=UTC
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// AutoRestore restores the last verified backup through the memory
+	// service when the integrity check finds corruption
+	AutoRestore bool `json:"autoRestore,omitempty"`
+}
+
+// VectorIndexSpec configures semantic search over the memory store
+type VectorIndexSpec struct {
+	// Enabled turns on the vector index
+	Enabled bool `json:"enabled"`
+
+	// Backend keeps the ANN index in the SQLite database with sqlite-vss,
+	// or in an external Qdrant
+	// +kubebuilder:validation:Enum=sqlite-vss;qdrant
+	// +kubebuilder:default=sqlite-vss
+	Backend string `json:"backend,omitempty"`
+
+	// Dimensions of the embeddings
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4096
+	// +kubebuilder:default=1536
+	Dimensions int32 `json:"dimensions,omitempty"`
+
+	// Metric compares embeddings
+	// +kubebuilder:validation:Enum=cosine;l2;dot
+	// +kubebuilder:default=cosine
+	Metric string `json:"metric,omitempty"`
+
+	// EmbeddingModel the memory service embeds entries and query texts
+	// with. Empty requires writers and queries to supply vectors.
+	EmbeddingModel string `json:"embeddingModel,omitempty"`
+
+	// Qdrant is the external index used with the qdrant backend
+	Qdrant *QdrantSpec `json:"qdrant,omitempty"`
+
+	// RecallAgentTypes get semantic recall wired into their environment.
+	// Defaults to researcher and analyst agents.
+	RecallAgentTypes []AgentType `json:"recallAgentTypes,omitempty"`
+}
+
+// QdrantSpec points the vector index at a Qdrant instance
+type QdrantSpec struct {
+	// URL of the Qdrant HTTP API, e.g. http://qdrant.vector.svc:6333
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// Collection holding the embeddings. Defaults to the store name.
+	Collection string `json:"collection,omitempty"`
+
+	// APIKeySecretRef selects the Qdrant API key
+	APIKeySecretRef *corev1.SecretKeySelector `json:"apiKeySecretRef,omitempty"`
+}
+
+// MemoryReplicationSpec configures the follower replicas of the memory service
+type MemoryReplicationSpec struct {
+	// Enabled runs followers next to the primary
+	Enabled bool `json:"enabled"`
+
+	// Replicas is the number of followers
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5
+	// +kubebuilder:default=1
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Mode selects how followers receive changes: wal streams WAL frames,
+	// snapshot ships periodic database snapshots
+	// +kubebuilder:validation:Enum=wal;snapshot
+	// +kubebuilder:default=wal
+	Mode string `json:"mode,omitempty"`
+
+	// SnapshotInterval is how often snapshots are shipped in snapshot mode
+	// and how often WAL followers resync from a full snapshot
+	// +kubebuilder:default="10m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	SnapshotInterval string `json:"snapshotInterval,omitempty"`
+
+	// FailoverTimeout is how long the primary may be unavailable before a
+	// follower is promoted
+	// +kubebuilder:default="30s"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	FailoverTimeout string `json:"failoverTimeout,omitempty"`
+
+	// MaxLagSeconds excludes followers lagging further behind from read
+	// traffic and promotion
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=30
+	MaxLagSeconds int64 `json:"maxLagSeconds,omitempty"`
+}
+
+// AgentCacheSpec configures the per-agent memory proxy sidecar
+type AgentCacheSpec struct {
+	// Enabled turns on sidecar injection
+	Enabled bool `json:"enabled"`
+
+	// Image of the memory proxy
+	// +kubebuilder:default="claudeflow/swarm-memory-proxy:latest"
+	Image string `json:"image,omitempty"`
+
+	// MaxEntries is the LRU capacity of each agent cache
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=500
+	MaxEntries int32 `json:"maxEntries,omitempty"`
+
+	// DefaultTTL bounds how long an entry stays cached, e.g. "5m"
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	DefaultTTL string `json:"defaultTTL,omitempty"`
+
+	// SyncURL is the hive-mind sync endpoint invalidations are published to
+	SyncURL string `json:"syncURL,omitempty"`
 }
 
 // SwarmMemoryStoreStatus defines the observed state of SwarmMemoryStore
@@ -143,6 +343,142 @@ type SwarmMemoryStoreStatus struct {
 
 	// Endpoints for accessing the memory service
 	Endpoints SwarmMemoryEndpoints `json:"endpoints,omitempty"`
+
+	// AgentCaches reports the cache effectiveness of each agent's memory proxy
+	AgentCaches []AgentCacheStatus `json:"agentCaches,omitempty"`
+
+	// Primary is the pod currently accepting writes when replication is
+	// enabled
+	Primary string `json:"primary,omitempty"`
+
+	// Replicas reports the role and replication lag of every memory pod
+	Replicas []MemoryReplicaStatus `json:"replicas,omitempty"`
+
+	// BackendLatencyMillis is the response time of the last probe of the
+	// memory service HTTP API, -1 when it failed
+	BackendLatencyMillis *int64 `json:"backendLatencyMillis,omitempty"`
+
+	// Maintenance reports the last maintenance Job and the next window
+	Maintenance *MemoryMaintenanceStatus `json:"maintenance,omitempty"`
+
+	// Encryption reports the key the database is encrypted with
+	Encryption *MemoryEncryptionStatus `json:"encryption,omitempty"`
+}
+
+// MemoryEncryptionStatus reports the encryption of the database and a key
+// rotation in progress
+type MemoryEncryptionStatus struct {
+	// ActiveKeyVersion is the key version the database is encrypted with,
+	// empty while it is plaintext
+	ActiveKeyVersion string `json:"activeKeyVersion,omitempty"`
+
+	// LastRotationTime is when the last key rotation finished
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// Rotation is the key rotation in progress
+	Rotation *MemoryKeyRotationStatus `json:"rotation,omitempty"`
+}
+
+// MemoryKeyRotationStatus reports a key rotation in progress
+type MemoryKeyRotationStatus struct {
+	// KeyVersion the database is re-encrypted with, empty when it is
+	// decrypted
+	KeyVersion string `json:"keyVersion,omitempty"`
+
+	// PreviousKeyVersions the databases may still be encrypted with. A
+	// rotation replacing a failed one tries the versions of both.
+	PreviousKeyVersions []string `json:"previousKeyVersions,omitempty"`
+
+	// StartTime is when the memory service was stopped for the rotation
+	StartTime metav1.Time `json:"startTime"`
+
+	// Jobs re-encrypting the database of each memory pod
+	Jobs []string `json:"jobs,omitempty"`
+
+	// Failed reports whether a Job failed. The memory service stays down
+	// until a new key version or the previous one is set.
+	Failed bool `json:"failed,omitempty"`
+}
+
+// MemoryMaintenanceStatus reports the results of the last maintenance Job
+type MemoryMaintenanceStatus struct {
+	// LastJob is the name of the last finished maintenance Job
+	LastJob string `json:"lastJob,omitempty"`
+
+	// LastRunTime is when the last maintenance Job finished
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// Integrity is the result of the last integrity check: ok, corrupt or
+	// unknown when the Job did not report one
+	Integrity string `json:"integrity,omitempty"`
+
+	// IntegrityMessage is the first problem the integrity check found
+	IntegrityMessage string `json:"integrityMessage,omitempty"`
+
+	// SizeBeforeBytes and SizeAfterBytes are the database size around the
+	// compaction
+	SizeBeforeBytes int64 `json:"sizeBeforeBytes,omitempty"`
+	SizeAfterBytes  int64 `json:"sizeAfterBytes,omitempty"`
+
+	// LastGoodBackup is the newest backup taken of a verified database
+	LastGoodBackup string `json:"lastGoodBackup,omitempty"`
+
+	// RestoredFrom is the backup the last corruption was repaired from
+	RestoredFrom string `json:"restoredFrom,omitempty"`
+
+	// LastRestoreTime is when the database was last restored
+	LastRestoreTime *metav1.Time `json:"lastRestoreTime,omitempty"`
+
+	// NextWindow is when the next maintenance window opens
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+}
+
+// MemoryReplicaStatus reports one pod of a replicated memory service
+type MemoryReplicaStatus struct {
+	// Pod name
+	Pod string `json:"pod"`
+
+	// Role is primary or follower
+	Role string `json:"role"`
+
+	// Ready reports whether the pod passes its readiness checks
+	Ready bool `json:"ready"`
+
+	// Readable reports whether the pod serves read traffic
+	Readable bool `json:"readable"`
+
+	// Position is the last WAL frame or snapshot sequence applied
+	Position int64 `json:"position,omitempty"`
+
+	// LagSeconds is how far the pod trails the primary
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+
+	// LastSyncTime is when the pod last applied changes from the primary
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+// AgentCacheStatus reports the memory proxy cache of one agent
+type AgentCacheStatus struct {
+	// Agent name
+	Agent string `json:"agent"`
+
+	// Entries currently cached
+	Entries int32 `json:"entries,omitempty"`
+
+	// Hits served from the cache
+	Hits int64 `json:"hits,omitempty"`
+
+	// Misses forwarded to the memory service
+	Misses int64 `json:"misses,omitempty"`
+
+	// Evictions due to the LRU capacity
+	Evictions int64 `json:"evictions,omitempty"`
+
+	// HitRate as a percentage
+	HitRate string `json:"hitRate,omitempty"`
+
+	// LastUpdated is when the stats were collected
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
 }
 
 // SwarmMemoryEndpoints contains the service endpoints
@@ -155,6 +491,13 @@ type SwarmMemoryEndpoints struct {
 
 	// Metrics endpoint for Prometheus
 	Metrics string `json:"metrics,omitempty"`
+
+	// Read endpoint spreading queries over the primary and followers
+	Read string `json:"read,omitempty"`
+
+	// Search endpoint for similarity queries when the vector index is
+	// enabled
+	Search string `json:"search,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -165,6 +508,8 @@ type SwarmMemoryEndpoints struct {
 //+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
 //+kubebuilder:printcolumn:name="Storage",type=string,JSONPath=`.status.databaseSize`
 //+kubebuilder:printcolumn:name="Entries",type=integer,JSONPath=`.status.entryCount`
+//+kubebuilder:printcolumn:name="Primary",type=string,JSONPath=`.status.primary`,priority=1
+//+kubebuilder:printcolumn:name="Key",type=string,JSONPath=`.status.encryption.activeKeyVersion`,priority=1
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // SwarmMemoryStore is the Schema for the swarmmemorystores API
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwarmOperatorConfigSpec holds the operator-wide defaults. Fields left
+// empty keep the value of the matching manager flag. Changes take effect
+// without restarting the operator, for resources created afterwards.
+type SwarmOperatorConfigSpec struct {
+	// Executor defaults of task Jobs
+	Executor *OperatorExecutorConfig `json:"executor,omitempty"`
+
+	// Namespaces are the default namespaces of swarm and hive-mind
+	// components
+	Namespaces *OperatorNamespaceConfig `json:"namespaces,omitempty"`
+
+	// StorageClass of task volumes and memory stores that name none
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// BackoffLimit bounds the pod retries of a task Job before the
+	// attempt fails and the task retry policy takes over
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// Priority maps task priorities to the swarm PriorityClasses
+	Priority *OperatorPriorityConfig `json:"priority,omitempty"`
+
+	// GarbageCollection overrides the retention of task resources
+	GarbageCollection *OperatorGCConfig `json:"garbageCollection,omitempty"`
+
+	// ImageScanning checks the executor image of a task for
+	// vulnerabilities before its Job is created. Off unless set.
+	ImageScanning *OperatorImageScanConfig `json:"imageScanning,omitempty"`
+
+	// LogLevel of the operator's logs. Unlike the other fields it takes
+	// effect right away, e.g. to turn on debug logs while investigating a
+	// task.
+	// +kubebuilder:validation:Enum=debug;info;error
+	LogLevel string `json:"logLevel,omitempty"`
+}
+
+// Actions on images over the vulnerability thresholds
+const (
+	ImageScanEnforce = "Enforce"
+	ImageScanWarn    = "Warn"
+)
+
+// OperatorImageScanConfig gates task Jobs on a vulnerability scan of their
+// executor image
+type OperatorImageScanConfig struct {
+	// URL of the scanner. The operator POSTs {"image": "<image>"} and
+	// reads the JSON report of the scanner from the response.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Format of the reports
+	// +kubebuilder:validation:Enum=trivy;grype
+	// +kubebuilder:default=trivy
+	Format string `json:"format,omitempty"`
+
+	// Action on images over the thresholds: Enforce holds the task Pending
+	// until the image or the thresholds change, Warn runs it with a
+	// warning event
+	// +kubebuilder:validation:Enum=Enforce;Warn
+	// +kubebuilder:default=Enforce
+	Action string `json:"action,omitempty"`
+
+	// MaxCritical is the number of critical vulnerabilities an image may
+	// have
+	// +kubebuilder:validation:Minimum=0
+	MaxCritical int32 `json:"maxCritical,omitempty"`
+
+	// MaxHigh is the number of high vulnerabilities an image may have,
+	// any number when unset
+	// +kubebuilder:validation:Minimum=0
+	MaxHigh *int32 `json:"maxHigh,omitempty"`
+
+	// FailOpen runs tasks whose image could not be scanned instead of
+	// holding them
+	FailOpen bool `json:"failOpen,omitempty"`
+}
+
+// OperatorGCConfig overrides the garbage collection flags of the manager.
+// The garbage collector sweeps the volumes the operator created for tasks
+// that were deleted, and the GitHub token secrets of tasks that finished or
+// were deleted.
+type OperatorGCConfig struct {
+	// KeepCompletedStorageFor is how long task volumes are kept after the
+	// task was deleted. 0s keeps them.
+	KeepCompletedStorageFor *metav1.Duration `json:"keepCompletedStorageFor,omitempty"`
+
+	// KeepTokenSecretsFor is how long GitHub token secrets are kept after
+	// the task finished or was deleted. 0s keeps them.
+	KeepTokenSecretsFor *metav1.Duration `json:"keepTokenSecretsFor,omitempty"`
+
+	// DryRun reports what would be deleted in events, logs and metrics
+	// without deleting it
+	DryRun *bool `json:"dryRun,omitempty"`
+}
+
+// OperatorPriorityConfig overrides the priority class flags of the manager
+type OperatorPriorityConfig struct {
+	// Classes creates the swarm-critical, -high, -medium and -low
+	// PriorityClasses and runs task pods with the one of their priority
+	Classes *bool `json:"classes,omitempty"`
+
+	// Preemption lets critical task pods preempt lower priority pods when
+	// the cluster is out of capacity. The other classes never preempt.
+	Preemption *bool `json:"preemption,omitempty"`
+}
+
+// OperatorExecutorConfig overrides the executor flags of the manager
+type OperatorExecutorConfig struct {
+	// Image runs tasks that set no executorImage
+	Image string `json:"image,omitempty"`
+
+	// WindowsImage runs Windows tasks that set no executorImage
+	WindowsImage string `json:"windowsImage,omitempty"`
+
+	// ScriptsConfigMap holds the entrypoint scripts mounted into executors
+	ScriptsConfigMap string `json:"scriptsConfigMap,omitempty"`
+
+	// InjectCredentialSecrets injects the github-, aws-, azure- and
+	// gcp-credentials secrets into task Jobs
+	InjectCredentialSecrets *bool `json:"injectCredentialSecrets,omitempty"`
+
+	// ProgressURL is the endpoint executors POST progress updates to
+	ProgressURL string `json:"progressURL,omitempty"`
+}
+
+// OperatorNamespaceConfig overrides the namespace flags of the manager
+type OperatorNamespaceConfig struct {
+	// Swarm is the default namespace of swarm agents
+	Swarm string `json:"swarm,omitempty"`
+
+	// HiveMind is the default namespace of hive-mind components
+	HiveMind string `json:"hiveMind,omitempty"`
+}
+
+// SwarmOperatorConfigStatus reports what the operator applied
+type SwarmOperatorConfigStatus struct {
+	// ObservedGeneration is the generation last validated
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastApplied is the spec the operator runs with. It is kept when a
+	// later spec fails validation.
+	LastApplied *SwarmOperatorConfigSpec `json:"lastApplied,omitempty"`
+
+	// LastAppliedTime is when LastApplied took effect
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// Conditions of the config
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName=soc
+//+kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+//+kubebuilder:printcolumn:name="Last Applied",type=date,JSONPath=`.status.lastAppliedTime`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SwarmOperatorConfig is the Schema for the swarmoperatorconfigs API. The
+// operator follows the one named by its --operator-config flag.
+type SwarmOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwarmOperatorConfigSpec   `json:"spec,omitempty"`
+	Status SwarmOperatorConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwarmOperatorConfigList contains a list of SwarmOperatorConfig
+type SwarmOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwarmOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwarmOperatorConfig{}, &SwarmOperatorConfigList{})
+}
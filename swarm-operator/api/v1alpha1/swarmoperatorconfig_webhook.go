@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the SwarmOperatorConfig webhooks with
+// the manager
+func (r *SwarmOperatorConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-swarm-claudeflow-io-v1alpha1-swarmoperatorconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=swarm.claudeflow.io,resources=swarmoperatorconfigs,verbs=create;update,versions=v1alpha1,name=vswarmoperatorconfig.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SwarmOperatorConfig{}
+
+// ValidateCreate implements webhook.Validator
+func (r *SwarmOperatorConfig) ValidateCreate() (admission.Warnings, error) {
+	return nil, r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (r *SwarmOperatorConfig) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	return nil, r.validate()
+}
+
+// ValidateDelete implements webhook.Validator
+func (r *SwarmOperatorConfig) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *SwarmOperatorConfig) validate() error {
+	allErrs := ValidateOperatorConfig(&r.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "SwarmOperatorConfig"},
+		r.Name, allErrs)
+}
+
+// ValidateOperatorConfig checks the operator defaults. The operator runs
+// the same checks before applying a config, so invalid configs are never
+// applied even without the webhook.
+func ValidateOperatorConfig(spec *SwarmOperatorConfigSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if executor := spec.Executor; executor != nil {
+		executorPath := fldPath.Child("executor")
+		for _, image := range [][2]string{{"image", executor.Image}, {"windowsImage", executor.WindowsImage}} {
+			if strings.ContainsAny(image[1], " \t\n") {
+				allErrs = append(allErrs, field.Invalid(executorPath.Child(image[0]), image[1], "must be an image reference"))
+			}
+		}
+		if executor.ScriptsConfigMap != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(executor.ScriptsConfigMap) {
+				allErrs = append(allErrs, field.Invalid(executorPath.Child("scriptsConfigMap"), executor.ScriptsConfigMap, msg))
+			}
+		}
+		if executor.ProgressURL != "" {
+			u, err := url.Parse(executor.ProgressURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(executorPath.Child("progressURL"), executor.ProgressURL, "must be an http or https URL"))
+			}
+		}
+	}
+
+	if namespaces := spec.Namespaces; namespaces != nil {
+		for _, namespace := range [][2]string{{"swarm", namespaces.Swarm}, {"hiveMind", namespaces.HiveMind}} {
+			if namespace[1] == "" {
+				continue
+			}
+			for _, msg := range validation.IsDNS1123Label(namespace[1]) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("namespaces", namespace[0]), namespace[1], msg))
+			}
+		}
+	}
+
+	if spec.StorageClass != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.StorageClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("storageClass"), spec.StorageClass, msg))
+		}
+	}
+	switch spec.LogLevel {
+	case "", "debug", "info", "error":
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("logLevel"), spec.LogLevel, []string{"debug", "info", "error"}))
+	}
+	if spec.BackoffLimit != nil && *spec.BackoffLimit < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("backoffLimit"), *spec.BackoffLimit, "must not be negative"))
+	}
+	if scan := spec.ImageScanning; scan != nil {
+		scanPath := fldPath.Child("imageScanning")
+		u, err := url.Parse(scan.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(scanPath.Child("url"), scan.URL, "must be an http or https URL"))
+		}
+		switch scan.Format {
+		case "", "trivy", "grype":
+		default:
+			allErrs = append(allErrs, field.NotSupported(scanPath.Child("format"), scan.Format, []string{"trivy", "grype"}))
+		}
+		switch scan.Action {
+		case "", ImageScanEnforce, ImageScanWarn:
+		default:
+			allErrs = append(allErrs, field.NotSupported(scanPath.Child("action"), scan.Action, []string{ImageScanEnforce, ImageScanWarn}))
+		}
+		if scan.MaxCritical < 0 {
+			allErrs = append(allErrs, field.Invalid(scanPath.Child("maxCritical"), scan.MaxCritical, "must not be negative"))
+		}
+		if scan.MaxHigh != nil && *scan.MaxHigh < 0 {
+			allErrs = append(allErrs, field.Invalid(scanPath.Child("maxHigh"), *scan.MaxHigh, "must not be negative"))
+		}
+	}
+	if gc := spec.GarbageCollection; gc != nil {
+		for _, retention := range []struct {
+			name  string
+			value *metav1.Duration
+		}{{"keepCompletedStorageFor", gc.KeepCompletedStorageFor}, {"keepTokenSecretsFor", gc.KeepTokenSecretsFor}} {
+			if retention.value != nil && retention.value.Duration < 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("garbageCollection", retention.name), retention.value.Duration.String(), "must not be negative"))
+			}
+		}
+	}
+	return allErrs
+}
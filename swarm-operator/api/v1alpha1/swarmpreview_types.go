@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwarmPreviewSpec defines the desired state of SwarmPreview
+type SwarmPreviewSpec struct {
+	// Repository to watch for pull requests, in owner/repo form
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`
+	Repository string `json:"repository"`
+
+	// GitHubApp credentials used to list pull requests and report results
+	GitHubApp GitHubAppConfig `json:"githubApp"`
+
+	// Filter selects which open pull requests get a preview swarm
+	Filter PreviewFilter `json:"filter,omitempty"`
+
+	// ClusterTemplate is the spec of the SwarmCluster created per pull request
+	ClusterTemplate SwarmClusterSpec `json:"clusterTemplate"`
+
+	// TaskTemplate is the spec of the validation SwarmTask run per pull
+	// request head commit. swarmCluster is set by the operator.
+	TaskTemplate SwarmTaskSpec `json:"taskTemplate"`
+
+	// TTL after which a preview is torn down even if the pull request is
+	// still open (e.g. "24h")
+	// +kubebuilder:default="24h"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	TTL string `json:"ttl,omitempty"`
+
+	// PollInterval between pull request syncs
+	// +kubebuilder:default="2m"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	PollInterval string `json:"pollInterval,omitempty"`
+
+	// CheckName is the name of the check run reported on the head commit
+	// +kubebuilder:default="swarm-preview"
+	CheckName string `json:"checkName,omitempty"`
+
+	// Comment posts the validation result as a pull request comment
+	// in addition to the check run
+	Comment bool `json:"comment,omitempty"`
+}
+
+// PreviewFilter selects pull requests; empty lists match everything
+type PreviewFilter struct {
+	// BaseBranches the pull request must target
+	BaseBranches []string `json:"baseBranches,omitempty"`
+
+	// Labels the pull request must all carry
+	Labels []string `json:"labels,omitempty"`
+
+	// Authors whose pull requests are previewed
+	Authors []string `json:"authors,omitempty"`
+
+	// IncludeDrafts previews draft pull requests too
+	IncludeDrafts bool `json:"includeDrafts,omitempty"`
+}
+
+// SwarmPreviewStatus defines the observed state of SwarmPreview
+type SwarmPreviewStatus struct {
+	// Previews tracks the preview swarm of each pull request
+	Previews []PullRequestPreview `json:"previews,omitempty"`
+
+	// ActivePreviews counts pull requests with a running preview swarm
+	ActivePreviews int32 `json:"activePreviews"`
+
+	// LastSyncTime of the last successful pull request sync
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Message provides additional information
+	Message string `json:"message,omitempty"`
+}
+
+// PullRequestPreview is the preview state of a single pull request
+type PullRequestPreview struct {
+	// Number of the pull request
+	Number int `json:"number"`
+
+	// HeadSHA the current validation task runs against
+	HeadSHA string `json:"headSHA"`
+
+	// Cluster is the preview SwarmCluster name
+	Cluster string `json:"cluster,omitempty"`
+
+	// Task is the validation SwarmTask name for HeadSHA
+	Task string `json:"task,omitempty"`
+
+	// Phase of the preview
+	// +kubebuilder:validation:Enum=Running;Succeeded;Failed;Expired
+	Phase string `json:"phase,omitempty"`
+
+	// CreatedAt is when the preview swarm was created, the TTL counts from it
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// ReportedSHA is the head commit whose result was posted to GitHub
+	ReportedSHA string `json:"reportedSHA,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=spv
+//+kubebuilder:printcolumn:name="Repository",type=string,JSONPath=`.spec.repository`
+//+kubebuilder:printcolumn:name="Active",type=integer,JSONPath=`.status.activePreviews`
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SwarmPreview is the Schema for the swarmpreviews API. It runs a short-lived
+// swarm and validation task for every matching open pull request.
+type SwarmPreview struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwarmPreviewSpec   `json:"spec,omitempty"`
+	Status SwarmPreviewStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwarmPreviewList contains a list of SwarmPreview
+type SwarmPreviewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwarmPreview `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwarmPreview{}, &SwarmPreviewList{})
+}
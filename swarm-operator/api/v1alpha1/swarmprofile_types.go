@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwarmProfileSpec holds the presets a SwarmCluster inherits through
+// spec.profile. Every field is optional; fields set on the SwarmCluster
+// take precedence over the profile.
+// +kubebuilder:validation:XValidation:rule="!has(self.minAgents) || !has(self.maxAgents) || self.minAgents <= self.maxAgents",message="minAgents must not exceed maxAgents"
+type SwarmProfileSpec struct {
+	// Description of what the profile is tuned for
+	Description string `json:"description,omitempty"`
+
+	// Topology of clusters using the profile
+	// +kubebuilder:validation:Enum=mesh;hierarchical;ring;star;custom
+	Topology SwarmTopology `json:"topology,omitempty"`
+
+	// CustomTopology for the custom topology
+	CustomTopology *CustomTopologySpec `json:"customTopology,omitempty"`
+
+	// MinAgents in the swarm
+	// +kubebuilder:validation:Minimum=1
+	MinAgents int32 `json:"minAgents,omitempty"`
+
+	// MaxAgents in the swarm
+	// +kubebuilder:validation:Minimum=1
+	MaxAgents int32 `json:"maxAgents,omitempty"`
+
+	// Strategy for agent selection
+	// +kubebuilder:validation:Enum=balanced;specialized;adaptive
+	Strategy string `json:"strategy,omitempty"`
+
+	// AgentTemplate for agents
+	AgentTemplate *AgentTemplateSpec `json:"agentTemplate,omitempty"`
+
+	// AgentDeploymentMode for agents
+	// +kubebuilder:validation:Enum=PerAgent;Pooled
+	AgentDeploymentMode AgentDeploymentMode `json:"agentDeploymentMode,omitempty"`
+
+	// TaskDistribution settings
+	TaskDistribution *TaskDistributionSpec `json:"taskDistribution,omitempty"`
+
+	// AutoScaling settings. The agent mix is expressed through
+	// autoScaling.topologyRatios.
+	AutoScaling *AutoScalingSpec `json:"autoScaling,omitempty"`
+
+	// Memory backend settings
+	Memory *MemorySpec `json:"memory,omitempty"`
+
+	// Monitoring settings
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// TLS settings
+	TLS *TLSSpec `json:"tls,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=sprof
+//+kubebuilder:printcolumn:name="Topology",type=string,JSONPath=`.spec.topology`
+//+kubebuilder:printcolumn:name="Description",type=string,JSONPath=`.spec.description`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SwarmProfile is the Schema for the swarmprofiles API. It is a reusable,
+// cluster-wide preset that SwarmClusters reference by name.
+type SwarmProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SwarmProfileSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwarmProfileList contains a list of SwarmProfile
+type SwarmProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwarmProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwarmProfile{}, &SwarmProfileList{})
+}
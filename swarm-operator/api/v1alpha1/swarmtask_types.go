@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -45,10 +47,24 @@ const (
 
 // SwarmTaskSpec defines the desired state of SwarmTask
 type SwarmTaskSpec struct {
-	// SwarmCluster reference
-	SwarmCluster string `json:"swarmCluster"`
+	// SwarmCluster reference. Set it or ClusterSelector.
+	SwarmCluster string `json:"swarmCluster,omitempty"`
+
+	// ClusterSelector runs the task on every SwarmCluster of its namespace
+	// whose labels match, as a child task per cluster whose results are
+	// aggregated in status.clusters. Clusters that start matching after
+	// the task finished are not run.
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// IdempotencyKey suppresses duplicate submissions. While a task with the
+	// same key in the same SwarmCluster is running, or completed within the
+	// last 24 hours, this task is skipped instead of launching a Job.
+	// +kubebuilder:validation:MaxLength=253
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
 
 	// Description of the task
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
 	Description string `json:"description"`
 
 	// Type of task (e.g., "research", "development", "analysis")
@@ -79,7 +95,8 @@ type SwarmTaskSpec struct {
 	// Parameters for task execution
 	Parameters map[string]string `json:"parameters,omitempty"`
 
-	// Timeout in seconds
+	// Timeout in seconds for a single attempt. A Job still running a minute
+	// past the timeout is stopped and the attempt fails.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=300
 	Timeout int32 `json:"timeout,omitempty"`
@@ -87,6 +104,14 @@ type SwarmTaskSpec struct {
 	// RetryPolicy for failed tasks
 	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
 
+	// FallbackStrategy hands the task to other agent types once the
+	// preferred one has used up its retries
+	FallbackStrategy *FallbackStrategy `json:"fallbackStrategy,omitempty"`
+
+	// Hooks run setup containers before the task and teardown containers
+	// after it succeeded
+	Hooks *TaskHooks `json:"hooks,omitempty"`
+
 	// ResultStorage configuration
 	ResultStorage ResultStorageSpec `json:"resultStorage,omitempty"`
 
@@ -97,8 +122,460 @@ type SwarmTaskSpec struct {
 	// GitHubApp configuration for repository access
 	GitHubApp *GitHubAppConfig `json:"githubApp,omitempty"`
 
+	// Checkout clones Repositories into a shared workspace before the
+	// executor starts, using the generated GitHub token when available
+	Checkout *GitCheckoutSpec `json:"checkout,omitempty"`
+
 	// Namespace to run this task in (defaults based on task type)
 	Namespace string `json:"namespace,omitempty"`
+
+	// Preemptible allows the task to run on spot/preemptible nodes
+	Preemptible *PreemptibleSpec `json:"preemptible,omitempty"`
+
+	// Resume tells the executor to continue from the latest checkpoint.
+	// The operator sets it when rescheduling a preempted task.
+	Resume bool `json:"resume,omitempty"`
+
+	// CachePolicy controls result caching. With "reuse" a task whose spec,
+	// pinned inputs, cluster and executor image match a previous successful
+	// run in the same namespace completes immediately with the cached
+	// result. Priority, timeout, retries and other scheduling fields are
+	// not part of the match.
+	// +kubebuilder:validation:Enum=none;reuse
+	// +kubebuilder:default=none
+	CachePolicy string `json:"cachePolicy,omitempty"`
+
+	// PinnedInputs identify the exact input versions (e.g. commit SHAs) that
+	// make up the result cache key together with the task spec
+	PinnedInputs map[string]string `json:"pinnedInputs,omitempty"`
+
+	// PodTemplateOverrides is a strategic merge patch applied to the
+	// executor pod template after the operator builds it, e.g. to set
+	// hostAliases, dnsConfig, priorityClassName or pod annotations.
+	// Fields that would weaken isolation or break the executor are rejected:
+	// host namespaces, hostPath volumes, ephemeral containers, and privileged,
+	// root or added-capability containers among others.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	PodTemplateOverrides *runtime.RawExtension `json:"podTemplateOverrides,omitempty"`
+
+	// PersistentVolumes are claims created for the task and mounted into
+	// the executor
+	PersistentVolumes []TaskVolumeSpec `json:"persistentVolumes,omitempty"`
+
+	// NetworkPolicy restricts the egress of the task's Job pods to the
+	// listed destinations. Without it the pods can reach anything.
+	NetworkPolicy *TaskNetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// ExecutorImage runs the task instead of the operator's default
+	// executor image
+	ExecutorImage string `json:"executorImage,omitempty"`
+
+	// AdditionalSecrets are mounted read-only into the executor
+	AdditionalSecrets []TaskSecretMount `json:"additionalSecrets,omitempty"`
+
+	// Env sets environment variables of the executor, either to a value or
+	// to a single key of a secret in the namespace the task runs in. Names
+	// starting with SWARM_ and the GitHub variables are reserved for the
+	// operator.
+	Env []TaskEnvVar `json:"env,omitempty"`
+
+	// ComputedEnv sets environment variables of the executor to the result
+	// of CEL expressions over the task, its cluster and the cluster's memory
+	// store, evaluated whenever a Job of the task is built, e.g. the
+	// endpoint of the memory backend actually deployed
+	ComputedEnv []ComputedEnvVar `json:"computedEnv,omitempty"`
+
+	// TargetCluster runs the task against the API server of another
+	// Kubernetes cluster, such as one whose workloads it manages
+	TargetCluster *TaskTargetCluster `json:"targetCluster,omitempty"`
+
+	// ConfigTemplates are files rendered by the operator from templates
+	// combining keys of several secrets, e.g. a cloud CLI credentials
+	// file. They are stored in a Secret of the task's Job and mounted
+	// read-only into the executor.
+	ConfigTemplates []TaskConfigTemplate `json:"configTemplates,omitempty"`
+
+	// GPU requests accelerators for the executor. The task is placed on
+	// nodes offering the requested GPU type and fails when none exist.
+	GPU *TaskGPUSpec `json:"gpu,omitempty"`
+
+	// OS of the nodes the executor runs on. Windows tasks are scheduled on
+	// Windows nodes, run PowerShell entrypoints and have their mount paths
+	// moved to the C: drive; Linux-only features are rejected for them.
+	// +kubebuilder:validation:Enum=linux;windows
+	// +kubebuilder:default=linux
+	OS TaskOS `json:"os,omitempty"`
+
+	// Windows tunes the placement and identity of Windows tasks
+	Windows *TaskWindowsSpec `json:"windows,omitempty"`
+
+	// Isolation sandboxes the executor for untrusted code. gvisor and kata
+	// run it under the RuntimeClass of the same name with a hardened
+	// security context, and fail the task when that RuntimeClass is not
+	// installed.
+	// +kubebuilder:validation:Enum=standard;gvisor;kata
+	// +kubebuilder:default=standard
+	Isolation TaskIsolation `json:"isolation,omitempty"`
+
+	// ImageConfig overrides the cluster's for the task's pods. Pull secrets
+	// are added to the cluster's, a pull policy replaces the cluster's and
+	// mirrors take precedence over the cluster's.
+	ImageConfig *ImageConfig `json:"imageConfig,omitempty"`
+
+	// Budget limits the paid API usage of the task. Executors get the
+	// limits in their environment and report usage with their progress;
+	// the task is cancelled once the usage of all its attempts reaches a
+	// limit.
+	Budget *TaskBudget `json:"budget,omitempty"`
+
+	// ColocateWithMemory prefers the node, then the zone, of the pods of
+	// the cluster's SwarmMemoryStore for the task's pods, for tasks that
+	// read swarm memory heavily. The scheduler may still place them
+	// elsewhere; status.memoryPlacement reports where they ran.
+	ColocateWithMemory bool `json:"colocateWithMemory,omitempty"`
+
+	// QueueName hands admission of the task's Job to Kueue: the Job is
+	// created suspended with the kueue.x-k8s.io/queue-name label and runs
+	// once Kueue admits its workload. It names a LocalQueue in the
+	// namespace the Job runs in.
+	// +kubebuilder:validation:MaxLength=63
+	QueueName string `json:"queueName,omitempty"`
+
+	// Mode is how the task runs. job tasks run to completion in a Job.
+	// service tasks are long-running services such as a review bot: the
+	// operator keeps them running in a Deployment behind a Service, rolls
+	// them out when their spec changes and publishes their URL in
+	// status.service.
+	// +kubebuilder:validation:Enum=job;service
+	// +kubebuilder:default=job
+	Mode TaskMode `json:"mode,omitempty"`
+
+	// Service configures the Deployment and Service of a service task
+	Service *TaskServiceSpec `json:"service,omitempty"`
+}
+
+// TaskBudget limits the paid API usage of a task. Unset limits are
+// unlimited.
+// +kubebuilder:validation:XValidation:rule="has(self.maxTokens) || has(self.maxAPICalls) || has(self.maxCost)",message="a budget needs at least one limit"
+type TaskBudget struct {
+	// MaxTokens is the most LLM tokens, input and output, the task may use
+	// +kubebuilder:validation:Minimum=1
+	MaxTokens *int64 `json:"maxTokens,omitempty"`
+
+	// MaxAPICalls is the most calls to paid APIs the task may make
+	// +kubebuilder:validation:Minimum=1
+	MaxAPICalls *int64 `json:"maxAPICalls,omitempty"`
+
+	// MaxCost is the most the task may spend by its executors' estimate,
+	// in US dollars
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:ExclusiveMinimum=true
+	MaxCost *float64 `json:"maxCost,omitempty"`
+}
+
+// TaskMode is how a task runs
+type TaskMode string
+
+const (
+	// JobTaskMode runs the task to completion in a Job
+	JobTaskMode TaskMode = "job"
+	// ServiceTaskMode keeps the task running in a Deployment behind a
+	// Service
+	ServiceTaskMode TaskMode = "service"
+)
+
+// TaskServiceSpec configures how a service task is served
+type TaskServiceSpec struct {
+	// Port the task container serves on, exposed by the Service on the
+	// same port and passed to the executor as SWARM_SERVICE_PORT
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=8080
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Replicas of the service
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ReadinessPath is the HTTP path pods are probed on before they get
+	// traffic. Without it pods are ready once the port accepts
+	// connections.
+	// +optional
+	ReadinessPath string `json:"readinessPath,omitempty"`
+
+	// ReadinessGates are pod condition types that must also be true for
+	// pods to be ready, set by controllers such as load balancers
+	// +optional
+	ReadinessGates []string `json:"readinessGates,omitempty"`
+}
+
+// TaskIsolation is the sandbox a task's executor runs in
+type TaskIsolation string
+
+const (
+	// StandardIsolation runs the executor under the node's default runtime
+	StandardIsolation TaskIsolation = "standard"
+	// GVisorIsolation runs the executor in the gVisor user-space kernel
+	GVisorIsolation TaskIsolation = "gvisor"
+	// KataIsolation runs the executor in a Kata Containers micro-VM
+	KataIsolation TaskIsolation = "kata"
+)
+
+// TaskOS is the operating system a task runs on
+type TaskOS string
+
+const (
+	// LinuxOS runs the task on Linux nodes
+	LinuxOS TaskOS = "linux"
+	// WindowsOS runs the task on Windows nodes
+	WindowsOS TaskOS = "windows"
+)
+
+// TaskWindowsSpec holds the settings of Windows tasks
+type TaskWindowsSpec struct {
+	// Build restricts the task to nodes of this Windows build, e.g.
+	// 10.0.20348 for Windows Server 2022. Process-isolated containers only
+	// run on the build their image was made for.
+	Build string `json:"build,omitempty"`
+
+	// RunAsUserName is the Windows user the containers run as, e.g.
+	// ContainerUser or ContainerAdministrator
+	RunAsUserName string `json:"runAsUserName,omitempty"`
+}
+
+// GPUVendor identifies the device plugin a GPU is exposed by
+type GPUVendor string
+
+const (
+	// NvidiaGPU requests nvidia.com resources
+	NvidiaGPU GPUVendor = "nvidia"
+	// AMDGPU requests amd.com/gpu
+	AMDGPU GPUVendor = "amd"
+	// IntelGPU requests gpu.intel.com/i915
+	IntelGPU GPUVendor = "intel"
+)
+
+// TaskGPUSpec describes the GPUs a task needs
+type TaskGPUSpec struct {
+	// Vendor of the GPU
+	// +kubebuilder:validation:Enum=nvidia;amd;intel
+	// +kubebuilder:default=nvidia
+	Vendor GPUVendor `json:"vendor,omitempty"`
+
+	// Count of whole GPUs, MIG instances or GPU shares
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Count int32 `json:"count,omitempty"`
+
+	// MIGProfile requests NVIDIA Multi-Instance GPU slices such as 1g.5gb,
+	// exposed as nvidia.com/mig-<profile> by the mixed MIG strategy
+	MIGProfile string `json:"migProfile,omitempty"`
+
+	// Shared requests time-sliced shares of a GPU, exposed as
+	// nvidia.com/gpu.shared, instead of whole GPUs
+	Shared bool `json:"shared,omitempty"`
+
+	// Product restricts the task to nodes labelled with this GPU product,
+	// e.g. NVIDIA-A100-SXM4-40GB
+	Product string `json:"product,omitempty"`
+
+	// ResourceName overrides the extended resource derived from the other
+	// fields, for device plugins that use other names
+	ResourceName string `json:"resourceName,omitempty"`
+}
+
+// TaskSecretMount mounts a secret from the task namespace into the executor
+type TaskSecretMount struct {
+	// Name of the secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// MountPath inside the executor container
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// Optional lets the task start when the secret does not exist
+	Optional bool `json:"optional,omitempty"`
+}
+
+// TaskEnvVar is an environment variable of the executor
+type TaskEnvVar struct {
+	// Name of the variable
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Value of the variable
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom reads the value from a secret instead
+	ValueFrom *TaskEnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// TaskTargetCluster is the cluster a task works on through a kubeconfig
+type TaskTargetCluster struct {
+	// KubeconfigSecret is the Secret holding the kubeconfig, in the
+	// namespace the task runs in. Its credentials and certificates must be
+	// inline, exec and auth-provider plugins, file references and proxies
+	// are refused.
+	// +kubebuilder:validation:MinLength=1
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+
+	// Key of the kubeconfig in the Secret
+	// +kubebuilder:default="kubeconfig"
+	Key string `json:"key,omitempty"`
+
+	// Context of the kubeconfig to use, its current context when empty.
+	// The task only gets this context and the cluster and user it names.
+	Context string `json:"context,omitempty"`
+
+	// DisableServiceAccountToken keeps the token of the task's service
+	// account out of the pod, so the task can only reach the target
+	// cluster
+	DisableServiceAccountToken bool `json:"disableServiceAccountToken,omitempty"`
+}
+
+// ImageScanStatus sums up a vulnerability scan of an executor image
+type ImageScanStatus struct {
+	// Image that was scanned
+	Image string `json:"image"`
+
+	// Digest the scanner resolved the image to
+	Digest string `json:"digest,omitempty"`
+
+	// Vulnerabilities of the image by severity
+	Critical int32 `json:"critical"`
+	High     int32 `json:"high"`
+	Medium   int32 `json:"medium"`
+	Low      int32 `json:"low"`
+	Unknown  int32 `json:"unknown,omitempty"`
+
+	// ScanTime is when the scanner reported on the image
+	ScanTime metav1.Time `json:"scanTime"`
+}
+
+// ComputedEnvVar is an environment variable of the executor computed by
+// the operator
+type ComputedEnvVar struct {
+	// Name of the variable
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Expression is a CEL expression over task, cluster and memory, the
+	// objects as stored in the API server. memory is null for clusters
+	// without a memory store. It must evaluate to a string, number or
+	// bool, e.g. memory.status.endpoints.http.
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+}
+
+// TaskEnvVarSource is where the value of a variable comes from
+type TaskEnvVarSource struct {
+	// SecretKeyRef selects a key of a secret in the namespace the task
+	// runs in
+	// +kubebuilder:validation:Required
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
+// TaskConfigTemplate is a config file rendered from secret keys
+type TaskConfigTemplate struct {
+	// Name of the file, unique within the task
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	Name string `json:"name"`
+
+	// MountPath of the file in the executor container
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// Template is a Go text/template. {{ secret "name" "key" }} inserts a
+	// key of a secret in the namespace the task runs in, quote and b64enc
+	// escape or encode it.
+	// +kubebuilder:validation:MinLength=1
+	Template string `json:"template"`
+}
+
+// TaskNetworkPolicySpec lists the destinations a task may connect to
+type TaskNetworkPolicySpec struct {
+	// AllowedCIDRs the executor may connect to, e.g. 10.0.0.0/8
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// AllowedDomains are resolved to their current addresses whenever the
+	// task is reconciled. NetworkPolicies only match addresses, so domains
+	// behind frequently changing addresses are better covered by CIDRs.
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// AllowedNamespaces whose pods the executor may connect to
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// Ports restricts the allowed destinations to these TCP ports. Empty
+	// allows every port.
+	Ports []int32 `json:"ports,omitempty"`
+
+	// AllowDNS permits lookups against the cluster DNS
+	// +kubebuilder:default=true
+	AllowDNS *bool `json:"allowDNS,omitempty"`
+}
+
+// VolumeReclaimPolicy decides what happens to a task volume once the task finishes
+type VolumeReclaimPolicy string
+
+const (
+	// RetainVolumeReclaimPolicy keeps the claim and its data
+	RetainVolumeReclaimPolicy VolumeReclaimPolicy = "Retain"
+	// DeleteVolumeReclaimPolicy deletes the claim
+	DeleteVolumeReclaimPolicy VolumeReclaimPolicy = "Delete"
+	// RecycleVolumeReclaimPolicy wipes the claim contents but keeps the claim
+	RecycleVolumeReclaimPolicy VolumeReclaimPolicy = "Recycle"
+)
+
+// TaskVolumeSpec describes a persistent volume claim used by a task
+type TaskVolumeSpec struct {
+	// Name of the volume, the claim is named <task>-<name>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// MountPath inside the executor container
+	MountPath string `json:"mountPath"`
+
+	// StorageClass of the claim, defaults to the cluster default class
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// Size of the claim. Increasing it expands an existing claim when
+	// AllowExpansion is set and the storage class supports it.
+	// +kubebuilder:default="1Gi"
+	// +kubebuilder:validation:Pattern=`^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+	Size string `json:"size,omitempty"`
+
+	// ReclaimPolicy applied when the task completes, fails or is deleted
+	// +kubebuilder:validation:Enum=Retain;Delete;Recycle
+	// +kubebuilder:default=Retain
+	ReclaimPolicy VolumeReclaimPolicy `json:"reclaimPolicy,omitempty"`
+
+	// AllowExpansion lets the operator grow the claim when Size increases
+	AllowExpansion bool `json:"allowExpansion,omitempty"`
+}
+
+// PreemptibleSpec configures spot/preemptible node support for a task
+type PreemptibleSpec struct {
+	// Enabled lets the task Job tolerate spot/preemptible node taints
+	Enabled bool `json:"enabled"`
+
+	// Tolerations added in addition to the well-known spot taints
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// StableNodeSelector selects the nodes used when rescheduling after a
+	// preemption. Defaults to excluding well-known spot node labels.
+	StableNodeSelector map[string]string `json:"stableNodeSelector,omitempty"`
+
+	// CheckpointGracePeriodSeconds gives the executor time to write a
+	// checkpoint after it is signalled
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=30
+	CheckpointGracePeriodSeconds *int64 `json:"checkpointGracePeriodSeconds,omitempty"`
 }
 
 // SubtaskSpec defines a subtask
@@ -157,6 +634,134 @@ type RetryPolicy struct {
 	BackoffMultiplier float64 `json:"backoffMultiplier,omitempty"`
 }
 
+// FallbackStrategy is a chain of agent types a failing task escalates
+// through, e.g. coder, then specialist, then coordinator
+type FallbackStrategy struct {
+	// Stages are tried in order after the preferred agent type fails
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=5
+	Stages []FallbackStage `json:"stages"`
+}
+
+// FallbackStage runs the task on one agent type
+type FallbackStage struct {
+	// AgentType that takes over the task
+	AgentType AgentType `json:"agentType"`
+
+	// MaxRetries on this agent type before moving to the next stage,
+	// defaults to the retry policy's, or 0 without one
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+}
+
+// Outcomes of a fallback chain
+const (
+	FallbackSucceeded = "Succeeded"
+	FallbackExhausted = "Exhausted"
+)
+
+// FallbackStatus reports how far a task walked its fallback chain
+type FallbackStatus struct {
+	// Stage is the current stage, 0 being the preferred agent type and i
+	// the i-th entry of spec.fallbackStrategy.stages
+	Stage int32 `json:"stage"`
+
+	// AgentType the current stage runs on
+	AgentType AgentType `json:"agentType"`
+
+	// Outcome is Succeeded once the current stage completed the task, or
+	// Exhausted once every stage failed
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// TaskHooks are containers run around the task. They get the task's
+// environment and volume mounts.
+type TaskHooks struct {
+	// PreStart containers run in order as init containers of every
+	// attempt, e.g. to download datasets
+	// +kubebuilder:validation:MaxItems=10
+	PreStart []corev1.Container `json:"preStart,omitempty"`
+
+	// PostComplete containers run in a separate Job once the task
+	// succeeded, e.g. to upload reports. The task completes when they do.
+	// +kubebuilder:validation:MaxItems=10
+	PostComplete []corev1.Container `json:"postComplete,omitempty"`
+}
+
+// HookPhase is the state of a task hook
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type HookPhase string
+
+const (
+	HookPending   HookPhase = "Pending"
+	HookRunning   HookPhase = "Running"
+	HookSucceeded HookPhase = "Succeeded"
+	HookFailed    HookPhase = "Failed"
+)
+
+// Hooks a failure can be attributed to
+const (
+	PreStartHook     = "preStart"
+	PostCompleteHook = "postComplete"
+)
+
+// TaskHooksStatus reports the hooks of the current attempt
+type TaskHooksStatus struct {
+	// PreStart is the phase of the preStart hooks
+	PreStart HookPhase `json:"preStart,omitempty"`
+
+	// PostComplete is the phase of the postComplete hooks
+	PostComplete HookPhase `json:"postComplete,omitempty"`
+
+	// PostCompleteJob runs the postComplete hooks
+	PostCompleteJob string `json:"postCompleteJob,omitempty"`
+}
+
+// GitCheckoutSpec configures how task repositories are cloned
+type GitCheckoutSpec struct {
+	// WorkspacePath is where the shared workspace is mounted in the executor
+	// +kubebuilder:default="/workspace"
+	WorkspacePath string `json:"workspacePath,omitempty"`
+
+	// Image of the clone init container, must provide sh and git, or
+	// PowerShell and git for windows tasks
+	Image string `json:"image,omitempty"`
+
+	// Ref to check out (branch, tag or commit SHA), defaults to the default branch
+	Ref string `json:"ref,omitempty"`
+
+	// Depth of the shallow clone, 0 fetches the full history
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	Depth *int32 `json:"depth,omitempty"`
+
+	// SparseCheckout limits the working tree to these directories
+	SparseCheckout []string `json:"sparseCheckout,omitempty"`
+
+	// Repositories overrides the settings above per repository
+	Repositories []RepositoryCheckout `json:"repositories,omitempty"`
+}
+
+// RepositoryCheckout overrides checkout settings for one repository
+type RepositoryCheckout struct {
+	// Repository in owner/repo form, as listed in spec.repositories
+	Repository string `json:"repository"`
+
+	// Ref to check out
+	Ref string `json:"ref,omitempty"`
+
+	// Depth of the shallow clone, 0 fetches the full history
+	// +kubebuilder:validation:Minimum=0
+	Depth *int32 `json:"depth,omitempty"`
+
+	// SparseCheckout limits the working tree to these directories
+	SparseCheckout []string `json:"sparseCheckout,omitempty"`
+
+	// Path relative to the workspace, defaults to the repository name
+	Path string `json:"path,omitempty"`
+}
+
 // GitHubAppConfig defines GitHub App configuration for repository access
 type GitHubAppConfig struct {
 	// AppID is the GitHub App ID
@@ -170,6 +775,7 @@ type GitHubAppConfig struct {
 
 	// TokenTTL is the duration for which generated tokens are valid
 	// +kubebuilder:default="1h"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
 	TokenTTL string `json:"tokenTTL,omitempty"`
 }
 
@@ -205,7 +811,7 @@ type ResultStorageSpec struct {
 // SwarmTaskStatus defines the observed state of SwarmTask
 type SwarmTaskStatus struct {
 	// Phase of the task
-	// +kubebuilder:validation:Enum=Pending;Scheduled;Running;Completed;Failed;Cancelled
+	// +kubebuilder:validation:Enum=Pending;Scheduled;Running;Completed;Failed;Cancelled;DeadLettered;Suspended;Skipped
 	Phase string `json:"phase,omitempty"`
 
 	// StartTime when the task started
@@ -232,10 +838,355 @@ type SwarmTaskStatus struct {
 	// RetryCount tracks retry attempts
 	RetryCount int32 `json:"retryCount"`
 
+	// Attempt counts every Job started for this task. Unlike RetryCount it is
+	// not reset on requeue, so each attempt gets a distinct Job name.
+	Attempt int32 `json:"attempt,omitempty"`
+
+	// JobName is the Job of the current attempt
+	JobName string `json:"jobName,omitempty"`
+
+	// NextRetryTime is when the next attempt may start after a backoff
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+
+	// DispatchSequence orders the tasks fair queuing dispatched in their
+	// cluster, later dispatches have higher numbers
+	DispatchSequence int64 `json:"dispatchSequence,omitempty"`
+
+	// ImageScan is the last vulnerability scan of the executor image
+	ImageScan *ImageScanStatus `json:"imageScan,omitempty"`
+
+	// CheckpointRef points at the latest checkpoint written by the executor
+	CheckpointRef string `json:"checkpointRef,omitempty"`
+
+	// Preemptions counts attempts lost to node preemption or eviction
+	Preemptions int32 `json:"preemptions,omitempty"`
+
+	// ResumeFromCheckpoint is set once an attempt was preempted, so later
+	// attempts continue from CheckpointRef whatever spec.resume says
+	ResumeFromCheckpoint bool `json:"resumeFromCheckpoint,omitempty"`
+
+	// CacheKey is the content hash used for result caching
+	CacheKey string `json:"cacheKey,omitempty"`
+
+	// CacheHit is true when the result was served from the cache
+	CacheHit bool `json:"cacheHit,omitempty"`
+
+	// DuplicateOf names the task this one was skipped as a duplicate of
+	DuplicateOf string `json:"duplicateOf,omitempty"`
+
+	// FailureDigest summarizes the final failure once the task is dead-lettered
+	FailureDigest *FailureDigest `json:"failureDigest,omitempty"`
+
+	// FailureDetails diagnoses the pod of the most recent failed attempt
+	FailureDetails *FailureDetails `json:"failureDetails,omitempty"`
+
+	// ArchivedAt is when the finished task was written to the cluster's
+	// task archive
+	ArchivedAt *metav1.Time `json:"archivedAt,omitempty"`
+
+	// Volumes reports the claims backing spec.persistentVolumes
+	Volumes []TaskVolumeStatus `json:"volumes,omitempty"`
+
+	// Debug reports the interactive debug pod requested with the
+	// swarm.claudeflow.io/debug annotation
+	Debug *TaskDebugStatus `json:"debug,omitempty"`
+
+	// Isolation records the sandbox the executor was run in, for
+	// compliance audits
+	Isolation *TaskIsolationStatus `json:"isolation,omitempty"`
+
+	// Fallback tracks the stage of spec.fallbackStrategy the task is in
+	Fallback *FallbackStatus `json:"fallback,omitempty"`
+
+	// Hooks reports the phases of spec.hooks
+	Hooks *TaskHooksStatus `json:"hooks,omitempty"`
+
+	// Usage is the paid API usage executors reported for the task
+	Usage *TaskUsage `json:"usage,omitempty"`
+
+	// MemoryPlacement reports how close spec.colocateWithMemory got the
+	// task's pod to its memory store
+	MemoryPlacement *MemoryPlacementStatus `json:"memoryPlacement,omitempty"`
+
+	// Queue reports the admission of the Job's Kueue workload when
+	// spec.queueName is set
+	Queue *TaskQueueStatus `json:"queue,omitempty"`
+
+	// Clusters reports the child task of every cluster spec.clusterSelector
+	// matched
+	Clusters []ClusterTaskStatus `json:"clusters,omitempty"`
+
+	// Plan reports the Job manifest rendered for the
+	// swarm.claudeflow.io/plan annotation
+	Plan *TaskPlanStatus `json:"plan,omitempty"`
+
+	// RerunAs names the task the swarm.claudeflow.io/rerun annotation last
+	// cloned this task into
+	RerunAs string `json:"rerunAs,omitempty"`
+
+	// Service reports the Deployment and URL of a service task
+	Service *TaskServiceStatus `json:"service,omitempty"`
+
 	// Message provides additional information
 	Message string `json:"message,omitempty"`
 }
 
+// TaskServiceStatus reports the rollout of a service task
+type TaskServiceStatus struct {
+	// DeploymentName is the Deployment running the service, also the name
+	// of its Service
+	DeploymentName string `json:"deploymentName"`
+
+	// URL the service is reachable on from within the cluster
+	URL string `json:"url"`
+
+	// ObservedGeneration is the spec generation last rolled out completely
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Replicas the service runs
+	Replicas int32 `json:"replicas"`
+
+	// UpdatedReplicas run the current spec
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// ReadyReplicas pass their readiness probe and gates
+	ReadyReplicas int32 `json:"readyReplicas"`
+}
+
+// TaskPlanStatus reports the Job a planned task would run
+type TaskPlanStatus struct {
+	// ObservedGeneration is the spec generation that was rendered
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PlannedAt is when the manifest was rendered
+	PlannedAt metav1.Time `json:"plannedAt"`
+
+	// JobName is the name the Job would be created with
+	JobName string `json:"jobName"`
+
+	// Namespace the Job would be created in
+	Namespace string `json:"namespace"`
+
+	// ConfigMap holds the rendered Job manifest under job.yaml
+	ConfigMap string `json:"configMap"`
+}
+
+// ClusterTaskStatus is the child task a task selecting clusters runs on
+// one of them
+type ClusterTaskStatus struct {
+	// Cluster is the SwarmCluster the child task runs on
+	Cluster string `json:"cluster"`
+
+	// Task is the name of the child SwarmTask
+	Task string `json:"task"`
+
+	// Phase of the child task
+	Phase string `json:"phase,omitempty"`
+
+	// Summary of the child task result, or why it failed
+	Summary string `json:"summary,omitempty"`
+}
+
+// TaskQueueStatus is the admission of a task's Job by Kueue
+type TaskQueueStatus struct {
+	// Name of the LocalQueue the Job was submitted to
+	Name string `json:"name"`
+
+	// Workload is the Kueue workload of the current Job
+	Workload string `json:"workload,omitempty"`
+
+	// ClusterQueue that admitted the workload
+	ClusterQueue string `json:"clusterQueue,omitempty"`
+
+	// Admitted reports whether the workload is admitted and may run
+	Admitted bool `json:"admitted"`
+
+	// AdmissionTime is when the workload was last admitted
+	AdmissionTime *metav1.Time `json:"admissionTime,omitempty"`
+
+	// Evictions counts how often Kueue evicted the workload, e.g. when it
+	// was preempted by a higher priority workload
+	Evictions int32 `json:"evictions,omitempty"`
+
+	// LastEvictionTime is when the workload was last evicted
+	LastEvictionTime *metav1.Time `json:"lastEvictionTime,omitempty"`
+
+	// LastEvictionReason is the reason Kueue gave for the last eviction
+	LastEvictionReason string `json:"lastEvictionReason,omitempty"`
+
+	// Message is the latest message of Kueue about the workload
+	Message string `json:"message,omitempty"`
+}
+
+// MemoryDistance is how far a task pod runs from its memory store
+type MemoryDistance string
+
+const (
+	// SameNodeDistance places the task on a node of a memory store pod
+	SameNodeDistance MemoryDistance = "SameNode"
+	// SameZoneDistance places the task in the zone of a memory store pod
+	SameZoneDistance MemoryDistance = "SameZone"
+	// CrossZoneDistance places the task in a zone without memory store pods
+	CrossZoneDistance MemoryDistance = "CrossZone"
+	// UnknownDistance is reported when the nodes carry no zone label or
+	// the memory store has no scheduled pods
+	UnknownDistance MemoryDistance = "Unknown"
+)
+
+// MemoryPlacementStatus is the placement of a task pod relative to the
+// memory store of its cluster
+type MemoryPlacementStatus struct {
+	// Store is the SwarmMemoryStore the task was co-located with
+	Store string `json:"store"`
+
+	// Distance between the task pod and the closest memory store pod
+	// +kubebuilder:validation:Enum=SameNode;SameZone;CrossZone;Unknown
+	Distance MemoryDistance `json:"distance"`
+
+	// Pod is the task pod the distance was measured for
+	Pod string `json:"pod,omitempty"`
+
+	// Node the task pod runs on
+	Node string `json:"node,omitempty"`
+
+	// MemoryNode the closest memory store pod runs on
+	MemoryNode string `json:"memoryNode,omitempty"`
+}
+
+// TaskIsolationStatus is the effective isolation of a task
+type TaskIsolationStatus struct {
+	// Level the executor runs at
+	Level TaskIsolation `json:"level"`
+
+	// RuntimeClassName the executor pods run under, empty for standard
+	// isolation
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+}
+
+// TaskDebugStatus reports the debug pod cloned from a failed task
+type TaskDebugStatus struct {
+	// PodName of the debug pod, <task>-debug, to kubectl exec into
+	PodName string `json:"podName"`
+
+	// Namespace the debug pod runs in
+	Namespace string `json:"namespace"`
+
+	// StartTime is when the debug pod was created
+	StartTime metav1.Time `json:"startTime"`
+
+	// ExpiresAt is when the debug pod is deleted
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// TaskVolumeStatus reports the state of a task volume claim
+type TaskVolumeStatus struct {
+	// Name of the volume in spec.persistentVolumes
+	Name string `json:"name"`
+
+	// ClaimName of the PersistentVolumeClaim
+	ClaimName string `json:"claimName"`
+
+	// Capacity currently provisioned for the claim
+	Capacity string `json:"capacity,omitempty"`
+
+	// VolumeName of the PersistentVolume bound to the claim
+	VolumeName string `json:"volumeName,omitempty"`
+
+	// Node the last pod mounting the claim ran on. Later attempts and
+	// requeued runs prefer it while it exists.
+	Node string `json:"node,omitempty"`
+
+	// Reclaim is the outcome of the reclaim policy once the task finished
+	// +kubebuilder:validation:Enum=Retained;Deleted;Recycling;Recycled
+	Reclaim string `json:"reclaim,omitempty"`
+}
+
+// FailureDigest captures why a task ended up dead-lettered
+type FailureDigest struct {
+	// JobName of the last failed attempt
+	JobName string `json:"jobName,omitempty"`
+
+	// Attempts made before giving up
+	Attempts int32 `json:"attempts"`
+
+	// Reason reported by the Job or the task container
+	Reason string `json:"reason,omitempty"`
+
+	// ExitCodes of the terminated task containers
+	ExitCodes []int32 `json:"exitCodes,omitempty"`
+
+	// LastLogLines from the task container's termination message
+	LastLogLines []string `json:"lastLogLines,omitempty"`
+
+	// CheckpointRef of the last checkpoint, if any, to resume from
+	CheckpointRef string `json:"checkpointRef,omitempty"`
+
+	// DeadLetteredAt is when the task was moved to DeadLettered
+	DeadLetteredAt metav1.Time `json:"deadLetteredAt"`
+}
+
+// FailureDetails describes why the pod of a failed Job attempt died
+type FailureDetails struct {
+	// JobName of the failed attempt
+	JobName string `json:"jobName"`
+
+	// PodName of the diagnosed pod, empty if the Job left no pod behind
+	PodName string `json:"podName,omitempty"`
+
+	// Attempt the failure belongs to
+	Attempt int32 `json:"attempt"`
+
+	// Reason is the most specific cause found, e.g. OOMKilled, Evicted,
+	// DeadlineExceeded or Error
+	Reason string `json:"reason,omitempty"`
+
+	// Message accompanying the reason
+	Message string `json:"message,omitempty"`
+
+	// Hook is preStart or postComplete when a hook failed rather than the
+	// task itself
+	Hook string `json:"hook,omitempty"`
+
+	// Containers that terminated unsuccessfully, init containers first
+	Containers []ContainerFailure `json:"containers,omitempty"`
+
+	// LogTail holds the last lines logged by the first failed container
+	LogTail []string `json:"logTail,omitempty"`
+
+	// ObservedAt is when the failure was diagnosed
+	ObservedAt metav1.Time `json:"observedAt"`
+}
+
+// ContainerFailure is the last terminated state of a failed container
+type ContainerFailure struct {
+	// Name of the container
+	Name string `json:"name"`
+
+	// InitContainer is true for init containers
+	InitContainer bool `json:"initContainer,omitempty"`
+
+	// ExitCode of the last termination
+	ExitCode int32 `json:"exitCode"`
+
+	// Signal that killed the container, if any
+	Signal int32 `json:"signal,omitempty"`
+
+	// Reason of the last termination, e.g. OOMKilled or Error
+	Reason string `json:"reason,omitempty"`
+
+	// Message of the last termination
+	Message string `json:"message,omitempty"`
+
+	// RestartCount of the container
+	RestartCount int32 `json:"restartCount,omitempty"`
+
+	// StartedAt is when the terminated run started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is when the terminated run ended
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+}
+
 // AssignedAgent represents an agent assigned to the task
 type AssignedAgent struct {
 	// Name of the agent
@@ -297,6 +1248,37 @@ type TaskResult struct {
 	StorageRef string `json:"storageRef,omitempty"`
 }
 
+// TaskUsage is the paid API usage of a task, summed over its attempts
+type TaskUsage struct {
+	// Tokens used, input and output
+	Tokens int64 `json:"tokens,omitempty"`
+
+	// APICalls made to paid APIs
+	APICalls int64 `json:"apiCalls,omitempty"`
+
+	// Cost estimated by the executors, in US dollars
+	Cost float64 `json:"cost,omitempty"`
+
+	// Runs is the usage last reported by each executor run
+	Runs []TaskRunUsage `json:"runs,omitempty"`
+}
+
+// TaskRunUsage is the usage reported by one run of the executor
+type TaskRunUsage struct {
+	// Pod the executor ran in
+	Pod string `json:"pod"`
+
+	// Restart of the executor container within the pod
+	Restart int32 `json:"restart,omitempty"`
+
+	Tokens   int64   `json:"tokens,omitempty"`
+	APICalls int64   `json:"apiCalls,omitempty"`
+	Cost     float64 `json:"cost,omitempty"`
+
+	// LastReportTime is when the run last reported usage
+	LastReportTime metav1.Time `json:"lastReportTime"`
+}
+
 // TaskMetrics contains execution metrics
 type TaskMetrics struct {
 	// ExecutionTime in seconds
@@ -321,7 +1303,11 @@ type TaskMetrics struct {
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
 // +kubebuilder:printcolumn:name="Priority",type="string",JSONPath=".spec.priority"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Agent",type="string",JSONPath=".status.assignedAgents[0].name"
 // +kubebuilder:printcolumn:name="Progress",type="integer",JSONPath=".status.progress"
+// +kubebuilder:printcolumn:name="Job",type="string",JSONPath=".status.jobName",priority=1
+// +kubebuilder:printcolumn:name="Retries",type="integer",JSONPath=".status.retryCount",priority=1
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.service.url",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // SwarmTask is the Schema for the swarmtasks API
@@ -329,6 +1315,9 @@ type SwarmTask struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	// Spec names the SwarmCluster of the task or selects clusters. The task
+	// templates of batches and previews leave that to the operator.
+	// +kubebuilder:validation:XValidation:rule="has(self.swarmCluster) || has(self.clusterSelector)",message="swarmCluster or clusterSelector is required"
 	Spec   SwarmTaskSpec   `json:"spec,omitempty"`
 	Status SwarmTaskStatus `json:"status,omitempty"`
 }
@@ -0,0 +1,670 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/claude-flow/swarm-operator/pkg/computedenv"
+	"github.com/claude-flow/swarm-operator/pkg/configtemplate"
+)
+
+// reservedLabelPrefix is owned by the operator and cannot be overridden
+const reservedLabelPrefix = "swarm.claudeflow.io/"
+
+// disallowedPodSpecFields may not be set through podTemplateOverrides. They
+// either escape pod isolation or break how the operator runs and tracks the
+// executor.
+var disallowedPodSpecFields = map[string]string{
+	"hostNetwork":                  "host namespaces are not allowed",
+	"hostPID":                      "host namespaces are not allowed",
+	"hostIPC":                      "host namespaces are not allowed",
+	"hostUsers":                    "host namespaces are not allowed",
+	"serviceAccountName":           "the executor service account is managed by the operator",
+	"serviceAccount":               "the executor service account is managed by the operator",
+	"automountServiceAccountToken": "the executor service account is managed by the operator",
+	"restartPolicy":                "the restart policy is managed by the operator",
+	"nodeName":                     "use nodeSelector or affinity instead",
+	"runtimeClassName":             "use spec.isolation instead",
+	"ephemeralContainers":          "ephemeral containers are not allowed",
+}
+
+// allowedMetadataFields are the pod metadata fields that may be overridden
+var allowedMetadataFields = map[string]bool{
+	"labels":      true,
+	"annotations": true,
+}
+
+// SetupWebhookWithManager registers the SwarmTask webhooks with the manager
+func (r *SwarmTask) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-swarm-claudeflow-io-v1alpha1-swarmtask,mutating=false,failurePolicy=fail,sideEffects=None,groups=swarm.claudeflow.io,resources=swarmtasks,verbs=create;update,versions=v1alpha1,name=vswarmtask.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &SwarmTask{}
+
+// ValidateCreate implements webhook.Validator
+func (r *SwarmTask) ValidateCreate() (admission.Warnings, error) {
+	return nil, r.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator
+func (r *SwarmTask) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	oldTask, _ := old.(*SwarmTask)
+	return nil, r.validate(oldTask)
+}
+
+// ValidateDelete implements webhook.Validator
+func (r *SwarmTask) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *SwarmTask) validate(old *SwarmTask) error {
+	allErrs := ValidateClusterSelector(&r.Spec, field.NewPath("spec"))
+	if old != nil {
+		allErrs = append(allErrs, ValidateTaskSpecUpdate(&r.Spec, old, field.NewPath("spec"))...)
+	}
+	allErrs = append(allErrs, ValidatePodTemplateOverrides(r.Spec.PodTemplateOverrides,
+		field.NewPath("spec", "podTemplateOverrides"))...)
+	allErrs = append(allErrs, ValidateTaskNetworkPolicy(r.Spec.NetworkPolicy,
+		field.NewPath("spec", "networkPolicy"))...)
+	allErrs = append(allErrs, ValidateTaskGPU(r.Spec.GPU, field.NewPath("spec", "gpu"))...)
+	allErrs = append(allErrs, ValidateTaskOS(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, ValidateTaskIsolation(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, ValidateFallbackStrategy(&r.Spec, field.NewPath("spec", "fallbackStrategy"))...)
+	allErrs = append(allErrs, ValidateTaskHooks(r.Spec.Hooks, field.NewPath("spec", "hooks"))...)
+	allErrs = append(allErrs, ValidateTaskEnv(r.Spec.Env, field.NewPath("spec", "env"))...)
+	allErrs = append(allErrs, ValidateComputedEnv(r.Spec.ComputedEnv, r.Spec.Env, field.NewPath("spec", "computedEnv"))...)
+	allErrs = append(allErrs, ValidateConfigTemplates(r.Spec.ConfigTemplates, field.NewPath("spec", "configTemplates"))...)
+	allErrs = append(allErrs, ValidateTargetCluster(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, ValidateTaskService(&r.Spec, field.NewPath("spec"))...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "SwarmTask"},
+		r.Name, allErrs)
+}
+
+// ValidateClusterSelector checks that a task targets either a cluster or a
+// valid selector of clusters
+func ValidateClusterSelector(spec *SwarmTaskSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case spec.SwarmCluster == "" && spec.ClusterSelector == nil:
+		allErrs = append(allErrs, field.Required(fldPath.Child("swarmCluster"), "set swarmCluster or clusterSelector"))
+	case spec.SwarmCluster != "" && spec.ClusterSelector != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("clusterSelector"), "may not be set together with swarmCluster"))
+	case spec.ClusterSelector != nil:
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(spec.ClusterSelector,
+			metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("clusterSelector"))...)
+	}
+	return allErrs
+}
+
+// TaskDispatched reports whether the operator started running the task.
+// Cache hits and duplicates count as dispatched once they completed.
+func TaskDispatched(task *SwarmTask) bool {
+	status := &task.Status
+	return status.JobName != "" || status.StartTime != nil || status.CompletionTime != nil || len(status.Clusters) > 0
+}
+
+// ValidateTaskSpecUpdate rejects changes to the spec of a dispatched task,
+// whose attempts would otherwise run different specs. Only resume may
+// change, the operator sets it on preempted and drained tasks. Service
+// tasks roll out their changes instead, but stay services.
+func ValidateTaskSpecUpdate(spec *SwarmTaskSpec, old *SwarmTask, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !TaskDispatched(old) {
+		return allErrs
+	}
+	if old.Spec.Mode == ServiceTaskMode {
+		if spec.Mode != ServiceTaskMode {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("mode"), "a dispatched service task stays a service"))
+		}
+		return allErrs
+	}
+	updated := *spec
+	updated.Resume = old.Spec.Resume
+	if !equality.Semantic.DeepEqual(updated, old.Spec) {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			"may not change once the task is dispatched, annotate it with swarm.claudeflow.io/rerun to run it again or create a new task"))
+	}
+	return allErrs
+}
+
+// ValidateTaskService checks the service settings and rejects the features
+// that only apply to tasks running to completion in a Job
+func ValidateTaskService(spec *SwarmTaskSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.Mode != ServiceTaskMode {
+		if spec.Service != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("service"), "only applies to tasks with mode service"))
+		}
+		return allErrs
+	}
+
+	if service := spec.Service; service != nil {
+		if service.Port != 0 {
+			for _, msg := range validation.IsValidPortNum(int(service.Port)) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("service", "port"), service.Port, msg))
+			}
+		}
+		if service.Replicas != nil && *service.Replicas < 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("service", "replicas"), *service.Replicas, "must be at least 1"))
+		}
+		if service.ReadinessPath != "" && !strings.HasPrefix(service.ReadinessPath, "/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("service", "readinessPath"), service.ReadinessPath, "must be an absolute path"))
+		}
+		for i, gate := range service.ReadinessGates {
+			for _, msg := range validation.IsQualifiedName(gate) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("service", "readinessGates").Index(i), gate, msg))
+			}
+		}
+	}
+
+	forbidden := func(child, detail string) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child(child), detail))
+	}
+	if spec.ClusterSelector != nil {
+		forbidden("clusterSelector", "service tasks run on a single cluster")
+	}
+	if spec.QueueName != "" {
+		forbidden("queueName", "Kueue only admits tasks running in a Job")
+	}
+	if spec.Preemptible != nil {
+		forbidden("preemptible", "service tasks are rescheduled by their Deployment")
+	}
+	if spec.Budget != nil {
+		forbidden("budget", "service tasks are not cancelled on their usage")
+	}
+	if spec.CachePolicy == "reuse" {
+		forbidden("cachePolicy", "service tasks have no result to reuse")
+	}
+	if len(spec.ConfigTemplates) > 0 {
+		forbidden("configTemplates", "only tasks running in a Job get rendered config")
+	}
+	if spec.Hooks != nil && len(spec.Hooks.PostComplete) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("hooks", "postComplete"), "service tasks never complete"))
+	}
+	return allErrs
+}
+
+// ValidatePodTemplateOverrides checks that an executor pod template patch
+// only touches fields users are allowed to change
+func ValidatePodTemplateOverrides(overrides *runtime.RawExtension, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if overrides == nil || len(overrides.Raw) == 0 {
+		return allErrs
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(overrides.Raw, &patch); err != nil {
+		return append(allErrs, field.Invalid(fldPath, string(overrides.Raw), fmt.Sprintf("must be a JSON object: %v", err)))
+	}
+
+	for _, key := range sortedKeys(patch) {
+		switch key {
+		case "metadata":
+			allErrs = append(allErrs, validateOverrideMetadata(patch[key], fldPath.Child("metadata"))...)
+		case "spec":
+			allErrs = append(allErrs, validateOverrideSpec(patch[key], fldPath.Child("spec"))...)
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child(key), key, []string{"metadata", "spec"}))
+		}
+	}
+	return allErrs
+}
+
+// ValidateTaskNetworkPolicy checks the destinations of a task egress policy
+func ValidateTaskNetworkPolicy(policy *TaskNetworkPolicySpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if policy == nil {
+		return allErrs
+	}
+
+	for i, cidr := range policy.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedCIDRs").Index(i), cidr, "must be a CIDR such as 10.0.0.0/8"))
+		}
+	}
+	for i, domain := range policy.AllowedDomains {
+		for _, msg := range validation.IsDNS1123Subdomain(domain) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedDomains").Index(i), domain, msg))
+		}
+	}
+	for i, namespace := range policy.AllowedNamespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedNamespaces").Index(i), namespace, msg))
+		}
+	}
+	for i, port := range policy.Ports {
+		for _, msg := range validation.IsValidPortNum(int(port)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ports").Index(i), port, msg))
+		}
+	}
+	return allErrs
+}
+
+// migProfilePattern matches MIG profiles such as 1g.5gb or 1g.10gb+me
+var migProfilePattern = regexp.MustCompile(`^[0-9]+g\.[0-9]+gb(\+me)?$`)
+
+// ValidateTaskGPU checks that a task GPU request maps to a single
+// extended resource
+func ValidateTaskGPU(gpu *TaskGPUSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if gpu == nil {
+		return allErrs
+	}
+
+	nvidia := gpu.Vendor == "" || gpu.Vendor == NvidiaGPU
+	if gpu.MIGProfile != "" {
+		if !nvidia {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("migProfile"), gpu.MIGProfile, "MIG is only available on nvidia GPUs"))
+		} else if !migProfilePattern.MatchString(gpu.MIGProfile) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("migProfile"), gpu.MIGProfile, "must be a MIG profile such as 1g.5gb"))
+		}
+		if gpu.Shared {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("shared"), gpu.Shared, "cannot be combined with migProfile"))
+		}
+	}
+	if gpu.Shared && !nvidia {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("shared"), gpu.Shared, "GPU sharing is only available on nvidia GPUs"))
+	}
+	if gpu.Product != "" && gpu.Vendor == IntelGPU {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("product"), gpu.Product, "is not supported for intel GPUs"))
+	}
+	if gpu.ResourceName != "" {
+		for _, msg := range validation.IsQualifiedName(gpu.ResourceName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("resourceName"), gpu.ResourceName, msg))
+		}
+		if !strings.Contains(gpu.ResourceName, "/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("resourceName"), gpu.ResourceName, "must be a vendor-prefixed extended resource"))
+		}
+	}
+	return allErrs
+}
+
+// linuxOnlyPodSecurityFields and linuxOnlyContainerSecurityFields are
+// rejected by the API server for pods running on Windows
+var (
+	linuxOnlyPodSecurityFields = map[string]bool{
+		"fsGroup": true, "fsGroupChangePolicy": true, "runAsGroup": true, "runAsUser": true,
+		"seLinuxOptions": true, "seccompProfile": true, "supplementalGroups": true, "sysctls": true,
+	}
+	linuxOnlyContainerSecurityFields = map[string]bool{
+		"allowPrivilegeEscalation": true, "capabilities": true, "privileged": true, "procMount": true,
+		"readOnlyRootFilesystem": true, "runAsGroup": true, "runAsUser": true, "seLinuxOptions": true,
+		"seccompProfile": true,
+	}
+)
+
+// ValidateTaskOS rejects the features Windows tasks cannot use: GPUs, the
+// Linux checkout image and Linux-only security settings in pod template
+// overrides
+func ValidateTaskOS(spec *SwarmTaskSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.OS != WindowsOS {
+		if spec.Windows != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("windows"), "only applies to tasks with os windows"))
+		}
+		return allErrs
+	}
+
+	if spec.Windows != nil && spec.Windows.Build != "" {
+		for _, msg := range validation.IsValidLabelValue(spec.Windows.Build) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("windows", "build"), spec.Windows.Build, msg))
+		}
+	}
+	if spec.GPU != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("gpu"), "GPUs are only available to linux tasks"))
+	}
+	if spec.Checkout != nil && spec.Checkout.Image == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("checkout", "image"),
+			"the default checkout image only runs on linux, windows tasks need an image with git and PowerShell"))
+	}
+
+	overrides := spec.PodTemplateOverrides
+	if overrides == nil || len(overrides.Raw) == 0 {
+		return allErrs
+	}
+	var patch struct {
+		Spec map[string]interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(overrides.Raw, &patch); err != nil {
+		// Reported by ValidatePodTemplateOverrides
+		return allErrs
+	}
+	specPath := fldPath.Child("podTemplateOverrides", "spec")
+	if _, ok := patch.Spec["shareProcessNamespace"]; ok {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("shareProcessNamespace"), "is not supported on windows"))
+	}
+	securityContext, _ := patch.Spec["securityContext"].(map[string]interface{})
+	for _, key := range sortedKeys(securityContext) {
+		if linuxOnlyPodSecurityFields[key] {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("securityContext", key), "is not supported on windows"))
+		}
+	}
+	for _, key := range []string{"containers", "initContainers"} {
+		containers, _ := patch.Spec[key].([]interface{})
+		for i, c := range containers {
+			container, _ := c.(map[string]interface{})
+			securityContext, _ := container["securityContext"].(map[string]interface{})
+			for _, name := range sortedKeys(securityContext) {
+				if linuxOnlyContainerSecurityFields[name] {
+					allErrs = append(allErrs, field.Forbidden(specPath.Child(key).Index(i).Child("securityContext", name), "is not supported on windows"))
+				}
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateTaskIsolation rejects sandboxed tasks the sandbox cannot run.
+// Overrides that would undo the executor security context are rejected for
+// every task by ValidatePodTemplateOverrides.
+func ValidateTaskIsolation(spec *SwarmTaskSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.Isolation == "" || spec.Isolation == StandardIsolation {
+		return allErrs
+	}
+	if spec.OS == WindowsOS {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("isolation"), "sandboxed runtimes only run linux tasks"))
+	}
+	return allErrs
+}
+
+// ValidateFallbackStrategy rejects stages that hand the task to the agent
+// type that just failed it
+func ValidateFallbackStrategy(spec *SwarmTaskSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.FallbackStrategy == nil {
+		return allErrs
+	}
+
+	previous := CoderAgent
+	if len(spec.PreferredAgentTypes) > 0 {
+		previous = spec.PreferredAgentTypes[0]
+	}
+	for i, stage := range spec.FallbackStrategy.Stages {
+		path := fldPath.Child("stages").Index(i).Child("agentType")
+		switch {
+		case stage.AgentType == "":
+			allErrs = append(allErrs, field.Required(path, ""))
+		case stage.AgentType == previous:
+			allErrs = append(allErrs, field.Invalid(path, stage.AgentType, "must differ from the agent type of the previous stage"))
+		}
+		previous = stage.AgentType
+	}
+	return allErrs
+}
+
+// ValidateTaskHooks checks that hook containers have an image and unique
+// names. PreStart containers are renamed with a "pre-" prefix.
+func ValidateTaskHooks(hooks *TaskHooks, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if hooks == nil {
+		return allErrs
+	}
+
+	validate := func(containers []corev1.Container, prefix string, fldPath *field.Path) {
+		names := map[string]bool{}
+		for i, container := range containers {
+			path := fldPath.Index(i)
+			if container.Image == "" {
+				allErrs = append(allErrs, field.Required(path.Child("image"), ""))
+			}
+			if container.Name == "" {
+				allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+				continue
+			}
+			for _, msg := range validation.IsDNS1123Label(prefix + container.Name) {
+				allErrs = append(allErrs, field.Invalid(path.Child("name"), container.Name, msg))
+			}
+			if names[container.Name] {
+				allErrs = append(allErrs, field.Duplicate(path.Child("name"), container.Name))
+			}
+			names[container.Name] = true
+		}
+	}
+	validate(hooks.PreStart, "pre-", fldPath.Child("preStart"))
+	validate(hooks.PostComplete, "", fldPath.Child("postComplete"))
+	return allErrs
+}
+
+// reservedEnvNames are set by the operator and cannot be overridden, along
+// with every name starting with SWARM_
+var reservedEnvNames = map[string]bool{"GITHUB_TOKEN": true, "GITHUB_REPOSITORIES": true}
+
+// ValidateTaskEnv checks that variables are unique, not reserved and set
+// from either a value or a secret key
+func ValidateTaskEnv(env []TaskEnvVar, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	for i, e := range env {
+		path := fldPath.Index(i)
+		if strings.HasPrefix(e.Name, "SWARM_") || reservedEnvNames[e.Name] {
+			allErrs = append(allErrs, field.Forbidden(path.Child("name"), fmt.Sprintf("%s is reserved for the operator", e.Name)))
+		}
+		if names[e.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), e.Name))
+		}
+		names[e.Name] = true
+
+		if e.ValueFrom == nil {
+			continue
+		}
+		if e.Value != "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("valueFrom"), "", "may not be set together with value"))
+		}
+		if ref := e.ValueFrom.SecretKeyRef; ref == nil || ref.Name == "" || ref.Key == "" {
+			allErrs = append(allErrs, field.Required(path.Child("valueFrom", "secretKeyRef"), "secret name and key are required"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateComputedEnv checks that computed variables compile, are unique
+// and neither reserved nor set by spec.env
+func ValidateComputedEnv(computed []ComputedEnvVar, env []TaskEnvVar, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	for _, e := range env {
+		names[e.Name] = true
+	}
+	for i, e := range computed {
+		path := fldPath.Index(i)
+		if strings.HasPrefix(e.Name, "SWARM_") || reservedEnvNames[e.Name] {
+			allErrs = append(allErrs, field.Forbidden(path.Child("name"), fmt.Sprintf("%s is reserved for the operator", e.Name)))
+		}
+		if names[e.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), e.Name))
+		}
+		names[e.Name] = true
+		if err := computedenv.Check(e.Expression); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("expression"), e.Expression, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// ValidateTargetCluster checks that tasks running against another cluster
+// leave KUBECONFIG to the operator
+func ValidateTargetCluster(spec *SwarmTaskSpec, fldPath *field.Path) field.ErrorList {
+	if spec.TargetCluster == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	for i, e := range spec.Env {
+		if e.Name == "KUBECONFIG" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("env").Index(i).Child("name"), "KUBECONFIG is set by the operator for targetCluster"))
+		}
+	}
+	for i, e := range spec.ComputedEnv {
+		if e.Name == "KUBECONFIG" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("computedEnv").Index(i).Child("name"), "KUBECONFIG is set by the operator for targetCluster"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateConfigTemplates checks that the templates parse and that their
+// files have unique names and absolute mount paths
+func ValidateConfigTemplates(templates []TaskConfigTemplate, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	paths := map[string]bool{}
+	for i, t := range templates {
+		path := fldPath.Index(i)
+		if names[t.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), t.Name))
+		}
+		names[t.Name] = true
+		if !strings.HasPrefix(t.MountPath, "/") {
+			allErrs = append(allErrs, field.Invalid(path.Child("mountPath"), t.MountPath, "must be an absolute path"))
+		} else if paths[t.MountPath] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("mountPath"), t.MountPath))
+		}
+		paths[t.MountPath] = true
+		if err := configtemplate.Parse(t.Name, t.Template); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("template"), t.Template, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateOverrideMetadata(value interface{}, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return append(allErrs, field.Invalid(fldPath, value, "must be an object"))
+	}
+
+	for _, key := range sortedKeys(metadata) {
+		if !allowedMetadataFields[key] {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(key), "only labels and annotations may be overridden"))
+			continue
+		}
+		values, ok := metadata[key].(map[string]interface{})
+		if !ok {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(key), metadata[key], "must be a map of strings"))
+			continue
+		}
+		if key != "labels" {
+			continue
+		}
+		for _, label := range sortedKeys(values) {
+			if strings.HasPrefix(label, reservedLabelPrefix) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child(key).Key(label), "labels with the "+reservedLabelPrefix+" prefix are managed by the operator"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateOverrideSpec(value interface{}, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	spec, ok := value.(map[string]interface{})
+	if !ok {
+		return append(allErrs, field.Invalid(fldPath, value, "must be an object"))
+	}
+
+	for _, key := range sortedKeys(spec) {
+		if reason, disallowed := disallowedPodSpecFields[key]; disallowed {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(key), reason))
+		}
+	}
+
+	volumes, _ := spec["volumes"].([]interface{})
+	for i, v := range volumes {
+		volume, _ := v.(map[string]interface{})
+		if _, ok := volume["hostPath"]; ok {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("volumes").Index(i).Child("hostPath"), "hostPath volumes are not allowed"))
+		}
+	}
+
+	securityContext, _ := spec["securityContext"].(map[string]interface{})
+	allErrs = append(allErrs, validateOverrideRunAs(securityContext, fldPath.Child("securityContext"))...)
+
+	// Containers are merged by name; none of them may be given more
+	// privileges than the executor runs with
+	for _, key := range []string{"containers", "initContainers"} {
+		containers, _ := spec[key].([]interface{})
+		for i, c := range containers {
+			container, _ := c.(map[string]interface{})
+			securityContext, _ := container["securityContext"].(map[string]interface{})
+			path := fldPath.Child(key).Index(i).Child("securityContext")
+			if privileged, _ := securityContext["privileged"].(bool); privileged {
+				allErrs = append(allErrs, field.Forbidden(path.Child("privileged"), "privileged containers are not allowed"))
+			}
+			if escalation, _ := securityContext["allowPrivilegeEscalation"].(bool); escalation {
+				allErrs = append(allErrs, field.Forbidden(path.Child("allowPrivilegeEscalation"), "privilege escalation is not allowed"))
+			}
+			capabilities, _ := securityContext["capabilities"].(map[string]interface{})
+			if add, _ := capabilities["add"].([]interface{}); len(add) > 0 {
+				allErrs = append(allErrs, field.Forbidden(path.Child("capabilities", "add"), "adding capabilities is not allowed"))
+			}
+			if procMount, ok := securityContext["procMount"]; ok && procMount != string(corev1.DefaultProcMount) {
+				allErrs = append(allErrs, field.Forbidden(path.Child("procMount"), "only the default proc mount is allowed"))
+			}
+			allErrs = append(allErrs, validateOverrideRunAs(securityContext, path)...)
+		}
+	}
+	return allErrs
+}
+
+// validateOverrideRunAs rejects pod or container security contexts that
+// run the executor as root
+func validateOverrideRunAs(securityContext map[string]interface{}, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if runAsNonRoot, ok := securityContext["runAsNonRoot"].(bool); ok && !runAsNonRoot {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("runAsNonRoot"), "running as root is not allowed"))
+	}
+	if runAsUser, ok := securityContext["runAsUser"].(float64); ok && runAsUser == 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("runAsUser"), "running as root is not allowed"))
+	}
+	return allErrs
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
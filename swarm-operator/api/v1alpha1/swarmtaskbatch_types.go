@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwarmTaskBatchSpec defines the desired state of SwarmTaskBatch
+// +kubebuilder:validation:XValidation:rule="has(self.items) || has(self.generator)",message="a batch needs items or a generator"
+type SwarmTaskBatchSpec struct {
+	// Template is the spec of every task of the batch. The parameters of an
+	// item are added to its parameters.
+	Template SwarmTaskSpec `json:"template"`
+
+	// Items are tasks listed one by one
+	Items []BatchItem `json:"items,omitempty"`
+
+	// Generator expands into items after those listed
+	Generator *BatchGenerator `json:"generator,omitempty"`
+
+	// Parallelism is the number of tasks of the batch run at a time
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	Parallelism int32 `json:"parallelism,omitempty"`
+
+	// MaxFailures fails the batch and stops creating tasks once more tasks
+	// failed. Unset lets every item run whatever fails.
+	// +kubebuilder:validation:Minimum=0
+	MaxFailures *int32 `json:"maxFailures,omitempty"`
+
+	// Canary runs the first items of the batch before the others, which
+	// are only created once enough of the canaries succeeded
+	Canary *BatchCanary `json:"canary,omitempty"`
+
+	// Paused stops creating tasks. Tasks already created keep running.
+	Paused bool `json:"paused,omitempty"`
+
+	// Cancel deletes the unfinished tasks of the batch and creates no more.
+	// Finished tasks are kept for their results.
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+// BatchItem is one task of a batch
+type BatchItem struct {
+	// Parameters added to the template parameters of the task
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// BatchCanary sizes the canary subset of a batch and sets the success rate
+// it must reach. Without size or percentage 5% of the items are canaries.
+// +kubebuilder:validation:XValidation:rule="!(has(self.size) && has(self.percentage))",message="set size or percentage, not both"
+type BatchCanary struct {
+	// Size is the number of canary items
+	// +kubebuilder:validation:Minimum=1
+	Size *int32 `json:"size,omitempty"`
+
+	// Percentage of the items that are canaries, rounded up
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percentage *int32 `json:"percentage,omitempty"`
+
+	// SuccessThreshold is the percentage of canaries that must succeed
+	// for the rest of the batch to be created
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=100
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+
+	// HaltOnFailure fails the batch at the first failed canary and deletes
+	// the canaries still running, instead of waiting for all of them
+	HaltOnFailure bool `json:"haltOnFailure,omitempty"`
+}
+
+// BatchGenerator expands into items. Exactly one source is set.
+// +kubebuilder:validation:XValidation:rule="(has(self.range) ? 1 : 0) + (has(self.list) ? 1 : 0) + (has(self.csv) ? 1 : 0) == 1",message="exactly one of range, list or csv must be set"
+type BatchGenerator struct {
+	// Range generates an item per number
+	Range *RangeGenerator `json:"range,omitempty"`
+
+	// List generates an item per value
+	List *ListGenerator `json:"list,omitempty"`
+
+	// CSV generates an item per row of a ConfigMap key
+	CSV *CSVGenerator `json:"csv,omitempty"`
+}
+
+// RangeGenerator generates an item for every number from Start to End
+// +kubebuilder:validation:XValidation:rule="self.start <= self.end",message="start must not exceed end"
+type RangeGenerator struct {
+	// Start is the first number
+	Start int32 `json:"start"`
+
+	// End is the last number
+	End int32 `json:"end"`
+
+	// Step between numbers
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Step int32 `json:"step,omitempty"`
+
+	// Parameter the number is passed as
+	// +kubebuilder:default="index"
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// ListGenerator generates an item for every value
+type ListGenerator struct {
+	// Values, one per item
+	// +kubebuilder:validation:MinItems=1
+	Values []string `json:"values"`
+
+	// Parameter the value is passed as
+	// +kubebuilder:default="item"
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// CSVGenerator generates an item for every row of a CSV document in a
+// ConfigMap. The header row names the parameters of the columns.
+type CSVGenerator struct {
+	// ConfigMapKeyRef selects the ConfigMap key holding the document
+	ConfigMapKeyRef corev1.ConfigMapKeySelector `json:"configMapKeyRef"`
+}
+
+// SwarmTaskBatchStatus defines the observed state of SwarmTaskBatch
+type SwarmTaskBatchStatus struct {
+	// Phase of the batch
+	// +kubebuilder:validation:Enum=Pending;Canary;Running;Paused;Completed;Failed;Cancelled
+	Phase string `json:"phase,omitempty"`
+
+	// Total number of items
+	Total int32 `json:"total"`
+
+	// Created tasks so far
+	Created int32 `json:"created"`
+
+	// Active tasks created that have not finished
+	Active int32 `json:"active"`
+
+	// Succeeded tasks
+	Succeeded int32 `json:"succeeded"`
+
+	// Failed tasks, including dead-lettered ones
+	Failed int32 `json:"failed"`
+
+	// Progress is the share of items finished, e.g. "42/500"
+	Progress string `json:"progress,omitempty"`
+
+	// Canary reports the results of the canary subset
+	Canary *BatchCanaryStatus `json:"canary,omitempty"`
+
+	// StartTime is when the first task was created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the batch finished, failed or was cancelled
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// ObservedGeneration is the generation of the spec last acted on
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Message provides additional information
+	Message string `json:"message,omitempty"`
+}
+
+// BatchCanaryStatus aggregates the results of the canary tasks
+type BatchCanaryStatus struct {
+	// Phase of the canary: Running, Passed or Failed
+	// +kubebuilder:validation:Enum=Running;Passed;Failed
+	Phase string `json:"phase"`
+
+	// Size of the canary subset, the first items of the batch
+	Size int32 `json:"size"`
+
+	// Succeeded canary tasks
+	Succeeded int32 `json:"succeeded"`
+
+	// Failed canary tasks
+	Failed int32 `json:"failed"`
+
+	// SuccessRate is the percentage of the finished canaries that succeeded
+	SuccessRate int32 `json:"successRate"`
+
+	// FailedTasks names the failed canary tasks
+	FailedTasks []string `json:"failedTasks,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=stb
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+//+kubebuilder:printcolumn:name="Active",type=integer,JSONPath=`.status.active`
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SwarmTaskBatch is the Schema for the swarmtaskbatches API. It expands a
+// list or generator into SwarmTasks and runs them with bounded parallelism.
+type SwarmTaskBatch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwarmTaskBatchSpec   `json:"spec,omitempty"`
+	Status SwarmTaskBatchStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwarmTaskBatchList contains a list of SwarmTaskBatch
+type SwarmTaskBatchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwarmTaskBatch `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwarmTaskBatch{}, &SwarmTaskBatchList{})
+}
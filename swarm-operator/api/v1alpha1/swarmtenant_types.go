@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SwarmTenantSpec isolates one team sharing the operator with others.
+// SwarmClusters join a tenant with the swarm.claudeflow.io/tenant label and
+// their SwarmTasks inherit it.
+type SwarmTenantSpec struct {
+	// DisplayName of the team
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Namespaces the tenant's SwarmClusters and SwarmTasks may be created
+	// in and its tasks may run in. They are created if missing and labeled
+	// with the tenant; a namespace belongs to at most one tenant.
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+
+	// Profile is the SwarmProfile the tenant's clusters use when they name
+	// none themselves
+	Profile string `json:"profile,omitempty"`
+
+	// Quota bounds what the tenant may run
+	Quota *TenantQuota `json:"quota,omitempty"`
+
+	// LimitRange is applied to every tenant namespace, giving tenant pods
+	// their default requests and limits
+	LimitRange *corev1.LimitRangeSpec `json:"limitRange,omitempty"`
+
+	// AllowedRegistries the tenant's executor, agent and hive-mind images
+	// must come from, as image prefixes such as ghcr.io/team-a/. Images the
+	// operator supplies itself are exempt. Empty allows any registry.
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// Credentials scopes the secrets the tenant's tasks may use. Without it
+	// tasks may use any secret in their namespace.
+	Credentials *TenantCredentials `json:"credentials,omitempty"`
+}
+
+// TenantQuota bounds the resources of a tenant. Zero means unlimited.
+type TenantQuota struct {
+	// MaxClusters the tenant may run. Clusters beyond the quota, youngest
+	// first, are not reconciled.
+	// +kubebuilder:validation:Minimum=0
+	MaxClusters int32 `json:"maxClusters,omitempty"`
+
+	// MaxConcurrentTasks bounds the task Jobs the tenant runs at once.
+	// Further tasks wait in Pending.
+	// +kubebuilder:validation:Minimum=0
+	MaxConcurrentTasks int32 `json:"maxConcurrentTasks,omitempty"`
+
+	// ResourceQuota is applied to every tenant namespace
+	ResourceQuota *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+}
+
+// TenantCredentials lists the secrets tenant tasks may use
+type TenantCredentials struct {
+	// AllowedSecrets tenant tasks may mount or reference, through
+	// spec.additionalSecrets or pod template overrides
+	AllowedSecrets []string `json:"allowedSecrets,omitempty"`
+
+	// InjectedSecrets are the well-known credential secrets
+	// (github-credentials, aws-credentials, azure-credentials,
+	// gcp-credentials) injected into tenant tasks when the operator injects
+	// credentials. Empty injects none.
+	InjectedSecrets []string `json:"injectedSecrets,omitempty"`
+}
+
+// SwarmTenantStatus defines the observed state of SwarmTenant
+type SwarmTenantStatus struct {
+	// ObservedGeneration is the generation the status reflects
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Clusters of the tenant
+	Clusters int32 `json:"clusters"`
+
+	// RunningTasks is the number of task Jobs the tenant runs
+	RunningTasks int32 `json:"runningTasks"`
+
+	// Namespaces provisioned for the tenant
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Conditions of the tenant
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster,shortName=stenant
+//+kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.status.clusters`
+//+kubebuilder:printcolumn:name="Running",type=integer,JSONPath=`.status.runningTasks`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// SwarmTenant is the Schema for the swarmtenants API. It ties SwarmClusters
+// and their tasks to a team and enforces the team's namespaces, quotas,
+// registries and credentials.
+type SwarmTenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SwarmTenantSpec   `json:"spec,omitempty"`
+	Status SwarmTenantStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// SwarmTenantList contains a list of SwarmTenant
+type SwarmTenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SwarmTenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SwarmTenant{}, &SwarmTenantList{})
+}
@@ -4,18 +4,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -33,8 +35,18 @@ var (
 		Version:  "v1alpha1",
 		Resource: "swarmtasks",
 	}
+
+	// logLevel is read from LOG_LEVEL and switched at runtime through the
+	// health endpoint, e.g.
+	//
+	//	curl -X PUT -d '{"level":"debug"}' localhost:8080/loglevel
+	logLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
 )
 
+// correlationIDAnnotation carries the correlation ID of a task onto its Job.
+// The executor gets it as SWARM_CORRELATION_ID.
+const correlationIDAnnotation = "swarm.claudeflow.io/correlation-id"
+
 // SecretMount represents additional secret mounting configuration
 type SecretMount struct {
 	Name      string `json:"name"`
@@ -67,6 +79,7 @@ type EnhancedOperator struct {
 	dynClient dynamic.Interface
 	namespace string
 	config    *OperatorConfig
+	log       *zap.Logger
 }
 
 type OperatorConfig struct {
@@ -77,22 +90,26 @@ type OperatorConfig struct {
 }
 
 func main() {
-	log.Println("Starting Enhanced Swarm Operator v0.5.0 with advanced features...")
+	log := newLogger()
+	defer log.Sync()
+
+	log.Info("Starting Enhanced Swarm Operator v0.5.0 with advanced features...")
+	log.Warn("DEPRECATED: this standalone operator is superseded by the controller-runtime operator in cmd/main.go. Use its --executor-image, --executor-scripts-configmap and --inject-credential-secrets flags instead.")
 
 	// Setup Kubernetes clients
 	config, err := rest.InClusterConfig()
 	if err != nil {
-		log.Fatalf("Failed to get in-cluster config: %v", err)
+		log.Fatal("Failed to get in-cluster config", zap.Error(err))
 	}
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Failed to create clientset: %v", err)
+		log.Fatal("Failed to create clientset", zap.Error(err))
 	}
 
 	dynClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Failed to create dynamic client: %v", err)
+		log.Fatal("Failed to create dynamic client", zap.Error(err))
 	}
 
 	// Load operator configuration
@@ -110,6 +127,7 @@ func main() {
 		dynClient: dynClient,
 		namespace: namespace,
 		config:    operatorConfig,
+		log:       log.With(zap.String("namespace", namespace)),
 	}
 
 	// Start health endpoint
@@ -121,31 +139,34 @@ func main() {
 
 func (o *EnhancedOperator) run() {
 	wait.Forever(func() {
+		// Every pass gets its own ID to tell its logs apart
+		log := o.log.With(zap.String("reconcileID", string(uuid.NewUUID())))
+
 		// Process SwarmClusters
 		swarms, err := o.dynClient.Resource(swarmGVR).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			log.Printf("Error listing swarms: %v", err)
+			log.Error("Failed to list swarms", zap.Error(err))
 			return
 		}
 
 		for _, swarm := range swarms.Items {
-			o.processSwarm(swarm)
+			o.processSwarm(log, swarm)
 		}
 
 		// Process SwarmTasks
 		tasks, err := o.dynClient.Resource(taskGVR).Namespace(o.namespace).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			log.Printf("Error listing tasks: %v", err)
+			log.Error("Failed to list tasks", zap.Error(err))
 			return
 		}
 
 		for _, task := range tasks.Items {
-			o.processTask(task)
+			o.processTask(log.With(taskFields(task)...), task)
 		}
 	}, 10*time.Second)
 }
 
-func (o *EnhancedOperator) processTask(task unstructured.Unstructured) {
+func (o *EnhancedOperator) processTask(log *zap.Logger, task unstructured.Unstructured) {
 	taskName := task.GetName()
 	taskSpec, found, err := unstructured.NestedMap(task.Object, "spec")
 	if !found || err != nil {
@@ -155,20 +176,24 @@ func (o *EnhancedOperator) processTask(task unstructured.Unstructured) {
 	// Check if we already created a job for this task
 	status, _, _ := unstructured.NestedMap(task.Object, "status")
 	if phase, ok := status["phase"].(string); ok && phase != "" && phase != "Pending" {
+		if phase == "Running" {
+			o.trackJob(log, task)
+		}
 		// Check if this is a resume request
 		if resume, ok := taskSpec["resume"].(bool); ok && resume && phase == "Failed" {
-			log.Printf("Resuming failed task: %s", taskName)
-			o.createEnhancedJob(taskName, task)
+			log.Info("Resuming failed task")
+			o.createEnhancedJob(log, taskName, task)
 		}
 		return
 	}
 
 	// Process new task
-	o.createEnhancedJob(taskName, task)
+	o.createEnhancedJob(log, taskName, task)
 }
 
-func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.Unstructured) {
+func (o *EnhancedOperator) createEnhancedJob(log *zap.Logger, taskName string, task unstructured.Unstructured) {
 	jobName := fmt.Sprintf("swarm-job-%s", taskName)
+	log = log.With(zap.String("job", jobName))
 	
 	// Check if job already exists
 	_, err := o.clientset.BatchV1().Jobs(o.namespace).Get(context.TODO(), jobName, metav1.GetOptions{})
@@ -182,9 +207,10 @@ func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.
 
 	// Create enhanced container spec
 	container := o.createEnhancedContainer(taskName, taskSpec, taskConfig)
+	container.Env = append(container.Env, corev1.EnvVar{Name: "SWARM_CORRELATION_ID", Value: correlationID(task)})
 	
 	// Create volumes
-	volumes, volumeMounts := o.createVolumes(taskName, taskConfig)
+	volumes, volumeMounts := o.createVolumes(log, taskName, taskConfig)
 	container.VolumeMounts = append(container.VolumeMounts, volumeMounts...)
 
 	// Create job spec
@@ -202,6 +228,9 @@ func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.
 				"task":       taskName,
 				"managed-by": "swarm-operator",
 			},
+			Annotations: map[string]string{
+				correlationIDAnnotation: correlationID(task),
+			},
 		},
 		Spec: batchv1.JobSpec{
 			Parallelism:             &parallelism,
@@ -217,6 +246,7 @@ func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.
 					Annotations: map[string]string{
 						"swarm.claudeflow.io/task-name": taskName,
 						"swarm.claudeflow.io/created":   time.Now().Format(time.RFC3339),
+						correlationIDAnnotation:         correlationID(task),
 					},
 				},
 				Spec: corev1.PodSpec{
@@ -229,15 +259,16 @@ func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.
 	}
 
 	// Create the job
-	_, err = o.clientset.BatchV1().Jobs(o.namespace).Create(context.TODO(), job, metav1.CreateOptions{})
+	created, err := o.clientset.BatchV1().Jobs(o.namespace).Create(context.TODO(), job, metav1.CreateOptions{})
 	if err != nil {
-		log.Printf("Failed to create job for task %s: %v", taskName, err)
-		o.updateTaskStatus(task, "Failed", fmt.Sprintf("Job creation failed: %v", err))
+		log.Error("Failed to create job", zap.Error(err))
+		o.updateTaskStatus(log, task, "Failed", fmt.Sprintf("Job creation failed: %v", err))
 		return
 	}
+	setJobStatus(&task, created)
 
-	log.Printf("Created enhanced job %s for task %s", jobName, taskName)
-	o.updateTaskStatus(task, "Running", "Enhanced job created")
+	log.Info("Created enhanced job")
+	o.updateTaskStatus(log, task, "Running", "Enhanced job created")
 }
 
 func (o *EnhancedOperator) createEnhancedContainer(taskName string, taskSpec map[string]interface{}, config *TaskConfig) corev1.Container {
@@ -461,7 +492,7 @@ func (o *EnhancedOperator) createCloudProviderEnvVars() []corev1.EnvVar {
 	return vars
 }
 
-func (o *EnhancedOperator) createVolumes(taskName string, config *TaskConfig) ([]corev1.Volume, []corev1.VolumeMount) {
+func (o *EnhancedOperator) createVolumes(log *zap.Logger, taskName string, config *TaskConfig) ([]corev1.Volume, []corev1.VolumeMount) {
 	volumes := []corev1.Volume{}
 	volumeMounts := []corev1.VolumeMount{}
 
@@ -507,7 +538,7 @@ func (o *EnhancedOperator) createVolumes(taskName string, config *TaskConfig) ([
 		
 		// Check if PVC exists, create if not
 		pvcName := fmt.Sprintf("%s-%s", taskName, pvc.Name)
-		o.ensurePVC(pvcName, pvc)
+		o.ensurePVC(log, pvcName, pvc)
 
 		volumes = append(volumes, corev1.Volume{
 			Name: volumeName,
@@ -544,7 +575,7 @@ func (o *EnhancedOperator) createVolumes(taskName string, config *TaskConfig) ([
 	return volumes, volumeMounts
 }
 
-func (o *EnhancedOperator) ensurePVC(name string, config PVCConfig) {
+func (o *EnhancedOperator) ensurePVC(log *zap.Logger, name string, config PVCConfig) {
 	// Check if PVC exists
 	_, err := o.clientset.CoreV1().PersistentVolumeClaims(o.namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err == nil {
@@ -586,9 +617,9 @@ func (o *EnhancedOperator) ensurePVC(name string, config PVCConfig) {
 
 	_, err = o.clientset.CoreV1().PersistentVolumeClaims(o.namespace).Create(context.TODO(), pvc, metav1.CreateOptions{})
 	if err != nil {
-		log.Printf("Failed to create PVC %s: %v", name, err)
+		log.Error("Failed to create PVC", zap.String("pvc", name), zap.Error(err))
 	} else {
-		log.Printf("Created PVC %s for swarm task", name)
+		log.Info("Created PVC for swarm task", zap.String("pvc", name))
 	}
 }
 
@@ -640,29 +671,70 @@ func (o *EnhancedOperator) parseTaskConfig(taskSpec map[string]interface{}) *Tas
 	return config
 }
 
-func (o *EnhancedOperator) updateTaskStatus(task unstructured.Unstructured, phase, message string) {
+func (o *EnhancedOperator) updateTaskStatus(log *zap.Logger, task unstructured.Unstructured, phase, message string) {
 	status := map[string]interface{}{
 		"phase":   phase,
 		"message": message,
 		"lastUpdateTime": time.Now().Format(time.RFC3339),
 	}
+	copyJobStatus(task, status)
 
 	// Update the task status
 	task.Object["status"] = status
+	o.writeTaskStatus(log, task)
+}
+
+func (o *EnhancedOperator) writeTaskStatus(log *zap.Logger, task unstructured.Unstructured) {
 	_, err := o.dynClient.Resource(taskGVR).Namespace(o.namespace).UpdateStatus(
 		context.TODO(),
 		&task,
 		metav1.UpdateOptions{},
 	)
 	if err != nil {
-		log.Printf("Failed to update task status: %v", err)
+		phase, _, _ := unstructured.NestedString(task.Object, "status", "phase")
+		log.Error("Failed to update task status", zap.String("phase", phase), zap.Error(err))
 	}
 }
 
-func (o *EnhancedOperator) processSwarm(swarm unstructured.Unstructured) {
+// trackJob keeps the Job name and retry count of a running task current
+func (o *EnhancedOperator) trackJob(log *zap.Logger, task unstructured.Unstructured) {
+	jobName := fmt.Sprintf("swarm-job-%s", task.GetName())
+	job, err := o.clientset.BatchV1().Jobs(o.namespace).Get(context.TODO(), jobName, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if setJobStatus(&task, job) {
+		o.writeTaskStatus(log, task)
+	}
+}
+
+// setJobStatus records the Job of a task and how often its pods were
+// retried, shown in the Job and Retries columns. It reports whether either
+// changed.
+func setJobStatus(task *unstructured.Unstructured, job *batchv1.Job) bool {
+	name, _, _ := unstructured.NestedString(task.Object, "status", "jobName")
+	retries, _, _ := unstructured.NestedInt64(task.Object, "status", "retryCount")
+	if name == job.Name && retries == int64(job.Status.Failed) {
+		return false
+	}
+	_ = unstructured.SetNestedField(task.Object, job.Name, "status", "jobName")
+	_ = unstructured.SetNestedField(task.Object, int64(job.Status.Failed), "status", "retryCount")
+	return true
+}
+
+// copyJobStatus keeps the Job fields of the task's status in the status
+// replacing it
+func copyJobStatus(task unstructured.Unstructured, status map[string]interface{}) {
+	for _, key := range []string{"jobName", "retryCount"} {
+		if value, found, _ := unstructured.NestedFieldCopy(task.Object, "status", key); found {
+			status[key] = value
+		}
+	}
+}
+
+func (o *EnhancedOperator) processSwarm(log *zap.Logger, swarm unstructured.Unstructured) {
 	// Process swarm logic (unchanged from original)
-	swarmName := swarm.GetName()
-	log.Printf("Processing swarm: %s", swarmName)
+	log.Debug("Processing swarm", zap.String("cluster", swarm.GetName()))
 }
 
 func (o *EnhancedOperator) startHealthEndpoint() {
@@ -683,7 +755,45 @@ func (o *EnhancedOperator) startHealthEndpoint() {
 		w.Write([]byte("Ready"))
 	})
 
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	// GET reports and PUT switches the log level
+	http.Handle("/loglevel", logLevel)
+
+	o.log.Fatal("Health endpoint stopped", zap.Error(http.ListenAndServe(":8080", nil)))
+}
+
+// newLogger logs JSON at the level of LOG_LEVEL, info by default
+func newLogger() *zap.Logger {
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid LOG_LEVEL %q, logging at info\n", level)
+		}
+	}
+	config := zap.NewProductionConfig()
+	config.Level = logLevel
+	logger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+	return logger
+}
+
+// correlationID ties the logs of a task, its Job and its executor
+// together. Clients may set it on the task; it defaults to the task UID.
+func correlationID(task unstructured.Unstructured) string {
+	if id := task.GetAnnotations()[correlationIDAnnotation]; id != "" {
+		return id
+	}
+	return string(task.GetUID())
+}
+
+// taskFields are the log fields of a task
+func taskFields(task unstructured.Unstructured) []zap.Field {
+	cluster, _, _ := unstructured.NestedString(task.Object, "spec", "swarmRef")
+	return []zap.Field{
+		zap.String("task", task.GetName()),
+		zap.String("cluster", cluster),
+		zap.String("correlationID", correlationID(task)),
+	}
 }
 
 // Helper functions
@@ -20,23 +20,41 @@ import (
 	"flag"
 	"os"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
 	"github.com/claude-flow/swarm-operator/controllers"
+	"github.com/claude-flow/swarm-operator/pkg/apiclient"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/circuitbreaker"
+	"github.com/claude-flow/swarm-operator/pkg/dispatch"
 	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/notify"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+	"github.com/claude-flow/swarm-operator/pkg/preflight"
+	"github.com/claude-flow/swarm-operator/pkg/profiling"
+	"github.com/claude-flow/swarm-operator/pkg/progress"
+	"github.com/claude-flow/swarm-operator/pkg/registration"
+	"github.com/claude-flow/swarm-operator/pkg/sharding"
+	"github.com/claude-flow/swarm-operator/pkg/summary"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -60,6 +78,32 @@ func main() {
 	var watchNamespaces string
 	var swarmNamespace string
 	var hivemindNamespace string
+	var summaryAddr string
+	var progressAddr string
+	var registrationAddr string
+	var enableWebhooks bool
+	var enableChaos bool
+	var shardTasks bool
+	var executorImage string
+	var executorWindowsImage string
+	var executorScripts string
+	var injectCredentials bool
+	var executorProgressURL string
+	var auditControllers string
+	var auditWebhookURL string
+	var auditOTLPEndpoint string
+	var operatorConfigName string
+	var priorityClasses bool
+	var priorityPreemption bool
+	var pprofAddr string
+	var profileOutput string
+	var profileInterval time.Duration
+	var keepCompletedStorageFor time.Duration
+	var keepTokenSecretsFor time.Duration
+	var gcDryRun bool
+	var kubeAPIQPS float64
+	var kubeAPIBurst int
+	var controllerAPILimits string
 	
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -76,6 +120,61 @@ func main() {
 		"Default namespace for swarm agents")
 	flag.StringVar(&hivemindNamespace, "hivemind-namespace", "claude-flow-hivemind",
 		"Default namespace for hive-mind components")
+	flag.StringVar(&summaryAddr, "summary-api-bind-address", "0",
+		"The address the authenticated cluster summary API binds to. Set to 0 to disable.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the admission webhooks are served. Requires serving certificates to be mounted.")
+	flag.BoolVar(&shardTasks, "shard-tasks", false,
+		"If set, every replica dispatches the SwarmTasks of its hash range while the other controllers stay leader-elected. Requires --leader-elect.")
+	flag.BoolVar(&enableChaos, "enable-chaos", false,
+		"If set, SwarmChaos experiments run against clusters annotated swarm.claudeflow.io/allow-chaos=true. Never set it in production.")
+	flag.StringVar(&executorImage, "executor-image", "",
+		"Image task Jobs run in unless the task sets spec.executorImage. Empty keeps the placeholder container.")
+	flag.StringVar(&executorWindowsImage, "executor-windows-image", "",
+		"Image Windows task Jobs run in unless the task sets spec.executorImage. Empty keeps the PowerShell placeholder container.")
+	flag.StringVar(&executorScripts, "executor-scripts-configmap", "",
+		"ConfigMap with entrypoint.sh and task.sh mounted into executor images at /scripts. "+
+			"Windows tasks run entrypoint.ps1 and task.ps1 from it instead.")
+	flag.BoolVar(&injectCredentials, "inject-credential-secrets", false,
+		"If set, GitHub and cloud credentials from the github-, aws-, azure- and gcp-credentials secrets are injected into task Jobs")
+	flag.StringVar(&executorProgressURL, "executor-progress-url", "",
+		"Endpoint executors POST progress updates to, passed as SWARM_PROGRESS_URL. Empty disables progress reporting.")
+	flag.StringVar(&progressAddr, "progress-api-bind-address", "0",
+		"The address the executor progress API binds to. Point --executor-progress-url at its /api/v1/progress. Set to 0 to disable.")
+	flag.StringVar(&registrationAddr, "registration-api-bind-address", "0",
+		"The address the API external agents register and send heartbeats to binds to. Set to 0 to disable.")
+	flag.StringVar(&auditControllers, "audit-controllers", "",
+		"Comma-separated controllers whose mutations are written to the audit log "+
+			"(swarmcluster, agent, swarmtask, swarmmemorystore, swarmmemory, swarmpreview, swarmtenant, swarmoperatorconfig, garbagecollector), or * for all. Empty disables auditing.")
+	flag.StringVar(&auditWebhookURL, "audit-webhook-url", "",
+		"If set, audit records are also posted in batches to this URL. A bearer token is read from AUDIT_WEBHOOK_TOKEN.")
+	flag.StringVar(&auditOTLPEndpoint, "audit-otlp-endpoint", "",
+		"If set, audit records are also exported as OTLP logs to this collector base URL, e.g. http://otel-collector:4318")
+	flag.StringVar(&operatorConfigName, "operator-config", "swarm-operator",
+		"Name of the cluster-scoped SwarmOperatorConfig whose settings override the flag defaults at runtime. Empty disables hot reload.")
+	flag.BoolVar(&priorityClasses, "task-priority-classes", false,
+		"If set, the swarm-critical, -high, -medium and -low PriorityClasses are created and task pods run with the one of their priority")
+	flag.BoolVar(&priorityPreemption, "task-priority-preemption", false,
+		"If set, critical task pods may preempt lower priority pods. Requires --task-priority-classes.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "0",
+		"The address the authenticated pprof endpoints bind to. Callers need get access to the /debug/pprof/* non-resource URLs. Set to 0 to disable.")
+	flag.StringVar(&profileOutput, "profile-output", "",
+		"If set, CPU and heap profiles are written periodically to this directory, typically a mounted volume")
+	flag.DurationVar(&profileInterval, "profile-interval", 15*time.Minute,
+		"How often profiles are written to --profile-output")
+	flag.DurationVar(&keepCompletedStorageFor, "keep-completed-storage-for", 0,
+		"How long the volumes of a task are kept after it was deleted. 0 keeps them.")
+	flag.DurationVar(&keepTokenSecretsFor, "keep-token-secrets-for", 24*time.Hour,
+		"How long the GitHub token secret of a task is kept after it finished or was deleted. 0 keeps them.")
+	flag.BoolVar(&gcDryRun, "gc-dry-run", false,
+		"If set, the garbage collector only logs and reports in metrics what it would delete")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 20,
+		"Sustained requests per second to the API server of the manager's clients")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 30,
+		"Burst of requests to the API server of the manager's clients")
+	flag.StringVar(&controllerAPILimits, "controller-api-limits", "",
+		"Comma-separated controller=qps[:burst] pairs giving controllers, named as in --audit-controllers, "+
+			"their own budget of API writes, e.g. swarmtask=50:100. The burst defaults to twice the QPS.")
 	
 	opts := zap.Options{
 		Development: true,
@@ -83,11 +182,25 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	// The SwarmOperatorConfig switches the level at runtime
+	logLevel := runtimeLogLevel(&opts)
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
 	// Create metrics recorder
 	metricsRecorder := metrics.NewMetricsRecorder()
 
+	// API server budget of the manager, with throttling by API priority
+	// and fairness surfaced in metrics
+	apiLimits, err := apiclient.ParseLimits(controllerAPILimits)
+	if err != nil {
+		setupLog.Error(err, "invalid --controller-api-limits")
+		os.Exit(1)
+	}
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = float32(kubeAPIQPS)
+	restConfig.Burst = kubeAPIBurst
+	apiclient.ObserveThrottling(restConfig, metricsRecorder.RecordServerThrottled)
+
 	// Parse watch namespaces
 	namespaces := strings.Split(watchNamespaces, ",")
 	for i := range namespaces {
@@ -104,7 +217,7 @@ func main() {
 		}
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme: scheme,
 		Cache:  cacheOptions,
 		Metrics: metricsserver.Options{
@@ -132,13 +245,119 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Lifecycle notifications are shared by the cluster and task controllers
+	notifier := notify.NewNotifier(mgr.GetClient())
+
+	// Circuit breakers for external dependencies are shared by all
+	// controllers so one flaky dependency backs off everywhere at once
+	breakers := circuitbreaker.NewRegistry()
+	breakers.OnStateChange = func(dependency string, state circuitbreaker.State) {
+		setupLog.Info("Circuit breaker state changed", "dependency", dependency, "state", state)
+		metricsRecorder.RecordCircuitBreakerState(dependency, string(state))
+	}
+
+	// Audit log of operator mutations, written to stdout and optionally
+	// shipped to a webhook or OTLP collector
+	auditOpts := audit.Options{Local: []audit.Sink{audit.NewWriterSink(os.Stdout)}}
+	for _, c := range strings.Split(auditControllers, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			auditOpts.Controllers = append(auditOpts.Controllers, c)
+		}
+	}
+	if auditWebhookURL != "" {
+		auditOpts.Remote = append(auditOpts.Remote, audit.NewWebhookSink(auditWebhookURL, os.Getenv("AUDIT_WEBHOOK_TOKEN")))
+	}
+	if auditOTLPEndpoint != "" {
+		auditOpts.Remote = append(auditOpts.Remote, audit.NewOTLPSink(strings.TrimSuffix(auditOTLPEndpoint, "/")))
+	}
+	auditor := audit.NewAuditor(auditOpts)
+	if len(auditOpts.Remote) > 0 {
+		if err := mgr.Add(auditor); err != nil {
+			setupLog.Error(err, "unable to set up audit log")
+			os.Exit(1)
+		}
+	}
+
+	// Controllers with an API limit write through a client of their own
+	// token bucket, reading from the shared cache like the manager's client
+	controllerClient := func(name string) client.Client {
+		limit, ok := apiLimits[name]
+		if !ok {
+			return audit.NewClient(mgr.GetClient(), name, auditor)
+		}
+		c, err := client.New(apiclient.WithLimit(restConfig, limit, func(wait time.Duration) {
+			metricsRecorder.RecordClientRateLimitWait(name, wait)
+		}), client.Options{
+			HTTPClient: mgr.GetHTTPClient(),
+			Scheme:     mgr.GetScheme(),
+			Mapper:     mgr.GetRESTMapper(),
+			Cache:      &client.CacheOptions{Reader: mgr.GetCache()},
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create client", "controller", name)
+			os.Exit(1)
+		}
+		return audit.NewClient(c, name, auditor)
+	}
+
+	// Typed client for pod logs and preflight checks
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create kubernetes client")
+		os.Exit(1)
+	}
+
+	// Service mesh proxies of task pods run as native sidecars from 1.29
+	nativeSidecars := false
+	if info, err := kubeClient.Discovery().ServerVersion(); err != nil {
+		setupLog.Error(err, "unable to read the API server version, assuming no native sidecars")
+	} else if serverVersion, err := utilversion.ParseGeneric(info.GitVersion); err == nil {
+		nativeSidecars = serverVersion.AtLeast(utilversion.MajorMinor(1, 29))
+	}
+
+	// Operator defaults, hot-reloaded from the SwarmOperatorConfig
+	var operatorConfig *operatorconfig.Store
+	if operatorConfigName != "" {
+		operatorConfig = operatorconfig.NewStore(operatorconfig.Settings{
+			ExecutorImage:            executorImage,
+			ExecutorWindowsImage:     executorWindowsImage,
+			ExecutorScriptsConfigMap: executorScripts,
+			InjectCredentialSecrets:  injectCredentials,
+			ExecutorProgressURL:      executorProgressURL,
+			SwarmNamespace:           swarmNamespace,
+			HiveMindNamespace:        hivemindNamespace,
+			PriorityClasses:          priorityClasses,
+			PriorityPreemption:       priorityPreemption,
+			KeepCompletedStorageFor:  keepCompletedStorageFor,
+			KeepTokenSecretsFor:      keepTokenSecretsFor,
+			GCDryRun:                 gcDryRun,
+			LogLevel:                 int(logLevel.Level()),
+		})
+		if err = (&controllers.SwarmOperatorConfigReconciler{
+			Client:          controllerClient("swarmoperatorconfig"),
+			Scheme:          mgr.GetScheme(),
+			Recorder:        mgr.GetEventRecorderFor("swarmoperatorconfig-controller"),
+			Store:           operatorConfig,
+			Name:            operatorConfigName,
+			LogLevel:        &logLevel,
+			MetricsRecorder: metricsRecorder,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "SwarmOperatorConfig")
+			os.Exit(1)
+		}
+	}
+
 	// Setup SwarmCluster controller
 	if err = (&controllers.SwarmClusterReconciler{
-		Client:            mgr.GetClient(),
+		Client:            controllerClient("swarmcluster"),
 		Scheme:            mgr.GetScheme(),
 		Recorder:          mgr.GetEventRecorderFor("swarmcluster-controller"),
 		SwarmNamespace:    swarmNamespace,
 		HiveMindNamespace: hivemindNamespace,
+		Notifier:          notifier,
+		MetricsRecorder:   metricsRecorder,
+		Config:            operatorConfig,
+		OperatorNamespace: os.Getenv("OPERATOR_NAMESPACE"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SwarmCluster")
 		os.Exit(1)
@@ -146,7 +365,7 @@ func main() {
 
 	// Setup Agent controller
 	if err = (&controllers.AgentReconciler{
-		Client:          mgr.GetClient(),
+		Client:          controllerClient("agent"),
 		Scheme:          mgr.GetScheme(),
 		Recorder:        mgr.GetEventRecorderFor("agent-controller"),
 		MetricsRecorder: metricsRecorder,
@@ -156,13 +375,64 @@ func main() {
 		os.Exit(1)
 	}
 	
+	// Split the tasks between the replicas, each renewing a lease in the
+	// operator namespace
+	var taskShard *sharding.Shard
+	if shardTasks {
+		if !enableLeaderElection {
+			setupLog.Error(nil, "--shard-tasks requires --leader-elect")
+			os.Exit(1)
+		}
+		identity := os.Getenv("POD_NAME")
+		if identity == "" {
+			if identity, err = os.Hostname(); err != nil {
+				setupLog.Error(err, "unable to determine the shard identity")
+				os.Exit(1)
+			}
+		}
+		leaseNamespace := os.Getenv("OPERATOR_NAMESPACE")
+		if leaseNamespace == "" {
+			leaseNamespace = swarmNamespace
+		}
+		taskShard = &sharding.Shard{
+			Client:    mgr.GetClient(),
+			Reader:    mgr.GetAPIReader(),
+			Namespace: leaseNamespace,
+			Group:     "swarmtask",
+			Identity:  identity,
+		}
+		if err := mgr.Add(taskShard); err != nil {
+			setupLog.Error(err, "unable to set up task sharding")
+			os.Exit(1)
+		}
+	}
+
 	// Setup SwarmTask controller
 	if err = (&controllers.SwarmTaskReconciler{
-		Client:            mgr.GetClient(),
+		Client:            controllerClient("swarmtask"),
 		Scheme:            mgr.GetScheme(),
 		Recorder:          mgr.GetEventRecorderFor("swarmtask-controller"),
 		SwarmNamespace:    swarmNamespace,
 		HiveMindNamespace: hivemindNamespace,
+		Notifier:          notifier,
+		Breakers:          breakers,
+		MetricsRecorder:   metricsRecorder,
+		Dispatcher:        dispatch.NewDispatcher(),
+		Kube:              kubeClient,
+		NativeSidecars:    nativeSidecars,
+		Executor: controllers.ExecutorConfig{
+			Image:             executorImage,
+			WindowsImage:      executorWindowsImage,
+			ScriptsConfigMap:  executorScripts,
+			CredentialSecrets: injectCredentials,
+			ProgressURL:       executorProgressURL,
+		},
+		Priority: controllers.PriorityClassConfig{
+			Enabled:    priorityClasses,
+			Preemption: priorityPreemption,
+		},
+		Config: operatorConfig,
+		Shard:  taskShard,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SwarmTask")
 		os.Exit(1)
@@ -170,15 +440,167 @@ func main() {
 	
 	// Setup SwarmMemoryStore controller
 	if err = (&controllers.SwarmMemoryStoreReconciler{
-		Client:         mgr.GetClient(),
-		Scheme:         mgr.GetScheme(),
-		SwarmNamespace: swarmNamespace,
+		Client:          controllerClient("swarmmemorystore"),
+		Scheme:          mgr.GetScheme(),
+		SwarmNamespace:  swarmNamespace,
+		Config:          operatorConfig,
+		MetricsRecorder: metricsRecorder,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SwarmMemoryStore")
 		os.Exit(1)
 	}
+
+	// Setup SwarmMemory controller
+	if err = (&controllers.SwarmMemoryReconciler{
+		Client:          controllerClient("swarmmemory"),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("swarmmemory-controller"),
+		MetricsRecorder: metricsRecorder,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwarmMemory")
+		os.Exit(1)
+	}
+
+	// Setup SwarmPreview controller
+	if err = (&controllers.SwarmPreviewReconciler{
+		Client:          controllerClient("swarmpreview"),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("swarmpreview-controller"),
+		Breakers:        breakers,
+		MetricsRecorder: metricsRecorder,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwarmPreview")
+		os.Exit(1)
+	}
+
+	// Setup SwarmTaskBatch controller
+	if err = (&controllers.SwarmTaskBatchReconciler{
+		Client:          controllerClient("swarmtaskbatch"),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("swarmtaskbatch-controller"),
+		MetricsRecorder: metricsRecorder,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwarmTaskBatch")
+		os.Exit(1)
+	}
+
+	// Setup SwarmTenant controller
+	if err = (&controllers.SwarmTenantReconciler{
+		Client:          controllerClient("swarmtenant"),
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("swarmtenant-controller"),
+		MetricsRecorder: metricsRecorder,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SwarmTenant")
+		os.Exit(1)
+	}
+
+	// Setup SwarmChaos controller, only outside production
+	if enableChaos {
+		if err = (&controllers.SwarmChaosReconciler{
+			Client:          controllerClient("swarmchaos"),
+			Scheme:          mgr.GetScheme(),
+			Recorder:        mgr.GetEventRecorderFor("swarmchaos-controller"),
+			SwarmNamespace:  swarmNamespace,
+			Config:          operatorConfig,
+			MetricsRecorder: metricsRecorder,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "SwarmChaos")
+			os.Exit(1)
+		}
+	}
+
+	if enableWebhooks {
+		if err = (&swarmv1alpha1.SwarmTask{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwarmTask")
+			os.Exit(1)
+		}
+		if err = (&swarmv1alpha1.SwarmOperatorConfig{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwarmOperatorConfig")
+			os.Exit(1)
+		}
+		if err = (&swarmv1alpha1.SwarmCluster{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "SwarmCluster")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	// Setup the aggregated summary API for dashboards
+	if summaryAddr != "0" && summaryAddr != "" {
+		if err := mgr.Add(&summary.Server{
+			BindAddress:      summaryAddr,
+			Client:           mgr.GetClient(),
+			DefaultNamespace: swarmNamespace,
+			Informers:        mgr.GetCache(),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up summary API server")
+			os.Exit(1)
+		}
+	}
+
+	// Sweep the volumes and token secrets of finished and deleted tasks
+	if err := mgr.Add(&controllers.GarbageCollector{
+		Client:          controllerClient("garbagecollector"),
+		Reader:          mgr.GetAPIReader(),
+		MetricsRecorder: metricsRecorder,
+		Retention: controllers.GCConfig{
+			KeepCompletedStorageFor: keepCompletedStorageFor,
+			KeepTokenSecretsFor:     keepTokenSecretsFor,
+			DryRun:                  gcDryRun,
+		},
+		Config: operatorConfig,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up garbage collector")
+		os.Exit(1)
+	}
+
+	// Receive the progress and usage executors report
+	if progressAddr != "0" && progressAddr != "" {
+		if err := mgr.Add(&progress.Server{
+			BindAddress: progressAddr,
+			Client:      mgr.GetClient(),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up progress API server")
+			os.Exit(1)
+		}
+	}
+
+	// Accept the registrations of agents launched outside the cluster
+	if registrationAddr != "0" && registrationAddr != "" {
+		if err := mgr.Add(&registration.Server{
+			BindAddress: registrationAddr,
+			Client:      mgr.GetClient(),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up agent registration API server")
+			os.Exit(1)
+		}
+	}
+
+	// Profiling for debugging reconcile slowness without a rebuild
+	if pprofAddr != "0" && pprofAddr != "" {
+		if err := mgr.Add(&profiling.Server{
+			BindAddress: pprofAddr,
+			Client:      mgr.GetClient(),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up pprof server")
+			os.Exit(1)
+		}
+	}
+	if profileOutput != "" {
+		if profileInterval <= 0 {
+			setupLog.Error(nil, "--profile-interval must be positive")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&profiling.Dumper{
+			Dir:      profileOutput,
+			Interval: profileInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up profile dumps")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -188,6 +610,31 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Verify CRDs, namespaces and RBAC before reporting ready
+	var preflightNamespaces []string
+	seen := map[string]bool{}
+	for _, ns := range append([]string{swarmNamespace, hivemindNamespace}, namespaces...) {
+		if ns != "" && !seen[ns] {
+			seen[ns] = true
+			preflightNamespaces = append(preflightNamespaces, ns)
+		}
+	}
+	preflightChecker := &preflight.Checker{
+		Kube:            kubeClient,
+		MetricsRecorder: metricsRecorder,
+		CRDs:            preflight.DefaultCRDs(),
+		Namespaces:      preflightNamespaces,
+		Permissions:     preflight.DefaultPermissions(namespaces),
+	}
+	if err := mgr.Add(preflightChecker); err != nil {
+		setupLog.Error(err, "unable to set up preflight checks")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("preflight", preflightChecker.Ready); err != nil {
+		setupLog.Error(err, "unable to set up preflight ready check")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager",
 		"watchNamespaces", namespaces,
 		"swarmNamespace", swarmNamespace,
@@ -197,4 +644,18 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
+
+// runtimeLogLevel returns the level of the logger built from opts as one
+// that can be switched while the manager runs
+func runtimeLogLevel(opts *zap.Options) uberzap.AtomicLevel {
+	if level, ok := opts.Level.(uberzap.AtomicLevel); ok {
+		return level
+	}
+	level := uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+	if opts.Development {
+		level.SetLevel(zapcore.DebugLevel)
+	}
+	opts.Level = level
+	return level
+}
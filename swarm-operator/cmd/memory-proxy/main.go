@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// memory-proxy is the agent sidecar that caches SwarmMemoryStore reads in
+// an LRU and writes through to the memory service
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+)
+
+func main() {
+	var listenAddr string
+	var backendURL string
+	var syncURL string
+	var maxEntries int
+	var defaultTTL time.Duration
+
+	flag.StringVar(&listenAddr, "listen-address", ":7070", "The address the proxy serves agents on.")
+	flag.StringVar(&backendURL, "backend-url", os.Getenv("SWARM_MEMORY_BACKEND_URL"), "Base URL of the memory service.")
+	flag.StringVar(&syncURL, "sync-url", os.Getenv("SWARM_HIVEMIND_SYNC_URL"),
+		"Hive-mind sync endpoint invalidations are published to. Empty disables publishing.")
+	flag.IntVar(&maxEntries, "max-entries", 500, "Maximum number of cached entries.")
+	flag.DurationVar(&defaultTTL, "default-ttl", 5*time.Minute, "Upper bound on how long an entry stays cached.")
+
+	opts := zap.Options{}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	log := ctrl.Log.WithName("memory-proxy")
+
+	if backendURL == "" {
+		log.Error(errors.New("missing backend URL"), "--backend-url or SWARM_MEMORY_BACKEND_URL is required")
+		os.Exit(1)
+	}
+
+	proxy := &memorycache.Proxy{
+		Cache:      memorycache.NewCache(maxEntries),
+		Backend:    memorycache.NewHTTPBackend(backendURL),
+		Agent:      os.Getenv("SWARM_AGENT_NAME"),
+		DefaultTTL: defaultTTL,
+	}
+	if syncURL != "" {
+		proxy.Publisher = &memorycache.HTTPPublisher{
+			URL:    syncURL,
+			Client: &http.Client{Timeout: 5 * time.Second},
+		}
+	}
+
+	server := &http.Server{
+		Addr:              listenAddr,
+		Handler:           proxy,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("starting memory proxy", "address", listenAddr, "backend", backendURL)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error(err, "memory proxy failed")
+		os.Exit(1)
+	}
+}
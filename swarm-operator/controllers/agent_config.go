@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeConfigApplied reports whether the pods of an agent
+	// applied its live config
+	ConditionTypeConfigApplied = "ConfigApplied"
+
+	ReasonLiveConfigApplied = "LiveConfigApplied"
+	ReasonLiveConfigPending = "LiveConfigPending"
+
+	// agentConfigNamespace is the swarm memory namespace of live agent
+	// configs, keyed by agent name
+	agentConfigNamespace = "agent-config"
+)
+
+// agentConfig is the live config document agents watch in the swarm memory
+type agentConfig struct {
+	// Generation is the Agent generation the config was taken from.
+	// Agents report it back once applied.
+	Generation       int64                            `json:"generation"`
+	CognitivePattern string                           `json:"cognitivePattern,omitempty"`
+	Capabilities     []string                         `json:"capabilities,omitempty"`
+	Peers            []string                         `json:"peers,omitempty"`
+	TaskAffinity     []swarmv1alpha1.TaskAffinityRule `json:"taskAffinity,omitempty"`
+}
+
+// liveAgentConfig reports whether agents of the cluster get their config
+// through the swarm memory
+func liveAgentConfig(swarmCluster *swarmv1alpha1.SwarmCluster) bool {
+	return swarmCluster.Spec.AgentTemplate.LiveConfig
+}
+
+// agentConfigEntryName is the SwarmMemory holding the live config of an
+// agent
+func agentConfigEntryName(agent *swarmv1alpha1.Agent) string {
+	return agent.Name + "-config"
+}
+
+// agentConfigEnv tells the agent container where to watch its config. The
+// settings it replaces would restart the pod on every change.
+func agentConfigEnv(agent *swarmv1alpha1.Agent) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "SWARM_CONFIG_NAMESPACE", Value: agentConfigNamespace},
+		{Name: "SWARM_CONFIG_KEY", Value: agent.Name},
+	}
+}
+
+// reconcileAgentConfig publishes the live config of an agent and reports in
+// its status which generation the agent pods applied. The caller saves the
+// status.
+func (r *AgentReconciler) reconcileAgentConfig(ctx context.Context, agent *swarmv1alpha1.Agent, swarmCluster *swarmv1alpha1.SwarmCluster) error {
+	if !liveAgentConfig(swarmCluster) {
+		agent.Status.Config = nil
+		meta.RemoveStatusCondition(&agent.Status.Conditions, ConditionTypeConfigApplied)
+		entry := &swarmv1alpha1.SwarmMemory{
+			ObjectMeta: metav1.ObjectMeta{Name: agentConfigEntryName(agent), Namespace: agent.Namespace},
+		}
+		return client.IgnoreNotFound(r.Delete(ctx, entry))
+	}
+
+	config := agentConfig{
+		Generation:       agent.Generation,
+		CognitivePattern: string(agent.Spec.CognitivePattern),
+		Capabilities:     agent.Spec.Capabilities,
+		Peers:            agent.Spec.CommunicationEndpoints.Peers,
+		TaskAffinity:     agent.Spec.TaskAffinity,
+	}
+	published, err := r.publishAgentConfig(ctx, agent, swarmCluster, config)
+	if err != nil {
+		return err
+	}
+
+	status := agent.Status.Config
+	if status == nil || status.Generation != config.Generation {
+		status = &swarmv1alpha1.AgentConfigStatus{Generation: config.Generation}
+		agent.Status.Config = status
+	}
+	if published || status.PublishedTime == nil {
+		now := metav1.Now()
+		status.PublishedTime = &now
+		log.FromContext(ctx).Info("Published agent config", "generation", config.Generation)
+	}
+
+	applied, total, err := r.appliedAgentConfig(ctx, agent)
+	if err != nil {
+		return err
+	}
+	status.AppliedGeneration = applied
+
+	condition := metav1.Condition{
+		Type:    ConditionTypeConfigApplied,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonLiveConfigApplied,
+		Message: fmt.Sprintf("Agent pods applied config generation %d", config.Generation),
+	}
+	if total == 0 || applied < config.Generation {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonLiveConfigPending
+		condition.Message = fmt.Sprintf("Waiting for %d agent pods to apply config generation %d", total, config.Generation)
+	}
+	meta.SetStatusCondition(&agent.Status.Conditions, condition)
+	return nil
+}
+
+// publishAgentConfig writes the config document to the SwarmMemory of the
+// agent, owned by the agent. It reports whether the document changed.
+func (r *AgentReconciler) publishAgentConfig(ctx context.Context, agent *swarmv1alpha1.Agent, swarmCluster *swarmv1alpha1.SwarmCluster, config agentConfig) (bool, error) {
+	value, err := json.Marshal(config)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode agent config: %w", err)
+	}
+
+	entry := &swarmv1alpha1.SwarmMemory{}
+	err = r.Get(ctx, types.NamespacedName{Name: agentConfigEntryName(agent), Namespace: agent.Namespace}, entry)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && entry.Spec.Value == string(value) {
+		return false, nil
+	}
+
+	if errors.IsNotFound(err) {
+		entry = &swarmv1alpha1.SwarmMemory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      agentConfigEntryName(agent),
+				Namespace: agent.Namespace,
+				Labels: map[string]string{
+					"swarm-cluster":             swarmCluster.Name,
+					"swarm.claudeflow.io/agent": agent.Name,
+				},
+			},
+			Spec: swarmv1alpha1.SwarmMemorySpec{
+				ClusterRef: swarmCluster.Name,
+				Namespace:  agentConfigNamespace,
+				Type:       swarmv1alpha1.MemoryTypeAgentConfig,
+				Key:        agent.Name,
+				Value:      string(value),
+			},
+		}
+		if err := controllerutil.SetControllerReference(agent, entry, r.Scheme); err != nil {
+			return false, err
+		}
+		return true, r.Create(ctx, entry)
+	}
+	entry.Spec.Value = string(value)
+	return true, r.Update(ctx, entry)
+}
+
+// appliedAgentConfig returns the oldest config generation the running pods
+// of an agent applied, and how many pods run
+func (r *AgentReconciler) appliedAgentConfig(ctx context.Context, agent *swarmv1alpha1.Agent) (int64, int, error) {
+	pods, err := agentPods(ctx, r, agent)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var oldest int64
+	total := 0
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		generation, err := strconv.ParseInt(pod.Annotations[swarmv1alpha1.AppliedConfigGenerationAnnotation], 10, 64)
+		if err != nil {
+			generation = 0
+		}
+		if total == 0 || generation < oldest {
+			oldest = generation
+		}
+		total++
+	}
+	return oldest, total, nil
+}
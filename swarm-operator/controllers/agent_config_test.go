@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent live config", func() {
+	var (
+		ctx        context.Context
+		agent      *swarmv1alpha1.Agent
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *AgentReconciler
+	)
+
+	entryKey := types.NamespacedName{Name: "coder-0-config", Namespace: "default"}
+
+	pod := func(name, applied string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"swarm.claudeflow.io/agent": "coder-0"},
+		}}
+		if applied != "" {
+			p.Annotations = map[string]string{swarmv1alpha1.AppliedConfigGenerationAnnotation: applied}
+		}
+		return p
+	}
+
+	setup := func(objects ...runtime.Object) {
+		reconciler = &AgentReconciler{
+			Client:   newTestClientBuilder().WithRuntimeObjects(objects...).Build(),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		agent = &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "coder-0", Namespace: "default", UID: "coder-0-uid", Generation: 3},
+			Spec: swarmv1alpha1.AgentSpec{
+				Type:                   swarmv1alpha1.CoderAgent,
+				SwarmCluster:           "swarm",
+				Capabilities:           []string{"go"},
+				CognitivePattern:       swarmv1alpha1.LateralPattern,
+				CommunicationEndpoints: swarmv1alpha1.CommunicationSpec{Port: 8080, Peers: []string{"coder-1:8080"}},
+			},
+		}
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				AgentTemplate: swarmv1alpha1.AgentTemplateSpec{LiveConfig: true},
+			},
+		}
+	})
+
+	It("points the agent at its config instead of passing it as env", func() {
+		setup()
+		deployment, err := reconciler.constructDeploymentForAgent(agent, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "SWARM_CONFIG_KEY", Value: "coder-0"}))
+		for _, e := range env {
+			Expect(e.Name).NotTo(BeElementOf("SWARM_COGNITIVE_PATTERN", "SWARM_PEERS"))
+		}
+	})
+
+	It("publishes the config and waits for the pods to apply it", func() {
+		setup(pod("coder-0-a", "3"), pod("coder-0-b", "2"))
+		Expect(reconciler.reconcileAgentConfig(ctx, agent, cluster)).To(Succeed())
+
+		entry := &swarmv1alpha1.SwarmMemory{}
+		Expect(reconciler.Get(ctx, entryKey, entry)).To(Succeed())
+		Expect(entry.Spec.Namespace).To(Equal(agentConfigNamespace))
+		Expect(entry.Spec.Key).To(Equal("coder-0"))
+		Expect(metav1.IsControlledBy(entry, agent)).To(BeTrue())
+		config := agentConfig{}
+		Expect(json.Unmarshal([]byte(entry.Spec.Value), &config)).To(Succeed())
+		Expect(config).To(Equal(agentConfig{
+			Generation:       3,
+			CognitivePattern: "lateral",
+			Capabilities:     []string{"go"},
+			Peers:            []string{"coder-1:8080"},
+		}))
+
+		Expect(agent.Status.Config.Generation).To(Equal(int64(3)))
+		Expect(agent.Status.Config.AppliedGeneration).To(Equal(int64(2)))
+		Expect(meta.FindStatusCondition(agent.Status.Conditions, ConditionTypeConfigApplied).Reason).To(Equal(ReasonLiveConfigPending))
+
+		// A pod catches up
+		p := &corev1.Pod{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "coder-0-b", Namespace: "default"}, p)).To(Succeed())
+		p.Annotations[swarmv1alpha1.AppliedConfigGenerationAnnotation] = "3"
+		Expect(reconciler.Update(ctx, p)).To(Succeed())
+		Expect(reconciler.reconcileAgentConfig(ctx, agent, cluster)).To(Succeed())
+		Expect(agent.Status.Config.AppliedGeneration).To(Equal(int64(3)))
+		Expect(meta.IsStatusConditionTrue(agent.Status.Conditions, ConditionTypeConfigApplied)).To(BeTrue())
+	})
+
+	It("republishes a changed config under the new generation", func() {
+		setup(pod("coder-0-a", "3"))
+		Expect(reconciler.reconcileAgentConfig(ctx, agent, cluster)).To(Succeed())
+		published := agent.Status.Config.PublishedTime
+
+		agent.Generation = 4
+		agent.Spec.CognitivePattern = swarmv1alpha1.CriticalPattern
+		Expect(reconciler.reconcileAgentConfig(ctx, agent, cluster)).To(Succeed())
+		entry := &swarmv1alpha1.SwarmMemory{}
+		Expect(reconciler.Get(ctx, entryKey, entry)).To(Succeed())
+		Expect(entry.Spec.Value).To(ContainSubstring(`"cognitivePattern":"critical"`))
+		Expect(agent.Status.Config.Generation).To(Equal(int64(4)))
+		Expect(agent.Status.Config.PublishedTime).NotTo(BeIdenticalTo(published))
+		Expect(meta.IsStatusConditionTrue(agent.Status.Conditions, ConditionTypeConfigApplied)).To(BeFalse())
+	})
+
+	It("removes the config when the cluster goes back to env", func() {
+		setup(pod("coder-0-a", "3"))
+		Expect(reconciler.reconcileAgentConfig(ctx, agent, cluster)).To(Succeed())
+
+		cluster.Spec.AgentTemplate.LiveConfig = false
+		Expect(reconciler.reconcileAgentConfig(ctx, agent, cluster)).To(Succeed())
+		Expect(agent.Status.Config).To(BeNil())
+		Expect(meta.FindStatusCondition(agent.Status.Conditions, ConditionTypeConfigApplied)).To(BeNil())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, entryKey, &swarmv1alpha1.SwarmMemory{}))).To(BeTrue())
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,7 +34,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
 	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/registration"
 	"github.com/claude-flow/swarm-operator/pkg/utils"
 )
 
@@ -58,12 +61,21 @@ type AgentReconciler struct {
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=agents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=agents/finalizers,verbs=update
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemorystores,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemories,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "Agent", req.NamespacedName)
 	log := log.FromContext(ctx)
-	startTime := time.Now()
 
 	// Fetch the Agent instance
 	agent := &swarmv1alpha1.Agent{}
@@ -77,15 +89,29 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
-	// Record reconciliation metrics
-	defer func() {
-		duration := time.Since(startTime).Seconds()
-		r.MetricsRecorder.RecordReconciliation("agent", duration, err)
-	}()
-
 	// Check if the agent instance is marked to be deleted
 	if agent.GetDeletionTimestamp() != nil {
 		if controllerutil.ContainsFinalizer(agent, agentFinalizer) {
+			// Let running tasks finish, or hand them over at the deadline
+			swarmCluster := &swarmv1alpha1.SwarmCluster{}
+			if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.SwarmCluster, Namespace: agent.Namespace}, swarmCluster); err != nil {
+				if !errors.IsNotFound(err) {
+					return ctrl.Result{}, err
+				}
+				swarmCluster = nil
+			}
+			drained, err := r.drainAgent(ctx, agent, swarmCluster)
+			if err != nil {
+				log.Error(err, "Failed to drain Agent")
+				return ctrl.Result{}, err
+			}
+			if !drained {
+				if err := r.Status().Update(ctx, agent); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: drainRequeueInterval}, nil
+			}
+
 			// Run finalization logic
 			if err := r.finalizeAgent(ctx, agent); err != nil {
 				log.Error(err, "Failed to finalize Agent")
@@ -94,7 +120,7 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 
 			// Remove finalizer
 			controllerutil.RemoveFinalizer(agent, agentFinalizer)
-			err := r.Update(ctx, agent)
+			err = r.Update(ctx, agent)
 			if err != nil {
 				log.Error(err, "Failed to remove finalizer")
 				return ctrl.Result{}, err
@@ -141,6 +167,10 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		}
 		return ctrl.Result{}, err
 	}
+	if err := resolveSwarmProfile(ctx, r, swarmCluster); err != nil {
+		log.Error(err, "Failed to resolve SwarmProfile", "profile", swarmCluster.Spec.Profile)
+		return ctrl.Result{}, err
+	}
 
 	// Check if SwarmCluster is ready
 	if swarmCluster.Status.Phase != "Running" && swarmCluster.Status.Phase != "Scaling" {
@@ -148,6 +178,47 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// Pooled agents run in a shared per-type StatefulSet instead. External
+	// agents run outside the cluster and get neither.
+	if poolingEnabled(swarmCluster) && !agent.Spec.External && agent.Status.Phase != "Failed" {
+		if err := r.reconcileAgentPool(ctx, agent, swarmCluster); err != nil {
+			log.Error(err, "Failed to reconcile agent pool")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Ensure the agent Deployment, including injected sidecars, is up to date
+	if !poolingEnabled(swarmCluster) && !agent.Spec.External && agent.Status.Phase != "Failed" {
+		deployment, err := r.constructDeploymentForAgent(agent, swarmCluster)
+		if err != nil {
+			r.Recorder.Event(swarmCluster, corev1.EventTypeWarning, "InvalidAgentTemplate", err.Error())
+			return r.markAgentFailed(ctx, agent, "InvalidAgentTemplate", err.Error())
+		}
+		if err := r.applyAgentCache(ctx, swarmCluster, &deployment.Spec.Template.Spec); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.applyMemoryRecall(ctx, swarmCluster, agent.Spec.Type, &deployment.Spec.Template.Spec); err != nil {
+			return ctrl.Result{}, err
+		}
+		applyAgentTLS(swarmCluster, &deployment.Spec.Template.Spec)
+		applyHiveMindPartition(swarmCluster, agent.Name, &deployment.Spec.Template)
+		applyMessageBus(swarmCluster, agent, &deployment.Spec.Template.Spec)
+		utils.ApplyImageConfig(&deployment.Spec.Template.Spec, swarmCluster.Spec.ImageConfig)
+		if err := r.reconcileDeployment(ctx, agent, deployment); err != nil {
+			log.Error(err, "Failed to reconcile agent Deployment")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Publish the live config of the agent. The phase handlers save the
+	// status.
+	if agent.Status.Phase != "Failed" {
+		if err := r.reconcileAgentConfig(ctx, agent, swarmCluster); err != nil {
+			log.Error(err, "Failed to reconcile agent config")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Reconcile the agent based on current phase
 	switch agent.Status.Phase {
 	case "Pending":
@@ -264,15 +335,17 @@ func (r *AgentReconciler) handleActivePhase(ctx context.Context, agent *swarmv1a
 	// Check heartbeat timeout
 	if agent.Status.LastHeartbeat != nil {
 		lastHeartbeat := agent.Status.LastHeartbeat.Time
-		if time.Since(lastHeartbeat) > heartbeatTimeout {
+		if time.Since(lastHeartbeat) > agentHeartbeatTimeout(agent, swarmCluster) {
 			log.Info("Agent heartbeat timeout", "lastHeartbeat", lastHeartbeat)
 			return r.markAgentFailed(ctx, agent, "HeartbeatTimeout", 
 				fmt.Sprintf("No heartbeat for %v", time.Since(lastHeartbeat)))
 		}
 	}
 
-	// Update heartbeat
-	agent.Status.LastHeartbeat = &metav1.Time{Time: time.Now()}
+	// Update heartbeat. External agents report their own.
+	if !agent.Spec.External {
+		agent.Status.LastHeartbeat = &metav1.Time{Time: time.Now()}
+	}
 
 	// Simulate task processing
 	if agent.Status.Phase == "Ready" && len(agent.Status.CurrentTasks) > 0 {
@@ -303,6 +376,12 @@ func (r *AgentReconciler) handleActivePhase(ctx context.Context, agent *swarmv1a
 			float64(agent.Status.CompletedTasks + agent.Status.FailedTasks) * 100
 	}
 
+	// Stop taking work while draining and hand over the tasks left at the
+	// deadline
+	if _, err := r.drainAgent(ctx, agent, swarmCluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Record metrics
 	r.MetricsRecorder.RecordAgentPhase(agent.Namespace, agent.Name, string(agent.Spec.Type), agent.Status.Phase)
 	r.MetricsRecorder.RecordAgentTasks(agent.Namespace, agent.Name, string(agent.Spec.Type), len(agent.Status.CurrentTasks))
@@ -314,6 +393,10 @@ func (r *AgentReconciler) handleActivePhase(ctx context.Context, agent *swarmv1a
 		return ctrl.Result{}, err
 	}
 
+	if agentDraining(agent) {
+		return ctrl.Result{RequeueAfter: drainRequeueInterval}, nil
+	}
+
 	// Regular heartbeat interval
 	return ctrl.Result{RequeueAfter: heartbeatInterval}, nil
 }
@@ -327,7 +410,12 @@ func (r *AgentReconciler) handleFailedPhase(ctx context.Context, agent *swarmv1a
 	condHelper := utils.NewConditionHelper(&agent.Status.Conditions)
 	failedCondition := condHelper.GetCondition(utils.ConditionReady)
 	
-	if failedCondition != nil && time.Since(failedCondition.LastTransitionTime.Time) > 5*time.Minute {
+	// External agents recover once they send heartbeats again
+	attemptRecovery := failedCondition != nil && time.Since(failedCondition.LastTransitionTime.Time) > 5*time.Minute
+	if agent.Spec.External {
+		attemptRecovery = externalAgentRecovered(agent, failedCondition, swarmCluster)
+	}
+	if attemptRecovery {
 		// Attempt recovery after 5 minutes
 		log.Info("Attempting agent recovery")
 		
@@ -348,6 +436,23 @@ func (r *AgentReconciler) handleFailedPhase(ctx context.Context, agent *swarmv1a
 	return ctrl.Result{RequeueAfter: time.Minute}, nil
 }
 
+// agentHeartbeatTimeout is how long the agent may go without a heartbeat
+func agentHeartbeatTimeout(agent *swarmv1alpha1.Agent, swarmCluster *swarmv1alpha1.SwarmCluster) time.Duration {
+	if agent.Spec.External {
+		return registration.HeartbeatTimeout(swarmCluster)
+	}
+	return heartbeatTimeout
+}
+
+// externalAgentRecovered reports whether a failed external agent sent a
+// heartbeat since it failed
+func externalAgentRecovered(agent *swarmv1alpha1.Agent, failedCondition *metav1.Condition, swarmCluster *swarmv1alpha1.SwarmCluster) bool {
+	if agent.Status.LastHeartbeat == nil || time.Since(agent.Status.LastHeartbeat.Time) > registration.HeartbeatTimeout(swarmCluster) {
+		return false
+	}
+	return failedCondition == nil || agent.Status.LastHeartbeat.After(failedCondition.LastTransitionTime.Time)
+}
+
 // markAgentFailed marks the agent as failed
 func (r *AgentReconciler) markAgentFailed(ctx context.Context, agent *swarmv1alpha1.Agent, reason, message string) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
@@ -382,6 +487,22 @@ func (r *AgentReconciler) finalizeAgent(ctx context.Context, agent *swarmv1alpha
 	// 3. Notify peers of disconnection
 	// 4. Save state if needed
 
+	// Release the agent's pool slot so the pool can shrink
+	if agent.Status.PoolSlot != nil {
+		swarmCluster := &swarmv1alpha1.SwarmCluster{}
+		err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.SwarmCluster, Namespace: agent.Namespace}, swarmCluster)
+		if err == nil {
+			err = resolveSwarmProfile(ctx, r, swarmCluster)
+		}
+		if err == nil && poolingEnabled(swarmCluster) {
+			if err := r.reconcileAgentPool(ctx, agent, swarmCluster); err != nil {
+				return err
+			}
+		} else if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
 	// Update metrics
 	r.MetricsRecorder.RecordAgentPhase(agent.Namespace, agent.Name, string(agent.Spec.Type), "Terminating")
 
@@ -393,5 +514,6 @@ func (r *AgentReconciler) finalizeAgent(ctx context.Context, agent *swarmv1alpha
 func (r *AgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&swarmv1alpha1.Agent{}).
-		Complete(r)
+		Owns(&appsv1.Deployment{}).
+		Complete(r.MetricsRecorder.InstrumentReconciler("agent", r))
 }
\ No newline at end of file
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// defaultAgentImage is used when the agent template does not set an image
+	defaultAgentImage = "claudeflow/swarm-agent:latest"
+
+	// agentContainerName is the name of the operator-managed agent container
+	agentContainerName = "agent"
+
+	// gpuResourceName is the extended resource agents request GPUs as
+	gpuResourceName corev1.ResourceName = "nvidia.com/gpu"
+)
+
+// reconcileDeployment creates or updates the Deployment that runs the agent
+func (r *AgentReconciler) reconcileDeployment(ctx context.Context, agent *swarmv1alpha1.Agent, desired *appsv1.Deployment) error {
+	log := log.FromContext(ctx)
+
+	if err := controllerutil.SetControllerReference(agent, desired, r.Scheme); err != nil {
+		return err
+	}
+	// The bus credentials gain a key for every new agent; existing agents'
+	// passwords never change, so they do not roll the pods
+	if err := applyConfigHash(ctx, r, desired.Namespace, &desired.Spec.Template, messageBusCredentialsName(agent.Spec.SwarmCluster)); err != nil {
+		return err
+	}
+
+	existing := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Creating agent Deployment", "deployment", desired.Name)
+			if err := r.Create(ctx, desired); err != nil {
+				return err
+			}
+			r.Recorder.Event(agent, corev1.EventTypeNormal, "DeploymentCreated", "Created agent Deployment")
+			return nil
+		}
+		return err
+	}
+
+	keepConfigHash(existing, &existing.Spec.Template, &desired.Spec.Template)
+	if existing.Spec.Replicas != nil && *existing.Spec.Replicas == *desired.Spec.Replicas &&
+		equality.Semantic.DeepDerivative(desired.Spec.Template, existing.Spec.Template) {
+		return nil
+	}
+
+	log.Info("Updating agent Deployment", "deployment", existing.Name, "replicas", *desired.Spec.Replicas)
+	existing.Spec.Replicas = desired.Spec.Replicas
+	existing.Spec.Template = desired.Spec.Template
+	return r.Update(ctx, existing)
+}
+
+// constructDeploymentForAgent builds the agent Deployment, injecting the
+// sidecars, init containers and volumes from the SwarmCluster agent template
+func (r *AgentReconciler) constructDeploymentForAgent(agent *swarmv1alpha1.Agent, swarmCluster *swarmv1alpha1.SwarmCluster) (*appsv1.Deployment, error) {
+	labels := map[string]string{
+		"swarm-cluster":             swarmCluster.Name,
+		"agent-type":                string(agent.Spec.Type),
+		"swarm.claudeflow.io/agent": agent.Name,
+	}
+
+	env := []corev1.EnvVar{{Name: "SWARM_AGENT_NAME", Value: agent.Name}}
+	if liveAgentConfig(swarmCluster) {
+		env = append(env, agentConfigEnv(agent)...)
+	} else {
+		env = append(env,
+			corev1.EnvVar{Name: "SWARM_COGNITIVE_PATTERN", Value: string(agent.Spec.CognitivePattern)},
+			corev1.EnvVar{Name: "SWARM_PEERS", Value: strings.Join(agent.Spec.CommunicationEndpoints.Peers, ",")},
+		)
+	}
+	podSpec, err := constructAgentPodSpec(swarmCluster, agent.Spec.Type, agent.Spec.CommunicationEndpoints.Port, agent.Spec.Resources, env)
+	if err != nil {
+		return nil, err
+	}
+	applyCapabilityPlacement(swarmCluster, agent.Spec.Capabilities, &podSpec)
+	if err := applyToolBundles(swarmCluster, agent.Spec.Capabilities, &podSpec); err != nil {
+		return nil, err
+	}
+	if centralizedQueen(swarmCluster) && isQueen(agent) {
+		if err := applyQueenReservation(swarmCluster, &podSpec); err != nil {
+			return nil, err
+		}
+		labels[swarmv1alpha1.QueenLabel] = "true"
+	}
+
+	replicas := scheduledAgentReplicas(swarmCluster, agent, time.Now())
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agent.Name,
+			Namespace: agent.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"swarm.claudeflow.io/agent": agent.Name},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: monitoringAnnotations(swarmCluster),
+				},
+				Spec: podSpec,
+			},
+		},
+	}, nil
+}
+
+// constructAgentPodSpec builds the agent pod spec shared by per-agent
+// Deployments and agent pools, injecting the sidecars, init containers and
+// volumes from the SwarmCluster agent template and the image and env
+// overrides of the agent type
+func constructAgentPodSpec(swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType, port int32, res swarmv1alpha1.ResourceRequirements, env []corev1.EnvVar) (corev1.PodSpec, error) {
+	template := swarmCluster.Spec.AgentTemplate
+	image := agentTypeImage(swarmCluster, agentType)
+
+	resources, err := agentResourceRequirements(res)
+	if err != nil {
+		return corev1.PodSpec{}, err
+	}
+
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:  agentContainerName,
+				Image: image,
+				Ports: []corev1.ContainerPort{
+					{
+						Name:          "comm",
+						ContainerPort: port,
+						Protocol:      corev1.ProtocolTCP,
+					},
+				},
+				Env: append([]corev1.EnvVar{
+					{Name: "SWARM_AGENT_TYPE", Value: string(agentType)},
+					{Name: "SWARM_CLUSTER", Value: swarmCluster.Name},
+					{Name: "SWARM_TOPOLOGY", Value: string(swarmCluster.Spec.Topology)},
+				}, append(env, agentTypeEnv(swarmCluster, agentType)...)...),
+				Resources: resources,
+			},
+		},
+	}
+
+	// Inject user-supplied containers and volumes, copying so the cluster
+	// object in the cache is never mutated
+	for _, c := range template.InitContainers {
+		podSpec.InitContainers = append(podSpec.InitContainers, *c.DeepCopy())
+	}
+	for _, c := range template.Sidecars {
+		podSpec.Containers = append(podSpec.Containers, *c.DeepCopy())
+	}
+	for _, v := range template.Volumes {
+		podSpec.Volumes = append(podSpec.Volumes, *v.DeepCopy())
+	}
+	applySharedWorkspace(swarmCluster, &podSpec)
+
+	if err := utils.ValidatePodInjection(&podSpec); err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("invalid agent template for SwarmCluster %s: %w", swarmCluster.Name, err)
+	}
+
+	return podSpec, nil
+}
+
+// agentResourceRequirements converts the agent resource strings into requests
+func agentResourceRequirements(res swarmv1alpha1.ResourceRequirements) (corev1.ResourceRequirements, error) {
+	requests := corev1.ResourceList{}
+	for name, value := range map[corev1.ResourceName]string{
+		corev1.ResourceCPU:              res.CPU,
+		corev1.ResourceMemory:           res.Memory,
+		corev1.ResourceEphemeralStorage: res.Storage,
+	} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return corev1.ResourceRequirements{}, fmt.Errorf("invalid %s quantity %q: %w", name, value, err)
+		}
+		requests[name] = quantity
+	}
+
+	// Extended resources cannot be overcommitted, so GPUs are requested
+	// and limited alike
+	if res.GPU == "" {
+		return corev1.ResourceRequirements{Requests: requests}, nil
+	}
+	gpus, err := resource.ParseQuantity(res.GPU)
+	if err != nil {
+		return corev1.ResourceRequirements{}, fmt.Errorf("invalid %s quantity %q: %w", gpuResourceName, res.GPU, err)
+	}
+	requests[gpuResourceName] = gpus
+	return corev1.ResourceRequirements{
+		Requests: requests,
+		Limits:   corev1.ResourceList{gpuResourceName: gpus},
+	}, nil
+}
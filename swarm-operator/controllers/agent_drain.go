@@ -0,0 +1,228 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeDraining reports an agent that takes no new tasks and
+	// waits for its current ones before it is removed
+	ConditionTypeDraining = "Draining"
+
+	ReasonDrainInProgress = "DrainInProgress"
+	ReasonDrainTimedOut   = "DrainTimedOut"
+
+	defaultDrainTimeout = 10 * time.Minute
+
+	// drainRequeueInterval polls draining agents for finished tasks
+	drainRequeueInterval = 15 * time.Second
+)
+
+// agentDraining reports whether the agent is on its way out, either
+// cordoned for scale-down or deleted
+func agentDraining(agent *swarmv1alpha1.Agent) bool {
+	return agent.Spec.Unschedulable || agent.GetDeletionTimestamp() != nil
+}
+
+// drainTimeout returns how long draining agents of the cluster may keep
+// their tasks
+func drainTimeout(cluster *swarmv1alpha1.SwarmCluster) time.Duration {
+	if cluster == nil {
+		return defaultDrainTimeout
+	}
+	return parseDurationOrDefault(cluster.Spec.DrainTimeout, defaultDrainTimeout)
+}
+
+// drainExpired reports whether the tasks of a draining agent are due to be
+// reassigned. Agents that are not working cannot finish them at all.
+func drainExpired(agent *swarmv1alpha1.Agent, now time.Time) bool {
+	if agent.Status.Phase != "Ready" && agent.Status.Phase != "Busy" {
+		return true
+	}
+	return agent.Status.Drain != nil && !now.Before(agent.Status.Drain.Deadline.Time)
+}
+
+// updateDrainStatus starts, tracks or clears the drain of an agent. It
+// reports whether the drain just started.
+func updateDrainStatus(agent *swarmv1alpha1.Agent, timeout time.Duration, now time.Time) bool {
+	if !agentDraining(agent) {
+		agent.Status.Drain = nil
+		meta.RemoveStatusCondition(&agent.Status.Conditions, ConditionTypeDraining)
+		return false
+	}
+
+	started := false
+	if agent.Status.Drain == nil {
+		agent.Status.Drain = &swarmv1alpha1.AgentDrainStatus{
+			StartTime: metav1.NewTime(now),
+			Deadline:  metav1.NewTime(now.Add(timeout)),
+		}
+		started = true
+	}
+	drain := agent.Status.Drain
+	drain.RemainingTasks = int32(len(agent.Status.CurrentTasks))
+
+	condition := metav1.Condition{
+		Type:   ConditionTypeDraining,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonDrainInProgress,
+		Message: fmt.Sprintf("Waiting for %d tasks, reassigning them at %s",
+			drain.RemainingTasks, drain.Deadline.UTC().Format(time.RFC3339)),
+	}
+	if drainExpired(agent, now) {
+		condition.Reason = ReasonDrainTimedOut
+		condition.Message = fmt.Sprintf("Drain timed out, reassigned %d tasks", len(drain.ReassignedTasks))
+	}
+	meta.SetStatusCondition(&agent.Status.Conditions, condition)
+	return started
+}
+
+// drainAgent advances the drain of an agent and reports whether it may be
+// removed. Tasks still running at the deadline are reassigned.
+func (r *AgentReconciler) drainAgent(ctx context.Context, agent *swarmv1alpha1.Agent, cluster *swarmv1alpha1.SwarmCluster) (bool, error) {
+	now := time.Now()
+	if updateDrainStatus(agent, drainTimeout(cluster), now) {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "Draining",
+			"Agent takes no new tasks, waiting for %d running tasks", len(agent.Status.CurrentTasks))
+	}
+	if len(agent.Status.CurrentTasks) > 0 && drainExpired(agent, now) {
+		if err := r.reassignAgentTasks(ctx, agent); err != nil {
+			return false, err
+		}
+		updateDrainStatus(agent, drainTimeout(cluster), now)
+	}
+	return len(agent.Status.CurrentTasks) == 0, nil
+}
+
+// reassignAgentTasks takes the tasks the agent still holds away from it.
+// The task controller pushes them to another agent, which resumes them
+// from their latest checkpoint.
+func (r *AgentReconciler) reassignAgentTasks(ctx context.Context, agent *swarmv1alpha1.Agent) error {
+	for _, ref := range agent.Status.CurrentTasks {
+		task := &swarmv1alpha1.SwarmTask{}
+		err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: agent.Namespace}, task)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if acknowledgedAgent(task) == agent.Name && task.Status.Phase != "Completed" && task.Status.Phase != "Failed" {
+			task.Status.AssignedAgents = nil
+			task.Status.Phase = "Pending"
+			task.Status.Message = fmt.Sprintf("Agent %s was drained, reassigning task", agent.Name)
+			if err := r.Status().Update(ctx, task); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(task, corev1.EventTypeWarning, "AgentDrained",
+				"Agent %s did not finish the task within the drain timeout, reassigning it", agent.Name)
+		}
+		agent.Status.Drain.ReassignedTasks = append(agent.Status.Drain.ReassignedTasks, ref.Name)
+	}
+
+	log.FromContext(ctx).Info("Reassigned tasks of drained agent", "tasks", len(agent.Status.CurrentTasks))
+	agent.Status.CurrentTasks = nil
+	return nil
+}
+
+// drainOrder sorts the agents the operator runs by how cheaply they are
+// removed: agents already on their way out first, then the ones running the
+// fewest tasks
+func drainOrder(agents []swarmv1alpha1.Agent) []swarmv1alpha1.Agent {
+	ordered := make([]swarmv1alpha1.Agent, 0, len(agents))
+	for _, agent := range agents {
+		// External agents leave by stopping, the operator does not
+		// remove them
+		if !agent.Spec.External {
+			ordered = append(ordered, agent)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := &ordered[i], &ordered[j]
+		if agentDraining(a) != agentDraining(b) {
+			return agentDraining(a)
+		}
+		if len(a.Status.CurrentTasks) != len(b.Status.CurrentTasks) {
+			return len(a.Status.CurrentTasks) < len(b.Status.CurrentTasks)
+		}
+		return a.Name < b.Name
+	})
+	return ordered
+}
+
+// drainAgents cordons count agents for scale-down and deletes those that
+// finished draining, returning how many were deleted. Agents cordoned
+// earlier that are no longer surplus go back to work.
+func (r *SwarmClusterReconciler) drainAgents(ctx context.Context, agents []swarmv1alpha1.Agent, count int) (int, error) {
+	log := log.FromContext(ctx)
+
+	removed := 0
+	for i, agent := range drainOrder(agents) {
+		agent := agent
+		if agent.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		if i >= count {
+			if agent.Spec.Unschedulable {
+				agent.Spec.Unschedulable = false
+				if err := r.Update(ctx, &agent); err != nil {
+					return removed, err
+				}
+				log.Info("Cancelled agent drain", "agent", agent.Name)
+			}
+			continue
+		}
+
+		if !agent.Spec.Unschedulable {
+			agent.Spec.Unschedulable = true
+			if err := r.Update(ctx, &agent); err != nil {
+				return removed, err
+			}
+			log.Info("Draining agent for scale-down", "agent", agent.Name, "tasks", len(agent.Status.CurrentTasks))
+			if len(agent.Status.CurrentTasks) > 0 {
+				continue
+			}
+		}
+
+		// The agent finalizer reassigns whatever is left at the deadline
+		if len(agent.Status.CurrentTasks) > 0 && !drainExpired(&agent, time.Now()) {
+			continue
+		}
+		if err := r.Delete(ctx, &agent); err != nil && !errors.IsNotFound(err) {
+			return removed, err
+		}
+		log.Info("Deleted agent for scale-down", "agent", agent.Name)
+		removed++
+	}
+	return removed, nil
+}
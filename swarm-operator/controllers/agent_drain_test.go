@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent drain", func() {
+	var (
+		ctx       context.Context
+		k8sClient client.Client
+	)
+
+	newAgent := func(name string, tasks ...string) *swarmv1alpha1.Agent {
+		agent := &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       swarmv1alpha1.AgentSpec{Type: swarmv1alpha1.CoderAgent, SwarmCluster: "swarm"},
+			Status:     swarmv1alpha1.AgentStatus{Phase: "Ready"},
+		}
+		for _, task := range tasks {
+			agent.Status.CurrentTasks = append(agent.Status.CurrentTasks, swarmv1alpha1.TaskReference{Name: task})
+		}
+		return agent
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		k8sClient = newTestClient()
+	})
+
+	It("tracks the drain progress and deadline in the agent status", func() {
+		agent := newAgent("coder-0", "build")
+		now := time.Now()
+		Expect(updateDrainStatus(agent, time.Minute, now)).To(BeFalse())
+		Expect(agent.Status.Drain).To(BeNil())
+
+		agent.Spec.Unschedulable = true
+		Expect(updateDrainStatus(agent, time.Minute, now)).To(BeTrue())
+		Expect(agent.Status.Drain.RemainingTasks).To(Equal(int32(1)))
+		Expect(agent.Status.Drain.Deadline.Time).To(BeTemporally("~", now.Add(time.Minute), time.Second))
+		Expect(meta.FindStatusCondition(agent.Status.Conditions, ConditionTypeDraining).Reason).To(Equal(ReasonDrainInProgress))
+		Expect(drainExpired(agent, now)).To(BeFalse())
+		Expect(drainExpired(agent, now.Add(2*time.Minute))).To(BeTrue())
+
+		agent.Spec.Unschedulable = false
+		updateDrainStatus(agent, time.Minute, now)
+		Expect(agent.Status.Drain).To(BeNil())
+		Expect(meta.FindStatusCondition(agent.Status.Conditions, ConditionTypeDraining)).To(BeNil())
+	})
+
+	It("removes idle agents first and waits for busy ones", func() {
+		idle := newAgent("coder-0")
+		busy := newAgent("coder-1", "build")
+		working := newAgent("coder-2", "test", "lint")
+		for _, agent := range []*swarmv1alpha1.Agent{idle, busy, working} {
+			Expect(k8sClient.Create(ctx, agent)).To(Succeed())
+		}
+		reconciler := &SwarmClusterReconciler{Client: k8sClient}
+		agents := []swarmv1alpha1.Agent{*working, *busy, *idle}
+
+		removed, err := reconciler.drainAgents(ctx, agents, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal(1))
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "coder-0", Namespace: "default"}, &swarmv1alpha1.Agent{})).NotTo(Succeed())
+
+		stored := &swarmv1alpha1.Agent{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "coder-1", Namespace: "default"}, stored)).To(Succeed())
+		Expect(stored.Spec.Unschedulable).To(BeTrue())
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "coder-2", Namespace: "default"}, stored)).To(Succeed())
+		Expect(stored.Spec.Unschedulable).To(BeFalse())
+
+		// Scaling back up cancels the drain
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "coder-1", Namespace: "default"}, stored)).To(Succeed())
+		_, err = reconciler.drainAgents(ctx, []swarmv1alpha1.Agent{*stored}, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "coder-1", Namespace: "default"}, stored)).To(Succeed())
+		Expect(stored.Spec.Unschedulable).To(BeFalse())
+	})
+
+	It("reassigns the tasks left at the drain deadline", func() {
+		task := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", Description: "build"},
+		}
+		Expect(k8sClient.Create(ctx, task)).To(Succeed())
+		task.Status.Phase = "Scheduled"
+		task.Status.CheckpointRef = "s3://checkpoints/build/3"
+		task.Status.AssignedAgents = []swarmv1alpha1.AssignedAgent{{Name: "coder-1", Status: assignmentAcknowledged}}
+		Expect(k8sClient.Status().Update(ctx, task)).To(Succeed())
+
+		agent := newAgent("coder-1", "build")
+		agent.Spec.Unschedulable = true
+		agent.Status.Drain = &swarmv1alpha1.AgentDrainStatus{
+			StartTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			Deadline:  metav1.NewTime(time.Now().Add(-time.Minute)),
+		}
+		reconciler := &AgentReconciler{Client: k8sClient, Recorder: record.NewFakeRecorder(10)}
+
+		drained, err := reconciler.drainAgent(ctx, agent, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drained).To(BeTrue())
+		Expect(agent.Status.Drain.ReassignedTasks).To(ConsistOf("build"))
+		Expect(meta.FindStatusCondition(agent.Status.Conditions, ConditionTypeDraining).Reason).To(Equal(ReasonDrainTimedOut))
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "build", Namespace: "default"}, task)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Pending"))
+		Expect(task.Status.AssignedAgents).To(BeEmpty())
+		Expect(newAssignment(task, "").CheckpointRef).To(Equal("s3://checkpoints/build/3"))
+	})
+})
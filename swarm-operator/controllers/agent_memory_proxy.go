@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// memoryProxyContainerName is the name of the injected memory cache sidecar
+	memoryProxyContainerName = "memory-proxy"
+
+	// memoryProxyPort is where agents reach the memory proxy on localhost
+	memoryProxyPort = int32(7070)
+
+	defaultMemoryProxyImage = "claudeflow/swarm-memory-proxy:latest"
+)
+
+// applyAgentCache injects the memory proxy sidecar when a memory store of the
+// cluster has agent caching enabled and its HTTP endpoint is published
+func (r *AgentReconciler) applyAgentCache(ctx context.Context, swarmCluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) error {
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := r.List(ctx, stores, client.InNamespace(swarmCluster.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range stores.Items {
+		store := &stores.Items[i]
+		if store.Spec.SwarmClusterRef != swarmCluster.Name ||
+			store.Spec.AgentCache == nil || !store.Spec.AgentCache.Enabled ||
+			store.Status.Endpoints.HTTP == "" {
+			continue
+		}
+		injectMemoryProxy(podSpec, store)
+		return nil
+	}
+	return nil
+}
+
+// injectMemoryProxy adds the memory proxy sidecar and points the agent
+// container at it
+func injectMemoryProxy(podSpec *corev1.PodSpec, store *swarmv1alpha1.SwarmMemoryStore) {
+	cache := store.Spec.AgentCache
+
+	image := cache.Image
+	if image == "" {
+		image = defaultMemoryProxyImage
+	}
+
+	args := []string{
+		fmt.Sprintf("--listen-address=:%d", memoryProxyPort),
+		"--backend-url=" + store.Status.Endpoints.HTTP,
+	}
+	if cache.MaxEntries > 0 {
+		args = append(args, fmt.Sprintf("--max-entries=%d", cache.MaxEntries))
+	}
+	if cache.DefaultTTL != "" {
+		args = append(args, "--default-ttl="+cache.DefaultTTL)
+	}
+	if cache.SyncURL != "" {
+		args = append(args, "--sync-url="+cache.SyncURL)
+	}
+
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:  memoryProxyContainerName,
+		Image: image,
+		Args:  args,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "memory-proxy",
+				ContainerPort: memoryProxyPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name: "SWARM_AGENT_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+		},
+	})
+
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == agentContainerName {
+			podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, corev1.EnvVar{
+				Name:  "SWARM_MEMORY_URL",
+				Value: fmt.Sprintf("http://localhost:%d", memoryProxyPort),
+			})
+		}
+	}
+}
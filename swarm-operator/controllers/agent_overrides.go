@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// agentTypeImage returns the agent image of a type: its override, else the
+// template's, else the default
+func agentTypeImage(swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType) string {
+	if image := swarmCluster.Spec.AgentTypes[agentType].Image; image != "" {
+		return image
+	}
+	if image := swarmCluster.Spec.AgentTemplate.Image; image != "" {
+		return image
+	}
+	return defaultAgentImage
+}
+
+// agentTypeResources merges the resource overrides of a type over the
+// template's, quantity by quantity
+func agentTypeResources(swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType) swarmv1alpha1.ResourceRequirements {
+	res := swarmCluster.Spec.AgentTemplate.Resources
+	override := swarmCluster.Spec.AgentTypes[agentType].Resources
+	if override == nil {
+		return res
+	}
+	if override.CPU != "" {
+		res.CPU = override.CPU
+	}
+	if override.Memory != "" {
+		res.Memory = override.Memory
+	}
+	if override.Storage != "" {
+		res.Storage = override.Storage
+	}
+	if override.GPU != "" {
+		res.GPU = override.GPU
+	}
+	return res
+}
+
+// agentTypeEnv returns a copy of the environment overrides of a type
+func agentTypeEnv(swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, e := range swarmCluster.Spec.AgentTypes[agentType].Env {
+		env = append(env, *e.DeepCopy())
+	}
+	return env
+}
+
+// agentTypeImages lists the image overrides of the cluster by agent type
+func agentTypeImages(swarmCluster *swarmv1alpha1.SwarmCluster) []string {
+	var agentTypes []string
+	for agentType, override := range swarmCluster.Spec.AgentTypes {
+		if override.Image != "" {
+			agentTypes = append(agentTypes, string(agentType))
+		}
+	}
+	sort.Strings(agentTypes)
+	images := make([]string, 0, len(agentTypes))
+	for _, agentType := range agentTypes {
+		images = append(images, swarmCluster.Spec.AgentTypes[swarmv1alpha1.AgentType(agentType)].Image)
+	}
+	return images
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent type overrides", func() {
+	var cluster *swarmv1alpha1.SwarmCluster
+
+	BeforeEach(func() {
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				AgentTemplate: swarmv1alpha1.AgentTemplateSpec{
+					Image:     "ghcr.io/acme/agent:1.0",
+					Resources: swarmv1alpha1.ResourceRequirements{CPU: "500m", Memory: "1Gi"},
+				},
+				AgentTypes: map[swarmv1alpha1.AgentType]swarmv1alpha1.AgentTypeOverride{
+					swarmv1alpha1.AnalystAgent: {
+						Image:     "ghcr.io/acme/analyst:2.3",
+						Resources: &swarmv1alpha1.ResourceRequirements{Memory: "8Gi"},
+						Env:       []corev1.EnvVar{{Name: "PANDAS_THREADS", Value: "4"}},
+					},
+					swarmv1alpha1.TesterAgent: {Image: "docker.io/someone/tester:latest"},
+				},
+			},
+		}
+	})
+
+	It("runs agents of an overridden type with its image and env", func() {
+		res := agentTypeResources(cluster, swarmv1alpha1.AnalystAgent)
+		podSpec, err := constructAgentPodSpec(cluster, swarmv1alpha1.AnalystAgent, 8080, res, nil)
+		Expect(err).NotTo(HaveOccurred())
+		agent := podSpec.Containers[0]
+		Expect(agent.Image).To(Equal("ghcr.io/acme/analyst:2.3"))
+		Expect(agent.Env).To(ContainElement(corev1.EnvVar{Name: "PANDAS_THREADS", Value: "4"}))
+		Expect(agent.Env).To(ContainElement(corev1.EnvVar{Name: "SWARM_AGENT_TYPE", Value: "analyst"}))
+
+		podSpec, err = constructAgentPodSpec(cluster, swarmv1alpha1.CoderAgent, 8080, cluster.Spec.AgentTemplate.Resources, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podSpec.Containers[0].Image).To(Equal("ghcr.io/acme/agent:1.0"))
+		Expect(podSpec.Containers[0].Env).NotTo(ContainElement(HaveField("Name", "PANDAS_THREADS")))
+	})
+
+	It("merges resource overrides over the template quantity by quantity", func() {
+		Expect(agentTypeResources(cluster, swarmv1alpha1.AnalystAgent)).To(Equal(swarmv1alpha1.ResourceRequirements{CPU: "500m", Memory: "8Gi"}))
+		Expect(agentTypeResources(cluster, swarmv1alpha1.CoderAgent)).To(Equal(cluster.Spec.AgentTemplate.Resources))
+
+		r := &SwarmClusterReconciler{}
+		agent := r.constructAgentOfType(cluster, swarmv1alpha1.AnalystAgent, 0)
+		Expect(agent.Spec.Resources.Memory).To(Equal("8Gi"))
+	})
+
+	It("checks override images against the tenant's registries", func() {
+		Expect(agentTypeImages(cluster)).To(Equal([]string{"ghcr.io/acme/analyst:2.3", "docker.io/someone/tester:latest"}))
+		tenant := &swarmv1alpha1.SwarmTenant{Spec: swarmv1alpha1.SwarmTenantSpec{AllowedRegistries: []string{"ghcr.io/acme/"}}}
+		Expect(registryAllowed(tenant, agentTypeImages(cluster)[0])).To(BeTrue())
+		Expect(registryAllowed(tenant, agentTypeImages(cluster)[1])).To(BeFalse())
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// applyCapabilityPlacement merges the placement the cluster maps to each of
+// the capabilities into the agent pod spec. Capabilities are applied in
+// sorted order, so on conflicting node selector values the last one wins.
+func applyCapabilityPlacement(swarmCluster *swarmv1alpha1.SwarmCluster, capabilities []string, podSpec *corev1.PodSpec) {
+	if len(swarmCluster.Spec.CapabilityPlacement) == 0 {
+		return
+	}
+
+	sorted := append([]string(nil), capabilities...)
+	sort.Strings(sorted)
+	for i, capability := range sorted {
+		if i > 0 && capability == sorted[i-1] {
+			continue
+		}
+		placement, ok := swarmCluster.Spec.CapabilityPlacement[capability]
+		if !ok {
+			continue
+		}
+
+		for key, value := range placement.NodeSelector {
+			if podSpec.NodeSelector == nil {
+				podSpec.NodeSelector = map[string]string{}
+			}
+			podSpec.NodeSelector[key] = value
+		}
+		for j := range placement.Tolerations {
+			addToleration(podSpec, placement.Tolerations[j])
+		}
+		if placement.NodeAffinity != nil {
+			mergeNodeAffinity(podSpec, placement.NodeAffinity)
+		}
+	}
+}
+
+// addToleration appends the toleration unless the pod already has it
+func addToleration(podSpec *corev1.PodSpec, toleration corev1.Toleration) {
+	for i := range podSpec.Tolerations {
+		if podSpec.Tolerations[i].MatchToleration(&toleration) {
+			return
+		}
+	}
+	podSpec.Tolerations = append(podSpec.Tolerations, *toleration.DeepCopy())
+}
+
+// mergeNodeAffinity adds the node affinity to the pod's. Required node
+// selector terms are ORed, so to require both sides every pair of terms is
+// combined into one; preferred terms are simply appended.
+func mergeNodeAffinity(podSpec *corev1.PodSpec, affinity *corev1.NodeAffinity) {
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = affinity.DeepCopy()
+		return
+	}
+	current := podSpec.Affinity.NodeAffinity
+
+	if required := affinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+		if current.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			current.RequiredDuringSchedulingIgnoredDuringExecution = required.DeepCopy()
+		} else {
+			var terms []corev1.NodeSelectorTerm
+			for _, a := range current.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+				for _, b := range required.NodeSelectorTerms {
+					term := *a.DeepCopy()
+					for _, expression := range b.MatchExpressions {
+						term.MatchExpressions = append(term.MatchExpressions, *expression.DeepCopy())
+					}
+					for _, field := range b.MatchFields {
+						term.MatchFields = append(term.MatchFields, *field.DeepCopy())
+					}
+					terms = append(terms, term)
+				}
+			}
+			current.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = terms
+		}
+	}
+	for _, preferred := range affinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		current.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			current.PreferredDuringSchedulingIgnoredDuringExecution, *preferred.DeepCopy())
+	}
+}
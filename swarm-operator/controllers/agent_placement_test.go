@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent capability placement", func() {
+	zoneTerm := func(zone string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{
+			Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{zone},
+		}}}
+	}
+
+	cluster := &swarmv1alpha1.SwarmCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+		Spec: swarmv1alpha1.SwarmClusterSpec{
+			CapabilityPlacement: map[string]swarmv1alpha1.AgentPlacement{
+				"profile": {
+					NodeSelector: map[string]string{"pool": "high-mem"},
+					Tolerations: []corev1.Toleration{{
+						Key: "high-mem", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule,
+					}},
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{zoneTerm("a"), zoneTerm("b")},
+						},
+					},
+				},
+				"licensed": {
+					NodeSelector: map[string]string{"license": "matlab"},
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key: "license-seats", Operator: corev1.NodeSelectorOpExists,
+							}}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	It("schedules agents onto the node pool of their capability", func() {
+		agent := &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "analyst-0", Namespace: "default"},
+			Spec: swarmv1alpha1.AgentSpec{
+				Type:         "analyst",
+				Capabilities: []string{"profile", "search"},
+			},
+		}
+		deployment, err := (&AgentReconciler{}).constructDeploymentForAgent(agent, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).To(Equal(map[string]string{"pool": "high-mem"}))
+		Expect(podSpec.Tolerations).To(HaveLen(1))
+		Expect(podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(HaveLen(2))
+	})
+
+	It("requires the affinity of every mapped capability", func() {
+		podSpec := &corev1.PodSpec{}
+		applyCapabilityPlacement(cluster, []string{"profile", "licensed", "profile"}, podSpec)
+
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("pool", "high-mem"))
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("license", "matlab"))
+		Expect(podSpec.Tolerations).To(HaveLen(1))
+
+		terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(2))
+		for _, term := range terms {
+			Expect(term.MatchExpressions).To(HaveLen(2))
+		}
+	})
+
+	It("leaves agents without mapped capabilities alone", func() {
+		podSpec := &corev1.PodSpec{}
+		applyCapabilityPlacement(cluster, []string{"search"}, podSpec)
+		Expect(podSpec.NodeSelector).To(BeNil())
+		Expect(podSpec.Affinity).To(BeNil())
+
+		Expect(cluster.Spec.CapabilityPlacement["profile"].NodeAffinity.
+			RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions).To(HaveLen(1))
+	})
+})
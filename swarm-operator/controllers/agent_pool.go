@@ -0,0 +1,329 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// agentPoolLabel identifies the pool StatefulSet and its pods
+	agentPoolLabel = "swarm.claudeflow.io/agent-pool"
+
+	// defaultAgentPort is the communication port of pooled agent pods
+	defaultAgentPort = int32(8080)
+)
+
+// poolingEnabled reports whether the cluster runs agents in per-type pools
+func poolingEnabled(swarmCluster *swarmv1alpha1.SwarmCluster) bool {
+	return swarmCluster.Spec.AgentDeploymentMode == swarmv1alpha1.PooledDeploymentMode
+}
+
+// agentPods returns the pods running an agent: its pool slot in pooled
+// mode, the pods of its Deployment otherwise
+func agentPods(ctx context.Context, c client.Reader, agent *swarmv1alpha1.Agent) ([]corev1.Pod, error) {
+	if agent.Status.Pool != "" && agent.Status.PoolSlot != nil {
+		pod := &corev1.Pod{}
+		err := c.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%d", agent.Status.Pool, *agent.Status.PoolSlot), Namespace: agent.Namespace}, pod)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	}
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList, client.InNamespace(agent.Namespace),
+		client.MatchingLabels{"swarm.claudeflow.io/agent": agent.Name}); err != nil {
+		return nil, err
+	}
+	return podList.Items, nil
+}
+
+// agentPoolName returns the StatefulSet name of an agent type's pool
+func agentPoolName(swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType) string {
+	return fmt.Sprintf("%s-%s", swarmCluster.Name, agentType)
+}
+
+// reconcileAgentPool assigns the agent a slot in the pool of its type and
+// sizes the pool StatefulSet to cover every assigned slot
+func (r *AgentReconciler) reconcileAgentPool(ctx context.Context, agent *swarmv1alpha1.Agent, swarmCluster *swarmv1alpha1.SwarmCluster) error {
+	log := log.FromContext(ctx)
+	poolName := agentPoolName(swarmCluster, agent.Spec.Type)
+
+	agents, err := r.listPoolAgents(ctx, swarmCluster, agent.Spec.Type)
+	if err != nil {
+		return err
+	}
+
+	if agent.GetDeletionTimestamp() == nil && (agent.Status.PoolSlot == nil || agent.Status.Pool != poolName) {
+		slot := lowestFreeSlot(agents, agent.Name)
+		agent.Status.Pool = poolName
+		agent.Status.PoolSlot = &slot
+		if err := r.Status().Update(ctx, agent); err != nil {
+			return err
+		}
+		log.Info("Assigned agent to pool slot", "pool", poolName, "slot", slot)
+
+		for i := range agents {
+			if agents[i].Name == agent.Name {
+				agents[i] = *agent
+			}
+		}
+	}
+
+	desired, err := r.constructAgentPool(swarmCluster, agent.Spec.Type, scheduledPoolReplicas(swarmCluster, agent.Spec.Type, agents, time.Now()))
+	if err != nil {
+		return err
+	}
+	if err := r.applyAgentCache(ctx, swarmCluster, &desired.Spec.Template.Spec); err != nil {
+		return err
+	}
+	if err := r.applyMemoryRecall(ctx, swarmCluster, agent.Spec.Type, &desired.Spec.Template.Spec); err != nil {
+		return err
+	}
+	applyAgentTLS(swarmCluster, &desired.Spec.Template.Spec)
+	applyHiveMindPoolPartition(swarmCluster, &desired.Spec.Template.Spec)
+	applyMessageBusPool(swarmCluster, poolName, agent.Spec.Type, &desired.Spec.Template.Spec)
+	utils.ApplyImageConfig(&desired.Spec.Template.Spec, swarmCluster.Spec.ImageConfig)
+	if err := r.reconcileStatefulSet(ctx, swarmCluster, desired); err != nil {
+		return err
+	}
+
+	// Migrating from per-agent mode: drop the agent's own Deployment
+	deployment := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, deployment)
+	if err == nil && metav1.IsControlledBy(deployment, agent) {
+		if err := r.Delete(ctx, deployment); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	} else if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if agent.GetDeletionTimestamp() != nil || agent.Status.PoolSlot == nil {
+		return nil
+	}
+	return r.labelSlotPod(ctx, agent, swarmCluster)
+}
+
+// listPoolAgents lists the live agents of a type that share a pool
+func (r *AgentReconciler) listPoolAgents(ctx context.Context, swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType) ([]swarmv1alpha1.Agent, error) {
+	agentList := &swarmv1alpha1.AgentList{}
+	if err := r.List(ctx, agentList,
+		client.InNamespace(swarmCluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": swarmCluster.Name, "agent-type": string(agentType)}); err != nil {
+		return nil, err
+	}
+
+	agents := make([]swarmv1alpha1.Agent, 0, len(agentList.Items))
+	for _, a := range agentList.Items {
+		if a.GetDeletionTimestamp() == nil {
+			agents = append(agents, a)
+		}
+	}
+	return agents, nil
+}
+
+// lowestFreeSlot returns the lowest pool ordinal not held by another agent
+func lowestFreeSlot(agents []swarmv1alpha1.Agent, self string) int32 {
+	taken := map[int32]bool{}
+	for _, a := range agents {
+		if a.Name != self && a.Status.PoolSlot != nil {
+			taken[*a.Status.PoolSlot] = true
+		}
+	}
+	slot := int32(0)
+	for taken[slot] {
+		slot++
+	}
+	return slot
+}
+
+// poolReplicas returns the replica count needed to cover the highest slot.
+// Freed slots below it stay idle until a new agent claims them.
+func poolReplicas(agents []swarmv1alpha1.Agent) int32 {
+	replicas := int32(0)
+	for _, a := range agents {
+		if a.Status.PoolSlot != nil && *a.Status.PoolSlot+1 > replicas {
+			replicas = *a.Status.PoolSlot + 1
+		}
+	}
+	return replicas
+}
+
+// constructAgentPool builds the StatefulSet running all agents of a type.
+// Agents learn which Agent they serve from the agent label on their pod,
+// exposed through the downward API.
+func (r *AgentReconciler) constructAgentPool(swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType, replicas int32) (*appsv1.StatefulSet, error) {
+	name := agentPoolName(swarmCluster, agentType)
+	labels := map[string]string{
+		"swarm-cluster": swarmCluster.Name,
+		"agent-type":    string(agentType),
+		agentPoolLabel:  name,
+	}
+
+	podSpec, err := constructAgentPodSpec(swarmCluster, agentType, defaultAgentPort, agentTypeResources(swarmCluster, agentType),
+		[]corev1.EnvVar{
+			{Name: "SWARM_AGENT_POOL", Value: name},
+			{Name: "SWARM_AGENT_LABELS_FILE", Value: podInfoMountPath + "/labels"},
+		})
+	if err != nil {
+		return nil, err
+	}
+	applyCapabilityPlacement(swarmCluster, swarmCluster.Spec.AgentTemplate.Capabilities, &podSpec)
+	if err := applyToolBundles(swarmCluster, swarmCluster.Spec.AgentTemplate.Capabilities, &podSpec); err != nil {
+		return nil, err
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: podInfoVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path:     "labels",
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels"},
+					},
+				},
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      podInfoVolumeName,
+		MountPath: podInfoMountPath,
+		ReadOnly:  true,
+	})
+
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: swarmCluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            &replicas,
+			ServiceName:         name,
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{agentPoolLabel: name},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: monitoringAnnotations(swarmCluster),
+				},
+				Spec: podSpec,
+			},
+		},
+	}, nil
+}
+
+// reconcileStatefulSet creates or updates a pool StatefulSet owned by the cluster
+func (r *AgentReconciler) reconcileStatefulSet(ctx context.Context, swarmCluster *swarmv1alpha1.SwarmCluster, desired *appsv1.StatefulSet) error {
+	log := log.FromContext(ctx)
+
+	if err := controllerutil.SetControllerReference(swarmCluster, desired, r.Scheme); err != nil {
+		return err
+	}
+	if err := applyConfigHash(ctx, r, desired.Namespace, &desired.Spec.Template, messageBusCredentialsName(swarmCluster.Name)); err != nil {
+		return err
+	}
+
+	existing := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Creating agent pool", "statefulset", desired.Name)
+			if err := r.Create(ctx, desired); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(swarmCluster, corev1.EventTypeNormal, "AgentPoolCreated", "Created agent pool %s", desired.Name)
+			return nil
+		}
+		return err
+	}
+
+	keepConfigHash(existing, &existing.Spec.Template, &desired.Spec.Template)
+	if *existing.Spec.Replicas == *desired.Spec.Replicas &&
+		equality.Semantic.DeepDerivative(desired.Spec.Template, existing.Spec.Template) {
+		return nil
+	}
+
+	log.Info("Updating agent pool", "statefulset", existing.Name, "replicas", *desired.Spec.Replicas)
+	existing.Spec.Replicas = desired.Spec.Replicas
+	existing.Spec.Template = desired.Spec.Template
+	return r.Update(ctx, existing)
+}
+
+// labelSlotPod marks the pod in the agent's slot with the agent name and
+// its hive-mind partition. Pods recreated by the StatefulSet are relabeled
+// on the next heartbeat.
+func (r *AgentReconciler) labelSlotPod(ctx context.Context, agent *swarmv1alpha1.Agent, swarmCluster *swarmv1alpha1.SwarmCluster) error {
+	pod := &corev1.Pod{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%d", agent.Status.Pool, *agent.Status.PoolSlot),
+		Namespace: agent.Namespace,
+	}, pod)
+	if err != nil {
+		// The StatefulSet has not created the pod yet
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	labels := map[string]string{"swarm.claudeflow.io/agent": agent.Name}
+	if hiveMindEnabled(swarmCluster) {
+		labels[hiveMindPartitionLabel] = strconv.Itoa(int(agentPartition(agent.Name, hiveMindPartitionCount(swarmCluster))))
+	}
+	current := true
+	for k, v := range labels {
+		if pod.Labels[k] != v {
+			current = false
+		}
+	}
+	if current {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		pod.Labels[k] = v
+	}
+	return r.Patch(ctx, pod, patch)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent pools", func() {
+	slotted := func(name string, slot int32) swarmv1alpha1.Agent {
+		return swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     swarmv1alpha1.AgentStatus{PoolSlot: &slot},
+		}
+	}
+
+	It("should assign the lowest free slot", func() {
+		agents := []swarmv1alpha1.Agent{
+			slotted("a", 0),
+			slotted("b", 2),
+			{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+		}
+		Expect(lowestFreeSlot(agents, "c")).To(Equal(int32(1)))
+	})
+
+	It("should keep the agent's own slot free for reassignment", func() {
+		agents := []swarmv1alpha1.Agent{slotted("a", 0)}
+		Expect(lowestFreeSlot(agents, "a")).To(Equal(int32(0)))
+	})
+
+	It("should size the pool to the highest assigned slot", func() {
+		agents := []swarmv1alpha1.Agent{slotted("a", 0), slotted("b", 3)}
+		Expect(poolReplicas(agents)).To(Equal(int32(4)))
+		Expect(poolReplicas(nil)).To(Equal(int32(0)))
+	})
+})
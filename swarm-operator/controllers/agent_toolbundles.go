@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// defaultToolBundlePath is where bundle images hold the bundle
+	defaultToolBundlePath = "/bundle"
+
+	// toolBundleMountRoot is the parent of the default bundle mount paths
+	toolBundleMountRoot = "/opt/tools"
+
+	// toolBundleStagingPath is where init containers copy image bundles to
+	toolBundleStagingPath = "/staging"
+
+	// toolBundlesEnv lists the mounted bundles as name=path pairs, so
+	// agents can put their binaries on the PATH
+	toolBundlesEnv = "SWARM_TOOL_BUNDLES"
+)
+
+// toolBundleVolumeName is the pod volume of the bundle
+func toolBundleVolumeName(name string) string {
+	return "tools-" + name
+}
+
+// toolBundleMountPath returns where agents find the bundle
+func toolBundleMountPath(bundle *swarmv1alpha1.ToolBundle) string {
+	if bundle.MountPath != "" {
+		return bundle.MountPath
+	}
+	return toolBundleMountRoot + "/" + bundle.Name
+}
+
+// capabilityToolBundles returns the bundles the capabilities need, sorted
+// by name so the pod template stays stable
+func capabilityToolBundles(swarmCluster *swarmv1alpha1.SwarmCluster, capabilities []string) ([]*swarmv1alpha1.ToolBundle, error) {
+	bundles := map[string]*swarmv1alpha1.ToolBundle{}
+	for i := range swarmCluster.Spec.ToolBundles {
+		bundles[swarmCluster.Spec.ToolBundles[i].Name] = &swarmCluster.Spec.ToolBundles[i]
+	}
+
+	needed := map[string]bool{}
+	for _, capability := range capabilities {
+		for _, name := range swarmCluster.Spec.CapabilityToolBundles[capability] {
+			if _, ok := bundles[name]; !ok {
+				return nil, fmt.Errorf("capability %s needs tool bundle %s, which the cluster does not define", capability, name)
+			}
+			needed[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(needed))
+	for name := range needed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	selected := make([]*swarmv1alpha1.ToolBundle, 0, len(names))
+	for _, name := range names {
+		selected = append(selected, bundles[name])
+	}
+	return selected, nil
+}
+
+// applyToolBundles mounts the tool bundles of the capabilities read-only
+// into the agent container. Image bundles are copied by an init container
+// into an emptyDir, claim bundles are mounted directly.
+func applyToolBundles(swarmCluster *swarmv1alpha1.SwarmCluster, capabilities []string, podSpec *corev1.PodSpec) error {
+	bundles, err := capabilityToolBundles(swarmCluster, capabilities)
+	if err != nil || len(bundles) == 0 {
+		return err
+	}
+
+	var agent *corev1.Container
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == agentContainerName {
+			agent = &podSpec.Containers[i]
+		}
+	}
+	if agent == nil {
+		return fmt.Errorf("pod has no %s container to mount tool bundles into", agentContainerName)
+	}
+
+	pairs := make([]string, 0, len(bundles))
+	for _, bundle := range bundles {
+		volumeName := toolBundleVolumeName(bundle.Name)
+		mountPath := toolBundleMountPath(bundle)
+		mount := corev1.VolumeMount{Name: volumeName, MountPath: mountPath, ReadOnly: true}
+
+		if claim := bundle.PersistentVolumeClaim; claim != nil {
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim.ClaimName, ReadOnly: true},
+				},
+			})
+			mount.SubPath = claim.SubPath
+		} else {
+			path := bundle.Path
+			if path == "" {
+				path = defaultToolBundlePath
+			}
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name:         volumeName,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			})
+			podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+				Name:         volumeName,
+				Image:        bundle.Image,
+				Command:      []string{"cp", "-a", strings.TrimSuffix(path, "/") + "/.", toolBundleStagingPath},
+				VolumeMounts: []corev1.VolumeMount{{Name: volumeName, MountPath: toolBundleStagingPath}},
+			})
+		}
+
+		agent.VolumeMounts = append(agent.VolumeMounts, mount)
+		pairs = append(pairs, bundle.Name+"="+mountPath)
+	}
+	agent.Env = append(agent.Env, corev1.EnvVar{Name: toolBundlesEnv, Value: strings.Join(pairs, ",")})
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent tool bundles", func() {
+	var cluster *swarmv1alpha1.SwarmCluster
+
+	agent := func(capabilities ...string) *swarmv1alpha1.Agent {
+		return &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "ops-0", Namespace: "default"},
+			Spec:       swarmv1alpha1.AgentSpec{Type: "coordinator", Capabilities: capabilities},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				ToolBundles: []swarmv1alpha1.ToolBundle{
+					{Name: "terraform", Image: "registry.example.com/tools/terraform:1.7"},
+					{Name: "kubectl", Image: "registry.example.com/tools/kubectl:1.29", Path: "/opt/kubectl/"},
+					{Name: "python", PersistentVolumeClaim: &swarmv1alpha1.ToolBundleClaim{ClaimName: "tool-cache", SubPath: "python3.12"}, MountPath: "/usr/local/python"},
+				},
+				CapabilityToolBundles: map[string][]string{
+					"infrastructure": {"terraform", "kubectl"},
+					"deploy":         {"kubectl"},
+					"data-analysis":  {"python"},
+				},
+			},
+		}
+	})
+
+	It("mounts only the bundles of the agent's capabilities", func() {
+		deployment, err := (&AgentReconciler{}).constructDeploymentForAgent(agent("infrastructure", "deploy", "search"), cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.InitContainers).To(HaveLen(2))
+		Expect(podSpec.InitContainers[0].Name).To(Equal("tools-kubectl"))
+		Expect(podSpec.InitContainers[0].Command).To(Equal([]string{"cp", "-a", "/opt/kubectl/.", toolBundleStagingPath}))
+		Expect(podSpec.InitContainers[1].Image).To(Equal("registry.example.com/tools/terraform:1.7"))
+
+		container := podSpec.Containers[0]
+		Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "tools-terraform", MountPath: "/opt/tools/terraform", ReadOnly: true}))
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{
+			Name: toolBundlesEnv, Value: "kubectl=/opt/tools/kubectl,terraform=/opt/tools/terraform",
+		}))
+		for _, volume := range podSpec.Volumes {
+			Expect(volume.Name).NotTo(Equal("tools-python"))
+		}
+	})
+
+	It("mounts claim bundles read-only without copying them", func() {
+		deployment, err := (&AgentReconciler{}).constructDeploymentForAgent(agent("data-analysis"), cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.InitContainers).To(BeEmpty())
+		Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "tools-python",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "tool-cache", ReadOnly: true},
+			},
+		}))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name: "tools-python", MountPath: "/usr/local/python", SubPath: "python3.12", ReadOnly: true,
+		}))
+	})
+
+	It("leaves agents without bundle capabilities alone", func() {
+		deployment, err := (&AgentReconciler{}).constructDeploymentForAgent(agent("search"), cluster)
+		Expect(err).NotTo(HaveOccurred())
+		for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+			Expect(env.Name).NotTo(Equal(toolBundlesEnv))
+		}
+	})
+
+	It("rejects capabilities referencing undefined bundles", func() {
+		cluster.Spec.CapabilityToolBundles["go"] = []string{"golang"}
+		_, err := (&AgentReconciler{}).constructDeploymentForAgent(agent("go"), cluster)
+		Expect(err).To(MatchError(ContainSubstring("tool bundle golang")))
+	})
+})
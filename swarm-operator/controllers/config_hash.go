@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// configHashAnnotation on a pod template hashes the ConfigMaps and
+	// Secrets its pods reference, so changing them rolls the pods
+	configHashAnnotation = "swarm.claudeflow.io/config-hash"
+
+	// autoRolloutAnnotation set to "false" on a Deployment or StatefulSet
+	// keeps its pods running when the ConfigMaps and Secrets they reference
+	// change. Other changes to the pod template still roll them.
+	autoRolloutAnnotation = "swarm.claudeflow.io/auto-rollout"
+)
+
+// podConfigRefs returns the ConfigMaps and Secrets a pod spec references
+// through volumes and environment variables, sorted by name
+func podConfigRefs(podSpec *corev1.PodSpec) (configMaps, secrets []string) {
+	cms := map[string]bool{}
+	secs := map[string]bool{}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for _, container := range containers {
+			for _, env := range container.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+					cms[ref.Name] = true
+				}
+				if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+					secs[ref.Name] = true
+				}
+			}
+			for _, from := range container.EnvFrom {
+				if from.ConfigMapRef != nil {
+					cms[from.ConfigMapRef.Name] = true
+				}
+				if from.SecretRef != nil {
+					secs[from.SecretRef.Name] = true
+				}
+			}
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		if volume.ConfigMap != nil {
+			cms[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			secs[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					cms[source.ConfigMap.Name] = true
+				}
+				if source.Secret != nil {
+					secs[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(cms), sortedKeys(secs)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// applyConfigHash annotates the pod template with the hash of the
+// ConfigMaps and Secrets it references in namespace. Missing ones hash as
+// missing, so creating them later rolls the pods too. ConfigMaps and
+// Secrets listed in skip are read live by the pods, or only grow keys the
+// pods do not use, and are left out.
+func applyConfigHash(ctx context.Context, c client.Reader, namespace string, template *corev1.PodTemplateSpec, skip ...string) error {
+	configMaps, secrets := podConfigRefs(&template.Spec)
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return nil
+	}
+
+	h := sha256.New()
+	for _, name := range configMaps {
+		if containsString(skip, name) {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cm)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		fmt.Fprintf(h, "configmap/%s\n", name)
+		if err == nil {
+			hashData(h, cm.Data)
+			hashBinaryData(h, cm.BinaryData)
+		}
+	}
+	for _, name := range secrets {
+		if containsString(skip, name) {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		fmt.Fprintf(h, "secret/%s\n", name)
+		if err == nil {
+			hashBinaryData(h, secret.Data)
+		}
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[configHashAnnotation] = hex.EncodeToString(h.Sum(nil))[:16]
+	return nil
+}
+
+func hashData(w io.Writer, data map[string]string) {
+	for _, key := range sortedKeys(data) {
+		fmt.Fprintf(w, "%s=%q\n", key, data[key])
+	}
+}
+
+func hashBinaryData(w io.Writer, data map[string][]byte) {
+	for _, key := range sortedKeys(data) {
+		fmt.Fprintf(w, "%s=%x\n", key, data[key])
+	}
+}
+
+// keepConfigHash carries the config hash of the running pods over to the
+// desired template of a workload that opted out of automatic rollouts
+func keepConfigHash(workload metav1.Object, current, desired *corev1.PodTemplateSpec) {
+	if workload.GetAnnotations()[autoRolloutAnnotation] != "false" {
+		return
+	}
+	hash, ok := current.Annotations[configHashAnnotation]
+	if !ok {
+		delete(desired.Annotations, configHashAnnotation)
+		return
+	}
+	if desired.Annotations == nil {
+		desired.Annotations = map[string]string{}
+	}
+	desired.Annotations[configHashAnnotation] = hash
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Config hash", func() {
+	var (
+		ctx       context.Context
+		k8sClient client.Client
+		config    *corev1.ConfigMap
+		template  func() *corev1.PodTemplateSpec
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		config = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-config", Namespace: "default"},
+			Data:       map[string]string{"config.yaml": "model: a"},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-token", Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("t")},
+		}
+		k8sClient = newTestClient(config, secret)
+
+		template = func() *corev1.PodTemplateSpec {
+			return &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: agentContainerName,
+					Env: []corev1.EnvVar{{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "agent-token"}, Key: "token",
+						},
+					}}},
+				}},
+				Volumes: []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "agent-config"}},
+				}}},
+			}}
+		}
+	})
+
+	It("collects the referenced ConfigMaps and Secrets", func() {
+		configMaps, secrets := podConfigRefs(&template().Spec)
+		Expect(configMaps).To(Equal([]string{"agent-config"}))
+		Expect(secrets).To(Equal([]string{"agent-token"}))
+	})
+
+	It("changes the hash when a referenced ConfigMap changes", func() {
+		before := template()
+		Expect(applyConfigHash(ctx, k8sClient, "default", before)).To(Succeed())
+		Expect(before.Annotations).To(HaveKey(configHashAnnotation))
+
+		unchanged := template()
+		Expect(applyConfigHash(ctx, k8sClient, "default", unchanged)).To(Succeed())
+		Expect(unchanged.Annotations[configHashAnnotation]).To(Equal(before.Annotations[configHashAnnotation]))
+
+		config.Data["config.yaml"] = "model: b"
+		Expect(k8sClient.Update(ctx, config)).To(Succeed())
+		after := template()
+		Expect(applyConfigHash(ctx, k8sClient, "default", after)).To(Succeed())
+		Expect(after.Annotations[configHashAnnotation]).NotTo(Equal(before.Annotations[configHashAnnotation]))
+
+		skipped := template()
+		Expect(applyConfigHash(ctx, k8sClient, "default", skipped, "agent-config")).To(Succeed())
+		config.Data["config.yaml"] = "model: c"
+		Expect(k8sClient.Update(ctx, config)).To(Succeed())
+		again := template()
+		Expect(applyConfigHash(ctx, k8sClient, "default", again, "agent-config")).To(Succeed())
+		Expect(again.Annotations[configHashAnnotation]).To(Equal(skipped.Annotations[configHashAnnotation]))
+	})
+
+	It("keeps the running hash for workloads that opted out", func() {
+		current := template()
+		current.Annotations = map[string]string{configHashAnnotation: "old"}
+		desired := template()
+		desired.Annotations = map[string]string{configHashAnnotation: "new"}
+
+		workload := &appsv1.Deployment{}
+		keepConfigHash(workload, current, desired)
+		Expect(desired.Annotations[configHashAnnotation]).To(Equal("new"))
+
+		workload.Annotations = map[string]string{autoRolloutAnnotation: "false"}
+		keepConfigHash(workload, current, desired)
+		Expect(desired.Annotations[configHashAnnotation]).To(Equal("old"))
+	})
+})
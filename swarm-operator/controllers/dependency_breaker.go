@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	stderrors "errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/claude-flow/swarm-operator/pkg/circuitbreaker"
+)
+
+const (
+	// dependencyGitHub names the GitHub API circuit breaker
+	dependencyGitHub = "github"
+
+	// ConditionTypeDependenciesAvailable is False while a circuit breaker
+	// for an external dependency the resource needs is open
+	ConditionTypeDependenciesAvailable = "DependenciesAvailable"
+
+	ReasonCircuitOpen           = "CircuitOpen"
+	ReasonDependenciesAvailable = "DependenciesAvailable"
+)
+
+// guardDependency runs call through the breaker of the dependency. Without
+// a registry the call is made unguarded.
+func guardDependency(breakers *circuitbreaker.Registry, dependency string, call func() error) error {
+	if breakers == nil {
+		return call()
+	}
+	return breakers.Get(dependency).Do(call)
+}
+
+// dependencyUnavailable marks the conditions when err comes from an open
+// breaker and returns how long to wait before the next attempt
+func dependencyUnavailable(conditions *[]metav1.Condition, err error) (time.Duration, bool) {
+	var openErr *circuitbreaker.OpenError
+	if !stderrors.As(err, &openErr) {
+		return 0, false
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    ConditionTypeDependenciesAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonCircuitOpen,
+		Message: openErr.Error(),
+	})
+	return openErr.RetryAfter, true
+}
+
+// dependenciesRecovered flips a previously unavailable condition back to
+// True and reports whether the conditions changed
+func dependenciesRecovered(conditions *[]metav1.Condition) bool {
+	if !meta.IsStatusConditionFalse(*conditions, ConditionTypeDependenciesAvailable) {
+		return false
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    ConditionTypeDependenciesAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonDependenciesAvailable,
+		Message: "All external dependencies are reachable",
+	})
+	return true
+}
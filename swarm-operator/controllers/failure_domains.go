@@ -0,0 +1,167 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeZoneSpread reports whether the ready replicas of a
+	// memory store run in more than one failure domain
+	ConditionTypeZoneSpread = "ZoneSpread"
+
+	// ConditionTypeHiveMindZoneSpread is ConditionTypeZoneSpread for the
+	// hive-mind replicas of a SwarmCluster
+	ConditionTypeHiveMindZoneSpread = "HiveMindZoneSpread"
+
+	ReasonSpreadAcrossZones = "SpreadAcrossZones"
+	ReasonSingleZone        = "SingleZone"
+	ReasonZonesUnknown      = "ZonesUnknown"
+)
+
+// failureDomainKey is the node label replicas are spread over
+func failureDomainKey(spec *swarmv1alpha1.FailureDomainSpec) string {
+	if spec != nil && spec.TopologyKey != "" {
+		return spec.TopologyKey
+	}
+	return corev1.LabelTopologyZone
+}
+
+// applyFailureDomains spreads the replicas selected by labels over failure
+// domains. Without a spec replicas are spread softly, so clusters with a
+// single zone keep scheduling them.
+func applyFailureDomains(spec *swarmv1alpha1.FailureDomainSpec, labels map[string]string, podSpec *corev1.PodSpec) {
+	key := failureDomainKey(spec)
+	maxSkew := int32(1)
+	whenUnsatisfiable := corev1.ScheduleAnyway
+	required := false
+	var zones []string
+	if spec != nil {
+		if spec.MaxSkew > 0 {
+			maxSkew = spec.MaxSkew
+		}
+		if spec.WhenUnsatisfiable != "" {
+			whenUnsatisfiable = spec.WhenUnsatisfiable
+		}
+		required = spec.RequiredAntiAffinity
+		zones = spec.Zones
+	}
+
+	selector := &metav1.LabelSelector{MatchLabels: labels}
+	podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+		MaxSkew:           maxSkew,
+		TopologyKey:       key,
+		WhenUnsatisfiable: whenUnsatisfiable,
+		LabelSelector:     selector,
+	})
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	antiAffinity := podSpec.Affinity.PodAntiAffinity
+	term := corev1.PodAffinityTerm{LabelSelector: selector.DeepCopy(), TopologyKey: key}
+	if required {
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	} else {
+		antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: term})
+	}
+
+	// Volumes bound on first consumer follow the replica into its zone
+	if len(zones) > 0 {
+		mergeNodeAffinity(podSpec, &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      corev1.LabelTopologyZone,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   append([]string(nil), zones...),
+					}},
+				}},
+			},
+		})
+	}
+}
+
+// zoneSpreadCondition reports which failure domains the ready replicas run
+// in. It returns nil with fewer than two scheduled ready replicas, which
+// cannot be spread.
+func zoneSpreadCondition(ctx context.Context, c client.Client, conditionType string, pods []*corev1.Pod, key string, generation int64) (*metav1.Condition, error) {
+	zones := map[string]int{}
+	ready := 0
+	unlabeled := 0
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || !podReady(pod) {
+			continue
+		}
+		ready++
+		node := &corev1.Node{}
+		if err := c.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		zone, ok := node.Labels[key]
+		if !ok {
+			unlabeled++
+			continue
+		}
+		zones[zone]++
+	}
+	if ready < 2 {
+		return nil, nil
+	}
+
+	condition := &metav1.Condition{Type: conditionType, ObservedGeneration: generation}
+	switch {
+	case unlabeled > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = ReasonZonesUnknown
+		condition.Message = fmt.Sprintf("%d of %d ready replicas run on nodes without a %s label", unlabeled, ready, key)
+	case len(zones) == 1:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonSingleZone
+		for zone := range zones {
+			condition.Message = fmt.Sprintf("All %d ready replicas run in %s; losing it takes them all down", ready, zone)
+		}
+	default:
+		names := make([]string, 0, len(zones))
+		for zone := range zones {
+			names = append(names, zone)
+		}
+		sort.Strings(names)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonSpreadAcrossZones
+		condition.Message = fmt.Sprintf("%d ready replicas run in %s", ready, strings.Join(names, ", "))
+	}
+	return condition, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Failure domains", func() {
+	labels := map[string]string{"swarm-cluster": "swarm", "component": "hivemind"}
+
+	It("spreads replicas softly by default", func() {
+		podSpec := &corev1.PodSpec{}
+		applyFailureDomains(nil, labels, podSpec)
+
+		Expect(podSpec.TopologySpreadConstraints).To(ConsistOf(corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+		}))
+		antiAffinity := podSpec.Affinity.PodAntiAffinity
+		Expect(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+		Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+		Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm.TopologyKey).To(Equal(corev1.LabelTopologyZone))
+		Expect(podSpec.Affinity.NodeAffinity).To(BeNil())
+	})
+
+	It("requires separate zones and pins replicas to the listed ones", func() {
+		podSpec := &corev1.PodSpec{}
+		applyFailureDomains(&swarmv1alpha1.FailureDomainSpec{
+			WhenUnsatisfiable:    corev1.DoNotSchedule,
+			RequiredAntiAffinity: true,
+			Zones:                []string{"eu-west-1a", "eu-west-1b", "eu-west-1c"},
+		}, labels, podSpec)
+
+		Expect(podSpec.TopologySpreadConstraints[0].WhenUnsatisfiable).To(Equal(corev1.DoNotSchedule))
+		Expect(podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(ConsistOf(corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+			TopologyKey:   corev1.LabelTopologyZone,
+		}))
+		terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].MatchExpressions[0].Values).To(ConsistOf("eu-west-1a", "eu-west-1b", "eu-west-1c"))
+	})
+
+	It("spreads the hive-mind StatefulSet", func() {
+		cluster := &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				HiveMind: &swarmv1alpha1.HiveMindSpec{
+					Enabled:        true,
+					Replicas:       3,
+					FailureDomains: &swarmv1alpha1.FailureDomainSpec{TopologyKey: "topology.example.com/rack"},
+				},
+			},
+		}
+		sts := constructHiveMindStatefulSet(cluster)
+		constraints := sts.Spec.Template.Spec.TopologySpreadConstraints
+		Expect(constraints).To(HaveLen(1))
+		Expect(constraints[0].TopologyKey).To(Equal("topology.example.com/rack"))
+		Expect(constraints[0].LabelSelector.MatchLabels).To(Equal(sts.Spec.Selector.MatchLabels))
+	})
+
+	Context("reporting the spread", func() {
+		var k8sClient client.Client
+
+		node := func(name, zone string) *corev1.Node {
+			n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			if zone != "" {
+				n.Labels = map[string]string{corev1.LabelTopologyZone: zone}
+			}
+			return n
+		}
+		pods := func(nodes ...string) []*corev1.Pod {
+			var pods []*corev1.Pod
+			for i, n := range nodes {
+				pods = append(pods, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("swarm-hivemind-%d", i)},
+					Spec:       corev1.PodSpec{NodeName: n},
+					Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+				})
+			}
+			return pods
+		}
+
+		BeforeEach(func() {
+			k8sClient = newTestClient(
+				node("node-a1", "eu-west-1a"),
+				node("node-a2", "eu-west-1a"),
+				node("node-b1", "eu-west-1b"),
+				node("node-x", ""),
+			)
+		})
+
+		It("flags replicas skewed into a single zone", func() {
+			condition, err := zoneSpreadCondition(context.Background(), k8sClient, ConditionTypeHiveMindZoneSpread,
+				pods("node-a1", "node-a2"), corev1.LabelTopologyZone, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ReasonSingleZone))
+			Expect(condition.Message).To(ContainSubstring("eu-west-1a"))
+		})
+
+		It("reports replicas spread over zones", func() {
+			condition, err := zoneSpreadCondition(context.Background(), k8sClient, ConditionTypeHiveMindZoneSpread,
+				pods("node-a1", "node-b1"), corev1.LabelTopologyZone, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("2 ready replicas run in eu-west-1a, eu-west-1b"))
+		})
+
+		It("cannot tell without zone labels", func() {
+			condition, err := zoneSpreadCondition(context.Background(), k8sClient, ConditionTypeZoneSpread,
+				pods("node-a1", "node-x"), corev1.LabelTopologyZone, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Reason).To(Equal(ReasonZonesUnknown))
+		})
+
+		It("ignores a single replica and replicas not ready", func() {
+			replicas := pods("node-a1", "node-b1")
+			replicas[1].Status.Conditions[0].Status = corev1.ConditionFalse
+			condition, err := zoneSpreadCondition(context.Background(), k8sClient, ConditionTypeZoneSpread,
+				replicas, corev1.LabelTopologyZone, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(condition).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,301 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+)
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=list;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=list;patch;delete
+
+const (
+	// managedByLabel marks the resources the operator created, the only
+	// ones the garbage collector deletes
+	managedByLabel    = "app.kubernetes.io/managed-by"
+	managedByOperator = "swarm-operator"
+
+	// secretTypeLabel tells the kinds of secrets the operator creates apart
+	secretTypeLabel       = "swarm.claudeflow.io/type"
+	githubTokenSecretType = "github-token"
+
+	// releasedAtAnnotation records when the garbage collector first found
+	// the task of a resource gone, or finished without a completion time.
+	// Retention counts from it.
+	releasedAtAnnotation = "swarm.claudeflow.io/released-at"
+
+	gcKindVolume = "pvc"
+	gcKindSecret = "secret"
+
+	defaultGCInterval = 10 * time.Minute
+)
+
+// taskResourceLabels labels the resources created for a task, so the
+// garbage collector can find them and tell whether their task is gone
+func taskResourceLabels(task *swarmv1alpha1.SwarmTask) map[string]string {
+	return map[string]string{
+		"swarm.claudeflow.io/task":    task.Name,
+		"swarm.claudeflow.io/cluster": task.Spec.SwarmCluster,
+		taskNamespaceLabel:            task.Namespace,
+		managedByLabel:                managedByOperator,
+	}
+}
+
+// GCConfig is the retention of task resources
+type GCConfig struct {
+	// KeepCompletedStorageFor is how long task volumes are kept after their
+	// task was deleted. Zero keeps them.
+	KeepCompletedStorageFor time.Duration
+
+	// KeepTokenSecretsFor is how long GitHub token secrets are kept after
+	// their task finished or was deleted. Zero keeps them.
+	KeepTokenSecretsFor time.Duration
+
+	// DryRun logs and counts what would be deleted without deleting it.
+	// Released resources are still annotated, so their retention runs.
+	DryRun bool
+}
+
+// GarbageCollector deletes the task volumes and GitHub token secrets the
+// operator created once their task has finished or is gone for longer than
+// the retention. Volumes retained by their reclaim policy and the secrets
+// of tasks deleted while the operator was down otherwise stay forever.
+type GarbageCollector struct {
+	client.Client
+
+	// Reader lists the candidates. The manager's API reader keeps every
+	// secret of the cluster out of the cache. Defaults to Client.
+	Reader client.Reader
+
+	MetricsRecorder *metrics.MetricsRecorder
+
+	// Retention applies unless Config is set
+	Retention GCConfig
+	Config    *operatorconfig.Store
+
+	// Interval between sweeps
+	Interval time.Duration
+}
+
+// NeedLeaderElection lets only the leader delete
+func (g *GarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sweeps on every interval until the context is cancelled
+func (g *GarbageCollector) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("garbage-collector")
+	interval := g.Interval
+	if interval == 0 {
+		interval = defaultGCInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := g.Sweep(ctx); err != nil {
+			log.Error(err, "Garbage collection failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// gcConfig returns the retention in effect, hot-reloaded from the
+// SwarmOperatorConfig when the manager follows one
+func (g *GarbageCollector) gcConfig() GCConfig {
+	if g.Config == nil {
+		return g.Retention
+	}
+	settings := g.Config.Get()
+	return GCConfig{
+		KeepCompletedStorageFor: settings.KeepCompletedStorageFor,
+		KeepTokenSecretsFor:     settings.KeepTokenSecretsFor,
+		DryRun:                  settings.GCDryRun,
+	}
+}
+
+// Sweep deletes the task resources past their retention once
+func (g *GarbageCollector) Sweep(ctx context.Context) error {
+	config := g.gcConfig()
+	reader := g.Reader
+	if reader == nil {
+		reader = g.Client
+	}
+	now := time.Now()
+
+	if config.KeepCompletedStorageFor > 0 {
+		claims := &corev1.PersistentVolumeClaimList{}
+		if err := reader.List(ctx, claims, client.MatchingLabels{managedByLabel: managedByOperator},
+			client.HasLabels{"swarm.claudeflow.io/task"}); err != nil {
+			return err
+		}
+		candidates := make([]client.Object, 0, len(claims.Items))
+		for i := range claims.Items {
+			candidates = append(candidates, &claims.Items[i])
+		}
+		if err := g.collect(ctx, gcKindVolume, candidates, config.KeepCompletedStorageFor, config.DryRun, now); err != nil {
+			return err
+		}
+	}
+
+	if config.KeepTokenSecretsFor > 0 {
+		secrets := &corev1.SecretList{}
+		if err := reader.List(ctx, secrets, client.MatchingLabels{managedByLabel: managedByOperator, secretTypeLabel: githubTokenSecretType},
+			client.HasLabels{"swarm.claudeflow.io/task"}); err != nil {
+			return err
+		}
+		candidates := make([]client.Object, 0, len(secrets.Items))
+		for i := range secrets.Items {
+			candidates = append(candidates, &secrets.Items[i])
+		}
+		if err := g.collect(ctx, gcKindSecret, candidates, config.KeepTokenSecretsFor, config.DryRun, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect deletes the candidates whose task has been finished or gone for
+// the retention. A dry run reports them instead.
+func (g *GarbageCollector) collect(ctx context.Context, kind string, candidates []client.Object, retention time.Duration, dryRun bool, now time.Time) error {
+	log := log.FromContext(ctx).WithName("garbage-collector")
+	count, total := 0, int64(0)
+	for _, obj := range candidates {
+		if obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		released, err := g.releaseTime(ctx, obj, now)
+		if err != nil {
+			return err
+		}
+		if released.IsZero() || now.Sub(released) < retention {
+			continue
+		}
+
+		bytes := storageBytes(obj)
+		count++
+		total += bytes
+		if dryRun {
+			log.Info("Dry run, would garbage collect", "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName(), "released", released)
+			continue
+		}
+		log.Info("Garbage collecting", "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName(), "released", released)
+		if err := g.Delete(ctx, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if g.MetricsRecorder != nil {
+			g.MetricsRecorder.RecordGarbageCollected(kind, bytes)
+		}
+	}
+	if dryRun && g.MetricsRecorder != nil {
+		g.MetricsRecorder.RecordGarbageCollectionDryRun(kind, count, total)
+	}
+	return nil
+}
+
+// storageBytes is the capacity a claim holds, or 0 for other resources
+func storageBytes(obj client.Object) int64 {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return 0
+	}
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok {
+		capacity = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	}
+	return capacity.Value()
+}
+
+// releaseTime returns when the task of the resource finished, or when it
+// was first found gone. It is zero while the task is unfinished, and for
+// volumes while the task exists at all: its reclaim policy decides what
+// happens to them until the task is deleted.
+func (g *GarbageCollector) releaseTime(ctx context.Context, obj client.Object, now time.Time) (time.Time, error) {
+	labels := obj.GetLabels()
+	namespace := labels[taskNamespaceLabel]
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+
+	task := &swarmv1alpha1.SwarmTask{}
+	err := g.Get(ctx, types.NamespacedName{Name: labels["swarm.claudeflow.io/task"], Namespace: namespace}, task)
+	switch {
+	case errors.IsNotFound(err):
+		return g.markReleased(ctx, obj, now)
+	case err != nil:
+		return time.Time{}, err
+	case !taskFinished(task):
+		// The task was requeued or recreated under the same name
+		return time.Time{}, g.clearReleased(ctx, obj)
+	case isClaim(obj):
+		return time.Time{}, g.clearReleased(ctx, obj)
+	case task.Status.CompletionTime == nil:
+		return g.markReleased(ctx, obj, now)
+	}
+	return task.Status.CompletionTime.Time, nil
+}
+
+func isClaim(obj client.Object) bool {
+	_, ok := obj.(*corev1.PersistentVolumeClaim)
+	return ok
+}
+
+// markReleased returns the release time recorded on the resource, recording
+// now on first sight
+func (g *GarbageCollector) markReleased(ctx context.Context, obj client.Object, now time.Time) (time.Time, error) {
+	if released, err := time.Parse(time.RFC3339, obj.GetAnnotations()[releasedAtAnnotation]); err == nil {
+		return released, nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[releasedAtAnnotation] = now.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+	return time.Time{}, client.IgnoreNotFound(g.Patch(ctx, obj, patch))
+}
+
+// clearReleased removes a release time recorded for an earlier task
+func (g *GarbageCollector) clearReleased(ctx context.Context, obj client.Object) error {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[releasedAtAnnotation]; !ok {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	delete(annotations, releasedAtAnnotation)
+	obj.SetAnnotations(annotations)
+	return client.IgnoreNotFound(g.Patch(ctx, obj, patch))
+}
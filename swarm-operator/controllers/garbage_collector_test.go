@@ -0,0 +1,183 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+var _ = Describe("Garbage collector", func() {
+	var (
+		ctx context.Context
+		gc  *GarbageCollector
+	)
+
+	task := func(name, phase string, finished time.Time) *swarmv1alpha1.SwarmTask {
+		t := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+			Status:     swarmv1alpha1.SwarmTaskStatus{Phase: phase},
+		}
+		if !finished.IsZero() {
+			t.Status.CompletionTime = &metav1.Time{Time: finished}
+		}
+		return t
+	}
+
+	claim := func(owner *swarmv1alpha1.SwarmTask, released string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      owner.Name + "-workspace",
+				Namespace: "swarm-tasks",
+				Labels:    taskResourceLabels(owner),
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		}
+		if released != "" {
+			pvc.Annotations = map[string]string{releasedAtAnnotation: released}
+		}
+		return pvc
+	}
+
+	tokenSecret := func(owner *swarmv1alpha1.SwarmTask) *corev1.Secret {
+		labels := taskResourceLabels(owner)
+		labels[secretTypeLabel] = githubTokenSecretType
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: owner.Name + "-github-token", Namespace: "swarm-tasks", Labels: labels,
+		}}
+	}
+
+	build := func(retention GCConfig, objects ...client.Object) {
+		gc = &GarbageCollector{
+			Client:          newTestClient(objects...),
+			MetricsRecorder: metrics.NewMetricsRecorder(),
+			Retention:       retention,
+		}
+	}
+
+	exists := func(obj client.Object) bool {
+		err := gc.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, obj)
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("keeps the volumes of tasks that still exist", func() {
+		retained := task("retained", "Completed", time.Now().Add(-48*time.Hour))
+		retained.Status.Volumes = []swarmv1alpha1.TaskVolumeStatus{{Name: "workspace", ClaimName: "retained-workspace", Reclaim: volumeRetained}}
+		running := task("running", "Running", time.Time{})
+		retainedClaim := claim(retained, time.Now().Add(-48*time.Hour).UTC().Format(time.RFC3339))
+		runningClaim := claim(running, "")
+		build(GCConfig{KeepCompletedStorageFor: time.Hour}, retained, running, retainedClaim, runningClaim)
+
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(retainedClaim)).To(BeTrue())
+		Expect(retainedClaim.Annotations).NotTo(HaveKey(releasedAtAnnotation))
+		Expect(exists(runningClaim)).To(BeTrue())
+	})
+
+	It("deletes the token secrets of tasks finished longer than the retention", func() {
+		old := task("old", "Completed", time.Now().Add(-48*time.Hour))
+		recent := task("recent", "Failed", time.Now().Add(-time.Hour))
+		oldSecret, recentSecret := tokenSecret(old), tokenSecret(recent)
+		build(GCConfig{KeepTokenSecretsFor: 24 * time.Hour}, old, recent, oldSecret, recentSecret)
+
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(oldSecret)).To(BeFalse())
+		Expect(exists(recentSecret)).To(BeTrue())
+	})
+
+	It("counts the retention of orphans from when they were first found", func() {
+		gone := task("gone", "Completed", time.Now())
+		fresh := claim(gone, "")
+		build(GCConfig{KeepCompletedStorageFor: time.Hour}, fresh)
+
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(fresh)).To(BeTrue())
+		Expect(fresh.Annotations).To(HaveKey(releasedAtAnnotation))
+
+		fresh.Annotations[releasedAtAnnotation] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+		Expect(gc.Update(ctx, fresh)).To(Succeed())
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(fresh)).To(BeFalse())
+	})
+
+	It("forgets the release of resources whose task came back", func() {
+		requeued := task("requeued", "Pending", time.Time{})
+		pvc := claim(requeued, time.Now().Add(-48*time.Hour).UTC().Format(time.RFC3339))
+		build(GCConfig{KeepCompletedStorageFor: time.Hour}, requeued, pvc)
+
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(pvc)).To(BeTrue())
+		Expect(pvc.Annotations).NotTo(HaveKey(releasedAtAnnotation))
+	})
+
+	It("deletes the token secrets of deleted tasks on their own retention", func() {
+		gone := task("gone", "Completed", time.Now())
+		secret := tokenSecret(gone)
+		secret.Annotations = map[string]string{releasedAtAnnotation: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)}
+		pvc := claim(gone, secret.Annotations[releasedAtAnnotation])
+		build(GCConfig{KeepTokenSecretsFor: time.Hour}, secret, pvc)
+
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(secret)).To(BeFalse())
+		Expect(exists(pvc)).To(BeTrue())
+	})
+
+	It("only reports what it would delete in a dry run", func() {
+		gone := task("gone", "Completed", time.Now())
+		released := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+		pvc, secret := claim(gone, released), tokenSecret(gone)
+		secret.Annotations = map[string]string{releasedAtAnnotation: released}
+		build(GCConfig{KeepCompletedStorageFor: time.Hour, KeepTokenSecretsFor: time.Hour, DryRun: true}, pvc, secret)
+
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(pvc)).To(BeTrue())
+		Expect(exists(secret)).To(BeTrue())
+	})
+
+	It("leaves resources the operator did not create alone", func() {
+		gone := task("gone", "Completed", time.Now())
+		pvc := claim(gone, time.Now().Add(-48*time.Hour).UTC().Format(time.RFC3339))
+		delete(pvc.Labels, managedByLabel)
+		build(GCConfig{KeepCompletedStorageFor: time.Hour}, pvc)
+
+		Expect(gc.Sweep(ctx)).To(Succeed())
+		Expect(exists(pvc)).To(BeTrue())
+	})
+})
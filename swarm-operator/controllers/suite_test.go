@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// testScheme is the scheme served by the fake clients of the specs. Specs
+// that register further kinds build their own with newTestScheme.
+var testScheme = newTestScheme()
+
+func TestControllers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Controllers Suite")
+}
+
+// newTestScheme returns a scheme with the built-in and swarm types
+func newTestScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(swarmv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+// newTestClientBuilder returns a fake client builder over testScheme. Like
+// the API server, the client keeps the status of the swarm resources behind
+// the status subresource.
+func newTestClientBuilder() *fake.ClientBuilder {
+	return fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithStatusSubresource(
+			&swarmv1alpha1.Agent{},
+			&swarmv1alpha1.SwarmChaos{},
+			&swarmv1alpha1.SwarmCluster{},
+			&swarmv1alpha1.SwarmMemory{},
+			&swarmv1alpha1.SwarmMemoryStore{},
+			&swarmv1alpha1.SwarmOperatorConfig{},
+			&swarmv1alpha1.SwarmPreview{},
+			&swarmv1alpha1.SwarmTask{},
+			&swarmv1alpha1.SwarmTaskBatch{},
+			&swarmv1alpha1.SwarmTenant{},
+		)
+}
+
+// newTestClient returns a fake client holding objects
+func newTestClient(objects ...client.Object) client.WithWatch {
+	return newTestClientBuilder().WithObjects(objects...).Build()
+}
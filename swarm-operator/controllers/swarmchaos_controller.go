@@ -0,0 +1,578 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+	"github.com/claude-flow/swarm-operator/pkg/schedule"
+)
+
+const (
+	// chaosAllowedAnnotation opts a cluster into chaos experiments
+	chaosAllowedAnnotation = "swarm.claudeflow.io/allow-chaos"
+
+	// chaosLabel names the SwarmChaos a partition NetworkPolicy belongs to
+	chaosLabel = "swarm.claudeflow.io/chaos"
+
+	chaosInjecting  = "Injecting"
+	chaosRecovering = "Recovering"
+	chaosRecovered  = "Recovered"
+	chaosFailed     = "Failed"
+
+	defaultChaosRecoveryTimeout = 10 * time.Minute
+	defaultChaosFaultDuration   = 5 * time.Minute
+	defaultChaosHistoryLimit    = 10
+	defaultChaosNetemImage      = "nicolaka/netshoot:v0.13"
+
+	// chaosRecoveryPollInterval is how often a recovering cluster is checked
+	chaosRecoveryPollInterval = 10 * time.Second
+
+	// Reasons of the chaos Ready condition
+	ReasonChaosReady           = "ChaosReady"
+	ReasonChaosNotAllowed      = "ChaosNotAllowed"
+	ReasonChaosClusterNotFound = "ClusterNotFound"
+	ReasonChaosInvalidSchedule = "InvalidSchedule"
+)
+
+// SwarmChaosReconciler reconciles a SwarmChaos object. It is only set up
+// when the operator runs with --enable-chaos.
+type SwarmChaosReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// SwarmNamespace is where memory stores without a namespace run
+	SwarmNamespace string
+
+	// Config hot-reloads the swarm namespace, SwarmNamespace applies when
+	// nil
+	Config *operatorconfig.Store
+
+	// MetricsRecorder records reconcile durations, disabled when nil
+	MetricsRecorder *metrics.MetricsRecorder
+}
+
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmchaos,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmchaos/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemorystores,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=pods/ephemeralcontainers,verbs=update;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *SwarmChaosReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmChaos", req.NamespacedName)
+
+	chaos := &swarmv1alpha1.SwarmChaos{}
+	if err := r.Get(ctx, req.NamespacedName, chaos); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// Partition NetworkPolicies are owned by the experiment and latency
+	// containers remove their qdisc themselves
+	if chaos.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	before := chaos.Status.DeepCopy()
+	result, err := r.reconcileChaos(ctx, chaos, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !equality.Semantic.DeepEqual(before, &chaos.Status) {
+		if err := r.Status().Update(ctx, chaos); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// reconcileChaos advances the run in progress or starts the next one when
+// it is due. The caller saves the status.
+func (r *SwarmChaosReconciler) reconcileChaos(ctx context.Context, chaos *swarmv1alpha1.SwarmChaos, now time.Time) (ctrl.Result, error) {
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: chaos.Spec.SwarmCluster, Namespace: chaos.Namespace}, cluster); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.setChaosCondition(chaos, metav1.ConditionFalse, ReasonChaosClusterNotFound,
+			fmt.Sprintf("SwarmCluster %s not found", chaos.Spec.SwarmCluster))
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	run := activeChaosRun(chaos)
+	if cluster.Annotations[chaosAllowedAnnotation] != "true" {
+		r.setChaosCondition(chaos, metav1.ConditionFalse, ReasonChaosNotAllowed,
+			fmt.Sprintf("SwarmCluster %s is not annotated %s=true", cluster.Name, chaosAllowedAnnotation))
+		if run != nil {
+			if err := r.liftFault(ctx, chaos); err != nil {
+				return ctrl.Result{}, err
+			}
+			run.Phase = chaosFailed
+			run.Message = "Aborted, the cluster no longer allows chaos"
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	sched, err := chaosSchedule(chaos)
+	if err != nil {
+		r.setChaosCondition(chaos, metav1.ConditionFalse, ReasonChaosInvalidSchedule, err.Error())
+		return ctrl.Result{}, nil
+	}
+	r.setChaosCondition(chaos, metav1.ConditionTrue, ReasonChaosReady,
+		fmt.Sprintf("Running experiments against SwarmCluster %s", cluster.Name))
+
+	if run != nil {
+		return r.advanceChaosRun(ctx, chaos, cluster, run, now)
+	}
+
+	next, due := nextChaosRun(chaos, sched, now)
+	if next.IsZero() {
+		chaos.Status.NextRunTime = nil
+	} else {
+		chaos.Status.NextRunTime = &metav1.Time{Time: next}
+	}
+	if !due {
+		if next.IsZero() {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	run, err = r.startChaosRun(ctx, chaos, cluster, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	chaos.Status.LastRunTime = &metav1.Time{Time: now}
+	chaos.Status.NextRunTime = nil
+	if sched != nil {
+		if next := sched.Next(now); !next.IsZero() {
+			chaos.Status.NextRunTime = &metav1.Time{Time: next}
+		}
+	}
+	chaos.Status.Runs = append(chaos.Status.Runs, *run)
+	if limit := chaosHistoryLimit(chaos); len(chaos.Status.Runs) > limit {
+		chaos.Status.Runs = chaos.Status.Runs[len(chaos.Status.Runs)-limit:]
+	}
+	if run.Phase == chaosFailed {
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	return ctrl.Result{RequeueAfter: chaosRecoveryPollInterval}, nil
+}
+
+// startChaosRun injects the fault of the experiment
+func (r *SwarmChaosReconciler) startChaosRun(ctx context.Context, chaos *swarmv1alpha1.SwarmChaos, cluster *swarmv1alpha1.SwarmCluster, now time.Time) (*swarmv1alpha1.ChaosRun, error) {
+	log := log.FromContext(ctx)
+	run := &swarmv1alpha1.ChaosRun{StartTime: metav1.NewTime(now), Phase: chaosInjecting}
+
+	pods, err := r.chaosTargetPods(ctx, chaos, cluster)
+	if err != nil {
+		return nil, err
+	}
+	run.ReadyBefore = countReadyPods(pods)
+	if len(pods) == 0 {
+		run.Phase = chaosFailed
+		run.Message = "No pods to inject the fault into"
+		r.Recorder.Event(chaos, corev1.EventTypeWarning, "ChaosSkipped", run.Message)
+		return run, nil
+	}
+
+	var fault string
+	switch {
+	case chaos.Spec.PodKill != nil:
+		targets := pickChaosTargets(pods, chaosKillCount(chaos.Spec.PodKill.Percent, len(pods)))
+		for i := range targets {
+			if err := r.Delete(ctx, &targets[i]); err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			run.Targets = append(run.Targets, targets[i].Name)
+		}
+		// Deleting the pods is the whole fault, recovery starts right away
+		run.Phase = chaosRecovering
+		run.FaultEndTime = &run.StartTime
+		fault = fmt.Sprintf("Killed %d of %d agent pods", len(targets), len(pods))
+
+	case chaos.Spec.MemoryLatency != nil:
+		spec := chaos.Spec.MemoryLatency
+		container := memoryLatencyContainer(spec, now)
+		for i := range pods {
+			pod := &pods[i]
+			pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, container)
+			if err := r.SubResource("ephemeralcontainers").Update(ctx, pod); err != nil {
+				return nil, err
+			}
+			run.Targets = append(run.Targets, pod.Name)
+		}
+		fault = fmt.Sprintf("Delayed the traffic of %d memory store pods by %s", len(pods), spec.Latency)
+
+	case chaos.Spec.HiveMindPartition != nil:
+		targets := partitionTargets(pods, chaos.Spec.HiveMindPartition.Replicas)
+		for _, pod := range targets {
+			run.Targets = append(run.Targets, pod.Name)
+		}
+		if err := r.partitionPods(ctx, chaos, cluster.Namespace, run.Targets); err != nil {
+			return nil, err
+		}
+		fault = fmt.Sprintf("Partitioned hive-mind replicas %s", strings.Join(run.Targets, ", "))
+	}
+
+	log.Info("Injected chaos", "cluster", cluster.Name, "fault", fault)
+	r.Recorder.Event(chaos, corev1.EventTypeNormal, "ChaosInjected", fault)
+	r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "ChaosInjected", "SwarmChaos %s: %s", chaos.Name, fault)
+	return run, nil
+}
+
+// advanceChaosRun lifts the fault once its duration is up and records when
+// the cluster is Healthy again
+func (r *SwarmChaosReconciler) advanceChaosRun(ctx context.Context, chaos *swarmv1alpha1.SwarmChaos, cluster *swarmv1alpha1.SwarmCluster, run *swarmv1alpha1.ChaosRun, now time.Time) (ctrl.Result, error) {
+	if run.Phase == chaosInjecting {
+		end := run.StartTime.Add(chaosFaultDuration(chaos))
+		if now.Before(end) {
+			return ctrl.Result{RequeueAfter: end.Sub(now)}, nil
+		}
+		if err := r.liftFault(ctx, chaos); err != nil {
+			return ctrl.Result{}, err
+		}
+		run.Phase = chaosRecovering
+		run.FaultEndTime = &metav1.Time{Time: now}
+		r.Recorder.Event(chaos, corev1.EventTypeNormal, "ChaosLifted", "Fault lifted, waiting for the cluster to recover")
+	}
+
+	recovered, err := r.chaosRecovered(ctx, chaos, cluster, run)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if recovered {
+		recovery := now.Sub(run.FaultEndTime.Time)
+		seconds := int64(recovery.Round(time.Second).Seconds())
+		run.RecoveredTime = &metav1.Time{Time: now}
+		run.RecoverySeconds = &seconds
+		run.Phase = chaosRecovered
+		run.Message = fmt.Sprintf("Cluster recovered in %s", recovery.Round(time.Second))
+		if objective := chaosRecoveryObjective(chaos); objective > 0 && recovery > objective {
+			run.Phase = chaosFailed
+			run.Message = fmt.Sprintf("Cluster recovered in %s, slower than the objective of %s",
+				recovery.Round(time.Second), objective)
+		}
+		eventType := corev1.EventTypeNormal
+		if run.Phase == chaosFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(chaos, eventType, "ChaosRecovered", run.Message)
+		return r.afterChaosRun(chaos, now), nil
+	}
+
+	timeout := parseDurationOrDefault(chaos.Spec.RecoveryTimeout, defaultChaosRecoveryTimeout)
+	if now.Sub(run.FaultEndTime.Time) > timeout {
+		run.Phase = chaosFailed
+		run.Message = fmt.Sprintf("Cluster did not recover within %s", timeout)
+		r.Recorder.Event(chaos, corev1.EventTypeWarning, "ChaosRecoveryTimeout", run.Message)
+		return r.afterChaosRun(chaos, now), nil
+	}
+	return ctrl.Result{RequeueAfter: chaosRecoveryPollInterval}, nil
+}
+
+// afterChaosRun requeues for the next scheduled run
+func (r *SwarmChaosReconciler) afterChaosRun(chaos *swarmv1alpha1.SwarmChaos, now time.Time) ctrl.Result {
+	if chaos.Status.NextRunTime == nil {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: max(chaos.Status.NextRunTime.Sub(now), time.Second)}
+}
+
+// chaosRecovered reports whether the cluster is Healthy again: running,
+// neither Degraded nor Unhealthy, and with as many ready pods in the
+// targeted component as before the fault
+func (r *SwarmChaosReconciler) chaosRecovered(ctx context.Context, chaos *swarmv1alpha1.SwarmChaos, cluster *swarmv1alpha1.SwarmCluster, run *swarmv1alpha1.ChaosRun) (bool, error) {
+	if cluster.Status.Phase != "Running" ||
+		meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeDegraded) ||
+		meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeUnhealthy) {
+		return false, nil
+	}
+	pods, err := r.chaosTargetPods(ctx, chaos, cluster)
+	if err != nil {
+		return false, err
+	}
+	return countReadyPods(pods) >= run.ReadyBefore, nil
+}
+
+// chaosTargetPods returns the live pods of the component the experiment
+// targets
+func (r *SwarmChaosReconciler) chaosTargetPods(ctx context.Context, chaos *swarmv1alpha1.SwarmChaos, cluster *swarmv1alpha1.SwarmCluster) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	switch {
+	case chaos.Spec.PodKill != nil:
+		list := &corev1.PodList{}
+		if err := r.List(ctx, list, client.InNamespace(cluster.Namespace),
+			client.MatchingLabels{"swarm-cluster": cluster.Name}, client.HasLabels{"agent-type"}); err != nil {
+			return nil, err
+		}
+		for _, pod := range list.Items {
+			if len(chaos.Spec.PodKill.AgentTypes) == 0 ||
+				containsAgentType(chaos.Spec.PodKill.AgentTypes, swarmv1alpha1.AgentType(pod.Labels["agent-type"])) {
+				pods = append(pods, pod)
+			}
+		}
+
+	case chaos.Spec.MemoryLatency != nil:
+		stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+		if err := r.List(ctx, stores, client.InNamespace(cluster.Namespace)); err != nil {
+			return nil, err
+		}
+		for _, store := range stores.Items {
+			if store.Spec.SwarmClusterRef != cluster.Name {
+				continue
+			}
+			namespace := store.Spec.Namespace
+			if namespace == "" {
+				namespace, _ = operatorNamespaces(r.Config, r.SwarmNamespace, "")
+			}
+			list := &corev1.PodList{}
+			if err := r.List(ctx, list, client.InNamespace(namespace),
+				client.MatchingLabels{"app": "swarm-memory", "memory-name": store.Name}); err != nil {
+				return nil, err
+			}
+			pods = append(pods, list.Items...)
+		}
+
+	case chaos.Spec.HiveMindPartition != nil:
+		list := &corev1.PodList{}
+		if err := r.List(ctx, list, client.InNamespace(cluster.Namespace),
+			client.MatchingLabels{"swarm-cluster": cluster.Name, "component": "hivemind"}); err != nil {
+			return nil, err
+		}
+		pods = list.Items
+	}
+
+	live := pods[:0]
+	for _, pod := range pods {
+		if pod.GetDeletionTimestamp() == nil {
+			live = append(live, pod)
+		}
+	}
+	sort.Slice(live, func(i, j int) bool { return live[i].Name < live[j].Name })
+	return live, nil
+}
+
+// liftFault removes the partition NetworkPolicy of the experiment. Latency
+// containers remove their qdisc when their duration is up.
+func (r *SwarmChaosReconciler) liftFault(ctx context.Context, chaos *swarmv1alpha1.SwarmChaos) error {
+	if chaos.Spec.HiveMindPartition == nil {
+		return nil
+	}
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: chaosPartitionName(chaos), Namespace: chaos.Namespace}}
+	if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// partitionPods denies all traffic to and from the pods
+func (r *SwarmChaosReconciler) partitionPods(ctx context.Context, chaos *swarmv1alpha1.SwarmChaos, namespace string, pods []string) error {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: chaosPartitionName(chaos), Namespace: namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		policy.Labels = map[string]string{chaosLabel: chaos.Name}
+		policy.Spec = networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "statefulset.kubernetes.io/pod-name",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   pods,
+				}},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		}
+		return controllerutil.SetControllerReference(chaos, policy, r.Scheme)
+	})
+	return err
+}
+
+func (r *SwarmChaosReconciler) setChaosCondition(chaos *swarmv1alpha1.SwarmChaos, status metav1.ConditionStatus, reason, message string) {
+	changed := meta.SetStatusCondition(&chaos.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: chaos.Generation,
+	})
+	if changed && status != metav1.ConditionTrue {
+		r.Recorder.Event(chaos, corev1.EventTypeWarning, reason, message)
+	}
+}
+
+// memoryLatencyContainer adds the latency with a netem qdisc and removes
+// it again after the duration, even if the operator is gone by then
+func memoryLatencyContainer(spec *swarmv1alpha1.MemoryLatencyChaos, now time.Time) corev1.EphemeralContainer {
+	latency := parseDurationOrDefault(spec.Latency, 100*time.Millisecond)
+	duration := parseDurationOrDefault(spec.Duration, defaultChaosFaultDuration)
+	image := spec.Image
+	if image == "" {
+		image = defaultChaosNetemImage
+	}
+	script := fmt.Sprintf("tc qdisc replace dev eth0 root netem delay %dms && sleep %d; tc qdisc del dev eth0 root",
+		latency.Milliseconds(), int64(duration.Seconds()))
+	return corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:    "chaos-latency-" + strconv.FormatInt(now.Unix(), 10),
+			Image:   image,
+			Command: []string{"/bin/sh", "-c", script},
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+			},
+		},
+	}
+}
+
+// activeChaosRun returns the run still injecting or recovering, if any
+func activeChaosRun(chaos *swarmv1alpha1.SwarmChaos) *swarmv1alpha1.ChaosRun {
+	if n := len(chaos.Status.Runs); n > 0 {
+		run := &chaos.Status.Runs[n-1]
+		if run.Phase == chaosInjecting || run.Phase == chaosRecovering {
+			return run
+		}
+	}
+	return nil
+}
+
+// chaosSchedule parses the schedule, nil for a one-off experiment
+func chaosSchedule(chaos *swarmv1alpha1.SwarmChaos) (*schedule.Schedule, error) {
+	if chaos.Spec.Schedule == "" {
+		return nil, nil
+	}
+	sched, err := schedule.Parse(chaos.Spec.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", chaos.Spec.Schedule, err)
+	}
+	return sched, nil
+}
+
+// nextChaosRun returns when the next run starts and whether it is due. A
+// one-off experiment is due until it ran once.
+func nextChaosRun(chaos *swarmv1alpha1.SwarmChaos, sched *schedule.Schedule, now time.Time) (time.Time, bool) {
+	if sched == nil {
+		return time.Time{}, !chaos.Spec.Suspend && len(chaos.Status.Runs) == 0
+	}
+	last := chaos.CreationTimestamp.Time
+	if chaos.Status.LastRunTime != nil {
+		last = chaos.Status.LastRunTime.Time
+	}
+	next := sched.Next(last)
+	return next, !chaos.Spec.Suspend && !next.IsZero() && !now.Before(next)
+}
+
+// chaosKillCount is the number of pods a percentage of total amounts to,
+// rounded up
+func chaosKillCount(percent int32, total int) int {
+	count := (int(percent)*total + 99) / 100
+	return min(max(count, 1), total)
+}
+
+// pickChaosTargets picks count pods at random
+func pickChaosTargets(pods []corev1.Pod, count int) []corev1.Pod {
+	shuffled := append([]corev1.Pod(nil), pods...)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	return shuffled[:min(count, len(shuffled))]
+}
+
+// partitionTargets picks the replicas with the highest ordinals, which
+// the hive-mind rebalances away from first on scale-down too
+func partitionTargets(pods []corev1.Pod, replicas int32) []corev1.Pod {
+	if replicas < 1 {
+		replicas = 1
+	}
+	count := min(int(replicas), len(pods))
+	return pods[len(pods)-count:]
+}
+
+func countReadyPods(pods []corev1.Pod) int32 {
+	ready := int32(0)
+	for i := range pods {
+		if podReady(&pods[i]) {
+			ready++
+		}
+	}
+	return ready
+}
+
+func containsAgentType(types []swarmv1alpha1.AgentType, agentType swarmv1alpha1.AgentType) bool {
+	for _, t := range types {
+		if t == agentType {
+			return true
+		}
+	}
+	return false
+}
+
+func chaosPartitionName(chaos *swarmv1alpha1.SwarmChaos) string {
+	return chaos.Name + "-partition"
+}
+
+func chaosFaultDuration(chaos *swarmv1alpha1.SwarmChaos) time.Duration {
+	switch {
+	case chaos.Spec.MemoryLatency != nil:
+		return parseDurationOrDefault(chaos.Spec.MemoryLatency.Duration, defaultChaosFaultDuration)
+	case chaos.Spec.HiveMindPartition != nil:
+		return parseDurationOrDefault(chaos.Spec.HiveMindPartition.Duration, defaultChaosFaultDuration)
+	}
+	return 0
+}
+
+func chaosRecoveryObjective(chaos *swarmv1alpha1.SwarmChaos) time.Duration {
+	return parseDurationOrDefault(chaos.Spec.RecoveryObjective, 0)
+}
+
+func chaosHistoryLimit(chaos *swarmv1alpha1.SwarmChaos) int {
+	if chaos.Spec.HistoryLimit > 0 {
+		return int(chaos.Spec.HistoryLimit)
+	}
+	return defaultChaosHistoryLimit
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwarmChaosReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swarmv1alpha1.SwarmChaos{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmchaos", r))
+}
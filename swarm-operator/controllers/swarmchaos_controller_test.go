@@ -0,0 +1,190 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/schedule"
+)
+
+var _ = Describe("SwarmChaos Controller", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		chaos      *swarmv1alpha1.SwarmChaos
+		reconciler *SwarmChaosReconciler
+		now        time.Time
+	)
+
+	readyPod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			}},
+		}
+	}
+	agentPod := func(name string) *corev1.Pod {
+		return readyPod(name, map[string]string{"swarm-cluster": "swarm", "agent-type": "coder"})
+	}
+	hiveMindPod := func(name string) *corev1.Pod {
+		return readyPod(name, map[string]string{"swarm-cluster": "swarm", "component": "hivemind"})
+	}
+
+	setup := func(objects ...client.Object) {
+		reconciler = &SwarmChaosReconciler{
+			Client:   newTestClient(append(objects, cluster, chaos)...),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(20),
+		}
+	}
+
+	reconcile := func(at time.Time) *swarmv1alpha1.SwarmChaos {
+		current := &swarmv1alpha1.SwarmChaos{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(chaos), current)).To(Succeed())
+		_, err := reconciler.reconcileChaos(ctx, current, at)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Status().Update(ctx, current)).To(Succeed())
+		return current
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "swarm", Namespace: "default",
+				Annotations: map[string]string{chaosAllowedAnnotation: "true"},
+			},
+			Status: swarmv1alpha1.SwarmClusterStatus{Phase: "Running"},
+		}
+		chaos = &swarmv1alpha1.SwarmChaos{
+			ObjectMeta: metav1.ObjectMeta{Name: "kill", Namespace: "default", UID: "kill-uid"},
+			Spec: swarmv1alpha1.SwarmChaosSpec{
+				SwarmCluster: "swarm",
+				PodKill:      &swarmv1alpha1.PodKillChaos{Percent: 30},
+			},
+		}
+	})
+
+	It("only runs against clusters that allow chaos", func() {
+		delete(cluster.Annotations, chaosAllowedAnnotation)
+		setup(agentPod("coder-0"))
+
+		current := reconcile(now)
+		Expect(current.Status.Runs).To(BeEmpty())
+		condition := meta.FindStatusCondition(current.Status.Conditions, ConditionTypeReady)
+		Expect(condition.Reason).To(Equal(ReasonChaosNotAllowed))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "coder-0", Namespace: "default"}, &corev1.Pod{})).To(Succeed())
+	})
+
+	It("kills agent pods and records how long the cluster took to recover", func() {
+		setup(agentPod("coder-0"), agentPod("coder-1"), agentPod("coder-2"))
+
+		current := reconcile(now)
+		Expect(current.Status.Runs).To(HaveLen(1))
+		run := current.Status.Runs[0]
+		Expect(run.Phase).To(Equal(chaosRecovering))
+		Expect(run.Targets).To(HaveLen(1))
+		Expect(run.ReadyBefore).To(Equal(int32(3)))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: run.Targets[0], Namespace: "default"}, &corev1.Pod{})).NotTo(Succeed())
+
+		// Not recovered while the replacement pod is missing
+		Expect(reconcile(now.Add(20 * time.Second)).Status.Runs[0].Phase).To(Equal(chaosRecovering))
+
+		Expect(reconciler.Create(ctx, agentPod("coder-3"))).To(Succeed())
+		run = reconcile(now.Add(45 * time.Second)).Status.Runs[0]
+		Expect(run.Phase).To(Equal(chaosRecovered))
+		Expect(*run.RecoverySeconds).To(BeNumerically("~", 45, 1))
+
+		// A one-off experiment does not run again
+		Expect(reconcile(now.Add(time.Hour)).Status.Runs).To(HaveLen(1))
+	})
+
+	It("fails runs that recover slower than the objective", func() {
+		chaos.Spec.RecoveryObjective = "30s"
+		setup(agentPod("coder-0"), agentPod("coder-1"))
+		reconcile(now)
+
+		Expect(reconciler.Create(ctx, agentPod("coder-2"))).To(Succeed())
+		run := reconcile(now.Add(time.Minute)).Status.Runs[0]
+		Expect(run.Phase).To(Equal(chaosFailed))
+		Expect(run.Message).To(ContainSubstring("slower than the objective"))
+	})
+
+	It("partitions hive-mind replicas for the duration of the fault", func() {
+		chaos.Spec.PodKill = nil
+		chaos.Spec.HiveMindPartition = &swarmv1alpha1.HiveMindPartitionChaos{Replicas: 1, Duration: "5m"}
+		setup(hiveMindPod("swarm-hivemind-0"), hiveMindPod("swarm-hivemind-1"))
+
+		run := reconcile(now).Status.Runs[0]
+		Expect(run.Phase).To(Equal(chaosInjecting))
+		Expect(run.Targets).To(Equal([]string{"swarm-hivemind-1"}))
+		policy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "kill-partition", Namespace: "default"}, policy)).To(Succeed())
+		Expect(policy.Spec.PodSelector.MatchExpressions[0].Values).To(Equal([]string{"swarm-hivemind-1"}))
+		Expect(policy.Spec.Ingress).To(BeEmpty())
+		Expect(policy.Spec.Egress).To(BeEmpty())
+
+		Expect(reconcile(now.Add(time.Minute)).Status.Runs[0].Phase).To(Equal(chaosInjecting))
+
+		run = reconcile(now.Add(6 * time.Minute)).Status.Runs[0]
+		Expect(run.Phase).To(Equal(chaosRecovered))
+		Expect(run.FaultEndTime).NotTo(BeNil())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "kill-partition", Namespace: "default"}, policy)).NotTo(Succeed())
+	})
+
+	It("runs scheduled experiments when they are due", func() {
+		sched, err := schedule.Parse("0 3 * * *")
+		Expect(err).NotTo(HaveOccurred())
+		lastRun := time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC)
+		chaos.Status.LastRunTime = &metav1.Time{Time: lastRun}
+
+		next, due := nextChaosRun(chaos, sched, lastRun.Add(time.Hour))
+		Expect(next).To(Equal(lastRun.Add(24 * time.Hour)))
+		Expect(due).To(BeFalse())
+
+		_, due = nextChaosRun(chaos, sched, lastRun.Add(24*time.Hour))
+		Expect(due).To(BeTrue())
+
+		chaos.Spec.Suspend = true
+		_, due = nextChaosRun(chaos, sched, lastRun.Add(24*time.Hour))
+		Expect(due).To(BeFalse())
+	})
+
+	It("removes the latency it adds to memory store pods", func() {
+		container := memoryLatencyContainer(&swarmv1alpha1.MemoryLatencyChaos{Latency: "250ms", Duration: "2m"}, now)
+		Expect(container.Image).To(Equal(defaultChaosNetemImage))
+		Expect(container.Command[2]).To(Equal("tc qdisc replace dev eth0 root netem delay 250ms && sleep 120; tc qdisc del dev eth0 root"))
+		Expect(container.SecurityContext.Capabilities.Add).To(ContainElement(corev1.Capability("NET_ADMIN")))
+		Expect(chaosKillCount(30, 3)).To(Equal(1))
+		Expect(chaosKillCount(50, 3)).To(Equal(2))
+	})
+})
@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/schedule"
+)
+
+const (
+	// scheduleOverrideAnnotation holds an RFC 3339 time until which every
+	// agent type runs regardless of its schedule
+	scheduleOverrideAnnotation = "swarm.claudeflow.io/schedule-override-until"
+
+	ReasonActiveHours      = "ActiveHours"
+	ReasonOffHours         = "OffHours"
+	ReasonScheduleOverride = "Override"
+	ReasonInvalidSchedule  = "InvalidSchedule"
+)
+
+// scheduleOverrideUntil returns until when the override annotation keeps
+// every agent type running. Past or malformed times are ignored.
+func scheduleOverrideUntil(cluster *swarmv1alpha1.SwarmCluster, now time.Time) (time.Time, bool) {
+	value := cluster.Annotations[scheduleOverrideAnnotation]
+	if value == "" {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil || !until.After(now) {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// agentScheduleState computes whether agents of a type run at now, why,
+// and when that next changes. Types without a schedule always run and
+// return a nil status. A type with a schedule that fails to parse keeps
+// running rather than going dark on a typo.
+func agentScheduleState(cluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType, now time.Time) *swarmv1alpha1.AgentScheduleStatus {
+	var windows []*schedule.Window
+	scheduled := false
+	for _, s := range cluster.Spec.AgentSchedules {
+		if s.AgentType != agentType {
+			continue
+		}
+		scheduled = true
+		w, err := schedule.NewWindow(s.Schedule, s.Duration.Duration, s.TimeZone)
+		if err != nil {
+			return &swarmv1alpha1.AgentScheduleStatus{AgentType: agentType, Active: true, Reason: ReasonInvalidSchedule}
+		}
+		windows = append(windows, w)
+	}
+	if !scheduled {
+		return nil
+	}
+
+	status := &swarmv1alpha1.AgentScheduleStatus{AgentType: agentType}
+	if until, ok := scheduleOverrideUntil(cluster, now); ok {
+		status.Active = true
+		status.Reason = ReasonScheduleOverride
+		status.NextTransition = &metav1.Time{Time: until}
+		return status
+	}
+
+	// Active hours last until the last open window closes, off hours until
+	// the next window opens
+	var next time.Time
+	for _, w := range windows {
+		if end, open := w.Active(now); open {
+			status.Active = true
+			if end.After(next) {
+				next = end
+			}
+		}
+	}
+	if status.Active {
+		status.Reason = ReasonActiveHours
+	} else {
+		status.Reason = ReasonOffHours
+		for _, w := range windows {
+			if start := w.NextStart(now); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+	if !next.IsZero() {
+		status.NextTransition = &metav1.Time{Time: next}
+	}
+	return status
+}
+
+// agentTypeOffHours reports whether a schedule keeps agents of the type
+// scaled to zero at now
+func agentTypeOffHours(cluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType, now time.Time) bool {
+	status := agentScheduleState(cluster, agentType, now)
+	return status != nil && !status.Active
+}
+
+// scheduledAgentReplicas returns the replicas of a per-agent Deployment.
+// Off hours the agent scales to zero once it finished its tasks.
+func scheduledAgentReplicas(cluster *swarmv1alpha1.SwarmCluster, agent *swarmv1alpha1.Agent, now time.Time) int32 {
+	if agentTypeOffHours(cluster, agent.Spec.Type, now) && len(agent.Status.CurrentTasks) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// scheduledPoolReplicas returns the replicas of an agent pool. Off hours
+// the pool shrinks to the highest slot of an agent still working.
+func scheduledPoolReplicas(cluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType, agents []swarmv1alpha1.Agent, now time.Time) int32 {
+	if !agentTypeOffHours(cluster, agentType, now) {
+		return poolReplicas(agents)
+	}
+	busy := make([]swarmv1alpha1.Agent, 0, len(agents))
+	for _, a := range agents {
+		if len(a.Status.CurrentTasks) > 0 {
+			busy = append(busy, a)
+		}
+	}
+	return poolReplicas(busy)
+}
+
+// reconcileAgentSchedules reports the schedule state of every scheduled
+// agent type in the status and records an event whenever a type is scaled
+// up or down. The agent controller applies the state to the workloads.
+func (r *SwarmClusterReconciler) reconcileAgentSchedules(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	now := time.Now()
+	seen := map[swarmv1alpha1.AgentType]bool{}
+	var statuses []swarmv1alpha1.AgentScheduleStatus
+	for _, s := range cluster.Spec.AgentSchedules {
+		if seen[s.AgentType] {
+			continue
+		}
+		seen[s.AgentType] = true
+		statuses = append(statuses, *agentScheduleState(cluster, s.AgentType, now))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].AgentType < statuses[j].AgentType })
+
+	previous := map[swarmv1alpha1.AgentType]swarmv1alpha1.AgentScheduleStatus{}
+	for _, status := range cluster.Status.AgentSchedules {
+		previous[status.AgentType] = status
+	}
+	for _, status := range statuses {
+		before, ok := previous[status.AgentType]
+		switch {
+		case status.Reason == ReasonInvalidSchedule && before.Reason != ReasonInvalidSchedule:
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, ReasonInvalidSchedule,
+				"Schedule of %s agents is invalid, they keep running", status.AgentType)
+		case ok && before.Active && !status.Active:
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "AgentTypeScaledDown",
+				"%s agents are off hours until %s", status.AgentType, formatTransition(status.NextTransition))
+		case !before.Active && status.Active && status.Reason == ReasonScheduleOverride:
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "AgentTypeScaledUp",
+				"%s agents run until %s by the schedule override", status.AgentType, formatTransition(status.NextTransition))
+		case ok && !before.Active && status.Active:
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "AgentTypeScaledUp",
+				"%s agents are in active hours until %s", status.AgentType, formatTransition(status.NextTransition))
+		}
+	}
+
+	if equality.Semantic.DeepEqual(statuses, cluster.Status.AgentSchedules) {
+		return nil
+	}
+	cluster.Status.AgentSchedules = statuses
+	return r.Status().Update(ctx, cluster)
+}
+
+// formatTransition renders the time of a schedule transition for events
+func formatTransition(t *metav1.Time) string {
+	if t == nil {
+		return "further notice"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
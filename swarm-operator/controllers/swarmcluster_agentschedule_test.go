@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Agent schedules", func() {
+	var cluster *swarmv1alpha1.SwarmCluster
+
+	// Thursday 2026-10-15, analysts work 08:00-18:00 Berlin time on weekdays
+	thursdayNoon := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	thursdayNight := time.Date(2026, 10, 15, 22, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				AgentSchedules: []swarmv1alpha1.AgentSchedule{{
+					AgentType: swarmv1alpha1.AnalystAgent,
+					Schedule:  "0 8 * * 1-5",
+					Duration:  metav1.Duration{Duration: 10 * time.Hour},
+					TimeZone:  "Europe/Berlin",
+				}},
+			},
+		}
+	})
+
+	It("runs scheduled types only within their active hours", func() {
+		status := agentScheduleState(cluster, swarmv1alpha1.AnalystAgent, thursdayNoon)
+		Expect(status.Active).To(BeTrue())
+		Expect(status.Reason).To(Equal(ReasonActiveHours))
+		Expect(status.NextTransition.Time.Equal(time.Date(2026, 10, 15, 16, 0, 0, 0, time.UTC))).To(BeTrue())
+
+		status = agentScheduleState(cluster, swarmv1alpha1.AnalystAgent, thursdayNight)
+		Expect(status.Active).To(BeFalse())
+		Expect(status.Reason).To(Equal(ReasonOffHours))
+		Expect(status.NextTransition.Time.Equal(time.Date(2026, 10, 16, 6, 0, 0, 0, time.UTC))).To(BeTrue())
+
+		Expect(agentScheduleState(cluster, swarmv1alpha1.CoderAgent, thursdayNight)).To(BeNil())
+		Expect(agentTypeOffHours(cluster, swarmv1alpha1.CoderAgent, thursdayNight)).To(BeFalse())
+	})
+
+	It("runs every type until the override expires", func() {
+		cluster.Annotations = map[string]string{scheduleOverrideAnnotation: "2026-10-16T02:00:00Z"}
+		status := agentScheduleState(cluster, swarmv1alpha1.AnalystAgent, thursdayNight)
+		Expect(status.Active).To(BeTrue())
+		Expect(status.Reason).To(Equal(ReasonScheduleOverride))
+
+		Expect(agentTypeOffHours(cluster, swarmv1alpha1.AnalystAgent, thursdayNight.Add(5*time.Hour))).To(BeTrue())
+	})
+
+	It("keeps types with invalid schedules running", func() {
+		cluster.Spec.AgentSchedules[0].Schedule = "business hours"
+		status := agentScheduleState(cluster, swarmv1alpha1.AnalystAgent, thursdayNight)
+		Expect(status.Active).To(BeTrue())
+		Expect(status.Reason).To(Equal(ReasonInvalidSchedule))
+	})
+
+	It("scales idle agents to zero off hours", func() {
+		agent := &swarmv1alpha1.Agent{Spec: swarmv1alpha1.AgentSpec{Type: swarmv1alpha1.AnalystAgent}}
+		Expect(scheduledAgentReplicas(cluster, agent, thursdayNoon)).To(BeEquivalentTo(1))
+		Expect(scheduledAgentReplicas(cluster, agent, thursdayNight)).To(BeEquivalentTo(0))
+
+		agent.Status.CurrentTasks = []swarmv1alpha1.TaskReference{{Name: "report"}}
+		Expect(scheduledAgentReplicas(cluster, agent, thursdayNight)).To(BeEquivalentTo(1))
+	})
+
+	It("shrinks pools to their busiest slot off hours", func() {
+		slot := func(n int32) *int32 { return &n }
+		agents := []swarmv1alpha1.Agent{
+			{Status: swarmv1alpha1.AgentStatus{PoolSlot: slot(0), CurrentTasks: []swarmv1alpha1.TaskReference{{Name: "report"}}}},
+			{Status: swarmv1alpha1.AgentStatus{PoolSlot: slot(1)}},
+			{Status: swarmv1alpha1.AgentStatus{PoolSlot: slot(2)}},
+		}
+		Expect(scheduledPoolReplicas(cluster, swarmv1alpha1.AnalystAgent, agents, thursdayNoon)).To(BeEquivalentTo(3))
+		Expect(scheduledPoolReplicas(cluster, swarmv1alpha1.AnalystAgent, agents, thursdayNight)).To(BeEquivalentTo(1))
+		Expect(scheduledPoolReplicas(cluster, swarmv1alpha1.CoderAgent, agents, thursdayNight)).To(BeEquivalentTo(3))
+	})
+
+	It("reports the schedule state in the status", func() {
+		k8sClient := newTestClient(cluster)
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: recorder}
+
+		// Whatever the current time, the override keeps analysts running
+		cluster.Annotations = map[string]string{scheduleOverrideAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+		Expect(reconciler.reconcileAgentSchedules(context.Background(), cluster)).To(Succeed())
+		Expect(cluster.Status.AgentSchedules).To(HaveLen(1))
+		Expect(cluster.Status.AgentSchedules[0].AgentType).To(Equal(swarmv1alpha1.AnalystAgent))
+		Expect(cluster.Status.AgentSchedules[0].Reason).To(Equal(ReasonScheduleOverride))
+		Expect(recorder.Events).To(Receive(ContainSubstring("schedule override")))
+	})
+})
@@ -0,0 +1,295 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	defaultTargetQueueDepth       = 2
+	defaultScaleDownStabilization = 5 * time.Minute
+
+	// taskLatencyQuantile is the task duration quantile compared against
+	// the target latency
+	taskLatencyQuantile = 0.95
+)
+
+// queueScalingEnabled reports whether agent types scale on their task
+// queues instead of cluster-wide CPU
+func queueScalingEnabled(swarmCluster *swarmv1alpha1.SwarmCluster) bool {
+	autoScaling := swarmCluster.Spec.AutoScaling
+	return autoScaling != nil && autoScaling.Enabled && len(autoScaling.TopologyRatios) > 0
+}
+
+// taskAgentType is the agent type a task waits for. Tasks without a
+// preference run on coders, the default agent type of a swarm.
+func taskAgentType(task *swarmv1alpha1.SwarmTask) swarmv1alpha1.AgentType {
+	return fallbackStatus(task).AgentType
+}
+
+// preferredAgentType is the agent type a task starts on
+func preferredAgentType(task *swarmv1alpha1.SwarmTask) swarmv1alpha1.AgentType {
+	if len(task.Spec.PreferredAgentTypes) > 0 {
+		return task.Spec.PreferredAgentTypes[0]
+	}
+	return swarmv1alpha1.CoderAgent
+}
+
+// agentTypeDemand is the load on one agent type
+type agentTypeDemand struct {
+	agentType  swarmv1alpha1.AgentType
+	agents     int
+	queueDepth int
+	// p95 task duration in seconds, valid if hasLatency
+	p95        float64
+	hasLatency bool
+}
+
+// desiredAgentCounts sizes every agent type to its queue, adds an agent to
+// types with queued work whose p95 latency exceeds the target and keeps the
+// result within the per-type bounds and the cluster's maxAgents
+func desiredAgentCounts(swarmCluster *swarmv1alpha1.SwarmCluster, demand []agentTypeDemand, targetLatency time.Duration, stabilizing bool) map[swarmv1alpha1.AgentType]int {
+	autoScaling := swarmCluster.Spec.AutoScaling
+	perAgent := int(autoScaling.TargetQueueDepth)
+	if perAgent <= 0 {
+		perAgent = defaultTargetQueueDepth
+	}
+
+	desired := make(map[swarmv1alpha1.AgentType]int, len(demand))
+	minimums := make(map[swarmv1alpha1.AgentType]int, len(demand))
+	total := 0
+	for _, d := range demand {
+		bounds := autoScaling.TopologyRatios[string(d.agentType)]
+		minAgents := int(bounds.MinAgents)
+		maxAgents := int(bounds.MaxAgents)
+		if maxAgents <= 0 {
+			maxAgents = int(swarmCluster.Spec.MaxAgents)
+		}
+
+		target := (d.queueDepth + perAgent - 1) / perAgent
+		if targetLatency > 0 && d.hasLatency && d.queueDepth > 0 && d.p95 > targetLatency.Seconds() && target <= d.agents {
+			target = d.agents + 1
+		}
+		// Hold on to agents until the last scaling event has settled
+		if stabilizing && target < d.agents {
+			target = d.agents
+		}
+		if target < minAgents {
+			target = minAgents
+		}
+		if target > maxAgents {
+			target = maxAgents
+		}
+
+		desired[d.agentType] = target
+		minimums[d.agentType] = minAgents
+		total += target
+	}
+
+	// Trim the types with the most headroom above their minimum until the
+	// swarm fits into maxAgents
+	types := make([]swarmv1alpha1.AgentType, 0, len(desired))
+	for agentType := range desired {
+		types = append(types, agentType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	for total > int(swarmCluster.Spec.MaxAgents) {
+		var trim swarmv1alpha1.AgentType
+		headroom := 0
+		for _, agentType := range types {
+			if h := desired[agentType] - minimums[agentType]; h > headroom {
+				trim, headroom = agentType, h
+			}
+		}
+		if headroom == 0 {
+			break
+		}
+		desired[trim]--
+		total--
+	}
+	return desired
+}
+
+// evaluateQueueScaling computes the desired agents per type from pending
+// SwarmTasks and recent task latency, records them in the status and
+// reports whether any type needs to scale
+func (r *SwarmClusterReconciler) evaluateQueueScaling(ctx context.Context, swarmCluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent) (bool, error) {
+	log := log.FromContext(ctx)
+	autoScaling := swarmCluster.Spec.AutoScaling
+
+	taskList := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, taskList, client.InNamespace(swarmCluster.Namespace)); err != nil {
+		return false, err
+	}
+	queueDepth := map[swarmv1alpha1.AgentType]int{}
+	for i := range taskList.Items {
+		task := &taskList.Items[i]
+		if task.Spec.SwarmCluster != swarmCluster.Name {
+			continue
+		}
+		if task.Status.Phase == "" || task.Status.Phase == "Pending" {
+			queueDepth[taskAgentType(task)]++
+		}
+	}
+
+	agentCounts := map[swarmv1alpha1.AgentType]int{}
+	for _, agent := range agents {
+		if agent.GetDeletionTimestamp() == nil && agent.Status.Phase != "Failed" && agent.Status.Phase != "Terminating" {
+			agentCounts[agent.Spec.Type]++
+		}
+	}
+
+	demand := make([]agentTypeDemand, 0, len(autoScaling.TopologyRatios))
+	for name := range autoScaling.TopologyRatios {
+		agentType := swarmv1alpha1.AgentType(name)
+		d := agentTypeDemand{
+			agentType:  agentType,
+			agents:     agentCounts[agentType],
+			queueDepth: queueDepth[agentType],
+		}
+		if r.MetricsRecorder != nil {
+			d.p95, d.hasLatency = r.MetricsRecorder.TaskLatencyQuantile(swarmCluster.Namespace, swarmCluster.Name, name, taskLatencyQuantile)
+		}
+		demand = append(demand, d)
+	}
+	sort.Slice(demand, func(i, j int) bool { return demand[i].agentType < demand[j].agentType })
+
+	targetLatency := parseDurationOrDefault(autoScaling.TargetTaskLatency, 0)
+	stabilization := parseDurationOrDefault(autoScaling.ScaleDownStabilization, defaultScaleDownStabilization)
+	stabilizing := swarmCluster.Status.LastScaleTime != nil && time.Since(swarmCluster.Status.LastScaleTime.Time) < stabilization
+	desired := desiredAgentCounts(swarmCluster, demand, targetLatency, stabilizing)
+
+	// Agents the namespace quota or the nodes have no room for would stay
+	// Pending, so the desired counts are capped to the headroom
+	current := make(map[swarmv1alpha1.AgentType]int, len(demand))
+	for _, d := range demand {
+		current[d.agentType] = d.agents
+	}
+	limits, err := r.capScaleUp(ctx, swarmCluster, current, desired)
+	if err != nil {
+		return false, err
+	}
+	r.setScalingLimited(swarmCluster, limits)
+	limitedBy := make(map[swarmv1alpha1.AgentType]string, len(limits))
+	for _, limit := range limits {
+		limitedBy[limit.agentType] = limit.limitedBy
+	}
+
+	scale := false
+	target := 0
+	statuses := make([]swarmv1alpha1.AgentTypeScalingStatus, 0, len(demand))
+	for _, d := range demand {
+		status := swarmv1alpha1.AgentTypeScalingStatus{
+			Type:          d.agentType,
+			Agents:        int32(d.agents),
+			DesiredAgents: int32(desired[d.agentType]),
+			QueueDepth:    int32(d.queueDepth),
+			LimitedBy:     limitedBy[d.agentType],
+		}
+		if d.hasLatency {
+			status.P95Latency = time.Duration(d.p95 * float64(time.Second)).Round(time.Second).String()
+		}
+		statuses = append(statuses, status)
+
+		if desired[d.agentType] != d.agents {
+			scale = true
+			log.Info("Agent type needs scaling", "type", d.agentType, "current", d.agents,
+				"desired", desired[d.agentType], "queueDepth", d.queueDepth, "p95", status.P95Latency)
+		}
+		target += desired[d.agentType]
+		if r.MetricsRecorder != nil {
+			r.MetricsRecorder.RecordAgentTypeScaling(swarmCluster.Namespace, swarmCluster.Name, string(d.agentType),
+				d.queueDepth, d.p95, desired[d.agentType])
+		}
+	}
+	swarmCluster.Status.AgentTypeScaling = statuses
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.RecordAutoscalingTarget(swarmCluster.Namespace, swarmCluster.Name, target)
+	}
+	return scale, nil
+}
+
+// scaleAgentTypes creates and removes agents until every agent type matches
+// the desired count recorded by evaluateQueueScaling. It returns the
+// resulting number of agents.
+func (r *SwarmClusterReconciler) scaleAgentTypes(ctx context.Context, swarmCluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent) (int, error) {
+	log := log.FromContext(ctx)
+
+	names := make(map[string]bool, len(agents))
+	byType := map[swarmv1alpha1.AgentType][]swarmv1alpha1.Agent{}
+	for _, agent := range agents {
+		names[agent.Name] = true
+		if agent.GetDeletionTimestamp() == nil && agent.Status.Phase != "Failed" && agent.Status.Phase != "Terminating" {
+			byType[agent.Spec.Type] = append(byType[agent.Spec.Type], agent)
+		}
+	}
+
+	total := len(agents)
+	index := 0
+	paused := scaleUpPaused(swarmCluster)
+	now := time.Now()
+	for _, status := range swarmCluster.Status.AgentTypeScaling {
+		current := byType[status.Type]
+		desired := int(status.DesiredAgents)
+		// Agents added off hours would sleep until the active hours anyway
+		if (paused || agentTypeOffHours(swarmCluster, status.Type, now)) && desired > len(current) {
+			desired = len(current)
+		}
+
+		for i := len(current); i < desired; i++ {
+			for names[fmt.Sprintf("%s-%s-%d", swarmCluster.Name, status.Type, index)] {
+				index++
+			}
+			agent := r.constructAgentOfType(swarmCluster, status.Type, index)
+			names[agent.Name] = true
+			if err := controllerutil.SetControllerReference(swarmCluster, agent, r.Scheme); err != nil {
+				return total, err
+			}
+			if err := r.Create(ctx, agent); err != nil {
+				log.Error(err, "Failed to create agent", "agent", agent.Name)
+				return total, err
+			}
+			log.Info("Created agent for scale-up", "agent", agent.Name, "type", status.Type)
+			total++
+		}
+		if desired > len(current) && r.MetricsRecorder != nil {
+			r.MetricsRecorder.RecordAutoscalingEvent(swarmCluster.Namespace, swarmCluster.Name, "up")
+		}
+
+		// Busy agents are drained and removed by a later reconcile once
+		// their tasks finish or the drain times out
+		removed, err := r.drainAgents(ctx, current, len(current)-desired)
+		if err != nil {
+			return total, err
+		}
+		total -= removed
+		if removed > 0 && r.MetricsRecorder != nil {
+			r.MetricsRecorder.RecordAutoscalingEvent(swarmCluster.Namespace, swarmCluster.Name, "down")
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Queue-based autoscaling", func() {
+	var swarmCluster *swarmv1alpha1.SwarmCluster
+
+	BeforeEach(func() {
+		swarmCluster = &swarmv1alpha1.SwarmCluster{
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				MaxAgents: 10,
+				AutoScaling: &swarmv1alpha1.AutoScalingSpec{
+					Enabled:          true,
+					TargetQueueDepth: 2,
+					TopologyRatios: map[string]swarmv1alpha1.AgentTypeScaling{
+						"coder":       {MinAgents: 1, MaxAgents: 6},
+						"coordinator": {MinAgents: 1, MaxAgents: 1},
+						"tester":      {},
+					},
+				},
+			},
+		}
+	})
+
+	It("should size agent types to their queues within their bounds", func() {
+		desired := desiredAgentCounts(swarmCluster, []agentTypeDemand{
+			{agentType: swarmv1alpha1.CoderAgent, agents: 1, queueDepth: 5},
+			{agentType: swarmv1alpha1.CoordinatorAgent, agents: 1, queueDepth: 4},
+			{agentType: swarmv1alpha1.TesterAgent, agents: 2},
+		}, 0, false)
+
+		Expect(desired).To(Equal(map[swarmv1alpha1.AgentType]int{
+			swarmv1alpha1.CoderAgent:       3,
+			swarmv1alpha1.CoordinatorAgent: 1,
+			swarmv1alpha1.TesterAgent:      0,
+		}))
+	})
+
+	It("should add an agent when p95 latency exceeds the target", func() {
+		demand := []agentTypeDemand{
+			{agentType: swarmv1alpha1.CoderAgent, agents: 2, queueDepth: 1, p95: 900, hasLatency: true},
+		}
+		Expect(desiredAgentCounts(swarmCluster, demand, 10*time.Minute, false)).
+			To(HaveKeyWithValue(swarmv1alpha1.CoderAgent, 3))
+
+		demand[0].queueDepth = 0
+		Expect(desiredAgentCounts(swarmCluster, demand, 10*time.Minute, false)).
+			To(HaveKeyWithValue(swarmv1alpha1.CoderAgent, 1))
+	})
+
+	It("should not scale down while stabilizing", func() {
+		desired := desiredAgentCounts(swarmCluster, []agentTypeDemand{
+			{agentType: swarmv1alpha1.TesterAgent, agents: 3},
+		}, 0, true)
+		Expect(desired).To(HaveKeyWithValue(swarmv1alpha1.TesterAgent, 3))
+	})
+
+	It("should trim the largest types to fit maxAgents", func() {
+		swarmCluster.Spec.MaxAgents = 5
+		desired := desiredAgentCounts(swarmCluster, []agentTypeDemand{
+			{agentType: swarmv1alpha1.CoderAgent, queueDepth: 12},
+			{agentType: swarmv1alpha1.CoordinatorAgent},
+			{agentType: swarmv1alpha1.TesterAgent, queueDepth: 4},
+		}, 0, false)
+
+		Expect(desired).To(Equal(map[swarmv1alpha1.AgentType]int{
+			swarmv1alpha1.CoderAgent:       2,
+			swarmv1alpha1.CoordinatorAgent: 1,
+			swarmv1alpha1.TesterAgent:      2,
+		}))
+	})
+
+	It("should queue untyped tasks for coders", func() {
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(taskAgentType(task)).To(Equal(swarmv1alpha1.CoderAgent))
+		task.Spec.PreferredAgentTypes = []swarmv1alpha1.AgentType{swarmv1alpha1.TesterAgent}
+		Expect(taskAgentType(task)).To(Equal(swarmv1alpha1.TesterAgent))
+	})
+})
@@ -19,8 +19,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -31,9 +33,14 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/notify"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
 	"github.com/claude-flow/swarm-operator/pkg/topology"
 )
 
@@ -60,6 +67,17 @@ type SwarmClusterReconciler struct {
 	Recorder          record.EventRecorder
 	SwarmNamespace    string
 	HiveMindNamespace string
+	Notifier          *notify.Notifier
+	MetricsRecorder   *metrics.MetricsRecorder
+	// Config hot-reloads the default namespaces. When nil SwarmNamespace
+	// and HiveMindNamespace apply.
+	Config *operatorconfig.Store
+	// OperatorNamespace is where the operator's own ServiceMonitor is
+	// created. When empty its metrics are not monitored.
+	OperatorNamespace string
+	// HTTPClient scrapes the sync lag of hive-mind replicas, defaults to a
+	// 2s timeout client
+	HTTPClient *http.Client
 }
 
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters,verbs=get;list;watch;create;update;patch;delete
@@ -67,10 +85,27 @@ type SwarmClusterReconciler struct {
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=agents,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=agents/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmprofiles,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors;servicemonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=resourcequotas;limitranges,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims;persistentvolumes,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch;create
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *SwarmClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmCluster", req.NamespacedName)
 	log := log.FromContext(ctx)
 
 	// Fetch the SwarmCluster instance
@@ -115,6 +150,125 @@ func (r *SwarmClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 
+	// Expand the profile and defaults into the spec used below
+	if err := resolveSwarmProfile(ctx, r, swarmCluster); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.Info("SwarmProfile not found", "profile", swarmCluster.Spec.Profile)
+		r.Recorder.Eventf(swarmCluster, corev1.EventTypeWarning, ReasonProfileNotFound,
+			"SwarmProfile %s not found", swarmCluster.Spec.Profile)
+		meta.SetStatusCondition(&swarmCluster.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeProgressing,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonProfileNotFound,
+			Message:            fmt.Sprintf("SwarmProfile %s not found", swarmCluster.Spec.Profile),
+			ObservedGeneration: swarmCluster.Generation,
+		})
+		if err := r.Status().Update(ctx, swarmCluster); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// Hold the cluster to the namespaces, registries and quota of its tenant
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.SetClusterTenant(swarmCluster.Namespace, swarmCluster.Name, swarmCluster.Labels[tenantLabel])
+	}
+	admitted, err := r.admitTenantCluster(ctx, swarmCluster)
+	if err != nil {
+		log.Error(err, "Failed to check tenant admission")
+		return ctrl.Result{}, err
+	}
+	if !admitted {
+		return ctrl.Result{RequeueAfter: tenantRequeueInterval}, nil
+	}
+
+	// In simulation mode report what would change instead of changing it
+	if simulationRequested(swarmCluster) {
+		return r.reconcileSimulation(ctx, swarmCluster)
+	}
+	if swarmCluster.Status.Simulation != nil {
+		swarmCluster.Status.Simulation = nil
+		if err := r.Status().Update(ctx, swarmCluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Provision the swarm and hive-mind namespaces before placing anything
+	// in them
+	if err := r.reconcileNamespaces(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile namespaces")
+		r.Recorder.Event(swarmCluster, corev1.EventTypeWarning, "NamespaceProvisioningFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	// Provision the shared workspace before agents and tasks mount it
+	if err := r.reconcileSharedWorkspace(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile shared workspace")
+		return ctrl.Result{}, err
+	}
+
+	// Issue and rotate the mTLS certificates before anything uses them
+	if err := r.reconcileTLS(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile TLS certificates")
+		return ctrl.Result{}, err
+	}
+
+	// Report whether a maintenance window freezes the swarm
+	if err := r.reconcileMaintenance(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile maintenance windows")
+		return ctrl.Result{}, err
+	}
+
+	// Report which agent types are within their active hours
+	if err := r.reconcileAgentSchedules(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile agent schedules")
+		return ctrl.Result{}, err
+	}
+
+	// Advance or pause the executor image rollout
+	if err := r.reconcileExecutorRollout(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile executor image rollout")
+		return ctrl.Result{}, err
+	}
+
+	// Run the hive-mind sync service and rebalance its partitions
+	if err := r.reconcileHiveMind(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile hive-mind")
+		return ctrl.Result{}, err
+	}
+
+	// Run the message bus and grant agents their topology peers
+	if err := r.reconcileMessageBus(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile message bus")
+		return ctrl.Result{}, err
+	}
+
+	// Accept the registrations of agents launched outside the cluster
+	if err := r.reconcileRegistration(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile agent registration")
+		return ctrl.Result{}, err
+	}
+
+	// Repair task references that drifted when agents died mid-task
+	if err := r.reconcileTaskReferences(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile task references")
+		return ctrl.Result{}, err
+	}
+
+	// Publish the task SLOs and install their burn-rate alerts
+	if err := r.reconcileSLOs(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile SLOs")
+		return ctrl.Result{}, err
+	}
+
+	// Generate the monitors and Grafana dashboards
+	if err := r.reconcileDashboards(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to reconcile dashboards")
+		return ctrl.Result{}, err
+	}
+
 	// Initialize status if needed
 	if swarmCluster.Status.Phase == "" {
 		swarmCluster.Status.Phase = "Pending"
@@ -317,6 +471,35 @@ func (r *SwarmClusterReconciler) handleRunningPhase(ctx context.Context, swarmCl
 	// Check if we need to scale
 	if swarmCluster.Spec.AutoScaling != nil && swarmCluster.Spec.AutoScaling.Enabled {
 		shouldScale, scaleDirection := r.evaluateScaling(swarmCluster, agentList.Items)
+		if queueScalingEnabled(swarmCluster) {
+			var err error
+			if shouldScale, err = r.evaluateQueueScaling(ctx, swarmCluster, agentList.Items); err != nil {
+				log.Error(err, "Failed to evaluate queue-based scaling")
+				return ctrl.Result{}, err
+			}
+			scaleDirection = "agent types to their task queues"
+		}
+		// Maintenance windows pause scaling up
+		if scaleDirection == "up" && scaleUpPaused(swarmCluster) {
+			shouldScale = false
+		}
+		// The queue-based evaluator caps every agent type itself, a CPU
+		// scale-up needs room for the next agent
+		if !queueScalingEnabled(swarmCluster) {
+			var limits []scalingLimit
+			if scaleDirection == "up" && shouldScale {
+				agentType := r.selectAgentType(swarmCluster, len(agentList.Items))
+				var err error
+				limits, err = r.capScaleUp(ctx, swarmCluster,
+					map[swarmv1alpha1.AgentType]int{agentType: 0}, map[swarmv1alpha1.AgentType]int{agentType: 1})
+				if err != nil {
+					log.Error(err, "Failed to check the scaling headroom")
+					return ctrl.Result{}, err
+				}
+				shouldScale = len(limits) == 0
+			}
+			r.setScalingLimited(swarmCluster, limits)
+		}
 		if shouldScale {
 			swarmCluster.Status.Phase = "Scaling"
 			swarmCluster.Status.LastScaleTime = &metav1.Time{Time: time.Now()}
@@ -340,20 +523,22 @@ func (r *SwarmClusterReconciler) handleRunningPhase(ctx context.Context, swarmCl
 		}
 	}
 
-	// Check health
-	if readyAgents < int(swarmCluster.Spec.MinAgents) {
-		meta.SetStatusCondition(&swarmCluster.Status.Conditions, metav1.Condition{
-			Type:               ConditionTypeDegraded,
-			Status:             metav1.ConditionTrue,
-			Reason:             ReasonInsufficientAgents,
-			Message:            fmt.Sprintf("Only %d/%d agents are ready", readyAgents, swarmCluster.Spec.MinAgents),
-			LastTransitionTime: metav1.Now(),
-		})
-		
-		r.Recorder.Event(swarmCluster, corev1.EventTypeWarning, "Degraded",
-			fmt.Sprintf("Insufficient ready agents: %d/%d", readyAgents, swarmCluster.Spec.MinAgents))
-	} else {
-		meta.RemoveStatusCondition(&swarmCluster.Status.Conditions, ConditionTypeDegraded)
+	// Elect the queen of a centralized swarm and replace a failed one
+	if err := r.reconcileQueen(ctx, swarmCluster, agentList.Items); err != nil {
+		log.Error(err, "Failed to reconcile queen")
+		return ctrl.Result{}, err
+	}
+
+	// Move the agents to a changed topology a batch at a time
+	if err := r.reconcileTopology(ctx, swarmCluster, agentList.Items); err != nil {
+		log.Error(err, "Failed to reconcile topology")
+		return ctrl.Result{}, err
+	}
+
+	// Score the health and set the Degraded and Unhealthy conditions
+	if err := r.reconcileHealth(ctx, swarmCluster, agentList.Items); err != nil {
+		log.Error(err, "Failed to score cluster health")
+		return ctrl.Result{}, err
 	}
 
 	if err := r.Status().Update(ctx, swarmCluster); err != nil {
@@ -380,10 +565,26 @@ func (r *SwarmClusterReconciler) handleScalingPhase(ctx context.Context, swarmCl
 
 	currentCount := len(agentList.Items)
 	targetCount := r.calculateTargetAgentCount(swarmCluster, agentList.Items)
+	if targetCount > currentCount && scaleUpPaused(swarmCluster) {
+		log.Info("Scale-up paused by maintenance window")
+		targetCount = currentCount
+	}
 	
 	log.Info("Scaling swarm", "current", currentCount, "target", targetCount)
 
-	if currentCount < targetCount {
+	if queueScalingEnabled(swarmCluster) {
+		count, err := r.scaleAgentTypes(ctx, swarmCluster, agentList.Items)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		targetCount = count
+	} else if currentCount < targetCount {
+		// Put agents drained earlier back to work before adding new ones
+		if _, err := r.drainAgents(ctx, agentList.Items, 0); err != nil {
+			log.Error(err, "Failed to cancel agent drains")
+			return ctrl.Result{}, err
+		}
+
 		// Scale up
 		for i := currentCount; i < targetCount; i++ {
 			agent := r.constructAgentForSwarmCluster(swarmCluster, i)
@@ -398,25 +599,12 @@ func (r *SwarmClusterReconciler) handleScalingPhase(ctx context.Context, swarmCl
 			}
 			log.Info("Created agent for scale-up", "agent", agent.Name)
 		}
-	} else if currentCount > targetCount {
-		// Scale down - remove agents gracefully
-		agentsToRemove := currentCount - targetCount
-		removed := 0
-		
-		// Sort agents by task count and remove idle ones first
-		for _, agent := range agentList.Items {
-			if removed >= agentsToRemove {
-				break
-			}
-			
-			if agent.Status.Phase == "Ready" && len(agent.Status.CurrentTasks) == 0 {
-				if err := r.Delete(ctx, &agent); err != nil {
-					log.Error(err, "Failed to delete agent", "agent", agent.Name)
-					continue
-				}
-				log.Info("Deleted agent for scale-down", "agent", agent.Name)
-				removed++
-			}
+	} else {
+		// Scale down - drain the surplus agents and remove them once their
+		// tasks are done, idle ones first
+		if _, err := r.drainAgents(ctx, agentList.Items, currentCount-targetCount); err != nil {
+			log.Error(err, "Failed to drain agents")
+			return ctrl.Result{}, err
 		}
 	}
 
@@ -468,7 +656,11 @@ func (r *SwarmClusterReconciler) handleFailedPhase(ctx context.Context, swarmClu
 
 // constructAgentForSwarmCluster creates an Agent resource for the SwarmCluster
 func (r *SwarmClusterReconciler) constructAgentForSwarmCluster(swarmCluster *swarmv1alpha1.SwarmCluster, index int) *swarmv1alpha1.Agent {
-	agentType := r.selectAgentType(swarmCluster, index)
+	return r.constructAgentOfType(swarmCluster, r.selectAgentType(swarmCluster, index), index)
+}
+
+// constructAgentOfType creates an Agent resource of the given type
+func (r *SwarmClusterReconciler) constructAgentOfType(swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType, index int) *swarmv1alpha1.Agent {
 	name := fmt.Sprintf("%s-%s-%d", swarmCluster.Name, agentType, index)
 
 	agent := &swarmv1alpha1.Agent{
@@ -486,7 +678,7 @@ func (r *SwarmClusterReconciler) constructAgentForSwarmCluster(swarmCluster *swa
 			SwarmCluster:     swarmCluster.Name,
 			Capabilities:     swarmCluster.Spec.AgentTemplate.Capabilities,
 			CognitivePattern: r.selectCognitivePattern(swarmCluster, index),
-			Resources:        swarmCluster.Spec.AgentTemplate.Resources,
+			Resources:        agentTypeResources(swarmCluster, agentType),
 		},
 	}
 
@@ -545,7 +737,11 @@ func (r *SwarmClusterReconciler) setupTopology(ctx context.Context, swarmCluster
 	log := log.FromContext(ctx)
 	
 	// Create topology manager
-	topologyManager := topology.NewManager(string(swarmCluster.Spec.Topology))
+	topologyManager, err := topology.ForCluster(swarmCluster)
+	if err != nil {
+		r.Recorder.Event(swarmCluster, corev1.EventTypeWarning, "InvalidTopology", err.Error())
+		return err
+	}
 	
 	// Configure peer connections for each agent
 	peerMap := topologyManager.CalculatePeers(agents)
@@ -662,6 +858,16 @@ func (r *SwarmClusterReconciler) finalizeSwarmCluster(ctx context.Context, swarm
 		}
 	}
 	
+	if err := r.cleanupSharedWorkspace(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to clean up shared workspace")
+		return err
+	}
+
+	if err := r.cleanupNamespaces(ctx, swarmCluster); err != nil {
+		log.Error(err, "Failed to clean up namespaces")
+		return err
+	}
+
 	r.Recorder.Event(swarmCluster, corev1.EventTypeNormal, "Finalized", "SwarmCluster finalization complete")
 	return nil
 }
@@ -689,6 +895,9 @@ func (r *SwarmClusterReconciler) ensureSwarmMemoryStore(ctx context.Context, swa
 			MCPMode:         true,
 		},
 	}
+	if tlsEnabled(swarmCluster) {
+		memoryStore.Spec.TLSSecretName = tlsSecretName(swarmCluster, memoryTLSComponent)
+	}
 	
 	// Apply SQLite-specific configuration if provided
 	if swarmCluster.Spec.Memory.SQLiteConfig != nil {
@@ -729,17 +938,18 @@ func (r *SwarmClusterReconciler) ensureSwarmMemoryStore(ctx context.Context, swa
 func (r *SwarmClusterReconciler) getNamespaceForComponent(cluster *swarmv1alpha1.SwarmCluster, component string) string {
 	// Check if cluster has namespace configuration
 	if cluster.Spec.NamespaceConfig != nil {
+		swarmNamespace, hiveMindNamespace := operatorNamespaces(r.Config, r.SwarmNamespace, r.HiveMindNamespace)
 		switch component {
 		case "hivemind", "consensus":
 			if cluster.Spec.NamespaceConfig.HiveMindNamespace != "" {
 				return cluster.Spec.NamespaceConfig.HiveMindNamespace
 			}
-			return r.HiveMindNamespace
+			return hiveMindNamespace
 		default:
 			if cluster.Spec.NamespaceConfig.SwarmNamespace != "" {
 				return cluster.Spec.NamespaceConfig.SwarmNamespace
 			}
-			return r.SwarmNamespace
+			return swarmNamespace
 		}
 	}
 	
@@ -753,5 +963,9 @@ func (r *SwarmClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&swarmv1alpha1.SwarmCluster{}).
 		Owns(&swarmv1alpha1.Agent{}).
 		Owns(&swarmv1alpha1.SwarmMemoryStore{}).
-		Complete(r)
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Watches(&swarmv1alpha1.SwarmProfile{}, handler.EnqueueRequestsFromMapFunc(r.mapProfileToClusters)).
+		Watches(&swarmv1alpha1.SwarmTenant{}, handler.EnqueueRequestsFromMapFunc(r.mapTenantToClusters)).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmcluster", r))
 }
\ No newline at end of file
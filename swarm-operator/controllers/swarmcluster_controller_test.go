@@ -18,415 +18,296 @@ package controllers
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
-	"github.com/claude-flow/swarm-operator/pkg/metrics"
-	"github.com/claude-flow/swarm-operator/pkg/topology"
 )
 
 var _ = Describe("SwarmCluster Controller", func() {
 	var (
-		ctx            context.Context
-		k8sClient      client.Client
-		reconciler     *SwarmClusterReconciler
-		scheme         *runtime.Scheme
-		recorder       *record.FakeRecorder
-		metricsCollect *metrics.Collector
-		topoManager    *topology.Manager
+		ctx        context.Context
+		k8sClient  client.Client
+		reconciler *SwarmClusterReconciler
+		recorder   *record.FakeRecorder
+		cluster    *swarmv1alpha1.SwarmCluster
+		req        reconcile.Request
+		// createFailures fails that many agent creations before passing
+		// them on
+		createFailures int
 	)
 
+	reconcileTimes := func(times int) {
+		for i := 0; i < times; i++ {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+	getCluster := func() *swarmv1alpha1.SwarmCluster {
+		updated := &swarmv1alpha1.SwarmCluster{}
+		Expect(k8sClient.Get(ctx, req.NamespacedName, updated)).To(Succeed())
+		return updated
+	}
+	listAgents := func() []swarmv1alpha1.Agent {
+		agents := &swarmv1alpha1.AgentList{}
+		Expect(k8sClient.List(ctx, agents, client.InNamespace(cluster.Namespace))).To(Succeed())
+		return agents.Items
+	}
+	// setAgents sets the phase and CPU usage of every agent, with a fresh
+	// heartbeat
+	setAgents := func(phase string, cpu float64) {
+		for _, agent := range listAgents() {
+			agent := agent
+			agent.Status.Phase = phase
+			agent.Status.Metrics.CPUUsage = cpu
+			agent.Status.LastHeartbeat = &metav1.Time{Time: time.Now()}
+			Expect(k8sClient.Status().Update(ctx, &agent)).To(Succeed())
+		}
+	}
+	// start creates the cluster and runs it until its agents are ready
+	start := func() {
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		reconcileTimes(4)
+		setAgents("Ready", 50)
+		reconcileTimes(1)
+		Expect(getCluster().Status.Phase).To(Equal("Running"))
+	}
+	updateSpec := func(update func(spec *swarmv1alpha1.SwarmClusterSpec)) {
+		updated := getCluster()
+		update(&updated.Spec)
+		Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+	}
+	events := func() []string {
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		return events
+	}
+
 	BeforeEach(func() {
 		ctx = context.Background()
-		scheme = runtime.NewScheme()
-		Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
-		Expect(corev1.AddToScheme(scheme)).To(Succeed())
 
-		k8sClient = fake.NewClientBuilder().
-			WithScheme(scheme).
-			WithStatusSubresource(&swarmv1alpha1.SwarmCluster{}, &swarmv1alpha1.Agent{}).
+		createFailures = 0
+		k8sClient = newTestClientBuilder().
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if _, ok := obj.(*swarmv1alpha1.Agent); ok && createFailures > 0 {
+						createFailures--
+						return apierrors.NewServiceUnavailable("etcd leader changed")
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).
 			Build()
-
 		recorder = record.NewFakeRecorder(100)
-		metricsCollect = metrics.NewCollector(k8sClient)
-		topoManager = topology.NewManager(k8sClient)
-
 		reconciler = &SwarmClusterReconciler{
-			Client:          k8sClient,
-			Scheme:          scheme,
-			Recorder:        recorder,
-			MetricsCollector: metricsCollect,
-			TopologyManager: topoManager,
+			Client:   k8sClient,
+			Scheme:   testScheme,
+			Recorder: recorder,
+		}
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Topology:  swarmv1alpha1.MeshTopology,
+				MinAgents: 3,
+				MaxAgents: 5,
+			},
 		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace}}
 	})
 
-	Describe("Reconcile", func() {
-		var (
-			cluster        *swarmv1alpha1.SwarmCluster
-			namespacedName types.NamespacedName
-			req            reconcile.Request
-		)
+	It("ignores a missing cluster", func() {
+		result, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+	})
 
-		BeforeEach(func() {
-			cluster = &swarmv1alpha1.SwarmCluster{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test-cluster",
-					Namespace: "default",
-				},
-				Spec: swarmv1alpha1.SwarmClusterSpec{
-					Topology: swarmv1alpha1.MeshTopology,
-					Size:     3,
-					Strategy: swarmv1alpha1.StrategySpec{
-						Type:               swarmv1alpha1.BalancedStrategy,
-						MaxConcurrentTasks: 5,
-					},
-				},
-			}
+	It("adds its finalizer and creates the minimum agents", func() {
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		reconcileTimes(4)
+
+		updated := getCluster()
+		Expect(controllerutil.ContainsFinalizer(updated, swarmClusterFinalizer)).To(BeTrue())
+		Expect(updated.Status.Phase).To(Equal("Initializing"))
+		Expect(updated.Status.ActiveAgents).To(Equal(int32(3)))
+		Expect(updated.Status.ReadyAgents).To(BeZero())
+
+		agents := listAgents()
+		Expect(agents).To(HaveLen(3))
+		for _, agent := range agents {
+			Expect(agent.Labels).To(HaveKeyWithValue("swarm-cluster", cluster.Name))
+			Expect(agent.OwnerReferences).To(HaveLen(1))
+			Expect(agent.OwnerReferences[0].Name).To(Equal(cluster.Name))
+		}
+	})
 
-			namespacedName = types.NamespacedName{
-				Name:      cluster.Name,
-				Namespace: cluster.Namespace,
+	It("records the initialization and readiness as events", func() {
+		start()
+		Expect(events()).To(ContainElements(
+			"Normal Initializing SwarmCluster initialization started",
+			"Normal Ready SwarmCluster is ready with 3 agents",
+		))
+	})
+
+	It("runs once every agent is ready", func() {
+		start()
+
+		updated := getCluster()
+		Expect(updated.Status.ReadyAgents).To(Equal(int32(3)))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, ConditionTypeProgressing)).To(BeFalse())
+	})
+
+	It("keeps initializing while some agents are not ready", func() {
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		reconcileTimes(4)
+		agents := listAgents()
+		for i := range agents {
+			agents[i].Status.Phase = "Ready"
+			if i == 2 {
+				agents[i].Status.Phase = "Failed"
 			}
+			Expect(k8sClient.Status().Update(ctx, &agents[i])).To(Succeed())
+		}
+		reconcileTimes(1)
 
-			req = reconcile.Request{
-				NamespacedName: namespacedName,
+		updated := getCluster()
+		Expect(updated.Status.Phase).To(Equal("Initializing"))
+		Expect(updated.Status.ReadyAgents).To(Equal(int32(2)))
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady)).To(BeNil())
+	})
+
+	DescribeTable("connects the agents in the topology of the spec",
+		func(topology swarmv1alpha1.SwarmTopology) {
+			cluster.Spec.Topology = topology
+			start()
+
+			updated := getCluster()
+			Expect(updated.Status.TopologyStatus).To(HaveKeyWithValue("configured", "true"))
+			Expect(updated.Status.TopologyStatus).To(HaveKeyWithValue("type", string(topology)))
+			for _, agent := range listAgents() {
+				Expect(agent.Spec.CommunicationEndpoints.Peers).NotTo(BeEmpty(), agent.Name)
 			}
-		})
+		},
+		Entry("mesh", swarmv1alpha1.MeshTopology),
+		Entry("hierarchical", swarmv1alpha1.HierarchicalTopology),
+		Entry("ring", swarmv1alpha1.RingTopology),
+		Entry("star", swarmv1alpha1.StarTopology),
+	)
 
-		Context("Creating a new SwarmCluster", func() {
-			It("should create agents and update status", func() {
-				Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
-
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
-
-				// Check that agents were created
-				var agents swarmv1alpha1.AgentList
-				Expect(k8sClient.List(ctx, &agents, client.InNamespace(cluster.Namespace))).To(Succeed())
-				Expect(agents.Items).To(HaveLen(int(cluster.Spec.Size)))
-
-				// Check agent properties
-				for i, agent := range agents.Items {
-					Expect(agent.Name).To(Equal(fmt.Sprintf("%s-agent-%d", cluster.Name, i)))
-					Expect(agent.Labels).To(HaveKeyWithValue("swarm.claudeflow.io/cluster", cluster.Name))
-					Expect(agent.OwnerReferences).To(HaveLen(1))
-					Expect(agent.OwnerReferences[0].Name).To(Equal(cluster.Name))
-				}
-
-				// Check cluster status
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				Expect(updatedCluster.Status.State).To(Equal(swarmv1alpha1.ClusterPending))
-				Expect(updatedCluster.Status.ReadyAgents).To(Equal(int32(0)))
-			})
-
-			It("should handle different topologies", func() {
-				testCases := []swarmv1alpha1.TopologyType{
-					swarmv1alpha1.MeshTopology,
-					swarmv1alpha1.HierarchicalTopology,
-					swarmv1alpha1.RingTopology,
-					swarmv1alpha1.StarTopology,
-				}
-
-				for _, topology := range testCases {
-					By(fmt.Sprintf("Testing %s topology", topology))
-					
-					testCluster := cluster.DeepCopy()
-					testCluster.Name = fmt.Sprintf("test-cluster-%s", topology)
-					testCluster.Spec.Topology = topology
-					
-					Expect(k8sClient.Create(ctx, testCluster)).To(Succeed())
-					
-					req := reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      testCluster.Name,
-							Namespace: testCluster.Namespace,
-						},
-					}
-					
-					result, err := reconciler.Reconcile(ctx, req)
-					Expect(err).NotTo(HaveOccurred())
-					Expect(result.Requeue).To(BeFalse())
-					
-					// Verify topology was set correctly
-					updatedCluster := &swarmv1alpha1.SwarmCluster{}
-					Expect(k8sClient.Get(ctx, req.NamespacedName, updatedCluster)).To(Succeed())
-					Expect(updatedCluster.Status.Topology).To(Equal(topology))
-				}
-			})
-
-			It("should emit events", func() {
-				Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
-
-				_, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-
-				// Check for expected events
-				Eventually(recorder.Events).Should(Receive(ContainSubstring("CreatingAgents")))
-			})
+	It("moves the agents to a changed topology", func() {
+		start()
+		updateSpec(func(spec *swarmv1alpha1.SwarmClusterSpec) {
+			spec.Topology = swarmv1alpha1.RingTopology
 		})
+		reconcileTimes(3)
 
-		Context("Updating an existing SwarmCluster", func() {
-			BeforeEach(func() {
-				Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
-				// Initial reconciliation
-				_, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-			})
-
-			It("should scale up when size increases", func() {
-				// Update cluster size
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				updatedCluster.Spec.Size = 5
-				Expect(k8sClient.Update(ctx, updatedCluster)).To(Succeed())
-
-				// Reconcile
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
-
-				// Check that new agents were created
-				var agents swarmv1alpha1.AgentList
-				Expect(k8sClient.List(ctx, &agents, client.InNamespace(cluster.Namespace))).To(Succeed())
-				Expect(agents.Items).To(HaveLen(5))
-			})
-
-			It("should scale down when size decreases", func() {
-				// Update cluster size
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				updatedCluster.Spec.Size = 1
-				Expect(k8sClient.Update(ctx, updatedCluster)).To(Succeed())
-
-				// Reconcile
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
-
-				// Check that excess agents were deleted
-				var agents swarmv1alpha1.AgentList
-				Expect(k8sClient.List(ctx, &agents, client.InNamespace(cluster.Namespace))).To(Succeed())
-				Expect(agents.Items).To(HaveLen(1))
-			})
-
-			It("should update agent connections when topology changes", func() {
-				// Change topology
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				updatedCluster.Spec.Topology = swarmv1alpha1.StarTopology
-				Expect(k8sClient.Update(ctx, updatedCluster)).To(Succeed())
-
-				// Reconcile
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
-
-				// Verify topology was updated
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				Expect(updatedCluster.Status.Topology).To(Equal(swarmv1alpha1.StarTopology))
-			})
-		})
+		updated := getCluster()
+		Expect(updated.Status.TopologyMigration).To(BeNil())
+		Expect(updated.Status.TopologyStatus).To(HaveKeyWithValue("type", string(swarmv1alpha1.RingTopology)))
+		for _, agent := range listAgents() {
+			Expect(agent.Spec.CommunicationEndpoints.Peers).To(HaveLen(2), agent.Name)
+		}
+	})
 
-		Context("Agent status updates", func() {
-			BeforeEach(func() {
-				Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
-				// Initial reconciliation
-				_, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-			})
-
-			It("should update cluster status when agents become ready", func() {
-				// Get created agents
-				var agents swarmv1alpha1.AgentList
-				Expect(k8sClient.List(ctx, &agents, client.InNamespace(cluster.Namespace))).To(Succeed())
-
-				// Update agents to ready state
-				for _, agent := range agents.Items {
-					agent.Status.State = swarmv1alpha1.AgentReady
-					Expect(k8sClient.Status().Update(ctx, &agent)).To(Succeed())
-				}
-
-				// Reconcile
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
-
-				// Check cluster status
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				Expect(updatedCluster.Status.ReadyAgents).To(Equal(int32(3)))
-				Expect(updatedCluster.Status.State).To(Equal(swarmv1alpha1.ClusterReady))
-			})
-
-			It("should handle partial agent readiness", func() {
-				// Get created agents
-				var agents swarmv1alpha1.AgentList
-				Expect(k8sClient.List(ctx, &agents, client.InNamespace(cluster.Namespace))).To(Succeed())
-
-				// Update only some agents to ready
-				for i, agent := range agents.Items {
-					if i < 2 {
-						agent.Status.State = swarmv1alpha1.AgentReady
-					} else {
-						agent.Status.State = swarmv1alpha1.AgentError
-					}
-					Expect(k8sClient.Status().Update(ctx, &agent)).To(Succeed())
-				}
-
-				// Reconcile
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
-
-				// Check cluster status
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				Expect(updatedCluster.Status.ReadyAgents).To(Equal(int32(2)))
-				Expect(updatedCluster.Status.State).To(Equal(swarmv1alpha1.ClusterDegraded))
-				Expect(updatedCluster.Status.Health).To(Equal(swarmv1alpha1.DegradedCondition))
-			})
+	Context("with autoscaling", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutoScaling = &swarmv1alpha1.AutoScalingSpec{
+				Enabled:            true,
+				ScaleUpThreshold:   80,
+				ScaleDownThreshold: 20,
+			}
 		})
 
-		Context("Deletion", func() {
-			It("should handle cluster deletion", func() {
-				// Create cluster with finalizer
-				cluster.Finalizers = []string{"swarm.claudeflow.io/finalizer"}
-				Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		It("adds an agent when the agents are busy", func() {
+			start()
+			setAgents("Ready", 95)
+			reconcileTimes(2)
 
-				// Initial reconciliation
-				_, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-
-				// Mark for deletion
-				Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
+			Expect(listAgents()).To(HaveLen(4))
+			updated := getCluster()
+			Expect(updated.Status.Phase).To(Equal("Running"))
+			Expect(updated.Status.LastScaleTime).NotTo(BeNil())
+			Expect(events()).To(ContainElement("Normal ScalingComplete Scaled from 3 to 4 agents"))
+		})
 
-				// Reconcile deletion
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
+		It("removes an idle agent above the minimum", func() {
+			cluster.Spec.MinAgents = 2
+			start()
+			// Start with the minimum and one more
+			Expect(k8sClient.Create(ctx, reconciler.constructAgentForSwarmCluster(getCluster(), 3))).To(Succeed())
+			setAgents("Ready", 5)
+			reconcileTimes(2)
 
-				// Verify cluster is deleted
-				err = k8sClient.Get(ctx, namespacedName, &swarmv1alpha1.SwarmCluster{})
-				Expect(apierrors.IsNotFound(err)).To(BeTrue())
-			})
+			Expect(listAgents()).To(HaveLen(2))
+			Expect(events()).To(ContainElement("Normal ScalingComplete Scaled from 3 to 2 agents"))
 		})
+	})
 
-		Context("Error handling", func() {
-			It("should handle missing cluster", func() {
-				// Reconcile non-existent cluster
-				result, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Requeue).To(BeFalse())
-			})
-
-			It("should requeue on transient errors", func() {
-				// Create a cluster that will trigger errors
-				cluster.Spec.Size = -1 // Invalid size
-				Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
-
-				// Reconcile should handle error gracefully
-				result, err := reconciler.Reconcile(ctx, req)
-				// Depending on implementation, this might error or requeue
-				if err != nil {
-					Expect(result.Requeue).To(BeTrue())
-				}
-			})
-		})
+	Context("health", func() {
+		It("scores a cluster of ready agents healthy", func() {
+			start()
+			reconcileTimes(1)
 
-		Context("Health monitoring", func() {
-			BeforeEach(func() {
-				Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
-				_, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-			})
-
-			It("should update health status based on agent states", func() {
-				// Get agents
-				var agents swarmv1alpha1.AgentList
-				Expect(k8sClient.List(ctx, &agents, client.InNamespace(cluster.Namespace))).To(Succeed())
-
-				// Set all agents to healthy
-				for _, agent := range agents.Items {
-					agent.Status.State = swarmv1alpha1.AgentReady
-					agent.Status.Health = swarmv1alpha1.HealthyCondition
-					Expect(k8sClient.Status().Update(ctx, &agent)).To(Succeed())
-				}
-
-				// Reconcile
-				_, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-
-				// Check health
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				Expect(updatedCluster.Status.Health).To(Equal(swarmv1alpha1.HealthyCondition))
-			})
-
-			It("should detect unhealthy conditions", func() {
-				// Get agents
-				var agents swarmv1alpha1.AgentList
-				Expect(k8sClient.List(ctx, &agents, client.InNamespace(cluster.Namespace))).To(Succeed())
-
-				// Set one agent to error
-				agents.Items[0].Status.State = swarmv1alpha1.AgentError
-				agents.Items[0].Status.Health = swarmv1alpha1.UnhealthyCondition
-				Expect(k8sClient.Status().Update(ctx, &agents.Items[0])).To(Succeed())
-
-				// Reconcile
-				_, err := reconciler.Reconcile(ctx, req)
-				Expect(err).NotTo(HaveOccurred())
-
-				// Check health
-				updatedCluster := &swarmv1alpha1.SwarmCluster{}
-				Expect(k8sClient.Get(ctx, namespacedName, updatedCluster)).To(Succeed())
-				Expect(updatedCluster.Status.Health).To(Equal(swarmv1alpha1.DegradedCondition))
-			})
+			updated := getCluster()
+			Expect(updated.Status.HealthScore).NotTo(BeNil())
+			Expect(*updated.Status.HealthScore).To(Equal(int32(100)))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeDegraded)).To(BeNil())
 		})
-	})
 
-	Describe("SetupWithManager", func() {
-		It("should setup the controller", func() {
-			// This would require a real manager, so we just verify the method exists
-			mgr := &mockManager{}
-			err := reconciler.SetupWithManager(mgr)
-			Expect(err).To(BeNil())
+		It("reports the cluster degraded when agents fail", func() {
+			start()
+			for _, agent := range listAgents()[:2] {
+				agent := agent
+				agent.Status.Phase = "Failed"
+				Expect(k8sClient.Status().Update(ctx, &agent)).To(Succeed())
+			}
+			reconcileTimes(1)
+
+			updated := getCluster()
+			Expect(updated.Status.ReadyAgents).To(Equal(int32(1)))
+			Expect(*updated.Status.HealthScore).To(BeNumerically("<", defaultDegradedThreshold))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeUnhealthy)).To(BeNil())
+			condition := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeDegraded)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(ReasonInsufficientAgents))
+			Expect(events()).To(ContainElement(HavePrefix("Warning Degraded")))
 		})
 	})
-})
-
-// Mock manager for testing
-type mockManager struct {
-	ctrl.Manager
-}
 
-func (m *mockManager) GetScheme() *runtime.Scheme {
-	return runtime.NewScheme()
-}
+	It("retries agents whose creation failed", func() {
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		reconcileTimes(2)
+		createFailures = 1
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(apierrors.IsServiceUnavailable(err)).To(BeTrue())
+		Expect(listAgents()).To(BeEmpty())
 
-func (m *mockManager) GetClient() client.Client {
-	return nil
-}
+		reconcileTimes(1)
+		Expect(listAgents()).To(HaveLen(3))
+	})
 
-func (m *mockManager) GetFieldIndexer() client.FieldIndexer {
-	return nil
-}
+	It("deletes its agents and releases the cluster on deletion", func() {
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		reconcileTimes(4)
+		Expect(listAgents()).To(HaveLen(3))
 
-func (m *mockManager) GetEventRecorderFor(name string) record.EventRecorder {
-	return nil
-}
\ No newline at end of file
+		Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
+		reconcileTimes(1)
+		Expect(apierrors.IsNotFound(k8sClient.Get(ctx, req.NamespacedName, &swarmv1alpha1.SwarmCluster{}))).To(BeTrue())
+		Expect(listAgents()).To(BeEmpty())
+	})
+})
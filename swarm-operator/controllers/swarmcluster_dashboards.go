@@ -0,0 +1,414 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeDashboardsReady reports whether the monitors and
+	// Grafana dashboards of the cluster are installed
+	ConditionTypeDashboardsReady = "DashboardsReady"
+
+	ReasonDashboardsApplied = "DashboardsApplied"
+
+	// operatorMonitorName is the ServiceMonitor of the operator's own
+	// metrics. It is shared by every cluster and lives in the operator
+	// namespace, so no cluster owns it.
+	operatorMonitorName = "swarm-operator"
+
+	// monitorScrapeInterval is how often the generated monitors scrape
+	monitorScrapeInterval = "30s"
+)
+
+var (
+	// podMonitorGVK and serviceMonitorGVK are the Prometheus operator
+	// monitor kinds, used unstructured like PrometheusRule
+	podMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+	// defaultDashboardLabels are discovered by the Grafana dashboard sidecar
+	defaultDashboardLabels = map[string]string{"grafana_dashboard": "1"}
+)
+
+// dashboardsEnabled reports whether the cluster generates monitors and
+// dashboards
+func dashboardsEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.Monitoring != nil && cluster.Spec.Monitoring.DashboardEnabled
+}
+
+// monitoringMetricsPort and monitoringMetricsPath return where agents
+// expose metrics
+func monitoringMetricsPort(monitoring *swarmv1alpha1.MonitoringSpec) int32 {
+	if monitoring.MetricsPort == 0 {
+		return 9090
+	}
+	return monitoring.MetricsPort
+}
+
+func monitoringMetricsPath(monitoring *swarmv1alpha1.MonitoringSpec) string {
+	if monitoring.MetricsPath == "" {
+		return "/metrics"
+	}
+	return monitoring.MetricsPath
+}
+
+// podMonitor describes a PodMonitor the cluster owns
+type podMonitor struct {
+	name      string
+	namespace string
+	selector  map[string]string
+	// requireLabel, when set, must be present on the selected pods
+	requireLabel string
+	endpoint     map[string]interface{}
+}
+
+// clusterPodMonitors returns the PodMonitors of the agents, the hive-mind
+// and the memory service. They live in the cluster namespace and select
+// pods in the namespace of their component.
+func (r *SwarmClusterReconciler) clusterPodMonitors(cluster *swarmv1alpha1.SwarmCluster) []podMonitor {
+	monitoring := cluster.Spec.Monitoring
+	monitors := []podMonitor{
+		{
+			// Agent pods only have the port in their scrape annotations,
+			// so the endpoint targets it by number
+			name:         cluster.Name + "-agents",
+			namespace:    cluster.Namespace,
+			selector:     map[string]string{"swarm-cluster": cluster.Name},
+			requireLabel: "agent-type",
+			endpoint: map[string]interface{}{
+				"targetPort": int64(monitoringMetricsPort(monitoring)),
+				"path":       monitoringMetricsPath(monitoring),
+			},
+		},
+		{
+			name:      cluster.Name + "-memory",
+			namespace: r.getNamespaceForComponent(cluster, "memory"),
+			selector:  map[string]string{"app": "swarm-memory", "memory-name": cluster.Name + "-memory"},
+			endpoint:  map[string]interface{}{"port": "metrics", "path": "/metrics"},
+		},
+	}
+	if hiveMindEnabled(cluster) {
+		monitors = append(monitors, podMonitor{
+			name:      cluster.Name + "-hivemind",
+			namespace: cluster.Namespace,
+			selector:  map[string]string{"swarm-cluster": cluster.Name, "component": "hivemind"},
+			endpoint:  map[string]interface{}{"port": "sync", "path": "/metrics"},
+		})
+	}
+	return monitors
+}
+
+// reconcileDashboards installs the monitors and Grafana dashboards of
+// clusters with dashboards enabled and reports them through the
+// DashboardsReady condition
+func (r *SwarmClusterReconciler) reconcileDashboards(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	if !dashboardsEnabled(cluster) {
+		if meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeDashboardsReady) == nil {
+			return nil
+		}
+		if err := r.deleteDashboards(ctx, cluster); err != nil {
+			return err
+		}
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeDashboardsReady)
+		return r.Status().Update(ctx, cluster)
+	}
+
+	dashboards := clusterDashboards(cluster)
+	for _, name := range sortedKeys(dashboards) {
+		if err := r.applyDashboard(ctx, cluster, name, dashboards[name]); err != nil {
+			return err
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeDashboardsReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonDashboardsApplied,
+		Message:            fmt.Sprintf("%d Grafana dashboards and their monitors are installed", len(dashboards)),
+		ObservedGeneration: cluster.Generation,
+	}
+	err := r.applyMonitors(ctx, cluster)
+	switch {
+	case meta.IsNoMatchError(err):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonPrometheusOperatorMissing
+		condition.Message = "The PodMonitor and ServiceMonitor kinds are not installed, monitors require the Prometheus operator"
+	case err != nil:
+		return err
+	}
+
+	if !meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+		return nil
+	}
+	eventType := corev1.EventTypeNormal
+	if condition.Status == metav1.ConditionFalse {
+		eventType = corev1.EventTypeWarning
+	}
+	r.Recorder.Event(cluster, eventType, condition.Reason, condition.Message)
+	return r.Status().Update(ctx, cluster)
+}
+
+// monitorLabels are the labels of the generated monitors
+func monitorLabels(cluster *swarmv1alpha1.SwarmCluster) map[string]string {
+	labels := map[string]string{"swarm-cluster": cluster.Name}
+	for key, value := range cluster.Spec.Monitoring.MonitorLabels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// applyMonitors keeps the cluster's PodMonitors in line with its
+// components and makes sure the operator's ServiceMonitor exists
+func (r *SwarmClusterReconciler) applyMonitors(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	monitors := r.clusterPodMonitors(cluster)
+	for _, monitor := range monitors {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(podMonitorGVK)
+		obj.SetName(monitor.name)
+		obj.SetNamespace(cluster.Namespace)
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+			obj.SetLabels(monitorLabels(cluster))
+			endpoint := map[string]interface{}{"interval": monitorScrapeInterval}
+			for key, value := range monitor.endpoint {
+				endpoint[key] = value
+			}
+			selector := map[string]interface{}{"matchLabels": stringMap(monitor.selector)}
+			if monitor.requireLabel != "" {
+				selector["matchExpressions"] = []interface{}{
+					map[string]interface{}{"key": monitor.requireLabel, "operator": "Exists"},
+				}
+			}
+			spec := map[string]interface{}{
+				"selector":            selector,
+				"namespaceSelector":   map[string]interface{}{"matchNames": []interface{}{monitor.namespace}},
+				"podMetricsEndpoints": []interface{}{endpoint},
+			}
+			if err := unstructured.SetNestedMap(obj.Object, spec, "spec"); err != nil {
+				return err
+			}
+			return controllerutil.SetControllerReference(cluster, obj, r.Scheme)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The hive-mind monitor goes away when the hive-mind does
+	if !hiveMindEnabled(cluster) {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(podMonitorGVK)
+		obj.SetName(cluster.Name + "-hivemind")
+		obj.SetNamespace(cluster.Namespace)
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if r.OperatorNamespace == "" {
+		return nil
+	}
+	operator := &unstructured.Unstructured{}
+	operator.SetGroupVersionKind(serviceMonitorGVK)
+	err := r.Get(ctx, client.ObjectKey{Name: operatorMonitorName, Namespace: r.OperatorNamespace}, operator)
+	if !errors.IsNotFound(err) {
+		return err
+	}
+	operator.SetName(operatorMonitorName)
+	operator.SetNamespace(r.OperatorNamespace)
+	operator.SetLabels(monitorLabels(cluster))
+	if err := unstructured.SetNestedMap(operator.Object, map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "swarm-operator"}},
+		"endpoints": []interface{}{
+			map[string]interface{}{"port": "metrics", "path": "/metrics", "interval": monitorScrapeInterval},
+		},
+	}, "spec"); err != nil {
+		return err
+	}
+	return r.Create(ctx, operator)
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		out[key] = value
+	}
+	return out
+}
+
+// dashboardConfigMapName names the ConfigMap of one of the cluster's
+// dashboards
+func dashboardConfigMapName(cluster *swarmv1alpha1.SwarmCluster, dashboard string) string {
+	return fmt.Sprintf("%s-dashboard-%s", cluster.Name, dashboard)
+}
+
+// applyDashboard creates or updates the ConfigMap holding a dashboard
+func (r *SwarmClusterReconciler) applyDashboard(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, dashboard, model string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: dashboardConfigMapName(cluster, dashboard), Namespace: cluster.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		labels := map[string]string{"swarm-cluster": cluster.Name, "component": "dashboard"}
+		dashboardLabels := cluster.Spec.Monitoring.DashboardLabels
+		if len(dashboardLabels) == 0 {
+			dashboardLabels = defaultDashboardLabels
+		}
+		for key, value := range dashboardLabels {
+			labels[key] = value
+		}
+		cm.Labels = labels
+		cm.Data = map[string]string{fmt.Sprintf("%s-%s.json", cluster.Name, dashboard): model}
+		return controllerutil.SetControllerReference(cluster, cm, r.Scheme)
+	})
+	return err
+}
+
+// deleteDashboards removes the dashboards and PodMonitors after dashboards
+// are disabled. The operator's ServiceMonitor may serve other clusters and
+// is kept.
+func (r *SwarmClusterReconciler) deleteDashboards(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	for _, dashboard := range []string{"overview", "tasks", "agents"} {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: dashboardConfigMapName(cluster, dashboard), Namespace: cluster.Namespace}}
+		if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	for _, component := range []string{"agents", "memory", "hivemind"} {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(podMonitorGVK)
+		obj.SetName(cluster.Name + "-" + component)
+		obj.SetNamespace(cluster.Namespace)
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// dashboardPanel is a Grafana panel plotting one or more queries
+type dashboardPanel struct {
+	title   string
+	kind    string
+	unit    string
+	queries []dashboardQuery
+}
+
+type dashboardQuery struct {
+	expr   string
+	legend string
+}
+
+// clusterDashboards renders the overview, task throughput and agent
+// utilization dashboards of the cluster, keyed by dashboard
+func clusterDashboards(cluster *swarmv1alpha1.SwarmCluster) map[string]string {
+	clusterSel := fmt.Sprintf(`namespace=%q,name=%q`, cluster.Namespace, cluster.Name)
+	taskSel := fmt.Sprintf(`swarm_cluster=%q`, cluster.Name)
+	agentSel := fmt.Sprintf(`namespace=%q`, cluster.Namespace)
+
+	return map[string]string{
+		"overview": renderDashboard(cluster, "overview", "Cluster overview", []dashboardPanel{
+			{title: "Phase", kind: "stat", queries: []dashboardQuery{{fmt.Sprintf(`swarm_cluster_phase{%s} == 1`, clusterSel), "{{phase}}"}}},
+			{title: "Agents by status", kind: "timeseries", queries: []dashboardQuery{{fmt.Sprintf(`sum by (status) (swarm_cluster_agents{%s})`, clusterSel), "{{status}}"}}},
+			{title: "Task queue", kind: "timeseries", queries: []dashboardQuery{{fmt.Sprintf(`sum(swarm_task_queue_size{%s})`, taskSel), "queued"}}},
+			{title: "Task success rate", kind: "stat", unit: "percentunit", queries: []dashboardQuery{{fmt.Sprintf(`avg(swarm_task_success_rate{%s})`, taskSel), "success"}}},
+			{title: "SLO compliance", kind: "timeseries", unit: "percentunit", queries: []dashboardQuery{{fmt.Sprintf(`swarm_task_slo_compliance{%s}`, taskSel), "{{slo}}"}}},
+			{title: "Maintenance freeze", kind: "stat", queries: []dashboardQuery{{fmt.Sprintf(`max(swarm_cluster_frozen{%s})`, clusterSel), "frozen"}}},
+		}),
+		"tasks": renderDashboard(cluster, "tasks", "Task throughput", []dashboardPanel{
+			{title: "Finished tasks", kind: "timeseries", unit: "ops", queries: []dashboardQuery{{fmt.Sprintf(`sum by (result) (rate(swarm_task_executor_jobs_total{%s}[5m]))`, taskSel), "{{result}}"}}},
+			{title: "Task duration", kind: "timeseries", unit: "s", queries: []dashboardQuery{
+				{fmt.Sprintf(`histogram_quantile(0.5, sum by (le) (rate(swarm_task_duration_seconds_bucket{%s}[5m])))`, taskSel), "p50"},
+				{fmt.Sprintf(`histogram_quantile(0.95, sum by (le) (rate(swarm_task_duration_seconds_bucket{%s}[5m])))`, taskSel), "p95"},
+			}},
+			{title: "Duration by task type", kind: "timeseries", unit: "s", queries: []dashboardQuery{
+				{fmt.Sprintf(`histogram_quantile(0.95, sum by (le, task_type) (rate(swarm_task_duration_seconds_bucket{%s}[5m])))`, taskSel), "{{task_type}}"},
+			}},
+			{title: "Task queue", kind: "timeseries", queries: []dashboardQuery{{fmt.Sprintf(`sum(swarm_task_queue_size{%s})`, taskSel), "queued"}}},
+			{title: "SLO error budget remaining", kind: "timeseries", unit: "percentunit", queries: []dashboardQuery{{fmt.Sprintf(`swarm_task_slo_error_budget_remaining{%s}`, taskSel), "{{slo}}"}}},
+		}),
+		"agents": renderDashboard(cluster, "agents", "Agent utilization", []dashboardPanel{
+			{title: "Agents by type", kind: "timeseries", queries: []dashboardQuery{{fmt.Sprintf(`sum by (type) (swarm_agent_total{%s,%s})`, agentSel, taskSel), "{{type}}"}}},
+			{title: "Current tasks per agent", kind: "timeseries", queries: []dashboardQuery{{fmt.Sprintf(`swarm_agent_tasks_current{%s}`, agentSel), "{{name}}"}}},
+			{title: "Completed tasks per agent", kind: "timeseries", unit: "ops", queries: []dashboardQuery{{fmt.Sprintf(`sum by (name) (rate(swarm_agent_tasks_completed_total{%s}[5m]))`, agentSel), "{{name}}"}}},
+			{title: "Agent CPU", kind: "timeseries", unit: "percent", queries: []dashboardQuery{{fmt.Sprintf(`swarm_agent_cpu_usage_percent{%s}`, agentSel), "{{name}}"}}},
+			{title: "Agent memory", kind: "timeseries", unit: "bytes", queries: []dashboardQuery{{fmt.Sprintf(`swarm_agent_memory_usage_bytes{%s}`, agentSel), "{{name}}"}}},
+			{title: "Autoscaling target", kind: "timeseries", queries: []dashboardQuery{{fmt.Sprintf(`swarm_autoscaling_target_agents{%s}`, agentSel), "target"}}},
+		}),
+	}
+}
+
+// renderDashboard renders a Grafana dashboard model laying the panels out
+// two per row. The uid is derived from the cluster so re-imports replace
+// the dashboard instead of duplicating it.
+func renderDashboard(cluster *swarmv1alpha1.SwarmCluster, dashboard, title string, panels []dashboardPanel) string {
+	sum := sha256.Sum256([]byte(cluster.Namespace + "/" + cluster.Name + "/" + dashboard))
+
+	var models []interface{}
+	for i, panel := range panels {
+		var targets []interface{}
+		for j, query := range panel.queries {
+			targets = append(targets, map[string]interface{}{
+				"refId":        string(rune('A' + j)),
+				"expr":         query.expr,
+				"legendFormat": query.legend,
+			})
+		}
+		model := map[string]interface{}{
+			"id":         i + 1,
+			"title":      panel.title,
+			"type":       panel.kind,
+			"datasource": map[string]interface{}{"type": "prometheus", "uid": "${datasource}"},
+			"gridPos":    map[string]interface{}{"x": (i % 2) * 12, "y": (i / 2) * 8, "w": 12, "h": 8},
+			"targets":    targets,
+		}
+		if panel.unit != "" {
+			model["fieldConfig"] = map[string]interface{}{"defaults": map[string]interface{}{"unit": panel.unit}, "overrides": []interface{}{}}
+		}
+		models = append(models, model)
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"uid":           "swarm-" + hex.EncodeToString(sum[:])[:16],
+		"title":         fmt.Sprintf("Swarm %s / %s", cluster.Name, title),
+		"tags":          []string{"claude-flow", "swarm"},
+		"editable":      false,
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]interface{}{"from": "now-6h", "to": "now"},
+		"templating": map[string]interface{}{"list": []interface{}{
+			map[string]interface{}{"name": "datasource", "type": "datasource", "query": "prometheus", "label": "Data source"},
+		}},
+		"panels": models,
+	})
+	return string(data)
+}
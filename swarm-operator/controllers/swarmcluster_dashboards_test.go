@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Dashboards", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	build := func() {
+		k8sClient := newTestClientBuilder().
+			WithScheme(scheme).
+			WithObjects(cluster).
+			WithInterceptorFuncs(uninstalledKinds(scheme)).
+			Build()
+		reconciler = &SwarmClusterReconciler{
+			Client:            k8sClient,
+			Scheme:            scheme,
+			Recorder:          record.NewFakeRecorder(10),
+			OperatorNamespace: "swarm-system",
+		}
+	}
+
+	getMonitor := func(kind, name, namespace string) (*unstructured.Unstructured, error) {
+		monitor := &unstructured.Unstructured{}
+		gvk := podMonitorGVK
+		gvk.Kind = kind
+		monitor.SetGroupVersionKind(gvk)
+		err := reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, monitor)
+		return monitor, err
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = newTestScheme()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Monitoring: &swarmv1alpha1.MonitoringSpec{DashboardEnabled: true, MetricsPort: 9102},
+			},
+		}
+	})
+
+	It("renders valid dashboard models scoped to the cluster", func() {
+		dashboards := clusterDashboards(cluster)
+		Expect(dashboards).To(HaveLen(3))
+
+		var model struct {
+			UID    string `json:"uid"`
+			Panels []struct {
+				Targets []struct {
+					Expr string `json:"expr"`
+				} `json:"targets"`
+			} `json:"panels"`
+		}
+		Expect(json.Unmarshal([]byte(dashboards["tasks"]), &model)).To(Succeed())
+		Expect(len(model.UID)).To(BeNumerically("<=", 40))
+		Expect(model.Panels).NotTo(BeEmpty())
+		Expect(model.Panels[0].Targets[0].Expr).To(ContainSubstring(`swarm_cluster="swarm"`))
+		Expect(clusterDashboards(cluster)["tasks"]).To(Equal(dashboards["tasks"]))
+	})
+
+	It("installs dashboards and reports a missing Prometheus operator", func() {
+		build()
+		Expect(reconciler.reconcileDashboards(ctx, cluster)).To(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-dashboard-overview", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.Labels).To(HaveKeyWithValue("grafana_dashboard", "1"))
+		Expect(cm.Data).To(HaveKey("swarm-overview.json"))
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeDashboardsReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(ReasonPrometheusOperatorMissing))
+	})
+
+	Context("with the Prometheus operator installed", func() {
+		BeforeEach(func() {
+			for _, kind := range []string{"PodMonitor", "ServiceMonitor"} {
+				gvk := podMonitorGVK
+				gvk.Kind = kind
+				scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+				gvk.Kind = kind + "List"
+				scheme.AddKnownTypeWithName(gvk, &unstructured.UnstructuredList{})
+			}
+			cluster.Spec.Monitoring.MonitorLabels = map[string]string{"release": "prometheus"}
+			cluster.Spec.HiveMind = &swarmv1alpha1.HiveMindSpec{Enabled: true}
+		})
+
+		It("monitors every component and cleans up once disabled", func() {
+			build()
+			Expect(reconciler.reconcileDashboards(ctx, cluster)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeDashboardsReady)).To(BeTrue())
+
+			agents, err := getMonitor("PodMonitor", "swarm-agents", "default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agents.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+			endpoints, _, _ := unstructured.NestedSlice(agents.Object, "spec", "podMetricsEndpoints")
+			Expect(endpoints[0].(map[string]interface{})["targetPort"]).To(BeEquivalentTo(9102))
+
+			_, err = getMonitor("PodMonitor", "swarm-hivemind", "default")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = getMonitor("ServiceMonitor", operatorMonitorName, "swarm-system")
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster.Spec.Monitoring.DashboardEnabled = false
+			Expect(reconciler.reconcileDashboards(ctx, cluster)).To(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeDashboardsReady)).To(BeNil())
+			_, err = getMonitor("PodMonitor", "swarm-agents", "default")
+			Expect(err).To(HaveOccurred())
+			err = reconciler.Get(ctx, types.NamespacedName{Name: "swarm-dashboard-agents", Namespace: "default"}, &corev1.ConfigMap{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
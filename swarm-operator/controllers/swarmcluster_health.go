@@ -0,0 +1,330 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/notify"
+)
+
+const (
+	// ConditionTypeUnhealthy reports a health score below the unhealthy
+	// threshold
+	ConditionTypeUnhealthy = "Unhealthy"
+
+	ReasonStaleHeartbeats   = "StaleHeartbeats"
+	ReasonTaskFailures      = "TaskFailures"
+	ReasonSlowMemoryBackend = "SlowMemoryBackend"
+	ReasonHiveMindLagging   = "HiveMindLagging"
+
+	healthAgentReadiness     = "agentReadiness"
+	healthHeartbeatFreshness = "heartbeatFreshness"
+	healthTaskFailureRate    = "taskFailureRate"
+	healthMemoryLatency      = "memoryLatency"
+	healthHiveMindSyncLag    = "hiveMindSyncLag"
+
+	defaultDegradedThreshold   = int32(80)
+	defaultUnhealthyThreshold  = int32(50)
+	defaultFailureWindow       = time.Hour
+	defaultMemoryLatencyTarget = 100 * time.Millisecond
+	defaultSyncLagTarget       = 30 * time.Second
+
+	// heartbeatGrace is the heartbeat age that still scores full. Agents
+	// heartbeat once per reconcile, so an age up to two intervals is on time.
+	heartbeatGrace = 2 * heartbeatInterval
+)
+
+// healthSignal is one weighted input of the health score
+type healthSignal struct {
+	name    string
+	reason  string
+	weight  int32
+	score   float64
+	message string
+}
+
+// deficit is how many points the signal costs the score
+func (s healthSignal) deficit() float64 {
+	return float64(s.weight) * (1 - s.score)
+}
+
+// healthSpec returns the health configuration of the cluster, the defaults
+// when it has none
+func healthSpec(cluster *swarmv1alpha1.SwarmCluster) swarmv1alpha1.HealthSpec {
+	if cluster.Spec.Health == nil {
+		return swarmv1alpha1.HealthSpec{}
+	}
+	return *cluster.Spec.Health
+}
+
+func healthWeight(weight *int32, defaultWeight int32) int32 {
+	if weight == nil {
+		return defaultWeight
+	}
+	return *weight
+}
+
+// healthThresholds returns the scores below which the cluster is Degraded
+// and Unhealthy
+func healthThresholds(spec swarmv1alpha1.HealthSpec) (degraded, unhealthy int32) {
+	degraded, unhealthy = defaultDegradedThreshold, defaultUnhealthyThreshold
+	if spec.DegradedThreshold > 0 {
+		degraded = spec.DegradedThreshold
+	}
+	if spec.UnhealthyThreshold > 0 {
+		unhealthy = spec.UnhealthyThreshold
+	}
+	return degraded, unhealthy
+}
+
+// targetScore scores a measurement against its target: 1 up to the target,
+// then falling with its inverse so twice the target scores 0.5
+func targetScore(value, target float64) float64 {
+	if value <= target {
+		return 1
+	}
+	return target / value
+}
+
+// clusterHealthSignals measures the signals of the health score. Signals
+// without data or with a weight of 0 are left out.
+func (r *SwarmClusterReconciler) clusterHealthSignals(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent, now time.Time) ([]healthSignal, error) {
+	spec := healthSpec(cluster)
+	weights := spec.Weights
+	var signals []healthSignal
+	add := func(signal healthSignal, weight *int32, defaultWeight int32) {
+		if signal.weight = healthWeight(weight, defaultWeight); signal.weight > 0 {
+			signals = append(signals, signal)
+		}
+	}
+
+	var ready, live, fresh int
+	var freshness float64
+	for _, agent := range agents {
+		if agent.Status.Phase == "Ready" || agent.Status.Phase == "Busy" {
+			ready++
+		}
+		if agent.Status.Phase == "Failed" || agent.Status.Phase == "Terminating" || agent.Status.LastHeartbeat == nil {
+			continue
+		}
+		live++
+		age := now.Sub(agent.Status.LastHeartbeat.Time)
+		if age <= heartbeatGrace {
+			fresh++
+		}
+		freshness += math.Max(0, math.Min(1, float64(heartbeatTimeout-age)/float64(heartbeatTimeout-heartbeatGrace)))
+	}
+
+	if desired := max(cluster.Spec.MinAgents, cluster.Status.ActiveAgents); desired > 0 {
+		add(healthSignal{
+			name:    healthAgentReadiness,
+			reason:  ReasonInsufficientAgents,
+			score:   math.Min(1, float64(ready)/float64(desired)),
+			message: fmt.Sprintf("%d/%d agents are ready", ready, desired),
+		}, weights.AgentReadiness, 30)
+	}
+
+	if live > 0 {
+		add(healthSignal{
+			name:    healthHeartbeatFreshness,
+			reason:  ReasonStaleHeartbeats,
+			score:   freshness / float64(live),
+			message: fmt.Sprintf("%d/%d agents sent a heartbeat within %s", fresh, live, heartbeatGrace),
+		}, weights.HeartbeatFreshness, 20)
+	}
+
+	window := parseDurationOrDefault(spec.FailureWindow, defaultFailureWindow)
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, tasks, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+	var finished, failed int
+	for _, task := range tasks.Items {
+		if task.Spec.SwarmCluster != cluster.Name || task.Status.CompletionTime == nil || now.Sub(task.Status.CompletionTime.Time) > window {
+			continue
+		}
+		switch task.Status.Phase {
+		case "Completed":
+			finished++
+		case "Failed", taskPhaseDeadLettered:
+			finished++
+			failed++
+		}
+	}
+	if finished > 0 {
+		add(healthSignal{
+			name:    healthTaskFailureRate,
+			reason:  ReasonTaskFailures,
+			score:   1 - float64(failed)/float64(finished),
+			message: fmt.Sprintf("%d of %d tasks finished within %s failed", failed, finished, window),
+		}, weights.TaskFailureRate, 25)
+	}
+
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := r.List(ctx, stores, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+	target := parseDurationOrDefault(spec.MemoryLatencyTarget, defaultMemoryLatencyTarget)
+	var memory *healthSignal
+	for _, store := range stores.Items {
+		latency := store.Status.BackendLatencyMillis
+		if store.Spec.SwarmClusterRef != cluster.Name || latency == nil {
+			continue
+		}
+		signal := healthSignal{name: healthMemoryLatency, reason: ReasonSlowMemoryBackend}
+		if *latency < 0 {
+			signal.message = fmt.Sprintf("Memory store %s does not answer", store.Name)
+		} else {
+			signal.score = targetScore(float64(*latency), float64(target.Milliseconds()))
+			signal.message = fmt.Sprintf("Memory store %s answers in %dms, target %s", store.Name, *latency, target)
+		}
+		if memory == nil || signal.score < memory.score {
+			memory = &signal
+		}
+	}
+	if memory != nil {
+		add(*memory, weights.MemoryLatency, 15)
+	}
+
+	if hiveMind := cluster.Status.HiveMindStatus; hiveMindEnabled(cluster) && hiveMind != nil {
+		target := parseDurationOrDefault(spec.SyncLagTarget, defaultSyncLagTarget)
+		signal := healthSignal{name: healthHiveMindSyncLag, reason: ReasonHiveMindLagging}
+		switch {
+		case hiveMind.ReadyReplicas == 0:
+			signal.message = "No hive-mind replica is ready"
+		case hiveMind.SyncLagSeconds != nil:
+			signal.score = targetScore(float64(*hiveMind.SyncLagSeconds), target.Seconds())
+			signal.message = fmt.Sprintf("Hive-mind replicas trail by up to %ds, target %s", *hiveMind.SyncLagSeconds, target)
+		}
+		if signal.message != "" {
+			add(signal, weights.HiveMindSyncLag, 10)
+		}
+	}
+	return signals, nil
+}
+
+// healthScore is the weighted mean of the signals from 0 to 100, false
+// without any signal
+func healthScore(signals []healthSignal) (int32, bool) {
+	var total, weighted float64
+	for _, signal := range signals {
+		total += float64(signal.weight)
+		weighted += float64(signal.weight) * signal.score
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return int32(math.Round(weighted / total * 100)), true
+}
+
+// reconcileHealth scores the cluster, publishes the score in status and
+// metrics, and sets the Degraded and Unhealthy conditions from it. The
+// caller updates the status.
+func (r *SwarmClusterReconciler) reconcileHealth(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent) error {
+	signals, err := r.clusterHealthSignals(ctx, cluster, agents, time.Now())
+	if err != nil {
+		return err
+	}
+
+	score, ok := healthScore(signals)
+	if !ok {
+		cluster.Status.HealthScore = nil
+		cluster.Status.HealthSignals = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeDegraded)
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeUnhealthy)
+		return nil
+	}
+
+	statuses := make([]swarmv1alpha1.HealthSignalStatus, 0, len(signals))
+	scores := make(map[string]float64, len(signals))
+	var problems []string
+	worst := signals[0]
+	for _, signal := range signals {
+		statuses = append(statuses, swarmv1alpha1.HealthSignalStatus{
+			Name:    signal.name,
+			Score:   int32(math.Round(signal.score * 100)),
+			Weight:  signal.weight,
+			Message: signal.message,
+		})
+		scores[signal.name] = signal.score * 100
+		if signal.score < 1 {
+			problems = append(problems, signal.message)
+		}
+		if signal.deficit() > worst.deficit() {
+			worst = signal
+		}
+	}
+	cluster.Status.HealthScore = &score
+	cluster.Status.HealthSignals = statuses
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.RecordSwarmClusterHealth(cluster.Namespace, cluster.Name, float64(score), scores)
+	}
+
+	degraded, unhealthy := healthThresholds(healthSpec(cluster))
+	message := fmt.Sprintf("Health score %d: %s", score, strings.Join(problems, "; "))
+
+	wasDegraded := meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeDegraded)
+	if score < degraded {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             worst.reason,
+			Message:            message,
+			ObservedGeneration: cluster.Generation,
+		})
+		if !wasDegraded {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "Degraded", message)
+			if r.Notifier != nil {
+				r.Notifier.Notify(ctx, cluster, notify.Event{
+					Type:    swarmv1alpha1.ClusterDegradedEvent,
+					Phase:   cluster.Status.Phase,
+					Message: message,
+				})
+			}
+		}
+	} else {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeDegraded)
+	}
+
+	wasUnhealthy := meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeUnhealthy)
+	if score < unhealthy {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeUnhealthy,
+			Status:             metav1.ConditionTrue,
+			Reason:             worst.reason,
+			Message:            message,
+			ObservedGeneration: cluster.Generation,
+		})
+		if !wasUnhealthy {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "Unhealthy", message)
+		}
+	} else {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeUnhealthy)
+	}
+	return nil
+}
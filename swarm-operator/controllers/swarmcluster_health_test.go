@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Cluster health", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		agents     []swarmv1alpha1.Agent
+		reconciler *SwarmClusterReconciler
+	)
+
+	agent := func(name, phase string, heartbeatAge time.Duration) swarmv1alpha1.Agent {
+		return swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: swarmv1alpha1.AgentStatus{
+				Phase:         phase,
+				LastHeartbeat: &metav1.Time{Time: time.Now().Add(-heartbeatAge)},
+			},
+		}
+	}
+
+	finishedTask := func(name, phase string, ago time.Duration) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+			Status: swarmv1alpha1.SwarmTaskStatus{
+				Phase:          phase,
+				CompletionTime: &metav1.Time{Time: time.Now().Add(-ago)},
+			},
+		}
+	}
+
+	build := func(objects ...client.Object) {
+		reconciler = &SwarmClusterReconciler{
+			Client:   newTestClient(append(objects, cluster)...),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmClusterSpec{MinAgents: 4},
+			Status:     swarmv1alpha1.SwarmClusterStatus{Phase: "Running", ActiveAgents: 4},
+		}
+		agents = []swarmv1alpha1.Agent{
+			agent("a", "Ready", 10*time.Second),
+			agent("b", "Busy", 20*time.Second),
+			agent("c", "Ready", 30*time.Second),
+			agent("d", "Ready", 40*time.Second),
+		}
+	})
+
+	It("scores a cluster without problems 100", func() {
+		build(finishedTask("done", "Completed", time.Minute))
+		Expect(reconciler.reconcileHealth(ctx, cluster, agents)).To(Succeed())
+
+		Expect(*cluster.Status.HealthScore).To(Equal(int32(100)))
+		Expect(cluster.Status.HealthSignals).To(HaveLen(3))
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeDegraded)).To(BeNil())
+	})
+
+	It("weighs the signals and leaves out those without data", func() {
+		agents[3] = agent("d", "Initializing", 90*time.Second)
+		build(
+			finishedTask("done", "Completed", time.Minute),
+			finishedTask("broken", "Failed", 2*time.Minute),
+			finishedTask("old", "Failed", 2*time.Hour),
+		)
+		signals, err := reconciler.clusterHealthSignals(ctx, cluster, agents, time.Now())
+		Expect(err).NotTo(HaveOccurred())
+
+		scores := map[string]int32{}
+		for _, signal := range signals {
+			scores[signal.name] = int32(signal.score * 100)
+		}
+		// The heartbeat of d is halfway from the grace period to the timeout
+		Expect(scores).To(HaveKeyWithValue(healthAgentReadiness, int32(75)))
+		Expect(scores).To(HaveKeyWithValue(healthHeartbeatFreshness, BeNumerically("~", 87, 1)))
+		Expect(scores).To(HaveKeyWithValue(healthTaskFailureRate, int32(50)))
+		Expect(scores).NotTo(HaveKey(healthMemoryLatency))
+		Expect(scores).NotTo(HaveKey(healthHiveMindSyncLag))
+
+		// (30*0.75 + 20*0.875 + 25*0.5) / 75
+		score, ok := healthScore(signals)
+		Expect(ok).To(BeTrue())
+		Expect(score).To(Equal(int32(70)))
+	})
+
+	It("marks the cluster Degraded and Unhealthy by the thresholds", func() {
+		noWeight, lag, unreachable := int32(0), int64(90), int64(-1)
+		cluster.Spec.Health = &swarmv1alpha1.HealthSpec{
+			Weights:            swarmv1alpha1.HealthWeights{HeartbeatFreshness: &noWeight},
+			UnhealthyThreshold: 60,
+		}
+		cluster.Spec.HiveMind = &swarmv1alpha1.HiveMindSpec{Enabled: true}
+		cluster.Status.HiveMindStatus = &swarmv1alpha1.HiveMindStatus{Replicas: 1, ReadyReplicas: 1, SyncLagSeconds: &lag}
+		store := &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "swarm"},
+			Status:     swarmv1alpha1.SwarmMemoryStoreStatus{BackendLatencyMillis: &unreachable},
+		}
+		build(store, finishedTask("broken", "Failed", time.Minute))
+		Expect(reconciler.reconcileHealth(ctx, cluster, agents)).To(Succeed())
+
+		// (30 + 25*0 + 15*0 + 10/3) / 80
+		Expect(*cluster.Status.HealthScore).To(Equal(int32(42)))
+		Expect(cluster.Status.HealthSignals).To(HaveLen(4))
+		degraded := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeDegraded)
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Reason).To(Equal(ReasonTaskFailures))
+		Expect(degraded.Message).To(ContainSubstring("Memory store swarm-memory does not answer"))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeUnhealthy)).To(BeTrue())
+
+		Expect(reconciler.Delete(ctx, store)).To(Succeed())
+		broken := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "broken", Namespace: "default"}, broken)).To(Succeed())
+		Expect(reconciler.Delete(ctx, broken)).To(Succeed())
+		Expect(reconciler.reconcileHealth(ctx, cluster, agents)).To(Succeed())
+
+		// (30 + 10/3) / 40
+		Expect(*cluster.Status.HealthScore).To(Equal(int32(83)))
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeDegraded)).To(BeNil())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeUnhealthy)).To(BeNil())
+	})
+})
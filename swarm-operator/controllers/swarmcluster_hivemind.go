@@ -0,0 +1,635 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	defaultHiveMindImage      = "claudeflow/hivemind:2.0.0"
+	defaultHiveMindPartitions = int32(16)
+
+	hiveMindContainerName = "hivemind"
+	hiveMindPort          = int32(7090)
+
+	// hiveMindPartitionLabel carries the partition of agent pods and of
+	// the per-partition Services
+	hiveMindPartitionLabel = "swarm.claudeflow.io/hivemind-partition"
+
+	// hiveMindPartitionsKey is the ConfigMap key with the partition owners,
+	// one "partition=pod" line per partition. Replicas sync the partitions
+	// whose owner is their own pod name.
+	hiveMindPartitionsKey       = "partitions"
+	hiveMindPartitionsMountPath = "/etc/hivemind"
+
+	// hiveMindSyncStatusPath reports how far a replica trails its peers as
+	// {"lagSeconds": n}
+	hiveMindSyncStatusPath = "/v1/sync/status"
+)
+
+// hiveMindSyncState is the sync status a hive-mind replica reports
+type hiveMindSyncState struct {
+	LagSeconds int64 `json:"lagSeconds"`
+}
+
+// hiveMindEnabled reports whether the cluster runs the hive-mind sync service
+func hiveMindEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.HiveMind != nil && cluster.Spec.HiveMind.Enabled
+}
+
+// hiveMindName names the sync StatefulSet, its headless Service and the
+// partition ConfigMap. The Service matches the hive-mind TLS server name.
+func hiveMindName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return cluster.Name + "-hivemind"
+}
+
+// hiveMindPartitionService is the Service agents of a partition sync with.
+// Its selector follows the partition owner, so agents keep their address
+// across rebalances.
+func hiveMindPartitionService(cluster *swarmv1alpha1.SwarmCluster, partition int32) string {
+	return fmt.Sprintf("%s-hivemind-p%d", cluster.Name, partition)
+}
+
+func hiveMindPartitionCount(cluster *swarmv1alpha1.SwarmCluster) int32 {
+	if cluster.Spec.HiveMind.Partitions > 0 {
+		return cluster.Spec.HiveMind.Partitions
+	}
+	return defaultHiveMindPartitions
+}
+
+func hiveMindReplicas(cluster *swarmv1alpha1.SwarmCluster) int32 {
+	if cluster.Spec.HiveMind.Replicas > 0 {
+		return cluster.Spec.HiveMind.Replicas
+	}
+	return 1
+}
+
+// agentPartition hashes an agent into one of the partitions. The hash only
+// depends on the agent name so agents keep their partition when replicas
+// come and go.
+func agentPartition(agent string, partitions int32) int32 {
+	h := fnv.New32a()
+	h.Write([]byte(agent))
+	return int32(h.Sum32() % uint32(partitions))
+}
+
+// assignPartitions spreads the partitions round-robin over the serving
+// replicas, ordered by ordinal. Without a ready replica everything goes to
+// the first one so agents wait for it rather than for nothing.
+func assignPartitions(cluster *swarmv1alpha1.SwarmCluster, serving []string) []string {
+	if len(serving) == 0 {
+		serving = []string{hiveMindName(cluster) + "-0"}
+	}
+	owners := make([]string, hiveMindPartitionCount(cluster))
+	for p := range owners {
+		owners[p] = serving[p%len(serving)]
+	}
+	return owners
+}
+
+// servingHiveMindPods returns the ready sync pods within the desired
+// replica count, ordered by ordinal. Pods being scaled away stop owning
+// partitions before they are gone.
+func (r *SwarmClusterReconciler) servingHiveMindPods(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) ([]string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name, "component": "hivemind"}); err != nil {
+		return nil, err
+	}
+
+	replicas := hiveMindReplicas(cluster)
+	byOrdinal := map[int]string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		ordinal, err := strconv.Atoi(strings.TrimPrefix(pod.Name, hiveMindName(cluster)+"-"))
+		if err != nil || ordinal >= int(replicas) || pod.GetDeletionTimestamp() != nil || !podReady(pod) {
+			continue
+		}
+		byOrdinal[ordinal] = pod.Name
+	}
+
+	var serving []string
+	for ordinal := 0; ordinal < int(replicas); ordinal++ {
+		if name, ok := byOrdinal[ordinal]; ok {
+			serving = append(serving, name)
+		}
+	}
+	return serving, nil
+}
+
+// hiveMindURL is the sync endpoint of a partition. Pooled agents get the
+// partition from their labels file and substitute it for "{partition}".
+func hiveMindURL(cluster *swarmv1alpha1.SwarmCluster, partition string) string {
+	scheme := "http"
+	if tlsEnabled(cluster) {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s-hivemind-p%s.%s.svc:%d", scheme, cluster.Name, partition, cluster.Namespace, hiveMindPort)
+}
+
+// applyHiveMindPartition assigns a per-agent Deployment to its partition
+func applyHiveMindPartition(cluster *swarmv1alpha1.SwarmCluster, agent string, template *corev1.PodTemplateSpec) {
+	if !hiveMindEnabled(cluster) {
+		return
+	}
+	partition := strconv.Itoa(int(agentPartition(agent, hiveMindPartitionCount(cluster))))
+	if template.Labels == nil {
+		template.Labels = map[string]string{}
+	}
+	template.Labels[hiveMindPartitionLabel] = partition
+	template.Spec.Containers[0].Env = append(template.Spec.Containers[0].Env,
+		corev1.EnvVar{Name: "SWARM_HIVEMIND_PARTITION", Value: partition},
+		corev1.EnvVar{Name: "SWARM_HIVEMIND_URL", Value: hiveMindURL(cluster, partition)},
+	)
+}
+
+// applyHiveMindPoolPartition points pooled agents at their partition. The
+// pod serves a different agent per slot, so the partition is set as a pod
+// label by labelSlotPod and read from the labels file.
+func applyHiveMindPoolPartition(cluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) {
+	if !hiveMindEnabled(cluster) {
+		return
+	}
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env,
+		corev1.EnvVar{Name: "SWARM_HIVEMIND_PARTITION_LABEL", Value: hiveMindPartitionLabel},
+		corev1.EnvVar{Name: "SWARM_HIVEMIND_URL", Value: hiveMindURL(cluster, "{partition}")},
+	)
+}
+
+// reconcileHiveMind runs the partitioned sync service, rebalances the
+// partitions over the ready replicas and reports ownership in status
+func (r *SwarmClusterReconciler) reconcileHiveMind(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	if !hiveMindEnabled(cluster) {
+		if cluster.Status.HiveMindStatus == nil {
+			return nil
+		}
+		if err := r.deleteHiveMind(ctx, cluster); err != nil {
+			return err
+		}
+		cluster.Status.HiveMindStatus = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeHiveMindZoneSpread)
+		return r.Status().Update(ctx, cluster)
+	}
+
+	sts, err := r.reconcileHiveMindStatefulSet(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	serving, err := r.servingHiveMindPods(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	owners := assignPartitions(cluster, serving)
+
+	if err := r.reconcileHiveMindServices(ctx, cluster, owners); err != nil {
+		return err
+	}
+	if err := r.reconcileHiveMindPartitionMap(ctx, cluster, owners); err != nil {
+		return err
+	}
+
+	agents := &swarmv1alpha1.AgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name}); err != nil {
+		return err
+	}
+	counts := make([]int32, len(owners))
+	for _, agent := range agents.Items {
+		counts[agentPartition(agent.Name, int32(len(owners)))]++
+	}
+
+	status := &swarmv1alpha1.HiveMindStatus{
+		Replicas:      hiveMindReplicas(cluster),
+		ReadyReplicas: sts.Status.ReadyReplicas,
+	}
+	for p, owner := range owners {
+		status.Partitions = append(status.Partitions, swarmv1alpha1.HiveMindPartition{
+			Partition: int32(p), Owner: owner, Agents: counts[p],
+		})
+	}
+
+	if status.SyncLagSeconds, err = r.hiveMindSyncLag(ctx, cluster); err != nil {
+		return err
+	}
+
+	previous := cluster.Status.HiveMindStatus
+	if previous != nil {
+		status.LastRebalanceTime = previous.LastRebalanceTime
+	}
+	if rebalanced := partitionOwnersChanged(previous, owners); rebalanced {
+		now := metav1.Now()
+		status.LastRebalanceTime = &now
+		if previous != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "HiveMindRebalanced",
+				"Spread %d partitions over %d ready hive-mind replicas", len(owners), len(serving))
+		}
+	}
+	spreadChanged, err := r.reconcileHiveMindZoneSpread(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(previous, status) && !spreadChanged {
+		return nil
+	}
+	cluster.Status.HiveMindStatus = status
+	return r.Status().Update(ctx, cluster)
+}
+
+// reconcileHiveMindZoneSpread reports whether the ready replicas survive
+// the loss of a zone and warns once they are all in one. It returns whether
+// the condition changed.
+func (r *SwarmClusterReconciler) reconcileHiveMindZoneSpread(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (bool, error) {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name, "component": "hivemind"}); err != nil {
+		return false, err
+	}
+	pods := make([]*corev1.Pod, 0, len(podList.Items))
+	for i := range podList.Items {
+		pods = append(pods, &podList.Items[i])
+	}
+
+	condition, err := zoneSpreadCondition(ctx, r.Client, ConditionTypeHiveMindZoneSpread, pods,
+		failureDomainKey(cluster.Spec.HiveMind.FailureDomains), cluster.Generation)
+	if err != nil {
+		return false, err
+	}
+	if condition == nil {
+		return meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeHiveMindZoneSpread), nil
+	}
+	previous := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeHiveMindZoneSpread)
+	if condition.Reason == ReasonSingleZone && (previous == nil || previous.Reason != ReasonSingleZone) {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, ReasonSingleZone, "Hive-mind: "+condition.Message)
+	}
+	return meta.SetStatusCondition(&cluster.Status.Conditions, *condition), nil
+}
+
+// hiveMindSyncLag returns the sync lag of the replica trailing furthest, or
+// nil when no ready replica reports it. Replicas serving mTLS only accept
+// clients with a cluster certificate and are not scraped.
+func (r *SwarmClusterReconciler) hiveMindSyncLag(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (*int64, error) {
+	if tlsEnabled(cluster) {
+		return nil, nil
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name, "component": "hivemind"}); err != nil {
+		return nil, err
+	}
+
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 2 * time.Second}
+	}
+
+	var lag *int64
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podReady(pod) || pod.Status.PodIP == "" {
+			continue
+		}
+		url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, hiveMindPort, hiveMindSyncStatusPath)
+		state, err := fetchHiveMindSyncState(ctx, httpClient, url)
+		if err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to collect hive-mind sync state", "pod", pod.Name, "error", err.Error())
+			continue
+		}
+		if lag == nil || state.LagSeconds > *lag {
+			lag = &state.LagSeconds
+		}
+	}
+	return lag, nil
+}
+
+func fetchHiveMindSyncState(ctx context.Context, httpClient *http.Client, url string) (*hiveMindSyncState, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hive-mind returned %s", resp.Status)
+	}
+
+	state := &hiveMindSyncState{}
+	if err := json.NewDecoder(resp.Body).Decode(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func partitionOwnersChanged(previous *swarmv1alpha1.HiveMindStatus, owners []string) bool {
+	if previous == nil || len(previous.Partitions) != len(owners) {
+		return true
+	}
+	for i, partition := range previous.Partitions {
+		if partition.Owner != owners[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileHiveMindStatefulSet creates or updates the sync StatefulSet
+func (r *SwarmClusterReconciler) reconcileHiveMindStatefulSet(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (*appsv1.StatefulSet, error) {
+	desired := constructHiveMindStatefulSet(cluster)
+	utils.ApplyImageConfig(&desired.Spec.Template.Spec, cluster.Spec.ImageConfig)
+	if err := controllerutil.SetControllerReference(cluster, desired, r.Scheme); err != nil {
+		return nil, err
+	}
+	// Replicas pick up partition map changes without restarting
+	if err := applyConfigHash(ctx, r, desired.Namespace, &desired.Spec.Template, hiveMindName(cluster)); err != nil {
+		return nil, err
+	}
+
+	existing := &appsv1.StatefulSet{}
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if errors.IsNotFound(err) {
+		if err := r.Create(ctx, desired); err != nil {
+			return nil, err
+		}
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "HiveMindCreated", "Created the hive-mind sync service")
+		return desired, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keepConfigHash(existing, &existing.Spec.Template, &desired.Spec.Template)
+	if *existing.Spec.Replicas == *desired.Spec.Replicas &&
+		equality.Semantic.DeepDerivative(desired.Spec.Template, existing.Spec.Template) {
+		return existing, nil
+	}
+	existing.Spec.Replicas = desired.Spec.Replicas
+	existing.Spec.Template = desired.Spec.Template
+	if err := r.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// constructHiveMindStatefulSet builds the sync service. Replicas find the
+// partitions they own in the mounted partition map, which the kubelet
+// refreshes on rebalance without a restart.
+func constructHiveMindStatefulSet(cluster *swarmv1alpha1.SwarmCluster) *appsv1.StatefulSet {
+	spec := cluster.Spec.HiveMind
+	name := hiveMindName(cluster)
+	labels := map[string]string{
+		"swarm-cluster": cluster.Name,
+		"component":     "hivemind",
+	}
+
+	image := spec.Image
+	if image == "" {
+		image = defaultHiveMindImage
+	}
+
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:  hiveMindContainerName,
+				Image: image,
+				Ports: []corev1.ContainerPort{
+					{Name: "sync", ContainerPort: hiveMindPort, Protocol: corev1.ProtocolTCP},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "SWARM_CLUSTER", Value: cluster.Name},
+					{
+						Name: "HIVEMIND_POD_NAME",
+						ValueFrom: &corev1.EnvVarSource{
+							FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+						},
+					},
+					{Name: "HIVEMIND_PARTITIONS", Value: strconv.Itoa(int(hiveMindPartitionCount(cluster)))},
+					{Name: "HIVEMIND_PARTITIONS_FILE", Value: hiveMindPartitionsMountPath + "/" + hiveMindPartitionsKey},
+					{Name: "HIVEMIND_PEERS", Value: fmt.Sprintf("%s.%s.svc", name, cluster.Namespace)},
+					{Name: "HIVEMIND_SYNC_INTERVAL", Value: spec.SyncInterval},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "partitions", MountPath: hiveMindPartitionsMountPath, ReadOnly: true},
+				},
+				Resources: *spec.Resources.DeepCopy(),
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "partitions",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					},
+				},
+			},
+		},
+	}
+	// Losing a zone must leave partitions with a replica to move to
+	applyFailureDomains(spec.FailureDomains, labels, &podSpec)
+	if tlsEnabled(cluster) {
+		applyPodTLS(&podSpec, tlsSecretName(cluster, hiveMindTLSComponent), hiveMindServerName(cluster), hiveMindContainerName)
+	}
+
+	replicas := hiveMindReplicas(cluster)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: name,
+			Selector:    &metav1.LabelSelector{MatchLabels: labels},
+			// Replicas serve independent partitions and need not wait for
+			// each other
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+}
+
+// reconcileHiveMindServices maintains the headless peer Service and one
+// Service per partition selecting the partition owner
+func (r *SwarmClusterReconciler) reconcileHiveMindServices(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, owners []string) error {
+	labels := map[string]string{"swarm-cluster": cluster.Name, "component": "hivemind"}
+	ports := []corev1.ServicePort{{Name: "sync", Port: hiveMindPort, TargetPort: intstr.FromString("sync")}}
+
+	if err := r.applyHiveMindService(ctx, cluster, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: hiveMindName(cluster), Namespace: cluster.Namespace, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			Selector:                 labels,
+			Ports:                    ports,
+			PublishNotReadyAddresses: true,
+		},
+	}); err != nil {
+		return err
+	}
+
+	for p, owner := range owners {
+		partition := strconv.Itoa(p)
+		if err := r.applyHiveMindService(ctx, cluster, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      hiveMindPartitionService(cluster, int32(p)),
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					"swarm-cluster":        cluster.Name,
+					"component":            "hivemind",
+					hiveMindPartitionLabel: partition,
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{
+					"swarm-cluster":                      cluster.Name,
+					"component":                          "hivemind",
+					"statefulset.kubernetes.io/pod-name": owner,
+				},
+				Ports: ports,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Drop the Services of partitions that no longer exist
+	services := &corev1.ServiceList{}
+	if err := r.List(ctx, services, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name, "component": "hivemind"}); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		svc := &services.Items[i]
+		value, ok := svc.Labels[hiveMindPartitionLabel]
+		if !ok {
+			continue
+		}
+		if partition, err := strconv.Atoi(value); err == nil && partition < len(owners) {
+			continue
+		}
+		if err := r.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyHiveMindService creates the Service or moves its selector, which is
+// how partitions follow their owner
+func (r *SwarmClusterReconciler) applyHiveMindService(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, desired *corev1.Service) error {
+	existing := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if errors.IsNotFound(err) {
+		if err := controllerutil.SetControllerReference(cluster, desired, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, desired)
+	}
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Spec.Selector, desired.Spec.Selector) {
+		return nil
+	}
+	existing.Spec.Selector = desired.Spec.Selector
+	return r.Update(ctx, existing)
+}
+
+// reconcileHiveMindPartitionMap publishes the partition owners to the
+// replicas
+func (r *SwarmClusterReconciler) reconcileHiveMindPartitionMap(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, owners []string) error {
+	var b strings.Builder
+	for p, owner := range owners {
+		fmt.Fprintf(&b, "%d=%s\n", p, owner)
+	}
+	data := map[string]string{hiveMindPartitionsKey: b.String()}
+
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: hiveMindName(cluster), Namespace: cluster.Namespace}, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      hiveMindName(cluster),
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"swarm-cluster": cluster.Name, "component": "hivemind"},
+			},
+			Data: data,
+		}
+		if err := controllerutil.SetControllerReference(cluster, cm, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(cm.Data, data) {
+		return nil
+	}
+	cm.Data = data
+	return r.Update(ctx, cm)
+}
+
+// deleteHiveMind removes the sync service after hive-mind is disabled
+func (r *SwarmClusterReconciler) deleteHiveMind(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	objects := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: hiveMindName(cluster), Namespace: cluster.Namespace}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: hiveMindName(cluster), Namespace: cluster.Namespace}},
+	}
+	services := &corev1.ServiceList{}
+	if err := r.List(ctx, services, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name, "component": "hivemind"}); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		objects = append(objects, &services.Items[i])
+	}
+	for _, obj := range objects {
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
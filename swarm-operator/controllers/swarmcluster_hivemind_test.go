@@ -0,0 +1,168 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Hive-mind partitions", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	hiveMindPod := func(ordinal int) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("swarm-hivemind-%d", ordinal),
+				Namespace: "default",
+				Labels:    map[string]string{"swarm-cluster": "swarm", "component": "hivemind"},
+			},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+
+	partitionSelector := func(partition int32) string {
+		svc := &corev1.Service{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{
+			Name: hiveMindPartitionService(cluster, partition), Namespace: "default",
+		}, svc)).To(Succeed())
+		return svc.Spec.Selector["statefulset.kubernetes.io/pod-name"]
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				HiveMind: &swarmv1alpha1.HiveMindSpec{Enabled: true, Replicas: 2, Partitions: 4},
+			},
+		}
+		agents := []client.Object{cluster, hiveMindPod(0), hiveMindPod(1)}
+		for i := 0; i < 8; i++ {
+			agents = append(agents, &swarmv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("coder-%d", i),
+				Namespace: "default",
+				Labels:    map[string]string{"swarm-cluster": "swarm"},
+			}})
+		}
+		k8sClient := newTestClient(agents...)
+		reconciler = &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("hashes agents into stable partitions", func() {
+		Expect(agentPartition("coder-1", 16)).To(Equal(agentPartition("coder-1", 16)))
+		seen := map[int32]bool{}
+		for i := 0; i < 64; i++ {
+			p := agentPartition(fmt.Sprintf("agent-%d", i), 4)
+			Expect(p).To(BeNumerically("<", 4))
+			seen[p] = true
+		}
+		Expect(seen).To(HaveLen(4))
+	})
+
+	It("spreads partitions over the ready replicas", func() {
+		Expect(assignPartitions(cluster, []string{"swarm-hivemind-0", "swarm-hivemind-1"})).To(Equal(
+			[]string{"swarm-hivemind-0", "swarm-hivemind-1", "swarm-hivemind-0", "swarm-hivemind-1"}))
+		Expect(assignPartitions(cluster, nil)).To(HaveEach("swarm-hivemind-0"))
+	})
+
+	It("runs the sync service and reports partition ownership", func() {
+		Expect(reconciler.reconcileHiveMind(ctx, cluster)).To(Succeed())
+
+		sts := &appsv1.StatefulSet{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-hivemind", Namespace: "default"}, sts)).To(Succeed())
+		Expect(*sts.Spec.Replicas).To(BeEquivalentTo(2))
+		Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "HIVEMIND_PARTITIONS", Value: "4"}))
+
+		peers := &corev1.Service{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-hivemind", Namespace: "default"}, peers)).To(Succeed())
+		Expect(peers.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+
+		cm := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-hivemind", Namespace: "default"}, cm)).To(Succeed())
+		Expect(cm.Data[hiveMindPartitionsKey]).To(Equal(
+			"0=swarm-hivemind-0\n1=swarm-hivemind-1\n2=swarm-hivemind-0\n3=swarm-hivemind-1\n"))
+
+		Expect(partitionSelector(1)).To(Equal("swarm-hivemind-1"))
+
+		status := cluster.Status.HiveMindStatus
+		Expect(status.Partitions).To(HaveLen(4))
+		Expect(status.LastRebalanceTime).NotTo(BeNil())
+		var agents int32
+		for _, partition := range status.Partitions {
+			agents += partition.Agents
+		}
+		Expect(agents).To(BeEquivalentTo(8))
+	})
+
+	It("rebalances when a replica goes away", func() {
+		Expect(reconciler.reconcileHiveMind(ctx, cluster)).To(Succeed())
+		events := reconciler.Recorder.(*record.FakeRecorder).Events
+		Expect(events).To(Receive(ContainSubstring("HiveMindCreated")))
+
+		pod := &corev1.Pod{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-hivemind-1", Namespace: "default"}, pod)).To(Succeed())
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		Expect(reconciler.Status().Update(ctx, pod)).To(Succeed())
+		Expect(reconciler.reconcileHiveMind(ctx, cluster)).To(Succeed())
+
+		Expect(partitionSelector(1)).To(Equal("swarm-hivemind-0"))
+		for _, partition := range cluster.Status.HiveMindStatus.Partitions {
+			Expect(partition.Owner).To(Equal("swarm-hivemind-0"))
+		}
+		Expect(events).To(Receive(ContainSubstring("HiveMindRebalanced")))
+	})
+
+	It("drops the Services of removed partitions", func() {
+		Expect(reconciler.reconcileHiveMind(ctx, cluster)).To(Succeed())
+
+		cluster.Spec.HiveMind.Partitions = 2
+		Expect(reconciler.reconcileHiveMind(ctx, cluster)).To(Succeed())
+
+		services := &corev1.ServiceList{}
+		Expect(reconciler.List(ctx, services, client.MatchingLabels{"swarm-cluster": "swarm"})).To(Succeed())
+		Expect(services.Items).To(HaveLen(3))
+	})
+
+	It("assigns agent Deployments to their partition", func() {
+		template := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: agentContainerName}}}}
+		applyHiveMindPartition(cluster, "coder-1", template)
+
+		partition := fmt.Sprint(agentPartition("coder-1", 4))
+		Expect(template.Labels).To(HaveKeyWithValue(hiveMindPartitionLabel, partition))
+		Expect(template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name:  "SWARM_HIVEMIND_URL",
+			Value: fmt.Sprintf("http://swarm-hivemind-p%s.default.svc:7090", partition),
+		}))
+	})
+})
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/schedule"
+)
+
+const (
+	// ConditionTypeFrozen reports whether a maintenance window freezes the swarm
+	ConditionTypeFrozen = "Frozen"
+
+	ReasonMaintenanceWindowOpen    = "MaintenanceWindowOpen"
+	ReasonNoMaintenanceWindow      = "NoMaintenanceWindow"
+	ReasonInvalidMaintenanceWindow = "InvalidMaintenanceWindow"
+)
+
+// activeMaintenanceWindow returns the maintenance window freezing the
+// cluster at now and when the freeze ends, nil when the cluster is not
+// frozen. With several windows open the one closing last is returned.
+// Windows that do not parse are skipped.
+func activeMaintenanceWindow(cluster *swarmv1alpha1.SwarmCluster, now time.Time) (*swarmv1alpha1.MaintenanceWindow, time.Time) {
+	var active *swarmv1alpha1.MaintenanceWindow
+	var activeEnd time.Time
+	for i := range cluster.Spec.MaintenanceWindows {
+		window := &cluster.Spec.MaintenanceWindows[i]
+		w, err := schedule.NewWindow(window.Schedule, window.Duration.Duration, window.TimeZone)
+		if err != nil {
+			continue
+		}
+		if end, open := w.Active(now); open && end.After(activeEnd) {
+			active, activeEnd = window, end
+		}
+	}
+	return active, activeEnd
+}
+
+// scaleUpPaused reports whether a maintenance window holds the cluster at
+// its current size. Scaling down stays allowed.
+func scaleUpPaused(cluster *swarmv1alpha1.SwarmCluster) bool {
+	window, _ := activeMaintenanceWindow(cluster, time.Now())
+	return window != nil
+}
+
+// invalidMaintenanceWindows describes the windows that fail to parse
+func invalidMaintenanceWindows(cluster *swarmv1alpha1.SwarmCluster) []string {
+	var invalid []string
+	for _, window := range cluster.Spec.MaintenanceWindows {
+		if _, err := schedule.NewWindow(window.Schedule, window.Duration.Duration, window.TimeZone); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", window.Name, err))
+		}
+	}
+	return invalid
+}
+
+// reconcileMaintenance reports the freeze state of the cluster through the
+// Frozen condition and the swarm_cluster_frozen metric
+func (r *SwarmClusterReconciler) reconcileMaintenance(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	if len(cluster.Spec.MaintenanceWindows) == 0 {
+		if meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeFrozen) == nil {
+			return nil
+		}
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeFrozen)
+		if r.MetricsRecorder != nil {
+			r.MetricsRecorder.RecordSwarmClusterFrozen(cluster.Namespace, cluster.Name, "")
+		}
+		return r.Status().Update(ctx, cluster)
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeFrozen,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonNoMaintenanceWindow,
+		Message:            "No maintenance window is open",
+		ObservedGeneration: cluster.Generation,
+	}
+	window, end := activeMaintenanceWindow(cluster, time.Now())
+	if window != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonMaintenanceWindowOpen
+		condition.Message = fmt.Sprintf("Maintenance window %s is open until %s", window.Name, end.UTC().Format(time.RFC3339))
+	} else if invalid := invalidMaintenanceWindows(cluster); len(invalid) > 0 {
+		condition.Reason = ReasonInvalidMaintenanceWindow
+		condition.Message = strings.Join(invalid, "; ")
+	}
+
+	if r.MetricsRecorder != nil {
+		name := ""
+		if window != nil {
+			name = window.Name
+		}
+		r.MetricsRecorder.RecordSwarmClusterFrozen(cluster.Namespace, cluster.Name, name)
+	}
+
+	if !meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+		return nil
+	}
+	eventType := corev1.EventTypeNormal
+	if condition.Reason == ReasonInvalidMaintenanceWindow {
+		eventType = corev1.EventTypeWarning
+	}
+	r.Recorder.Event(cluster, eventType, condition.Reason, condition.Message)
+	return r.Status().Update(ctx, cluster)
+}
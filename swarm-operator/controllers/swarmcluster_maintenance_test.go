@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Maintenance windows", func() {
+	var (
+		ctx context.Context
+	)
+
+	// A window opening every minute is always open
+	alwaysOpen := swarmv1alpha1.MaintenanceWindow{
+		Name:     "always",
+		Schedule: "* * * * *",
+		Duration: metav1.Duration{Duration: time.Hour},
+	}
+
+	frozenCluster := func(windows ...swarmv1alpha1.MaintenanceWindow) *swarmv1alpha1.SwarmCluster {
+		return &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmClusterSpec{MaintenanceWindows: windows},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("finds the window open at a time in its zone", func() {
+		cluster := frozenCluster(swarmv1alpha1.MaintenanceWindow{
+			Name:     "nightly",
+			Schedule: "0 22 * * *",
+			Duration: metav1.Duration{Duration: 4 * time.Hour},
+			TimeZone: "Europe/Berlin",
+		}, swarmv1alpha1.MaintenanceWindow{Name: "broken", Schedule: "every night"})
+
+		window, end := activeMaintenanceWindow(cluster, time.Date(2026, 10, 16, 20, 30, 0, 0, time.UTC))
+		Expect(window).NotTo(BeNil())
+		Expect(window.Name).To(Equal("nightly"))
+		Expect(end.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+
+		window, _ = activeMaintenanceWindow(cluster, time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+		Expect(window).To(BeNil())
+		Expect(invalidMaintenanceWindows(cluster)).To(HaveLen(1))
+	})
+
+	It("sets the Frozen condition while a window is open", func() {
+		cluster := frozenCluster(alwaysOpen)
+		k8sClient := newTestClient(cluster)
+		reconciler := &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+
+		Expect(reconciler.reconcileMaintenance(ctx, cluster)).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeFrozen)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(ReasonMaintenanceWindowOpen))
+		Expect(scaleUpPaused(cluster)).To(BeTrue())
+	})
+
+	Context("dispatching tasks", func() {
+		var (
+			reconciler *SwarmTaskReconciler
+			k8sClient  client.Client
+			task       *swarmv1alpha1.SwarmTask
+		)
+
+		build := func(objects ...client.Object) {
+			k8sClient = newTestClient(objects...)
+			reconciler = &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+		}
+
+		BeforeEach(func() {
+			task = &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "refactor", Namespace: "default"},
+				Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+			}
+		})
+
+		It("holds tasks that have no Job yet", func() {
+			build(task)
+			end := time.Now().Add(time.Hour)
+
+			held, err := reconciler.holdForMaintenance(ctx, task, "swarm-system", &alwaysOpen, end)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeTrue())
+			Expect(task.Status.Phase).To(Equal("Pending"))
+			Expect(task.Status.Message).To(ContainSubstring("maintenance window always"))
+		})
+
+		It("lets running tasks finish unless the window suspends them", func() {
+			task.Status.Phase = "Running"
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "swarm-system"},
+				Status:     batchv1.JobStatus{Active: 1},
+			}
+			build(task, job)
+
+			held, err := reconciler.holdForMaintenance(ctx, task, "swarm-system", &alwaysOpen, time.Now().Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeFalse())
+		})
+
+		It("checkpoints and suspends running tasks", func() {
+			task.Status.Phase = "Running"
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "swarm-system"},
+				Status:     batchv1.JobStatus{Active: 1},
+			}
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      "refactor-pod",
+				Namespace: "swarm-system",
+				Labels:    map[string]string{"job-name": job.Name},
+			}}
+			build(task, job, pod)
+
+			window := alwaysOpen
+			window.SuspendRunning = true
+			held, err := reconciler.holdForMaintenance(ctx, task, "swarm-system", &window, time.Now().Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeTrue())
+			Expect(task.Status.Phase).To(Equal(taskPhaseSuspended))
+			Expect(task.Status.Attempt).To(BeEquivalentTo(1))
+			Expect(task.Spec.Resume).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+			Expect(pod.Annotations).To(HaveKey(checkpointRequestedAnnotation))
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,673 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/topology"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// ConditionTypeMessageBusReady reports whether agents can reach the
+	// message bus
+	ConditionTypeMessageBusReady = "MessageBusReady"
+
+	ReasonBrokerReady       = "BrokerReady"
+	ReasonBrokerUnavailable = "BrokerUnavailable"
+
+	defaultMessageBusImage  = "nats:2.10.22-alpine"
+	messageBusReloaderImage = "natsio/nats-server-config-reloader:0.16.0"
+
+	messageBusContainerName = "nats"
+	messageBusClientPort    = int32(4222)
+	messageBusRoutePort     = int32(6222)
+	messageBusMonitorPort   = int32(8222)
+
+	messageBusConfigKey       = "nats.conf"
+	messageBusConfigMountPath = "/etc/nats-config"
+	messageBusPidMountPath    = "/var/run/nats"
+	messageBusDataMountPath   = "/data"
+
+	// messageBusAdminUser is the credentials key of the unrestricted user
+	// kept for operators debugging the bus. Agent names cannot start with
+	// an underscore, so it never clashes with an agent user.
+	messageBusAdminUser = "_admin"
+)
+
+// messageBusEnabled reports whether the cluster runs a message bus
+func messageBusEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.MessageBus != nil && cluster.Spec.MessageBus.Enabled
+}
+
+// messageBusName names the broker StatefulSet and its client Service
+func messageBusName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return cluster.Name + "-bus"
+}
+
+func messageBusHeadlessName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return messageBusName(cluster) + "-headless"
+}
+
+// messageBusConfigName is the Secret holding the rendered broker config,
+// which embeds the user passwords
+func messageBusConfigName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return messageBusName(cluster) + "-config"
+}
+
+// messageBusCredentialsName is the Secret with one password per broker
+// user, keyed by user name. Agents resolve it from the name of their
+// cluster, so it takes the name rather than the cluster.
+func messageBusCredentialsName(clusterName string) string {
+	return clusterName + "-bus-credentials"
+}
+
+func messageBusReplicas(cluster *swarmv1alpha1.SwarmCluster) int32 {
+	if cluster.Spec.MessageBus.Replicas < 1 {
+		return 1
+	}
+	return cluster.Spec.MessageBus.Replicas
+}
+
+// messageBusURL is the client address agents connect to
+func messageBusURL(cluster *swarmv1alpha1.SwarmCluster) string {
+	return fmt.Sprintf("nats://%s.%s.svc:%d", messageBusName(cluster), cluster.Namespace, messageBusClientPort)
+}
+
+// Subjects are scoped to the cluster: every agent has an inbox, every agent
+// type a work queue, and the whole swarm shares a broadcast subject
+func messageBusSubjectPrefix(cluster *swarmv1alpha1.SwarmCluster) string {
+	return "swarm." + cluster.Name
+}
+
+func agentInbox(cluster *swarmv1alpha1.SwarmCluster, agent string) string {
+	return messageBusSubjectPrefix(cluster) + ".agent." + agent
+}
+
+func agentTypeQueue(cluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType) string {
+	return messageBusSubjectPrefix(cluster) + ".queue." + string(agentType)
+}
+
+func broadcastSubject(cluster *swarmv1alpha1.SwarmCluster) string {
+	return messageBusSubjectPrefix(cluster) + ".broadcast"
+}
+
+// busUser returns the broker user of an agent. Pooled agents share the
+// user of their pool, since a pool pod serves whichever agent holds its
+// slot.
+func busUser(agent *swarmv1alpha1.Agent) string {
+	if agent.Status.Pool != "" {
+		return agent.Status.Pool
+	}
+	return agent.Name
+}
+
+// peerName extracts the agent name from a topology peer address
+func peerName(address string) string {
+	name, _, _ := strings.Cut(address, ".")
+	return name
+}
+
+// busPermissions are the subjects a broker user may publish and subscribe to
+type busPermissions struct {
+	Publish   []string
+	Subscribe []string
+}
+
+// messageBusPermissions derives the broker users from the topology: each
+// user subscribes to the inboxes and type queues of its agents and may
+// publish to the inboxes of their peers. It also returns the number of
+// distinct peer routes.
+func messageBusPermissions(cluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent, peers map[string][]string) (map[string]busPermissions, int) {
+	publish := map[string]map[string]bool{}
+	subscribe := map[string]map[string]bool{}
+	add := func(set map[string]map[string]bool, user, subject string) {
+		if set[user] == nil {
+			set[user] = map[string]bool{}
+		}
+		set[user][subject] = true
+	}
+
+	routes := 0
+	for i := range agents {
+		agent := &agents[i]
+		user := busUser(agent)
+		add(subscribe, user, agentInbox(cluster, agent.Name))
+		add(subscribe, user, agentTypeQueue(cluster, agent.Spec.Type))
+		add(subscribe, user, broadcastSubject(cluster))
+		add(subscribe, user, "_INBOX.>")
+		add(publish, user, broadcastSubject(cluster))
+		add(publish, user, messageBusSubjectPrefix(cluster)+".queue.>")
+		if cluster.Spec.MessageBus.JetStream {
+			add(publish, user, "$JS.API.>")
+		}
+		for _, address := range peers[agent.Name] {
+			add(publish, user, agentInbox(cluster, peerName(address)))
+			routes++
+		}
+	}
+
+	users := make(map[string]busPermissions, len(subscribe))
+	for user := range subscribe {
+		users[user] = busPermissions{Publish: sortedKeys(publish[user]), Subscribe: sortedKeys(subscribe[user])}
+	}
+	return users, routes
+}
+
+// renderMessageBusConfig renders the NATS server config. Passwords are
+// looked up by user name.
+func renderMessageBusConfig(cluster *swarmv1alpha1.SwarmCluster, users map[string]busPermissions, passwords map[string]string) string {
+	spec := cluster.Spec.MessageBus
+	quoteAll := func(subjects []string) string {
+		quoted := make([]string, len(subjects))
+		for i, subject := range subjects {
+			quoted[i] = strconv.Quote(subject)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+
+	var b strings.Builder
+	b.WriteString("server_name: $SERVER_NAME\n")
+	fmt.Fprintf(&b, "port: %d\n", messageBusClientPort)
+	fmt.Fprintf(&b, "http_port: %d\n", messageBusMonitorPort)
+	fmt.Fprintf(&b, "pid_file: %q\n", messageBusPidMountPath+"/nats.pid")
+
+	if spec.JetStream {
+		fmt.Fprintf(&b, "jetstream {\n  store_dir: %q\n}\n", messageBusDataMountPath)
+	}
+
+	if replicas := messageBusReplicas(cluster); replicas > 1 {
+		routes := make([]string, replicas)
+		for i := range routes {
+			routes[i] = fmt.Sprintf("nats://%s-%d.%s.%s.svc:%d",
+				messageBusName(cluster), i, messageBusHeadlessName(cluster), cluster.Namespace, messageBusRoutePort)
+		}
+		fmt.Fprintf(&b, "cluster {\n  name: %q\n  port: %d\n  routes: %s\n}\n",
+			cluster.Name, messageBusRoutePort, quoteAll(routes))
+	}
+
+	b.WriteString("authorization {\n  users: [\n")
+	fmt.Fprintf(&b, "    {user: %q, password: %q}\n", messageBusAdminUser, passwords[messageBusAdminUser])
+	for _, user := range sortedKeys(users) {
+		permissions := users[user]
+		fmt.Fprintf(&b, "    {user: %q, password: %q, permissions: {publish: {allow: %s}, subscribe: {allow: %s}, allow_responses: true}}\n",
+			user, passwords[user], quoteAll(permissions.Publish), quoteAll(permissions.Subscribe))
+	}
+	b.WriteString("  ]\n}\n")
+	return b.String()
+}
+
+// reconcileMessageBus runs the cluster's NATS broker, keeps one user per
+// agent with permissions following the topology, and reports the broker's
+// health in status
+func (r *SwarmClusterReconciler) reconcileMessageBus(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	if !messageBusEnabled(cluster) {
+		if cluster.Status.MessageBus == nil && meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeMessageBusReady) == nil {
+			return nil
+		}
+		if err := r.deleteMessageBus(ctx, cluster); err != nil {
+			return err
+		}
+		cluster.Status.MessageBus = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeMessageBusReady)
+		return r.Status().Update(ctx, cluster)
+	}
+
+	agents := &swarmv1alpha1.AgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name}); err != nil {
+		return err
+	}
+	manager, err := topology.ForCluster(cluster)
+	if err != nil {
+		return err
+	}
+	users, routes := messageBusPermissions(cluster, agents.Items, withCurrentPeers(manager.CalculatePeers(agents.Items), agents.Items))
+
+	passwords, err := r.reconcileMessageBusCredentials(ctx, cluster, users)
+	if err != nil {
+		return err
+	}
+	if err := r.reconcileMessageBusConfig(ctx, cluster, renderMessageBusConfig(cluster, users, passwords)); err != nil {
+		return err
+	}
+	if err := r.reconcileMessageBusServices(ctx, cluster); err != nil {
+		return err
+	}
+	sts, err := r.reconcileMessageBusStatefulSet(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	status := &swarmv1alpha1.MessageBusStatus{
+		URL:           messageBusURL(cluster),
+		Replicas:      messageBusReplicas(cluster),
+		ReadyReplicas: sts.Status.ReadyReplicas,
+		Users:         int32(len(users)),
+		Routes:        int32(routes),
+	}
+	condition := metav1.Condition{
+		Type:               ConditionTypeMessageBusReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonBrokerReady,
+		Message:            fmt.Sprintf("%d/%d broker replicas ready", status.ReadyReplicas, status.Replicas),
+		ObservedGeneration: cluster.Generation,
+	}
+	if status.ReadyReplicas == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonBrokerUnavailable
+	}
+
+	changed := meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	if changed {
+		eventType := corev1.EventTypeNormal
+		if condition.Status != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(cluster, eventType, condition.Reason, condition.Message)
+	}
+	if !changed && equality.Semantic.DeepEqual(cluster.Status.MessageBus, status) {
+		return nil
+	}
+	cluster.Status.MessageBus = status
+	return r.Status().Update(ctx, cluster)
+}
+
+// reconcileMessageBusCredentials generates a password for every new user,
+// drops those of removed users and returns all of them
+func (r *SwarmClusterReconciler) reconcileMessageBusCredentials(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, users map[string]busPermissions) (map[string]string, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: messageBusCredentialsName(cluster.Name), Namespace: cluster.Namespace}, secret)
+	create := errors.IsNotFound(err)
+	if err != nil && !create {
+		return nil, err
+	}
+	if create {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      messageBusCredentialsName(cluster.Name),
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"swarm-cluster": cluster.Name, "component": "messagebus"},
+			},
+		}
+		if err := controllerutil.SetControllerReference(cluster, secret, r.Scheme); err != nil {
+			return nil, err
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	changed := false
+	for _, user := range append(sortedKeys(users), messageBusAdminUser) {
+		if len(secret.Data[user]) > 0 {
+			continue
+		}
+		password, err := randomPassword()
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[user] = []byte(password)
+		changed = true
+	}
+	for user := range secret.Data {
+		if _, ok := users[user]; !ok && user != messageBusAdminUser {
+			delete(secret.Data, user)
+			changed = true
+		}
+	}
+
+	switch {
+	case create:
+		err = r.Create(ctx, secret)
+	case changed:
+		err = r.Update(ctx, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	passwords := make(map[string]string, len(secret.Data))
+	for user, password := range secret.Data {
+		passwords[user] = string(password)
+	}
+	return passwords, nil
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// reconcileMessageBusConfig publishes the rendered config. The reloader
+// sidecar signals the broker to pick it up, so user and permission
+// changes apply without dropping connections.
+func (r *SwarmClusterReconciler) reconcileMessageBusConfig(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, config string) error {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: messageBusConfigName(cluster), Namespace: cluster.Namespace}, secret)
+	if errors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      messageBusConfigName(cluster),
+				Namespace: cluster.Namespace,
+				Labels:    map[string]string{"swarm-cluster": cluster.Name, "component": "messagebus"},
+			},
+			Data: map[string][]byte{messageBusConfigKey: []byte(config)},
+		}
+		if err := controllerutil.SetControllerReference(cluster, secret, r.Scheme); err != nil {
+			return err
+		}
+		return r.Create(ctx, secret)
+	}
+	if err != nil {
+		return err
+	}
+	if string(secret.Data[messageBusConfigKey]) == config {
+		return nil
+	}
+	secret.Data = map[string][]byte{messageBusConfigKey: []byte(config)}
+	return r.Update(ctx, secret)
+}
+
+// reconcileMessageBusServices maintains the client Service and the headless
+// Service the replicas route to each other over
+func (r *SwarmClusterReconciler) reconcileMessageBusServices(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	labels := map[string]string{"swarm-cluster": cluster.Name, "component": "messagebus"}
+	services := []*corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: messageBusName(cluster), Namespace: cluster.Namespace, Labels: labels},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports: []corev1.ServicePort{
+					{Name: "client", Port: messageBusClientPort, TargetPort: intstr.FromString("client")},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: messageBusHeadlessName(cluster), Namespace: cluster.Namespace, Labels: labels},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: corev1.ClusterIPNone,
+				Selector:  labels,
+				Ports: []corev1.ServicePort{
+					{Name: "client", Port: messageBusClientPort, TargetPort: intstr.FromString("client")},
+					{Name: "cluster", Port: messageBusRoutePort, TargetPort: intstr.FromString("cluster")},
+				},
+				PublishNotReadyAddresses: true,
+			},
+		},
+	}
+	for _, desired := range services {
+		existing := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+		if errors.IsNotFound(err) {
+			if err := controllerutil.SetControllerReference(cluster, desired, r.Scheme); err != nil {
+				return err
+			}
+			if err := r.Create(ctx, desired); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileMessageBusStatefulSet creates or updates the broker StatefulSet
+func (r *SwarmClusterReconciler) reconcileMessageBusStatefulSet(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (*appsv1.StatefulSet, error) {
+	desired, err := constructMessageBusStatefulSet(cluster)
+	if err != nil {
+		return nil, err
+	}
+	utils.ApplyImageConfig(&desired.Spec.Template.Spec, cluster.Spec.ImageConfig)
+	if err := controllerutil.SetControllerReference(cluster, desired, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	existing := &appsv1.StatefulSet{}
+	err = r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if errors.IsNotFound(err) {
+		if err := r.Create(ctx, desired); err != nil {
+			return nil, err
+		}
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "MessageBusCreated", "Created the message bus")
+		return desired, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if *existing.Spec.Replicas == *desired.Spec.Replicas &&
+		equality.Semantic.DeepDerivative(desired.Spec.Template, existing.Spec.Template) {
+		return existing, nil
+	}
+	existing.Spec.Replicas = desired.Spec.Replicas
+	existing.Spec.Template = desired.Spec.Template
+	if err := r.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// constructMessageBusStatefulSet builds the broker. The config reloader
+// shares the process namespace to signal the server on config changes.
+func constructMessageBusStatefulSet(cluster *swarmv1alpha1.SwarmCluster) (*appsv1.StatefulSet, error) {
+	spec := cluster.Spec.MessageBus
+	labels := map[string]string{"swarm-cluster": cluster.Name, "component": "messagebus"}
+
+	image := spec.Image
+	if image == "" {
+		image = defaultMessageBusImage
+	}
+	configFile := messageBusConfigMountPath + "/" + messageBusConfigKey
+	shareProcessNamespace := true
+
+	mounts := []corev1.VolumeMount{
+		{Name: "config", MountPath: messageBusConfigMountPath, ReadOnly: true},
+		{Name: "pid", MountPath: messageBusPidMountPath},
+	}
+	volumes := []corev1.Volume{
+		{Name: "config", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: messageBusConfigName(cluster)}}},
+		{Name: "pid", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	var claims []corev1.PersistentVolumeClaim
+	if spec.JetStream {
+		mounts = append(mounts, corev1.VolumeMount{Name: "data", MountPath: messageBusDataMountPath})
+		if spec.Storage == "" {
+			volumes = append(volumes, corev1.Volume{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+		} else {
+			size, err := resource.ParseQuantity(spec.Storage)
+			if err != nil {
+				return nil, fmt.Errorf("invalid message bus storage %q: %w", spec.Storage, err)
+			}
+			claims = append(claims, corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+					},
+				},
+			})
+		}
+	}
+
+	healthz := &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("monitor")},
+		},
+		PeriodSeconds: 10,
+	}
+
+	replicas := messageBusReplicas(cluster)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      messageBusName(cluster),
+			Namespace: cluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            &replicas,
+			ServiceName:         messageBusHeadlessName(cluster),
+			Selector:            &metav1.LabelSelector{MatchLabels: labels},
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ShareProcessNamespace: &shareProcessNamespace,
+					Containers: []corev1.Container{
+						{
+							Name:  messageBusContainerName,
+							Image: image,
+							Args:  []string{"--config", configFile},
+							Env: []corev1.EnvVar{{
+								Name:      "SERVER_NAME",
+								ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+							}},
+							Ports: []corev1.ContainerPort{
+								{Name: "client", ContainerPort: messageBusClientPort, Protocol: corev1.ProtocolTCP},
+								{Name: "cluster", ContainerPort: messageBusRoutePort, Protocol: corev1.ProtocolTCP},
+								{Name: "monitor", ContainerPort: messageBusMonitorPort, Protocol: corev1.ProtocolTCP},
+							},
+							ReadinessProbe: healthz,
+							LivenessProbe:  healthz.DeepCopy(),
+							VolumeMounts:   mounts,
+							Resources:      *spec.Resources.DeepCopy(),
+						},
+						{
+							Name:  "reloader",
+							Image: messageBusReloaderImage,
+							Args:  []string{"-pid", messageBusPidMountPath + "/nats.pid", "-config", configFile},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "config", MountPath: messageBusConfigMountPath, ReadOnly: true},
+								{Name: "pid", MountPath: messageBusPidMountPath},
+							},
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+			VolumeClaimTemplates: claims,
+		},
+	}, nil
+}
+
+// deleteMessageBus removes the broker after the message bus is disabled
+func (r *SwarmClusterReconciler) deleteMessageBus(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	objects := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: messageBusName(cluster), Namespace: cluster.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: messageBusName(cluster), Namespace: cluster.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: messageBusHeadlessName(cluster), Namespace: cluster.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: messageBusConfigName(cluster), Namespace: cluster.Namespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: messageBusCredentialsName(cluster.Name), Namespace: cluster.Namespace}},
+	}
+	for _, obj := range objects {
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// withCurrentPeers adds the peers agents have now to the peer map, so
+// agents not yet moved to a new topology keep reaching their old peers
+func withCurrentPeers(peers map[string][]string, agents []swarmv1alpha1.Agent) map[string][]string {
+	for i := range agents {
+		agent := &agents[i]
+		for _, address := range agent.Spec.CommunicationEndpoints.Peers {
+			found := false
+			for _, peer := range peers[agent.Name] {
+				found = found || peer == address
+			}
+			if !found {
+				peers[agent.Name] = append(peers[agent.Name], address)
+			}
+		}
+	}
+	return peers
+}
+
+// applyMessageBus connects a per-agent pod to the bus as the agent's user
+func applyMessageBus(cluster *swarmv1alpha1.SwarmCluster, agent *swarmv1alpha1.Agent, podSpec *corev1.PodSpec) {
+	if !messageBusEnabled(cluster) {
+		return
+	}
+	peers := make([]string, 0, len(agent.Spec.CommunicationEndpoints.Peers))
+	for _, address := range agent.Spec.CommunicationEndpoints.Peers {
+		peers = append(peers, agentInbox(cluster, peerName(address)))
+	}
+	sort.Strings(peers)
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, messageBusEnv(cluster, agent.Name, agent.Spec.Type)...)
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env,
+		corev1.EnvVar{Name: "SWARM_BUS_INBOX", Value: agentInbox(cluster, agent.Name)},
+		corev1.EnvVar{Name: "SWARM_BUS_PEERS", Value: strings.Join(peers, ",")},
+	)
+}
+
+// applyMessageBusPool connects pool pods to the bus as the pool's user.
+// The pod serves a different agent per slot, so the agent substitutes its
+// name for "{agent}" in the inbox.
+func applyMessageBusPool(cluster *swarmv1alpha1.SwarmCluster, pool string, agentType swarmv1alpha1.AgentType, podSpec *corev1.PodSpec) {
+	if !messageBusEnabled(cluster) {
+		return
+	}
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, messageBusEnv(cluster, pool, agentType)...)
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env,
+		corev1.EnvVar{Name: "SWARM_BUS_INBOX", Value: agentInbox(cluster, "{agent}")},
+	)
+}
+
+func messageBusEnv(cluster *swarmv1alpha1.SwarmCluster, user string, agentType swarmv1alpha1.AgentType) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "SWARM_BUS_URL", Value: messageBusURL(cluster)},
+		{Name: "SWARM_BUS_USER", Value: user},
+		{
+			Name: "SWARM_BUS_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: messageBusCredentialsName(cluster.Name)},
+				Key:                  user,
+			}},
+		},
+		{Name: "SWARM_BUS_QUEUE", Value: agentTypeQueue(cluster, agentType)},
+		{Name: "SWARM_BUS_BROADCAST", Value: broadcastSubject(cluster)},
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Message bus", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	agent := func(name string, agentType swarmv1alpha1.AgentType) *swarmv1alpha1.Agent {
+		return &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"swarm-cluster": "swarm"},
+			},
+			Spec: swarmv1alpha1.AgentSpec{Type: agentType, SwarmCluster: "swarm"},
+		}
+	}
+
+	credentials := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-bus-credentials", Namespace: "default"}, secret)).To(Succeed())
+		return secret
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Topology:   swarmv1alpha1.RingTopology,
+				MessageBus: &swarmv1alpha1.MessageBusSpec{Enabled: true, Replicas: 3},
+			},
+		}
+		k8sClient := newTestClient(cluster,
+			agent("coder-a", swarmv1alpha1.CoderAgent),
+			agent("coder-b", swarmv1alpha1.CoderAgent),
+			agent("tester-a", swarmv1alpha1.TesterAgent))
+		reconciler = &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("limits publishing to the inboxes of topology peers", func() {
+		users, routes := messageBusPermissions(cluster,
+			[]swarmv1alpha1.Agent{*agent("coder-a", swarmv1alpha1.CoderAgent), *agent("coder-b", swarmv1alpha1.CoderAgent)},
+			map[string][]string{"coder-a": {"coder-b.swarm-agents.default.svc.cluster.local:8080"}})
+
+		Expect(routes).To(Equal(1))
+		Expect(users["coder-a"].Publish).To(ContainElement("swarm.swarm.agent.coder-b"))
+		Expect(users["coder-a"].Subscribe).To(ContainElements("swarm.swarm.agent.coder-a", "swarm.swarm.queue.coder"))
+		Expect(users["coder-b"].Publish).NotTo(ContainElement("swarm.swarm.agent.coder-a"))
+	})
+
+	It("runs the broker and reports its health", func() {
+		Expect(reconciler.reconcileMessageBus(ctx, cluster)).To(Succeed())
+
+		sts := &appsv1.StatefulSet{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-bus", Namespace: "default"}, sts)).To(Succeed())
+		Expect(*sts.Spec.Replicas).To(BeEquivalentTo(3))
+		Expect(sts.Spec.Template.Spec.Containers).To(HaveLen(2))
+
+		config := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-bus-config", Namespace: "default"}, config)).To(Succeed())
+		Expect(string(config.Data[messageBusConfigKey])).To(ContainSubstring("nats://swarm-bus-2.swarm-bus-headless.default.svc:6222"))
+		Expect(string(config.Data[messageBusConfigKey])).To(ContainSubstring(`{user: "tester-a"`))
+
+		Expect(credentials().Data).To(HaveLen(4))
+
+		status := cluster.Status.MessageBus
+		Expect(status.URL).To(Equal("nats://swarm-bus.default.svc:4222"))
+		Expect(status.Users).To(BeEquivalentTo(3))
+		Expect(status.Routes).To(BeEquivalentTo(6))
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, ConditionTypeMessageBusReady)).To(BeTrue())
+	})
+
+	It("keeps passwords stable and drops removed agents", func() {
+		Expect(reconciler.reconcileMessageBus(ctx, cluster)).To(Succeed())
+		password := credentials().Data["coder-a"]
+
+		Expect(reconciler.Delete(ctx, agent("tester-a", swarmv1alpha1.TesterAgent))).To(Succeed())
+		Expect(reconciler.reconcileMessageBus(ctx, cluster)).To(Succeed())
+
+		Expect(credentials().Data).To(HaveKeyWithValue("coder-a", password))
+		Expect(credentials().Data).NotTo(HaveKey("tester-a"))
+		Expect(cluster.Status.MessageBus.Users).To(BeEquivalentTo(2))
+	})
+
+	It("removes the broker when disabled", func() {
+		Expect(reconciler.reconcileMessageBus(ctx, cluster)).To(Succeed())
+
+		cluster.Spec.MessageBus.Enabled = false
+		Expect(reconciler.reconcileMessageBus(ctx, cluster)).To(Succeed())
+
+		err := reconciler.Get(ctx, types.NamespacedName{Name: "swarm-bus", Namespace: "default"}, &appsv1.StatefulSet{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(cluster.Status.MessageBus).To(BeNil())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeMessageBusReady)).To(BeNil())
+
+		services := &corev1.ServiceList{}
+		Expect(reconciler.List(ctx, services, client.MatchingLabels{"component": "messagebus"})).To(Succeed())
+		Expect(services.Items).To(BeEmpty())
+	})
+
+	It("injects the connection into agent pods", func() {
+		a := agent("coder-a", swarmv1alpha1.CoderAgent)
+		a.Spec.CommunicationEndpoints.Peers = []string{"tester-a.swarm-agents.default.svc.cluster.local:8080"}
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: agentContainerName}}}
+		applyMessageBus(cluster, a, podSpec)
+
+		env := podSpec.Containers[0].Env
+		Expect(env).To(ContainElements(
+			corev1.EnvVar{Name: "SWARM_BUS_USER", Value: "coder-a"},
+			corev1.EnvVar{Name: "SWARM_BUS_INBOX", Value: "swarm.swarm.agent.coder-a"},
+			corev1.EnvVar{Name: "SWARM_BUS_PEERS", Value: "swarm.swarm.agent.tester-a"},
+		))
+	})
+})
@@ -0,0 +1,288 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// namespaceOwnerAnnotation marks namespaces created by the operator with
+	// the <namespace>/<name> of the SwarmCluster that owns them. Namespaces
+	// without it existed before and are never deleted.
+	namespaceOwnerAnnotation = "swarm.claudeflow.io/owner"
+
+	// namespaceComponentLabel tells swarm and hive-mind namespaces apart
+	namespaceComponentLabel = "swarm.claudeflow.io/component"
+)
+
+// swarmNamespaces lists the distinct namespaces of the cluster's swarm and
+// hive-mind components
+func (r *SwarmClusterReconciler) swarmNamespaces(cluster *swarmv1alpha1.SwarmCluster) map[string]string {
+	namespaces := map[string]string{}
+	for _, component := range []string{"hivemind", "swarm"} {
+		if ns := r.getNamespaceForComponent(cluster, component); ns != "" {
+			namespaces[ns] = component
+		}
+	}
+	return namespaces
+}
+
+// namespaceOwner is the value of namespaceOwnerAnnotation for the cluster
+func namespaceOwner(cluster *swarmv1alpha1.SwarmCluster) string {
+	return cluster.Namespace + "/" + cluster.Name
+}
+
+// reconcileNamespaces creates and labels the swarm and hive-mind
+// namespaces and applies the quota, limit range and network policy
+// templates to them
+func (r *SwarmClusterReconciler) reconcileNamespaces(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	config := cluster.Spec.NamespaceConfig
+	if config == nil || !config.CreateNamespaces {
+		return nil
+	}
+
+	namespaces := r.swarmNamespaces(cluster)
+	for ns, component := range namespaces {
+		// The cluster's own namespace is the user's, not the operator's
+		if ns == cluster.Namespace {
+			continue
+		}
+		if err := r.ensureNamespace(ctx, cluster, ns, component); err != nil {
+			return fmt.Errorf("namespace %s: %w", ns, err)
+		}
+		if err := r.applyNamespacePolicies(ctx, cluster, ns, namespaces); err != nil {
+			return fmt.Errorf("namespace %s: %w", ns, err)
+		}
+	}
+	return nil
+}
+
+// ensureNamespace creates a missing namespace and keeps its labels current
+func (r *SwarmClusterReconciler) ensureNamespace(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, name, component string) error {
+	log := log.FromContext(ctx)
+
+	labels := map[string]string{}
+	for key, value := range cluster.Spec.NamespaceConfig.Labels {
+		labels[key] = value
+	}
+	labels["app.kubernetes.io/managed-by"] = "swarm-operator"
+	labels["app.kubernetes.io/part-of"] = "claude-flow"
+	labels[namespaceComponentLabel] = component
+
+	namespace := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: name}, namespace)
+	if errors.IsNotFound(err) {
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      labels,
+				Annotations: map[string]string{namespaceOwnerAnnotation: namespaceOwner(cluster)},
+			},
+		}
+		log.Info("Creating namespace", "namespace", name)
+		if err := r.Create(ctx, namespace); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "NamespaceCreated", "Created namespace %s", name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if namespace.Status.Phase == corev1.NamespaceTerminating {
+		return fmt.Errorf("namespace is terminating")
+	}
+
+	changed := false
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	for key, value := range labels {
+		if namespace.Labels[key] != value {
+			namespace.Labels[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.Update(ctx, namespace)
+}
+
+// applyNamespacePolicies creates, updates or removes the cluster's
+// ResourceQuota, LimitRange and NetworkPolicy in a namespace
+func (r *SwarmClusterReconciler) applyNamespacePolicies(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, ns string, namespaces map[string]string) error {
+	config := cluster.Spec.NamespaceConfig
+	labels := map[string]string{"swarm.claudeflow.io/cluster": cluster.Name}
+
+	quota := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-quota", Namespace: ns}}
+	if config.ResourceQuota != nil {
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, quota, func() error {
+			quota.Labels = labels
+			quota.Spec = *config.ResourceQuota.DeepCopy()
+			return nil
+		}); err != nil {
+			return err
+		}
+	} else if err := r.deleteIfExists(ctx, quota); err != nil {
+		return err
+	}
+
+	limitRange := &corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-limits", Namespace: ns}}
+	if config.LimitRange != nil {
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, limitRange, func() error {
+			limitRange.Labels = labels
+			limitRange.Spec = *config.LimitRange.DeepCopy()
+			return nil
+		}); err != nil {
+			return err
+		}
+	} else if err := r.deleteIfExists(ctx, limitRange); err != nil {
+		return err
+	}
+
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-isolation", Namespace: ns}}
+	if config.NetworkIsolation {
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+			policy.Labels = labels
+			policy.Spec = isolationPolicySpec(cluster, namespaces)
+			return nil
+		}); err != nil {
+			return err
+		}
+	} else if err := r.deleteIfExists(ctx, policy); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isolationPolicySpec admits ingress to all pods of a namespace only from
+// the cluster's namespaces and the allowed namespaces
+func isolationPolicySpec(cluster *swarmv1alpha1.SwarmCluster, namespaces map[string]string) networkingv1.NetworkPolicySpec {
+	allowed := []string{cluster.Namespace}
+	for ns := range namespaces {
+		allowed = append(allowed, ns)
+	}
+	allowed = append(allowed, cluster.Spec.NamespaceConfig.AllowedNamespaces...)
+
+	var unique []string
+	for _, ns := range allowed {
+		if !containsString(unique, ns) {
+			unique = append(unique, ns)
+		}
+	}
+	sort.Strings(unique)
+
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      corev1.LabelMetadataName,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   unique,
+					}},
+				},
+			}},
+		}},
+	}
+}
+
+// deleteIfExists deletes an object, ignoring that it is already gone
+func (r *SwarmClusterReconciler) deleteIfExists(ctx context.Context, obj client.Object) error {
+	if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// cleanupNamespaces deletes the namespaces the cluster created, unless
+// another cluster still uses them, and removes the cluster's policies from
+// namespaces it does not own
+func (r *SwarmClusterReconciler) cleanupNamespaces(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	log := log.FromContext(ctx)
+
+	config := cluster.Spec.NamespaceConfig
+	if config == nil || !config.CreateNamespaces {
+		return nil
+	}
+
+	clusters := &swarmv1alpha1.SwarmClusterList{}
+	if err := r.List(ctx, clusters); err != nil {
+		return err
+	}
+	inUse := map[string]bool{}
+	for i := range clusters.Items {
+		other := &clusters.Items[i]
+		if other.UID == cluster.UID || other.DeletionTimestamp != nil {
+			continue
+		}
+		inUse[other.Namespace] = true
+		for ns := range r.swarmNamespaces(other) {
+			inUse[ns] = true
+		}
+	}
+
+	for ns := range r.swarmNamespaces(cluster) {
+		if ns == cluster.Namespace {
+			continue
+		}
+
+		namespace := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ns}, namespace); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if namespace.Annotations[namespaceOwnerAnnotation] == namespaceOwner(cluster) && !inUse[ns] {
+			log.Info("Deleting namespace", "namespace", ns)
+			if err := r.deleteIfExists(ctx, namespace); err != nil {
+				return err
+			}
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "NamespaceDeleted", "Deleted namespace %s", ns)
+			continue
+		}
+
+		for _, obj := range []client.Object{
+			&corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-quota", Namespace: ns}},
+			&corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-limits", Namespace: ns}},
+			&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-isolation", Namespace: ns}},
+		} {
+			if err := r.deleteIfExists(ctx, obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Namespace provisioning", func() {
+	var (
+		ctx        context.Context
+		k8sClient  client.Client
+		reconciler *SwarmClusterReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+	)
+
+	namespace := func(name string) (*corev1.Namespace, error) {
+		ns := &corev1.Namespace{}
+		return ns, k8sClient.Get(ctx, types.NamespacedName{Name: name}, ns)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default", UID: "uid"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				NamespaceConfig: &swarmv1alpha1.NamespaceConfig{
+					SwarmNamespace:    "team-swarm",
+					HiveMindNamespace: "team-hivemind",
+					AllowedNamespaces: []string{"swarm-system"},
+					CreateNamespaces:  true,
+					Labels:            map[string]string{"team": "research"},
+					ResourceQuota: &corev1.ResourceQuotaSpec{
+						Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("20")},
+					},
+					NetworkIsolation: true,
+				},
+			},
+		}
+		existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-hivemind"}}
+		k8sClient = newTestClient(cluster, existing)
+		reconciler = &SwarmClusterReconciler{
+			Client:   k8sClient,
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+	})
+
+	It("creates missing namespaces and labels existing ones", func() {
+		Expect(reconciler.reconcileNamespaces(ctx, cluster)).To(Succeed())
+
+		created, err := namespace("team-swarm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Annotations).To(HaveKeyWithValue(namespaceOwnerAnnotation, "default/swarm"))
+		Expect(created.Labels).To(HaveKeyWithValue("team", "research"))
+		Expect(created.Labels).To(HaveKeyWithValue(namespaceComponentLabel, "swarm"))
+
+		existing, err := namespace("team-hivemind")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(existing.Annotations).NotTo(HaveKey(namespaceOwnerAnnotation))
+		Expect(existing.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "swarm-operator"))
+	})
+
+	It("applies the quota and isolation templates", func() {
+		Expect(reconciler.reconcileNamespaces(ctx, cluster)).To(Succeed())
+
+		quota := &corev1.ResourceQuota{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "swarm-quota", Namespace: "team-swarm"}, quota)).To(Succeed())
+		Expect(quota.Spec.Hard).To(HaveKey(corev1.ResourcePods))
+
+		policy := &networkingv1.NetworkPolicy{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "swarm-isolation", Namespace: "team-hivemind"}, policy)).To(Succeed())
+		Expect(policy.Spec.Ingress[0].From[0].NamespaceSelector.MatchExpressions[0].Values).To(
+			Equal([]string{"default", "swarm-system", "team-hivemind", "team-swarm"}))
+
+		limits := &corev1.LimitRange{}
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: "swarm-limits", Namespace: "team-swarm"}, limits)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("only deletes namespaces the cluster created", func() {
+		Expect(reconciler.reconcileNamespaces(ctx, cluster)).To(Succeed())
+		Expect(reconciler.cleanupNamespaces(ctx, cluster)).To(Succeed())
+
+		_, err := namespace("team-swarm")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		_, err = namespace("team-hivemind")
+		Expect(err).NotTo(HaveOccurred())
+
+		policy := &networkingv1.NetworkPolicy{}
+		err = k8sClient.Get(ctx, types.NamespacedName{Name: "swarm-isolation", Namespace: "team-hivemind"}, policy)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("keeps namespaces another cluster still uses", func() {
+		Expect(reconciler.reconcileNamespaces(ctx, cluster)).To(Succeed())
+		other := &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				NamespaceConfig: &swarmv1alpha1.NamespaceConfig{SwarmNamespace: "team-swarm"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, other)).To(Succeed())
+
+		Expect(reconciler.cleanupNamespaces(ctx, cluster)).To(Succeed())
+		_, err := namespace("team-swarm")
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,173 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// Built-in defaults for fields set by neither the cluster nor its profile
+	defaultSwarmTopology       = swarmv1alpha1.MeshTopology
+	defaultSwarmMaxAgents      = 5
+	defaultSwarmMinAgents      = 1
+	defaultSwarmStrategy       = "balanced"
+	defaultAgentDeploymentMode = swarmv1alpha1.PerAgentDeploymentMode
+
+	// ReasonProfileNotFound is set while the referenced SwarmProfile is missing
+	ReasonProfileNotFound = "ProfileNotFound"
+)
+
+// resolveSwarmProfile expands the cluster's profile and the built-in
+// defaults into the in-memory spec. The result is never written back, so
+// editing a profile reaches every cluster using it on the next reconcile.
+func resolveSwarmProfile(ctx context.Context, c client.Reader, cluster *swarmv1alpha1.SwarmCluster) error {
+	// Tenant clusters naming no profile use their tenant's. A missing
+	// tenant is reported by the tenant admission check instead.
+	if cluster.Spec.Profile == "" {
+		tenant, err := getTenant(ctx, c, cluster.Labels[tenantLabel])
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if tenant != nil {
+			cluster.Spec.Profile = tenant.Spec.Profile
+		}
+	}
+	if cluster.Spec.Profile != "" {
+		profile := &swarmv1alpha1.SwarmProfile{}
+		if err := c.Get(ctx, types.NamespacedName{Name: cluster.Spec.Profile}, profile); err != nil {
+			return err
+		}
+		mergeProfile(&cluster.Spec, &profile.Spec)
+	}
+	applyClusterDefaults(&cluster.Spec)
+	return nil
+}
+
+// mergeProfile fills the fields the cluster leaves unset from the profile.
+// Structured sections are taken from the profile as a whole, explicit
+// cluster sections always win.
+func mergeProfile(spec *swarmv1alpha1.SwarmClusterSpec, profile *swarmv1alpha1.SwarmProfileSpec) {
+	if spec.Topology == "" {
+		spec.Topology = profile.Topology
+	}
+	if spec.CustomTopology == nil && profile.CustomTopology != nil {
+		spec.CustomTopology = profile.CustomTopology.DeepCopy()
+	}
+	if spec.MinAgents == 0 {
+		spec.MinAgents = profile.MinAgents
+	}
+	if spec.MaxAgents == 0 {
+		spec.MaxAgents = profile.MaxAgents
+	}
+	if spec.Strategy == "" {
+		spec.Strategy = profile.Strategy
+	}
+	if spec.AgentDeploymentMode == "" {
+		spec.AgentDeploymentMode = profile.AgentDeploymentMode
+	}
+	if profile.AgentTemplate != nil && equality.Semantic.DeepEqual(spec.AgentTemplate, swarmv1alpha1.AgentTemplateSpec{}) {
+		spec.AgentTemplate = *profile.AgentTemplate.DeepCopy()
+	}
+	if profile.TaskDistribution != nil && equality.Semantic.DeepEqual(spec.TaskDistribution, swarmv1alpha1.TaskDistributionSpec{}) {
+		spec.TaskDistribution = *profile.TaskDistribution.DeepCopy()
+	}
+	if spec.AutoScaling == nil && profile.AutoScaling != nil {
+		spec.AutoScaling = profile.AutoScaling.DeepCopy()
+	}
+	if profile.Memory != nil && equality.Semantic.DeepEqual(spec.Memory, swarmv1alpha1.MemorySpec{}) {
+		spec.Memory = *profile.Memory.DeepCopy()
+	}
+	if spec.Monitoring == nil && profile.Monitoring != nil {
+		spec.Monitoring = profile.Monitoring.DeepCopy()
+	}
+	if spec.TLS == nil && profile.TLS != nil {
+		spec.TLS = profile.TLS.DeepCopy()
+	}
+}
+
+// applyClusterDefaults sets the built-in defaults that used to be applied
+// by the API server, now that a profile may provide them instead
+func applyClusterDefaults(spec *swarmv1alpha1.SwarmClusterSpec) {
+	if spec.Topology == "" {
+		spec.Topology = defaultSwarmTopology
+	}
+	if spec.MaxAgents == 0 {
+		spec.MaxAgents = defaultSwarmMaxAgents
+	}
+	if spec.MinAgents == 0 {
+		spec.MinAgents = defaultSwarmMinAgents
+	}
+	if spec.Strategy == "" {
+		spec.Strategy = defaultSwarmStrategy
+	}
+	if spec.AgentDeploymentMode == "" {
+		spec.AgentDeploymentMode = defaultAgentDeploymentMode
+	}
+}
+
+// monitoringAnnotations returns the Prometheus scrape annotations for agent
+// pods of clusters with monitoring enabled
+func monitoringAnnotations(cluster *swarmv1alpha1.SwarmCluster) map[string]string {
+	monitoring := cluster.Spec.Monitoring
+	if monitoring == nil || !monitoring.Enabled {
+		return nil
+	}
+	return map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   strconv.Itoa(int(monitoringMetricsPort(monitoring))),
+		"prometheus.io/path":   monitoringMetricsPath(monitoring),
+	}
+}
+
+// mapProfileToClusters enqueues every SwarmCluster using a SwarmProfile
+func (r *SwarmClusterReconciler) mapProfileToClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusterList := &swarmv1alpha1.SwarmClusterList{}
+	if err := r.List(ctx, clusterList); err != nil {
+		return nil
+	}
+	// Clusters naming no profile inherit their tenant's
+	tenantProfiles := map[string]string{}
+	tenantList := &swarmv1alpha1.SwarmTenantList{}
+	if err := r.List(ctx, tenantList); err == nil {
+		for _, tenant := range tenantList.Items {
+			tenantProfiles[tenant.Name] = tenant.Spec.Profile
+		}
+	}
+	var requests []reconcile.Request
+	for _, cluster := range clusterList.Items {
+		profile := cluster.Spec.Profile
+		if profile == "" {
+			profile = tenantProfiles[cluster.Labels[tenantLabel]]
+		}
+		if profile == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+			})
+		}
+	}
+	return requests
+}
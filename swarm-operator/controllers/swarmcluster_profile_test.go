@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("SwarmProfile", func() {
+	var profile *swarmv1alpha1.SwarmProfileSpec
+
+	BeforeEach(func() {
+		profile = &swarmv1alpha1.SwarmProfileSpec{
+			Topology:            swarmv1alpha1.HierarchicalTopology,
+			MinAgents:           2,
+			MaxAgents:           10,
+			Strategy:            "specialized",
+			AgentDeploymentMode: swarmv1alpha1.PooledDeploymentMode,
+			AgentTemplate: &swarmv1alpha1.AgentTemplateSpec{
+				Resources: swarmv1alpha1.ResourceRequirements{CPU: "4", GPU: "1"},
+			},
+			TaskDistribution: &swarmv1alpha1.TaskDistributionSpec{Algorithm: "least-loaded", MaxTasksPerAgent: 1},
+			AutoScaling: &swarmv1alpha1.AutoScalingSpec{
+				Enabled:        true,
+				TopologyRatios: map[string]swarmv1alpha1.AgentTypeScaling{"researcher": {MaxAgents: 6}},
+			},
+			Memory:     &swarmv1alpha1.MemorySpec{Type: "sqlite", EnableMemoryStore: true},
+			Monitoring: &swarmv1alpha1.MonitoringSpec{Enabled: true},
+		}
+	})
+
+	It("fills an empty spec from the profile", func() {
+		spec := &swarmv1alpha1.SwarmClusterSpec{Profile: "research"}
+		mergeProfile(spec, profile)
+
+		Expect(spec.Topology).To(Equal(swarmv1alpha1.HierarchicalTopology))
+		Expect(spec.MinAgents).To(Equal(int32(2)))
+		Expect(spec.MaxAgents).To(Equal(int32(10)))
+		Expect(spec.Strategy).To(Equal("specialized"))
+		Expect(spec.AgentDeploymentMode).To(Equal(swarmv1alpha1.PooledDeploymentMode))
+		Expect(spec.AgentTemplate.Resources.GPU).To(Equal("1"))
+		Expect(spec.TaskDistribution.Algorithm).To(Equal("least-loaded"))
+		Expect(spec.AutoScaling.TopologyRatios).To(HaveKey("researcher"))
+		Expect(spec.Memory.Type).To(Equal("sqlite"))
+		Expect(spec.Monitoring.Enabled).To(BeTrue())
+	})
+
+	It("keeps explicit cluster fields over the profile", func() {
+		spec := &swarmv1alpha1.SwarmClusterSpec{
+			Topology:         swarmv1alpha1.MeshTopology,
+			MaxAgents:        3,
+			TaskDistribution: swarmv1alpha1.TaskDistributionSpec{Algorithm: "round-robin"},
+			Memory:           swarmv1alpha1.MemorySpec{Type: "redis"},
+		}
+		mergeProfile(spec, profile)
+
+		Expect(spec.Topology).To(Equal(swarmv1alpha1.MeshTopology))
+		Expect(spec.MaxAgents).To(Equal(int32(3)))
+		Expect(spec.MinAgents).To(Equal(int32(2)))
+		Expect(spec.TaskDistribution.Algorithm).To(Equal("round-robin"))
+		Expect(spec.TaskDistribution.MaxTasksPerAgent).To(BeZero())
+		Expect(spec.Memory.Type).To(Equal("redis"))
+		Expect(spec.Memory.EnableMemoryStore).To(BeFalse())
+	})
+
+	It("does not share profile sections with the cluster", func() {
+		spec := &swarmv1alpha1.SwarmClusterSpec{}
+		mergeProfile(spec, profile)
+		spec.AutoScaling.TopologyRatios["coder"] = swarmv1alpha1.AgentTypeScaling{MaxAgents: 1}
+
+		Expect(profile.AutoScaling.TopologyRatios).NotTo(HaveKey("coder"))
+	})
+
+	It("applies the built-in defaults last", func() {
+		spec := &swarmv1alpha1.SwarmClusterSpec{}
+		mergeProfile(spec, &swarmv1alpha1.SwarmProfileSpec{MaxAgents: 8})
+		applyClusterDefaults(spec)
+
+		Expect(spec.Topology).To(Equal(swarmv1alpha1.MeshTopology))
+		Expect(spec.MaxAgents).To(Equal(int32(8)))
+		Expect(spec.MinAgents).To(Equal(int32(1)))
+		Expect(spec.Strategy).To(Equal("balanced"))
+		Expect(spec.AgentDeploymentMode).To(Equal(swarmv1alpha1.PerAgentDeploymentMode))
+	})
+
+	It("annotates agent pods for scraping when monitoring is enabled", func() {
+		cluster := &swarmv1alpha1.SwarmCluster{}
+		Expect(monitoringAnnotations(cluster)).To(BeNil())
+
+		cluster.Spec.Monitoring = &swarmv1alpha1.MonitoringSpec{Enabled: true, MetricsPort: 9100}
+		Expect(monitoringAnnotations(cluster)).To(Equal(map[string]string{
+			"prometheus.io/scrape": "true",
+			"prometheus.io/port":   "9100",
+			"prometheus.io/path":   "/metrics",
+		}))
+	})
+})
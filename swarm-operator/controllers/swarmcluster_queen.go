@@ -0,0 +1,370 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeQueenReady reports whether the queen of a centralized
+	// swarm runs
+	ConditionTypeQueenReady = "QueenReady"
+
+	ReasonQueenReady       = "QueenReady"
+	ReasonQueenNotReady    = "QueenNotReady"
+	ReasonQueenElected     = "QueenElected"
+	ReasonQueenFailover    = "QueenFailover"
+	ReasonNoQueenCandidate = "NoQueenCandidate"
+
+	// queenPriorityClassName is the PriorityClass of queens that set none
+	queenPriorityClassName = "swarm-queen"
+
+	// queenPriorityValue puts the queen above critical tasks and below
+	// the system classes
+	queenPriorityValue = 2000000
+
+	defaultQueenFailoverAfter = 30 * time.Second
+)
+
+// centralizedQueen reports whether the cluster is coordinated by a queen
+func centralizedQueen(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.QueenMode == swarmv1alpha1.CentralizedQueenMode
+}
+
+// isQueen reports whether the agent is the elected queen
+func isQueen(agent *swarmv1alpha1.Agent) bool {
+	return agent.Labels[swarmv1alpha1.QueenLabel] == "true"
+}
+
+// queenFailoverAfter returns how long the queen may stay NotReady
+func queenFailoverAfter(cluster *swarmv1alpha1.SwarmCluster) time.Duration {
+	if cluster.Spec.Queen == nil {
+		return defaultQueenFailoverAfter
+	}
+	return parseDurationOrDefault(cluster.Spec.Queen.FailoverAfter, defaultQueenFailoverAfter)
+}
+
+// applyQueenReservation gives the queen pod Guaranteed QoS, its
+// PriorityClass and the dedicated node pool. Containers are limited to
+// what they request; sidecars that request nothing keep the pod Burstable.
+func applyQueenReservation(cluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) error {
+	spec := cluster.Spec.Queen
+	if spec == nil {
+		spec = &swarmv1alpha1.QueenSpec{}
+	}
+	res := spec.Resources
+	template := agentTypeResources(cluster, swarmv1alpha1.CoordinatorAgent)
+	if res.CPU == "" {
+		res.CPU = template.CPU
+	}
+	if res.CPU == "" {
+		res.CPU = "1"
+	}
+	if res.Memory == "" {
+		res.Memory = template.Memory
+	}
+	if res.Memory == "" {
+		res.Memory = "1Gi"
+	}
+	if res.GPU == "" {
+		res.GPU = template.GPU
+	}
+	resources, err := agentResourceRequirements(res)
+	if err != nil {
+		return fmt.Errorf("invalid queen resources for SwarmCluster %s: %w", cluster.Name, err)
+	}
+
+	guarantee := func(containers []corev1.Container) {
+		for i := range containers {
+			c := &containers[i]
+			if c.Name == agentContainerName {
+				c.Resources = resources
+			}
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				if request, ok := c.Resources.Requests[name]; ok {
+					if c.Resources.Limits == nil {
+						c.Resources.Limits = corev1.ResourceList{}
+					}
+					c.Resources.Limits[name] = request
+				}
+			}
+		}
+	}
+	guarantee(podSpec.InitContainers)
+	guarantee(podSpec.Containers)
+
+	podSpec.PriorityClassName = spec.PriorityClassName
+	if podSpec.PriorityClassName == "" {
+		podSpec.PriorityClassName = queenPriorityClassName
+	}
+	if len(spec.NodeSelector) > 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		for k, v := range spec.NodeSelector {
+			podSpec.NodeSelector[k] = v
+		}
+	}
+	for _, t := range spec.Tolerations {
+		podSpec.Tolerations = append(podSpec.Tolerations, *t.DeepCopy())
+	}
+	return nil
+}
+
+// reconcileQueen elects the queen of a centralized swarm and elects another
+// coordinator agent when the queen's pod stays NotReady past the failover
+// delay or the queen is drained. Swarms leaving centralized mode demote
+// their queen.
+func (r *SwarmClusterReconciler) reconcileQueen(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent) error {
+	log := log.FromContext(ctx)
+
+	if !centralizedQueen(cluster) {
+		for i := range agents {
+			if isQueen(&agents[i]) {
+				if err := r.setQueenLabel(ctx, &agents[i], false); err != nil {
+					return err
+				}
+			}
+		}
+		cluster.Status.Queen = nil
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeQueenReady)
+		return nil
+	}
+
+	if cluster.Spec.Queen == nil || cluster.Spec.Queen.PriorityClassName == "" {
+		if err := r.ensureQueenPriorityClass(ctx); err != nil {
+			return err
+		}
+	}
+
+	condition := metav1.Condition{Type: ConditionTypeQueenReady, ObservedGeneration: cluster.Generation}
+	var queen *swarmv1alpha1.Agent
+	if cluster.Status.Queen != nil {
+		for i := range agents {
+			if agents[i].Name == cluster.Status.Queen.Agent {
+				queen = &agents[i]
+			}
+		}
+	}
+
+	now := time.Now()
+	reason := ""
+	if queen != nil {
+		ready, since, err := r.agentPodReadiness(ctx, queen)
+		if err != nil {
+			return err
+		}
+		// A new queen gets the failover delay to restart with its reservation
+		if elected := cluster.Status.Queen.ElectedTime.Time; since.Before(elected) {
+			since = elected
+		}
+		switch {
+		case agentDraining(queen):
+			reason = fmt.Sprintf("queen %s is draining", queen.Name)
+		case ready:
+			if err := r.setQueenLabel(ctx, queen, true); err != nil {
+				return err
+			}
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = ReasonQueenReady
+			condition.Message = fmt.Sprintf("Agent %s is the queen", queen.Name)
+			meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+			return nil
+		// A queen restarting with the peers of a new topology keeps its
+		// election
+		case now.Sub(since) < queenFailoverAfter(cluster) || restartingForTopology(cluster, queen.Name):
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = ReasonQueenNotReady
+			condition.Message = fmt.Sprintf("The pod of queen %s is not ready", queen.Name)
+			meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+			return nil
+		default:
+			reason = fmt.Sprintf("the pod of queen %s was not ready for %s", queen.Name, now.Sub(since).Round(time.Second))
+		}
+	}
+
+	candidate, err := r.electQueen(ctx, agents, queen)
+	if err != nil {
+		return err
+	}
+	if candidate == nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonNoQueenCandidate
+		condition.Message = "No coordinator agent can be elected queen"
+		if reason != "" {
+			condition.Message = fmt.Sprintf("No ready coordinator agent can take over: %s", reason)
+		}
+		if meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, ReasonNoQueenCandidate, condition.Message)
+		}
+		return nil
+	}
+
+	failovers := int32(0)
+	if cluster.Status.Queen != nil {
+		failovers = cluster.Status.Queen.Failovers
+	}
+	if queen != nil {
+		if err := r.setQueenLabel(ctx, queen, false); err != nil {
+			return err
+		}
+		failovers++
+		log.Info("Queen failover", "queen", queen.Name, "elected", candidate.Name, "reason", reason)
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, ReasonQueenFailover, "Elected %s queen: %s", candidate.Name, reason)
+	} else {
+		log.Info("Elected queen", "queen", candidate.Name)
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, ReasonQueenElected, "Elected %s queen", candidate.Name)
+	}
+	if err := r.setQueenLabel(ctx, candidate, true); err != nil {
+		return err
+	}
+	cluster.Status.Queen = &swarmv1alpha1.QueenStatus{
+		Agent:       candidate.Name,
+		ElectedTime: metav1.NewTime(now),
+		Failovers:   failovers,
+	}
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = ReasonQueenElected
+	condition.Message = fmt.Sprintf("Agent %s was elected queen and is starting", candidate.Name)
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+
+	// Route the topology through the new queen
+	return r.setupTopology(ctx, cluster, agents)
+}
+
+// electQueen picks the coordinator agent to become queen, the first by name
+// whose pod is ready. Without a previous queen, e.g. while the swarm
+// starts, a coordinator whose pod is not ready yet is elected as well.
+func (r *SwarmClusterReconciler) electQueen(ctx context.Context, agents []swarmv1alpha1.Agent, previous *swarmv1alpha1.Agent) (*swarmv1alpha1.Agent, error) {
+	var candidates []*swarmv1alpha1.Agent
+	for i := range agents {
+		agent := &agents[i]
+		if agent.Spec.Type != swarmv1alpha1.CoordinatorAgent || agentDraining(agent) || agent.Status.Phase == "Failed" {
+			continue
+		}
+		// The queen runs in a pod the operator reserves resources for
+		if agent.Spec.External {
+			continue
+		}
+		if previous != nil && agent.Name == previous.Name {
+			continue
+		}
+		candidates = append(candidates, agent)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+
+	for _, agent := range candidates {
+		ready, _, err := r.agentPodReadiness(ctx, agent)
+		if err != nil {
+			return nil, err
+		}
+		if ready {
+			return agent, nil
+		}
+	}
+	if previous == nil && len(candidates) > 0 {
+		return candidates[0], nil
+	}
+	return nil, nil
+}
+
+// agentPodReadiness reports whether the pod of an agent is ready and, if
+// not, since when. The time is zero when the agent has no pod.
+func (r *SwarmClusterReconciler) agentPodReadiness(ctx context.Context, agent *swarmv1alpha1.Agent) (bool, time.Time, error) {
+	pods, err := agentPods(ctx, r, agent)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	var since time.Time
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if podReady(pod) {
+			return true, time.Time{}, nil
+		}
+		notReady := pod.CreationTimestamp.Time
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady {
+				notReady = c.LastTransitionTime.Time
+			}
+		}
+		if since.IsZero() || notReady.Before(since) {
+			since = notReady
+		}
+	}
+	return false, since, nil
+}
+
+// setQueenLabel labels the agent queen or removes the label
+func (r *SwarmClusterReconciler) setQueenLabel(ctx context.Context, agent *swarmv1alpha1.Agent, queen bool) error {
+	if isQueen(agent) == queen {
+		return nil
+	}
+	if queen {
+		if agent.Labels == nil {
+			agent.Labels = map[string]string{}
+		}
+		agent.Labels[swarmv1alpha1.QueenLabel] = "true"
+	} else {
+		delete(agent.Labels, swarmv1alpha1.QueenLabel)
+	}
+	return r.Update(ctx, agent)
+}
+
+// ensureQueenPriorityClass creates the default PriorityClass of queens. It
+// preempts lower priority pods, so a queen rescheduled onto a full node
+// evicts task pods rather than waiting.
+func (r *SwarmClusterReconciler) ensureQueenPriorityClass(ctx context.Context) error {
+	err := r.Get(ctx, types.NamespacedName{Name: queenPriorityClassName}, &schedulingv1.PriorityClass{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+	policy := corev1.PreemptLowerPriority
+	class := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: queenPriorityClassName,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "swarm-operator",
+			},
+		},
+		Value:            queenPriorityValue,
+		PreemptionPolicy: &policy,
+		Description:      "Queen agents of centralized swarms",
+	}
+	log.FromContext(ctx).Info("Creating PriorityClass", "name", queenPriorityClassName)
+	if err := r.Create(ctx, class); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Queen", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	agent := func(name string, agentType swarmv1alpha1.AgentType) *swarmv1alpha1.Agent {
+		return &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"swarm-cluster": "swarm"}},
+			Spec:       swarmv1alpha1.AgentSpec{Type: agentType, SwarmCluster: "swarm"},
+			Status:     swarmv1alpha1.AgentStatus{Phase: "Ready"},
+		}
+	}
+	pod := func(agentName string, ready bool, since time.Time) *corev1.Pod {
+		status := corev1.ConditionTrue
+		if !ready {
+			status = corev1.ConditionFalse
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      agentName + "-pod",
+				Namespace: "default",
+				Labels:    map[string]string{"swarm.claudeflow.io/agent": agentName},
+			},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+				Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(since),
+			}}},
+		}
+	}
+	agents := func() []swarmv1alpha1.Agent {
+		list := &swarmv1alpha1.AgentList{}
+		Expect(reconciler.List(ctx, list)).To(Succeed())
+		return list.Items
+	}
+	queenLabelled := func(name string) bool {
+		a := &swarmv1alpha1.Agent{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, a)).To(Succeed())
+		return isQueen(a)
+	}
+
+	setup := func(objects ...runtime.Object) {
+		reconciler = &SwarmClusterReconciler{
+			Client:   newTestClientBuilder().WithRuntimeObjects(objects...).Build(),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Topology:  swarmv1alpha1.StarTopology,
+				QueenMode: swarmv1alpha1.CentralizedQueenMode,
+			},
+		}
+	})
+
+	It("reserves Guaranteed resources, priority and a node pool for the queen", func() {
+		cluster.Spec.Queen = &swarmv1alpha1.QueenSpec{
+			Resources:    swarmv1alpha1.ResourceRequirements{CPU: "2", Memory: "4Gi"},
+			NodeSelector: map[string]string{"pool": "queen"},
+			Tolerations:  []corev1.Toleration{{Key: "dedicated", Value: "queen", Effect: corev1.TaintEffectNoSchedule}},
+		}
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{
+			{Name: agentContainerName},
+			{Name: "proxy", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}},
+		}}
+		Expect(applyQueenReservation(cluster, podSpec)).To(Succeed())
+
+		agentResources := podSpec.Containers[0].Resources
+		Expect(agentResources.Limits).To(Equal(agentResources.Requests))
+		Expect(agentResources.Requests.Cpu().String()).To(Equal("2"))
+		Expect(agentResources.Requests.Memory().String()).To(Equal("4Gi"))
+		Expect(podSpec.Containers[1].Resources.Limits.Cpu().String()).To(Equal("100m"))
+		Expect(podSpec.PriorityClassName).To(Equal(queenPriorityClassName))
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("pool", "queen"))
+		Expect(podSpec.Tolerations).To(HaveLen(1))
+	})
+
+	It("elects the first coordinator while the swarm starts", func() {
+		setup(agent("swarm-coordinator-1", swarmv1alpha1.CoordinatorAgent),
+			agent("swarm-coordinator-0", swarmv1alpha1.CoordinatorAgent),
+			agent("swarm-coder-2", swarmv1alpha1.CoderAgent))
+
+		Expect(reconciler.reconcileQueen(ctx, cluster, agents())).To(Succeed())
+		Expect(cluster.Status.Queen.Agent).To(Equal("swarm-coordinator-0"))
+		Expect(queenLabelled("swarm-coordinator-0")).To(BeTrue())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeQueenReady).Reason).To(Equal(ReasonQueenElected))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: queenPriorityClassName}, &schedulingv1.PriorityClass{})).To(Succeed())
+
+		// The spokes of the star connect to the queen
+		coder := &swarmv1alpha1.Agent{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-coder-2", Namespace: "default"}, coder)).To(Succeed())
+		Expect(coder.Spec.CommunicationEndpoints.Peers).To(ConsistOf(ContainSubstring("swarm-coordinator-0")))
+	})
+
+	It("elects a ready coordinator when the queen stays NotReady", func() {
+		queen := agent("swarm-coordinator-0", swarmv1alpha1.CoordinatorAgent)
+		queen.Labels[swarmv1alpha1.QueenLabel] = "true"
+		setup(queen, agent("swarm-coordinator-1", swarmv1alpha1.CoordinatorAgent),
+			pod("swarm-coordinator-0", false, time.Now().Add(-time.Minute)),
+			pod("swarm-coordinator-1", true, time.Now().Add(-time.Hour)))
+		cluster.Status.Queen = &swarmv1alpha1.QueenStatus{Agent: "swarm-coordinator-0", ElectedTime: metav1.NewTime(time.Now().Add(-time.Hour))}
+
+		Expect(reconciler.reconcileQueen(ctx, cluster, agents())).To(Succeed())
+		Expect(cluster.Status.Queen.Agent).To(Equal("swarm-coordinator-1"))
+		Expect(cluster.Status.Queen.Failovers).To(Equal(int32(1)))
+		Expect(queenLabelled("swarm-coordinator-0")).To(BeFalse())
+		Expect(queenLabelled("swarm-coordinator-1")).To(BeTrue())
+		Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(ReasonQueenFailover)))
+	})
+
+	It("waits out the failover delay and keeps a queen nobody can replace", func() {
+		queen := agent("swarm-coordinator-0", swarmv1alpha1.CoordinatorAgent)
+		queen.Labels[swarmv1alpha1.QueenLabel] = "true"
+		setup(queen, pod("swarm-coordinator-0", false, time.Now().Add(-10*time.Second)))
+		cluster.Status.Queen = &swarmv1alpha1.QueenStatus{Agent: "swarm-coordinator-0", ElectedTime: metav1.NewTime(time.Now().Add(-time.Hour))}
+
+		Expect(reconciler.reconcileQueen(ctx, cluster, agents())).To(Succeed())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeQueenReady).Reason).To(Equal(ReasonQueenNotReady))
+
+		cluster.Spec.Queen = &swarmv1alpha1.QueenSpec{FailoverAfter: "5s"}
+		Expect(reconciler.reconcileQueen(ctx, cluster, agents())).To(Succeed())
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeQueenReady).Reason).To(Equal(ReasonNoQueenCandidate))
+		Expect(cluster.Status.Queen.Agent).To(Equal("swarm-coordinator-0"))
+	})
+
+	It("demotes the queen when the swarm leaves centralized mode", func() {
+		queen := agent("swarm-coordinator-0", swarmv1alpha1.CoordinatorAgent)
+		queen.Labels[swarmv1alpha1.QueenLabel] = "true"
+		setup(queen)
+		cluster.Status.Queen = &swarmv1alpha1.QueenStatus{Agent: "swarm-coordinator-0"}
+		cluster.Spec.QueenMode = swarmv1alpha1.DistributedQueenMode
+
+		Expect(reconciler.reconcileQueen(ctx, cluster, agents())).To(Succeed())
+		Expect(cluster.Status.Queen).To(BeNil())
+		Expect(queenLabelled("swarm-coordinator-0")).To(BeFalse())
+	})
+})
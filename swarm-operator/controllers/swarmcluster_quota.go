@@ -0,0 +1,320 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/placement"
+)
+
+const (
+	// ConditionTypeScalingLimited reports whether the namespace quota or the
+	// free node capacity holds the swarm below the agents autoscaling wants
+	ConditionTypeScalingLimited = "ScalingLimited"
+
+	ReasonQuotaExhausted           = "QuotaExhausted"
+	ReasonInsufficientNodeCapacity = "InsufficientNodeCapacity"
+	ReasonWithinCapacity           = "WithinCapacity"
+)
+
+// scalingLimit is an agent type autoscaling could not grow as far as it
+// wanted
+type scalingLimit struct {
+	agentType swarmv1alpha1.AgentType
+	wanted    int
+	allowed   int
+	reason    string
+	limitedBy string
+}
+
+// quotaHeadroom is what is left of a ResourceQuota
+type quotaHeadroom struct {
+	name string
+	free corev1.ResourceList
+}
+
+// scalingHeadroom is the room left for new agents by the ResourceQuotas of
+// the cluster's namespace and the allocatable capacity of the nodes. Agents
+// fitted into it consume it, so agent types scaling up at once share it.
+type scalingHeadroom struct {
+	quotas    []quotaHeadroom
+	nodes     []corev1.Node
+	requested map[string]placement.Capacity
+}
+
+// scalingHeadroom collects the headroom for new agents of the cluster.
+// Scoped quotas only count some pods and are left to the API server.
+func (r *SwarmClusterReconciler) scalingHeadroom(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (*scalingHeadroom, error) {
+	headroom := &scalingHeadroom{requested: map[string]placement.Capacity{}}
+
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, quota := range quotas.Items {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		free := corev1.ResourceList{}
+		for name, hard := range quota.Status.Hard {
+			left := hard.DeepCopy()
+			if used, ok := quota.Status.Used[name]; ok {
+				left.Sub(used)
+			}
+			free[name] = left
+		}
+		headroom.quotas = append(headroom.quotas, quotaHeadroom{name: quota.Name, free: free})
+	}
+	sort.Slice(headroom.quotas, func(i, j int) bool { return headroom.quotas[i].name < headroom.quotas[j].name })
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	headroom.nodes = nodes.Items
+	if len(headroom.nodes) == 0 {
+		return headroom, nil
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods); err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podRequests := placement.PodRequests(&pod.Spec)
+		used := headroom.requested[pod.Spec.NodeName]
+		used.MilliCPU += podRequests.MilliCPU
+		used.Memory += podRequests.Memory
+		headroom.requested[pod.Spec.NodeName] = used
+	}
+	return headroom, nil
+}
+
+// fit returns how many of n agent pods with the spec the headroom admits,
+// with the reason and what limits them when that is fewer than n, and
+// consumes the headroom of the admitted pods. Without nodes in the cache
+// only the quotas are checked.
+func (h *scalingHeadroom) fit(podSpec *corev1.PodSpec, n int) (int, string, string) {
+	allowed, reason, limitedBy := n, "", ""
+	usage := podQuotaUsage(podSpec)
+	for _, quota := range h.quotas {
+		names := make([]string, 0, len(quota.free))
+		for name := range quota.free {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			use, ok := usage[corev1.ResourceName(name)]
+			if !ok || use.IsZero() {
+				continue
+			}
+			free := quota.free[corev1.ResourceName(name)]
+			fits := int(free.MilliValue() / use.MilliValue())
+			if fits < 0 {
+				fits = 0
+			}
+			if fits < allowed {
+				allowed = fits
+				reason = ReasonQuotaExhausted
+				limitedBy = fmt.Sprintf("ResourceQuota %s (%s)", quota.name, name)
+			}
+		}
+	}
+
+	if len(h.nodes) > 0 && allowed > 0 {
+		var nodes []placement.Node
+		for i := range h.nodes {
+			node := &h.nodes[i]
+			if !placement.Schedulable(node, podSpec) {
+				continue
+			}
+			nodes = append(nodes, placement.Node{
+				Name: node.Name,
+				Allocatable: placement.Capacity{
+					MilliCPU: node.Status.Allocatable.Cpu().MilliValue(),
+					Memory:   node.Status.Allocatable.Memory().Value(),
+				},
+				Requested: h.requested[node.Name],
+			})
+		}
+		fits, placed := placement.Fit(nodes, placement.PodRequests(podSpec), allowed)
+		if fits < allowed {
+			allowed = fits
+			reason = ReasonInsufficientNodeCapacity
+			limitedBy = "node capacity"
+		}
+		for _, node := range placed {
+			h.requested[node.Name] = node.Requested
+		}
+	}
+
+	for _, quota := range h.quotas {
+		for name, use := range usage {
+			free, ok := quota.free[name]
+			if !ok {
+				continue
+			}
+			free.Sub(*resource.NewMilliQuantity(use.MilliValue()*int64(allowed), use.Format))
+			quota.free[name] = free
+		}
+	}
+	return allowed, reason, limitedBy
+}
+
+// podQuotaUsage is what a pod with the spec counts against a ResourceQuota:
+// the pod itself and its compute requests and limits, init containers
+// counting when they ask for more than the containers together
+func podQuotaUsage(podSpec *corev1.PodSpec) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+	for _, c := range podSpec.Containers {
+		addResources(requests, c.Resources.Requests)
+		addResources(limits, c.Resources.Limits)
+	}
+	for _, c := range podSpec.InitContainers {
+		maxResources(requests, c.Resources.Requests)
+		maxResources(limits, c.Resources.Limits)
+	}
+
+	usage := corev1.ResourceList{
+		corev1.ResourcePods:               resource.MustParse("1"),
+		corev1.ResourceName("count/pods"): resource.MustParse("1"),
+	}
+	for name, q := range requests {
+		if !strings.Contains(string(name), "/") {
+			usage[name] = q
+		}
+		usage[corev1.ResourceName("requests."+string(name))] = q
+	}
+	for name, q := range limits {
+		usage[corev1.ResourceName("limits."+string(name))] = q
+	}
+	return usage
+}
+
+func addResources(total, list corev1.ResourceList) {
+	for name, q := range list {
+		sum := total[name]
+		sum.Add(q)
+		total[name] = sum
+	}
+}
+
+func maxResources(total, list corev1.ResourceList) {
+	for name, q := range list {
+		if current, ok := total[name]; !ok || q.Cmp(current) > 0 {
+			total[name] = q.DeepCopy()
+		}
+	}
+}
+
+// capScaleUp lowers the desired agents of the types scaling up to what the
+// quota and node headroom admit and returns the types it held back. Types
+// are fitted in name order, so an earlier type may take the headroom a
+// later one wanted. The headroom is only collected when a type scales up.
+func (r *SwarmClusterReconciler) capScaleUp(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, current, desired map[swarmv1alpha1.AgentType]int) ([]scalingLimit, error) {
+	types := make([]swarmv1alpha1.AgentType, 0, len(desired))
+	for agentType := range desired {
+		types = append(types, agentType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	var headroom *scalingHeadroom
+	var limits []scalingLimit
+	for _, agentType := range types {
+		wanted := desired[agentType] - current[agentType]
+		if wanted <= 0 {
+			continue
+		}
+		if headroom == nil {
+			var err error
+			if headroom, err = r.scalingHeadroom(ctx, cluster); err != nil {
+				return nil, err
+			}
+		}
+		podSpec, err := constructAgentPodSpec(cluster, agentType, 8080, agentTypeResources(cluster, agentType), nil)
+		if err != nil {
+			// Creating the agent reports the invalid resources
+			continue
+		}
+		applyCapabilityPlacement(cluster, cluster.Spec.AgentTemplate.Capabilities, &podSpec)
+
+		allowed, reason, limitedBy := headroom.fit(&podSpec, wanted)
+		if allowed < wanted {
+			desired[agentType] = current[agentType] + allowed
+			limits = append(limits, scalingLimit{
+				agentType: agentType,
+				wanted:    current[agentType] + wanted,
+				allowed:   current[agentType] + allowed,
+				reason:    reason,
+				limitedBy: limitedBy,
+			})
+		}
+	}
+	return limits, nil
+}
+
+// setScalingLimited reports the agent types capScaleUp held back through
+// the ScalingLimited condition, raising a warning when scaling becomes
+// limited. Clusters never limited do not get the condition.
+func (r *SwarmClusterReconciler) setScalingLimited(cluster *swarmv1alpha1.SwarmCluster, limits []scalingLimit) {
+	if len(limits) == 0 && meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeScalingLimited) == nil {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeScalingLimited,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonWithinCapacity,
+		Message:            "Autoscaling is within the namespace quota and node capacity",
+		ObservedGeneration: cluster.Generation,
+	}
+	if len(limits) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonInsufficientNodeCapacity
+		held := make([]string, 0, len(limits))
+		for _, limit := range limits {
+			if limit.reason == ReasonQuotaExhausted {
+				condition.Reason = ReasonQuotaExhausted
+			}
+			held = append(held, fmt.Sprintf("%s %d of %d agents by %s", limit.agentType, limit.allowed, limit.wanted, limit.limitedBy))
+		}
+		if condition.Reason == ReasonQuotaExhausted {
+			condition.Message = "Scaling limited by quota: " + strings.Join(held, "; ")
+		} else {
+			condition.Message = "Scaling limited by node capacity: " + strings.Join(held, "; ")
+		}
+	}
+
+	if meta.SetStatusCondition(&cluster.Status.Conditions, condition) && condition.Status == metav1.ConditionTrue {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+}
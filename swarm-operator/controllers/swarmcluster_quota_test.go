@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Quota-aware autoscaling", func() {
+	var (
+		ctx          context.Context
+		swarmCluster *swarmv1alpha1.SwarmCluster
+		objects      []client.Object
+	)
+
+	reconciler := func() *SwarmClusterReconciler {
+		k8sClient := newTestClient(objects...)
+		return &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	}
+
+	quota := func(name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+			Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+		}
+	}
+
+	node := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}
+	}
+
+	desiredAgents := func(r *SwarmClusterReconciler) map[swarmv1alpha1.AgentType]int32 {
+		scale, err := r.evaluateQueueScaling(ctx, swarmCluster, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scale).To(BeTrue())
+		desired := map[swarmv1alpha1.AgentType]int32{}
+		for _, status := range swarmCluster.Status.AgentTypeScaling {
+			desired[status.Type] = status.DesiredAgents
+		}
+		return desired
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		swarmCluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				MaxAgents: 10,
+				AgentTemplate: swarmv1alpha1.AgentTemplateSpec{
+					Resources: swarmv1alpha1.ResourceRequirements{CPU: "1", Memory: "2Gi"},
+				},
+				AutoScaling: &swarmv1alpha1.AutoScalingSpec{
+					Enabled:          true,
+					TargetQueueDepth: 1,
+					TopologyRatios: map[string]swarmv1alpha1.AgentTypeScaling{
+						"coder":  {},
+						"tester": {},
+					},
+				},
+			},
+		}
+		objects = []client.Object{swarmCluster.DeepCopy()}
+		for i := 0; i < 6; i++ {
+			agentType := swarmv1alpha1.CoderAgent
+			if i >= 4 {
+				agentType = swarmv1alpha1.TesterAgent
+			}
+			objects = append(objects, &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("task-%d", i), Namespace: "default"},
+				Spec: swarmv1alpha1.SwarmTaskSpec{
+					SwarmCluster:        "swarm",
+					PreferredAgentTypes: []swarmv1alpha1.AgentType{agentType},
+				},
+			})
+		}
+	})
+
+	It("scales freely without quotas or nodes", func() {
+		Expect(desiredAgents(reconciler())).To(Equal(map[swarmv1alpha1.AgentType]int32{
+			swarmv1alpha1.CoderAgent:  4,
+			swarmv1alpha1.TesterAgent: 2,
+		}))
+		Expect(meta.FindStatusCondition(swarmCluster.Status.Conditions, ConditionTypeScalingLimited)).To(BeNil())
+	})
+
+	It("caps the agent types to the namespace quota", func() {
+		objects = append(objects,
+			quota("compute", corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("8"),
+			}, corev1.ResourceList{
+				corev1.ResourceRequestsCPU: resource.MustParse("3500m"),
+			}),
+			quota("pods", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("50")}, nil),
+			// Scoped quotas only count some pods
+			&corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "best-effort", Namespace: "default"},
+				Spec:       corev1.ResourceQuotaSpec{Scopes: []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("0")},
+				},
+			},
+		)
+		Expect(desiredAgents(reconciler())).To(Equal(map[swarmv1alpha1.AgentType]int32{
+			swarmv1alpha1.CoderAgent:  4,
+			swarmv1alpha1.TesterAgent: 0,
+		}))
+		Expect(swarmCluster.Status.AgentTypeScaling[1].LimitedBy).To(Equal("ResourceQuota compute (requests.cpu)"))
+
+		condition := meta.FindStatusCondition(swarmCluster.Status.Conditions, ConditionTypeScalingLimited)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(ReasonQuotaExhausted))
+		Expect(condition.Message).To(Equal("Scaling limited by quota: tester 0 of 2 agents by ResourceQuota compute (requests.cpu)"))
+	})
+
+	It("caps the agent types to the free node capacity", func() {
+		objects = append(objects,
+			node("small", "2", "8Gi"),
+			node("large", "4", "4Gi"),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "busy", Namespace: "other"},
+				Spec: corev1.PodSpec{
+					NodeName: "small",
+					Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					}}},
+				},
+			},
+		)
+		Expect(desiredAgents(reconciler())).To(Equal(map[swarmv1alpha1.AgentType]int32{
+			swarmv1alpha1.CoderAgent:  3,
+			swarmv1alpha1.TesterAgent: 0,
+		}))
+
+		condition := meta.FindStatusCondition(swarmCluster.Status.Conditions, ConditionTypeScalingLimited)
+		Expect(condition.Reason).To(Equal(ReasonInsufficientNodeCapacity))
+		Expect(condition.Message).To(Equal("Scaling limited by node capacity: " +
+			"coder 3 of 4 agents by node capacity; tester 0 of 2 agents by node capacity"))
+	})
+
+	It("clears the condition once the agents fit", func() {
+		r := reconciler()
+		r.setScalingLimited(swarmCluster, []scalingLimit{{
+			agentType: swarmv1alpha1.CoderAgent, wanted: 4, allowed: 2,
+			reason: ReasonQuotaExhausted, limitedBy: "ResourceQuota compute (requests.cpu)",
+		}})
+		Expect(meta.IsStatusConditionTrue(swarmCluster.Status.Conditions, ConditionTypeScalingLimited)).To(BeTrue())
+
+		desiredAgents(r)
+		condition := meta.FindStatusCondition(swarmCluster.Status.Conditions, ConditionTypeScalingLimited)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonWithinCapacity))
+	})
+})
@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// Inconsistencies between agent status.currentTasks and task
+// status.assignedAgents, as counted by the metrics
+const (
+	inconsistencyTaskDeleted    = "task_deleted"
+	inconsistencyTaskFinished   = "task_finished"
+	inconsistencyTaskReassigned = "task_reassigned"
+	inconsistencyAgentDeleted   = "agent_deleted"
+	inconsistencyAgentFailed    = "agent_failed"
+
+	// staleReferenceGrace gives an agent time to report a task it just
+	// acked before its reference counts as stale
+	staleReferenceGrace = time.Minute
+)
+
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks/status,verbs=get;update;patch
+
+// staleTaskReference returns why the agent should no longer list the
+// task, or "". task is nil when it was deleted. Only clusters pushing tasks
+// to agents know which agent holds a task.
+func staleTaskReference(agent *swarmv1alpha1.Agent, ref swarmv1alpha1.TaskReference, task *swarmv1alpha1.SwarmTask, push bool, now time.Time) string {
+	switch {
+	case task == nil:
+		return inconsistencyTaskDeleted
+	case taskFinished(task):
+		return inconsistencyTaskFinished
+	case !push:
+		return ""
+	}
+	holder := acknowledgedAgent(task)
+	if holder != agent.Name && (holder != "" || now.Sub(ref.StartTime.Time) > staleReferenceGrace) {
+		return inconsistencyTaskReassigned
+	}
+	return ""
+}
+
+// orphanedTask returns why the agent holding the task cannot finish it,
+// or "". Draining and terminating agents hand their tasks over themselves.
+func orphanedTask(task *swarmv1alpha1.SwarmTask, agents map[string]*swarmv1alpha1.Agent) string {
+	holder := acknowledgedAgent(task)
+	if holder == "" || taskFinished(task) {
+		return ""
+	}
+	agent, ok := agents[holder]
+	switch {
+	case !ok:
+		return inconsistencyAgentDeleted
+	case agent.Status.Phase == "Failed":
+		return inconsistencyAgentFailed
+	}
+	return ""
+}
+
+// reconcileTaskReferences cross-references the tasks agents report in
+// status.currentTasks with the agents tasks are assigned to. References to
+// deleted, finished or reassigned tasks are dropped from the agents, and
+// tasks held by deleted or failed agents go back to Pending so the task
+// controller assigns them again.
+func (r *SwarmClusterReconciler) reconcileTaskReferences(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	log := log.FromContext(ctx)
+
+	agentList := &swarmv1alpha1.AgentList{}
+	if err := r.List(ctx, agentList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name}); err != nil {
+		return err
+	}
+	taskList := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, taskList, client.InNamespace(cluster.Namespace)); err != nil {
+		return err
+	}
+
+	agents := map[string]*swarmv1alpha1.Agent{}
+	for i := range agentList.Items {
+		agents[agentList.Items[i].Name] = &agentList.Items[i]
+	}
+	tasks := map[string]*swarmv1alpha1.SwarmTask{}
+	for i := range taskList.Items {
+		if taskList.Items[i].Spec.SwarmCluster == cluster.Name {
+			tasks[taskList.Items[i].Name] = &taskList.Items[i]
+		}
+	}
+
+	push, now := pushAssignmentEnabled(cluster), time.Now()
+	for _, agent := range agents {
+		current := make([]swarmv1alpha1.TaskReference, 0, len(agent.Status.CurrentTasks))
+		for _, ref := range agent.Status.CurrentTasks {
+			if reason := staleTaskReference(agent, ref, tasks[ref.Name], push, now); reason != "" {
+				log.Info("Dropping stale task reference", "agent", agent.Name, "task", ref.Name, "reason", reason)
+				r.recordInconsistency(cluster, reason)
+				continue
+			}
+			current = append(current, ref)
+		}
+		if len(current) == len(agent.Status.CurrentTasks) {
+			continue
+		}
+		agent.Status.CurrentTasks = current
+		if err := r.Status().Update(ctx, agent); err != nil {
+			return err
+		}
+	}
+
+	for _, task := range tasks {
+		reason := orphanedTask(task, agents)
+		if reason == "" {
+			continue
+		}
+		holder := acknowledgedAgent(task)
+		r.recordInconsistency(cluster, reason)
+		task.Status.AssignedAgents = nil
+		task.Status.Phase = "Pending"
+		task.Status.Message = fmt.Sprintf("Agent %s is gone, reassigning task", holder)
+		if err := r.Status().Update(ctx, task); err != nil {
+			return err
+		}
+		log.Info("Requeued task of lost agent", "task", task.Name, "agent", holder, "reason", reason)
+		r.Recorder.Eventf(task, corev1.EventTypeWarning, "AgentLost",
+			"Agent %s is no longer available, reassigning task", holder)
+	}
+	return nil
+}
+
+// recordInconsistency counts a drifted task reference
+func (r *SwarmClusterReconciler) recordInconsistency(cluster *swarmv1alpha1.SwarmCluster, reason string) {
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.RecordTaskReferenceInconsistency(cluster.Namespace, cluster.Name, reason)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task reference cleanup", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	newAgent := func(name, phase string, tasks ...string) *swarmv1alpha1.Agent {
+		agent := &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"swarm-cluster": "swarm"}},
+			Status:     swarmv1alpha1.AgentStatus{Phase: phase},
+		}
+		for _, task := range tasks {
+			agent.Status.CurrentTasks = append(agent.Status.CurrentTasks, swarmv1alpha1.TaskReference{
+				Name: task, StartTime: metav1.NewTime(time.Now().Add(-5 * time.Minute)),
+			})
+		}
+		return agent
+	}
+	newTask := func(name, phase, agent string) *swarmv1alpha1.SwarmTask {
+		task := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", Description: "Run " + name},
+			Status:     swarmv1alpha1.SwarmTaskStatus{Phase: phase},
+		}
+		if agent != "" {
+			task.Status.AssignedAgents = []swarmv1alpha1.AssignedAgent{{Name: agent, Status: assignmentAcknowledged}}
+		}
+		return task
+	}
+	setup := func(objects ...client.Object) {
+		k8sClient := newTestClient(objects...)
+		reconciler = &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	}
+	currentTasks := func(name string) []string {
+		agent := &swarmv1alpha1.Agent{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, agent)).To(Succeed())
+		var names []string
+		for _, ref := range agent.Status.CurrentTasks {
+			names = append(names, ref.Name)
+		}
+		return names
+	}
+	getTask := func(name string) *swarmv1alpha1.SwarmTask {
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, task)).To(Succeed())
+		return task
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{TaskDistribution: swarmv1alpha1.TaskDistributionSpec{
+				AssignmentMode: pushAssignmentMode,
+			}},
+		}
+	})
+
+	It("drops references to deleted, finished and reassigned tasks", func() {
+		setup(
+			newAgent("agent-1", "Busy", "deleted", "done", "moved", "running"),
+			newAgent("agent-2", "Busy", "moved"),
+			newTask("done", "Completed", "agent-1"),
+			newTask("moved", "Running", "agent-2"),
+			newTask("running", "Running", "agent-1"),
+		)
+		Expect(reconciler.reconcileTaskReferences(ctx, cluster)).To(Succeed())
+		Expect(currentTasks("agent-1")).To(Equal([]string{"running"}))
+		Expect(currentTasks("agent-2")).To(Equal([]string{"moved"}))
+		Expect(getTask("running").Status.AssignedAgents).To(HaveLen(1))
+	})
+
+	It("gives agents time to report a task they just acked", func() {
+		agent := newAgent("agent-1", "Busy", "new")
+		agent.Status.CurrentTasks[0].StartTime = metav1.Now()
+		setup(agent, newTask("new", "Pending", ""))
+		Expect(reconciler.reconcileTaskReferences(ctx, cluster)).To(Succeed())
+		Expect(currentTasks("agent-1")).To(Equal([]string{"new"}))
+	})
+
+	It("requeues the tasks of deleted and failed agents", func() {
+		setup(
+			newAgent("failed", "Failed"),
+			newAgent("draining", "Terminating", "drained"),
+			newTask("lost", "Running", "gone"),
+			newTask("stuck", "Running", "failed"),
+			newTask("drained", "Running", "draining"),
+		)
+		Expect(reconciler.reconcileTaskReferences(ctx, cluster)).To(Succeed())
+
+		for _, name := range []string{"lost", "stuck"} {
+			task := getTask(name)
+			Expect(task.Status.Phase).To(Equal("Pending"))
+			Expect(task.Status.AssignedAgents).To(BeEmpty())
+		}
+		Expect(getTask("lost").Status.Message).To(Equal("Agent gone is gone, reassigning task"))
+		Expect(getTask("drained").Status.AssignedAgents).To(HaveLen(1))
+	})
+
+	It("only drops references to finished tasks when tasks run as Jobs", func() {
+		cluster.Spec.TaskDistribution.AssignmentMode = ""
+		setup(newAgent("agent-1", "Busy", "done", "running"), newTask("done", "Failed", ""), newTask("running", "Running", ""))
+		Expect(reconciler.reconcileTaskReferences(ctx, cluster)).To(Succeed())
+		Expect(currentTasks("agent-1")).To(Equal([]string{"running"}))
+	})
+})
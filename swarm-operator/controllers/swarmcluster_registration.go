@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/registration"
+)
+
+// reconcileRegistration generates the registration token external agents
+// join the cluster with and reports how many joined. The token is kept
+// once generated; deleting its Secret rotates it. A change in the number
+// of external agents recomputes the peers of the swarm so they take part in
+// its topology.
+func (r *SwarmClusterReconciler) reconcileRegistration(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	secretKey := types.NamespacedName{Name: registration.TokenSecretName(cluster.Name), Namespace: cluster.Namespace}
+	if !registration.Enabled(cluster) {
+		if cluster.Status.Registration == nil {
+			return nil
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretKey.Name, Namespace: secretKey.Namespace}}
+		if err := r.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		cluster.Status.Registration = nil
+		return r.Status().Update(ctx, cluster)
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, secretKey, secret)
+	if errors.IsNotFound(err) {
+		token, err := randomPassword()
+		if err != nil {
+			return err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretKey.Name,
+				Namespace: secretKey.Namespace,
+				Labels:    map[string]string{"swarm-cluster": cluster.Name, "component": "registration"},
+			},
+			Data: map[string][]byte{registration.TokenKey: []byte(token)},
+		}
+		if err := controllerutil.SetControllerReference(cluster, secret, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, secret); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "RegistrationTokenCreated",
+			"Generated the agent registration token in Secret %s", secret.Name)
+	} else if err != nil {
+		return err
+	}
+
+	agents := &swarmv1alpha1.AgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name}); err != nil {
+		return err
+	}
+	external := int32(0)
+	for _, agent := range agents.Items {
+		if agent.Spec.External && agent.GetDeletionTimestamp() == nil {
+			external++
+		}
+	}
+
+	status := &swarmv1alpha1.AgentRegistrationStatus{TokenSecret: secret.Name, ExternalAgents: external}
+	if equality.Semantic.DeepEqual(cluster.Status.Registration, status) {
+		return nil
+	}
+	if cluster.Status.Registration != nil && cluster.Status.Registration.ExternalAgents != external &&
+		(cluster.Status.Phase == "Running" || cluster.Status.Phase == "Scaling") {
+		log.FromContext(ctx).Info("External agents changed, recomputing peers", "externalAgents", external)
+		if err := r.setupTopology(ctx, cluster, agents.Items); err != nil {
+			return err
+		}
+	}
+	cluster.Status.Registration = status
+	return r.Status().Update(ctx, cluster)
+}
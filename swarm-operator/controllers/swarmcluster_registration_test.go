@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/registration"
+)
+
+var _ = Describe("External agent registration", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	key := types.NamespacedName{Name: "swarm", Namespace: "default"}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "swarm-uid"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Topology:     swarmv1alpha1.MeshTopology,
+				Registration: &swarmv1alpha1.AgentRegistrationSpec{Enabled: true},
+			},
+			Status: swarmv1alpha1.SwarmClusterStatus{Phase: "Running"},
+		}
+		managed := &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "coder-a", Namespace: "default", Labels: map[string]string{"swarm-cluster": "swarm"}},
+			Spec:       swarmv1alpha1.AgentSpec{Type: swarmv1alpha1.CoderAgent, SwarmCluster: "swarm"},
+		}
+		k8sClient := newTestClient(cluster, managed)
+		reconciler = &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	reconcile := func() *swarmv1alpha1.SwarmCluster {
+		current := &swarmv1alpha1.SwarmCluster{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		Expect(reconciler.reconcileRegistration(ctx, current)).To(Succeed())
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		return current
+	}
+
+	token := func() string {
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-registration", Namespace: "default"}, secret)).To(Succeed())
+		return string(secret.Data[registration.TokenKey])
+	}
+
+	It("generates the registration token once", func() {
+		current := reconcile()
+		Expect(current.Status.Registration).To(Equal(&swarmv1alpha1.AgentRegistrationStatus{TokenSecret: "swarm-registration"}))
+		generated := token()
+		Expect(generated).To(HaveLen(48))
+
+		reconcile()
+		Expect(token()).To(Equal(generated))
+	})
+
+	It("counts registered agents and gives them topology peers", func() {
+		current := reconcile()
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		_, err := registration.Register(ctx, reconciler.Client, current, registration.Registration{
+			Cluster: "swarm", Namespace: "default", Name: "laptop",
+			Type: swarmv1alpha1.ReviewerAgent, Address: "10.1.2.3:8080",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reconcile().Status.Registration.ExternalAgents).To(Equal(int32(1)))
+		external := &swarmv1alpha1.Agent{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "laptop", Namespace: "default"}, external)).To(Succeed())
+		Expect(external.Spec.CommunicationEndpoints.Peers).To(HaveLen(1))
+
+		address, err := (&SwarmTaskReconciler{Client: reconciler.Client}).agentAddress(ctx, external)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(address).To(Equal("10.1.2.3:8080"))
+	})
+
+	It("removes the token once registration is disabled", func() {
+		current := reconcile()
+		current.Spec.Registration = nil
+		Expect(reconciler.Update(ctx, current)).To(Succeed())
+
+		Expect(reconcile().Status.Registration).To(BeNil())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-registration", Namespace: "default"}, &corev1.Secret{})).NotTo(Succeed())
+	})
+
+	It("never drains external agents", func() {
+		ordered := drainOrder([]swarmv1alpha1.Agent{
+			{ObjectMeta: metav1.ObjectMeta{Name: "laptop"}, Spec: swarmv1alpha1.AgentSpec{External: true}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "coder-a"}},
+		})
+		Expect(ordered).To(HaveLen(1))
+		Expect(ordered[0].Name).To(Equal("coder-a"))
+	})
+
+	It("recovers a failed external agent once it sends heartbeats again", func() {
+		agent := &swarmv1alpha1.Agent{Spec: swarmv1alpha1.AgentSpec{External: true}}
+		failed := &metav1.Condition{LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute))}
+		agent.Status.LastHeartbeat = &metav1.Time{Time: time.Now().Add(-90 * time.Second)}
+		Expect(externalAgentRecovered(agent, failed, cluster)).To(BeFalse())
+
+		agent.Status.LastHeartbeat = &metav1.Time{Time: time.Now()}
+		Expect(externalAgentRecovered(agent, failed, cluster)).To(BeTrue())
+		Expect(agentHeartbeatTimeout(agent, cluster)).To(Equal(registration.DefaultHeartbeatTimeout))
+	})
+})
@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	rolloutProgressing = "Progressing"
+	rolloutPaused      = "Paused"
+	rolloutCompleted   = "Completed"
+
+	defaultRolloutMaxFailureRateIncrease = 10
+	defaultRolloutMinSamples             = 5
+)
+
+// executorRolloutTarget returns the image being rolled out and the percent
+// of new tasks that should run it, 0 while the rollout has not started or
+// is paused
+func executorRolloutTarget(cluster *swarmv1alpha1.SwarmCluster) (string, int32) {
+	rollout, status := cluster.Spec.ExecutorImageRollout, cluster.Status.ExecutorRollout
+	if rollout == nil || status == nil || status.Image != rollout.Image || status.Phase == rolloutPaused {
+		return "", 0
+	}
+	return rollout.Image, status.Percent
+}
+
+// reconcileExecutorRollout advances the executor image rollout through its
+// steps and pauses it when the new image fails noticeably more often than
+// the images it replaces
+func (r *SwarmClusterReconciler) reconcileExecutorRollout(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	rollout := cluster.Spec.ExecutorImageRollout
+	if rollout == nil || len(rollout.Steps) == 0 {
+		if cluster.Status.ExecutorRollout == nil {
+			return nil
+		}
+		cluster.Status.ExecutorRollout = nil
+		return r.Status().Update(ctx, cluster)
+	}
+
+	now := time.Now()
+	status := cluster.Status.ExecutorRollout
+	var previous *swarmv1alpha1.ExecutorRolloutStatus
+	if status != nil && status.Image == rollout.Image {
+		previous = status.DeepCopy()
+	} else {
+		status = &swarmv1alpha1.ExecutorRolloutStatus{
+			Image:         rollout.Image,
+			Phase:         rolloutProgressing,
+			StepStartedAt: &metav1.Time{Time: now},
+		}
+		cluster.Status.ExecutorRollout = status
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "ExecutorRolloutStarted",
+			"Rolling out executor image %s", rollout.Image)
+	}
+	if last := int32(len(rollout.Steps)) - 1; status.Step > last {
+		status.Step = last
+	}
+
+	if r.MetricsRecorder != nil {
+		canary, baseline := r.MetricsRecorder.CompareExecutorImage(cluster.Namespace, cluster.Name, rollout.Image)
+		status.CanaryJobs, status.CanaryFailures = int32(canary.Jobs), int32(canary.Failures)
+		status.BaselineJobs, status.BaselineFailures = int32(baseline.Jobs), int32(baseline.Failures)
+
+		minSamples, maxIncrease := rolloutThresholds(rollout)
+		increase := (canary.FailureRate() - baseline.FailureRate()) * 100
+		if status.Phase == rolloutProgressing && canary.Jobs >= minSamples && increase > maxIncrease {
+			status.Phase = rolloutPaused
+			status.Message = fmt.Sprintf("Paused: %s failed %.0f%% of %d Jobs against %.0f%% for the current image",
+				rollout.Image, canary.FailureRate()*100, canary.Jobs, baseline.FailureRate()*100)
+			r.Recorder.Event(cluster, corev1.EventTypeWarning, "ExecutorRolloutPaused", status.Message)
+		}
+	}
+
+	if status.Phase == rolloutProgressing {
+		step := rollout.Steps[status.Step]
+		if int(status.Step) < len(rollout.Steps)-1 && status.StepStartedAt != nil &&
+			now.Sub(status.StepStartedAt.Time) >= step.Duration.Duration {
+			status.Step++
+			status.StepStartedAt = &metav1.Time{Time: now}
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "ExecutorRolloutProgressed",
+				"Sending %d%% of new tasks to %s", rollout.Steps[status.Step].Percent, rollout.Image)
+		}
+
+		status.Percent = rollout.Steps[status.Step].Percent
+		status.Message = fmt.Sprintf("Sending %d%% of new tasks to %s", status.Percent, rollout.Image)
+		if int(status.Step) == len(rollout.Steps)-1 && status.Percent == 100 {
+			status.Phase = rolloutCompleted
+			status.Message = fmt.Sprintf("All new tasks run %s", rollout.Image)
+		}
+	}
+	if status.Phase == rolloutPaused {
+		status.Percent = 0
+	}
+
+	if previous != nil && equality.Semantic.DeepEqual(previous, status) {
+		return nil
+	}
+	return r.Status().Update(ctx, cluster)
+}
+
+// rolloutThresholds returns the canary sample size and the failure rate
+// increase in percentage points that pause the rollout
+func rolloutThresholds(rollout *swarmv1alpha1.ExecutorImageRollout) (int, float64) {
+	minSamples, maxIncrease := int32(defaultRolloutMinSamples), int32(defaultRolloutMaxFailureRateIncrease)
+	if rollout.MinSamples > 0 {
+		minSamples = rollout.MinSamples
+	}
+	if rollout.MaxFailureRateIncrease > 0 {
+		maxIncrease = rollout.MaxFailureRateIncrease
+	}
+	return int(minSamples), float64(maxIncrease)
+}
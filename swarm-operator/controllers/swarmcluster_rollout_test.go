@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+var _ = Describe("Executor image rollout", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		recorder   *metrics.MetricsRecorder
+		reconciler *SwarmClusterReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "rollout", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				ExecutorImageRollout: &swarmv1alpha1.ExecutorImageRollout{
+					Image: "claude-flow/swarm-executor:v2",
+					Steps: []swarmv1alpha1.RolloutStep{
+						{Percent: 10, Duration: metav1.Duration{Duration: time.Hour}},
+						{Percent: 50, Duration: metav1.Duration{Duration: time.Hour}},
+						{Percent: 100},
+					},
+				},
+			},
+		}
+		k8sClient := newTestClient(cluster)
+		recorder = metrics.NewMetricsRecorder()
+		reconciler = &SwarmClusterReconciler{
+			Client:          k8sClient,
+			Scheme:          testScheme,
+			Recorder:        record.NewFakeRecorder(10),
+			MetricsRecorder: recorder,
+		}
+	})
+
+	It("starts at the first step and advances once it has been held", func() {
+		Expect(reconciler.reconcileExecutorRollout(ctx, cluster)).To(Succeed())
+		status := cluster.Status.ExecutorRollout
+		Expect(status.Phase).To(Equal(rolloutProgressing))
+		Expect(status.Percent).To(BeEquivalentTo(10))
+
+		status.StepStartedAt = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		Expect(reconciler.reconcileExecutorRollout(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.ExecutorRollout.Step).To(BeEquivalentTo(1))
+		Expect(cluster.Status.ExecutorRollout.Percent).To(BeEquivalentTo(50))
+
+		image, percent := executorRolloutTarget(cluster)
+		Expect(image).To(Equal("claude-flow/swarm-executor:v2"))
+		Expect(percent).To(BeEquivalentTo(50))
+	})
+
+	It("pauses when the new image fails more often than the current one", func() {
+		for i := 0; i < 10; i++ {
+			recorder.RecordExecutorOutcome("default", "rollout", "claude-flow/swarm-executor:v1", i == 0)
+		}
+		for i := 0; i < 5; i++ {
+			recorder.RecordExecutorOutcome("default", "rollout", "claude-flow/swarm-executor:v2", i < 2)
+		}
+
+		Expect(reconciler.reconcileExecutorRollout(ctx, cluster)).To(Succeed())
+		status := cluster.Status.ExecutorRollout
+		Expect(status.Phase).To(Equal(rolloutPaused))
+		Expect(status.CanaryFailures).To(BeEquivalentTo(2))
+		Expect(status.BaselineJobs).To(BeEquivalentTo(10))
+
+		_, percent := executorRolloutTarget(cluster)
+		Expect(percent).To(BeZero())
+	})
+
+	It("restarts when the image changes", func() {
+		cluster.Status.ExecutorRollout = &swarmv1alpha1.ExecutorRolloutStatus{
+			Image: "claude-flow/swarm-executor:v2", Phase: rolloutPaused, Step: 1,
+		}
+		cluster.Spec.ExecutorImageRollout.Image = "claude-flow/swarm-executor:v3"
+
+		Expect(reconciler.reconcileExecutorRollout(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.ExecutorRollout.Phase).To(Equal(rolloutProgressing))
+		Expect(cluster.Status.ExecutorRollout.Step).To(BeZero())
+	})
+
+	It("sends a stable share of tasks to the new image", func() {
+		canaries := 0
+		for i := 0; i < 1000; i++ {
+			task := &swarmv1alpha1.SwarmTask{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("task-%d", i), Namespace: "default"}}
+			if canaryTask(task, 25) {
+				canaries++
+				Expect(canaryTask(task, 50)).To(BeTrue())
+			}
+		}
+		Expect(canaries).To(BeNumerically("~", 250, 60))
+	})
+})
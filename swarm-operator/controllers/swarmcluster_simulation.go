@@ -0,0 +1,398 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/topology"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// simulateAnnotation switches a SwarmCluster into simulation mode: the
+	// reconciler reports the changes it would make instead of making them
+	simulateAnnotation = "swarm.claudeflow.io/simulate"
+
+	simulateCreate = "create"
+	simulateUpdate = "update"
+	simulateDelete = "delete"
+)
+
+// simulatedChange is one entry of a simulation report
+type simulatedChange struct {
+	Action    string `json:"action"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func (c simulatedChange) String() string {
+	name := c.Name
+	if c.Namespace != "" {
+		name = c.Namespace + "/" + c.Name
+	}
+	if c.Reason == "" {
+		return fmt.Sprintf("%s %s %s", c.Action, c.Kind, name)
+	}
+	return fmt.Sprintf("%s %s %s: %s", c.Action, c.Kind, name, c.Reason)
+}
+
+// simulationRequested reports whether the cluster is in simulation mode
+func simulationRequested(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Annotations[simulateAnnotation] == "true"
+}
+
+func simulationConfigMapName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return cluster.Name + "-simulation"
+}
+
+// reconcileSimulation computes the objects the cluster spec leads to, diffs
+// them against the live objects and writes the report into a ConfigMap and
+// the cluster status. Nothing else is created, changed or deleted.
+func (r *SwarmClusterReconciler) reconcileSimulation(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	changes, err := r.simulateChanges(ctx, cluster)
+	if err != nil {
+		log.Error(err, "Failed to simulate SwarmCluster")
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "SimulationFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	summary := &swarmv1alpha1.SimulationStatus{
+		ObservedGeneration: cluster.Generation,
+		SimulatedAt:        metav1.Now(),
+		ReportConfigMap:    simulationConfigMapName(cluster),
+	}
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		switch change.Action {
+		case simulateCreate:
+			summary.Creates++
+		case simulateUpdate:
+			summary.Updates++
+		case simulateDelete:
+			summary.Deletes++
+		}
+		lines = append(lines, change.String())
+	}
+	report, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: simulationConfigMapName(cluster), Namespace: cluster.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Labels = map[string]string{"swarm-cluster": cluster.Name}
+		configMap.Data = map[string]string{
+			"summary":      strings.Join(lines, "\n"),
+			"changes.json": string(report),
+			"generation":   fmt.Sprint(cluster.Generation),
+		}
+		return controllerutil.SetControllerReference(cluster, configMap, r.Scheme)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	previous := cluster.Status.Simulation
+	cluster.Status.Simulation = summary
+	if err := r.Status().Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	if previous == nil || previous.ObservedGeneration != cluster.Generation {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "Simulated",
+			"Simulation would create %d, update %d and delete %d objects, see ConfigMap %s",
+			summary.Creates, summary.Updates, summary.Deletes, summary.ReportConfigMap)
+	}
+	return ctrl.Result{}, nil
+}
+
+// simulateChanges lists the changes reconciling the cluster spec would make
+// to namespaces, the memory store, agents and agent workloads
+func (r *SwarmClusterReconciler) simulateChanges(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) ([]simulatedChange, error) {
+	var changes []simulatedChange
+
+	// Namespaces
+	if config := cluster.Spec.NamespaceConfig; config != nil && config.CreateNamespaces {
+		var names []string
+		for ns := range r.swarmNamespaces(cluster) {
+			if ns != cluster.Namespace {
+				names = append(names, ns)
+			}
+		}
+		sort.Strings(names)
+		for _, ns := range names {
+			found, err := r.exists(ctx, types.NamespacedName{Name: ns}, &corev1.Namespace{})
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				changes = append(changes, simulatedChange{Action: simulateCreate, Kind: "Namespace", Name: ns})
+			}
+		}
+	}
+
+	// Memory store and its storage
+	if memoryStoreEnabled(cluster) {
+		memoryName := cluster.Name + "-memory"
+		key := types.NamespacedName{Name: memoryName, Namespace: r.getNamespaceForComponent(cluster, "memory")}
+		found, err := r.exists(ctx, key, &swarmv1alpha1.SwarmMemoryStore{})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			serviceNamespace, _ := operatorNamespaces(r.Config, r.SwarmNamespace, r.HiveMindNamespace)
+			if serviceNamespace == "" {
+				serviceNamespace = key.Namespace
+			}
+			changes = append(changes,
+				simulatedChange{Action: simulateCreate, Kind: "SwarmMemoryStore", Namespace: key.Namespace, Name: memoryName},
+				simulatedChange{Action: simulateCreate, Kind: "PersistentVolumeClaim", Namespace: serviceNamespace, Name: memoryName + "-storage",
+					Reason: fmt.Sprintf("size %s", cluster.Spec.Memory.Size)},
+			)
+		}
+	}
+
+	// Agents
+	agentList := &swarmv1alpha1.AgentList{}
+	if err := r.List(ctx, agentList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name}); err != nil {
+		return nil, err
+	}
+	agents, removed := r.simulateAgents(cluster, agentList.Items)
+	existing := map[string]*swarmv1alpha1.Agent{}
+	for i := range agentList.Items {
+		existing[agentList.Items[i].Name] = &agentList.Items[i]
+	}
+
+	if manager, err := topology.ForCluster(cluster); err == nil {
+		peers := manager.CalculatePeers(agents)
+		for i := range agents {
+			agents[i].Spec.CommunicationEndpoints.Peers = peers[agents[i].Name]
+		}
+	} else {
+		changes = append(changes, simulatedChange{Action: simulateUpdate, Kind: "SwarmCluster", Namespace: cluster.Namespace, Name: cluster.Name,
+			Reason: fmt.Sprintf("invalid topology: %v", err)})
+	}
+
+	for i := range agents {
+		agent := &agents[i]
+		current, ok := existing[agent.Name]
+		switch {
+		case !ok:
+			changes = append(changes, simulatedChange{Action: simulateCreate, Kind: "Agent", Namespace: agent.Namespace, Name: agent.Name,
+				Reason: fmt.Sprintf("type %s", agent.Spec.Type)})
+		case current.Labels["topology"] != string(cluster.Spec.Topology):
+			changes = append(changes, simulatedChange{Action: simulateUpdate, Kind: "Agent", Namespace: agent.Namespace, Name: agent.Name,
+				Reason: fmt.Sprintf("topology %s -> %s", current.Labels["topology"], cluster.Spec.Topology)})
+		case !equality.Semantic.DeepEqual(sortedCopy(current.Spec.CommunicationEndpoints.Peers), sortedCopy(agent.Spec.CommunicationEndpoints.Peers)):
+			changes = append(changes, simulatedChange{Action: simulateUpdate, Kind: "Agent", Namespace: agent.Namespace, Name: agent.Name,
+				Reason: fmt.Sprintf("%d -> %d peers", len(current.Spec.CommunicationEndpoints.Peers), len(agent.Spec.CommunicationEndpoints.Peers))})
+		}
+	}
+	for _, agent := range removed {
+		changes = append(changes, simulatedChange{Action: simulateDelete, Kind: "Agent", Namespace: agent.Namespace, Name: agent.Name, Reason: "scale down"})
+	}
+
+	// Agent workloads
+	workloads, err := r.simulateWorkloads(ctx, cluster, agents, removed)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, workloads...), nil
+}
+
+// simulateAgents returns the agents the cluster would run and the existing
+// agents it would remove. New agents are named and typed the way the
+// Initializing and Scaling phases create them, and scale-down drains agents
+// like handleScalingPhase. Size changes are applied within
+// minAgents and maxAgents even though a running cluster only reaches them
+// through its next scaling pass.
+func (r *SwarmClusterReconciler) simulateAgents(cluster *swarmv1alpha1.SwarmCluster, current []swarmv1alpha1.Agent) ([]swarmv1alpha1.Agent, []swarmv1alpha1.Agent) {
+	target := len(current)
+	if cluster.Status.Phase == "Running" || cluster.Status.Phase == "Scaling" {
+		target = r.calculateTargetAgentCount(cluster, current)
+	}
+	minAgents := int(cluster.Spec.MinAgents)
+	if minAgents < 1 {
+		minAgents = 1
+	}
+	if target < minAgents {
+		target = minAgents
+	}
+	if cluster.Spec.MaxAgents > 0 && target > int(cluster.Spec.MaxAgents) {
+		target = int(cluster.Spec.MaxAgents)
+	}
+
+	// Scale-down drains the agents first in drainOrder
+	surplus := map[string]bool{}
+	for i, agent := range drainOrder(current) {
+		if i < len(current)-target {
+			surplus[agent.Name] = true
+		}
+	}
+
+	agents := make([]swarmv1alpha1.Agent, 0, target)
+	var removed []swarmv1alpha1.Agent
+	for _, agent := range current {
+		if surplus[agent.Name] {
+			removed = append(removed, agent)
+			continue
+		}
+		agent := agent.DeepCopy()
+		if agent.Labels == nil {
+			agent.Labels = map[string]string{}
+		}
+		agent.Labels["topology"] = string(cluster.Spec.Topology)
+		agents = append(agents, *agent)
+	}
+	for i := len(current); i < target; i++ {
+		agents = append(agents, *r.constructAgentForSwarmCluster(cluster, i))
+	}
+	return agents, removed
+}
+
+// simulateWorkloads diffs the Deployments or pool StatefulSets the agents
+// would run in against the live ones
+func (r *SwarmClusterReconciler) simulateWorkloads(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, agents, removed []swarmv1alpha1.Agent) ([]simulatedChange, error) {
+	var changes []simulatedChange
+	builder := &AgentReconciler{Scheme: r.Scheme}
+
+	if !poolingEnabled(cluster) {
+		for i := range agents {
+			desired, err := builder.constructDeploymentForAgent(&agents[i], cluster)
+			if err != nil {
+				return nil, err
+			}
+			utils.ApplyImageConfig(&desired.Spec.Template.Spec, cluster.Spec.ImageConfig)
+			change, err := r.simulateWorkload(ctx, "Deployment", desired, desired.Spec.Replicas, desired.Spec.Template, func(obj client.Object) (*int32, corev1.PodTemplateSpec) {
+				deployment := obj.(*appsv1.Deployment)
+				return deployment.Spec.Replicas, deployment.Spec.Template
+			}, &appsv1.Deployment{})
+			if err != nil {
+				return nil, err
+			}
+			if change != nil {
+				changes = append(changes, *change)
+			}
+		}
+		for _, agent := range removed {
+			changes = append(changes, simulatedChange{Action: simulateDelete, Kind: "Deployment", Namespace: agent.Namespace, Name: agent.Name,
+				Reason: "agent removed"})
+		}
+		return changes, nil
+	}
+
+	counts := map[swarmv1alpha1.AgentType]int32{}
+	for _, agent := range agents {
+		counts[agent.Spec.Type]++
+	}
+	agentTypes := make([]string, 0, len(counts))
+	for agentType := range counts {
+		agentTypes = append(agentTypes, string(agentType))
+	}
+	sort.Strings(agentTypes)
+	for _, agentType := range agentTypes {
+		desired, err := builder.constructAgentPool(cluster, swarmv1alpha1.AgentType(agentType), counts[swarmv1alpha1.AgentType(agentType)])
+		if err != nil {
+			return nil, err
+		}
+		utils.ApplyImageConfig(&desired.Spec.Template.Spec, cluster.Spec.ImageConfig)
+		change, err := r.simulateWorkload(ctx, "StatefulSet", desired, desired.Spec.Replicas, desired.Spec.Template, func(obj client.Object) (*int32, corev1.PodTemplateSpec) {
+			sts := obj.(*appsv1.StatefulSet)
+			return sts.Spec.Replicas, sts.Spec.Template
+		}, &appsv1.StatefulSet{})
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	return changes, nil
+}
+
+// simulateWorkload compares a desired workload with the live object. Only
+// fields the operator sets are compared, so sidecars and settings added by
+// admission or the agent cache do not show up as changes.
+func (r *SwarmClusterReconciler) simulateWorkload(ctx context.Context, kind string, desired client.Object, replicas *int32, template corev1.PodTemplateSpec,
+	spec func(client.Object) (*int32, corev1.PodTemplateSpec), live client.Object) (*simulatedChange, error) {
+	change := &simulatedChange{Kind: kind, Namespace: desired.GetNamespace(), Name: desired.GetName()}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), live)
+	if errors.IsNotFound(err) {
+		change.Action = simulateCreate
+		if replicas != nil {
+			change.Reason = fmt.Sprintf("%d replicas", *replicas)
+		}
+		return change, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	liveReplicas, liveTemplate := spec(live)
+	var reasons []string
+	if replicas != nil && liveReplicas != nil && *replicas != *liveReplicas {
+		reasons = append(reasons, fmt.Sprintf("replicas %d -> %d", *liveReplicas, *replicas))
+	}
+	if !equality.Semantic.DeepDerivative(template, liveTemplate) {
+		reasons = append(reasons, "pod template")
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+	change.Action = simulateUpdate
+	change.Reason = strings.Join(reasons, ", ")
+	return change, nil
+}
+
+// exists reports whether the object exists
+func (r *SwarmClusterReconciler) exists(ctx context.Context, key types.NamespacedName, obj client.Object) (bool, error) {
+	err := r.Get(ctx, key, obj)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// sortedCopy returns a sorted copy of the strings
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("SwarmCluster simulation", func() {
+	var (
+		ctx        context.Context
+		k8sClient  client.Client
+		reconciler *SwarmClusterReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "swarm",
+				Namespace:   "default",
+				UID:         "uid",
+				Annotations: map[string]string{simulateAnnotation: "true"},
+			},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Topology:  swarmv1alpha1.MeshTopology,
+				MinAgents: 3,
+				MaxAgents: 5,
+			},
+			Status: swarmv1alpha1.SwarmClusterStatus{Phase: "Initializing"},
+		}
+		reconciler = &SwarmClusterReconciler{Scheme: testScheme, Recorder: record.NewFakeRecorder(100)}
+		existing := reconciler.constructAgentForSwarmCluster(cluster, 0)
+
+		k8sClient = newTestClient(cluster, existing)
+		reconciler.Client = k8sClient
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	})
+
+	It("reports the agents and deployments it would create without creating them", func() {
+		_, err := reconciler.reconcileSimulation(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cluster.Status.Simulation).NotTo(BeNil())
+		// Two more agents and a Deployment for each of the three
+		Expect(cluster.Status.Simulation.Creates).To(Equal(int32(5)))
+		Expect(cluster.Status.Simulation.Deletes).To(BeZero())
+
+		report := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "swarm-simulation", Namespace: "default"}, report)).To(Succeed())
+		var changes []simulatedChange
+		Expect(json.Unmarshal([]byte(report.Data["changes.json"]), &changes)).To(Succeed())
+		Expect(changes).To(ContainElement(And(
+			HaveField("Action", simulateCreate),
+			HaveField("Kind", "Agent"),
+			HaveField("Name", reconciler.constructAgentForSwarmCluster(cluster, 2).Name),
+		)))
+		Expect(report.OwnerReferences).To(HaveLen(1))
+
+		agents := &swarmv1alpha1.AgentList{}
+		Expect(k8sClient.List(ctx, agents)).To(Succeed())
+		Expect(agents.Items).To(HaveLen(1))
+		deployments := &appsv1.DeploymentList{}
+		Expect(k8sClient.List(ctx, deployments)).To(Succeed())
+		Expect(deployments.Items).To(BeEmpty())
+	})
+
+	It("reports the idle agents a smaller cluster would remove", func() {
+		for i := 1; i < 3; i++ {
+			agent := reconciler.constructAgentForSwarmCluster(cluster, i)
+			agent.Status.Phase = "Ready"
+			Expect(k8sClient.Create(ctx, agent)).To(Succeed())
+		}
+		cluster.Spec.MinAgents = 1
+		cluster.Spec.MaxAgents = 1
+
+		changes, err := reconciler.simulateChanges(ctx, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(ContainElement(And(HaveField("Action", simulateDelete), HaveField("Kind", "Agent"))))
+		Expect(changes).To(ContainElement(And(HaveField("Action", simulateDelete), HaveField("Kind", "Deployment"))))
+	})
+})
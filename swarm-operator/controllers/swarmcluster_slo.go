@@ -0,0 +1,327 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+const (
+	// ConditionTypeSLOsMet reports whether the task SLOs of the cluster hold
+	ConditionTypeSLOsMet = "SLOsMet"
+
+	// ConditionTypeAlertRulesReady reports whether the burn-rate alerts of
+	// the SLOs are installed
+	ConditionTypeAlertRulesReady = "AlertRulesReady"
+
+	ReasonSLOsMet                   = "SLOsMet"
+	ReasonErrorBudgetExhausted      = "ErrorBudgetExhausted"
+	ReasonErrorBudgetBurning        = "ErrorBudgetBurning"
+	ReasonInvalidSLO                = "InvalidSLO"
+	ReasonAlertRulesApplied         = "AlertRulesApplied"
+	ReasonPrometheusOperatorMissing = "PrometheusOperatorMissing"
+
+	defaultSLOObjective = "99"
+	defaultSLOWindow    = 24 * time.Hour
+
+	// sloPageBurnRate and sloTicketBurnRate are the multi-window burn-rate
+	// thresholds recommended for 30 day budgets: 2% of the budget spent in
+	// an hour pages, 5% in six hours opens a ticket
+	sloPageBurnRate   = 14.4
+	sloTicketBurnRate = 6
+)
+
+// prometheusRuleGVK is the Prometheus operator PrometheusRule kind, used
+// unstructured so the operator does not depend on its API module
+var prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+// clusterSLO is an SLO with its parsed objective
+type clusterSLO struct {
+	swarmv1alpha1.TaskSLO
+
+	// percent is the objective as given, e.g. 95, and objective the
+	// share, e.g. 0.95
+	percent   float64
+	objective float64
+}
+
+// sloStage returns the stage the SLO measures, completion by default
+func sloStage(slo swarmv1alpha1.TaskSLO) swarmv1alpha1.SLOStage {
+	if slo.Stage == "" {
+		return swarmv1alpha1.CompletionStage
+	}
+	return slo.Stage
+}
+
+// sloAppliesTo reports whether the task counts towards the SLO
+func sloAppliesTo(slo swarmv1alpha1.TaskSLO, task *swarmv1alpha1.SwarmTask) bool {
+	if len(slo.Priorities) == 0 {
+		return true
+	}
+	priority := task.Spec.Priority
+	if priority == "" {
+		priority = swarmv1alpha1.MediumPriority
+	}
+	for _, p := range slo.Priorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterSLOs returns the valid SLOs of the cluster and describes the
+// invalid ones
+func clusterSLOs(cluster *swarmv1alpha1.SwarmCluster) ([]clusterSLO, []string) {
+	if cluster.Spec.Monitoring == nil {
+		return nil, nil
+	}
+	var slos []clusterSLO
+	var invalid []string
+	seen := map[string]bool{}
+	for _, slo := range cluster.Spec.Monitoring.SLOs {
+		objective := slo.Objective
+		if objective == "" {
+			objective = defaultSLOObjective
+		}
+		percent, err := strconv.ParseFloat(objective, 64)
+		switch {
+		case seen[slo.Name]:
+			invalid = append(invalid, fmt.Sprintf("%s: duplicate SLO name", slo.Name))
+		case err != nil || percent <= 0 || percent >= 100:
+			invalid = append(invalid, fmt.Sprintf("%s: objective %q is not a percentage between 0 and 100", slo.Name, slo.Objective))
+		case slo.Threshold.Duration <= 0:
+			invalid = append(invalid, fmt.Sprintf("%s: threshold must be positive", slo.Name))
+		default:
+			slos = append(slos, clusterSLO{TaskSLO: slo, percent: percent, objective: percent / 100})
+		}
+		seen[slo.Name] = true
+	}
+	return slos, invalid
+}
+
+// sloWindow is the window compliance and error budget are computed over
+func sloWindow(slo clusterSLO) time.Duration {
+	if slo.Window.Duration <= 0 {
+		return defaultSLOWindow
+	}
+	return slo.Window.Duration
+}
+
+// reconcileSLOs publishes the compliance and error budget of the task SLOs
+// in status and metrics and installs their burn-rate alerts
+func (r *SwarmClusterReconciler) reconcileSLOs(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	before := cluster.Status.DeepCopy()
+	slos, invalid := clusterSLOs(cluster)
+
+	current := map[string]bool{}
+	var statuses []swarmv1alpha1.SLOStatus
+	var exhausted, burning []string
+	for _, slo := range slos {
+		current[slo.Name] = true
+		report := metrics.SLOReport{Compliance: 1, ErrorBudgetRemaining: 1}
+		if r.MetricsRecorder != nil {
+			report = r.MetricsRecorder.EvaluateSLO(cluster.Namespace, cluster.Name, slo.Name, slo.objective, sloWindow(slo))
+		}
+		statuses = append(statuses, swarmv1alpha1.SLOStatus{
+			Name:                 slo.Name,
+			Events:               int32(report.Events),
+			Compliance:           fmt.Sprintf("%.2f", report.Compliance*100),
+			ErrorBudgetRemaining: fmt.Sprintf("%.1f", report.ErrorBudgetRemaining*100),
+			BurnRate:             fmt.Sprintf("%.2f", report.SlowBurnRate),
+		})
+		switch {
+		case report.Events > 0 && report.Compliance < slo.objective:
+			exhausted = append(exhausted, fmt.Sprintf("%s: %.2f%% of tasks met the %s threshold, objective %s%%",
+				slo.Name, report.Compliance*100, slo.Threshold.Duration, strconv.FormatFloat(slo.percent, 'f', -1, 64)))
+		case report.FastBurnRate > sloPageBurnRate && report.SlowBurnRate > sloPageBurnRate:
+			burning = append(burning, fmt.Sprintf("%s: error budget burning at %.1fx", slo.Name, report.SlowBurnRate))
+		}
+	}
+	// Forget the series of SLOs removed from the spec
+	if r.MetricsRecorder != nil {
+		for _, status := range cluster.Status.SLOs {
+			if !current[status.Name] {
+				r.MetricsRecorder.DeleteSLO(cluster.Namespace, cluster.Name, status.Name)
+			}
+		}
+	}
+	cluster.Status.SLOs = statuses
+
+	if len(slos) == 0 && len(invalid) == 0 {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeSLOsMet)
+	} else {
+		condition := metav1.Condition{
+			Type:               ConditionTypeSLOsMet,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonSLOsMet,
+			Message:            fmt.Sprintf("All %d SLOs are met", len(slos)),
+			ObservedGeneration: cluster.Generation,
+		}
+		switch {
+		case len(invalid) > 0:
+			condition.Status, condition.Reason = metav1.ConditionFalse, ReasonInvalidSLO
+		case len(exhausted) > 0:
+			condition.Status, condition.Reason = metav1.ConditionFalse, ReasonErrorBudgetExhausted
+		case len(burning) > 0:
+			condition.Status, condition.Reason = metav1.ConditionFalse, ReasonErrorBudgetBurning
+		}
+		if problems := append(append(invalid, exhausted...), burning...); len(problems) > 0 {
+			condition.Message = strings.Join(problems, "; ")
+		}
+		if meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+			eventType := corev1.EventTypeNormal
+			if condition.Status == metav1.ConditionFalse {
+				eventType = corev1.EventTypeWarning
+			}
+			r.Recorder.Event(cluster, eventType, condition.Reason, condition.Message)
+		}
+	}
+
+	if err := r.reconcileSLOAlertRules(ctx, cluster, slos); err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(before, &cluster.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, cluster)
+}
+
+// reconcileSLOAlertRules keeps the PrometheusRule with the burn-rate alerts
+// of the SLOs in line with the spec and reports it through the
+// AlertRulesReady condition
+func (r *SwarmClusterReconciler) reconcileSLOAlertRules(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, slos []clusterSLO) error {
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(prometheusRuleGVK)
+	rule.SetName(cluster.Name + "-slos")
+	rule.SetNamespace(cluster.Namespace)
+
+	if len(slos) == 0 || !cluster.Spec.Monitoring.AlertRules {
+		if meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeAlertRulesReady) == nil {
+			return nil
+		}
+		if err := r.Delete(ctx, rule); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeAlertRulesReady)
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeAlertRulesReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonAlertRulesApplied,
+		Message:            fmt.Sprintf("PrometheusRule %s alerts on the burn rate of %d SLOs", rule.GetName(), len(slos)),
+		ObservedGeneration: cluster.Generation,
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, rule, func() error {
+		labels := map[string]string{"swarm-cluster": cluster.Name}
+		for key, value := range cluster.Spec.Monitoring.RuleLabels {
+			labels[key] = value
+		}
+		rule.SetLabels(labels)
+		if err := unstructured.SetNestedSlice(rule.Object, sloRuleGroups(cluster, slos), "spec", "groups"); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(cluster, rule, r.Scheme)
+	})
+	switch {
+	case meta.IsNoMatchError(err):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonPrometheusOperatorMissing
+		condition.Message = "The PrometheusRule kind is not installed, alert rules require the Prometheus operator"
+	case err != nil:
+		return err
+	}
+	if meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+		eventType := corev1.EventTypeNormal
+		if condition.Status == metav1.ConditionFalse {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(cluster, eventType, condition.Reason, condition.Message)
+	}
+	return nil
+}
+
+// sloRuleGroups records the error ratio of every SLO over the alert
+// windows and alerts on fast and slow burn of its error budget
+func sloRuleGroups(cluster *swarmv1alpha1.SwarmCluster, slos []clusterSLO) []interface{} {
+	var rules []interface{}
+	for _, slo := range slos {
+		selector := fmt.Sprintf(`namespace=%q,swarm_cluster=%q,slo=%q`, cluster.Namespace, cluster.Name, slo.Name)
+		for _, window := range []string{"5m", "30m", "1h", "6h"} {
+			rules = append(rules, map[string]interface{}{
+				"record": "swarm:task_slo_error_ratio:rate" + window,
+				"expr": fmt.Sprintf(
+					`sum by (namespace, swarm_cluster, slo) (rate(swarm_task_slo_events_total{%s,result="bad"}[%s])) / `+
+						`sum by (namespace, swarm_cluster, slo) (rate(swarm_task_slo_events_total{%s}[%s]))`,
+					selector, window, selector, window),
+			})
+		}
+
+		budget := 1 - slo.objective
+		for _, alert := range []struct {
+			name, long, short, severity, pending, speed string
+			burnRate                                    float64
+		}{
+			{"SwarmTaskSLOFastBurn", "1h", "5m", "critical", "2m", "fast", sloPageBurnRate},
+			{"SwarmTaskSLOSlowBurn", "6h", "30m", "warning", "15m", "steadily", sloTicketBurnRate},
+		} {
+			threshold := strconv.FormatFloat(alert.burnRate*budget, 'g', 6, 64)
+			rules = append(rules, map[string]interface{}{
+				"alert": alert.name,
+				"expr": fmt.Sprintf(`swarm:task_slo_error_ratio:rate%s{%s} > %s and swarm:task_slo_error_ratio:rate%s{%s} > %s`,
+					alert.long, selector, threshold, alert.short, selector, threshold),
+				"for": alert.pending,
+				"labels": map[string]interface{}{
+					"severity": alert.severity,
+					"slo":      slo.Name,
+				},
+				"annotations": map[string]interface{}{
+					"summary": fmt.Sprintf("SwarmCluster %s/%s is spending the error budget of SLO %s %s",
+						cluster.Namespace, cluster.Name, slo.Name, alert.speed),
+					"description": fmt.Sprintf("More than %s%% of %s tasks missed the %s threshold over the last %s, objective %s%%.",
+						strconv.FormatFloat(alert.burnRate*budget*100, 'g', 4, 64), sloStage(slo.TaskSLO), slo.Threshold.Duration, alert.long,
+						strconv.FormatFloat(slo.percent, 'f', -1, 64)),
+				},
+			})
+		}
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"name":  fmt.Sprintf("swarm-slos.%s.%s", cluster.Namespace, cluster.Name),
+			"rules": rules,
+		},
+	}
+}
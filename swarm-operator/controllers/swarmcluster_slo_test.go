@@ -0,0 +1,241 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+// uninstalledKinds fails requests for unstructured kinds the scheme does
+// not know with a NoKindMatchError, like an API server without their CRDs.
+// The fake client itself accepts any unstructured kind.
+func uninstalledKinds(scheme *runtime.Scheme) interceptor.Funcs {
+	check := func(obj runtime.Object) error {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Empty() || scheme.Recognizes(gvk) {
+			return nil
+		}
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := check(obj); err != nil {
+				return err
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if err := check(list); err != nil {
+				return err
+			}
+			return c.List(ctx, list, opts...)
+		},
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if err := check(obj); err != nil {
+				return err
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if err := check(obj); err != nil {
+				return err
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := check(obj); err != nil {
+				return err
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if err := check(obj); err != nil {
+				return err
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+	}
+}
+
+var _ = Describe("Task SLOs", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		cluster    *swarmv1alpha1.SwarmCluster
+		recorder   *metrics.MetricsRecorder
+		reconciler *SwarmClusterReconciler
+	)
+
+	build := func() {
+		k8sClient := newTestClientBuilder().
+			WithScheme(scheme).
+			WithObjects(cluster).
+			WithInterceptorFuncs(uninstalledKinds(scheme)).
+			Build()
+		reconciler = &SwarmClusterReconciler{
+			Client:          k8sClient,
+			Scheme:          scheme,
+			Recorder:        record.NewFakeRecorder(10),
+			MetricsRecorder: recorder,
+		}
+	}
+
+	getRule := func() (*unstructured.Unstructured, error) {
+		rule := &unstructured.Unstructured{}
+		rule.SetGroupVersionKind(prometheusRuleGVK)
+		err := reconciler.Get(ctx, types.NamespacedName{Name: "swarm-slos", Namespace: "default"}, rule)
+		return rule, err
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = newTestScheme()
+		recorder = metrics.NewMetricsRecorder()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Monitoring: &swarmv1alpha1.MonitoringSpec{
+					SLOs: []swarmv1alpha1.TaskSLO{
+						{
+							Name:       "high-priority-dispatch",
+							Stage:      swarmv1alpha1.DispatchStage,
+							Priorities: []swarmv1alpha1.TaskPriority{swarmv1alpha1.HighPriority},
+							Threshold:  metav1.Duration{Duration: 30 * time.Second},
+							Objective:  "95",
+						},
+						{
+							Name:      "completion",
+							Threshold: metav1.Duration{Duration: 30 * time.Minute},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("records tasks against the SLOs of the stage and priority they match", func() {
+		build()
+		taskReconciler := &SwarmTaskReconciler{MetricsRecorder: recorder}
+		created := time.Now().Add(-time.Minute)
+		task := func(priority swarmv1alpha1.TaskPriority) *swarmv1alpha1.SwarmTask {
+			return &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "task", Namespace: "default", CreationTimestamp: metav1.Time{Time: created}},
+				Spec:       swarmv1alpha1.SwarmTaskSpec{Priority: priority},
+			}
+		}
+
+		// Dispatched after 10s and 50s, the low-priority task does not count
+		taskReconciler.recordTaskSLOs(task(swarmv1alpha1.HighPriority), cluster, swarmv1alpha1.DispatchStage, created.Add(10*time.Second), false)
+		taskReconciler.recordTaskSLOs(task(swarmv1alpha1.HighPriority), cluster, swarmv1alpha1.DispatchStage, created.Add(50*time.Second), false)
+		taskReconciler.recordTaskSLOs(task(swarmv1alpha1.LowPriority), cluster, swarmv1alpha1.DispatchStage, created.Add(50*time.Second), false)
+		// A fast failure still misses the completion SLO
+		taskReconciler.recordTaskSLOs(task(""), cluster, swarmv1alpha1.CompletionStage, created.Add(time.Minute), true)
+
+		Expect(reconciler.reconcileSLOs(ctx, cluster)).To(Succeed())
+
+		Expect(cluster.Status.SLOs).To(Equal([]swarmv1alpha1.SLOStatus{
+			{Name: "high-priority-dispatch", Events: 2, Compliance: "50.00", ErrorBudgetRemaining: "-900.0", BurnRate: "10.00"},
+			{Name: "completion", Events: 1, Compliance: "0.00", ErrorBudgetRemaining: "-9900.0", BurnRate: "100.00"},
+		}))
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeSLOsMet)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonErrorBudgetExhausted))
+		Expect(condition.Message).To(ContainSubstring("high-priority-dispatch: 50.00% of tasks met the 30s threshold, objective 95%"))
+	})
+
+	It("reports invalid SLOs and met SLOs without events", func() {
+		cluster.Spec.Monitoring.SLOs = append(cluster.Spec.Monitoring.SLOs, swarmv1alpha1.TaskSLO{
+			Name: "broken", Threshold: metav1.Duration{Duration: time.Second}, Objective: "100",
+		})
+		build()
+
+		Expect(reconciler.reconcileSLOs(ctx, cluster)).To(Succeed())
+		Expect(cluster.Status.SLOs).To(HaveLen(2))
+		Expect(cluster.Status.SLOs[0].Compliance).To(Equal("100.00"))
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeSLOsMet)
+		Expect(condition.Reason).To(Equal(ReasonInvalidSLO))
+		Expect(condition.Message).To(ContainSubstring("broken"))
+
+		cluster.Spec.Monitoring.SLOs = cluster.Spec.Monitoring.SLOs[:1]
+		Expect(reconciler.reconcileSLOs(ctx, cluster)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeSLOsMet)).To(BeTrue())
+		Expect(cluster.Status.SLOs).To(HaveLen(1))
+	})
+
+	It("reports a missing Prometheus operator instead of failing", func() {
+		cluster.Spec.Monitoring.AlertRules = true
+		build()
+
+		Expect(reconciler.reconcileSLOs(ctx, cluster)).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeAlertRulesReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonPrometheusOperatorMissing))
+	})
+
+	Context("with the Prometheus operator installed", func() {
+		BeforeEach(func() {
+			scheme.AddKnownTypeWithName(prometheusRuleGVK, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(prometheusRuleGVK.GroupVersion().WithKind("PrometheusRuleList"), &unstructured.UnstructuredList{})
+			cluster.Spec.Monitoring.AlertRules = true
+			cluster.Spec.Monitoring.RuleLabels = map[string]string{"release": "prometheus"}
+		})
+
+		It("generates burn-rate alerts and removes them once disabled", func() {
+			build()
+			Expect(reconciler.reconcileSLOs(ctx, cluster)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeAlertRulesReady)).To(BeTrue())
+
+			rule, err := getRule()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rule.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+			Expect(rule.GetOwnerReferences()).To(HaveLen(1))
+			groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+			Expect(groups).To(HaveLen(1))
+			rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+			// Four recording rules and two alerts per SLO
+			Expect(rules).To(HaveLen(12))
+			fastBurn := rules[4].(map[string]interface{})
+			Expect(fastBurn["alert"]).To(Equal("SwarmTaskSLOFastBurn"))
+			Expect(fastBurn["expr"]).To(Equal(
+				`swarm:task_slo_error_ratio:rate1h{namespace="default",swarm_cluster="swarm",slo="high-priority-dispatch"} > 0.72 and ` +
+					`swarm:task_slo_error_ratio:rate5m{namespace="default",swarm_cluster="swarm",slo="high-priority-dispatch"} > 0.72`))
+
+			cluster.Spec.Monitoring.AlertRules = false
+			Expect(reconciler.reconcileSLOs(ctx, cluster)).To(Succeed())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeAlertRulesReady)).To(BeNil())
+			_, err = getRule()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// admitTenantCluster holds the cluster to the namespaces, registries and
+// cluster quota of its tenant and reports the outcome in the TenantAdmitted
+// condition. Clusters that are not admitted are not reconciled any further.
+func (r *SwarmClusterReconciler) admitTenantCluster(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (bool, error) {
+	name := cluster.Labels[tenantLabel]
+	reason, message, err := r.tenantClusterVerdict(ctx, cluster, name)
+	if err != nil {
+		return false, err
+	}
+
+	// Clusters outside any tenant carry no condition
+	if name == "" && reason == ReasonTenantAdmitted {
+		if meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeTenantAdmitted) {
+			if err := r.Status().Update(ctx, cluster); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	admitted := reason == ReasonTenantAdmitted
+	condition := metav1.Condition{
+		Type:               ConditionTypeTenantAdmitted,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cluster.Generation,
+	}
+	if !admitted {
+		condition.Status = metav1.ConditionFalse
+	}
+	if meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return false, err
+		}
+		eventType := corev1.EventTypeNormal
+		if !admitted {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(cluster, eventType, reason, message)
+	}
+	return admitted, nil
+}
+
+// tenantClusterVerdict returns the reason and message of the TenantAdmitted
+// condition for the cluster
+func (r *SwarmClusterReconciler) tenantClusterVerdict(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, name string) (string, string, error) {
+	tenant, err := getTenant(ctx, r, name)
+	if errors.IsNotFound(err) {
+		return ReasonTenantNotFound, fmt.Sprintf("SwarmTenant %s not found", name), nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	namespaces := []string{cluster.Namespace}
+	for ns := range r.swarmNamespaces(cluster) {
+		if ns != cluster.Namespace {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces[1:])
+	violation, err := tenantNamespaceViolation(ctx, r, tenant, namespaces...)
+	if err != nil {
+		return "", "", err
+	}
+	if violation != "" {
+		return ReasonTenantPolicyViolation, violation, nil
+	}
+	if tenant == nil {
+		return ReasonTenantAdmitted, "", nil
+	}
+
+	images := append([]string{cluster.Spec.AgentTemplate.Image}, agentTypeImages(cluster)...)
+	if cluster.Spec.HiveMind != nil {
+		images = append(images, cluster.Spec.HiveMind.Image)
+	}
+	if cluster.Spec.ExecutorImageRollout != nil {
+		images = append(images, cluster.Spec.ExecutorImageRollout.Image)
+	}
+	for _, image := range images {
+		if image != "" && !registryAllowed(tenant, image) {
+			return ReasonTenantPolicyViolation,
+				fmt.Sprintf("image %s is not from a registry allowed for tenant %s", image, tenant.Name), nil
+		}
+	}
+
+	if quota := tenant.Spec.Quota; quota != nil && quota.MaxClusters > 0 {
+		rank, err := tenantClusterRank(ctx, r, tenant, cluster)
+		if err != nil {
+			return "", "", err
+		}
+		if rank >= int(quota.MaxClusters) {
+			return ReasonTenantQuotaExceeded,
+				fmt.Sprintf("Tenant %s is limited to %d clusters", tenant.Name, quota.MaxClusters), nil
+		}
+	}
+
+	return ReasonTenantAdmitted, fmt.Sprintf("Admitted by tenant %s", tenant.Name), nil
+}
+
+// mapTenantToClusters enqueues every SwarmCluster of a SwarmTenant
+func (r *SwarmClusterReconciler) mapTenantToClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusterList := &swarmv1alpha1.SwarmClusterList{}
+	if err := r.List(ctx, clusterList, client.MatchingLabels{tenantLabel: obj.GetName()}); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(clusterList.Items))
+	for _, cluster := range clusterList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: cluster.Name, Namespace: cluster.Namespace},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,515 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/pki"
+)
+
+const (
+	// ConditionTypeCertificatesReady reports the health of the mTLS certificates
+	ConditionTypeCertificatesReady = "CertificatesReady"
+
+	ReasonCertificatesIssued     = "CertificatesIssued"
+	ReasonCertificatesPending    = "CertificatesPending"
+	ReasonCertificateIssueFailed = "CertificateIssueFailed"
+
+	selfSignedIssuer  = "SelfSigned"
+	certManagerIssuer = "CertManager"
+
+	defaultCertificateDuration    = 90 * 24 * time.Hour
+	defaultCertificateRenewBefore = 15 * 24 * time.Hour
+
+	// The per-cluster CA outlives its leaves by far and is rotated a year
+	// ahead of its expiry
+	caValidity    = 10 * 365 * 24 * time.Hour
+	caRenewBefore = 365 * 24 * time.Hour
+
+	// Components holding a certificate
+	agentTLSComponent    = "agent"
+	hiveMindTLSComponent = "hivemind"
+	memoryTLSComponent   = "memory"
+	operatorTLSComponent = "operator"
+
+	// tlsVolumeName and tlsMountPath are where pods find their certificate
+	tlsVolumeName = "swarm-tls"
+	tlsMountPath  = "/etc/swarm/tls"
+)
+
+// certificateGVK is the cert-manager Certificate kind, used unstructured so
+// the operator does not depend on cert-manager's API module
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// tlsEnabled reports whether the cluster runs with mTLS
+func tlsEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.TLS != nil && cluster.Spec.TLS.Enabled
+}
+
+// tlsSecretName is the Secret holding a component's certificate, key and CA
+func tlsSecretName(cluster *swarmv1alpha1.SwarmCluster, component string) string {
+	return fmt.Sprintf("%s-%s-tls", cluster.Name, component)
+}
+
+func caSecretName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return cluster.Name + "-ca"
+}
+
+// agentServerName is the name agents present and verify each other as.
+// Agents are dialed by pod IP, so their shared certificate carries this
+// name instead of addresses.
+func agentServerName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return fmt.Sprintf("%s-agent.%s.svc", cluster.Name, cluster.Namespace)
+}
+
+// hiveMindServerName is the name hive-mind executors present and verify
+// each other as
+func hiveMindServerName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return fmt.Sprintf("%s-hivemind.%s.svc", cluster.Name, cluster.Namespace)
+}
+
+// tlsComponent is a component with its own certificate
+type tlsComponent struct {
+	name      string
+	namespace string
+	request   pki.Request
+}
+
+// tlsComponents lists the certificates the cluster needs
+func (r *SwarmClusterReconciler) tlsComponents(cluster *swarmv1alpha1.SwarmCluster, validity time.Duration) []tlsComponent {
+	components := []tlsComponent{
+		{
+			name:      agentTLSComponent,
+			namespace: cluster.Namespace,
+			request: pki.Request{
+				CommonName: cluster.Name + "-agent",
+				DNSNames:   []string{agentServerName(cluster)},
+				Validity:   validity,
+			},
+		},
+		{
+			name:      hiveMindTLSComponent,
+			namespace: cluster.Namespace,
+			request: pki.Request{
+				CommonName: cluster.Name + "-hivemind",
+				DNSNames:   []string{hiveMindServerName(cluster)},
+				Validity:   validity,
+			},
+		},
+		{
+			name:      operatorTLSComponent,
+			namespace: cluster.Namespace,
+			request: pki.Request{
+				CommonName: "swarm-operator",
+				Validity:   validity,
+			},
+		},
+	}
+
+	if memoryStoreEnabled(cluster) {
+		// The memory service runs where SwarmMemoryStoreReconciler puts
+		// cluster-bound stores
+		memoryName := cluster.Name + "-memory"
+		serviceNamespace, _ := operatorNamespaces(r.Config, r.SwarmNamespace, r.HiveMindNamespace)
+		if serviceNamespace == "" {
+			serviceNamespace = r.getNamespaceForComponent(cluster, "memory")
+		}
+		components = append(components, tlsComponent{
+			name:      memoryTLSComponent,
+			namespace: r.getNamespaceForComponent(cluster, "memory"),
+			request: pki.Request{
+				CommonName: memoryName,
+				DNSNames: []string{
+					memoryName,
+					fmt.Sprintf("%s.%s", memoryName, serviceNamespace),
+					fmt.Sprintf("%s.%s.svc", memoryName, serviceNamespace),
+					fmt.Sprintf("%s.%s.svc.cluster.local", memoryName, serviceNamespace),
+				},
+				Validity: validity,
+			},
+		})
+	}
+	return components
+}
+
+// memoryStoreEnabled reports whether the cluster runs its own memory store
+func memoryStoreEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.Memory.Type == "sqlite" && cluster.Spec.Memory.EnableMemoryStore
+}
+
+// reconcileTLS issues and rotates the cluster's certificates and reports
+// their health in the CertificatesReady condition
+func (r *SwarmClusterReconciler) reconcileTLS(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	if !tlsEnabled(cluster) {
+		if meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeCertificatesReady) {
+			if err := r.Status().Update(ctx, cluster); err != nil {
+				return err
+			}
+		}
+		if memoryStoreEnabled(cluster) {
+			return r.syncMemoryStoreTLS(ctx, cluster, "")
+		}
+		return nil
+	}
+
+	duration := parseDurationOrDefault(cluster.Spec.TLS.Duration, defaultCertificateDuration)
+	renewBefore := parseDurationOrDefault(cluster.Spec.TLS.RenewBefore, defaultCertificateRenewBefore)
+	components := r.tlsComponents(cluster, duration)
+
+	var (
+		notAfter time.Time
+		err      error
+	)
+	if cluster.Spec.TLS.Issuer == certManagerIssuer {
+		notAfter, err = r.reconcileCertManagerCertificates(ctx, cluster, components, duration, renewBefore)
+	} else {
+		notAfter, err = r.reconcileSelfSignedCertificates(ctx, cluster, components, renewBefore, time.Now())
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeCertificatesReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonCertificatesIssued,
+		Message:            fmt.Sprintf("Certificates valid until %s", notAfter.UTC().Format(time.RFC3339)),
+		ObservedGeneration: cluster.Generation,
+	}
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonCertificateIssueFailed
+		condition.Message = err.Error()
+	case notAfter.IsZero():
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonCertificatesPending
+		condition.Message = "Waiting for cert-manager to issue the certificates"
+	case time.Now().After(notAfter):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonCertificateIssueFailed
+		condition.Message = fmt.Sprintf("Certificates expired at %s", notAfter.UTC().Format(time.RFC3339))
+	}
+
+	if meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+		eventType := corev1.EventTypeNormal
+		if condition.Status != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(cluster, eventType, condition.Reason, condition.Message)
+		if updateErr := r.Status().Update(ctx, cluster); updateErr != nil {
+			return updateErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if memoryStoreEnabled(cluster) {
+		return r.syncMemoryStoreTLS(ctx, cluster, tlsSecretName(cluster, memoryTLSComponent))
+	}
+	return nil
+}
+
+// reconcileSelfSignedCertificates keeps the cluster CA and every component
+// certificate current. It returns the earliest certificate expiry.
+func (r *SwarmClusterReconciler) reconcileSelfSignedCertificates(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, components []tlsComponent, renewBefore time.Duration, now time.Time) (time.Time, error) {
+	log := log.FromContext(ctx)
+
+	ca, err := r.ensureClusterCA(ctx, cluster, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to issue cluster CA: %w", err)
+	}
+	earliest, err := pki.NotAfter(ca.Cert)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, component := range components {
+		name := tlsSecretName(cluster, component.name)
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: component.namespace}, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return time.Time{}, err
+		}
+
+		if err == nil && pki.Matches(secret.Data[corev1.TLSCertKey], ca.Cert, component.request) {
+			renewAt, err := pki.RenewalTime(secret.Data[corev1.TLSCertKey], renewBefore)
+			if err == nil && now.Before(renewAt) {
+				notAfter, _ := pki.NotAfter(secret.Data[corev1.TLSCertKey])
+				if notAfter.Before(earliest) {
+					earliest = notAfter
+				}
+				continue
+			}
+		}
+
+		leaf, err := pki.Issue(ca, component.request, now)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to issue %s certificate: %w", component.name, err)
+		}
+		if err := r.applyTLSSecret(ctx, cluster, name, component.namespace, map[string][]byte{
+			corev1.TLSCertKey:       leaf.Cert,
+			corev1.TLSPrivateKeyKey: leaf.Key,
+			"ca.crt":                ca.Cert,
+		}); err != nil {
+			return time.Time{}, err
+		}
+		log.Info("Issued certificate", "component", component.name, "secret", name)
+
+		notAfter, err := pki.NotAfter(leaf.Cert)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if notAfter.Before(earliest) {
+			earliest = notAfter
+		}
+	}
+	return earliest, nil
+}
+
+// ensureClusterCA returns the cluster CA, creating or rotating it as needed.
+// Rotating the CA invalidates every leaf, which are then reissued.
+func (r *SwarmClusterReconciler) ensureClusterCA(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, now time.Time) (*pki.KeyPair, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: caSecretName(cluster), Namespace: cluster.Namespace}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		ca := &pki.KeyPair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+		if renewAt, err := pki.RenewalTime(ca.Cert, caRenewBefore); err == nil && now.Before(renewAt) {
+			return ca, nil
+		}
+	}
+
+	ca, err := pki.NewCA(fmt.Sprintf("%s.%s swarm CA", cluster.Name, cluster.Namespace), caValidity, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.applyTLSSecret(ctx, cluster, caSecretName(cluster), cluster.Namespace, map[string][]byte{
+		corev1.TLSCertKey:       ca.Cert,
+		corev1.TLSPrivateKeyKey: ca.Key,
+	}); err != nil {
+		return nil, err
+	}
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "CAIssued", "Issued a new cluster certificate authority")
+	return ca, nil
+}
+
+// applyTLSSecret creates or replaces a certificate Secret. Secrets in the
+// cluster's namespace are owned by the cluster.
+func (r *SwarmClusterReconciler) applyTLSSecret(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, name, namespace string, data map[string][]byte) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels["swarm-cluster"] = cluster.Name
+		secret.Type = corev1.SecretTypeTLS
+		secret.Data = data
+		if namespace == cluster.Namespace {
+			return controllerutil.SetControllerReference(cluster, secret, r.Scheme)
+		}
+		return nil
+	})
+	return err
+}
+
+// reconcileCertManagerCertificates requests the component certificates from
+// cert-manager, which also rotates them. It returns the earliest expiry of
+// the issued certificates, or zero while any is still pending.
+func (r *SwarmClusterReconciler) reconcileCertManagerCertificates(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, components []tlsComponent, duration, renewBefore time.Duration) (time.Time, error) {
+	issuerRef := cluster.Spec.TLS.IssuerRef
+	if issuerRef == nil || issuerRef.Name == "" {
+		return time.Time{}, fmt.Errorf("tls.issuerRef is required with the %s issuer", certManagerIssuer)
+	}
+	kind := issuerRef.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+
+	var earliest time.Time
+	pending := false
+	for _, component := range components {
+		name := tlsSecretName(cluster, component.name)
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(certificateGVK)
+		certificate.SetName(name)
+		certificate.SetNamespace(component.namespace)
+
+		dnsNames := make([]interface{}, 0, len(component.request.DNSNames))
+		for _, dnsName := range component.request.DNSNames {
+			dnsNames = append(dnsNames, dnsName)
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, certificate, func() error {
+			certificate.SetLabels(map[string]string{"swarm-cluster": cluster.Name})
+			if err := unstructured.SetNestedMap(certificate.Object, map[string]interface{}{
+				"secretName":  name,
+				"commonName":  component.request.CommonName,
+				"dnsNames":    dnsNames,
+				"duration":    duration.String(),
+				"renewBefore": renewBefore.String(),
+				"usages":      []interface{}{"server auth", "client auth"},
+				"privateKey":  map[string]interface{}{"algorithm": "ECDSA", "size": int64(256), "rotationPolicy": "Always"},
+				"issuerRef":   map[string]interface{}{"name": issuerRef.Name, "kind": kind, "group": "cert-manager.io"},
+			}, "spec"); err != nil {
+				return err
+			}
+			if component.namespace == cluster.Namespace {
+				return controllerutil.SetControllerReference(cluster, certificate, r.Scheme)
+			}
+			return nil
+		}); err != nil {
+			if meta.IsNoMatchError(err) {
+				return time.Time{}, fmt.Errorf("cert-manager is not installed: %w", err)
+			}
+			return time.Time{}, err
+		}
+
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: component.namespace}, secret); err != nil {
+			if errors.IsNotFound(err) {
+				pending = true
+				continue
+			}
+			return time.Time{}, err
+		}
+		notAfter, err := pki.NotAfter(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			pending = true
+			continue
+		}
+		if earliest.IsZero() || notAfter.Before(earliest) {
+			earliest = notAfter
+		}
+	}
+	if pending {
+		return time.Time{}, nil
+	}
+	return earliest, nil
+}
+
+// syncMemoryStoreTLS points the cluster's memory store at its certificate,
+// or back to plaintext for an empty secretName
+func (r *SwarmClusterReconciler) syncMemoryStoreTLS(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, secretName string) error {
+	store := &swarmv1alpha1.SwarmMemoryStore{}
+	err := r.Get(ctx, types.NamespacedName{
+		Name:      cluster.Name + "-memory",
+		Namespace: r.getNamespaceForComponent(cluster, "memory"),
+	}, store)
+	if errors.IsNotFound(err) {
+		// Created with the secret name later on
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if store.Spec.TLSSecretName == secretName {
+		return nil
+	}
+	store.Spec.TLSSecretName = secretName
+	return r.Update(ctx, store)
+}
+
+// applyPodTLS mounts a certificate Secret into the named containers and
+// tells them to require mTLS. serverName, if set, is the name peers of the
+// same component are verified as.
+func applyPodTLS(podSpec *corev1.PodSpec, secretName, serverName string, containers ...string) {
+	mode := int32(0440)
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: tlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: secretName, DefaultMode: &mode},
+		},
+	})
+
+	env := []corev1.EnvVar{
+		{Name: "SWARM_TLS_ENABLED", Value: "true"},
+		{Name: "SWARM_TLS_REQUIRE_CLIENT_CERT", Value: "true"},
+		{Name: "SWARM_TLS_CERT_FILE", Value: tlsMountPath + "/" + corev1.TLSCertKey},
+		{Name: "SWARM_TLS_KEY_FILE", Value: tlsMountPath + "/" + corev1.TLSPrivateKeyKey},
+		{Name: "SWARM_TLS_CA_FILE", Value: tlsMountPath + "/ca.crt"},
+	}
+	if serverName != "" {
+		env = append(env, corev1.EnvVar{Name: "SWARM_TLS_SERVER_NAME", Value: serverName})
+	}
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if !containsString(containers, container.Name) {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      tlsVolumeName,
+			MountPath: tlsMountPath,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, env...)
+	}
+}
+
+// applyAgentTLS mounts the agent certificate into the agent and its memory
+// proxy
+func applyAgentTLS(cluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) {
+	if !tlsEnabled(cluster) {
+		return
+	}
+	applyPodTLS(podSpec, tlsSecretName(cluster, agentTLSComponent), agentServerName(cluster),
+		agentContainerName, memoryProxyContainerName)
+}
+
+// copyTLSSecret mirrors a certificate Secret into another namespace for
+// pods that run outside the namespace it was issued to, returning false
+// while the source is not issued yet
+func copyTLSSecret(ctx context.Context, c client.Client, name, sourceNamespace, namespace string) (bool, error) {
+	source := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: sourceNamespace}, source); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if namespace == sourceNamespace {
+		return true, nil
+	}
+
+	copied := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, copied, func() error {
+		copied.Labels = map[string]string{
+			"swarm-cluster":                source.Labels["swarm-cluster"],
+			"swarm.claudeflow.io/tls-copy": strings.Join([]string{sourceNamespace, name}, "."),
+		}
+		copied.Type = source.Type
+		copied.Data = source.Data
+		return nil
+	})
+	return err == nil, err
+}
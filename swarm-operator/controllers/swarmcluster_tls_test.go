@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/pki"
+)
+
+var _ = Describe("Cluster TLS", func() {
+	var (
+		ctx        context.Context
+		k8sClient  client.Client
+		reconciler *SwarmClusterReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+	)
+
+	secret := func(name string) *corev1.Secret {
+		s := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, s)).To(Succeed())
+		return s
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default", UID: "uid"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				TLS: &swarmv1alpha1.TLSSpec{Enabled: true, Issuer: selfSignedIssuer},
+			},
+		}
+		k8sClient = newTestClient(cluster)
+		reconciler = &SwarmClusterReconciler{
+			Client:   k8sClient,
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+	})
+
+	It("issues a CA and a certificate per component", func() {
+		Expect(reconciler.reconcileTLS(ctx, cluster)).To(Succeed())
+
+		ca := secret("swarm-ca")
+		for _, component := range []string{agentTLSComponent, hiveMindTLSComponent, operatorTLSComponent} {
+			leaf := secret(tlsSecretName(cluster, component))
+			Expect(leaf.Type).To(Equal(corev1.SecretTypeTLS))
+			Expect(leaf.Data["ca.crt"]).To(Equal(ca.Data[corev1.TLSCertKey]))
+			Expect(metav1.IsControlledBy(leaf, cluster)).To(BeTrue())
+		}
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeCertificatesReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(ReasonCertificatesIssued))
+	})
+
+	It("keeps valid certificates and reissues them for a new CA", func() {
+		components := reconciler.tlsComponents(cluster, defaultCertificateDuration)
+		now := time.Now()
+		_, err := reconciler.reconcileSelfSignedCertificates(ctx, cluster, components, defaultCertificateRenewBefore, now)
+		Expect(err).NotTo(HaveOccurred())
+		issued := secret(tlsSecretName(cluster, agentTLSComponent)).Data[corev1.TLSCertKey]
+
+		_, err = reconciler.reconcileSelfSignedCertificates(ctx, cluster, components, defaultCertificateRenewBefore, now.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secret(tlsSecretName(cluster, agentTLSComponent)).Data[corev1.TLSCertKey]).To(Equal(issued))
+
+		// Past the renewal time the leaf is rotated
+		_, err = reconciler.reconcileSelfSignedCertificates(ctx, cluster, components, defaultCertificateRenewBefore, now.Add(80*24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		rotated := secret(tlsSecretName(cluster, agentTLSComponent)).Data[corev1.TLSCertKey]
+		Expect(rotated).NotTo(Equal(issued))
+
+		// A rotated CA invalidates every leaf
+		Expect(k8sClient.Delete(ctx, secret("swarm-ca"))).To(Succeed())
+		_, err = reconciler.reconcileSelfSignedCertificates(ctx, cluster, components, defaultCertificateRenewBefore, now.Add(80*24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		leaf := secret(tlsSecretName(cluster, agentTLSComponent))
+		Expect(leaf.Data[corev1.TLSCertKey]).NotTo(Equal(rotated))
+		Expect(pki.Matches(leaf.Data[corev1.TLSCertKey], secret("swarm-ca").Data[corev1.TLSCertKey], components[0].request)).To(BeTrue())
+	})
+
+	It("reports a missing cert-manager issuer", func() {
+		cluster.Spec.TLS = &swarmv1alpha1.TLSSpec{Enabled: true, Issuer: certManagerIssuer}
+		Expect(reconciler.reconcileTLS(ctx, cluster)).NotTo(Succeed())
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeCertificatesReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonCertificateIssueFailed))
+	})
+
+	It("mounts certificates into the listed containers only", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: agentContainerName}, {Name: "user-sidecar"}}}
+		applyAgentTLS(cluster, podSpec)
+
+		Expect(podSpec.Volumes).To(HaveLen(1))
+		Expect(podSpec.Volumes[0].Secret.SecretName).To(Equal("swarm-agent-tls"))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ConsistOf(HaveField("MountPath", tlsMountPath)))
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "SWARM_TLS_SERVER_NAME", Value: "swarm-agent.default.svc"}))
+		Expect(podSpec.Containers[1].VolumeMounts).To(BeEmpty())
+		Expect(podSpec.Containers[1].Env).To(BeEmpty())
+	})
+})
@@ -0,0 +1,254 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/topology"
+)
+
+const (
+	// ConditionTypeTopologyReady reports whether every agent has the peers
+	// of spec.topology
+	ConditionTypeTopologyReady = "TopologyReady"
+
+	ReasonTopologyApplied      = "TopologyApplied"
+	ReasonTopologyMigrating    = "TopologyMigrating"
+	ReasonTopologyIncompatible = "TopologyIncompatible"
+
+	defaultTopologyBatchSize     = 2
+	defaultTopologyBatchInterval = 30 * time.Second
+)
+
+// appliedTopology returns the topology the agents were configured with,
+// and false before the cluster set up its first one
+func appliedTopology(cluster *swarmv1alpha1.SwarmCluster) (swarmv1alpha1.SwarmTopology, bool) {
+	if cluster.Status.TopologyStatus["configured"] != "true" {
+		return "", false
+	}
+	return swarmv1alpha1.SwarmTopology(cluster.Status.TopologyStatus["type"]), true
+}
+
+// topologyMigrating reports whether agents are moving to a new topology
+func topologyMigrating(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Status.TopologyMigration != nil
+}
+
+// topologyBatch returns the batch size and interval of topology migrations
+func topologyBatch(cluster *swarmv1alpha1.SwarmCluster) (int, time.Duration) {
+	spec := cluster.Spec.TopologyMigration
+	if spec == nil {
+		return defaultTopologyBatchSize, defaultTopologyBatchInterval
+	}
+	size := int(spec.BatchSize)
+	if size < 1 {
+		size = defaultTopologyBatchSize
+	}
+	return size, parseDurationOrDefault(spec.BatchInterval, defaultTopologyBatchInterval)
+}
+
+// topologyMigrationOrder sorts the agents that still need their new peers
+// by name, with the queen and coordinators last so the coordination of the
+// swarm moves once everyone else can reach it
+func topologyMigrationOrder(agents []*swarmv1alpha1.Agent) {
+	rank := func(agent *swarmv1alpha1.Agent) int {
+		switch {
+		case isQueen(agent):
+			return 2
+		case agent.Spec.Type == swarmv1alpha1.CoordinatorAgent:
+			return 1
+		}
+		return 0
+	}
+	sort.SliceStable(agents, func(i, j int) bool {
+		if rank(agents[i]) != rank(agents[j]) {
+			return rank(agents[i]) < rank(agents[j])
+		}
+		return agents[i].Name < agents[j].Name
+	})
+}
+
+// agentsSettled reports whether the named agents are ready again. Agents
+// that went away count as settled.
+func agentsSettled(agents []swarmv1alpha1.Agent, names []string) bool {
+	for _, name := range names {
+		for i := range agents {
+			if agents[i].Name == name && agents[i].Status.Phase != "Ready" && agents[i].Status.Phase != "Busy" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// restartingForTopology reports whether the agent restarts with the peers
+// of a new topology
+func restartingForTopology(cluster *swarmv1alpha1.SwarmCluster, name string) bool {
+	if !topologyMigrating(cluster) {
+		return false
+	}
+	for _, agent := range cluster.Status.TopologyMigration.LastBatch {
+		if agent == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileTopology moves the agents of a running cluster to a changed
+// spec.topology. The new peer map is applied a batch of agents at a time,
+// waiting for each batch to be ready again, so agents not moved yet keep
+// talking to their old peers. The queen is never re-elected for the move,
+// hierarchical and star topologies are built around it. A topology the
+// current agents cannot form is refused and the old one kept.
+func (r *SwarmClusterReconciler) reconcileTopology(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent) error {
+	log := log.FromContext(ctx)
+
+	applied, configured := appliedTopology(cluster)
+	if !configured {
+		return nil
+	}
+	desired := cluster.Spec.Topology
+	if applied == desired && !topologyMigrating(cluster) {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeTopologyReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonTopologyApplied,
+			Message:            fmt.Sprintf("Agents use the %s topology", desired),
+			ObservedGeneration: cluster.Generation,
+		})
+		return nil
+	}
+
+	active := 0
+	for i := range agents {
+		if agents[i].GetDeletionTimestamp() == nil && agents[i].Status.Phase != "Failed" && agents[i].Status.Phase != "Terminating" {
+			active++
+		}
+	}
+	manager, err := topology.ForCluster(cluster)
+	if err == nil {
+		err = manager.ValidateTopology(active)
+	}
+	if err != nil {
+		if meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeTopologyReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonTopologyIncompatible,
+			Message:            fmt.Sprintf("Keeping the %s topology: %v", applied, err),
+			ObservedGeneration: cluster.Generation,
+		}) {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, ReasonTopologyIncompatible,
+				"Refused the %s topology: %v", desired, err)
+		}
+		return nil
+	}
+
+	migration := cluster.Status.TopologyMigration
+	now := time.Now()
+	if migration == nil || migration.To != desired {
+		// A change during a migration starts over from where the agents
+		// are, agents already moved to the abandoned topology move again
+		migration = &swarmv1alpha1.TopologyMigrationStatus{From: applied, To: desired, StartTime: metav1.NewTime(now)}
+		cluster.Status.TopologyMigration = migration
+		log.Info("Migrating topology", "from", applied, "to", desired)
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, ReasonTopologyMigrating,
+			"Moving %d agents from the %s to the %s topology", len(agents), applied, desired)
+	}
+
+	peerMap := manager.CalculatePeers(agents)
+	var stale []*swarmv1alpha1.Agent
+	for i := range agents {
+		agent := &agents[i]
+		if !equality.Semantic.DeepEqual(agent.Spec.CommunicationEndpoints.Peers, peerMap[agent.Name]) {
+			stale = append(stale, agent)
+		}
+	}
+	migration.RemainingAgents = int32(len(stale))
+
+	// The agents of the last batch restart with their new peers first
+	settled := agentsSettled(agents, migration.LastBatch)
+	if len(stale) == 0 && settled {
+		cluster.Status.TopologyStatus["type"] = string(desired)
+		cluster.Status.TopologyStatus["lastUpdate"] = now.Format(time.RFC3339)
+		cluster.Status.TopologyMigration = nil
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeTopologyReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonTopologyApplied,
+			Message:            fmt.Sprintf("Agents use the %s topology", desired),
+			ObservedGeneration: cluster.Generation,
+		})
+		log.Info("Migrated topology", "from", migration.From, "to", desired)
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "TopologyMigrated",
+			"Moved %d agents from the %s to the %s topology", migration.MigratedAgents, migration.From, desired)
+		return nil
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:   ConditionTypeTopologyReady,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonTopologyMigrating,
+		Message: fmt.Sprintf("Moving from the %s to the %s topology, %d agents left",
+			migration.From, desired, len(stale)),
+		ObservedGeneration: cluster.Generation,
+	})
+
+	size, interval := topologyBatch(cluster)
+	if len(stale) == 0 || !settled ||
+		(migration.LastBatchTime != nil && now.Sub(migration.LastBatchTime.Time) < interval) {
+		return nil
+	}
+
+	topologyMigrationOrder(stale)
+	// The queen moves in a batch of its own
+	batch := stale
+	if len(batch) > size {
+		batch = batch[:size]
+	}
+	for i, agent := range batch {
+		if isQueen(agent) && i > 0 {
+			batch = batch[:i]
+			break
+		}
+	}
+
+	migration.LastBatch = nil
+	for _, agent := range batch {
+		agent.Spec.CommunicationEndpoints.Peers = peerMap[agent.Name]
+		if err := r.Update(ctx, agent); err != nil {
+			return err
+		}
+		migration.LastBatch = append(migration.LastBatch, agent.Name)
+	}
+	migration.LastBatchTime = &metav1.Time{Time: now}
+	migration.MigratedAgents += int32(len(batch))
+	migration.RemainingAgents -= int32(len(batch))
+	log.Info("Moved agents to the new topology", "agents", migration.LastBatch, "remaining", migration.RemainingAgents)
+	return nil
+}
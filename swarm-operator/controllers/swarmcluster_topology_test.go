@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/topology"
+)
+
+var _ = Describe("Topology migration", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	setup := func(names ...string) {
+		var agents []swarmv1alpha1.Agent
+		for _, name := range names {
+			agentType := swarmv1alpha1.CoderAgent
+			if name == "queen" {
+				agentType = swarmv1alpha1.CoordinatorAgent
+			}
+			agents = append(agents, swarmv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"swarm-cluster": "swarm"}},
+				Spec: swarmv1alpha1.AgentSpec{
+					Type:                   agentType,
+					CommunicationEndpoints: swarmv1alpha1.CommunicationSpec{Port: 8080},
+				},
+				Status: swarmv1alpha1.AgentStatus{Phase: "Ready"},
+			})
+		}
+		mesh := topology.NewManager(string(swarmv1alpha1.MeshTopology)).CalculatePeers(agents)
+		builder := newTestClientBuilder()
+		for i := range agents {
+			agents[i].Spec.CommunicationEndpoints.Peers = mesh[agents[i].Name]
+			builder = builder.WithObjects(&agents[i])
+		}
+		reconciler = &SwarmClusterReconciler{
+			Client:   builder.Build(),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(20),
+		}
+	}
+	reconcile := func() []swarmv1alpha1.Agent {
+		agents := &swarmv1alpha1.AgentList{}
+		Expect(reconciler.List(ctx, agents)).To(Succeed())
+		Expect(reconciler.reconcileTopology(ctx, cluster, agents.Items)).To(Succeed())
+		Expect(reconciler.List(ctx, agents)).To(Succeed())
+		return agents.Items
+	}
+	peerCount := func(agents []swarmv1alpha1.Agent, name string) int {
+		for _, agent := range agents {
+			if agent.Name == name {
+				return len(agent.Spec.CommunicationEndpoints.Peers)
+			}
+		}
+		return -1
+	}
+	setPhase := func(name, phase string) {
+		agent := &swarmv1alpha1.Agent{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, agent)).To(Succeed())
+		agent.Status.Phase = phase
+		Expect(reconciler.Status().Update(ctx, agent)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default", Generation: 2},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Topology:          swarmv1alpha1.HierarchicalTopology,
+				TopologyMigration: &swarmv1alpha1.TopologyMigrationSpec{BatchSize: 2, BatchInterval: "1ns"},
+			},
+			Status: swarmv1alpha1.SwarmClusterStatus{
+				TopologyStatus: map[string]string{"configured": "true", "type": "mesh"},
+			},
+		}
+	})
+
+	It("moves the agents to the new topology a batch at a time", func() {
+		setup("agent-a", "agent-b", "agent-c", "queen", "agent-d")
+
+		agents := reconcile()
+		migration := cluster.Status.TopologyMigration
+		Expect(migration.From).To(Equal(swarmv1alpha1.MeshTopology))
+		Expect(migration.LastBatch).To(Equal([]string{"agent-a", "agent-b"}))
+		Expect(migration.RemainingAgents).To(Equal(int32(3)))
+		Expect(peerCount(agents, "agent-c")).To(Equal(4))
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeTopologyReady).Reason).
+			To(Equal(ReasonTopologyMigrating))
+
+		// The next batch waits for the last one to be ready again
+		setPhase("agent-a", "Initializing")
+		reconcile()
+		Expect(cluster.Status.TopologyMigration.LastBatch).To(Equal([]string{"agent-a", "agent-b"}))
+		setPhase("agent-a", "Ready")
+
+		reconcile()
+		Expect(cluster.Status.TopologyMigration.LastBatch).To(Equal([]string{"agent-c", "agent-d"}))
+		agents = reconcile()
+		Expect(cluster.Status.TopologyMigration.LastBatch).To(Equal([]string{"queen"}))
+		Expect(peerCount(agents, "queen")).To(Equal(2))
+
+		agents = reconcile()
+		Expect(cluster.Status.TopologyMigration).To(BeNil())
+		Expect(cluster.Status.TopologyStatus["type"]).To(Equal("hierarchical"))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeTopologyReady)).To(BeTrue())
+		Expect(peerCount(agents, "agent-d")).To(Equal(1))
+	})
+
+	It("keeps the queen during its restart and moves it last on its own", func() {
+		cluster.Spec.TopologyMigration.BatchSize = 10
+		setup("agent-a", "agent-b", "agent-c", "queen")
+		queen := &swarmv1alpha1.Agent{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "queen", Namespace: "default"}, queen)).To(Succeed())
+		queen.Labels[swarmv1alpha1.QueenLabel] = "true"
+		Expect(reconciler.Update(ctx, queen)).To(Succeed())
+
+		reconcile()
+		Expect(cluster.Status.TopologyMigration.LastBatch).To(Equal([]string{"agent-a", "agent-b", "agent-c"}))
+		Expect(restartingForTopology(cluster, "queen")).To(BeFalse())
+		reconcile()
+		Expect(cluster.Status.TopologyMigration.LastBatch).To(Equal([]string{"queen"}))
+		Expect(restartingForTopology(cluster, "queen")).To(BeTrue())
+	})
+
+	It("refuses a topology the agents cannot form", func() {
+		cluster.Spec.Topology = swarmv1alpha1.RingTopology
+		setup("agent-a", "agent-b")
+
+		agents := reconcile()
+		Expect(cluster.Status.TopologyMigration).To(BeNil())
+		Expect(peerCount(agents, "agent-a")).To(Equal(1))
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeTopologyReady)
+		Expect(condition.Reason).To(Equal(ReasonTopologyIncompatible))
+		Expect(condition.Message).To(Equal("Keeping the mesh topology: ring topology requires at least 3 agents, got 2"))
+	})
+
+	It("lets the message bus reach the old peers while agents move", func() {
+		peers := withCurrentPeers(map[string][]string{"agent-a": {"b"}}, []swarmv1alpha1.Agent{{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-a"},
+			Spec:       swarmv1alpha1.AgentSpec{CommunicationEndpoints: swarmv1alpha1.CommunicationSpec{Peers: []string{"b", "c"}}},
+		}})
+		Expect(peers["agent-a"]).To(Equal([]string{"b", "c"}))
+	})
+})
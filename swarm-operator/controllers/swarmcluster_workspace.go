@@ -0,0 +1,311 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+const (
+	// ConditionTypeSharedWorkspaceReady reports whether the shared
+	// workspace can be mounted in every namespace of the cluster
+	ConditionTypeSharedWorkspaceReady = "SharedWorkspaceReady"
+
+	ReasonSharedWorkspaceBound   = "SharedWorkspaceBound"
+	ReasonSharedWorkspaceMounted = "SharedWorkspaceMounted"
+	ReasonSharedWorkspacePending = "SharedWorkspacePending"
+
+	// sharedWorkspaceVolumeName is the pod volume of the shared workspace
+	sharedWorkspaceVolumeName = "shared-workspace"
+
+	// sharedWorkspaceLabel marks the claims and volumes provisioned for the
+	// shared workspace of the cluster it names
+	sharedWorkspaceLabel = "swarm.claudeflow.io/shared-workspace"
+
+	defaultSharedWorkspacePath = "/shared"
+	defaultSharedWorkspaceSize = "10Gi"
+)
+
+// sharedWorkspaceClaimName is the claim of the shared workspace in every
+// namespace of the cluster
+func sharedWorkspaceClaimName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return cluster.Name + "-shared-workspace"
+}
+
+// sharedWorkspaceProvisioned reports whether the operator provisions a
+// claim for the shared workspace rather than mounting NFS or CSI directly
+func sharedWorkspaceProvisioned(workspace *swarmv1alpha1.SharedWorkspaceSpec) bool {
+	return workspace != nil && workspace.NFS == nil && workspace.CSI == nil
+}
+
+// applySharedWorkspace mounts the shared workspace of the cluster into the
+// first container of the pod, the agent or task container
+func applySharedWorkspace(cluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) {
+	if cluster == nil || cluster.Spec.SharedWorkspace == nil || len(podSpec.Containers) == 0 {
+		return
+	}
+	workspace := cluster.Spec.SharedWorkspace
+	mountPath := workspace.MountPath
+	if mountPath == "" {
+		mountPath = defaultSharedWorkspacePath
+	}
+
+	volume := corev1.Volume{Name: sharedWorkspaceVolumeName}
+	switch {
+	case workspace.NFS != nil:
+		volume.NFS = workspace.NFS.DeepCopy()
+	case workspace.CSI != nil:
+		volume.CSI = workspace.CSI.DeepCopy()
+	default:
+		volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: sharedWorkspaceClaimName(cluster)}
+	}
+	podSpec.Volumes = append(podSpec.Volumes, volume)
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: sharedWorkspaceVolumeName, MountPath: mountPath})
+	container.Env = append(container.Env, corev1.EnvVar{Name: executor.EnvSharedWorkspace, Value: mountPath})
+}
+
+// checkSharedWorkspace fails building the Job of a task whose namespace
+// has no claim of the shared workspace, which the cluster provisions in its
+// own, swarm and hive-mind namespaces only
+func (r *SwarmTaskReconciler) checkSharedWorkspace(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string) error {
+	if cluster == nil || !sharedWorkspaceProvisioned(cluster.Spec.SharedWorkspace) {
+		return nil
+	}
+	claim := types.NamespacedName{Name: sharedWorkspaceClaimName(cluster), Namespace: namespace}
+	err := r.Get(ctx, claim, &corev1.PersistentVolumeClaim{})
+	if errors.IsNotFound(err) {
+		err = fmt.Errorf("the shared workspace of SwarmCluster %s is not provisioned in namespace %s yet", cluster.Name, namespace)
+		r.Recorder.Event(task, corev1.EventTypeWarning, "SharedWorkspaceMissing", err.Error())
+	}
+	return err
+}
+
+// sharedWorkspaceNamespaces lists the namespaces agents and tasks of the
+// cluster run in, other than the cluster's own
+func (r *SwarmClusterReconciler) sharedWorkspaceNamespaces(cluster *swarmv1alpha1.SwarmCluster) []string {
+	var namespaces []string
+	for ns := range r.swarmNamespaces(cluster) {
+		if ns != cluster.Namespace {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// reconcileSharedWorkspace provisions the claim of the shared workspace in
+// the cluster's namespace. A claim is bound to a single volume in a single
+// namespace, so once it is bound the swarm and hive-mind namespaces get a
+// claim of their own, bound to a copy of the volume that points at the
+// same storage and retains it.
+func (r *SwarmClusterReconciler) reconcileSharedWorkspace(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	workspace := cluster.Spec.SharedWorkspace
+	if workspace == nil {
+		if meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeSharedWorkspaceReady) == nil {
+			return nil
+		}
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, ConditionTypeSharedWorkspaceReady)
+		return r.Status().Update(ctx, cluster)
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeSharedWorkspaceReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonSharedWorkspaceMounted,
+		Message:            "Agents and tasks mount the workspace directly",
+		ObservedGeneration: cluster.Generation,
+	}
+	if sharedWorkspaceProvisioned(workspace) {
+		claim, err := r.ensureSharedWorkspaceClaim(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		if claim.Status.Phase != corev1.ClaimBound || claim.Spec.VolumeName == "" {
+			condition.Status, condition.Reason = metav1.ConditionFalse, ReasonSharedWorkspacePending
+			condition.Message = fmt.Sprintf("Waiting for PersistentVolumeClaim %s to bind", claim.Name)
+		} else {
+			volume := &corev1.PersistentVolume{}
+			if err := r.Get(ctx, types.NamespacedName{Name: claim.Spec.VolumeName}, volume); err != nil {
+				return err
+			}
+			namespaces := r.sharedWorkspaceNamespaces(cluster)
+			for _, ns := range namespaces {
+				if err := r.ensureSharedWorkspaceReplica(ctx, cluster, volume, ns); err != nil {
+					return fmt.Errorf("shared workspace in namespace %s: %w", ns, err)
+				}
+			}
+			condition.Reason = ReasonSharedWorkspaceBound
+			condition.Message = fmt.Sprintf("PersistentVolume %s is shared by %d namespaces", volume.Name, len(namespaces)+1)
+		}
+	}
+
+	if !meta.SetStatusCondition(&cluster.Status.Conditions, condition) {
+		return nil
+	}
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, condition.Reason, condition.Message)
+	return r.Status().Update(ctx, cluster)
+}
+
+// ensureSharedWorkspaceClaim creates the ReadWriteMany claim of the shared
+// workspace in the cluster's namespace, deleted with the cluster
+func (r *SwarmClusterReconciler) ensureSharedWorkspaceClaim(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (*corev1.PersistentVolumeClaim, error) {
+	claim := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: sharedWorkspaceClaimName(cluster), Namespace: cluster.Namespace}, claim)
+	if err == nil || !errors.IsNotFound(err) {
+		return claim, err
+	}
+
+	workspace := cluster.Spec.SharedWorkspace
+	size := workspace.Size
+	if size == "" {
+		size = defaultSharedWorkspaceSize
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shared workspace size %q: %w", size, err)
+	}
+	claim = &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sharedWorkspaceClaimName(cluster),
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{"swarm-cluster": cluster.Name, sharedWorkspaceLabel: cluster.Name},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
+			},
+		},
+	}
+	if workspace.StorageClassName != "" {
+		claim.Spec.StorageClassName = &workspace.StorageClassName
+	}
+	if err := controllerutil.SetControllerReference(cluster, claim, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, claim); err != nil {
+		return nil, err
+	}
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "SharedWorkspaceCreated",
+		fmt.Sprintf("Created PersistentVolumeClaim %s for the shared workspace", claim.Name))
+	return claim, nil
+}
+
+// ensureSharedWorkspaceReplica binds a claim in the namespace to a copy of
+// the shared workspace volume. Neither can be owned by the cluster across
+// namespaces, so they carry the owner annotation and are deleted when the
+// cluster is finalized; the copy retains the data either way.
+func (r *SwarmClusterReconciler) ensureSharedWorkspaceReplica(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, volume *corev1.PersistentVolume, namespace string) error {
+	claimName := sharedWorkspaceClaimName(cluster)
+	err := r.Get(ctx, types.NamespacedName{Name: claimName, Namespace: namespace}, &corev1.PersistentVolumeClaim{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	labels := map[string]string{"swarm-cluster": cluster.Name, sharedWorkspaceLabel: cluster.Name}
+	annotations := map[string]string{namespaceOwnerAnnotation: namespaceOwner(cluster)}
+	replica := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%s", volume.Name, namespace),
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			Capacity:                      volume.Spec.Capacity,
+			PersistentVolumeSource:        *volume.Spec.PersistentVolumeSource.DeepCopy(),
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+			StorageClassName:              volume.Spec.StorageClassName,
+			MountOptions:                  volume.Spec.MountOptions,
+			VolumeMode:                    volume.Spec.VolumeMode,
+			ClaimRef:                      &corev1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: namespace, Name: claimName},
+		},
+	}
+	if err := r.Create(ctx, replica); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	storageClass := volume.Spec.StorageClassName
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        claimName,
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			StorageClassName: &storageClass,
+			VolumeName:       replica.Name,
+			VolumeMode:       volume.Spec.VolumeMode,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: volume.Spec.Capacity[corev1.ResourceStorage]},
+			},
+		},
+	}
+	return r.Create(ctx, claim)
+}
+
+// cleanupSharedWorkspace deletes the claims and volume copies of the shared
+// workspace outside the cluster's namespace. The claim in the cluster's
+// namespace is owned by the cluster.
+func (r *SwarmClusterReconciler) cleanupSharedWorkspace(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	selector := client.MatchingLabels{sharedWorkspaceLabel: cluster.Name}
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, claims, selector); err != nil {
+		return err
+	}
+	for i := range claims.Items {
+		claim := &claims.Items[i]
+		if claim.Annotations[namespaceOwnerAnnotation] != namespaceOwner(cluster) {
+			continue
+		}
+		if err := r.Delete(ctx, claim); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	volumes := &corev1.PersistentVolumeList{}
+	if err := r.List(ctx, volumes, selector); err != nil {
+		return err
+	}
+	for i := range volumes.Items {
+		volume := &volumes.Items[i]
+		if volume.Annotations[namespaceOwnerAnnotation] != namespaceOwner(cluster) {
+			continue
+		}
+		if err := r.Delete(ctx, volume); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
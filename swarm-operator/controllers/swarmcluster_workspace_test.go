@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Shared workspace", func() {
+	var (
+		ctx        context.Context
+		k8sClient  client.Client
+		reconciler *SwarmClusterReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+	)
+
+	claim := func(namespace string) (*corev1.PersistentVolumeClaim, error) {
+		claim := &corev1.PersistentVolumeClaim{}
+		return claim, k8sClient.Get(ctx, types.NamespacedName{Name: "swarm-shared-workspace", Namespace: namespace}, claim)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default", UID: "uid"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				NamespaceConfig: &swarmv1alpha1.NamespaceConfig{
+					SwarmNamespace:    "team-swarm",
+					HiveMindNamespace: "default",
+				},
+				SharedWorkspace: &swarmv1alpha1.SharedWorkspaceSpec{
+					MountPath:        "/handoff",
+					Size:             "50Gi",
+					StorageClassName: "efs",
+				},
+			},
+		}
+		k8sClient = newTestClientBuilder().
+			WithObjects(cluster).WithStatusSubresource(&corev1.PersistentVolumeClaim{}).
+			Build()
+		reconciler = &SwarmClusterReconciler{
+			Client:   k8sClient,
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+	})
+
+	It("shares the provisioned claim with the swarm namespace once it is bound", func() {
+		Expect(reconciler.reconcileSharedWorkspace(ctx, cluster)).To(Succeed())
+		home, err := claim("default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(home.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+		Expect(*home.Spec.StorageClassName).To(Equal("efs"))
+		Expect(home.Spec.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("50Gi")))
+		Expect(home.OwnerReferences).To(HaveLen(1))
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, ConditionTypeSharedWorkspaceReady)).To(BeTrue())
+		_, err = claim("team-swarm")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// The storage provisioner binds the claim
+		volume := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc-123"},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("50Gi")},
+				AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+				StorageClassName:              "efs",
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: "efs.csi.aws.com", VolumeHandle: "fs-1::fsap-1"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, volume)).To(Succeed())
+		home.Spec.VolumeName = volume.Name
+		Expect(k8sClient.Update(ctx, home)).To(Succeed())
+		home.Status.Phase = corev1.ClaimBound
+		Expect(k8sClient.Status().Update(ctx, home)).To(Succeed())
+
+		Expect(reconciler.reconcileSharedWorkspace(ctx, cluster)).To(Succeed())
+		replica := &corev1.PersistentVolume{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "pvc-123-team-swarm"}, replica)).To(Succeed())
+		Expect(replica.Spec.CSI.VolumeHandle).To(Equal("fs-1::fsap-1"))
+		Expect(replica.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+		Expect(replica.Spec.ClaimRef.Namespace).To(Equal("team-swarm"))
+
+		swarmClaim, err := claim("team-swarm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(swarmClaim.Spec.VolumeName).To(Equal(replica.Name))
+		Expect(*swarmClaim.Spec.StorageClassName).To(Equal("efs"))
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeSharedWorkspaceReady)).To(BeTrue())
+
+		// Finalizing the cluster removes what it cannot own
+		Expect(reconciler.cleanupSharedWorkspace(ctx, cluster)).To(Succeed())
+		_, err = claim("team-swarm")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: replica.Name}, replica))).To(BeTrue())
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: volume.Name}, volume)).To(Succeed())
+		_, err = claim("default")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("mounts the workspace into agent and task pods", func() {
+		podSpec, err := constructAgentPodSpec(cluster, swarmv1alpha1.CoderAgent, defaultAgentPort, swarmv1alpha1.ResourceRequirements{}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+			Name: sharedWorkspaceVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "swarm-shared-workspace"},
+			},
+		}))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: sharedWorkspaceVolumeName, MountPath: "/handoff"}))
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: executor.EnvSharedWorkspace, Value: "/handoff"}))
+
+		cluster.Spec.SharedWorkspace = &swarmv1alpha1.SharedWorkspaceSpec{
+			NFS: &corev1.NFSVolumeSource{Server: "nfs.storage.svc", Path: "/exports/swarm"},
+		}
+		taskSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applySharedWorkspace(cluster, taskSpec)
+		Expect(taskSpec.Volumes[0].NFS.Server).To(Equal("nfs.storage.svc"))
+		Expect(taskSpec.Containers[0].VolumeMounts[0].MountPath).To(Equal(defaultSharedWorkspacePath))
+
+		Expect(reconciler.reconcileSharedWorkspace(ctx, cluster)).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeSharedWorkspaceReady)
+		Expect(condition.Reason).To(Equal(ReasonSharedWorkspaceMounted))
+		_, err = claim("default")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("holds tasks in namespaces without the claim", func() {
+		recorder := record.NewFakeRecorder(10)
+		taskReconciler := &SwarmTaskReconciler{Client: k8sClient, Scheme: reconciler.Scheme, Recorder: recorder}
+		task := &swarmv1alpha1.SwarmTask{ObjectMeta: metav1.ObjectMeta{Name: "handoff", Namespace: "default"}}
+
+		err := taskReconciler.checkSharedWorkspace(ctx, task, cluster, "elsewhere")
+		Expect(err).To(MatchError("the shared workspace of SwarmCluster swarm is not provisioned in namespace elsewhere yet"))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning SharedWorkspaceMissing")))
+
+		Expect(reconciler.reconcileSharedWorkspace(ctx, cluster)).To(Succeed())
+		Expect(taskReconciler.checkSharedWorkspace(ctx, task, cluster, "default")).To(Succeed())
+	})
+})
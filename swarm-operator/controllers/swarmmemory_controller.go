@@ -0,0 +1,331 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+const (
+	// memoryEntryFinalizer removes the value from the backend before the
+	// SwarmMemory object goes away
+	memoryEntryFinalizer = "swarm.claudeflow.io/memory-entry"
+
+	// memoryStatsInterval is how often access counts are pulled from the backend
+	memoryStatsInterval = time.Minute
+
+	memoryPhasePending = "Pending"
+	memoryPhaseActive  = "Active"
+	memoryPhaseExpired = "Expired"
+
+	memoryExpiryRetain = "Retain"
+
+	// ConditionTypeSynced reports whether the entry is stored in the backend
+	ConditionTypeSynced = "Synced"
+
+	ReasonEntryWritten    = "EntryWritten"
+	ReasonNoMemoryBackend = "NoMemoryBackend"
+	ReasonBackendError    = "BackendError"
+	ReasonEntryExpired    = "EntryExpired"
+)
+
+// SwarmMemoryReconciler writes SwarmMemory entries to the memory service of
+// their cluster and enforces their TTL
+type SwarmMemoryReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MetricsRecorder records reconcile durations, disabled when nil
+	MetricsRecorder *metrics.MetricsRecorder
+
+	// NewBackend connects to a memory service endpoint, defaults to the HTTP
+	// backend
+	NewBackend func(endpoint string) memorycache.Backend
+}
+
+//+kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemories,verbs=get;list;watch;update;patch;delete
+//+kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemories/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemories/finalizers,verbs=update
+//+kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemorystores,verbs=get;list;watch
+
+// Reconcile pushes the entry into the backend, expires it once its TTL has
+// elapsed and mirrors backend access counts into status
+func (r *SwarmMemoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmMemory", req.NamespacedName)
+	logger := log.FromContext(ctx)
+
+	memory := &swarmv1alpha1.SwarmMemory{}
+	if err := r.Get(ctx, req.NamespacedName, memory); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	backend, store, err := r.memoryBackend(ctx, memory)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if memory.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, r.handleMemoryDelete(ctx, memory, backend)
+	}
+
+	if !containsString(memory.GetFinalizers(), memoryEntryFinalizer) {
+		memory.SetFinalizers(append(memory.GetFinalizers(), memoryEntryFinalizer))
+		if err := r.Update(ctx, memory); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if memory.Status.Phase == memoryPhaseExpired && memory.Status.ObservedGeneration == memory.Generation {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	if synced := meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeSynced); synced == nil && !memory.CreationTimestamp.IsZero() {
+		memory.Status.ExpiresAt = memoryExpiry(memory, memory.CreationTimestamp.Time)
+	} else if synced == nil || synced.ObservedGeneration != memory.Generation {
+		// A rewritten entry starts a fresh TTL
+		memory.Status.ExpiresAt = memoryExpiry(memory, now)
+	}
+
+	if memory.Status.ExpiresAt != nil && !now.Before(memory.Status.ExpiresAt.Time) {
+		return ctrl.Result{}, r.expireMemory(ctx, memory, backend)
+	}
+
+	if backend == nil {
+		memory.Status.Phase = memoryPhasePending
+		memory.Status.StorageBackend = ""
+		setMemorySynced(memory, metav1.ConditionFalse, ReasonNoMemoryBackend,
+			"No SwarmMemoryStore with an HTTP endpoint serves cluster "+memory.Spec.ClusterRef)
+		if err := r.Status().Update(ctx, memory); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: memoryRequeue(memory, now)}, nil
+	}
+
+	if memory.Status.ObservedGeneration != memory.Generation || !meta.IsStatusConditionTrue(memory.Status.Conditions, ConditionTypeSynced) {
+		if err := backend.Put(ctx, memory.Spec.Namespace, memory.Spec.Key, memoryEntry(memory, now)); err != nil {
+			logger.Error(err, "Failed to write memory entry", "key", memory.Spec.Key)
+			setMemorySynced(memory, metav1.ConditionFalse, ReasonBackendError, err.Error())
+			if updateErr := r.Status().Update(ctx, memory); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, err
+		}
+		memory.Status.Size, memory.Status.CompressedSize = memorySizes(memory)
+		memory.Status.ObservedGeneration = memory.Generation
+		memory.Status.StorageBackend = store
+		memory.Status.Phase = memoryPhaseActive
+		setMemorySynced(memory, metav1.ConditionTrue, ReasonEntryWritten, "Entry written to "+store)
+	}
+
+	if stats, ok := backend.(memorycache.StatsBackend); ok {
+		s, err := stats.Stats(ctx, memory.Spec.Namespace, memory.Spec.Key)
+		switch {
+		case err == nil:
+			memory.Status.AccessCount = s.AccessCount
+			if s.LastAccessTime != nil {
+				t := metav1.NewTime(*s.LastAccessTime)
+				memory.Status.LastAccessTime = &t
+			}
+		case errors.Is(err, memorycache.ErrNotFound):
+			// The backend lost the entry, write it again on the next pass
+			setMemorySynced(memory, metav1.ConditionFalse, ReasonBackendError, "Entry missing from "+store)
+		default:
+			logger.V(1).Info("Failed to read memory stats", "key", memory.Spec.Key, "error", err)
+		}
+	}
+
+	if err := r.Status().Update(ctx, memory); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: memoryRequeue(memory, now)}, nil
+}
+
+// memoryBackend returns the backend of the first SwarmMemoryStore of the
+// entry's cluster that publishes an HTTP endpoint, or nil if there is none
+func (r *SwarmMemoryReconciler) memoryBackend(ctx context.Context, memory *swarmv1alpha1.SwarmMemory) (memorycache.Backend, string, error) {
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := r.List(ctx, stores, client.InNamespace(memory.Namespace)); err != nil {
+		return nil, "", err
+	}
+	for i := range stores.Items {
+		store := &stores.Items[i]
+		if store.Spec.SwarmClusterRef != memory.Spec.ClusterRef || store.Status.Endpoints.HTTP == "" {
+			continue
+		}
+		if r.NewBackend != nil {
+			return r.NewBackend(store.Status.Endpoints.HTTP), store.Name, nil
+		}
+		return memorycache.NewHTTPBackend(store.Status.Endpoints.HTTP), store.Name, nil
+	}
+	return nil, "", nil
+}
+
+// expireMemory drops the value from the backend and deletes the entry, or
+// keeps it in the Expired phase when the expiry policy retains it
+func (r *SwarmMemoryReconciler) expireMemory(ctx context.Context, memory *swarmv1alpha1.SwarmMemory, backend memorycache.Backend) error {
+	if backend != nil {
+		if err := backend.Delete(ctx, memory.Spec.Namespace, memory.Spec.Key); err != nil && !errors.Is(err, memorycache.ErrNotFound) {
+			return err
+		}
+	}
+
+	if memory.Spec.ExpiryPolicy != memoryExpiryRetain {
+		r.Recorder.Event(memory, corev1.EventTypeNormal, ReasonEntryExpired, "TTL elapsed, deleting entry")
+		// The value is already gone, skip the finalizer round trip
+		memory.SetFinalizers(removeString(memory.GetFinalizers(), memoryEntryFinalizer))
+		if err := r.Update(ctx, memory); err != nil {
+			return err
+		}
+		return client.IgnoreNotFound(r.Delete(ctx, memory))
+	}
+
+	memory.Status.Phase = memoryPhaseExpired
+	memory.Status.ObservedGeneration = memory.Generation
+	if setMemorySynced(memory, metav1.ConditionFalse, ReasonEntryExpired, "TTL elapsed, entry removed from the backend") {
+		r.Recorder.Event(memory, corev1.EventTypeNormal, ReasonEntryExpired, "TTL elapsed, entry retained as Expired")
+	}
+	return r.Status().Update(ctx, memory)
+}
+
+// handleMemoryDelete removes the value from the backend and releases the
+// finalizer. Entries whose cluster has no memory store are released as is.
+func (r *SwarmMemoryReconciler) handleMemoryDelete(ctx context.Context, memory *swarmv1alpha1.SwarmMemory, backend memorycache.Backend) error {
+	if !containsString(memory.GetFinalizers(), memoryEntryFinalizer) {
+		return nil
+	}
+	if backend != nil {
+		if err := backend.Delete(ctx, memory.Spec.Namespace, memory.Spec.Key); err != nil && !errors.Is(err, memorycache.ErrNotFound) {
+			return err
+		}
+	}
+	memory.SetFinalizers(removeString(memory.GetFinalizers(), memoryEntryFinalizer))
+	return r.Update(ctx, memory)
+}
+
+// memoryExpiry is when the entry expires counting from from, nil for
+// permanent entries. New entries count from creation so entries created
+// while the operator was down are not kept alive past their TTL.
+func memoryExpiry(memory *swarmv1alpha1.SwarmMemory, from time.Time) *metav1.Time {
+	if memory.Spec.TTL <= 0 {
+		return nil
+	}
+	expiresAt := metav1.NewTime(from.Add(time.Duration(memory.Spec.TTL) * time.Second))
+	return &expiresAt
+}
+
+// memoryEntry builds the backend entry, passing on the remaining TTL so the
+// backend expires the value even if the operator is unavailable
+func memoryEntry(memory *swarmv1alpha1.SwarmMemory, now time.Time) *memorycache.Entry {
+	entry := &memorycache.Entry{Value: memory.Spec.Value}
+	if memory.Status.ExpiresAt != nil {
+		remaining := memory.Status.ExpiresAt.Sub(now)
+		entry.TTL = int32((remaining + time.Second - 1) / time.Second)
+	}
+	return entry
+}
+
+// memorySizes returns the value size and, for compressed entries, its
+// gzip-compressed size
+func memorySizes(memory *swarmv1alpha1.SwarmMemory) (int64, int64) {
+	size := int64(len(memory.Spec.Value))
+	if !memory.Spec.Compression {
+		return size, 0
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(memory.Spec.Value))
+	_ = zw.Close()
+	return size, int64(buf.Len())
+}
+
+// memoryRequeue is when to look at the entry again: at expiry or the next
+// stats refresh, whichever comes first
+func memoryRequeue(memory *swarmv1alpha1.SwarmMemory, now time.Time) time.Duration {
+	requeue := memoryStatsInterval
+	if memory.Status.ExpiresAt != nil {
+		if untilExpiry := memory.Status.ExpiresAt.Sub(now); untilExpiry < requeue {
+			requeue = untilExpiry
+		}
+	}
+	if requeue < time.Second {
+		requeue = time.Second
+	}
+	return requeue
+}
+
+func setMemorySynced(memory *swarmv1alpha1.SwarmMemory, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeSynced,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: memory.Generation,
+	})
+}
+
+// mapStoreToMemories requeues the entries of a memory store's cluster when
+// the store publishes or changes its endpoint
+func (r *SwarmMemoryReconciler) mapStoreToMemories(ctx context.Context, obj client.Object) []reconcile.Request {
+	store, ok := obj.(*swarmv1alpha1.SwarmMemoryStore)
+	if !ok {
+		return nil
+	}
+	memories := &swarmv1alpha1.SwarmMemoryList{}
+	if err := r.List(ctx, memories, client.InNamespace(store.Namespace)); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, memory := range memories.Items {
+		if memory.Spec.ClusterRef == store.Spec.SwarmClusterRef {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: memory.Name, Namespace: memory.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwarmMemoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swarmv1alpha1.SwarmMemory{}).
+		Watches(&swarmv1alpha1.SwarmMemoryStore{}, handler.EnqueueRequestsFromMapFunc(r.mapStoreToMemories)).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmmemory", r))
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+)
+
+type memoryBackendStub struct {
+	entries  map[string]*memorycache.Entry
+	accessed map[string]int64
+}
+
+func (b *memoryBackendStub) Get(_ context.Context, namespace, key string) (*memorycache.Entry, error) {
+	e, ok := b.entries[namespace+"/"+key]
+	if !ok {
+		return nil, memorycache.ErrNotFound
+	}
+	return e, nil
+}
+
+func (b *memoryBackendStub) Put(_ context.Context, namespace, key string, e *memorycache.Entry) error {
+	b.entries[namespace+"/"+key] = e
+	return nil
+}
+
+func (b *memoryBackendStub) Delete(_ context.Context, namespace, key string) error {
+	delete(b.entries, namespace+"/"+key)
+	return nil
+}
+
+func (b *memoryBackendStub) Stats(_ context.Context, namespace, key string) (*memorycache.EntryStats, error) {
+	if _, ok := b.entries[namespace+"/"+key]; !ok {
+		return nil, memorycache.ErrNotFound
+	}
+	return &memorycache.EntryStats{AccessCount: b.accessed[namespace+"/"+key]}, nil
+}
+
+var _ = Describe("SwarmMemory controller", func() {
+	var (
+		ctx     context.Context
+		backend *memoryBackendStub
+		store   *swarmv1alpha1.SwarmMemoryStore
+	)
+
+	entry := func(ttl int32, age time.Duration) *swarmv1alpha1.SwarmMemory {
+		return &swarmv1alpha1.SwarmMemory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "notes",
+				Namespace:         "default",
+				Generation:        1,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec: swarmv1alpha1.SwarmMemorySpec{
+				ClusterRef: "swarm",
+				Namespace:  "shared",
+				Key:        "notes",
+				Value:      "remember the milk",
+				TTL:        ttl,
+			},
+		}
+	}
+
+	reconcilerFor := func(objects ...client.Object) *SwarmMemoryReconciler {
+		k8sClient := newTestClient(objects...)
+		return &SwarmMemoryReconciler{
+			Client:     k8sClient,
+			Scheme:     testScheme,
+			Recorder:   record.NewFakeRecorder(10),
+			NewBackend: func(string) memorycache.Backend { return backend },
+		}
+	}
+
+	reconcileMemory := func(r *SwarmMemoryReconciler, memory *swarmv1alpha1.SwarmMemory) ctrl.Result {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(memory)})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = &memoryBackendStub{
+			entries:  map[string]*memorycache.Entry{},
+			accessed: map[string]int64{"shared/notes": 3},
+		}
+		store = &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "swarm"},
+			Status: swarmv1alpha1.SwarmMemoryStoreStatus{
+				Endpoints: swarmv1alpha1.SwarmMemoryEndpoints{HTTP: "http://swarm-memory.default.svc:8080"},
+			},
+		}
+	})
+
+	It("writes the entry with its remaining TTL and reports access counts", func() {
+		memory := entry(600, 5*time.Minute)
+		memory.Spec.Compression = true
+		r := reconcilerFor(store, memory)
+
+		result := reconcileMemory(r, memory)
+		Expect(result.RequeueAfter).To(Equal(memoryStatsInterval))
+
+		Expect(backend.entries).To(HaveKey("shared/notes"))
+		Expect(backend.entries["shared/notes"].Value).To(Equal("remember the milk"))
+		Expect(backend.entries["shared/notes"].TTL).To(BeNumerically("~", 300, 2))
+
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(memory), memory)).To(Succeed())
+		Expect(memory.Finalizers).To(ContainElement(memoryEntryFinalizer))
+		Expect(memory.Status.Phase).To(Equal(memoryPhaseActive))
+		Expect(memory.Status.StorageBackend).To(Equal("swarm-memory"))
+		Expect(memory.Status.Size).To(Equal(int64(len("remember the milk"))))
+		Expect(memory.Status.CompressedSize).To(BeNumerically(">", 0))
+		Expect(memory.Status.AccessCount).To(Equal(int64(3)))
+		Expect(memory.Status.ExpiresAt.Time).To(BeTemporally("~", time.Now().Add(5*time.Minute), 2*time.Second))
+		Expect(meta.IsStatusConditionTrue(memory.Status.Conditions, ConditionTypeSynced)).To(BeTrue())
+	})
+
+	It("stays pending until the cluster has a memory store endpoint", func() {
+		store.Status.Endpoints.HTTP = ""
+		memory := entry(0, 0)
+		r := reconcilerFor(store, memory)
+
+		reconcileMemory(r, memory)
+
+		Expect(backend.entries).To(BeEmpty())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(memory), memory)).To(Succeed())
+		Expect(memory.Status.Phase).To(Equal(memoryPhasePending))
+		condition := meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeSynced)
+		Expect(condition.Reason).To(Equal(ReasonNoMemoryBackend))
+	})
+
+	It("deletes entries whose TTL has elapsed", func() {
+		memory := entry(60, 2*time.Minute)
+		backend.entries["shared/notes"] = &memorycache.Entry{Value: "stale"}
+		r := reconcilerFor(store, memory)
+
+		reconcileMemory(r, memory)
+
+		Expect(backend.entries).To(BeEmpty())
+		err := r.Get(ctx, client.ObjectKeyFromObject(memory), &swarmv1alpha1.SwarmMemory{})
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("keeps expired entries marked Expired with the Retain policy", func() {
+		memory := entry(60, 2*time.Minute)
+		memory.Spec.ExpiryPolicy = memoryExpiryRetain
+		backend.entries["shared/notes"] = &memorycache.Entry{Value: "stale"}
+		r := reconcilerFor(store, memory)
+
+		reconcileMemory(r, memory)
+
+		Expect(backend.entries).To(BeEmpty())
+		Expect(r.Get(ctx, client.ObjectKeyFromObject(memory), memory)).To(Succeed())
+		Expect(memory.Status.Phase).To(Equal(memoryPhaseExpired))
+		condition := meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeSynced)
+		Expect(condition.Reason).To(Equal(ReasonEntryExpired))
+	})
+
+	It("removes the value from the backend when the entry is deleted", func() {
+		memory := entry(0, 0)
+		r := reconcilerFor(store, memory)
+		reconcileMemory(r, memory)
+		Expect(backend.entries).To(HaveKey("shared/notes"))
+
+		Expect(r.Delete(ctx, memory)).To(Succeed())
+		reconcileMemory(r, memory)
+
+		Expect(backend.entries).To(BeEmpty())
+		err := r.Get(ctx, client.ObjectKeyFromObject(memory), &swarmv1alpha1.SwarmMemory{})
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+)
+
+const (
+	memoryGRPCPort    = int32(9090)
+	memoryMetricsPort = int32(9091)
+	memoryHTTPPort    = int32(8080)
+
+	// agentCacheStatsInterval is how often memory proxy stats are collected
+	agentCacheStatsInterval = time.Minute
+)
+
+// agentCacheEnabled reports whether agents get a memory proxy sidecar
+func agentCacheEnabled(memory *swarmv1alpha1.SwarmMemoryStore) bool {
+	return memory.Spec.AgentCache != nil && memory.Spec.AgentCache.Enabled
+}
+
+// reconcileService exposes the memory service and publishes its endpoints
+func (r *SwarmMemoryStoreReconciler) reconcileService(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
+	svc := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: memory.Name, Namespace: namespace}, svc)
+	if errors.IsNotFound(err) {
+		svc = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      memory.Name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					"app":         "swarm-memory",
+					"memory-name": memory.Name,
+				},
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: memoryServiceSelector(memory),
+				Ports: []corev1.ServicePort{
+					{Name: "grpc", Port: memoryGRPCPort, TargetPort: intstr.FromString("grpc")},
+					{Name: "http", Port: memoryHTTPPort, TargetPort: intstr.FromString("http")},
+					{Name: "metrics", Port: memoryMetricsPort, TargetPort: intstr.FromString("metrics")},
+				},
+			},
+		}
+		if namespace == memory.Namespace {
+			if err := controllerutil.SetControllerReference(memory, svc, r.Scheme); err != nil {
+				return err
+			}
+		}
+		if err := r.Create(ctx, svc); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if selector := memoryServiceSelector(memory); !equality.Semantic.DeepEqual(svc.Spec.Selector, selector) {
+		// Writes follow the primary once replication is switched on
+		svc.Spec.Selector = selector
+		if err := r.Update(ctx, svc); err != nil {
+			return err
+		}
+	}
+
+	host := fmt.Sprintf("%s.%s.svc", memory.Name, namespace)
+	scheme := "http"
+	if memory.Spec.TLSSecretName != "" {
+		scheme = "https"
+	}
+	memory.Status.Endpoints = swarmv1alpha1.SwarmMemoryEndpoints{
+		GRPC: fmt.Sprintf("%s:%d", host, memoryGRPCPort),
+		HTTP: fmt.Sprintf("%s://%s:%d", scheme, host, memoryHTTPPort),
+		// Metrics stay plaintext for Prometheus
+		Metrics: fmt.Sprintf("http://%s:%d/metrics", host, memoryMetricsPort),
+	}
+	if replicationEnabled(memory) {
+		memory.Status.Endpoints.Read = fmt.Sprintf("%s://%s.%s.svc:%d", scheme, readServiceName(memory), namespace, memoryHTTPPort)
+	}
+	if vectorIndexEnabled(memory) {
+		memory.Status.Endpoints.Search = memorySearchEndpoint(memory)
+	}
+	return nil
+}
+
+// memoryServiceSelector selects the pods behind the memory Service, only
+// the primary when replication is enabled
+func memoryServiceSelector(memory *swarmv1alpha1.SwarmMemoryStore) map[string]string {
+	selector := map[string]string{
+		"app":         "swarm-memory",
+		"memory-name": memory.Name,
+	}
+	if replicationEnabled(memory) {
+		selector[memoryRoleLabel] = memoryRolePrimary
+	}
+	return selector
+}
+
+// collectAgentCacheStats scrapes the memory proxy of every agent pod of the
+// referenced cluster and records per-agent and overall hit rates
+func (r *SwarmMemoryStoreReconciler) collectAgentCacheStats(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore) {
+	logger := log.FromContext(ctx)
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods,
+		client.InNamespace(memory.Namespace),
+		client.MatchingLabels{"swarm-cluster": memory.Spec.SwarmClusterRef}); err != nil {
+		logger.Error(err, "Failed to list agent pods for cache stats")
+		return
+	}
+
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 2 * time.Second}
+	}
+
+	now := metav1.Now()
+	var caches []swarmv1alpha1.AgentCacheStatus
+	var hits, misses int64
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || !hasContainer(pod, memoryProxyContainerName) {
+			continue
+		}
+
+		stats, err := fetchCacheStats(ctx, httpClient, fmt.Sprintf("http://%s:%d/v1/stats", pod.Status.PodIP, memoryProxyPort))
+		if err != nil {
+			logger.V(1).Info("Failed to collect memory proxy stats", "pod", pod.Name, "error", err.Error())
+			continue
+		}
+
+		agent := pod.Labels["swarm.claudeflow.io/agent"]
+		if agent == "" {
+			agent = pod.Name
+		}
+		caches = append(caches, swarmv1alpha1.AgentCacheStatus{
+			Agent:       agent,
+			Entries:     int32(stats.Entries),
+			Hits:        stats.Hits,
+			Misses:      stats.Misses,
+			Evictions:   stats.Evictions,
+			HitRate:     stats.HitRate,
+			LastUpdated: &now,
+		})
+		hits += stats.Hits
+		misses += stats.Misses
+	}
+
+	sort.Slice(caches, func(i, j int) bool { return caches[i].Agent < caches[j].Agent })
+	memory.Status.AgentCaches = caches
+	if len(caches) > 0 {
+		memory.Status.CacheHitRate = memorycache.FormatHitRate(hits, misses)
+	}
+}
+
+func fetchCacheStats(ctx context.Context, httpClient *http.Client, url string) (*memorycache.Stats, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("memory proxy returned %s", resp.Status)
+	}
+
+	stats := &memorycache.Stats{}
+	if err := json.NewDecoder(resp.Body).Decode(stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func hasContainer(pod *corev1.Pod, name string) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
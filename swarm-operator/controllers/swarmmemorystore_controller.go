@@ -18,7 +18,9 @@ package controllers
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,11 +32,18 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
 )
 
 // SwarmMemoryStoreReconciler reconciles a SwarmMemoryStore object
@@ -42,6 +51,16 @@ type SwarmMemoryStoreReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
 	SwarmNamespace string
+
+	// Config hot-reloads the default namespace and storage class. When nil
+	// SwarmNamespace applies and claims use the cluster default class.
+	Config *operatorconfig.Store
+
+	// HTTPClient scrapes agent memory proxies, defaults to a 2s timeout client
+	HTTPClient *http.Client
+
+	// MetricsRecorder records reconcile durations, disabled when nil
+	MetricsRecorder *metrics.MetricsRecorder
 }
 
 //+kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemorystores,verbs=get;list;watch;create;update;patch;delete
@@ -49,11 +68,15 @@ type SwarmMemoryStoreReconciler struct {
 //+kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemorystores/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *SwarmMemoryStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmMemoryStore", req.NamespacedName)
 	logger := log.FromContext(ctx)
 
 	// Fetch the SwarmMemoryStore instance
@@ -81,6 +104,21 @@ func (r *SwarmMemoryStoreReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
+	// Hold stores whose storage size is not a quantity until it is fixed
+	if _, err := memoryStorageSize(memory); err != nil {
+		logger.Info("Invalid storage size", "storageSize", memory.Spec.StorageSize)
+		memory.Status.Phase = "Failed"
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeStorageConfigured,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonInvalidStorageSize,
+			Message:            err.Error(),
+			ObservedGeneration: memory.Generation,
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, memory)
+	}
+	meta.RemoveStatusCondition(&memory.Status.Conditions, ConditionTypeStorageConfigured)
+
 	// Determine namespace
 	namespace := r.determineNamespace(memory)
 
@@ -90,6 +128,12 @@ func (r *SwarmMemoryStoreReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	// Start or finish a key rotation before the StatefulSets follow it
+	if err := r.reconcileEncryption(ctx, memory, namespace); err != nil {
+		logger.Error(err, "Failed to reconcile encryption")
+		return ctrl.Result{}, err
+	}
+
 	// Reconcile ConfigMap with migration scripts
 	if err := r.reconcileConfigMap(ctx, memory, namespace); err != nil {
 		logger.Error(err, "Failed to reconcile ConfigMap")
@@ -102,6 +146,18 @@ func (r *SwarmMemoryStoreReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	// Expose the memory service to agents and their memory proxies
+	if err := r.reconcileService(ctx, memory, namespace); err != nil {
+		logger.Error(err, "Failed to reconcile Service")
+		return ctrl.Result{}, err
+	}
+
+	// Expose the followers for query traffic
+	if err := r.reconcileReadService(ctx, memory, namespace); err != nil {
+		logger.Error(err, "Failed to reconcile read Service")
+		return ctrl.Result{}, err
+	}
+
 	// Run migration if needed
 	if memory.Spec.MigrateFromLegacy {
 		if err := r.runMigration(ctx, memory, namespace); err != nil {
@@ -115,21 +171,63 @@ func (r *SwarmMemoryStoreReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	memory.Status.StorageReady = true
 	memory.Status.LastBackup = memory.Status.LastBackup // Keep existing value
 	memory.Status.DatabaseSize = r.getDatabaseSize(ctx, memory, namespace)
+	memory.Status.BackendLatencyMillis = r.probeBackendLatency(ctx, memory)
+	if agentCacheEnabled(memory) {
+		r.collectAgentCacheStats(ctx, memory)
+	}
+	if err := r.reconcileReplication(ctx, memory, namespace); err != nil {
+		logger.Error(err, "Failed to reconcile replication")
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileZoneSpread(ctx, memory, namespace); err != nil {
+		logger.Error(err, "Failed to reconcile zone spread")
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileMaintenance(ctx, memory, namespace); err != nil {
+		logger.Error(err, "Failed to reconcile maintenance")
+		return ctrl.Result{}, err
+	}
 	
 	if err := r.Status().Update(ctx, memory); err != nil {
 		logger.Error(err, "Failed to update SwarmMemoryStore status")
 		return ctrl.Result{}, err
 	}
 
+	// Bring the memory service back soon after its key is rotated
+	if keyRotating(memory) {
+		return ctrl.Result{RequeueAfter: keyRotationInterval}, nil
+	}
+
 	// Requeue for periodic backup check
 	if memory.Spec.BackupInterval != "" {
 		duration, _ := time.ParseDuration(memory.Spec.BackupInterval)
 		if duration > 0 {
+			if agentCacheEnabled(memory) && duration > agentCacheStatsInterval {
+				duration = agentCacheStatsInterval
+			}
+			if replicationEnabled(memory) && duration > replicationStatusInterval {
+				duration = replicationStatusInterval
+			}
+			if maintenanceEnabled(memory) && duration > memoryProbeInterval {
+				// Start maintenance Jobs soon after their window opens
+				duration = memoryProbeInterval
+			}
 			return ctrl.Result{RequeueAfter: duration}, nil
 		}
 	}
 
-	return ctrl.Result{}, nil
+	// Watch the primary for failover and keep replica lag fresh
+	if replicationEnabled(memory) {
+		return ctrl.Result{RequeueAfter: replicationStatusInterval}, nil
+	}
+
+	// Keep agent cache stats fresh
+	if agentCacheEnabled(memory) {
+		return ctrl.Result{RequeueAfter: agentCacheStatsInterval}, nil
+	}
+
+	// Keep the backend latency the cluster health score uses fresh
+	return ctrl.Result{RequeueAfter: memoryProbeInterval}, nil
 }
 
 func (r *SwarmMemoryStoreReconciler) determineNamespace(memory *swarmv1alpha1.SwarmMemoryStore) string {
@@ -142,16 +240,57 @@ func (r *SwarmMemoryStoreReconciler) determineNamespace(memory *swarmv1alpha1.Sw
 	if memory.Spec.SwarmClusterRef != "" {
 		// In a real implementation, we'd look up the SwarmCluster
 		// For now, use the default swarm namespace
-		return r.SwarmNamespace
+		return r.swarmNamespace()
 	}
 	
 	// Default to the configured swarm namespace
-	return r.SwarmNamespace
+	return r.swarmNamespace()
+}
+
+// clusterImageConfig returns the image config of the SwarmCluster the store
+// belongs to, nil for stores of no or a missing cluster
+func (r *SwarmMemoryStoreReconciler) clusterImageConfig(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore) (*swarmv1alpha1.ImageConfig, error) {
+	if memory.Spec.SwarmClusterRef == "" {
+		return nil, nil
+	}
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	err := r.Get(ctx, types.NamespacedName{Name: memory.Spec.SwarmClusterRef, Namespace: memory.Namespace}, cluster)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Spec.ImageConfig, nil
+}
+
+// swarmNamespace returns the default namespace of memory stores
+func (r *SwarmMemoryStoreReconciler) swarmNamespace() string {
+	namespace, _ := operatorNamespaces(r.Config, r.SwarmNamespace, "")
+	return namespace
+}
+
+// memoryStorageSize parses the storage size of the database claims
+func memoryStorageSize(memory *swarmv1alpha1.SwarmMemoryStore) (resource.Quantity, error) {
+	size := memory.Spec.StorageSize
+	if size == "" {
+		size = defaultMemoryStorageSize
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid storage size %q: %w", memory.Spec.StorageSize, err)
+	}
+	return quantity, nil
 }
 
 func (r *SwarmMemoryStoreReconciler) reconcilePVC(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
 	logger := log.FromContext(ctx)
-	
+
+	storageSize, err := memoryStorageSize(memory)
+	if err != nil {
+		return err
+	}
+
 	// Define PVC
 	pvc := &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -166,9 +305,9 @@ func (r *SwarmMemoryStoreReconciler) reconcilePVC(ctx context.Context, memory *s
 			AccessModes: []corev1.PersistentVolumeAccessMode{
 				corev1.ReadWriteOnce,
 			},
-			Resources: corev1.ResourceRequirements{
+			Resources: corev1.VolumeResourceRequirements{
 				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: resource.MustParse(memory.Spec.StorageSize),
+					corev1.ResourceStorage: storageSize,
 				},
 			},
 		},
@@ -176,11 +315,13 @@ func (r *SwarmMemoryStoreReconciler) reconcilePVC(ctx context.Context, memory *s
 	
 	if memory.Spec.StorageClass != "" {
 		pvc.Spec.StorageClassName = &memory.Spec.StorageClass
+	} else {
+		pvc.Spec.StorageClassName = operatorStorageClass(r.Config)
 	}
 	
 	// Check if PVC exists
 	foundPVC := &corev1.PersistentVolumeClaim{}
-	err := r.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, foundPVC)
+	err = r.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, foundPVC)
 	if err != nil && errors.IsNotFound(err) {
 		logger.Info("Creating PVC", "Name", pvc.Name, "Namespace", pvc.Namespace)
 		if err := r.Create(ctx, pvc); err != nil {
@@ -213,10 +354,25 @@ set -e
 # Initialize SQLite database directory
 mkdir -p /data/memory
 
+# Encrypted databases are opened with their key
+KEY=""
+if [ -n "${DB_KEY:-}" ]; then
+  . /scripts/db.sh
+  KEY=$(unwrap "$DB_KEY")
+  db() { sql /data/memory/swarm-memory.db "$KEY"; }
+else
+  db() { sqlite3 /data/memory/swarm-memory.db; }
+fi
+
 # Create initial database if it doesn't exist
 if [ ! -f /data/memory/swarm-memory.db ]; then
   echo "Initializing new SQLite database..."
-  sqlite3 /data/memory/swarm-memory.db < /scripts/schema.sql
+  db < /scripts/schema.sql
+fi
+
+# Add the embedding tables once the vector index is enabled
+if [ -f /scripts/vector.sql ]; then
+  db < /scripts/vector.sql
 fi
 
 echo "Database initialization complete"
@@ -241,6 +397,19 @@ echo "Migration complete"
 		},
 	}
 	
+	if vectorIndexEnabled(memory) {
+		cm.Data["vector.sql"] = getVectorSchema()
+	}
+	if maintenanceEnabled(memory) || memory.Spec.Encryption != nil || memory.Status.Encryption != nil {
+		cm.Data["db.sh"] = getDatabaseScript()
+	}
+	if maintenanceEnabled(memory) {
+		cm.Data["maintain.sh"] = getMaintenanceScript()
+	}
+	if memory.Spec.Encryption != nil || memory.Status.Encryption != nil {
+		cm.Data["rekey.sh"] = getRekeyScript()
+	}
+
 	// Check if ConfigMap exists
 	foundCM := &corev1.ConfigMap{}
 	err := r.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, foundCM)
@@ -251,6 +420,13 @@ echo "Migration complete"
 		}
 	} else if err != nil {
 		return err
+	} else if !equality.Semantic.DeepEqual(cm.Data, foundCM.Data) {
+		// Pick up the vector schema when the index is switched on
+		logger.Info("Updating ConfigMap", "Name", cm.Name, "Namespace", cm.Namespace)
+		foundCM.Data = cm.Data
+		if err := r.Update(ctx, foundCM); err != nil {
+			return err
+		}
 	}
 	
 	return nil
@@ -340,6 +516,10 @@ func (r *SwarmMemoryStoreReconciler) reconcileStatefulSet(ctx context.Context, m
 									Name:          "grpc",
 									ContainerPort: 9090,
 								},
+								{
+									Name:          "http",
+									ContainerPort: memoryHTTPPort,
+								},
 								{
 									Name:          "metrics",
 									ContainerPort: 9091,
@@ -389,9 +569,47 @@ func (r *SwarmMemoryStoreReconciler) reconcileStatefulSet(ctx context.Context, m
 		},
 	}
 	
+	// Keep followers out of the primary's zone so a zone outage leaves one
+	// to promote
+	applyFailureDomains(memory.Spec.FailureDomains, sts.Spec.Selector.MatchLabels, &sts.Spec.Template.Spec)
+
+	// Serve mTLS with the certificate issued by the SwarmCluster
+	if memory.Spec.TLSSecretName != "" {
+		issued, err := copyTLSSecret(ctx, r.Client, memory.Spec.TLSSecretName, memory.Namespace, namespace)
+		if err != nil {
+			return err
+		}
+		if !issued {
+			return fmt.Errorf("TLS secret %s has not been issued yet", memory.Spec.TLSSecretName)
+		}
+		applyPodTLS(&sts.Spec.Template.Spec, memory.Spec.TLSSecretName, "", "memory-service")
+	}
+	if replicationEnabled(memory) {
+		applyReplication(memory, &sts.Spec.Template.Spec, namespace)
+	}
+	if vectorIndexEnabled(memory) {
+		applyVectorIndex(memory, &sts.Spec.Template.Spec)
+	}
+	applyDatabaseEncryption(memory, &sts.Spec.Template.Spec)
+	// Nothing may have the database open while its key is rotated
+	if keyRotating(memory) {
+		replicas = 0
+	}
+	imageConfig, err := r.clusterImageConfig(ctx, memory)
+	if err != nil {
+		return err
+	}
+	utils.ApplyImageConfig(&sts.Spec.Template.Spec, imageConfig)
+
+	// Restart the memory service when its scripts or certificate change
+	if err := applyConfigHash(ctx, r, namespace, &sts.Spec.Template); err != nil {
+		return err
+	}
+
 	// Check if StatefulSet exists
 	foundSts := &appsv1.StatefulSet{}
-	err := r.Get(ctx, types.NamespacedName{Name: sts.Name, Namespace: sts.Namespace}, foundSts)
+	err = r.Get(ctx, types.NamespacedName{Name: sts.Name, Namespace: sts.Namespace}, foundSts)
+	keepConfigHash(foundSts, &foundSts.Spec.Template, &sts.Spec.Template)
 	if err != nil && errors.IsNotFound(err) {
 		logger.Info("Creating StatefulSet", "Name", sts.Name, "Namespace", sts.Namespace)
 		if err := r.Create(ctx, sts); err != nil {
@@ -399,9 +617,18 @@ func (r *SwarmMemoryStoreReconciler) reconcileStatefulSet(ctx context.Context, m
 		}
 	} else if err != nil {
 		return err
+	} else if !equality.Semantic.DeepDerivative(sts.Spec.Template, foundSts.Spec.Template) ||
+		foundSts.Spec.Replicas == nil || *foundSts.Spec.Replicas != replicas {
+		// Roll out TLS being switched on or off, and config changes
+		logger.Info("Updating StatefulSet", "Name", sts.Name, "Namespace", sts.Namespace)
+		foundSts.Spec.Replicas = &replicas
+		foundSts.Spec.Template = sts.Spec.Template
+		if err := r.Update(ctx, foundSts); err != nil {
+			return err
+		}
 	}
 	
-	return nil
+	return r.reconcileReplicaStatefulSet(ctx, memory, sts)
 }
 
 func (r *SwarmMemoryStoreReconciler) runMigration(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
@@ -433,6 +660,7 @@ func (r *SwarmMemoryStoreReconciler) runMigration(ctx context.Context, memory *s
 							Image: fmt.Sprintf("claudeflow/swarm-memory:%s", memory.Spec.Version),
 							Command: []string{"/bin/sh", "-c"},
 							Args:    []string{"/scripts/migrate.sh"},
+							Env:     activeKeyEnv(memory),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "data",
@@ -483,10 +711,15 @@ func (r *SwarmMemoryStoreReconciler) runMigration(ctx context.Context, memory *s
 			},
 		},
 	}
+	imageConfig, err := r.clusterImageConfig(ctx, memory)
+	if err != nil {
+		return err
+	}
+	utils.ApplyImageConfig(&job.Spec.Template.Spec, imageConfig)
 	
 	// Check if job exists
 	foundJob := &batchv1.Job{}
-	err := r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, foundJob)
+	err = r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: job.Namespace}, foundJob)
 	if err != nil && errors.IsNotFound(err) {
 		logger.Info("Creating migration job", "Name", job.Name)
 		if err := r.Create(ctx, job); err != nil {
@@ -543,6 +776,30 @@ func (r *SwarmMemoryStoreReconciler) getDatabaseSize(ctx context.Context, memory
 	return "0 MB"
 }
 
+// probeBackendLatency times a read of the memory service HTTP API. A key
+// that is not found still measures the round trip. It returns -1 when the
+// service does not answer and nil before it publishes its endpoint.
+func (r *SwarmMemoryStoreReconciler) probeBackendLatency(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore) *int64 {
+	if memory.Status.Endpoints.HTTP == "" {
+		return nil
+	}
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 2 * time.Second}
+	}
+	backend := &memorycache.HTTPBackend{BaseURL: memory.Status.Endpoints.HTTP, Client: httpClient}
+
+	latency := int64(-1)
+	start := time.Now()
+	_, err := backend.Stats(ctx, memoryProbeNamespace, memoryProbeKey)
+	if err == nil || stderrors.Is(err, memorycache.ErrNotFound) {
+		latency = time.Since(start).Milliseconds()
+	} else {
+		log.FromContext(ctx).V(1).Info("Failed to probe memory backend", "error", err.Error())
+	}
+	return &latency
+}
+
 func getEnhancedSchema() string {
 	return `-- Enhanced SQLite schema for SwarmMemory
 CREATE TABLE IF NOT EXISTS memory_store (
@@ -626,6 +883,25 @@ CREATE INDEX IF NOT EXISTS idx_swarm_patterns_confidence ON swarm_patterns(confi
 
 const swarmMemoryFinalizer = "swarm.claudeflow.io/memory-finalizer"
 
+const (
+	// memoryProbeInterval is how often the backend latency is probed
+	memoryProbeInterval = time.Minute
+
+	// memoryProbeNamespace and memoryProbeKey name the entry read to probe
+	// the backend. It need not exist.
+	memoryProbeNamespace = "swarm-operator"
+	memoryProbeKey       = "health-probe"
+
+	// defaultMemoryStorageSize sizes the database claims when the store
+	// does not set a storage size
+	defaultMemoryStorageSize = "10Gi"
+
+	// ConditionTypeStorageConfigured is False while the storage size of the
+	// store cannot be parsed
+	ConditionTypeStorageConfigured = "StorageConfigured"
+	ReasonInvalidStorageSize       = "InvalidStorageSize"
+)
+
 // Helper functions
 func containsString(slice []string, s string) bool {
 	for _, item := range slice {
@@ -653,5 +929,7 @@ func (r *SwarmMemoryStoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&appsv1.StatefulSet{}).
-		Complete(r)
+		Owns(&corev1.Service{}).
+		Owns(&batchv1.Job{}).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmmemorystore", r))
 }
\ No newline at end of file
@@ -0,0 +1,555 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// ConditionTypeEncryptionKeyCurrent reports whether the database is
+	// encrypted with the key version the spec asks for
+	ConditionTypeEncryptionKeyCurrent = "EncryptionKeyCurrent"
+
+	ReasonKeyRotated            = "KeyRotated"
+	ReasonKeyRotationInProgress = "KeyRotationInProgress"
+	ReasonKeyRotationFailed     = "KeyRotationFailed"
+
+	defaultKeyVersion = "v1"
+
+	// keyRotationInterval is how often a rotation in progress is checked
+	keyRotationInterval = 10 * time.Second
+)
+
+// encryptionEnabled reports whether the database should be encrypted
+func encryptionEnabled(memory *swarmv1alpha1.SwarmMemoryStore) bool {
+	return memory.Spec.Encryption != nil && memory.Spec.Encryption.Enabled
+}
+
+// desiredKeyVersion is the key version the database should be encrypted
+// with, empty when it should be plaintext
+func desiredKeyVersion(memory *swarmv1alpha1.SwarmMemoryStore) string {
+	if !encryptionEnabled(memory) {
+		return ""
+	}
+	if memory.Spec.Encryption.KeyVersion != "" {
+		return memory.Spec.Encryption.KeyVersion
+	}
+	return defaultKeyVersion
+}
+
+// activeKeyVersion is the key version the database is encrypted with
+func activeKeyVersion(memory *swarmv1alpha1.SwarmMemoryStore) string {
+	if memory.Status.Encryption == nil {
+		return ""
+	}
+	return memory.Status.Encryption.ActiveKeyVersion
+}
+
+// keyRotating reports whether the memory service is stopped for a key
+// rotation
+func keyRotating(memory *swarmv1alpha1.SwarmMemoryStore) bool {
+	return memory.Status.Encryption != nil && memory.Status.Encryption.Rotation != nil
+}
+
+// databaseKeyEnv selects the key of a version from the key Secret. The
+// plaintext version has an empty key.
+func databaseKeyEnv(memory *swarmv1alpha1.SwarmMemoryStore, name, version string) corev1.EnvVar {
+	if version == "" || memory.Spec.Encryption == nil {
+		return corev1.EnvVar{Name: name}
+	}
+	return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: memory.Spec.Encryption.KeySecretName},
+			Key:                  version,
+		},
+	}}
+}
+
+// kmsEnv tells the database scripts and the memory service how to unwrap
+// keys wrapped by a KMS
+func kmsEnv(memory *swarmv1alpha1.SwarmMemoryStore) []corev1.EnvVar {
+	if memory.Spec.Encryption == nil || memory.Spec.Encryption.KMS == nil {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: "KMS_PROVIDER", Value: memory.Spec.Encryption.KMS.Provider},
+		{Name: "KMS_KEY_URI", Value: memory.Spec.Encryption.KMS.KeyURI},
+	}
+}
+
+// activeKeyEnv opens the database with its active key, nil while it is
+// plaintext
+func activeKeyEnv(memory *swarmv1alpha1.SwarmMemoryStore) []corev1.EnvVar {
+	version := activeKeyVersion(memory)
+	if version == "" {
+		return nil
+	}
+	env := []corev1.EnvVar{
+		{Name: "DB_ENCRYPTION", Value: "sqlcipher"},
+		databaseKeyEnv(memory, "DB_KEY", version),
+		{Name: "DB_KEY_VERSION", Value: version},
+	}
+	return append(env, kmsEnv(memory)...)
+}
+
+// applyDatabaseEncryption hands the active key to the memory service and
+// initializes the database in the memory image, which ships SQLCipher
+func applyDatabaseEncryption(memory *swarmv1alpha1.SwarmMemoryStore, podSpec *corev1.PodSpec) {
+	env := activeKeyEnv(memory)
+	if env == nil {
+		return
+	}
+	for i := range podSpec.InitContainers {
+		container := &podSpec.InitContainers[i]
+		if container.Name == "init-db" {
+			container.Image = fmt.Sprintf("claudeflow/swarm-memory:%s", memory.Spec.Version)
+			container.Args = []string{"/scripts/init.sh"}
+			container.Env = append(container.Env, env...)
+		}
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name == "memory-service" {
+			container.Env = append(container.Env, env...)
+		}
+	}
+}
+
+// reconcileEncryption copies the key Secret next to the memory service and
+// drives key rotations: once the spec asks for another key version the
+// memory service is stopped, a Job re-encrypts the database of every
+// memory pod and the service comes back with the new key. The caller
+// scales the StatefulSets down while keyRotating and saves the status.
+func (r *SwarmMemoryStoreReconciler) reconcileEncryption(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
+	logger := log.FromContext(ctx)
+
+	status := memory.Status.Encryption
+	if status == nil {
+		if !encryptionEnabled(memory) {
+			return nil
+		}
+		status = &swarmv1alpha1.MemoryEncryptionStatus{}
+		memory.Status.Encryption = status
+	}
+	spec := memory.Spec.Encryption
+	if spec == nil {
+		if status.ActiveKeyVersion == "" && status.Rotation == nil {
+			memory.Status.Encryption = nil
+			meta.RemoveStatusCondition(&memory.Status.Conditions, ConditionTypeEncryptionKeyCurrent)
+			return nil
+		}
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:   ConditionTypeEncryptionKeyCurrent,
+			Status: metav1.ConditionFalse,
+			Reason: ReasonKeyRotationFailed,
+			Message: fmt.Sprintf("Encryption was removed while the database is encrypted with key version %s; "+
+				"set encryption.enabled to false to decrypt it", status.ActiveKeyVersion),
+		})
+		return nil
+	}
+
+	found, err := copyTLSSecret(ctx, r.Client, spec.KeySecretName, memory.Namespace, namespace)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("encryption key secret %s not found", spec.KeySecretName)
+	}
+
+	target := desiredKeyVersion(memory)
+	rotation := status.Rotation
+	if rotation != nil && rotation.KeyVersion != target {
+		// Let running Jobs finish before replacing the rotation, the
+		// databases are then encrypted with either version
+		finished, _, err := r.rotationJobsFinished(ctx, rotation, namespace)
+		if err != nil || !finished {
+			return err
+		}
+		previous := append([]string{rotation.KeyVersion}, rotation.PreviousKeyVersions...)
+		if len(rotation.Jobs) == 0 {
+			previous = rotation.PreviousKeyVersions
+		}
+		status.Rotation = nil
+		if len(rotation.Jobs) > 0 || target != status.ActiveKeyVersion {
+			status.Rotation = newKeyRotation(target, previous)
+			logger.Info("Replacing key rotation", "Memory", memory.Name, "keyVersion", target)
+		}
+		rotation = status.Rotation
+	}
+	if rotation == nil {
+		condition := metav1.Condition{
+			Type:    ConditionTypeEncryptionKeyCurrent,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonKeyRotated,
+			Message: fmt.Sprintf("Database is encrypted with key version %s", target),
+		}
+		if target != status.ActiveKeyVersion {
+			status.Rotation = newKeyRotation(target, []string{status.ActiveKeyVersion})
+			logger.Info("Starting key rotation", "Memory", memory.Name, "from", status.ActiveKeyVersion, "to", target)
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = ReasonKeyRotationInProgress
+			condition.Message = fmt.Sprintf("Stopping the memory service to re-encrypt the database with key version %s", target)
+		} else if target == "" {
+			condition.Message = "Database is decrypted"
+		}
+		meta.SetStatusCondition(&memory.Status.Conditions, condition)
+		return nil
+	}
+
+	// Jobs only open the databases once no memory pod or maintenance Job
+	// has them open
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{
+		"app":         "swarm-memory",
+		"memory-name": memory.Name,
+	}); err != nil {
+		return err
+	}
+	if len(pods.Items) > 0 {
+		return nil
+	}
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(namespace), client.MatchingLabels{
+		"app":         "swarm-memory",
+		"memory-name": memory.Name,
+		"job-type":    "maintenance",
+	}); err != nil {
+		return err
+	}
+	for i := range jobs.Items {
+		if !jobFinished(&jobs.Items[i]) {
+			return nil
+		}
+	}
+
+	if len(rotation.Jobs) == 0 {
+		claims, err := r.memoryClaims(ctx, memory, namespace)
+		if err != nil {
+			return err
+		}
+		for i, claim := range claims {
+			rotation.Jobs = append(rotation.Jobs, fmt.Sprintf("%s-rekey-%d-%d", memory.Name, rotation.StartTime.Unix(), i))
+			if err := r.startKeyRotation(ctx, memory, namespace, rotation, rotation.Jobs[i], claim); err != nil {
+				return err
+			}
+		}
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:    ConditionTypeEncryptionKeyCurrent,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonKeyRotationInProgress,
+			Message: fmt.Sprintf("Re-encrypting %d databases with key version %s", len(claims), rotation.KeyVersion),
+		})
+		return nil
+	}
+
+	finished, failed, err := r.rotationJobsFinished(ctx, rotation, namespace)
+	if err != nil || !finished {
+		return err
+	}
+	if failed != "" {
+		rotation.Failed = true
+		retry := fmt.Sprintf("set encryption.keyVersion back to %s or to a new version", status.ActiveKeyVersion)
+		if status.ActiveKeyVersion == "" {
+			retry = "disable encryption or set encryption.keyVersion to a new version"
+		}
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:    ConditionTypeEncryptionKeyCurrent,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonKeyRotationFailed,
+			Message: fmt.Sprintf("Key rotation job %s failed; %s to retry", failed, retry),
+		})
+		return nil
+	}
+
+	logger.Info("Finished key rotation", "Memory", memory.Name, "keyVersion", rotation.KeyVersion)
+	status.ActiveKeyVersion = rotation.KeyVersion
+	status.LastRotationTime = &metav1.Time{Time: time.Now()}
+	status.Rotation = nil
+	condition := metav1.Condition{
+		Type:    ConditionTypeEncryptionKeyCurrent,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonKeyRotated,
+		Message: fmt.Sprintf("Database is encrypted with key version %s", status.ActiveKeyVersion),
+	}
+	if status.ActiveKeyVersion == "" {
+		condition.Message = "Database is decrypted"
+	}
+	meta.SetStatusCondition(&memory.Status.Conditions, condition)
+	return nil
+}
+
+// newKeyRotation starts a rotation to a key version from the versions the
+// databases may be encrypted with
+func newKeyRotation(target string, previous []string) *swarmv1alpha1.MemoryKeyRotationStatus {
+	rotation := &swarmv1alpha1.MemoryKeyRotationStatus{KeyVersion: target, StartTime: metav1.Now()}
+	seen := map[string]bool{target: true}
+	for _, version := range previous {
+		if !seen[version] {
+			seen[version] = true
+			rotation.PreviousKeyVersions = append(rotation.PreviousKeyVersions, version)
+		}
+	}
+	return rotation
+}
+
+// memoryClaims lists the claims holding a database: the primary's and one
+// per follower, including followers scaled away
+func (r *SwarmMemoryStoreReconciler) memoryClaims(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) ([]string, error) {
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, claims, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	names := []string{memory.Name + "-storage"}
+	var followers []string
+	prefix := "data-" + replicaStatefulSetName(memory) + "-"
+	for _, claim := range claims.Items {
+		if strings.HasPrefix(claim.Name, prefix) {
+			followers = append(followers, claim.Name)
+		}
+	}
+	sort.Strings(followers)
+	return append(names, followers...), nil
+}
+
+// rotationJobsFinished reports whether every Job of a rotation finished and
+// the first one that failed. Jobs deleted before they reported back count
+// as failed.
+func (r *SwarmMemoryStoreReconciler) rotationJobsFinished(ctx context.Context, rotation *swarmv1alpha1.MemoryKeyRotationStatus, namespace string) (bool, string, error) {
+	finished, failed := true, ""
+	for _, name := range rotation.Jobs {
+		job := &batchv1.Job{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, job)
+		if errors.IsNotFound(err) {
+			if failed == "" {
+				failed = name
+			}
+			continue
+		}
+		if err != nil {
+			return false, "", err
+		}
+		if !jobFinished(job) {
+			finished = false
+			continue
+		}
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue && failed == "" {
+				failed = name
+			}
+		}
+	}
+	return finished, failed, nil
+}
+
+// startKeyRotation creates the Job re-encrypting the database on a claim
+func (r *SwarmMemoryStoreReconciler) startKeyRotation(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string, rotation *swarmv1alpha1.MemoryKeyRotationStatus, name, claim string) error {
+	env := []corev1.EnvVar{
+		databaseKeyEnv(memory, "NEW_KEY", rotation.KeyVersion),
+		{Name: "PREVIOUS_KEYS", Value: strconv.Itoa(len(rotation.PreviousKeyVersions))},
+	}
+	for i, version := range rotation.PreviousKeyVersions {
+		env = append(env, databaseKeyEnv(memory, fmt.Sprintf("PREVIOUS_KEY_%d", i), version))
+	}
+	env = append(env, kmsEnv(memory)...)
+
+	backoffLimit := int32(2)
+	ttl := maintenanceJobTTL
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":         "swarm-memory-rekey",
+				"memory-name": memory.Name,
+				"job-type":    "rekey",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "rekey",
+							Image:   fmt.Sprintf("claudeflow/swarm-memory:%s", memory.Spec.Version),
+							Command: []string{"/bin/sh", "-c"},
+							Args:    []string{"/scripts/rekey.sh"},
+							Env:     env,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "data", MountPath: "/data"},
+								{Name: "scripts", MountPath: "/scripts"},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+							},
+						},
+						{
+							Name: "scripts",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: memory.Name + "-scripts"},
+									DefaultMode:          &[]int32{0755}[0],
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if namespace == memory.Namespace {
+		if err := controllerutil.SetControllerReference(memory, job, r.Scheme); err != nil {
+			return err
+		}
+	}
+	imageConfig, err := r.clusterImageConfig(ctx, memory)
+	if err != nil {
+		return err
+	}
+	utils.ApplyImageConfig(&job.Spec.Template.Spec, imageConfig)
+
+	log.FromContext(ctx).Info("Creating key rotation job", "Name", name, "claim", claim)
+	if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// getDatabaseScript has the helpers of the scripts opening the database.
+// Keys are 64 hex characters, SQLCipher uses them as raw 256-bit keys.
+func getDatabaseScript() string {
+	return `# Sourced by the database scripts
+
+# unwrap prints a database key, decrypting it with the KMS key when the
+# Secret holds wrapped keys
+unwrap() {
+  if [ -n "$1" ] && [ -n "${KMS_KEY_URI:-}" ]; then
+    printf '%s' "$1" | swarm-memory kms decrypt --provider "$KMS_PROVIDER" --key-uri "$KMS_KEY_URI"
+  else
+    printf '%s' "$1"
+  fi
+}
+
+# sql runs sqlite3 against database $1, or sqlcipher keyed with $2 when it
+# is not empty
+sql() {
+  db=$1
+  key=$2
+  shift 2
+  if [ -z "$key" ]; then
+    sqlite3 "$db" "$@"
+  else
+    sqlcipher -cmd '.output /dev/null' -cmd "PRAGMA key = \"x'$key'\";" -cmd '.output stdout' "$db" "$@"
+  fi
+}
+
+# opens reports whether database $1 can be read with key $2
+opens() {
+  sql "$1" "$2" 'SELECT count(*) FROM sqlite_master;' > /dev/null 2>&1
+}
+
+# export_db copies database $1, opened with key $2, into a new database $3
+# encrypted with key $4. Empty keys mean plaintext.
+export_db() {
+  target="KEY ''"
+  if [ -n "$4" ]; then
+    target="KEY \"x'$4'\""
+  fi
+  keyed=''
+  if [ -n "$2" ]; then
+    keyed="PRAGMA key = \"x'$2'\";"
+  fi
+  rm -f "$3"
+  sqlcipher -cmd '.output /dev/null' "$1" \
+    "$keyed ATTACH DATABASE '$3' AS target $target; SELECT sqlcipher_export('target'); DETACH DATABASE target;"
+}
+
+# backup_db backs database $1 up to $2, keeping its key $3
+backup_db() {
+  if [ -z "$3" ]; then
+    sqlite3 "$1" ".backup '$2'"
+  else
+    export_db "$1" "$3" "$2" "$3"
+  fi
+}
+`
+}
+
+// getRekeyScript re-encrypts the database and the backups on a volume with
+// NEW_KEY. They may be encrypted with any of the previous keys or already
+// with the new one when an earlier attempt got that far.
+func getRekeyScript() string {
+	return `#!/bin/sh
+set -u
+. /scripts/db.sh
+
+NEW=$(unwrap "$NEW_KEY") || exit 1
+
+rekey() {
+  [ -f "$1" ] || return 0
+  if opens "$1" "$NEW"; then
+    return 0
+  fi
+  i=0
+  while [ "$i" -lt "$PREVIOUS_KEYS" ]; do
+    eval "wrapped=\${PREVIOUS_KEY_$i}"
+    old=$(unwrap "$wrapped") || return 1
+    if opens "$1" "$old"; then
+      sql "$1" "$old" 'PRAGMA wal_checkpoint(TRUNCATE);' > /dev/null || return 1
+      export_db "$1" "$old" "$1.rekey" "$NEW" || return 1
+      mv "$1.rekey" "$1" && rm -f "$1-wal" "$1-shm"
+      return
+    fi
+    i=$((i + 1))
+  done
+  echo "No key opens $1" >&2
+  return 1
+}
+
+rekey /data/memory/swarm-memory.db || exit 1
+for backup in /data/backups/*.db; do
+  rekey "$backup" || exit 1
+done
+echo "Re-encrypted the databases"
+`
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Memory store encryption", func() {
+	var (
+		ctx        context.Context
+		memory     *swarmv1alpha1.SwarmMemoryStore
+		reconciler *SwarmMemoryStoreReconciler
+	)
+
+	setup := func(objects ...runtime.Object) {
+		keys := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "memory-keys", Namespace: "default"},
+			Data:       map[string][]byte{"v1": []byte("aa"), "v2": []byte("bb")},
+		}
+		reconciler = &SwarmMemoryStoreReconciler{
+			Client: newTestClientBuilder().WithRuntimeObjects(append(objects, keys)...).Build(),
+			Scheme: testScheme,
+		}
+	}
+	rekeyJobs := func() []batchv1.Job {
+		jobs := &batchv1.JobList{}
+		Expect(reconciler.List(ctx, jobs, client.MatchingLabels{"job-type": "rekey"})).To(Succeed())
+		return jobs.Items
+	}
+	finish := func(name string, condition batchv1.JobConditionType) {
+		job := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, job)).To(Succeed())
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+		Expect(reconciler.Status().Update(ctx, job)).To(Succeed())
+	}
+	keyRef := func(version string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "memory-keys"},
+			Key:                  version,
+		}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		memory = &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmMemoryStoreSpec{
+				Version: "2.0.0",
+				Encryption: &swarmv1alpha1.MemoryEncryptionSpec{
+					Enabled:       true,
+					KeySecretName: "memory-keys",
+					KeyVersion:    "v1",
+				},
+			},
+		}
+	})
+
+	It("encrypts the database of a new store before it starts", func() {
+		setup(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory-storage", Namespace: "default"}})
+
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(keyRotating(memory)).To(BeTrue())
+		Expect(memory.Status.Encryption.Rotation.PreviousKeyVersions).To(Equal([]string{""}))
+		Expect(meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeEncryptionKeyCurrent).Reason).
+			To(Equal(ReasonKeyRotationInProgress))
+
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		jobs := rekeyJobs()
+		Expect(jobs).To(HaveLen(1))
+		Expect(jobs[0].Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("swarm-memory-storage"))
+		Expect(jobs[0].Spec.Template.Spec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "NEW_KEY", ValueFrom: keyRef("v1")},
+			corev1.EnvVar{Name: "PREVIOUS_KEYS", Value: "1"},
+			corev1.EnvVar{Name: "PREVIOUS_KEY_0"},
+		))
+
+		finish(jobs[0].Name, batchv1.JobComplete)
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(keyRotating(memory)).To(BeFalse())
+		Expect(memory.Status.Encryption.ActiveKeyVersion).To(Equal("v1"))
+		Expect(memory.Status.Encryption.LastRotationTime).NotTo(BeNil())
+		Expect(meta.IsStatusConditionTrue(memory.Status.Conditions, ConditionTypeEncryptionKeyCurrent)).To(BeTrue())
+
+		podSpec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init-db", Image: "alpine:3.18"}},
+			Containers:     []corev1.Container{{Name: "memory-service"}},
+		}
+		applyDatabaseEncryption(memory, podSpec)
+		Expect(podSpec.InitContainers[0].Image).To(Equal("claudeflow/swarm-memory:2.0.0"))
+		Expect(podSpec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "DB_KEY", ValueFrom: keyRef("v1")},
+			corev1.EnvVar{Name: "DB_KEY_VERSION", Value: "v1"},
+		))
+	})
+
+	It("rotates the key of every memory pod once the service stopped", func() {
+		memory.Status.Encryption = &swarmv1alpha1.MemoryEncryptionStatus{ActiveKeyVersion: "v1"}
+		memory.Spec.Encryption.KeyVersion = "v2"
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "swarm-memory-0", Namespace: "default",
+			Labels: map[string]string{"app": "swarm-memory", "memory-name": "swarm-memory"},
+		}}
+		setup(pod, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-swarm-memory-replica-0", Namespace: "default"}})
+
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(rekeyJobs()).To(BeEmpty())
+
+		Expect(reconciler.Delete(ctx, pod)).To(Succeed())
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		jobs := rekeyJobs()
+		Expect(jobs).To(HaveLen(2))
+		Expect(memory.Status.Encryption.Rotation.Jobs).To(HaveLen(2))
+		claims := []string{}
+		for _, job := range jobs {
+			claims = append(claims, job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+			Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(
+				corev1.EnvVar{Name: "PREVIOUS_KEY_0", ValueFrom: keyRef("v1")}))
+		}
+		Expect(claims).To(ConsistOf("swarm-memory-storage", "data-swarm-memory-replica-0"))
+
+		finish(jobs[0].Name, batchv1.JobComplete)
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(keyRotating(memory)).To(BeTrue())
+		finish(jobs[1].Name, batchv1.JobComplete)
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(memory.Status.Encryption.ActiveKeyVersion).To(Equal("v2"))
+	})
+
+	It("keeps the service down after a failed rotation until the key version changes", func() {
+		memory.Status.Encryption = &swarmv1alpha1.MemoryEncryptionStatus{ActiveKeyVersion: "v1"}
+		memory.Spec.Encryption.KeyVersion = "v2"
+		setup()
+
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		finish(memory.Status.Encryption.Rotation.Jobs[0], batchv1.JobFailed)
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(memory.Status.Encryption.Rotation.Failed).To(BeTrue())
+		condition := meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeEncryptionKeyCurrent)
+		Expect(condition.Reason).To(Equal(ReasonKeyRotationFailed))
+		Expect(condition.Message).To(ContainSubstring("back to v1"))
+
+		// Going back rekeys whatever the failed rotation got to
+		memory.Spec.Encryption.KeyVersion = "v1"
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		rotation := memory.Status.Encryption.Rotation
+		Expect(rotation.KeyVersion).To(Equal("v1"))
+		Expect(rotation.PreviousKeyVersions).To(Equal([]string{"v2"}))
+		Expect(rotation.Failed).To(BeFalse())
+	})
+
+	It("cancels a rotation that has not started yet", func() {
+		memory.Status.Encryption = &swarmv1alpha1.MemoryEncryptionStatus{ActiveKeyVersion: "v1"}
+		memory.Spec.Encryption.KeyVersion = "v2"
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "swarm-memory-0", Namespace: "default",
+			Labels: map[string]string{"app": "swarm-memory", "memory-name": "swarm-memory"},
+		}}
+		setup(pod)
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(keyRotating(memory)).To(BeTrue())
+
+		memory.Spec.Encryption.KeyVersion = "v1"
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(Succeed())
+		Expect(keyRotating(memory)).To(BeFalse())
+		Expect(meta.IsStatusConditionTrue(memory.Status.Conditions, ConditionTypeEncryptionKeyCurrent)).To(BeTrue())
+	})
+
+	It("waits for the key Secret", func() {
+		memory.Spec.Encryption.KeySecretName = "missing"
+		setup()
+		Expect(reconciler.reconcileEncryption(ctx, memory, "default")).To(MatchError(ContainSubstring("missing")))
+	})
+})
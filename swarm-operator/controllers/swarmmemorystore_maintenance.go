@@ -0,0 +1,413 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/backup"
+	"github.com/claude-flow/swarm-operator/pkg/schedule"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// ConditionTypeDatabaseIntegrity reports the last integrity check of a
+	// memory store database
+	ConditionTypeDatabaseIntegrity = "DatabaseIntegrity"
+
+	ReasonIntegrityOK          = "IntegrityOK"
+	ReasonDatabaseCorrupt      = "DatabaseCorrupt"
+	ReasonDatabaseRestored     = "DatabaseRestored"
+	ReasonMaintenanceJobFailed = "MaintenanceJobFailed"
+
+	defaultMaintenanceSchedule   = "0 3 * * *"
+	defaultMaintenanceDuration   = time.Hour
+	defaultMemoryBackupRetention = 7
+
+	integrityOK      = "ok"
+	integrityCorrupt = "corrupt"
+	integrityUnknown = "unknown"
+
+	// maintenanceContainerName reports its results in its termination
+	// message
+	maintenanceContainerName = "maintain"
+
+	// maintenanceJobTTL keeps finished Jobs around for inspection
+	maintenanceJobTTL = int32(24 * 60 * 60)
+)
+
+// maintenanceEnabled reports whether the store runs maintenance Jobs
+func maintenanceEnabled(memory *swarmv1alpha1.SwarmMemoryStore) bool {
+	return memory.Spec.Maintenance != nil && memory.Spec.Maintenance.Enabled
+}
+
+// maintenanceWindow resolves the window maintenance Jobs start in
+func maintenanceWindow(spec *swarmv1alpha1.MemoryMaintenanceSpec) (*schedule.Window, error) {
+	cron := spec.Schedule
+	if cron == "" {
+		cron = defaultMaintenanceSchedule
+	}
+	duration := defaultMaintenanceDuration
+	if spec.Duration != nil {
+		duration = spec.Duration.Duration
+	}
+	return schedule.NewWindow(cron, duration, spec.TimeZone)
+}
+
+// reconcileMaintenance records the results of the last maintenance Job and
+// starts one when a window opens. The caller saves the status.
+func (r *SwarmMemoryStoreReconciler) reconcileMaintenance(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
+	if !maintenanceEnabled(memory) {
+		memory.Status.Maintenance = nil
+		meta.RemoveStatusCondition(&memory.Status.Conditions, ConditionTypeDatabaseIntegrity)
+		return nil
+	}
+	if memory.Status.Maintenance == nil {
+		memory.Status.Maintenance = &swarmv1alpha1.MemoryMaintenanceStatus{}
+	}
+	status := memory.Status.Maintenance
+
+	window, err := maintenanceWindow(memory.Spec.Maintenance)
+	if err != nil {
+		status.NextWindow = nil
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:    ConditionTypeDatabaseIntegrity,
+			Status:  metav1.ConditionUnknown,
+			Reason:  ReasonInvalidMaintenanceWindow,
+			Message: fmt.Sprintf("Invalid maintenance window: %v", err),
+		})
+		return nil
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(namespace), client.MatchingLabels{
+		"app":         "swarm-memory",
+		"memory-name": memory.Name,
+		"job-type":    "maintenance",
+	}); err != nil {
+		return err
+	}
+
+	var latest *batchv1.Job
+	active := false
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if !jobFinished(job) {
+			active = true
+			continue
+		}
+		if latest == nil || job.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+			latest = job
+		}
+	}
+	if latest != nil && latest.Name != status.LastJob {
+		if err := r.recordMaintenance(ctx, memory, latest); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	// The database is closed to everything but the rotation Jobs while its
+	// key is rotated
+	if end, open := window.Active(now); open && !active && !keyRotating(memory) {
+		name := fmt.Sprintf("%s-maintenance-%d", memory.Name, end.Add(-window.Duration).Unix())
+		if name != status.LastJob {
+			if err := r.startMaintenance(ctx, memory, namespace, name); err != nil {
+				return err
+			}
+		}
+	}
+	if next := window.NextStart(now); !next.IsZero() {
+		status.NextWindow = &metav1.Time{Time: next}
+	}
+	return nil
+}
+
+// jobFinished reports whether a Job completed or failed
+func jobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// startMaintenance creates the maintenance Job of the current window unless
+// it already exists
+func (r *SwarmMemoryStoreReconciler) startMaintenance(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace, name string) error {
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &batchv1.Job{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	job, err := r.maintenanceJob(ctx, memory, namespace, name)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Creating maintenance job", "Name", job.Name)
+	return r.Create(ctx, job)
+}
+
+// maintenanceJob builds a Job running maintain.sh against the store volume
+func (r *SwarmMemoryStoreReconciler) maintenanceJob(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace, name string) (*batchv1.Job, error) {
+	retention := memory.Spec.BackupRetention
+	if retention <= 0 {
+		retention = defaultMemoryBackupRetention
+	}
+	snapshotURL := ""
+	if memory.Status.Endpoints.HTTP != "" {
+		snapshotURL = strings.TrimSuffix(memory.Status.Endpoints.HTTP, "/") + backup.SnapshotPath
+	}
+	backoffLimit := int32(0)
+	ttl := maintenanceJobTTL
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":         "swarm-memory",
+				"memory-name": memory.Name,
+				"job-type":    "maintenance",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    maintenanceContainerName,
+							Image:   fmt.Sprintf("claudeflow/swarm-memory:%s", memory.Spec.Version),
+							Command: []string{"/bin/sh", "-c"},
+							Args:    []string{"/scripts/maintain.sh"},
+							Env: []corev1.EnvVar{
+								{Name: "VACUUM", Value: strconv.FormatBool(memory.Spec.EnableVacuum)},
+								{Name: "AUTO_RESTORE", Value: strconv.FormatBool(memory.Spec.Maintenance.AutoRestore)},
+								{Name: "BACKUP_RETENTION", Value: strconv.Itoa(retention)},
+								{Name: "SNAPSHOT_URL", Value: snapshotURL},
+							},
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "data",
+									MountPath: "/data",
+								},
+								{
+									Name:      "scripts",
+									MountPath: "/scripts",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: memory.Name + "-storage",
+								},
+							},
+						},
+						{
+							Name: "scripts",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: memory.Name + "-scripts",
+									},
+									DefaultMode: &[]int32{0755}[0],
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	container := &job.Spec.Template.Spec.Containers[0]
+	container.Env = append(container.Env, activeKeyEnv(memory)...)
+	if namespace == memory.Namespace {
+		if err := controllerutil.SetControllerReference(memory, job, r.Scheme); err != nil {
+			return nil, err
+		}
+	}
+	imageConfig, err := r.clusterImageConfig(ctx, memory)
+	if err != nil {
+		return nil, err
+	}
+	utils.ApplyImageConfig(&job.Spec.Template.Spec, imageConfig)
+	return job, nil
+}
+
+// recordMaintenance copies the results a finished maintenance Job reported
+// into the store status
+func (r *SwarmMemoryStoreReconciler) recordMaintenance(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, job *batchv1.Job) error {
+	message, err := r.maintenanceReport(ctx, job)
+	if err != nil {
+		return err
+	}
+	result := parseMaintenanceReport(message)
+
+	status := memory.Status.Maintenance
+	status.LastJob = job.Name
+	finished := metav1.Now()
+	if job.Status.CompletionTime != nil {
+		finished = *job.Status.CompletionTime
+	}
+	status.LastRunTime = &finished
+	status.Integrity = result["integrity"]
+	if status.Integrity == "" {
+		status.Integrity = integrityUnknown
+	}
+	status.IntegrityMessage = result["message"]
+	status.SizeBeforeBytes, _ = strconv.ParseInt(result["sizeBefore"], 10, 64)
+	status.SizeAfterBytes, _ = strconv.ParseInt(result["sizeAfter"], 10, 64)
+	if result["backup"] != "" {
+		status.LastGoodBackup = result["backup"]
+	}
+
+	condition := metav1.Condition{
+		Type:    ConditionTypeDatabaseIntegrity,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonIntegrityOK,
+		Message: fmt.Sprintf("Maintenance job %s found no integrity problems", job.Name),
+	}
+	switch {
+	case result["restored"] != "":
+		status.RestoredFrom = result["restored"]
+		status.LastRestoreTime = &finished
+		condition.Reason = ReasonDatabaseRestored
+		condition.Message = fmt.Sprintf("Database was corrupt (%s) and was restored from backup %s", status.IntegrityMessage, status.RestoredFrom)
+		log.FromContext(ctx).Info("Restored corrupt memory database", "job", job.Name, "backup", status.RestoredFrom)
+	case status.Integrity == integrityCorrupt:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonDatabaseCorrupt
+		condition.Message = fmt.Sprintf("Database is corrupt: %s", status.IntegrityMessage)
+		if !memory.Spec.Maintenance.AutoRestore {
+			condition.Message += "; autoRestore is disabled"
+		} else {
+			condition.Message += "; no backup could be restored"
+		}
+	case status.Integrity != integrityOK:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = ReasonMaintenanceJobFailed
+		condition.Message = fmt.Sprintf("Maintenance job %s failed before checking the database", job.Name)
+	}
+	meta.SetStatusCondition(&memory.Status.Conditions, condition)
+	return nil
+}
+
+// maintenanceReport returns the termination message of the maintenance
+// container of a Job, empty when no pod reported one
+func (r *SwarmMemoryStoreReconciler) maintenanceReport(ctx context.Context, job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != maintenanceContainerName {
+				continue
+			}
+			if cs.State.Terminated != nil && cs.State.Terminated.Message != "" {
+				return cs.State.Terminated.Message, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// parseMaintenanceReport parses the key=value lines maintain.sh writes to
+// its termination message
+func parseMaintenanceReport(message string) map[string]string {
+	result := map[string]string{}
+	for _, line := range strings.Split(message, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// getMaintenanceScript checks, compacts and backs up the database. Backups
+// are only taken of a database that passed the check, so the newest one is
+// the last good state.
+func getMaintenanceScript() string {
+	return `#!/bin/sh
+set -u
+
+DB=/data/memory/swarm-memory.db
+BACKUPS=/data/backups
+REPORT=/dev/termination-log
+
+. /scripts/db.sh
+KEY=$(unwrap "${DB_KEY:-}") || exit 1
+
+: > "$REPORT"
+report() { echo "$1=$2" >> "$REPORT"; }
+
+mkdir -p "$BACKUPS"
+report sizeBefore "$(stat -c %s "$DB")"
+
+check=$(sql "$DB" "$KEY" 'PRAGMA integrity_check;' 2>&1)
+if [ "$check" != "ok" ]; then
+  report integrity corrupt
+  report message "$(echo "$check" | head -n 1)"
+  latest=$(ls -1t "$BACKUPS"/*.db 2>/dev/null | head -n 1)
+  if [ "$AUTO_RESTORE" = "true" ] && [ -n "$latest" ] && [ -n "$SNAPSHOT_URL" ]; then
+    if curl -fsS -X PUT -H 'Content-Type: application/octet-stream' --data-binary "@$latest" "$SNAPSHOT_URL"; then
+      report restored "$(basename "$latest")"
+      exit 0
+    fi
+    echo "Failed to restore $latest" >&2
+  fi
+  exit 1
+fi
+report integrity ok
+
+if [ "$VACUUM" = "true" ]; then
+  sql "$DB" "$KEY" 'VACUUM;' || exit 1
+fi
+sql "$DB" "$KEY" 'ANALYZE;' || exit 1
+report sizeAfter "$(stat -c %s "$DB")"
+
+backup="swarm-memory-$(date -u +%Y%m%dT%H%M%SZ).db"
+backup_db "$DB" "$BACKUPS/$backup" "$KEY" || exit 1
+report backup "$backup"
+ls -1t "$BACKUPS"/*.db | tail -n +$((BACKUP_RETENTION + 1)) | xargs -r rm -f
+`
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Memory store maintenance", func() {
+	var (
+		ctx        context.Context
+		memory     *swarmv1alpha1.SwarmMemoryStore
+		reconciler *SwarmMemoryStoreReconciler
+	)
+
+	setup := func(objects ...runtime.Object) {
+		reconciler = &SwarmMemoryStoreReconciler{
+			Client: newTestClientBuilder().WithRuntimeObjects(objects...).Build(),
+			Scheme: testScheme,
+		}
+	}
+	finishedJob := func(name string, created time.Time, report string) []runtime.Object {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created),
+				Labels:            map[string]string{"app": "swarm-memory", "memory-name": "swarm-memory", "job-type": "maintenance"},
+			},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name + "-abcde", Namespace: "default", Labels: map[string]string{"job-name": name}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  maintenanceContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: report}},
+			}}},
+		}
+		return []runtime.Object{job, pod}
+	}
+	maintenanceJobs := func() []batchv1.Job {
+		jobs := &batchv1.JobList{}
+		Expect(reconciler.List(ctx, jobs, client.MatchingLabels{"job-type": "maintenance"})).To(Succeed())
+		return jobs.Items
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		memory = &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmMemoryStoreSpec{
+				Version:      "2.0.0",
+				EnableVacuum: true,
+				Maintenance: &swarmv1alpha1.MemoryMaintenanceSpec{
+					Enabled:     true,
+					Schedule:    "0 3 * * *",
+					Duration:    &metav1.Duration{Duration: time.Minute},
+					AutoRestore: true,
+				},
+			},
+			Status: swarmv1alpha1.SwarmMemoryStoreStatus{
+				Endpoints: swarmv1alpha1.SwarmMemoryEndpoints{HTTP: "http://swarm-memory.default.svc:8080"},
+			},
+		}
+	})
+
+	It("starts one maintenance Job per open window", func() {
+		memory.Spec.Maintenance.Schedule = "0 * * * *"
+		memory.Spec.Maintenance.Duration = &metav1.Duration{Duration: time.Hour}
+		setup()
+
+		Expect(reconciler.reconcileMaintenance(ctx, memory, "default")).To(Succeed())
+		jobs := maintenanceJobs()
+		Expect(jobs).To(HaveLen(1))
+		container := jobs[0].Spec.Template.Spec.Containers[0]
+		Expect(container.Args).To(Equal([]string{"/scripts/maintain.sh"}))
+		Expect(container.Env).To(ContainElements(
+			corev1.EnvVar{Name: "VACUUM", Value: "true"},
+			corev1.EnvVar{Name: "AUTO_RESTORE", Value: "true"},
+			corev1.EnvVar{Name: "SNAPSHOT_URL", Value: "http://swarm-memory.default.svc:8080/v1/snapshot"},
+		))
+		Expect(metav1.IsControlledBy(&jobs[0], memory)).To(BeTrue())
+		Expect(memory.Status.Maintenance.NextWindow).NotTo(BeNil())
+
+		// The running Job holds off another one
+		Expect(reconciler.reconcileMaintenance(ctx, memory, "default")).To(Succeed())
+		Expect(maintenanceJobs()).To(HaveLen(1))
+	})
+
+	It("records the integrity check, compaction and backup of a finished Job", func() {
+		setup(finishedJob("swarm-memory-maintenance-1", time.Now().Add(-time.Hour),
+			"sizeBefore=4096\nintegrity=ok\nsizeAfter=1024\nbackup=swarm-memory-20250101T030000Z.db\n")...)
+
+		Expect(reconciler.reconcileMaintenance(ctx, memory, "default")).To(Succeed())
+		status := memory.Status.Maintenance
+		Expect(status.LastJob).To(Equal("swarm-memory-maintenance-1"))
+		Expect(status.Integrity).To(Equal(integrityOK))
+		Expect(status.SizeBeforeBytes).To(Equal(int64(4096)))
+		Expect(status.SizeAfterBytes).To(Equal(int64(1024)))
+		Expect(status.LastGoodBackup).To(Equal("swarm-memory-20250101T030000Z.db"))
+		Expect(meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeDatabaseIntegrity).Reason).To(Equal(ReasonIntegrityOK))
+	})
+
+	It("reports corruption and the backup it was restored from", func() {
+		objects := finishedJob("swarm-memory-maintenance-1", time.Now().Add(-2*time.Hour),
+			"integrity=corrupt\nmessage=*** in database main *** Page 12 is never used\nrestored=swarm-memory-20250101T030000Z.db\n")
+		setup(objects...)
+
+		Expect(reconciler.reconcileMaintenance(ctx, memory, "default")).To(Succeed())
+		Expect(memory.Status.Maintenance.RestoredFrom).To(Equal("swarm-memory-20250101T030000Z.db"))
+		Expect(memory.Status.Maintenance.LastRestoreTime).NotTo(BeNil())
+		condition := meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeDatabaseIntegrity)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(ReasonDatabaseRestored))
+
+		// Without a backup to restore the store stays corrupt
+		setup(append(objects, finishedJob("swarm-memory-maintenance-2", time.Now().Add(-time.Hour),
+			"integrity=corrupt\nmessage=*** in database main *** Page 12 is never used\n")...)...)
+		Expect(reconciler.reconcileMaintenance(ctx, memory, "default")).To(Succeed())
+		Expect(memory.Status.Maintenance.LastJob).To(Equal("swarm-memory-maintenance-2"))
+		condition = meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeDatabaseIntegrity)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonDatabaseCorrupt))
+		Expect(condition.Message).To(ContainSubstring("Page 12 is never used"))
+	})
+
+	It("flags an invalid window and clears the status when disabled", func() {
+		memory.Spec.Maintenance.Schedule = "every night"
+		setup()
+		Expect(reconciler.reconcileMaintenance(ctx, memory, "default")).To(Succeed())
+		Expect(meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeDatabaseIntegrity).Reason).To(Equal(ReasonInvalidMaintenanceWindow))
+		Expect(maintenanceJobs()).To(BeEmpty())
+
+		memory.Spec.Maintenance.Enabled = false
+		Expect(reconciler.reconcileMaintenance(ctx, memory, "default")).To(Succeed())
+		Expect(memory.Status.Maintenance).To(BeNil())
+		Expect(meta.FindStatusCondition(memory.Status.Conditions, ConditionTypeDatabaseIntegrity)).To(BeNil())
+	})
+})
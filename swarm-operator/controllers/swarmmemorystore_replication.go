@@ -0,0 +1,495 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypePrimaryReady reports whether the replicated memory
+	// service has a primary accepting writes
+	ConditionTypePrimaryReady = "PrimaryReady"
+
+	ReasonPrimaryAvailable     = "PrimaryAvailable"
+	ReasonPrimaryUnavailable   = "PrimaryUnavailable"
+	ReasonFollowerPromoted     = "FollowerPromoted"
+	ReasonNoPromotableFollower = "NoPromotableFollower"
+
+	// memoryRoleLabel carries the replication role of a memory pod. Pods
+	// read it through the downward API and promote or demote themselves
+	// when the operator changes it.
+	memoryRoleLabel = "swarm.claudeflow.io/memory-role"
+	// memoryReadableLabel selects the pods behind the read Service
+	memoryReadableLabel = "swarm.claudeflow.io/memory-readable"
+
+	memoryRolePrimary  = "primary"
+	memoryRoleFollower = "follower"
+
+	defaultReplicationMode      = "wal"
+	defaultSnapshotInterval     = 10 * time.Minute
+	defaultFailoverTimeout      = 30 * time.Second
+	defaultMaxReplicaLagSeconds = int64(30)
+	replicationStatusInterval   = 15 * time.Second
+	replicationStatusPath       = "/replication"
+)
+
+// replicationEnabled reports whether the memory service runs followers
+func replicationEnabled(memory *swarmv1alpha1.SwarmMemoryStore) bool {
+	return memory.Spec.Replication != nil && memory.Spec.Replication.Enabled
+}
+
+// replicaStatefulSetName is the StatefulSet of the followers. The primary
+// keeps running in the original StatefulSet so enabling replication does
+// not recreate it.
+func replicaStatefulSetName(memory *swarmv1alpha1.SwarmMemoryStore) string {
+	return memory.Name + "-replica"
+}
+
+func readServiceName(memory *swarmv1alpha1.SwarmMemoryStore) string {
+	return memory.Name + "-read"
+}
+
+// maxReplicaLag returns the lag beyond which followers stop serving reads
+func maxReplicaLag(memory *swarmv1alpha1.SwarmMemoryStore) int64 {
+	if memory.Spec.Replication.MaxLagSeconds > 0 {
+		return memory.Spec.Replication.MaxLagSeconds
+	}
+	return defaultMaxReplicaLagSeconds
+}
+
+// applyReplication configures the memory container to follow whichever pod
+// the write Service currently routes to
+func applyReplication(memory *swarmv1alpha1.SwarmMemoryStore, podSpec *corev1.PodSpec, namespace string) {
+	spec := memory.Spec.Replication
+	mode := spec.Mode
+	if mode == "" {
+		mode = defaultReplicationMode
+	}
+	scheme := "http"
+	if memory.Spec.TLSSecretName != "" {
+		scheme = "https"
+	}
+	snapshotInterval := parseDurationOrDefault(spec.SnapshotInterval, defaultSnapshotInterval)
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: podInfoVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{{
+					Path:     "labels",
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels"},
+				}},
+			},
+		},
+	})
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != "memory-service" {
+			continue
+		}
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "REPLICATION_ENABLED", Value: "true"},
+			corev1.EnvVar{Name: "REPLICATION_MODE", Value: mode},
+			corev1.EnvVar{Name: "REPLICATION_PRIMARY_URL", Value: fmt.Sprintf("%s://%s.%s.svc:%d", scheme, memory.Name, namespace, memoryHTTPPort)},
+			corev1.EnvVar{Name: "REPLICATION_SNAPSHOT_INTERVAL", Value: snapshotInterval.String()},
+			corev1.EnvVar{Name: "REPLICATION_ROLE_FILE", Value: podInfoMountPath + "/labels"},
+			corev1.EnvVar{Name: "REPLICATION_ROLE_LABEL", Value: memoryRoleLabel},
+			corev1.EnvVar{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+			}},
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      podInfoVolumeName,
+			MountPath: podInfoMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// reconcileReplicaStatefulSet runs the followers from the primary's
+// template, each with its own copy of the database
+func (r *SwarmMemoryStoreReconciler) reconcileReplicaStatefulSet(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, primary *appsv1.StatefulSet) error {
+	logger := log.FromContext(ctx)
+
+	name := replicaStatefulSetName(memory)
+	if !replicationEnabled(memory) {
+		sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: primary.Namespace}}
+		if err := r.Delete(ctx, sts); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	replicas := memory.Spec.Replication.Replicas
+	if replicas < 1 {
+		replicas = 1
+	}
+	if keyRotating(memory) {
+		replicas = 0
+	}
+
+	template := *primary.Spec.Template.DeepCopy()
+	var volumes []corev1.Volume
+	for _, volume := range template.Spec.Volumes {
+		// Followers keep their own database on a per-pod claim
+		if volume.Name != "data" {
+			volumes = append(volumes, volume)
+		}
+	}
+	template.Spec.Volumes = volumes
+
+	storageSize, err := memoryStorageSize(memory)
+	if err != nil {
+		return err
+	}
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: storageSize,
+				},
+			},
+		},
+	}
+	if memory.Spec.StorageClass != "" {
+		claim.Spec.StorageClassName = &memory.Spec.StorageClass
+	} else {
+		claim.Spec.StorageClassName = operatorStorageClass(r.Config)
+	}
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: primary.Namespace,
+			Labels:    primary.Labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName:          primary.Spec.ServiceName,
+			Replicas:             &replicas,
+			Selector:             primary.Spec.Selector.DeepCopy(),
+			Template:             template,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim},
+		},
+	}
+	if primary.Namespace == memory.Namespace {
+		if err := controllerutil.SetControllerReference(memory, sts, r.Scheme); err != nil {
+			return err
+		}
+	}
+
+	found := &appsv1.StatefulSet{}
+	err = r.Get(ctx, types.NamespacedName{Name: name, Namespace: sts.Namespace}, found)
+	if errors.IsNotFound(err) {
+		logger.Info("Creating replica StatefulSet", "Name", name, "Namespace", sts.Namespace)
+		return r.Create(ctx, sts)
+	}
+	if err != nil {
+		return err
+	}
+	if found.Spec.Replicas != nil && *found.Spec.Replicas == replicas &&
+		equality.Semantic.DeepDerivative(sts.Spec.Template, found.Spec.Template) {
+		return nil
+	}
+	logger.Info("Updating replica StatefulSet", "Name", name, "Namespace", sts.Namespace)
+	found.Spec.Replicas = &replicas
+	found.Spec.Template = sts.Spec.Template
+	return r.Update(ctx, found)
+}
+
+// reconcileReadService exposes the readable pods for query traffic
+func (r *SwarmMemoryStoreReconciler) reconcileReadService(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: readServiceName(memory), Namespace: namespace}}
+	if !replicationEnabled(memory) {
+		if err := r.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Labels = map[string]string{
+			"app":         "swarm-memory",
+			"memory-name": memory.Name,
+		}
+		svc.Spec.Selector = map[string]string{
+			"app":               "swarm-memory",
+			"memory-name":       memory.Name,
+			memoryReadableLabel: "true",
+		}
+		svc.Spec.Ports = []corev1.ServicePort{
+			{Name: "grpc", Port: memoryGRPCPort, TargetPort: intstr.FromString("grpc")},
+			{Name: "http", Port: memoryHTTPPort, TargetPort: intstr.FromString("http")},
+		}
+		if namespace == memory.Namespace {
+			return controllerutil.SetControllerReference(memory, svc, r.Scheme)
+		}
+		return nil
+	})
+	return err
+}
+
+// replicationState is what a memory pod reports on its metrics port
+type replicationState struct {
+	Position     int64     `json:"position"`
+	LagSeconds   int64     `json:"lagSeconds"`
+	LastSyncTime time.Time `json:"lastSyncTime"`
+}
+
+// reconcileReplication collects the replication state of the memory pods,
+// promotes a follower when the primary has been unavailable for the
+// failover timeout and labels the pods with their role and readability
+func (r *SwarmMemoryStoreReconciler) reconcileReplication(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
+	logger := log.FromContext(ctx)
+
+	if !replicationEnabled(memory) {
+		memory.Status.Primary = ""
+		memory.Status.Replicas = nil
+		meta.RemoveStatusCondition(&memory.Status.Conditions, ConditionTypePrimaryReady)
+		return nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(namespace),
+		client.MatchingLabels{"app": "swarm-memory", "memory-name": memory.Name}); err != nil {
+		return err
+	}
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		// Skip migration and backup job pods
+		if pod.Labels["job-type"] == "" && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 2 * time.Second}
+	}
+
+	replicas := make([]swarmv1alpha1.MemoryReplicaStatus, 0, len(pods))
+	for _, pod := range pods {
+		replica := swarmv1alpha1.MemoryReplicaStatus{Pod: pod.Name, Ready: podReady(pod), LagSeconds: -1}
+		if replica.Ready && pod.Status.PodIP != "" {
+			url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, memoryMetricsPort, replicationStatusPath)
+			state, err := fetchReplicationState(ctx, httpClient, url)
+			if err != nil {
+				logger.V(1).Info("Failed to collect replication state", "pod", pod.Name, "error", err.Error())
+			} else {
+				replica.Position = state.Position
+				replica.LagSeconds = state.LagSeconds
+				if !state.LastSyncTime.IsZero() {
+					replica.LastSyncTime = &metav1.Time{Time: state.LastSyncTime}
+				}
+			}
+		}
+		replicas = append(replicas, replica)
+	}
+
+	primary := memory.Status.Primary
+	if primary == "" {
+		// Bootstrap with the pod holding the original database
+		primary = memory.Name + "-0"
+	}
+	primary = failover(memory, primary, replicas, time.Now())
+	memory.Status.Primary = primary
+
+	maxLag := maxReplicaLag(memory)
+	for i := range replicas {
+		replica := &replicas[i]
+		replica.Role = memoryRoleFollower
+		if replica.Pod == primary {
+			replica.Role = memoryRolePrimary
+			replica.LagSeconds = 0
+		}
+		replica.Readable = replica.Ready && (replica.Role == memoryRolePrimary || (replica.LagSeconds >= 0 && replica.LagSeconds <= maxLag))
+		if replica.LagSeconds < 0 {
+			replica.LagSeconds = 0
+		}
+
+		if err := r.labelMemoryPod(ctx, pods[i], replica.Role, replica.Readable); err != nil {
+			return err
+		}
+	}
+	memory.Status.Replicas = replicas
+	return nil
+}
+
+// reconcileZoneSpread reports whether the ready primary and followers run
+// in more than one zone
+func (r *SwarmMemoryStoreReconciler) reconcileZoneSpread(ctx context.Context, memory *swarmv1alpha1.SwarmMemoryStore, namespace string) error {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(namespace),
+		client.MatchingLabels{"app": "swarm-memory", "memory-name": memory.Name}); err != nil {
+		return err
+	}
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Labels["job-type"] == "" && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+
+	condition, err := zoneSpreadCondition(ctx, r.Client, ConditionTypeZoneSpread, pods,
+		failureDomainKey(memory.Spec.FailureDomains), memory.Generation)
+	if err != nil {
+		return err
+	}
+	if condition == nil {
+		meta.RemoveStatusCondition(&memory.Status.Conditions, ConditionTypeZoneSpread)
+		return nil
+	}
+	meta.SetStatusCondition(&memory.Status.Conditions, *condition)
+	return nil
+}
+
+// failover returns the pod that should be primary. A follower replaces the
+// primary once it has been unavailable for the failover timeout; the most
+// caught-up ready follower within the lag bound wins.
+func failover(memory *swarmv1alpha1.SwarmMemoryStore, primary string, replicas []swarmv1alpha1.MemoryReplicaStatus, now time.Time) string {
+	available := false
+	for _, replica := range replicas {
+		if replica.Pod == primary && replica.Ready {
+			available = true
+		}
+	}
+	if available {
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypePrimaryReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonPrimaryAvailable,
+			Message:            fmt.Sprintf("Primary %s is accepting writes", primary),
+			ObservedGeneration: memory.Generation,
+		})
+		return primary
+	}
+
+	condition := meta.FindStatusCondition(memory.Status.Conditions, ConditionTypePrimaryReady)
+	if condition == nil || condition.Status == metav1.ConditionTrue {
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypePrimaryReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonPrimaryUnavailable,
+			Message:            fmt.Sprintf("Primary %s is unavailable", primary),
+			ObservedGeneration: memory.Generation,
+		})
+		return primary
+	}
+
+	timeout := parseDurationOrDefault(memory.Spec.Replication.FailoverTimeout, defaultFailoverTimeout)
+	if now.Sub(condition.LastTransitionTime.Time) < timeout {
+		return primary
+	}
+
+	candidate := promotableFollower(replicas, primary, maxReplicaLag(memory))
+	if candidate == "" {
+		meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypePrimaryReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonNoPromotableFollower,
+			Message:            fmt.Sprintf("Primary %s is unavailable and no follower is within %ds of it", primary, maxReplicaLag(memory)),
+			ObservedGeneration: memory.Generation,
+		})
+		return primary
+	}
+
+	meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePrimaryReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonFollowerPromoted,
+		Message:            fmt.Sprintf("Promoted %s after primary %s became unavailable", candidate, primary),
+		ObservedGeneration: memory.Generation,
+	})
+	return candidate
+}
+
+// promotableFollower picks the ready follower with the least lag, or ""
+// when every follower lags too far behind or its lag is unknown
+func promotableFollower(replicas []swarmv1alpha1.MemoryReplicaStatus, primary string, maxLag int64) string {
+	candidate := ""
+	var best swarmv1alpha1.MemoryReplicaStatus
+	for _, replica := range replicas {
+		if replica.Pod == primary || !replica.Ready || replica.LagSeconds < 0 || replica.LagSeconds > maxLag {
+			continue
+		}
+		if candidate == "" || replica.Position > best.Position ||
+			(replica.Position == best.Position && replica.LagSeconds < best.LagSeconds) {
+			candidate, best = replica.Pod, replica
+		}
+	}
+	return candidate
+}
+
+// labelMemoryPod sets the role and readability labels of a memory pod
+func (r *SwarmMemoryStoreReconciler) labelMemoryPod(ctx context.Context, pod *corev1.Pod, role string, readable bool) error {
+	if pod.Labels[memoryRoleLabel] == role && pod.Labels[memoryReadableLabel] == fmt.Sprint(readable) {
+		return nil
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[memoryRoleLabel] = role
+	pod.Labels[memoryReadableLabel] = fmt.Sprint(readable)
+	return client.IgnoreNotFound(r.Patch(ctx, pod, patch))
+}
+
+func fetchReplicationState(ctx context.Context, httpClient *http.Client, url string) (*replicationState, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("memory service returned %s", resp.Status)
+	}
+
+	state := &replicationState{}
+	if err := json.NewDecoder(resp.Body).Decode(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
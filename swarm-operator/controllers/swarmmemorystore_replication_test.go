@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Memory replication", func() {
+	var memory *swarmv1alpha1.SwarmMemoryStore
+
+	BeforeEach(func() {
+		memory = &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmMemoryStoreSpec{
+				StorageSize: "1Gi",
+				Replication: &swarmv1alpha1.MemoryReplicationSpec{
+					Enabled:         true,
+					Replicas:        2,
+					FailoverTimeout: "30s",
+					MaxLagSeconds:   10,
+				},
+			},
+		}
+	})
+
+	It("promotes the most caught-up follower within the lag bound", func() {
+		replicas := []swarmv1alpha1.MemoryReplicaStatus{
+			{Pod: "swarm-memory-0", Ready: false},
+			{Pod: "swarm-memory-replica-0", Ready: true, Position: 90, LagSeconds: 2},
+			{Pod: "swarm-memory-replica-1", Ready: true, Position: 120, LagSeconds: 1},
+			{Pod: "swarm-memory-replica-2", Ready: true, Position: 200, LagSeconds: 60},
+			{Pod: "swarm-memory-replica-3", Ready: false, Position: 300, LagSeconds: 0},
+		}
+		Expect(promotableFollower(replicas, "swarm-memory-0", 10)).To(Equal("swarm-memory-replica-1"))
+		Expect(promotableFollower(replicas[:1], "swarm-memory-0", 10)).To(BeEmpty())
+	})
+
+	It("waits for the failover timeout before promoting", func() {
+		now := time.Now()
+		replicas := []swarmv1alpha1.MemoryReplicaStatus{
+			{Pod: "swarm-memory-0", Ready: false},
+			{Pod: "swarm-memory-replica-0", Ready: true, Position: 10, LagSeconds: 1},
+		}
+
+		Expect(failover(memory, "swarm-memory-0", replicas, now)).To(Equal("swarm-memory-0"))
+		condition := meta.FindStatusCondition(memory.Status.Conditions, ConditionTypePrimaryReady)
+		Expect(condition.Reason).To(Equal(ReasonPrimaryUnavailable))
+
+		condition.LastTransitionTime = metav1.NewTime(now.Add(-time.Minute))
+		Expect(failover(memory, "swarm-memory-0", replicas, now)).To(Equal("swarm-memory-replica-0"))
+		condition = meta.FindStatusCondition(memory.Status.Conditions, ConditionTypePrimaryReady)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(ReasonFollowerPromoted))
+	})
+
+	It("keeps an available primary", func() {
+		replicas := []swarmv1alpha1.MemoryReplicaStatus{{Pod: "swarm-memory-0", Ready: true}}
+		Expect(failover(memory, "swarm-memory-0", replicas, time.Now())).To(Equal("swarm-memory-0"))
+		Expect(meta.IsStatusConditionTrue(memory.Status.Conditions, ConditionTypePrimaryReady)).To(BeTrue())
+	})
+
+	It("points followers at the write Service", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "memory-service"}}}
+		applyReplication(memory, podSpec, "swarm")
+
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: "REPLICATION_PRIMARY_URL", Value: "http://swarm-memory.swarm.svc:8080",
+		}))
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "REPLICATION_MODE", Value: "wal"}))
+		Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", podInfoVolumeName)))
+		Expect(memoryServiceSelector(memory)).To(HaveKeyWithValue(memoryRoleLabel, memoryRolePrimary))
+	})
+
+	It("labels pods with their role and readability", func() {
+		ctx := context.Background()
+
+		pod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels:    map[string]string{"app": "swarm-memory", "memory-name": "swarm-memory"},
+				},
+				Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+			}
+		}
+		k8sClient := newTestClient(pod("swarm-memory-0", corev1.ConditionTrue), pod("swarm-memory-replica-0", corev1.ConditionTrue))
+		reconciler := &SwarmMemoryStoreReconciler{Client: k8sClient, Scheme: testScheme}
+
+		Expect(reconciler.reconcileReplication(ctx, memory, "default")).To(Succeed())
+		Expect(memory.Status.Primary).To(Equal("swarm-memory-0"))
+		Expect(memory.Status.Replicas).To(HaveLen(2))
+
+		primary := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "swarm-memory-0", Namespace: "default"}, primary)).To(Succeed())
+		Expect(primary.Labels).To(HaveKeyWithValue(memoryRoleLabel, memoryRolePrimary))
+		Expect(primary.Labels).To(HaveKeyWithValue(memoryReadableLabel, "true"))
+
+		// Without a reported lag the follower stays out of read traffic
+		follower := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "swarm-memory-replica-0", Namespace: "default"}, follower)).To(Succeed())
+		Expect(follower.Labels).To(HaveKeyWithValue(memoryRoleLabel, memoryRoleFollower))
+		Expect(follower.Labels).To(HaveKeyWithValue(memoryReadableLabel, "false"))
+	})
+
+	It("holds stores whose storage size is not a quantity", func() {
+		ctx := context.Background()
+		memory.Spec.StorageSize = "ten gigs"
+		k8sClient := newTestClient(memory)
+		reconciler := &SwarmMemoryStoreReconciler{Client: k8sClient, Scheme: testScheme}
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(memory)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		stored := &swarmv1alpha1.SwarmMemoryStore{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(memory), stored)).To(Succeed())
+		Expect(stored.Status.Phase).To(Equal("Failed"))
+		condition := meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeStorageConfigured)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonInvalidStorageSize))
+		claims := &corev1.PersistentVolumeClaimList{}
+		Expect(k8sClient.List(ctx, claims)).To(Succeed())
+		Expect(claims.Items).To(BeEmpty())
+
+		// Followers claim the same size as the primary
+		memory.Spec.StorageSize = "2Gi"
+		size, err := memoryStorageSize(memory)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(size.String()).To(Equal("2Gi"))
+		memory.Spec.StorageSize = ""
+		size, err = memoryStorageSize(memory)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(size.String()).To(Equal(defaultMemoryStorageSize))
+	})
+})
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	vectorBackendSQLiteVSS = "sqlite-vss"
+	vectorBackendQdrant    = "qdrant"
+
+	defaultVectorDimensions = int32(1536)
+	defaultVectorMetric     = "cosine"
+
+	// memorySearchPath serves similarity queries on the memory service and
+	// the memory proxy
+	memorySearchPath = "/v1/search"
+)
+
+// defaultRecallAgentTypes get semantic recall unless the store lists others
+var defaultRecallAgentTypes = []swarmv1alpha1.AgentType{
+	swarmv1alpha1.ResearcherAgent,
+	swarmv1alpha1.AnalystAgent,
+}
+
+// vectorIndexEnabled reports whether the memory service indexes embeddings
+func vectorIndexEnabled(memory *swarmv1alpha1.SwarmMemoryStore) bool {
+	return memory.Spec.VectorIndex != nil && memory.Spec.VectorIndex.Enabled
+}
+
+func vectorDimensions(memory *swarmv1alpha1.SwarmMemoryStore) int32 {
+	if memory.Spec.VectorIndex.Dimensions > 0 {
+		return memory.Spec.VectorIndex.Dimensions
+	}
+	return defaultVectorDimensions
+}
+
+// applyVectorIndex configures the memory container to embed entries and
+// patterns and to answer similarity queries from the selected ANN backend
+func applyVectorIndex(memory *swarmv1alpha1.SwarmMemoryStore, podSpec *corev1.PodSpec) {
+	spec := memory.Spec.VectorIndex
+	backend := spec.Backend
+	if backend == "" {
+		backend = vectorBackendSQLiteVSS
+	}
+	metric := spec.Metric
+	if metric == "" {
+		metric = defaultVectorMetric
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "VECTOR_INDEX_ENABLED", Value: "true"},
+		{Name: "VECTOR_BACKEND", Value: backend},
+		{Name: "VECTOR_DIMENSIONS", Value: fmt.Sprintf("%d", vectorDimensions(memory))},
+		{Name: "VECTOR_METRIC", Value: metric},
+	}
+	if spec.EmbeddingModel != "" {
+		env = append(env, corev1.EnvVar{Name: "EMBEDDING_MODEL", Value: spec.EmbeddingModel})
+	}
+	if backend == vectorBackendQdrant && spec.Qdrant != nil {
+		collection := spec.Qdrant.Collection
+		if collection == "" {
+			collection = memory.Name
+		}
+		env = append(env,
+			corev1.EnvVar{Name: "QDRANT_URL", Value: spec.Qdrant.URL},
+			corev1.EnvVar{Name: "QDRANT_COLLECTION", Value: collection},
+		)
+		if spec.Qdrant.APIKeySecretRef != nil {
+			env = append(env, corev1.EnvVar{Name: "QDRANT_API_KEY", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: spec.Qdrant.APIKeySecretRef,
+			}})
+		}
+	}
+
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == "memory-service" {
+			podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, env...)
+		}
+	}
+}
+
+// memorySearchEndpoint spreads similarity queries over the followers when
+// replication is enabled, since they never write
+func memorySearchEndpoint(memory *swarmv1alpha1.SwarmMemoryStore) string {
+	if memory.Status.Endpoints.Read != "" {
+		return memory.Status.Endpoints.Read + memorySearchPath
+	}
+	return memory.Status.Endpoints.HTTP + memorySearchPath
+}
+
+// recallAgentType reports whether agents of the given type get semantic
+// recall from the memory store
+func recallAgentType(memory *swarmv1alpha1.SwarmMemoryStore, agentType swarmv1alpha1.AgentType) bool {
+	agentTypes := memory.Spec.VectorIndex.RecallAgentTypes
+	if len(agentTypes) == 0 {
+		agentTypes = defaultRecallAgentTypes
+	}
+	for _, t := range agentTypes {
+		if t == agentType {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMemoryRecall points agents of the recall types at the search
+// endpoint of a memory store of the cluster with a vector index. Queries go
+// through the memory proxy when it was injected.
+func (r *AgentReconciler) applyMemoryRecall(ctx context.Context, swarmCluster *swarmv1alpha1.SwarmCluster, agentType swarmv1alpha1.AgentType, podSpec *corev1.PodSpec) error {
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := r.List(ctx, stores, client.InNamespace(swarmCluster.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range stores.Items {
+		store := &stores.Items[i]
+		if store.Spec.SwarmClusterRef != swarmCluster.Name || !vectorIndexEnabled(store) ||
+			store.Status.Endpoints.Search == "" || !recallAgentType(store, agentType) {
+			continue
+		}
+
+		searchURL := store.Status.Endpoints.Search
+		for _, container := range podSpec.Containers {
+			if container.Name == memoryProxyContainerName {
+				searchURL = fmt.Sprintf("http://localhost:%d%s", memoryProxyPort, memorySearchPath)
+			}
+		}
+		for j := range podSpec.Containers {
+			if podSpec.Containers[j].Name == agentContainerName {
+				podSpec.Containers[j].Env = append(podSpec.Containers[j].Env,
+					corev1.EnvVar{Name: "SWARM_MEMORY_RECALL", Value: "semantic"},
+					corev1.EnvVar{Name: "SWARM_MEMORY_SEARCH_URL", Value: searchURL},
+					corev1.EnvVar{Name: "SWARM_MEMORY_EMBEDDING_DIMENSIONS", Value: fmt.Sprintf("%d", vectorDimensions(store))},
+				)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// getVectorSchema adds the embedding tables next to the enhanced schema.
+// The ANN index over them lives in the sqlite-vss virtual tables or in
+// Qdrant and is built by the memory service, which loads the extension.
+func getVectorSchema() string {
+	return `-- Embeddings for semantic search
+CREATE TABLE IF NOT EXISTS memory_embeddings (
+    memory_id INTEGER PRIMARY KEY REFERENCES memory_store(id) ON DELETE CASCADE,
+    model TEXT NOT NULL,
+    dimensions INTEGER NOT NULL,
+    embedding BLOB NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS swarm_pattern_embeddings (
+    pattern_id TEXT PRIMARY KEY REFERENCES swarm_patterns(pattern_id) ON DELETE CASCADE,
+    model TEXT NOT NULL,
+    dimensions INTEGER NOT NULL,
+    embedding BLOB NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_embeddings_model ON memory_embeddings(model);
+CREATE INDEX IF NOT EXISTS idx_swarm_pattern_embeddings_model ON swarm_pattern_embeddings(model);
+`
+}
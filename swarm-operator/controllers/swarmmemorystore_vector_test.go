@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Memory vector index", func() {
+	var memory *swarmv1alpha1.SwarmMemoryStore
+
+	BeforeEach(func() {
+		memory = &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmMemoryStoreSpec{
+				SwarmClusterRef: "research",
+				VectorIndex: &swarmv1alpha1.VectorIndexSpec{
+					Enabled:        true,
+					Backend:        vectorBackendQdrant,
+					EmbeddingModel: "text-embedding-3-small",
+					Qdrant: &swarmv1alpha1.QdrantSpec{
+						URL: "http://qdrant.vector.svc:6333",
+						APIKeySecretRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "qdrant"},
+							Key:                  "api-key",
+						},
+					},
+				},
+			},
+			Status: swarmv1alpha1.SwarmMemoryStoreStatus{
+				Endpoints: swarmv1alpha1.SwarmMemoryEndpoints{HTTP: "http://swarm-memory.swarm.svc:8080"},
+			},
+		}
+	})
+
+	It("configures the memory service for the selected backend", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "memory-service"}}}
+		applyVectorIndex(memory, podSpec)
+
+		env := podSpec.Containers[0].Env
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "VECTOR_BACKEND", Value: "qdrant"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "VECTOR_DIMENSIONS", Value: "1536"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "VECTOR_METRIC", Value: "cosine"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "QDRANT_COLLECTION", Value: "swarm-memory"}))
+		Expect(env).To(ContainElement(HaveField("Name", "QDRANT_API_KEY")))
+	})
+
+	It("serves searches from the followers when replicated", func() {
+		Expect(memorySearchEndpoint(memory)).To(Equal("http://swarm-memory.swarm.svc:8080/v1/search"))
+
+		memory.Status.Endpoints.Read = "http://swarm-memory-read.swarm.svc:8080"
+		Expect(memorySearchEndpoint(memory)).To(Equal("http://swarm-memory-read.swarm.svc:8080/v1/search"))
+	})
+
+	It("wires semantic recall into researcher and analyst agents", func() {
+		memory.Status.Endpoints.Search = memorySearchEndpoint(memory)
+		r := &AgentReconciler{Client: newTestClient(memory)}
+		cluster := &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "research", Namespace: "default"}}
+
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: agentContainerName}}}
+		Expect(r.applyMemoryRecall(context.Background(), cluster, swarmv1alpha1.ResearcherAgent, podSpec)).To(Succeed())
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: "SWARM_MEMORY_SEARCH_URL", Value: "http://swarm-memory.swarm.svc:8080/v1/search",
+		}))
+
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{Name: agentContainerName}, {Name: memoryProxyContainerName}}}
+		Expect(r.applyMemoryRecall(context.Background(), cluster, swarmv1alpha1.AnalystAgent, podSpec)).To(Succeed())
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: "SWARM_MEMORY_SEARCH_URL", Value: "http://localhost:7070/v1/search",
+		}))
+
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{Name: agentContainerName}}}
+		Expect(r.applyMemoryRecall(context.Background(), cluster, swarmv1alpha1.CoderAgent, podSpec)).To(Succeed())
+		Expect(podSpec.Containers[0].Env).To(BeEmpty())
+	})
+})
@@ -0,0 +1,251 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+)
+
+const (
+	// ConditionTypeApplied reports whether the operator runs with the
+	// config's spec
+	ConditionTypeApplied = "Applied"
+
+	ReasonConfigApplied     = "Applied"
+	ReasonConfigInvalid     = "Invalid"
+	ReasonConfigNotSelected = "NotSelected"
+)
+
+// SwarmOperatorConfigReconciler hot-reloads the operator defaults from a
+// SwarmOperatorConfig
+type SwarmOperatorConfigReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Store    *operatorconfig.Store
+
+	// Name of the SwarmOperatorConfig the operator follows
+	Name string
+
+	// LogLevel is the level of the operator's logger, switched to the
+	// configured log level. Nil leaves the level to the manager flags.
+	LogLevel *zap.AtomicLevel
+
+	// MetricsRecorder records reconcile durations, disabled when nil
+	MetricsRecorder *metrics.MetricsRecorder
+}
+
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmoperatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmoperatorconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *SwarmOperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmOperatorConfig", req.NamespacedName)
+	log := log.FromContext(ctx)
+
+	config := &swarmv1alpha1.SwarmOperatorConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if errors.IsNotFound(err) {
+			if req.Name == r.Name {
+				log.Info("SwarmOperatorConfig removed, using the flag defaults")
+				r.Store.Reset()
+				r.applyLogLevel(ctx)
+			}
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonConfigApplied,
+		Message:            "The operator runs with this config",
+		ObservedGeneration: config.Generation,
+	}
+	applied := false
+	switch errs := swarmv1alpha1.ValidateOperatorConfig(&config.Spec, field.NewPath("spec")); {
+	case config.Name != r.Name:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonConfigNotSelected
+		condition.Message = fmt.Sprintf("The operator follows SwarmOperatorConfig %s", r.Name)
+	case len(errs) > 0:
+		// The previous settings stay in effect
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonConfigInvalid
+		condition.Message = errs.ToAggregate().Error()
+	default:
+		r.Store.Apply(operatorSettings(r.Store.Defaults(), &config.Spec))
+		r.applyLogLevel(ctx)
+		if config.Status.LastApplied == nil || !equality.Semantic.DeepEqual(config.Status.LastApplied, &config.Spec) {
+			now := metav1.Now()
+			config.Status.LastApplied = config.Spec.DeepCopy()
+			config.Status.LastAppliedTime = &now
+			applied = true
+			log.Info("Applied SwarmOperatorConfig", "generation", config.Generation)
+			r.Recorder.Event(config, corev1.EventTypeNormal, "ConfigApplied", "Operator defaults reloaded")
+		}
+	}
+
+	before := config.Status.ObservedGeneration
+	config.Status.ObservedGeneration = config.Generation
+	changed := meta.SetStatusCondition(&config.Status.Conditions, condition)
+	if changed && condition.Reason == ReasonConfigInvalid {
+		r.Recorder.Event(config, corev1.EventTypeWarning, ReasonConfigInvalid, condition.Message)
+	}
+	if changed || applied || before != config.Generation {
+		if err := r.Status().Update(ctx, config); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// applyLogLevel switches the operator's logger to the level in effect
+func (r *SwarmOperatorConfigReconciler) applyLogLevel(ctx context.Context) {
+	if r.LogLevel == nil {
+		return
+	}
+	level := zapcore.Level(r.Store.Get().LogLevel)
+	if level == r.LogLevel.Level() {
+		return
+	}
+	log.FromContext(ctx).Info("Switching log level", "level", level.String())
+	r.LogLevel.SetLevel(level)
+}
+
+// operatorSettings overlays the fields a config sets on the flag defaults
+func operatorSettings(defaults operatorconfig.Settings, spec *swarmv1alpha1.SwarmOperatorConfigSpec) operatorconfig.Settings {
+	settings := defaults
+	if executor := spec.Executor; executor != nil {
+		if executor.Image != "" {
+			settings.ExecutorImage = executor.Image
+		}
+		if executor.WindowsImage != "" {
+			settings.ExecutorWindowsImage = executor.WindowsImage
+		}
+		if executor.ScriptsConfigMap != "" {
+			settings.ExecutorScriptsConfigMap = executor.ScriptsConfigMap
+		}
+		if executor.InjectCredentialSecrets != nil {
+			settings.InjectCredentialSecrets = *executor.InjectCredentialSecrets
+		}
+		if executor.ProgressURL != "" {
+			settings.ExecutorProgressURL = executor.ProgressURL
+		}
+	}
+	if namespaces := spec.Namespaces; namespaces != nil {
+		if namespaces.Swarm != "" {
+			settings.SwarmNamespace = namespaces.Swarm
+		}
+		if namespaces.HiveMind != "" {
+			settings.HiveMindNamespace = namespaces.HiveMind
+		}
+	}
+	if spec.StorageClass != "" {
+		settings.StorageClass = spec.StorageClass
+	}
+	if level, err := zapcore.ParseLevel(spec.LogLevel); err == nil {
+		settings.LogLevel = int(level)
+	}
+	if spec.BackoffLimit != nil {
+		limit := *spec.BackoffLimit
+		settings.BackoffLimit = &limit
+	}
+	if priority := spec.Priority; priority != nil {
+		if priority.Classes != nil {
+			settings.PriorityClasses = *priority.Classes
+		}
+		if priority.Preemption != nil {
+			settings.PriorityPreemption = *priority.Preemption
+		}
+	}
+	if gc := spec.GarbageCollection; gc != nil {
+		if gc.KeepCompletedStorageFor != nil {
+			settings.KeepCompletedStorageFor = gc.KeepCompletedStorageFor.Duration
+		}
+		if gc.KeepTokenSecretsFor != nil {
+			settings.KeepTokenSecretsFor = gc.KeepTokenSecretsFor.Duration
+		}
+		if gc.DryRun != nil {
+			settings.GCDryRun = *gc.DryRun
+		}
+	}
+	if scan := spec.ImageScanning; scan != nil {
+		settings.ImageScan = operatorconfig.ImageScanSettings{
+			URL:         scan.URL,
+			Format:      scan.Format,
+			WarnOnly:    scan.Action == swarmv1alpha1.ImageScanWarn,
+			MaxCritical: scan.MaxCritical,
+			MaxHigh:     -1,
+			FailOpen:    scan.FailOpen,
+		}
+		if scan.MaxHigh != nil {
+			settings.ImageScan.MaxHigh = *scan.MaxHigh
+		}
+	}
+	return settings
+}
+
+// operatorNamespaces returns the default swarm and hive-mind namespaces,
+// hot-reloaded from the SwarmOperatorConfig when the manager follows one
+func operatorNamespaces(config *operatorconfig.Store, swarm, hivemind string) (string, string) {
+	if config == nil {
+		return swarm, hivemind
+	}
+	settings := config.Get()
+	return settings.SwarmNamespace, settings.HiveMindNamespace
+}
+
+// operatorStorageClass returns the storage class for claims naming none,
+// or nil to leave the cluster default
+func operatorStorageClass(config *operatorconfig.Store) *string {
+	if config == nil {
+		return nil
+	}
+	if class := config.Get().StorageClass; class != "" {
+		return &class
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwarmOperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swarmv1alpha1.SwarmOperatorConfig{}).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmoperatorconfig", r))
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+)
+
+var _ = Describe("SwarmOperatorConfig Controller", func() {
+	var (
+		ctx        context.Context
+		config     *swarmv1alpha1.SwarmOperatorConfig
+		store      *operatorconfig.Store
+		reconciler *SwarmOperatorConfigReconciler
+	)
+
+	reconcileConfig := func(name string) *swarmv1alpha1.SwarmOperatorConfig {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+		Expect(err).NotTo(HaveOccurred())
+		stored := &swarmv1alpha1.SwarmOperatorConfig{}
+		if err := reconciler.Get(ctx, types.NamespacedName{Name: name}, stored); err != nil {
+			return nil
+		}
+		return stored
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		inject := true
+		backoff := int32(2)
+		config = &swarmv1alpha1.SwarmOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-operator", Generation: 1},
+			Spec: swarmv1alpha1.SwarmOperatorConfigSpec{
+				Executor: &swarmv1alpha1.OperatorExecutorConfig{
+					Image:                   "ghcr.io/claude-flow/executor:2.0",
+					InjectCredentialSecrets: &inject,
+				},
+				Namespaces:   &swarmv1alpha1.OperatorNamespaceConfig{HiveMind: "hivemind-v2"},
+				StorageClass: "fast-ssd",
+				BackoffLimit: &backoff,
+				Priority:     &swarmv1alpha1.OperatorPriorityConfig{Classes: &inject},
+				GarbageCollection: &swarmv1alpha1.OperatorGCConfig{
+					KeepCompletedStorageFor: &metav1.Duration{Duration: 72 * time.Hour},
+				},
+			},
+		}
+		store = operatorconfig.NewStore(operatorconfig.Settings{
+			ExecutorImage:       "ghcr.io/claude-flow/executor:1.0",
+			SwarmNamespace:      "claude-flow-swarm",
+			HiveMindNamespace:   "claude-flow-hivemind",
+			KeepTokenSecretsFor: 24 * time.Hour,
+		})
+
+		k8sClient := newTestClient(config)
+		reconciler = &SwarmOperatorConfigReconciler{
+			Client:   k8sClient,
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+			Store:    store,
+			Name:     "swarm-operator",
+		}
+	})
+
+	It("applies the config over the flag defaults and records it", func() {
+		stored := reconcileConfig(config.Name)
+
+		settings := store.Get()
+		Expect(settings.ExecutorImage).To(Equal("ghcr.io/claude-flow/executor:2.0"))
+		Expect(settings.InjectCredentialSecrets).To(BeTrue())
+		Expect(settings.SwarmNamespace).To(Equal("claude-flow-swarm"))
+		Expect(settings.HiveMindNamespace).To(Equal("hivemind-v2"))
+		Expect(settings.StorageClass).To(Equal("fast-ssd"))
+		Expect(*settings.BackoffLimit).To(Equal(int32(2)))
+		Expect(settings.PriorityClasses).To(BeTrue())
+		Expect(settings.PriorityPreemption).To(BeFalse())
+		Expect(settings.KeepCompletedStorageFor).To(Equal(72 * time.Hour))
+		Expect(settings.KeepTokenSecretsFor).To(Equal(24 * time.Hour))
+
+		Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, ConditionTypeApplied)).To(BeTrue())
+		Expect(stored.Status.ObservedGeneration).To(Equal(int64(1)))
+		Expect(stored.Status.LastApplied).NotTo(BeNil())
+		Expect(stored.Status.LastApplied.StorageClass).To(Equal("fast-ssd"))
+		Expect(stored.Status.LastAppliedTime).NotTo(BeNil())
+	})
+
+	It("keeps the last applied settings when the config becomes invalid", func() {
+		reconcileConfig(config.Name)
+
+		stored := &swarmv1alpha1.SwarmOperatorConfig{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: config.Name}, stored)).To(Succeed())
+		negative := int32(-1)
+		stored.Spec.BackoffLimit = &negative
+		stored.Spec.StorageClass = "slow-hdd"
+		Expect(reconciler.Update(ctx, stored)).To(Succeed())
+
+		stored = reconcileConfig(config.Name)
+		Expect(store.Get().StorageClass).To(Equal("fast-ssd"))
+		Expect(*store.Get().BackoffLimit).To(Equal(int32(2)))
+
+		condition := meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeApplied)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonConfigInvalid))
+		Expect(stored.Status.LastApplied.StorageClass).To(Equal("fast-ssd"))
+	})
+
+	It("ignores configs the operator does not follow", func() {
+		other := &swarmv1alpha1.SwarmOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+			Spec:       swarmv1alpha1.SwarmOperatorConfigSpec{StorageClass: "other"},
+		}
+		Expect(reconciler.Create(ctx, other)).To(Succeed())
+
+		stored := reconcileConfig(other.Name)
+		Expect(store.Version()).To(BeZero())
+		Expect(meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeApplied).Reason).To(Equal(ReasonConfigNotSelected))
+	})
+
+	It("falls back to the flag defaults when the config is deleted", func() {
+		reconcileConfig(config.Name)
+		Expect(reconciler.Delete(ctx, config)).To(Succeed())
+
+		Expect(reconcileConfig(config.Name)).To(BeNil())
+		Expect(store.Get()).To(Equal(store.Defaults()))
+	})
+
+	It("switches the log level at runtime", func() {
+		level := zap.NewAtomicLevel()
+		reconciler.LogLevel = &level
+
+		stored := &swarmv1alpha1.SwarmOperatorConfig{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: config.Name}, stored)).To(Succeed())
+		stored.Spec.LogLevel = "debug"
+		Expect(reconciler.Update(ctx, stored)).To(Succeed())
+		reconcileConfig(config.Name)
+		Expect(level.Level()).To(Equal(zapcore.DebugLevel))
+
+		Expect(reconciler.Delete(ctx, stored)).To(Succeed())
+		reconcileConfig(config.Name)
+		Expect(level.Level()).To(Equal(zapcore.InfoLevel))
+	})
+
+	It("hot-reloads the executor image, namespaces and backoff limit of task Jobs", func() {
+		reconcileConfig(config.Name)
+
+		task := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "reload", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "cluster", Description: "reload"},
+		}
+		taskReconciler := &SwarmTaskReconciler{
+			Client:   newTestClient(task),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+			Executor: ExecutorConfig{Image: "ghcr.io/claude-flow/executor:1.0"},
+			Config:   store,
+		}
+
+		cluster := &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+		job, err := taskReconciler.createOrUpdateJob(ctx, task, cluster, "claude-flow-swarm", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("ghcr.io/claude-flow/executor:2.0"))
+		Expect(*job.Spec.BackoffLimit).To(Equal(int32(2)))
+
+		task.Spec.Type = "hivemind"
+		Expect(taskReconciler.determineNamespace(task)).To(Equal("hivemind-v2"))
+	})
+})
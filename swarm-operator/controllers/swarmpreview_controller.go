@@ -0,0 +1,467 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/circuitbreaker"
+	"github.com/claude-flow/swarm-operator/pkg/github"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+const (
+	// previewLabel names the SwarmPreview a cluster or task belongs to
+	previewLabel = "swarm.claudeflow.io/preview"
+
+	// pullRequestLabel carries the pull request number of a preview resource
+	pullRequestLabel = "swarm.claudeflow.io/pull-request"
+
+	// headSHALabel carries the commit a validation task runs against
+	headSHALabel = "swarm.claudeflow.io/head-sha"
+
+	defaultPreviewPollInterval = 2 * time.Minute
+	defaultPreviewTTL          = 24 * time.Hour
+	defaultPreviewCheckName    = "swarm-preview"
+
+	previewRunning   = "Running"
+	previewSucceeded = "Succeeded"
+	previewFailed    = "Failed"
+	previewExpired   = "Expired"
+)
+
+// SwarmPreviewReconciler reconciles a SwarmPreview object
+type SwarmPreviewReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	Recorder       record.EventRecorder
+	TokenGenerator *github.TokenGenerator
+	Breakers       *circuitbreaker.Registry
+
+	// MetricsRecorder records reconcile durations, disabled when nil
+	MetricsRecorder *metrics.MetricsRecorder
+}
+
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmpreviews,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmpreviews/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmpreviews/finalizers,verbs=update
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *SwarmPreviewReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmPreview", req.NamespacedName)
+	log := log.FromContext(ctx)
+
+	preview := &swarmv1alpha1.SwarmPreview{}
+	if err := r.Get(ctx, req.NamespacedName, preview); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Preview clusters and tasks are owned by the preview and garbage collected
+	if preview.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	pollInterval := parseDurationOrDefault(preview.Spec.PollInterval, defaultPreviewPollInterval)
+	ttl := parseDurationOrDefault(preview.Spec.TTL, defaultPreviewTTL)
+
+	if r.TokenGenerator == nil {
+		r.TokenGenerator = github.NewTokenGenerator(r.Client)
+	}
+	var repo *github.RepositoryClient
+	var prs []github.PullRequest
+	err := guardDependency(r.Breakers, dependencyGitHub, func() error {
+		var err error
+		repo, err = r.TokenGenerator.RepositoryClient(ctx, &preview.Spec.GitHubApp, preview.Spec.Repository, preview.Namespace)
+		if err != nil {
+			return err
+		}
+		prs, err = repo.ListOpenPullRequests(ctx)
+		return err
+	})
+	if err != nil {
+		return r.syncFailed(ctx, preview, err, pollInterval)
+	}
+
+	previous := map[int]swarmv1alpha1.PullRequestPreview{}
+	for _, p := range preview.Status.Previews {
+		previous[p.Number] = p
+	}
+
+	open := map[int]bool{}
+	previews := []swarmv1alpha1.PullRequestPreview{}
+	for _, pr := range prs {
+		if !matchesPreviewFilter(preview.Spec.Filter, pr) {
+			continue
+		}
+		open[pr.Number] = true
+
+		state, err := r.syncPullRequest(ctx, preview, repo, pr, previous[pr.Number], ttl)
+		if err != nil {
+			log.Error(err, "Failed to sync preview", "pullRequest", pr.Number)
+			r.Recorder.Eventf(preview, corev1.EventTypeWarning, "PreviewFailed", "Pull request #%d: %v", pr.Number, err)
+		}
+		previews = append(previews, state)
+	}
+
+	// Tear down previews of pull requests that closed or stopped matching
+	if err := r.teardownClosedPreviews(ctx, preview, open); err != nil {
+		log.Error(err, "Failed to tear down closed previews")
+		return ctrl.Result{}, err
+	}
+
+	sort.Slice(previews, func(i, j int) bool { return previews[i].Number < previews[j].Number })
+	active := int32(0)
+	for _, p := range previews {
+		if p.Phase != previewExpired {
+			active++
+		}
+	}
+	now := metav1.Now()
+	preview.Status.Previews = previews
+	preview.Status.ActivePreviews = active
+	preview.Status.LastSyncTime = &now
+	preview.Status.Message = ""
+	dependenciesRecovered(&preview.Status.Conditions)
+	if err := r.Status().Update(ctx, preview); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: pollInterval}, nil
+}
+
+// syncFailed records a GitHub sync failure and retries on the next poll
+func (r *SwarmPreviewReconciler) syncFailed(ctx context.Context, preview *swarmv1alpha1.SwarmPreview, err error, pollInterval time.Duration) (ctrl.Result, error) {
+	log.FromContext(ctx).Error(err, "Failed to sync pull requests", "repository", preview.Spec.Repository)
+	r.Recorder.Event(preview, corev1.EventTypeWarning, "SyncFailed", err.Error())
+
+	preview.Status.Message = err.Error()
+	requeueAfter := pollInterval
+	if wait, open := dependencyUnavailable(&preview.Status.Conditions, err); open && wait > requeueAfter {
+		requeueAfter = wait
+	}
+	if err := r.Status().Update(ctx, preview); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// syncPullRequest ensures the preview cluster and the validation task for
+// the pull request head, expires it after the TTL and reports finished
+// results back to GitHub
+func (r *SwarmPreviewReconciler) syncPullRequest(ctx context.Context, preview *swarmv1alpha1.SwarmPreview, repo *github.RepositoryClient, pr github.PullRequest, state swarmv1alpha1.PullRequestPreview, ttl time.Duration) (swarmv1alpha1.PullRequestPreview, error) {
+	// A new head commit gets a fresh validation run, reviving expired previews
+	if state.HeadSHA != pr.HeadSHA {
+		if state.Phase == previewExpired {
+			state.CreatedAt = nil
+		}
+		state.Number = pr.Number
+		state.HeadSHA = pr.HeadSHA
+		state.Phase = ""
+	}
+	if state.Phase == previewExpired {
+		return state, nil
+	}
+
+	if state.CreatedAt != nil && time.Since(state.CreatedAt.Time) > ttl {
+		if err := r.teardownPreview(ctx, preview, pr.Number); err != nil {
+			return state, err
+		}
+		r.Recorder.Eventf(preview, corev1.EventTypeNormal, "PreviewExpired", "Tore down preview of pull request #%d after %s", pr.Number, ttl)
+		state.Phase = previewExpired
+		state.Cluster = ""
+		state.Task = ""
+		return state, nil
+	}
+
+	cluster, err := r.ensurePreviewCluster(ctx, preview, pr)
+	if err != nil {
+		return state, err
+	}
+	state.Cluster = cluster.Name
+	if state.CreatedAt == nil {
+		created := cluster.CreationTimestamp
+		if created.IsZero() {
+			created = metav1.Now()
+		}
+		state.CreatedAt = &created
+	}
+
+	task, err := r.ensurePreviewTask(ctx, preview, pr, cluster.Name)
+	if err != nil {
+		return state, err
+	}
+	state.Task = task.Name
+
+	switch task.Status.Phase {
+	case "Completed":
+		state.Phase = previewSucceeded
+	case "Failed", taskPhaseDeadLettered:
+		state.Phase = previewFailed
+	default:
+		state.Phase = previewRunning
+	}
+
+	if state.Phase != previewRunning && state.ReportedSHA != pr.HeadSHA {
+		if err := r.reportPreview(ctx, preview, repo, pr, task); err != nil {
+			return state, err
+		}
+		state.ReportedSHA = pr.HeadSHA
+	}
+	return state, nil
+}
+
+// ensurePreviewCluster creates the SwarmCluster of a pull request preview
+func (r *SwarmPreviewReconciler) ensurePreviewCluster(ctx context.Context, preview *swarmv1alpha1.SwarmPreview, pr github.PullRequest) (*swarmv1alpha1.SwarmCluster, error) {
+	name := previewClusterName(preview, pr.Number)
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: preview.Namespace}, cluster)
+	if err == nil {
+		return cluster, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	cluster = &swarmv1alpha1.SwarmCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: preview.Namespace,
+			Labels:    previewLabels(preview, pr.Number),
+		},
+		Spec: *preview.Spec.ClusterTemplate.DeepCopy(),
+	}
+	if err := controllerutil.SetControllerReference(preview, cluster, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, cluster); err != nil {
+		return nil, err
+	}
+	r.Recorder.Eventf(preview, corev1.EventTypeNormal, "PreviewCreated", "Created preview swarm %s for pull request #%d", name, pr.Number)
+	return cluster, nil
+}
+
+// ensurePreviewTask creates the validation task for the pull request head
+// and removes tasks left over from earlier commits
+func (r *SwarmPreviewReconciler) ensurePreviewTask(ctx context.Context, preview *swarmv1alpha1.SwarmPreview, pr github.PullRequest, clusterName string) (*swarmv1alpha1.SwarmTask, error) {
+	name := previewTaskName(preview, pr.Number, pr.HeadSHA)
+
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, tasks, client.InNamespace(preview.Namespace),
+		client.MatchingLabels{previewLabel: preview.Name, pullRequestLabel: strconv.Itoa(pr.Number)}); err != nil {
+		return nil, err
+	}
+	var current *swarmv1alpha1.SwarmTask
+	for i := range tasks.Items {
+		if tasks.Items[i].Name == name {
+			current = &tasks.Items[i]
+			continue
+		}
+		if err := r.Delete(ctx, &tasks.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	if current != nil {
+		return current, nil
+	}
+
+	spec := preview.Spec.TaskTemplate.DeepCopy()
+	spec.SwarmCluster = clusterName
+	if len(spec.Repositories) == 0 {
+		spec.Repositories = []string{preview.Spec.Repository}
+	}
+	if spec.GitHubApp == nil {
+		spec.GitHubApp = preview.Spec.GitHubApp.DeepCopy()
+	}
+	if spec.Parameters == nil {
+		spec.Parameters = map[string]string{}
+	}
+	spec.Parameters["pr_number"] = strconv.Itoa(pr.Number)
+	spec.Parameters["head_sha"] = pr.HeadSHA
+	spec.Parameters["head_ref"] = pr.HeadRef
+	spec.Parameters["base_ref"] = pr.BaseRef
+
+	labels := previewLabels(preview, pr.Number)
+	labels[headSHALabel] = pr.HeadSHA
+	task := &swarmv1alpha1.SwarmTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: preview.Namespace,
+			Labels:    labels,
+		},
+		Spec: *spec,
+	}
+	if err := controllerutil.SetControllerReference(preview, task, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// reportPreview posts the validation result as a check run and, when
+// enabled, a pull request comment
+func (r *SwarmPreviewReconciler) reportPreview(ctx context.Context, preview *swarmv1alpha1.SwarmPreview, repo *github.RepositoryClient, pr github.PullRequest, task *swarmv1alpha1.SwarmTask) error {
+	success := task.Status.Phase == "Completed"
+	title := "Swarm validation failed"
+	if success {
+		title = "Swarm validation passed"
+	}
+	summary := task.Status.Message
+	if task.Status.Result != nil && task.Status.Result.Summary != "" {
+		summary = task.Status.Result.Summary
+	}
+	if summary == "" {
+		summary = fmt.Sprintf("Task %s finished with phase %s", task.Name, task.Status.Phase)
+	}
+
+	checkName := preview.Spec.CheckName
+	if checkName == "" {
+		checkName = defaultPreviewCheckName
+	}
+	err := guardDependency(r.Breakers, dependencyGitHub, func() error {
+		if err := repo.ReportCheck(ctx, checkName, pr.HeadSHA, success, title, summary); err != nil {
+			return err
+		}
+		if preview.Spec.Comment {
+			body := fmt.Sprintf("**%s** for %s\n\n%s", title, pr.HeadSHA, summary)
+			return repo.Comment(ctx, pr.Number, body)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.Recorder.Eventf(preview, corev1.EventTypeNormal, "PreviewReported", "Reported %s for pull request #%d", task.Status.Phase, pr.Number)
+	return nil
+}
+
+// teardownClosedPreviews deletes preview resources of pull requests not in open
+func (r *SwarmPreviewReconciler) teardownClosedPreviews(ctx context.Context, preview *swarmv1alpha1.SwarmPreview, open map[int]bool) error {
+	clusters := &swarmv1alpha1.SwarmClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(preview.Namespace),
+		client.MatchingLabels{previewLabel: preview.Name}); err != nil {
+		return err
+	}
+
+	for _, cluster := range clusters.Items {
+		number, err := strconv.Atoi(cluster.Labels[pullRequestLabel])
+		if err != nil || open[number] {
+			continue
+		}
+		if err := r.teardownPreview(ctx, preview, number); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(preview, corev1.EventTypeNormal, "PreviewDeleted", "Tore down preview of closed pull request #%d", number)
+	}
+	return nil
+}
+
+// teardownPreview deletes the tasks and cluster of a pull request preview
+func (r *SwarmPreviewReconciler) teardownPreview(ctx context.Context, preview *swarmv1alpha1.SwarmPreview, number int) error {
+	selector := client.MatchingLabels{previewLabel: preview.Name, pullRequestLabel: strconv.Itoa(number)}
+	if err := r.DeleteAllOf(ctx, &swarmv1alpha1.SwarmTask{}, client.InNamespace(preview.Namespace), selector); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	cluster.Name = previewClusterName(preview, number)
+	cluster.Namespace = preview.Namespace
+	if err := r.Delete(ctx, cluster); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// matchesPreviewFilter reports whether a pull request gets a preview
+func matchesPreviewFilter(filter swarmv1alpha1.PreviewFilter, pr github.PullRequest) bool {
+	if pr.Draft && !filter.IncludeDrafts {
+		return false
+	}
+	if len(filter.BaseBranches) > 0 && !containsString(filter.BaseBranches, pr.BaseRef) {
+		return false
+	}
+	if len(filter.Authors) > 0 && !containsString(filter.Authors, pr.Author) {
+		return false
+	}
+	for _, label := range filter.Labels {
+		if !containsString(pr.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+func previewLabels(preview *swarmv1alpha1.SwarmPreview, number int) map[string]string {
+	return map[string]string{
+		previewLabel:     preview.Name,
+		pullRequestLabel: strconv.Itoa(number),
+	}
+}
+
+// previewClusterName returns the SwarmCluster name of a pull request preview
+func previewClusterName(preview *swarmv1alpha1.SwarmPreview, number int) string {
+	return fmt.Sprintf("%s-pr-%d", preview.Name, number)
+}
+
+// previewTaskName returns the validation task name for a pull request head
+func previewTaskName(preview *swarmv1alpha1.SwarmPreview, number int, headSHA string) string {
+	if len(headSHA) > 7 {
+		headSHA = headSHA[:7]
+	}
+	return fmt.Sprintf("%s-pr-%d-%s", preview.Name, number, headSHA)
+}
+
+func parseDurationOrDefault(value string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwarmPreviewReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swarmv1alpha1.SwarmPreview{}).
+		Owns(&swarmv1alpha1.SwarmCluster{}).
+		Owns(&swarmv1alpha1.SwarmTask{}).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmpreview", r))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/github"
+)
+
+var _ = Describe("SwarmPreview", func() {
+	pr := github.PullRequest{
+		Number:  42,
+		HeadSHA: "0123456789abcdef",
+		BaseRef: "main",
+		Author:  "octocat",
+		Labels:  []string{"preview", "backend"},
+	}
+
+	It("should match everything with an empty filter", func() {
+		Expect(matchesPreviewFilter(swarmv1alpha1.PreviewFilter{}, pr)).To(BeTrue())
+	})
+
+	It("should require every filter label", func() {
+		filter := swarmv1alpha1.PreviewFilter{Labels: []string{"preview", "frontend"}}
+		Expect(matchesPreviewFilter(filter, pr)).To(BeFalse())
+	})
+
+	It("should filter on base branch and author", func() {
+		Expect(matchesPreviewFilter(swarmv1alpha1.PreviewFilter{BaseBranches: []string{"release"}}, pr)).To(BeFalse())
+		Expect(matchesPreviewFilter(swarmv1alpha1.PreviewFilter{Authors: []string{"octocat"}}, pr)).To(BeTrue())
+	})
+
+	It("should skip drafts unless included", func() {
+		draft := pr
+		draft.Draft = true
+		Expect(matchesPreviewFilter(swarmv1alpha1.PreviewFilter{}, draft)).To(BeFalse())
+		Expect(matchesPreviewFilter(swarmv1alpha1.PreviewFilter{IncludeDrafts: true}, draft)).To(BeTrue())
+	})
+
+	It("should name preview resources after the pull request", func() {
+		preview := &swarmv1alpha1.SwarmPreview{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+		Expect(previewClusterName(preview, 42)).To(Equal("web-pr-42"))
+		Expect(previewTaskName(preview, 42, pr.HeadSHA)).To(Equal("web-pr-42-0123456"))
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/archive"
+)
+
+// archiveTask writes the record of a finished task to the archive sink of
+// its cluster and marks the task as archived. Tasks of clusters without an
+// archive are left alone.
+func (r *SwarmTaskReconciler) archiveTask(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	err := r.Get(ctx, types.NamespacedName{Name: task.Spec.SwarmCluster, Namespace: task.Namespace}, cluster)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	spec := cluster.Spec.Archive
+	if spec == nil {
+		return nil
+	}
+
+	sink, err := r.archiveSink(ctx, cluster, spec)
+	if err != nil {
+		return err
+	}
+	record, err := archive.NewRecord(task)
+	if err != nil {
+		return err
+	}
+	if err := sink.Archive(ctx, record); err != nil {
+		r.Recorder.Eventf(task, corev1.EventTypeWarning, "ArchiveFailed", "Failed to archive task: %v", err)
+		return err
+	}
+
+	now := metav1.Now()
+	task.Status.ArchivedAt = &now
+	return r.Status().Update(ctx, task)
+}
+
+// archiveSink builds the sink configured for the cluster
+func (r *SwarmTaskReconciler) archiveSink(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, spec *swarmv1alpha1.TaskArchiveSpec) (archive.Sink, error) {
+	switch spec.Sink {
+	case "", archive.SinkMemory:
+		return &archive.MemorySink{Client: r.Client, TTL: spec.RetentionDays * 24 * 60 * 60}, nil
+	case archive.SinkWebhook:
+		if spec.URL == "" {
+			return nil, fmt.Errorf("archive of SwarmCluster %s has no webhook url", cluster.Name)
+		}
+		var token string
+		if ref := spec.SecretRef; ref != nil {
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = cluster.Namespace
+			}
+			secret := &corev1.Secret{}
+			if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+				return nil, fmt.Errorf("failed to read archive sink secret %s: %w", ref.Name, err)
+			}
+			token = string(secret.Data[ref.Key])
+		}
+		return archive.NewWebhookSink(spec.URL, token), nil
+	}
+	return nil, fmt.Errorf("unknown archive sink %q", spec.Sink)
+}
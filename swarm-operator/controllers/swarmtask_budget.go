@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+const (
+	// taskPhaseCancelled marks tasks stopped before they finished
+	taskPhaseCancelled = "Cancelled"
+
+	// ConditionTypeBudgetExceeded reports that the usage of the task
+	// reached a limit of spec.budget
+	ConditionTypeBudgetExceeded = "BudgetExceeded"
+
+	ReasonBudgetExceeded = "BudgetExceeded"
+)
+
+// budgetEnvironment passes the limits of the task budget to the executor
+func budgetEnvironment(budget *swarmv1alpha1.TaskBudget) []corev1.EnvVar {
+	if budget == nil {
+		return nil
+	}
+	var env []corev1.EnvVar
+	if budget.MaxTokens != nil {
+		env = append(env, corev1.EnvVar{Name: executor.EnvBudgetMaxTokens, Value: strconv.FormatInt(*budget.MaxTokens, 10)})
+	}
+	if budget.MaxAPICalls != nil {
+		env = append(env, corev1.EnvVar{Name: executor.EnvBudgetMaxAPICalls, Value: strconv.FormatInt(*budget.MaxAPICalls, 10)})
+	}
+	if budget.MaxCost != nil {
+		env = append(env, corev1.EnvVar{Name: executor.EnvBudgetMaxCost, Value: strconv.FormatFloat(*budget.MaxCost, 'f', -1, 64)})
+	}
+	return env
+}
+
+// budgetViolation describes the first limit of the budget the usage
+// reached, or returns ""
+func budgetViolation(budget *swarmv1alpha1.TaskBudget, usage *swarmv1alpha1.TaskUsage) string {
+	if budget == nil || usage == nil {
+		return ""
+	}
+	switch {
+	case budget.MaxTokens != nil && usage.Tokens >= *budget.MaxTokens:
+		return fmt.Sprintf("used %d tokens of a budget of %d", usage.Tokens, *budget.MaxTokens)
+	case budget.MaxAPICalls != nil && usage.APICalls >= *budget.MaxAPICalls:
+		return fmt.Sprintf("made %d API calls of a budget of %d", usage.APICalls, *budget.MaxAPICalls)
+	case budget.MaxCost != nil && usage.Cost >= *budget.MaxCost:
+		return fmt.Sprintf("spent an estimated $%.2f of a budget of $%.2f", usage.Cost, *budget.MaxCost)
+	}
+	return ""
+}
+
+// enforceBudget cancels the task once its reported usage reaches a limit
+// of its budget: the Job is deleted with its pods and the task is not
+// retried. It reports whether the task was cancelled.
+func (r *SwarmTaskReconciler) enforceBudget(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string) (bool, error) {
+	violation := budgetViolation(task.Spec.Budget, task.Status.Usage)
+	if violation == "" || taskFinished(task) {
+		return false, nil
+	}
+
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: namespace}}
+	propagation := metav1.DeletePropagationBackground
+	if err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	message := "Task cancelled, it " + violation
+	now := metav1.Now()
+	task.Status.Phase = taskPhaseCancelled
+	task.Status.CompletionTime = &now
+	task.Status.Message = message
+	meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeBudgetExceeded,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonBudgetExceeded,
+		Message:            message,
+		ObservedGeneration: task.Generation,
+	})
+	if err := r.Status().Update(ctx, task); err != nil {
+		return false, err
+	}
+	r.Recorder.Event(task, corev1.EventTypeWarning, ReasonBudgetExceeded, message)
+	return true, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Task budget", func() {
+	var (
+		ctx      context.Context
+		task     *swarmv1alpha1.SwarmTask
+		recorder *record.FakeRecorder
+	)
+
+	maxTokens := int64(10000)
+	maxCost := 2.5
+
+	reconcilerFor := func(objects ...client.Object) *SwarmTaskReconciler {
+		k8sClient := newTestClient(objects...)
+		return &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: recorder}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "refactor", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				Budget:       &swarmv1alpha1.TaskBudget{MaxTokens: &maxTokens, MaxCost: &maxCost},
+			},
+			Status: swarmv1alpha1.SwarmTaskStatus{
+				Phase: "Running",
+				Usage: &swarmv1alpha1.TaskUsage{Tokens: 4000, APICalls: 12, Cost: 0.8},
+			},
+		}
+	})
+
+	It("passes the limits to the executor", func() {
+		env := (&SwarmTaskReconciler{}).buildEnvironment(task, "")
+		Expect(env).To(ContainElements(
+			corev1.EnvVar{Name: executor.EnvBudgetMaxTokens, Value: "10000"},
+			corev1.EnvVar{Name: executor.EnvBudgetMaxCost, Value: "2.5"},
+		))
+		for _, e := range env {
+			Expect(e.Name).NotTo(Equal(executor.EnvBudgetMaxAPICalls))
+		}
+	})
+
+	It("lets tasks within their budget run", func() {
+		r := reconcilerFor(task)
+
+		cancelled, err := r.enforceBudget(ctx, task, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cancelled).To(BeFalse())
+		Expect(task.Status.Phase).To(Equal("Running"))
+	})
+
+	It("cancels the task once a limit is reached", func() {
+		task.Status.Usage.Cost = 2.6
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "default"}}
+		r := reconcilerFor(task, job)
+
+		cancelled, err := r.enforceBudget(ctx, task, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cancelled).To(BeTrue())
+
+		err = r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: "default"}, &batchv1.Job{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		current := &swarmv1alpha1.SwarmTask{}
+		Expect(r.Get(ctx, types.NamespacedName{Name: "refactor", Namespace: "default"}, current)).To(Succeed())
+		Expect(current.Status.Phase).To(Equal(taskPhaseCancelled))
+		Expect(current.Status.CompletionTime).NotTo(BeNil())
+		Expect(current.Status.Message).To(ContainSubstring("$2.60 of a budget of $2.50"))
+		Expect(meta.IsStatusConditionTrue(current.Status.Conditions, ConditionTypeBudgetExceeded)).To(BeTrue())
+		Expect(taskFinished(current)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring(ReasonBudgetExceeded)))
+
+		// Cancelled tasks are not cancelled again
+		cancelled, err = r.enforceBudget(ctx, current, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cancelled).To(BeFalse())
+	})
+})
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/resultcache"
+)
+
+// resultCacheEnabled reports whether the task opted into result reuse
+func resultCacheEnabled(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Spec.CachePolicy == resultcache.PolicyReuse
+}
+
+// checkResultCache looks up a prior result for the task before its first Job
+// is created. On a hit the task is completed from the cache and true is
+// returned. The computed key is recorded so the lookup only happens once.
+func (r *SwarmTaskReconciler) checkResultCache(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string) (bool, error) {
+	log := log.FromContext(ctx)
+
+	defaults, err := r.taskDefaults(ctx, task, cluster, namespace)
+	if err != nil {
+		return false, err
+	}
+	key, err := resultcache.Key(&task.Spec, cluster.Name, r.executorImage(task, cluster, defaults))
+	if err != nil {
+		return false, err
+	}
+
+	result, err := resultcache.New(r.Client).Lookup(ctx, task.Namespace, cluster.Name, key)
+	if err != nil {
+		// A broken cache must never block the task from running
+		log.Error(err, "Result cache lookup failed", "key", key)
+	}
+
+	task.Status.CacheKey = key
+	if result == nil {
+		return false, r.Status().Update(ctx, task)
+	}
+
+	now := metav1.Now()
+	task.Status.Phase = "Completed"
+	task.Status.CacheHit = true
+	task.Status.Result = result
+	task.Status.Progress = 100
+	task.Status.StartTime = &now
+	task.Status.CompletionTime = &now
+	task.Status.Message = fmt.Sprintf("Reused cached result %s", key[:12])
+	if err := r.Status().Update(ctx, task); err != nil {
+		return false, err
+	}
+
+	r.Recorder.Event(task, corev1.EventTypeNormal, "CacheHit", task.Status.Message)
+	return true, nil
+}
+
+// storeResult records the result of a successful Job in the result cache.
+// Tasks that opted in after their first Job are keyed by the image that Job
+// ran.
+func (r *SwarmTaskReconciler) storeResult(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster) error {
+	key := task.Status.CacheKey
+	if key == "" {
+		var image string
+		if containers := job.Spec.Template.Spec.Containers; len(containers) > 0 {
+			image = containers[0].Image
+		}
+		var err error
+		if key, err = resultcache.Key(&task.Spec, cluster.Name, image); err != nil {
+			return err
+		}
+	}
+
+	result := task.Status.Result
+	if result == nil {
+		result = &swarmv1alpha1.TaskResult{
+			Success: true,
+			Summary: fmt.Sprintf("Job %s succeeded", job.Name),
+		}
+	}
+
+	return resultcache.New(r.Client).Store(ctx, task, cluster.Name, key, result)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+const (
+	checkoutContainerName = "git-checkout"
+	workspaceVolumeName   = "workspace"
+
+	defaultWorkspacePath = executor.DefaultWorkspace
+	defaultCheckoutImage = "alpine/git:2.43.0"
+)
+
+// repositoryCheckout is the resolved checkout of a single repository
+type repositoryCheckout struct {
+	url            string
+	dir            string
+	ref            string
+	depth          int32
+	sparseCheckout []string
+}
+
+// resolveCheckouts merges the task-wide checkout settings with the
+// per-repository overrides for every repository of the task
+func resolveCheckouts(task *swarmv1alpha1.SwarmTask) []repositoryCheckout {
+	spec := task.Spec.Checkout
+	depth := int32(1)
+	if spec.Depth != nil {
+		depth = *spec.Depth
+	}
+
+	overrides := map[string]swarmv1alpha1.RepositoryCheckout{}
+	for _, o := range spec.Repositories {
+		overrides[o.Repository] = o
+	}
+
+	checkouts := make([]repositoryCheckout, 0, len(task.Spec.Repositories))
+	for _, repo := range task.Spec.Repositories {
+		c := repositoryCheckout{
+			url:            repositoryURL(repo),
+			dir:            path.Base(strings.TrimSuffix(repo, ".git")),
+			ref:            spec.Ref,
+			depth:          depth,
+			sparseCheckout: spec.SparseCheckout,
+		}
+		if o, ok := overrides[repo]; ok {
+			if o.Ref != "" {
+				c.ref = o.Ref
+			}
+			if o.Depth != nil {
+				c.depth = *o.Depth
+			}
+			if len(o.SparseCheckout) > 0 {
+				c.sparseCheckout = o.SparseCheckout
+			}
+			if o.Path != "" {
+				c.dir = o.Path
+			}
+		}
+		checkouts = append(checkouts, c)
+	}
+	return checkouts
+}
+
+// repositoryURL turns owner/repo into a GitHub HTTPS clone URL
+func repositoryURL(repo string) string {
+	if strings.Contains(repo, "://") {
+		return repo
+	}
+	return fmt.Sprintf("https://github.com/%s.git", strings.TrimSuffix(repo, ".git"))
+}
+
+// checkoutScript renders the shell script of the clone init container. The
+// token is handed to git through a credential helper so it never ends up in
+// the cloned repository's config.
+func checkoutScript(workspace string, checkouts []repositoryCheckout) string {
+	var b strings.Builder
+	b.WriteString("set -eu\n")
+	b.WriteString(`if [ -n "${GITHUB_TOKEN:-}" ]; then` + "\n")
+	b.WriteString(`  git config --global credential.helper '!f() { echo username=x-access-token; echo "password=${GITHUB_TOKEN}"; }; f'` + "\n")
+	b.WriteString("fi\n")
+
+	for _, c := range checkouts {
+		dir := shellQuote(path.Join(workspace, c.dir))
+		fmt.Fprintf(&b, "git init -q %s\n", dir)
+		fmt.Fprintf(&b, "git -C %s remote add origin %s\n", dir, shellQuote(c.url))
+		if len(c.sparseCheckout) > 0 {
+			quoted := make([]string, len(c.sparseCheckout))
+			for i, p := range c.sparseCheckout {
+				quoted[i] = shellQuote(p)
+			}
+			fmt.Fprintf(&b, "git -C %s sparse-checkout set %s\n", dir, strings.Join(quoted, " "))
+		}
+
+		ref := c.ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		depth := ""
+		if c.depth > 0 {
+			depth = fmt.Sprintf(" --depth %d", c.depth)
+		}
+		fmt.Fprintf(&b, "git -C %s fetch -q%s origin %s\n", dir, depth, shellQuote(ref))
+		fmt.Fprintf(&b, "git -C %s checkout -q FETCH_HEAD\n", dir)
+	}
+	return b.String()
+}
+
+// checkoutPowerShellScript renders the checkout script for Windows tasks.
+// The token travels in a per-command HTTP header so it is never written to
+// any git config.
+func checkoutPowerShellScript(workspace string, checkouts []repositoryCheckout) string {
+	var b strings.Builder
+	b.WriteString("$ErrorActionPreference = 'Stop'\n")
+	b.WriteString("function Invoke-Git { git @args; if ($LASTEXITCODE -ne 0) { exit $LASTEXITCODE } }\n")
+	b.WriteString("$auth = @()\n")
+	b.WriteString("if ($env:GITHUB_TOKEN) {\n")
+	b.WriteString(`  $basic = [Convert]::ToBase64String([Text.Encoding]::ASCII.GetBytes("x-access-token:$($env:GITHUB_TOKEN)"))` + "\n")
+	b.WriteString(`  $auth = @('-c', "http.extraHeader=Authorization: Basic $basic")` + "\n")
+	b.WriteString("}\n")
+
+	for _, c := range checkouts {
+		dir := powerShellQuote(strings.TrimSuffix(workspace, `\`) + `\` + strings.ReplaceAll(c.dir, "/", `\`))
+		fmt.Fprintf(&b, "Invoke-Git init -q %s\n", dir)
+		fmt.Fprintf(&b, "Invoke-Git -C %s remote add origin %s\n", dir, powerShellQuote(c.url))
+		if len(c.sparseCheckout) > 0 {
+			quoted := make([]string, len(c.sparseCheckout))
+			for i, p := range c.sparseCheckout {
+				quoted[i] = powerShellQuote(p)
+			}
+			fmt.Fprintf(&b, "Invoke-Git -C %s sparse-checkout set %s\n", dir, strings.Join(quoted, " "))
+		}
+
+		ref := c.ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		depth := ""
+		if c.depth > 0 {
+			depth = fmt.Sprintf(" --depth %d", c.depth)
+		}
+		fmt.Fprintf(&b, "Invoke-Git @auth -C %s fetch -q%s origin %s\n", dir, depth, powerShellQuote(ref))
+		fmt.Fprintf(&b, "Invoke-Git -C %s checkout -q FETCH_HEAD\n", dir)
+	}
+	return b.String()
+}
+
+// shellQuote wraps s in single quotes for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// applyGitCheckout adds the clone init container and the shared workspace
+// volume to the executor pod when the task asks for a checkout
+func applyGitCheckout(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec, githubTokenSecret string) {
+	if task.Spec.Checkout == nil || len(task.Spec.Repositories) == 0 {
+		return
+	}
+
+	workspace := task.Spec.Checkout.WorkspacePath
+	if workspace == "" {
+		workspace = defaultWorkspacePath
+	}
+	image := task.Spec.Checkout.Image
+	if image == "" {
+		image = defaultCheckoutImage
+	}
+
+	mount := corev1.VolumeMount{Name: workspaceVolumeName, MountPath: workspace}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         workspaceVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+
+	// git needs a writable HOME for its global config
+	env := []corev1.EnvVar{{Name: "HOME", Value: "/tmp"}}
+	if githubTokenSecret != "" {
+		env = append(env, corev1.EnvVar{
+			Name: "GITHUB_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: githubTokenSecret},
+					Key:                  "token",
+				},
+			},
+		})
+	}
+
+	checkout := corev1.Container{
+		Name:         checkoutContainerName,
+		Image:        image,
+		Command:      []string{"/bin/sh", "-c"},
+		Args:         []string{checkoutScript(workspace, resolveCheckouts(task))},
+		Env:          env,
+		VolumeMounts: []corev1.VolumeMount{mount},
+	}
+	// Windows checkouts run PowerShell and write no global git config, so
+	// they need no HOME. Their mount paths are moved by applyTaskOS.
+	if windowsTask(task) {
+		checkout.Command = windowsCommand(checkoutPowerShellScript(windowsPath(workspace), resolveCheckouts(task)))
+		checkout.Args = nil
+		checkout.Env = env[1:]
+	}
+	podSpec.InitContainers = append(podSpec.InitContainers, checkout)
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, mount)
+	container.Env = append(container.Env, corev1.EnvVar{Name: executor.EnvWorkspace, Value: workspace})
+}
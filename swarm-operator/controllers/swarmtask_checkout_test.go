@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Git checkout", func() {
+	var task *swarmv1alpha1.SwarmTask
+
+	BeforeEach(func() {
+		full := int32(0)
+		task = &swarmv1alpha1.SwarmTask{
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				Repositories: []string{"claude-flow/swarm-operator", "claude-flow/docs"},
+				Checkout: &swarmv1alpha1.GitCheckoutSpec{
+					Ref: "main",
+					Repositories: []swarmv1alpha1.RepositoryCheckout{
+						{Repository: "claude-flow/docs", Ref: "v1.2.0", Depth: &full, SparseCheckout: []string{"guides"}, Path: "site"},
+					},
+				},
+			},
+		}
+	})
+
+	It("should merge per-repository overrides", func() {
+		checkouts := resolveCheckouts(task)
+		Expect(checkouts).To(HaveLen(2))
+
+		Expect(checkouts[0].url).To(Equal("https://github.com/claude-flow/swarm-operator.git"))
+		Expect(checkouts[0].dir).To(Equal("swarm-operator"))
+		Expect(checkouts[0].ref).To(Equal("main"))
+		Expect(checkouts[0].depth).To(Equal(int32(1)))
+
+		Expect(checkouts[1].dir).To(Equal("site"))
+		Expect(checkouts[1].ref).To(Equal("v1.2.0"))
+		Expect(checkouts[1].depth).To(Equal(int32(0)))
+		Expect(checkouts[1].sparseCheckout).To(Equal([]string{"guides"}))
+	})
+
+	It("should render shallow and sparse fetches", func() {
+		script := checkoutScript("/workspace", resolveCheckouts(task))
+		Expect(script).To(ContainSubstring("git -C '/workspace/swarm-operator' fetch -q --depth 1 origin 'main'"))
+		Expect(script).To(ContainSubstring("git -C '/workspace/site' sparse-checkout set 'guides'"))
+		Expect(script).To(ContainSubstring("git -C '/workspace/site' fetch -q origin 'v1.2.0'"))
+	})
+
+	It("should quote shell arguments", func() {
+		Expect(shellQuote("it's")).To(Equal(`'it'"'"'s'`))
+	})
+
+	It("should share the workspace with the executor", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applyGitCheckout(task, podSpec, "task-github-token")
+
+		Expect(podSpec.InitContainers).To(HaveLen(1))
+		Expect(podSpec.InitContainers[0].Env).To(ContainElement(HaveField("Name", "GITHUB_TOKEN")))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: workspaceVolumeName, MountPath: "/workspace"}))
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "SWARM_WORKSPACE", Value: "/workspace"}))
+	})
+
+	It("should leave tasks without checkout untouched", func() {
+		task.Spec.Checkout = nil
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applyGitCheckout(task, podSpec, "")
+		Expect(podSpec.InitContainers).To(BeEmpty())
+		Expect(podSpec.Volumes).To(BeEmpty())
+	})
+})
@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// Sharing the node of a memory store pod is preferred over sharing its
+	// zone, which is preferred over crossing zones
+	memoryNodeAffinityWeight = 100
+	memoryZoneAffinityWeight = 50
+)
+
+// clusterMemoryStore returns the SwarmMemoryStore of the cluster, or nil if
+// it has none
+func (r *SwarmTaskReconciler) clusterMemoryStore(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (*swarmv1alpha1.SwarmMemoryStore, error) {
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := r.List(ctx, stores, client.InNamespace(cluster.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range stores.Items {
+		if stores.Items[i].Spec.SwarmClusterRef == cluster.Name {
+			return &stores.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// memoryStoreNamespace is where the memory store controller runs the pods
+// of the store
+func (r *SwarmTaskReconciler) memoryStoreNamespace(store *swarmv1alpha1.SwarmMemoryStore) string {
+	if store.Spec.Namespace != "" {
+		return store.Spec.Namespace
+	}
+	swarmNamespace, _ := operatorNamespaces(r.Config, r.SwarmNamespace, r.HiveMindNamespace)
+	return swarmNamespace
+}
+
+// memoryStorePodLabels select the pods of the memory store
+func memoryStorePodLabels(store *swarmv1alpha1.SwarmMemoryStore) map[string]string {
+	return map[string]string{
+		"app":         "swarm-memory",
+		"memory-name": store.Name,
+	}
+}
+
+// applyMemoryColocation prefers the node, then the zone, of the memory
+// store pods for the task pod. The affinity is a preference so tasks still
+// run while the store is down or its nodes are full.
+func (r *SwarmTaskReconciler) applyMemoryColocation(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) error {
+	store, err := r.clusterMemoryStore(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		log.FromContext(ctx).Info("Cluster has no memory store to co-locate the task with")
+		return nil
+	}
+
+	term := func(topologyKey string) corev1.PodAffinityTerm {
+		return corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: memoryStorePodLabels(store)},
+			Namespaces:    []string{r.memoryStoreNamespace(store)},
+			TopologyKey:   topologyKey,
+		}
+	}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.PodAffinity == nil {
+		podSpec.Affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	affinity := podSpec.Affinity.PodAffinity
+	affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.WeightedPodAffinityTerm{Weight: memoryNodeAffinityWeight, PodAffinityTerm: term(corev1.LabelHostname)},
+		corev1.WeightedPodAffinityTerm{Weight: memoryZoneAffinityWeight, PodAffinityTerm: term(corev1.LabelTopologyZone)},
+	)
+	return nil
+}
+
+// recordMemoryPlacement measures the distance between the scheduled pod of
+// the job and the closest memory store pod. It reports whether the status
+// changed, which happens once per pod.
+func (r *SwarmTaskReconciler) recordMemoryPlacement(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster) (bool, error) {
+	pod, err := r.scheduledJobPod(ctx, job)
+	if err != nil || pod == nil {
+		return false, err
+	}
+	if current := task.Status.MemoryPlacement; current != nil && current.Pod == pod.Name && current.Distance != swarmv1alpha1.UnknownDistance {
+		return false, nil
+	}
+
+	store, err := r.clusterMemoryStore(ctx, cluster)
+	if err != nil || store == nil {
+		return false, err
+	}
+	memoryPods := &corev1.PodList{}
+	if err := r.List(ctx, memoryPods, client.InNamespace(r.memoryStoreNamespace(store)),
+		client.MatchingLabels(memoryStorePodLabels(store))); err != nil {
+		return false, err
+	}
+
+	zone, err := r.nodeZone(ctx, pod.Spec.NodeName)
+	if err != nil {
+		return false, err
+	}
+	placement := &swarmv1alpha1.MemoryPlacementStatus{
+		Store:    store.Name,
+		Distance: swarmv1alpha1.UnknownDistance,
+		Pod:      pod.Name,
+		Node:     pod.Spec.NodeName,
+	}
+	for i := range memoryPods.Items {
+		memoryNode := memoryPods.Items[i].Spec.NodeName
+		if memoryNode == "" {
+			continue
+		}
+		memoryZone, err := r.nodeZone(ctx, memoryNode)
+		if err != nil {
+			return false, err
+		}
+		distance := memoryDistance(pod.Spec.NodeName, zone, memoryNode, memoryZone)
+		if placement.MemoryNode == "" || closerToMemory(distance, placement.Distance) {
+			placement.Distance = distance
+			placement.MemoryNode = memoryNode
+		}
+	}
+
+	if current := task.Status.MemoryPlacement; current != nil && *current == *placement {
+		return false, nil
+	}
+	task.Status.MemoryPlacement = placement
+	return true, nil
+}
+
+// nodeZone returns the zone label of the node, "" if it has none or is gone
+func (r *SwarmTaskReconciler) nodeZone(ctx context.Context, name string) (string, error) {
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return node.Labels[corev1.LabelTopologyZone], nil
+}
+
+// memoryDistance classifies the distance between two nodes. Without zone
+// labels only sharing a node can be told.
+func memoryDistance(node, zone, memoryNode, memoryZone string) swarmv1alpha1.MemoryDistance {
+	switch {
+	case node == memoryNode:
+		return swarmv1alpha1.SameNodeDistance
+	case zone == "" || memoryZone == "":
+		return swarmv1alpha1.UnknownDistance
+	case zone == memoryZone:
+		return swarmv1alpha1.SameZoneDistance
+	}
+	return swarmv1alpha1.CrossZoneDistance
+}
+
+// closerToMemory reports whether distance a is closer than b
+func closerToMemory(a, b swarmv1alpha1.MemoryDistance) bool {
+	rank := map[swarmv1alpha1.MemoryDistance]int{
+		swarmv1alpha1.SameNodeDistance:  0,
+		swarmv1alpha1.SameZoneDistance:  1,
+		swarmv1alpha1.CrossZoneDistance: 2,
+		swarmv1alpha1.UnknownDistance:   3,
+	}
+	return rank[a] < rank[b]
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Memory co-location", func() {
+	var (
+		ctx     context.Context
+		cluster *swarmv1alpha1.SwarmCluster
+		store   *swarmv1alpha1.SwarmMemoryStore
+		task    *swarmv1alpha1.SwarmTask
+		job     *batchv1.Job
+	)
+
+	reconcilerFor := func(objects ...client.Object) *SwarmTaskReconciler {
+		k8sClient := newTestClient(objects...)
+		return &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, SwarmNamespace: "claude-flow-swarm"}
+	}
+
+	node := func(name, zone string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone},
+		}}
+	}
+
+	pod := func(name, namespace, nodeName string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"}}
+		store = &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "swarm"},
+		}
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "recall", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", ColocateWithMemory: true},
+		}
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "claude-flow-swarm"}}
+	})
+
+	It("prefers the node, then the zone, of the memory store pods", func() {
+		r := reconcilerFor(store)
+		podSpec := &corev1.PodSpec{}
+		Expect(r.applyMemoryColocation(ctx, cluster, podSpec)).To(Succeed())
+
+		terms := podSpec.Affinity.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		Expect(terms).To(HaveLen(2))
+		Expect(terms[0].Weight).To(BeNumerically(">", terms[1].Weight))
+		Expect(terms[0].PodAffinityTerm.TopologyKey).To(Equal(corev1.LabelHostname))
+		Expect(terms[1].PodAffinityTerm.TopologyKey).To(Equal(corev1.LabelTopologyZone))
+		Expect(terms[0].PodAffinityTerm.Namespaces).To(ConsistOf("claude-flow-swarm"))
+		Expect(terms[0].PodAffinityTerm.LabelSelector.MatchLabels).To(HaveKeyWithValue("memory-name", "swarm-memory"))
+		Expect(podSpec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(BeEmpty())
+	})
+
+	It("leaves tasks of clusters without a memory store alone", func() {
+		r := reconcilerFor()
+		podSpec := &corev1.PodSpec{}
+		Expect(r.applyMemoryColocation(ctx, cluster, podSpec)).To(Succeed())
+		Expect(podSpec.Affinity).To(BeNil())
+	})
+
+	It("reports the distance to the closest memory store pod", func() {
+		memoryLabels := memoryStorePodLabels(store)
+		r := reconcilerFor(store,
+			node("node-a", "us-east-1a"), node("node-b", "us-east-1a"), node("node-c", "us-east-1b"),
+			pod("swarm-memory-0", "claude-flow-swarm", "node-c", memoryLabels),
+			pod("swarm-memory-1", "claude-flow-swarm", "node-b", memoryLabels),
+			pod("recall-abc", "claude-flow-swarm", "node-a", map[string]string{"job-name": job.Name}),
+		)
+
+		changed, err := r.recordMemoryPlacement(ctx, task, job, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(*task.Status.MemoryPlacement).To(Equal(swarmv1alpha1.MemoryPlacementStatus{
+			Store:      "swarm-memory",
+			Distance:   swarmv1alpha1.SameZoneDistance,
+			Pod:        "recall-abc",
+			Node:       "node-a",
+			MemoryNode: "node-b",
+		}))
+
+		// The placement of a pod is measured once
+		changed, err = r.recordMemoryPlacement(ctx, task, job, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("classifies distances by node and zone", func() {
+		Expect(memoryDistance("a", "z1", "a", "z1")).To(Equal(swarmv1alpha1.SameNodeDistance))
+		Expect(memoryDistance("a", "z1", "b", "z1")).To(Equal(swarmv1alpha1.SameZoneDistance))
+		Expect(memoryDistance("a", "z1", "b", "z2")).To(Equal(swarmv1alpha1.CrossZoneDistance))
+		Expect(memoryDistance("a", "", "b", "z2")).To(Equal(swarmv1alpha1.UnknownDistance))
+	})
+})
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/computedenv"
+)
+
+// computedEnvVariables returns the objects computed variables of the task
+// are evaluated against
+func (r *SwarmTaskReconciler) computedEnvVariables(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster) (computedenv.Variables, error) {
+	var vars computedenv.Variables
+	var err error
+	if vars.Task, err = runtime.DefaultUnstructuredConverter.ToUnstructured(task); err != nil {
+		return vars, err
+	}
+	if cluster == nil {
+		return vars, nil
+	}
+	if vars.Cluster, err = runtime.DefaultUnstructuredConverter.ToUnstructured(cluster); err != nil {
+		return vars, err
+	}
+	store, err := r.clusterMemoryStore(ctx, cluster)
+	if err != nil || store == nil {
+		return vars, err
+	}
+	vars.Memory, err = runtime.DefaultUnstructuredConverter.ToUnstructured(store)
+	return vars, err
+}
+
+// applyComputedEnv sets the computed variables of the task on the task
+// container. A variable that fails to evaluate, e.g. because the memory
+// store has not reported its endpoint yet, fails building the Job so it is
+// retried rather than started without it.
+func (r *SwarmTaskReconciler) applyComputedEnv(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) error {
+	if len(task.Spec.ComputedEnv) == 0 {
+		return nil
+	}
+	vars, err := r.computedEnvVariables(ctx, task, cluster)
+	if err != nil {
+		return err
+	}
+	container := &podSpec.Containers[0]
+	for _, e := range task.Spec.ComputedEnv {
+		value, err := computedenv.Evaluate(e.Expression, vars)
+		if err != nil {
+			err = fmt.Errorf("computing %s: %w", e.Name, err)
+			r.Recorder.Event(task, corev1.EventTypeWarning, "ComputedEnvFailed", err.Error())
+			return err
+		}
+		container.Env = append(container.Env, corev1.EnvVar{Name: e.Name, Value: value})
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Computed task env", func() {
+	var (
+		ctx      context.Context
+		task     *swarmv1alpha1.SwarmTask
+		cluster  *swarmv1alpha1.SwarmCluster
+		recorder *record.FakeRecorder
+		podSpec  *corev1.PodSpec
+	)
+
+	reconcilerWith := func(objects ...client.Object) *SwarmTaskReconciler {
+		recorder = record.NewFakeRecorder(10)
+		return &SwarmTaskReconciler{
+			Client:   newTestClient(objects...),
+			Scheme:   testScheme,
+			Recorder: recorder,
+		}
+	}
+	memoryStore := func() *swarmv1alpha1.SwarmMemoryStore {
+		return &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm-memory", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "swarm"},
+			Status: swarmv1alpha1.SwarmMemoryStoreStatus{Endpoints: swarmv1alpha1.SwarmMemoryEndpoints{
+				HTTP: "http://swarm-memory.swarm-system.svc:8080",
+			}},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "index", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				Priority:     swarmv1alpha1.HighPriority,
+				ComputedEnv: []swarmv1alpha1.ComputedEnvVar{
+					{Name: "MEMORY_URL", Expression: `memory.status.endpoints.http + "/v1"`},
+					{Name: "WORKERS", Expression: `task.spec.priority == "high" ? cluster.status.readyAgents : 1`},
+				},
+			},
+		}
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Status:     swarmv1alpha1.SwarmClusterStatus{ReadyAgents: 4},
+		}
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+	})
+
+	It("evaluates the variables against the task, cluster and memory store", func() {
+		reconciler := reconcilerWith(memoryStore())
+		Expect(reconciler.applyComputedEnv(ctx, task, cluster, podSpec)).To(Succeed())
+		Expect(podSpec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+			{Name: "MEMORY_URL", Value: "http://swarm-memory.swarm-system.svc:8080/v1"},
+			{Name: "WORKERS", Value: "4"},
+		}))
+	})
+
+	It("lets expressions fall back for clusters without a memory store", func() {
+		task.Spec.ComputedEnv = []swarmv1alpha1.ComputedEnvVar{
+			{Name: "MEMORY_URL", Expression: `memory == null ? "" : memory.status.endpoints.http`},
+		}
+		reconciler := reconcilerWith()
+		Expect(reconciler.applyComputedEnv(ctx, task, cluster, podSpec)).To(Succeed())
+		Expect(podSpec.Containers[0].Env).To(Equal([]corev1.EnvVar{{Name: "MEMORY_URL", Value: ""}}))
+	})
+
+	It("fails building the Job until the variables can be computed", func() {
+		store := memoryStore()
+		store.Status.Endpoints.HTTP = ""
+		reconciler := reconcilerWith(store)
+		task.Spec.ComputedEnv[0].Expression = `memory.status.endpoints.http`
+
+		err := reconciler.applyComputedEnv(ctx, task, cluster, podSpec)
+		Expect(err).To(MatchError(ContainSubstring("computing MEMORY_URL: no such key: http")))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning ComputedEnvFailed")))
+		Expect(podSpec.Containers[0].Env).To(BeEmpty())
+	})
+})
@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/configtemplate"
+)
+
+const (
+	// taskConfigVolume holds the rendered config templates of a task
+	taskConfigVolume = "task-config"
+
+	configTemplateSecretType = "config-template"
+)
+
+// taskConfigSecretName returns the Secret holding the rendered config
+// templates of a Job. Every attempt renders its own, which is deleted with
+// its Job.
+func taskConfigSecretName(job *batchv1.Job) string {
+	return job.Name + "-config"
+}
+
+// taskEnv returns the task's own environment variables
+func taskEnv(task *swarmv1alpha1.SwarmTask) []corev1.EnvVar {
+	env := make([]corev1.EnvVar, 0, len(task.Spec.Env))
+	for _, e := range task.Spec.Env {
+		v := corev1.EnvVar{Name: e.Name, Value: e.Value}
+		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+			v.ValueFrom = &corev1.EnvVarSource{SecretKeyRef: e.ValueFrom.SecretKeyRef.DeepCopy()}
+		}
+		env = append(env, v)
+	}
+	return env
+}
+
+// applyConfigTemplates mounts every rendered config file read-only at its
+// mount path
+func applyConfigTemplates(task *swarmv1alpha1.SwarmTask, job *batchv1.Job) {
+	if len(task.Spec.ConfigTemplates) == 0 {
+		return
+	}
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: taskConfigVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: taskConfigSecretName(job)},
+		},
+	})
+	for _, t := range task.Spec.ConfigTemplates {
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      taskConfigVolume,
+			MountPath: t.MountPath,
+			SubPath:   t.Name,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// renderConfigTemplates renders the config templates of a task from the
+// secrets of the namespace it runs in. Tenants scoped to credentials may
+// only read those secrets; templates reading others are reported as
+// violations.
+func (r *SwarmTaskReconciler) renderConfigTemplates(ctx context.Context, task *swarmv1alpha1.SwarmTask, tenant *swarmv1alpha1.SwarmTenant, namespace string) (map[string][]byte, error) {
+	secrets := map[string]*corev1.Secret{}
+	var violations []string
+	lookup := func(name, key string) (string, error) {
+		if tenant != nil && tenant.Spec.Credentials != nil && !containsString(tenant.Spec.Credentials.AllowedSecrets, name) {
+			violations = append(violations, fmt.Sprintf("secret %s is outside the credentials of tenant %s", name, tenant.Name))
+			return "", fmt.Errorf("secret %s is not allowed", name)
+		}
+		secret, ok := secrets[name]
+		if !ok {
+			secret = &corev1.Secret{}
+			if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret); err != nil {
+				return "", err
+			}
+			secrets[name] = secret
+		}
+		value, ok := secret.Data[key]
+		if !ok {
+			return "", fmt.Errorf("secret %s has no key %s", name, key)
+		}
+		return string(value), nil
+	}
+
+	data := make(map[string][]byte, len(task.Spec.ConfigTemplates))
+	for _, t := range task.Spec.ConfigTemplates {
+		rendered, err := configtemplate.Render(t.Name, t.Template, lookup)
+		if len(violations) > 0 {
+			return nil, &tenantViolationError{violations: violations}
+		}
+		if err != nil {
+			return nil, err
+		}
+		data[t.Name] = rendered
+	}
+	return data, nil
+}
+
+// ensureConfigSecret stores the rendered config templates in a Secret
+// owned by the Job, rendering them if the caller has not already
+func (r *SwarmTaskReconciler) ensureConfigSecret(ctx context.Context, task *swarmv1alpha1.SwarmTask, tenant *swarmv1alpha1.SwarmTenant, job *batchv1.Job, data map[string][]byte) error {
+	if len(task.Spec.ConfigTemplates) == 0 {
+		return nil
+	}
+	name := taskConfigSecretName(job)
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: job.Namespace}, &corev1.Secret{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	if data == nil {
+		if data, err = r.renderConfigTemplates(ctx, task, tenant, job.Namespace); err != nil {
+			r.Recorder.Event(task, corev1.EventTypeWarning, "ConfigTemplateFailed", err.Error())
+			return err
+		}
+	}
+	labels := taskResourceLabels(task)
+	labels[secretTypeLabel] = configTemplateSecretType
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: job.Namespace, Labels: labels},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       data,
+	}
+	if err := controllerutil.SetControllerReference(job, secret, r.Scheme); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Creating config template secret", "secret", name)
+	if err := r.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task env and config templates", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		reconciler *SwarmTaskReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		k8sClient := newTestClient(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "tasks"},
+				Data:       map[string][]byte{"access-key": []byte("AKIA"), "secret-key": []byte("s3cr3t")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "tasks"},
+				Data:       map[string][]byte{"password": []byte("hunter2")},
+			},
+		)
+		reconciler = &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				Env: []swarmv1alpha1.TaskEnvVar{
+					{Name: "REGION", Value: "eu-west-1"},
+					{Name: "DB_PASSWORD", ValueFrom: &swarmv1alpha1.TaskEnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "db"},
+							Key:                  "password",
+						},
+					}},
+				},
+				ConfigTemplates: []swarmv1alpha1.TaskConfigTemplate{{
+					Name:      "credentials",
+					MountPath: "/root/.aws/credentials",
+					Template:  "[default]\naws_access_key_id = {{ secret \"aws\" \"access-key\" }}\naws_secret_access_key = {{ secret \"aws\" \"secret-key\" }}\n",
+				}},
+			},
+		}
+	})
+
+	It("passes the task's variables and secret keys to the executor", func() {
+		env := reconciler.buildEnvironment(task, "")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "REGION", Value: "eu-west-1"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "db"},
+				Key:                  "password",
+			},
+		}}))
+	})
+
+	It("mounts each rendered file at its path", func() {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate-1", Namespace: "tasks"}}
+		job.Spec.Template.Spec.Containers = []corev1.Container{{Name: "task"}}
+		applyConfigTemplates(task, job)
+
+		podSpec := job.Spec.Template.Spec
+		Expect(podSpec.Volumes).To(HaveLen(1))
+		Expect(podSpec.Volumes[0].Secret.SecretName).To(Equal("migrate-1-config"))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ConsistOf(corev1.VolumeMount{
+			Name: taskConfigVolume, MountPath: "/root/.aws/credentials", SubPath: "credentials", ReadOnly: true,
+		}))
+	})
+
+	It("renders the templates into a secret owned by the Job", func() {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate-1", Namespace: "tasks", UID: types.UID("job-uid")}}
+		Expect(reconciler.ensureConfigSecret(ctx, task, nil, job, nil)).To(Succeed())
+
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "migrate-1-config", Namespace: "tasks"}, secret)).To(Succeed())
+		Expect(string(secret.Data["credentials"])).To(Equal("[default]\naws_access_key_id = AKIA\naws_secret_access_key = s3cr3t\n"))
+		Expect(secret.Labels).To(HaveKeyWithValue(secretTypeLabel, configTemplateSecretType))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].UID).To(Equal(types.UID("job-uid")))
+	})
+
+	It("fails on missing secret keys", func() {
+		task.Spec.ConfigTemplates[0].Template = `{{ secret "aws" "session-token" }}`
+		_, err := reconciler.renderConfigTemplates(ctx, task, nil, "tasks")
+		Expect(err).To(MatchError(ContainSubstring("has no key session-token")))
+	})
+
+	It("keeps tenants to the secrets of their credentials", func() {
+		tenant := &swarmv1alpha1.SwarmTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: swarmv1alpha1.SwarmTenantSpec{
+				Credentials: &swarmv1alpha1.TenantCredentials{AllowedSecrets: []string{"db"}},
+			},
+		}
+		_, err := reconciler.renderConfigTemplates(ctx, task, tenant, "tasks")
+		violation, ok := err.(*tenantViolationError)
+		Expect(ok).To(BeTrue())
+		Expect(violation.Error()).To(ContainSubstring("secret aws is outside the credentials of tenant team-a"))
+
+		tenant.Spec.Credentials.AllowedSecrets = append(tenant.Spec.Credentials.AllowedSecrets, "aws")
+		_, err = reconciler.renderConfigTemplates(ctx, task, tenant, "tasks")
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -19,27 +19,58 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"math"
+	"net"
 	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/circuitbreaker"
+	"github.com/claude-flow/swarm-operator/pkg/deadletter"
+	"github.com/claude-flow/swarm-operator/pkg/dispatch"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
 	"github.com/claude-flow/swarm-operator/pkg/github"
+	"github.com/claude-flow/swarm-operator/pkg/imagescan"
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+	"github.com/claude-flow/swarm-operator/pkg/notify"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+	"github.com/claude-flow/swarm-operator/pkg/sharding"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
 )
 
 const (
 	swarmTaskFinalizer = "swarmtask.swarm.claudeflow.io/finalizer"
+
+	// requeueAnnotation asks the operator to run a dead-lettered or failed
+	// task again, e.g. `kubectl annotate swarmtasks -l ... swarm.claudeflow.io/requeue=true`
+	requeueAnnotation = "swarm.claudeflow.io/requeue"
+
+	// taskPhaseDeadLettered is set once a task has exhausted its retry policy
+	taskPhaseDeadLettered = "DeadLettered"
+
+	// maxDigestLogLines bounds the log tail kept in a failure digest
+	maxDigestLogLines = 20
 )
 
 // SwarmTaskReconciler reconciles a SwarmTask object
@@ -50,6 +81,41 @@ type SwarmTaskReconciler struct {
 	SwarmNamespace    string
 	HiveMindNamespace string
 	TokenGenerator    *github.TokenGenerator
+	Notifier          *notify.Notifier
+	Breakers          *circuitbreaker.Registry
+	MetricsRecorder   *metrics.MetricsRecorder
+	Dispatcher        *dispatch.Dispatcher
+	// Kube reads the logs of failed task pods. When nil, failure details
+	// fall back to the container termination messages.
+	Kube kubernetes.Interface
+	// LookupIPAddr resolves the allowed domains of task egress policies,
+	// defaults to net.DefaultResolver
+	LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	// Executor configures the image, scripts and credentials task Jobs run with
+	Executor ExecutorConfig
+	// NativeSidecars is set when the API server runs native sidecar
+	// containers, Kubernetes 1.29 and later. Task pods in a service mesh
+	// then run the proxy as one by default.
+	NativeSidecars bool
+	// Priority maps task priorities to the swarm PriorityClasses
+	Priority PriorityClassConfig
+	// Config hot-reloads the executor settings, namespaces, storage class,
+	// Job backoff limit and priority classes. When nil the fields above
+	// apply.
+	Config *operatorconfig.Store
+	// NewMemoryBackend connects to the memory store that keeps the
+	// fallback patterns, defaults to the HTTP backend
+	NewMemoryBackend func(endpoint string) memorycache.Backend
+	// NewImageScanner connects to the vulnerability scanner of the image
+	// scanning gate, defaults to the HTTP client
+	NewImageScanner func(url, format string) imagescan.Scanner
+	// CheckTargetCluster reaches the API server of the target cluster of a
+	// task with its kubeconfig and returns its version, defaults to asking
+	// the discovery API
+	CheckTargetCluster func(ctx context.Context, kubeconfig []byte) (string, error)
+	// Shard splits the tasks between the operator replicas. When nil this
+	// replica reconciles all tasks once elected leader.
+	Shard *sharding.Shard
 }
 
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks,verbs=get;list;watch;create;update;patch;delete
@@ -57,11 +123,25 @@ type SwarmTaskReconciler struct {
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks/finalizers,verbs=update
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmagents,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=agents,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemories,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmmemorystores,verbs=get;list;watch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmdefaults,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=node.k8s.io,resources=runtimeclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch;create;delete
 
 func (r *SwarmTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmTask", req.NamespacedName)
 	log := log.FromContext(ctx)
 
 	// Fetch the SwarmTask
@@ -73,6 +153,12 @@ func (r *SwarmTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 		return ctrl.Result{}, err
 	}
+	// Another replica dispatches the task
+	if !r.ownsTask(task) {
+		return ctrl.Result{}, nil
+	}
+	ctx = withTaskLogger(ctx, task)
+	log = ctrl.LoggerFrom(ctx)
 
 	// Handle deletion
 	if task.GetDeletionTimestamp() != nil {
@@ -98,6 +184,85 @@ func (r *SwarmTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 
+	// Handle bulk requeue requests
+	if _, ok := task.Annotations[requeueAnnotation]; ok {
+		return r.handleRequeue(ctx, task)
+	}
+
+	// Clone finished tasks into a new run when asked to
+	if _, ok := task.Annotations[rerunAnnotation]; ok && taskFinished(task) {
+		return r.handleRerun(ctx, task)
+	}
+
+	// Apply volume reclaim policies once the task has finished
+	if taskFinished(task) && len(task.Spec.PersistentVolumes) > 0 {
+		pending, err := r.reclaimTaskVolumes(ctx, task)
+		if err != nil {
+			log.Error(err, "Failed to reclaim task volumes")
+			return ctrl.Result{}, err
+		}
+		if pending {
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+
+	// Lift the egress restrictions once the task has finished
+	if taskFinished(task) && task.Spec.NetworkPolicy != nil {
+		if err := r.deleteTaskNetworkPolicy(ctx, task); err != nil {
+			log.Error(err, "Failed to delete task network policy")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Keep a record of finished tasks that outlives them
+	if taskFinished(task) && task.Status.ArchivedAt == nil {
+		if err := r.archiveTask(ctx, task); err != nil {
+			log.Error(err, "Failed to archive task")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Failed tasks can be inspected in a sleeping clone of their pod
+	if debugRequested(task) || task.Status.Debug != nil {
+		result, err := r.reconcileDebugPod(ctx, task)
+		if err != nil {
+			log.Error(err, "Failed to reconcile debug pod")
+			return ctrl.Result{}, err
+		}
+		if !result.IsZero() {
+			return result, nil
+		}
+	}
+
+	// Dead-lettered tasks stay parked until they are requeued, cancelled
+	// tasks stay stopped, and skipped duplicates and tasks served from the
+	// result cache have no Job to track
+	if task.Status.Phase == taskPhaseDeadLettered || task.Status.Phase == taskPhaseCancelled ||
+		task.Status.Phase == taskPhaseSkipped || task.Status.CacheHit {
+		return ctrl.Result{}, nil
+	}
+
+	// Tasks selecting clusters run as a child task on each of them
+	if task.Spec.ClusterSelector != nil {
+		return r.reconcileFanOut(ctx, task)
+	}
+
+	// Skip duplicate submissions instead of launching a second Job
+	if task.Spec.IdempotencyKey != "" && task.Status.StartTime == nil &&
+		(task.Status.Phase == "" || task.Status.Phase == "Pending") {
+		original, err := r.findOriginalTask(ctx, task)
+		if err != nil {
+			log.Error(err, "Failed to look up tasks by idempotency key")
+			return ctrl.Result{}, err
+		}
+		if original != nil {
+			if err := r.skipDuplicateTask(ctx, task, original); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+
 	// Determine target namespace
 	targetNamespace := r.determineNamespace(task)
 
@@ -107,6 +272,16 @@ func (r *SwarmTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Stop tasks whose reported usage reached a limit of their budget
+	cancelled, err := r.enforceBudget(ctx, task, targetNamespace)
+	if err != nil {
+		log.Error(err, "Failed to enforce task budget")
+		return ctrl.Result{}, err
+	}
+	if cancelled {
+		return ctrl.Result{}, nil
+	}
+
 	// Get the SwarmCluster
 	cluster := &swarmv1alpha1.SwarmCluster{}
 	err = r.Get(ctx, types.NamespacedName{
@@ -117,33 +292,291 @@ func (r *SwarmTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		log.Error(err, "Failed to get SwarmCluster", "cluster", task.Spec.SwarmCluster)
 		return ctrl.Result{}, err
 	}
+	if err := resolveSwarmProfile(ctx, r, cluster); err != nil {
+		log.Error(err, "Failed to resolve SwarmProfile", "profile", cluster.Spec.Profile)
+		return ctrl.Result{}, err
+	}
+
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.SetClusterTenant(cluster.Namespace, cluster.Name, cluster.Labels[tenantLabel])
+	}
+
+	// Hold the task to the namespaces and quota of its tenant
+	held, err := r.admitTenantTask(ctx, task, cluster, targetNamespace)
+	if err != nil {
+		log.Error(err, "Failed to check tenant admission")
+		return ctrl.Result{}, err
+	}
+	if held {
+		if task.Status.Phase == "Failed" {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: tenantRequeueInterval}, nil
+	}
+
+	// Wait out the retry backoff before starting the next attempt
+	if task.Status.NextRetryTime != nil {
+		if wait := time.Until(task.Status.NextRetryTime.Time); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	// Reject overrides the admission webhook would have refused, in case it
+	// is not installed
+	if errs := swarmv1alpha1.ValidatePodTemplateOverrides(task.Spec.PodTemplateOverrides,
+		field.NewPath("spec", "podTemplateOverrides")); len(errs) > 0 {
+		if task.Status.Phase != "Failed" {
+			task.Status.Phase = "Failed"
+			task.Status.Message = errs.ToAggregate().Error()
+			if err := r.Status().Update(ctx, task); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(task, corev1.EventTypeWarning, "InvalidPodTemplateOverrides", task.Status.Message)
+		}
+		return ctrl.Result{}, nil
+	}
+	if errs := swarmv1alpha1.ValidateTaskNetworkPolicy(task.Spec.NetworkPolicy,
+		field.NewPath("spec", "networkPolicy")); len(errs) > 0 {
+		if task.Status.Phase != "Failed" {
+			task.Status.Phase = "Failed"
+			task.Status.Message = errs.ToAggregate().Error()
+			if err := r.Status().Update(ctx, task); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(task, corev1.EventTypeWarning, "InvalidNetworkPolicy", task.Status.Message)
+		}
+		return ctrl.Result{}, nil
+	}
+	if errs := swarmv1alpha1.ValidateTaskService(&task.Spec, field.NewPath("spec")); len(errs) > 0 {
+		if task.Status.Phase != "Failed" {
+			task.Status.Phase = "Failed"
+			task.Status.Message = errs.ToAggregate().Error()
+			if err := r.Status().Update(ctx, task); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(task, corev1.EventTypeWarning, "InvalidServiceTask", task.Status.Message)
+		}
+		return ctrl.Result{}, nil
+	}
+	if errs := swarmv1alpha1.ValidateTaskGPU(task.Spec.GPU, field.NewPath("spec", "gpu")); len(errs) > 0 {
+		if task.Status.Phase != "Failed" {
+			task.Status.Phase = "Failed"
+			task.Status.Message = errs.ToAggregate().Error()
+			if err := r.Status().Update(ctx, task); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Event(task, corev1.EventTypeWarning, "InvalidGPURequest", task.Status.Message)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Short-circuit tasks whose result is already cached
+	if resultCacheEnabled(task) && task.Status.CacheKey == "" && task.Status.StartTime == nil {
+		hit, err := r.checkResultCache(ctx, task, cluster, targetNamespace)
+		if err != nil {
+			log.Error(err, "Failed to check result cache")
+			return ctrl.Result{}, err
+		}
+		if hit {
+			r.notifyLifecycle(ctx, task, cluster, swarmv1alpha1.TaskCompletedEvent)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Planned tasks only render the Job they would run
+	if planRequested(task) {
+		return r.reconcilePlan(ctx, task, cluster, targetNamespace)
+	}
+
+	// No new Jobs are dispatched while a maintenance window freezes the
+	// cluster
+	if window, end := activeMaintenanceWindow(cluster, time.Now()); window != nil {
+		held, err := r.holdForMaintenance(ctx, task, targetNamespace, window, end)
+		if err != nil {
+			log.Error(err, "Failed to hold task for maintenance window")
+			return ctrl.Result{}, err
+		}
+		if held {
+			return ctrl.Result{RequeueAfter: time.Until(end)}, nil
+		}
+	}
+
+	// Fair queuing hands the cluster's free dispatch slots out across teams
+	held, err = r.awaitFairShare(ctx, task, cluster)
+	if err != nil {
+		log.Error(err, "Failed to queue task fairly")
+		return ctrl.Result{}, err
+	}
+	if held {
+		return ctrl.Result{RequeueAfter: fairQueueInterval}, nil
+	}
 
 	// Generate GitHub token if needed
 	var githubTokenSecret string
-	if cluster.Spec.GitHubApp != nil && len(task.Spec.Repositories) > 0 {
-		tokenSecret, err := r.ensureGitHubToken(ctx, task, cluster.Spec.GitHubApp, targetNamespace)
+	if task.Spec.GitHubApp != nil && len(task.Spec.Repositories) > 0 {
+		tokenSecret, err := r.ensureGitHubToken(ctx, task, task.Spec.GitHubApp, targetNamespace)
 		if err != nil {
+			// Back off instead of retrying hot while GitHub keeps failing
+			if wait, open := dependencyUnavailable(&task.Status.Conditions, err); open {
+				log.Info("GitHub circuit breaker open, deferring task", "retryAfter", wait)
+				if err := r.Status().Update(ctx, task); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
 			log.Error(err, "Failed to ensure GitHub token")
 			return ctrl.Result{}, err
 		}
 		githubTokenSecret = tokenSecret
+
+		if dependenciesRecovered(&task.Status.Conditions) {
+			if err := r.Status().Update(ctx, task); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// Provision and resize task volumes
+	if len(task.Spec.PersistentVolumes) > 0 {
+		if err := r.ensureTaskVolumes(ctx, task, targetNamespace); err != nil {
+			log.Error(err, "Failed to ensure task volumes")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Push the task to an agent instead of running it in a Job
+	if pushAssignmentEnabled(cluster) && !serviceTask(task) {
+		return r.reconcileAssignment(ctx, task, cluster, githubTokenSecret)
+	}
+
+	// Hive-mind executors join the cluster's mTLS mesh, with the
+	// certificate mirrored into the namespace they run in
+	if tlsEnabled(cluster) && isHiveMindTask(task) {
+		issued, err := copyTLSSecret(ctx, r.Client, tlsSecretName(cluster, hiveMindTLSComponent), cluster.Namespace, targetNamespace)
+		if err != nil {
+			log.Error(err, "Failed to copy hive-mind certificate")
+			return ctrl.Result{}, err
+		}
+		if !issued {
+			log.Info("Waiting for the hive-mind certificate to be issued")
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+	}
+
+	// Fail tasks asking for GPUs no node offers instead of leaving their
+	// pods unschedulable
+	if task.Spec.GPU != nil && task.Status.StartTime == nil {
+		reason, err := r.checkGPUCapacity(ctx, task)
+		if err != nil {
+			log.Error(err, "Failed to check GPU capacity")
+			return ctrl.Result{}, err
+		}
+		if reason != "" {
+			if task.Status.Phase != "Failed" {
+				task.Status.Phase = "Failed"
+				task.Status.Message = reason
+				if err := r.Status().Update(ctx, task); err != nil {
+					return ctrl.Result{}, err
+				}
+				r.Recorder.Event(task, corev1.EventTypeWarning, "GPUUnavailable", reason)
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Fail sandboxed tasks whose runtime is not installed instead of
+	// leaving their pods stuck in ContainerCreating
+	if taskSandboxed(task) && task.Status.StartTime == nil {
+		reason, err := r.checkRuntimeClass(ctx, task)
+		if err != nil {
+			log.Error(err, "Failed to check RuntimeClass")
+			return ctrl.Result{}, err
+		}
+		if reason != "" {
+			if task.Status.Phase != "Failed" {
+				task.Status.Phase = "Failed"
+				task.Status.Message = reason
+				if err := r.Status().Update(ctx, task); err != nil {
+					return ctrl.Result{}, err
+				}
+				r.Recorder.Event(task, corev1.EventTypeWarning, "RuntimeClassUnavailable", reason)
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Restrict egress before the Job's pods start
+	if task.Spec.NetworkPolicy != nil {
+		if err := r.reconcileTaskNetworkPolicy(ctx, task, targetNamespace); err != nil {
+			log.Error(err, "Failed to reconcile task network policy")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Service tasks run in a Deployment behind a Service instead of a Job
+	if serviceTask(task) {
+		return r.reconcileService(ctx, task, cluster, targetNamespace, githubTokenSecret)
+	}
+
+	// Fail attempts whose Job was lost or overran the timeout, and never
+	// recreate the Job of a finished task
+	stale, err := r.sweepStaleTask(ctx, task, cluster, targetNamespace)
+	if err != nil {
+		log.Error(err, "Failed to check task for a stale Job")
+		return ctrl.Result{}, err
+	}
+	if stale {
+		if taskFinished(task) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{Requeue: true}, nil
 	}
 
 	// Create or update the Job
-	job, err := r.createOrUpdateJob(ctx, task, targetNamespace, githubTokenSecret)
+	job, err := r.createOrUpdateJob(ctx, task, cluster, targetNamespace, githubTokenSecret)
+	if violation, ok := err.(*tenantViolationError); ok {
+		if err := r.failTenantTask(ctx, task, violation.Error()); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if hold, ok := err.(*dispatchHoldError); ok {
+		log.Info("Holding task", "reason", hold.message)
+		return ctrl.Result{RequeueAfter: hold.retryAfter}, nil
+	}
 	if err != nil {
 		log.Error(err, "Failed to create/update job")
 		return ctrl.Result{}, err
 	}
 
+	// Reschedule tasks whose pods were lost to spot/preemptible node reclaims
+	if preemptionEnabled(task) && job.Status.Succeeded == 0 {
+		pod, err := r.findPreemptedPod(ctx, job)
+		if err != nil {
+			log.Error(err, "Failed to check for preempted pods")
+			return ctrl.Result{}, err
+		}
+		if _, requested := checkpointRequestTime(job); pod != nil || requested {
+			wait, err := r.handlePreemption(ctx, task, job, pod)
+			if err != nil {
+				log.Error(err, "Failed to reschedule preempted task")
+				return ctrl.Result{}, err
+			}
+			if wait > 0 {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
 	// Update task status based on job status
-	if err := r.updateTaskStatus(ctx, task, job); err != nil {
+	if err := r.updateTaskStatus(ctx, task, job, cluster); err != nil {
 		log.Error(err, "Failed to update task status")
 		return ctrl.Result{}, err
 	}
 
 	// Requeue to check job status
-	if task.Status.Phase != "Completed" && task.Status.Phase != "Failed" {
+	if task.Status.Phase != "Completed" && task.Status.Phase != "Failed" && task.Status.Phase != taskPhaseDeadLettered {
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
@@ -158,12 +591,18 @@ func (r *SwarmTaskReconciler) determineNamespace(task *swarmv1alpha1.SwarmTask)
 	}
 
 	// Determine based on task type
-	if task.Spec.Type == "hivemind" || task.Spec.Type == "consensus" {
-		return r.HiveMindNamespace
+	swarmNamespace, hiveMindNamespace := operatorNamespaces(r.Config, r.SwarmNamespace, r.HiveMindNamespace)
+	if isHiveMindTask(task) {
+		return hiveMindNamespace
 	}
 
 	// Default to swarm namespace
-	return r.SwarmNamespace
+	return swarmNamespace
+}
+
+// isHiveMindTask reports whether the task runs as part of the hive-mind
+func isHiveMindTask(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Spec.Type == "hivemind" || task.Spec.Type == "consensus"
 }
 
 // ensureNamespace ensures the target namespace exists
@@ -194,12 +633,13 @@ func (r *SwarmTaskReconciler) ensureGitHubToken(ctx context.Context, task *swarm
 		r.TokenGenerator = github.NewTokenGenerator(r.Client)
 	}
 
-	secretName := fmt.Sprintf("%s-github-token", task.Name)
+	secretName := githubTokenSecretName(task)
 
 	// Check if token already exists and is valid
 	expired, err := r.TokenGenerator.IsTokenExpired(ctx, secretName, namespace)
+	missing := errors.IsNotFound(err)
 	if err != nil {
-		if !errors.IsNotFound(err) {
+		if !missing {
 			return "", err
 		}
 		// Token doesn't exist, create it
@@ -208,7 +648,12 @@ func (r *SwarmTaskReconciler) ensureGitHubToken(ctx context.Context, task *swarm
 
 	if expired {
 		// Generate new token
-		token, err := r.TokenGenerator.GenerateToken(ctx, appConfig, task.Spec.Repositories, namespace)
+		var token string
+		err := guardDependency(r.Breakers, dependencyGitHub, func() error {
+			var err error
+			token, err = r.TokenGenerator.GenerateToken(ctx, appConfig, task.Spec.Repositories, namespace)
+			return err
+		})
 		if err != nil {
 			return "", err
 		}
@@ -220,11 +665,12 @@ func (r *SwarmTaskReconciler) ensureGitHubToken(ctx context.Context, task *swarm
 		}
 		expiresAt := time.Now().Add(ttl)
 
-		// Create or update secret
-		if errors.IsNotFound(err) {
-			err = r.TokenGenerator.CreateTokenSecret(ctx, secretName, namespace, token, task.Spec.Repositories, expiresAt)
+		// Create or update secret, labeled for the garbage collector
+		labels := taskResourceLabels(task)
+		if missing {
+			err = r.TokenGenerator.CreateTokenSecret(ctx, secretName, namespace, token, task.Spec.Repositories, expiresAt, labels)
 		} else {
-			err = r.TokenGenerator.UpdateTokenSecret(ctx, secretName, namespace, token, task.Spec.Repositories, expiresAt)
+			err = r.TokenGenerator.UpdateTokenSecret(ctx, secretName, namespace, token, task.Spec.Repositories, expiresAt, labels)
 		}
 		if err != nil {
 			return "", err
@@ -237,9 +683,197 @@ func (r *SwarmTaskReconciler) ensureGitHubToken(ctx context.Context, task *swarm
 	return secretName, nil
 }
 
+// githubTokenSecretName returns the name of the Secret holding the task's
+// GitHub App token
+func githubTokenSecretName(task *swarmv1alpha1.SwarmTask) string {
+	return fmt.Sprintf("%s-github-token", task.Name)
+}
+
+// dispatchHoldError keeps a task from starting until a check before its
+// Job is created passes, such as the scan of its executor image
+type dispatchHoldError struct {
+	message    string
+	retryAfter time.Duration
+}
+
+func (e *dispatchHoldError) Error() string {
+	return e.message
+}
+
+// holdDispatch keeps the task Pending with the reason in its message and
+// returns the dispatchHoldError retrying it
+func (r *SwarmTaskReconciler) holdDispatch(ctx context.Context, task *swarmv1alpha1.SwarmTask, message string, retryAfter time.Duration) error {
+	if task.Status.Phase == "" {
+		task.Status.Phase = "Pending"
+	}
+	task.Status.Message = message
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	return &dispatchHoldError{message: message, retryAfter: retryAfter}
+}
+
 // createOrUpdateJob creates or updates the Kubernetes Job for the task
-func (r *SwarmTaskReconciler) createOrUpdateJob(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string, githubTokenSecret string) (*batchv1.Job, error) {
-	jobName := fmt.Sprintf("%s-job", task.Name)
+func (r *SwarmTaskReconciler) createOrUpdateJob(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string, githubTokenSecret string) (*batchv1.Job, error) {
+	job, tenant, err := r.buildJob(ctx, task, cluster, namespace, githubTokenSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// Owner references cannot cross namespaces. Jobs running elsewhere are
+	// found by their task labels and deleted by the finalizer.
+	if namespace == task.Namespace {
+		if err := controllerutil.SetControllerReference(task, job, r.Scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check if job exists
+	existingJob := &batchv1.Job{}
+	err = r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: namespace}, existingJob)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Tenants only run the images and secrets they are allowed,
+			// which is checked for the postComplete hooks up front
+			operatorImages, operatorSecrets := r.tenantOperatorImages(), tenantOperatorSecrets(tenant, cluster, githubTokenSecret)
+			if len(task.Spec.ConfigTemplates) > 0 {
+				operatorSecrets[taskConfigSecretName(job)] = true
+			}
+			if task.Spec.TargetCluster != nil {
+				operatorSecrets[taskKubeconfigSecretName(job)] = true
+			}
+			if r.executorConfig().CredentialSecrets {
+				defaults, err := r.taskDefaults(ctx, task, cluster, namespace)
+				if err != nil {
+					return nil, err
+				}
+				for _, name := range defaults.credentialSecrets(tenant) {
+					operatorSecrets[name] = true
+				}
+				operatorSecrets[taskCredentialsSecretName(job.Name)] = true
+			}
+			violations := tenantPodSpecViolations(tenant, &job.Spec.Template.Spec, operatorImages, operatorSecrets)
+			if len(postCompleteHooks(task)) > 0 {
+				violations = append(violations, tenantPodSpecViolations(tenant,
+					postCompletePodSpec(task, &job.Spec.Template.Spec), operatorImages, operatorSecrets)...)
+			}
+			if config := task.Spec.ImageConfig; config != nil && len(config.PullSecrets) > 0 {
+				violations = append(violations, tenantPodSpecViolations(tenant,
+					&corev1.PodSpec{ImagePullSecrets: config.PullSecrets}, operatorImages, operatorSecrets)...)
+			}
+			if target := task.Spec.TargetCluster; target != nil {
+				violations = append(violations, tenantPodSpecViolations(tenant, &corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         targetKubeconfigVolume,
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: target.KubeconfigSecret}},
+				}}}, operatorImages, operatorSecrets)...)
+			}
+			if len(violations) > 0 {
+				return nil, &tenantViolationError{violations: violations}
+			}
+
+			// Registries are allowed by the images tasks ask for rather than
+			// the mirrors they are pulled from
+			utils.ApplyImageConfig(&job.Spec.Template.Spec, taskImageConfig(task, cluster))
+
+			// Scan the image as it will be pulled before anything runs it
+			if err := r.scanExecutorImage(ctx, task, &job.Spec.Template.Spec); err != nil {
+				return nil, err
+			}
+
+			// Reach the target cluster before the task works on it
+			kubeconfig, err := r.verifyTargetCluster(ctx, task, namespace)
+			if err != nil {
+				return nil, err
+			}
+
+			// A pod whose claim is missing would never start
+			if err := r.checkSharedWorkspace(ctx, task, cluster, namespace); err != nil {
+				return nil, err
+			}
+
+			// The class must exist before pods reference it
+			if err := r.ensurePriorityClass(ctx, job.Spec.Template.Spec.PriorityClassName); err != nil {
+				return nil, err
+			}
+
+			// Start in an idle pod of the warm pool when one matches,
+			// otherwise hint the scheduler towards a tightly packed node.
+			// Jobs admitted by Kueue never skip its queue.
+			claimed := false
+			if warmPoolEnabled(cluster) && task.Spec.QueueName == "" {
+				if claimed, err = r.claimWarmPod(ctx, task, cluster, job); err != nil {
+					return nil, err
+				}
+			}
+			// Later attempts go back to the node their volumes' data
+			// lives on, ahead of bin packing
+			sticky := false
+			if !claimed && len(task.Status.Volumes) > 0 {
+				if sticky, err = r.applyVolumeAffinity(ctx, task, &job.Spec.Template.Spec); err != nil {
+					return nil, err
+				}
+			}
+			if !claimed && !sticky && binPackingEnabled(cluster) {
+				if err := r.applyPlacementHint(ctx, cluster, &job.Spec.Template.Spec); err != nil {
+					return nil, err
+				}
+			}
+			if !claimed && task.Spec.ColocateWithMemory {
+				if err := r.applyMemoryColocation(ctx, cluster, &job.Spec.Template.Spec); err != nil {
+					return nil, err
+				}
+			}
+
+			// Render the config templates up front, so missing secrets
+			// and tenant violations surface before the Job exists
+			var configData map[string][]byte
+			if len(task.Spec.ConfigTemplates) > 0 {
+				if configData, err = r.renderConfigTemplates(ctx, task, tenant, namespace); err != nil {
+					if _, ok := err.(*tenantViolationError); !ok {
+						r.Recorder.Event(task, corev1.EventTypeWarning, "ConfigTemplateFailed", err.Error())
+					}
+					return nil, err
+				}
+			}
+
+			// Create new job
+			if err := r.Create(ctx, job); err != nil {
+				return nil, err
+			}
+			if err := r.ensureConfigSecret(ctx, task, tenant, job, configData); err != nil {
+				return nil, err
+			}
+			if err := r.ensureKubeconfigSecret(ctx, task, job, kubeconfig); err != nil {
+				return nil, err
+			}
+			if err := r.ensureCredentialsSecret(ctx, task, cluster, tenant, job, githubTokenSecret); err != nil {
+				return nil, err
+			}
+			return job, nil
+		}
+		return nil, err
+	}
+
+	// Recreate the config, kubeconfig and credentials secrets should
+	// creating them have failed after the Job
+	if !taskFinished(task) {
+		if err := r.ensureConfigSecret(ctx, task, tenant, existingJob, nil); err != nil {
+			return nil, err
+		}
+		if err := r.ensureKubeconfigSecret(ctx, task, existingJob, nil); err != nil {
+			return nil, err
+		}
+		if err := r.ensureCredentialsSecret(ctx, task, cluster, tenant, existingJob, githubTokenSecret); err != nil {
+			return nil, err
+		}
+	}
+	return existingJob, nil
+}
+
+// buildJob builds the Job of the task's current attempt along with the
+// tenant it runs for
+func (r *SwarmTaskReconciler) buildJob(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string, githubTokenSecret string) (*batchv1.Job, *swarmv1alpha1.SwarmTenant, error) {
+	jobName := taskJobName(task)
 
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -248,6 +882,10 @@ func (r *SwarmTaskReconciler) createOrUpdateJob(ctx context.Context, task *swarm
 			Labels: map[string]string{
 				"swarm.claudeflow.io/task":    task.Name,
 				"swarm.claudeflow.io/cluster": task.Spec.SwarmCluster,
+				taskNamespaceLabel:            task.Namespace,
+			},
+			Annotations: map[string]string{
+				correlationIDAnnotation: taskCorrelationID(task),
 			},
 		},
 		Spec: batchv1.JobSpec{
@@ -256,17 +894,23 @@ func (r *SwarmTaskReconciler) createOrUpdateJob(ctx context.Context, task *swarm
 					Labels: map[string]string{
 						"swarm.claudeflow.io/task":    task.Name,
 						"swarm.claudeflow.io/cluster": task.Spec.SwarmCluster,
+						taskNamespaceLabel:            task.Namespace,
+					},
+					Annotations: map[string]string{
+						correlationIDAnnotation: taskCorrelationID(task),
 					},
 				},
 				Spec: corev1.PodSpec{
 					RestartPolicy: corev1.RestartPolicyOnFailure,
 					Containers: []corev1.Container{
 						{
-							Name:  "task",
-							Image: "busybox:latest", // This should be configurable
+							Name:    "task",
+							Image:   placeholderExecutorImage,
 							Command: []string{"/bin/sh", "-c"},
 							Args:    []string{fmt.Sprintf("echo 'Executing task: %s'", task.Spec.Description)},
 							Env:     r.buildEnvironment(task, githubTokenSecret),
+							// Surface the log tail in the termination message for failure digests
+							TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 						},
 					},
 				},
@@ -274,49 +918,89 @@ func (r *SwarmTaskReconciler) createOrUpdateJob(ctx context.Context, task *swarm
 		},
 	}
 
-	// Set owner reference
-	if err := controllerutil.SetControllerReference(task, job, r.Scheme); err != nil {
-		return nil, err
+	if r.Config != nil {
+		if limit := r.Config.Get().BackoffLimit; limit != nil {
+			backoffLimit := *limit
+			job.Spec.BackoffLimit = &backoffLimit
+		}
 	}
 
-	// Check if job exists
-	existingJob := &batchv1.Job{}
-	err := r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: namespace}, existingJob)
+	tenant, err := getTenant(ctx, r, taskTenant(task, cluster))
 	if err != nil {
-		if errors.IsNotFound(err) {
-			// Create new job
-			if err := r.Create(ctx, job); err != nil {
-				return nil, err
-			}
-			return job, nil
-		}
-		return nil, err
+		return nil, nil, err
 	}
+	if tenant != nil {
+		job.Labels[tenantLabel] = tenant.Name
+		job.Spec.Template.Labels[tenantLabel] = tenant.Name
+	}
+	applyTaskQueue(task, job)
 
-	return existingJob, nil
+	defaults, err := r.taskDefaults(ctx, task, cluster, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.applyExecutor(ctx, task, cluster, namespace, defaults, &job.Spec.Template.Spec, githubTokenSecret); err != nil {
+		return nil, nil, err
+	}
+	if err := r.applyComputedEnv(ctx, task, cluster, &job.Spec.Template.Spec); err != nil {
+		return nil, nil, err
+	}
+	applyTaskVolumes(task, &job.Spec.Template.Spec)
+	applySharedWorkspace(cluster, &job.Spec.Template.Spec)
+	applyConfigTemplates(task, job)
+	applyTargetCluster(task, job)
+	applyTaskGPU(task, &job.Spec.Template.Spec)
+	applyGitCheckout(task, &job.Spec.Template.Spec, githubTokenSecret)
+	applyTaskHooks(task, &job.Spec.Template.Spec)
+	applyPreemptionPolicy(task, &job.Spec.Template.Spec)
+	r.applyPriorityClass(task, &job.Spec.Template.Spec)
+	if tlsEnabled(cluster) && isHiveMindTask(task) {
+		applyPodTLS(&job.Spec.Template.Spec, tlsSecretName(cluster, hiveMindTLSComponent), hiveMindServerName(cluster), "task")
+	}
+	applyTaskOS(task, &job.Spec.Template.Spec)
+	applyTaskDefaults(defaults, &job.Spec.Template.Spec)
+	r.applyServiceMesh(cluster, &job.Spec.Template)
+	applyTaskTelemetry(task, cluster, namespace, &job.Spec.Template)
+
+	// User overrides are applied last so they can adjust anything above,
+	// except for the sandbox, which is reapplied over them
+	if err := utils.ApplyPodTemplateOverrides(&job.Spec.Template, task.Spec.PodTemplateOverrides); err != nil {
+		return nil, nil, err
+	}
+	applyTaskIsolation(task, &job.Spec.Template.Spec)
+
+	return job, tenant, nil
 }
 
 // buildEnvironment builds environment variables for the task
 func (r *SwarmTaskReconciler) buildEnvironment(task *swarmv1alpha1.SwarmTask, githubTokenSecret string) []corev1.EnvVar {
 	env := []corev1.EnvVar{
 		{
-			Name:  "SWARM_TASK_NAME",
+			Name:  executor.EnvTaskName,
 			Value: task.Name,
 		},
 		{
-			Name:  "SWARM_CLUSTER",
+			Name:  executor.EnvCluster,
 			Value: task.Spec.SwarmCluster,
 		},
 		{
-			Name:  "SWARM_TASK_TYPE",
+			Name:  executor.EnvTaskType,
 			Value: task.Spec.Type,
 		},
+		{
+			Name:  executor.EnvAgentType,
+			Value: string(taskAgentType(task)),
+		},
+		{
+			Name:  executor.EnvCorrelationID,
+			Value: taskCorrelationID(task),
+		},
 	}
 
 	// Add GitHub token if present
 	if githubTokenSecret != "" {
 		env = append(env, corev1.EnvVar{
-			Name: "GITHUB_TOKEN",
+			Name: executor.EnvGitHubToken,
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: &corev1.SecretKeySelector{
 					LocalObjectReference: corev1.LocalObjectReference{
@@ -330,46 +1014,90 @@ func (r *SwarmTaskReconciler) buildEnvironment(task *swarmv1alpha1.SwarmTask, gi
 		// Add repository list
 		if len(task.Spec.Repositories) > 0 {
 			env = append(env, corev1.EnvVar{
-				Name:  "GITHUB_REPOSITORIES",
+				Name:  executor.EnvGitHubRepositories,
 				Value: strings.Join(task.Spec.Repositories, ","),
 			})
 		}
 	}
 
+	// Ask the executor to continue from its latest checkpoint
+	if task.Spec.Resume || task.Status.ResumeFromCheckpoint {
+		env = append(env, corev1.EnvVar{Name: executor.EnvResume, Value: "true"})
+		if task.Status.CheckpointRef != "" {
+			env = append(env, corev1.EnvVar{Name: executor.EnvCheckpointRef, Value: task.Status.CheckpointRef})
+		}
+	}
+
+	// Let the executor wind down before it runs out of budget
+	env = append(env, budgetEnvironment(task.Spec.Budget)...)
+
 	// Add custom parameters
 	for k, v := range task.Spec.Parameters {
 		env = append(env, corev1.EnvVar{
-			Name:  fmt.Sprintf("PARAM_%s", strings.ToUpper(k)),
+			Name:  executor.ParamPrefix + strings.ToUpper(k),
 			Value: v,
 		})
 	}
 
+	// Add the task's own variables, secret keys included
+	env = append(env, taskEnv(task)...)
+
 	return env
 }
 
+// taskJobName returns the Job name for the task's current attempt. The first
+// attempt keeps the historical "<task>-job" name.
+func taskJobName(task *swarmv1alpha1.SwarmTask) string {
+	if task.Status.Attempt == 0 {
+		return fmt.Sprintf("%s-job", task.Name)
+	}
+	return fmt.Sprintf("%s-job-%d", task.Name, task.Status.Attempt)
+}
+
 // updateTaskStatus updates the SwarmTask status based on the Job status
-func (r *SwarmTaskReconciler) updateTaskStatus(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job) error {
+func (r *SwarmTaskReconciler) updateTaskStatus(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster) error {
 	updated := false
+	completed := false
 
 	// Update phase based on job status
 	if job.Status.Succeeded > 0 {
+		if task.Status.Phase != "Completed" && len(postCompleteHooks(task)) > 0 {
+			// The task completes once its postComplete hooks succeeded
+			done, err := r.reconcilePostCompleteHooks(ctx, task, job, cluster)
+			if err != nil || !done {
+				return err
+			}
+		}
 		if task.Status.Phase != "Completed" {
 			task.Status.Phase = "Completed"
 			task.Status.CompletionTime = &metav1.Time{Time: time.Now()}
 			updated = true
+			completed = true
+			r.recordTaskDuration(task)
+			r.recordTaskSLOs(task, cluster, swarmv1alpha1.CompletionStage, task.Status.CompletionTime.Time, false)
+			r.recordExecutorOutcome(task, job, false)
+			r.collectExecutorReport(ctx, task, job)
+			finishFallback(task, swarmv1alpha1.FallbackSucceeded)
+			finishPreStartHooks(task)
+
+			if resultCacheEnabled(task) {
+				if err := r.storeResult(ctx, task, job, cluster); err != nil {
+					log.FromContext(ctx).Error(err, "Failed to store task result in cache")
+				}
+			}
 		}
 	} else if job.Status.Failed > 0 {
 		if task.Status.Phase != "Failed" {
-			task.Status.Phase = "Failed"
-			task.Status.CompletionTime = &metav1.Time{Time: time.Now()}
-			task.Status.Message = "Job failed"
-			updated = true
+			r.recordExecutorOutcome(task, job, true)
+			r.collectExecutorReport(ctx, task, job)
+			return r.handleJobFailure(ctx, task, job, cluster)
 		}
 	} else if job.Status.Active > 0 {
 		if task.Status.Phase != "Running" {
 			task.Status.Phase = "Running"
 			if task.Status.StartTime == nil {
 				task.Status.StartTime = &metav1.Time{Time: time.Now()}
+				r.recordTaskSLOs(task, cluster, swarmv1alpha1.DispatchStage, task.Status.StartTime.Time, false)
 			}
 			updated = true
 		}
@@ -380,13 +1108,338 @@ func (r *SwarmTaskReconciler) updateTaskStatus(ctx context.Context, task *swarmv
 		}
 	}
 
+	if task.Status.JobName != job.Name {
+		task.Status.JobName = job.Name
+		updated = true
+	}
+
+	// Record the sandbox the executor runs in once its pods have started
+	if task.Status.Isolation == nil && (job.Status.Active > 0 || job.Status.Succeeded > 0) {
+		task.Status.Isolation = taskIsolationStatus(task)
+		updated = true
+	}
+
+	// Mirror the admission of Jobs queued in Kueue
+	if task.Spec.QueueName != "" && job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		changed, err := r.recordQueueStatus(ctx, task, job)
+		if err != nil {
+			return err
+		}
+		updated = updated || changed
+	}
+
+	// Remember the node the volumes were mounted on for later attempts
+	if len(task.Status.Volumes) > 0 && job.Status.Active > 0 {
+		changed, err := r.recordVolumeNode(ctx, task, job)
+		if err != nil {
+			return err
+		}
+		updated = updated || changed
+	}
+
+	// Report how close the scheduler got the pod to the memory store
+	if task.Spec.ColocateWithMemory && job.Status.Active > 0 {
+		changed, err := r.recordMemoryPlacement(ctx, task, job, cluster)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to measure the distance to the memory store")
+		}
+		updated = updated || changed
+	}
+
 	if updated {
+		if err := r.Status().Update(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	if completed {
+		r.recordFallbackOutcome(ctx, task, fallbackStatus(task), true)
+		r.notifyLifecycle(ctx, task, cluster, swarmv1alpha1.TaskCompletedEvent)
+	}
+
+	return nil
+}
+
+// recordTaskDuration feeds the run time of a finished task into the
+// latency metrics used by queue-based autoscaling
+func (r *SwarmTaskReconciler) recordTaskDuration(task *swarmv1alpha1.SwarmTask) {
+	if r.MetricsRecorder == nil || task.Status.StartTime == nil || task.Status.CompletionTime == nil {
+		return
+	}
+	duration := task.Status.CompletionTime.Sub(task.Status.StartTime.Time).Seconds()
+	r.MetricsRecorder.RecordTaskDuration(task.Namespace, task.Spec.SwarmCluster, string(taskAgentType(task)), task.Spec.Type, duration)
+}
+
+// recordTaskSLOs measures the time from the creation of the task to the
+// stage it reached against the cluster SLOs of that stage. Tasks that fail
+// for good miss every completion SLO.
+func (r *SwarmTaskReconciler) recordTaskSLOs(task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, stage swarmv1alpha1.SLOStage, at time.Time, failed bool) {
+	if r.MetricsRecorder == nil || cluster == nil {
+		return
+	}
+	slos, _ := clusterSLOs(cluster)
+	latency := at.Sub(task.CreationTimestamp.Time)
+	for _, slo := range slos {
+		if sloStage(slo.TaskSLO) != stage || !sloAppliesTo(slo.TaskSLO, task) {
+			continue
+		}
+		r.MetricsRecorder.RecordSLOEvent(cluster.Namespace, cluster.Name, slo.Name, !failed && latency <= slo.Threshold.Duration)
+	}
+}
+
+// handleJobFailure either schedules another attempt according to the task's
+// retry policy, hands the task to the next stage of its fallback strategy
+// or, once both are exhausted, dead-letters the task
+func (r *SwarmTaskReconciler) handleJobFailure(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster) error {
+	now := metav1.Now()
+	policy := task.Spec.RetryPolicy
+	details := r.diagnoseJobFailure(ctx, task, job)
+	task.Status.FailureDetails = details
+	recordPreStartOutcome(task, details)
+
+	if maxRetries := stageMaxRetries(task); task.Status.RetryCount < maxRetries {
+		backoff := policy
+		if backoff == nil {
+			backoff = &swarmv1alpha1.RetryPolicy{}
+		}
+		delay := retryBackoff(backoff, task.Status.RetryCount)
+		task.Status.RetryCount++
+		task.Status.Attempt++
+		task.Status.Phase = "Pending"
+		task.Status.NextRetryTime = &metav1.Time{Time: now.Add(delay)}
+		task.Status.Message = fmt.Sprintf("Job %s failed (%s), retry %d/%d in %s",
+			job.Name, failureSummary(details), task.Status.RetryCount, maxRetries, delay)
+		r.Recorder.Event(task, corev1.EventTypeWarning, "RetryScheduled", task.Status.Message)
 		return r.Status().Update(ctx, task)
 	}
 
+	// The agent type used up its retries, escalate to the next one
+	failed := fallbackStatus(task)
+	if advanceFallback(task) {
+		task.Status.RetryCount = 0
+		task.Status.Attempt++
+		task.Status.Phase = "Pending"
+		task.Status.NextRetryTime = nil
+		task.Status.Message = fmt.Sprintf("Job %s failed (%s) on %s agents, falling back to %s (stage %d/%d)",
+			job.Name, failureSummary(details), failed.AgentType, task.Status.Fallback.AgentType,
+			task.Status.Fallback.Stage, len(task.Spec.FallbackStrategy.Stages))
+		if err := r.Status().Update(ctx, task); err != nil {
+			return err
+		}
+		r.Recorder.Event(task, corev1.EventTypeWarning, "FallbackEscalated", task.Status.Message)
+		r.recordFallbackOutcome(ctx, task, failed, false)
+		return nil
+	}
+	finishFallback(task, swarmv1alpha1.FallbackExhausted)
+
+	// Without a retry policy a failed task simply stays Failed
+	if policy == nil {
+		task.Status.Phase = "Failed"
+		task.Status.CompletionTime = &now
+		task.Status.Message = fmt.Sprintf("Job failed: %s", failureSummary(details))
+		if err := r.Status().Update(ctx, task); err != nil {
+			return err
+		}
+		r.recordTaskSLOs(task, cluster, swarmv1alpha1.CompletionStage, now.Time, true)
+		r.recordFallbackOutcome(ctx, task, failed, false)
+		r.notifyLifecycle(ctx, task, cluster, swarmv1alpha1.TaskFailedEvent)
+		return nil
+	}
+
+	digest := r.buildFailureDigest(ctx, task, job)
+	task.Status.Phase = taskPhaseDeadLettered
+	task.Status.CompletionTime = &now
+	task.Status.NextRetryTime = nil
+	task.Status.FailureDigest = digest
+	task.Status.Message = fmt.Sprintf("Dead-lettered after %d attempts", digest.Attempts)
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	r.recordTaskSLOs(task, cluster, swarmv1alpha1.CompletionStage, now.Time, true)
+	r.recordFallbackOutcome(ctx, task, failed, false)
+
+	r.Recorder.Eventf(task, corev1.EventTypeWarning, "DeadLettered",
+		"Task failed after %d attempts: %s", digest.Attempts, digest.Reason)
+	r.notifyDeadLetter(ctx, task, cluster)
+	r.notifyLifecycle(ctx, task, cluster, swarmv1alpha1.TaskDeadLetteredEvent)
+
 	return nil
 }
 
+// retryBackoff returns the delay before the given retry
+func retryBackoff(policy *swarmv1alpha1.RetryPolicy, retryCount int32) time.Duration {
+	backoff := float64(policy.BackoffSeconds)
+	if backoff <= 0 {
+		backoff = 30
+	}
+	multiplier := policy.BackoffMultiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	seconds := backoff * math.Pow(multiplier, float64(retryCount))
+	if seconds > time.Hour.Seconds() {
+		seconds = time.Hour.Seconds()
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// buildFailureDigest collects exit codes and the log tail of the failed job's pods
+func (r *SwarmTaskReconciler) buildFailureDigest(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job) *swarmv1alpha1.FailureDigest {
+	log := log.FromContext(ctx)
+
+	digest := &swarmv1alpha1.FailureDigest{
+		JobName:        job.Name,
+		Attempts:       task.Status.RetryCount + 1,
+		CheckpointRef:  task.Status.CheckpointRef,
+		DeadLetteredAt: metav1.Now(),
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			digest.Reason = condition.Reason
+			if condition.Message != "" {
+				digest.Reason = fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+			}
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		log.Error(err, "Failed to list pods for failure digest", "job", job.Name)
+		return digest
+	}
+
+	var lastMessage string
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != "task" {
+				continue
+			}
+			terminated := cs.State.Terminated
+			if terminated == nil {
+				terminated = cs.LastTerminationState.Terminated
+			}
+			if terminated == nil || terminated.ExitCode == 0 {
+				continue
+			}
+			digest.ExitCodes = append(digest.ExitCodes, terminated.ExitCode)
+			if digest.Reason == "" {
+				digest.Reason = terminated.Reason
+			}
+			if terminated.Message != "" {
+				lastMessage = terminated.Message
+			}
+		}
+	}
+
+	if lastMessage != "" {
+		lines := strings.Split(strings.TrimRight(lastMessage, "\n"), "\n")
+		if len(lines) > maxDigestLogLines {
+			lines = lines[len(lines)-maxDigestLogLines:]
+		}
+		digest.LastLogLines = lines
+	}
+
+	return digest
+}
+
+// notifyDeadLetter emits the failure digest to the cluster's dead-letter sink,
+// if one is configured. The sink secret is read from the task's namespace
+// synchronously; delivery is best effort and runs in the background with
+// bounded retries, so a slow sink never holds up a reconcile.
+func (r *SwarmTaskReconciler) notifyDeadLetter(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster) {
+	log := log.FromContext(ctx)
+
+	spec := cluster.Spec.DeadLetter
+	if spec == nil || spec.SinkURL == "" || task.Status.FailureDigest == nil {
+		return
+	}
+
+	var token string
+	if ref := spec.SinkSecretRef; ref != nil {
+		if ref.Namespace != "" && ref.Namespace != task.Namespace {
+			r.Recorder.Eventf(task, corev1.EventTypeWarning, "DeadLetterNotifyFailed",
+				"Sink secret %s must be in the task's namespace %s", ref.Name, task.Namespace)
+			return
+		}
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: task.Namespace}, secret); err != nil {
+			log.Error(err, "Failed to get dead-letter sink secret", "secret", ref.Name)
+			r.Recorder.Eventf(task, corev1.EventTypeWarning, "DeadLetterNotifyFailed",
+				"Failed to read sink secret %s: %v", ref.Name, err)
+			return
+		}
+		token = string(secret.Data[ref.Key])
+	}
+
+	sink := deadletter.NewWebhookSink(spec.SinkURL, token)
+	notification := deadletter.Notification{
+		Task:      task.Name,
+		Namespace: task.Namespace,
+		Cluster:   cluster.Name,
+		Digest:    *task.Status.FailureDigest,
+	}
+	task = task.DeepCopy()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := sink.Notify(ctx, notification); err != nil {
+			log.Error(err, "Failed to notify dead-letter sink", "url", spec.SinkURL)
+			r.Recorder.Eventf(task, corev1.EventTypeWarning, "DeadLetterNotifyFailed",
+				"Failed to notify dead-letter sink: %v", err)
+		}
+	}()
+}
+
+// notifyLifecycle sends a task lifecycle event to the cluster's notification sinks
+func (r *SwarmTaskReconciler) notifyLifecycle(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, eventType swarmv1alpha1.NotificationEvent) {
+	if r.Notifier == nil {
+		return
+	}
+	r.Notifier.Notify(ctx, cluster, notify.Event{
+		Type:      eventType,
+		Namespace: task.Namespace,
+		Task:      task.Name,
+		Phase:     task.Status.Phase,
+		Message:   task.Status.Message,
+	})
+}
+
+// handleRequeue resets a dead-lettered or failed task so it runs again from a
+// fresh retry budget, then clears the requeue annotation
+func (r *SwarmTaskReconciler) handleRequeue(ctx context.Context, task *swarmv1alpha1.SwarmTask) (ctrl.Result, error) {
+	if task.Status.Phase == taskPhaseDeadLettered || task.Status.Phase == "Failed" {
+		previous := task.Status.Phase
+		task.Status.Phase = "Pending"
+		task.Status.RetryCount = 0
+		task.Status.Attempt++
+		task.Status.NextRetryTime = nil
+		task.Status.CompletionTime = nil
+		task.Status.FailureDigest = nil
+		task.Status.ArchivedAt = nil
+		task.Status.FailureDetails = nil
+		// The fallback chain starts over on the preferred agent type
+		task.Status.Fallback = nil
+		task.Status.Hooks = nil
+		// Reclaimed volumes are provisioned again for the new attempt,
+		// retained ones keep the node their data lives on
+		task.Status.Volumes = retainedVolumes(task.Status.Volumes)
+		task.Status.Message = fmt.Sprintf("Requeued from %s", previous)
+		if err := r.Status().Update(ctx, task); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "Requeued", "Task requeued from %s", previous)
+	}
+
+	delete(task.Annotations, requeueAnnotation)
+	if err := r.Update(ctx, task); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{Requeue: true}, nil
+}
+
 // finalizeSwarmTask cleans up resources when task is deleted
 func (r *SwarmTaskReconciler) finalizeSwarmTask(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
 	log := log.FromContext(ctx)
@@ -406,13 +1459,81 @@ func (r *SwarmTaskReconciler) finalizeSwarmTask(ctx context.Context, task *swarm
 		}
 	}
 
+	if err := r.deleteTaskVolumes(ctx, task); err != nil {
+		log.Error(err, "Failed to delete task volumes")
+		return err
+	}
+
+	if err := r.deleteTaskNetworkPolicy(ctx, task); err != nil {
+		log.Error(err, "Failed to delete task network policy")
+		return err
+	}
+
+	if serviceTask(task) {
+		if err := r.deleteTaskService(ctx, task); err != nil {
+			log.Error(err, "Failed to delete task service")
+			return err
+		}
+	}
+
+	if err := r.deleteUnownedJobs(ctx, task); err != nil {
+		log.Error(err, "Failed to delete task jobs")
+		return err
+	}
+
+	return nil
+}
+
+// deleteUnownedJobs deletes the Jobs of a task that runs in another
+// namespace, which are not garbage collected with the task
+func (r *SwarmTaskReconciler) deleteUnownedJobs(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
+	namespace := r.determineNamespace(task)
+	if namespace == task.Namespace {
+		return nil
+	}
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(namespace), client.MatchingLabels{
+		"swarm.claudeflow.io/task": task.Name,
+		taskNamespaceLabel:         task.Namespace,
+	}); err != nil {
+		return err
+	}
+	propagation := metav1.DeletePropagationBackground
+	for i := range jobs.Items {
+		if err := r.Delete(ctx, &jobs.Items[i], &client.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
 	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SwarmTaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &swarmv1alpha1.SwarmTask{},
+		idempotencyKeyField, indexIdempotencyKey); err != nil {
+		return err
+	}
+
+	// Warm pools are sized per cluster and built like task Jobs
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("warmpool").
+		For(&swarmv1alpha1.SwarmCluster{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(mapWarmPodToCluster)).
+		Complete(r.MetricsRecorder.InstrumentReconciler("warmpool", reconcile.Func(r.reconcileWarmPool))); err != nil {
+		return err
+	}
+
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&swarmv1alpha1.SwarmTask{}).
 		Owns(&batchv1.Job{}).
-		Complete(r)
+		Owns(&appsv1.Deployment{}).
+		Owns(&swarmv1alpha1.SwarmTask{}).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(mapToTask),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isUnownedTaskObject))).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(mapToTask),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isTaskPod)))
+	if r.Shard != nil {
+		bldr = r.shardTasks(bldr)
+	}
+	return bldr.Complete(r.MetricsRecorder.InstrumentReconciler("swarmtask", r))
 }
\ No newline at end of file
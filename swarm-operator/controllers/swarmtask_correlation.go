@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// correlationIDAnnotation carries the correlation ID of a task. Clients may
+// set it on the task to join the task to their own request; the operator
+// copies it onto the task's Jobs and into the executor environment.
+const correlationIDAnnotation = "swarm.claudeflow.io/correlation-id"
+
+// taskCorrelationID ties the logs of a task, its Jobs and its executor
+// together. It defaults to the task UID, which stays the same across
+// retries.
+func taskCorrelationID(task *swarmv1alpha1.SwarmTask) string {
+	if id := task.Annotations[correlationIDAnnotation]; id != "" {
+		return id
+	}
+	return string(task.UID)
+}
+
+// withTaskLogger adds the cluster and correlation ID of the task to the
+// logger of the reconcile, next to the name, namespace and reconcileID
+// controller-runtime sets
+func withTaskLogger(ctx context.Context, task *swarmv1alpha1.SwarmTask) context.Context {
+	return log.IntoContext(ctx, log.FromContext(ctx).WithValues(
+		"cluster", task.Spec.SwarmCluster,
+		"correlationID", taskCorrelationID(task),
+	))
+}
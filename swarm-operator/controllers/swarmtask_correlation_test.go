@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Task correlation IDs", func() {
+	var (
+		task       *swarmv1alpha1.SwarmTask
+		reconciler *SwarmTaskReconciler
+	)
+
+	BeforeEach(func() {
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClient(),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "trace", Namespace: "default", UID: types.UID("task-uid")},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", Description: "trace me"},
+		}
+	})
+
+	It("passes the task UID from the Job to the executor", func() {
+		cluster := &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"}}
+		job, _, err := reconciler.buildJob(context.Background(), task, cluster, "default", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(job.Annotations).To(HaveKeyWithValue(correlationIDAnnotation, "task-uid"))
+		Expect(job.Spec.Template.Annotations).To(HaveKeyWithValue(correlationIDAnnotation, "task-uid"))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: executor.EnvCorrelationID, Value: "task-uid"}))
+	})
+
+	It("keeps the correlation ID set by the client", func() {
+		task.Annotations = map[string]string{correlationIDAnnotation: "req-42"}
+		Expect(taskCorrelationID(task)).To(Equal("req-42"))
+		Expect(reconciler.buildEnvironment(task, "")).To(ContainElement(corev1.EnvVar{Name: executor.EnvCorrelationID, Value: "req-42"}))
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Dead-letter notifications", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		cluster    *swarmv1alpha1.SwarmCluster
+		recorder   *record.FakeRecorder
+		reconciler *SwarmTaskReconciler
+		server     *httptest.Server
+		received   chan string
+		release    chan struct{}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		received = make(chan string, 10)
+		release = make(chan struct{})
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			received <- r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "team-a"},
+			Status: swarmv1alpha1.SwarmTaskStatus{
+				FailureDigest: &swarmv1alpha1.FailureDigest{JobName: "build-job", Attempts: 3},
+			},
+		}
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "team-a"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				DeadLetter: &swarmv1alpha1.DeadLetterSpec{
+					SinkURL:       server.URL,
+					SinkSecretRef: &swarmv1alpha1.SecretKeyRef{Name: "sink", Key: "token"},
+				},
+			},
+		}
+		secrets := []*corev1.Secret{
+			{ObjectMeta: metav1.ObjectMeta{Name: "sink", Namespace: "team-a"}, Data: map[string][]byte{"token": []byte("team-a-token")}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "sink", Namespace: "team-b"}, Data: map[string][]byte{"token": []byte("team-b-token")}},
+		}
+
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClient(secrets[0], secrets[1]),
+			Scheme:   testScheme,
+			Recorder: recorder,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("delivers the digest in the background", func() {
+		reconciler.notifyDeadLetter(ctx, task, cluster)
+		// The sink is still blocked, so the reconcile must not have waited
+		close(release)
+		Eventually(received).Should(Receive(Equal("Bearer team-a-token")))
+	})
+
+	It("refuses sink secrets outside the task's namespace", func() {
+		close(release)
+		cluster.Spec.DeadLetter.SinkSecretRef.Namespace = "team-b"
+		reconciler.notifyDeadLetter(ctx, task, cluster)
+		Expect(recorder.Events).To(Receive(ContainSubstring("must be in the task's namespace team-a")))
+		Consistently(received).ShouldNot(Receive())
+	})
+})
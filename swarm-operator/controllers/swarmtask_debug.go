@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// debugAnnotation asks the operator to clone the pod of a failed task
+	// with its command replaced by sleep so it can be inspected with
+	// kubectl exec. Set on a task that has not failed yet, it arms the
+	// debug pod for when it does.
+	debugAnnotation = "swarm.claudeflow.io/debug"
+
+	// debugTTLAnnotation overrides how long the debug pod is kept
+	debugTTLAnnotation = "swarm.claudeflow.io/debug-ttl"
+
+	// debugPodLabel marks debug pods
+	debugPodLabel = "swarm.claudeflow.io/debug"
+
+	defaultDebugTTL = time.Hour
+)
+
+// debugRequested reports whether a debug pod is requested for the task
+func debugRequested(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Annotations[debugAnnotation] == "true"
+}
+
+// taskDebuggable reports whether the task has failed for good, the only
+// state a debug pod is cloned in
+func taskDebuggable(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Status.Phase == "Failed" || task.Status.Phase == taskPhaseDeadLettered
+}
+
+// taskDebugPodName names the debug pod after the task rather than its
+// last Job, so the pod to kubectl exec into is always <task>-debug in the
+// namespace the task ran in
+func taskDebugPodName(task *swarmv1alpha1.SwarmTask) string {
+	return task.Name + "-debug"
+}
+
+// debugTTL returns how long the debug pod is kept, from the TTL annotation
+// or the default of an hour
+func debugTTL(task *swarmv1alpha1.SwarmTask) time.Duration {
+	if ttl, err := time.ParseDuration(task.Annotations[debugTTLAnnotation]); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultDebugTTL
+}
+
+// reconcileDebugPod starts, keeps and ends the debug session of a failed
+// task. It returns a non-zero result while a debug pod is running so the
+// task comes back when the session expires.
+func (r *SwarmTaskReconciler) reconcileDebugPod(ctx context.Context, task *swarmv1alpha1.SwarmTask) (ctrl.Result, error) {
+	if session := task.Status.Debug; session != nil {
+		if debugRequested(task) && taskDebuggable(task) && time.Now().Before(session.ExpiresAt.Time) {
+			pod := &corev1.Pod{}
+			err := r.Get(ctx, types.NamespacedName{Name: session.PodName, Namespace: session.Namespace}, pod)
+			if err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			if err == nil && pod.DeletionTimestamp == nil {
+				return ctrl.Result{RequeueAfter: time.Until(session.ExpiresAt.Time)}, nil
+			}
+		}
+		return ctrl.Result{}, r.endDebugSession(ctx, task)
+	}
+
+	if !debugRequested(task) || !taskDebuggable(task) {
+		return ctrl.Result{}, nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: taskJobName(task), Namespace: r.determineNamespace(task)}, job)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Event(task, corev1.EventTypeWarning, "DebugUnavailable",
+			"The task has no Job left to clone a debug pod from")
+		return ctrl.Result{}, r.clearDebugAnnotation(ctx, task)
+	}
+
+	ttl := debugTTL(task)
+	pod := buildDebugPod(task, job, ttl)
+	if err := controllerutil.SetControllerReference(task, pod, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	// A pod left behind by a session whose status was never written is
+	// adopted rather than replaced
+	if err := r.Create(ctx, pod); err != nil && !errors.IsAlreadyExists(err) {
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	task.Status.Debug = &swarmv1alpha1.TaskDebugStatus{
+		PodName:   pod.Name,
+		Namespace: pod.Namespace,
+		StartTime: now,
+		ExpiresAt: metav1.NewTime(now.Add(ttl)),
+	}
+	if err := r.Status().Update(ctx, task); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(task, corev1.EventTypeNormal, "DebugStarted",
+		"Debug pod %s/%s is running until %s, attach with kubectl exec -it -n %s %s -- sh",
+		pod.Namespace, pod.Name, task.Status.Debug.ExpiresAt.UTC().Format(time.RFC3339), pod.Namespace, pod.Name)
+
+	return ctrl.Result{RequeueAfter: ttl}, nil
+}
+
+// endDebugSession deletes the debug pod, clears the debug status and removes
+// the annotation so the session is not started again
+func (r *SwarmTaskReconciler) endDebugSession(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
+	session := task.Status.Debug
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: session.PodName, Namespace: session.Namespace}}
+	if err := r.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	task.Status.Debug = nil
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Debug session ended", "pod", session.PodName)
+	r.Recorder.Eventf(task, corev1.EventTypeNormal, "DebugEnded", "Deleted debug pod %s/%s", session.Namespace, session.PodName)
+
+	return r.clearDebugAnnotation(ctx, task)
+}
+
+func (r *SwarmTaskReconciler) clearDebugAnnotation(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
+	if _, ok := task.Annotations[debugAnnotation]; !ok {
+		return nil
+	}
+	delete(task.Annotations, debugAnnotation)
+	delete(task.Annotations, debugTTLAnnotation)
+	return r.Update(ctx, task)
+}
+
+// buildDebugPod clones the pod template of the task's Job with the same
+// volumes, secrets and environment, but with the task container sleeping
+// instead of running, no restarts and no probes
+func buildDebugPod(task *swarmv1alpha1.SwarmTask, job *batchv1.Job, ttl time.Duration) *corev1.Pod {
+	template := job.Spec.Template.DeepCopy()
+
+	labels := map[string]string{}
+	for k, v := range template.Labels {
+		// Without the Job's selector labels the Job controller leaves the
+		// pod alone and pod lookups by job-name skip it
+		switch k {
+		case "job-name", "controller-uid", batchv1.JobNameLabel, batchv1.ControllerUidLabel:
+			continue
+		}
+		labels[k] = v
+	}
+	labels[debugPodLabel] = "true"
+
+	spec := template.Spec
+	spec.RestartPolicy = corev1.RestartPolicyNever
+	// The kubelet stops the pod at the TTL even if the operator is down
+	deadline := int64(ttl.Seconds())
+	spec.ActiveDeadlineSeconds = &deadline
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		container.LivenessProbe = nil
+		container.ReadinessProbe = nil
+		container.StartupProbe = nil
+		if container.Name != "task" {
+			continue
+		}
+		container.Command = []string{"sleep", strconv.FormatInt(deadline, 10)}
+		if spec.OS != nil && spec.OS.Name == corev1.Windows {
+			container.Command = windowsCommand("Start-Sleep -Seconds " + strconv.FormatInt(deadline, 10))
+		}
+		container.Args = nil
+		container.Stdin = true
+		container.TTY = true
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        taskDebugPodName(task),
+			Namespace:   job.Namespace,
+			Labels:      labels,
+			Annotations: template.Annotations,
+		},
+		Spec: spec,
+	}
+}
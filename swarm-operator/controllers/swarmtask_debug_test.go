@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task debug pods", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		job        *batchv1.Job
+		recorder   *record.FakeRecorder
+		reconciler *SwarmTaskReconciler
+	)
+
+	reconcilerFor := func(objects ...client.Object) *SwarmTaskReconciler {
+		k8sClient := newTestClient(objects...)
+		return &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: recorder}
+	}
+
+	debugPod := func() (*corev1.Pod, error) {
+		pod := &corev1.Pod{}
+		err := reconciler.Get(ctx, types.NamespacedName{Name: taskDebugPodName(task), Namespace: "default"}, pod)
+		return pod, err
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "build",
+				Namespace:   "default",
+				Annotations: map[string]string{debugAnnotation: "true", debugTTLAnnotation: "30m"},
+			},
+			Spec:   swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", Namespace: "default"},
+			Status: swarmv1alpha1.SwarmTaskStatus{Phase: "Failed"},
+		}
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "default"},
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+						"swarm.claudeflow.io/task": "build",
+						"job-name":                 taskJobName(task),
+						batchv1.ControllerUidLabel: "1234",
+					}},
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyOnFailure,
+						Volumes:       []corev1.Volume{{Name: "workspace"}},
+						Containers: []corev1.Container{
+							{
+								Name:          "task",
+								Command:       []string{"/bin/sh", "-c"},
+								Args:          []string{"run-task"},
+								Env:           []corev1.EnvVar{{Name: "SWARM_TASK_NAME", Value: "build"}},
+								LivenessProbe: &corev1.Probe{},
+							},
+							{Name: "memory-proxy", Command: []string{"proxy"}},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("clones the Job's pod with the task container sleeping", func() {
+		pod := buildDebugPod(task, job, 30*time.Minute)
+
+		Expect(pod.Name).To(Equal("build-debug"))
+		Expect(pod.Labels).To(Equal(map[string]string{"swarm.claudeflow.io/task": "build", debugPodLabel: "true"}))
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(*pod.Spec.ActiveDeadlineSeconds).To(Equal(int64(1800)))
+		Expect(pod.Spec.Volumes).To(Equal(job.Spec.Template.Spec.Volumes))
+
+		Expect(pod.Spec.Containers[0].Command).To(Equal([]string{"sleep", "1800"}))
+		Expect(pod.Spec.Containers[0].Args).To(BeEmpty())
+		Expect(pod.Spec.Containers[0].Env).To(Equal(job.Spec.Template.Spec.Containers[0].Env))
+		Expect(pod.Spec.Containers[0].LivenessProbe).To(BeNil())
+		Expect(pod.Spec.Containers[1].Command).To(Equal([]string{"proxy"}))
+
+		// The Job's template is left untouched
+		Expect(job.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"run-task"}))
+	})
+
+	It("starts a debug session for a failed task and ends it at the TTL", func() {
+		reconciler = reconcilerFor(task, job)
+
+		result, err := reconciler.reconcileDebugPod(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Minute))
+		Expect(task.Status.Debug).NotTo(BeNil())
+		Expect(task.Status.Debug.PodName).To(Equal("build-debug"))
+		Expect(<-recorder.Events).To(ContainSubstring("DebugStarted"))
+
+		_, err = debugPod()
+		Expect(err).NotTo(HaveOccurred())
+
+		// Within the TTL the session is kept
+		result, err = reconciler.reconcileDebugPod(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 29*time.Minute))
+
+		task.Status.Debug.ExpiresAt = metav1.NewTime(time.Now().Add(-time.Second))
+		result, err = reconciler.reconcileDebugPod(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(<-recorder.Events).To(ContainSubstring("DebugEnded"))
+
+		_, err = debugPod()
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		updated := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(task), updated)).To(Succeed())
+		Expect(updated.Status.Debug).To(BeNil())
+		Expect(updated.Annotations).NotTo(HaveKey(debugAnnotation))
+	})
+
+	It("ends the session when the annotation is removed", func() {
+		reconciler = reconcilerFor(task, job)
+		_, err := reconciler.reconcileDebugPod(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+
+		delete(task.Annotations, debugAnnotation)
+		result, err := reconciler.reconcileDebugPod(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(task.Status.Debug).To(BeNil())
+
+		_, err = debugPod()
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("waits for the task to fail", func() {
+		task.Status.Phase = "Running"
+		reconciler = reconcilerFor(task, job)
+
+		result, err := reconciler.reconcileDebugPod(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(task.Status.Debug).To(BeNil())
+
+		_, err = debugPod()
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("drops the request when there is no Job to clone", func() {
+		reconciler = reconcilerFor(task)
+
+		result, err := reconciler.reconcileDebugPod(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsZero()).To(BeTrue())
+		Expect(<-recorder.Events).To(ContainSubstring("DebugUnavailable"))
+		Expect(task.Annotations).NotTo(HaveKey(debugAnnotation))
+	})
+})
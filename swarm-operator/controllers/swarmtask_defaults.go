@@ -0,0 +1,302 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const inheritedCredentialsSecretType = "inherited-credentials"
+
+// taskDefaults are the SwarmDefaults along the chain of namespaces a task
+// resolves its defaults through, closest first
+type taskDefaults struct {
+	namespaces []string
+	// defaults of each namespace, nil where it has none
+	defaults []*swarmv1alpha1.SwarmDefaults
+}
+
+// resolvedCredential is a well-known credential secret found along the
+// chain
+type resolvedCredential struct {
+	// name of the well-known secret, e.g. github-credentials
+	name string
+	// namespace and secret providing it
+	namespace string
+	secret    string
+	data      map[string][]byte
+}
+
+// defaultsChain returns the namespaces a task resolves its defaults
+// through: the task's own, the swarm namespace of its cluster's
+// namespaceConfig, the operator's swarm namespace and last the namespace
+// its Job runs in
+func (r *SwarmTaskReconciler) defaultsChain(task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, jobNamespace string) []string {
+	swarmNamespace, _ := operatorNamespaces(r.Config, r.SwarmNamespace, r.HiveMindNamespace)
+	chain := []string{task.Namespace}
+	if cluster != nil && cluster.Spec.NamespaceConfig != nil {
+		chain = append(chain, cluster.Spec.NamespaceConfig.SwarmNamespace)
+	}
+	chain = append(chain, swarmNamespace, jobNamespace)
+
+	var namespaces []string
+	for _, ns := range chain {
+		if ns != "" && !containsString(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// taskDefaults reads the SwarmDefaults along the task's chain
+func (r *SwarmTaskReconciler) taskDefaults(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, jobNamespace string) (*taskDefaults, error) {
+	d := &taskDefaults{namespaces: r.defaultsChain(task, cluster, jobNamespace)}
+	for _, ns := range d.namespaces {
+		defaults := &swarmv1alpha1.SwarmDefaults{}
+		err := r.Get(ctx, types.NamespacedName{Name: swarmv1alpha1.SwarmDefaultsName, Namespace: ns}, defaults)
+		if errors.IsNotFound(err) {
+			defaults = nil
+		} else if err != nil {
+			return nil, err
+		}
+		d.defaults = append(d.defaults, defaults)
+	}
+	return d, nil
+}
+
+// executorImage returns the closest default executor image, empty when no
+// namespace sets one
+func (d *taskDefaults) executorImage(windows bool) string {
+	if d == nil {
+		return ""
+	}
+	for _, defaults := range d.defaults {
+		if defaults == nil {
+			continue
+		}
+		image := defaults.Spec.ExecutorImage
+		if windows {
+			image = defaults.Spec.ExecutorWindowsImage
+		}
+		if image != "" {
+			return image
+		}
+	}
+	return ""
+}
+
+// nodeSelector merges the node selectors along the chain, closer
+// namespaces winning on conflicting keys
+func (d *taskDefaults) nodeSelector() map[string]string {
+	var selector map[string]string
+	if d == nil {
+		return nil
+	}
+	for i := len(d.defaults) - 1; i >= 0; i-- {
+		if d.defaults[i] == nil {
+			continue
+		}
+		for k, v := range d.defaults[i].Spec.NodeSelector {
+			if selector == nil {
+				selector = map[string]string{}
+			}
+			selector[k] = v
+		}
+	}
+	return selector
+}
+
+// resolveCredential finds the well-known credential secret in the closest
+// namespace providing it, under the name its SwarmDefaults maps it to or
+// its own
+func (r *SwarmTaskReconciler) resolveCredential(ctx context.Context, d *taskDefaults, name string) (*resolvedCredential, error) {
+	for i, ns := range d.namespaces {
+		secretName := name
+		if defaults := d.defaults[i]; defaults != nil && defaults.Spec.Credentials[name] != "" {
+			secretName = defaults.Spec.Credentials[name]
+		}
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: ns}, secret)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &resolvedCredential{name: name, namespace: ns, secret: secretName, data: secret.Data}, nil
+	}
+	return nil, nil
+}
+
+// injectedCredentials resolves the well-known credential secrets the task
+// gets injected, in the scope of its tenant
+func (r *SwarmTaskReconciler) injectedCredentials(ctx context.Context, tenant *swarmv1alpha1.SwarmTenant, d *taskDefaults, githubTokenSecret string) ([]resolvedCredential, error) {
+	names := make([]string, 0, len(credentialSecrets)+1)
+	for _, creds := range credentialSecrets {
+		names = append(names, creds.secret)
+	}
+	names = append(names, gcpCredentialsSecret)
+
+	var resolved []resolvedCredential
+	for _, name := range names {
+		if name == "github-credentials" && githubTokenSecret != "" {
+			continue
+		}
+		if !tenantInjectsCredential(tenant, name) {
+			continue
+		}
+		cred, err := r.resolveCredential(ctx, d, name)
+		if err != nil {
+			return nil, err
+		}
+		if cred != nil {
+			resolved = append(resolved, *cred)
+		}
+	}
+	return resolved, nil
+}
+
+// credentialSecrets returns the secrets the namespace defaults map the
+// credentials the tenant gets injected to
+func (d *taskDefaults) credentialSecrets(tenant *swarmv1alpha1.SwarmTenant) []string {
+	var names []string
+	for _, defaults := range d.defaults {
+		if defaults == nil {
+			continue
+		}
+		for name, secret := range defaults.Spec.Credentials {
+			if tenantInjectsCredential(tenant, name) {
+				names = append(names, secret)
+			}
+		}
+	}
+	return names
+}
+
+// inherited reports whether the credential comes from another namespace
+// than the Job's, and is copied into the Job's credentials secret
+func (c *resolvedCredential) inherited(jobNamespace string) bool {
+	return c.namespace != jobNamespace
+}
+
+// credentialKeys returns the keys of a well-known credential secret the
+// executor uses
+func credentialKeys(name string) []string {
+	if name == gcpCredentialsSecret {
+		return []string{"key.json"}
+	}
+	for _, creds := range credentialSecrets {
+		if creds.secret == name {
+			keys := make([]string, 0, len(creds.env))
+			for _, pair := range creds.env {
+				keys = append(keys, pair[1])
+			}
+			return keys
+		}
+	}
+	return nil
+}
+
+// inheritedCredentialKey is the key of a credential's key in the Job's
+// credentials secret
+func inheritedCredentialKey(name, key string) string {
+	return name + "." + key
+}
+
+// taskCredentialsSecretName returns the Secret holding the credentials a
+// Job inherited from other namespaces. Every attempt copies its own, which
+// is deleted with its Job.
+func taskCredentialsSecretName(jobName string) string {
+	return jobName + "-credentials"
+}
+
+// ensureCredentialsSecret copies the credentials the Job inherited from
+// other namespaces into a Secret owned by the Job, or by the Deployment of
+// a service task. Secret references must stay within the pod's namespace.
+func (r *SwarmTaskReconciler) ensureCredentialsSecret(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, tenant *swarmv1alpha1.SwarmTenant, owner client.Object, githubTokenSecret string) error {
+	if !r.executorConfig().CredentialSecrets {
+		return nil
+	}
+	name := taskCredentialsSecretName(taskJobName(task))
+	namespace := owner.GetNamespace()
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &corev1.Secret{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	d, err := r.taskDefaults(ctx, task, cluster, namespace)
+	if err != nil {
+		return err
+	}
+	resolved, err := r.injectedCredentials(ctx, tenant, d, githubTokenSecret)
+	if err != nil {
+		return err
+	}
+	data := map[string][]byte{}
+	for _, cred := range resolved {
+		if !cred.inherited(namespace) {
+			continue
+		}
+		for _, key := range credentialKeys(cred.name) {
+			if value, ok := cred.data[key]; ok {
+				data[inheritedCredentialKey(cred.name, key)] = value
+			}
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	labels := taskResourceLabels(task)
+	labels[secretTypeLabel] = inheritedCredentialsSecretType
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       data,
+	}
+	if err := controllerutil.SetControllerReference(owner, secret, r.Scheme); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Creating inherited credentials secret", "secret", name)
+	if err := r.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// applyTaskDefaults merges the default node selector of the task's chain
+// into the pod spec, keeping the keys already set
+func applyTaskDefaults(d *taskDefaults, podSpec *corev1.PodSpec) {
+	for k, v := range d.nodeSelector() {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		if _, ok := podSpec.NodeSelector[k]; !ok {
+			podSpec.NodeSelector[k] = v
+		}
+	}
+}
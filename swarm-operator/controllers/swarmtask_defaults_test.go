@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Namespace task defaults", func() {
+	var (
+		ctx        context.Context
+		reconciler *SwarmTaskReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+		task       *swarmv1alpha1.SwarmTask
+		podSpec    *corev1.PodSpec
+	)
+
+	secret := func(name, namespace string, data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Data: map[string][]byte{}}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+		return s
+	}
+	envVar := func(name string) *corev1.EnvVar {
+		for i := range podSpec.Containers[0].Env {
+			if podSpec.Containers[0].Env[i].Name == name {
+				return &podSpec.Containers[0].Env[i]
+			}
+		}
+		return nil
+	}
+
+	setup := func(objects ...client.Object) {
+		reconciler = &SwarmTaskReconciler{
+			Client:         newTestClient(objects...),
+			Scheme:         testScheme,
+			SwarmNamespace: "claude-flow-swarm",
+			Executor:       ExecutorConfig{CredentialSecrets: true},
+		}
+	}
+
+	apply := func() {
+		defaults, err := reconciler.taskDefaults(ctx, task, cluster, "claude-flow-swarm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.applyExecutor(ctx, task, cluster, "claude-flow-swarm", defaults, podSpec, "")).To(Succeed())
+		applyTaskDefaults(defaults, podSpec)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "team-a"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				NamespaceConfig: &swarmv1alpha1.NamespaceConfig{SwarmNamespace: "team-a-swarm"},
+			},
+		}
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "team-a"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+		}
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{Name: "task", Image: placeholderExecutorImage}}}
+	})
+
+	It("resolves credentials through the task, cluster and operator namespaces", func() {
+		setup(
+			secret("github-credentials", "team-a", map[string]string{"token": "team-a-token"}),
+			secret("github-credentials", "claude-flow-swarm", map[string]string{"token": "shared-token"}),
+			secret("team-a-aws", "team-a-swarm", map[string]string{"access-key-id": "AKIA", "secret-access-key": "s3cret"}),
+			secret("azure-credentials", "claude-flow-swarm", map[string]string{"client-id": "shared"}),
+			&swarmv1alpha1.SwarmDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "team-a-swarm"},
+				Spec:       swarmv1alpha1.SwarmDefaultsSpec{Credentials: map[string]string{"aws-credentials": "team-a-aws"}},
+			},
+		)
+		apply()
+
+		// The task namespace's secret wins over the operator's and is copied
+		token := envVar("GITHUB_TOKEN").ValueFrom.SecretKeyRef
+		Expect(token.Name).To(Equal("deploy-job-credentials"))
+		Expect(token.Key).To(Equal("github-credentials.token"))
+		Expect(*token.Optional).To(BeFalse())
+		Expect(envVar("GITHUB_USERNAME")).To(BeNil())
+
+		// The cluster's swarm namespace maps aws-credentials to its own secret
+		Expect(envVar("AWS_ACCESS_KEY_ID").ValueFrom.SecretKeyRef.Key).To(Equal("aws-credentials.access-key-id"))
+		Expect(envVar("AWS_DEFAULT_REGION")).To(BeNil())
+
+		// The operator namespace is the Job's, referenced as before
+		azure := envVar("AZURE_CLIENT_ID").ValueFrom.SecretKeyRef
+		Expect(azure.Name).To(Equal("azure-credentials"))
+		Expect(*azure.Optional).To(BeTrue())
+
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "deploy-job", Namespace: "claude-flow-swarm", UID: "job-uid"}}
+		Expect(reconciler.ensureCredentialsSecret(ctx, task, cluster, nil, job, "")).To(Succeed())
+		copied := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "deploy-job-credentials", Namespace: "claude-flow-swarm"}, copied)).To(Succeed())
+		Expect(copied.Data).To(Equal(map[string][]byte{
+			"github-credentials.token":          []byte("team-a-token"),
+			"aws-credentials.access-key-id":     []byte("AKIA"),
+			"aws-credentials.secret-access-key": []byte("s3cret"),
+		}))
+		Expect(metav1.IsControlledBy(copied, job)).To(BeTrue())
+	})
+
+	It("only injects the credentials in the tenant's scope", func() {
+		setup(secret("github-credentials", "team-a", map[string]string{"token": "team-a-token"}))
+		tenant := &swarmv1alpha1.SwarmTenant{Spec: swarmv1alpha1.SwarmTenantSpec{
+			Credentials: &swarmv1alpha1.TenantCredentials{InjectedSecrets: []string{"aws-credentials"}},
+		}}
+		defaults, err := reconciler.taskDefaults(ctx, task, cluster, "claude-flow-swarm")
+		Expect(err).NotTo(HaveOccurred())
+		resolved, err := reconciler.injectedCredentials(ctx, tenant, defaults, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(BeEmpty())
+	})
+
+	It("inherits the executor image and node selector", func() {
+		setup(
+			&swarmv1alpha1.SwarmDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "claude-flow-swarm"},
+				Spec: swarmv1alpha1.SwarmDefaultsSpec{
+					ExecutorImage: "ghcr.io/acme/executor:v1",
+					NodeSelector:  map[string]string{"pool": "shared", "arch": "amd64"},
+				},
+			},
+			&swarmv1alpha1.SwarmDefaults{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "team-a"},
+				Spec: swarmv1alpha1.SwarmDefaultsSpec{
+					ExecutorImage: "ghcr.io/team-a/executor:v2",
+					NodeSelector:  map[string]string{"pool": "team-a"},
+				},
+			},
+		)
+		reconciler.Executor.Image = "claude-flow/swarm-executor:latest"
+		apply()
+
+		Expect(podSpec.Containers[0].Image).To(Equal("ghcr.io/team-a/executor:v2"))
+		Expect(podSpec.NodeSelector).To(Equal(map[string]string{"pool": "team-a", "arch": "amd64"}))
+
+		// The task's own image still wins
+		task.Spec.ExecutorImage = "example.com/terraform:1.8"
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{Name: "task", Image: placeholderExecutorImage}}}
+		apply()
+		Expect(podSpec.Containers[0].Image).To(Equal("example.com/terraform:1.8"))
+	})
+
+	It("resolves through each namespace once, closest first", func() {
+		setup()
+		Expect(reconciler.defaultsChain(task, cluster, "claude-flow-swarm")).To(Equal(
+			[]string{"team-a", "team-a-swarm", "claude-flow-swarm"}))
+		Expect(reconciler.defaultsChain(task, nil, "team-a")).To(Equal([]string{"team-a", "claude-flow-swarm"}))
+		Expect(reconciler.defaultsChain(task, nil, "isolated")).To(Equal([]string{"team-a", "claude-flow-swarm", "isolated"}))
+	})
+})
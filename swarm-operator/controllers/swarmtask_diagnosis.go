@@ -0,0 +1,227 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// maxFailureLogLines and maxFailureLogBytes bound the log tail kept in
+	// status.failureDetails
+	maxFailureLogLines = 50
+	maxFailureLogBytes = 8 * 1024
+)
+
+// diagnoseJobFailure inspects the pod of a failed Job attempt for exit
+// codes, OOM kills, evictions and the log tail of the failed container
+func (r *SwarmTaskReconciler) diagnoseJobFailure(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job) *swarmv1alpha1.FailureDetails {
+	log := log.FromContext(ctx)
+
+	details := &swarmv1alpha1.FailureDetails{
+		JobName:    job.Name,
+		Attempt:    task.Status.Attempt,
+		ObservedAt: metav1.Now(),
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			details.Reason = condition.Reason
+			details.Message = condition.Message
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		log.Error(err, "Failed to list pods for failure diagnosis", "job", job.Name)
+		return details
+	}
+	pod := failedPod(pods.Items)
+	if pod == nil {
+		return details
+	}
+
+	container, previous := diagnosePod(details, pod)
+	if container == nil {
+		return details
+	}
+	if container.InitContainer && isPreStartContainer(task, container.Name) {
+		details.Hook = swarmv1alpha1.PreStartHook
+	}
+	if r.Kube != nil {
+		lines, err := r.tailContainerLog(ctx, pod, container.Name, previous)
+		if err == nil && len(lines) > 0 {
+			details.LogTail = lines
+			return details
+		}
+		if err != nil {
+			log.V(1).Info("Falling back to the termination message for the log tail", "pod", pod.Name, "error", err.Error())
+		}
+	}
+	// The task container reports its log tail as termination message
+	details.LogTail = tailLines(container.Message, maxFailureLogLines)
+	return details
+}
+
+// failedPod picks the most recent pod of a Job that shows a failure
+func failedPod(pods []corev1.Pod) *corev1.Pod {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+	})
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodFailed {
+			return pod
+		}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if failure, _ := containerFailure(status, false); failure != nil {
+				return pod
+			}
+		}
+	}
+	return nil
+}
+
+// diagnosePod records the failed containers of the pod and the most
+// specific failure reason. It returns the container whose log explains the
+// failure, preferring OOM kills, and whether that log belongs to a previous
+// run of the container.
+func diagnosePod(details *swarmv1alpha1.FailureDetails, pod *corev1.Pod) (*swarmv1alpha1.ContainerFailure, bool) {
+	details.PodName = pod.Name
+
+	var restarted []bool
+	record := func(statuses []corev1.ContainerStatus, init bool) {
+		for _, status := range statuses {
+			if failure, previous := containerFailure(status, init); failure != nil {
+				details.Containers = append(details.Containers, *failure)
+				restarted = append(restarted, previous)
+			}
+		}
+	}
+	record(pod.Status.InitContainerStatuses, true)
+	record(pod.Status.ContainerStatuses, false)
+
+	culprit := -1
+	for i := range details.Containers {
+		if culprit < 0 || (details.Containers[i].Reason == "OOMKilled" && details.Containers[culprit].Reason != "OOMKilled") {
+			culprit = i
+		}
+	}
+
+	// Pod-level reasons such as Evicted or DeadlineExceeded explain the
+	// container terminations
+	switch {
+	case pod.Status.Reason != "":
+		details.Reason, details.Message = pod.Status.Reason, pod.Status.Message
+	case culprit >= 0:
+		failure := details.Containers[culprit]
+		details.Reason = failure.Reason
+		if details.Reason == "" {
+			details.Reason = "Error"
+		}
+		details.Message = fmt.Sprintf("container %s exited with code %d", failure.Name, failure.ExitCode)
+	}
+
+	if culprit < 0 {
+		return nil, false
+	}
+	return &details.Containers[culprit], restarted[culprit]
+}
+
+// containerFailure returns the unsuccessful last termination of a
+// container, and whether the container has been restarted since
+func containerFailure(status corev1.ContainerStatus, init bool) (*swarmv1alpha1.ContainerFailure, bool) {
+	terminated, restarted := status.State.Terminated, false
+	if terminated == nil {
+		terminated, restarted = status.LastTerminationState.Terminated, true
+	}
+	if terminated == nil || (terminated.ExitCode == 0 && terminated.Reason != "OOMKilled") {
+		return nil, false
+	}
+
+	failure := &swarmv1alpha1.ContainerFailure{
+		Name:          status.Name,
+		InitContainer: init,
+		ExitCode:      terminated.ExitCode,
+		Signal:        terminated.Signal,
+		Reason:        terminated.Reason,
+		Message:       terminated.Message,
+		RestartCount:  status.RestartCount,
+	}
+	if !terminated.StartedAt.IsZero() {
+		failure.StartedAt = terminated.StartedAt.DeepCopy()
+	}
+	if !terminated.FinishedAt.IsZero() {
+		failure.FinishedAt = terminated.FinishedAt.DeepCopy()
+	}
+	return failure, restarted
+}
+
+// tailContainerLog fetches the last lines logged by a container
+func (r *SwarmTaskReconciler) tailContainerLog(ctx context.Context, pod *corev1.Pod, container string, previous bool) ([]string, error) {
+	lines, limit := int64(maxFailureLogLines), int64(maxFailureLogBytes)
+	raw, err := r.Kube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		TailLines:  &lines,
+		LimitBytes: &limit,
+	}).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tailLines(string(raw), maxFailureLogLines), nil
+}
+
+// tailLines splits text into lines and keeps the last n
+func tailLines(text string, n int) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// failureSummary renders failure details for task messages and events
+func failureSummary(details *swarmv1alpha1.FailureDetails) string {
+	if details.Hook != "" {
+		hook := *details
+		hook.Hook = ""
+		return fmt.Sprintf("%s hook failed: %s", details.Hook, failureSummary(&hook))
+	}
+	switch {
+	case details.Reason != "" && details.Message != "":
+		return fmt.Sprintf("%s: %s", details.Reason, details.Message)
+	case details.Reason != "":
+		return details.Reason
+	default:
+		return "unknown reason"
+	}
+}
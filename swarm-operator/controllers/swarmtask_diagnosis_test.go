@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Job failure diagnosis", func() {
+	terminated := func(name string, exitCode int32, reason, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name: name,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: exitCode,
+				Reason:   reason,
+				Message:  message,
+			}},
+		}
+	}
+
+	It("should report OOM kills over other container failures", func() {
+		pod := &corev1.Pod{Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{
+				terminated("sidecar", 1, "Error", ""),
+				terminated("task", 137, "OOMKilled", "allocating buffers\n"),
+			},
+		}}
+		pod.Name = "task-job-abc"
+
+		details := &swarmv1alpha1.FailureDetails{}
+		container, previous := diagnosePod(details, pod)
+		Expect(container).NotTo(BeNil())
+		Expect(container.Name).To(Equal("task"))
+		Expect(previous).To(BeFalse())
+		Expect(details.PodName).To(Equal("task-job-abc"))
+		Expect(details.Reason).To(Equal("OOMKilled"))
+		Expect(details.Message).To(Equal("container task exited with code 137"))
+		Expect(details.Containers).To(HaveLen(2))
+	})
+
+	It("should prefer the pod reason for evictions", func() {
+		pod := &corev1.Pod{Status: corev1.PodStatus{
+			Phase:             corev1.PodFailed,
+			Reason:            "Evicted",
+			Message:           "The node was low on resource: ephemeral-storage.",
+			ContainerStatuses: []corev1.ContainerStatus{terminated("task", 137, "Error", "")},
+		}}
+
+		details := &swarmv1alpha1.FailureDetails{}
+		diagnosePod(details, pod)
+		Expect(details.Reason).To(Equal("Evicted"))
+		Expect(details.Message).To(ContainSubstring("ephemeral-storage"))
+	})
+
+	It("should capture failed init containers from their last termination", func() {
+		status := corev1.ContainerStatus{
+			Name:         "git-checkout",
+			RestartCount: 2,
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 128,
+				Message:  "fatal: repository not found",
+			}},
+		}
+		pod := &corev1.Pod{Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{status}}}
+
+		details := &swarmv1alpha1.FailureDetails{}
+		container, previous := diagnosePod(details, pod)
+		Expect(container).NotTo(BeNil())
+		Expect(container.InitContainer).To(BeTrue())
+		Expect(container.RestartCount).To(Equal(int32(2)))
+		Expect(previous).To(BeTrue())
+		Expect(details.Reason).To(Equal("Error"))
+		Expect(failedPod([]corev1.Pod{*pod})).NotTo(BeNil())
+	})
+
+	It("should ignore successful containers", func() {
+		pod := corev1.Pod{Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{terminated("task", 0, "Completed", "")},
+		}}
+		Expect(failedPod([]corev1.Pod{pod})).To(BeNil())
+	})
+
+	It("should keep the tail of the log", func() {
+		Expect(tailLines("one\ntwo\nthree\n", 2)).To(Equal([]string{"two", "three"}))
+		Expect(tailLines("", 2)).To(BeEmpty())
+		Expect(failureSummary(&swarmv1alpha1.FailureDetails{Reason: "OOMKilled"})).To(Equal("OOMKilled"))
+	})
+})
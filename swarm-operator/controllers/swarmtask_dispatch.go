@@ -0,0 +1,354 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/dispatch"
+	"github.com/claude-flow/swarm-operator/pkg/policy"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// pushAssignmentMode sends tasks to agents instead of running Jobs
+	pushAssignmentMode = "Push"
+
+	// assignmentAcknowledged is the AssignedAgent status of an acked task
+	assignmentAcknowledged = "Acknowledged"
+
+	// agentCommPortName is the container port agents serve gRPC on
+	agentCommPortName = "comm"
+)
+
+// pushAssignmentEnabled reports whether the cluster pushes tasks to agents
+func pushAssignmentEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.TaskDistribution.AssignmentMode == pushAssignmentMode
+}
+
+// reconcileAssignment pushes the task to the best agent of the cluster that
+// acks it and keeps status.assignedAgents in line with the agents that
+// actually hold the task. Agents report progress and completion by updating
+// the task status themselves.
+func (r *SwarmTaskReconciler) reconcileAssignment(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, githubTokenSecret string) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	if task.Status.Phase == "Completed" || task.Status.Phase == "Failed" {
+		return ctrl.Result{}, nil
+	}
+
+	agentList := &swarmv1alpha1.AgentList{}
+	if err := r.List(ctx, agentList, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{"swarm-cluster": cluster.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Keep the assignment while the agent that acked it is alive
+	if assigned := acknowledgedAgent(task); assigned != "" {
+		if agentAlive(agentList.Items, assigned) {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		log.Info("Assigned agent is gone, reassigning task", "agent", assigned)
+		r.Recorder.Eventf(task, corev1.EventTypeWarning, "AgentLost",
+			"Agent %s is no longer available, reassigning task", assigned)
+		task.Status.AssignedAgents = nil
+	}
+
+	targets, err := r.assignmentTargets(ctx, task, cluster, agentList.Items)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if r.Dispatcher == nil {
+		r.Dispatcher = dispatch.NewDispatcher()
+	}
+	ackTimeout := time.Duration(cluster.Spec.TaskDistribution.AckTimeoutSeconds) * time.Second
+	result := r.Dispatcher.Dispatch(ctx, newAssignment(task, githubTokenSecret), targets, ackTimeout)
+	for _, rejection := range result.Rejections {
+		r.Recorder.Eventf(task, corev1.EventTypeWarning, "AssignmentRejected",
+			"Agent %s did not accept the task: %s", rejection.Agent, rejection.Reason)
+	}
+
+	if result.Agent == "" {
+		task.Status.Phase = "Pending"
+		task.Status.Message = "No ready agent to assign the task to"
+		if len(result.Rejections) > 0 {
+			task.Status.Message = fmt.Sprintf("No agent accepted the task: %s", result.Summary())
+		}
+		if err := r.Status().Update(ctx, task); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+
+	var agentType swarmv1alpha1.AgentType
+	for _, agent := range agentList.Items {
+		if agent.Name == result.Agent {
+			agentType = agent.Spec.Type
+		}
+	}
+	task.Status.Phase = "Scheduled"
+	task.Status.AssignedAgents = []swarmv1alpha1.AssignedAgent{{
+		Name:   result.Agent,
+		Type:   agentType,
+		Status: assignmentAcknowledged,
+	}}
+	task.Status.Message = fmt.Sprintf("Assigned to agent %s", result.Agent)
+	if task.Status.StartTime == nil {
+		task.Status.StartTime = &metav1.Time{Time: time.Now()}
+	}
+	if err := r.Status().Update(ctx, task); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("Task assigned", "agent", result.Agent, "rejections", len(result.Rejections))
+	r.Recorder.Eventf(task, corev1.EventTypeNormal, "Assigned", "Agent %s acknowledged the task", result.Agent)
+	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// assignmentTargets ranks the cluster's agents with the cluster's
+// distribution algorithm and resolves the gRPC address of each
+func (r *SwarmTaskReconciler) assignmentTargets(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, agents []swarmv1alpha1.Agent) ([]dispatch.Target, error) {
+	distribution := cluster.Spec.TaskDistribution
+	if distribution.MaxTasksPerAgent <= 0 {
+		distribution.MaxTasksPerAgent = 10
+	}
+	distributor := utils.NewTaskDistributor(distribution)
+	if len(distribution.Policies) > 0 {
+		engine, err := schedulingPolicies(distribution.Policies)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheduling policy: %w", err)
+		}
+		distributor.WithPolicies(engine, func(result policy.Result) {
+			if result.Err != nil {
+				log.FromContext(ctx).V(1).Info("Scheduling policy failed to evaluate", "policy", result.Policy, "error", result.Err.Error())
+			}
+			if r.MetricsRecorder != nil {
+				r.MetricsRecorder.RecordSchedulingPolicy(cluster.Namespace, cluster.Name, result.Policy, result.Outcome, result.Duration.Seconds())
+			}
+		})
+	}
+	candidate := utils.Task{
+		Name:         task.Name,
+		Namespace:    task.Namespace,
+		Type:         task.Spec.Type,
+		Priority:     taskPriorityWeight(task.Spec.Priority),
+		Capabilities: task.Spec.RequiredCapabilities,
+		Labels:       task.Labels,
+	}
+
+	var tlsConfig *tls.Config
+	if tlsEnabled(cluster) {
+		var err error
+		if tlsConfig, err = r.operatorTLSConfig(ctx, cluster); err != nil {
+			return nil, fmt.Errorf("failed to load operator certificate: %w", err)
+		}
+	}
+
+	remaining := make([]swarmv1alpha1.Agent, 0, len(agents))
+	for _, agent := range agents {
+		if agent.GetDeletionTimestamp() == nil {
+			remaining = append(remaining, agent)
+		}
+	}
+
+	var targets []dispatch.Target
+	for len(remaining) > 0 {
+		picked, err := distributor.AssignTask(candidate, remaining)
+		if err != nil {
+			// No further agent has capacity
+			break
+		}
+		agent := *picked
+
+		address, err := r.agentAddress(ctx, &agent)
+		if err != nil {
+			return nil, err
+		}
+		if address != "" {
+			targets = append(targets, dispatch.Target{Agent: agent.Name, Address: address, TLS: tlsConfig})
+		}
+
+		for i := range remaining {
+			if remaining[i].Name == agent.Name {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+	return targets, nil
+}
+
+// operatorTLSConfig loads the operator's client certificate for the agents
+// of a cluster requiring mTLS
+func (r *SwarmTaskReconciler) operatorTLSConfig(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (*tls.Config, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      tlsSecretName(cluster, operatorTLSComponent),
+		Namespace: cluster.Namespace,
+	}, secret); err != nil {
+		return nil, err
+	}
+
+	certificate, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(secret.Data["ca.crt"]) {
+		return nil, fmt.Errorf("secret %s has no CA certificate", secret.Name)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      roots,
+		ServerName:   agentServerName(cluster),
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// agentAddress returns the gRPC address of the agent's ready pod, or an
+// empty string if the agent has no ready pod. External agents are reached
+// at the address they registered.
+func (r *SwarmTaskReconciler) agentAddress(ctx context.Context, agent *swarmv1alpha1.Agent) (string, error) {
+	if agent.Spec.External {
+		return agent.Spec.CommunicationEndpoints.Address, nil
+	}
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(agent.Namespace),
+		client.MatchingLabels{"swarm.claudeflow.io/agent": agent.Name}); err != nil {
+		return "", err
+	}
+	for i := range podList.Items {
+		if address := podAgentAddress(&podList.Items[i], agent); address != "" {
+			return address, nil
+		}
+	}
+	return "", nil
+}
+
+// podAgentAddress returns the gRPC address of a ready agent pod
+func podAgentAddress(pod *corev1.Pod, agent *swarmv1alpha1.Agent) string {
+	if pod.GetDeletionTimestamp() != nil || pod.Status.PodIP == "" || !podReady(pod) {
+		return ""
+	}
+
+	port := agent.Spec.CommunicationEndpoints.Port
+	for _, container := range pod.Spec.Containers {
+		for _, p := range container.Ports {
+			if p.Name == agentCommPortName {
+				port = p.ContainerPort
+			}
+		}
+	}
+	if port == 0 {
+		port = defaultAgentPort
+	}
+	return net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port)))
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// newAssignment builds the AssignTask request for a task
+func newAssignment(task *swarmv1alpha1.SwarmTask, githubTokenSecret string) *dispatch.Assignment {
+	subtasks := make([]string, 0, len(task.Spec.Subtasks))
+	for _, subtask := range task.Spec.Subtasks {
+		subtasks = append(subtasks, subtask.Name)
+	}
+	return &dispatch.Assignment{
+		Task:        task.Name,
+		Namespace:   task.Namespace,
+		Type:        task.Spec.Type,
+		Description: task.Spec.Description,
+		Priority:    string(task.Spec.Priority),
+		Parameters:  task.Spec.Parameters,
+		Subtasks:    subtasks,
+		TokenSecret: githubTokenSecret,
+		// Tasks taken from a drained agent continue where it stopped
+		CheckpointRef: task.Status.CheckpointRef,
+	}
+}
+
+// acknowledgedAgent returns the agent holding the task, if any
+func acknowledgedAgent(task *swarmv1alpha1.SwarmTask) string {
+	for _, assigned := range task.Status.AssignedAgents {
+		if assigned.Status == assignmentAcknowledged {
+			return assigned.Name
+		}
+	}
+	return ""
+}
+
+// agentAlive reports whether the named agent exists and can still work
+func agentAlive(agents []swarmv1alpha1.Agent, name string) bool {
+	for _, agent := range agents {
+		if agent.Name == name {
+			return agent.GetDeletionTimestamp() == nil &&
+				agent.Status.Phase != "Failed" && agent.Status.Phase != "Terminating"
+		}
+	}
+	return false
+}
+
+// schedulingPolicies compiles the scheduling policies of a cluster
+func schedulingPolicies(policies []swarmv1alpha1.SchedulingPolicy) (*policy.Engine, error) {
+	compiled := make([]policy.Policy, 0, len(policies))
+	for _, p := range policies {
+		compiled = append(compiled, policy.Policy{
+			Name:       p.Name,
+			Expression: p.Expression,
+			Mode:       p.Mode,
+			Weight:     p.Weight,
+		})
+	}
+	return policy.New(compiled)
+}
+
+// taskPriorityWeight maps task priorities onto the 0-10 scale of the
+// task distributor, where anything above 7 is high priority
+func taskPriorityWeight(priority swarmv1alpha1.TaskPriority) int {
+	switch priority {
+	case swarmv1alpha1.CriticalPriority:
+		return 10
+	case swarmv1alpha1.HighPriority:
+		return 8
+	case swarmv1alpha1.LowPriority:
+		return 2
+	default:
+		return 5
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task assignment push", func() {
+	agent := &swarmv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "swarm-coder-1"},
+		Spec: swarmv1alpha1.AgentSpec{
+			CommunicationEndpoints: swarmv1alpha1.CommunicationSpec{Port: 8081},
+		},
+	}
+
+	readyPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  agentContainerName,
+				Ports: []corev1.ContainerPort{{Name: agentCommPortName, ContainerPort: 8080}},
+			}}},
+			Status: corev1.PodStatus{
+				PodIP:      "10.0.0.7",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	It("should address the comm port of ready pods", func() {
+		Expect(podAgentAddress(readyPod(), agent)).To(Equal("10.0.0.7:8080"))
+
+		pod := readyPod()
+		pod.Spec.Containers[0].Ports = nil
+		Expect(podAgentAddress(pod, agent)).To(Equal("10.0.0.7:8081"))
+
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		Expect(podAgentAddress(pod, agent)).To(BeEmpty())
+	})
+
+	It("should only keep assignments of live agents", func() {
+		task := &swarmv1alpha1.SwarmTask{}
+		task.Status.AssignedAgents = []swarmv1alpha1.AssignedAgent{{Name: "swarm-coder-1", Status: assignmentAcknowledged}}
+		Expect(acknowledgedAgent(task)).To(Equal("swarm-coder-1"))
+
+		agents := []swarmv1alpha1.Agent{*agent}
+		Expect(agentAlive(agents, "swarm-coder-1")).To(BeTrue())
+		agents[0].Status.Phase = "Failed"
+		Expect(agentAlive(agents, "swarm-coder-1")).To(BeFalse())
+		Expect(agentAlive(agents, "swarm-coder-2")).To(BeFalse())
+	})
+
+	It("should carry the task to the agent", func() {
+		task := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				Type:     "coding",
+				Priority: swarmv1alpha1.HighPriority,
+				Subtasks: []swarmv1alpha1.SubtaskSpec{{Name: "compile"}, {Name: "test"}},
+			},
+		}
+		assignment := newAssignment(task, "build-github-token")
+		Expect(assignment.Task).To(Equal("build"))
+		Expect(assignment.Subtasks).To(Equal([]string{"compile", "test"}))
+		Expect(assignment.TokenSecret).To(Equal("build-github-token"))
+		Expect(taskPriorityWeight(task.Spec.Priority)).To(BeNumerically(">", 7))
+	})
+})
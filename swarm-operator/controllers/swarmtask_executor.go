@@ -0,0 +1,434 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+const (
+	// placeholderExecutorImage runs tasks when no executor image is configured
+	placeholderExecutorImage = "busybox:latest"
+
+	executorScriptsVolume    = "executor-scripts"
+	executorScriptsMountPath = "/scripts"
+
+	// progressTokenVolume projects the token executors authenticate their
+	// progress updates with
+	progressTokenVolume    = "progress-token"
+	progressTokenMountPath = "/var/run/secrets/swarm.claudeflow.io/progress"
+	progressTokenTTL       = int64(3600)
+
+	gcpCredentialsSecret    = "gcp-credentials"
+	gcpCredentialsMountPath = "/secrets/gcp"
+)
+
+// ExecutorConfig holds the executor settings that used to be specific to
+// the standalone enhanced operator
+type ExecutorConfig struct {
+	// Image runs tasks that set no executorImage. Empty keeps the
+	// placeholder container.
+	Image string
+
+	// WindowsImage runs Windows tasks that set no executorImage. Empty
+	// keeps the PowerShell placeholder container.
+	WindowsImage string
+
+	// ScriptsConfigMap holds entrypoint.sh and task.sh. When set it is
+	// mounted at /scripts and the executor runs task.sh through
+	// entrypoint.sh instead of the image entrypoint. Windows tasks run
+	// entrypoint.ps1 and task.ps1 from C:\scripts with PowerShell.
+	ScriptsConfigMap string
+
+	// CredentialSecrets injects GitHub and cloud provider credentials from
+	// the well-known secrets in the task namespace when they exist
+	CredentialSecrets bool
+
+	// ProgressURL is handed to executors as SWARM_PROGRESS_URL, the endpoint
+	// they POST executor.Progress updates to. Empty disables reporting.
+	ProgressURL string
+}
+
+// executorConfig returns the executor settings in effect, hot-reloaded from
+// the SwarmOperatorConfig when the manager follows one
+func (r *SwarmTaskReconciler) executorConfig() ExecutorConfig {
+	if r.Config == nil {
+		return r.Executor
+	}
+	settings := r.Config.Get()
+	return ExecutorConfig{
+		Image:             settings.ExecutorImage,
+		WindowsImage:      settings.ExecutorWindowsImage,
+		ScriptsConfigMap:  settings.ExecutorScriptsConfigMap,
+		CredentialSecrets: settings.InjectCredentialSecrets,
+		ProgressURL:       settings.ExecutorProgressURL,
+	}
+}
+
+// taskImageConfig merges the image config of the task over the cluster's.
+// The task's pull secrets are added to the cluster's and its mirrors take
+// precedence.
+func taskImageConfig(task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster) *swarmv1alpha1.ImageConfig {
+	var base *swarmv1alpha1.ImageConfig
+	if cluster != nil {
+		base = cluster.Spec.ImageConfig
+	}
+	override := task.Spec.ImageConfig
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		return override
+	}
+	merged := &swarmv1alpha1.ImageConfig{
+		PullSecrets: append(append([]corev1.LocalObjectReference{}, base.PullSecrets...), override.PullSecrets...),
+		PullPolicy:  base.PullPolicy,
+		Mirrors:     append(append([]swarmv1alpha1.RegistryMirror{}, override.Mirrors...), base.Mirrors...),
+	}
+	if override.PullPolicy != "" {
+		merged.PullPolicy = override.PullPolicy
+	}
+	return merged
+}
+
+// credentialSecretEnv maps the keys of a well-known credential secret to
+// executor environment variables, as {variable, key} pairs
+type credentialSecretEnv struct {
+	secret string
+	env    [][2]string
+}
+
+// credentialSecrets are looked up in the task namespace. github-credentials
+// only applies to tasks without a GitHub App token.
+var credentialSecrets = []credentialSecretEnv{
+	{secret: "github-credentials", env: [][2]string{{"GITHUB_TOKEN", "token"}, {"GITHUB_USERNAME", "username"}}},
+	{secret: "aws-credentials", env: [][2]string{
+		{"AWS_ACCESS_KEY_ID", "access-key-id"}, {"AWS_SECRET_ACCESS_KEY", "secret-access-key"}, {"AWS_DEFAULT_REGION", "region"}}},
+	{secret: "azure-credentials", env: [][2]string{
+		{"AZURE_CLIENT_ID", "client-id"}, {"AZURE_CLIENT_SECRET", "client-secret"}, {"AZURE_TENANT_ID", "tenant-id"}}},
+}
+
+// executorImage returns the image the task runs in. While the cluster rolls
+// out a new executor image a stable share of tasks picks it up. Windows
+// tasks never take part in rollouts of the Linux executor. The namespace
+// defaults take precedence over the operator's image.
+func (r *SwarmTaskReconciler) executorImage(task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, defaults *taskDefaults) string {
+	if task.Spec.ExecutorImage != "" {
+		return task.Spec.ExecutorImage
+	}
+	config := r.executorConfig()
+	if windowsTask(task) {
+		if image := defaults.executorImage(true); image != "" {
+			return image
+		}
+		if config.WindowsImage != "" {
+			return config.WindowsImage
+		}
+		return placeholderWindowsExecutorImage
+	}
+	if image, percent := executorRolloutTarget(cluster); percent > 0 && canaryTask(task, percent) {
+		return image
+	}
+	if image := defaults.executorImage(false); image != "" {
+		return image
+	}
+	if config.Image != "" {
+		return config.Image
+	}
+	return placeholderExecutorImage
+}
+
+// canaryTask reports whether the task falls into the given percent of
+// tasks, the same for every reconcile and retry of the task
+func canaryTask(task *swarmv1alpha1.SwarmTask, percent int32) bool {
+	h := fnv.New32a()
+	h.Write([]byte(task.Namespace + "/" + task.Name))
+	return int32(h.Sum32()%100) < percent
+}
+
+// recordExecutorOutcome feeds the result of a finished Job into the
+// comparison of executor images
+func (r *SwarmTaskReconciler) recordExecutorOutcome(task *swarmv1alpha1.SwarmTask, job *batchv1.Job, failed bool) {
+	if r.MetricsRecorder == nil || len(job.Spec.Template.Spec.Containers) == 0 {
+		return
+	}
+	r.MetricsRecorder.RecordExecutorOutcome(task.Namespace, task.Spec.SwarmCluster,
+		job.Spec.Template.Spec.Containers[0].Image, failed)
+}
+
+// collectExecutorReport picks up the report the executor left in the
+// termination message of its most recent pod: the result of a successful
+// run and the checkpoint a retry or resume continues from
+func (r *SwarmTaskReconciler) collectExecutorReport(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list pods for the executor report", "job", job.Name)
+		return
+	}
+	report := latestExecutorReport(pods.Items)
+	if report == nil {
+		return
+	}
+
+	if report.CheckpointRef != "" {
+		task.Status.CheckpointRef = report.CheckpointRef
+	}
+	if report.Result != nil && job.Status.Succeeded > 0 {
+		task.Status.Result = &swarmv1alpha1.TaskResult{
+			Success:    report.Result.Success,
+			Data:       report.Result.Data,
+			Summary:    report.Result.Summary,
+			StorageRef: executor.ResultFile,
+			Metrics: swarmv1alpha1.TaskMetrics{
+				ExecutionTime:  report.Result.ExecutionTime,
+				TokensConsumed: report.Result.TokensConsumed,
+			},
+		}
+	}
+}
+
+// latestExecutorReport returns the report of the newest terminated task
+// container that wrote one
+func latestExecutorReport(pods []corev1.Pod) *executor.Report {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+	})
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != "task" {
+				continue
+			}
+			terminated := cs.State.Terminated
+			if terminated == nil {
+				terminated = cs.LastTerminationState.Terminated
+			}
+			if terminated == nil {
+				continue
+			}
+			if report, ok := executor.ParseReport(terminated.Message); ok {
+				return report
+			}
+		}
+	}
+	return nil
+}
+
+// applyExecutor turns the placeholder task container into the executor:
+// image, scripts, default resources, additional secrets and credentials.
+// Without namespace defaults credentials are only looked up in the
+// namespace the Job runs in.
+func (r *SwarmTaskReconciler) applyExecutor(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string, defaults *taskDefaults, podSpec *corev1.PodSpec, githubTokenSecret string) error {
+	config := r.executorConfig()
+	container := &podSpec.Containers[0]
+	image := r.executorImage(task, cluster, defaults)
+	if image == placeholderWindowsExecutorImage {
+		container.Image = image
+		container.Command = windowsCommand(fmt.Sprintf("Write-Output %s", powerShellQuote("Executing task: "+task.Spec.Description)))
+		container.Args = nil
+	}
+	if image != placeholderExecutorImage && image != placeholderWindowsExecutorImage {
+		container.Image = image
+		container.Command = nil
+		container.Args = nil
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: executor.EnvTaskDescription, Value: task.Spec.Description},
+			corev1.EnvVar{Name: executor.EnvTaskPriority, Value: string(task.Spec.Priority)},
+		)
+		if config.ProgressURL != "" {
+			container.Env = append(container.Env,
+				corev1.EnvVar{Name: executor.EnvProgressURL, Value: config.ProgressURL},
+				corev1.EnvVar{Name: executor.EnvProgressTokenFile, Value: progressTokenMountPath + "/token"},
+			)
+			applyProgressToken(podSpec, container)
+		}
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			}
+		}
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			}
+		}
+
+		if config.ScriptsConfigMap != "" {
+			mode := int32(0755)
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: executorScriptsVolume,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: config.ScriptsConfigMap},
+						DefaultMode:          &mode,
+					},
+				},
+			})
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      executorScriptsVolume,
+				MountPath: executorScriptsMountPath,
+				ReadOnly:  true,
+			})
+			container.Command = []string{executorScriptsMountPath + "/entrypoint.sh"}
+			container.Args = []string{executorScriptsMountPath + "/task.sh"}
+			if windowsTask(task) {
+				scripts := windowsPath(executorScriptsMountPath)
+				container.Command = []string{windowsShell, "-NoProfile", "-NonInteractive", "-File", scripts + `\entrypoint.ps1`}
+				container.Args = []string{scripts + `\task.ps1`}
+			}
+		}
+	}
+
+	for i, mount := range task.Spec.AdditionalSecrets {
+		name := fmt.Sprintf("task-secret-%d", i)
+		optional := mount.Optional
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: mount.Name, Optional: &optional},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: mount.MountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	if config.CredentialSecrets {
+		tenant, err := getTenant(ctx, r, taskTenant(task, cluster))
+		if err != nil {
+			return err
+		}
+		if defaults == nil {
+			defaults = &taskDefaults{namespaces: []string{namespace}, defaults: []*swarmv1alpha1.SwarmDefaults{nil}}
+		}
+		return r.applyCredentialSecrets(ctx, task, tenant, namespace, defaults, podSpec, githubTokenSecret)
+	}
+	return nil
+}
+
+// applyProgressToken mounts a service account token bound to the pod, with
+// the audience the progress endpoint accepts
+func applyProgressToken(podSpec *corev1.PodSpec, container *corev1.Container) {
+	ttl := progressTokenTTL
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: progressTokenVolume,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          executor.ProgressAudience,
+						ExpirationSeconds: &ttl,
+						Path:              "token",
+					},
+				}},
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      progressTokenVolume,
+		MountPath: progressTokenMountPath,
+		ReadOnly:  true,
+	})
+}
+
+// applyCredentialSecrets injects the well-known credential secrets in the
+// scope of the task's tenant, from the closest namespace of the chain that
+// provides them. Those of other namespaces than the Job's are read from the
+// Job's credentials secret, which may not exist yet when the pod is
+// created, so the references are required.
+func (r *SwarmTaskReconciler) applyCredentialSecrets(ctx context.Context, task *swarmv1alpha1.SwarmTask, tenant *swarmv1alpha1.SwarmTenant, namespace string, defaults *taskDefaults, podSpec *corev1.PodSpec, githubTokenSecret string) error {
+	resolved, err := r.injectedCredentials(ctx, tenant, defaults, githubTokenSecret)
+	if err != nil {
+		return err
+	}
+	container := &podSpec.Containers[0]
+	inheritedSecret := taskCredentialsSecretName(taskJobName(task))
+
+	for _, cred := range resolved {
+		secretName, optional := cred.secret, true
+		keyName := func(key string) string { return key }
+		if cred.inherited(namespace) {
+			secretName, optional = inheritedSecret, false
+			keyName = func(key string) string { return inheritedCredentialKey(cred.name, key) }
+		}
+
+		// Google credentials are a key file rather than environment variables
+		if cred.name == gcpCredentialsSecret {
+			if _, ok := cred.data["key.json"]; !ok && !optional {
+				continue
+			}
+			volume := corev1.Volume{
+				Name: gcpCredentialsSecret,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: secretName, Optional: &optional},
+				},
+			}
+			if cred.inherited(namespace) {
+				volume.Secret.Items = []corev1.KeyToPath{{Key: keyName("key.json"), Path: "key.json"}}
+			}
+			podSpec.Volumes = append(podSpec.Volumes, volume)
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      gcpCredentialsSecret,
+				MountPath: gcpCredentialsMountPath,
+				ReadOnly:  true,
+			})
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:  "GOOGLE_APPLICATION_CREDENTIALS",
+				Value: gcpCredentialsMountPath + "/key.json",
+			})
+			continue
+		}
+
+		for _, creds := range credentialSecrets {
+			if creds.secret != cred.name {
+				continue
+			}
+			for _, pair := range creds.env {
+				// Only the keys that were copied can be required
+				if _, ok := cred.data[pair[1]]; !ok && !optional {
+					continue
+				}
+				container.Env = append(container.Env, corev1.EnvVar{
+					Name: pair[0],
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+							Key:                  keyName(pair[1]),
+							Optional:             &optional,
+						},
+					},
+				})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Task executor", func() {
+	var (
+		ctx        context.Context
+		reconciler *SwarmTaskReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+		task       *swarmv1alpha1.SwarmTask
+		podSpec    *corev1.PodSpec
+	)
+
+	envNames := func() []string {
+		var names []string
+		for _, env := range podSpec.Containers[0].Env {
+			names = append(names, env.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		secrets := []*corev1.Secret{
+			{ObjectMeta: metav1.ObjectMeta{Name: "aws-credentials", Namespace: "tasks"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "gcp-credentials", Namespace: "tasks"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "github-credentials", Namespace: "tasks"}},
+		}
+		builder := newTestClientBuilder()
+		for _, secret := range secrets {
+			builder = builder.WithObjects(secret)
+		}
+		reconciler = &SwarmTaskReconciler{Client: builder.Build(), Scheme: testScheme}
+
+		cluster = &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "swarm"}}
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "deploy"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				Description: "deploy the stack",
+				AdditionalSecrets: []swarmv1alpha1.TaskSecretMount{
+					{Name: "db-credentials", MountPath: "/secrets/db", Optional: true},
+				},
+			},
+		}
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{
+			Name:    "task",
+			Image:   placeholderExecutorImage,
+			Command: []string{"/bin/sh", "-c"},
+		}}}
+	})
+
+	It("keeps the placeholder container without an executor image", func() {
+		Expect(reconciler.applyExecutor(ctx, task, cluster, "tasks", nil, podSpec, "")).To(Succeed())
+
+		Expect(podSpec.Containers[0].Image).To(Equal(placeholderExecutorImage))
+		Expect(podSpec.Containers[0].Command).To(Equal([]string{"/bin/sh", "-c"}))
+		Expect(podSpec.Volumes).To(HaveLen(1))
+		Expect(*podSpec.Volumes[0].Secret.Optional).To(BeTrue())
+		Expect(podSpec.Containers[0].VolumeMounts[0].MountPath).To(Equal("/secrets/db"))
+		Expect(envNames()).To(BeEmpty())
+	})
+
+	It("runs the task image through the executor scripts", func() {
+		reconciler.Executor = ExecutorConfig{Image: "claude-flow/swarm-executor:latest", ScriptsConfigMap: "swarm-task-scripts"}
+		task.Spec.ExecutorImage = "example.com/terraform:1.8"
+
+		Expect(reconciler.applyExecutor(ctx, task, cluster, "tasks", nil, podSpec, "")).To(Succeed())
+
+		container := podSpec.Containers[0]
+		Expect(container.Image).To(Equal("example.com/terraform:1.8"))
+		Expect(container.Command).To(Equal([]string{"/scripts/entrypoint.sh"}))
+		Expect(container.Args).To(Equal([]string{"/scripts/task.sh"}))
+		Expect(container.Resources.Limits).NotTo(BeEmpty())
+		Expect(envNames()).To(ContainElement("SWARM_TASK_DESCRIPTION"))
+	})
+
+	It("injects the credential secrets that exist", func() {
+		reconciler.Executor.CredentialSecrets = true
+
+		Expect(reconciler.applyExecutor(ctx, task, cluster, "tasks", nil, podSpec, "")).To(Succeed())
+
+		Expect(envNames()).To(ContainElements("AWS_ACCESS_KEY_ID", "GITHUB_TOKEN", "GOOGLE_APPLICATION_CREDENTIALS"))
+		Expect(envNames()).NotTo(ContainElement("AZURE_CLIENT_ID"))
+	})
+
+	It("prefers the GitHub App token over github-credentials", func() {
+		reconciler.Executor.CredentialSecrets = true
+
+		Expect(reconciler.applyExecutor(ctx, task, cluster, "tasks", nil, podSpec, "deploy-github-token")).To(Succeed())
+
+		Expect(envNames()).NotTo(ContainElement("GITHUB_TOKEN"))
+	})
+	It("passes the progress endpoint to executor images", func() {
+		reconciler.Executor = ExecutorConfig{Image: "claude-flow/swarm-executor:latest", ProgressURL: "http://progress.swarm.svc/v1/progress"}
+
+		Expect(reconciler.applyExecutor(ctx, task, cluster, "tasks", nil, podSpec, "")).To(Succeed())
+
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+			Name: executor.EnvProgressURL, Value: "http://progress.swarm.svc/v1/progress",
+		}))
+		Expect(envNames()).To(ContainElement(executor.EnvProgressTokenFile))
+		var token *corev1.Volume
+		for i := range podSpec.Volumes {
+			if podSpec.Volumes[i].Name == progressTokenVolume {
+				token = &podSpec.Volumes[i]
+			}
+		}
+		Expect(token).NotTo(BeNil())
+		Expect(token.Projected.Sources[0].ServiceAccountToken.Audience).To(Equal(executor.ProgressAudience))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name: progressTokenVolume, MountPath: progressTokenMountPath, ReadOnly: true,
+		}))
+	})
+
+	It("reads the report of the newest task container", func() {
+		terminated := func(name string, age time.Duration, message string) corev1.Pod {
+			return corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(time.Now().Add(-age))},
+				Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "task",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}},
+				}}},
+			}
+		}
+		older, err := executor.EncodeReport(&executor.Report{CheckpointRef: ".swarm/checkpoints/1.json"})
+		Expect(err).NotTo(HaveOccurred())
+		newer, err := executor.EncodeReport(&executor.Report{
+			Result:        &executor.Result{Success: true, Summary: "applied"},
+			CheckpointRef: ".swarm/checkpoints/2.json",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := latestExecutorReport([]corev1.Pod{
+			terminated("deploy-job-a", 2*time.Minute, string(older)),
+			terminated("deploy-job-b", time.Minute, string(newer)),
+			terminated("deploy-job-c", 0, "error: exit status 1\n"),
+		})
+		Expect(report).NotTo(BeNil())
+		Expect(report.CheckpointRef).To(Equal(".swarm/checkpoints/2.json"))
+		Expect(report.Result.Summary).To(Equal("applied"))
+
+		Expect(latestExecutorReport([]corev1.Pod{terminated("deploy-job-c", 0, "log tail")})).To(BeNil())
+	})
+
+	It("merges the image config of the task over the cluster's", func() {
+		cluster.Spec.ImageConfig = &swarmv1alpha1.ImageConfig{
+			PullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}},
+			PullPolicy:  corev1.PullIfNotPresent,
+			Mirrors:     []swarmv1alpha1.RegistryMirror{{Registry: "docker.io", Mirror: "mirror.example.com/hub"}},
+		}
+		Expect(taskImageConfig(task, cluster)).To(BeIdenticalTo(cluster.Spec.ImageConfig))
+
+		task.Spec.ImageConfig = &swarmv1alpha1.ImageConfig{
+			PullSecrets: []corev1.LocalObjectReference{{Name: "team-creds"}},
+			PullPolicy:  corev1.PullAlways,
+			Mirrors:     []swarmv1alpha1.RegistryMirror{{Registry: "docker.io", Mirror: "team.example.com/hub"}},
+		}
+		Expect(taskImageConfig(task, cluster)).To(Equal(&swarmv1alpha1.ImageConfig{
+			PullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}, {Name: "team-creds"}},
+			PullPolicy:  corev1.PullAlways,
+			Mirrors: []swarmv1alpha1.RegistryMirror{
+				{Registry: "docker.io", Mirror: "team.example.com/hub"},
+				{Registry: "docker.io", Mirror: "mirror.example.com/hub"},
+			},
+		}))
+	})
+})
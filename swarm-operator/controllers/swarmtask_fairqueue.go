@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/fairqueue"
+)
+
+const (
+	// ConditionTypeFairQueued reports that the task waits for a dispatch
+	// slot of its cluster's fair queuing
+	ConditionTypeFairQueued = "FairQueued"
+
+	ReasonQueued     = "Queued"
+	ReasonDispatched = "Dispatched"
+
+	// defaultFairQueueKey is the queue of tasks without the key label
+	defaultFairQueueKey = "default"
+
+	// fairQueueInterval is how often queued tasks look for a free slot,
+	// finishing tasks do not wake them
+	fairQueueInterval = 10 * time.Second
+)
+
+// fairQueuingEnabled reports whether the cluster dispatches its tasks by
+// weighted fair queuing
+func fairQueuingEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	fq := cluster.Spec.TaskDistribution.FairQueuing
+	return fq != nil && fq.Enabled
+}
+
+// fairQueueKey returns the queue of a task
+func fairQueueKey(task *swarmv1alpha1.SwarmTask, fq *swarmv1alpha1.FairQueuingSpec) string {
+	if key := task.Labels[fq.KeyLabel]; key != "" {
+		return key
+	}
+	return defaultFairQueueKey
+}
+
+// fairQueueWeight returns the weight of a queue
+func fairQueueWeight(fq *swarmv1alpha1.FairQueuingSpec, key string) int32 {
+	if weight, ok := fq.Weights[key]; ok {
+		return weight
+	}
+	if fq.DefaultWeight > 0 {
+		return fq.DefaultWeight
+	}
+	return 1
+}
+
+// fairQueueCapacity returns how many tasks of the cluster may be in flight
+func fairQueueCapacity(cluster *swarmv1alpha1.SwarmCluster) int {
+	if limit := cluster.Spec.TaskDistribution.FairQueuing.MaxInFlight; limit > 0 {
+		return int(limit)
+	}
+	agents, perAgent := cluster.Spec.MaxAgents, cluster.Spec.TaskDistribution.MaxTasksPerAgent
+	if agents <= 0 {
+		agents = 5
+	}
+	if perAgent <= 0 {
+		perAgent = 10
+	}
+	return int(agents * perAgent)
+}
+
+// taskDispatched reports whether the task got a Job or an agent
+func taskDispatched(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Status.StartTime != nil || task.Status.JobName != "" || len(task.Status.AssignedAgents) > 0
+}
+
+// awaitFairShare holds the task until fair queuing hands it a dispatch
+// slot. The unfinished dispatched tasks of the cluster take the slots, the
+// free ones go to the queued tasks in the order of fairqueue.Order. Tasks
+// dispatched by fair queuing are numbered so queues with equal shares take
+// turns. It reports whether the task was held.
+func (r *SwarmTaskReconciler) awaitFairShare(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster) (bool, error) {
+	if !fairQueuingEnabled(cluster) || serviceTask(task) || taskDispatched(task) || taskFinished(task) {
+		return false, nil
+	}
+	fq := cluster.Spec.TaskDistribution.FairQueuing
+
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, tasks, client.InNamespace(task.Namespace)); err != nil {
+		return false, err
+	}
+	queues := map[string]fairqueue.Queue{}
+	total, sequence := 0, int64(0)
+	waiting := []fairqueue.Item{{Key: fairQueueKey(task, fq), Name: task.Name, Enqueued: task.CreationTimestamp.Time}}
+	depths := map[string]int{}
+	for i := range tasks.Items {
+		other := &tasks.Items[i]
+		if other.Name == task.Name || other.Spec.SwarmCluster != cluster.Name || serviceTask(other) {
+			continue
+		}
+		key := fairQueueKey(other, fq)
+		queue := queues[key]
+		if other.Status.DispatchSequence > queue.LastServed {
+			queue.LastServed = other.Status.DispatchSequence
+		}
+		if other.Status.DispatchSequence > sequence {
+			sequence = other.Status.DispatchSequence
+		}
+		switch {
+		case taskFinished(other):
+		case taskDispatched(other):
+			queue.InFlight++
+			total++
+		case meta.IsStatusConditionTrue(other.Status.Conditions, ConditionTypeFairQueued):
+			waiting = append(waiting, fairqueue.Item{Key: key, Name: other.Name, Enqueued: other.CreationTimestamp.Time})
+			depths[key]++
+		}
+		queues[key] = queue
+	}
+
+	order := fairqueue.Order(waiting, queues, func(key string) int32 { return fairQueueWeight(fq, key) }, len(waiting))
+	position := fairqueue.Position(order, task.Name)
+	key := waiting[0].Key
+	queued := meta.IsStatusConditionTrue(task.Status.Conditions, ConditionTypeFairQueued)
+
+	if position < fairQueueCapacity(cluster)-total {
+		if r.MetricsRecorder != nil {
+			r.MetricsRecorder.RecordFairQueueDepth(task.Namespace, cluster.Name, depths)
+		}
+		changed := meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeFairQueued,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonDispatched,
+			Message:            fmt.Sprintf("Dispatched from queue %s", key),
+			ObservedGeneration: task.Generation,
+		})
+		if !changed {
+			return false, nil
+		}
+		task.Status.DispatchSequence = sequence + 1
+		if err := r.Status().Update(ctx, task); err != nil {
+			return false, err
+		}
+		if r.MetricsRecorder != nil {
+			r.MetricsRecorder.RecordFairQueueDispatch(task.Namespace, cluster.Name, key, time.Since(task.CreationTimestamp.Time).Seconds())
+		}
+		return false, nil
+	}
+
+	depths[key]++
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.RecordFairQueueDepth(task.Namespace, cluster.Name, depths)
+	}
+	message := fmt.Sprintf("Waiting in queue %s, position %d of %d", key, position+1, len(order))
+	if task.Status.Phase == "" {
+		task.Status.Phase = "Pending"
+	}
+	changed := meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeFairQueued,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonQueued,
+		Message:            message,
+		ObservedGeneration: task.Generation,
+	})
+	if !changed {
+		return true, nil
+	}
+	if err := r.Status().Update(ctx, task); err != nil {
+		return false, err
+	}
+	if !queued {
+		r.Recorder.Event(task, corev1.EventTypeNormal, ReasonQueued, message)
+	}
+	return true, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Fair queuing", func() {
+	var (
+		ctx        context.Context
+		reconciler *SwarmTaskReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+		created    time.Time
+	)
+
+	newTask := func(name, team string) *swarmv1alpha1.SwarmTask {
+		created = created.Add(time.Minute)
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: "default",
+				Labels:            map[string]string{"team": team},
+				CreationTimestamp: metav1.Time{Time: created},
+			},
+			Spec: swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", Description: "Run " + name},
+		}
+	}
+	setup := func(tasks ...*swarmv1alpha1.SwarmTask) {
+		builder := newTestClientBuilder()
+		for _, task := range tasks {
+			builder = builder.WithObjects(task)
+		}
+		reconciler = &SwarmTaskReconciler{Client: builder.Build(), Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	}
+	// await runs the fair queuing gate on the stored task, the way the
+	// reconciler would, and marks dispatched tasks as running
+	await := func(name string) bool {
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, task)).To(Succeed())
+		held, err := reconciler.awaitFairShare(ctx, task, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		if !held {
+			task.Status.Phase = "Running"
+			task.Status.JobName = name
+			Expect(reconciler.Status().Update(ctx, task)).To(Succeed())
+		}
+		return held
+	}
+	finish := func(name string) {
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, task)).To(Succeed())
+		task.Status.Phase = "Completed"
+		Expect(reconciler.Status().Update(ctx, task)).To(Succeed())
+	}
+	// next runs the gate for every pending task and returns the one
+	// dispatched
+	next := func(names ...string) string {
+		var dispatched []string
+		for _, name := range names {
+			task := &swarmv1alpha1.SwarmTask{}
+			Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, task)).To(Succeed())
+			if task.Status.Phase == "Pending" && !await(name) {
+				dispatched = append(dispatched, name)
+			}
+		}
+		Expect(dispatched).To(HaveLen(1))
+		return dispatched[0]
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		created = time.Now().Add(-time.Hour)
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{TaskDistribution: swarmv1alpha1.TaskDistributionSpec{
+				FairQueuing: &swarmv1alpha1.FairQueuingSpec{Enabled: true, KeyLabel: "team", MaxInFlight: 1},
+			}},
+		}
+	})
+
+	It("interleaves a burst of one team with the tasks of another", func() {
+		names := []string{"batch-1", "batch-2", "batch-3", "web-1", "web-2"}
+		setup(newTask("batch-1", "batch"), newTask("batch-2", "batch"), newTask("batch-3", "batch"),
+			newTask("web-1", "web"), newTask("web-2", "web"))
+		Expect(await("batch-1")).To(BeFalse())
+		for _, name := range names[1:] {
+			Expect(await(name)).To(BeTrue())
+		}
+
+		running := "batch-1"
+		var dispatched []string
+		for i := 0; i < 4; i++ {
+			finish(running)
+			running = next(names...)
+			dispatched = append(dispatched, running)
+		}
+		Expect(dispatched).To(Equal([]string{"web-1", "batch-2", "web-2", "batch-3"}))
+	})
+
+	It("reports the queue and position of held tasks", func() {
+		setup(newTask("running", "web"), newTask("batch-1", "batch"), newTask("web-1", "web"))
+		Expect(await("running")).To(BeFalse())
+		Expect(await("batch-1")).To(BeTrue())
+		Expect(await("web-1")).To(BeTrue())
+
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "web-1", Namespace: "default"}, task)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Pending"))
+		condition := meta.FindStatusCondition(task.Status.Conditions, ConditionTypeFairQueued)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(Equal("Waiting in queue web, position 2 of 2"))
+
+		finish("running")
+		Expect(await("batch-1")).To(BeFalse())
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "batch-1", Namespace: "default"}, task)).To(Succeed())
+		Expect(meta.FindStatusCondition(task.Status.Conditions, ConditionTypeFairQueued).Reason).To(Equal(ReasonDispatched))
+		Expect(task.Status.DispatchSequence).To(Equal(int64(2)))
+	})
+
+	It("gives heavier queues more of the slots", func() {
+		fq := cluster.Spec.TaskDistribution.FairQueuing
+		fq.MaxInFlight = 6
+		fq.Weights = map[string]int32{"batch": 2}
+		var tasks []*swarmv1alpha1.SwarmTask
+		for _, name := range []string{"batch-1", "batch-2", "batch-3", "batch-4", "web-1", "web-2", "web-3"} {
+			task := newTask(name, name[:len(name)-2])
+			task.Status.Phase = "Pending"
+			meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+				Type: ConditionTypeFairQueued, Status: metav1.ConditionTrue, Reason: ReasonQueued,
+			})
+			tasks = append(tasks, task)
+		}
+		setup(tasks...)
+
+		Expect(await("web-3")).To(BeTrue())
+		for _, name := range []string{"batch-1", "web-1", "batch-2", "web-2", "batch-3", "batch-4"} {
+			Expect(await(name)).To(BeFalse(), name)
+		}
+		Expect(await("web-3")).To(BeTrue())
+	})
+
+	It("puts tasks without the label in the default queue", func() {
+		setup(newTask("running", "web"), newTask("unlabeled", ""))
+		Expect(await("running")).To(BeFalse())
+		Expect(await("unlabeled")).To(BeTrue())
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "unlabeled", Namespace: "default"}, task)).To(Succeed())
+		Expect(meta.FindStatusCondition(task.Status.Conditions, ConditionTypeFairQueued).Message).
+			To(Equal("Waiting in queue default, position 1 of 1"))
+	})
+
+	It("leaves clusters without fair queuing alone", func() {
+		cluster.Spec.TaskDistribution.FairQueuing.Enabled = false
+		setup(newTask("running", "web"), newTask("web-1", "web"))
+		Expect(await("running")).To(BeFalse())
+		Expect(await("web-1")).To(BeFalse())
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+)
+
+// fallbackPatternType groups the outcomes of fallback stages in the pattern
+// table of the memory store
+const fallbackPatternType = "agent-fallback"
+
+// fallbackStatus returns the stage the task runs in. Tasks that have not
+// escalated yet are in stage 0 on their preferred agent type.
+func fallbackStatus(task *swarmv1alpha1.SwarmTask) *swarmv1alpha1.FallbackStatus {
+	if task.Status.Fallback != nil {
+		return task.Status.Fallback
+	}
+	return &swarmv1alpha1.FallbackStatus{AgentType: preferredAgentType(task)}
+}
+
+// stageMaxRetries is the number of retries the current stage gets before
+// the task moves on. Stages without their own limit use the retry policy's.
+func stageMaxRetries(task *swarmv1alpha1.SwarmTask) int32 {
+	var retries int32
+	if task.Spec.RetryPolicy != nil {
+		retries = task.Spec.RetryPolicy.MaxRetries
+	}
+	if stage := fallbackStatus(task).Stage; stage > 0 && task.Spec.FallbackStrategy != nil {
+		if limit := task.Spec.FallbackStrategy.Stages[stage-1].MaxRetries; limit != nil {
+			retries = *limit
+		}
+	}
+	return retries
+}
+
+// advanceFallback moves the task to the next stage of its fallback chain,
+// returning false once the chain is exhausted. The caller persists the
+// status.
+func advanceFallback(task *swarmv1alpha1.SwarmTask) bool {
+	strategy := task.Spec.FallbackStrategy
+	if strategy == nil {
+		return false
+	}
+	current := fallbackStatus(task)
+	if int(current.Stage) >= len(strategy.Stages) {
+		return false
+	}
+	task.Status.Fallback = &swarmv1alpha1.FallbackStatus{
+		Stage:     current.Stage + 1,
+		AgentType: strategy.Stages[current.Stage].AgentType,
+	}
+	return true
+}
+
+// finishFallback records the final outcome of a task with a fallback chain
+func finishFallback(task *swarmv1alpha1.SwarmTask, outcome string) {
+	if task.Spec.FallbackStrategy == nil {
+		return
+	}
+	status := *fallbackStatus(task)
+	status.Outcome = outcome
+	task.Status.Fallback = &status
+}
+
+// recordFallbackOutcome feeds the success or failure of a stage into the
+// pattern table of the cluster's memory store, where it scores the agent
+// type for future tasks of the same type. Recording is best effort.
+func (r *SwarmTaskReconciler) recordFallbackOutcome(ctx context.Context, task *swarmv1alpha1.SwarmTask, stage *swarmv1alpha1.FallbackStatus, success bool) {
+	if task.Spec.FallbackStrategy == nil {
+		return
+	}
+	log := log.FromContext(ctx)
+
+	backend, err := r.patternBackend(ctx, task)
+	if err != nil {
+		log.Error(err, "Failed to find the memory store for fallback patterns")
+		return
+	}
+	if backend == nil {
+		return
+	}
+
+	outcome := &memorycache.PatternOutcome{
+		PatternID: fmt.Sprintf("fallback:%s:%s", task.Spec.Type, stage.AgentType),
+		SwarmID:   task.Spec.SwarmCluster,
+		Type:      fallbackPatternType,
+		Success:   success,
+		Data: map[string]string{
+			"taskType":  task.Spec.Type,
+			"agentType": string(stage.AgentType),
+			"stage":     strconv.Itoa(int(stage.Stage)),
+			"task":      task.Namespace + "/" + task.Name,
+		},
+	}
+	if err := backend.RecordPatternOutcome(ctx, outcome); err != nil {
+		log.Error(err, "Failed to record fallback outcome", "pattern", outcome.PatternID)
+	}
+}
+
+// patternBackend returns the pattern table of the first SwarmMemoryStore of
+// the task's cluster that publishes an HTTP endpoint, or nil if there is none
+func (r *SwarmTaskReconciler) patternBackend(ctx context.Context, task *swarmv1alpha1.SwarmTask) (memorycache.PatternBackend, error) {
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := r.List(ctx, stores, client.InNamespace(task.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range stores.Items {
+		store := &stores.Items[i]
+		if store.Spec.SwarmClusterRef != task.Spec.SwarmCluster || store.Status.Endpoints.HTTP == "" {
+			continue
+		}
+		var backend memorycache.Backend = memorycache.NewHTTPBackend(store.Status.Endpoints.HTTP)
+		if r.NewMemoryBackend != nil {
+			backend = r.NewMemoryBackend(store.Status.Endpoints.HTTP)
+		}
+		patterns, _ := backend.(memorycache.PatternBackend)
+		return patterns, nil
+	}
+	return nil, nil
+}
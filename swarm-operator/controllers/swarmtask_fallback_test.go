@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/memorycache"
+)
+
+type patternBackendStub struct {
+	memoryBackendStub
+	outcomes []*memorycache.PatternOutcome
+}
+
+func (b *patternBackendStub) RecordPatternOutcome(_ context.Context, outcome *memorycache.PatternOutcome) error {
+	b.outcomes = append(b.outcomes, outcome)
+	return nil
+}
+
+var _ = Describe("Task fallback strategy", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		backend    *patternBackendStub
+		reconciler *SwarmTaskReconciler
+	)
+
+	job := func(failed bool) *batchv1.Job {
+		j := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "default"}}
+		if failed {
+			j.Status.Failed = 1
+		} else {
+			j.Status.Succeeded = 1
+		}
+		return j
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		one := int32(1)
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "refactor", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster:        "swarm",
+				Type:                "refactor",
+				PreferredAgentTypes: []swarmv1alpha1.AgentType{swarmv1alpha1.CoderAgent},
+				FallbackStrategy: &swarmv1alpha1.FallbackStrategy{Stages: []swarmv1alpha1.FallbackStage{
+					{AgentType: swarmv1alpha1.SpecialistAgent, MaxRetries: &one},
+					{AgentType: swarmv1alpha1.CoordinatorAgent},
+				}},
+			},
+		}
+		store := &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "memory", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "swarm"},
+			Status: swarmv1alpha1.SwarmMemoryStoreStatus{
+				Endpoints: swarmv1alpha1.SwarmMemoryEndpoints{HTTP: "http://memory.default.svc:8080"},
+			},
+		}
+		backend = &patternBackendStub{}
+
+		reconciler = &SwarmTaskReconciler{
+			Client:           newTestClient(task, store),
+			Scheme:           testScheme,
+			Recorder:         record.NewFakeRecorder(10),
+			NewMemoryBackend: func(string) memorycache.Backend { return backend },
+		}
+	})
+
+	It("walks the chain on failures and records which stage succeeded", func() {
+		Expect(reconciler.handleJobFailure(ctx, task, job(true), nil)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Pending"))
+		Expect(task.Status.Fallback).To(Equal(&swarmv1alpha1.FallbackStatus{Stage: 1, AgentType: swarmv1alpha1.SpecialistAgent}))
+		Expect(taskAgentType(task)).To(Equal(swarmv1alpha1.SpecialistAgent))
+
+		// The specialist stage retries once before escalating
+		Expect(reconciler.handleJobFailure(ctx, task, job(true), nil)).To(Succeed())
+		Expect(task.Status.RetryCount).To(BeEquivalentTo(1))
+		Expect(task.Status.Fallback.Stage).To(BeEquivalentTo(1))
+		Expect(reconciler.handleJobFailure(ctx, task, job(true), nil)).To(Succeed())
+		Expect(task.Status.Fallback.AgentType).To(Equal(swarmv1alpha1.CoordinatorAgent))
+		Expect(task.Status.RetryCount).To(BeZero())
+		Expect(task.Status.Attempt).To(BeEquivalentTo(3))
+
+		Expect(reconciler.updateTaskStatus(ctx, task, job(false), nil)).To(Succeed())
+		Expect(task.Status.Fallback.Outcome).To(Equal(swarmv1alpha1.FallbackSucceeded))
+		Expect(task.Status.Fallback.Stage).To(BeEquivalentTo(2))
+
+		Expect(backend.outcomes).To(HaveLen(3))
+		Expect(backend.outcomes[0].PatternID).To(Equal("fallback:refactor:coder"))
+		Expect(backend.outcomes[0].Success).To(BeFalse())
+		Expect(backend.outcomes[2].PatternID).To(Equal("fallback:refactor:coordinator"))
+		Expect(backend.outcomes[2].Success).To(BeTrue())
+		Expect(backend.outcomes[2].Data).To(HaveKeyWithValue("stage", "2"))
+	})
+
+	It("fails the task once every stage is exhausted", func() {
+		task.Status.Fallback = &swarmv1alpha1.FallbackStatus{Stage: 2, AgentType: swarmv1alpha1.CoordinatorAgent}
+		Expect(reconciler.handleJobFailure(ctx, task, job(true), nil)).To(Succeed())
+
+		Expect(task.Status.Phase).To(Equal("Failed"))
+		Expect(task.Status.Fallback.Outcome).To(Equal(swarmv1alpha1.FallbackExhausted))
+		Expect(backend.outcomes).To(HaveLen(1))
+		Expect(backend.outcomes[0].Success).To(BeFalse())
+	})
+
+	It("rejects stages that repeat the failing agent type", func() {
+		task.Spec.FallbackStrategy.Stages[0].AgentType = swarmv1alpha1.CoderAgent
+		errs := swarmv1alpha1.ValidateFallbackStrategy(&task.Spec, field.NewPath("spec", "fallbackStrategy"))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.fallbackStrategy.stages[0].agentType"))
+	})
+})
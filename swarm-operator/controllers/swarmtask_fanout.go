@@ -0,0 +1,253 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// fanOutLabel names the task selecting clusters a child task was
+	// created for
+	fanOutLabel = "swarm.claudeflow.io/fan-out"
+
+	// ConditionTypeClustersSelected reports whether the cluster selector of
+	// a task matched any cluster
+	ConditionTypeClustersSelected = "ClustersSelected"
+
+	ReasonClustersMatched        = "ClustersMatched"
+	ReasonNoMatchingClusters     = "NoMatchingClusters"
+	ReasonInvalidClusterSelector = "InvalidClusterSelector"
+
+	// fanOutRetryInterval is how long a task whose selector matches no
+	// cluster waits before looking again
+	fanOutRetryInterval = time.Minute
+)
+
+// reconcileFanOut runs a task selecting clusters as a child task on every
+// matching cluster and aggregates the phases and results of the children
+func (r *SwarmTaskReconciler) reconcileFanOut(ctx context.Context, task *swarmv1alpha1.SwarmTask) (ctrl.Result, error) {
+	if taskFinished(task) {
+		return ctrl.Result{}, nil
+	}
+	before := task.Status.DeepCopy()
+
+	selector, err := metav1.LabelSelectorAsSelector(task.Spec.ClusterSelector)
+	if err != nil {
+		task.Status.Phase = "Failed"
+		task.Status.Message = fmt.Sprintf("Invalid cluster selector: %v", err)
+		meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+			Type:    ConditionTypeClustersSelected,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonInvalidClusterSelector,
+			Message: task.Status.Message,
+		})
+		r.Recorder.Event(task, corev1.EventTypeWarning, ReasonInvalidClusterSelector, task.Status.Message)
+		return ctrl.Result{}, r.Status().Update(ctx, task)
+	}
+
+	clusters := &swarmv1alpha1.SwarmClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(task.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+	children := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, children, client.InNamespace(task.Namespace), client.MatchingLabels{fanOutLabel: task.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	byCluster := map[string]*swarmv1alpha1.SwarmTask{}
+	for i := range children.Items {
+		child := &children.Items[i]
+		if metav1.IsControlledBy(child, task) {
+			byCluster[child.Spec.SwarmCluster] = child
+		}
+	}
+
+	var created []string
+	for i := range clusters.Items {
+		cluster := clusters.Items[i].Name
+		if byCluster[cluster] != nil {
+			continue
+		}
+		child, err := r.createClusterTask(ctx, task, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		byCluster[cluster] = child
+		created = append(created, cluster)
+	}
+	if len(created) > 0 {
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "ClusterTasksCreated",
+			"Created tasks on clusters %s", strings.Join(created, ", "))
+	}
+
+	if len(byCluster) == 0 {
+		task.Status.Phase = "Pending"
+		task.Status.Message = "No SwarmCluster matches the cluster selector"
+		meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+			Type:    ConditionTypeClustersSelected,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonNoMatchingClusters,
+			Message: task.Status.Message,
+		})
+		if !equality.Semantic.DeepEqual(before, &task.Status) {
+			if err := r.Status().Update(ctx, task); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: fanOutRetryInterval}, nil
+	}
+
+	meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+		Type:    ConditionTypeClustersSelected,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonClustersMatched,
+		Message: fmt.Sprintf("Running on %d clusters", len(byCluster)),
+	})
+	aggregateClusterTasks(task, byCluster)
+	if taskFinished(task) {
+		eventType := corev1.EventTypeNormal
+		if task.Status.Phase == "Failed" {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(task, eventType, "ClusterTasks"+task.Status.Phase, task.Status.Result.Summary)
+	}
+
+	if equality.Semantic.DeepEqual(before, &task.Status) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.Status().Update(ctx, task)
+}
+
+// createClusterTask creates the child task of a task selecting clusters on
+// one cluster
+func (r *SwarmTaskReconciler) createClusterTask(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster string) (*swarmv1alpha1.SwarmTask, error) {
+	spec := task.Spec.DeepCopy()
+	spec.SwarmCluster = cluster
+	spec.ClusterSelector = nil
+
+	child := &swarmv1alpha1.SwarmTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", task.Name, cluster),
+			Namespace: task.Namespace,
+			Labels:    map[string]string{fanOutLabel: task.Name},
+		},
+		Spec: *spec,
+	}
+	if err := controllerutil.SetControllerReference(task, child, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, child); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		// Created by an earlier reconcile the cache has not caught up with
+		if err := r.Get(ctx, types.NamespacedName{Name: child.Name, Namespace: child.Namespace}, child); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(child, task) {
+			return nil, fmt.Errorf("task %s already exists and does not belong to this task", child.Name)
+		}
+	}
+	return child, nil
+}
+
+// aggregateClusterTasks sets the phase, progress and result of a task
+// selecting clusters from its children. It fails once every child finished
+// and any of them failed. Result data is keyed by cluster, "<cluster>/<key>".
+func aggregateClusterTasks(task *swarmv1alpha1.SwarmTask, children map[string]*swarmv1alpha1.SwarmTask) {
+	clusters := make([]string, 0, len(children))
+	for cluster := range children {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	status := &task.Status
+	status.Clusters = nil
+	result := &swarmv1alpha1.TaskResult{Data: map[string]string{}}
+	var succeeded, failed int
+	var progress int32
+	for _, cluster := range clusters {
+		child := children[cluster]
+		clusterStatus := swarmv1alpha1.ClusterTaskStatus{Cluster: cluster, Task: child.Name, Phase: child.Status.Phase}
+		switch child.Status.Phase {
+		case "Completed", taskPhaseSkipped:
+			succeeded++
+			progress += 100
+			if childResult := child.Status.Result; childResult != nil {
+				clusterStatus.Summary = childResult.Summary
+				for key, value := range childResult.Data {
+					result.Data[cluster+"/"+key] = value
+				}
+				sum := &result.Metrics
+				if childResult.Metrics.ExecutionTime > sum.ExecutionTime {
+					sum.ExecutionTime = childResult.Metrics.ExecutionTime
+				}
+				sum.AgentsUsed += childResult.Metrics.AgentsUsed
+				sum.SubtasksCompleted += childResult.Metrics.SubtasksCompleted
+				sum.TokensConsumed += childResult.Metrics.TokensConsumed
+				sum.CostEstimate += childResult.Metrics.CostEstimate
+			}
+		case "Failed", taskPhaseCancelled, taskPhaseDeadLettered:
+			failed++
+			progress += 100
+			clusterStatus.Summary = child.Status.Message
+		default:
+			progress += child.Status.Progress
+		}
+		status.Clusters = append(status.Clusters, clusterStatus)
+	}
+	status.Progress = progress / int32(len(clusters))
+	if status.StartTime == nil {
+		now := metav1.Now()
+		status.StartTime = &now
+	}
+
+	if succeeded+failed < len(clusters) {
+		status.Phase = "Running"
+		status.Message = fmt.Sprintf("%d of %d cluster tasks finished", succeeded+failed, len(clusters))
+		return
+	}
+	result.Success = failed == 0
+	result.Summary = fmt.Sprintf("%d of %d cluster tasks succeeded", succeeded, len(clusters))
+	if len(result.Data) == 0 {
+		result.Data = nil
+	}
+	status.Result = result
+	status.Message = result.Summary
+	status.Phase = "Completed"
+	if failed > 0 {
+		status.Phase = "Failed"
+	}
+	now := metav1.Now()
+	status.CompletionTime = &now
+}
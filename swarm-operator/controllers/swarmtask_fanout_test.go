@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Tasks selecting clusters", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		reconciler *SwarmTaskReconciler
+	)
+
+	key := types.NamespacedName{Name: "audit", Namespace: "default"}
+
+	cluster := func(name, region string) *swarmv1alpha1.SwarmCluster {
+		return &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default", Labels: map[string]string{"region": region},
+		}}
+	}
+	build := func(objects ...client.Object) {
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClient(append(objects, task)...),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+	reconcile := func() *swarmv1alpha1.SwarmTask {
+		current := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		_, err := reconciler.reconcileFanOut(ctx, current)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		return current
+	}
+	children := func() []swarmv1alpha1.SwarmTask {
+		list := &swarmv1alpha1.SwarmTaskList{}
+		Expect(reconciler.List(ctx, list, client.MatchingLabels{fanOutLabel: "audit"})).To(Succeed())
+		return list.Items
+	}
+	finish := func(name, phase string, result *swarmv1alpha1.TaskResult) {
+		child := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, child)).To(Succeed())
+		child.Status.Phase = phase
+		child.Status.Result = result
+		child.Status.Message = "executor exited with code 1"
+		Expect(reconciler.Status().Update(ctx, child)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "audit-uid"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}},
+				Description:     "Audit dependencies",
+				Parameters:      map[string]string{"scope": "all"},
+			},
+		}
+	})
+
+	It("creates a child task on every matching cluster", func() {
+		build(cluster("eu-west", "eu"), cluster("eu-north", "eu"), cluster("us-east", "us"))
+		current := reconcile()
+
+		tasks := children()
+		Expect(tasks).To(HaveLen(2))
+		for _, child := range tasks {
+			Expect(child.Name).To(Equal("audit-" + child.Spec.SwarmCluster))
+			Expect(child.Spec.SwarmCluster).To(BeElementOf("eu-west", "eu-north"))
+			Expect(child.Spec.ClusterSelector).To(BeNil())
+			Expect(child.Spec.Parameters).To(HaveKeyWithValue("scope", "all"))
+			Expect(metav1.IsControlledBy(&child, current)).To(BeTrue())
+		}
+		Expect(current.Status.Phase).To(Equal("Running"))
+		Expect(current.Status.Clusters).To(HaveLen(2))
+		Expect(current.Status.Clusters[0].Cluster).To(Equal("eu-north"))
+		Expect(meta.IsStatusConditionTrue(current.Status.Conditions, ConditionTypeClustersSelected)).To(BeTrue())
+
+		// Another pass creates nothing new
+		reconcile()
+		Expect(children()).To(HaveLen(2))
+	})
+
+	It("aggregates the results of the cluster tasks", func() {
+		build(cluster("eu-west", "eu"), cluster("eu-north", "eu"))
+		reconcile()
+
+		finish("audit-eu-west", "Completed", &swarmv1alpha1.TaskResult{
+			Success: true, Summary: "3 outdated", Data: map[string]string{"outdated": "3"},
+			Metrics: swarmv1alpha1.TaskMetrics{ExecutionTime: 40, TokensConsumed: 1000},
+		})
+		current := reconcile()
+		Expect(current.Status.Phase).To(Equal("Running"))
+		Expect(current.Status.Progress).To(Equal(int32(50)))
+
+		finish("audit-eu-north", "Completed", &swarmv1alpha1.TaskResult{
+			Success: true, Data: map[string]string{"outdated": "0"},
+			Metrics: swarmv1alpha1.TaskMetrics{ExecutionTime: 60, TokensConsumed: 500},
+		})
+		current = reconcile()
+		Expect(current.Status.Phase).To(Equal("Completed"))
+		Expect(current.Status.CompletionTime).NotTo(BeNil())
+		Expect(current.Status.Result.Success).To(BeTrue())
+		Expect(current.Status.Result.Data).To(Equal(map[string]string{"eu-west/outdated": "3", "eu-north/outdated": "0"}))
+		Expect(current.Status.Result.Metrics.ExecutionTime).To(Equal(int64(60)))
+		Expect(current.Status.Result.Metrics.TokensConsumed).To(Equal(int64(1500)))
+		Expect(current.Status.Clusters[1].Summary).To(Equal("3 outdated"))
+	})
+
+	It("fails once every cluster task finished and one failed", func() {
+		build(cluster("eu-west", "eu"), cluster("eu-north", "eu"))
+		reconcile()
+		finish("audit-eu-west", "Failed", nil)
+		Expect(reconcile().Status.Phase).To(Equal("Running"))
+
+		finish("audit-eu-north", "Completed", nil)
+		current := reconcile()
+		Expect(current.Status.Phase).To(Equal("Failed"))
+		Expect(current.Status.Result.Success).To(BeFalse())
+		Expect(current.Status.Result.Summary).To(Equal("1 of 2 cluster tasks succeeded"))
+		Expect(current.Status.Clusters[1].Summary).To(Equal("executor exited with code 1"))
+	})
+
+	It("waits for a matching cluster", func() {
+		build(cluster("us-east", "us"))
+		current := reconcile()
+		Expect(children()).To(BeEmpty())
+		Expect(current.Status.Phase).To(Equal("Pending"))
+		Expect(meta.FindStatusCondition(current.Status.Conditions, ConditionTypeClustersSelected).Reason).To(Equal(ReasonNoMatchingClusters))
+	})
+})
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// gpuVendorResources are the whole-GPU resources advertised by each vendor's
+// device plugin. GPU nodes are commonly tainted with the same key.
+var gpuVendorResources = map[swarmv1alpha1.GPUVendor]corev1.ResourceName{
+	swarmv1alpha1.NvidiaGPU: gpuResourceName,
+	swarmv1alpha1.AMDGPU:    "amd.com/gpu",
+	swarmv1alpha1.IntelGPU:  "gpu.intel.com/i915",
+}
+
+// gpuProductLabels are the node labels each vendor's feature discovery
+// publishes the GPU product under
+var gpuProductLabels = map[swarmv1alpha1.GPUVendor]string{
+	swarmv1alpha1.NvidiaGPU: "nvidia.com/gpu.product",
+	swarmv1alpha1.AMDGPU:    "amd.com/gpu.product-name",
+}
+
+// nvidiaGPUPresentLabel is set by NVIDIA GPU feature discovery
+const nvidiaGPUPresentLabel = "nvidia.com/gpu.present"
+
+// taskGPUVendor returns the requested vendor, defaulting to nvidia
+func taskGPUVendor(gpu *swarmv1alpha1.TaskGPUSpec) swarmv1alpha1.GPUVendor {
+	if gpu.Vendor == "" {
+		return swarmv1alpha1.NvidiaGPU
+	}
+	return gpu.Vendor
+}
+
+// taskGPUCount returns the requested number of GPUs, defaulting to one
+func taskGPUCount(gpu *swarmv1alpha1.TaskGPUSpec) int64 {
+	if gpu.Count < 1 {
+		return 1
+	}
+	return int64(gpu.Count)
+}
+
+// taskGPUResource returns the extended resource a GPU request maps to
+func taskGPUResource(gpu *swarmv1alpha1.TaskGPUSpec) corev1.ResourceName {
+	switch {
+	case gpu.ResourceName != "":
+		return corev1.ResourceName(gpu.ResourceName)
+	case gpu.MIGProfile != "":
+		return corev1.ResourceName("nvidia.com/mig-" + gpu.MIGProfile)
+	case gpu.Shared:
+		return "nvidia.com/gpu.shared"
+	}
+	return gpuVendorResources[taskGPUVendor(gpu)]
+}
+
+// taskGPUNodeSelector returns the node labels a GPU task is placed by
+func taskGPUNodeSelector(gpu *swarmv1alpha1.TaskGPUSpec) map[string]string {
+	selector := map[string]string{}
+	vendor := taskGPUVendor(gpu)
+	if vendor == swarmv1alpha1.NvidiaGPU && gpu.ResourceName == "" {
+		selector[nvidiaGPUPresentLabel] = "true"
+	}
+	if label, ok := gpuProductLabels[vendor]; ok && gpu.Product != "" {
+		selector[label] = gpu.Product
+	}
+	return selector
+}
+
+// applyTaskGPU requests the task GPUs for the executor and adds the node
+// selector and tolerations GPU nodes need
+func applyTaskGPU(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) {
+	gpu := task.Spec.GPU
+	if gpu == nil {
+		return
+	}
+
+	// Extended resources cannot be overcommitted, so GPUs are requested
+	// and limited alike
+	name := taskGPUResource(gpu)
+	quantity := *resource.NewQuantity(taskGPUCount(gpu), resource.DecimalSI)
+	container := &podSpec.Containers[0]
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	container.Resources.Requests[name] = quantity
+	container.Resources.Limits[name] = quantity
+
+	for key, value := range taskGPUNodeSelector(gpu) {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		podSpec.NodeSelector[key] = value
+	}
+
+	taints := []corev1.ResourceName{gpuVendorResources[taskGPUVendor(gpu)]}
+	if name != taints[0] {
+		taints = append(taints, name)
+	}
+	for _, key := range taints {
+		podSpec.Tolerations = append(podSpec.Tolerations, corev1.Toleration{
+			Key:      string(key),
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+}
+
+// checkGPUCapacity reports why no node can run the task's GPUs, or an empty
+// string when at least one node offers enough of the requested resource.
+// Allocatable rather than free capacity is compared, so busy GPUs only
+// delay the task.
+func (r *SwarmTaskReconciler) checkGPUCapacity(ctx context.Context, task *swarmv1alpha1.SwarmTask) (string, error) {
+	gpu := task.Spec.GPU
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabels(taskGPUNodeSelector(gpu))); err != nil {
+		return "", err
+	}
+
+	name := taskGPUResource(gpu)
+	count := taskGPUCount(gpu)
+	offered := false
+	for _, node := range nodes.Items {
+		allocatable, ok := node.Status.Allocatable[name]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+		offered = true
+		if allocatable.Value() >= count {
+			return "", nil
+		}
+	}
+
+	if !offered {
+		if gpu.Product != "" {
+			return fmt.Sprintf("no node offers %s with GPU product %s", name, gpu.Product), nil
+		}
+		return fmt.Sprintf("no node offers %s", name), nil
+	}
+	return fmt.Sprintf("no node offers %d %s", count, name), nil
+}
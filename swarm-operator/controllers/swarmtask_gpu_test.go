@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task GPUs", func() {
+	gpuTask := func(gpu swarmv1alpha1.TaskGPUSpec) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{GPU: &gpu},
+		}
+	}
+
+	gpuNode := func(name string, labels map[string]string, resources corev1.ResourceList) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status:     corev1.NodeStatus{Allocatable: resources},
+		}
+	}
+
+	It("maps vendors, MIG profiles and shared GPUs to extended resources", func() {
+		Expect(taskGPUResource(&swarmv1alpha1.TaskGPUSpec{})).To(Equal(corev1.ResourceName("nvidia.com/gpu")))
+		Expect(taskGPUResource(&swarmv1alpha1.TaskGPUSpec{Vendor: swarmv1alpha1.AMDGPU})).To(Equal(corev1.ResourceName("amd.com/gpu")))
+		Expect(taskGPUResource(&swarmv1alpha1.TaskGPUSpec{Vendor: swarmv1alpha1.IntelGPU})).To(Equal(corev1.ResourceName("gpu.intel.com/i915")))
+		Expect(taskGPUResource(&swarmv1alpha1.TaskGPUSpec{MIGProfile: "1g.5gb"})).To(Equal(corev1.ResourceName("nvidia.com/mig-1g.5gb")))
+		Expect(taskGPUResource(&swarmv1alpha1.TaskGPUSpec{Shared: true})).To(Equal(corev1.ResourceName("nvidia.com/gpu.shared")))
+	})
+
+	It("requests the GPUs and tolerates GPU node taints", func() {
+		task := gpuTask(swarmv1alpha1.TaskGPUSpec{Count: 2, MIGProfile: "3g.20gb", Product: "NVIDIA-A100-SXM4-40GB"})
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applyTaskGPU(task, podSpec)
+
+		mig := corev1.ResourceName("nvidia.com/mig-3g.20gb")
+		limit := podSpec.Containers[0].Resources.Limits[mig]
+		request := podSpec.Containers[0].Resources.Requests[mig]
+		Expect(limit.Value()).To(BeEquivalentTo(2))
+		Expect(request.Value()).To(BeEquivalentTo(2))
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue("nvidia.com/gpu.product", "NVIDIA-A100-SXM4-40GB"))
+		Expect(podSpec.NodeSelector).To(HaveKeyWithValue(nvidiaGPUPresentLabel, "true"))
+		Expect(podSpec.Tolerations).To(HaveLen(2))
+	})
+
+	It("rejects MIG profiles on other vendors", func() {
+		errs := swarmv1alpha1.ValidateTaskGPU(&swarmv1alpha1.TaskGPUSpec{Vendor: swarmv1alpha1.AMDGPU, MIGProfile: "1g.5gb"},
+			field.NewPath("spec", "gpu"))
+		Expect(errs).To(HaveLen(1))
+		Expect(swarmv1alpha1.ValidateTaskGPU(&swarmv1alpha1.TaskGPUSpec{MIGProfile: "1g.5gb"}, field.NewPath("spec", "gpu"))).To(BeEmpty())
+	})
+
+	Context("checking cluster capacity", func() {
+		var reconciler *SwarmTaskReconciler
+
+		BeforeEach(func() {
+			k8sClient := newTestClientBuilder().
+				WithObjects(
+					gpuNode("a100", map[string]string{nvidiaGPUPresentLabel: "true", "nvidia.com/gpu.product": "NVIDIA-A100-SXM4-40GB"},
+						corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("4")}),
+					gpuNode("cpu", nil, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}),
+				).
+				Build()
+			reconciler = &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme}
+		})
+
+		It("accepts requests a node can satisfy", func() {
+			reason, err := reconciler.checkGPUCapacity(context.Background(), gpuTask(swarmv1alpha1.TaskGPUSpec{Count: 4}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(BeEmpty())
+		})
+
+		It("reports GPU types no node offers", func() {
+			reason, err := reconciler.checkGPUCapacity(context.Background(), gpuTask(swarmv1alpha1.TaskGPUSpec{Vendor: swarmv1alpha1.AMDGPU}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(Equal("no node offers amd.com/gpu"))
+
+			reason, err = reconciler.checkGPUCapacity(context.Background(), gpuTask(swarmv1alpha1.TaskGPUSpec{Product: "NVIDIA-H100-80GB-HBM3"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(ContainSubstring("NVIDIA-H100-80GB-HBM3"))
+		})
+
+		It("reports requests larger than any node", func() {
+			reason, err := reconciler.checkGPUCapacity(context.Background(), gpuTask(swarmv1alpha1.TaskGPUSpec{Count: 8}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reason).To(Equal("no node offers 8 nvidia.com/gpu"))
+		})
+	})
+})
@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// preStartContainerPrefix keeps preStart hooks apart from the operator's
+	// own init containers
+	preStartContainerPrefix = "pre-"
+
+	// taskHookLabel marks the Job running the postComplete hooks
+	taskHookLabel = "swarm.claudeflow.io/hook"
+)
+
+// applyTaskHooks runs the preStart hooks as init containers after the
+// repository checkout, with the environment and mounts of the task container
+func applyTaskHooks(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) {
+	if task.Spec.Hooks == nil {
+		return
+	}
+	for _, hook := range task.Spec.Hooks.PreStart {
+		podSpec.InitContainers = append(podSpec.InitContainers,
+			hookContainer(hook, preStartContainerPrefix, &podSpec.Containers[0]))
+	}
+}
+
+// hookContainer copies a hook container and prepends the environment and
+// volume mounts of the task container, so hooks see what the task sees.
+// The hook's own variables win.
+func hookContainer(hook corev1.Container, prefix string, task *corev1.Container) corev1.Container {
+	container := *hook.DeepCopy()
+	container.Name = prefix + hook.Name
+	container.Env = append(append([]corev1.EnvVar{}, task.Env...), container.Env...)
+
+	mounted := map[string]bool{}
+	for _, mount := range container.VolumeMounts {
+		mounted[mount.MountPath] = true
+	}
+	for _, mount := range task.VolumeMounts {
+		if !mounted[mount.MountPath] {
+			container.VolumeMounts = append(container.VolumeMounts, mount)
+		}
+	}
+	if container.TerminationMessagePolicy == "" {
+		container.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+	}
+	return container
+}
+
+// postCompleteHooks returns the hooks to run once the task succeeded
+func postCompleteHooks(task *swarmv1alpha1.SwarmTask) []corev1.Container {
+	if task.Spec.Hooks == nil {
+		return nil
+	}
+	return task.Spec.Hooks.PostComplete
+}
+
+// postCompletePodSpec derives the pod of the postComplete Job from the pod
+// of the task's Job. It keeps the volumes, placement and sandbox of the
+// task but none of its init containers.
+func postCompletePodSpec(task *swarmv1alpha1.SwarmTask, template *corev1.PodSpec) *corev1.PodSpec {
+	podSpec := template.DeepCopy()
+	podSpec.InitContainers = nil
+	podSpec.Containers = nil
+	for _, hook := range postCompleteHooks(task) {
+		podSpec.Containers = append(podSpec.Containers, hookContainer(hook, "", &template.Containers[0]))
+	}
+	applyTaskIsolation(task, podSpec)
+	return podSpec
+}
+
+// postCompleteJobName names the Job running the postComplete hooks of an
+// attempt
+func postCompleteJobName(job *batchv1.Job) string {
+	return job.Name + "-post"
+}
+
+// taskHooksStatus returns the hooks status of the task, initialising it
+// for tasks with hooks. It returns nil for tasks without any.
+func taskHooksStatus(task *swarmv1alpha1.SwarmTask) *swarmv1alpha1.TaskHooksStatus {
+	hooks := task.Spec.Hooks
+	if hooks == nil || (len(hooks.PreStart) == 0 && len(hooks.PostComplete) == 0) {
+		return nil
+	}
+	if task.Status.Hooks == nil {
+		task.Status.Hooks = &swarmv1alpha1.TaskHooksStatus{}
+		if len(hooks.PreStart) > 0 {
+			task.Status.Hooks.PreStart = swarmv1alpha1.HookPending
+		}
+		if len(hooks.PostComplete) > 0 {
+			task.Status.Hooks.PostComplete = swarmv1alpha1.HookPending
+		}
+	}
+	return task.Status.Hooks
+}
+
+// recordPreStartOutcome attributes a failed attempt to the preStart hooks
+// when one of their init containers is the culprit. Otherwise they ran to
+// completion before the task container failed.
+func recordPreStartOutcome(task *swarmv1alpha1.SwarmTask, details *swarmv1alpha1.FailureDetails) {
+	status := taskHooksStatus(task)
+	if status == nil || len(task.Spec.Hooks.PreStart) == 0 {
+		return
+	}
+	switch {
+	case details.Hook == swarmv1alpha1.PreStartHook:
+		status.PreStart = swarmv1alpha1.HookFailed
+	case len(details.Containers) > 0 && !details.Containers[0].InitContainer:
+		status.PreStart = swarmv1alpha1.HookSucceeded
+	}
+}
+
+// finishPreStartHooks marks the preStart hooks succeeded once the task's
+// Job did
+func finishPreStartHooks(task *swarmv1alpha1.SwarmTask) {
+	if status := taskHooksStatus(task); status != nil && status.PreStart != "" {
+		status.PreStart = swarmv1alpha1.HookSucceeded
+	}
+}
+
+// isPreStartContainer reports whether an init container runs a preStart hook
+func isPreStartContainer(task *swarmv1alpha1.SwarmTask, name string) bool {
+	if task.Spec.Hooks == nil || !strings.HasPrefix(name, preStartContainerPrefix) {
+		return false
+	}
+	for _, hook := range task.Spec.Hooks.PreStart {
+		if preStartContainerPrefix+hook.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcilePostCompleteHooks runs the postComplete hooks once the task's
+// Job succeeded. It returns true once they succeeded. Until then the task
+// stays Running, and it fails without further retries if they fail, as the
+// task's work is already done.
+func (r *SwarmTaskReconciler) reconcilePostCompleteHooks(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster) (bool, error) {
+	finishPreStartHooks(task)
+	status := taskHooksStatus(task)
+	switch status.PostComplete {
+	case swarmv1alpha1.HookSucceeded:
+		return true, nil
+	case swarmv1alpha1.HookFailed:
+		return false, nil
+	}
+
+	post, err := r.ensurePostCompleteJob(ctx, task, job, cluster)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case post.Status.Succeeded > 0:
+		status.PostComplete = swarmv1alpha1.HookSucceeded
+		task.Status.Message = fmt.Sprintf("postComplete hooks succeeded in Job %s", post.Name)
+		return true, nil
+	case post.Status.Failed > 0:
+		return false, r.failPostCompleteHooks(ctx, task, post, cluster)
+	}
+
+	if status.PostComplete == swarmv1alpha1.HookRunning && task.Status.Phase == "Running" {
+		return false, nil
+	}
+	status.PostComplete = swarmv1alpha1.HookRunning
+	status.PostCompleteJob = post.Name
+	task.Status.Phase = "Running"
+	if task.Status.StartTime == nil {
+		task.Status.StartTime = &metav1.Time{Time: time.Now()}
+	}
+	task.Status.Message = fmt.Sprintf("Running postComplete hooks in Job %s", post.Name)
+	return false, r.Status().Update(ctx, task)
+}
+
+// ensurePostCompleteJob creates the Job running the postComplete hooks of
+// the attempt that succeeded
+func (r *SwarmTaskReconciler) ensurePostCompleteJob(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster) (*batchv1.Job, error) {
+	post := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: postCompleteJobName(job), Namespace: job.Namespace}, post)
+	if err == nil || !errors.IsNotFound(err) {
+		return post, err
+	}
+
+	post = &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      postCompleteJobName(job),
+			Namespace: job.Namespace,
+			Labels:    hookJobLabels(job.Labels),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: job.Spec.BackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      hookJobLabels(job.Spec.Template.Labels),
+					Annotations: job.Spec.Template.Annotations,
+				},
+				Spec: *postCompletePodSpec(task, &job.Spec.Template.Spec),
+			},
+		},
+	}
+	// The hook images are pulled like the task's
+	utils.ApplyImageConfig(&post.Spec.Template.Spec, taskImageConfig(task, cluster))
+	if err := controllerutil.SetControllerReference(task, post, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, post); err != nil {
+		return nil, err
+	}
+	r.Recorder.Eventf(task, corev1.EventTypeNormal, "HooksStarted", "Running postComplete hooks in Job %s", post.Name)
+	return post, nil
+}
+
+// hookJobLabels copies the operator's labels of the task's Job, leaving out
+// those the Job controller sets for its own selector
+func hookJobLabels(labels map[string]string) map[string]string {
+	copied := map[string]string{}
+	for key, value := range labels {
+		if strings.HasPrefix(key, "swarm.claudeflow.io/") {
+			copied[key] = value
+		}
+	}
+	// The Job of a task started in a warm pod selects it by its claim
+	delete(copied, warmClaimLabel)
+	copied[taskHookLabel] = swarmv1alpha1.PostCompleteHook
+	return copied
+}
+
+// failPostCompleteHooks fails the task with the diagnosis of its
+// postComplete Job
+func (r *SwarmTaskReconciler) failPostCompleteHooks(ctx context.Context, task *swarmv1alpha1.SwarmTask, post *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster) error {
+	now := metav1.Now()
+	details := r.diagnoseJobFailure(ctx, task, post)
+	details.Hook = swarmv1alpha1.PostCompleteHook
+	task.Status.FailureDetails = details
+	task.Status.Hooks.PostComplete = swarmv1alpha1.HookFailed
+	task.Status.Phase = "Failed"
+	task.Status.CompletionTime = &now
+	task.Status.Message = fmt.Sprintf("Task succeeded but %s", failureSummary(details))
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	r.recordTaskSLOs(task, cluster, swarmv1alpha1.CompletionStage, now.Time, true)
+	r.Recorder.Event(task, corev1.EventTypeWarning, "HookFailed", task.Status.Message)
+	r.notifyLifecycle(ctx, task, cluster, swarmv1alpha1.TaskFailedEvent)
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Task hooks", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmTaskReconciler
+	)
+
+	newReconciler := func(objects ...client.Object) {
+		reconciler = &SwarmTaskReconciler{
+			Client: newTestClientBuilder().
+				WithObjects(append(objects, task)...).WithStatusSubresource(&batchv1.Job{}).
+				Build(),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"}}
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default", UID: "task-uid"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				Hooks: &swarmv1alpha1.TaskHooks{
+					PreStart: []corev1.Container{{
+						Name:  "download",
+						Image: "curlimages/curl",
+						Env:   []corev1.EnvVar{{Name: "DATASET", Value: "s3://data"}},
+					}},
+					PostComplete: []corev1.Container{{Name: "upload", Image: "amazon/aws-cli"}},
+				},
+			},
+		}
+	})
+
+	It("runs preStart hooks as init containers with the task environment", func() {
+		newReconciler()
+		job, err := reconciler.createOrUpdateJob(ctx, task, cluster, "default", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		init := job.Spec.Template.Spec.InitContainers
+		Expect(init).To(HaveLen(1))
+		Expect(init[0].Name).To(Equal("pre-download"))
+		Expect(init[0].Env).To(ContainElement(corev1.EnvVar{Name: executor.EnvTaskName, Value: "train"}))
+		Expect(init[0].Env[len(init[0].Env)-1].Name).To(Equal("DATASET"))
+	})
+
+	It("attributes failures of preStart containers to the hook", func() {
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "default"},
+			Status:     batchv1.JobStatus{Failed: 1},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "train-job-abc", Namespace: "default", Labels: map[string]string{"job-name": job.Name}},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodFailed,
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "pre-download",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 6}},
+				}},
+			},
+		}
+		newReconciler(pod)
+
+		Expect(reconciler.handleJobFailure(ctx, task, job, nil)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Failed"))
+		Expect(task.Status.FailureDetails.Hook).To(Equal(swarmv1alpha1.PreStartHook))
+		Expect(task.Status.Hooks.PreStart).To(Equal(swarmv1alpha1.HookFailed))
+		Expect(task.Status.Hooks.PostComplete).To(Equal(swarmv1alpha1.HookPending))
+		Expect(task.Status.Message).To(Equal("Job failed: preStart hook failed: Error: container pre-download exited with code 6"))
+	})
+
+	It("completes the task only once the postComplete Job succeeded", func() {
+		newReconciler()
+		job, err := reconciler.createOrUpdateJob(ctx, task, cluster, "default", "")
+		Expect(err).NotTo(HaveOccurred())
+		job.Status.Succeeded = 1
+
+		Expect(reconciler.updateTaskStatus(ctx, task, job, nil)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Running"))
+		Expect(task.Status.Hooks).To(Equal(&swarmv1alpha1.TaskHooksStatus{
+			PreStart:        swarmv1alpha1.HookSucceeded,
+			PostComplete:    swarmv1alpha1.HookRunning,
+			PostCompleteJob: "train-job-post",
+		}))
+
+		post := &batchv1.Job{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "train-job-post", Namespace: "default"}, post)).To(Succeed())
+		Expect(post.Labels).To(HaveKeyWithValue(taskHookLabel, swarmv1alpha1.PostCompleteHook))
+		Expect(post.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		Expect(post.Spec.Template.Spec.Containers).To(ConsistOf(HaveField("Name", "upload")))
+
+		post.Status.Succeeded = 1
+		Expect(reconciler.Status().Update(ctx, post)).To(Succeed())
+		Expect(reconciler.updateTaskStatus(ctx, task, job, nil)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Completed"))
+		Expect(task.Status.Hooks.PostComplete).To(Equal(swarmv1alpha1.HookSucceeded))
+	})
+
+	It("fails the task without retrying its work when postComplete hooks fail", func() {
+		task.Spec.RetryPolicy = &swarmv1alpha1.RetryPolicy{MaxRetries: 3}
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "default"},
+			Status:     batchv1.JobStatus{Succeeded: 1},
+		}
+		post := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "train-job-post", Namespace: "default"},
+			Status: batchv1.JobStatus{
+				Failed: 1,
+				Conditions: []batchv1.JobCondition{{
+					Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded",
+				}},
+			},
+		}
+		newReconciler(post)
+
+		Expect(reconciler.updateTaskStatus(ctx, task, job, nil)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Failed"))
+		Expect(task.Status.RetryCount).To(BeZero())
+		Expect(task.Status.FailureDetails.Hook).To(Equal(swarmv1alpha1.PostCompleteHook))
+		Expect(task.Status.Hooks.PostComplete).To(Equal(swarmv1alpha1.HookFailed))
+		Expect(task.Status.Message).To(Equal("Task succeeded but postComplete hook failed: BackoffLimitExceeded"))
+
+		// The succeeded Job of the task does not complete it later on
+		Expect(reconciler.updateTaskStatus(ctx, task, job, nil)).To(Succeed())
+		Expect(task.Status.Phase).To(Equal("Failed"))
+	})
+
+	It("rejects hooks with duplicate names", func() {
+		task.Spec.Hooks.PostComplete = append(task.Spec.Hooks.PostComplete, corev1.Container{Name: "upload", Image: "busybox"})
+		errs := swarmv1alpha1.ValidateTaskHooks(task.Spec.Hooks, field.NewPath("spec", "hooks"))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.hooks.postComplete[1].name"))
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// taskPhaseSkipped marks tasks suppressed as duplicates of another task
+	taskPhaseSkipped = "Skipped"
+
+	// idempotencyKeyField indexes tasks by "<cluster>/<idempotency key>"
+	idempotencyKeyField = "spec.idempotencyKey"
+
+	// idempotencyWindow is how long a completed task keeps suppressing
+	// duplicates
+	idempotencyWindow = 24 * time.Hour
+)
+
+// indexIdempotencyKey is the field indexer of idempotencyKeyField
+func indexIdempotencyKey(obj client.Object) []string {
+	task, ok := obj.(*swarmv1alpha1.SwarmTask)
+	if !ok || task.Spec.IdempotencyKey == "" {
+		return nil
+	}
+	return []string{task.Spec.SwarmCluster + "/" + task.Spec.IdempotencyKey}
+}
+
+// suppressesDuplicates reports whether the task counts as the original of
+// its idempotency key: it is still active or completed recently. Failed and
+// skipped tasks do not, so a resubmission after a failure runs.
+func suppressesDuplicates(task *swarmv1alpha1.SwarmTask, now time.Time) bool {
+	switch task.Status.Phase {
+	case "Failed", "Cancelled", taskPhaseDeadLettered, taskPhaseSkipped:
+		return false
+	case "Completed":
+		return task.Status.CompletionTime != nil && now.Sub(task.Status.CompletionTime.Time) < idempotencyWindow
+	}
+	return true
+}
+
+// findOriginalTask returns the task the given one duplicates, nil when it is
+// the original itself. The oldest task suppressing duplicates is the
+// original, so tasks submitted together agree on which of them runs.
+func (r *SwarmTaskReconciler) findOriginalTask(ctx context.Context, task *swarmv1alpha1.SwarmTask) (*swarmv1alpha1.SwarmTask, error) {
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, tasks, client.InNamespace(task.Namespace),
+		client.MatchingFields{idempotencyKeyField: task.Spec.SwarmCluster + "/" + task.Spec.IdempotencyKey}); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var original *swarmv1alpha1.SwarmTask
+	for i := range tasks.Items {
+		candidate := &tasks.Items[i]
+		if candidate.Name != task.Name && !suppressesDuplicates(candidate, now) {
+			continue
+		}
+		if original == nil || olderTask(candidate, original) {
+			original = candidate
+		}
+	}
+	if original == nil || original.Name == task.Name {
+		return nil, nil
+	}
+	return original, nil
+}
+
+// olderTask orders tasks by creation, breaking ties by name
+func olderTask(a, b *swarmv1alpha1.SwarmTask) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// skipDuplicateTask parks the task as a duplicate of the original
+func (r *SwarmTaskReconciler) skipDuplicateTask(ctx context.Context, task, original *swarmv1alpha1.SwarmTask) error {
+	task.Status.Phase = taskPhaseSkipped
+	task.Status.DuplicateOf = original.Name
+	task.Status.Message = fmt.Sprintf("Duplicate of task %s with idempotency key %q", original.Name, task.Spec.IdempotencyKey)
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	r.Recorder.Event(task, corev1.EventTypeNormal, "DuplicateSuppressed", task.Status.Message)
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task idempotency keys", func() {
+	var (
+		ctx     context.Context
+		created time.Time
+	)
+
+	keyedTask := func(name, cluster, key string, age time.Duration, status swarmv1alpha1.SwarmTaskStatus) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+			},
+			Spec:   swarmv1alpha1.SwarmTaskSpec{SwarmCluster: cluster, IdempotencyKey: key},
+			Status: status,
+		}
+	}
+
+	reconcilerFor := func(objects ...client.Object) *SwarmTaskReconciler {
+		k8sClient := newTestClientBuilder().
+			WithObjects(objects...).
+			WithIndex(&swarmv1alpha1.SwarmTask{}, idempotencyKeyField, indexIdempotencyKey).
+			Build()
+		return &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		created = time.Now().Truncate(time.Second)
+	})
+
+	It("skips a resubmission of a running task", func() {
+		original := keyedTask("build-1", "swarm", "ci-42", time.Minute, swarmv1alpha1.SwarmTaskStatus{Phase: "Running"})
+		duplicate := keyedTask("build-2", "swarm", "ci-42", 0, swarmv1alpha1.SwarmTaskStatus{})
+		r := reconcilerFor(original, duplicate)
+
+		found, err := r.findOriginalTask(ctx, duplicate)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).NotTo(BeNil())
+		Expect(found.Name).To(Equal("build-1"))
+
+		Expect(r.skipDuplicateTask(ctx, duplicate, found)).To(Succeed())
+		Expect(duplicate.Status.Phase).To(Equal(taskPhaseSkipped))
+		Expect(duplicate.Status.DuplicateOf).To(Equal("build-1"))
+
+		// The original keeps running
+		found, err = r.findOriginalTask(ctx, original)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeNil())
+	})
+
+	It("only suppresses duplicates within the same cluster", func() {
+		other := keyedTask("build-1", "other", "ci-42", time.Minute, swarmv1alpha1.SwarmTaskStatus{Phase: "Running"})
+		task := keyedTask("build-2", "swarm", "ci-42", 0, swarmv1alpha1.SwarmTaskStatus{})
+
+		found, err := reconcilerFor(other, task).findOriginalTask(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeNil())
+	})
+
+	It("lets resubmissions of failed or long completed tasks run", func() {
+		failed := keyedTask("build-1", "swarm", "ci-42", time.Hour, swarmv1alpha1.SwarmTaskStatus{Phase: "Failed"})
+		stale := keyedTask("build-0", "swarm", "ci-42", 48*time.Hour, swarmv1alpha1.SwarmTaskStatus{
+			Phase:          "Completed",
+			CompletionTime: &metav1.Time{Time: created.Add(-47 * time.Hour)},
+		})
+		task := keyedTask("build-2", "swarm", "ci-42", 0, swarmv1alpha1.SwarmTaskStatus{})
+
+		found, err := reconcilerFor(failed, stale, task).findOriginalTask(ctx, task)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeNil())
+	})
+
+	It("agrees on the oldest of simultaneous submissions", func() {
+		first := keyedTask("build-a", "swarm", "ci-42", 0, swarmv1alpha1.SwarmTaskStatus{})
+		second := keyedTask("build-b", "swarm", "ci-42", 0, swarmv1alpha1.SwarmTaskStatus{})
+		r := reconcilerFor(first, second)
+
+		found, err := r.findOriginalTask(ctx, second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.Name).To(Equal("build-a"))
+
+		found, err = r.findOriginalTask(ctx, first)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeNil())
+	})
+})
@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/imagescan"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+)
+
+const (
+	// ConditionTypeImageScanned reports whether the executor image of the
+	// task passed the vulnerability scan
+	ConditionTypeImageScanned = "ImageScanned"
+
+	ReasonImageScanPassed         = "ImageScanPassed"
+	ReasonVulnerabilitiesAccepted = "VulnerabilitiesAccepted"
+	ReasonVulnerableImage         = "VulnerableImage"
+	ReasonImageScanFailed         = "ImageScanFailed"
+
+	// imageRescanInterval is how long a scan of the executor image is
+	// reused, tags may be pushed again and scanners learn of new CVEs
+	imageRescanInterval = 10 * time.Minute
+
+	// imageScanRetryInterval is how soon a task whose image could not be
+	// scanned tries again
+	imageScanRetryInterval = time.Minute
+)
+
+// imageScanSettings returns the image scanning settings, URL is empty when
+// images are not scanned
+func (r *SwarmTaskReconciler) imageScanSettings() operatorconfig.ImageScanSettings {
+	if r.Config == nil {
+		return operatorconfig.ImageScanSettings{}
+	}
+	return r.Config.Get().ImageScan
+}
+
+// imageScanner returns the client of the configured scanner
+func (r *SwarmTaskReconciler) imageScanner(settings operatorconfig.ImageScanSettings) imagescan.Scanner {
+	if r.NewImageScanner != nil {
+		return r.NewImageScanner(settings.URL, settings.Format)
+	}
+	return imagescan.NewClient(settings.URL, settings.Format)
+}
+
+// imageScanViolation describes the first threshold the scan is over, or
+// returns ""
+func imageScanViolation(settings operatorconfig.ImageScanSettings, scan *swarmv1alpha1.ImageScanStatus) string {
+	switch {
+	case settings.MaxCritical >= 0 && scan.Critical > settings.MaxCritical:
+		return fmt.Sprintf("image %s has %d critical vulnerabilities, at most %d are allowed", scan.Image, scan.Critical, settings.MaxCritical)
+	case settings.MaxHigh >= 0 && scan.High > settings.MaxHigh:
+		return fmt.Sprintf("image %s has %d high vulnerabilities, at most %d are allowed", scan.Image, scan.High, settings.MaxHigh)
+	}
+	return ""
+}
+
+// scanExecutorImage checks the image of the task container against the
+// vulnerability thresholds of the operator before the Job is created, and
+// records the scan in the task status. Tasks over the thresholds, or whose
+// image could not be scanned unless the scanner fails open, stay Pending
+// with a dispatchHoldError. Scans are reused for imageRescanInterval.
+func (r *SwarmTaskReconciler) scanExecutorImage(ctx context.Context, task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) error {
+	settings := r.imageScanSettings()
+	if settings.URL == "" || len(podSpec.Containers) == 0 {
+		return nil
+	}
+	image := podSpec.Containers[0].Image
+
+	scan := task.Status.ImageScan
+	fresh := scan == nil || scan.Image != image || time.Since(scan.ScanTime.Time) > imageRescanInterval
+	if fresh {
+		summary, err := r.imageScanner(settings).Scan(ctx, image)
+		if err != nil {
+			message := fmt.Sprintf("Scanning image %s failed: %v", image, err)
+			r.Recorder.Event(task, corev1.EventTypeWarning, ReasonImageScanFailed, message)
+			changed := meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+				Type:               ConditionTypeImageScanned,
+				Status:             metav1.ConditionFalse,
+				Reason:             ReasonImageScanFailed,
+				Message:            message,
+				ObservedGeneration: task.Generation,
+			})
+			if settings.FailOpen {
+				if changed {
+					return r.Status().Update(ctx, task)
+				}
+				return nil
+			}
+			return r.holdDispatch(ctx, task, message, imageScanRetryInterval)
+		}
+		scan = &swarmv1alpha1.ImageScanStatus{
+			Image:    image,
+			Digest:   summary.Digest,
+			Critical: summary.Critical,
+			High:     summary.High,
+			Medium:   summary.Medium,
+			Low:      summary.Low,
+			Unknown:  summary.Unknown,
+			ScanTime: metav1.Now(),
+		}
+		task.Status.ImageScan = scan
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeImageScanned,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonImageScanPassed,
+		Message:            fmt.Sprintf("Image %s has %d critical and %d high vulnerabilities", image, scan.Critical, scan.High),
+		ObservedGeneration: task.Generation,
+	}
+	violation := imageScanViolation(settings, scan)
+	if violation != "" {
+		condition.Reason, condition.Message = ReasonVulnerabilitiesAccepted, "Running although "+violation
+		if !settings.WarnOnly {
+			condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, ReasonVulnerableImage, "Held, "+violation
+		}
+		if fresh {
+			r.Recorder.Event(task, corev1.EventTypeWarning, ReasonVulnerableImage, condition.Message)
+		}
+	}
+	changed := meta.SetStatusCondition(&task.Status.Conditions, condition)
+	if violation != "" && !settings.WarnOnly {
+		return r.holdDispatch(ctx, task, condition.Message, imageRescanInterval)
+	}
+	if fresh || changed {
+		return r.Status().Update(ctx, task)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/imagescan"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+)
+
+// fakeImageScanner answers scans with a fixed summary or error
+type fakeImageScanner struct {
+	summary *imagescan.Summary
+	err     error
+	scans   []string
+}
+
+func (s *fakeImageScanner) Scan(_ context.Context, image string) (*imagescan.Summary, error) {
+	s.scans = append(s.scans, image)
+	return s.summary, s.err
+}
+
+var _ = Describe("Executor image scanning", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		podSpec    *corev1.PodSpec
+		scanner    *fakeImageScanner
+		settings   operatorconfig.ImageScanSettings
+		recorder   *record.FakeRecorder
+		reconciler *SwarmTaskReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		task = &swarmv1alpha1.SwarmTask{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "default"}}
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{Name: "task", Image: "mirror.local/executor:v1"}}}
+		scanner = &fakeImageScanner{summary: &imagescan.Summary{Digest: "sha256:abc", High: 2, Medium: 5}}
+		settings = operatorconfig.ImageScanSettings{URL: "http://trivy:4954/scan", Format: imagescan.FormatTrivy, MaxHigh: -1}
+	})
+
+	JustBeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SwarmTaskReconciler{
+			Client:          newTestClient(task),
+			Scheme:          testScheme,
+			Recorder:        recorder,
+			Config:          operatorconfig.NewStore(operatorconfig.Settings{ImageScan: settings}),
+			NewImageScanner: func(string, string) imagescan.Scanner { return scanner },
+		}
+	})
+
+	stored := func() *swarmv1alpha1.SwarmTask {
+		stored := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(task), stored)).To(Succeed())
+		return stored
+	}
+
+	It("records the scan of images under the thresholds and reuses it", func() {
+		Expect(reconciler.scanExecutorImage(ctx, task, podSpec)).To(Succeed())
+		Expect(reconciler.scanExecutorImage(ctx, task, podSpec)).To(Succeed())
+		Expect(scanner.scans).To(Equal([]string{"mirror.local/executor:v1"}))
+
+		scan := stored().Status.ImageScan
+		Expect(scan).NotTo(BeNil())
+		Expect(scan.Digest).To(Equal("sha256:abc"))
+		Expect(scan.High).To(Equal(int32(2)))
+		Expect(meta.IsStatusConditionTrue(stored().Status.Conditions, ConditionTypeImageScanned)).To(BeTrue())
+	})
+
+	Context("with critical vulnerabilities", func() {
+		BeforeEach(func() {
+			scanner.summary.Critical = 1
+		})
+
+		It("holds the task", func() {
+			err := reconciler.scanExecutorImage(ctx, task, podSpec)
+			var hold *dispatchHoldError
+			Expect(errors.As(err, &hold)).To(BeTrue())
+			Expect(hold.retryAfter).To(Equal(imageRescanInterval))
+
+			Expect(stored().Status.Phase).To(Equal("Pending"))
+			Expect(stored().Status.Message).To(ContainSubstring("has 1 critical vulnerabilities, at most 0 are allowed"))
+			condition := meta.FindStatusCondition(stored().Status.Conditions, ConditionTypeImageScanned)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(ReasonVulnerableImage))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning VulnerableImage")))
+		})
+
+		Context("in warn mode", func() {
+			BeforeEach(func() {
+				settings.WarnOnly = true
+			})
+
+			It("lets the task run with a warning", func() {
+				Expect(reconciler.scanExecutorImage(ctx, task, podSpec)).To(Succeed())
+				condition := meta.FindStatusCondition(stored().Status.Conditions, ConditionTypeImageScanned)
+				Expect(condition.Reason).To(Equal(ReasonVulnerabilitiesAccepted))
+				Expect(recorder.Events).To(Receive(HavePrefix("Warning VulnerableImage")))
+			})
+		})
+	})
+
+	Context("when the scanner fails", func() {
+		BeforeEach(func() {
+			scanner.err = errors.New("connection refused")
+		})
+
+		It("holds the task", func() {
+			err := reconciler.scanExecutorImage(ctx, task, podSpec)
+			var hold *dispatchHoldError
+			Expect(errors.As(err, &hold)).To(BeTrue())
+			Expect(hold.retryAfter).To(Equal(imageScanRetryInterval))
+			Expect(stored().Status.Message).To(Equal("Scanning image mirror.local/executor:v1 failed: connection refused"))
+		})
+
+		Context("failing open", func() {
+			BeforeEach(func() {
+				settings.FailOpen = true
+			})
+
+			It("lets the task run", func() {
+				Expect(reconciler.scanExecutorImage(ctx, task, podSpec)).To(Succeed())
+				Expect(stored().Status.Phase).To(BeEmpty())
+				condition := meta.FindStatusCondition(stored().Status.Conditions, ConditionTypeImageScanned)
+				Expect(condition.Reason).To(Equal(ReasonImageScanFailed))
+				Expect(recorder.Events).To(Receive(HavePrefix("Warning ImageScanFailed")))
+			})
+		})
+	})
+})
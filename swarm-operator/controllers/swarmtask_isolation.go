@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// sandboxUser is the unprivileged user and group sandboxed executors run
+// as when their pod does not pick one
+const sandboxUser = int64(65532)
+
+// isolationRuntimeClasses are the RuntimeClasses sandboxed tasks run under.
+// They carry the names the gVisor and Kata installers register.
+var isolationRuntimeClasses = map[swarmv1alpha1.TaskIsolation]string{
+	swarmv1alpha1.GVisorIsolation: "gvisor",
+	swarmv1alpha1.KataIsolation:   "kata",
+}
+
+// taskIsolation returns the requested isolation, defaulting to standard
+func taskIsolation(task *swarmv1alpha1.SwarmTask) swarmv1alpha1.TaskIsolation {
+	if task.Spec.Isolation == "" {
+		return swarmv1alpha1.StandardIsolation
+	}
+	return task.Spec.Isolation
+}
+
+// taskSandboxed reports whether the task runs under a sandboxed runtime
+func taskSandboxed(task *swarmv1alpha1.SwarmTask) bool {
+	return taskIsolation(task) != swarmv1alpha1.StandardIsolation
+}
+
+// applyTaskIsolation runs a sandboxed task under its RuntimeClass and
+// hardens the security context of every container. Settings the pod
+// already makes are kept unless they grant privileges; the webhook rejects
+// overrides that would.
+func applyTaskIsolation(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) {
+	if !taskSandboxed(task) {
+		return
+	}
+
+	runtimeClass := isolationRuntimeClasses[taskIsolation(task)]
+	podSpec.RuntimeClassName = &runtimeClass
+	automount := false
+	podSpec.AutomountServiceAccountToken = &automount
+
+	if podSpec.SecurityContext == nil {
+		podSpec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	sc := podSpec.SecurityContext
+	runAsNonRoot := true
+	sc.RunAsNonRoot = &runAsNonRoot
+	if sc.RunAsUser == nil || *sc.RunAsUser == 0 {
+		user := sandboxUser
+		sc.RunAsUser = &user
+	}
+	if sc.RunAsGroup == nil {
+		group := sandboxUser
+		sc.RunAsGroup = &group
+	}
+	if sc.FSGroup == nil {
+		group := sandboxUser
+		sc.FSGroup = &group
+	}
+	if sc.SeccompProfile == nil {
+		sc.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			hardenContainer(&containers[i])
+		}
+	}
+}
+
+// hardenContainer drops every capability and privilege of a sandboxed
+// container
+func hardenContainer(container *corev1.Container) {
+	if container.SecurityContext == nil {
+		container.SecurityContext = &corev1.SecurityContext{}
+	}
+	sc := container.SecurityContext
+	privileged := false
+	escalation := false
+	sc.Privileged = &privileged
+	sc.AllowPrivilegeEscalation = &escalation
+	sc.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	if sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot {
+		sc.RunAsNonRoot = nil
+	}
+	if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		sc.RunAsUser = nil
+	}
+	if sc.ProcMount != nil && *sc.ProcMount == corev1.UnmaskedProcMount {
+		sc.ProcMount = nil
+	}
+}
+
+// checkRuntimeClass reports why a sandboxed task cannot run, or an empty
+// string when its RuntimeClass is installed
+func (r *SwarmTaskReconciler) checkRuntimeClass(ctx context.Context, task *swarmv1alpha1.SwarmTask) (string, error) {
+	name := isolationRuntimeClasses[taskIsolation(task)]
+	err := r.Get(ctx, types.NamespacedName{Name: name}, &nodev1.RuntimeClass{})
+	if errors.IsNotFound(err) {
+		return fmt.Sprintf("%s isolation requires RuntimeClass %s, which is not installed", taskIsolation(task), name), nil
+	}
+	return "", err
+}
+
+// taskIsolationStatus is the isolation the task's executor runs at
+func taskIsolationStatus(task *swarmv1alpha1.SwarmTask) *swarmv1alpha1.TaskIsolationStatus {
+	return &swarmv1alpha1.TaskIsolationStatus{
+		Level:            taskIsolation(task),
+		RuntimeClassName: isolationRuntimeClasses[taskIsolation(task)],
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task isolation", func() {
+	isolatedTask := func(isolation swarmv1alpha1.TaskIsolation) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "untrusted", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{Isolation: isolation},
+		}
+	}
+
+	It("leaves standard tasks alone", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applyTaskIsolation(isolatedTask(""), podSpec)
+
+		Expect(podSpec.RuntimeClassName).To(BeNil())
+		Expect(podSpec.Containers[0].SecurityContext).To(BeNil())
+		Expect(taskIsolationStatus(isolatedTask(""))).To(Equal(&swarmv1alpha1.TaskIsolationStatus{Level: swarmv1alpha1.StandardIsolation}))
+	})
+
+	It("runs sandboxed tasks under their RuntimeClass with a hardened security context", func() {
+		privileged := true
+		root := int64(0)
+		podSpec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "checkout"}},
+			Containers: []corev1.Container{{
+				Name:            "task",
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged, RunAsUser: &root},
+			}},
+		}
+		applyTaskIsolation(isolatedTask(swarmv1alpha1.GVisorIsolation), podSpec)
+
+		Expect(*podSpec.RuntimeClassName).To(Equal("gvisor"))
+		Expect(*podSpec.AutomountServiceAccountToken).To(BeFalse())
+		Expect(*podSpec.SecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(*podSpec.SecurityContext.RunAsUser).To(Equal(sandboxUser))
+		Expect(podSpec.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+		for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+			Expect(*container.SecurityContext.Privileged).To(BeFalse())
+			Expect(*container.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+			Expect(container.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+			Expect(container.SecurityContext.RunAsUser).To(BeNil())
+		}
+	})
+
+	It("rejects overrides that weaken the sandbox", func() {
+		task := isolatedTask(swarmv1alpha1.KataIsolation)
+		task.Spec.PodTemplateOverrides = &runtime.RawExtension{Raw: []byte(
+			`{"spec":{"securityContext":{"runAsNonRoot":false},"containers":[{"name":"task","securityContext":{"capabilities":{"add":["SYS_ADMIN"]}}}]}}`)}
+		errs := swarmv1alpha1.ValidatePodTemplateOverrides(task.Spec.PodTemplateOverrides, field.NewPath("spec", "podTemplateOverrides"))
+		Expect(errs).To(HaveLen(2))
+		Expect(swarmv1alpha1.ValidateTaskIsolation(&task.Spec, field.NewPath("spec"))).To(BeEmpty())
+
+		task.Spec.OS = swarmv1alpha1.WindowsOS
+		task.Spec.PodTemplateOverrides = nil
+		Expect(swarmv1alpha1.ValidateTaskIsolation(&task.Spec, field.NewPath("spec"))).To(HaveLen(1))
+	})
+
+	It("reports RuntimeClasses that are not installed", func() {
+		reconciler := &SwarmTaskReconciler{
+			Client: newTestClient(&nodev1.RuntimeClass{ObjectMeta: metav1.ObjectMeta{Name: "gvisor"}, Handler: "runsc"}),
+			Scheme: testScheme,
+		}
+
+		reason, err := reconciler.checkRuntimeClass(context.Background(), isolatedTask(swarmv1alpha1.GVisorIsolation))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+
+		reason, err = reconciler.checkRuntimeClass(context.Background(), isolatedTask(swarmv1alpha1.KataIsolation))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reason).To(Equal("kata isolation requires RuntimeClass kata, which is not installed"))
+	})
+})
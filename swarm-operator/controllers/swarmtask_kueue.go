@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch
+
+const (
+	// kueueQueueNameLabel submits a Job to a Kueue LocalQueue
+	kueueQueueNameLabel = "kueue.x-k8s.io/queue-name"
+
+	// Conditions of Kueue workloads
+	workloadAdmitted = "Admitted"
+	workloadEvicted  = "Evicted"
+)
+
+// workloadGVK is the Kueue Workload kind, used unstructured so the operator
+// does not depend on its API module
+var workloadGVK = schema.GroupVersionKind{Group: "kueue.x-k8s.io", Version: "v1beta1", Kind: "Workload"}
+
+// workloadStatus is the part of a Kueue workload's status tasks report
+type workloadStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Admission  *struct {
+		ClusterQueue string `json:"clusterQueue"`
+	} `json:"admission,omitempty"`
+}
+
+// applyTaskQueue submits the Job to the task's Kueue LocalQueue. The Job is
+// created suspended and Kueue resumes it once its workload is admitted.
+func applyTaskQueue(task *swarmv1alpha1.SwarmTask, job *batchv1.Job) {
+	if task.Spec.QueueName == "" {
+		return
+	}
+	job.Labels[kueueQueueNameLabel] = task.Spec.QueueName
+	suspend := true
+	job.Spec.Suspend = &suspend
+}
+
+// jobWorkload returns the Kueue workload of the Job, or nil if Kueue has
+// not created it yet
+func (r *SwarmTaskReconciler) jobWorkload(ctx context.Context, job *batchv1.Job) (*unstructured.Unstructured, error) {
+	workloads := &unstructured.UnstructuredList{}
+	workloads.SetGroupVersionKind(workloadGVK.GroupVersion().WithKind(workloadGVK.Kind + "List"))
+	if err := r.List(ctx, workloads, client.InNamespace(job.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range workloads.Items {
+		for _, owner := range workloads.Items[i].GetOwnerReferences() {
+			if owner.UID == job.UID {
+				return &workloads.Items[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// recordQueueStatus mirrors the admission and evictions of the Job's Kueue
+// workload into the task status and reports whether it changed. Events are
+// recorded for admissions and evictions.
+func (r *SwarmTaskReconciler) recordQueueStatus(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job) (bool, error) {
+	previous := task.Status.Queue
+	queue := &swarmv1alpha1.TaskQueueStatus{Name: task.Spec.QueueName}
+	if previous != nil && previous.Name == queue.Name {
+		// Evictions are counted across the Jobs of every attempt
+		queue.Evictions = previous.Evictions
+		queue.LastEvictionTime = previous.LastEvictionTime
+		queue.LastEvictionReason = previous.LastEvictionReason
+	}
+
+	workload, err := r.jobWorkload(ctx, job)
+	switch {
+	case meta.IsNoMatchError(err):
+		queue.Message = "Kueue is not installed, the Job stays suspended"
+	case err != nil:
+		return false, err
+	case workload == nil:
+		queue.Message = fmt.Sprintf("Waiting for Kueue to create the workload in queue %s", task.Spec.QueueName)
+	default:
+		status := workloadStatus{}
+		raw, _, _ := unstructured.NestedMap(workload.Object, "status")
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &status); err != nil {
+			return false, err
+		}
+		queue.Workload = workload.GetName()
+		if status.Admission != nil {
+			queue.ClusterQueue = status.Admission.ClusterQueue
+		}
+		if admitted := meta.FindStatusCondition(status.Conditions, workloadAdmitted); admitted != nil {
+			queue.Admitted = admitted.Status == metav1.ConditionTrue
+			queue.Message = admitted.Message
+			if queue.Admitted {
+				queue.AdmissionTime = &admitted.LastTransitionTime
+			}
+		}
+		if evicted := meta.FindStatusCondition(status.Conditions, workloadEvicted); evicted != nil && evicted.Status == metav1.ConditionTrue &&
+			(queue.LastEvictionTime == nil || evicted.LastTransitionTime.After(queue.LastEvictionTime.Time)) {
+			queue.Evictions++
+			queue.LastEvictionTime = &evicted.LastTransitionTime
+			queue.LastEvictionReason = evicted.Reason
+			queue.Message = evicted.Message
+			r.Recorder.Eventf(task, corev1.EventTypeWarning, "Evicted", "Kueue evicted workload %s: %s", queue.Workload, evicted.Message)
+		}
+		if queue.Admitted && (previous == nil || !previous.Admitted || previous.Workload != queue.Workload) {
+			r.Recorder.Eventf(task, corev1.EventTypeNormal, "Admitted", "Kueue admitted workload %s to cluster queue %s", queue.Workload, queue.ClusterQueue)
+		}
+	}
+
+	if equality.Semantic.DeepEqual(previous, queue) {
+		return false, nil
+	}
+	task.Status.Queue = queue
+	if !queue.Admitted && job.Status.Active == 0 && queue.Message != "" {
+		task.Status.Message = queue.Message
+	}
+	return true, nil
+}
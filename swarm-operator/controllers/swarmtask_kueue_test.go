@@ -0,0 +1,141 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Kueue integration", func() {
+	var (
+		ctx      context.Context
+		task     *swarmv1alpha1.SwarmTask
+		job      *batchv1.Job
+		recorder *record.FakeRecorder
+	)
+
+	reconcilerFor := func(objects ...client.Object) *SwarmTaskReconciler {
+		scheme := newTestScheme()
+		scheme.AddKnownTypeWithName(workloadGVK, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(workloadGVK.GroupVersion().WithKind("WorkloadList"), &unstructured.UnstructuredList{})
+		k8sClient := newTestClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		return &SwarmTaskReconciler{Client: k8sClient, Scheme: scheme, Recorder: recorder}
+	}
+
+	workload := func(conditions ...interface{}) *unstructured.Unstructured {
+		w := &unstructured.Unstructured{}
+		w.SetGroupVersionKind(workloadGVK)
+		w.SetName("job-" + job.Name + "-1a2b3")
+		w.SetNamespace(job.Namespace)
+		w.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: "batch/v1", Kind: "Job", Name: job.Name, UID: job.UID,
+		}})
+		Expect(unstructured.SetNestedField(w.Object, map[string]interface{}{
+			"admission":  map[string]interface{}{"clusterQueue": "team-a"},
+			"conditions": conditions,
+		}, "status")).To(Succeed())
+		return w
+	}
+
+	condition := func(conditionType, status, reason string, at time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"type": conditionType, "status": status, "reason": reason,
+			"message":            reason + " by kueue",
+			"lastTransitionTime": at.UTC().Format(time.RFC3339),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", QueueName: "batch"},
+		}
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name: taskJobName(task), Namespace: "claude-flow-swarm", UID: types.UID("job-uid"), Labels: map[string]string{},
+		}}
+	})
+
+	It("creates queued Jobs suspended in their LocalQueue", func() {
+		applyTaskQueue(task, job)
+		Expect(job.Labels).To(HaveKeyWithValue(kueueQueueNameLabel, "batch"))
+		Expect(*job.Spec.Suspend).To(BeTrue())
+
+		unqueued := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{}}}
+		task.Spec.QueueName = ""
+		applyTaskQueue(task, unqueued)
+		Expect(unqueued.Labels).To(BeEmpty())
+		Expect(unqueued.Spec.Suspend).To(BeNil())
+	})
+
+	It("waits for Kueue to create the workload", func() {
+		r := reconcilerFor()
+		changed, err := r.recordQueueStatus(ctx, task, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(task.Status.Queue.Admitted).To(BeFalse())
+		Expect(task.Status.Message).To(ContainSubstring("queue batch"))
+	})
+
+	It("reports the admission of the workload", func() {
+		r := reconcilerFor(workload(condition(workloadAdmitted, "True", "Admitted", time.Now())))
+		changed, err := r.recordQueueStatus(ctx, task, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(task.Status.Queue.Admitted).To(BeTrue())
+		Expect(task.Status.Queue.ClusterQueue).To(Equal("team-a"))
+		Expect(task.Status.Queue.Workload).To(Equal("job-" + job.Name + "-1a2b3"))
+		Expect(task.Status.Queue.AdmissionTime).NotTo(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Admitted")))
+
+		changed, err = r.recordQueueStatus(ctx, task, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("counts every eviction once", func() {
+		evictedAt := time.Now().Add(-time.Minute)
+		r := reconcilerFor(workload(
+			condition(workloadAdmitted, "False", "Pending", evictedAt),
+			condition(workloadEvicted, "True", "Preempted", evictedAt),
+		))
+
+		for i := 0; i < 2; i++ {
+			_, err := r.recordQueueStatus(ctx, task, job)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(task.Status.Queue.Admitted).To(BeFalse())
+		Expect(task.Status.Queue.Evictions).To(BeEquivalentTo(1))
+		Expect(task.Status.Queue.LastEvictionReason).To(Equal("Preempted"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Evicted")))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+})
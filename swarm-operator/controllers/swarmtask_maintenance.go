@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// taskPhaseSuspended marks tasks checkpointed and stopped by a maintenance
+// window, they resume once it closes
+const taskPhaseSuspended = "Suspended"
+
+// holdForMaintenance keeps the task from dispatching a Job while the
+// cluster's maintenance window is open, and suspends running tasks when the
+// window asks for it. It reports whether the task was held.
+func (r *SwarmTaskReconciler) holdForMaintenance(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string, window *swarmv1alpha1.MaintenanceWindow, end time.Time) (bool, error) {
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: taskJobName(task), Namespace: namespace}, job)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	if errors.IsNotFound(err) {
+		// Only tasks waiting for their Job are held, tasks pushed to an
+		// agent have none
+		switch task.Status.Phase {
+		case "", "Pending", taskPhaseSuspended:
+		default:
+			return false, nil
+		}
+		message := fmt.Sprintf("Held by maintenance window %s until %s", window.Name, end.UTC().Format(time.RFC3339))
+		if task.Status.Message == message {
+			return true, nil
+		}
+		if task.Status.Phase == "" {
+			task.Status.Phase = "Pending"
+		}
+		task.Status.Message = message
+		if err := r.Status().Update(ctx, task); err != nil {
+			return false, err
+		}
+		r.Recorder.Event(task, corev1.EventTypeNormal, "MaintenanceHold", message)
+		return true, nil
+	}
+
+	if window.SuspendRunning && job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		return true, r.suspendForMaintenance(ctx, task, job, window)
+	}
+	return false, nil
+}
+
+// suspendForMaintenance asks the task's pods for a checkpoint and stops its
+// Job. The next attempt resumes from the checkpoint once the window closes.
+func (r *SwarmTaskReconciler) suspendForMaintenance(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, window *swarmv1alpha1.MaintenanceWindow) error {
+	log := log.FromContext(ctx)
+	log.Info("Suspending task for maintenance window", "window", window.Name, "job", job.Name)
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if _, requested := pod.Annotations[checkpointRequestedAnnotation]; requested {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[checkpointRequestedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.Patch(ctx, pod, patch); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to request checkpoint", "pod", pod.Name)
+		}
+	}
+
+	// The pods get their termination grace period to write the checkpoint
+	propagation := metav1.DeletePropagationBackground
+	if err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if !task.Spec.Resume {
+		task.Spec.Resume = true
+		if err := r.Update(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	task.Status.Attempt++
+	task.Status.Phase = taskPhaseSuspended
+	task.Status.Message = fmt.Sprintf("Suspended by maintenance window %s", window.Name)
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+
+	r.Recorder.Event(task, corev1.EventTypeNormal, "Suspended", task.Status.Message)
+	return nil
+}
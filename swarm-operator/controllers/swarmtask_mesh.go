@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+const (
+	istioInjectAnnotation        = "sidecar.istio.io/inject"
+	istioNativeSidecarAnnotation = "sidecar.istio.io/nativeSidecar"
+	// istioQuitURL is served by the pilot-agent of the proxy to localhost
+	istioQuitURL = "http://127.0.0.1:15020/quitquitquit"
+
+	linkerdInjectAnnotation        = "linkerd.io/inject"
+	linkerdNativeSidecarAnnotation = "config.alpha.linkerd.io/proxy-enable-native-sidecar"
+	// linkerdQuitURL is the shutdown endpoint of the proxy's admin server
+	linkerdQuitURL = "http://127.0.0.1:4191/shutdown"
+)
+
+// serviceMeshMode returns the mode task pods of the cluster join its mesh
+// in, resolving Auto by whether the API server runs native sidecars. It
+// returns an empty mode for clusters without a mesh.
+func serviceMeshMode(cluster *swarmv1alpha1.SwarmCluster, nativeSidecars bool) swarmv1alpha1.ServiceMeshMode {
+	if cluster == nil || cluster.Spec.ServiceMesh == nil {
+		return ""
+	}
+	switch mode := cluster.Spec.ServiceMesh.Mode; mode {
+	case "", swarmv1alpha1.AutoMeshMode:
+		if nativeSidecars {
+			return swarmv1alpha1.NativeSidecarMeshMode
+		}
+		return swarmv1alpha1.QuitOnExitMeshMode
+	default:
+		return mode
+	}
+}
+
+// applyServiceMesh annotates the task pod for the mesh of its cluster so
+// the Job completes with the task container: the proxy either runs as a
+// native sidecar, is asked to quit by the executor through
+// SWARM_MESH_QUIT_URL or is not injected at all
+func (r *SwarmTaskReconciler) applyServiceMesh(cluster *swarmv1alpha1.SwarmCluster, template *corev1.PodTemplateSpec) {
+	mode := serviceMeshMode(cluster, r.NativeSidecars)
+	if mode == "" {
+		return
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	istio := cluster.Spec.ServiceMesh.Provider == swarmv1alpha1.IstioMesh
+
+	switch mode {
+	case swarmv1alpha1.DisabledMeshMode:
+		if istio {
+			template.Annotations[istioInjectAnnotation] = "false"
+		} else {
+			template.Annotations[linkerdInjectAnnotation] = "disabled"
+		}
+	case swarmv1alpha1.NativeSidecarMeshMode:
+		if istio {
+			template.Annotations[istioNativeSidecarAnnotation] = "true"
+		} else {
+			template.Annotations[linkerdNativeSidecarAnnotation] = "true"
+		}
+	case swarmv1alpha1.QuitOnExitMeshMode:
+		url := linkerdQuitURL
+		if istio {
+			url = istioQuitURL
+		}
+		container := &template.Spec.Containers[0]
+		container.Env = append(container.Env, corev1.EnvVar{Name: executor.EnvMeshQuitURL, Value: url})
+	}
+}
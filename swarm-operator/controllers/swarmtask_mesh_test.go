@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Service mesh compatibility", func() {
+	var (
+		reconciler *SwarmTaskReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+		template   *corev1.PodTemplateSpec
+	)
+
+	mesh := func(provider swarmv1alpha1.ServiceMeshProvider, mode swarmv1alpha1.ServiceMeshMode) {
+		cluster.Spec.ServiceMesh = &swarmv1alpha1.ServiceMeshSpec{Provider: provider, Mode: mode}
+	}
+
+	BeforeEach(func() {
+		reconciler = &SwarmTaskReconciler{}
+		cluster = &swarmv1alpha1.SwarmCluster{}
+		template = &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}}
+	})
+
+	It("leaves pods of clusters without a mesh alone", func() {
+		reconciler.applyServiceMesh(cluster, template)
+		Expect(template.Annotations).To(BeEmpty())
+		Expect(template.Spec.Containers[0].Env).To(BeEmpty())
+	})
+
+	It("runs the proxy as a native sidecar where the API server supports it", func() {
+		mesh(swarmv1alpha1.IstioMesh, swarmv1alpha1.AutoMeshMode)
+		reconciler.NativeSidecars = true
+		reconciler.applyServiceMesh(cluster, template)
+		Expect(template.Annotations).To(Equal(map[string]string{istioNativeSidecarAnnotation: "true"}))
+		Expect(template.Spec.Containers[0].Env).To(BeEmpty())
+	})
+
+	It("has the executor quit the proxy before native sidecars", func() {
+		mesh(swarmv1alpha1.IstioMesh, "")
+		reconciler.applyServiceMesh(cluster, template)
+		Expect(template.Annotations).To(BeEmpty())
+		Expect(template.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: executor.EnvMeshQuitURL, Value: istioQuitURL}))
+
+		template = &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}}
+		mesh(swarmv1alpha1.LinkerdMesh, swarmv1alpha1.QuitOnExitMeshMode)
+		reconciler.NativeSidecars = true
+		reconciler.applyServiceMesh(cluster, template)
+		Expect(template.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: executor.EnvMeshQuitURL, Value: linkerdQuitURL}))
+	})
+
+	It("keeps task pods out of the mesh when disabled", func() {
+		mesh(swarmv1alpha1.IstioMesh, swarmv1alpha1.DisabledMeshMode)
+		reconciler.applyServiceMesh(cluster, template)
+		Expect(template.Annotations).To(Equal(map[string]string{istioInjectAnnotation: "false"}))
+
+		template.Annotations = nil
+		mesh(swarmv1alpha1.LinkerdMesh, swarmv1alpha1.DisabledMeshMode)
+		reconciler.applyServiceMesh(cluster, template)
+		Expect(template.Annotations).To(Equal(map[string]string{linkerdInjectAnnotation: "disabled"}))
+
+		template.Annotations = nil
+		mesh(swarmv1alpha1.LinkerdMesh, swarmv1alpha1.NativeSidecarMeshMode)
+		reconciler.applyServiceMesh(cluster, template)
+		Expect(template.Annotations).To(Equal(map[string]string{linkerdNativeSidecarAnnotation: "true"}))
+	})
+})
@@ -0,0 +1,171 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// taskNetworkPolicyName is the egress policy generated for a task
+func taskNetworkPolicyName(task *swarmv1alpha1.SwarmTask) string {
+	return task.Name + "-egress"
+}
+
+// reconcileTaskNetworkPolicy restricts the egress of the task's Job pods
+// to the destinations in spec.networkPolicy. Domains that fail to resolve
+// are left out, so the policy fails closed.
+func (r *SwarmTaskReconciler) reconcileTaskNetworkPolicy(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string) error {
+	log := log.FromContext(ctx)
+
+	lookup := r.LookupIPAddr
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupIPAddr
+	}
+	var addresses []string
+	for _, domain := range task.Spec.NetworkPolicy.AllowedDomains {
+		ips, err := lookup(ctx, domain)
+		if err != nil {
+			log.Info("Failed to resolve allowed domain", "domain", domain, "error", err.Error())
+			r.Recorder.Eventf(task, corev1.EventTypeWarning, "DomainResolutionFailed",
+				"Egress to %s is blocked until it resolves: %v", domain, err)
+			continue
+		}
+		for _, ip := range ips {
+			addresses = append(addresses, ip.IP.String())
+		}
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: taskNetworkPolicyName(task), Namespace: namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		policy.Labels = map[string]string{
+			"swarm.claudeflow.io/task":    task.Name,
+			"swarm.claudeflow.io/cluster": task.Spec.SwarmCluster,
+		}
+		policy.Spec = taskEgressPolicySpec(task, addresses)
+		if namespace == task.Namespace {
+			return controllerutil.SetControllerReference(task, policy, r.Scheme)
+		}
+		return nil
+	})
+	return err
+}
+
+// taskEgressPolicySpec selects the task's Job pods and only lets them reach
+// the allowed CIDRs, resolved domain addresses, namespaces and cluster DNS
+func taskEgressPolicySpec(task *swarmv1alpha1.SwarmTask, addresses []string) networkingv1.NetworkPolicySpec {
+	spec := task.Spec.NetworkPolicy
+
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range spec.AllowedCIDRs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+
+	sort.Strings(addresses)
+	for i, address := range addresses {
+		if i > 0 && address == addresses[i-1] {
+			continue
+		}
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+		cidr := address + "/32"
+		if ip.To4() == nil {
+			cidr = address + "/128"
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+
+	if len(spec.AllowedNamespaces) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      corev1.LabelMetadataName,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   spec.AllowedNamespaces,
+				}},
+			},
+		})
+	}
+
+	tcp := corev1.ProtocolTCP
+	var ports []networkingv1.NetworkPolicyPort
+	for _, port := range spec.Ports {
+		value := intstr.FromInt32(port)
+		ports = append(ports, networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &value})
+	}
+
+	// An empty egress list denies everything
+	egress := []networkingv1.NetworkPolicyEgressRule{}
+	if len(peers) > 0 {
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{To: peers, Ports: ports})
+	}
+	if spec.AllowDNS == nil || *spec.AllowDNS {
+		udp := corev1.ProtocolUDP
+		dnsPort := intstr.FromInt32(53)
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{corev1.LabelMetadataName: "kube-system"},
+				},
+				PodSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+				},
+			}},
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dnsPort},
+				{Protocol: &tcp, Port: &dnsPort},
+			},
+		})
+	}
+
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"swarm.claudeflow.io/task": task.Name,
+				taskNamespaceLabel:         task.Namespace,
+			},
+		},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+		Egress:      egress,
+	}
+}
+
+// deleteTaskNetworkPolicy removes the task's egress policy
+func (r *SwarmTaskReconciler) deleteTaskNetworkPolicy(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: taskNetworkPolicyName(task), Namespace: r.determineNamespace(task)},
+	}
+	if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task network policy", func() {
+	var task *swarmv1alpha1.SwarmTask
+
+	BeforeEach(func() {
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "default", UID: "task-uid"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				Namespace:    "default",
+				NetworkPolicy: &swarmv1alpha1.TaskNetworkPolicySpec{
+					AllowedCIDRs:      []string{"10.0.0.0/8"},
+					AllowedDomains:    []string{"github.com", "unknown.invalid"},
+					AllowedNamespaces: []string{"claude-flow-hivemind"},
+					Ports:             []int32{443},
+				},
+			},
+		}
+	})
+
+	It("selects the task pods and allows only the listed destinations", func() {
+		spec := taskEgressPolicySpec(task, []string{"140.82.112.3", "140.82.112.3", "2606:50c0::1"})
+
+		Expect(spec.PodSelector.MatchLabels).To(HaveKeyWithValue("swarm.claudeflow.io/task", "build"))
+		Expect(spec.PodSelector.MatchLabels).To(HaveKeyWithValue(taskNamespaceLabel, "default"))
+		Expect(spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeEgress}))
+		Expect(spec.Egress).To(HaveLen(2))
+
+		peers := spec.Egress[0].To
+		Expect(peers).To(HaveLen(4))
+		Expect(peers[0].IPBlock.CIDR).To(Equal("10.0.0.0/8"))
+		Expect(peers[1].IPBlock.CIDR).To(Equal("140.82.112.3/32"))
+		Expect(peers[2].IPBlock.CIDR).To(Equal("2606:50c0::1/128"))
+		Expect(peers[3].NamespaceSelector.MatchExpressions[0].Values).To(Equal([]string{"claude-flow-hivemind"}))
+		Expect(spec.Egress[0].Ports).To(HaveLen(1))
+		Expect(spec.Egress[0].Ports[0].Port.IntValue()).To(Equal(443))
+	})
+
+	It("denies everything but DNS without destinations", func() {
+		allowDNS := false
+		task.Spec.NetworkPolicy = &swarmv1alpha1.TaskNetworkPolicySpec{AllowDNS: &allowDNS}
+		spec := taskEgressPolicySpec(task, nil)
+		Expect(spec.Egress).NotTo(BeNil())
+		Expect(spec.Egress).To(BeEmpty())
+	})
+
+	It("generates the policy and deletes it when the task is done", func() {
+		ctx := context.Background()
+
+		k8sClient := newTestClient(task)
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &SwarmTaskReconciler{
+			Client:   k8sClient,
+			Scheme:   testScheme,
+			Recorder: recorder,
+			LookupIPAddr: func(_ context.Context, host string) ([]net.IPAddr, error) {
+				if host == "github.com" {
+					return []net.IPAddr{{IP: net.ParseIP("140.82.112.3")}}, nil
+				}
+				return nil, fmt.Errorf("no such host")
+			},
+		}
+
+		Expect(reconciler.reconcileTaskNetworkPolicy(ctx, task, "default")).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("DomainResolutionFailed")))
+
+		policy := &networkingv1.NetworkPolicy{}
+		key := types.NamespacedName{Name: "build-egress", Namespace: "default"}
+		Expect(k8sClient.Get(ctx, key, policy)).To(Succeed())
+		Expect(policy.OwnerReferences).To(HaveLen(1))
+		Expect(policy.Spec.Egress[0].To).To(ContainElement(HaveField("IPBlock.CIDR", "140.82.112.3/32")))
+
+		Expect(reconciler.deleteTaskNetworkPolicy(ctx, task)).To(Succeed())
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, key, policy))).To(BeTrue())
+	})
+
+	It("rejects malformed destinations", func() {
+		task.Spec.NetworkPolicy.AllowedCIDRs = []string{"10.0.0.300/8"}
+		task.Spec.NetworkPolicy.Ports = []int32{70000}
+		errs := swarmv1alpha1.ValidateTaskNetworkPolicy(task.Spec.NetworkPolicy, nil)
+		Expect(errs).To(HaveLen(2))
+	})
+})
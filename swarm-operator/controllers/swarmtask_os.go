@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// placeholderWindowsExecutorImage runs Windows tasks when no Windows
+	// executor image is configured
+	placeholderWindowsExecutorImage = "mcr.microsoft.com/powershell:lts-nanoserver-ltsc2022"
+
+	// windowsShell runs scripts in Windows containers
+	windowsShell = "pwsh"
+
+	// windowsDrive is where the Linux-style mount paths of the operator
+	// are moved to
+	windowsDrive = "C:"
+)
+
+// windowsNodeTaints are the taints cloud providers put on Windows node
+// pools to keep Linux pods off them
+var windowsNodeTaints = []string{"os", "node.kubernetes.io/os"}
+
+// windowsTask reports whether the task runs on Windows nodes
+func windowsTask(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Spec.OS == swarmv1alpha1.WindowsOS
+}
+
+// windowsPath moves an absolute Linux path to the system drive. Paths that
+// are already Windows paths are kept.
+func windowsPath(p string) string {
+	if !strings.HasPrefix(p, "/") {
+		return p
+	}
+	return windowsDrive + strings.ReplaceAll(p, "/", `\`)
+}
+
+// windowsCommand runs a PowerShell script as the container command
+func windowsCommand(script string) []string {
+	return []string{windowsShell, "-NoProfile", "-NonInteractive", "-Command", script}
+}
+
+// powerShellQuote wraps s in single quotes for PowerShell
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// applyTaskOS places a Windows task on Windows nodes and adapts the pod the
+// operator built for Linux: Linux-only security settings are dropped and
+// mount paths, and the environment variables pointing into them, move to
+// the C: drive
+func applyTaskOS(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) {
+	if !windowsTask(task) {
+		return
+	}
+
+	podSpec.OS = &corev1.PodOS{Name: corev1.Windows}
+	if podSpec.NodeSelector == nil {
+		podSpec.NodeSelector = map[string]string{}
+	}
+	podSpec.NodeSelector[corev1.LabelOSStable] = string(corev1.Windows)
+	if task.Spec.Windows != nil && task.Spec.Windows.Build != "" {
+		podSpec.NodeSelector[corev1.LabelWindowsBuild] = task.Spec.Windows.Build
+	}
+	for _, key := range windowsNodeTaints {
+		podSpec.Tolerations = append(podSpec.Tolerations, corev1.Toleration{
+			Key:      key,
+			Operator: corev1.TolerationOpEqual,
+			Value:    string(corev1.Windows),
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+
+	var runAsUserName *string
+	if task.Spec.Windows != nil && task.Spec.Windows.RunAsUserName != "" {
+		name := task.Spec.Windows.RunAsUserName
+		runAsUserName = &name
+	}
+
+	podSpec.ShareProcessNamespace = nil
+	if sc := podSpec.SecurityContext; sc != nil {
+		sc.SELinuxOptions = nil
+		sc.SeccompProfile = nil
+		sc.RunAsUser = nil
+		sc.RunAsGroup = nil
+		sc.FSGroup = nil
+		sc.FSGroupChangePolicy = nil
+		sc.SupplementalGroups = nil
+		sc.Sysctls = nil
+	}
+	if runAsUserName != nil {
+		if podSpec.SecurityContext == nil {
+			podSpec.SecurityContext = &corev1.PodSecurityContext{}
+		}
+		podSpec.SecurityContext.WindowsOptions = &corev1.WindowsSecurityContextOptions{RunAsUserName: runAsUserName}
+	}
+
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			adaptWindowsContainer(&containers[i])
+		}
+	}
+}
+
+// adaptWindowsContainer drops the Linux-only security settings of a
+// container and moves its mount paths to the C: drive
+func adaptWindowsContainer(container *corev1.Container) {
+	if sc := container.SecurityContext; sc != nil {
+		sc.SELinuxOptions = nil
+		sc.SeccompProfile = nil
+		sc.RunAsUser = nil
+		sc.RunAsGroup = nil
+		sc.Capabilities = nil
+		sc.Privileged = nil
+		sc.AllowPrivilegeEscalation = nil
+		sc.ReadOnlyRootFilesystem = nil
+		sc.ProcMount = nil
+	}
+
+	var moved []string
+	for i := range container.VolumeMounts {
+		mount := &container.VolumeMounts[i]
+		if path := windowsPath(mount.MountPath); path != mount.MountPath {
+			moved = append(moved, mount.MountPath)
+			mount.MountPath = path
+		}
+	}
+	for i := range container.Env {
+		env := &container.Env[i]
+		for _, path := range moved {
+			if env.Value == path || strings.HasPrefix(env.Value, path+"/") {
+				env.Value = windowsPath(env.Value)
+				break
+			}
+		}
+	}
+}
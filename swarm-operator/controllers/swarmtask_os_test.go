@@ -0,0 +1,145 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Windows tasks", func() {
+	var task *swarmv1alpha1.SwarmTask
+
+	BeforeEach(func() {
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "dotnet-build", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				Description: "Build the solution",
+				OS:          swarmv1alpha1.WindowsOS,
+				Windows:     &swarmv1alpha1.TaskWindowsSpec{Build: "10.0.20348", RunAsUserName: "ContainerUser"},
+			},
+		}
+	})
+
+	It("moves mount paths and the variables pointing into them to the C: drive", func() {
+		Expect(windowsPath("/etc/swarm/tls")).To(Equal(`C:\etc\swarm\tls`))
+		Expect(windowsPath(`D:\data`)).To(Equal(`D:\data`))
+
+		runAsUser := int64(1000)
+		privileged := false
+		podSpec := &corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{RunAsUser: &runAsUser, SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
+			Containers: []corev1.Container{{
+				Name:            "task",
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged, Capabilities: &corev1.Capabilities{}},
+				VolumeMounts:    []corev1.VolumeMount{{Name: tlsVolumeName, MountPath: tlsMountPath}},
+				Env: []corev1.EnvVar{
+					{Name: "SWARM_TLS_CA_FILE", Value: tlsMountPath + "/ca.crt"},
+					{Name: "SWARM_TLS_ENABLED", Value: "true"},
+					{Name: "OTHER", Value: "/etc/swarm/tlsother"},
+				},
+			}},
+		}
+		applyTaskOS(task, podSpec)
+
+		Expect(podSpec.OS.Name).To(Equal(corev1.Windows))
+		Expect(podSpec.NodeSelector).To(Equal(map[string]string{
+			corev1.LabelOSStable:     "windows",
+			corev1.LabelWindowsBuild: "10.0.20348",
+		}))
+		Expect(podSpec.Tolerations).To(HaveLen(2))
+		Expect(podSpec.SecurityContext.RunAsUser).To(BeNil())
+		Expect(podSpec.SecurityContext.SeccompProfile).To(BeNil())
+		Expect(*podSpec.SecurityContext.WindowsOptions.RunAsUserName).To(Equal("ContainerUser"))
+
+		container := podSpec.Containers[0]
+		Expect(container.SecurityContext.Privileged).To(BeNil())
+		Expect(container.SecurityContext.Capabilities).To(BeNil())
+		Expect(container.VolumeMounts[0].MountPath).To(Equal(`C:\etc\swarm\tls`))
+		Expect(container.Env[0].Value).To(Equal(`C:\etc\swarm\tls\ca.crt`))
+		Expect(container.Env[1].Value).To(Equal("true"))
+		Expect(container.Env[2].Value).To(Equal("/etc/swarm/tlsother"))
+	})
+
+	It("leaves Linux tasks alone", func() {
+		task.Spec.OS = ""
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applyTaskOS(task, podSpec)
+		Expect(podSpec.OS).To(BeNil())
+		Expect(podSpec.NodeSelector).To(BeEmpty())
+	})
+
+	It("runs the PowerShell placeholder or the Windows executor image", func() {
+		reconciler := &SwarmTaskReconciler{Executor: ExecutorConfig{Image: "ghcr.io/acme/executor:v1"}}
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task", Image: placeholderExecutorImage}}}
+		Expect(reconciler.applyExecutor(context.Background(), task, &swarmv1alpha1.SwarmCluster{}, "default", nil, podSpec, "")).To(Succeed())
+		Expect(podSpec.Containers[0].Image).To(Equal(placeholderWindowsExecutorImage))
+		Expect(podSpec.Containers[0].Command).To(Equal(windowsCommand("Write-Output 'Executing task: Build the solution'")))
+
+		reconciler.Executor.WindowsImage = "ghcr.io/acme/executor-windows:v1"
+		reconciler.Executor.ScriptsConfigMap = "executor-scripts"
+		podSpec = &corev1.PodSpec{Containers: []corev1.Container{{Name: "task", Image: placeholderExecutorImage}}}
+		Expect(reconciler.applyExecutor(context.Background(), task, &swarmv1alpha1.SwarmCluster{}, "default", nil, podSpec, "")).To(Succeed())
+		Expect(podSpec.Containers[0].Image).To(Equal("ghcr.io/acme/executor-windows:v1"))
+		Expect(podSpec.Containers[0].Command).To(ContainElement(`C:\scripts\entrypoint.ps1`))
+		Expect(podSpec.Containers[0].Args).To(Equal([]string{`C:\scripts\task.ps1`}))
+	})
+
+	It("checks out repositories with PowerShell", func() {
+		task.Spec.Repositories = []string{"acme/app"}
+		task.Spec.Checkout = &swarmv1alpha1.GitCheckoutSpec{Image: "ghcr.io/acme/git-windows:2.43", Ref: "main"}
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applyGitCheckout(task, podSpec, "dotnet-build-github-token")
+		applyTaskOS(task, podSpec)
+
+		checkout := podSpec.InitContainers[0]
+		Expect(checkout.Command[0]).To(Equal(windowsShell))
+		script := checkout.Command[len(checkout.Command)-1]
+		Expect(script).To(ContainSubstring(`Invoke-Git @auth -C 'C:\workspace\app' fetch -q --depth 1 origin 'main'`))
+		Expect(script).NotTo(ContainSubstring("credential.helper"))
+		Expect(checkout.Env).To(HaveLen(1))
+		Expect(checkout.VolumeMounts[0].MountPath).To(Equal(`C:\workspace`))
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: executor.EnvWorkspace, Value: `C:\workspace`}))
+	})
+
+	It("rejects Linux-only features", func() {
+		task.Spec.GPU = &swarmv1alpha1.TaskGPUSpec{}
+		task.Spec.Checkout = &swarmv1alpha1.GitCheckoutSpec{}
+		task.Spec.PodTemplateOverrides = &runtime.RawExtension{Raw: []byte(
+			`{"spec":{"securityContext":{"seccompProfile":{"type":"RuntimeDefault"}},"containers":[{"name":"task","securityContext":{"capabilities":{"add":["NET_ADMIN"]}}}]}}`)}
+
+		errs := swarmv1alpha1.ValidateTaskOS(&task.Spec, field.NewPath("spec"))
+		Expect(errs.ToAggregate().Error()).To(And(
+			ContainSubstring("spec.gpu"),
+			ContainSubstring("spec.checkout.image"),
+			ContainSubstring("spec.podTemplateOverrides.spec.securityContext.seccompProfile"),
+			ContainSubstring("spec.podTemplateOverrides.spec.containers[0].securityContext.capabilities"),
+		))
+
+		linux := &swarmv1alpha1.SwarmTaskSpec{Windows: &swarmv1alpha1.TaskWindowsSpec{}}
+		Expect(swarmv1alpha1.ValidateTaskOS(linux, field.NewPath("spec"))).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/placement"
+)
+
+const binPackPlacement = "BinPack"
+
+// binPackingEnabled reports whether task Jobs of the cluster get node hints
+func binPackingEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	p := cluster.Spec.TaskDistribution.Placement
+	return p != nil && p.Strategy == binPackPlacement
+}
+
+// placementOptions converts the placement spec, ignoring thresholds that do
+// not parse
+func placementOptions(spec *swarmv1alpha1.TaskPlacementSpec) placement.Options {
+	opts := placement.Options{ReservedNodes: int(spec.ReservedNodes)}
+	if q, err := resource.ParseQuantity(spec.LargeTaskCPU); err == nil {
+		opts.LargeTask.MilliCPU = q.MilliValue()
+	}
+	if q, err := resource.ParseQuantity(spec.LargeTaskMemory); err == nil {
+		opts.LargeTask.Memory = q.Value()
+	}
+	return opts
+}
+
+// applyPlacementHint prefers the node bin-packing picks for the task pod.
+// Free capacity is tracked from the pods the operator watches, so the hint
+// is a preference the scheduler may overrule rather than a binding.
+func (r *SwarmTaskReconciler) applyPlacementHint(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, podSpec *corev1.PodSpec) error {
+	nodeList := &corev1.NodeList{}
+	if err := r.List(ctx, nodeList); err != nil {
+		return err
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods); err != nil {
+		return err
+	}
+
+	requested := map[string]placement.Capacity{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podRequests := placement.PodRequests(&pod.Spec)
+		used := requested[pod.Spec.NodeName]
+		used.MilliCPU += podRequests.MilliCPU
+		used.Memory += podRequests.Memory
+		requested[pod.Spec.NodeName] = used
+	}
+
+	var nodes []placement.Node
+	hostnames := map[string]string{}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if !placement.Schedulable(node, podSpec) {
+			continue
+		}
+		nodes = append(nodes, placement.Node{
+			Name: node.Name,
+			Allocatable: placement.Capacity{
+				MilliCPU: node.Status.Allocatable.Cpu().MilliValue(),
+				Memory:   node.Status.Allocatable.Memory().Value(),
+			},
+			Requested: requested[node.Name],
+		})
+		hostnames[node.Name] = node.Name
+		if hostname := node.Labels[corev1.LabelHostname]; hostname != "" {
+			hostnames[node.Name] = hostname
+		}
+	}
+
+	name, ok := placement.Pick(nodes, placement.PodRequests(podSpec), placementOptions(cluster.Spec.TaskDistribution.Placement))
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.RecordPlacementHint(cluster.Namespace, cluster.Name, ok, placement.Efficiency(nodes))
+	}
+	if !ok {
+		log.FromContext(ctx).Info("No node fits the task, leaving placement to the scheduler")
+		return nil
+	}
+
+	mergeNodeAffinity(podSpec, &corev1.NodeAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+			Weight: 100,
+			Preference: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      corev1.LabelHostname,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{hostnames[name]},
+				}},
+			},
+		}},
+	})
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task placement hints", func() {
+	var (
+		reconciler *SwarmTaskReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+	)
+
+	resources := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelHostname: name}},
+			Status:     corev1.NodeStatus{Allocatable: resources("8", "32Gi")},
+		}
+	}
+
+	pod := func(name, nodeName, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "claude-flow-swarm"},
+			Spec: corev1.PodSpec{
+				NodeName:   nodeName,
+				Containers: []corev1.Container{{Name: "task", Resources: corev1.ResourceRequirements{Requests: resources(cpu, "1Gi")}}},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		k8sClient := newTestClient(node("node-a"), node("node-b"), node("node-c"),
+			pod("busy", "node-a", "6"), pod("some", "node-b", "2"))
+		reconciler = &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme}
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{TaskDistribution: swarmv1alpha1.TaskDistributionSpec{
+				Placement: &swarmv1alpha1.TaskPlacementSpec{Strategy: binPackPlacement, LargeTaskCPU: "4", ReservedNodes: 1},
+			}},
+		}
+	})
+
+	preferredNode := func(podSpec *corev1.PodSpec) string {
+		preferred := podSpec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		Expect(preferred).To(HaveLen(1))
+		return preferred[0].Preference.MatchExpressions[0].Values[0]
+	}
+
+	It("packs small tasks onto the fullest node", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "task", Resources: corev1.ResourceRequirements{Requests: resources("1", "1Gi")},
+		}}}
+		Expect(reconciler.applyPlacementHint(context.Background(), cluster, podSpec)).To(Succeed())
+		Expect(preferredNode(podSpec)).To(Equal("node-a"))
+	})
+
+	It("sends large tasks to the reserved headroom", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "task", Resources: corev1.ResourceRequirements{Requests: resources("7", "1Gi")},
+		}}}
+		Expect(reconciler.applyPlacementHint(context.Background(), cluster, podSpec)).To(Succeed())
+		Expect(preferredNode(podSpec)).To(Equal("node-c"))
+	})
+
+	It("leaves tasks no node fits to the scheduler", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "task", Resources: corev1.ResourceRequirements{Requests: resources("16", "1Gi")},
+		}}}
+		Expect(reconciler.applyPlacementHint(context.Background(), cluster, podSpec)).To(Succeed())
+		Expect(podSpec.Affinity).To(BeNil())
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	// planAnnotation holds a task that has not started yet: the reconciler
+	// renders the Job it would run into a ConfigMap instead of creating it
+	planAnnotation = "swarm.claudeflow.io/plan"
+
+	// planManifestKey is the ConfigMap key of the rendered Job manifest
+	planManifestKey = "job.yaml"
+)
+
+// planRequested reports whether the task asks for a plan and has not
+// started a Job yet. Tasks that already run ignore the annotation.
+func planRequested(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Annotations[planAnnotation] == "true" &&
+		task.Status.JobName == "" && task.Status.StartTime == nil
+}
+
+func planConfigMapName(task *swarmv1alpha1.SwarmTask) string {
+	return task.Name + "-plan"
+}
+
+// reconcilePlan renders the Job of a planned task into a ConfigMap and
+// keeps the task pending until the annotation is removed. Nothing the Job
+// depends on is created: GitHub tokens are not minted and config templates
+// are not rendered, so the manifest only references Secrets by name and
+// never carries their values. Placement hints and warm pool claims are
+// decided when the Job is created and are not part of the plan.
+func (r *SwarmTaskReconciler) reconcilePlan(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string) (ctrl.Result, error) {
+	var githubTokenSecret string
+	if task.Spec.GitHubApp != nil && len(task.Spec.Repositories) > 0 {
+		githubTokenSecret = githubTokenSecretName(task)
+	}
+	job, _, err := r.buildJob(ctx, task, cluster, namespace, githubTokenSecret)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to render planned Job")
+		r.Recorder.Event(task, corev1.EventTypeWarning, "PlanFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+	utils.ApplyImageConfig(&job.Spec.Template.Spec, taskImageConfig(task, cluster))
+
+	manifest, err := renderJobManifest(job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: planConfigMapName(task), Namespace: task.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Labels = map[string]string{"swarm.claudeflow.io/task": task.Name}
+		configMap.Data = map[string]string{
+			planManifestKey: manifest,
+			"generation":    fmt.Sprint(task.Generation),
+		}
+		return controllerutil.SetControllerReference(task, configMap, r.Scheme)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	previous := task.Status.Plan
+	if previous != nil && previous.ObservedGeneration == task.Generation &&
+		previous.JobName == job.Name && previous.Namespace == namespace && task.Status.Phase == "Pending" {
+		return ctrl.Result{}, nil
+	}
+	task.Status.Plan = &swarmv1alpha1.TaskPlanStatus{
+		ObservedGeneration: task.Generation,
+		PlannedAt:          metav1.Now(),
+		JobName:            job.Name,
+		Namespace:          namespace,
+		ConfigMap:          configMap.Name,
+	}
+	task.Status.Phase = "Pending"
+	task.Status.Message = fmt.Sprintf("Planned, remove the %s annotation to run the task", planAnnotation)
+	if err := r.Status().Update(ctx, task); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(task, corev1.EventTypeNormal, "Planned",
+		"Rendered Job %s/%s into ConfigMap %s", namespace, job.Name, configMap.Name)
+	return ctrl.Result{}, nil
+}
+
+// renderJobManifest marshals a Job built by the reconciler as the YAML
+// manifest it would be created from
+func renderJobManifest(job *batchv1.Job) (string, error) {
+	job = job.DeepCopy()
+	job.TypeMeta = metav1.TypeMeta{APIVersion: batchv1.SchemeGroupVersion.String(), Kind: "Job"}
+	job.Status = batchv1.JobStatus{}
+	out, err := yaml.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
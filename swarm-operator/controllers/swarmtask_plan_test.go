@@ -0,0 +1,117 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/yaml"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task plans", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmTaskReconciler
+	)
+
+	key := types.NamespacedName{Name: "review", Namespace: "default"}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"}}
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: key.Name, Namespace: key.Namespace, UID: "review-uid", Generation: 1,
+				Annotations: map[string]string{planAnnotation: "true"},
+			},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				Description:  "Review the pull request",
+				GitHubApp:    &swarmv1alpha1.GitHubAppConfig{AppID: 1, PrivateKeyRef: swarmv1alpha1.SecretKeyRef{Name: "app-key", Key: "key"}},
+				Repositories: []string{"claude-flow/swarm-operator"},
+			},
+		}
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClient(task, cluster),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	plan := func() *swarmv1alpha1.SwarmTask {
+		current := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		_, err := reconciler.reconcilePlan(ctx, current, cluster, "claude-flow-swarm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		return current
+	}
+
+	It("renders the Job into a ConfigMap without creating it", func() {
+		current := plan()
+		Expect(current.Status.Phase).To(Equal("Pending"))
+		Expect(current.Status.Plan).NotTo(BeNil())
+		Expect(current.Status.Plan.JobName).To(Equal("review-job"))
+		Expect(current.Status.Plan.Namespace).To(Equal("claude-flow-swarm"))
+		Expect(current.Status.Plan.ObservedGeneration).To(Equal(int64(1)))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "review-plan", Namespace: "default"}, configMap)).To(Succeed())
+		Expect(metav1.IsControlledBy(configMap, current)).To(BeTrue())
+		job := &batchv1.Job{}
+		Expect(yaml.Unmarshal([]byte(configMap.Data[planManifestKey]), job)).To(Succeed())
+		Expect(job.Kind).To(Equal("Job"))
+		Expect(job.Name).To(Equal("review-job"))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(HaveField("ValueFrom.SecretKeyRef.Name", "review-github-token")))
+
+		// Neither the Job nor the token it references were created
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "review-job", Namespace: "claude-flow-swarm"}, &batchv1.Job{})).NotTo(Succeed())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "review-github-token", Namespace: "claude-flow-swarm"}, &corev1.Secret{})).NotTo(Succeed())
+	})
+
+	It("renders the plan again once the spec changes", func() {
+		plannedAt := plan().Status.Plan.PlannedAt
+		Expect(plan().Status.Plan.PlannedAt).To(Equal(plannedAt))
+
+		current := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		current.Spec.Description = "Review the release"
+		current.Generation = 2
+		Expect(reconciler.Update(ctx, current)).To(Succeed())
+		Expect(plan().Status.Plan.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("ignores the annotation once the task started", func() {
+		Expect(planRequested(task)).To(BeTrue())
+		task.Status.JobName = "review-job"
+		Expect(planRequested(task)).To(BeFalse())
+		task.Status.JobName = ""
+		task.Annotations[planAnnotation] = "false"
+		Expect(planRequested(task)).To(BeFalse())
+	})
+})
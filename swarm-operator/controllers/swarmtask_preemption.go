@@ -0,0 +1,306 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+const (
+	// checkpointRequestedAnnotation is set on a task pod to ask the executor
+	// for an immediate checkpoint. Executors see it through the downward API
+	// file referenced by SWARM_CHECKPOINT_SIGNAL_FILE.
+	checkpointRequestedAnnotation = "swarm.claudeflow.io/checkpoint-requested"
+
+	// taskNamespaceLabel maps task pods, which may run in another namespace,
+	// back to their SwarmTask
+	taskNamespaceLabel = "swarm.claudeflow.io/task-namespace"
+
+	podInfoVolumeName = "swarm-podinfo"
+	podInfoMountPath  = "/etc/swarm/podinfo"
+
+	defaultCheckpointGracePeriod = int64(30)
+
+	// checkpointPollInterval is how often a preempted pod is checked for
+	// the executor to exit after its checkpoint
+	checkpointPollInterval = 5 * time.Second
+)
+
+// spotTaintKeys are the taints cloud providers put on spot/preemptible nodes
+var spotTaintKeys = []string{
+	"cloud.google.com/gke-spot",
+	"cloud.google.com/gke-preemptible",
+	"kubernetes.azure.com/scalesetpriority",
+	"karpenter.sh/capacity-type",
+}
+
+// preemptionEnabled reports whether the task opted into spot/preemptible nodes
+func preemptionEnabled(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Spec.Preemptible != nil && task.Spec.Preemptible.Enabled
+}
+
+// applyPreemptionPolicy configures the task pod for spot nodes on the first
+// attempts and pins it to stable nodes once it has been preempted
+func applyPreemptionPolicy(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) {
+	if !preemptionEnabled(task) {
+		return
+	}
+	spec := task.Spec.Preemptible
+
+	gracePeriod := int64(checkpointGracePeriod(task).Seconds())
+	podSpec.TerminationGracePeriodSeconds = &gracePeriod
+
+	// Expose pod annotations so the executor can watch for checkpoint requests
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: podInfoVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path:     "annotations",
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"},
+					},
+				},
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      podInfoVolumeName,
+			MountPath: podInfoMountPath,
+			ReadOnly:  true,
+		})
+		podSpec.Containers[i].Env = append(podSpec.Containers[i].Env,
+			corev1.EnvVar{Name: executor.EnvCheckpointSignalFile, Value: podInfoMountPath + "/annotations"},
+			corev1.EnvVar{Name: executor.EnvCheckpointAnnotation, Value: checkpointRequestedAnnotation},
+		)
+	}
+
+	if task.Status.Preemptions == 0 {
+		for _, key := range spotTaintKeys {
+			podSpec.Tolerations = append(podSpec.Tolerations, corev1.Toleration{
+				Key:      key,
+				Operator: corev1.TolerationOpExists,
+			})
+		}
+		podSpec.Tolerations = append(podSpec.Tolerations, spec.Tolerations...)
+		return
+	}
+
+	// Rescheduled after a preemption: stay off spot capacity
+	if len(spec.StableNodeSelector) > 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		for k, v := range spec.StableNodeSelector {
+			podSpec.NodeSelector[k] = v
+		}
+		return
+	}
+
+	mergeNodeAffinity(podSpec, &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "cloud.google.com/gke-spot", Operator: corev1.NodeSelectorOpDoesNotExist},
+						{Key: "cloud.google.com/gke-preemptible", Operator: corev1.NodeSelectorOpDoesNotExist},
+						{Key: "kubernetes.azure.com/scalesetpriority", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"spot"}},
+						{Key: "karpenter.sh/capacity-type", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"spot"}},
+						{Key: "eks.amazonaws.com/capacityType", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"SPOT"}},
+					},
+				},
+			},
+		},
+	})
+}
+
+// findPreemptedPod returns the first pod of the job that is being evicted or
+// preempted, or nil if none is
+func (r *SwarmTaskReconciler) findPreemptedPod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+
+	for i := range pods.Items {
+		if isPodPreempted(&pods.Items[i]) {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// isPodPreempted detects evictions, scheduler preemption and spot node
+// terminations through the DisruptionTarget condition or the Evicted reason
+func isPodPreempted(pod *corev1.Pod) bool {
+	if pod.Status.Reason == "Evicted" || pod.Status.Reason == "Preempting" {
+		return true
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.DisruptionTarget && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// checkpointGracePeriod is how long the executor of a preempted pod gets to
+// write its checkpoint
+func checkpointGracePeriod(task *swarmv1alpha1.SwarmTask) time.Duration {
+	seconds := defaultCheckpointGracePeriod
+	if spec := task.Spec.Preemptible; spec != nil && spec.CheckpointGracePeriodSeconds != nil {
+		seconds = *spec.CheckpointGracePeriodSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// checkpointRequestTime returns when a checkpoint was requested for the Job,
+// which is recorded on the Job so the request outlives the preempted pod
+func checkpointRequestTime(job *batchv1.Job) (time.Time, bool) {
+	value, ok := job.Annotations[checkpointRequestedAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	requested, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, true
+	}
+	return requested, true
+}
+
+// executorExited reports whether the task container of the pod has stopped,
+// the executor's acknowledgement that its checkpoint is written
+func executorExited(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == "task" && cs.State.Terminated != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// handlePreemption asks the executor of the preempted pod for a checkpoint
+// and waits until the executor exits, or the checkpoint grace period ends,
+// before it tears down the Job and reschedules the task to resume on stable
+// nodes. The kubelet refreshes the downward API file the executor watches
+// lazily, so deleting the Job right away would usually beat the request.
+// It returns how long to wait before checking again, zero once the task is
+// rescheduled. pod is nil when the preempted pod is already gone.
+func (r *SwarmTaskReconciler) handlePreemption(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, pod *corev1.Pod) (time.Duration, error) {
+	log := log.FromContext(ctx)
+
+	requestedAt, requested := checkpointRequestTime(job)
+	if !requested {
+		requestedAt = time.Now().UTC()
+		stamp := requestedAt.Format(time.RFC3339)
+		log.Info("Task pod preempted, requesting a checkpoint", "pod", pod.Name, "node", pod.Spec.NodeName)
+
+		// The pod may already be gone, which is fine
+		if _, ok := pod.Annotations[checkpointRequestedAnnotation]; !ok {
+			patch := client.MergeFrom(pod.DeepCopy())
+			if pod.Annotations == nil {
+				pod.Annotations = map[string]string{}
+			}
+			pod.Annotations[checkpointRequestedAnnotation] = stamp
+			if err := r.Patch(ctx, pod, patch); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "Failed to request checkpoint", "pod", pod.Name)
+			}
+		}
+
+		patch := client.MergeFrom(job.DeepCopy())
+		if job.Annotations == nil {
+			job.Annotations = map[string]string{}
+		}
+		job.Annotations[checkpointRequestedAnnotation] = stamp
+		if err := r.Patch(ctx, job, patch); err != nil {
+			return 0, err
+		}
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "CheckpointRequested",
+			"Pod %s preempted on node %s, waiting up to %s for a checkpoint", pod.Name, pod.Spec.NodeName, checkpointGracePeriod(task))
+	}
+
+	if pod != nil && !executorExited(pod) {
+		if wait := time.Until(requestedAt.Add(checkpointGracePeriod(task))); wait > 0 {
+			return min(wait, checkpointPollInterval), nil
+		}
+	}
+
+	// Pick up the checkpoint the executor reported before it exited
+	r.collectExecutorReport(ctx, task, job)
+
+	// Stop the Job controller from recreating the pod on spot capacity
+	propagation := metav1.DeletePropagationBackground
+	if err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	}
+
+	task.Status.ResumeFromCheckpoint = true
+	task.Status.Preemptions++
+	task.Status.Attempt++
+	task.Status.Phase = "Pending"
+	task.Status.Message = fmt.Sprintf("Job %s preempted, resuming on a stable node", job.Name)
+	if pod != nil {
+		task.Status.Message = fmt.Sprintf("Pod %s preempted on node %s, resuming on a stable node", pod.Name, pod.Spec.NodeName)
+	}
+	if err := r.Status().Update(ctx, task); err != nil {
+		return 0, err
+	}
+
+	r.Recorder.Event(task, corev1.EventTypeWarning, "Preempted", task.Status.Message)
+	return 0, nil
+}
+
+// isTaskPod reports whether the pod runs a task, the only pods the task
+// controller watches
+func isTaskPod(obj client.Object) bool {
+	return obj.GetLabels()[taskNamespaceLabel] != ""
+}
+
+// isUnownedTaskObject reports whether the object belongs to a task in
+// another namespace, which cannot own it
+func isUnownedTaskObject(obj client.Object) bool {
+	namespace := obj.GetLabels()[taskNamespaceLabel]
+	return namespace != "" && namespace != obj.GetNamespace()
+}
+
+// mapToTask enqueues the SwarmTask a task pod or Job is labelled with
+func mapToTask(ctx context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, namespace := labels["swarm.claudeflow.io/task"], labels[taskNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
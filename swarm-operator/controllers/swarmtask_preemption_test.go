@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Task preemption", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		job        *batchv1.Job
+		pod        *corev1.Pod
+		recorder   *record.FakeRecorder
+		reconciler *SwarmTaskReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		grace := int64(60)
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				Preemptible: &swarmv1alpha1.PreemptibleSpec{Enabled: true, CheckpointGracePeriodSeconds: &grace},
+			},
+		}
+		job = &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "train-job", Namespace: "default"}}
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "train-job-x1",
+				Namespace: "default",
+				Labels:    map[string]string{"job-name": "train-job", "swarm.claudeflow.io/task": "train", taskNamespaceLabel: "default"},
+			},
+			Spec: corev1.PodSpec{NodeName: "spot-1"},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				Conditions:        []corev1.PodCondition{{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue}},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "task", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+			},
+		}
+
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClientBuilder().WithObjects(task, job, pod).WithStatusSubresource(&corev1.Pod{}).Build(),
+			Scheme:   testScheme,
+			Recorder: recorder,
+		}
+	})
+
+	stored := func() *swarmv1alpha1.SwarmTask {
+		stored := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(task), stored)).To(Succeed())
+		return stored
+	}
+
+	It("waits for the executor to checkpoint before rescheduling", func() {
+		preempted, err := reconciler.findPreemptedPod(ctx, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preempted.Name).To(Equal(pod.Name))
+
+		wait, err := reconciler.handlePreemption(ctx, task, job, preempted)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wait).To(Equal(checkpointPollInterval))
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+		Expect(pod.Annotations).To(HaveKey(checkpointRequestedAnnotation))
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(job), job)).To(Succeed())
+		_, requested := checkpointRequestTime(job)
+		Expect(requested).To(BeTrue())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal CheckpointRequested")))
+
+		// The executor writes its checkpoint and exits
+		report, err := executor.EncodeReport(&executor.Report{CheckpointRef: ".swarm/checkpoints/7.json"})
+		Expect(err).NotTo(HaveOccurred())
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 143, Message: string(report)},
+		}
+		Expect(reconciler.Status().Update(ctx, pod)).To(Succeed())
+
+		wait, err = reconciler.handlePreemption(ctx, task, job, pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wait).To(BeZero())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{}))).To(BeTrue())
+
+		status := stored().Status
+		Expect(status.Phase).To(Equal("Pending"))
+		Expect(status.Preemptions).To(Equal(int32(1)))
+		Expect(status.ResumeFromCheckpoint).To(BeTrue())
+		Expect(status.CheckpointRef).To(Equal(".swarm/checkpoints/7.json"))
+		Expect(stored().Spec.Resume).To(BeFalse())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning Preempted")))
+	})
+
+	It("reschedules once the grace period is over", func() {
+		job.Annotations = map[string]string{
+			checkpointRequestedAnnotation: time.Now().Add(-2 * time.Minute).UTC().Format(time.RFC3339),
+		}
+		Expect(reconciler.Update(ctx, job)).To(Succeed())
+
+		wait, err := reconciler.handlePreemption(ctx, task, job, pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wait).To(BeZero())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{}))).To(BeTrue())
+		Expect(stored().Status.ResumeFromCheckpoint).To(BeTrue())
+	})
+
+	It("reschedules when the preempted pod is already gone", func() {
+		job.Annotations = map[string]string{checkpointRequestedAnnotation: time.Now().UTC().Format(time.RFC3339)}
+		Expect(reconciler.Update(ctx, job)).To(Succeed())
+
+		wait, err := reconciler.handlePreemption(ctx, task, job, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wait).To(BeZero())
+		Expect(stored().Status.Message).To(Equal("Job train-job preempted, resuming on a stable node"))
+	})
+
+	It("keeps other placement rules when moving to stable nodes", func() {
+		podSpec := &corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "task"}},
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu-west-1a"}},
+							},
+						}},
+					},
+				},
+				PodAntiAffinity: &corev1.PodAntiAffinity{},
+			},
+		}
+		applyPreemptionPolicy(task, podSpec)
+		Expect(podSpec.Tolerations).To(ContainElement(corev1.Toleration{Key: "cloud.google.com/gke-spot", Operator: corev1.TolerationOpExists}))
+		Expect(*podSpec.TerminationGracePeriodSeconds).To(Equal(int64(60)))
+
+		podSpec.Tolerations = nil
+		task.Status.Preemptions = 1
+		applyPreemptionPolicy(task, podSpec)
+		Expect(podSpec.Tolerations).To(BeEmpty())
+		Expect(podSpec.Affinity.PodAntiAffinity).NotTo(BeNil())
+		terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].MatchExpressions[0].Key).To(Equal("topology.kubernetes.io/zone"))
+		Expect(terms[0].MatchExpressions).To(ContainElement(corev1.NodeSelectorRequirement{
+			Key: "cloud.google.com/gke-spot", Operator: corev1.NodeSelectorOpDoesNotExist,
+		}))
+	})
+
+	It("resumes rescheduled attempts without touching the spec", func() {
+		task.Status.ResumeFromCheckpoint = true
+		task.Status.CheckpointRef = ".swarm/checkpoints/7.json"
+		Expect(isTaskPod(pod)).To(BeTrue())
+		Expect(isTaskPod(&corev1.Pod{})).To(BeFalse())
+
+		env := reconciler.buildEnvironment(task, "")
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: executor.EnvResume, Value: "true"}))
+		Expect(env).To(ContainElement(corev1.EnvVar{Name: executor.EnvCheckpointRef, Value: ".swarm/checkpoints/7.json"}))
+	})
+})
@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// priorityClassPrefix names the PriorityClass of each task priority,
+// e.g. swarm-critical
+const priorityClassPrefix = "swarm-"
+
+// priorityClassValues leave room below the system classes and above the
+// default priority 0 of pods without a class
+var priorityClassValues = map[swarmv1alpha1.TaskPriority]int32{
+	swarmv1alpha1.CriticalPriority: 1000000,
+	swarmv1alpha1.HighPriority:     100000,
+	swarmv1alpha1.MediumPriority:   10000,
+	swarmv1alpha1.LowPriority:      1000,
+}
+
+// PriorityClassConfig maps task priorities to the swarm PriorityClasses
+type PriorityClassConfig struct {
+	// Enabled creates the classes on demand and runs task pods with the one
+	// of their priority
+	Enabled bool
+
+	// Preemption lets critical task pods preempt lower priority pods
+	Preemption bool
+}
+
+// priorityConfig returns the priority class settings in effect,
+// hot-reloaded from the SwarmOperatorConfig when the manager follows one
+func (r *SwarmTaskReconciler) priorityConfig() PriorityClassConfig {
+	if r.Config == nil {
+		return r.Priority
+	}
+	settings := r.Config.Get()
+	return PriorityClassConfig{Enabled: settings.PriorityClasses, Preemption: settings.PriorityPreemption}
+}
+
+// taskPriorityClassName returns the swarm PriorityClass of a task priority,
+// medium for tasks that set none
+func taskPriorityClassName(priority swarmv1alpha1.TaskPriority) string {
+	if _, ok := priorityClassValues[priority]; !ok {
+		priority = swarmv1alpha1.MediumPriority
+	}
+	return priorityClassPrefix + string(priority)
+}
+
+// swarmPriorityClass returns the desired PriorityClass of a task priority.
+// Only critical tasks preempt, and only when preemption is enabled.
+func swarmPriorityClass(priority swarmv1alpha1.TaskPriority, preemption bool) *schedulingv1.PriorityClass {
+	policy := corev1.PreemptNever
+	if preemption && priority == swarmv1alpha1.CriticalPriority {
+		policy = corev1.PreemptLowerPriority
+	}
+	return &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: taskPriorityClassName(priority),
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "swarm-operator",
+			},
+		},
+		Value:            priorityClassValues[priority],
+		PreemptionPolicy: &policy,
+		Description:      fmt.Sprintf("Swarm task pods of %s priority", priority),
+	}
+}
+
+// applyPriorityClass runs the task pod with the PriorityClass of the task
+// priority. Pod template overrides applied afterwards may replace it.
+func (r *SwarmTaskReconciler) applyPriorityClass(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) {
+	if !r.priorityConfig().Enabled {
+		return
+	}
+	podSpec.PriorityClassName = taskPriorityClassName(task.Spec.Priority)
+}
+
+// ensurePriorityClass creates the swarm PriorityClass a task pod references.
+// The value and preemption policy of a PriorityClass are immutable, so a
+// class left over from another preemption setting is recreated. Pods that
+// already run keep the priority they were admitted with. Classes of the
+// same name the operator did not create are used as they are.
+func (r *SwarmTaskReconciler) ensurePriorityClass(ctx context.Context, name string) error {
+	config := r.priorityConfig()
+	if !config.Enabled {
+		return nil
+	}
+	var desired *schedulingv1.PriorityClass
+	for priority := range priorityClassValues {
+		if taskPriorityClassName(priority) == name {
+			desired = swarmPriorityClass(priority, config.Preemption)
+		}
+	}
+	if desired == nil {
+		// Set by the pod template overrides of the task
+		return nil
+	}
+
+	existing := &schedulingv1.PriorityClass{}
+	err := r.Get(ctx, types.NamespacedName{Name: name}, existing)
+	if errors.IsNotFound(err) {
+		log.FromContext(ctx).Info("Creating PriorityClass", "name", name)
+		if err := r.Create(ctx, desired); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Value == desired.Value && existing.PreemptionPolicy != nil &&
+		*existing.PreemptionPolicy == *desired.PreemptionPolicy {
+		return nil
+	}
+	if existing.Labels["app.kubernetes.io/managed-by"] != "swarm-operator" {
+		// Respect a class the cluster admin created under the same name
+		return nil
+	}
+	log.FromContext(ctx).Info("Recreating PriorityClass", "name", name, "preemptionPolicy", *desired.PreemptionPolicy)
+	if err := r.Delete(ctx, existing); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err := r.Create(ctx, desired); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
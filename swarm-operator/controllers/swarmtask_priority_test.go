@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/operatorconfig"
+)
+
+var _ = Describe("Task priority classes", func() {
+	var (
+		ctx        context.Context
+		reconciler *SwarmTaskReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+	)
+
+	newTask := func(name string, priority swarmv1alpha1.TaskPriority) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "cluster", Description: name, Priority: priority},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClient(),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+			Priority: PriorityClassConfig{Enabled: true},
+		}
+		cluster = &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"}}
+	})
+
+	It("maps task priorities to the swarm classes", func() {
+		Expect(taskPriorityClassName(swarmv1alpha1.CriticalPriority)).To(Equal("swarm-critical"))
+		Expect(taskPriorityClassName(swarmv1alpha1.LowPriority)).To(Equal("swarm-low"))
+		Expect(taskPriorityClassName("")).To(Equal("swarm-medium"))
+
+		Expect(swarmPriorityClass(swarmv1alpha1.CriticalPriority, false).Value).
+			To(BeNumerically(">", swarmPriorityClass(swarmv1alpha1.HighPriority, false).Value))
+		Expect(*swarmPriorityClass(swarmv1alpha1.CriticalPriority, true).PreemptionPolicy).To(Equal(corev1.PreemptLowerPriority))
+		Expect(*swarmPriorityClass(swarmv1alpha1.HighPriority, true).PreemptionPolicy).To(Equal(corev1.PreemptNever))
+		Expect(*swarmPriorityClass(swarmv1alpha1.CriticalPriority, false).PreemptionPolicy).To(Equal(corev1.PreemptNever))
+	})
+
+	It("creates the class and runs the task pod with it", func() {
+		task := newTask("urgent", swarmv1alpha1.CriticalPriority)
+		Expect(reconciler.Create(ctx, task)).To(Succeed())
+
+		job, err := reconciler.createOrUpdateJob(ctx, task, cluster, "default", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Spec.Template.Spec.PriorityClassName).To(Equal("swarm-critical"))
+
+		class := &schedulingv1.PriorityClass{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-critical"}, class)).To(Succeed())
+		Expect(class.Value).To(Equal(int32(1000000)))
+		Expect(*class.PreemptionPolicy).To(Equal(corev1.PreemptNever))
+	})
+
+	It("recreates the class when preemption is switched on", func() {
+		store := operatorconfig.NewStore(operatorconfig.Settings{PriorityClasses: true})
+		reconciler.Config = store
+		Expect(reconciler.ensurePriorityClass(ctx, "swarm-critical")).To(Succeed())
+
+		settings := store.Get()
+		settings.PriorityPreemption = true
+		store.Apply(settings)
+		Expect(reconciler.ensurePriorityClass(ctx, "swarm-critical")).To(Succeed())
+
+		class := &schedulingv1.PriorityClass{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-critical"}, class)).To(Succeed())
+		Expect(*class.PreemptionPolicy).To(Equal(corev1.PreemptLowerPriority))
+	})
+
+	It("leaves task pods without a class when disabled", func() {
+		reconciler.Priority.Enabled = false
+		task := newTask("plain", swarmv1alpha1.HighPriority)
+		Expect(reconciler.Create(ctx, task)).To(Succeed())
+
+		job, err := reconciler.createOrUpdateJob(ctx, task, cluster, "default", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Spec.Template.Spec.PriorityClassName).To(BeEmpty())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm-high"}, &schedulingv1.PriorityClass{})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// rerunAnnotation asks the operator to clone a finished task into a new
+	// run, e.g. `kubectl annotate swarmtask deploy swarm.claudeflow.io/rerun=true`.
+	// Tasks still running are cloned once they finish.
+	rerunAnnotation = "swarm.claudeflow.io/rerun"
+
+	// runOfLabel names the task the runs of a task were cloned from
+	runOfLabel = "swarm.claudeflow.io/run-of"
+
+	// runIndexLabel is the index of a run, the original task being run 1
+	runIndexLabel = "swarm.claudeflow.io/run-index"
+)
+
+// handleRerun clones a finished task into its next run, then clears the
+// rerun annotation. The runs of a task are named <task>-run-<index> and
+// labelled with the original task, so its run history is a label selector
+// away.
+func (r *SwarmTaskReconciler) handleRerun(ctx context.Context, task *swarmv1alpha1.SwarmTask) (ctrl.Result, error) {
+	root := task.Labels[runOfLabel]
+	if root == "" {
+		root = task.Name
+	}
+	runs := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, runs, client.InNamespace(task.Namespace), client.MatchingLabels{runOfLabel: root}); err != nil {
+		return ctrl.Result{}, err
+	}
+	index := 1
+	for _, run := range runs.Items {
+		if i, err := strconv.Atoi(run.Labels[runIndexLabel]); err == nil && i > index {
+			index = i
+		}
+	}
+	index++
+
+	run := rerunTask(task, root, index)
+	if err := r.Create(ctx, run); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, err
+		}
+		// Created by an earlier reconcile the cache has not caught up with
+		existing := &swarmv1alpha1.SwarmTask{}
+		if err := r.Get(ctx, types.NamespacedName{Name: run.Name, Namespace: run.Namespace}, existing); err != nil {
+			return ctrl.Result{}, err
+		}
+		if existing.Labels[runOfLabel] != root {
+			return ctrl.Result{}, fmt.Errorf("task %s already exists and is not a run of task %s", run.Name, root)
+		}
+	} else {
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "Rerun", "Task rerun as %s", run.Name)
+	}
+
+	if task.Status.RerunAs != run.Name {
+		task.Status.RerunAs = run.Name
+		if err := r.Status().Update(ctx, task); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	delete(task.Annotations, rerunAnnotation)
+	if err := r.Update(ctx, task); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// rerunTask clones the spec, labels and annotations of a task into its run
+// with the given index. Operator-managed labels and annotations other than
+// the tenant are not carried over, and the clone neither deduplicates against nor reuses the
+// cached result of the task it reruns.
+func rerunTask(task *swarmv1alpha1.SwarmTask, root string, index int) *swarmv1alpha1.SwarmTask {
+	spec := task.Spec.DeepCopy()
+	spec.Resume = false
+	spec.IdempotencyKey = ""
+	spec.CachePolicy = "none"
+
+	labels := map[string]string{}
+	for k, v := range task.Labels {
+		if !strings.HasPrefix(k, "swarm.claudeflow.io/") || k == tenantLabel {
+			labels[k] = v
+		}
+	}
+	labels[runOfLabel] = root
+	labels[runIndexLabel] = strconv.Itoa(index)
+
+	var annotations map[string]string
+	for k, v := range task.Annotations {
+		if strings.HasPrefix(k, "swarm.claudeflow.io/") || k == corev1.LastAppliedConfigAnnotation {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[k] = v
+	}
+
+	return &swarmv1alpha1.SwarmTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-run-%d", root, index),
+			Namespace:   task.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: *spec,
+	}
+}
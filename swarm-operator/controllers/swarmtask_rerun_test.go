@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task reruns", func() {
+	var (
+		ctx        context.Context
+		reconciler *SwarmTaskReconciler
+		task       *swarmv1alpha1.SwarmTask
+	)
+
+	rerun := func(name string) *swarmv1alpha1.SwarmTask {
+		current := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, current)).To(Succeed())
+		current.Annotations = map[string]string{rerunAnnotation: "true"}
+		Expect(reconciler.Update(ctx, current)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(current)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(current), current)).To(Succeed())
+		return current
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "deploy",
+				Namespace: "default",
+				Labels:    map[string]string{"team": "a", fanOutLabel: "parent", tenantLabel: "acme"},
+			},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster:   "swarm",
+				Description:    "Deploy the app",
+				Type:           "deployment",
+				IdempotencyKey: "deploy-42",
+				CachePolicy:    "reuse",
+				Resume:         true,
+			},
+			Status: swarmv1alpha1.SwarmTaskStatus{Phase: "Failed", JobName: "deploy-job"},
+		}
+		k8sClient := newTestClient(task)
+		reconciler = &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("clones a finished task into numbered runs", func() {
+		original := rerun("deploy")
+		Expect(original.Annotations).NotTo(HaveKey(rerunAnnotation))
+		Expect(original.Status.RerunAs).To(Equal("deploy-run-2"))
+		Expect(original.Status.Phase).To(Equal("Failed"))
+
+		run := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "deploy-run-2", Namespace: "default"}, run)).To(Succeed())
+		Expect(run.Labels).To(Equal(map[string]string{
+			"team": "a", tenantLabel: "acme", runOfLabel: "deploy", runIndexLabel: "2",
+		}))
+		Expect(run.Spec.Description).To(Equal("Deploy the app"))
+		Expect(run.Spec.IdempotencyKey).To(BeEmpty())
+		Expect(run.Spec.CachePolicy).To(Equal("none"))
+		Expect(run.Spec.Resume).To(BeFalse())
+		Expect(run.Status.Phase).To(BeEmpty())
+
+		// Rerunning a run continues the numbering of the original
+		run.Status.Phase = "Completed"
+		Expect(reconciler.Status().Update(ctx, run)).To(Succeed())
+		Expect(rerun("deploy-run-2").Status.RerunAs).To(Equal("deploy-run-3"))
+		Expect(rerun("deploy").Status.RerunAs).To(Equal("deploy-run-4"))
+
+		runs := &swarmv1alpha1.SwarmTaskList{}
+		Expect(reconciler.List(ctx, runs, client.MatchingLabels{runOfLabel: "deploy"})).To(Succeed())
+		Expect(runs.Items).To(HaveLen(3))
+	})
+
+	It("keeps the annotation of a running task until it finishes", func() {
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(task), task)).To(Succeed())
+		task.Status.Phase = "Running"
+		Expect(reconciler.Status().Update(ctx, task)).To(Succeed())
+		Expect(taskFinished(task)).To(BeFalse())
+
+		// The rest of the reconcile needs the cluster, the rerun check
+		// runs before it
+		task.Annotations = map[string]string{rerunAnnotation: "true"}
+		Expect(reconciler.Update(ctx, task)).To(Succeed())
+		_, _ = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(task)})
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(task), task)).To(Succeed())
+		Expect(task.Annotations).To(HaveKey(rerunAnnotation))
+		Expect(task.Status.RerunAs).To(BeEmpty())
+
+		runs := &swarmv1alpha1.SwarmTaskList{}
+		Expect(reconciler.List(ctx, runs, client.MatchingLabels{runOfLabel: "deploy"})).To(Succeed())
+		Expect(runs.Items).To(BeEmpty())
+	})
+
+	It("rejects spec changes once a task is dispatched", func() {
+		pending := task.DeepCopy()
+		pending.Status = swarmv1alpha1.SwarmTaskStatus{Phase: "Pending"}
+		updated := pending.DeepCopy()
+		updated.Spec.Description = "Deploy something else"
+		Expect(swarmv1alpha1.ValidateTaskSpecUpdate(&updated.Spec, pending, nil)).To(BeEmpty())
+
+		Expect(swarmv1alpha1.ValidateTaskSpecUpdate(&updated.Spec, task, nil)).To(HaveLen(1))
+		resumed := task.DeepCopy()
+		resumed.Spec.Resume = false
+		Expect(swarmv1alpha1.ValidateTaskSpecUpdate(&resumed.Spec, task, nil)).To(BeEmpty())
+	})
+})
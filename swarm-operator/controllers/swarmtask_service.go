@@ -0,0 +1,338 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// ConditionTypeServiceReady reports whether every replica of a service
+	// task runs its current spec and is ready
+	ConditionTypeServiceReady = "ServiceReady"
+
+	ReasonServiceAvailable  = "ServiceAvailable"
+	ReasonRolloutInProgress = "RolloutInProgress"
+	ReasonRolloutStalled    = "RolloutStalled"
+
+	defaultServicePort int32 = 8080
+
+	// servicePortName names the port of the task container and Service
+	servicePortName = "http"
+)
+
+// serviceTask reports whether the task runs as a long-lived service
+func serviceTask(task *swarmv1alpha1.SwarmTask) bool {
+	return task.Spec.Mode == swarmv1alpha1.ServiceTaskMode
+}
+
+// servicePort is the port the service task serves on
+func servicePort(task *swarmv1alpha1.SwarmTask) int32 {
+	if task.Spec.Service != nil && task.Spec.Service.Port != 0 {
+		return task.Spec.Service.Port
+	}
+	return defaultServicePort
+}
+
+// serviceReplicas is the number of replicas of the service task
+func serviceReplicas(task *swarmv1alpha1.SwarmTask) int32 {
+	if task.Spec.Service != nil && task.Spec.Service.Replicas != nil {
+		return *task.Spec.Service.Replicas
+	}
+	return 1
+}
+
+// serviceSelector selects the pods of the service task
+func serviceSelector(task *swarmv1alpha1.SwarmTask) map[string]string {
+	return map[string]string{
+		"swarm.claudeflow.io/task": task.Name,
+		taskNamespaceLabel:         task.Namespace,
+	}
+}
+
+// serviceURL is where the service of the task is reachable in the cluster
+func serviceURL(task *swarmv1alpha1.SwarmTask, namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", task.Name, namespace, servicePort(task))
+}
+
+// buildServiceDeployment builds the Deployment of a service task from the
+// pod template its Job would have, so service tasks get the executor,
+// defaults, isolation and overrides of any other task. The task container
+// serves on the service port and gets a readiness probe unless the
+// overrides gave it one.
+func (r *SwarmTaskReconciler) buildServiceDeployment(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string, githubTokenSecret string) (*appsv1.Deployment, *swarmv1alpha1.SwarmTenant, error) {
+	job, tenant, err := r.buildJob(ctx, task, cluster, namespace, githubTokenSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := job.Spec.Template
+	template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+
+	port := servicePort(task)
+	container := &template.Spec.Containers[0]
+	container.Env = append(container.Env, corev1.EnvVar{Name: executor.EnvServicePort, Value: strconv.Itoa(int(port))})
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          servicePortName,
+		ContainerPort: port,
+		Protocol:      corev1.ProtocolTCP,
+	})
+	if container.ReadinessProbe == nil {
+		handler := corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromString(servicePortName)}}
+		if task.Spec.Service != nil && task.Spec.Service.ReadinessPath != "" {
+			handler = corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+				Path: task.Spec.Service.ReadinessPath,
+				Port: intstr.FromString(servicePortName),
+			}}
+		}
+		container.ReadinessProbe = &corev1.Probe{ProbeHandler: handler, PeriodSeconds: 10}
+	}
+	if task.Spec.Service != nil {
+		for _, gate := range task.Spec.Service.ReadinessGates {
+			template.Spec.ReadinessGates = append(template.Spec.ReadinessGates,
+				corev1.PodReadinessGate{ConditionType: corev1.PodConditionType(gate)})
+		}
+	}
+
+	replicas := serviceReplicas(task)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        task.Name,
+			Namespace:   namespace,
+			Labels:      job.Labels,
+			Annotations: job.Annotations,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: serviceSelector(task)},
+			Template: template,
+		},
+	}, tenant, nil
+}
+
+// reconcileService runs a service task: it creates its Deployment and
+// Service, rolls the Deployment out when the task or its cluster changes
+// and reports the rollout and URL in the task status. Service tasks run
+// until they are deleted.
+func (r *SwarmTaskReconciler) reconcileService(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string, githubTokenSecret string) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	desired, tenant, err := r.buildServiceDeployment(ctx, task, cluster, namespace, githubTokenSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	operatorImages, operatorSecrets := r.tenantOperatorImages(), tenantOperatorSecrets(tenant, cluster, githubTokenSecret)
+	if r.executorConfig().CredentialSecrets {
+		operatorSecrets[taskCredentialsSecretName(taskJobName(task))] = true
+	}
+	violations := tenantPodSpecViolations(tenant, &desired.Spec.Template.Spec, operatorImages, operatorSecrets)
+	if config := task.Spec.ImageConfig; config != nil && len(config.PullSecrets) > 0 {
+		violations = append(violations, tenantPodSpecViolations(tenant,
+			&corev1.PodSpec{ImagePullSecrets: config.PullSecrets}, operatorImages, operatorSecrets)...)
+	}
+	if len(violations) > 0 {
+		if err := r.failTenantTask(ctx, task, (&tenantViolationError{violations: violations}).Error()); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	utils.ApplyImageConfig(&desired.Spec.Template.Spec, taskImageConfig(task, cluster))
+	if err := r.ensurePriorityClass(ctx, desired.Spec.Template.Spec.PriorityClassName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Owner references cannot cross namespaces, the finalizer cleans up
+	// services running elsewhere
+	owned := namespace == task.Namespace
+	if owned {
+		if err := controllerutil.SetControllerReference(task, desired, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	deployment := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: namespace}, deployment)
+	switch {
+	case errors.IsNotFound(err):
+		log.Info("Creating service Deployment", "deployment", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			return ctrl.Result{}, err
+		}
+		deployment = desired
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "ServiceCreated", "Created Deployment %s/%s", namespace, desired.Name)
+	case err != nil:
+		return ctrl.Result{}, err
+	case *deployment.Spec.Replicas != *desired.Spec.Replicas ||
+		!equality.Semantic.DeepDerivative(desired.Spec.Template, deployment.Spec.Template):
+		log.Info("Rolling out service Deployment", "deployment", deployment.Name)
+		deployment.Spec.Replicas = desired.Spec.Replicas
+		deployment.Spec.Template = desired.Spec.Template
+		if err := r.Update(ctx, deployment); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "RolloutStarted", "Rolling out generation %d", task.Generation)
+	}
+	if err := r.ensureCredentialsSecret(ctx, task, cluster, tenant, deployment, githubTokenSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileTaskService(ctx, task, namespace, owned); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	wasReady := meta.IsStatusConditionTrue(task.Status.Conditions, ConditionTypeServiceReady)
+	ready, changed := updateServiceStatus(task, deployment, namespace)
+	if changed {
+		if err := r.Status().Update(ctx, task); err != nil {
+			return ctrl.Result{}, err
+		}
+		if ready && !wasReady {
+			r.Recorder.Eventf(task, corev1.EventTypeNormal, ReasonServiceAvailable, "Service available at %s", task.Status.Service.URL)
+		}
+	}
+	if !ready {
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileTaskService creates the Service in front of the task's pods or
+// moves it to the current port
+func (r *SwarmTaskReconciler) reconcileTaskService(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string, owned bool) error {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: task.Name, Namespace: namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Labels = taskResourceLabels(task)
+		svc.Spec.Selector = serviceSelector(task)
+		svc.Spec.Ports = []corev1.ServicePort{{
+			Name:       servicePortName,
+			Port:       servicePort(task),
+			TargetPort: intstr.FromString(servicePortName),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		if owned {
+			return controllerutil.SetControllerReference(task, svc, r.Scheme)
+		}
+		return nil
+	})
+	return err
+}
+
+// updateServiceStatus reports the rollout of the Deployment in the task
+// status. It returns whether every replica runs the current spec and is
+// ready, and whether the status changed. The task turns Running once the
+// first replica is ready.
+func updateServiceStatus(task *swarmv1alpha1.SwarmTask, deployment *appsv1.Deployment, namespace string) (ready, changed bool) {
+	replicas := *deployment.Spec.Replicas
+	status := deployment.Status
+	rolledOut := status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas && status.Replicas == replicas && status.ReadyReplicas >= replicas
+
+	service := swarmv1alpha1.TaskServiceStatus{
+		DeploymentName:  deployment.Name,
+		URL:             serviceURL(task, namespace),
+		Replicas:        replicas,
+		UpdatedReplicas: status.UpdatedReplicas,
+		ReadyReplicas:   status.ReadyReplicas,
+	}
+	if task.Status.Service != nil {
+		service.ObservedGeneration = task.Status.Service.ObservedGeneration
+	}
+	if rolledOut {
+		service.ObservedGeneration = task.Generation
+	}
+	if task.Status.Service == nil || *task.Status.Service != service {
+		task.Status.Service = &service
+		changed = true
+	}
+
+	if task.Status.StartTime == nil {
+		now := metav1.Now()
+		task.Status.StartTime = &now
+		changed = true
+	}
+	phase := task.Status.Phase
+	if status.ReadyReplicas > 0 {
+		phase = "Running"
+	} else if phase == "" {
+		phase = "Pending"
+	}
+	if phase != task.Status.Phase {
+		task.Status.Phase = phase
+		changed = true
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeServiceReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonRolloutInProgress,
+		Message:            fmt.Sprintf("%d of %d replicas updated, %d ready", status.UpdatedReplicas, replicas, status.ReadyReplicas),
+		ObservedGeneration: task.Generation,
+	}
+	for _, c := range status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse {
+			condition.Reason = ReasonRolloutStalled
+			condition.Message = c.Message
+		}
+	}
+	if rolledOut {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonServiceAvailable
+		condition.Message = fmt.Sprintf("%d of %d replicas ready", status.ReadyReplicas, replicas)
+	}
+	if meta.SetStatusCondition(&task.Status.Conditions, condition) {
+		changed = true
+	}
+	return rolledOut, changed
+}
+
+// deleteTaskService removes the Deployment and Service of a service task.
+// Those running outside the task's namespace have no owner reference to
+// clean them up.
+func (r *SwarmTaskReconciler) deleteTaskService(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
+	namespace := r.determineNamespace(task)
+	propagation := metav1.DeletePropagationBackground
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: task.Name, Namespace: namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: task.Name, Namespace: namespace}},
+	} {
+		if err := r.Delete(ctx, obj, &client.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Service tasks", func() {
+	var (
+		ctx        context.Context
+		reconciler *SwarmTaskReconciler
+		cluster    *swarmv1alpha1.SwarmCluster
+		task       *swarmv1alpha1.SwarmTask
+	)
+
+	reconcile := func() (time.Duration, *appsv1.Deployment) {
+		result, err := reconciler.reconcileService(ctx, task, cluster, "default", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(task), task)).To(Succeed())
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "preview", Namespace: "default"}, deployment)).To(Succeed())
+		return result.RequeueAfter, deployment
+	}
+
+	rollOut := func(deployment *appsv1.Deployment, ready int32) {
+		deployment.Status = appsv1.DeploymentStatus{
+			ObservedGeneration: deployment.Generation,
+			Replicas:           *deployment.Spec.Replicas,
+			UpdatedReplicas:    *deployment.Spec.Replicas,
+			ReadyReplicas:      ready,
+		}
+		Expect(reconciler.Status().Update(ctx, deployment)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		replicas := int32(2)
+
+		cluster = &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"}}
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "preview", Namespace: "default", Generation: 1},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				Description:  "Serve the preview",
+				Mode:         swarmv1alpha1.ServiceTaskMode,
+				Service: &swarmv1alpha1.TaskServiceSpec{
+					Port:          9000,
+					Replicas:      &replicas,
+					ReadinessPath: "/healthz",
+				},
+			},
+		}
+		k8sClient := newTestClientBuilder().WithObjects(task).WithStatusSubresource(&appsv1.Deployment{}).Build()
+		reconciler = &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("runs the task as a Deployment behind a Service", func() {
+		requeue, deployment := reconcile()
+		Expect(requeue).To(Equal(10 * time.Second))
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(2)))
+		Expect(deployment.OwnerReferences).To(HaveLen(1))
+
+		pod := deployment.Spec.Template.Spec
+		Expect(pod.RestartPolicy).To(Equal(corev1.RestartPolicyAlways))
+		Expect(pod.Containers[0].Ports).To(ContainElement(HaveField("ContainerPort", int32(9000))))
+		Expect(pod.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: executor.EnvServicePort, Value: "9000"}))
+		Expect(pod.Containers[0].ReadinessProbe.HTTPGet.Path).To(Equal("/healthz"))
+
+		svc := &corev1.Service{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "preview", Namespace: "default"}, svc)).To(Succeed())
+		Expect(svc.Spec.Selector).To(Equal(deployment.Spec.Selector.MatchLabels))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(9000)))
+
+		Expect(task.Status.Phase).To(Equal("Pending"))
+		Expect(task.Status.Service.URL).To(Equal("http://preview.default.svc:9000"))
+		Expect(meta.IsStatusConditionTrue(task.Status.Conditions, ConditionTypeServiceReady)).To(BeFalse())
+	})
+
+	It("reports the service available once every replica is ready", func() {
+		_, deployment := reconcile()
+		rollOut(deployment, 1)
+		requeue, _ := reconcile()
+		Expect(requeue).To(Equal(10 * time.Second))
+		Expect(task.Status.Phase).To(Equal("Running"))
+		Expect(task.Status.Service.ReadyReplicas).To(Equal(int32(1)))
+
+		_, deployment = reconcile()
+		Expect(meta.IsStatusConditionTrue(task.Status.Conditions, ConditionTypeServiceReady)).To(BeFalse())
+		rollOut(deployment, 2)
+		requeue, _ = reconcile()
+		Expect(requeue).To(BeZero())
+		condition := meta.FindStatusCondition(task.Status.Conditions, ConditionTypeServiceReady)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(ReasonServiceAvailable))
+		Expect(task.Status.Service.ObservedGeneration).To(Equal(int64(1)))
+
+		// A settled service leaves the status alone
+		ready, changed := updateServiceStatus(task, deployment, "default")
+		Expect(ready).To(BeTrue())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("rolls the Deployment out when the task changes", func() {
+		_, deployment := reconcile()
+		rollOut(deployment, 2)
+		reconcile()
+
+		replicas := int32(3)
+		task.Spec.Service.Replicas = &replicas
+		task.Spec.Description = "Serve the new preview"
+		Expect(reconciler.Update(ctx, task)).To(Succeed())
+		_, deployment = reconcile()
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+		Expect(meta.FindStatusCondition(task.Status.Conditions, ConditionTypeServiceReady).Reason).
+			To(Equal(ReasonRolloutInProgress))
+	})
+
+	It("validates service settings", func() {
+		path := field.NewPath("spec")
+		Expect(swarmv1alpha1.ValidateTaskService(&task.Spec, path)).To(BeEmpty())
+
+		task.Spec.Service.ReadinessPath = "healthz"
+		task.Spec.QueueName = "batch"
+		Expect(swarmv1alpha1.ValidateTaskService(&task.Spec, path)).To(HaveLen(2))
+
+		job := swarmv1alpha1.SwarmTaskSpec{Service: &swarmv1alpha1.TaskServiceSpec{Port: 9000}}
+		Expect(swarmv1alpha1.ValidateTaskService(&job, path)).To(HaveLen(1))
+	})
+})
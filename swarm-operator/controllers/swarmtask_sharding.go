@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// ownsTask reports whether this replica dispatches the task
+func (r *SwarmTaskReconciler) ownsTask(task *swarmv1alpha1.SwarmTask) bool {
+	return r.Shard == nil || r.Shard.Owns(task.UID)
+}
+
+// shardTasks runs the task controller on every replica rather than only
+// the leader. Reconcile skips the tasks outside the replica's range, and the
+// tasks it gained are enqueued when replicas join or leave.
+func (r *SwarmTaskReconciler) shardTasks(bldr *builder.Builder) *builder.Builder {
+	needLeaderElection := false
+	rebalanced := make(chan event.GenericEvent)
+	r.Shard.OnRebalance = func(ctx context.Context) {
+		go r.enqueueOwnedTasks(ctx, rebalanced)
+	}
+	return bldr.
+		WithOptions(controller.Options{NeedLeaderElection: &needLeaderElection}).
+		WatchesRawSource(&source.Channel{Source: rebalanced}, &handler.EnqueueRequestForObject{})
+}
+
+// enqueueOwnedTasks reconciles every task in this replica's range after a
+// rebalance. Tasks the replica already owned are reconciled once more,
+// which is harmless.
+func (r *SwarmTaskReconciler) enqueueOwnedTasks(ctx context.Context, events chan<- event.GenericEvent) {
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, tasks); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list tasks after a shard rebalance")
+		return
+	}
+	for i := range tasks.Items {
+		task := &tasks.Items[i]
+		if !r.ownsTask(task) {
+			continue
+		}
+		select {
+		case events <- event.GenericEvent{Object: task}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/sharding"
+)
+
+var _ = Describe("Task sharding", func() {
+	var (
+		ctx        context.Context
+		reconciler *SwarmTaskReconciler
+		mine       *swarmv1alpha1.SwarmTask
+		theirs     *swarmv1alpha1.SwarmTask
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		k8sClient := newTestClient()
+
+		// Two replicas, this one is "a"
+		now := time.Now()
+		peer := &sharding.Shard{Client: k8sClient, Reader: k8sClient, Namespace: "operator", Group: "swarmtask", Identity: "b"}
+		_, err := peer.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		shard := &sharding.Shard{Client: k8sClient, Reader: k8sClient, Namespace: "operator", Group: "swarmtask", Identity: "a"}
+		_, err = shard.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shard.Members()).To(Equal([]string{"a", "b"}))
+
+		for i := 0; mine == nil || theirs == nil; i++ {
+			task := &swarmv1alpha1.SwarmTask{ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("task-%d", i), Namespace: "default", UID: types.UID(fmt.Sprintf("uid-%d", i)),
+			}}
+			if shard.Owns(task.UID) {
+				mine = task
+			} else {
+				theirs = task
+			}
+		}
+		Expect(k8sClient.Create(ctx, mine)).To(Succeed())
+		Expect(k8sClient.Create(ctx, theirs)).To(Succeed())
+
+		reconciler = &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10), Shard: shard}
+	})
+
+	AfterEach(func() {
+		mine, theirs = nil, nil
+	})
+
+	It("leaves the tasks of other replicas alone", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(theirs)})
+		Expect(err).NotTo(HaveOccurred())
+		current := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(theirs), current)).To(Succeed())
+		Expect(current.Finalizers).To(BeEmpty())
+		Expect(current.Status.Phase).To(BeEmpty())
+	})
+
+	It("enqueues the tasks of its range after a rebalance", func() {
+		events := make(chan event.GenericEvent, 10)
+		reconciler.enqueueOwnedTasks(ctx, events)
+		close(events)
+		var names []string
+		for e := range events {
+			names = append(names, e.Object.GetName())
+		}
+		Expect(names).To(Equal([]string{mine.Name}))
+	})
+})
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// volumeNodeAffinityWeight makes the node holding a task's data the
+// strongest scheduling preference of its pod
+const volumeNodeAffinityWeight = 100
+
+// scheduledJobPod returns the pod of the Job that is bound to a node and
+// still running, or nil if there is none
+func (r *SwarmTaskReconciler) scheduledJobPod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != "" && pod.DeletionTimestamp == nil &&
+			pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			return pod, nil
+		}
+	}
+	return nil, nil
+}
+
+// recordVolumeNode records the node the Job's pod mounts the task volumes
+// on. It reports whether the status changed.
+func (r *SwarmTaskReconciler) recordVolumeNode(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job) (bool, error) {
+	pod, err := r.scheduledJobPod(ctx, job)
+	if err != nil || pod == nil {
+		return false, err
+	}
+	changed := false
+	for i := range task.Status.Volumes {
+		if status := &task.Status.Volumes[i]; status.Node != pod.Spec.NodeName {
+			status.Node = pod.Spec.NodeName
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// volumeDataNode returns the node the task's volumes were last mounted on
+func volumeDataNode(task *swarmv1alpha1.SwarmTask) string {
+	for _, status := range task.Status.Volumes {
+		if status.Node != "" {
+			return status.Node
+		}
+	}
+	return ""
+}
+
+// applyVolumeAffinity prefers the node the task's volumes were last mounted
+// on, so a retried, resumed or requeued task runs next to its data. The
+// affinity is a preference and is left out when the node is gone, letting
+// the scheduler place the pod wherever the claims can be attached. It
+// reports whether the affinity was applied.
+func (r *SwarmTaskReconciler) applyVolumeAffinity(ctx context.Context, task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) (bool, error) {
+	nodeName := volumeDataNode(task)
+	if nodeName == "" {
+		return false, nil
+	}
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		if !errors.IsNotFound(err) {
+			return false, err
+		}
+		node = nil
+	}
+	if node == nil || node.DeletionTimestamp != nil {
+		log.FromContext(ctx).Info("Node of the task volumes is gone, leaving placement to the scheduler", "node", nodeName)
+		r.Recorder.Eventf(task, corev1.EventTypeWarning, "VolumeNodeGone",
+			"Node %s the task volumes were last mounted on is gone", nodeName)
+		return false, nil
+	}
+
+	hostname := node.Name
+	if label := node.Labels[corev1.LabelHostname]; label != "" {
+		hostname = label
+	}
+	mergeNodeAffinity(podSpec, &corev1.NodeAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+			Weight: volumeNodeAffinityWeight,
+			Preference: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      corev1.LabelHostname,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{hostname},
+				}},
+			},
+		}},
+	})
+	return true, nil
+}
+
+// retainedVolumes keeps the status of the volumes whose claims outlived the
+// task for a requeued run, with their reclaim outcome reset
+func retainedVolumes(statuses []swarmv1alpha1.TaskVolumeStatus) []swarmv1alpha1.TaskVolumeStatus {
+	var retained []swarmv1alpha1.TaskVolumeStatus
+	for _, status := range statuses {
+		if status.Reclaim == volumeRetained {
+			status.Reclaim = ""
+			retained = append(retained, status)
+		}
+	}
+	return retained
+}
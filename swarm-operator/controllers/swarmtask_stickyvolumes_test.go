@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Sticky task volumes", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		reconciler *SwarmTaskReconciler
+		recorder   *record.FakeRecorder
+	)
+
+	setup := func(objects ...client.Object) {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClient(objects...),
+			Scheme:   testScheme,
+			Recorder: recorder,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				PersistentVolumes: []swarmv1alpha1.TaskVolumeSpec{{Name: "cache", MountPath: "/cache"}},
+			},
+			Status: swarmv1alpha1.SwarmTaskStatus{
+				Volumes: []swarmv1alpha1.TaskVolumeStatus{{Name: "cache", ClaimName: "build-cache"}},
+			},
+		}
+	})
+
+	It("records the node the Job's pod mounts the volumes on", func() {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "build-job", Namespace: "swarm"}}
+		setup(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "build-job-abcde", Namespace: "swarm", Labels: map[string]string{"job-name": "build-job"}},
+			Spec:       corev1.PodSpec{NodeName: "node-a"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		})
+
+		changed, err := reconciler.recordVolumeNode(ctx, task, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(task.Status.Volumes[0].Node).To(Equal("node-a"))
+
+		changed, err = reconciler.recordVolumeNode(ctx, task, job)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("prefers the node of the volumes on later attempts", func() {
+		task.Status.Volumes[0].Node = "node-a"
+		setup(&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: "node-a", Labels: map[string]string{corev1.LabelHostname: "host-a"},
+		}})
+		podSpec := &corev1.PodSpec{}
+
+		sticky, err := reconciler.applyVolumeAffinity(ctx, task, podSpec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sticky).To(BeTrue())
+		terms := podSpec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].Weight).To(Equal(int32(volumeNodeAffinityWeight)))
+		Expect(terms[0].Preference.MatchExpressions[0].Values).To(Equal([]string{"host-a"}))
+	})
+
+	It("leaves placement to the scheduler when the node is gone", func() {
+		task.Status.Volumes[0].Node = "node-a"
+		setup()
+		podSpec := &corev1.PodSpec{}
+
+		sticky, err := reconciler.applyVolumeAffinity(ctx, task, podSpec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sticky).To(BeFalse())
+		Expect(podSpec.Affinity).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("VolumeNodeGone")))
+	})
+
+	It("keeps the node of retained volumes for a requeued run", func() {
+		statuses := retainedVolumes([]swarmv1alpha1.TaskVolumeStatus{
+			{Name: "cache", ClaimName: "build-cache", Node: "node-a", Reclaim: volumeRetained},
+			{Name: "scratch", ClaimName: "build-scratch", Node: "node-a", Reclaim: volumeDeleted},
+		})
+		Expect(statuses).To(Equal([]swarmv1alpha1.TaskVolumeStatus{{Name: "cache", ClaimName: "build-cache", Node: "node-a"}}))
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// taskTimeoutGrace is added to spec.timeout before a running Job is
+	// stopped, so executors enforcing the timeout themselves go first
+	taskTimeoutGrace = time.Minute
+
+	// reasonJobLost marks attempts whose Job disappeared while the task was
+	// running
+	reasonJobLost = "JobLost"
+
+	// reasonTaskTimeout marks attempts stopped for exceeding spec.timeout
+	reasonTaskTimeout = "TaskTimeout"
+)
+
+// sweepStaleTask cross-checks the task phase against its Job on every
+// reconcile. Task state lives only in the status and the Job, so after an
+// operator restart the first reconcile of each task finds attempts whose
+// Job was deleted underneath them, or that overran their timeout, and fails
+// them through the usual retry and dead-letter path. Finished tasks whose
+// Job is gone are left alone instead of having the Job recreated. It returns
+// true when the reconcile must not go on to create or sync the Job.
+func (r *SwarmTaskReconciler) sweepStaleTask(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string) (bool, error) {
+	log := log.FromContext(ctx)
+
+	job := &batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Name: taskJobName(task), Namespace: namespace}, job)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	jobGone := errors.IsNotFound(err) || job.GetDeletionTimestamp() != nil
+
+	if taskFinished(task) {
+		return jobGone, nil
+	}
+	if task.Status.Phase != "Running" {
+		return false, nil
+	}
+
+	if jobGone {
+		log.Info("Job of running task is gone, failing the attempt", "job", taskJobName(task))
+		stub := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: namespace}}
+		return true, r.failStaleAttempt(ctx, task, stub, cluster, reasonJobLost,
+			fmt.Sprintf("Job %s disappeared while the task was running", stub.Name))
+	}
+
+	if timeout, started := taskTimeout(task), job.Status.StartTime; timeout > 0 && started != nil &&
+		job.Status.Active > 0 && time.Since(started.Time) > timeout+taskTimeoutGrace {
+		log.Info("Task exceeded its timeout, stopping the Job", "job", job.Name, "timeout", timeout)
+		propagation := metav1.DeletePropagationBackground
+		if err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		return true, r.failStaleAttempt(ctx, task, job.DeepCopy(), cluster, reasonTaskTimeout,
+			fmt.Sprintf("Task ran longer than its timeout of %s", timeout))
+	}
+
+	return false, nil
+}
+
+// taskTimeout is the run time allowed for a single attempt
+func taskTimeout(task *swarmv1alpha1.SwarmTask) time.Duration {
+	return time.Duration(task.Spec.Timeout) * time.Second
+}
+
+// failStaleAttempt records the reason on the Job as a failed condition and
+// hands it to handleJobFailure, so stale attempts are retried or
+// dead-lettered like any failed Job
+func (r *SwarmTaskReconciler) failStaleAttempt(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, cluster *swarmv1alpha1.SwarmCluster, reason, message string) error {
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type:               batchv1.JobFailed,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+	r.Recorder.Event(task, corev1.EventTypeWarning, reason, message)
+	return r.handleJobFailure(ctx, task, job, cluster)
+}
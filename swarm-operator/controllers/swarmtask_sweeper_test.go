@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Stale task sweeper", func() {
+	var (
+		ctx      context.Context
+		cluster  *swarmv1alpha1.SwarmCluster
+		recorder *record.FakeRecorder
+	)
+
+	newTask := func(phase string, retry *swarmv1alpha1.RetryPolicy) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", Timeout: 300, RetryPolicy: retry},
+			Status:     swarmv1alpha1.SwarmTaskStatus{Phase: phase},
+		}
+	}
+
+	runningJob := func(task *swarmv1alpha1.SwarmTask, started time.Time) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: taskJobName(task), Namespace: "default"},
+			Status:     batchv1.JobStatus{Active: 1, StartTime: &metav1.Time{Time: started}},
+		}
+	}
+
+	reconcilerFor := func(objects ...client.Object) *SwarmTaskReconciler {
+		k8sClient := newTestClient(objects...)
+		return &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: recorder}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"}}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	It("should leave running tasks with a live Job alone", func() {
+		task := newTask("Running", nil)
+		r := reconcilerFor(task, runningJob(task, time.Now().Add(-time.Minute)))
+
+		stale, err := r.sweepStaleTask(ctx, task, cluster, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(BeFalse())
+		Expect(task.Status.Phase).To(Equal("Running"))
+	})
+
+	It("should retry running tasks whose Job disappeared", func() {
+		task := newTask("Running", &swarmv1alpha1.RetryPolicy{MaxRetries: 2, BackoffSeconds: 10})
+		r := reconcilerFor(task)
+
+		stale, err := r.sweepStaleTask(ctx, task, cluster, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(BeTrue())
+		Expect(task.Status.Phase).To(Equal("Pending"))
+		Expect(task.Status.Attempt).To(Equal(int32(1)))
+		Expect(task.Status.FailureDetails.Reason).To(Equal(reasonJobLost))
+		Expect(recorder.Events).To(Receive(ContainSubstring(reasonJobLost)))
+	})
+
+	It("should stop and fail tasks that overran their timeout", func() {
+		task := newTask("Running", nil)
+		job := runningJob(task, time.Now().Add(-time.Hour))
+		r := reconcilerFor(task, job)
+
+		stale, err := r.sweepStaleTask(ctx, task, cluster, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(BeTrue())
+		Expect(task.Status.Phase).To(Equal("Failed"))
+		Expect(task.Status.Message).To(ContainSubstring(reasonTaskTimeout))
+
+		err = r.Get(ctx, types.NamespacedName{Name: job.Name, Namespace: "default"}, &batchv1.Job{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// The Job of the failed task is not recreated
+		stale, err = r.sweepStaleTask(ctx, task, cluster, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(BeTrue())
+	})
+
+	It("should not recreate the Job of a finished task", func() {
+		task := newTask("Completed", nil)
+		r := reconcilerFor(task)
+
+		stale, err := r.sweepStaleTask(ctx, task, cluster, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(BeTrue())
+		Expect(task.Status.Phase).To(Equal("Completed"))
+	})
+
+	It("should let pending tasks create their Job", func() {
+		task := newTask("Pending", nil)
+		r := reconcilerFor(task)
+
+		stale, err := r.sweepStaleTask(ctx, task, cluster, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stale).To(BeFalse())
+	})
+})
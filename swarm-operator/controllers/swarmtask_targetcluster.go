@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// ConditionTypeTargetClusterReachable reports whether the operator
+	// reached the API server of the task's target cluster
+	ConditionTypeTargetClusterReachable = "TargetClusterReachable"
+
+	ReasonTargetClusterReachable   = "TargetClusterReachable"
+	ReasonTargetClusterUnreachable = "TargetClusterUnreachable"
+
+	// targetKubeconfigVolume holds the kubeconfig of the target cluster,
+	// mounted at targetKubeconfigDir with the file targetKubeconfigFile
+	targetKubeconfigVolume = "target-kubeconfig"
+	targetKubeconfigDir    = "/var/run/swarm/kubeconfig"
+	targetKubeconfigFile   = "config"
+
+	targetKubeconfigSecretType = "target-kubeconfig"
+	defaultKubeconfigKey       = "kubeconfig"
+
+	// targetClusterTimeout bounds the connectivity check
+	targetClusterTimeout = 10 * time.Second
+
+	// targetClusterRetryInterval is how soon a task whose target cluster
+	// could not be reached tries again
+	targetClusterRetryInterval = time.Minute
+)
+
+// taskKubeconfigSecretName returns the Secret holding the kubeconfig of
+// the target cluster of a Job, deleted with its Job
+func taskKubeconfigSecretName(job *batchv1.Job) string {
+	return job.Name + "-kubeconfig"
+}
+
+// applyTargetCluster mounts the kubeconfig of the target cluster and
+// points KUBECONFIG at it, optionally keeping the token of the task's own
+// service account out of the pod
+func applyTargetCluster(task *swarmv1alpha1.SwarmTask, job *batchv1.Job) {
+	target := task.Spec.TargetCluster
+	if target == nil {
+		return
+	}
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: targetKubeconfigVolume,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: taskKubeconfigSecretName(job)},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      targetKubeconfigVolume,
+		MountPath: targetKubeconfigDir,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env, corev1.EnvVar{Name: "KUBECONFIG", Value: targetKubeconfigDir + "/" + targetKubeconfigFile})
+	if target.DisableServiceAccountToken {
+		automount := false
+		podSpec.AutomountServiceAccountToken = &automount
+	}
+}
+
+// targetClusterError is a target cluster the task cannot use, which holds
+// the task rather than failing the reconcile
+type targetClusterError struct {
+	message string
+}
+
+func (e *targetClusterError) Error() string {
+	return e.message
+}
+
+// targetKubeconfig reads the kubeconfig of the task's target cluster from
+// the namespace the task runs in and reduces it to the chosen context, so
+// the task never sees the credentials of other clusters. A missing Secret,
+// key or context is returned as a *targetClusterError.
+func (r *SwarmTaskReconciler) targetKubeconfig(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string) ([]byte, error) {
+	target := task.Spec.TargetCluster
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: target.KubeconfigSecret, Namespace: namespace}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, &targetClusterError{fmt.Sprintf("secret %s not found in namespace %s", target.KubeconfigSecret, namespace)}
+		}
+		return nil, err
+	}
+	key := target.Key
+	if key == "" {
+		key = defaultKubeconfigKey
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, &targetClusterError{fmt.Sprintf("secret %s has no key %s", secret.Name, key)}
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, &targetClusterError{fmt.Sprintf("invalid kubeconfig in secret %s: %v", secret.Name, err)}
+	}
+	if target.Context != "" {
+		if _, ok := config.Contexts[target.Context]; !ok {
+			return nil, &targetClusterError{fmt.Sprintf("kubeconfig in secret %s has no context %s", secret.Name, target.Context)}
+		}
+		config.CurrentContext = target.Context
+	}
+	if err := clientcmdapi.MinifyConfig(config); err != nil {
+		return nil, &targetClusterError{fmt.Sprintf("kubeconfig in secret %s: %v", secret.Name, err)}
+	}
+	if err := checkInlineKubeconfig(config); err != nil {
+		return nil, &targetClusterError{fmt.Sprintf("kubeconfig in secret %s: %v", secret.Name, err)}
+	}
+	return clientcmd.Write(*config)
+}
+
+// checkInlineKubeconfig refuses kubeconfigs that reach beyond their own
+// content. The operator loads them in its own process, where a credential
+// plugin would run a command, a file reference would read the operator's
+// files and a proxy would route its requests. Credentials and certificates
+// must be inline.
+func checkInlineKubeconfig(config *clientcmdapi.Config) error {
+	for name, cluster := range config.Clusters {
+		switch {
+		case cluster.CertificateAuthority != "":
+			return fmt.Errorf("cluster %s reads its certificate authority from a file, use certificate-authority-data", name)
+		case cluster.ProxyURL != "":
+			return fmt.Errorf("cluster %s sets a proxy-url, which is not allowed", name)
+		}
+	}
+	for name, user := range config.AuthInfos {
+		switch {
+		case user.Exec != nil:
+			return fmt.Errorf("user %s runs an exec credential plugin, use an inline token or client certificate", name)
+		case user.AuthProvider != nil:
+			return fmt.Errorf("user %s uses an auth-provider, use an inline token or client certificate", name)
+		case user.TokenFile != "":
+			return fmt.Errorf("user %s reads its token from a file, use token", name)
+		case user.ClientCertificate != "" || user.ClientKey != "":
+			return fmt.Errorf("user %s reads its client certificate from a file, use client-certificate-data and client-key-data", name)
+		}
+	}
+	return nil
+}
+
+// checkTargetCluster asks the API server of the kubeconfig for its version
+func checkTargetCluster(_ context.Context, kubeconfig []byte) (string, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+	config.Timeout = targetClusterTimeout
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return "", err
+	}
+	version, err := client.ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	return version.GitVersion, nil
+}
+
+// verifyTargetCluster checks that the target cluster of the task can be
+// reached with its kubeconfig before the Job is created, and returns the
+// kubeconfig to mount. Tasks whose target cluster cannot be reached stay
+// Pending with a dispatchHoldError.
+func (r *SwarmTaskReconciler) verifyTargetCluster(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string) ([]byte, error) {
+	if task.Spec.TargetCluster == nil {
+		return nil, nil
+	}
+	check := r.CheckTargetCluster
+	if check == nil {
+		check = checkTargetCluster
+	}
+
+	kubeconfig, err := r.targetKubeconfig(ctx, task, namespace)
+	var version string
+	if err == nil {
+		version, err = check(ctx, kubeconfig)
+	} else if _, ok := err.(*targetClusterError); !ok {
+		return nil, err
+	}
+	if err != nil {
+		message := fmt.Sprintf("Target cluster unreachable: %v", err)
+		r.Recorder.Event(task, corev1.EventTypeWarning, ReasonTargetClusterUnreachable, message)
+		meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeTargetClusterReachable,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonTargetClusterUnreachable,
+			Message:            message,
+			ObservedGeneration: task.Generation,
+		})
+		return nil, r.holdDispatch(ctx, task, message, targetClusterRetryInterval)
+	}
+
+	if meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeTargetClusterReachable,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonTargetClusterReachable,
+		Message:            fmt.Sprintf("The target cluster runs Kubernetes %s", version),
+		ObservedGeneration: task.Generation,
+	}) {
+		if err := r.Status().Update(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+	return kubeconfig, nil
+}
+
+// ensureKubeconfigSecret stores the kubeconfig of the target cluster in a
+// Secret owned by the Job, reading it if the caller has not already
+func (r *SwarmTaskReconciler) ensureKubeconfigSecret(ctx context.Context, task *swarmv1alpha1.SwarmTask, job *batchv1.Job, kubeconfig []byte) error {
+	if task.Spec.TargetCluster == nil {
+		return nil
+	}
+	name := taskKubeconfigSecretName(job)
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: job.Namespace}, &corev1.Secret{})
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	if kubeconfig == nil {
+		if kubeconfig, err = r.targetKubeconfig(ctx, task, job.Namespace); err != nil {
+			return err
+		}
+	}
+	labels := taskResourceLabels(task)
+	labels[secretTypeLabel] = targetKubeconfigSecretType
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: job.Namespace, Labels: labels},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{targetKubeconfigFile: kubeconfig},
+	}
+	if err := controllerutil.SetControllerReference(job, secret, r.Scheme); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Creating target cluster kubeconfig secret", "secret", name)
+	if err := r.Create(ctx, secret); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const targetKubeconfigYAML = `apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod
+  cluster: {server: "https://prod.example.com"}
+- name: staging
+  cluster: {server: "https://staging.example.com"}
+users:
+- name: prod-admin
+  user: {token: prod-token}
+- name: staging-deployer
+  user: {token: staging-token}
+contexts:
+- name: prod
+  context: {cluster: prod, user: prod-admin}
+- name: staging
+  context: {cluster: staging, user: staging-deployer, namespace: apps}
+`
+
+var _ = Describe("Task target clusters", func() {
+	var (
+		ctx        context.Context
+		task       *swarmv1alpha1.SwarmTask
+		recorder   *record.FakeRecorder
+		reconciler *SwarmTaskReconciler
+		checked    []byte
+		checkErr   error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		checked, checkErr = nil, nil
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				TargetCluster: &swarmv1alpha1.TaskTargetCluster{
+					KubeconfigSecret:           "staging-access",
+					Context:                    "staging",
+					DisableServiceAccountToken: true,
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "staging-access", Namespace: "swarm"},
+			Data:       map[string][]byte{"kubeconfig": []byte(targetKubeconfigYAML)},
+		}
+
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SwarmTaskReconciler{
+			Client:   newTestClient(task, secret),
+			Scheme:   testScheme,
+			Recorder: recorder,
+			CheckTargetCluster: func(_ context.Context, kubeconfig []byte) (string, error) {
+				checked = kubeconfig
+				return "v1.30.2", checkErr
+			},
+		}
+	})
+
+	stored := func() *swarmv1alpha1.SwarmTask {
+		stored := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(task), stored)).To(Succeed())
+		return stored
+	}
+
+	It("checks the chosen context only and passes it on to the Job", func() {
+		kubeconfig, err := reconciler.verifyTargetCluster(ctx, task, "swarm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked).To(Equal(kubeconfig))
+
+		config, err := clientcmd.Load(kubeconfig)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.CurrentContext).To(Equal("staging"))
+		Expect(config.Clusters).To(HaveKey("staging"))
+		Expect(config.Clusters).NotTo(HaveKey("prod"))
+		Expect(config.AuthInfos).NotTo(HaveKey("prod-admin"))
+		condition := meta.FindStatusCondition(stored().Status.Conditions, ConditionTypeTargetClusterReachable)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("v1.30.2"))
+
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "deploy-job", Namespace: "swarm", UID: "job-uid"},
+			Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "task"}},
+			}}},
+		}
+		applyTargetCluster(task, job)
+		podSpec := job.Spec.Template.Spec
+		Expect(podSpec.Volumes[0].Secret.SecretName).To(Equal("deploy-job-kubeconfig"))
+		Expect(podSpec.Containers[0].VolumeMounts[0].MountPath).To(Equal(targetKubeconfigDir))
+		Expect(podSpec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "KUBECONFIG", Value: "/var/run/swarm/kubeconfig/config"}))
+		Expect(*podSpec.AutomountServiceAccountToken).To(BeFalse())
+
+		Expect(reconciler.ensureKubeconfigSecret(ctx, task, job, kubeconfig)).To(Succeed())
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "deploy-job-kubeconfig", Namespace: "swarm"}, secret)).To(Succeed())
+		Expect(secret.Data[targetKubeconfigFile]).To(Equal(kubeconfig))
+		Expect(secret.OwnerReferences[0].Name).To(Equal("deploy-job"))
+	})
+
+	It("holds the task while the target cluster cannot be reached", func() {
+		checkErr = errors.New("dial tcp: i/o timeout")
+		_, err := reconciler.verifyTargetCluster(ctx, task, "swarm")
+		var hold *dispatchHoldError
+		Expect(errors.As(err, &hold)).To(BeTrue())
+		Expect(hold.retryAfter).To(Equal(targetClusterRetryInterval))
+
+		Expect(stored().Status.Phase).To(Equal("Pending"))
+		Expect(stored().Status.Message).To(Equal("Target cluster unreachable: dial tcp: i/o timeout"))
+		Expect(meta.IsStatusConditionFalse(stored().Status.Conditions, ConditionTypeTargetClusterReachable)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning TargetClusterUnreachable")))
+	})
+
+	It("holds the task until its kubeconfig exists", func() {
+		_, err := reconciler.verifyTargetCluster(ctx, task, "elsewhere")
+		var hold *dispatchHoldError
+		Expect(errors.As(err, &hold)).To(BeTrue())
+		Expect(hold.message).To(Equal("Target cluster unreachable: secret staging-access not found in namespace elsewhere"))
+		Expect(checked).To(BeNil())
+
+		task.Spec.TargetCluster.Context = "dev"
+		_, err = reconciler.verifyTargetCluster(ctx, task, "swarm")
+		Expect(err).To(MatchError(ContainSubstring("has no context dev")))
+	})
+
+	DescribeTable("refuses kubeconfigs that are not self-contained",
+		func(cluster, user, message string) {
+			secret := &corev1.Secret{}
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: "staging-access", Namespace: "swarm"}, secret)).To(Succeed())
+			secret.Data["kubeconfig"] = []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster: {server: "https://staging.example.com", ` + cluster + `}
+users:
+- name: deployer
+  user: {` + user + `}
+contexts:
+- name: staging
+  context: {cluster: staging, user: deployer}
+`)
+			Expect(reconciler.Update(ctx, secret)).To(Succeed())
+
+			_, err := reconciler.verifyTargetCluster(ctx, task, "swarm")
+			var hold *dispatchHoldError
+			Expect(errors.As(err, &hold)).To(BeTrue())
+			Expect(hold.message).To(ContainSubstring(message))
+			Expect(checked).To(BeNil())
+		},
+		Entry("exec plugin", "", `exec: {apiVersion: client.authentication.k8s.io/v1, command: /bin/sh}`, "exec credential plugin"),
+		Entry("auth provider", "", `auth-provider: {name: oidc}`, "auth-provider"),
+		Entry("token file", "", `tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token`, "token from a file"),
+		Entry("client certificate file", "", `client-certificate: /etc/tls/tls.crt, client-key: /etc/tls/tls.key`, "client certificate from a file"),
+		Entry("certificate authority file", `certificate-authority: /etc/ca.crt`, `token: t`, "certificate authority from a file"),
+		Entry("proxy", `proxy-url: "http://169.254.169.254"`, `token: t`, "proxy-url"),
+	)
+})
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+// Standard OpenTelemetry SDK environment variables
+const (
+	envOTelServiceName        = "OTEL_SERVICE_NAME"
+	envOTelResourceAttributes = "OTEL_RESOURCE_ATTRIBUTES"
+	envOTelExporterEndpoint   = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+	defaultTelemetryServiceName = "swarm-task"
+)
+
+// taskTelemetry returns the task telemetry settings of the cluster, nil
+// when they are off
+func taskTelemetry(cluster *swarmv1alpha1.SwarmCluster) *swarmv1alpha1.TaskTelemetrySpec {
+	if cluster == nil || cluster.Spec.Monitoring == nil {
+		return nil
+	}
+	if telemetry := cluster.Spec.Monitoring.TaskTelemetry; telemetry != nil && telemetry.Enabled {
+		return telemetry
+	}
+	return nil
+}
+
+// encodeTelemetryPairs joins the pairs as name=value sorted by name, with
+// the values percent-encoded as OTEL_RESOURCE_ATTRIBUTES expects
+func encodeTelemetryPairs(pairs map[string]string) string {
+	names := make([]string, 0, len(pairs))
+	for name := range pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	encoded := make([]string, 0, len(names))
+	for _, name := range names {
+		encoded = append(encoded, name+"="+url.PathEscape(pairs[name]))
+	}
+	return strings.Join(encoded, ",")
+}
+
+// taskResourceAttributes are the OpenTelemetry resource attributes of the
+// task's executor. The correlation ID stays the same across retries, the
+// attempt tells them apart.
+func taskResourceAttributes(task *swarmv1alpha1.SwarmTask, namespace string, telemetry *swarmv1alpha1.TaskTelemetrySpec) map[string]string {
+	attributes := map[string]string{}
+	for name, value := range telemetry.ResourceAttributes {
+		attributes[name] = value
+	}
+	attributes["k8s.namespace.name"] = namespace
+	attributes["swarm.task.name"] = task.Name
+	attributes["swarm.task.namespace"] = task.Namespace
+	attributes["swarm.task.attempt"] = strconv.Itoa(int(task.Status.Attempt))
+	attributes["swarm.cluster"] = task.Spec.SwarmCluster
+	attributes["swarm.correlation_id"] = taskCorrelationID(task)
+	return attributes
+}
+
+// taskExemplarLabels are the labels the task's executor attaches to metric
+// exemplars
+func taskExemplarLabels(task *swarmv1alpha1.SwarmTask, telemetry *swarmv1alpha1.TaskTelemetrySpec) map[string]string {
+	labels := map[string]string{}
+	for name, value := range telemetry.ExemplarLabels {
+		labels[name] = value
+	}
+	labels["task"] = task.Name
+	labels["task_namespace"] = task.Namespace
+	labels["cluster"] = task.Spec.SwarmCluster
+	labels["correlation_id"] = taskCorrelationID(task)
+	return labels
+}
+
+// applyTaskTelemetry tags the telemetry of the task's executor with the
+// task and cluster, and has Prometheus scrape the task pod where agents of
+// the cluster are scraped. Variables and annotations the task sets itself
+// win, so applying it again leaves the template unchanged.
+func applyTaskTelemetry(task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string, template *corev1.PodTemplateSpec) {
+	telemetry := taskTelemetry(cluster)
+	if telemetry == nil {
+		return
+	}
+
+	serviceName := telemetry.ServiceName
+	if serviceName == "" {
+		serviceName = defaultTelemetryServiceName
+	}
+	env := []corev1.EnvVar{
+		{Name: envOTelServiceName, Value: serviceName},
+		{Name: envOTelResourceAttributes, Value: encodeTelemetryPairs(taskResourceAttributes(task, namespace, telemetry))},
+		{Name: executor.EnvExemplarLabels, Value: encodeTelemetryPairs(taskExemplarLabels(task, telemetry))},
+	}
+	if telemetry.OTLPEndpoint != "" {
+		env = append(env, corev1.EnvVar{Name: envOTelExporterEndpoint, Value: telemetry.OTLPEndpoint})
+	}
+	container := &template.Spec.Containers[0]
+	for _, v := range env {
+		if !hasEnv(container, v.Name) {
+			container.Env = append(container.Env, v)
+		}
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	monitoring := cluster.Spec.Monitoring
+	for name, value := range map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   strconv.Itoa(int(monitoringMetricsPort(monitoring))),
+		"prometheus.io/path":   monitoringMetricsPath(monitoring),
+	} {
+		if _, ok := template.Annotations[name]; !ok {
+			template.Annotations[name] = value
+		}
+	}
+}
+
+// hasEnv reports whether the container sets the variable
+func hasEnv(container *corev1.Container, name string) bool {
+	for _, v := range container.Env {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+var _ = Describe("Task telemetry", func() {
+	var (
+		task     *swarmv1alpha1.SwarmTask
+		cluster  *swarmv1alpha1.SwarmCluster
+		template *corev1.PodTemplateSpec
+	)
+
+	env := func() map[string]string {
+		values := map[string]string{}
+		for _, v := range template.Spec.Containers[0].Env {
+			values[v.Name] = v.Value
+		}
+		return values
+	}
+
+	BeforeEach(func() {
+		task = &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "team-a", UID: "uid-1"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+			Status:     swarmv1alpha1.SwarmTaskStatus{Attempt: 2},
+		}
+		cluster = &swarmv1alpha1.SwarmCluster{Spec: swarmv1alpha1.SwarmClusterSpec{
+			Monitoring: &swarmv1alpha1.MonitoringSpec{MetricsPort: 9102, TaskTelemetry: &swarmv1alpha1.TaskTelemetrySpec{
+				Enabled:            true,
+				OTLPEndpoint:       "http://otel-collector:4317",
+				ResourceAttributes: map[string]string{"deployment.environment": "prod"},
+				ExemplarLabels:     map[string]string{"team": "a"},
+			}},
+		}}
+		template = &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}}
+	})
+
+	It("tags the executor's traces and metrics with the task", func() {
+		applyTaskTelemetry(task, cluster, "swarm-tasks", template)
+
+		Expect(env()).To(Equal(map[string]string{
+			envOTelServiceName: "swarm-task",
+			envOTelResourceAttributes: "deployment.environment=prod,k8s.namespace.name=swarm-tasks," +
+				"swarm.cluster=swarm,swarm.correlation_id=uid-1,swarm.task.attempt=2," +
+				"swarm.task.name=build,swarm.task.namespace=team-a",
+			executor.EnvExemplarLabels: "cluster=swarm,correlation_id=uid-1,task=build,task_namespace=team-a,team=a",
+			envOTelExporterEndpoint:    "http://otel-collector:4317",
+		}))
+		Expect(template.Annotations).To(Equal(map[string]string{
+			"prometheus.io/scrape": "true",
+			"prometheus.io/port":   "9102",
+			"prometheus.io/path":   "/metrics",
+		}))
+	})
+
+	It("encodes correlation IDs clients chose", func() {
+		task.Annotations = map[string]string{correlationIDAnnotation: "req 1,2"}
+		applyTaskTelemetry(task, cluster, "swarm-tasks", template)
+		Expect(env()[executor.EnvExemplarLabels]).To(ContainSubstring("correlation_id=req%201%2C2,"))
+	})
+
+	It("keeps what the task sets itself", func() {
+		template.Annotations = map[string]string{"prometheus.io/port": "8000"}
+		template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: envOTelServiceName, Value: "builder"}}
+		applyTaskTelemetry(task, cluster, "swarm-tasks", template)
+		applyTaskTelemetry(task, cluster, "swarm-tasks", template)
+
+		Expect(template.Spec.Containers[0].Env).To(HaveLen(4))
+		Expect(env()[envOTelServiceName]).To(Equal("builder"))
+		Expect(template.Annotations["prometheus.io/port"]).To(Equal("8000"))
+	})
+
+	It("leaves task pods alone unless enabled", func() {
+		cluster.Spec.Monitoring.TaskTelemetry.Enabled = false
+		applyTaskTelemetry(task, cluster, "swarm-tasks", template)
+		applyTaskTelemetry(task, nil, "swarm-tasks", template)
+		Expect(template.Annotations).To(BeEmpty())
+		Expect(template.Spec.Containers[0].Env).To(BeEmpty())
+	})
+})
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// tenantViolationError is returned instead of creating a task Job that
+// would break the policies of the task's tenant
+type tenantViolationError struct {
+	violations []string
+}
+
+func (e *tenantViolationError) Error() string {
+	return strings.Join(e.violations, "; ")
+}
+
+// admitTenantTask holds a task waiting for its Job to the namespaces and
+// concurrency quota of its tenant. Tasks breaking the tenant's policies are
+// failed, tasks over the quota or of a missing tenant stay Pending. It
+// reports whether the task was held or failed.
+func (r *SwarmTaskReconciler) admitTenantTask(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, namespace string) (bool, error) {
+	switch task.Status.Phase {
+	case "", "Pending", taskPhaseSuspended:
+	default:
+		return false, nil
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: taskJobName(task), Namespace: namespace}, &batchv1.Job{})
+	if err == nil || !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	if label, clusterTenant := task.Labels[tenantLabel], cluster.Labels[tenantLabel]; label != "" && label != clusterTenant {
+		return true, r.failTenantTask(ctx, task, fmt.Sprintf("Task belongs to tenant %s but SwarmCluster %s does not", label, cluster.Name))
+	}
+
+	name := taskTenant(task, cluster)
+	tenant, err := getTenant(ctx, r, name)
+	if errors.IsNotFound(err) {
+		return true, r.holdTenantTask(ctx, task, fmt.Sprintf("Waiting for SwarmTenant %s", name))
+	}
+	if err != nil {
+		return false, err
+	}
+
+	violation, err := tenantNamespaceViolation(ctx, r, tenant, task.Namespace, namespace)
+	if err != nil {
+		return false, err
+	}
+	if violation == "" && tenant != nil && task.Spec.GitHubApp != nil {
+		ref := task.Spec.GitHubApp.PrivateKeyRef
+		switch {
+		case ref.Namespace != "" && !containsString(tenant.Spec.Namespaces, ref.Namespace):
+			violation = fmt.Sprintf("GitHub App key namespace %s is not a namespace of tenant %s", ref.Namespace, tenant.Name)
+		case tenant.Spec.Credentials != nil && !containsString(tenant.Spec.Credentials.AllowedSecrets, ref.Name):
+			violation = fmt.Sprintf("secret %s is outside the credentials of tenant %s", ref.Name, tenant.Name)
+		}
+	}
+	if violation != "" {
+		return true, r.failTenantTask(ctx, task, violation)
+	}
+	if tenant == nil || tenant.Spec.Quota == nil || tenant.Spec.Quota.MaxConcurrentTasks == 0 {
+		return false, nil
+	}
+
+	running, err := countTenantJobs(ctx, r, tenant.Name)
+	if err != nil {
+		return false, err
+	}
+	if running >= tenant.Spec.Quota.MaxConcurrentTasks {
+		return true, r.holdTenantTask(ctx, task, fmt.Sprintf("Held by the quota of tenant %s, %d tasks may run at once",
+			tenant.Name, tenant.Spec.Quota.MaxConcurrentTasks))
+	}
+	return false, nil
+}
+
+// holdTenantTask keeps the task Pending with the reason in its message
+func (r *SwarmTaskReconciler) holdTenantTask(ctx context.Context, task *swarmv1alpha1.SwarmTask, message string) error {
+	if task.Status.Message == message {
+		return nil
+	}
+	if task.Status.Phase == "" {
+		task.Status.Phase = "Pending"
+	}
+	task.Status.Message = message
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	r.Recorder.Event(task, corev1.EventTypeNormal, "TenantHold", message)
+	return nil
+}
+
+// failTenantTask fails a task that breaks the policies of its tenant
+func (r *SwarmTaskReconciler) failTenantTask(ctx context.Context, task *swarmv1alpha1.SwarmTask, message string) error {
+	if task.Status.Phase == "Failed" {
+		return nil
+	}
+	task.Status.Phase = "Failed"
+	task.Status.Message = message
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	r.Recorder.Event(task, corev1.EventTypeWarning, ReasonTenantPolicyViolation, message)
+	return nil
+}
+
+// countTenantJobs counts the task Jobs of the tenant that have not finished
+func countTenantJobs(ctx context.Context, c client.Reader, tenant string) (int32, error) {
+	jobs := &batchv1.JobList{}
+	if err := c.List(ctx, jobs, client.MatchingLabels{tenantLabel: tenant}); err != nil {
+		return 0, err
+	}
+	var running int32
+	for _, job := range jobs.Items {
+		if job.Status.Succeeded > 0 {
+			continue
+		}
+		finished := false
+		for _, condition := range job.Status.Conditions {
+			if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+				condition.Status == corev1.ConditionTrue {
+				finished = true
+			}
+		}
+		if !finished {
+			running++
+		}
+	}
+	return running, nil
+}
+
+// tenantOperatorImages are the images the operator puts into task pods
+// itself, exempt from the tenant's registry restrictions
+func (r *SwarmTaskReconciler) tenantOperatorImages() map[string]bool {
+	images := map[string]bool{placeholderExecutorImage: true, placeholderWindowsExecutorImage: true, defaultCheckoutImage: true}
+	config := r.executorConfig()
+	for _, image := range []string{config.Image, config.WindowsImage} {
+		if image != "" {
+			images[image] = true
+		}
+	}
+	return images
+}
+
+// tenantOperatorSecrets are the secrets the operator mounts into the task
+// pod itself, exempt from the tenant's credential scope
+func tenantOperatorSecrets(tenant *swarmv1alpha1.SwarmTenant, cluster *swarmv1alpha1.SwarmCluster, githubTokenSecret string) map[string]bool {
+	secrets := map[string]bool{tlsSecretName(cluster, hiveMindTLSComponent): true}
+	if githubTokenSecret != "" {
+		secrets[githubTokenSecret] = true
+	}
+	for _, creds := range credentialSecrets {
+		if tenantInjectsCredential(tenant, creds.secret) {
+			secrets[creds.secret] = true
+		}
+	}
+	if tenantInjectsCredential(tenant, gcpCredentialsSecret) {
+		secrets[gcpCredentialsSecret] = true
+	}
+	return secrets
+}
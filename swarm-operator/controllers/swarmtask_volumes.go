@@ -0,0 +1,405 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+const (
+	defaultTaskVolumeSize = "1Gi"
+
+	// recycleMountPath is where the recycle Job mounts the claim it wipes
+	recycleMountPath = "/scrub"
+
+	volumeRetained  = "Retained"
+	volumeDeleted   = "Deleted"
+	volumeRecycling = "Recycling"
+	volumeRecycled  = "Recycled"
+)
+
+// taskFinished reports whether the task reached a phase its volumes outlive
+func taskFinished(task *swarmv1alpha1.SwarmTask) bool {
+	switch task.Status.Phase {
+	case "Completed", "Failed", taskPhaseDeadLettered, taskPhaseCancelled:
+		return true
+	}
+	return false
+}
+
+// taskVolumeClaimName returns the PVC name of a task volume
+func taskVolumeClaimName(task *swarmv1alpha1.SwarmTask, vol swarmv1alpha1.TaskVolumeSpec) string {
+	return fmt.Sprintf("%s-%s", task.Name, vol.Name)
+}
+
+// taskVolumeSize returns the requested size of a task volume
+func taskVolumeSize(vol swarmv1alpha1.TaskVolumeSpec) (resource.Quantity, error) {
+	size := vol.Size
+	if size == "" {
+		size = defaultTaskVolumeSize
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return quantity, fmt.Errorf("invalid size %q for volume %s: %w", size, vol.Name, err)
+	}
+	return quantity, nil
+}
+
+// volumeGrowth compares the claim request with the spec and returns the new
+// size when the spec asks for more storage. Shrinking is reported as an error
+// since PVCs cannot be shrunk.
+func volumeGrowth(pvc *corev1.PersistentVolumeClaim, vol swarmv1alpha1.TaskVolumeSpec) (*resource.Quantity, error) {
+	desired, err := taskVolumeSize(vol)
+	if err != nil {
+		return nil, err
+	}
+	current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch desired.Cmp(current) {
+	case 1:
+		return &desired, nil
+	case -1:
+		return nil, fmt.Errorf("volume %s cannot shrink from %s to %s", vol.Name, current.String(), desired.String())
+	}
+	return nil, nil
+}
+
+// applyTaskVolumes mounts the task claims into the executor container
+func applyTaskVolumes(task *swarmv1alpha1.SwarmTask, podSpec *corev1.PodSpec) {
+	for _, vol := range task.Spec.PersistentVolumes {
+		name := "vol-" + vol.Name
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: taskVolumeClaimName(task, vol),
+				},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: vol.MountPath,
+		})
+	}
+}
+
+// ensureTaskVolumes creates missing claims for running tasks, expands claims
+// whose size was increased and records their state in the task status.
+// Claims are not owned by the task so that retained volumes survive it.
+func (r *SwarmTaskReconciler) ensureTaskVolumes(ctx context.Context, task *swarmv1alpha1.SwarmTask, namespace string) error {
+	log := log.FromContext(ctx)
+
+	statuses := make([]swarmv1alpha1.TaskVolumeStatus, 0, len(task.Spec.PersistentVolumes))
+	for _, vol := range task.Spec.PersistentVolumes {
+		status := swarmv1alpha1.TaskVolumeStatus{Name: vol.Name, ClaimName: taskVolumeClaimName(task, vol)}
+		if previous := findVolumeStatus(task, vol.Name); previous != nil {
+			status.Reclaim = previous.Reclaim
+			status.Node = previous.Node
+		}
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Get(ctx, types.NamespacedName{Name: status.ClaimName, Namespace: namespace}, pvc)
+		switch {
+		case errors.IsNotFound(err):
+			// Reclaimed volumes are only recreated when the task is requeued
+			if !taskFinished(task) {
+				size, err := taskVolumeSize(vol)
+				if err != nil {
+					return err
+				}
+				log.Info("Creating task volume", "pvc", status.ClaimName)
+				claim := constructTaskVolumeClaim(task, vol, namespace, size)
+				if claim.Spec.StorageClassName == nil {
+					claim.Spec.StorageClassName = operatorStorageClass(r.Config)
+				}
+				if err := r.Create(ctx, claim); err != nil {
+					return err
+				}
+				status.Capacity = size.String()
+			}
+		case err != nil:
+			return err
+		default:
+			if err := r.expandTaskVolume(ctx, task, vol, pvc); err != nil {
+				return err
+			}
+			if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+				status.Capacity = capacity.String()
+			}
+			status.VolumeName = pvc.Spec.VolumeName
+		}
+		statuses = append(statuses, status)
+	}
+
+	if equality.Semantic.DeepEqual(statuses, task.Status.Volumes) {
+		return nil
+	}
+	task.Status.Volumes = statuses
+	return r.Status().Update(ctx, task)
+}
+
+func constructTaskVolumeClaim(task *swarmv1alpha1.SwarmTask, vol swarmv1alpha1.TaskVolumeSpec, namespace string, size resource.Quantity) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      taskVolumeClaimName(task, vol),
+			Namespace: namespace,
+			Labels:    taskResourceLabels(task),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	if vol.StorageClass != "" {
+		pvc.Spec.StorageClassName = &vol.StorageClass
+	}
+	return pvc
+}
+
+// expandTaskVolume grows the claim to the spec size when expansion is
+// allowed on the volume and supported by its storage class
+func (r *SwarmTaskReconciler) expandTaskVolume(ctx context.Context, task *swarmv1alpha1.SwarmTask, vol swarmv1alpha1.TaskVolumeSpec, pvc *corev1.PersistentVolumeClaim) error {
+	desired, err := volumeGrowth(pvc, vol)
+	if err != nil {
+		r.Recorder.Event(task, corev1.EventTypeWarning, "VolumeResizeRejected", err.Error())
+		return nil
+	}
+	if desired == nil {
+		return nil
+	}
+
+	if !vol.AllowExpansion {
+		r.Recorder.Eventf(task, corev1.EventTypeWarning, "VolumeResizeRejected",
+			"Volume %s requests %s but allowExpansion is not set", vol.Name, desired.String())
+		return nil
+	}
+
+	expandable, err := r.storageClassAllowsExpansion(ctx, pvc)
+	if err != nil {
+		return err
+	}
+	if !expandable {
+		r.Recorder.Eventf(task, corev1.EventTypeWarning, "VolumeResizeRejected",
+			"Storage class of volume %s does not allow expansion", vol.Name)
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Expanding task volume", "pvc", pvc.Name, "size", desired.String())
+	patch := client.MergeFrom(pvc.DeepCopy())
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *desired
+	if err := r.Patch(ctx, pvc, patch); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(task, corev1.EventTypeNormal, "VolumeExpanding", "Expanding volume %s to %s", vol.Name, desired.String())
+	return nil
+}
+
+func (r *SwarmTaskReconciler) storageClassAllowsExpansion(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, nil
+	}
+	sc := &storagev1.StorageClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, sc); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion, nil
+}
+
+// reclaimTaskVolumes applies each volume's reclaim policy once the task has
+// finished. It returns true while a recycle Job is still running.
+func (r *SwarmTaskReconciler) reclaimTaskVolumes(ctx context.Context, task *swarmv1alpha1.SwarmTask) (bool, error) {
+	namespace := r.determineNamespace(task)
+	pending := false
+	changed := false
+
+	for _, vol := range task.Spec.PersistentVolumes {
+		status := findVolumeStatus(task, vol.Name)
+		if status == nil {
+			task.Status.Volumes = append(task.Status.Volumes, swarmv1alpha1.TaskVolumeStatus{
+				Name:      vol.Name,
+				ClaimName: taskVolumeClaimName(task, vol),
+			})
+			status = &task.Status.Volumes[len(task.Status.Volumes)-1]
+		}
+		if status.Reclaim != "" && status.Reclaim != volumeRecycling {
+			continue
+		}
+
+		reclaim, err := r.reclaimTaskVolume(ctx, task, vol, namespace)
+		if err != nil {
+			return false, err
+		}
+		if reclaim == volumeRecycling {
+			pending = true
+		}
+		if reclaim != status.Reclaim {
+			status.Reclaim = reclaim
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := r.Status().Update(ctx, task); err != nil {
+			return false, err
+		}
+	}
+	return pending, nil
+}
+
+func (r *SwarmTaskReconciler) reclaimTaskVolume(ctx context.Context, task *swarmv1alpha1.SwarmTask, vol swarmv1alpha1.TaskVolumeSpec, namespace string) (string, error) {
+	log := log.FromContext(ctx)
+	claimName := taskVolumeClaimName(task, vol)
+
+	switch vol.ReclaimPolicy {
+	case swarmv1alpha1.DeleteVolumeReclaimPolicy:
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Name = claimName
+		pvc.Namespace = namespace
+		if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		log.Info("Deleted task volume", "pvc", claimName)
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "VolumeDeleted", "Deleted volume %s", vol.Name)
+		return volumeDeleted, nil
+
+	case swarmv1alpha1.RecycleVolumeReclaimPolicy:
+		job := &batchv1.Job{}
+		err := r.Get(ctx, types.NamespacedName{Name: claimName + "-recycle", Namespace: namespace}, job)
+		if errors.IsNotFound(err) {
+			log.Info("Recycling task volume", "pvc", claimName)
+			recycle := constructRecycleJob(task, claimName, namespace)
+			// The cluster may be gone by now, leaving the task's image config
+			cluster := &swarmv1alpha1.SwarmCluster{}
+			if err := r.Get(ctx, types.NamespacedName{Name: task.Spec.SwarmCluster, Namespace: task.Namespace}, cluster); err != nil {
+				if !errors.IsNotFound(err) {
+					return "", err
+				}
+				cluster = nil
+			}
+			utils.ApplyImageConfig(&recycle.Spec.Template.Spec, taskImageConfig(task, cluster))
+			if err := r.Create(ctx, recycle); err != nil {
+				return "", err
+			}
+			return volumeRecycling, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if job.Status.Succeeded > 0 {
+			r.Recorder.Eventf(task, corev1.EventTypeNormal, "VolumeRecycled", "Recycled volume %s", vol.Name)
+			return volumeRecycled, nil
+		}
+		return volumeRecycling, nil
+
+	default:
+		return volumeRetained, nil
+	}
+}
+
+// constructRecycleJob builds a Job that wipes the contents of a claim
+func constructRecycleJob(task *swarmv1alpha1.SwarmTask, claimName, namespace string) *batchv1.Job {
+	ttl := int32(300)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName + "-recycle",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"swarm.claudeflow.io/task":    task.Name,
+				"swarm.claudeflow.io/cluster": task.Spec.SwarmCluster,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{
+						{
+							Name:    "recycle",
+							Image:   "busybox:latest",
+							Command: []string{"/bin/sh", "-c"},
+							Args:    []string{fmt.Sprintf("find %s -mindepth 1 -delete", recycleMountPath)},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "data", MountPath: recycleMountPath},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: claimName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// deleteTaskVolumes removes claims with a Delete or Recycle policy when the
+// task itself is deleted; a recycled claim has no task left to reuse it
+func (r *SwarmTaskReconciler) deleteTaskVolumes(ctx context.Context, task *swarmv1alpha1.SwarmTask) error {
+	namespace := r.determineNamespace(task)
+	for _, vol := range task.Spec.PersistentVolumes {
+		if vol.ReclaimPolicy != swarmv1alpha1.DeleteVolumeReclaimPolicy && vol.ReclaimPolicy != swarmv1alpha1.RecycleVolumeReclaimPolicy {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Name = taskVolumeClaimName(task, vol)
+		pvc.Namespace = namespace
+		if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func findVolumeStatus(task *swarmv1alpha1.SwarmTask, name string) *swarmv1alpha1.TaskVolumeStatus {
+	for i := range task.Status.Volumes {
+		if task.Status.Volumes[i].Name == name {
+			return &task.Status.Volumes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task volumes", func() {
+	claim := func(size string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+				},
+			},
+		}
+	}
+
+	It("should report growth when the size is increased", func() {
+		desired, err := volumeGrowth(claim("1Gi"), swarmv1alpha1.TaskVolumeSpec{Name: "data", Size: "5Gi"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(desired).NotTo(BeNil())
+		Expect(desired.String()).To(Equal("5Gi"))
+	})
+
+	It("should not report growth for an unchanged size", func() {
+		desired, err := volumeGrowth(claim("1Gi"), swarmv1alpha1.TaskVolumeSpec{Name: "data"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(desired).To(BeNil())
+	})
+
+	It("should reject shrinking a claim", func() {
+		_, err := volumeGrowth(claim("5Gi"), swarmv1alpha1.TaskVolumeSpec{Name: "data", Size: "1Gi"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should mount every claim into the executor", func() {
+		task := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "build"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				PersistentVolumes: []swarmv1alpha1.TaskVolumeSpec{
+					{Name: "cache", MountPath: "/cache"},
+				},
+			},
+		}
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "task"}}}
+		applyTaskVolumes(task, podSpec)
+
+		Expect(podSpec.Volumes).To(HaveLen(1))
+		Expect(podSpec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("build-cache"))
+		Expect(podSpec.Containers[0].VolumeMounts[0].MountPath).To(Equal("/cache"))
+	})
+})
@@ -0,0 +1,420 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/utils"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=create;delete
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters/status,verbs=get;update;patch
+
+const (
+	// warmPoolLabel holds the name of the cluster an idle pod is kept for.
+	// Claiming a pod removes it.
+	warmPoolLabel = "swarm.claudeflow.io/warm-pool"
+
+	// warmPoolNamespaceLabel holds the namespace of that cluster, pool pods
+	// run in the task namespace
+	warmPoolNamespaceLabel = "swarm.claudeflow.io/warm-pool-namespace"
+
+	// warmPoolHashLabel hashes the pod spec of a pool pod apart from the
+	// environment of the task container. Tasks only claim pods whose spec
+	// hashes the same as their own Job's.
+	warmPoolHashLabel = "swarm.claudeflow.io/warm-pool-hash"
+
+	// warmClaimLabel on a claimed pod and the Job template selects the pod
+	// for the Job that adopts it
+	warmClaimLabel = "swarm.claudeflow.io/warm-claim"
+
+	// warmClaimAnnotation hands the task environment to a claimed pod as
+	// base64 encoded shell exports
+	warmClaimAnnotation = "swarm.claudeflow.io/warm-claim"
+
+	warmClaimVolumeName = "swarm-claim"
+	warmClaimMountPath  = "/etc/swarm/claim"
+
+	defaultWarmPoolMaxSize      = 10
+	defaultWarmPoolDemandWindow = 10 * time.Minute
+
+	// warmPoolResync recounts the demand while no pods or tasks change
+	warmPoolResync = time.Minute
+)
+
+// warmClaimScript wraps the task container command of a pool pod. It waits
+// for the claim annotation in the downward API file, exports the task
+// environment and runs the original command, which follows as arguments.
+var warmClaimScript = fmt.Sprintf(`f=%[1]s/annotations
+until grep -q '^%[2]s=' "$f" 2>/dev/null; do sleep 1; done
+eval "$(sed -n 's|^%[2]s="\(.*\)"$|\1|p' "$f" | base64 -d)"
+exec "$@"`, warmClaimMountPath, warmClaimAnnotation)
+
+// warmPoolEnabled reports whether the cluster keeps a warm pool
+func warmPoolEnabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.WarmPool != nil && cluster.Spec.WarmPool.Enabled
+}
+
+// warmPoolDemandWindow returns how far back task starts size the pool
+func warmPoolDemandWindow(spec *swarmv1alpha1.WarmPoolSpec) time.Duration {
+	if window, err := time.ParseDuration(spec.DemandWindow); err == nil && window > 0 {
+		return window
+	}
+	return defaultWarmPoolDemandWindow
+}
+
+// warmPoolTemplateTask is the task pool pods are built for: a Linux task of
+// the cluster with the defaults of the SwarmTask API. Tasks asking for
+// anything that changes their pod, such as volumes, hooks, a checkout or
+// another priority class, do not match the pool and start a Job as usual.
+func warmPoolTemplateTask(cluster *swarmv1alpha1.SwarmCluster) *swarmv1alpha1.SwarmTask {
+	return &swarmv1alpha1.SwarmTask{
+		ObjectMeta: metav1.ObjectMeta{Name: cluster.Name + "-warm-pool", Namespace: cluster.Namespace},
+		Spec: swarmv1alpha1.SwarmTaskSpec{
+			SwarmCluster: cluster.Name,
+			Priority:     swarmv1alpha1.MediumPriority,
+			OS:           swarmv1alpha1.LinuxOS,
+			Isolation:    swarmv1alpha1.StandardIsolation,
+		},
+	}
+}
+
+// warmPodHash hashes a task pod spec without the plain environment values
+// of the task container, which claims hand over separately
+func warmPodHash(podSpec *corev1.PodSpec) string {
+	spec := podSpec.DeepCopy()
+	spec.Containers[0].Env = secretEnv(spec.Containers[0].Env)
+	data, _ := json.Marshal(spec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// secretEnv returns the variables that are read from other resources
+func secretEnv(env []corev1.EnvVar) []corev1.EnvVar {
+	var kept []corev1.EnvVar
+	for _, e := range env {
+		if e.ValueFrom != nil {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// warmClaimEnv encodes the plain environment of the task container for the
+// claim annotation
+func warmClaimEnv(env []corev1.EnvVar) string {
+	var b strings.Builder
+	for _, e := range env {
+		if e.ValueFrom != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "export %s='%s'\n", e.Name, strings.ReplaceAll(e.Value, "'", `'\''`))
+	}
+	return base64.StdEncoding.EncodeToString([]byte(b.String()))
+}
+
+// warmPoolPod builds an idle pod from the Job of the template task. The task
+// container waits for a claim before running the executor.
+func warmPoolPod(cluster *swarmv1alpha1.SwarmCluster, job *batchv1.Job, hash string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: cluster.Name + "-warm-",
+			Namespace:    job.Namespace,
+			Labels: map[string]string{
+				"swarm.claudeflow.io/cluster": cluster.Name,
+				warmPoolLabel:                 cluster.Name,
+				warmPoolNamespaceLabel:        cluster.Namespace,
+				warmPoolHashLabel:             hash,
+			},
+		},
+		Spec: *job.Spec.Template.Spec.DeepCopy(),
+	}
+
+	container := &pod.Spec.Containers[0]
+	container.Env = secretEnv(container.Env)
+	container.Args = append(append([]string{}, container.Command...), container.Args...)
+	container.Command = []string{"/bin/sh", "-c", warmClaimScript, "sh"}
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      warmClaimVolumeName,
+		MountPath: warmClaimMountPath,
+		ReadOnly:  true,
+	})
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: warmClaimVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{{
+					Path:     "annotations",
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"},
+				}},
+			},
+		},
+	})
+	return pod
+}
+
+// warmPodIdle reports whether a pool pod can take a task
+func warmPodIdle(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning
+}
+
+// warmPoolSelector selects the pool pods of the cluster
+func warmPoolSelector(namespace, name string) client.MatchingLabels {
+	return client.MatchingLabels{warmPoolLabel: name, warmPoolNamespaceLabel: namespace}
+}
+
+// reconcileWarmPool keeps as many idle executor pods for the cluster as
+// tasks of the cluster started within the demand window, between the
+// configured minimum and maximum. Pods built from an outdated executor
+// configuration are replaced.
+func (r *SwarmTaskReconciler) reconcileWarmPool(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	if err := r.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.deleteWarmPool(ctx, req.Namespace, req.Name, nil)
+	}
+	if cluster.DeletionTimestamp != nil || !warmPoolEnabled(cluster) {
+		if err := r.deleteWarmPool(ctx, req.Namespace, req.Name, nil); err != nil {
+			return ctrl.Result{}, err
+		}
+		if cluster.Status.WarmPool != nil && cluster.DeletionTimestamp == nil {
+			cluster.Status.WarmPool = nil
+			return ctrl.Result{}, r.Status().Update(ctx, cluster)
+		}
+		return ctrl.Result{}, nil
+	}
+	if err := resolveSwarmProfile(ctx, r, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	spec := cluster.Spec.WarmPool
+
+	template := warmPoolTemplateTask(cluster)
+	namespace := r.determineNamespace(template)
+	job, _, err := r.buildJob(ctx, template, cluster, namespace, "")
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	utils.ApplyImageConfig(&job.Spec.Template.Spec, cluster.Spec.ImageConfig)
+	hash := warmPodHash(&job.Spec.Template.Spec)
+
+	recent, err := r.recentTaskStarts(ctx, cluster, warmPoolDemandWindow(spec))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	maxSize := spec.MaxSize
+	if maxSize == 0 {
+		maxSize = defaultWarmPoolMaxSize
+	}
+	desired := max(spec.MinSize, min(recent, maxSize))
+	// The placeholder container echoes the task description, so no task
+	// pod would ever match the pool, and executors run through the image
+	// entrypoint have no command to run once claimed
+	if container := job.Spec.Template.Spec.Containers[0]; container.Image == placeholderExecutorImage || len(container.Command) == 0 {
+		desired = 0
+	}
+
+	if err := r.deleteWarmPool(ctx, cluster.Namespace, cluster.Name, func(pod *corev1.Pod) bool {
+		return pod.Labels[warmPoolHashLabel] != hash ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, warmPoolSelector(cluster.Namespace, cluster.Name)); err != nil {
+		return ctrl.Result{}, err
+	}
+	var live []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil {
+			live = append(live, pod)
+		}
+	}
+
+	if missing := int(desired) - len(live); missing > 0 {
+		if err := r.ensureNamespace(ctx, namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.ensurePriorityClass(ctx, job.Spec.Template.Spec.PriorityClassName); err != nil {
+			return ctrl.Result{}, err
+		}
+		for i := 0; i < missing; i++ {
+			pod := warmPoolPod(cluster, job, hash)
+			if err := r.Create(ctx, pod); err != nil {
+				return ctrl.Result{}, err
+			}
+			live = append(live, *pod)
+		}
+		log.Info("Scaled up warm pool", "namespace", namespace, "pods", len(live))
+	} else if missing < 0 {
+		// Pods still starting go first, then the youngest
+		sort.SliceStable(live, func(i, j int) bool {
+			if idle := warmPodIdle(&live[i]); idle != warmPodIdle(&live[j]) {
+				return !idle
+			}
+			return live[j].CreationTimestamp.Before(&live[i].CreationTimestamp)
+		})
+		for i := 0; i < -missing; i++ {
+			if err := r.Delete(ctx, &live[i]); err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		live = live[-missing:]
+		log.Info("Scaled down warm pool", "namespace", namespace, "pods", len(live))
+	}
+
+	status := &swarmv1alpha1.WarmPoolStatus{Desired: desired, RecentStarts: recent}
+	for i := range live {
+		if warmPodIdle(&live[i]) {
+			status.Ready++
+		} else {
+			status.Pending++
+		}
+	}
+	if !equality.Semantic.DeepEqual(cluster.Status.WarmPool, status) {
+		cluster.Status.WarmPool = status
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: warmPoolResync}, nil
+}
+
+// recentTaskStarts counts the tasks of the cluster that started within the
+// window
+func (r *SwarmTaskReconciler) recentTaskStarts(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, window time.Duration) (int32, error) {
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, tasks, client.InNamespace(cluster.Namespace)); err != nil {
+		return 0, err
+	}
+	since := time.Now().Add(-window)
+	var count int32
+	for _, task := range tasks.Items {
+		if task.Spec.SwarmCluster == cluster.Name && task.Status.StartTime != nil && task.Status.StartTime.Time.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// deleteWarmPool deletes the pool pods of the cluster that match, or all of
+// them when match is nil
+func (r *SwarmTaskReconciler) deleteWarmPool(ctx context.Context, namespace, name string, match func(*corev1.Pod) bool) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, warmPoolSelector(namespace, name)); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || (match != nil && !match(pod)) {
+			continue
+		}
+		if err := r.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimWarmPod hands an idle pool pod whose spec matches the Job to the task
+// and makes the Job select it. The Job controller adopts the pod instead of
+// creating one. Pods stay with the Job they were claimed for and are
+// deleted with it rather than returned to the pool, so no task sees the
+// workspace or environment of another. It reports whether a pod was
+// claimed.
+func (r *SwarmTaskReconciler) claimWarmPod(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster, job *batchv1.Job) (bool, error) {
+	template := &job.Spec.Template
+	selector := warmPoolSelector(cluster.Namespace, cluster.Name)
+	selector[warmPoolHashLabel] = warmPodHash(&template.Spec)
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(job.Namespace), selector); err != nil {
+		return false, err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !warmPodIdle(pod) {
+			continue
+		}
+
+		// The optimistic lock keeps two tasks from claiming the same pod
+		patch := client.MergeFromWithOptions(pod.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		delete(pod.Labels, warmPoolLabel)
+		delete(pod.Labels, warmPoolNamespaceLabel)
+		delete(pod.Labels, warmPoolHashLabel)
+		for k, v := range template.Labels {
+			pod.Labels[k] = v
+		}
+		pod.Labels["job-name"] = job.Name
+		pod.Labels[warmClaimLabel] = pod.Name
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[warmClaimAnnotation] = warmClaimEnv(template.Spec.Containers[0].Env)
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			if errors.IsConflict(err) || errors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+
+		manualSelector := true
+		job.Spec.ManualSelector = &manualSelector
+		job.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{warmClaimLabel: pod.Name}}
+		template.Labels[warmClaimLabel] = pod.Name
+		template.Labels["job-name"] = job.Name
+		r.Recorder.Eventf(task, corev1.EventTypeNormal, "WarmStart", "Starting in warm pool pod %s", pod.Name)
+		return true, nil
+	}
+	return false, nil
+}
+
+// mapWarmPodToCluster maps pool pods to the cluster they are kept for
+func mapWarmPodToCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, namespace := labels[warmPoolLabel], labels[warmPoolNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Warm pool", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmTaskReconciler
+	)
+
+	startedTask := func(name string, ago time.Duration) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+			Status:     swarmv1alpha1.SwarmTaskStatus{StartTime: &metav1.Time{Time: time.Now().Add(-ago)}},
+		}
+	}
+
+	newReconciler := func(objects ...client.Object) {
+		reconciler = &SwarmTaskReconciler{
+			Client:         newTestClient(append(objects, cluster)...),
+			Scheme:         testScheme,
+			Recorder:       record.NewFakeRecorder(10),
+			SwarmNamespace: "swarm-tasks",
+			Executor:       ExecutorConfig{Image: "executor:v1", ScriptsConfigMap: "executor-scripts"},
+		}
+	}
+
+	reconcilePool := func() []corev1.Pod {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "swarm", Namespace: "default"}}
+		_, err := reconciler.reconcileWarmPool(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		pods := &corev1.PodList{}
+		Expect(reconciler.List(ctx, pods, warmPoolSelector("default", "swarm"))).To(Succeed())
+		return pods.Items
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				WarmPool: &swarmv1alpha1.WarmPoolSpec{Enabled: true, MinSize: 1, MaxSize: 3, DemandWindow: "10m"},
+			},
+		}
+	})
+
+	It("sizes the pool by the tasks started within the demand window", func() {
+		newReconciler(startedTask("a", time.Minute), startedTask("b", 2*time.Minute), startedTask("old", time.Hour))
+
+		pods := reconcilePool()
+		Expect(pods).To(HaveLen(2))
+		Expect(pods[0].Namespace).To(Equal("swarm-tasks"))
+		container := pods[0].Spec.Containers[0]
+		Expect(container.Image).To(Equal("executor:v1"))
+		Expect(container.Command).To(Equal([]string{"/bin/sh", "-c", warmClaimScript, "sh"}))
+		Expect(container.Args).To(Equal([]string{"/scripts/entrypoint.sh", "/scripts/task.sh"}))
+		Expect(container.Env).To(BeEmpty())
+
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm", Namespace: "default"}, cluster)).To(Succeed())
+		Expect(cluster.Status.WarmPool).To(Equal(&swarmv1alpha1.WarmPoolStatus{Desired: 2, Pending: 2, RecentStarts: 2}))
+	})
+
+	It("replaces pods built from another executor configuration", func() {
+		newReconciler()
+		Expect(reconcilePool()).To(HaveLen(1))
+
+		reconciler.Executor.Image = "executor:v2"
+		pods := reconcilePool()
+		Expect(pods).To(HaveLen(1))
+		Expect(pods[0].Spec.Containers[0].Image).To(Equal("executor:v2"))
+	})
+
+	It("removes the pool once disabled", func() {
+		newReconciler()
+		Expect(reconcilePool()).To(HaveLen(1))
+
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "swarm", Namespace: "default"}, cluster)).To(Succeed())
+		cluster.Spec.WarmPool.Enabled = false
+		Expect(reconciler.Update(ctx, cluster)).To(Succeed())
+		Expect(reconcilePool()).To(BeEmpty())
+	})
+
+	Context("claiming pods", func() {
+		var task *swarmv1alpha1.SwarmTask
+
+		BeforeEach(func() {
+			task = &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"},
+				Spec: swarmv1alpha1.SwarmTaskSpec{
+					SwarmCluster: "swarm",
+					Description:  "it's training",
+					Priority:     swarmv1alpha1.MediumPriority,
+				},
+			}
+			newReconciler(task)
+			pods := reconcilePool()
+			Expect(pods).To(HaveLen(1))
+			pods[0].Status.Phase = corev1.PodRunning
+			Expect(reconciler.Status().Update(ctx, &pods[0])).To(Succeed())
+		})
+
+		It("starts the task in an idle pod with the same spec", func() {
+			job, err := reconciler.createOrUpdateJob(ctx, task, cluster, "swarm-tasks", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*job.Spec.ManualSelector).To(BeTrue())
+			claim := job.Spec.Selector.MatchLabels[warmClaimLabel]
+			Expect(claim).NotTo(BeEmpty())
+			Expect(job.Spec.Template.Labels).To(HaveKeyWithValue(warmClaimLabel, claim))
+
+			pod := &corev1.Pod{}
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: claim, Namespace: "swarm-tasks"}, pod)).To(Succeed())
+			Expect(pod.Labels).NotTo(HaveKey(warmPoolLabel))
+			Expect(pod.Labels).To(HaveKeyWithValue("job-name", job.Name))
+			Expect(pod.Labels).To(HaveKeyWithValue("swarm.claudeflow.io/task", "train"))
+			env, err := base64.StdEncoding.DecodeString(pod.Annotations[warmClaimAnnotation])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(env)).To(ContainSubstring("export SWARM_TASK_NAME='train'\n"))
+			Expect(string(env)).To(ContainSubstring(`export SWARM_TASK_DESCRIPTION='it'\''s training'`))
+
+			Expect(reconcilePool()).To(HaveLen(1))
+		})
+
+		It("starts a Job as usual when no pod matches", func() {
+			task.Spec.ExecutorImage = "custom:latest"
+			job, err := reconciler.createOrUpdateJob(ctx, task, cluster, "swarm-tasks", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.Spec.Selector).To(BeNil())
+			Expect(job.Spec.Template.Labels).NotTo(HaveKey(warmClaimLabel))
+		})
+
+		It("tracks Jobs in the task namespace by label and deletes them with the task", func() {
+			task.Spec.ExecutorImage = "custom:latest"
+			job, err := reconciler.createOrUpdateJob(ctx, task, cluster, "swarm-tasks", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.OwnerReferences).To(BeEmpty())
+			Expect(job.Labels).To(HaveKeyWithValue(taskNamespaceLabel, "default"))
+			Expect(isUnownedTaskObject(job)).To(BeTrue())
+			Expect(mapToTask(ctx, job)).To(ConsistOf(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(task)}))
+
+			Expect(reconciler.deleteUnownedJobs(ctx, task)).To(Succeed())
+			jobs := &batchv1.JobList{}
+			Expect(reconciler.List(ctx, jobs, client.InNamespace("swarm-tasks"))).To(Succeed())
+			Expect(jobs.Items).To(BeEmpty())
+		})
+	})
+})
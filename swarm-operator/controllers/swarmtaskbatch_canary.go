@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// batchCanary is the phase of a batch while its canaries run
+	batchCanary = "Canary"
+
+	canaryRunning = "Running"
+	canaryPassed  = "Passed"
+	canaryFailed  = "Failed"
+
+	defaultCanaryPercentage = 5
+)
+
+// batchCanarySize returns the number of canary items, at least one and at
+// most every item
+func batchCanarySize(spec *swarmv1alpha1.BatchCanary, total int) int {
+	size := 0
+	switch {
+	case spec.Size != nil:
+		size = int(*spec.Size)
+	default:
+		percentage := defaultCanaryPercentage
+		if spec.Percentage != nil {
+			percentage = int(*spec.Percentage)
+		}
+		size = (total*percentage + 99) / 100
+	}
+	if size < 1 {
+		size = 1
+	}
+	if size > total {
+		size = total
+	}
+	return size
+}
+
+// canarySuccessThreshold returns the percentage of canaries that must
+// succeed
+func canarySuccessThreshold(spec *swarmv1alpha1.BatchCanary) int32 {
+	if spec.SuccessThreshold == nil {
+		return 100
+	}
+	return *spec.SuccessThreshold
+}
+
+// batchCanaryStatus aggregates the canary tasks of a batch. A running
+// canary fails as soon as the threshold is out of reach, or at the first
+// failure with HaltOnFailure. A canary that passed or failed keeps its
+// results.
+func batchCanaryStatus(batch *swarmv1alpha1.SwarmTaskBatch, tasks []swarmv1alpha1.SwarmTask, total int) *swarmv1alpha1.BatchCanaryStatus {
+	spec := batch.Spec.Canary
+	if spec == nil {
+		return nil
+	}
+	if previous := batch.Status.Canary; previous != nil && previous.Phase != canaryRunning {
+		return previous
+	}
+
+	size := batchCanarySize(spec, total)
+	status := &swarmv1alpha1.BatchCanaryStatus{Phase: canaryRunning, Size: int32(size)}
+	for i := range tasks {
+		task := &tasks[i]
+		index, err := strconv.Atoi(task.Labels[batchIndexLabel])
+		if err != nil || index >= size {
+			continue
+		}
+		switch task.Status.Phase {
+		case "Completed", taskPhaseSkipped:
+			status.Succeeded++
+		case "Failed", "Cancelled", taskPhaseDeadLettered:
+			status.Failed++
+			status.FailedTasks = append(status.FailedTasks, task.Name)
+		}
+	}
+	sort.Strings(status.FailedTasks)
+	if finished := status.Succeeded + status.Failed; finished > 0 {
+		status.SuccessRate = status.Succeeded * 100 / finished
+	}
+
+	threshold := canarySuccessThreshold(spec)
+	switch {
+	case spec.HaltOnFailure && status.Failed > 0:
+		status.Phase = canaryFailed
+	case (status.Size-status.Failed)*100 < threshold*status.Size:
+		// Even if every running canary succeeds
+		status.Phase = canaryFailed
+	case status.Succeeded+status.Failed == status.Size:
+		status.Phase = canaryPassed
+	}
+	return status
+}
+
+// canaryMessage describes the outcome of a finished canary
+func canaryMessage(spec *swarmv1alpha1.BatchCanary, status *swarmv1alpha1.BatchCanaryStatus) string {
+	if status.Phase == canaryFailed && spec.HaltOnFailure && len(status.FailedTasks) > 0 {
+		return fmt.Sprintf("Canary task %s failed", status.FailedTasks[0])
+	}
+	return fmt.Sprintf("%d of %d canary tasks succeeded, the success threshold is %d%%",
+		status.Succeeded, status.Size, canarySuccessThreshold(spec))
+}
@@ -0,0 +1,467 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+const (
+	// batchLabel names the SwarmTaskBatch a task belongs to
+	batchLabel = "swarm.claudeflow.io/batch"
+
+	// batchIndexLabel carries the item index of a batch task
+	batchIndexLabel = "swarm.claudeflow.io/batch-index"
+
+	// ConditionTypeItemsResolved reports whether the items of a batch could
+	// be expanded
+	ConditionTypeItemsResolved = "ItemsResolved"
+
+	ReasonItemsResolved = "ItemsResolved"
+	ReasonInvalidItems  = "InvalidItems"
+
+	batchPending   = "Pending"
+	batchRunning   = "Running"
+	batchPaused    = "Paused"
+	batchCompleted = "Completed"
+	batchFailed    = "Failed"
+	batchCancelled = "Cancelled"
+
+	defaultBatchParallelism = int32(10)
+
+	// maxBatchItems bounds the tasks a single batch expands into
+	maxBatchItems = 10000
+
+	// batchRetryInterval is how long a batch whose items cannot be resolved,
+	// e.g. for a missing ConfigMap, waits before trying again
+	batchRetryInterval = time.Minute
+)
+
+// SwarmTaskBatchReconciler reconciles a SwarmTaskBatch object
+type SwarmTaskBatchReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MetricsRecorder records reconcile durations, disabled when nil
+	MetricsRecorder *metrics.MetricsRecorder
+}
+
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtaskbatches,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtaskbatches/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtaskbatches/finalizers,verbs=update
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *SwarmTaskBatchReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmTaskBatch", req.NamespacedName)
+	log := log.FromContext(ctx)
+
+	batch := &swarmv1alpha1.SwarmTaskBatch{}
+	if err := r.Get(ctx, req.NamespacedName, batch); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Batch tasks are owned by the batch and garbage collected
+	if batch.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+	before := batch.Status.DeepCopy()
+
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := r.List(ctx, tasks, client.InNamespace(batch.Namespace), client.MatchingLabels{batchLabel: batch.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	items, err := r.batchItems(ctx, batch)
+	if err != nil {
+		log.Error(err, "Failed to resolve batch items")
+		batch.Status.Message = err.Error()
+		if meta.SetStatusCondition(&batch.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeItemsResolved,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonInvalidItems,
+			Message:            err.Error(),
+			ObservedGeneration: batch.Generation,
+		}) {
+			r.Recorder.Event(batch, corev1.EventTypeWarning, ReasonInvalidItems, err.Error())
+		}
+		if batch.Status.Phase == "" {
+			batch.Status.Phase = batchPending
+		}
+		if err := r.updateBatchStatus(ctx, batch, before); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: batchRetryInterval}, nil
+	}
+	meta.SetStatusCondition(&batch.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeItemsResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonItemsResolved,
+		Message:            fmt.Sprintf("Expanded into %d tasks", len(items)),
+		ObservedGeneration: batch.Generation,
+	})
+	batch.Status.Message = ""
+
+	byIndex, stray := batchTasksByIndex(batch, tasks.Items, len(items))
+	var active []*swarmv1alpha1.SwarmTask
+	var created, succeeded, failed int32
+	for _, task := range byIndex {
+		if task == nil {
+			continue
+		}
+		created++
+		switch task.Status.Phase {
+		case "Completed", taskPhaseSkipped:
+			succeeded++
+		case "Failed", "Cancelled", taskPhaseDeadLettered:
+			failed++
+		default:
+			active = append(active, task)
+		}
+	}
+	// Stray tasks still take a slot of the parallelism while they run
+	for _, task := range stray {
+		switch task.Status.Phase {
+		case "Completed", taskPhaseSkipped, "Failed", "Cancelled", taskPhaseDeadLettered:
+		default:
+			active = append(active, task)
+		}
+	}
+
+	// Cancelled batches stay cancelled
+	cancelled := batch.Spec.Cancel || batch.Status.Phase == batchCancelled
+	exhausted := batch.Spec.MaxFailures != nil && failed > *batch.Spec.MaxFailures
+
+	// The rest of the batch waits for its canaries
+	canary := batchCanaryStatus(batch, tasks.Items, len(items))
+	canaryRun := canary != nil && canary.Phase == canaryRunning
+	canaryFail := canary != nil && canary.Phase == canaryFailed
+	if canary != nil && !canaryRun && (batch.Status.Canary == nil || batch.Status.Canary.Phase != canary.Phase) {
+		if canaryFail {
+			r.Recorder.Event(batch, corev1.EventTypeWarning, "CanaryFailed", canaryMessage(batch.Spec.Canary, canary))
+		} else {
+			r.Recorder.Eventf(batch, corev1.EventTypeNormal, "CanaryPassed", "%s, creating the other %d tasks",
+				canaryMessage(batch.Spec.Canary, canary), len(items)-int(canary.Size))
+		}
+	}
+
+	switch {
+	case cancelled:
+		for _, task := range active {
+			if err := r.Delete(ctx, task); err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+		}
+		if batch.Status.Phase != batchCancelled {
+			r.Recorder.Eventf(batch, corev1.EventTypeNormal, "BatchCancelled", "Cancelled %d unfinished tasks", len(active))
+		}
+		active = nil
+	case canaryFail:
+		if batch.Spec.Canary.HaltOnFailure {
+			for _, task := range active {
+				if err := r.Delete(ctx, task); err != nil && !errors.IsNotFound(err) {
+					return ctrl.Result{}, err
+				}
+			}
+			active = nil
+		}
+	case exhausted, batch.Spec.Paused:
+	default:
+		parallelism := batch.Spec.Parallelism
+		if parallelism <= 0 {
+			parallelism = defaultBatchParallelism
+		}
+		limit := len(items)
+		if canaryRun {
+			limit = int(canary.Size)
+		}
+		for index := 0; index < limit && int32(len(active)) < parallelism; index++ {
+			if byIndex[index] != nil {
+				continue
+			}
+			task, err := r.createBatchTask(ctx, batch, index, items[index])
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			byIndex[index] = task
+			created++
+			active = append(active, task)
+		}
+	}
+
+	status := &batch.Status
+	status.Total = int32(len(items))
+	status.Created = created
+	status.Active = int32(len(active))
+	status.Succeeded = succeeded
+	status.Failed = failed
+	status.Progress = fmt.Sprintf("%d/%d", succeeded+failed, len(items))
+	status.Canary = canary
+	status.ObservedGeneration = batch.Generation
+	if status.StartTime == nil && status.Created > 0 {
+		now := metav1.Now()
+		status.StartTime = &now
+	}
+
+	phase := batchRunning
+	switch {
+	case cancelled:
+		phase = batchCancelled
+	case exhausted:
+		phase = batchFailed
+		status.Message = fmt.Sprintf("%d tasks failed, more than the %d allowed", failed, *batch.Spec.MaxFailures)
+	case canaryFail:
+		phase = batchFailed
+		status.Message = canaryMessage(batch.Spec.Canary, canary)
+	case status.Created == status.Total && status.Active == 0:
+		phase = batchCompleted
+	case batch.Spec.Paused:
+		phase = batchPaused
+	case canaryRun:
+		phase = batchCanary
+	case status.Created == 0:
+		phase = batchPending
+	}
+	finished := phase == batchCompleted || phase == batchFailed || phase == batchCancelled
+	if finished && status.Phase != phase {
+		now := metav1.Now()
+		status.CompletionTime = &now
+		eventType := corev1.EventTypeNormal
+		if phase == batchFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Eventf(batch, eventType, "Batch"+phase, "%d of %d tasks succeeded, %d failed", succeeded, len(items), failed)
+	}
+	if !finished {
+		status.CompletionTime = nil
+	}
+	status.Phase = phase
+
+	if err := r.updateBatchStatus(ctx, batch, before); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *SwarmTaskBatchReconciler) updateBatchStatus(ctx context.Context, batch *swarmv1alpha1.SwarmTaskBatch, before *swarmv1alpha1.SwarmTaskBatchStatus) error {
+	if equality.Semantic.DeepEqual(before, &batch.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, batch)
+}
+
+// createBatchTask creates the task of a batch item
+func (r *SwarmTaskBatchReconciler) createBatchTask(ctx context.Context, batch *swarmv1alpha1.SwarmTaskBatch, index int, parameters map[string]string) (*swarmv1alpha1.SwarmTask, error) {
+	spec := batch.Spec.Template.DeepCopy()
+	if len(parameters) > 0 && spec.Parameters == nil {
+		spec.Parameters = map[string]string{}
+	}
+	for key, value := range parameters {
+		spec.Parameters[key] = value
+	}
+
+	task := &swarmv1alpha1.SwarmTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      batchTaskName(batch, index),
+			Namespace: batch.Namespace,
+			Labels: map[string]string{
+				batchLabel:      batch.Name,
+				batchIndexLabel: strconv.Itoa(index),
+			},
+		},
+		Spec: *spec,
+	}
+	if err := controllerutil.SetControllerReference(batch, task, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, task); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+		// Created by an earlier reconcile the cache has not caught up with
+		if err := r.Get(ctx, types.NamespacedName{Name: task.Name, Namespace: task.Namespace}, task); err != nil {
+			return nil, err
+		}
+	}
+	return task, nil
+}
+
+// batchItems expands the listed items and the generator into the
+// parameters of every task of the batch
+func (r *SwarmTaskBatchReconciler) batchItems(ctx context.Context, batch *swarmv1alpha1.SwarmTaskBatch) ([]map[string]string, error) {
+	var items []map[string]string
+	for _, item := range batch.Spec.Items {
+		items = append(items, item.Parameters)
+	}
+
+	generator := batch.Spec.Generator
+	switch {
+	case generator == nil:
+	case generator.Range != nil:
+		gen := generator.Range
+		step := gen.Step
+		if step <= 0 {
+			step = 1
+		}
+		if gen.End < gen.Start {
+			return nil, fmt.Errorf("range start %d exceeds its end %d", gen.Start, gen.End)
+		}
+		if (int64(gen.End)-int64(gen.Start))/int64(step)+1+int64(len(items)) > maxBatchItems {
+			return nil, fmt.Errorf("batch expands into more than %d tasks", maxBatchItems)
+		}
+		parameter := defaultString(gen.Parameter, "index")
+		for n := int64(gen.Start); n <= int64(gen.End); n += int64(step) {
+			items = append(items, map[string]string{parameter: strconv.FormatInt(n, 10)})
+		}
+	case generator.List != nil:
+		parameter := defaultString(generator.List.Parameter, "item")
+		for _, value := range generator.List.Values {
+			items = append(items, map[string]string{parameter: value})
+		}
+	case generator.CSV != nil:
+		rows, err := r.csvItems(ctx, batch.Namespace, generator.CSV.ConfigMapKeyRef)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, rows...)
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("batch has no items")
+	}
+	if len(items) > maxBatchItems {
+		return nil, fmt.Errorf("batch expands into more than %d tasks", maxBatchItems)
+	}
+	return items, nil
+}
+
+// csvItems reads an item per row of a CSV document in a ConfigMap, keyed
+// by the header row
+func (r *SwarmTaskBatchReconciler) csvItems(ctx context.Context, namespace string, ref corev1.ConfigMapKeySelector) ([]map[string]string, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, cm); err != nil {
+		return nil, fmt.Errorf("failed to read ConfigMap %s: %w", ref.Name, err)
+	}
+	document, ok := cm.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s has no key %s", ref.Name, ref.Key)
+	}
+	return parseCSVItems(document)
+}
+
+// parseCSVItems parses a CSV document whose header row names the
+// parameters of its columns
+func parseCSVItems(document string) ([]map[string]string, error) {
+	reader := csv.NewReader(strings.NewReader(document))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV document is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	for i, name := range header {
+		if name == "" {
+			return nil, fmt.Errorf("CSV column %d has no name", i+1)
+		}
+	}
+
+	var items []map[string]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV row: %w", err)
+		}
+		if len(items) == maxBatchItems {
+			return nil, fmt.Errorf("batch expands into more than %d tasks", maxBatchItems)
+		}
+		item := make(map[string]string, len(header))
+		for i, name := range header {
+			item[name] = row[i]
+		}
+		items = append(items, item)
+	}
+}
+
+// batchTaskName returns the task name of a batch item
+// batchTasksByIndex orders the tasks of a batch by item index, so they are
+// counted and created the same way whatever order they are listed in.
+// Tasks not named after their index, or of items the batch no longer
+// expands into, e.g. rows removed from its CSV, are returned as stray.
+func batchTasksByIndex(batch *swarmv1alpha1.SwarmTaskBatch, tasks []swarmv1alpha1.SwarmTask, total int) (byIndex, stray []*swarmv1alpha1.SwarmTask) {
+	byIndex = make([]*swarmv1alpha1.SwarmTask, total)
+	for i := range tasks {
+		task := &tasks[i]
+		index, err := strconv.Atoi(task.Labels[batchIndexLabel])
+		if err != nil || index < 0 || index >= total || task.Name != batchTaskName(batch, index) {
+			stray = append(stray, task)
+			continue
+		}
+		byIndex[index] = task
+	}
+	return byIndex, stray
+}
+
+func batchTaskName(batch *swarmv1alpha1.SwarmTaskBatch, index int) string {
+	return fmt.Sprintf("%s-%d", batch.Name, index)
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwarmTaskBatchReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swarmv1alpha1.SwarmTaskBatch{}).
+		Owns(&swarmv1alpha1.SwarmTask{}).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmtaskbatch", r))
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("SwarmTaskBatch", func() {
+	var (
+		ctx        context.Context
+		batch      *swarmv1alpha1.SwarmTaskBatch
+		reconciler *SwarmTaskBatchReconciler
+	)
+
+	key := types.NamespacedName{Name: "review", Namespace: "default"}
+
+	build := func(objects ...client.Object) {
+		reconciler = &SwarmTaskBatchReconciler{
+			Client:   newTestClient(append(objects, batch)...),
+			Scheme:   testScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	reconcile := func() *swarmv1alpha1.SwarmTaskBatch {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		current := &swarmv1alpha1.SwarmTaskBatch{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		return current
+	}
+
+	tasks := func() []swarmv1alpha1.SwarmTask {
+		list := &swarmv1alpha1.SwarmTaskList{}
+		Expect(reconciler.List(ctx, list, client.MatchingLabels{batchLabel: "review"})).To(Succeed())
+		return list.Items
+	}
+
+	finish := func(name, phase string) {
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, task)).To(Succeed())
+		task.Status.Phase = phase
+		Expect(reconciler.Status().Update(ctx, task)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		batch = &swarmv1alpha1.SwarmTaskBatch{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: swarmv1alpha1.SwarmTaskBatchSpec{
+				Template: swarmv1alpha1.SwarmTaskSpec{
+					SwarmCluster: "swarm",
+					Parameters:   map[string]string{"repository": "monorepo"},
+				},
+				Generator: &swarmv1alpha1.BatchGenerator{
+					Range: &swarmv1alpha1.RangeGenerator{Start: 1, End: 5},
+				},
+				Parallelism: 2,
+			},
+		}
+	})
+
+	It("expands items, ranges and lists into parameters", func() {
+		batch.Spec.Items = []swarmv1alpha1.BatchItem{{Parameters: map[string]string{"service": "auth"}}}
+		batch.Spec.Generator.Range = &swarmv1alpha1.RangeGenerator{Start: 0, End: 6, Step: 3, Parameter: "shard"}
+		build()
+		items, err := reconciler.batchItems(ctx, batch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(Equal([]map[string]string{
+			{"service": "auth"}, {"shard": "0"}, {"shard": "3"}, {"shard": "6"},
+		}))
+
+		batch.Spec.Generator = &swarmv1alpha1.BatchGenerator{
+			List: &swarmv1alpha1.ListGenerator{Values: []string{"a", "b"}},
+		}
+		items, err = reconciler.batchItems(ctx, batch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(Equal([]map[string]string{{"service": "auth"}, {"item": "a"}, {"item": "b"}}))
+	})
+
+	It("reads CSV rows from a ConfigMap", func() {
+		batch.Spec.Generator = &swarmv1alpha1.BatchGenerator{
+			CSV: &swarmv1alpha1.CSVGenerator{ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "inputs"},
+				Key:                  "rows.csv",
+			}},
+		}
+		build(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "inputs", Namespace: "default"},
+			Data:       map[string]string{"rows.csv": "service,owner\nauth, alice\nbilling,bob\n"},
+		})
+		items, err := reconciler.batchItems(ctx, batch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(Equal([]map[string]string{
+			{"service": "auth", "owner": "alice"},
+			{"service": "billing", "owner": "bob"},
+		}))
+
+		_, err = parseCSVItems("service,owner\nauth\n")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reports items that cannot be resolved", func() {
+		batch.Spec.Generator = &swarmv1alpha1.BatchGenerator{
+			CSV: &swarmv1alpha1.CSVGenerator{ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+				Key:                  "rows.csv",
+			}},
+		}
+		build()
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(batchRetryInterval))
+
+		current := &swarmv1alpha1.SwarmTaskBatch{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		Expect(current.Status.Phase).To(Equal(batchPending))
+		Expect(current.Status.Message).To(ContainSubstring("missing"))
+		Expect(tasks()).To(BeEmpty())
+	})
+
+	It("runs the tasks with bounded parallelism until the batch completes", func() {
+		build()
+		current := reconcile()
+		Expect(current.Status.Phase).To(Equal(batchRunning))
+		Expect(current.Status.Total).To(Equal(int32(5)))
+		Expect(current.Status.Active).To(Equal(int32(2)))
+		Expect(current.Status.StartTime).NotTo(BeNil())
+
+		Expect(tasks()).To(HaveLen(2))
+		first := swarmv1alpha1.SwarmTask{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "review-0", Namespace: "default"}, &first)).To(Succeed())
+		Expect(first.Spec.Parameters).To(Equal(map[string]string{"repository": "monorepo", "index": "1"}))
+		Expect(metav1.IsControlledBy(&first, current)).To(BeTrue())
+
+		finish("review-0", "Completed")
+		finish("review-1", "Failed")
+		current = reconcile()
+		Expect(current.Status.Progress).To(Equal("2/5"))
+		Expect(tasks()).To(HaveLen(4))
+
+		for _, name := range []string{"review-2", "review-3"} {
+			finish(name, "Completed")
+		}
+		reconcile()
+		finish("review-4", "Completed")
+		current = reconcile()
+		Expect(current.Status.Phase).To(Equal(batchCompleted))
+		Expect(current.Status.Succeeded).To(Equal(int32(4)))
+		Expect(current.Status.Failed).To(Equal(int32(1)))
+		Expect(current.Status.Progress).To(Equal("5/5"))
+		Expect(current.Status.CompletionTime).NotTo(BeNil())
+	})
+
+	It("creates and counts tasks in item order whatever order they are listed in", func() {
+		task := func(index, phase string) *swarmv1alpha1.SwarmTask {
+			return &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "review-" + index,
+					Namespace: "default",
+					Labels:    map[string]string{batchLabel: "review", batchIndexLabel: index},
+				},
+				Status: swarmv1alpha1.SwarmTaskStatus{Phase: phase},
+			}
+		}
+		// review-7 is left from an item the batch no longer expands into
+		build(task("7", "Running"), task("3", "Running"), task("0", "Completed"))
+		current := reconcile()
+		Expect(current.Status.Created).To(Equal(int32(2)))
+		Expect(current.Status.Active).To(Equal(int32(2)))
+		Expect(tasks()).To(HaveLen(3))
+
+		finish("review-7", "Completed")
+		current = reconcile()
+		Expect(current.Status.Created).To(Equal(int32(3)))
+		Expect(current.Status.Succeeded).To(Equal(int32(1)))
+		Expect(current.Status.Progress).To(Equal("1/5"))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "review-1", Namespace: "default"}, &swarmv1alpha1.SwarmTask{})).To(Succeed())
+		Expect(tasks()).To(HaveLen(4))
+	})
+
+	It("stops creating tasks when paused or out of failures", func() {
+		batch.Spec.Paused = true
+		build()
+		Expect(reconcile().Status.Phase).To(Equal(batchPaused))
+		Expect(tasks()).To(BeEmpty())
+
+		current := &swarmv1alpha1.SwarmTaskBatch{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		current.Spec.Paused = false
+		noFailures := int32(0)
+		current.Spec.MaxFailures = &noFailures
+		Expect(reconciler.Update(ctx, current)).To(Succeed())
+		reconcile()
+		finish("review-0", "Failed")
+
+		current = reconcile()
+		Expect(current.Status.Phase).To(Equal(batchFailed))
+		Expect(tasks()).To(HaveLen(2))
+	})
+
+	It("deletes the unfinished tasks on cancel", func() {
+		build()
+		reconcile()
+		finish("review-0", "Completed")
+
+		current := &swarmv1alpha1.SwarmTaskBatch{}
+		Expect(reconciler.Get(ctx, key, current)).To(Succeed())
+		current.Spec.Cancel = true
+		Expect(reconciler.Update(ctx, current)).To(Succeed())
+
+		current = reconcile()
+		Expect(current.Status.Phase).To(Equal(batchCancelled))
+		Expect(current.Status.Active).To(BeZero())
+		remaining := tasks()
+		Expect(remaining).To(HaveLen(1))
+		Expect(remaining[0].Name).To(Equal("review-0"))
+	})
+
+	It("runs the canary before the rest of the batch", func() {
+		size := int32(2)
+		batch.Spec.Canary = &swarmv1alpha1.BatchCanary{Size: &size}
+		batch.Spec.Parallelism = 5
+		build()
+		current := reconcile()
+		Expect(current.Status.Phase).To(Equal(batchCanary))
+		Expect(current.Status.Canary.Phase).To(Equal(canaryRunning))
+		Expect(tasks()).To(HaveLen(2))
+
+		finish("review-0", "Completed")
+		Expect(reconcile().Status.Canary.Succeeded).To(Equal(int32(1)))
+		Expect(tasks()).To(HaveLen(2))
+
+		finish("review-1", "Completed")
+		current = reconcile()
+		Expect(current.Status.Canary.Phase).To(Equal(canaryPassed))
+		Expect(current.Status.Canary.SuccessRate).To(Equal(int32(100)))
+		Expect(current.Status.Phase).To(Equal(batchRunning))
+		Expect(tasks()).To(HaveLen(5))
+		Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("CanaryPassed")))
+	})
+
+	It("fails the batch once the canary cannot reach its threshold", func() {
+		percentage, threshold := int32(60), int32(50)
+		batch.Spec.Canary = &swarmv1alpha1.BatchCanary{Percentage: &percentage, SuccessThreshold: &threshold}
+		batch.Spec.Parallelism = 5
+		build()
+		reconcile()
+		Expect(tasks()).To(HaveLen(3))
+
+		finish("review-0", "Failed")
+		Expect(reconcile().Status.Canary.Phase).To(Equal(canaryRunning))
+		finish("review-1", "Failed")
+		current := reconcile()
+		Expect(current.Status.Phase).To(Equal(batchFailed))
+		Expect(current.Status.Canary.Phase).To(Equal(canaryFailed))
+		Expect(current.Status.Canary.FailedTasks).To(Equal([]string{"review-0", "review-1"}))
+		Expect(current.Status.Message).To(ContainSubstring("0 of 3 canary tasks succeeded"))
+
+		// The last canary still runs, but nothing else starts
+		finish("review-2", "Completed")
+		current = reconcile()
+		Expect(current.Status.Phase).To(Equal(batchFailed))
+		Expect(tasks()).To(HaveLen(3))
+	})
+
+	It("halts the canary at the first failure", func() {
+		size := int32(3)
+		batch.Spec.Canary = &swarmv1alpha1.BatchCanary{Size: &size, HaltOnFailure: true}
+		batch.Spec.Parallelism = 5
+		build()
+		reconcile()
+		finish("review-1", "Failed")
+
+		current := reconcile()
+		Expect(current.Status.Phase).To(Equal(batchFailed))
+		Expect(current.Status.Message).To(Equal("Canary task review-1 failed"))
+		Expect(current.Status.Active).To(BeZero())
+		remaining := tasks()
+		Expect(remaining).To(HaveLen(1))
+		Expect(remaining[0].Name).To(Equal("review-1"))
+	})
+
+	It("sizes the canary from a percentage of the items", func() {
+		Expect(batchCanarySize(&swarmv1alpha1.BatchCanary{}, 100)).To(Equal(5))
+		Expect(batchCanarySize(&swarmv1alpha1.BatchCanary{}, 3)).To(Equal(1))
+		percentage, size := int32(10), int32(8)
+		Expect(batchCanarySize(&swarmv1alpha1.BatchCanary{Percentage: &percentage}, 25)).To(Equal(3))
+		Expect(batchCanarySize(&swarmv1alpha1.BatchCanary{Size: &size}, 5)).To(Equal(5))
+	})
+})
@@ -0,0 +1,294 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/audit"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+const (
+	// swarmTenantFinalizer releases the tenant's namespaces before the
+	// tenant goes away
+	swarmTenantFinalizer = "swarm.claudeflow.io/tenant-finalizer"
+
+	ReasonTenantReady             = "TenantReady"
+	ReasonTenantNamespaceConflict = "NamespaceConflict"
+)
+
+// SwarmTenantReconciler reconciles a SwarmTenant object
+type SwarmTenantReconciler struct {
+	client.Client
+	Scheme          *runtime.Scheme
+	Recorder        record.EventRecorder
+	MetricsRecorder *metrics.MetricsRecorder
+}
+
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtenants,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtenants/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtenants/finalizers,verbs=update
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=resourcequotas;limitranges,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *SwarmTenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = audit.WithTrigger(ctx, "SwarmTenant", req.NamespacedName)
+	log := log.FromContext(ctx)
+
+	tenant := &swarmv1alpha1.SwarmTenant{}
+	if err := r.Get(ctx, req.NamespacedName, tenant); err != nil {
+		if errors.IsNotFound(err) {
+			if r.MetricsRecorder != nil {
+				r.MetricsRecorder.DeleteTenant(req.Name)
+			}
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if tenant.GetDeletionTimestamp() != nil {
+		if !controllerutil.ContainsFinalizer(tenant, swarmTenantFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.releaseNamespaces(ctx, tenant, nil); err != nil {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(tenant, swarmTenantFinalizer)
+		return ctrl.Result{}, r.Update(ctx, tenant)
+	}
+
+	if !controllerutil.ContainsFinalizer(tenant, swarmTenantFinalizer) {
+		controllerutil.AddFinalizer(tenant, swarmTenantFinalizer)
+		if err := r.Update(ctx, tenant); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Namespaces dropped from the spec stop belonging to the tenant
+	if err := r.releaseNamespaces(ctx, tenant, tenant.Spec.Namespaces); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var provisioned, conflicts []string
+	for _, ns := range tenant.Spec.Namespaces {
+		owner, err := r.ensureTenantNamespace(ctx, tenant, ns)
+		if err != nil {
+			log.Error(err, "Failed to provision tenant namespace", "namespace", ns)
+			return ctrl.Result{}, fmt.Errorf("namespace %s: %w", ns, err)
+		}
+		if owner != tenant.Name {
+			conflicts = append(conflicts, fmt.Sprintf("%s (tenant %s)", ns, owner))
+			continue
+		}
+		if err := r.applyTenantPolicies(ctx, tenant, ns); err != nil {
+			return ctrl.Result{}, fmt.Errorf("namespace %s: %w", ns, err)
+		}
+		provisioned = append(provisioned, ns)
+	}
+	sort.Strings(provisioned)
+
+	clusters := &swarmv1alpha1.SwarmClusterList{}
+	if err := r.List(ctx, clusters, client.MatchingLabels{tenantLabel: tenant.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	running, err := countTenantJobs(ctx, r, tenant.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.MetricsRecorder != nil {
+		r.MetricsRecorder.RecordTenantUsage(tenant.Name, int32(len(clusters.Items)), running)
+	}
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonTenantReady,
+		Message:            fmt.Sprintf("%d namespaces provisioned", len(provisioned)),
+		ObservedGeneration: tenant.Generation,
+	}
+	if len(conflicts) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonTenantNamespaceConflict
+		condition.Message = "Namespaces belong to other tenants: " + strings.Join(conflicts, ", ")
+	}
+
+	before := tenant.Status.DeepCopy()
+	tenant.Status.ObservedGeneration = tenant.Generation
+	tenant.Status.Clusters = int32(len(clusters.Items))
+	tenant.Status.RunningTasks = running
+	tenant.Status.Namespaces = provisioned
+	if meta.SetStatusCondition(&tenant.Status.Conditions, condition) {
+		eventType := corev1.EventTypeNormal
+		if condition.Status != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(tenant, eventType, condition.Reason, condition.Message)
+	}
+	if !equality.Semantic.DeepEqual(before, &tenant.Status) {
+		if err := r.Status().Update(ctx, tenant); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Running tasks are counted from Jobs, which are not watched here
+	return ctrl.Result{RequeueAfter: tenantRequeueInterval}, nil
+}
+
+// ensureTenantNamespace creates a missing namespace for the tenant or
+// labels an existing one. It returns the tenant owning the namespace,
+// which differs from the tenant when another one claimed it first.
+func (r *SwarmTenantReconciler) ensureTenantNamespace(ctx context.Context, tenant *swarmv1alpha1.SwarmTenant, name string) (string, error) {
+	namespace := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: name}, namespace)
+	if errors.IsNotFound(err) {
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"app.kubernetes.io/managed-by": "swarm-operator",
+					"app.kubernetes.io/part-of":    "claude-flow",
+					tenantLabel:                    tenant.Name,
+				},
+			},
+		}
+		if err := r.Create(ctx, namespace); err != nil {
+			return "", err
+		}
+		r.Recorder.Eventf(tenant, corev1.EventTypeNormal, "NamespaceCreated", "Created namespace %s", name)
+		return tenant.Name, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if owner := namespace.Labels[tenantLabel]; owner != "" {
+		return owner, nil
+	}
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	namespace.Labels[tenantLabel] = tenant.Name
+	if err := r.Update(ctx, namespace); err != nil {
+		return "", err
+	}
+	return tenant.Name, nil
+}
+
+// applyTenantPolicies creates, updates or removes the tenant's
+// ResourceQuota and LimitRange in one of its namespaces
+func (r *SwarmTenantReconciler) applyTenantPolicies(ctx context.Context, tenant *swarmv1alpha1.SwarmTenant, ns string) error {
+	labels := map[string]string{tenantLabel: tenant.Name}
+
+	quota := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: tenant.Name + "-tenant-quota", Namespace: ns}}
+	if tenant.Spec.Quota != nil && tenant.Spec.Quota.ResourceQuota != nil {
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, quota, func() error {
+			quota.Labels = labels
+			quota.Spec = *tenant.Spec.Quota.ResourceQuota.DeepCopy()
+			return controllerutil.SetControllerReference(tenant, quota, r.Scheme)
+		}); err != nil {
+			return err
+		}
+	} else if err := r.Delete(ctx, quota); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	limitRange := &corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: tenant.Name + "-tenant-limits", Namespace: ns}}
+	if tenant.Spec.LimitRange != nil {
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, limitRange, func() error {
+			limitRange.Labels = labels
+			limitRange.Spec = *tenant.Spec.LimitRange.DeepCopy()
+			return controllerutil.SetControllerReference(tenant, limitRange, r.Scheme)
+		}); err != nil {
+			return err
+		}
+	} else if err := r.Delete(ctx, limitRange); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// releaseNamespaces removes the tenant label and policies from the
+// tenant's namespaces that are not kept. Namespaces are never deleted.
+func (r *SwarmTenantReconciler) releaseNamespaces(ctx context.Context, tenant *swarmv1alpha1.SwarmTenant, keep []string) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabels{tenantLabel: tenant.Name}); err != nil {
+		return err
+	}
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		if containsString(keep, namespace.Name) {
+			continue
+		}
+		for _, obj := range []client.Object{
+			&corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: tenant.Name + "-tenant-quota", Namespace: namespace.Name}},
+			&corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: tenant.Name + "-tenant-limits", Namespace: namespace.Name}},
+		} {
+			if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		delete(namespace.Labels, tenantLabel)
+		if err := r.Update(ctx, namespace); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(tenant, corev1.EventTypeNormal, "NamespaceReleased", "Released namespace %s", namespace.Name)
+	}
+	return nil
+}
+
+// mapClusterToTenant enqueues the tenant of a SwarmCluster so its cluster
+// count stays current
+func (r *SwarmTenantReconciler) mapClusterToTenant(ctx context.Context, obj client.Object) []reconcile.Request {
+	tenant := obj.GetLabels()[tenantLabel]
+	if tenant == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: tenant}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SwarmTenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&swarmv1alpha1.SwarmTenant{}).
+		Owns(&corev1.ResourceQuota{}).
+		Owns(&corev1.LimitRange{}).
+		Watches(&swarmv1alpha1.SwarmCluster{}, handler.EnqueueRequestsFromMapFunc(r.mapClusterToTenant)).
+		Complete(r.MetricsRecorder.InstrumentReconciler("swarmtenant", r))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("SwarmTenant Controller", func() {
+	var (
+		ctx        context.Context
+		tenant     *swarmv1alpha1.SwarmTenant
+		reconciler *SwarmTenantReconciler
+	)
+
+	reconcilerFor := func(objects ...client.Object) *SwarmTenantReconciler {
+		k8sClient := newTestClient(objects...)
+		return &SwarmTenantReconciler{Client: k8sClient, Scheme: testScheme, Recorder: record.NewFakeRecorder(10)}
+	}
+
+	reconcileTenant := func() *swarmv1alpha1.SwarmTenant {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: tenant.Name}})
+		Expect(err).NotTo(HaveOccurred())
+		stored := &swarmv1alpha1.SwarmTenant{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: tenant.Name}, stored)).To(Succeed())
+		return stored
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		tenant = &swarmv1alpha1.SwarmTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "acme"},
+			Spec: swarmv1alpha1.SwarmTenantSpec{
+				Namespaces: []string{"acme-dev", "acme-prod"},
+				Quota: &swarmv1alpha1.TenantQuota{
+					ResourceQuota: &corev1.ResourceQuotaSpec{
+						Hard: corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("8")},
+					},
+				},
+			},
+		}
+	})
+
+	It("provisions the tenant's namespaces with its quota", func() {
+		cluster := &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{
+			Name: "swarm", Namespace: "acme-dev", Labels: map[string]string{tenantLabel: "acme"},
+		}}
+		reconciler = reconcilerFor(tenant, cluster, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "acme-prod"}})
+
+		stored := reconcileTenant()
+		Expect(stored.Finalizers).To(ContainElement(swarmTenantFinalizer))
+		Expect(stored.Status.Namespaces).To(Equal([]string{"acme-dev", "acme-prod"}))
+		Expect(stored.Status.Clusters).To(Equal(int32(1)))
+		Expect(meta.IsStatusConditionTrue(stored.Status.Conditions, ConditionTypeReady)).To(BeTrue())
+
+		for _, name := range tenant.Spec.Namespaces {
+			ns := &corev1.Namespace{}
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: name}, ns)).To(Succeed())
+			Expect(ns.Labels).To(HaveKeyWithValue(tenantLabel, "acme"))
+
+			quota := &corev1.ResourceQuota{}
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: "acme-tenant-quota", Namespace: name}, quota)).To(Succeed())
+			Expect(quota.Spec.Hard).To(HaveKey(corev1.ResourceLimitsCPU))
+		}
+	})
+
+	It("reports namespaces claimed by another tenant", func() {
+		reconciler = reconcilerFor(tenant, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "acme-prod", Labels: map[string]string{tenantLabel: "globex"},
+		}})
+
+		stored := reconcileTenant()
+		Expect(stored.Status.Namespaces).To(Equal([]string{"acme-dev"}))
+		condition := meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeReady)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonTenantNamespaceConflict))
+		Expect(condition.Message).To(ContainSubstring("acme-prod (tenant globex)"))
+
+		ns := &corev1.Namespace{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "acme-prod"}, ns)).To(Succeed())
+		Expect(ns.Labels).To(HaveKeyWithValue(tenantLabel, "globex"))
+	})
+
+	It("releases namespaces dropped from the spec", func() {
+		reconciler = reconcilerFor(tenant)
+		reconcileTenant()
+
+		stored := &swarmv1alpha1.SwarmTenant{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "acme"}, stored)).To(Succeed())
+		stored.Spec.Namespaces = []string{"acme-dev"}
+		Expect(reconciler.Update(ctx, stored)).To(Succeed())
+		reconcileTenant()
+
+		ns := &corev1.Namespace{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "acme-prod"}, ns)).To(Succeed())
+		Expect(ns.Labels).NotTo(HaveKey(tenantLabel))
+		err := reconciler.Get(ctx, types.NamespacedName{Name: "acme-tenant-quota", Namespace: "acme-prod"}, &corev1.ResourceQuota{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,207 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// tenantLabel ties SwarmClusters, their tasks and task Jobs to a
+	// SwarmTenant, and marks the namespaces provisioned for one
+	tenantLabel = "swarm.claudeflow.io/tenant"
+
+	// ConditionTypeTenantAdmitted reports whether the tenant's policies
+	// and quotas admit the cluster
+	ConditionTypeTenantAdmitted = "TenantAdmitted"
+
+	ReasonTenantAdmitted        = "Admitted"
+	ReasonTenantNotFound        = "TenantNotFound"
+	ReasonTenantPolicyViolation = "TenantPolicyViolation"
+	ReasonTenantQuotaExceeded   = "TenantQuotaExceeded"
+
+	tenantRequeueInterval = 30 * time.Second
+)
+
+// taskTenant returns the tenant of the task: its own label, otherwise the
+// label of its cluster
+func taskTenant(task *swarmv1alpha1.SwarmTask, cluster *swarmv1alpha1.SwarmCluster) string {
+	if tenant := task.Labels[tenantLabel]; tenant != "" {
+		return tenant
+	}
+	return cluster.Labels[tenantLabel]
+}
+
+// getTenant fetches the named tenant, or nil for resources without one
+func getTenant(ctx context.Context, c client.Reader, name string) (*swarmv1alpha1.SwarmTenant, error) {
+	if name == "" {
+		return nil, nil
+	}
+	tenant := &swarmv1alpha1.SwarmTenant{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// namespaceTenant returns the tenant a namespace was provisioned for
+func namespaceTenant(ctx context.Context, c client.Reader, namespace string) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return ns.Labels[tenantLabel], nil
+}
+
+// tenantNamespaceViolation checks that a tenant's resources stay in the
+// tenant's namespaces and that resources without a tenant stay out of
+// tenant namespaces. It returns the first violation, or "".
+func tenantNamespaceViolation(ctx context.Context, c client.Reader, tenant *swarmv1alpha1.SwarmTenant, namespaces ...string) (string, error) {
+	for _, ns := range namespaces {
+		owner, err := namespaceTenant(ctx, c, ns)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case tenant == nil && owner != "":
+			return fmt.Sprintf("namespace %s belongs to tenant %s, label the resource with %s=%s", ns, owner, tenantLabel, owner), nil
+		case tenant == nil:
+			continue
+		case !containsString(tenant.Spec.Namespaces, ns):
+			return fmt.Sprintf("namespace %s is not a namespace of tenant %s", ns, tenant.Name), nil
+		case owner != "" && owner != tenant.Name:
+			return fmt.Sprintf("namespace %s belongs to tenant %s", ns, owner), nil
+		}
+	}
+	return "", nil
+}
+
+// registryAllowed reports whether the image comes from one of the tenant's
+// allowed registries
+func registryAllowed(tenant *swarmv1alpha1.SwarmTenant, image string) bool {
+	if tenant == nil || len(tenant.Spec.AllowedRegistries) == 0 {
+		return true
+	}
+	for _, prefix := range tenant.Spec.AllowedRegistries {
+		if strings.HasPrefix(image, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// tenantInjectsCredential reports whether the well-known credential secret
+// is injected into the tenant's tasks
+func tenantInjectsCredential(tenant *swarmv1alpha1.SwarmTenant, secret string) bool {
+	if tenant == nil || tenant.Spec.Credentials == nil {
+		return true
+	}
+	return containsString(tenant.Spec.Credentials.InjectedSecrets, secret)
+}
+
+// tenantPodSpecViolations lists the images from registries the tenant does
+// not allow and the secrets outside its credential scope in a pod spec.
+// Images and secrets the operator supplies itself are exempt.
+func tenantPodSpecViolations(tenant *swarmv1alpha1.SwarmTenant, podSpec *corev1.PodSpec, operatorImages, operatorSecrets map[string]bool) []string {
+	if tenant == nil {
+		return nil
+	}
+
+	images := map[string]bool{}
+	secrets := map[string]bool{}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for _, container := range containers {
+			images[container.Image] = true
+			for _, env := range container.Env {
+				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+					secrets[env.ValueFrom.SecretKeyRef.Name] = true
+				}
+			}
+			for _, from := range container.EnvFrom {
+				if from.SecretRef != nil {
+					secrets[from.SecretRef.Name] = true
+				}
+			}
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		if volume.Secret != nil {
+			secrets[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					secrets[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+	for _, ref := range podSpec.ImagePullSecrets {
+		secrets[ref.Name] = true
+	}
+
+	var violations []string
+	for image := range images {
+		if !operatorImages[image] && !registryAllowed(tenant, image) {
+			violations = append(violations, fmt.Sprintf("image %s is not from a registry allowed for tenant %s", image, tenant.Name))
+		}
+	}
+	if tenant.Spec.Credentials != nil {
+		for secret := range secrets {
+			if !operatorSecrets[secret] && !containsString(tenant.Spec.Credentials.AllowedSecrets, secret) {
+				violations = append(violations, fmt.Sprintf("secret %s is outside the credentials of tenant %s", secret, tenant.Name))
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations
+}
+
+// tenantClusterRank returns how many of the tenant's clusters were created
+// before the given one. Clusters at or beyond the quota are not admitted.
+func tenantClusterRank(ctx context.Context, c client.Reader, tenant *swarmv1alpha1.SwarmTenant, cluster *swarmv1alpha1.SwarmCluster) (int, error) {
+	clusters := &swarmv1alpha1.SwarmClusterList{}
+	if err := c.List(ctx, clusters, client.MatchingLabels{tenantLabel: tenant.Name}); err != nil {
+		return 0, err
+	}
+	rank := 0
+	for i := range clusters.Items {
+		other := &clusters.Items[i]
+		if other.UID == cluster.UID {
+			continue
+		}
+		if other.CreationTimestamp.Before(&cluster.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&cluster.CreationTimestamp) && other.Namespace+"/"+other.Name < cluster.Namespace+"/"+cluster.Name) {
+			rank++
+		}
+	}
+	return rank, nil
+}
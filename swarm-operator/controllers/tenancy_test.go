@@ -0,0 +1,238 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Tenancy", func() {
+	var (
+		ctx      context.Context
+		tenant   *swarmv1alpha1.SwarmTenant
+		recorder *record.FakeRecorder
+	)
+
+	buildClient := func(objects ...client.Object) (client.Client, *runtime.Scheme) {
+		k8sClient := newTestClient(objects...)
+		return k8sClient, testScheme
+	}
+
+	tenantNamespace := func(name, owner string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{tenantLabel: owner}}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		tenant = &swarmv1alpha1.SwarmTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "acme"},
+			Spec: swarmv1alpha1.SwarmTenantSpec{
+				Namespaces:        []string{"acme-dev"},
+				AllowedRegistries: []string{"ghcr.io/acme/"},
+				Credentials:       &swarmv1alpha1.TenantCredentials{AllowedSecrets: []string{"acme-token"}},
+			},
+		}
+	})
+
+	Context("pod spec policies", func() {
+		It("reports images and secrets outside the tenant's scope", func() {
+			podSpec := &corev1.PodSpec{
+				InitContainers: []corev1.Container{{Image: defaultCheckoutImage}},
+				Containers: []corev1.Container{{
+					Image: "docker.io/evil/miner:latest",
+					Env: []corev1.EnvVar{{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "acme-token"}},
+					}}},
+					EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "prod-db"}}}},
+				}},
+				Volumes: []corev1.Volume{{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "swarm-tls"}}}},
+			}
+
+			violations := tenantPodSpecViolations(tenant, podSpec,
+				map[string]bool{defaultCheckoutImage: true}, map[string]bool{"swarm-tls": true})
+			Expect(violations).To(Equal([]string{
+				"image docker.io/evil/miner:latest is not from a registry allowed for tenant acme",
+				"secret prod-db is outside the credentials of tenant acme",
+			}))
+			Expect(tenantPodSpecViolations(nil, podSpec, nil, nil)).To(BeEmpty())
+		})
+
+		It("injects only the credentials the tenant lists", func() {
+			Expect(tenantInjectsCredential(nil, "aws-credentials")).To(BeTrue())
+			Expect(tenantInjectsCredential(tenant, "aws-credentials")).To(BeFalse())
+			tenant.Spec.Credentials.InjectedSecrets = []string{"aws-credentials"}
+			Expect(tenantInjectsCredential(tenant, "aws-credentials")).To(BeTrue())
+		})
+	})
+
+	Context("namespaces", func() {
+		It("keeps tenants and untenanted resources in their own namespaces", func() {
+			k8sClient, _ := buildClient(tenantNamespace("acme-dev", "acme"), tenantNamespace("globex-dev", "globex"),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shared"}})
+
+			Expect(tenantNamespaceViolation(ctx, k8sClient, tenant, "acme-dev")).To(BeEmpty())
+			Expect(tenantNamespaceViolation(ctx, k8sClient, tenant, "shared")).To(ContainSubstring("not a namespace of tenant acme"))
+			Expect(tenantNamespaceViolation(ctx, k8sClient, nil, "shared")).To(BeEmpty())
+			Expect(tenantNamespaceViolation(ctx, k8sClient, nil, "globex-dev")).To(ContainSubstring("belongs to tenant globex"))
+
+			tenant.Spec.Namespaces = append(tenant.Spec.Namespaces, "globex-dev")
+			Expect(tenantNamespaceViolation(ctx, k8sClient, tenant, "globex-dev")).To(Equal("namespace globex-dev belongs to tenant globex"))
+		})
+	})
+
+	Context("task admission", func() {
+		var (
+			cluster *swarmv1alpha1.SwarmCluster
+			task    *swarmv1alpha1.SwarmTask
+		)
+
+		BeforeEach(func() {
+			cluster = &swarmv1alpha1.SwarmCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "acme-dev", Labels: map[string]string{tenantLabel: "acme"}},
+			}
+			task = &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "acme-dev"},
+				Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+			}
+		})
+
+		reconcilerFor := func(objects ...client.Object) *SwarmTaskReconciler {
+			k8sClient, testScheme := buildClient(objects...)
+			return &SwarmTaskReconciler{Client: k8sClient, Scheme: testScheme, Recorder: recorder}
+		}
+
+		It("admits a task of its tenant", func() {
+			r := reconcilerFor(tenant, cluster, task, tenantNamespace("acme-dev", "acme"))
+			held, err := r.admitTenantTask(ctx, task, cluster, "acme-dev")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeFalse())
+		})
+
+		It("fails a task running outside the tenant's namespaces", func() {
+			r := reconcilerFor(tenant, cluster, task, tenantNamespace("acme-dev", "acme"))
+			held, err := r.admitTenantTask(ctx, task, cluster, "kube-system")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeTrue())
+			Expect(task.Status.Phase).To(Equal("Failed"))
+			Expect(task.Status.Message).To(Equal("namespace kube-system is not a namespace of tenant acme"))
+			Expect(recorder.Events).To(Receive(ContainSubstring(ReasonTenantPolicyViolation)))
+		})
+
+		It("fails a task whose GitHub App key is outside the tenant's credentials", func() {
+			task.Spec.GitHubApp = &swarmv1alpha1.GitHubAppConfig{
+				PrivateKeyRef: swarmv1alpha1.SecretKeyRef{Name: "platform-app-key", Key: "key.pem"},
+			}
+			r := reconcilerFor(tenant, cluster, task, tenantNamespace("acme-dev", "acme"))
+			held, err := r.admitTenantTask(ctx, task, cluster, "acme-dev")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeTrue())
+			Expect(task.Status.Message).To(Equal("secret platform-app-key is outside the credentials of tenant acme"))
+		})
+
+		It("holds a task while its tenant is missing", func() {
+			r := reconcilerFor(cluster, task)
+			held, err := r.admitTenantTask(ctx, task, cluster, "acme-dev")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeTrue())
+			Expect(task.Status.Phase).To(Equal("Pending"))
+			Expect(task.Status.Message).To(Equal("Waiting for SwarmTenant acme"))
+		})
+
+		It("holds a task while the tenant runs its quota of Jobs", func() {
+			tenant.Spec.Quota = &swarmv1alpha1.TenantQuota{MaxConcurrentTasks: 1}
+			running := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+				Name: "other", Namespace: "acme-dev", Labels: map[string]string{tenantLabel: "acme"},
+			}}
+			done := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "acme-dev", Labels: map[string]string{tenantLabel: "acme"}},
+				Status:     batchv1.JobStatus{Succeeded: 1},
+			}
+			r := reconcilerFor(tenant, cluster, task, tenantNamespace("acme-dev", "acme"), running, done)
+
+			held, err := r.admitTenantTask(ctx, task, cluster, "acme-dev")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeTrue())
+			Expect(task.Status.Message).To(ContainSubstring("Held by the quota of tenant acme"))
+
+			Expect(r.Delete(ctx, running)).To(Succeed())
+			held, err = r.admitTenantTask(ctx, task, cluster, "acme-dev")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(held).To(BeFalse())
+		})
+	})
+
+	Context("cluster admission", func() {
+		It("admits the oldest clusters up to the tenant's quota", func() {
+			tenant.Spec.Quota = &swarmv1alpha1.TenantQuota{MaxClusters: 1}
+			older := &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{
+				Name: "first", Namespace: "acme-dev", UID: "1", Labels: map[string]string{tenantLabel: "acme"},
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+			}}
+			younger := &swarmv1alpha1.SwarmCluster{ObjectMeta: metav1.ObjectMeta{
+				Name: "second", Namespace: "acme-dev", UID: "2", Labels: map[string]string{tenantLabel: "acme"},
+				CreationTimestamp: metav1.NewTime(time.Now()),
+			}}
+			k8sClient, testScheme := buildClient(tenant, older, younger, tenantNamespace("acme-dev", "acme"))
+			r := &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: recorder}
+
+			admitted, err := r.admitTenantCluster(ctx, older)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(admitted).To(BeTrue())
+
+			admitted, err = r.admitTenantCluster(ctx, younger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(admitted).To(BeFalse())
+			condition := meta.FindStatusCondition(younger.Status.Conditions, ConditionTypeTenantAdmitted)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(ReasonTenantQuotaExceeded))
+
+			stored := &swarmv1alpha1.SwarmCluster{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "second", Namespace: "acme-dev"}, stored)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(stored.Status.Conditions, ConditionTypeTenantAdmitted)).To(BeTrue())
+		})
+
+		It("rejects agent images from registries the tenant does not allow", func() {
+			cluster := &swarmv1alpha1.SwarmCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "acme-dev", Labels: map[string]string{tenantLabel: "acme"}},
+			}
+			cluster.Spec.AgentTemplate.Image = "docker.io/library/agent:latest"
+			k8sClient, testScheme := buildClient(tenant, cluster, tenantNamespace("acme-dev", "acme"))
+			r := &SwarmClusterReconciler{Client: k8sClient, Scheme: testScheme, Recorder: recorder}
+
+			admitted, err := r.admitTenantCluster(ctx, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(admitted).To(BeFalse())
+			Expect(meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeTenantAdmitted).Reason).
+				To(Equal(ReasonTenantPolicyViolation))
+		})
+	})
+})
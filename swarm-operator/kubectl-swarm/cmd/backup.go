@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Swarm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/claude-flow/kubectl-swarm/pkg/client"
+	"github.com/claude-flow/swarm-operator/pkg/backup"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	exportExample = templates.Examples(`
+		# Export a swarm with its agents, memory and in-flight tasks
+		kubectl swarm export my-swarm -f my-swarm.tar.gz
+
+		# Export the definition only, without the memory store databases
+		kubectl swarm export my-swarm -f my-swarm.tar.gz --skip-memory`)
+
+	importExample = templates.Examples(`
+		# Restore a swarm into the namespace it was exported from
+		kubectl swarm import -f my-swarm.tar.gz
+
+		# Clone a swarm into another namespace
+		kubectl swarm import -f my-swarm.tar.gz -n staging
+
+		# Restore into another cluster, moving two namespaces
+		kubectl swarm import -f my-swarm.tar.gz --context dr --namespace-map prod=dr,prod-hivemind=dr-hivemind`)
+)
+
+// Export command
+type ExportOptions struct {
+	genericclioptions.IOStreams
+
+	SwarmName  string
+	File       string
+	SkipMemory bool
+
+	configFlags *genericclioptions.ConfigFlags
+}
+
+func NewExportOptions(streams genericclioptions.IOStreams) *ExportOptions {
+	return &ExportOptions{
+		IOStreams:   streams,
+		configFlags: genericclioptions.NewConfigFlags(true),
+	}
+}
+
+func NewCmdExport(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewExportOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "export SWARM-NAME -f FILE",
+		Short: "Export a swarm into a portable backup archive",
+		Long: templates.LongDesc(`Export a swarm for disaster recovery or environment cloning. The
+			archive holds the SwarmCluster, its agents, memory stores, memory entries,
+			in-flight tasks and a snapshot of every memory store database. Secrets are
+			only listed by name and must be recreated before importing.`),
+		Example: exportExample,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.SwarmName = args[0]
+			if o.File == "" {
+				o.File = o.SwarmName + "-backup.tar.gz"
+			}
+			if err := o.Run(cmd.Context()); err != nil {
+				fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.File, "file", "f", "", "Archive to write, defaults to SWARM-NAME-backup.tar.gz")
+	cmd.Flags().BoolVar(&o.SkipMemory, "skip-memory", false, "Do not snapshot the memory store databases")
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *ExportOptions) Run(ctx context.Context) error {
+	swarmClient, err := client.NewTypedClient(o.configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	var snapshots backup.Snapshotter
+	if !o.SkipMemory {
+		snapshots = swarmClient.MemorySnapshots()
+	}
+	archive, err := backup.Export(ctx, swarmClient, swarmClient.Namespace(), o.SwarmName, snapshots)
+	if err != nil {
+		return fmt.Errorf("failed to export swarm %s: %w", o.SwarmName, err)
+	}
+
+	f, err := os.Create(o.File)
+	if err != nil {
+		return err
+	}
+	if err := archive.Write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", o.File, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.Out, "swarmcluster.swarm.claudeflow.io/%s exported to %s (%d objects, %d memory snapshots)\n",
+		o.SwarmName, o.File, len(archive.Manifest.Objects), len(archive.Manifest.Snapshots))
+	if len(archive.Manifest.SecretRefs) > 0 {
+		fmt.Fprintln(o.Out, "Referenced secrets, recreate them in the target before importing:")
+		for _, ref := range archive.Manifest.SecretRefs {
+			fmt.Fprintf(o.Out, "  %s/%s\n", ref.Namespace, ref.Name)
+		}
+	}
+	return nil
+}
+
+// Import command
+type ImportOptions struct {
+	genericclioptions.IOStreams
+
+	File         string
+	NamespaceMap map[string]string
+	SkipMemory   bool
+	Timeout      time.Duration
+
+	// targetNamespace is set when --namespace was given explicitly
+	targetNamespace string
+	configFlags     *genericclioptions.ConfigFlags
+}
+
+func NewImportOptions(streams genericclioptions.IOStreams) *ImportOptions {
+	return &ImportOptions{
+		IOStreams:   streams,
+		Timeout:     5 * time.Minute,
+		configFlags: genericclioptions.NewConfigFlags(true),
+	}
+}
+
+func NewCmdImport(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewImportOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "import -f FILE",
+		Short: "Restore a swarm from a backup archive",
+		Long: templates.LongDesc(`Restore a swarm exported with "kubectl swarm export". Existing objects
+			are left untouched. Memory store databases are restored once the stores
+			are ready. With --namespace the swarm is restored into that namespace;
+			--namespace-map moves any other namespace the swarm refers to.`),
+		Example: importExample,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.Complete(cmd); err != nil {
+				fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
+				return
+			}
+			if err := o.Run(cmd.Context()); err != nil {
+				fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.File, "file", "f", "", "Archive written by kubectl swarm export")
+	cmd.MarkFlagRequired("file")
+	cmd.Flags().StringToStringVar(&o.NamespaceMap, "namespace-map", nil, "Namespaces to move, as OLD=NEW pairs")
+	cmd.Flags().BoolVar(&o.SkipMemory, "skip-memory", false, "Do not restore the memory store databases")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "How long to wait for each memory store to become ready")
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *ImportOptions) Complete(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("namespace") {
+		return nil
+	}
+	var err error
+	o.targetNamespace, _, err = o.configFlags.ToRawKubeConfigLoader().Namespace()
+	return err
+}
+
+func (o *ImportOptions) Run(ctx context.Context) error {
+	swarmClient, err := client.NewTypedClient(o.configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	f, err := os.Open(o.File)
+	if err != nil {
+		return err
+	}
+	archive, err := backup.Read(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	mapping := map[string]string{}
+	for from, to := range o.NamespaceMap {
+		mapping[from] = to
+	}
+	if _, mapped := mapping[archive.Manifest.Namespace]; !mapped && o.targetNamespace != "" {
+		mapping[archive.Manifest.Namespace] = o.targetNamespace
+	}
+	archive.Remap(mapping)
+
+	opts := backup.ImportOptions{Timeout: o.Timeout}
+	if !o.SkipMemory {
+		opts.Snapshots = swarmClient.MemorySnapshots()
+	}
+	result, err := backup.Import(ctx, swarmClient, archive, opts)
+	if result != nil {
+		for _, ref := range result.Created {
+			fmt.Fprintf(o.Out, "%s %s/%s created\n", ref.Kind, ref.Namespace, ref.Name)
+		}
+		for _, ref := range result.Existing {
+			fmt.Fprintf(o.Out, "%s %s/%s unchanged, it already exists\n", ref.Kind, ref.Namespace, ref.Name)
+		}
+		for _, ref := range result.RestoredSnapshots {
+			fmt.Fprintf(o.Out, "Memory store %s/%s restored\n", ref.Namespace, ref.Store)
+		}
+		for _, ref := range result.MissingSecrets {
+			fmt.Fprintf(o.ErrOut, "Warning: secret %s/%s is missing, create it for the swarm to work\n", ref.Namespace, ref.Name)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", o.File, err)
+	}
+	return nil
+}
@@ -21,10 +21,9 @@ import (
 	"fmt"
 
 	"github.com/claude-flow/kubectl-swarm/pkg/client"
-	"github.com/claude-flow/kubectl-swarm/pkg/printer"
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/util/templates"
 )
@@ -37,6 +36,9 @@ var (
 		# Create a swarm with specific topology
 		kubectl swarm create my-swarm --topology hierarchical
 
+		# Create a fixed-size mesh swarm with 5 agents
+		kubectl swarm create my-swarm --topology mesh --size 5
+
 		# Create a swarm with custom agent limits
 		kubectl swarm create my-swarm --max-agents 10 --min-agents 3
 
@@ -55,6 +57,7 @@ type CreateOptions struct {
 	Topology    string
 	MaxAgents   int32
 	MinAgents   int32
+	Size        int32
 	Strategy    string
 	Interactive bool
 
@@ -103,6 +106,7 @@ func NewCmdCreate(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVar(&o.Topology, "topology", o.Topology, "Swarm topology (mesh, hierarchical, ring, star)")
 	cmd.Flags().Int32Var(&o.MaxAgents, "max-agents", o.MaxAgents, "Maximum number of agents")
 	cmd.Flags().Int32Var(&o.MinAgents, "min-agents", o.MinAgents, "Minimum number of agents")
+	cmd.Flags().Int32Var(&o.Size, "size", 0, "Fixed number of agents (sets both --min-agents and --max-agents)")
 	cmd.Flags().StringVar(&o.Strategy, "strategy", o.Strategy, "Distribution strategy (balanced, specialized, adaptive)")
 	cmd.Flags().BoolVarP(&o.Interactive, "interactive", "i", false, "Use interactive mode with prompts")
 
@@ -118,6 +122,11 @@ func (o *CreateOptions) Complete(cmd *cobra.Command) error {
 		return err
 	}
 
+	if o.Size > 0 {
+		o.MinAgents = o.Size
+		o.MaxAgents = o.Size
+	}
+
 	if o.Interactive && o.Name == "" {
 		// Interactive mode - prompt for values
 		fmt.Fprint(o.Out, "Swarm name: ")
@@ -168,44 +177,36 @@ func (o *CreateOptions) Validate() error {
 
 func (o *CreateOptions) Run(ctx context.Context) error {
 	// Create Kubernetes client
-	swarmClient, err := client.NewSwarmClient(o.configFlags)
+	swarmClient, err := client.NewTypedClient(o.configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
 	// Create swarm object
-	swarm := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "swarm.io/v1alpha1",
-			"kind":       "Swarm",
-			"metadata": map[string]interface{}{
-				"name":      o.Name,
-				"namespace": o.Namespace,
-			},
-			"spec": map[string]interface{}{
-				"topology": o.Topology,
-				"agents": map[string]interface{}{
-					"min": o.MinAgents,
-					"max": o.MaxAgents,
-				},
-				"strategy": o.Strategy,
-			},
+	swarm := &swarmv1alpha1.SwarmCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      o.Name,
+			Namespace: o.Namespace,
+		},
+		Spec: swarmv1alpha1.SwarmClusterSpec{
+			Topology:  swarmv1alpha1.SwarmTopology(o.Topology),
+			MinAgents: o.MinAgents,
+			MaxAgents: o.MaxAgents,
+			Strategy:  o.Strategy,
 		},
 	}
 
 	// Create the swarm
-	created, err := swarmClient.Create(ctx, swarm, metav1.CreateOptions{})
-	if err != nil {
+	if err := swarmClient.Create(ctx, swarm); err != nil {
 		return fmt.Errorf("failed to create swarm: %w", err)
 	}
 
-	// Print result
-	p := printer.NewTablePrinter(o.Out)
-	fmt.Fprintf(o.Out, "swarm.swarm.io/%s created\n", created.GetName())
+	fmt.Fprintf(o.Out, "swarmcluster.swarm.claudeflow.io/%s created\n", swarm.Name)
 	
 	if o.Interactive {
 		fmt.Fprintf(o.Out, "\nSwarm Details:\n")
-		p.PrintSwarm(created)
+		fmt.Fprintf(o.Out, "  Topology: %s\n", swarm.Spec.Topology)
+		fmt.Fprintf(o.Out, "  Agents:   %d-%d\n", swarm.Spec.MinAgents, swarm.Spec.MaxAgents)
 		fmt.Fprintf(o.Out, "\nNext steps:\n")
 		fmt.Fprintf(o.Out, "  - Check status: kubectl swarm status %s\n", o.Name)
 		fmt.Fprintf(o.Out, "  - Submit task: kubectl swarm task submit %s --task \"Your task here\"\n", o.Name)
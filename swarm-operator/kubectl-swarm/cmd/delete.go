@@ -184,7 +184,7 @@ func (o *DeleteOptions) Run(ctx context.Context) error {
 		}
 
 		successCount++
-		fmt.Fprintf(o.Out, "swarm.swarm.io/%s deleted\n", name)
+		fmt.Fprintf(o.Out, "swarmcluster.swarm.claudeflow.io/%s deleted\n", name)
 	}
 
 	if successCount == len(swarmsToDelete) {
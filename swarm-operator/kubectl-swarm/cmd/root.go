@@ -40,7 +40,11 @@ var (
 		kubectl swarm logs my-swarm --follow
 
 		# Debug a swarm
-		kubectl swarm debug my-swarm --verbose`)
+		kubectl swarm debug my-swarm --verbose
+
+		# Back up a swarm and restore it into another namespace
+		kubectl swarm export my-swarm -f my-swarm.tar.gz
+		kubectl swarm import -f my-swarm.tar.gz -n staging`)
 )
 
 // NewCmdSwarm provides a cobra command for swarm operations
@@ -67,6 +71,8 @@ func NewCmdSwarm(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(NewCmdLogs(streams))
 	cmd.AddCommand(NewCmdDebug(streams))
 	cmd.AddCommand(NewCmdDelete(streams))
+	cmd.AddCommand(NewCmdExport(streams))
+	cmd.AddCommand(NewCmdImport(streams))
 	cmd.AddCommand(NewCmdCompletion())
 
 	return cmd
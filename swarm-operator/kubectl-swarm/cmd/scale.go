@@ -21,10 +21,10 @@ import (
 	"fmt"
 
 	"github.com/claude-flow/kubectl-swarm/pkg/client"
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"k8s.io/kubectl/pkg/util/templates"
 )
 
@@ -106,15 +106,15 @@ func (o *ScaleOptions) Validate() error {
 	if len(o.Names) == 0 {
 		return fmt.Errorf("at least one swarm name is required")
 	}
-	if o.Replicas < 0 {
-		return fmt.Errorf("replicas cannot be negative")
+	if o.Replicas < 1 {
+		return fmt.Errorf("replicas must be at least 1")
 	}
 	return nil
 }
 
 func (o *ScaleOptions) Run(ctx context.Context) error {
 	// Create Kubernetes client
-	swarmClient, err := client.NewSwarmClient(o.configFlags)
+	swarmClient, err := client.NewTypedClient(o.configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
@@ -125,16 +125,34 @@ func (o *ScaleOptions) Run(ctx context.Context) error {
 			fmt.Fprintf(o.ErrOut, "Error scaling %s: %v\n", name, err)
 			continue
 		}
-		fmt.Fprintf(o.Out, "swarm.swarm.io/%s scaled to %d agents\n", name, o.Replicas)
+		fmt.Fprintf(o.Out, "swarmcluster.swarm.claudeflow.io/%s scaled to %d agents\n", name, o.Replicas)
 	}
 
 	return nil
 }
 
-func (o *ScaleOptions) scaleSwarm(ctx context.Context, client *client.SwarmClient, name string) error {
-	// Create patch to update the agent count
-	patch := []byte(fmt.Sprintf(`{"spec":{"agents":{"current":%d,"autoAdjust":%t}}}`, o.Replicas, o.AutoAdjust))
+func (o *ScaleOptions) scaleSwarm(ctx context.Context, c *client.TypedClient, name string) error {
+	swarm, err := c.GetSwarmCluster(ctx, name)
+	if err != nil {
+		return err
+	}
 
-	_, err := client.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
-	return err
-}
\ No newline at end of file
+	patch := ctrlclient.MergeFrom(swarm.DeepCopy())
+
+	// Without auto-adjust the swarm is pinned to exactly the requested size;
+	// with it, replicas becomes the ceiling and the autoscaler works below it.
+	swarm.Spec.MaxAgents = o.Replicas
+	if o.AutoAdjust {
+		if swarm.Spec.AutoScaling == nil {
+			swarm.Spec.AutoScaling = &swarmv1alpha1.AutoScalingSpec{}
+		}
+		swarm.Spec.AutoScaling.Enabled = true
+		if swarm.Spec.MinAgents > o.Replicas {
+			swarm.Spec.MinAgents = o.Replicas
+		}
+	} else {
+		swarm.Spec.MinAgents = o.Replicas
+	}
+
+	return c.Patch(ctx, swarm, patch)
+}
@@ -17,19 +17,41 @@ limitations under the License.
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/claude-flow/kubectl-swarm/pkg/client"
 	"github.com/claude-flow/kubectl-swarm/pkg/printer"
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/archive"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/kubectl/pkg/util/templates"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 )
 
+// clusterLabel is the label the operator uses to associate tasks and jobs
+// with their SwarmCluster
+const clusterLabel = "swarm.claudeflow.io/cluster"
+
+// planAnnotation asks the operator to render the Job of a task instead of
+// running it
+const planAnnotation = "swarm.claudeflow.io/plan"
+
 var (
 	taskExample = templates.Examples(`
 		# Submit a task to a swarm
@@ -41,6 +63,12 @@ var (
 		# Submit a task with dependencies
 		kubectl swarm task submit my-swarm --task "Deploy application" --depends-on task-123,task-456
 
+		# Submit a SwarmTask manifest and stream its status and job logs
+		kubectl swarm task submit -f task.yaml --follow
+
+		# Print the logs of a task's job
+		kubectl swarm task logs task-789
+
 		# List all tasks for a swarm
 		kubectl swarm task list my-swarm
 
@@ -48,7 +76,13 @@ var (
 		kubectl swarm task status task-789
 
 		# Cancel a running task
-		kubectl swarm task cancel task-789`)
+		kubectl swarm task cancel task-789
+
+		# Print the Job a task created with the swarm.claudeflow.io/plan annotation would run
+		kubectl swarm task plan task-789
+
+		# Browse the archived tasks of the last day that failed
+		kubectl swarm task history my-swarm --since 24h --phase Failed`)
 )
 
 type TaskOptions struct {
@@ -65,8 +99,6 @@ func NewTaskOptions(streams genericclioptions.IOStreams) *TaskOptions {
 }
 
 func NewCmdTask(streams genericclioptions.IOStreams) *cobra.Command {
-	o := NewTaskOptions(streams)
-
 	cmd := &cobra.Command{
 		Use:     "task",
 		Short:   "Submit and monitor tasks",
@@ -78,7 +110,10 @@ func NewCmdTask(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(NewCmdTaskSubmit(streams))
 	cmd.AddCommand(NewCmdTaskList(streams))
 	cmd.AddCommand(NewCmdTaskStatus(streams))
+	cmd.AddCommand(NewCmdTaskLogs(streams))
 	cmd.AddCommand(NewCmdTaskCancel(streams))
+	cmd.AddCommand(NewCmdTaskPlan(streams))
+	cmd.AddCommand(NewCmdTaskHistory(streams))
 
 	return cmd
 }
@@ -88,12 +123,16 @@ type TaskSubmitOptions struct {
 	genericclioptions.IOStreams
 
 	SwarmName   string
+	Filename    string
 	Task        string
 	Priority    string
 	DependsOn   []string
 	Namespace   string
 	Strategy    string
 	MaxRetries  int
+	Follow      bool
+	// IdempotencyKey makes resubmissions of the same task no-ops
+	IdempotencyKey string
 
 	configFlags *genericclioptions.ConfigFlags
 }
@@ -112,11 +151,13 @@ func NewCmdTaskSubmit(streams genericclioptions.IOStreams) *cobra.Command {
 	o := NewTaskSubmitOptions(streams)
 
 	cmd := &cobra.Command{
-		Use:   "submit SWARM-NAME",
+		Use:   "submit [SWARM-NAME]",
 		Short: "Submit a task to a swarm",
-		Args:  cobra.ExactArgs(1),
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			o.SwarmName = args[0]
+			if len(args) > 0 {
+				o.SwarmName = args[0]
+			}
 			if err := o.Complete(cmd); err != nil {
 				fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
 				return
@@ -132,12 +173,14 @@ func NewCmdTaskSubmit(streams genericclioptions.IOStreams) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", "", "SwarmTask manifest to submit (use - for stdin)")
 	cmd.Flags().StringVar(&o.Task, "task", "", "Task description")
-	cmd.MarkFlagRequired("task")
 	cmd.Flags().StringVar(&o.Priority, "priority", o.Priority, "Task priority (low, medium, high, critical)")
 	cmd.Flags().StringSliceVar(&o.DependsOn, "depends-on", nil, "Comma-separated list of task IDs this task depends on")
 	cmd.Flags().StringVar(&o.Strategy, "strategy", o.Strategy, "Execution strategy (parallel, sequential, adaptive)")
 	cmd.Flags().IntVar(&o.MaxRetries, "max-retries", o.MaxRetries, "Maximum number of retries on failure")
+	cmd.Flags().BoolVar(&o.Follow, "follow", false, "Stream task status and job logs until the task finishes")
+	cmd.Flags().StringVar(&o.IdempotencyKey, "idempotency-key", "", "Skip the task if one with the same key is running or completed in the last 24h")
 
 	o.configFlags.AddFlags(cmd.Flags())
 
@@ -151,8 +194,15 @@ func (o *TaskSubmitOptions) Complete(cmd *cobra.Command) error {
 }
 
 func (o *TaskSubmitOptions) Validate() error {
+	if o.Filename != "" {
+		return nil
+	}
+
+	if o.SwarmName == "" {
+		return fmt.Errorf("swarm name is required unless --filename is set")
+	}
 	if o.Task == "" {
-		return fmt.Errorf("task description is required")
+		return fmt.Errorf("either --task or --filename is required")
 	}
 
 	validPriorities := map[string]bool{
@@ -170,61 +220,272 @@ func (o *TaskSubmitOptions) Validate() error {
 
 func (o *TaskSubmitOptions) Run(ctx context.Context) error {
 	// Create Kubernetes client
-	swarmClient, err := client.NewSwarmClient(o.configFlags)
+	swarmClient, err := client.NewTypedClient(o.configFlags)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// Generate task name
-	taskName := fmt.Sprintf("%s-task-%d", o.SwarmName, metav1.Now().Unix())
-
-	// Create SwarmTask object
-	task := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "swarm.io/v1alpha1",
-			"kind":       "SwarmTask",
-			"metadata": map[string]interface{}{
-				"name":      taskName,
-				"namespace": o.Namespace,
-				"labels": map[string]interface{}{
-					"swarm.io/swarm": o.SwarmName,
-				},
-			},
-			"spec": map[string]interface{}{
-				"swarmRef": map[string]interface{}{
-					"name": o.SwarmName,
-				},
-				"task": map[string]interface{}{
-					"description": o.Task,
-					"priority":    o.Priority,
-					"strategy":    o.Strategy,
-					"maxRetries":  o.MaxRetries,
-				},
-			},
-		},
+	var task *swarmv1alpha1.SwarmTask
+	if o.Filename != "" {
+		task, err = o.loadTask()
+		if err != nil {
+			return err
+		}
+	} else {
+		task = o.buildTask()
 	}
 
-	// Add dependencies if specified
-	if len(o.DependsOn) > 0 {
-		spec := task.Object["spec"].(map[string]interface{})
-		spec["dependencies"] = o.DependsOn
+	if task.Namespace == "" {
+		task.Namespace = o.Namespace
+	}
+	if o.IdempotencyKey != "" {
+		task.Spec.IdempotencyKey = o.IdempotencyKey
+	}
+	if task.Labels == nil {
+		task.Labels = map[string]string{}
 	}
+	task.Labels[clusterLabel] = task.Spec.SwarmCluster
 
 	// Create the task
-	created, err := swarmClient.CreateTask(ctx, task, metav1.CreateOptions{})
-	if err != nil {
+	if err := swarmClient.Create(ctx, task); err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
-	fmt.Fprintf(o.Out, "Task submitted successfully!\n")
-	fmt.Fprintf(o.Out, "Task ID: %s\n", created.GetName())
+	fmt.Fprintf(o.Out, "swarmtask.swarm.claudeflow.io/%s created\n", task.Name)
+
+	if o.Follow {
+		return followTask(ctx, swarmClient, task.Name, o.IOStreams)
+	}
+
 	fmt.Fprintf(o.Out, "\nMonitor progress:\n")
-	fmt.Fprintf(o.Out, "  kubectl swarm task status %s\n", created.GetName())
-	fmt.Fprintf(o.Out, "  kubectl swarm logs %s --task %s\n", o.SwarmName, created.GetName())
+	fmt.Fprintf(o.Out, "  kubectl swarm task status %s --watch\n", task.Name)
+	fmt.Fprintf(o.Out, "  kubectl swarm task logs %s --follow\n", task.Name)
+
+	return nil
+}
+
+// loadTask reads a SwarmTask manifest from the --filename flag
+func (o *TaskSubmitOptions) loadTask() (*swarmv1alpha1.SwarmTask, error) {
+	var data []byte
+	var err error
+	if o.Filename == "-" {
+		data, err = io.ReadAll(o.In)
+	} else {
+		data, err = os.ReadFile(o.Filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", o.Filename, err)
+	}
+
+	task := &swarmv1alpha1.SwarmTask{}
+	if err := yaml.UnmarshalStrict(data, task); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", o.Filename, err)
+	}
+
+	if task.Kind != "" && task.Kind != "SwarmTask" {
+		return nil, fmt.Errorf("%s contains a %s, expected a SwarmTask", o.Filename, task.Kind)
+	}
+	if o.SwarmName != "" {
+		// The cluster passed as an argument replaces a selector of clusters
+		task.Spec.SwarmCluster = o.SwarmName
+		task.Spec.ClusterSelector = nil
+	}
+	if task.Spec.SwarmCluster == "" && task.Spec.ClusterSelector == nil {
+		return nil, fmt.Errorf("spec.swarmCluster or spec.clusterSelector must be set in %s, or a swarm passed as an argument", o.Filename)
+	}
+	if task.Name == "" && task.GenerateName == "" {
+		task.GenerateName = task.Spec.SwarmCluster + "-task-"
+		if task.Spec.SwarmCluster == "" {
+			task.GenerateName = "task-"
+		}
+	}
+
+	return task, nil
+}
+
+// buildTask constructs a SwarmTask from command line flags
+func (o *TaskSubmitOptions) buildTask() *swarmv1alpha1.SwarmTask {
+	return &swarmv1alpha1.SwarmTask{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: o.SwarmName + "-task-",
+			Namespace:    o.Namespace,
+		},
+		Spec: swarmv1alpha1.SwarmTaskSpec{
+			SwarmCluster: o.SwarmName,
+			Description:  o.Task,
+			Priority:     swarmv1alpha1.TaskPriority(o.Priority),
+			Strategy:     swarmv1alpha1.TaskStrategy(o.Strategy),
+			Dependencies: taskDependencies(o.DependsOn),
+			RetryPolicy: &swarmv1alpha1.RetryPolicy{
+				MaxRetries: int32(o.MaxRetries),
+			},
+		},
+	}
+}
+
+// taskDependencies makes the submitted task wait for the completion of each
+// named task. To is left empty, it is the submitted task itself.
+func taskDependencies(names []string) []swarmv1alpha1.TaskDependency {
+	var deps []swarmv1alpha1.TaskDependency
+	for _, name := range names {
+		deps = append(deps, swarmv1alpha1.TaskDependency{From: name, Type: "completion"})
+	}
+	return deps
+}
+
+// Logs subcommand
+type TaskLogsOptions struct {
+	genericclioptions.IOStreams
+
+	TaskName string
+	Follow   bool
+
+	configFlags *genericclioptions.ConfigFlags
+}
+
+func NewTaskLogsOptions(streams genericclioptions.IOStreams) *TaskLogsOptions {
+	return &TaskLogsOptions{
+		IOStreams:   streams,
+		configFlags: genericclioptions.NewConfigFlags(true),
+	}
+}
+
+func NewCmdTaskLogs(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewTaskLogsOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "logs TASK-ID",
+		Short: "Print the logs of the job running a task",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.TaskName = args[0]
+			if err := o.Run(cmd.Context()); err != nil {
+				fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
+				return
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.Follow, "follow", "f", false, "Stream status and logs until the task finishes")
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *TaskLogsOptions) Run(ctx context.Context) error {
+	swarmClient, err := client.NewTypedClient(o.configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if o.Follow {
+		return followTask(ctx, swarmClient, o.TaskName, o.IOStreams)
+	}
+
+	task, err := swarmClient.GetTask(ctx, o.TaskName)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	pods, err := swarmClient.ListTaskPods(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to list task pods: %w", err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("no pods found for task %s (phase %s)", task.Name, task.Status.Phase)
+	}
+
+	for i := range pods.Items {
+		if err := streamTaskPod(ctx, swarmClient, &pods.Items[i], false, o.Out); err != nil {
+			fmt.Fprintf(o.ErrOut, "Error reading logs from %s: %v\n", pods.Items[i].Name, err)
+		}
+	}
 
 	return nil
 }
 
+// followTask watches a task, reporting phase and progress changes, and
+// streams the logs of each job pod as soon as it starts running. It returns
+// once the task reaches a terminal phase.
+func followTask(ctx context.Context, c *client.TypedClient, name string, streams genericclioptions.IOStreams) error {
+	w, err := c.WatchTask(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to watch task: %w", err)
+	}
+	defer w.Stop()
+
+	var (
+		lastPhase    string
+		lastProgress int32 = -1
+		streamed           = map[string]bool{}
+		wg           sync.WaitGroup
+	)
+	defer wg.Wait()
+
+	for event := range w.ResultChan() {
+		task, ok := event.Object.(*swarmv1alpha1.SwarmTask)
+		if !ok {
+			continue
+		}
+		if event.Type == watch.Deleted {
+			return fmt.Errorf("task %s was deleted", name)
+		}
+
+		if task.Status.Phase != lastPhase || task.Status.Progress != lastProgress {
+			fmt.Fprintf(streams.ErrOut, "[%s] phase=%s progress=%d%% %s\n",
+				task.Name, task.Status.Phase, task.Status.Progress, task.Status.Message)
+			lastPhase = task.Status.Phase
+			lastProgress = task.Status.Progress
+		}
+
+		pods, err := c.ListTaskPods(ctx, task)
+		if err != nil {
+			fmt.Fprintf(streams.ErrOut, "Error listing task pods: %v\n", err)
+		} else {
+			for i := range pods.Items {
+				pod := pods.Items[i]
+				if streamed[pod.Name] || pod.Status.Phase == corev1.PodPending {
+					continue
+				}
+				streamed[pod.Name] = true
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := streamTaskPod(ctx, c, &pod, true, streams.Out); err != nil {
+						fmt.Fprintf(streams.ErrOut, "Error streaming logs from %s: %v\n", pod.Name, err)
+					}
+				}()
+			}
+		}
+
+		switch task.Status.Phase {
+		case "Completed":
+			return nil
+		case "Failed", "Cancelled":
+			return fmt.Errorf("task %s finished with phase %s", task.Name, task.Status.Phase)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// streamTaskPod copies the logs of the task container to out, prefixed with
+// the pod name so retries can be told apart
+func streamTaskPod(ctx context.Context, c *client.TypedClient, pod *corev1.Pod, follow bool, out io.Writer) error {
+	stream, err := c.StreamPodLogs(ctx, pod, "task", follow)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		fmt.Fprintf(out, "[%s] %s\n", pod.Name, scanner.Text())
+	}
+	return scanner.Err()
+}
+
 // List subcommand
 type TaskListOptions struct {
 	genericclioptions.IOStreams
@@ -288,7 +549,7 @@ func (o *TaskListOptions) Run(ctx context.Context) error {
 
 	// List tasks
 	listOpts := metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("swarm.io/swarm=%s", o.SwarmName),
+		LabelSelector: fmt.Sprintf("%s=%s", clusterLabel, o.SwarmName),
 	}
 	
 	if o.Status != "" {
@@ -459,4 +720,208 @@ func (o *TaskCancelOptions) Run(ctx context.Context) error {
 
 	fmt.Fprintf(o.Out, "Task %s cancelled successfully\n", o.TaskName)
 	return nil
-}
\ No newline at end of file
+}
+
+// Plan subcommand
+type TaskPlanOptions struct {
+	genericclioptions.IOStreams
+
+	TaskName string
+	Timeout  time.Duration
+
+	configFlags *genericclioptions.ConfigFlags
+}
+
+func NewTaskPlanOptions(streams genericclioptions.IOStreams) *TaskPlanOptions {
+	return &TaskPlanOptions{
+		IOStreams:   streams,
+		Timeout:     30 * time.Second,
+		configFlags: genericclioptions.NewConfigFlags(true),
+	}
+}
+
+func NewCmdTaskPlan(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewTaskPlanOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "plan TASK-ID",
+		Short: "Print the Job a task would run",
+		Long: templates.LongDesc(`Print the Job manifest the operator would create for a task that has
+			not started yet. The task is annotated with swarm.claudeflow.io/plan=true
+			and held until the annotation is removed. Create the task with the
+			annotation to plan it before it can start.`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.TaskName = args[0]
+			if err := o.Run(cmd.Context()); err != nil {
+				fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
+				return
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "How long to wait for the operator to render the plan")
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *TaskPlanOptions) Run(ctx context.Context) error {
+	swarmClient, err := client.NewTypedClient(o.configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	task, err := swarmClient.GetTask(ctx, o.TaskName)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.Status.JobName != "" || task.Status.StartTime != nil {
+		return fmt.Errorf("task %s already started", o.TaskName)
+	}
+	if task.Annotations[planAnnotation] != "true" {
+		patch := ctrlclient.MergeFrom(task.DeepCopy())
+		if task.Annotations == nil {
+			task.Annotations = map[string]string{}
+		}
+		task.Annotations[planAnnotation] = "true"
+		if err := swarmClient.Patch(ctx, task, patch); err != nil {
+			return fmt.Errorf("failed to annotate task: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if plan := task.Status.Plan; plan != nil && plan.ObservedGeneration >= task.Generation {
+			configMap := &corev1.ConfigMap{}
+			key := ctrlclient.ObjectKey{Namespace: task.Namespace, Name: plan.ConfigMap}
+			if err := swarmClient.Get(ctx, key, configMap); err != nil {
+				return fmt.Errorf("failed to get plan: %w", err)
+			}
+			fmt.Fprint(o.Out, configMap.Data["job.yaml"])
+			fmt.Fprintf(o.ErrOut, "Remove the %s annotation to run task %s\n", planAnnotation, o.TaskName)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the plan of task %s", o.TaskName)
+		case <-ticker.C:
+		}
+		if task, err = swarmClient.GetTask(ctx, o.TaskName); err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+	}
+}
+
+// History subcommand
+type TaskHistoryOptions struct {
+	genericclioptions.IOStreams
+
+	SwarmName string
+	Since     string
+	Until     string
+	Selector  string
+	Phase     string
+	Limit     int
+	Output    string
+
+	configFlags *genericclioptions.ConfigFlags
+}
+
+func NewTaskHistoryOptions(streams genericclioptions.IOStreams) *TaskHistoryOptions {
+	return &TaskHistoryOptions{
+		IOStreams:   streams,
+		Limit:       50,
+		Output:      "table",
+		configFlags: genericclioptions.NewConfigFlags(true),
+	}
+}
+
+func NewCmdTaskHistory(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewTaskHistoryOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "history SWARM-NAME",
+		Short: "Browse archived tasks of a swarm",
+		Long: templates.LongDesc(`Browse the tasks a swarm archived when they finished. Requires
+			spec.archive with the memory sink on the SwarmCluster.`),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			o.SwarmName = args[0]
+			if err := o.Run(cmd.Context()); err != nil {
+				fmt.Fprintf(o.ErrOut, "Error: %v\n", err)
+				return
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Since, "since", "", "Only tasks finished after this RFC 3339 time or duration ago, e.g. 24h")
+	cmd.Flags().StringVar(&o.Until, "until", "", "Only tasks finished before this RFC 3339 time or duration ago")
+	cmd.Flags().StringVarP(&o.Selector, "selector", "l", "", "Label selector matched against the task labels")
+	cmd.Flags().StringVar(&o.Phase, "phase", "", "Filter by final phase (Completed, Failed, DeadLettered)")
+	cmd.Flags().IntVar(&o.Limit, "limit", o.Limit, "Maximum number of tasks to show, newest first. 0 shows all")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format (table, json, yaml)")
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *TaskHistoryOptions) Run(ctx context.Context) error {
+	swarmClient, err := client.NewTypedClient(o.configFlags)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	query, err := archive.ParseQuery(url.Values{
+		"since":    {o.Since},
+		"until":    {o.Until},
+		"selector": {o.Selector},
+		"phase":    {o.Phase},
+		"limit":    {strconv.Itoa(o.Limit)},
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	query.Namespace = swarmClient.Namespace()
+	query.Cluster = o.SwarmName
+
+	records, err := archive.List(ctx, swarmClient, query)
+	if err != nil {
+		return fmt.Errorf("failed to list task history: %w", err)
+	}
+
+	switch o.Output {
+	case "json":
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(records)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.Out, string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "TASK\tATTEMPT\tPHASE\tTYPE\tFINISHED\tDURATION\tAGENTS")
+	for _, record := range records {
+		finished := "<unknown>"
+		if record.CompletionTime != nil {
+			finished = record.CompletionTime.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n", record.Task, record.Attempt, record.Phase, record.Type,
+			finished, time.Duration(record.DurationSeconds)*time.Second, strings.Join(record.Agents, ","))
+	}
+	return w.Flush()
+}
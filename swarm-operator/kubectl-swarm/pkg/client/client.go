@@ -36,19 +36,19 @@ type SwarmClient struct {
 
 var (
 	swarmGVR = schema.GroupVersionResource{
-		Group:    "swarm.io",
+		Group:    "swarm.claudeflow.io",
 		Version:  "v1alpha1",
-		Resource: "swarms",
+		Resource: "swarmclusters",
 	}
 
 	swarmAgentGVR = schema.GroupVersionResource{
-		Group:    "swarm.io",
+		Group:    "swarm.claudeflow.io",
 		Version:  "v1alpha1",
-		Resource: "swarmagents",
+		Resource: "agents",
 	}
 
 	swarmTaskGVR = schema.GroupVersionResource{
-		Group:    "swarm.io",
+		Group:    "swarm.claudeflow.io",
 		Version:  "v1alpha1",
 		Resource: "swarmtasks",
 	}
@@ -114,9 +114,9 @@ func (c *SwarmClient) Delete(ctx context.Context, name string, opts metav1.Delet
 // ListAgents lists all agents for a swarm
 func (c *SwarmClient) ListAgents(ctx context.Context, swarmName string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
 	if opts.LabelSelector != "" {
-		opts.LabelSelector = fmt.Sprintf("%s,swarm.io/swarm=%s", opts.LabelSelector, swarmName)
+		opts.LabelSelector = fmt.Sprintf("%s,%s=%s", opts.LabelSelector, ClusterLabel, swarmName)
 	} else {
-		opts.LabelSelector = fmt.Sprintf("swarm.io/swarm=%s", swarmName)
+		opts.LabelSelector = fmt.Sprintf("%s=%s", ClusterLabel, swarmName)
 	}
 	return c.dynamicClient.Resource(swarmAgentGVR).Namespace(c.namespace).List(ctx, opts)
 }
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Swarm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/backup"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// MemorySnapshots reads and restores memory store databases through the API
+// server's service proxy, so no port-forward is needed
+func (c *TypedClient) MemorySnapshots() backup.Snapshotter {
+	return &memorySnapshotter{kube: c.kube}
+}
+
+type memorySnapshotter struct {
+	kube kubernetes.Interface
+}
+
+// Snapshot implements backup.Snapshotter
+func (s *memorySnapshotter) Snapshot(ctx context.Context, store *swarmv1alpha1.SwarmMemoryStore) (io.ReadCloser, error) {
+	req, err := s.proxy(store, "GET")
+	if err != nil {
+		return nil, err
+	}
+	return req.Stream(ctx)
+}
+
+// Restore implements backup.Snapshotter
+func (s *memorySnapshotter) Restore(ctx context.Context, store *swarmv1alpha1.SwarmMemoryStore, data io.Reader) error {
+	req, err := s.proxy(store, "PUT")
+	if err != nil {
+		return err
+	}
+	return req.SetHeader("Content-Type", "application/octet-stream").
+		Body(data).
+		Do(ctx).
+		Error()
+}
+
+// proxy addresses the snapshot endpoint of the Service published in the
+// store's HTTP endpoint, e.g. http://memory.swarm.svc:8080
+func (s *memorySnapshotter) proxy(store *swarmv1alpha1.SwarmMemoryStore, verb string) (*rest.Request, error) {
+	endpoint, err := url.Parse(store.Status.Endpoints.HTTP)
+	if err != nil || endpoint.Hostname() == "" {
+		return nil, fmt.Errorf("memory store %s has no HTTP endpoint", store.Name)
+	}
+	host := strings.Split(endpoint.Hostname(), ".")
+	namespace := store.Namespace
+	if len(host) > 1 {
+		namespace = host[1]
+	}
+
+	return s.kube.CoreV1().RESTClient().Verb(verb).
+		Namespace(namespace).
+		Resource("services").
+		Name(fmt.Sprintf("%s:%s:%s", endpoint.Scheme, host[0], endpoint.Port())).
+		SubResource("proxy").
+		Suffix(backup.SnapshotPath), nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Swarm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TaskLabel is set by the operator on every pod it runs for a SwarmTask
+	TaskLabel = "swarm.claudeflow.io/task"
+	// TaskNamespaceLabel is the namespace of the SwarmTask a pod runs for
+	TaskNamespaceLabel = "swarm.claudeflow.io/task-namespace"
+	// ClusterLabel is set by the operator on agents belonging to a SwarmCluster
+	ClusterLabel = "swarm-cluster"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = swarmv1alpha1.AddToScheme(scheme)
+}
+
+// TypedClient provides typed access to the swarm.claudeflow.io/v1alpha1 API
+type TypedClient struct {
+	ctrlclient.WithWatch
+
+	kube      kubernetes.Interface
+	namespace string
+}
+
+// NewTypedClient creates a typed client for the swarm operator API
+func NewTypedClient(configFlags *genericclioptions.ConfigFlags) (*TypedClient, error) {
+	config, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get REST config: %w", err)
+	}
+
+	c, err := ctrlclient.NewWithWatch(config, ctrlclient.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create typed client: %w", err)
+	}
+
+	kube, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	return &TypedClient{
+		WithWatch: c,
+		kube:      kube,
+		namespace: namespace,
+	}, nil
+}
+
+// Namespace returns the namespace resolved from the kubeconfig flags
+func (c *TypedClient) Namespace() string {
+	return c.namespace
+}
+
+// GetSwarmCluster retrieves a SwarmCluster by name
+func (c *TypedClient) GetSwarmCluster(ctx context.Context, name string) (*swarmv1alpha1.SwarmCluster, error) {
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	if err := c.Get(ctx, ctrlclient.ObjectKey{Namespace: c.namespace, Name: name}, cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// ListAgents lists the agents that belong to a SwarmCluster
+func (c *TypedClient) ListAgents(ctx context.Context, clusterName string) (*swarmv1alpha1.AgentList, error) {
+	agents := &swarmv1alpha1.AgentList{}
+	err := c.List(ctx, agents,
+		ctrlclient.InNamespace(c.namespace),
+		ctrlclient.MatchingLabels{ClusterLabel: clusterName})
+	return agents, err
+}
+
+// GetTask retrieves a SwarmTask by name
+func (c *TypedClient) GetTask(ctx context.Context, name string) (*swarmv1alpha1.SwarmTask, error) {
+	task := &swarmv1alpha1.SwarmTask{}
+	if err := c.Get(ctx, ctrlclient.ObjectKey{Namespace: c.namespace, Name: name}, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// WatchTask watches a single SwarmTask by name
+func (c *TypedClient) WatchTask(ctx context.Context, name string) (watch.Interface, error) {
+	return c.Watch(ctx, &swarmv1alpha1.SwarmTaskList{},
+		ctrlclient.InNamespace(c.namespace),
+		ctrlclient.MatchingFields{"metadata.name": name})
+}
+
+// WatchSwarmCluster watches a single SwarmCluster by name
+func (c *TypedClient) WatchSwarmCluster(ctx context.Context, name string) (watch.Interface, error) {
+	return c.Watch(ctx, &swarmv1alpha1.SwarmClusterList{},
+		ctrlclient.InNamespace(c.namespace),
+		ctrlclient.MatchingFields{"metadata.name": name})
+}
+
+// ListTaskPods lists the pods the operator started for a SwarmTask. The job
+// namespace is chosen by the operator, so unless the task pins one we search
+// all namespaces for the task name and namespace labels.
+func (c *TypedClient) ListTaskPods(ctx context.Context, task *swarmv1alpha1.SwarmTask) (*corev1.PodList, error) {
+	namespace := task.Spec.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceAll
+	}
+	return c.kube.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			TaskLabel:          task.Name,
+			TaskNamespaceLabel: task.Namespace,
+		}).String(),
+	})
+}
+
+// StreamPodLogs opens a log stream for the given pod container
+func (c *TypedClient) StreamPodLogs(ctx context.Context, pod *corev1.Pod, container string, follow bool) (io.ReadCloser, error) {
+	return c.kube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Follow:    follow,
+	}).Stream(ctx)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Swarm Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func taskPod(name, namespace, task, taskNamespace string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    map[string]string{TaskLabel: task, TaskNamespaceLabel: taskNamespace},
+	}}
+}
+
+func TestListTaskPods(t *testing.T) {
+	c := &TypedClient{kube: fake.NewSimpleClientset(
+		taskPod("build-job-a", "swarm-tasks", "build", "team-a"),
+		taskPod("build-job-b", "swarm-tasks", "build", "team-b"),
+		taskPod("build-job-c", "team-a", "build", "team-a"),
+		taskPod("test-job-a", "swarm-tasks", "test", "team-a"),
+	)}
+
+	tests := []struct {
+		name string
+		task *swarmv1alpha1.SwarmTask
+		want []string
+	}{
+		{
+			name: "searches all namespaces for pods of the task",
+			task: &swarmv1alpha1.SwarmTask{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "team-a"}},
+			want: []string{"build-job-a", "build-job-c"},
+		},
+		{
+			name: "ignores tasks of the same name in other namespaces",
+			task: &swarmv1alpha1.SwarmTask{ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "team-b"}},
+			want: []string{"build-job-b"},
+		},
+		{
+			name: "searches the namespace the task pins",
+			task: &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "team-a"},
+				Spec:       swarmv1alpha1.SwarmTaskSpec{Namespace: "team-a"},
+			},
+			want: []string{"build-job-c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods, err := c.ListTaskPods(context.Background(), tt.task)
+			if err != nil {
+				t.Fatalf("ListTaskPods() error = %v", err)
+			}
+			var got []string
+			for _, pod := range pods.Items {
+				got = append(got, pod.Name)
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListTaskPods() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ListTaskPods() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
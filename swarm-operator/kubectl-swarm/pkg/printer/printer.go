@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strings"
 	"text/tabwriter"
 	"time"
 
@@ -148,13 +147,12 @@ func (p *TablePrinter) printSwarmRow(w io.Writer, swarm *unstructured.Unstructur
 	
 	status, _ := swarm.Object["status"].(map[string]interface{})
 	phase, _ := status["phase"].(string)
-	agents, _ := status["agents"].(map[string]interface{})
-	active, _ := agents["active"].(int64)
-	total, _ := agents["total"].(int64)
+	ready, _ := status["readyAgents"].(int64)
+	active, _ := status["activeAgents"].(int64)
 	
 	age := p.getAge(swarm.GetCreationTimestamp().Time)
 	
-	fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\n", name, topology, active, total, phase, age)
+	fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%s\n", name, topology, ready, active, phase, age)
 }
 
 func (p *TablePrinter) printAgentRow(w io.Writer, agent *unstructured.Unstructured) {
@@ -165,8 +163,17 @@ func (p *TablePrinter) printAgentRow(w io.Writer, agent *unstructured.Unstructur
 	
 	status, _ := agent.Object["status"].(map[string]interface{})
 	phase, _ := status["phase"].(string)
-	health, _ := status["health"].(string)
-	taskCount, _ := status["taskCount"].(int64)
+	health := "Unknown"
+	if conditions, ok := status["conditions"].([]interface{}); ok {
+		for _, c := range conditions {
+			condition, _ := c.(map[string]interface{})
+			if condition["type"] == "Ready" {
+				health, _ = condition["status"].(string)
+			}
+		}
+	}
+	currentTasks, _ := status["currentTasks"].([]interface{})
+	taskCount := len(currentTasks)
 	
 	age := p.getAge(agent.GetCreationTimestamp().Time)
 	
@@ -177,8 +184,7 @@ func (p *TablePrinter) printTaskRow(w io.Writer, task *unstructured.Unstructured
 	name := task.GetName()
 	
 	spec, _ := task.Object["spec"].(map[string]interface{})
-	taskSpec, _ := spec["task"].(map[string]interface{})
-	description, _ := taskSpec["description"].(string)
+	description, _ := spec["description"].(string)
 	
 	// Truncate long descriptions
 	if len(description) > 50 {
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apiclient tunes how hard the operator's controllers lean on the
+// API server: client-side rate limits per controller, and backing off as
+// API priority and fairness asks when the server throttles them.
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	// DefaultThrottleDelay is how long to back off from a 429 that suggests
+	// no delay
+	DefaultThrottleDelay = time.Second
+
+	// priorityLevelHeader names the API priority and fairness priority
+	// level a request was classified into
+	priorityLevelHeader = "X-Kubernetes-PF-PriorityLevel-UID"
+)
+
+// Limit is a client-side token bucket of API requests
+type Limit struct {
+	// QPS is the sustained rate of requests per second
+	QPS float32
+	// Burst is how many requests may be made at once
+	Burst int
+}
+
+// ParseLimits parses comma-separated controller=qps[:burst] pairs, e.g.
+// "swarmtask=50:100,agent=10". The burst defaults to twice the QPS.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := map[string]Limit{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid limit %q, want controller=qps[:burst]", pair)
+		}
+		qpsValue, burstValue, hasBurst := strings.Cut(value, ":")
+		qps, err := strconv.ParseFloat(qpsValue, 32)
+		if err != nil || qps <= 0 {
+			return nil, fmt.Errorf("invalid QPS %q for %s", qpsValue, name)
+		}
+		limit := Limit{QPS: float32(qps), Burst: int(2 * qps)}
+		if hasBurst {
+			burst, err := strconv.Atoi(burstValue)
+			if err != nil || burst < 1 {
+				return nil, fmt.Errorf("invalid burst %q for %s", burstValue, name)
+			}
+			limit.Burst = burst
+		}
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+		limits[name] = limit
+	}
+	return limits, nil
+}
+
+// WithLimit returns a copy of the config whose clients share one token
+// bucket of the limit. observe, if set, is called with how long each
+// request waited for the bucket.
+func WithLimit(base *rest.Config, limit Limit, observe func(wait time.Duration)) *rest.Config {
+	config := rest.CopyConfig(base)
+	config.QPS = limit.QPS
+	config.Burst = limit.Burst
+	config.RateLimiter = &observedLimiter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(limit.QPS, limit.Burst),
+		observe:     observe,
+	}
+	return config
+}
+
+// observedLimiter reports the time requests wait for a token
+type observedLimiter struct {
+	flowcontrol.RateLimiter
+	observe func(time.Duration)
+}
+
+// Accept implements flowcontrol.RateLimiter
+func (l *observedLimiter) Accept() {
+	start := time.Now()
+	l.RateLimiter.Accept()
+	l.record(time.Since(start))
+}
+
+// Wait implements flowcontrol.RateLimiter
+func (l *observedLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := l.RateLimiter.Wait(ctx)
+	l.record(time.Since(start))
+	return err
+}
+
+func (l *observedLimiter) record(wait time.Duration) {
+	if l.observe != nil {
+		l.observe(wait)
+	}
+}
+
+// ObserveThrottling makes the config's clients call observe with the
+// priority level of every request the API server throttled. Client-go
+// retries those itself, so they only surface as errors once its retries are
+// exhausted.
+func ObserveThrottling(config *rest.Config, observe func(priorityLevel string)) {
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &throttleObserver{next: rt, observe: observe}
+	})
+}
+
+type throttleObserver struct {
+	next    http.RoundTripper
+	observe func(string)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *throttleObserver) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		t.observe(resp.Header.Get(priorityLevelHeader))
+	}
+	return resp, err
+}
+
+// ThrottleDelay reports whether err is the API server throttling a request
+// and how long it asked clients to back off
+func ThrottleDelay(err error) (time.Duration, bool) {
+	if !apierrors.IsTooManyRequests(err) {
+		return 0, false
+	}
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	return DefaultThrottleDelay, true
+}
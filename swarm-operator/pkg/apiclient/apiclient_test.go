@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestAPIClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "API Client Suite")
+}
+
+var _ = Describe("ParseLimits", func() {
+	It("parses per controller limits", func() {
+		limits, err := ParseLimits("swarmtask=50:100, agent=2.5")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(Equal(map[string]Limit{
+			"swarmtask": {QPS: 50, Burst: 100},
+			"agent":     {QPS: 2.5, Burst: 5},
+		}))
+	})
+
+	It("accepts an empty spec", func() {
+		limits, err := ParseLimits("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(BeEmpty())
+	})
+
+	It("rejects malformed limits", func() {
+		for _, spec := range []string{"swarmtask", "=5", "agent=fast", "agent=0", "agent=5:0"} {
+			_, err := ParseLimits(spec)
+			Expect(err).To(HaveOccurred(), spec)
+		}
+	})
+})
+
+var _ = Describe("WithLimit", func() {
+	It("shares one observed token bucket among the config's clients", func() {
+		base := &rest.Config{Host: "https://example.com", QPS: 5, Burst: 10}
+		var waits []time.Duration
+		config := WithLimit(base, Limit{QPS: 1000, Burst: 1}, func(wait time.Duration) {
+			waits = append(waits, wait)
+		})
+
+		Expect(base.RateLimiter).To(BeNil())
+		Expect(config.QPS).To(BeEquivalentTo(1000))
+		config.RateLimiter.Accept()
+		config.RateLimiter.Accept()
+		Expect(waits).To(HaveLen(2))
+		Expect(waits[1]).To(BeNumerically(">", 0))
+	})
+})
+
+var _ = Describe("ObserveThrottling", func() {
+	It("reports the priority level of throttled requests", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/throttled" {
+				w.Header().Set(priorityLevelHeader, "workload-low")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		var levels []string
+		config := &rest.Config{Host: server.URL}
+		ObserveThrottling(config, func(level string) { levels = append(levels, level) })
+		httpClient, err := rest.HTTPClientFor(config)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, path := range []string{"/ok", "/throttled"} {
+			resp, err := httpClient.Get(server.URL + path)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+		}
+		Expect(levels).To(Equal([]string{"workload-low"}))
+	})
+})
+
+var _ = Describe("ThrottleDelay", func() {
+	It("backs off as long as the server asks", func() {
+		delay, ok := ThrottleDelay(apierrors.NewTooManyRequests("too many requests", 7))
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(7 * time.Second))
+	})
+
+	It("backs off by default when the server suggests no delay", func() {
+		delay, ok := ThrottleDelay(apierrors.NewTooManyRequests("too many requests", 0))
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(DefaultThrottleDelay))
+	})
+
+	It("ignores other errors", func() {
+		_, ok := ThrottleDelay(apierrors.NewNotFound(schema.GroupResource{Resource: "swarmtasks"}, "task"))
+		Expect(ok).To(BeFalse())
+		_, ok = ThrottleDelay(fmt.Errorf("connection refused"))
+		Expect(ok).To(BeFalse())
+	})
+})
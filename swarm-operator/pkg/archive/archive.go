@@ -0,0 +1,326 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package archive keeps a compact record of every finished SwarmTask so the
+// history of a swarm survives garbage collection of the tasks themselves.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/resultcache"
+)
+
+const (
+	// SinkMemory stores records as SwarmMemory entries next to the task
+	SinkMemory = "memory"
+	// SinkWebhook posts records as JSON to an HTTP endpoint
+	SinkWebhook = "webhook"
+
+	// ClusterLabel and PhaseLabel select archived records
+	ClusterLabel = "swarm-cluster"
+	PhaseLabel   = "swarm.claudeflow.io/archive-phase"
+	taskLabel    = "swarm.claudeflow.io/task"
+
+	// memoryNamespace is the SwarmMemory namespace records live in
+	memoryNamespace = "task-archive"
+)
+
+// Record is the archived summary of one task attempt
+type Record struct {
+	Task            string                        `json:"task"`
+	Namespace       string                        `json:"namespace"`
+	Cluster         string                        `json:"cluster"`
+	UID             string                        `json:"uid"`
+	Attempt         int32                         `json:"attempt"`
+	Type            string                        `json:"type,omitempty"`
+	Phase           string                        `json:"phase"`
+	SpecHash        string                        `json:"specHash"`
+	Labels          map[string]string             `json:"labels,omitempty"`
+	CreatedAt       metav1.Time                   `json:"createdAt"`
+	StartTime       *metav1.Time                  `json:"startTime,omitempty"`
+	CompletionTime  *metav1.Time                  `json:"completionTime,omitempty"`
+	DurationSeconds int64                         `json:"durationSeconds,omitempty"`
+	Retries         int32                         `json:"retries,omitempty"`
+	Agents          []string                      `json:"agents,omitempty"`
+	ResultRef       string                        `json:"resultRef,omitempty"`
+	Message         string                        `json:"message,omitempty"`
+	Failure         *swarmv1alpha1.FailureDetails `json:"failure,omitempty"`
+	Digest          *swarmv1alpha1.FailureDigest  `json:"digest,omitempty"`
+}
+
+// NewRecord summarizes a finished task
+func NewRecord(task *swarmv1alpha1.SwarmTask) (*Record, error) {
+	// The executor image is only known to the operator; tasks that used
+	// the result cache recorded the full key
+	hash := task.Status.CacheKey
+	if hash == "" {
+		var err error
+		if hash, err = resultcache.Key(&task.Spec, task.Spec.SwarmCluster, task.Spec.ExecutorImage); err != nil {
+			return nil, err
+		}
+	}
+
+	record := &Record{
+		Task:           task.Name,
+		Namespace:      task.Namespace,
+		Cluster:        task.Spec.SwarmCluster,
+		UID:            string(task.UID),
+		Attempt:        task.Status.Attempt,
+		Type:           task.Spec.Type,
+		Phase:          task.Status.Phase,
+		SpecHash:       hash,
+		Labels:         task.Labels,
+		CreatedAt:      task.CreationTimestamp,
+		StartTime:      task.Status.StartTime,
+		CompletionTime: task.Status.CompletionTime,
+		Retries:        task.Status.RetryCount,
+		Message:        task.Status.Message,
+		Failure:        task.Status.FailureDetails,
+		Digest:         task.Status.FailureDigest,
+	}
+	if task.Status.StartTime != nil && task.Status.CompletionTime != nil {
+		record.DurationSeconds = int64(task.Status.CompletionTime.Sub(task.Status.StartTime.Time).Seconds())
+	}
+	for _, agent := range task.Status.AssignedAgents {
+		record.Agents = append(record.Agents, agent.Name)
+	}
+	if result := task.Status.Result; result != nil {
+		record.ResultRef = result.StorageRef
+	}
+	if record.ResultRef == "" && task.Status.CacheKey != "" && task.Status.Phase == "Completed" {
+		record.ResultRef = "task-result:" + task.Status.CacheKey
+	}
+	return record, nil
+}
+
+// finishedAt is the time a record is ordered and filtered by
+func (r *Record) finishedAt() time.Time {
+	if r.CompletionTime != nil {
+		return r.CompletionTime.Time
+	}
+	return r.CreatedAt.Time
+}
+
+// Sink receives archived records
+type Sink interface {
+	Archive(ctx context.Context, record *Record) error
+}
+
+// MemorySink stores records as SwarmMemory entries in the task namespace
+type MemorySink struct {
+	client.Client
+
+	// TTL in seconds of the entries, 0 keeps them forever
+	TTL int32
+}
+
+// entryName is the SwarmMemory name of a task attempt's record
+func entryName(record *Record) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", record.UID, record.Attempt)))
+	return "task-archive-" + hex.EncodeToString(sum[:])[:32]
+}
+
+// Archive writes the record. Archiving the same attempt twice is a no-op.
+func (s *MemorySink) Archive(ctx context.Context, record *Record) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	entry := &swarmv1alpha1.SwarmMemory{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      entryName(record),
+			Namespace: record.Namespace,
+			Labels: map[string]string{
+				ClusterLabel: record.Cluster,
+				PhaseLabel:   record.Phase,
+				taskLabel:    record.Task,
+			},
+		},
+		Spec: swarmv1alpha1.SwarmMemorySpec{
+			ClusterRef: record.Cluster,
+			Namespace:  memoryNamespace,
+			Type:       swarmv1alpha1.MemoryTypeTaskArchive,
+			Key:        fmt.Sprintf("%s/%s/%d", record.Namespace, record.Task, record.Attempt),
+			Value:      string(value),
+			TTL:        s.TTL,
+			Tags:       []string{record.Type, record.Phase},
+		},
+	}
+	if err := s.Create(ctx, entry); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// WebhookSink posts records as JSON to an HTTP endpoint
+type WebhookSink struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+// NewWebhookSink creates a sink for the given URL. Token is sent as a bearer
+// token when non-empty.
+func NewWebhookSink(url, token string) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Token:  token,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Archive sends the record and fails on any non-2xx response
+func (s *WebhookSink) Archive(ctx context.Context, record *Record) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send record: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Query selects archived records. Zero fields match everything.
+type Query struct {
+	Namespace string
+	Cluster   string
+	Phase     string
+	// Since and Until bound the completion time
+	Since time.Time
+	Until time.Time
+	// Selector matches the labels the task had
+	Selector labels.Selector
+	// Limit caps the number of records, newest first
+	Limit int
+}
+
+// List returns the records of the memory sink matching the query, newest
+// first
+func List(ctx context.Context, c client.Reader, q Query) ([]Record, error) {
+	matching := client.MatchingLabels{}
+	if q.Cluster != "" {
+		matching[ClusterLabel] = q.Cluster
+	}
+	if q.Phase != "" {
+		matching[PhaseLabel] = q.Phase
+	}
+	entries := &swarmv1alpha1.SwarmMemoryList{}
+	if err := c.List(ctx, entries, client.InNamespace(q.Namespace), matching); err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	for _, entry := range entries.Items {
+		if entry.Spec.Type != swarmv1alpha1.MemoryTypeTaskArchive {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal([]byte(entry.Spec.Value), &record); err != nil {
+			return nil, fmt.Errorf("corrupt archive entry %s: %w", entry.Name, err)
+		}
+		finished := record.finishedAt()
+		if !q.Since.IsZero() && finished.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && finished.After(q.Until) {
+			continue
+		}
+		if q.Selector != nil && !q.Selector.Matches(labels.Set(record.Labels)) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].finishedAt().After(records[j].finishedAt())
+	})
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[:q.Limit]
+	}
+	return records, nil
+}
+
+// ParseQuery reads a query from the phase, since, until, selector and
+// limit parameters. Since and until take RFC 3339 times or durations
+// before now such as 24h.
+func ParseQuery(params url.Values, now time.Time) (Query, error) {
+	query := Query{Phase: params.Get("phase")}
+
+	var err error
+	if query.Since, err = parseQueryTime(params.Get("since"), now); err != nil {
+		return query, fmt.Errorf("invalid since: %w", err)
+	}
+	if query.Until, err = parseQueryTime(params.Get("until"), now); err != nil {
+		return query, fmt.Errorf("invalid until: %w", err)
+	}
+	if selector := params.Get("selector"); selector != "" {
+		if query.Selector, err = labels.Parse(selector); err != nil {
+			return query, fmt.Errorf("invalid selector: %w", err)
+		}
+	}
+	if limit := params.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 0 {
+			return query, fmt.Errorf("invalid limit %q", limit)
+		}
+	}
+	return query, nil
+}
+
+// parseQueryTime accepts an RFC 3339 time or a duration before now
+func parseQueryTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
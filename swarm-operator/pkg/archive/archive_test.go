@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+func TestArchive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Task Archive Suite")
+}
+
+var _ = Describe("Task archive", func() {
+	var ctx context.Context
+
+	finishedTask := func(name, phase string, finished time.Time, taskLabels map[string]string) *swarmv1alpha1.SwarmTask {
+		start := metav1.NewTime(finished.Add(-time.Minute))
+		end := metav1.NewTime(finished)
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), Labels: taskLabels},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm", Type: "analysis"},
+			Status: swarmv1alpha1.SwarmTaskStatus{
+				Phase:          phase,
+				StartTime:      &start,
+				CompletionTime: &end,
+				AssignedAgents: []swarmv1alpha1.AssignedAgent{{Name: "swarm-coder-0"}},
+				Result:         &swarmv1alpha1.TaskResult{StorageRef: "s3://results/" + name},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("summarizes a finished task", func() {
+		record, err := NewRecord(finishedTask("build", "Completed", time.Now(), nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(record.SpecHash).To(HaveLen(64))
+		Expect(record.DurationSeconds).To(BeEquivalentTo(60))
+		Expect(record.Agents).To(Equal([]string{"swarm-coder-0"}))
+		Expect(record.ResultRef).To(Equal("s3://results/build"))
+	})
+
+	Describe("memory sink", func() {
+		var (
+			c    client.Client
+			sink *MemorySink
+		)
+
+		archive := func(task *swarmv1alpha1.SwarmTask) {
+			record, err := NewRecord(task)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sink.Archive(ctx, record)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
+			c = fake.NewClientBuilder().WithScheme(scheme).Build()
+			sink = &MemorySink{Client: c, TTL: 3600}
+
+			now := time.Now()
+			archive(finishedTask("old", "Completed", now.Add(-48*time.Hour), map[string]string{"team": "research"}))
+			archive(finishedTask("failed", "Failed", now.Add(-time.Hour), map[string]string{"team": "research"}))
+			archive(finishedTask("recent", "Completed", now, map[string]string{"team": "ops"}))
+		})
+
+		It("ignores archiving the same attempt twice", func() {
+			archive(finishedTask("recent", "Completed", time.Now(), nil))
+
+			records, err := List(ctx, c, Query{Namespace: "default", Cluster: "swarm"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(3))
+		})
+
+		It("lists records newest first", func() {
+			records, err := List(ctx, c, Query{Namespace: "default", Cluster: "swarm"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(3))
+			Expect(records[0].Task).To(Equal("recent"))
+			Expect(records[2].Task).To(Equal("old"))
+		})
+
+		It("filters by time range, phase and task labels", func() {
+			records, err := List(ctx, c, Query{Namespace: "default", Since: time.Now().Add(-24 * time.Hour)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+
+			records, err = List(ctx, c, Query{Namespace: "default", Phase: "Failed"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Task).To(Equal("failed"))
+
+			selector, err := labels.Parse("team=research")
+			Expect(err).NotTo(HaveOccurred())
+			records, err = List(ctx, c, Query{Namespace: "default", Selector: selector, Limit: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(1))
+			Expect(records[0].Task).To(Equal("failed"))
+		})
+	})
+
+	It("posts records to the webhook sink", func() {
+		received := make(chan Record, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer secret"))
+			var record Record
+			Expect(json.NewDecoder(r.Body).Decode(&record)).To(Succeed())
+			received <- record
+		}))
+		defer server.Close()
+
+		record, err := NewRecord(finishedTask("build", "Completed", time.Now(), nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NewWebhookSink(server.URL, "secret").Archive(ctx, record)).To(Succeed())
+		Eventually(received).Should(Receive(HaveField("Task", "build")))
+	})
+})
+
+var _ = Describe("ParseQuery", func() {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	It("accepts durations, times and selectors", func() {
+		query, err := ParseQuery(url.Values{
+			"since":    {"24h"},
+			"until":    {"2025-06-01T11:00:00Z"},
+			"selector": {"team=research"},
+			"phase":    {"Failed"},
+			"limit":    {"10"},
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query.Since).To(Equal(now.Add(-24 * time.Hour)))
+		Expect(query.Until).To(Equal(now.Add(-time.Hour)))
+		Expect(query.Selector.String()).To(Equal("team=research"))
+		Expect(query.Phase).To(Equal("Failed"))
+		Expect(query.Limit).To(Equal(10))
+	})
+
+	It("rejects malformed parameters", func() {
+		_, err := ParseQuery(url.Values{"since": {"yesterday"}}, now)
+		Expect(err).To(HaveOccurred())
+		_, err = ParseQuery(url.Values{"limit": {"-1"}}, now)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records every mutation the operator makes against the API
+// server as a structured JSON record, so security can answer which object
+// was created, updated or deleted, by which controller and why.
+package audit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultQueueSize     = 1024
+	defaultBatchSize     = 100
+	defaultFlushInterval = 5 * time.Second
+)
+
+// Verbs recorded for mutations
+const (
+	VerbCreate           = "create"
+	VerbUpdate           = "update"
+	VerbPatch            = "patch"
+	VerbDelete           = "delete"
+	VerbDeleteCollection = "deletecollection"
+)
+
+// ObjectRef identifies the object a record is about
+type ObjectRef struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+}
+
+// Record is a single audited mutation
+type Record struct {
+	Time time.Time `json:"time"`
+
+	// Actor is always controller/<name>; the operator only mutates on behalf
+	// of its reconcilers
+	Actor      string `json:"actor"`
+	Controller string `json:"controller"`
+
+	Verb        string    `json:"verb"`
+	Object      ObjectRef `json:"object"`
+	Subresource string    `json:"subresource,omitempty"`
+
+	// Changes lists the field paths that differ from the cached object, or
+	// the paths touched by a patch
+	Changes []string `json:"changes,omitempty"`
+
+	// Trigger is the resource whose reconcile made the mutation
+	Trigger     *ObjectRef `json:"trigger,omitempty"`
+	ReconcileID string     `json:"reconcileID,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// Sink receives batches of audit records
+type Sink interface {
+	Send(ctx context.Context, records []Record) error
+}
+
+// Options configures an Auditor
+type Options struct {
+	// Local sinks are written synchronously so no record is lost if the
+	// process dies; typically stdout
+	Local []Sink
+
+	// Remote sinks are fed in batches from a bounded queue by Start and
+	// never block a reconcile
+	Remote []Sink
+
+	// Controllers enables auditing per controller; "*" enables all
+	Controllers []string
+
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// Auditor fans audit records out to the configured sinks
+type Auditor struct {
+	local         []Sink
+	remote        []Sink
+	controllers   map[string]bool
+	batchSize     int
+	flushInterval time.Duration
+
+	queue   chan Record
+	dropped atomic.Int64
+	mu      sync.Mutex
+}
+
+// NewAuditor creates an auditor from opts
+func NewAuditor(opts Options) *Auditor {
+	a := &Auditor{
+		local:         opts.Local,
+		remote:        opts.Remote,
+		controllers:   map[string]bool{},
+		batchSize:     opts.BatchSize,
+		flushInterval: opts.FlushInterval,
+	}
+	for _, c := range opts.Controllers {
+		a.controllers[c] = true
+	}
+	if a.batchSize <= 0 {
+		a.batchSize = defaultBatchSize
+	}
+	if a.flushInterval <= 0 {
+		a.flushInterval = defaultFlushInterval
+	}
+	queueSize := opts.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	a.queue = make(chan Record, queueSize)
+	return a
+}
+
+// Enabled reports whether mutations made by the controller are audited
+func (a *Auditor) Enabled(controller string) bool {
+	if a == nil {
+		return false
+	}
+	return a.controllers["*"] || a.controllers[controller]
+}
+
+// Record writes rec to the local sinks and queues it for the remote ones.
+// Records are dropped, and counted, when the remote queue is full.
+func (a *Auditor) Record(ctx context.Context, rec Record) {
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+
+	a.mu.Lock()
+	for _, sink := range a.local {
+		if err := sink.Send(ctx, []Record{rec}); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to write audit record")
+		}
+	}
+	a.mu.Unlock()
+
+	if len(a.remote) == 0 {
+		return
+	}
+	select {
+	case a.queue <- rec:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of records dropped because the remote queue was
+// full
+func (a *Auditor) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+// Start delivers queued records to the remote sinks until ctx is cancelled,
+// then flushes what is left. It implements manager.Runnable.
+func (a *Auditor) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("audit")
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	var reported int64
+	batch := make([]Record, 0, a.batchSize)
+	flush := func(ctx context.Context) {
+		if dropped := a.Dropped(); dropped != reported {
+			logger.Info("Audit records dropped, remote queue full", "dropped", dropped-reported)
+			reported = dropped
+		}
+		if len(batch) == 0 {
+			return
+		}
+		for _, sink := range a.remote {
+			if err := sink.Send(ctx, batch); err != nil {
+				logger.Error(err, "Failed to deliver audit records", "records", len(batch))
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case rec := <-a.queue:
+			batch = append(batch, rec)
+			if len(batch) >= a.batchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+		drain:
+			for {
+				select {
+				case rec := <-a.queue:
+					batch = append(batch, rec)
+				default:
+					break drain
+				}
+			}
+			drainCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			flush(drainCtx)
+			cancel()
+			return nil
+		}
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAudit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Audit Suite")
+}
+
+type recordingSink struct {
+	records []Record
+}
+
+func (s *recordingSink) Send(_ context.Context, records []Record) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+var _ = Describe("Changes", func() {
+	It("should report changed paths and skip bookkeeping and status", func() {
+		before := map[string]interface{}{
+			"metadata": map[string]interface{}{"resourceVersion": "1", "labels": map[string]interface{}{"app": "a"}},
+			"spec":     map[string]interface{}{"replicas": int64(1), "paused": false},
+			"status":   map[string]interface{}{"phase": "Pending"},
+		}
+		after := map[string]interface{}{
+			"metadata": map[string]interface{}{"resourceVersion": "2", "labels": map[string]interface{}{"app": "b"}},
+			"spec":     map[string]interface{}{"replicas": int64(3), "paused": false},
+			"status":   map[string]interface{}{"phase": "Running"},
+		}
+
+		Expect(Changes(before, after, "")).To(Equal([]string{"metadata.labels.app", "spec.replicas"}))
+		Expect(Changes(before, after, "status")).To(Equal([]string{"status.phase"}))
+	})
+
+	It("should summarise merge and JSON patches", func() {
+		Expect(PatchPaths([]byte(`{"metadata":{"annotations":{"checkpoint":null}},"spec":{"suspend":true}}`))).
+			To(Equal([]string{"metadata.annotations.checkpoint", "spec.suspend"}))
+		Expect(PatchPaths([]byte(`[{"op":"replace","path":"/spec/template/spec/containers/0/image"},{"op":"add","path":"/spec/replicas"}]`))).
+			To(Equal([]string{"spec.replicas", "spec.template.spec"}))
+	})
+})
+
+var _ = Describe("Client", func() {
+	var (
+		ctx     context.Context
+		scheme  *runtime.Scheme
+		sink    *recordingSink
+		auditor *Auditor
+		inner   client.Client
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		sink = &recordingSink{}
+		auditor = NewAuditor(Options{Local: []Sink{sink}, Controllers: []string{"swarmtask"}})
+		inner = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&corev1.Pod{}).Build()
+		ctx = WithTrigger(context.Background(), "SwarmTask", types.NamespacedName{Namespace: "default", Name: "build"})
+	})
+
+	It("should only wrap enabled controllers", func() {
+		Expect(NewClient(inner, "agent", auditor)).To(BeIdenticalTo(inner))
+		Expect(NewClient(inner, "swarmtask", auditor)).To(BeAssignableToTypeOf(&Client{}))
+		Expect(NewClient(inner, "swarmtask", nil)).To(BeIdenticalTo(inner))
+	})
+
+	It("should record creates, updates and deletes with their trigger", func() {
+		c := NewClient(inner, "swarmtask", auditor)
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "build-config", Namespace: "default"},
+			Data:       map[string]string{"token": "secret-value"},
+		}
+		Expect(c.Create(ctx, cm)).To(Succeed())
+
+		cm.Data["token"] = "rotated-value"
+		cm.Labels = map[string]string{"rotated": "true"}
+		Expect(c.Update(ctx, cm)).To(Succeed())
+		Expect(c.Delete(ctx, cm)).To(Succeed())
+
+		Expect(sink.records).To(HaveLen(3))
+		created, updated, deleted := sink.records[0], sink.records[1], sink.records[2]
+
+		Expect(created.Verb).To(Equal(VerbCreate))
+		Expect(created.Actor).To(Equal("controller/swarmtask"))
+		Expect(created.Object).To(Equal(ObjectRef{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "build-config"}))
+		Expect(created.Trigger).To(Equal(&ObjectRef{Kind: "SwarmTask", Namespace: "default", Name: "build"}))
+
+		Expect(updated.Verb).To(Equal(VerbUpdate))
+		Expect(updated.Changes).To(Equal([]string{"data.token", "metadata.labels"}))
+		Expect(deleted.Verb).To(Equal(VerbDelete))
+
+		encoded, err := json.Marshal(sink.records)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(encoded)).NotTo(ContainSubstring("secret-value"))
+		Expect(string(encoded)).NotTo(ContainSubstring("rotated-value"))
+	})
+
+	It("should record status writes and failed mutations", func() {
+		c := NewClient(inner, "swarmtask", auditor)
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "default"}}
+		Expect(inner.Create(ctx, pod)).To(Succeed())
+
+		pod.Status.Phase = corev1.PodRunning
+		Expect(c.Status().Update(ctx, pod)).To(Succeed())
+
+		missing := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "default"}}
+		Expect(c.Delete(ctx, missing)).NotTo(Succeed())
+
+		Expect(sink.records).To(HaveLen(2))
+		Expect(sink.records[0].Subresource).To(Equal("status"))
+		Expect(sink.records[0].Changes).To(Equal([]string{"status.phase"}))
+		Expect(sink.records[1].Error).To(ContainSubstring("not found"))
+	})
+})
+
+var _ = Describe("Sinks", func() {
+	var rec Record
+
+	BeforeEach(func() {
+		rec = Record{
+			Time:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Actor:      "controller/swarmcluster",
+			Controller: "swarmcluster",
+			Verb:       VerbCreate,
+			Object:     ObjectRef{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "default", Name: "swarm-hivemind"},
+		}
+	})
+
+	It("should write one JSON line per record", func() {
+		var buf bytes.Buffer
+		Expect(NewWriterSink(&buf).Send(context.Background(), []Record{rec, rec})).To(Succeed())
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(2))
+		var decoded Record
+		Expect(json.Unmarshal(lines[0], &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(rec))
+	})
+
+	It("should render OTLP log records", func() {
+		body, err := OTLPPayload("swarm-operator", []Record{rec})
+		Expect(err).NotTo(HaveOccurred())
+
+		var payload struct {
+			ResourceLogs []struct {
+				ScopeLogs []struct {
+					LogRecords []struct {
+						TimeUnixNano string `json:"timeUnixNano"`
+						Body         struct {
+							StringValue string `json:"stringValue"`
+						} `json:"body"`
+					} `json:"logRecords"`
+				} `json:"scopeLogs"`
+			} `json:"resourceLogs"`
+		}
+		Expect(json.Unmarshal(body, &payload)).To(Succeed())
+		logRecord := payload.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+		Expect(logRecord.TimeUnixNano).To(Equal("1735689600000000000"))
+		Expect(logRecord.Body.StringValue).To(ContainSubstring(`"kind":"StatefulSet"`))
+	})
+
+	It("should batch records to remote sinks and flush on shutdown", func() {
+		batches := make(chan []Record, 4)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer token"))
+			var records []Record
+			Expect(json.NewDecoder(r.Body).Decode(&records)).To(Succeed())
+			batches <- records
+		}))
+		defer server.Close()
+
+		auditor := NewAuditor(Options{
+			Remote:        []Sink{NewWebhookSink(server.URL, "token")},
+			Controllers:   []string{"*"},
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- auditor.Start(ctx) }()
+
+		for i := 0; i < 3; i++ {
+			auditor.Record(ctx, rec)
+		}
+		Eventually(batches).Should(Receive(HaveLen(2)))
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(batches).To(Receive(HaveLen(1)))
+	})
+
+	It("should drop records when the remote queue is full", func() {
+		auditor := NewAuditor(Options{Remote: []Sink{&recordingSink{}}, QueueSize: 1})
+		auditor.Record(context.Background(), rec)
+		auditor.Record(context.Background(), rec)
+		Expect(auditor.Dropped()).To(Equal(int64(1)))
+	})
+})
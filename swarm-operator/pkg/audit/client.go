@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+type triggerKey struct{}
+
+// WithTrigger records the resource being reconciled on ctx so mutations made
+// while handling it are attributed to it
+func WithTrigger(ctx context.Context, kind string, key types.NamespacedName) context.Context {
+	return context.WithValue(ctx, triggerKey{}, &ObjectRef{Kind: kind, Namespace: key.Namespace, Name: key.Name})
+}
+
+// TriggerFrom returns the resource recorded by WithTrigger, if any
+func TriggerFrom(ctx context.Context) *ObjectRef {
+	trigger, _ := ctx.Value(triggerKey{}).(*ObjectRef)
+	return trigger
+}
+
+// Client wraps a controller client and records every write it makes. Reads
+// pass straight through.
+type Client struct {
+	client.Client
+
+	Controller string
+	Auditor    *Auditor
+}
+
+// NewClient returns c wrapped for auditing when the controller is enabled on
+// the auditor, and c unchanged otherwise
+func NewClient(c client.Client, name string, auditor *Auditor) client.Client {
+	if !auditor.Enabled(name) {
+		return c
+	}
+	return &Client{Client: c, Controller: name, Auditor: auditor}
+}
+
+// Create implements client.Writer
+func (c *Client) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	c.record(ctx, VerbCreate, obj, "", nil, err)
+	return err
+}
+
+// Update implements client.Writer
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	changes := c.changes(ctx, obj, "")
+	err := c.Client.Update(ctx, obj, opts...)
+	c.record(ctx, VerbUpdate, obj, "", changes, err)
+	return err
+}
+
+// Patch implements client.Writer
+func (c *Client) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	changes := patchChanges(obj, patch)
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	c.record(ctx, VerbPatch, obj, "", changes, err)
+	return err
+}
+
+// Delete implements client.Writer
+func (c *Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	c.record(ctx, VerbDelete, obj, "", nil, err)
+	return err
+}
+
+// DeleteAllOf implements client.Writer
+func (c *Client) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	deleteOpts := &client.DeleteAllOfOptions{}
+	deleteOpts.ApplyOptions(opts)
+
+	rec := c.newRecord(ctx, VerbDeleteCollection, obj, "", nil, err)
+	rec.Object.Name = ""
+	rec.Object.Namespace = deleteOpts.Namespace
+	if deleteOpts.LabelSelector != nil {
+		rec.Changes = []string{"labelSelector=" + deleteOpts.LabelSelector.String()}
+	}
+	c.Auditor.Record(ctx, rec)
+	return err
+}
+
+// Status implements client.StatusClient
+func (c *Client) Status() client.SubResourceWriter {
+	return &subResourceClient{SubResourceClient: c.Client.SubResource("status"), parent: c, name: "status"}
+}
+
+// SubResource implements client.SubResourceClientConstructor
+func (c *Client) SubResource(subResource string) client.SubResourceClient {
+	return &subResourceClient{SubResourceClient: c.Client.SubResource(subResource), parent: c, name: subResource}
+}
+
+type subResourceClient struct {
+	client.SubResourceClient
+
+	parent *Client
+	name   string
+}
+
+func (s *subResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	err := s.SubResourceClient.Create(ctx, obj, subResource, opts...)
+	s.parent.record(ctx, VerbCreate, obj, s.name, nil, err)
+	return err
+}
+
+func (s *subResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	changes := s.parent.changes(ctx, obj, s.name)
+	err := s.SubResourceClient.Update(ctx, obj, opts...)
+	s.parent.record(ctx, VerbUpdate, obj, s.name, changes, err)
+	return err
+}
+
+func (s *subResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	changes := patchChanges(obj, patch)
+	err := s.SubResourceClient.Patch(ctx, obj, patch, opts...)
+	s.parent.record(ctx, VerbPatch, obj, s.name, changes, err)
+	return err
+}
+
+// changes diffs obj against the copy the client currently reads, which for a
+// manager client is the informer cache and costs no API call
+func (c *Client) changes(ctx context.Context, obj client.Object, subresource string) []string {
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil
+	}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		return nil
+	}
+	before, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return nil
+	}
+	after, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil
+	}
+	return Changes(before, after, subresource)
+}
+
+func patchChanges(obj client.Object, patch client.Patch) []string {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return nil
+	}
+	return PatchPaths(data)
+}
+
+func (c *Client) record(ctx context.Context, verb string, obj client.Object, subresource string, changes []string, err error) {
+	c.Auditor.Record(ctx, c.newRecord(ctx, verb, obj, subresource, changes, err))
+}
+
+func (c *Client) newRecord(ctx context.Context, verb string, obj client.Object, subresource string, changes []string, err error) Record {
+	ref := ObjectRef{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	if gvk, gvkErr := c.Client.GroupVersionKindFor(obj); gvkErr == nil {
+		ref.APIVersion, ref.Kind = gvk.GroupVersion().String(), gvk.Kind
+	}
+
+	rec := Record{
+		Actor:       "controller/" + c.Controller,
+		Controller:  c.Controller,
+		Verb:        verb,
+		Object:      ref,
+		Subresource: subresource,
+		Changes:     changes,
+		Trigger:     TriggerFrom(ctx),
+		ReconcileID: string(controller.ReconcileIDFromContext(ctx)),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	return rec
+}
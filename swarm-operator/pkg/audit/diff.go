@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxChangeDepth bounds how deep changed paths are reported; deeper
+	// changes are attributed to their ancestor at this depth
+	maxChangeDepth = 3
+
+	// maxChanges bounds the summary so a rewritten ConfigMap does not
+	// produce an unbounded record
+	maxChanges = 32
+)
+
+// ignoredPaths are bookkeeping fields the API server owns
+var ignoredPaths = map[string]bool{
+	"metadata.resourceVersion":   true,
+	"metadata.managedFields":     true,
+	"metadata.generation":        true,
+	"metadata.creationTimestamp": true,
+	"metadata.uid":               true,
+}
+
+// Changes summarises how after differs from before as a sorted list of field
+// paths. Only paths are reported, never values, so secret data cannot leak
+// into the audit stream. For the status subresource only status is compared;
+// otherwise status is skipped because the API server ignores it.
+func Changes(before, after map[string]interface{}, subresource string) []string {
+	var paths []string
+	for _, key := range unionKeys(before, after) {
+		switch {
+		case subresource != "" && key != subresource:
+			continue
+		case subresource == "" && key == "status":
+			continue
+		}
+		changedPaths(key, before[key], after[key], 1, &paths)
+	}
+	return limit(paths)
+}
+
+// PatchPaths summarises the field paths a patch body touches. JSON patches
+// report each operation's path; merge and strategic merge patches report the
+// keys they set or remove.
+func PatchPaths(data []byte) []string {
+	var ops []struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(data, &ops); err == nil {
+		var paths []string
+		for _, op := range ops {
+			segments := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+			if len(segments) > maxChangeDepth {
+				segments = segments[:maxChangeDepth]
+			}
+			paths = append(paths, strings.Join(segments, "."))
+		}
+		return limit(dedupe(paths))
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil
+	}
+	var paths []string
+	for _, key := range unionKeys(patch, nil) {
+		patchPaths(key, patch[key], 1, &paths)
+	}
+	return limit(paths)
+}
+
+func changedPaths(path string, before, after interface{}, depth int, paths *[]string) {
+	if ignoredPaths[path] || reflect.DeepEqual(before, after) {
+		return
+	}
+	b, bok := before.(map[string]interface{})
+	a, aok := after.(map[string]interface{})
+	if !bok || !aok || depth >= maxChangeDepth {
+		*paths = append(*paths, path)
+		return
+	}
+	for _, key := range unionKeys(b, a) {
+		changedPaths(path+"."+key, b[key], a[key], depth+1, paths)
+	}
+}
+
+func patchPaths(path string, value interface{}, depth int, paths *[]string) {
+	if ignoredPaths[path] {
+		return
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok || len(m) == 0 || depth >= maxChangeDepth {
+		*paths = append(*paths, path)
+		return
+	}
+	for _, key := range unionKeys(m, nil) {
+		patchPaths(path+"."+key, m[key], depth+1, paths)
+	}
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func dedupe(paths []string) []string {
+	seen := map[string]bool{}
+	out := paths[:0]
+	for _, p := range paths {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func limit(paths []string) []string {
+	if len(paths) > maxChanges {
+		return append(paths[:maxChanges], "...")
+	}
+	return paths
+}
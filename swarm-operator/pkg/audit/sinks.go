@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WriterSink writes one JSON record per line, typically to stdout
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink writing JSON lines to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Send implements Sink
+func (s *WriterSink) Send(_ context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enc := json.NewEncoder(s.w)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WebhookSink posts batches of records as a JSON array
+type WebhookSink struct {
+	URL        string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewWebhookSink creates a webhook sink for url
+func NewWebhookSink(url, token string) *WebhookSink {
+	return &WebhookSink{
+		URL:        url,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send implements Sink
+func (s *WebhookSink) Send(ctx context.Context, records []Record) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.HTTPClient, s.URL, s.Token, body)
+}
+
+// OTLPSink exports records as OTLP log records over HTTP with JSON encoding,
+// so any OpenTelemetry collector can ingest them without extra dependencies
+type OTLPSink struct {
+	// Endpoint is the collector base URL; /v1/logs is appended
+	Endpoint    string
+	ServiceName string
+	HTTPClient  *http.Client
+}
+
+// NewOTLPSink creates an OTLP/HTTP log sink for the collector at endpoint
+func NewOTLPSink(endpoint string) *OTLPSink {
+	return &OTLPSink{
+		Endpoint:    endpoint,
+		ServiceName: "swarm-operator",
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send implements Sink
+func (s *OTLPSink) Send(ctx context.Context, records []Record) error {
+	body, err := OTLPPayload(s.ServiceName, records)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.HTTPClient, s.Endpoint+"/v1/logs", "", body)
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// OTLPPayload renders records as an ExportLogsServiceRequest. The full record
+// is the log body; the fields used for filtering are also attributes.
+func OTLPPayload(serviceName string, records []Record) ([]byte, error) {
+	logRecords := make([]map[string]interface{}, 0, len(records))
+	for _, rec := range records {
+		body, err := json.Marshal(rec)
+		if err != nil {
+			return nil, err
+		}
+		attrs := []otlpAttribute{
+			{Key: "audit.controller", Value: otlpValue{rec.Controller}},
+			{Key: "audit.verb", Value: otlpValue{rec.Verb}},
+			{Key: "k8s.object.kind", Value: otlpValue{rec.Object.Kind}},
+			{Key: "k8s.namespace.name", Value: otlpValue{rec.Object.Namespace}},
+			{Key: "k8s.object.name", Value: otlpValue{rec.Object.Name}},
+		}
+		severity := "INFO"
+		if rec.Error != "" {
+			severity = "WARN"
+		}
+		logRecords = append(logRecords, map[string]interface{}{
+			"timeUnixNano": strconv.FormatInt(rec.Time.UnixNano(), 10),
+			"severityText": severity,
+			"body":         otlpValue{string(body)},
+			"attributes":   attrs,
+		})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"resourceLogs": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpAttribute{{Key: "service.name", Value: otlpValue{serviceName}}},
+				},
+				"scopeLogs": []interface{}{
+					map[string]interface{}{
+						"scope":      map[string]string{"name": "swarm-operator/audit"},
+						"logRecords": logRecords,
+					},
+				},
+			},
+		},
+	})
+}
+
+func post(ctx context.Context, httpClient *http.Client, url, token string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit sink returned %s", resp.Status)
+	}
+	return nil
+}
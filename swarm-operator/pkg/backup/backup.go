@@ -0,0 +1,615 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup captures a SwarmCluster with its agents, memory stores,
+// memory entries and in-flight tasks in a portable archive, and restores
+// such an archive into the same or another Kubernetes cluster, optionally
+// remapping namespaces. Secrets are only recorded by reference.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// FormatVersion is bumped on incompatible changes to the archive layout
+	FormatVersion = "v1"
+
+	// SnapshotPath is the memory service endpoint that returns (GET) and
+	// replaces (PUT) the SQLite database of a memory store
+	SnapshotPath = "/v1/snapshot"
+
+	// ClusterLabel is set on the agents of a SwarmCluster
+	ClusterLabel = "swarm-cluster"
+
+	manifestFile = "manifest.json"
+	objectsDir   = "objects/"
+	snapshotsDir = "snapshots/"
+
+	// storePollInterval is how often a restore checks whether a memory
+	// store is ready for its snapshot
+	storePollInterval = 2 * time.Second
+)
+
+// Kinds in the order they are restored
+const (
+	KindSwarmCluster     = "SwarmCluster"
+	KindSwarmMemoryStore = "SwarmMemoryStore"
+	KindSwarmMemory      = "SwarmMemory"
+	KindAgent            = "Agent"
+	KindSwarmTask        = "SwarmTask"
+)
+
+// Manifest describes the content of an archive
+type Manifest struct {
+	Version    string      `json:"version"`
+	Cluster    string      `json:"cluster"`
+	Namespace  string      `json:"namespace"`
+	ExportedAt metav1.Time `json:"exportedAt"`
+
+	// Objects lists the archived objects in the order they are restored
+	Objects []ObjectRef `json:"objects"`
+
+	// Snapshots lists the memory store databases in the archive
+	Snapshots []SnapshotRef `json:"snapshots,omitempty"`
+
+	// SecretRefs are the Secrets the archived objects reference. Their
+	// content is never exported, they must exist before the restore.
+	SecretRefs []SecretRef `json:"secretRefs,omitempty"`
+}
+
+// ObjectRef locates an archived object
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	File      string `json:"file"`
+
+	// Owned is true when the SwarmCluster controlled the object. The owner
+	// reference is recreated on restore.
+	Owned bool `json:"owned,omitempty"`
+}
+
+// SnapshotRef locates the database snapshot of a memory store
+type SnapshotRef struct {
+	Namespace string `json:"namespace"`
+	Store     string `json:"store"`
+	File      string `json:"file"`
+}
+
+// SecretRef names a Secret an archived object depends on
+type SecretRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Archive is an exported SwarmCluster
+type Archive struct {
+	Manifest Manifest
+
+	// Objects and Snapshots are keyed by their file in the archive
+	Objects   map[string]*unstructured.Unstructured
+	Snapshots map[string][]byte
+}
+
+// Snapshotter reads and replaces the database of a memory store
+type Snapshotter interface {
+	Snapshot(ctx context.Context, store *swarmv1alpha1.SwarmMemoryStore) (io.ReadCloser, error)
+	Restore(ctx context.Context, store *swarmv1alpha1.SwarmMemoryStore, data io.Reader) error
+}
+
+// Export captures the named SwarmCluster. Memory store databases are
+// included when snapshots is not nil.
+func Export(ctx context.Context, c client.Client, namespace, name string, snapshots Snapshotter) (*Archive, error) {
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, cluster); err != nil {
+		return nil, err
+	}
+
+	a := &Archive{
+		Manifest: Manifest{
+			Version:    FormatVersion,
+			Cluster:    name,
+			Namespace:  namespace,
+			ExportedAt: metav1.Now(),
+		},
+		Objects:   map[string]*unstructured.Unstructured{},
+		Snapshots: map[string][]byte{},
+	}
+	secrets := map[SecretRef]bool{}
+	if err := a.add(KindSwarmCluster, cluster, cluster.UID, secrets); err != nil {
+		return nil, err
+	}
+
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := c.List(ctx, stores, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range stores.Items {
+		store := &stores.Items[i]
+		if store.Spec.SwarmClusterRef != name {
+			continue
+		}
+		if err := a.add(KindSwarmMemoryStore, store, cluster.UID, secrets); err != nil {
+			return nil, err
+		}
+		if snapshots != nil {
+			if err := a.addSnapshot(ctx, snapshots, store); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	memories := &swarmv1alpha1.SwarmMemoryList{}
+	if err := c.List(ctx, memories, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range memories.Items {
+		if memories.Items[i].Spec.ClusterRef != name {
+			continue
+		}
+		if err := a.add(KindSwarmMemory, &memories.Items[i], cluster.UID, secrets); err != nil {
+			return nil, err
+		}
+	}
+
+	agents := &swarmv1alpha1.AgentList{}
+	if err := c.List(ctx, agents, client.InNamespace(namespace), client.MatchingLabels{ClusterLabel: name}); err != nil {
+		return nil, err
+	}
+	for i := range agents.Items {
+		if err := a.add(KindAgent, &agents.Items[i], cluster.UID, secrets); err != nil {
+			return nil, err
+		}
+	}
+
+	// Finished tasks live on in the task archive, only in-flight work is
+	// carried over
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := c.List(ctx, tasks, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range tasks.Items {
+		task := &tasks.Items[i]
+		if task.Spec.SwarmCluster != name || taskFinished(task) {
+			continue
+		}
+		if err := a.add(KindSwarmTask, task, cluster.UID, secrets); err != nil {
+			return nil, err
+		}
+	}
+
+	for ref := range secrets {
+		a.Manifest.SecretRefs = append(a.Manifest.SecretRefs, ref)
+	}
+	sortSecretRefs(a.Manifest.SecretRefs)
+	return a, nil
+}
+
+// taskFinished reports whether the task reached a final phase
+func taskFinished(task *swarmv1alpha1.SwarmTask) bool {
+	switch task.Status.Phase {
+	case "Completed", "Failed", "DeadLettered", "Cancelled":
+		return true
+	}
+	return false
+}
+
+// add archives the spec and identifying metadata of an object. Status,
+// owner references and server-set fields are dropped.
+func (a *Archive) add(kind string, obj client.Object, clusterUID types.UID, secrets map[SecretRef]bool) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", kind, obj.GetName(), err)
+	}
+	delete(content, "status")
+	metadata := map[string]interface{}{
+		"name":      obj.GetName(),
+		"namespace": obj.GetNamespace(),
+	}
+	if labels := obj.GetLabels(); len(labels) > 0 {
+		metadata["labels"] = content["metadata"].(map[string]interface{})["labels"]
+	}
+	if annotations := obj.GetAnnotations(); len(annotations) > 0 {
+		metadata["annotations"] = content["metadata"].(map[string]interface{})["annotations"]
+	}
+	content["metadata"] = metadata
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(swarmv1alpha1.GroupVersion.String())
+	u.SetKind(kind)
+
+	ref := ObjectRef{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		File:      fmt.Sprintf("%s%03d-%s-%s.json", objectsDir, len(a.Manifest.Objects), strings.ToLower(kind), obj.GetName()),
+	}
+	if owner := metav1.GetControllerOf(obj); owner != nil && kind != KindSwarmCluster {
+		ref.Owned = owner.UID == clusterUID
+	}
+	a.Manifest.Objects = append(a.Manifest.Objects, ref)
+	a.Objects[ref.File] = u
+
+	collectSecretRefs(content["spec"], obj.GetNamespace(), secrets)
+	return nil
+}
+
+// addSnapshot archives the database of a memory store
+func (a *Archive) addSnapshot(ctx context.Context, snapshots Snapshotter, store *swarmv1alpha1.SwarmMemoryStore) error {
+	reader, err := snapshots.Snapshot(ctx, store)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot memory store %s: %w", store.Name, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot of memory store %s: %w", store.Name, err)
+	}
+
+	ref := SnapshotRef{
+		Namespace: store.Namespace,
+		Store:     store.Name,
+		File:      fmt.Sprintf("%s%s.db", snapshotsDir, store.Name),
+	}
+	a.Manifest.Snapshots = append(a.Manifest.Snapshots, ref)
+	a.Snapshots[ref.File] = data
+	return nil
+}
+
+// collectSecretRefs finds the Secrets a spec references: fields ending in
+// secretName, secretRef or keyRef, and lists ending in secrets
+func collectSecretRefs(value interface{}, namespace string, secrets map[SecretRef]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			lower := strings.ToLower(key)
+			switch f := field.(type) {
+			case string:
+				if strings.HasSuffix(lower, "secretname") && f != "" {
+					secrets[SecretRef{Namespace: namespace, Name: f}] = true
+				}
+			case map[string]interface{}:
+				if strings.HasSuffix(lower, "secretref") || strings.HasSuffix(lower, "keyref") {
+					addSecretRef(f, namespace, secrets)
+				}
+			case []interface{}:
+				if strings.HasSuffix(lower, "secrets") {
+					for _, item := range f {
+						if ref, ok := item.(map[string]interface{}); ok {
+							addSecretRef(ref, namespace, secrets)
+						}
+					}
+				}
+			}
+			collectSecretRefs(field, namespace, secrets)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectSecretRefs(item, namespace, secrets)
+		}
+	}
+}
+
+func addSecretRef(ref map[string]interface{}, namespace string, secrets map[SecretRef]bool) {
+	name, _ := ref["name"].(string)
+	if name == "" {
+		return
+	}
+	if ns, _ := ref["namespace"].(string); ns != "" {
+		namespace = ns
+	}
+	secrets[SecretRef{Namespace: namespace, Name: name}] = true
+}
+
+func sortSecretRefs(refs []SecretRef) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Namespace != refs[j].Namespace {
+			return refs[i].Namespace < refs[j].Namespace
+		}
+		return refs[i].Name < refs[j].Name
+	})
+}
+
+// Remap moves the archived objects to other namespaces. Besides the object
+// namespaces it rewrites every namespace-valued spec field: namespace,
+// fields ending in Namespace and lists ending in Namespaces.
+func (a *Archive) Remap(mapping map[string]string) {
+	if len(mapping) == 0 {
+		return
+	}
+	remap := func(namespace string) string {
+		if to, ok := mapping[namespace]; ok {
+			return to
+		}
+		return namespace
+	}
+
+	a.Manifest.Namespace = remap(a.Manifest.Namespace)
+	for i, ref := range a.Manifest.Objects {
+		a.Manifest.Objects[i].Namespace = remap(ref.Namespace)
+		obj := a.Objects[ref.File]
+		obj.SetNamespace(remap(obj.GetNamespace()))
+
+		spec, ok := obj.Object["spec"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// The namespace of a memory entry is a memory namespace, not a
+		// Kubernetes one
+		memoryNamespace, keep := spec["namespace"]
+		remapNamespaces(spec, remap)
+		if ref.Kind == KindSwarmMemory && keep {
+			spec["namespace"] = memoryNamespace
+		}
+	}
+	for i, ref := range a.Manifest.Snapshots {
+		a.Manifest.Snapshots[i].Namespace = remap(ref.Namespace)
+	}
+	for i, ref := range a.Manifest.SecretRefs {
+		a.Manifest.SecretRefs[i].Namespace = remap(ref.Namespace)
+	}
+	sortSecretRefs(a.Manifest.SecretRefs)
+}
+
+func remapNamespaces(value interface{}, remap func(string) string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			switch f := field.(type) {
+			case string:
+				if key == "namespace" || strings.HasSuffix(key, "Namespace") {
+					v[key] = remap(f)
+				}
+			case []interface{}:
+				if key == "namespaces" || strings.HasSuffix(key, "Namespaces") {
+					for i, item := range f {
+						if ns, ok := item.(string); ok {
+							f[i] = remap(ns)
+						}
+					}
+				}
+			}
+			remapNamespaces(field, remap)
+		}
+	case []interface{}:
+		for _, item := range v {
+			remapNamespaces(item, remap)
+		}
+	}
+}
+
+// Write stores the archive as a gzipped tarball
+func (a *Archive) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	writeFile := func(name string, data []byte) error {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: a.Manifest.ExportedAt.Time,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(a.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFile(manifestFile, manifest); err != nil {
+		return err
+	}
+	for _, ref := range a.Manifest.Objects {
+		data, err := json.MarshalIndent(a.Objects[ref.File].Object, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFile(ref.File, data); err != nil {
+			return err
+		}
+	}
+	for _, ref := range a.Manifest.Snapshots {
+		if err := writeFile(ref.File, a.Snapshots[ref.File]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Read loads an archive written by Write
+func Read(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a swarm backup: %w", err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		files[header.Name] = data
+	}
+
+	a := &Archive{
+		Objects:   map[string]*unstructured.Unstructured{},
+		Snapshots: map[string][]byte{},
+	}
+	manifest, ok := files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("not a swarm backup: %s is missing", manifestFile)
+	}
+	if err := json.Unmarshal(manifest, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", manifestFile, err)
+	}
+	if a.Manifest.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %q, expected %q", a.Manifest.Version, FormatVersion)
+	}
+
+	for _, ref := range a.Manifest.Objects {
+		data, ok := files[ref.File]
+		if !ok {
+			return nil, fmt.Errorf("archive is missing %s", ref.File)
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", ref.File, err)
+		}
+		a.Objects[ref.File] = obj
+	}
+	for _, ref := range a.Manifest.Snapshots {
+		data, ok := files[ref.File]
+		if !ok {
+			return nil, fmt.Errorf("archive is missing %s", ref.File)
+		}
+		a.Snapshots[ref.File] = data
+	}
+	return a, nil
+}
+
+// ImportOptions control a restore
+type ImportOptions struct {
+	// Snapshots restores the memory store databases, which are skipped
+	// when nil
+	Snapshots Snapshotter
+
+	// Timeout bounds the wait for each memory store to become ready for
+	// its snapshot
+	Timeout time.Duration
+}
+
+// ImportResult reports what a restore did
+type ImportResult struct {
+	Created []ObjectRef
+	// Existing objects are left untouched
+	Existing          []ObjectRef
+	RestoredSnapshots []SnapshotRef
+	// MissingSecrets must be created for the restored swarm to work
+	MissingSecrets []SecretRef
+}
+
+// Import creates the archived objects, leaving existing ones alone, and
+// restores the memory store databases once the stores are ready
+func Import(ctx context.Context, c client.Client, a *Archive, opts ImportOptions) (*ImportResult, error) {
+	result := &ImportResult{}
+
+	for _, ref := range a.Manifest.SecretRefs {
+		err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &corev1.Secret{})
+		if errors.IsNotFound(err) {
+			result.MissingSecrets = append(result.MissingSecrets, ref)
+		} else if err != nil {
+			return result, err
+		}
+	}
+
+	var cluster *unstructured.Unstructured
+	for _, ref := range a.Manifest.Objects {
+		obj := a.Objects[ref.File].DeepCopy()
+		if ref.Owned && cluster != nil {
+			obj.SetOwnerReferences([]metav1.OwnerReference{
+				*metav1.NewControllerRef(cluster, swarmv1alpha1.GroupVersion.WithKind(KindSwarmCluster)),
+			})
+		}
+
+		err := c.Create(ctx, obj)
+		switch {
+		case errors.IsAlreadyExists(err):
+			result.Existing = append(result.Existing, ref)
+			if ref.Kind == KindSwarmCluster {
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+					return result, err
+				}
+			}
+		case err != nil:
+			return result, fmt.Errorf("failed to create %s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+		default:
+			result.Created = append(result.Created, ref)
+		}
+		if ref.Kind == KindSwarmCluster {
+			cluster = obj
+		}
+	}
+
+	if opts.Snapshots == nil {
+		return result, nil
+	}
+	for _, ref := range a.Manifest.Snapshots {
+		store, err := waitForStore(ctx, c, ref, opts.Timeout)
+		if err != nil {
+			return result, err
+		}
+		if err := opts.Snapshots.Restore(ctx, store, bytes.NewReader(a.Snapshots[ref.File])); err != nil {
+			return result, fmt.Errorf("failed to restore memory store %s/%s: %w", ref.Namespace, ref.Store, err)
+		}
+		result.RestoredSnapshots = append(result.RestoredSnapshots, ref)
+	}
+	return result, nil
+}
+
+// waitForStore waits until the memory store serves its HTTP endpoint
+func waitForStore(ctx context.Context, c client.Client, ref SnapshotRef, timeout time.Duration) (*swarmv1alpha1.SwarmMemoryStore, error) {
+	store := &swarmv1alpha1.SwarmMemoryStore{}
+	err := wait.PollUntilContextTimeout(ctx, storePollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Store}, store); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return store.Status.Phase == "Ready" && store.Status.Endpoints.HTTP != "", nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("memory store %s/%s did not become ready: %w", ref.Namespace, ref.Store, err)
+	}
+	return store, nil
+}
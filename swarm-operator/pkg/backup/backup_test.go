@@ -0,0 +1,199 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+func TestBackup(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Backup Suite")
+}
+
+type snapshotterStub struct {
+	databases map[string]string
+}
+
+func (s *snapshotterStub) Snapshot(_ context.Context, store *swarmv1alpha1.SwarmMemoryStore) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewBufferString(s.databases[store.Namespace+"/"+store.Name])), nil
+}
+
+func (s *snapshotterStub) Restore(_ context.Context, store *swarmv1alpha1.SwarmMemoryStore, data io.Reader) error {
+	content, err := io.ReadAll(data)
+	s.databases[store.Namespace+"/"+store.Name] = string(content)
+	return err
+}
+
+var _ = Describe("Swarm backup", func() {
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+	)
+
+	newClient := func(objects ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	}
+
+	source := func() []client.Object {
+		controller := true
+		cluster := &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "swarm", Namespace: "prod", UID: "cluster-uid"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Topology:        swarmv1alpha1.MeshTopology,
+				NamespaceConfig: &swarmv1alpha1.NamespaceConfig{SwarmNamespace: "prod"},
+			},
+		}
+		agent := &swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "swarm-coder-0",
+				Namespace: "prod",
+				Labels:    map[string]string{ClusterLabel: "swarm"},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: swarmv1alpha1.GroupVersion.String(),
+					Kind:       KindSwarmCluster,
+					Name:       "swarm",
+					UID:        "cluster-uid",
+					Controller: &controller,
+				}},
+			},
+			Spec: swarmv1alpha1.AgentSpec{Type: swarmv1alpha1.CoderAgent, SwarmCluster: "swarm"},
+		}
+		store := &swarmv1alpha1.SwarmMemoryStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "memory", Namespace: "prod"},
+			Spec:       swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "swarm", TLSSecretName: "memory-tls"},
+			Status:     swarmv1alpha1.SwarmMemoryStoreStatus{Phase: "Ready"},
+		}
+		memory := &swarmv1alpha1.SwarmMemory{
+			ObjectMeta: metav1.ObjectMeta{Name: "notes", Namespace: "prod"},
+			Spec:       swarmv1alpha1.SwarmMemorySpec{ClusterRef: "swarm", Namespace: "prod", Key: "notes", Value: "v"},
+		}
+		running := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "prod"},
+			Spec: swarmv1alpha1.SwarmTaskSpec{
+				SwarmCluster: "swarm",
+				GitHubApp: &swarmv1alpha1.GitHubAppConfig{
+					AppID:         1,
+					PrivateKeyRef: swarmv1alpha1.SecretKeyRef{Name: "github-app", Key: "key"},
+				},
+			},
+			Status: swarmv1alpha1.SwarmTaskStatus{Phase: "Running"},
+		}
+		finished := &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: "finished", Namespace: "prod"},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "swarm"},
+			Status:     swarmv1alpha1.SwarmTaskStatus{Phase: "Completed"},
+		}
+		return []client.Object{cluster, agent, store, memory, running, finished}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("captures the cluster with its in-flight work and secret references", func() {
+		snapshots := &snapshotterStub{databases: map[string]string{"prod/memory": "sqlite"}}
+		a, err := Export(ctx, newClient(source()...), "prod", "swarm", snapshots)
+		Expect(err).NotTo(HaveOccurred())
+
+		var kinds []string
+		for _, ref := range a.Manifest.Objects {
+			kinds = append(kinds, ref.Kind+"/"+ref.Name)
+		}
+		Expect(kinds).To(Equal([]string{"SwarmCluster/swarm", "SwarmMemoryStore/memory", "SwarmMemory/notes", "Agent/swarm-coder-0", "SwarmTask/running"}))
+		Expect(a.Manifest.Objects[3].Owned).To(BeTrue())
+		Expect(a.Manifest.SecretRefs).To(Equal([]SecretRef{{Namespace: "prod", Name: "github-app"}, {Namespace: "prod", Name: "memory-tls"}}))
+
+		task := a.Objects[a.Manifest.Objects[4].File]
+		Expect(task.Object).NotTo(HaveKey("status"))
+		Expect(task.GetAPIVersion()).To(Equal("swarm.claudeflow.io/v1alpha1"))
+		Expect(task.GetOwnerReferences()).To(BeEmpty())
+		Expect(a.Snapshots).To(HaveKeyWithValue("snapshots/memory.db", []byte("sqlite")))
+	})
+
+	It("restores into another namespace", func() {
+		exported, err := Export(ctx, newClient(source()...), "prod", "swarm",
+			&snapshotterStub{databases: map[string]string{"prod/memory": "sqlite"}})
+		Expect(err).NotTo(HaveOccurred())
+		buf := &bytes.Buffer{}
+		Expect(exported.Write(buf)).To(Succeed())
+
+		a, err := Read(buf)
+		Expect(err).NotTo(HaveOccurred())
+		a.Remap(map[string]string{"prod": "dr"})
+
+		// The store already runs in the target, so its snapshot can be
+		// restored right away
+		target := newClient(
+			&swarmv1alpha1.SwarmMemoryStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "memory", Namespace: "dr"},
+				Status: swarmv1alpha1.SwarmMemoryStoreStatus{
+					Phase:     "Ready",
+					Endpoints: swarmv1alpha1.SwarmMemoryEndpoints{HTTP: "http://memory.dr.svc:8080"},
+				},
+			},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "github-app", Namespace: "dr"}},
+		)
+		restored := &snapshotterStub{databases: map[string]string{}}
+		result, err := Import(ctx, target, a, ImportOptions{Snapshots: restored, Timeout: time.Second})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.Created).To(HaveLen(4))
+		Expect(result.Existing).To(ConsistOf(HaveField("Kind", KindSwarmMemoryStore)))
+		Expect(result.MissingSecrets).To(Equal([]SecretRef{{Namespace: "dr", Name: "memory-tls"}}))
+		Expect(restored.databases).To(HaveKeyWithValue("dr/memory", "sqlite"))
+
+		cluster := &swarmv1alpha1.SwarmCluster{}
+		Expect(target.Get(ctx, types.NamespacedName{Name: "swarm", Namespace: "dr"}, cluster)).To(Succeed())
+		Expect(cluster.Spec.NamespaceConfig.SwarmNamespace).To(Equal("dr"))
+
+		agent := &swarmv1alpha1.Agent{}
+		Expect(target.Get(ctx, types.NamespacedName{Name: "swarm-coder-0", Namespace: "dr"}, agent)).To(Succeed())
+		Expect(metav1.GetControllerOf(agent).Name).To(Equal("swarm"))
+
+		// Memory namespaces are not Kubernetes namespaces
+		memory := &swarmv1alpha1.SwarmMemory{}
+		Expect(target.Get(ctx, types.NamespacedName{Name: "notes", Namespace: "dr"}, memory)).To(Succeed())
+		Expect(memory.Spec.Namespace).To(Equal("prod"))
+	})
+
+	It("rejects archives of an unknown version", func() {
+		a := &Archive{Manifest: Manifest{Version: "v0"}}
+		buf := &bytes.Buffer{}
+		Expect(a.Write(buf)).To(Succeed())
+		_, err := Read(buf)
+		Expect(err).To(MatchError(ContainSubstring("unsupported backup version")))
+	})
+})
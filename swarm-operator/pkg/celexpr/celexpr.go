@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package celexpr compiles the CEL expressions users write in the operator's
+// APIs, e.g. scheduling policies and computed environment variables. Each
+// API declares the variables its expressions see and the result types it
+// accepts; compiled programs are cached and cost limited the same way.
+package celexpr
+
+import (
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// maxCachedPrograms bounds the compiled program cache of an environment
+const maxCachedPrograms = 512
+
+// CheckFunc vets the output type of a compiled expression, e.g. "bool" or
+// "dyn", before its program is used
+type CheckFunc func(outputType string) error
+
+type cached struct {
+	program    cel.Program
+	outputType string
+}
+
+// Env compiles expressions over a fixed set of variables
+type Env struct {
+	costLimit uint64
+	variables []cel.EnvOption
+
+	once sync.Once
+	env  *cel.Env
+	err  error
+
+	mu       sync.Mutex
+	programs map[string]cached
+}
+
+// NewEnv declares the variables expressions see. costLimit bounds the
+// runtime cost of a single evaluation so a runaway comprehension cannot
+// stall the caller. The CEL environment is built on first use.
+func NewEnv(costLimit uint64, variables ...cel.EnvOption) *Env {
+	return &Env{
+		costLimit: costLimit,
+		variables: variables,
+		programs:  map[string]cached{},
+	}
+}
+
+// Compile returns the program of the expression, compiling it on first use.
+// check is run against the output type on every call, so callers accepting
+// different types for the same expression share the cache.
+func (e *Env) Compile(expression string, check CheckFunc) (cel.Program, error) {
+	e.mu.Lock()
+	entry, ok := e.programs[expression]
+	e.mu.Unlock()
+	if !ok {
+		var err error
+		if entry, err = e.compile(expression); err != nil {
+			return nil, err
+		}
+	}
+	if check != nil {
+		if err := check(entry.outputType); err != nil {
+			return nil, err
+		}
+	}
+	return entry.program, nil
+}
+
+func (e *Env) compile(expression string) (cached, error) {
+	e.once.Do(func() {
+		e.env, e.err = cel.NewEnv(e.variables...)
+	})
+	if e.err != nil {
+		return cached{}, e.err
+	}
+
+	ast, issues := e.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return cached{}, issues.Err()
+	}
+	program, err := e.env.Program(ast, cel.CostLimit(e.costLimit))
+	if err != nil {
+		return cached{}, err
+	}
+	entry := cached{program: program, outputType: ast.OutputType().String()}
+
+	e.mu.Lock()
+	if len(e.programs) >= maxCachedPrograms {
+		e.programs = map[string]cached{}
+	}
+	e.programs[expression] = entry
+	e.mu.Unlock()
+	return entry, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celexpr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCELExpr(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CEL Expression Suite")
+}
+
+var _ = Describe("Env", func() {
+	var env *Env
+
+	BeforeEach(func() {
+		env = NewEnv(1000, cel.Variable("items", cel.ListType(cel.IntType)))
+	})
+
+	It("checks the output type of cached programs on every compile", func() {
+		boolOnly := func(outputType string) error {
+			if outputType != "bool" {
+				return errors.New("not a bool")
+			}
+			return nil
+		}
+
+		program, err := env.Compile("items.size()", nil)
+		Expect(err).NotTo(HaveOccurred())
+		out, _, err := program.Eval(map[string]interface{}{"items": []int64{1, 2}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Value()).To(Equal(int64(2)))
+
+		_, err = env.Compile("items.size()", boolOnly)
+		Expect(err).To(MatchError("not a bool"))
+		Expect(env.programs).To(HaveLen(1))
+	})
+
+	It("rejects undeclared variables", func() {
+		_, err := env.Compile("agent.name", nil)
+		Expect(err).To(MatchError(ContainSubstring("undeclared reference")))
+	})
+
+	It("stops evaluations past the cost limit", func() {
+		program, err := env.Compile("items.all(a, items.all(b, items.all(c, a + b + c >= 0)))", nil)
+		Expect(err).NotTo(HaveOccurred())
+		items := make([]int64, 50)
+		_, _, err = program.Eval(map[string]interface{}{"items": items})
+		Expect(err).To(MatchError(ContainSubstring("cost limit")))
+	})
+})
@@ -0,0 +1,225 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package circuitbreaker stops reconcilers from hammering an external
+// dependency that keeps failing. Each dependency gets its own breaker which
+// opens after consecutive failures, stays open for an exponentially growing
+// backoff and then lets a single probe call through to decide whether to
+// close again.
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State of a circuit breaker
+type State string
+
+const (
+	// Closed lets every call through
+	Closed State = "closed"
+	// Open rejects calls until the backoff elapses
+	Open State = "open"
+	// HalfOpen lets a single probe call through
+	HalfOpen State = "half-open"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultBaseBackoff      = 5 * time.Second
+	defaultMaxBackoff       = 5 * time.Minute
+)
+
+// ErrOpen is matched by errors returned for calls rejected by an open breaker
+var ErrOpen = errors.New("circuit breaker open")
+
+// OpenError is returned for calls rejected by an open breaker
+type OpenError struct {
+	Dependency string
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuit breaker for %s is open, retrying in %s", e.Dependency, e.RetryAfter.Round(time.Second))
+}
+
+// Is makes errors.Is(err, ErrOpen) match
+func (e *OpenError) Is(target error) bool {
+	return target == ErrOpen
+}
+
+// Breaker guards calls to a single external dependency
+type Breaker struct {
+	Name string
+
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+
+	// BaseBackoff is how long the breaker first stays open, doubled on every
+	// failed probe up to MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+
+	// OnStateChange is called with the new state on every transition
+	OnStateChange func(dependency string, state State)
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	trips     int
+	openUntil time.Time
+	probing   bool
+
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// New creates a closed breaker with default thresholds
+func New(name string) *Breaker {
+	return &Breaker{
+		Name:             name,
+		FailureThreshold: defaultFailureThreshold,
+		BaseBackoff:      defaultBaseBackoff,
+		MaxBackoff:       defaultMaxBackoff,
+		state:            Closed,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a call may proceed. When the backoff of an open
+// breaker has elapsed the breaker turns half-open and admits one probe.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if wait := b.openUntil.Sub(b.now()); wait > 0 {
+			return &OpenError{Dependency: b.Name, RetryAfter: wait}
+		}
+		b.setState(HalfOpen)
+		b.probing = true
+		return nil
+	case HalfOpen:
+		if b.probing {
+			return &OpenError{Dependency: b.Name, RetryAfter: b.BaseBackoff}
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record reports the outcome of an admitted call
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		b.trips = 0
+		b.setState(Closed)
+		return
+	}
+
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.FailureThreshold {
+		b.trip()
+	}
+}
+
+// Do runs fn if the breaker allows it and records the result
+func (b *Breaker) Do(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.Record(err)
+	return err
+}
+
+// State returns the current state
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) trip() {
+	b.trips++
+	backoff := b.BaseBackoff
+	for i := 1; i < b.trips && backoff < b.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > b.MaxBackoff {
+		backoff = b.MaxBackoff
+	}
+	b.openUntil = b.now().Add(backoff)
+	b.setState(Open)
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	if b.OnStateChange != nil {
+		b.OnStateChange(b.Name, state)
+	}
+}
+
+// Registry hands out one breaker per dependency name
+type Registry struct {
+	FailureThreshold int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	OnStateChange    func(dependency string, state State)
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewRegistry creates a registry whose breakers use the default thresholds
+func NewRegistry() *Registry {
+	return &Registry{
+		FailureThreshold: defaultFailureThreshold,
+		BaseBackoff:      defaultBaseBackoff,
+		MaxBackoff:       defaultMaxBackoff,
+		breakers:         map[string]*Breaker{},
+	}
+}
+
+// Get returns the breaker of a dependency, creating it on first use
+func (r *Registry) Get(dependency string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.breakers[dependency]; ok {
+		return b
+	}
+	b := New(dependency)
+	b.FailureThreshold = r.FailureThreshold
+	b.BaseBackoff = r.BaseBackoff
+	b.MaxBackoff = r.MaxBackoff
+	b.OnStateChange = r.OnStateChange
+	r.breakers[dependency] = b
+	return b
+}
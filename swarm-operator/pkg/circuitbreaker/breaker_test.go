@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Circuit Breaker Suite")
+}
+
+var _ = Describe("Breaker", func() {
+	var (
+		b       *Breaker
+		now     time.Time
+		changes []State
+		failure = errors.New("boom")
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		changes = nil
+		b = New("github")
+		b.FailureThreshold = 3
+		b.BaseBackoff = 10 * time.Second
+		b.MaxBackoff = 30 * time.Second
+		b.OnStateChange = func(_ string, s State) { changes = append(changes, s) }
+		b.now = func() time.Time { return now }
+	})
+
+	fail := func(n int) {
+		for i := 0; i < n; i++ {
+			Expect(b.Allow()).To(Succeed())
+			b.Record(failure)
+		}
+	}
+
+	It("should open after consecutive failures", func() {
+		fail(2)
+		Expect(b.State()).To(Equal(Closed))
+		fail(1)
+		Expect(b.State()).To(Equal(Open))
+
+		err := b.Allow()
+		Expect(errors.Is(err, ErrOpen)).To(BeTrue())
+		var openErr *OpenError
+		Expect(errors.As(err, &openErr)).To(BeTrue())
+		Expect(openErr.RetryAfter).To(Equal(10 * time.Second))
+	})
+
+	It("should reset the failure count on success", func() {
+		fail(2)
+		Expect(b.Do(func() error { return nil })).To(Succeed())
+		fail(2)
+		Expect(b.State()).To(Equal(Closed))
+	})
+
+	It("should admit a single probe once the backoff elapses", func() {
+		fail(3)
+		now = now.Add(11 * time.Second)
+
+		Expect(b.Allow()).To(Succeed())
+		Expect(b.State()).To(Equal(HalfOpen))
+		Expect(errors.Is(b.Allow(), ErrOpen)).To(BeTrue())
+
+		b.Record(nil)
+		Expect(b.State()).To(Equal(Closed))
+		Expect(changes).To(Equal([]State{Open, HalfOpen, Closed}))
+	})
+
+	It("should double the backoff on failed probes up to the maximum", func() {
+		fail(3)
+		now = now.Add(11 * time.Second)
+		fail(1)
+
+		var openErr *OpenError
+		Expect(errors.As(b.Allow(), &openErr)).To(BeTrue())
+		Expect(openErr.RetryAfter).To(Equal(20 * time.Second))
+
+		now = now.Add(21 * time.Second)
+		fail(1)
+		Expect(errors.As(b.Allow(), &openErr)).To(BeTrue())
+		Expect(openErr.RetryAfter).To(Equal(30 * time.Second))
+	})
+})
+
+var _ = Describe("Registry", func() {
+	It("should keep one breaker per dependency", func() {
+		r := NewRegistry()
+		Expect(r.Get("github")).To(BeIdenticalTo(r.Get("github")))
+		Expect(r.Get("github")).NotTo(BeIdenticalTo(r.Get("slack")))
+	})
+})
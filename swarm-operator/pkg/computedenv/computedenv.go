@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package computedenv evaluates the CEL expressions of task environment
+// variables that are computed when the task's Job is built, e.g.
+//
+//	memory.status.endpoints.http + "/v1"
+//
+// The expressions see the task, its cluster and the cluster's memory store
+// as the objects stored in the API server, with metadata, spec and status.
+package computedenv
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/claude-flow/swarm-operator/pkg/celexpr"
+)
+
+// Variables are the objects an expression sees. Memory is nil for clusters
+// without a memory store, which expressions see as null.
+type Variables struct {
+	Task    map[string]interface{}
+	Cluster map[string]interface{}
+	Memory  map[string]interface{}
+}
+
+// env is shared by every computed variable. The cost limit of a single
+// evaluation keeps a runaway comprehension from stalling building Jobs.
+var env = celexpr.NewEnv(100000,
+	cel.Variable("task", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("cluster", cel.MapType(cel.StringType, cel.DynType)),
+	// Dyn rather than a map so expressions can compare it to null
+	cel.Variable("memory", cel.DynType),
+)
+
+// Check compiles the expression and verifies that it evaluates to a value
+// an environment variable can hold. It is used at admission.
+func Check(expression string) error {
+	_, err := compile(expression)
+	return err
+}
+
+func compile(expression string) (cel.Program, error) {
+	return env.Compile(expression, func(outputType string) error {
+		switch outputType {
+		case "string", "int", "uint", "double", "bool", "dyn":
+			return nil
+		}
+		return fmt.Errorf("expression must evaluate to a string, number or bool, got %s", outputType)
+	})
+}
+
+// Evaluate runs the expression against the variables and formats its
+// result as the value of an environment variable
+func Evaluate(expression string, vars Variables) (string, error) {
+	program, err := compile(expression)
+	if err != nil {
+		return "", err
+	}
+	activation := map[string]interface{}{"task": vars.Task, "cluster": vars.Cluster, "memory": nil}
+	if vars.Memory != nil {
+		activation["memory"] = vars.Memory
+	}
+	out, _, err := program.Eval(activation)
+	if err != nil {
+		return "", err
+	}
+	switch value := out.Value().(type) {
+	case string:
+		return value, nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case uint64:
+		return strconv.FormatUint(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	default:
+		return "", fmt.Errorf("expression evaluated to %s, not a string, number or bool", out.Type().TypeName())
+	}
+}
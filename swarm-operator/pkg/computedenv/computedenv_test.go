@@ -0,0 +1,89 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package computedenv
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestComputedEnv(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Computed Env Suite")
+}
+
+var _ = Describe("Computed environment variables", func() {
+	vars := Variables{
+		Task: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "build", "namespace": "team-a"},
+			"spec":     map[string]interface{}{"priority": "high", "parameters": map[string]interface{}{"shards": "4"}},
+		},
+		Cluster: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "swarm"},
+			"status":   map[string]interface{}{"readyAgents": int64(3)},
+		},
+		Memory: map[string]interface{}{
+			"status": map[string]interface{}{"endpoints": map[string]interface{}{"http": "http://swarm-memory.swarm-system.svc:8080"}},
+		},
+	}
+
+	It("reads the task, cluster and memory store", func() {
+		value, err := Evaluate(`memory.status.endpoints.http + "/" + task.metadata.name`, vars)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("http://swarm-memory.swarm-system.svc:8080/build"))
+
+		value, err = Evaluate(`task.spec.priority == "high" ? cluster.metadata.name + "-fast" : cluster.metadata.name`, vars)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("swarm-fast"))
+	})
+
+	It("formats numbers and bools", func() {
+		value, err := Evaluate(`cluster.status.readyAgents * 2`, vars)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("6"))
+
+		value, err = Evaluate(`double(task.spec.parameters.shards) / 8.0`, vars)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("0.5"))
+
+		value, err = Evaluate(`has(task.spec.gpu)`, vars)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("false"))
+	})
+
+	It("sees a missing memory store as null", func() {
+		value, err := Evaluate(`memory == null ? "none" : memory.status.endpoints.http`, Variables{Task: vars.Task, Cluster: vars.Cluster})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("none"))
+	})
+
+	It("fails on missing fields and other result types", func() {
+		_, err := Evaluate(`cluster.status.endpoint`, vars)
+		Expect(err).To(MatchError(ContainSubstring("no such key")))
+		_, err = Evaluate(`cluster.metadata`, vars)
+		Expect(err).To(MatchError(ContainSubstring("not a string, number or bool")))
+	})
+
+	It("rejects invalid expressions at admission", func() {
+		Expect(Check(`memory.status.endpoints.http`)).To(Succeed())
+		Expect(Check(`task.metadata.name +`)).NotTo(Succeed())
+		Expect(Check(`agent.name`)).To(MatchError(ContainSubstring("undeclared reference")))
+		Expect(Check(`[task.metadata.name]`)).To(MatchError(ContainSubstring("got list")))
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configtemplate renders the config files of tasks from Go
+// templates that read secret keys, e.g.
+//
+//	[default]
+//	aws_access_key_id = {{ secret "aws" "access-key" }}
+//	aws_secret_access_key = {{ secret "aws" "secret-key" }}
+package configtemplate
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"text/template"
+)
+
+// Lookup returns the value of a key of a secret
+type Lookup func(secret, key string) (string, error)
+
+func funcs(lookup Lookup) template.FuncMap {
+	return template.FuncMap{
+		"secret": lookup,
+		"quote":  strconv.Quote,
+		"b64enc": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	}
+}
+
+// Parse checks the syntax of a template without reading any secret
+func Parse(name, text string) error {
+	_, err := template.New(name).Option("missingkey=error").Funcs(funcs(nil)).Parse(text)
+	return err
+}
+
+// Render executes a template, resolving secret keys through lookup
+func Render(name, text string, lookup Lookup) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcs(lookup)).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, nil); err != nil {
+		return nil, fmt.Errorf("rendering %s: %w", name, err)
+	}
+	return out.Bytes(), nil
+}
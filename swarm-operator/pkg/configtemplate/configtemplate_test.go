@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configtemplate
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigTemplate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Config Template Suite")
+}
+
+var _ = Describe("Config templates", func() {
+	secrets := map[string]map[string]string{
+		"aws": {"access-key": "AKIA", "secret-key": "s3cr3t"},
+		"db":  {"password": `p"w`},
+	}
+	lookup := func(secret, key string) (string, error) {
+		value, ok := secrets[secret][key]
+		if !ok {
+			return "", fmt.Errorf("secret %s has no key %s", secret, key)
+		}
+		return value, nil
+	}
+
+	It("combines keys of several secrets", func() {
+		out, err := Render("config.json", `{"key": {{ secret "aws" "access-key" | quote }}, "password": {{ secret "db" "password" | quote }}}`, lookup)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal(`{"key": "AKIA", "password": "p\"w"}`))
+	})
+
+	It("encodes values", func() {
+		out, err := Render("netrc", `{{ secret "aws" "secret-key" | b64enc }}`, lookup)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal("czNjcjN0"))
+	})
+
+	It("fails on missing keys", func() {
+		_, err := Render("credentials", `{{ secret "aws" "session-token" }}`, lookup)
+		Expect(err).To(MatchError(ContainSubstring("has no key session-token")))
+	})
+
+	It("checks the syntax without secrets", func() {
+		Expect(Parse("ok", `{{ secret "aws" "access-key" }}`)).To(Succeed())
+		Expect(Parse("unclosed", `{{ secret "aws"`)).NotTo(Succeed())
+		Expect(Parse("unknown", `{{ env "HOME" }}`)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/notify"
+)
+
+// Notification is the payload emitted when a task is dead-lettered
+type Notification struct {
+	Task      string                      `json:"task"`
+	Namespace string                      `json:"namespace"`
+	Cluster   string                      `json:"cluster"`
+	Digest    swarmv1alpha1.FailureDigest `json:"digest"`
+}
+
+// Sink receives dead-letter notifications
+type Sink interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// WebhookSink posts notifications as JSON to an HTTP endpoint
+type WebhookSink struct {
+	notify.Poster
+	URL   string
+	Token string
+}
+
+// NewWebhookSink creates a sink for the given URL. Token is sent as a bearer
+// token when non-empty.
+func NewWebhookSink(url, token string) *WebhookSink {
+	return &WebhookSink{
+		Poster: notify.NewPoster(),
+		URL:    url,
+		Token:  token,
+	}
+}
+
+// Notify sends the notification, retrying with exponential backoff on
+// network errors, 429 and 5xx responses, and fails on any other non-2xx
+// response
+func (s *WebhookSink) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return s.Post(ctx, s.URL, s.Token, body)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+func TestDeadLetter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dead Letter Suite")
+}
+
+var _ = Describe("WebhookSink", func() {
+	var notification Notification
+
+	BeforeEach(func() {
+		notification = Notification{
+			Task:      "build-task",
+			Namespace: "default",
+			Cluster:   "test-swarm",
+			Digest: swarmv1alpha1.FailureDigest{
+				JobName:   "build-task-job-3",
+				Attempts:  4,
+				ExitCodes: []int32{137},
+			},
+		}
+	})
+
+	It("should post the notification with the bearer token", func() {
+		var received Notification
+		var auth string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		sink := NewWebhookSink(server.URL, "secret")
+		Expect(sink.Notify(context.Background(), notification)).To(Succeed())
+		Expect(auth).To(Equal("Bearer secret"))
+		Expect(received.Task).To(Equal("build-task"))
+		Expect(received.Digest.ExitCodes).To(Equal([]int32{137}))
+	})
+
+	It("should retry server errors", func() {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		sink := NewWebhookSink(server.URL, "")
+		sink.Backoff = time.Millisecond
+		Expect(sink.Notify(context.Background(), notification)).To(Succeed())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(3)))
+	})
+
+	It("should fail once the attempts are exhausted", func() {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		sink := NewWebhookSink(server.URL, "")
+		sink.Backoff = time.Millisecond
+		Expect(sink.Notify(context.Background(), notification)).To(MatchError(ContainSubstring("after 4 attempt(s)")))
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(4)))
+	})
+
+	It("should not retry client errors", func() {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		sink := NewWebhookSink(server.URL, "")
+		Expect(sink.Notify(context.Background(), notification)).NotTo(Succeed())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+	})
+})
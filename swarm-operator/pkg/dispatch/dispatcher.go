@@ -0,0 +1,134 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dispatch pushes task assignments to agents over gRPC and falls
+// back to the next candidate when an agent rejects or does not answer.
+package dispatch
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultAckTimeout bounds the wait for an agent's ack
+const DefaultAckTimeout = 10 * time.Second
+
+// Assignment is the AssignTask request sent to an agent
+type Assignment struct {
+	Task        string            `json:"task"`
+	Namespace   string            `json:"namespace"`
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Priority    string            `json:"priority,omitempty"`
+	Parameters  map[string]string `json:"parameters,omitempty"`
+	Subtasks    []string          `json:"subtasks,omitempty"`
+	// TokenSecret names the Secret holding the task's GitHub token
+	TokenSecret string `json:"tokenSecret,omitempty"`
+	// CheckpointRef is the checkpoint a reassigned task resumes from
+	CheckpointRef string `json:"checkpointRef,omitempty"`
+}
+
+// Ack is the agent's answer to an assignment. Accepted false is a nack.
+type Ack struct {
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Transport delivers an assignment to the target agent
+type Transport interface {
+	Assign(ctx context.Context, target Target, assignment *Assignment) (*Ack, error)
+}
+
+// Target is a candidate agent and its gRPC address
+type Target struct {
+	Agent   string
+	Address string
+
+	// TLS is the client config for agents requiring mTLS, nil for
+	// plaintext
+	TLS *tls.Config
+}
+
+// Rejection records why an agent did not take an assignment
+type Rejection struct {
+	Agent  string
+	Reason string
+}
+
+// Result of a dispatch
+type Result struct {
+	// Agent that acked the assignment, empty when every candidate refused
+	Agent string
+
+	// Rejections of the candidates tried before Agent, in order
+	Rejections []Rejection
+}
+
+// Summary describes the rejections for events and status messages
+func (r Result) Summary() string {
+	reasons := make([]string, 0, len(r.Rejections))
+	for _, rejection := range r.Rejections {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", rejection.Agent, rejection.Reason))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Dispatcher pushes assignments to agents
+type Dispatcher struct {
+	Transport Transport
+}
+
+// NewDispatcher creates a dispatcher using the gRPC transport
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{Transport: NewGRPCTransport()}
+}
+
+// Dispatch offers the assignment to the targets in order until one acks
+// within ackTimeout. Nacks, errors and timeouts move on to the next target.
+func (d *Dispatcher) Dispatch(ctx context.Context, assignment *Assignment, targets []Target, ackTimeout time.Duration) Result {
+	if ackTimeout <= 0 {
+		ackTimeout = DefaultAckTimeout
+	}
+
+	var result Result
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+
+		ackCtx, cancel := context.WithTimeout(ctx, ackTimeout)
+		ack, err := d.Transport.Assign(ackCtx, target, assignment)
+		cancel()
+
+		switch {
+		case err != nil:
+			result.Rejections = append(result.Rejections, Rejection{Agent: target.Agent, Reason: err.Error()})
+		case !ack.Accepted:
+			reason := ack.Reason
+			if reason == "" {
+				reason = "rejected"
+			}
+			result.Rejections = append(result.Rejections, Rejection{Agent: target.Agent, Reason: reason})
+		default:
+			result.Agent = target.Agent
+			return result
+		}
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestDispatch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Dispatch Suite")
+}
+
+// fakeTransport answers per address
+type fakeTransport map[string]func(ctx context.Context) (*Ack, error)
+
+func (f fakeTransport) Assign(ctx context.Context, target Target, _ *Assignment) (*Ack, error) {
+	return f[target.Address](ctx)
+}
+
+var _ = Describe("Dispatcher", func() {
+	targets := []Target{{Agent: "a", Address: "a:8080"}, {Agent: "b", Address: "b:8080"}, {Agent: "c", Address: "c:8080"}}
+
+	It("should fall back to the next agent on nack, error and timeout", func() {
+		d := &Dispatcher{Transport: fakeTransport{
+			"a:8080": func(context.Context) (*Ack, error) { return &Ack{Accepted: false, Reason: "at capacity"}, nil },
+			"b:8080": func(ctx context.Context) (*Ack, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			"c:8080": func(context.Context) (*Ack, error) { return &Ack{Accepted: true}, nil },
+		}}
+
+		result := d.Dispatch(context.Background(), &Assignment{Task: "t"}, targets, 10*time.Millisecond)
+		Expect(result.Agent).To(Equal("c"))
+		Expect(result.Rejections).To(HaveLen(2))
+		Expect(result.Summary()).To(Equal("a: at capacity; b: context deadline exceeded"))
+	})
+
+	It("should stop at the first ack", func() {
+		d := &Dispatcher{Transport: fakeTransport{
+			"a:8080": func(context.Context) (*Ack, error) { return &Ack{Accepted: true}, nil },
+		}}
+		result := d.Dispatch(context.Background(), &Assignment{Task: "t"}, targets, 0)
+		Expect(result.Agent).To(Equal("a"))
+		Expect(result.Rejections).To(BeEmpty())
+	})
+
+	It("should report when nobody accepts", func() {
+		failing := func(context.Context) (*Ack, error) { return nil, errors.New("unavailable") }
+		d := &Dispatcher{Transport: fakeTransport{"a:8080": failing, "b:8080": failing, "c:8080": failing}}
+		result := d.Dispatch(context.Background(), &Assignment{Task: "t"}, targets, time.Second)
+		Expect(result.Agent).To(BeEmpty())
+		Expect(result.Rejections).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("GRPCTransport", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.URL.Path).To(Equal(AssignTaskMethod))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/grpc+json"))
+			Expect(r.Header.Get("grpc-timeout")).To(HaveSuffix("m"))
+
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			payload, err := decodeFrame(body)
+			Expect(err).NotTo(HaveOccurred())
+			assignment := &Assignment{}
+			Expect(json.Unmarshal(payload, assignment)).To(Succeed())
+
+			w.Header().Set("Content-Type", "application/grpc+json")
+			if strings.HasPrefix(assignment.Task, "unavailable") {
+				// Trailers-only error response
+				w.Header().Set("grpc-status", "14")
+				w.Header().Set("grpc-message", "agent%20draining")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			w.Header().Set("Trailer", "grpc-status, grpc-message")
+			w.WriteHeader(http.StatusOK)
+			ack, _ := json.Marshal(Ack{Accepted: assignment.Type == "coding", Reason: "wrong type"})
+			_, _ = w.Write(encodeFrame(ack))
+			w.Header().Set("grpc-status", "0")
+		})
+		server = httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	assign := func(assignment *Assignment) (*Ack, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return NewGRPCTransport().Assign(ctx, Target{Agent: "a", Address: server.Listener.Addr().String()}, assignment)
+	}
+
+	It("should return the agent's ack", func() {
+		ack, err := assign(&Assignment{Task: "build", Type: "coding"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ack.Accepted).To(BeTrue())
+	})
+
+	It("should return a nack", func() {
+		ack, err := assign(&Assignment{Task: "build", Type: "research"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ack).To(Equal(&Ack{Accepted: false, Reason: "wrong type"}))
+	})
+
+	It("should surface gRPC errors", func() {
+		_, err := assign(&Assignment{Task: "unavailable"})
+		var statusErr *StatusError
+		Expect(errors.As(err, &statusErr)).To(BeTrue())
+		Expect(statusErr.Code).To(Equal("14"))
+		Expect(statusErr.Message).To(Equal("agent draining"))
+	})
+
+	It("should call agents requiring TLS over HTTPS", func() {
+		tlsServer := httptest.NewUnstartedServer(server.Config.Handler)
+		tlsServer.EnableHTTP2 = true
+		tlsServer.StartTLS()
+		defer tlsServer.Close()
+
+		roots := x509.NewCertPool()
+		roots.AddCert(tlsServer.Certificate())
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		ack, err := NewGRPCTransport().Assign(ctx, Target{
+			Agent:   "a",
+			Address: tlsServer.Listener.Addr().String(),
+			TLS:     &tls.Config{RootCAs: roots, ServerName: "example.com"},
+		}, &Assignment{Task: "build", Type: "coding"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ack.Accepted).To(BeTrue())
+	})
+})
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// AssignTaskMethod is the full gRPC method name agents serve. Messages use
+// the "json" codec, so agents register a JSON codec with their gRPC server
+// instead of generated protobuf types.
+const AssignTaskMethod = "/claudeflow.swarm.v1.Agent/AssignTask"
+
+// StatusError is a non-OK gRPC status returned by an agent
+type StatusError struct {
+	Code    string
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("grpc status %s: %s", e.Code, e.Message)
+}
+
+// GRPCTransport calls AssignTask over plaintext HTTP/2 (h2c), the transport
+// used inside the cluster network, or over TLS for targets requiring mTLS
+type GRPCTransport struct {
+	client *http.Client
+}
+
+// NewGRPCTransport creates a transport with a shared HTTP/2 connection pool
+func NewGRPCTransport() *GRPCTransport {
+	return &GRPCTransport{
+		client: &http.Client{
+			Transport: &http2.Transport{
+				AllowHTTP: true,
+				DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, network, addr)
+				},
+			},
+		},
+	}
+}
+
+// Assign implements Transport
+func (t *GRPCTransport) Assign(ctx context.Context, target Target, assignment *Assignment) (*Ack, error) {
+	payload, err := json.Marshal(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	client, scheme := t.client, "http"
+	if target.TLS != nil {
+		// TLS configs change with certificate rotation, so connections
+		// are not pooled across calls
+		transport := &http2.Transport{TLSClientConfig: target.TLS}
+		defer transport.CloseIdleConnections()
+		client, scheme = &http.Client{Transport: transport}, "https"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scheme+"://"+target.Address+AssignTaskMethod,
+		bytes.NewReader(encodeFrame(payload)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc+json")
+	req.Header.Set("TE", "trailers")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("grpc-timeout", encodeTimeout(time.Until(deadline)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agent returned HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors may come as trailers-only responses, with the status in the
+	// headers
+	code, message := resp.Trailer.Get("grpc-status"), resp.Trailer.Get("grpc-message")
+	if code == "" {
+		code, message = resp.Header.Get("grpc-status"), resp.Header.Get("grpc-message")
+	}
+	if code != "0" {
+		if decoded, err := url.PathUnescape(message); err == nil {
+			message = decoded
+		}
+		return nil, &StatusError{Code: code, Message: message}
+	}
+
+	payload, err = decodeFrame(body)
+	if err != nil {
+		return nil, err
+	}
+	ack := &Ack{}
+	if err := json.Unmarshal(payload, ack); err != nil {
+		return nil, fmt.Errorf("failed to decode ack: %w", err)
+	}
+	return ack, nil
+}
+
+// encodeFrame wraps a message in the gRPC length-prefixed frame
+func encodeFrame(payload []byte) []byte {
+	frame := make([]byte, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+	copy(frame[5:], payload)
+	return frame
+}
+
+// decodeFrame unwraps the single message of a unary response
+func decodeFrame(data []byte) ([]byte, error) {
+	if len(data) < 5 {
+		return nil, fmt.Errorf("short gRPC response of %d bytes", len(data))
+	}
+	if data[0] != 0 {
+		return nil, fmt.Errorf("compressed gRPC responses are not supported")
+	}
+	length := binary.BigEndian.Uint32(data[1:5])
+	if uint32(len(data)-5) < length {
+		return nil, fmt.Errorf("truncated gRPC response")
+	}
+	return data[5 : 5+length], nil
+}
+
+// encodeTimeout formats a grpc-timeout header value in milliseconds
+func encodeTimeout(d time.Duration) string {
+	ms := d.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	return fmt.Sprintf("%dm", ms)
+}
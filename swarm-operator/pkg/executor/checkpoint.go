@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Checkpoint is the file format of a checkpoint. State is opaque to the
+// operator and belongs to the executor.
+type Checkpoint struct {
+	Contract  string          `json:"contract"`
+	Task      string          `json:"task"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Step      string          `json:"step,omitempty"`
+	Progress  int32           `json:"progress,omitempty"`
+	State     json.RawMessage `json:"state,omitempty"`
+}
+
+// WriteCheckpoint stores the checkpoint under CheckpointDir and returns its
+// ref, the path relative to the workspace. Report the ref in
+// Report.CheckpointRef so a resumed attempt gets it as SWARM_CHECKPOINT_REF.
+func (e *Env) WriteCheckpoint(cp *Checkpoint) (string, error) {
+	cp.Contract = ContractVersion
+	if cp.Task == "" {
+		cp.Task = e.TaskName
+	}
+	if cp.CreatedAt.IsZero() {
+		cp.CreatedAt = time.Now().UTC()
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	ref := fmt.Sprintf("%s/%d.json", CheckpointDir, cp.CreatedAt.UnixNano())
+	if err := writeFileAtomic(e.Path(ref), data); err != nil {
+		return "", err
+	}
+	return ref, nil
+}
+
+// ReadCheckpoint loads the checkpoint to resume from, nil if the task does
+// not resume or has no checkpoint
+func (e *Env) ReadCheckpoint() (*Checkpoint, error) {
+	if !e.Resume || e.CheckpointRef == "" {
+		return nil, nil
+	}
+	if filepath.IsAbs(e.CheckpointRef) || strings.HasPrefix(filepath.Clean(e.CheckpointRef), "..") {
+		return nil, fmt.Errorf("checkpoint ref %q is outside the workspace", e.CheckpointRef)
+	}
+
+	data, err := os.ReadFile(e.Path(e.CheckpointRef))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("corrupt checkpoint %s: %w", e.CheckpointRef, err)
+	}
+	if cp.Contract != ContractVersion {
+		return nil, fmt.Errorf("checkpoint %s has unsupported contract %q", e.CheckpointRef, cp.Contract)
+	}
+	return cp, nil
+}
+
+// writeFileAtomic writes through a temporary file so a preempted executor
+// never leaves a truncated file behind
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
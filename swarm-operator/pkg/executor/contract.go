@@ -0,0 +1,240 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package executor defines the contract between the swarm operator and the
+// images that run SwarmTask Jobs: the environment the operator sets, the
+// workspace layout for checkpoints and results, the progress endpoint and
+// how executors are asked to checkpoint and shut down. It only depends on
+// the standard library so custom executor images can vendor it cheaply.
+package executor
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ContractVersion is bumped on incompatible changes to the contract
+const ContractVersion = "v1"
+
+// Environment variables the operator sets on the task container
+const (
+	EnvTaskName        = "SWARM_TASK_NAME"
+	EnvCluster         = "SWARM_CLUSTER"
+	EnvTaskType        = "SWARM_TASK_TYPE"
+	EnvTaskDescription = "SWARM_TASK_DESCRIPTION"
+	EnvTaskPriority    = "SWARM_TASK_PRIORITY"
+
+	// EnvAgentType is the agent type the task runs as, which changes as a
+	// failing task walks its fallback strategy
+	EnvAgentType = "SWARM_AGENT_TYPE"
+
+	// EnvCorrelationID ties the executor's logs to those of the operator
+	// about the task. It stays the same across retries.
+	EnvCorrelationID = "SWARM_CORRELATION_ID"
+
+	// EnvWorkspace is the checked out workspace, DefaultWorkspace if unset
+	EnvWorkspace = "SWARM_WORKSPACE"
+
+	// EnvSharedWorkspace is where the volume shared by all agents and
+	// tasks of the cluster is mounted, unset without a shared workspace
+	EnvSharedWorkspace = "SWARM_SHARED_WORKSPACE"
+
+	// EnvResume is "true" when the executor should continue from the
+	// checkpoint in EnvCheckpointRef
+	EnvResume        = "SWARM_RESUME"
+	EnvCheckpointRef = "SWARM_CHECKPOINT_REF"
+
+	// EnvCheckpointSignalFile is the downward API file with the pod
+	// annotations. The operator sets EnvCheckpointAnnotation on the pod to
+	// ask for a checkpoint before preempting or suspending the task.
+	EnvCheckpointSignalFile = "SWARM_CHECKPOINT_SIGNAL_FILE"
+	EnvCheckpointAnnotation = "SWARM_CHECKPOINT_ANNOTATION"
+
+	// EnvProgressURL is where executors POST Progress updates. Unset when
+	// the operator has no progress endpoint configured.
+	EnvProgressURL = "SWARM_PROGRESS_URL"
+
+	// EnvProgressTokenFile is a service account token with the
+	// ProgressAudience that authenticates progress updates to the
+	// operator. The kubelet rotates it, so it is read for every update.
+	EnvProgressTokenFile = "SWARM_PROGRESS_TOKEN_FILE"
+
+	// EnvBudgetMaxTokens, EnvBudgetMaxAPICalls and EnvBudgetMaxCost are
+	// the limits of spec.budget, unset for limits the task has none of.
+	// The operator cancels the task once the usage reported with progress
+	// updates reaches one.
+	EnvBudgetMaxTokens   = "SWARM_BUDGET_MAX_TOKENS"
+	EnvBudgetMaxAPICalls = "SWARM_BUDGET_MAX_API_CALLS"
+	EnvBudgetMaxCost     = "SWARM_BUDGET_MAX_COST"
+
+	// EnvMeshQuitURL is where executors POST once the task finished, to
+	// stop the service mesh proxy that would otherwise keep the pod
+	// running. Unset outside of a mesh or when the proxy stops by itself.
+	EnvMeshQuitURL = "SWARM_MESH_QUIT_URL"
+
+	// EnvServicePort is the port service tasks serve on, unset for tasks
+	// that run to completion
+	EnvServicePort = "SWARM_SERVICE_PORT"
+
+	// EnvExemplarLabels are comma-separated name=value pairs, values
+	// percent-encoded, that executors attach to metric exemplars so
+	// dashboards link a sample to its task.
+	// Unset unless the cluster enables task telemetry, which also sets the
+	// standard OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES.
+	EnvExemplarLabels = "SWARM_EXEMPLAR_LABELS"
+
+	EnvGitHubToken        = "GITHUB_TOKEN"
+	EnvGitHubRepositories = "GITHUB_REPOSITORIES"
+
+	// ParamPrefix prefixes the upper-cased spec.parameters keys
+	ParamPrefix = "PARAM_"
+)
+
+// ProgressAudience is the token audience the operator's progress endpoint
+// accepts
+const ProgressAudience = "swarm-progress"
+
+// Workspace layout, relative to the workspace root
+const (
+	DefaultWorkspace = "/workspace"
+
+	// StateDir holds everything the executor hands back to the operator
+	StateDir = ".swarm"
+
+	// ResultFile is the full TaskResult of the run as JSON
+	ResultFile = StateDir + "/result.json"
+
+	// ArtifactsDir holds files produced by the task, listed in
+	// Result.Artifacts relative to this directory
+	ArtifactsDir = StateDir + "/artifacts"
+
+	// CheckpointDir holds checkpoints, see WriteCheckpoint
+	CheckpointDir = StateDir + "/checkpoints"
+
+	// TerminationMessagePath is where the compact Report is written. The
+	// operator reads it from the container status once the pod terminates.
+	TerminationMessagePath = "/dev/termination-log"
+)
+
+// Env is the task environment as seen by the executor
+type Env struct {
+	TaskName    string
+	Cluster     string
+	Type        string
+	Description string
+	Priority    string
+	Workspace   string
+
+	// SharedWorkspace is the volume shared with the other agents and tasks
+	// of the cluster, empty without one
+	SharedWorkspace string
+
+	// CorrelationID is the correlation ID of the task to log with
+	CorrelationID string
+
+	Resume        bool
+	CheckpointRef string
+
+	CheckpointSignalFile string
+	CheckpointAnnotation string
+
+	ProgressURL       string
+	ProgressTokenFile string
+
+	// Budget holds the limits of the task, zero for none
+	Budget Budget
+
+	// MeshQuitURL stops the service mesh proxy, see QuitMesh
+	MeshQuitURL string
+
+	// ServicePort is the port of service tasks, zero for other tasks
+	ServicePort int
+
+	// ExemplarLabels are the labels to attach to metric exemplars, nil
+	// without task telemetry
+	ExemplarLabels map[string]string
+
+	GitHubToken  string
+	Repositories []string
+
+	// Parameters are the spec.parameters of the task, keyed by their
+	// upper-cased name
+	Parameters map[string]string
+}
+
+// LoadEnv reads the task environment from the process environment
+func LoadEnv() *Env {
+	env := &Env{
+		TaskName:             os.Getenv(EnvTaskName),
+		Cluster:              os.Getenv(EnvCluster),
+		Type:                 os.Getenv(EnvTaskType),
+		Description:          os.Getenv(EnvTaskDescription),
+		Priority:             os.Getenv(EnvTaskPriority),
+		Workspace:            os.Getenv(EnvWorkspace),
+		SharedWorkspace:      os.Getenv(EnvSharedWorkspace),
+		CorrelationID:        os.Getenv(EnvCorrelationID),
+		Resume:               os.Getenv(EnvResume) == "true",
+		CheckpointRef:        os.Getenv(EnvCheckpointRef),
+		CheckpointSignalFile: os.Getenv(EnvCheckpointSignalFile),
+		CheckpointAnnotation: os.Getenv(EnvCheckpointAnnotation),
+		ProgressURL:          os.Getenv(EnvProgressURL),
+		ProgressTokenFile:    os.Getenv(EnvProgressTokenFile),
+		MeshQuitURL:          os.Getenv(EnvMeshQuitURL),
+		GitHubToken:          os.Getenv(EnvGitHubToken),
+		Parameters:           map[string]string{},
+	}
+	if env.Workspace == "" {
+		env.Workspace = DefaultWorkspace
+	}
+	if repos := os.Getenv(EnvGitHubRepositories); repos != "" {
+		env.Repositories = strings.Split(repos, ",")
+	}
+	env.ServicePort, _ = strconv.Atoi(os.Getenv(EnvServicePort))
+	if labels := os.Getenv(EnvExemplarLabels); labels != "" {
+		env.ExemplarLabels = map[string]string{}
+		for _, pair := range strings.Split(labels, ",") {
+			name, value, _ := strings.Cut(pair, "=")
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+			env.ExemplarLabels[name] = value
+		}
+	}
+	env.Budget.MaxTokens, _ = strconv.ParseInt(os.Getenv(EnvBudgetMaxTokens), 10, 64)
+	env.Budget.MaxAPICalls, _ = strconv.ParseInt(os.Getenv(EnvBudgetMaxAPICalls), 10, 64)
+	env.Budget.MaxCost, _ = strconv.ParseFloat(os.Getenv(EnvBudgetMaxCost), 64)
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(name, ParamPrefix) {
+			env.Parameters[strings.TrimPrefix(name, ParamPrefix)] = value
+		}
+	}
+	return env
+}
+
+// Param returns the task parameter with the given name, matched the way
+// the operator upper-cases parameter names
+func (e *Env) Param(name string) string {
+	return e.Parameters[strings.ToUpper(name)]
+}
+
+// Path resolves a path of the workspace layout
+func (e *Env) Path(rel string) string {
+	return filepath.Join(e.Workspace, rel)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestExecutor(t *testing.T) {
+	RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Executor Suite")
+}
+
+var _ = ginkgo.Describe("Executor contract", func() {
+	var env *Env
+
+	ginkgo.BeforeEach(func() {
+		env = &Env{TaskName: "build", Cluster: "swarm", Workspace: ginkgo.GinkgoT().TempDir()}
+	})
+
+	ginkgo.It("loads the task environment", func() {
+		ginkgo.GinkgoT().Setenv(EnvTaskName, "build")
+		ginkgo.GinkgoT().Setenv(EnvResume, "true")
+		ginkgo.GinkgoT().Setenv(EnvCheckpointRef, ".swarm/checkpoints/1.json")
+		ginkgo.GinkgoT().Setenv(EnvGitHubRepositories, "org/a,org/b")
+		ginkgo.GinkgoT().Setenv(EnvWorkspace, "")
+		ginkgo.GinkgoT().Setenv(ParamPrefix+"TARGET", "prod")
+		ginkgo.GinkgoT().Setenv(EnvServicePort, "8080")
+		ginkgo.GinkgoT().Setenv(EnvExemplarLabels, "task=build,correlation_id=a%2Cb")
+
+		loaded := LoadEnv()
+		Expect(loaded.TaskName).To(Equal("build"))
+		Expect(loaded.Resume).To(BeTrue())
+		Expect(loaded.Workspace).To(Equal(DefaultWorkspace))
+		Expect(loaded.Repositories).To(Equal([]string{"org/a", "org/b"}))
+		Expect(loaded.Param("target")).To(Equal("prod"))
+		Expect(loaded.ServicePort).To(Equal(8080))
+		Expect(loaded.ExemplarLabels).To(Equal(map[string]string{"task": "build", "correlation_id": "a,b"}))
+	})
+
+	ginkgo.It("round-trips checkpoints through the workspace", func() {
+		ref, err := env.WriteCheckpoint(&Checkpoint{Step: "compile", State: json.RawMessage(`{"done":3}`)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref).To(HavePrefix(CheckpointDir + "/"))
+
+		resumed := &Env{Workspace: env.Workspace, Resume: true, CheckpointRef: ref}
+		cp, err := resumed.ReadCheckpoint()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp.Task).To(Equal("build"))
+		Expect(cp.Step).To(Equal("compile"))
+		Expect(string(cp.State)).To(Equal(`{"done":3}`))
+
+		resumed.CheckpointRef = "../../etc/passwd"
+		_, err = resumed.ReadCheckpoint()
+		Expect(err).To(HaveOccurred())
+	})
+
+	ginkgo.It("reports results in the termination message", func() {
+		message := filepath.Join(env.Workspace, "termination-log")
+		Expect(WriteReport(message, &Report{
+			Result:        &Result{Success: true, Summary: "done"},
+			CheckpointRef: ".swarm/checkpoints/1.json",
+		})).To(Succeed())
+
+		data, err := os.ReadFile(message)
+		Expect(err).NotTo(HaveOccurred())
+		report, ok := ParseReport(string(data))
+		Expect(ok).To(BeTrue())
+		Expect(report.Result.Summary).To(Equal("done"))
+		Expect(report.CheckpointRef).To(Equal(".swarm/checkpoints/1.json"))
+
+		_, ok = ParseReport("panic: something went wrong\n")
+		Expect(ok).To(BeFalse())
+	})
+
+	ginkgo.It("keeps reports within the termination message limit", func() {
+		data, err := EncodeReport(&Report{Result: &Result{
+			Success: true,
+			Summary: strings.Repeat("s", 3000),
+			Data:    map[string]string{"log": strings.Repeat("x", 5000)},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(data)).To(BeNumerically("<=", maxTerminationMessage))
+
+		report, ok := ParseReport(string(data))
+		Expect(ok).To(BeTrue())
+		Expect(report.Result.Data).To(BeEmpty())
+		Expect(report.Result.Summary).To(HaveLen(3000))
+
+		data, err = EncodeReport(&Report{Result: &Result{Summary: strings.Repeat("s", 5000)}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(data)).To(BeNumerically("<=", maxTerminationMessage))
+	})
+
+	ginkgo.It("detects checkpoint requests in the signal file", func() {
+		env.CheckpointSignalFile = filepath.Join(env.Workspace, "annotations")
+		env.CheckpointAnnotation = "swarm.claudeflow.io/checkpoint-requested"
+		Expect(env.CheckpointRequested()).To(BeFalse())
+
+		Expect(os.WriteFile(env.CheckpointSignalFile, []byte(
+			"kubernetes.io/config.seen=\"2024-01-01T00:00:00Z\"\n"+
+				"swarm.claudeflow.io/checkpoint-requested=\"2024-01-01T00:05:00Z\"\n"), 0644)).To(Succeed())
+		Expect(env.CheckpointRequested()).To(BeTrue())
+	})
+
+	ginkgo.It("posts progress to the progress endpoint", func() {
+		var received Progress
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		env.ProgressURL = server.URL
+		Expect(env.NewReporter().Report(context.Background(), Progress{Percent: 140, Message: "linking"})).To(Succeed())
+		Expect(received).To(Equal(Progress{Task: "build", Cluster: "swarm", Percent: 100, Message: "linking"}))
+
+		Expect((&Reporter{}).Report(context.Background(), Progress{Percent: 10})).To(Succeed())
+	})
+
+	ginkgo.It("authenticates progress with the token file and reports usage", func() {
+		var authorization string
+		var received Progress
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		env.ProgressURL = server.URL
+		env.ProgressTokenFile = filepath.Join(env.Workspace, "token")
+		Expect(os.WriteFile(env.ProgressTokenFile, []byte("secret\n"), 0600)).To(Succeed())
+		usage := Usage{Tokens: 1200, APICalls: 3, Cost: 0.04}
+		Expect(env.NewReporter().Report(context.Background(), Progress{Percent: 20, Usage: &usage})).To(Succeed())
+		Expect(authorization).To(Equal("Bearer secret"))
+		Expect(received.Usage).To(Equal(&usage))
+	})
+
+	ginkgo.It("asks the mesh proxy to quit", func() {
+		var method string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		Expect(env.QuitMesh(context.Background())).To(Succeed())
+		Expect(method).To(BeEmpty())
+
+		ginkgo.GinkgoT().Setenv(EnvMeshQuitURL, server.URL+"/quitquitquit")
+		Expect(LoadEnv().QuitMesh(context.Background())).To(Succeed())
+		Expect(method).To(Equal(http.MethodPost))
+	})
+
+	ginkgo.It("loads the budget and checks usage against it", func() {
+		ginkgo.GinkgoT().Setenv(EnvBudgetMaxTokens, "1000")
+		ginkgo.GinkgoT().Setenv(EnvBudgetMaxAPICalls, "")
+		ginkgo.GinkgoT().Setenv(EnvBudgetMaxCost, "2.5")
+
+		budget := LoadEnv().Budget
+		Expect(budget).To(Equal(Budget{MaxTokens: 1000, MaxCost: 2.5}))
+		Expect(budget.Exceeded(Usage{Tokens: 999, APICalls: 500, Cost: 2.49})).To(BeFalse())
+		Expect(budget.Exceeded(Usage{Tokens: 1000})).To(BeTrue())
+		Expect(budget.Exceeded(Usage{Cost: 2.5})).To(BeTrue())
+	})
+})
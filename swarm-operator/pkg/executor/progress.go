@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Progress is the body executors POST to SWARM_PROGRESS_URL
+type Progress struct {
+	Task    string `json:"task"`
+	Cluster string `json:"cluster,omitempty"`
+
+	// Percent complete, 0-100
+	Percent int32  `json:"percent"`
+	Message string `json:"message,omitempty"`
+
+	// CheckpointRef reports a checkpoint written since the last update
+	CheckpointRef string `json:"checkpointRef,omitempty"`
+
+	// Usage is the paid API usage of this run so far. It is cumulative, so
+	// a lost update is made up for by the next.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// Usage counts the calls to paid APIs, such as LLM providers, and what they
+// are estimated to cost
+type Usage struct {
+	Tokens   int64 `json:"tokens,omitempty"`
+	APICalls int64 `json:"apiCalls,omitempty"`
+
+	// Cost is the estimated spend in US dollars
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// Budget is the most usage a task may report before it is cancelled. Zero
+// limits are unlimited.
+type Budget struct {
+	MaxTokens   int64
+	MaxAPICalls int64
+	MaxCost     float64
+}
+
+// Exceeded reports whether the usage reached a limit of the budget.
+// Executors can check it to wind down before the operator cancels them.
+func (b Budget) Exceeded(u Usage) bool {
+	return (b.MaxTokens > 0 && u.Tokens >= b.MaxTokens) ||
+		(b.MaxAPICalls > 0 && u.APICalls >= b.MaxAPICalls) ||
+		(b.MaxCost > 0 && u.Cost >= b.MaxCost)
+}
+
+// Reporter sends progress updates. The zero value, and a Reporter for an
+// environment without a progress URL, drops updates.
+type Reporter struct {
+	URL     string
+	Task    string
+	Cluster string
+	Client  *http.Client
+
+	// TokenFile holds the bearer token sent with updates, if set
+	TokenFile string
+}
+
+// NewReporter creates a reporter for the progress endpoint of the task
+func (e *Env) NewReporter() *Reporter {
+	return &Reporter{
+		URL:       e.ProgressURL,
+		Task:      e.TaskName,
+		Cluster:   e.Cluster,
+		Client:    &http.Client{Timeout: 5 * time.Second},
+		TokenFile: e.ProgressTokenFile,
+	}
+}
+
+// Report posts a progress update. Progress is advisory, callers should log
+// errors rather than fail the task.
+func (r *Reporter) Report(ctx context.Context, p Progress) error {
+	if r == nil || r.URL == "" {
+		return nil
+	}
+	if p.Task == "" {
+		p.Task = r.Task
+	}
+	if p.Cluster == "" {
+		p.Cluster = r.Cluster
+	}
+	if p.Percent < 0 {
+		p.Percent = 0
+	} else if p.Percent > 100 {
+		p.Percent = 100
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.TokenFile != "" {
+		token, err := os.ReadFile(r.TokenFile)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("progress endpoint returned %s", resp.Status)
+	}
+	return nil
+}
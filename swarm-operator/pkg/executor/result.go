@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTerminationMessage is the kubelet limit on termination messages
+const maxTerminationMessage = 4096
+
+// Result is the outcome of a run, mirrored into the SwarmTask status
+type Result struct {
+	Success bool              `json:"success"`
+	Summary string            `json:"summary,omitempty"`
+	Data    map[string]string `json:"data,omitempty"`
+
+	// Artifacts lists files under ArtifactsDir
+	Artifacts []string `json:"artifacts,omitempty"`
+
+	ExecutionTime  int64 `json:"executionTime,omitempty"`
+	TokensConsumed int64 `json:"tokensConsumed,omitempty"`
+}
+
+// Report is what the executor hands back through its termination message:
+// the result of a finished run, or the checkpoint of an interrupted one
+type Report struct {
+	Contract string `json:"contract"`
+
+	Result *Result `json:"result,omitempty"`
+
+	// CheckpointRef is the latest checkpoint, passed back as
+	// SWARM_CHECKPOINT_REF when the task resumes
+	CheckpointRef string `json:"checkpointRef,omitempty"`
+}
+
+// WriteResult stores the full result in ResultFile and reports it, along
+// with the latest checkpoint, in the termination message
+func (e *Env) WriteResult(result *Result, checkpointRef string) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	if err := writeFileAtomic(e.Path(ResultFile), data); err != nil {
+		return err
+	}
+	return WriteReport(TerminationMessagePath, &Report{Result: result, CheckpointRef: checkpointRef})
+}
+
+// WriteReport writes the report as termination message. Reports over the
+// kubelet limit lose their result data first and then their summary tail;
+// the full result stays in ResultFile.
+func WriteReport(path string, report *Report) error {
+	data, err := EncodeReport(report)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// EncodeReport encodes the report within the termination message limit
+func EncodeReport(report *Report) ([]byte, error) {
+	r := *report
+	r.Contract = ContractVersion
+
+	data, err := json.Marshal(&r)
+	if err != nil || len(data) <= maxTerminationMessage || r.Result == nil {
+		return data, err
+	}
+
+	result := *r.Result
+	result.Data = nil
+	result.Artifacts = nil
+	r.Result = &result
+	if data, err = json.Marshal(&r); err != nil || len(data) <= maxTerminationMessage {
+		return data, err
+	}
+
+	keep := len(result.Summary) - (len(data) - maxTerminationMessage)
+	if keep < 0 {
+		keep = 0
+	}
+	for keep > 0 && !utf8.RuneStart(result.Summary[keep]) {
+		keep--
+	}
+	result.Summary = result.Summary[:keep]
+	return json.Marshal(&r)
+}
+
+// ParseReport decodes a termination message written by WriteReport. It
+// returns false for anything else, such as a log tail.
+func ParseReport(message string) (*Report, bool) {
+	message = strings.TrimSpace(message)
+	if !strings.HasPrefix(message, "{") {
+		return nil, false
+	}
+	report := &Report{}
+	if err := json.Unmarshal([]byte(message), report); err != nil || report.Contract != ContractVersion {
+		return nil, false
+	}
+	return report, true
+}
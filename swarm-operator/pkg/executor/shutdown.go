@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// checkpointPollInterval is how often the signal file is read. The kubelet
+// refreshes downward API files on its sync period, so polling faster does
+// not help.
+const checkpointPollInterval = 5 * time.Second
+
+// CheckpointRequested reports whether the operator asked for a checkpoint
+// through the pod annotation in the signal file
+func (e *Env) CheckpointRequested() (bool, error) {
+	if e.CheckpointSignalFile == "" || e.CheckpointAnnotation == "" {
+		return false, nil
+	}
+	annotations, err := readAnnotations(e.CheckpointSignalFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	_, requested := annotations[e.CheckpointAnnotation]
+	return requested, nil
+}
+
+// readAnnotations parses the downward API annotations file, one
+// key="escaped value" pair per line
+func readAnnotations(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	annotations := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, quoted, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			value = quoted
+		}
+		annotations[key] = value
+	}
+	return annotations, scanner.Err()
+}
+
+// Shutdown tracks the two ways the operator stops an executor: a checkpoint
+// request ahead of preemption or a maintenance window, and SIGTERM when the
+// pod is deleted. In both cases the executor should write a checkpoint,
+// report it with WriteReport and exit within the termination grace period.
+type Shutdown struct {
+	// Checkpoint is closed when a checkpoint is requested or SIGTERM arrives
+	Checkpoint <-chan struct{}
+
+	// Terminating is cancelled on SIGTERM or SIGINT
+	Terminating context.Context
+
+	stopOnce sync.Once
+	stop     func()
+}
+
+// WatchShutdown starts watching for checkpoint requests and termination
+// signals until ctx is done or Stop is called
+func (e *Env) WatchShutdown(ctx context.Context) *Shutdown {
+	terminating, stopSignals := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	checkpoint := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(checkpoint)
+		ticker := time.NewTicker(checkpointPollInterval)
+		defer ticker.Stop()
+		for {
+			if requested, _ := e.CheckpointRequested(); requested {
+				return
+			}
+			select {
+			case <-terminating.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return &Shutdown{
+		Checkpoint:  checkpoint,
+		Terminating: terminating,
+		stop: func() {
+			close(done)
+			stopSignals()
+		},
+	}
+}
+
+// Stop releases the signal handlers
+func (s *Shutdown) Stop() {
+	s.stopOnce.Do(s.stop)
+}
+
+// QuitMesh asks the service mesh proxy of the pod to exit, so the Job
+// completes once the executor does. Executors call it last, after writing
+// their report. It does nothing outside of a mesh or when the proxy stops
+// by itself.
+func (e *Env) QuitMesh(ctx context.Context) error {
+	if e.MeshQuitURL == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.MeshQuitURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("mesh proxy returned %s", resp.Status)
+	}
+	return nil
+}
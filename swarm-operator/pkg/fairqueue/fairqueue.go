@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fairqueue orders waiting tasks so every key, a team or a user,
+// gets a share of the dispatch slots in proportion to its weight
+package fairqueue
+
+import (
+	"sort"
+	"time"
+)
+
+// Item is a task waiting to be dispatched
+type Item struct {
+	// Key is the queue the task belongs to
+	Key string
+
+	// Name identifies the task
+	Name string
+
+	// Enqueued is when the task started waiting
+	Enqueued time.Time
+}
+
+// Queue is the state of a key
+type Queue struct {
+	// InFlight is how many tasks of the key are dispatched and unfinished
+	InFlight int
+
+	// LastServed orders the keys by their last dispatch, higher is more
+	// recent and 0 is never
+	LastServed int64
+}
+
+// Order returns the first n items to dispatch. It repeatedly takes the
+// oldest item of the key with the smallest share, its tasks in flight plus
+// those taken so far divided by its weight. Ties go to the key served least
+// recently, so keys with nothing in flight take turns, then to the key
+// name. Keys with a weight below 1 count as weight 1.
+func Order(waiting []Item, queues map[string]Queue, weight func(key string) int32, n int) []Item {
+	items := map[string][]Item{}
+	for _, item := range waiting {
+		items[item.Key] = append(items[item.Key], item)
+	}
+	keys := make([]string, 0, len(items))
+	for key, queue := range items {
+		sort.SliceStable(queue, func(i, j int) bool {
+			if !queue[i].Enqueued.Equal(queue[j].Enqueued) {
+				return queue[i].Enqueued.Before(queue[j].Enqueued)
+			}
+			return queue[i].Name < queue[j].Name
+		})
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// taken counts the items taken per key, lastTaken is the position
+	// after the last one so keys served in this round count as served
+	// after every earlier dispatch
+	taken, lastTaken := map[string]int{}, map[string]int{}
+	share := func(key string) float64 {
+		w := weight(key)
+		if w < 1 {
+			w = 1
+		}
+		return float64(queues[key].InFlight+taken[key]) / float64(w)
+	}
+	before := func(a, b string) bool {
+		if sa, sb := share(a), share(b); sa != sb {
+			return sa < sb
+		}
+		if la, lb := lastTaken[a], lastTaken[b]; la != lb {
+			return la < lb
+		}
+		if sa, sb := queues[a].LastServed, queues[b].LastServed; sa != sb {
+			return sa < sb
+		}
+		return a < b
+	}
+
+	var order []Item
+	for len(order) < n {
+		best := ""
+		for _, key := range keys {
+			if taken[key] == len(items[key]) {
+				continue
+			}
+			if best == "" || before(key, best) {
+				best = key
+			}
+		}
+		if best == "" {
+			break
+		}
+		order = append(order, items[best][taken[best]])
+		taken[best]++
+		lastTaken[best] = len(order)
+	}
+	return order
+}
+
+// Position returns the index of the named item in order, or -1
+func Position(order []Item, name string) int {
+	for i, item := range order {
+		if item.Name == name {
+			return i
+		}
+	}
+	return -1
+}
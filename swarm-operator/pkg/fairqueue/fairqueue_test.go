@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fairqueue
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFairQueue(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fair Queue Suite")
+}
+
+var _ = Describe("Order", func() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := func(key, name string, minute int) Item {
+		return Item{Key: key, Name: name, Enqueued: start.Add(time.Duration(minute) * time.Minute)}
+	}
+	names := func(items []Item) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.Name)
+		}
+		return out
+	}
+	equal := func(string) int32 { return 1 }
+
+	It("interleaves keys instead of serving the oldest tasks first", func() {
+		waiting := []Item{
+			item("batch", "b1", 0), item("batch", "b2", 1), item("batch", "b3", 2),
+			item("web", "w1", 5), item("web", "w2", 6),
+		}
+		Expect(names(Order(waiting, nil, equal, 5))).To(Equal([]string{"b1", "w1", "b2", "w2", "b3"}))
+	})
+
+	It("gives keys dispatch slots in proportion to their weight", func() {
+		var waiting []Item
+		for i := 0; i < 6; i++ {
+			waiting = append(waiting, item("a", "a"+string(rune('0'+i)), i), item("b", "b"+string(rune('0'+i)), i))
+		}
+		weights := map[string]int32{"a": 2, "b": 1}
+		order := Order(waiting, nil, func(key string) int32 { return weights[key] }, 6)
+		counts := map[string]int{}
+		for _, item := range order {
+			counts[item.Key]++
+		}
+		Expect(counts).To(Equal(map[string]int{"a": 4, "b": 2}))
+	})
+
+	It("counts the tasks a key already has in flight", func() {
+		waiting := []Item{item("a", "a1", 0), item("b", "b1", 1), item("b", "b2", 2)}
+		order := Order(waiting, map[string]Queue{"a": {InFlight: 1}}, equal, 2)
+		Expect(names(order)).To(Equal([]string{"b1", "a1"}))
+		Expect(Position(order, "b2")).To(Equal(-1))
+	})
+
+	It("takes turns between keys with nothing in flight", func() {
+		waiting := []Item{item("a", "a1", 0), item("a", "a2", 1), item("b", "b1", 2)}
+		queues := map[string]Queue{"a": {LastServed: 2}, "b": {LastServed: 1}}
+		Expect(names(Order(waiting, queues, equal, 1))).To(Equal([]string{"b1"}))
+
+		queues["b"] = Queue{LastServed: 3}
+		Expect(names(Order(waiting, queues, equal, 1))).To(Equal([]string{"a1"}))
+	})
+
+	It("stops when the queue is empty", func() {
+		Expect(Order([]Item{item("a", "a1", 0)}, nil, func(string) int32 { return 0 }, 3)).To(HaveLen(1))
+		Expect(Order(nil, nil, equal, 3)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// PullRequest is the subset of pull request fields previews care about
+type PullRequest struct {
+	Number  int
+	HeadSHA string
+	HeadRef string
+	BaseRef string
+	Author  string
+	Labels  []string
+	Draft   bool
+	URL     string
+}
+
+// RepositoryClient reads pull requests and reports results for one repository
+type RepositoryClient struct {
+	client *github.Client
+	Owner  string
+	Repo   string
+}
+
+// SplitRepository splits an owner/repo string
+func SplitRepository(repository string) (string, string, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("repository %q is not in owner/repo form", repository)
+	}
+	return parts[0], parts[1], nil
+}
+
+// RepositoryClient returns a client for the repository authenticated with an
+// installation token of the GitHub App
+func (g *TokenGenerator) RepositoryClient(ctx context.Context, appConfig *swarmv1alpha1.GitHubAppConfig, repository, namespace string) (*RepositoryClient, error) {
+	owner, repo, err := SplitRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token is not restricted so it keeps the app's checks permission
+	token, err := g.GenerateToken(ctx, appConfig, nil, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RepositoryClient{
+		client: github.NewClient(nil).WithAuthToken(token),
+		Owner:  owner,
+		Repo:   repo,
+	}, nil
+}
+
+// ListOpenPullRequests returns every open pull request of the repository
+func (c *RepositoryClient) ListOpenPullRequests(ctx context.Context) ([]PullRequest, error) {
+	opts := &github.PullRequestListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var prs []PullRequest
+	for {
+		page, resp, err := c.client.PullRequests.List(ctx, c.Owner, c.Repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+		for _, pr := range page {
+			labels := make([]string, 0, len(pr.Labels))
+			for _, l := range pr.Labels {
+				labels = append(labels, l.GetName())
+			}
+			prs = append(prs, PullRequest{
+				Number:  pr.GetNumber(),
+				HeadSHA: pr.GetHead().GetSHA(),
+				HeadRef: pr.GetHead().GetRef(),
+				BaseRef: pr.GetBase().GetRef(),
+				Author:  pr.GetUser().GetLogin(),
+				Labels:  labels,
+				Draft:   pr.GetDraft(),
+				URL:     pr.GetHTMLURL(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return prs, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// ReportCheck creates a completed check run on the commit
+func (c *RepositoryClient) ReportCheck(ctx context.Context, name, headSHA string, success bool, title, summary string) error {
+	conclusion := "failure"
+	if success {
+		conclusion = "success"
+	}
+	_, _, err := c.client.Checks.CreateCheckRun(ctx, c.Owner, c.Repo, github.CreateCheckRunOptions{
+		Name:       name,
+		HeadSHA:    headSHA,
+		Status:     github.String("completed"),
+		Conclusion: github.String(conclusion),
+		Output: &github.CheckRunOutput{
+			Title:   github.String(title),
+			Summary: github.String(summary),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create check run: %w", err)
+	}
+	return nil
+}
+
+// Comment posts a comment on the pull request
+func (c *RepositoryClient) Comment(ctx context.Context, number int, body string) error {
+	_, _, err := c.client.Issues.CreateComment(ctx, c.Owner, c.Repo, number, &github.IssueComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to comment on pull request #%d: %w", number, err)
+	}
+	return nil
+}
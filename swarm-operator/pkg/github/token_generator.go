@@ -154,8 +154,9 @@ func (g *TokenGenerator) createAppJWT(appID int64, privateKey *rsa.PrivateKey) (
 	return token.SignedString(privateKey)
 }
 
-// CreateTokenSecret creates a Kubernetes secret containing the GitHub token
-func (g *TokenGenerator) CreateTokenSecret(ctx context.Context, name, namespace, token string, repositories []string, expiresAt time.Time) error {
+// CreateTokenSecret creates a Kubernetes secret containing the GitHub token.
+// The labels are added to those marking the secret as a token.
+func (g *TokenGenerator) CreateTokenSecret(ctx context.Context, name, namespace, token string, repositories []string, expiresAt time.Time, labels map[string]string) error {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -174,12 +175,16 @@ func (g *TokenGenerator) CreateTokenSecret(ctx context.Context, name, namespace,
 			"token": []byte(token),
 		},
 	}
+	for k, v := range labels {
+		secret.Labels[k] = v
+	}
 
 	return g.Create(ctx, secret)
 }
 
-// UpdateTokenSecret updates an existing token secret
-func (g *TokenGenerator) UpdateTokenSecret(ctx context.Context, name, namespace, token string, repositories []string, expiresAt time.Time) error {
+// UpdateTokenSecret updates an existing token secret, adding the labels
+// secrets created before them are missing
+func (g *TokenGenerator) UpdateTokenSecret(ctx context.Context, name, namespace, token string, repositories []string, expiresAt time.Time, labels map[string]string) error {
 	secret := &corev1.Secret{}
 	err := g.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret)
 	if err != nil {
@@ -190,6 +195,12 @@ func (g *TokenGenerator) UpdateTokenSecret(ctx context.Context, name, namespace,
 	secret.Annotations["swarm.claudeflow.io/expires-at"] = expiresAt.Format(time.RFC3339)
 	secret.Annotations["swarm.claudeflow.io/repositories"] = strings.Join(repositories, ",")
 	secret.Annotations["swarm.claudeflow.io/rotated-at"] = time.Now().Format(time.RFC3339)
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		secret.Labels[k] = v
+	}
 
 	return g.Update(ctx, secret)
 }
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpserver holds what the operator's own HTTP APIs share: running
+// a server for the lifetime of the manager, and authorizing Kubernetes
+// bearer tokens against the API server.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// shutdownTimeout bounds how long in-flight requests may finish once the
+// manager stops
+const shutdownTimeout = 5 * time.Second
+
+// Serve listens on address and serves handler until the context is
+// cancelled, then shuts the server down. Requests run under the context, so
+// long-lived streams end when the manager stops instead of holding up the
+// shutdown. name identifies the server in logs.
+func Serve(ctx context.Context, name, address string, handler http.Handler) error {
+	log := log.FromContext(ctx).WithName(name)
+
+	srv := &http.Server{
+		Addr:              address,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("Starting server", "address", address)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+// Authorize authenticates the bearer token of the request with a TokenReview
+// and checks the access described by attributes with a SubjectAccessReview
+// for the user it belongs to. Set either ResourceAttributes or
+// NonResourceAttributes. It returns the HTTP status to fail the request with.
+func Authorize(ctx context.Context, c client.Client, r *http.Request, attributes authorizationv1.SubjectAccessReviewSpec) (int, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return http.StatusUnauthorized, errors.New("missing bearer token")
+	}
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if err := c.Create(ctx, review); err != nil {
+		return http.StatusInternalServerError, errors.New("token review failed")
+	}
+	if !review.Status.Authenticated {
+		return http.StatusUnauthorized, errors.New("invalid bearer token")
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(review.Status.User.Extra))
+	for k, v := range review.Status.User.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	attributes.User = review.Status.User.Username
+	attributes.UID = review.Status.User.UID
+	attributes.Groups = review.Status.User.Groups
+	attributes.Extra = extra
+	access := &authorizationv1.SubjectAccessReview{Spec: attributes}
+	if err := c.Create(ctx, access); err != nil {
+		return http.StatusInternalServerError, errors.New("access review failed")
+	}
+	if !access.Status.Allowed {
+		return http.StatusForbidden, errors.New("forbidden")
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagescan asks a vulnerability scanner about an image and sums up
+// its report. The scanner is an HTTP service answering
+//
+//	POST <url> {"image": "<reference>"}
+//
+// with the JSON report of Trivy (trivy image --format json) or Grype
+// (grype -o json) for the image, such as a Trivy server or a Grype endpoint
+// behind a small scan API.
+package imagescan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Report formats
+const (
+	FormatTrivy = "trivy"
+	FormatGrype = "grype"
+)
+
+// maxReportSize bounds the reports read from the scanner
+const maxReportSize = 32 << 20
+
+// Summary counts the distinct vulnerabilities of an image by severity
+type Summary struct {
+	// Digest is the digest the scanner resolved the image to, empty when
+	// the report does not name it
+	Digest string
+
+	Critical int32
+	High     int32
+	Medium   int32
+	Low      int32
+	Unknown  int32
+}
+
+// Scanner scans images for vulnerabilities
+type Scanner interface {
+	Scan(ctx context.Context, image string) (*Summary, error)
+}
+
+// Client scans images through a scanner service
+type Client struct {
+	URL        string
+	Format     string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client of the scanner at url answering in format.
+// Scans of images the scanner has not seen before can take a while.
+func NewClient(url, format string) Scanner {
+	return &Client{URL: url, Format: format, HTTPClient: &http.Client{Timeout: 2 * time.Minute}}
+}
+
+// Scan asks the scanner for a report on the image
+func (c *Client) Scan(ctx context.Context, image string) (*Summary, error) {
+	body, err := json.Marshal(map[string]string{"image": image})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	report, err := io.ReadAll(io.LimitReader(resp.Body, maxReportSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("scanner returned %s: %s", resp.Status, strings.TrimSpace(string(report)))
+	}
+	return Parse(c.Format, report)
+}
+
+// trivyReport is the part of a Trivy JSON report the summary reads
+type trivyReport struct {
+	Metadata struct {
+		RepoDigests []string `json:"RepoDigests"`
+	} `json:"Metadata"`
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID string `json:"VulnerabilityID"`
+			Severity        string `json:"Severity"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// grypeReport is the part of a Grype JSON report the summary reads
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID       string `json:"id"`
+			Severity string `json:"severity"`
+		} `json:"vulnerability"`
+	} `json:"matches"`
+	Source struct {
+		Target struct {
+			ManifestDigest string   `json:"manifestDigest"`
+			RepoDigests    []string `json:"repoDigests"`
+		} `json:"target"`
+	} `json:"source"`
+}
+
+// Parse sums up a report of the given format. A vulnerability found in
+// several packages of the image counts once.
+func Parse(format string, report []byte) (*Summary, error) {
+	summary := &Summary{}
+	seen := map[string]bool{}
+	count := func(id, severity string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		switch strings.ToUpper(severity) {
+		case "CRITICAL":
+			summary.Critical++
+		case "HIGH":
+			summary.High++
+		case "MEDIUM":
+			summary.Medium++
+		case "LOW", "NEGLIGIBLE":
+			summary.Low++
+		default:
+			summary.Unknown++
+		}
+	}
+
+	switch format {
+	case FormatTrivy, "":
+		var parsed trivyReport
+		if err := json.Unmarshal(report, &parsed); err != nil {
+			return nil, fmt.Errorf("reading Trivy report: %w", err)
+		}
+		summary.Digest = repoDigest(parsed.Metadata.RepoDigests)
+		for _, result := range parsed.Results {
+			for _, v := range result.Vulnerabilities {
+				count(v.VulnerabilityID, v.Severity)
+			}
+		}
+	case FormatGrype:
+		var parsed grypeReport
+		if err := json.Unmarshal(report, &parsed); err != nil {
+			return nil, fmt.Errorf("reading Grype report: %w", err)
+		}
+		summary.Digest = repoDigest(parsed.Source.Target.RepoDigests)
+		if summary.Digest == "" {
+			summary.Digest = parsed.Source.Target.ManifestDigest
+		}
+		for _, match := range parsed.Matches {
+			count(match.Vulnerability.ID, match.Vulnerability.Severity)
+		}
+	default:
+		return nil, fmt.Errorf("unknown report format %q", format)
+	}
+	return summary, nil
+}
+
+// repoDigest returns the digest of the first repo@digest reference
+func repoDigest(references []string) string {
+	for _, reference := range references {
+		if _, digest, ok := strings.Cut(reference, "@"); ok {
+			return digest
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestImageScan(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Image Scan Suite")
+}
+
+const trivyReportJSON = `{
+  "ArtifactName": "ghcr.io/claude-flow/executor:v1",
+  "Metadata": {"RepoDigests": ["ghcr.io/claude-flow/executor@sha256:abc"]},
+  "Results": [
+    {"Target": "debian", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"},
+      {"VulnerabilityID": "CVE-2", "Severity": "HIGH"},
+      {"VulnerabilityID": "CVE-3", "Severity": "LOW"}
+    ]},
+    {"Target": "go.sum", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-1", "Severity": "CRITICAL"},
+      {"VulnerabilityID": "CVE-4", "Severity": "MEDIUM"}
+    ]},
+    {"Target": "node", "Vulnerabilities": null}
+  ]
+}`
+
+const grypeReportJSON = `{
+  "matches": [
+    {"vulnerability": {"id": "CVE-1", "severity": "Critical"}},
+    {"vulnerability": {"id": "CVE-5", "severity": "Negligible"}},
+    {"vulnerability": {"id": "GHSA-1", "severity": "Unknown"}}
+  ],
+  "source": {"type": "image", "target": {"manifestDigest": "sha256:def", "repoDigests": []}}
+}`
+
+var _ = Describe("Image scans", func() {
+	It("sums up Trivy reports", func() {
+		summary, err := Parse(FormatTrivy, []byte(trivyReportJSON))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*summary).To(Equal(Summary{Digest: "sha256:abc", Critical: 1, High: 1, Medium: 1, Low: 1}))
+	})
+
+	It("sums up Grype reports", func() {
+		summary, err := Parse(FormatGrype, []byte(grypeReportJSON))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*summary).To(Equal(Summary{Digest: "sha256:def", Critical: 1, Low: 1, Unknown: 1}))
+	})
+
+	It("rejects unknown formats and broken reports", func() {
+		_, err := Parse("clair", []byte(trivyReportJSON))
+		Expect(err).To(MatchError(`unknown report format "clair"`))
+		_, err = Parse(FormatGrype, []byte("<html>"))
+		Expect(err).To(MatchError(ContainSubstring("reading Grype report")))
+	})
+
+	It("asks the scanner about the image", func() {
+		var requested map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(json.NewDecoder(r.Body).Decode(&requested)).To(Succeed())
+			_, _ = w.Write([]byte(trivyReportJSON))
+		}))
+		defer server.Close()
+
+		summary, err := NewClient(server.URL, FormatTrivy).Scan(context.Background(), "ghcr.io/claude-flow/executor:v1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requested).To(Equal(map[string]string{"image": "ghcr.io/claude-flow/executor:v1"}))
+		Expect(summary.Critical).To(Equal(int32(1)))
+	})
+
+	It("reports scanner errors", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "image not found", http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := NewClient(server.URL, FormatTrivy).Scan(context.Background(), "missing:v1")
+		Expect(err).To(MatchError("scanner returned 404 Not Found: image not found"))
+	})
+})
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorycache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMemoryCache(t *testing.T) {
+	RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Memory Cache Suite")
+}
+
+type fakeBackend struct {
+	entries map[string]*Entry
+	gets    int
+}
+
+func (b *fakeBackend) Get(_ context.Context, namespace, key string) (*Entry, error) {
+	b.gets++
+	e, ok := b.entries[cacheKey(namespace, key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return e, nil
+}
+
+func (b *fakeBackend) Put(_ context.Context, namespace, key string, e *Entry) error {
+	b.entries[cacheKey(namespace, key)] = e
+	return nil
+}
+
+func (b *fakeBackend) Delete(_ context.Context, namespace, key string) error {
+	delete(b.entries, cacheKey(namespace, key))
+	return nil
+}
+
+type fakePublisher struct {
+	published []Invalidation
+}
+
+func (p *fakePublisher) Publish(_ context.Context, inv Invalidation) error {
+	p.published = append(p.published, inv)
+	return nil
+}
+
+var _ = ginkgo.Describe("Cache", func() {
+	ginkgo.It("should evict the least recently used entry", func() {
+		cache := NewCache(2)
+		cache.Set("a", "1", 0)
+		cache.Set("b", "2", 0)
+		_, _ = cache.Get("a")
+		cache.Set("c", "3", 0)
+
+		_, ok := cache.Get("b")
+		Expect(ok).To(BeFalse())
+		_, ok = cache.Get("a")
+		Expect(ok).To(BeTrue())
+		Expect(cache.Stats().Evictions).To(Equal(int64(1)))
+	})
+
+	ginkgo.It("should expire entries after their TTL", func() {
+		now := time.Now()
+		cache := NewCache(10)
+		cache.now = func() time.Time { return now }
+		cache.Set("a", "1", time.Minute)
+
+		now = now.Add(2 * time.Minute)
+		_, ok := cache.Get("a")
+		Expect(ok).To(BeFalse())
+		Expect(cache.Stats().Entries).To(Equal(0))
+	})
+})
+
+var _ = ginkgo.Describe("Proxy", func() {
+	var (
+		ctx       context.Context
+		backend   *fakeBackend
+		publisher *fakePublisher
+		proxy     *Proxy
+	)
+
+	ginkgo.BeforeEach(func() {
+		ctx = context.Background()
+		backend = &fakeBackend{entries: map[string]*Entry{"shared/k": {Value: "v"}}}
+		publisher = &fakePublisher{}
+		proxy = &Proxy{Cache: NewCache(10), Backend: backend, Publisher: publisher, Agent: "agent-a"}
+	})
+
+	ginkgo.It("should serve repeated reads from the cache", func() {
+		for i := 0; i < 3; i++ {
+			e, err := proxy.Get(ctx, "shared", "k")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e.Value).To(Equal("v"))
+		}
+		Expect(backend.gets).To(Equal(1))
+		Expect(proxy.Cache.Stats().HitRate).To(Equal("66.7%"))
+	})
+
+	ginkgo.It("should write through and publish an invalidation", func() {
+		Expect(proxy.Put(ctx, "shared", "k", &Entry{Value: "v2"})).To(Succeed())
+		Expect(backend.entries["shared/k"].Value).To(Equal("v2"))
+		Expect(publisher.published).To(ConsistOf(Invalidation{Namespace: "shared", Key: "k", Source: "agent-a"}))
+	})
+
+	ginkgo.It("should drop entries invalidated by peers", func() {
+		_, _ = proxy.Get(ctx, "shared", "k")
+		backend.entries["shared/k"] = &Entry{Value: "changed"}
+		proxy.Invalidate(Invalidation{Namespace: "shared", Key: "k"})
+
+		e, err := proxy.Get(ctx, "shared", "k")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e.Value).To(Equal("changed"))
+	})
+})
+
+var _ = ginkgo.Describe("HTTPBackend", func() {
+	ginkgo.It("should read per-key access stats", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.EscapedPath() {
+			case "/v1/stats/shared/task%2Fresult":
+				writeJSON(w, http.StatusOK, EntryStats{AccessCount: 7})
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		backend := NewHTTPBackend(server.URL)
+		stats, err := backend.Stats(context.Background(), "shared", "task/result")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats.AccessCount).To(Equal(int64(7)))
+
+		_, err = backend.Stats(context.Background(), "shared", "missing")
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+})
@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorycache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// PatternOutcome is one success or failure of a learned pattern. The memory
+// service adds it to the success and failure counts of the pattern, creating
+// the pattern on its first outcome.
+type PatternOutcome struct {
+	// PatternID identifies the pattern within the swarm
+	PatternID string `json:"patternId"`
+
+	// SwarmID is the cluster the pattern was learned in
+	SwarmID string `json:"swarmId"`
+
+	// Type groups patterns for scoring, e.g. "agent-fallback"
+	Type string `json:"type"`
+
+	// Success is true if the pattern led to a successful outcome
+	Success bool `json:"success"`
+
+	// Data is free-form context stored with the pattern
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// PatternBackend is implemented by backends that keep the pattern table
+type PatternBackend interface {
+	RecordPatternOutcome(ctx context.Context, outcome *PatternOutcome) error
+}
+
+// RecordPatternOutcome implements PatternBackend using
+// POST /v1/patterns/{patternId}/outcomes
+func (b *HTTPBackend) RecordPatternOutcome(ctx context.Context, outcome *PatternOutcome) error {
+	body, err := json.Marshal(outcome)
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/v1/patterns/%s/outcomes", b.BaseURL, url.PathEscape(outcome.PatternID))
+	resp, err := b.do(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorycache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by backends for keys that do not exist
+var ErrNotFound = errors.New("memory entry not found")
+
+// Entry is a memory value as exchanged with agents and the memory service
+type Entry struct {
+	Value string `json:"value"`
+	// TTL in seconds, 0 means permanent
+	TTL int32 `json:"ttl,omitempty"`
+}
+
+// Invalidation announces that a key changed and cached copies are stale
+type Invalidation struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Source    string `json:"source,omitempty"`
+}
+
+// Backend is the authoritative memory store behind the cache
+type Backend interface {
+	Get(ctx context.Context, namespace, key string) (*Entry, error)
+	Put(ctx context.Context, namespace, key string, e *Entry) error
+	Delete(ctx context.Context, namespace, key string) error
+}
+
+// EntryStats reports how often the memory service served a key
+type EntryStats struct {
+	AccessCount    int64      `json:"accessCount"`
+	LastAccessTime *time.Time `json:"lastAccessTime,omitempty"`
+}
+
+// StatsBackend is implemented by backends that track per-key access counts
+type StatsBackend interface {
+	Stats(ctx context.Context, namespace, key string) (*EntryStats, error)
+}
+
+// Publisher broadcasts invalidations over the hive-mind sync channel
+type Publisher interface {
+	Publish(ctx context.Context, inv Invalidation) error
+}
+
+// Proxy serves agent memory reads from a local cache and writes through to
+// the backend, publishing invalidations so peers drop stale copies
+type Proxy struct {
+	Cache     *Cache
+	Backend   Backend
+	Publisher Publisher
+
+	// Agent identifies this proxy in published invalidations
+	Agent string
+
+	// DefaultTTL bounds how long entries without a TTL stay cached
+	DefaultTTL time.Duration
+}
+
+func cacheKey(namespace, key string) string {
+	return namespace + "/" + key
+}
+
+// Get returns the entry from cache, falling back to the backend
+func (p *Proxy) Get(ctx context.Context, namespace, key string) (*Entry, error) {
+	if value, ok := p.Cache.Get(cacheKey(namespace, key)); ok {
+		return &Entry{Value: value}, nil
+	}
+
+	e, err := p.Backend.Get(ctx, namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	p.Cache.Set(cacheKey(namespace, key), e.Value, p.ttl(e))
+	return e, nil
+}
+
+// Put writes the entry to the backend first and caches it only on success
+func (p *Proxy) Put(ctx context.Context, namespace, key string, e *Entry) error {
+	if err := p.Backend.Put(ctx, namespace, key, e); err != nil {
+		return err
+	}
+	p.Cache.Set(cacheKey(namespace, key), e.Value, p.ttl(e))
+	p.publish(ctx, namespace, key)
+	return nil
+}
+
+// Delete removes the entry from the backend and the cache
+func (p *Proxy) Delete(ctx context.Context, namespace, key string) error {
+	if err := p.Backend.Delete(ctx, namespace, key); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	p.Cache.Invalidate(cacheKey(namespace, key))
+	p.publish(ctx, namespace, key)
+	return nil
+}
+
+// Invalidate drops a key announced as changed by a peer
+func (p *Proxy) Invalidate(inv Invalidation) {
+	p.Cache.Invalidate(cacheKey(inv.Namespace, inv.Key))
+}
+
+// ttl caps the entry TTL at the proxy default so remote changes that miss
+// the sync channel are still picked up eventually
+func (p *Proxy) ttl(e *Entry) time.Duration {
+	ttl := time.Duration(e.TTL) * time.Second
+	if p.DefaultTTL > 0 && (ttl == 0 || ttl > p.DefaultTTL) {
+		ttl = p.DefaultTTL
+	}
+	return ttl
+}
+
+func (p *Proxy) publish(ctx context.Context, namespace, key string) {
+	if p.Publisher == nil {
+		return
+	}
+	// Invalidation is best effort; the TTL bounds staleness on failure
+	_ = p.Publisher.Publish(ctx, Invalidation{Namespace: namespace, Key: key, Source: p.Agent})
+}
+
+// ServeHTTP exposes the proxy to the agent:
+//
+//	GET|PUT|DELETE /v1/memory/{namespace}/{key}
+//	POST           /v1/invalidate
+//	POST           /v1/search
+//	GET            /v1/stats
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/v1/stats" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, p.Cache.Stats())
+	case r.URL.Path == "/v1/invalidate" && r.Method == http.MethodPost:
+		var inv Invalidation
+		if err := json.NewDecoder(r.Body).Decode(&inv); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p.Invalidate(inv)
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/v1/search" && r.Method == http.MethodPost:
+		p.serveSearch(w, r)
+	case strings.HasPrefix(r.URL.Path, "/v1/memory/"):
+		p.serveMemory(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *Proxy) serveMemory(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v1/memory/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.Error(w, "expected /v1/memory/{namespace}/{key}", http.StatusBadRequest)
+		return
+	}
+	namespace, key := parts[0], parts[1]
+
+	switch r.Method {
+	case http.MethodGet:
+		e, err := p.Get(r.Context(), namespace, key)
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, http.StatusOK, e)
+	case http.MethodPut:
+		e := &Entry{}
+		if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := p.Put(r.Context(), namespace, key, e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		if err := p.Delete(r.Context(), namespace, key); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// FormatHitRate renders a hit rate percentage the way SwarmMemoryStore
+// status reports it
+func FormatHitRate(hits, misses int64) string {
+	if hits+misses == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(hits)/float64(hits+misses)*100)
+}
+
+// HTTPBackend talks to the memory service HTTP API
+type HTTPBackend struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewHTTPBackend creates a backend for the memory service at baseURL
+func NewHTTPBackend(baseURL string) *HTTPBackend {
+	return &HTTPBackend{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (b *HTTPBackend) url(namespace, key string) string {
+	return fmt.Sprintf("%s/v1/memory/%s/%s", b.BaseURL, url.PathEscape(namespace), url.PathEscape(key))
+}
+
+// Get implements Backend
+func (b *HTTPBackend) Get(ctx context.Context, namespace, key string) (*Entry, error) {
+	resp, err := b.do(ctx, http.MethodGet, b.url(namespace, key), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	e := &Entry{}
+	if err := json.NewDecoder(resp.Body).Decode(e); err != nil {
+		return nil, fmt.Errorf("failed to decode memory entry: %w", err)
+	}
+	return e, nil
+}
+
+// Put implements Backend
+func (b *HTTPBackend) Put(ctx context.Context, namespace, key string, e *Entry) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	resp, err := b.do(ctx, http.MethodPut, b.url(namespace, key), body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Stats implements StatsBackend using GET /v1/stats/{namespace}/{key}
+func (b *HTTPBackend) Stats(ctx context.Context, namespace, key string) (*EntryStats, error) {
+	u := fmt.Sprintf("%s/v1/stats/%s/%s", b.BaseURL, url.PathEscape(namespace), url.PathEscape(key))
+	resp, err := b.do(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	stats := &EntryStats{}
+	if err := json.NewDecoder(resp.Body).Decode(stats); err != nil {
+		return nil, fmt.Errorf("failed to decode memory stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Delete implements Backend
+func (b *HTTPBackend) Delete(ctx context.Context, namespace, key string) error {
+	resp, err := b.do(ctx, http.MethodDelete, b.url(namespace, key), nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (b *HTTPBackend) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("memory service returned %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// HTTPPublisher posts invalidations to the hive-mind sync endpoint, which
+// fans them out to the /v1/invalidate endpoint of every peer proxy
+type HTTPPublisher struct {
+	URL    string
+	Client *http.Client
+}
+
+// Publish implements Publisher
+func (p *HTTPPublisher) Publish(ctx context.Context, inv Invalidation) error {
+	body, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sync channel returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorycache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// DefaultSearchLimit and MaxSearchLimit bound the matches of a query
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 100
+
+	// Kinds of memory a search can be restricted to
+	SearchKindEntry   = "entry"
+	SearchKindPattern = "pattern"
+)
+
+// SearchQuery is a similarity search over the embeddings of stored entries
+// and learned patterns
+type SearchQuery struct {
+	// Text is embedded by the memory service. Either Text or Vector is set.
+	Text string `json:"text,omitempty"`
+
+	// Vector is compared with the stored embeddings as is
+	Vector []float32 `json:"vector,omitempty"`
+
+	// Namespace restricts entry matches to one memory namespace
+	Namespace string `json:"namespace,omitempty"`
+
+	// Kind restricts matches to entries or patterns
+	Kind string `json:"kind,omitempty"`
+
+	// Limit is the maximum number of matches, DefaultSearchLimit when 0
+	Limit int `json:"limit,omitempty"`
+
+	// MinScore drops matches less similar than this
+	MinScore float64 `json:"minScore,omitempty"`
+}
+
+// SearchMatch is a stored entry or pattern similar to the query
+type SearchMatch struct {
+	Kind      string  `json:"kind"`
+	Namespace string  `json:"namespace,omitempty"`
+	Key       string  `json:"key"`
+	Value     string  `json:"value"`
+	Score     float64 `json:"score"`
+}
+
+// SearchResult lists the matches of a query, most similar first
+type SearchResult struct {
+	Matches []SearchMatch `json:"matches"`
+}
+
+// SearchBackend is implemented by backends with a vector index
+type SearchBackend interface {
+	Search(ctx context.Context, q *SearchQuery) (*SearchResult, error)
+}
+
+// Validate checks the query and applies the default limit
+func (q *SearchQuery) Validate() error {
+	switch {
+	case q.Text == "" && len(q.Vector) == 0:
+		return errors.New("either text or vector is required")
+	case q.Text != "" && len(q.Vector) > 0:
+		return errors.New("text and vector are mutually exclusive")
+	case q.Kind != "" && q.Kind != SearchKindEntry && q.Kind != SearchKindPattern:
+		return fmt.Errorf("kind must be %q or %q", SearchKindEntry, SearchKindPattern)
+	case q.Limit < 0 || q.Limit > MaxSearchLimit:
+		return fmt.Errorf("limit must be between 0 and %d", MaxSearchLimit)
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultSearchLimit
+	}
+	return nil
+}
+
+// Search implements SearchBackend using POST /v1/search
+func (b *HTTPBackend) Search(ctx context.Context, q *SearchQuery) (*SearchResult, error) {
+	body, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.do(ctx, http.MethodPost, b.BaseURL+"/v1/search", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &SearchResult{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to decode search result: %w", err)
+	}
+	return result, nil
+}
+
+// serveSearch passes similarity queries through to the backend. Results
+// are not cached since every write can change them.
+func (p *Proxy) serveSearch(w http.ResponseWriter, r *http.Request) {
+	backend, ok := p.Backend.(SearchBackend)
+	if !ok {
+		http.Error(w, "the memory backend has no vector index", http.StatusNotImplemented)
+		return
+	}
+	q := &SearchQuery{}
+	if err := json.NewDecoder(r.Body).Decode(q); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := q.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := backend.Search(r.Context(), q)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "the memory service has no vector index", http.StatusNotImplemented)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
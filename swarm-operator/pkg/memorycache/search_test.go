@@ -0,0 +1,74 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorycache
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Search", func() {
+	ginkgo.It("should validate queries and default the limit", func() {
+		q := &SearchQuery{Text: "retry backoff"}
+		Expect(q.Validate()).To(Succeed())
+		Expect(q.Limit).To(Equal(DefaultSearchLimit))
+
+		Expect((&SearchQuery{}).Validate()).NotTo(Succeed())
+		Expect((&SearchQuery{Text: "a", Vector: []float32{1}}).Validate()).NotTo(Succeed())
+		Expect((&SearchQuery{Text: "a", Kind: "task"}).Validate()).NotTo(Succeed())
+		Expect((&SearchQuery{Text: "a", Limit: MaxSearchLimit + 1}).Validate()).NotTo(Succeed())
+	})
+
+	ginkgo.It("should pass queries through the proxy to the memory service", func() {
+		var received SearchQuery
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/search" || r.Method != http.MethodPost {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewDecoder(r.Body).Decode(&received)
+			writeJSON(w, http.StatusOK, SearchResult{Matches: []SearchMatch{
+				{Kind: SearchKindPattern, Key: "retry-with-jitter", Value: "...", Score: 0.92},
+			}})
+		}))
+		defer server.Close()
+
+		proxy := &Proxy{Cache: NewCache(10), Backend: NewHTTPBackend(server.URL), Publisher: &fakePublisher{}}
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/search",
+			strings.NewReader(`{"text":"retry backoff","kind":"pattern"}`)))
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(received.Limit).To(Equal(DefaultSearchLimit))
+		result := SearchResult{}
+		Expect(json.NewDecoder(rec.Body).Decode(&result)).To(Succeed())
+		Expect(result.Matches).To(HaveLen(1))
+		Expect(result.Matches[0].Key).To(Equal("retry-with-jitter"))
+	})
+
+	ginkgo.It("should reject searches when the backend has no vector index", func() {
+		proxy := &Proxy{Cache: NewCache(10), Backend: &fakeBackend{entries: map[string]*Entry{}}}
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/search", strings.NewReader(`{"text":"x"}`)))
+		Expect(rec.Code).To(Equal(http.StatusNotImplemented))
+	})
+})
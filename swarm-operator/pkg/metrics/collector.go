@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -36,7 +38,7 @@ var (
 			Name: "swarm_cluster_phase",
 			Help: "Current phase of SwarmCluster (1 for the current phase, 0 for others)",
 		},
-		[]string{"namespace", "name", "phase"},
+		[]string{"namespace", "name", "phase", "tenant"},
 	)
 
 	swarmClusterAgents = prometheus.NewGaugeVec(
@@ -44,7 +46,31 @@ var (
 			Name: "swarm_cluster_agents",
 			Help: "Number of agents in the swarm cluster",
 		},
-		[]string{"namespace", "name", "status"},
+		[]string{"namespace", "name", "status", "tenant"},
+	)
+
+	swarmClusterFrozen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_cluster_frozen",
+			Help: "Whether the swarm cluster is frozen by a maintenance window (1) or not (0)",
+		},
+		[]string{"namespace", "name", "window", "tenant"},
+	)
+
+	swarmClusterHealthScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_cluster_health_score",
+			Help: "Weighted health score of the swarm cluster from 0 to 100",
+		},
+		[]string{"namespace", "name", "tenant"},
+	)
+
+	swarmClusterHealthSignal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_cluster_health_signal",
+			Help: "Score from 0 to 100 of each signal of the swarm cluster health score",
+		},
+		[]string{"namespace", "name", "signal", "tenant"},
 	)
 
 	// Agent metrics
@@ -102,7 +128,7 @@ var (
 			Name: "swarm_task_queue_size",
 			Help: "Current size of the task queue",
 		},
-		[]string{"namespace", "swarm_cluster"},
+		[]string{"namespace", "swarm_cluster", "tenant"},
 	)
 
 	taskDuration = prometheus.NewHistogramVec(
@@ -111,7 +137,7 @@ var (
 			Help:    "Duration of task execution in seconds",
 			Buckets: prometheus.ExponentialBuckets(0.1, 2, 10), // 0.1s to ~100s
 		},
-		[]string{"namespace", "swarm_cluster", "agent_type", "task_type"},
+		[]string{"namespace", "swarm_cluster", "agent_type", "task_type", "tenant"},
 	)
 
 	taskSuccessRate = prometheus.NewGaugeVec(
@@ -119,7 +145,7 @@ var (
 			Name: "swarm_task_success_rate",
 			Help: "Success rate of tasks (0-1)",
 		},
-		[]string{"namespace", "swarm_cluster"},
+		[]string{"namespace", "swarm_cluster", "tenant"},
 	)
 
 	// Topology metrics
@@ -146,7 +172,7 @@ var (
 			Name: "swarm_autoscaling_events_total",
 			Help: "Total number of autoscaling events",
 		},
-		[]string{"namespace", "swarm_cluster", "direction"},
+		[]string{"namespace", "swarm_cluster", "direction", "tenant"},
 	)
 
 	autoscalingTargetAgents = prometheus.NewGaugeVec(
@@ -154,7 +180,31 @@ var (
 			Name: "swarm_autoscaling_target_agents",
 			Help: "Target number of agents based on autoscaling calculations",
 		},
-		[]string{"namespace", "swarm_cluster"},
+		[]string{"namespace", "swarm_cluster", "tenant"},
+	)
+
+	autoscalingQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_autoscaling_queue_depth",
+			Help: "Pending tasks waiting for an agent type",
+		},
+		[]string{"namespace", "swarm_cluster", "agent_type", "tenant"},
+	)
+
+	autoscalingTaskLatencyP95 = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_autoscaling_task_latency_p95_seconds",
+			Help: "95th percentile task duration of an agent type over the recent window",
+		},
+		[]string{"namespace", "swarm_cluster", "agent_type", "tenant"},
+	)
+
+	autoscalingAgentTypeTarget = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_autoscaling_agent_type_target",
+			Help: "Target number of agents of an agent type",
+		},
+		[]string{"namespace", "swarm_cluster", "agent_type", "tenant"},
 	)
 
 	// Controller metrics
@@ -174,6 +224,183 @@ var (
 		},
 		[]string{"controller"},
 	)
+
+	reconcileRequeues = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_controller_reconcile_requeues_total",
+			Help: "Total number of successful reconciliations that asked to be requeued",
+		},
+		[]string{"controller"},
+	)
+
+	// API server back-pressure metrics
+	apiClientRateLimitWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "swarm_api_client_rate_limiter_wait_seconds",
+			Help:    "Time requests of a controller waited for its client-side rate limit",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 8), // 1ms to ~16s
+		},
+		[]string{"controller"},
+	)
+
+	apiServerThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_api_server_throttled_total",
+			Help: "Total number of operator requests the API server throttled with a 429, by priority level",
+		},
+		[]string{"priority_level"},
+	)
+
+	reconcileThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_controller_reconcile_throttled_total",
+			Help: "Total number of reconciliations requeued because the API server throttled them after client retries",
+		},
+		[]string{"controller"},
+	)
+
+	// Preflight metrics
+	preflightOK = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "swarm_operator_preflight_ok",
+			Help: "Whether all operator preflight checks passed (1) or not (0)",
+		},
+	)
+
+	preflightCheck = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_operator_preflight_check",
+			Help: "Result of an individual preflight check (1 for passed, 0 for failed)",
+		},
+		[]string{"check"},
+	)
+
+	executorJobOutcomes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_task_executor_jobs_total",
+			Help: "Total number of finished task Jobs by executor image and result",
+		},
+		[]string{"namespace", "swarm_cluster", "image", "result", "tenant"},
+	)
+
+	// Placement metrics
+	placementHints = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_task_placement_hints_total",
+			Help: "Total number of task Jobs given a bin-packing node hint, by result (hinted, no_fit)",
+		},
+		[]string{"namespace", "swarm_cluster", "result", "tenant"},
+	)
+
+	placementEfficiency = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_task_placement_efficiency",
+			Help: "Share of CPU requested on the nodes running swarm pods at the last placement",
+		},
+		[]string{"namespace", "swarm_cluster", "tenant"},
+	)
+
+	// Scheduling policy metrics
+	schedulingPolicyEvaluations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_scheduling_policy_evaluations_total",
+			Help: "Total number of scheduling policy evaluations, by result (pass, fail, score, error)",
+		},
+		[]string{"namespace", "swarm_cluster", "policy", "result", "tenant"},
+	)
+
+	schedulingPolicyDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "swarm_scheduling_policy_evaluation_duration_seconds",
+			Help:    "Time spent evaluating a scheduling policy for one agent",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 8), // 10µs to ~160ms
+		},
+		[]string{"namespace", "swarm_cluster", "policy"},
+	)
+
+	// Fair queuing metrics
+	fairQueueDispatched = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_task_fair_queue_dispatched_total",
+			Help: "Total number of tasks dispatched by fair queuing, by queue key",
+		},
+		[]string{"namespace", "swarm_cluster", "key", "tenant"},
+	)
+
+	fairQueueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "swarm_task_fair_queue_wait_seconds",
+			Help:    "Time tasks waited from creation until fair queuing dispatched them, by queue key",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 14), // 1s to ~2.3h
+		},
+		[]string{"namespace", "swarm_cluster", "key", "tenant"},
+	)
+
+	fairQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_task_fair_queue_depth",
+			Help: "Number of tasks waiting for a dispatch slot, by queue key",
+		},
+		[]string{"namespace", "swarm_cluster", "key", "tenant"},
+	)
+
+	// Task reference metrics
+	taskReferenceInconsistencies = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_task_reference_inconsistencies_total",
+			Help: "Total number of drifted agent and task references repaired, by reason",
+		},
+		[]string{"namespace", "swarm_cluster", "reason", "tenant"},
+	)
+
+	// Circuit breaker metrics
+	circuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_operator_circuit_breaker_state",
+			Help: "State of the circuit breaker for an external dependency (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"dependency"},
+	)
+
+	circuitBreakerTrips = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_operator_circuit_breaker_trips_total",
+			Help: "Total number of times the circuit breaker for an external dependency opened",
+		},
+		[]string{"dependency"},
+	)
+
+	// Garbage collection metrics
+	gcDeleted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_gc_deleted_total",
+			Help: "Total number of released task resources garbage collected, by kind (pvc, secret)",
+		},
+		[]string{"kind"},
+	)
+
+	gcReclaimedBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "swarm_gc_reclaimed_bytes_total",
+			Help: "Total storage capacity of the task volumes garbage collected, in bytes",
+		},
+	)
+
+	gcDryRunResources = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_gc_dry_run_resources",
+			Help: "Number of task resources the last dry run of the garbage collector would delete, by kind (pvc, secret)",
+		},
+		[]string{"kind"},
+	)
+
+	gcDryRunBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_gc_dry_run_reclaimable_bytes",
+			Help: "Storage capacity of the task resources the last dry run of the garbage collector would delete, in bytes",
+		},
+		[]string{"kind"},
+	)
 )
 
 func init() {
@@ -183,6 +410,9 @@ func init() {
 		swarmClusterTotal,
 		swarmClusterPhase,
 		swarmClusterAgents,
+		swarmClusterFrozen,
+		swarmClusterHealthScore,
+		swarmClusterHealthSignal,
 		
 		// Agent metrics
 		agentTotal,
@@ -196,6 +426,7 @@ func init() {
 		taskQueueSize,
 		taskDuration,
 		taskSuccessRate,
+		executorJobOutcomes,
 		
 		// Topology metrics
 		topologyPeerConnections,
@@ -204,19 +435,71 @@ func init() {
 		// Autoscaling metrics
 		autoscalingEvents,
 		autoscalingTargetAgents,
+		autoscalingQueueDepth,
+		autoscalingTaskLatencyP95,
+		autoscalingAgentTypeTarget,
 		
 		// Controller metrics
 		reconcileTotal,
 		reconcileDuration,
+		reconcileRequeues,
+		apiClientRateLimitWait,
+		apiServerThrottled,
+		reconcileThrottled,
+
+		// Preflight metrics
+		preflightOK,
+		preflightCheck,
+
+		// Placement metrics
+		placementHints,
+		placementEfficiency,
+
+		// Scheduling policy metrics
+		schedulingPolicyEvaluations,
+		schedulingPolicyDuration,
+
+		// Fair queuing metrics
+		fairQueueDispatched,
+		fairQueueWait,
+		fairQueueDepth,
+
+		// Task reference metrics
+		taskReferenceInconsistencies,
+
+		// Circuit breaker metrics
+		circuitBreakerState,
+		circuitBreakerTrips,
+
+		// Garbage collection metrics
+		gcDeleted,
+		gcReclaimedBytes,
+		gcDryRunResources,
+		gcDryRunBytes,
+
+		// Tenant metrics
+		tenantClusters,
+		tenantRunningTasks,
+
+		// SLO metrics
+		sloEvents,
+		sloCompliance,
+		sloErrorBudgetRemaining,
+		sloBurnRate,
 	)
 }
 
 // MetricsRecorder provides methods to record metrics
-type MetricsRecorder struct{}
+type MetricsRecorder struct {
+	latency  *latencyWindows
+	outcomes *executorOutcomes
+	tenants  *tenantIndex
+	slos     *sloWindows
+}
 
 // NewMetricsRecorder creates a new metrics recorder
 func NewMetricsRecorder() *MetricsRecorder {
-	return &MetricsRecorder{}
+	return &MetricsRecorder{latency: newLatencyWindows(), outcomes: newExecutorOutcomes(), tenants: newTenantIndex(), slos: newSLOWindows()}
 }
 
 // RecordSwarmClusterPhase records the current phase of a SwarmCluster
@@ -227,14 +510,14 @@ func (m *MetricsRecorder) RecordSwarmClusterPhase(namespace, name, phase string)
 		if p == phase {
 			value = 1.0
 		}
-		swarmClusterPhase.WithLabelValues(namespace, name, p).Set(value)
+		swarmClusterPhase.WithLabelValues(namespace, name, p, m.tenant(namespace, name)).Set(value)
 	}
 }
 
 // RecordSwarmClusterAgents records agent counts
 func (m *MetricsRecorder) RecordSwarmClusterAgents(namespace, name string, active, ready int32) {
-	swarmClusterAgents.WithLabelValues(namespace, name, "active").Set(float64(active))
-	swarmClusterAgents.WithLabelValues(namespace, name, "ready").Set(float64(ready))
+	swarmClusterAgents.WithLabelValues(namespace, name, "active", m.tenant(namespace, name)).Set(float64(active))
+	swarmClusterAgents.WithLabelValues(namespace, name, "ready", m.tenant(namespace, name)).Set(float64(ready))
 }
 
 // RecordAgentPhase records the current phase of an Agent
@@ -267,17 +550,60 @@ func (m *MetricsRecorder) RecordAgentResourceUsage(namespace, name, agentType st
 
 // RecordTaskQueueSize records the task queue size
 func (m *MetricsRecorder) RecordTaskQueueSize(namespace, swarmCluster string, size int32) {
-	taskQueueSize.WithLabelValues(namespace, swarmCluster).Set(float64(size))
+	taskQueueSize.WithLabelValues(namespace, swarmCluster, m.tenant(namespace, swarmCluster)).Set(float64(size))
 }
 
 // RecordTaskDuration records task execution duration
 func (m *MetricsRecorder) RecordTaskDuration(namespace, swarmCluster, agentType, taskType string, duration float64) {
-	taskDuration.WithLabelValues(namespace, swarmCluster, agentType, taskType).Observe(duration)
+	taskDuration.WithLabelValues(namespace, swarmCluster, agentType, taskType, m.tenant(namespace, swarmCluster)).Observe(duration)
+	m.latency.observe(latencyKey{namespace, swarmCluster, agentType}, duration)
+}
+
+// TaskLatencyQuantile returns the q-quantile of the task durations in
+// seconds recorded for an agent type over the last 15 minutes. ok is false
+// when no task finished in that window.
+func (m *MetricsRecorder) TaskLatencyQuantile(namespace, swarmCluster, agentType string, q float64) (seconds float64, ok bool) {
+	return m.latency.quantile(latencyKey{namespace, swarmCluster, agentType}, q)
+}
+
+// RecordExecutorOutcome records a finished task Job by the executor image
+// it ran
+func (m *MetricsRecorder) RecordExecutorOutcome(namespace, swarmCluster, image string, failed bool) {
+	result := "success"
+	if failed {
+		result = "failure"
+	}
+	executorJobOutcomes.WithLabelValues(namespace, swarmCluster, image, result, m.tenant(namespace, swarmCluster)).Inc()
+	m.outcomes.observe(outcomeKey{namespace, swarmCluster, image}, failed)
+}
+
+// CompareExecutorImage returns the Job outcomes of the image and of every
+// other image of the cluster since the operator started
+func (m *MetricsRecorder) CompareExecutorImage(namespace, swarmCluster, image string) (canary, baseline ExecutorOutcomes) {
+	return m.outcomes.compare(namespace, swarmCluster, image)
+}
+
+// RecordPlacementHint records a bin-packing decision for a task Job and the
+// packing of the nodes it was made against
+func (m *MetricsRecorder) RecordPlacementHint(namespace, swarmCluster string, hinted bool, efficiency float64) {
+	result := "hinted"
+	if !hinted {
+		result = "no_fit"
+	}
+	placementHints.WithLabelValues(namespace, swarmCluster, result, m.tenant(namespace, swarmCluster)).Inc()
+	placementEfficiency.WithLabelValues(namespace, swarmCluster, m.tenant(namespace, swarmCluster)).Set(efficiency)
+}
+
+// RecordSchedulingPolicy records the evaluation of a scheduling policy for
+// one candidate agent
+func (m *MetricsRecorder) RecordSchedulingPolicy(namespace, swarmCluster, policy, result string, seconds float64) {
+	schedulingPolicyEvaluations.WithLabelValues(namespace, swarmCluster, policy, result, m.tenant(namespace, swarmCluster)).Inc()
+	schedulingPolicyDuration.WithLabelValues(namespace, swarmCluster, policy).Observe(seconds)
 }
 
 // RecordTaskSuccessRate records the task success rate
 func (m *MetricsRecorder) RecordTaskSuccessRate(namespace, swarmCluster string, rate float64) {
-	taskSuccessRate.WithLabelValues(namespace, swarmCluster).Set(rate)
+	taskSuccessRate.WithLabelValues(namespace, swarmCluster, m.tenant(namespace, swarmCluster)).Set(rate)
 }
 
 // RecordPeerConnections records the number of peer connections
@@ -292,12 +618,63 @@ func (m *MetricsRecorder) RecordCommunicationLatency(namespace, fromAgent, toAge
 
 // RecordAutoscalingEvent records an autoscaling event
 func (m *MetricsRecorder) RecordAutoscalingEvent(namespace, swarmCluster, direction string) {
-	autoscalingEvents.WithLabelValues(namespace, swarmCluster, direction).Inc()
+	autoscalingEvents.WithLabelValues(namespace, swarmCluster, direction, m.tenant(namespace, swarmCluster)).Inc()
 }
 
 // RecordAutoscalingTarget records the target agent count
 func (m *MetricsRecorder) RecordAutoscalingTarget(namespace, swarmCluster string, target int) {
-	autoscalingTargetAgents.WithLabelValues(namespace, swarmCluster).Set(float64(target))
+	autoscalingTargetAgents.WithLabelValues(namespace, swarmCluster, m.tenant(namespace, swarmCluster)).Set(float64(target))
+}
+
+// RecordAgentTypeScaling records the inputs and target of an agent type's
+// autoscaling decision
+func (m *MetricsRecorder) RecordAgentTypeScaling(namespace, swarmCluster, agentType string, queueDepth int, p95Latency float64, target int) {
+	tenant := m.tenant(namespace, swarmCluster)
+	autoscalingQueueDepth.WithLabelValues(namespace, swarmCluster, agentType, tenant).Set(float64(queueDepth))
+	autoscalingTaskLatencyP95.WithLabelValues(namespace, swarmCluster, agentType, tenant).Set(p95Latency)
+	autoscalingAgentTypeTarget.WithLabelValues(namespace, swarmCluster, agentType, tenant).Set(float64(target))
+}
+
+// RecordFairQueueDispatch records a task fair queuing dispatched and how
+// long it waited
+func (m *MetricsRecorder) RecordFairQueueDispatch(namespace, swarmCluster, key string, wait float64) {
+	tenant := m.tenant(namespace, swarmCluster)
+	fairQueueDispatched.WithLabelValues(namespace, swarmCluster, key, tenant).Inc()
+	fairQueueWait.WithLabelValues(namespace, swarmCluster, key, tenant).Observe(wait)
+}
+
+// RecordFairQueueDepth records the waiting tasks of every queue of a
+// cluster. Queues left out have no tasks waiting.
+func (m *MetricsRecorder) RecordFairQueueDepth(namespace, swarmCluster string, depths map[string]int) {
+	tenant := m.tenant(namespace, swarmCluster)
+	fairQueueDepth.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "swarm_cluster": swarmCluster})
+	for key, depth := range depths {
+		fairQueueDepth.WithLabelValues(namespace, swarmCluster, key, tenant).Set(float64(depth))
+	}
+}
+
+// RecordTaskReferenceInconsistency records a reference between an agent
+// and a task that drifted out of sync and was repaired
+func (m *MetricsRecorder) RecordTaskReferenceInconsistency(namespace, swarmCluster, reason string) {
+	taskReferenceInconsistencies.WithLabelValues(namespace, swarmCluster, reason, m.tenant(namespace, swarmCluster)).Inc()
+}
+
+// RecordSwarmClusterFrozen records whether a maintenance window freezes the
+// cluster. The window label is empty while the cluster is not frozen.
+func (m *MetricsRecorder) RecordSwarmClusterFrozen(namespace, name, window string) {
+	swarmClusterFrozen.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+	swarmClusterFrozen.WithLabelValues(namespace, name, window, m.tenant(namespace, name)).Set(boolToFloat(window != ""))
+}
+
+// RecordSwarmClusterHealth records the health score of a cluster and the
+// scores of its signals. Signals left out of the score are dropped.
+func (m *MetricsRecorder) RecordSwarmClusterHealth(namespace, name string, score float64, signals map[string]float64) {
+	tenant := m.tenant(namespace, name)
+	swarmClusterHealthScore.WithLabelValues(namespace, name, tenant).Set(score)
+	swarmClusterHealthSignal.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+	for signal, value := range signals {
+		swarmClusterHealthSignal.WithLabelValues(namespace, name, signal, tenant).Set(value)
+	}
 }
 
 // RecordReconciliation records reconciliation metrics
@@ -308,4 +685,61 @@ func (m *MetricsRecorder) RecordReconciliation(controller string, duration float
 	}
 	reconcileTotal.WithLabelValues(controller, result).Inc()
 	reconcileDuration.WithLabelValues(controller).Observe(duration)
-}
\ No newline at end of file
+}
+
+// RecordClientRateLimitWait records how long a request of the controller
+// waited for its client-side rate limit
+func (m *MetricsRecorder) RecordClientRateLimitWait(controller string, wait time.Duration) {
+	apiClientRateLimitWait.WithLabelValues(controller).Observe(wait.Seconds())
+}
+
+// RecordServerThrottled records a request the API server throttled. The
+// priority level is empty when the server does not run API priority and
+// fairness.
+func (m *MetricsRecorder) RecordServerThrottled(priorityLevel string) {
+	apiServerThrottled.WithLabelValues(priorityLevel).Inc()
+}
+
+// RecordPreflightCheck records the result of a single preflight check
+func (m *MetricsRecorder) RecordPreflightCheck(check string, ok bool) {
+	preflightCheck.WithLabelValues(check).Set(boolToFloat(ok))
+}
+
+// RecordPreflight records whether all preflight checks passed
+func (m *MetricsRecorder) RecordPreflight(ok bool) {
+	preflightOK.Set(boolToFloat(ok))
+}
+
+// RecordCircuitBreakerState records a circuit breaker transition
+func (m *MetricsRecorder) RecordCircuitBreakerState(dependency, state string) {
+	value := 0.0
+	switch state {
+	case "half-open":
+		value = 1.0
+	case "open":
+		value = 2.0
+		circuitBreakerTrips.WithLabelValues(dependency).Inc()
+	}
+	circuitBreakerState.WithLabelValues(dependency).Set(value)
+}
+
+// RecordGarbageCollected records a task resource deleted by the garbage
+// collector and the storage it held
+func (m *MetricsRecorder) RecordGarbageCollected(kind string, bytes int64) {
+	gcDeleted.WithLabelValues(kind).Inc()
+	gcReclaimedBytes.Add(float64(bytes))
+}
+
+// RecordGarbageCollectionDryRun records what a dry run of the garbage
+// collector would delete
+func (m *MetricsRecorder) RecordGarbageCollectionDryRun(kind string, count int, bytes int64) {
+	gcDryRunResources.WithLabelValues(kind).Set(float64(count))
+	gcDryRunBytes.WithLabelValues(kind).Set(float64(bytes))
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1.0
+	}
+	return 0.0
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyWindow is how far back task durations count towards quantiles
+	latencyWindow = 15 * time.Minute
+
+	// maxLatencySamples bounds the samples kept per series
+	maxLatencySamples = 512
+)
+
+type latencyKey struct {
+	namespace    string
+	swarmCluster string
+	agentType    string
+}
+
+type latencySample struct {
+	at       time.Time
+	duration float64
+}
+
+// latencyWindows keeps the recent task durations of every series so
+// autoscaling can react to current latency rather than the lifetime
+// histogram
+type latencyWindows struct {
+	mu      sync.Mutex
+	samples map[latencyKey][]latencySample
+	now     func() time.Time
+}
+
+func newLatencyWindows() *latencyWindows {
+	return &latencyWindows{
+		samples: map[latencyKey][]latencySample{},
+		now:     time.Now,
+	}
+}
+
+func (w *latencyWindows) observe(key latencyKey, duration float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	samples := append(w.prune(key), latencySample{at: w.now(), duration: duration})
+	if len(samples) > maxLatencySamples {
+		samples = samples[len(samples)-maxLatencySamples:]
+	}
+	w.samples[key] = samples
+}
+
+// quantile returns the q-quantile of the series' durations in the window
+func (w *latencyWindows) quantile(key latencyKey, q float64) (float64, bool) {
+	w.mu.Lock()
+	samples := w.prune(key)
+	durations := make([]float64, len(samples))
+	for i, s := range samples {
+		durations[i] = s.duration
+	}
+	w.mu.Unlock()
+
+	if len(durations) == 0 {
+		return 0, false
+	}
+	sort.Float64s(durations)
+	// Nearest-rank quantile
+	rank := int(math.Ceil(q*float64(len(durations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return durations[rank], true
+}
+
+// prune drops samples older than the window; callers hold mu
+func (w *latencyWindows) prune(key latencyKey) []latencySample {
+	samples := w.samples[key]
+	cutoff := w.now().Add(-latencyWindow)
+	i := 0
+	for i < len(samples) && samples[i].at.Before(cutoff) {
+		i++
+	}
+	if i == len(samples) {
+		delete(w.samples, key)
+		return nil
+	}
+	w.samples[key] = samples[i:]
+	return samples[i:]
+}
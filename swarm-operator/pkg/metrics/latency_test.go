@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Task latency window", func() {
+	var (
+		windows *latencyWindows
+		now     time.Time
+		key     = latencyKey{"default", "swarm", "coder"}
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		windows = newLatencyWindows()
+		windows.now = func() time.Time { return now }
+	})
+
+	It("should report nearest-rank quantiles", func() {
+		for i := 1; i <= 100; i++ {
+			windows.observe(key, float64(i))
+		}
+		p95, ok := windows.quantile(key, 0.95)
+		Expect(ok).To(BeTrue())
+		Expect(p95).To(Equal(95.0))
+	})
+
+	It("should forget samples outside the window", func() {
+		windows.observe(key, 600)
+		now = now.Add(10 * time.Minute)
+		windows.observe(key, 30)
+
+		p95, _ := windows.quantile(key, 0.95)
+		Expect(p95).To(Equal(600.0))
+
+		now = now.Add(6 * time.Minute)
+		p95, _ = windows.quantile(key, 0.95)
+		Expect(p95).To(Equal(30.0))
+
+		now = now.Add(10 * time.Minute)
+		_, ok := windows.quantile(key, 0.95)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "sync"
+
+type outcomeKey struct {
+	namespace    string
+	swarmCluster string
+	image        string
+}
+
+// ExecutorOutcomes counts the finished Jobs of an executor image
+type ExecutorOutcomes struct {
+	Jobs     int
+	Failures int
+}
+
+// FailureRate is the share of failed Jobs, 0 without any Job
+func (o ExecutorOutcomes) FailureRate() float64 {
+	if o.Jobs == 0 {
+		return 0
+	}
+	return float64(o.Failures) / float64(o.Jobs)
+}
+
+// executorOutcomes keeps the Job outcomes of every executor image so
+// rollouts can compare a new image with the ones it replaces
+type executorOutcomes struct {
+	mu     sync.Mutex
+	counts map[outcomeKey]ExecutorOutcomes
+}
+
+func newExecutorOutcomes() *executorOutcomes {
+	return &executorOutcomes{counts: map[outcomeKey]ExecutorOutcomes{}}
+}
+
+func (o *executorOutcomes) observe(key outcomeKey, failed bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	counts := o.counts[key]
+	counts.Jobs++
+	if failed {
+		counts.Failures++
+	}
+	o.counts[key] = counts
+}
+
+// compare sums the outcomes of the image and of all other images of the
+// cluster
+func (o *executorOutcomes) compare(namespace, swarmCluster, image string) (canary, baseline ExecutorOutcomes) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for key, counts := range o.counts {
+		if key.namespace != namespace || key.swarmCluster != swarmCluster {
+			continue
+		}
+		if key.image == image {
+			canary = counts
+			continue
+		}
+		baseline.Jobs += counts.Jobs
+		baseline.Failures += counts.Failures
+	}
+	return canary, baseline
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Executor outcomes", func() {
+	It("should compare an image with the other images of its cluster", func() {
+		outcomes := newExecutorOutcomes()
+		outcomes.observe(outcomeKey{"default", "swarm", "executor:v2"}, true)
+		outcomes.observe(outcomeKey{"default", "swarm", "executor:v2"}, false)
+		outcomes.observe(outcomeKey{"default", "swarm", "executor:v1"}, false)
+		outcomes.observe(outcomeKey{"default", "swarm", "busybox:latest"}, true)
+		outcomes.observe(outcomeKey{"default", "swarm", "executor:v1"}, false)
+		outcomes.observe(outcomeKey{"default", "swarm", "executor:v1"}, false)
+		outcomes.observe(outcomeKey{"default", "other", "executor:v1"}, true)
+
+		canary, baseline := outcomes.compare("default", "swarm", "executor:v2")
+		Expect(canary).To(Equal(ExecutorOutcomes{Jobs: 2, Failures: 1}))
+		Expect(baseline).To(Equal(ExecutorOutcomes{Jobs: 4, Failures: 1}))
+		Expect(canary.FailureRate()).To(Equal(0.5))
+		Expect(ExecutorOutcomes{}.FailureRate()).To(Equal(0.0))
+	})
+})
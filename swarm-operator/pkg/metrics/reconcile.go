@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/claude-flow/swarm-operator/pkg/apiclient"
+)
+
+// InstrumentReconciler counts the reconciliations of a controller that
+// asked to be requeued. Counts and durations per controller come from
+// controller-runtime's controller_runtime_reconcile_total and
+// controller_runtime_reconcile_time_seconds, queue depth and wait time from
+// workqueue_depth and workqueue_queue_duration_seconds.
+//
+// Reconciliations failing because the API server throttled them are
+// requeued after the delay the server asked for rather than retried with
+// the short exponential backoff of errors, which would add to the load
+// API priority and fairness is shedding.
+func (m *MetricsRecorder) InstrumentReconciler(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	if m == nil {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := r.Reconcile(ctx, req)
+		if delay, throttled := apiclient.ThrottleDelay(err); throttled {
+			log.FromContext(ctx).V(1).Info("API server throttled the reconciliation, backing off", "delay", delay)
+			reconcileThrottled.WithLabelValues(controller).Inc()
+			result, err = reconcile.Result{RequeueAfter: delay}, nil
+		}
+		if err == nil && (result.Requeue || result.RequeueAfter > 0) {
+			reconcileRequeues.WithLabelValues(controller).Inc()
+		}
+		return result, err
+	})
+}
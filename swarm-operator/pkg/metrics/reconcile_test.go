@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Reconciler instrumentation", func() {
+	It("should count the reconciliations that asked to be requeued", func() {
+		results := []error{nil, errors.New("conflict"), nil}
+		calls := 0
+		inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			err := results[calls]
+			calls++
+			return reconcile.Result{RequeueAfter: time.Duration(calls) * time.Second}, err
+		})
+
+		r := NewMetricsRecorder().InstrumentReconciler("instrumented", inner)
+		for range results {
+			_, _ = r.Reconcile(context.Background(), reconcile.Request{})
+		}
+
+		Expect(testutil.ToFloat64(reconcileRequeues.WithLabelValues("instrumented"))).To(Equal(2.0))
+	})
+
+	It("should back off from reconciliations the API server throttled", func() {
+		inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, apierrors.NewTooManyRequests("the priority level is saturated", 3)
+		})
+
+		result, err := NewMetricsRecorder().InstrumentReconciler("throttled", inner).Reconcile(context.Background(), reconcile.Request{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(3 * time.Second))
+		Expect(testutil.ToFloat64(reconcileThrottled.WithLabelValues("throttled"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(reconcileRequeues.WithLabelValues("throttled"))).To(Equal(1.0))
+	})
+
+	It("should leave reconcilers alone without a recorder", func() {
+		inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, nil
+		})
+		var m *MetricsRecorder
+		Expect(m.InstrumentReconciler("none", inner)).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,226 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// maxSLOWindow is the longest window SLO events are kept for
+	maxSLOWindow = 30 * 24 * time.Hour
+
+	// maxSLOSamples bounds the events kept per SLO. Busy clusters see a
+	// shorter effective window.
+	maxSLOSamples = 10000
+
+	// sloFastBurnWindow and sloSlowBurnWindow are the windows burn rates
+	// are reported for
+	sloFastBurnWindow = 5 * time.Minute
+	sloSlowBurnWindow = time.Hour
+)
+
+var (
+	sloEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "swarm_task_slo_events_total",
+			Help: "Total number of tasks measured against an SLO, by result (good, bad)",
+		},
+		[]string{"namespace", "swarm_cluster", "slo", "result", "tenant"},
+	)
+
+	sloCompliance = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_task_slo_compliance",
+			Help: "Share of tasks meeting the SLO threshold over the SLO window (0-1)",
+		},
+		[]string{"namespace", "swarm_cluster", "slo", "tenant"},
+	)
+
+	sloErrorBudgetRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_task_slo_error_budget_remaining",
+			Help: "Share of the SLO window's error budget left, negative once overspent",
+		},
+		[]string{"namespace", "swarm_cluster", "slo", "tenant"},
+	)
+
+	sloBurnRate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_task_slo_burn_rate",
+			Help: "Rate the SLO error budget is spent at over the window, 1 spends it exactly over the SLO window",
+		},
+		[]string{"namespace", "swarm_cluster", "slo", "window", "tenant"},
+	)
+)
+
+type sloKey struct {
+	namespace    string
+	swarmCluster string
+	slo          string
+}
+
+type sloSample struct {
+	at   time.Time
+	good bool
+}
+
+// SLOReport is the state of an SLO over its window
+type SLOReport struct {
+	// Events and Good count the measured tasks and those that met the
+	// threshold
+	Events int
+	Good   int
+
+	// Compliance is the share of good events, 1 without events
+	Compliance float64
+
+	// ErrorBudgetRemaining is the share of the error budget left
+	ErrorBudgetRemaining float64
+
+	// FastBurnRate and SlowBurnRate are the burn rates over the last five
+	// minutes and the last hour
+	FastBurnRate float64
+	SlowBurnRate float64
+}
+
+// sloWindows keeps the recent events of every SLO so compliance and burn
+// rates can be computed without querying Prometheus
+type sloWindows struct {
+	mu      sync.Mutex
+	samples map[sloKey][]sloSample
+	now     func() time.Time
+}
+
+func newSLOWindows() *sloWindows {
+	return &sloWindows{
+		samples: map[sloKey][]sloSample{},
+		now:     time.Now,
+	}
+}
+
+func (w *sloWindows) observe(key sloKey, good bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	samples := append(w.prune(key), sloSample{at: w.now(), good: good})
+	if len(samples) > maxSLOSamples {
+		samples = samples[len(samples)-maxSLOSamples:]
+	}
+	w.samples[key] = samples
+}
+
+// count returns the events and good events of the SLO within the window
+func (w *sloWindows) count(key sloKey, window time.Duration) (events, good int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := w.now().Add(-window)
+	samples := w.prune(key)
+	for i := len(samples) - 1; i >= 0 && !samples[i].at.Before(cutoff); i-- {
+		events++
+		if samples[i].good {
+			good++
+		}
+	}
+	return events, good
+}
+
+func (w *sloWindows) forget(key sloKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.samples, key)
+}
+
+// prune drops events older than maxSLOWindow; callers hold mu
+func (w *sloWindows) prune(key sloKey) []sloSample {
+	samples := w.samples[key]
+	cutoff := w.now().Add(-maxSLOWindow)
+	i := 0
+	for i < len(samples) && samples[i].at.Before(cutoff) {
+		i++
+	}
+	if i == len(samples) {
+		delete(w.samples, key)
+		return nil
+	}
+	w.samples[key] = samples[i:]
+	return samples[i:]
+}
+
+// burnRate is the share of bad events relative to the error budget
+func burnRate(events, good int, objective float64) float64 {
+	if events == 0 {
+		return 0
+	}
+	budget := 1 - objective
+	if budget <= 0 {
+		budget = 1e-9
+	}
+	return float64(events-good) / float64(events) / budget
+}
+
+// RecordSLOEvent records whether a task met the threshold of an SLO
+func (m *MetricsRecorder) RecordSLOEvent(namespace, swarmCluster, slo string, good bool) {
+	result := "good"
+	if !good {
+		result = "bad"
+	}
+	sloEvents.WithLabelValues(namespace, swarmCluster, slo, result, m.tenant(namespace, swarmCluster)).Inc()
+	m.slos.observe(sloKey{namespace, swarmCluster, slo}, good)
+}
+
+// EvaluateSLO computes the compliance, remaining error budget and burn
+// rates of an SLO and publishes them. objective is the share of tasks that
+// have to meet the threshold, e.g. 0.95.
+func (m *MetricsRecorder) EvaluateSLO(namespace, swarmCluster, slo string, objective float64, window time.Duration) SLOReport {
+	key := sloKey{namespace, swarmCluster, slo}
+	if window > maxSLOWindow {
+		window = maxSLOWindow
+	}
+
+	report := SLOReport{Compliance: 1, ErrorBudgetRemaining: 1}
+	report.Events, report.Good = m.slos.count(key, window)
+	if report.Events > 0 {
+		report.Compliance = float64(report.Good) / float64(report.Events)
+		report.ErrorBudgetRemaining = 1 - burnRate(report.Events, report.Good, objective)
+	}
+	events, good := m.slos.count(key, sloFastBurnWindow)
+	report.FastBurnRate = burnRate(events, good, objective)
+	events, good = m.slos.count(key, sloSlowBurnWindow)
+	report.SlowBurnRate = burnRate(events, good, objective)
+
+	tenant := m.tenant(namespace, swarmCluster)
+	sloCompliance.WithLabelValues(namespace, swarmCluster, slo, tenant).Set(report.Compliance)
+	sloErrorBudgetRemaining.WithLabelValues(namespace, swarmCluster, slo, tenant).Set(report.ErrorBudgetRemaining)
+	sloBurnRate.WithLabelValues(namespace, swarmCluster, slo, "5m", tenant).Set(report.FastBurnRate)
+	sloBurnRate.WithLabelValues(namespace, swarmCluster, slo, "1h", tenant).Set(report.SlowBurnRate)
+	return report
+}
+
+// DeleteSLO drops the events and series of an SLO removed from its cluster
+func (m *MetricsRecorder) DeleteSLO(namespace, swarmCluster, slo string) {
+	m.slos.forget(sloKey{namespace, swarmCluster, slo})
+	labels := prometheus.Labels{"namespace": namespace, "swarm_cluster": swarmCluster, "slo": slo}
+	sloEvents.DeletePartialMatch(labels)
+	for _, vec := range []*prometheus.GaugeVec{sloCompliance, sloErrorBudgetRemaining, sloBurnRate} {
+		vec.DeletePartialMatch(labels)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Task SLOs", func() {
+	var (
+		recorder *MetricsRecorder
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		recorder = NewMetricsRecorder()
+		recorder.slos.now = func() time.Time { return now }
+	})
+
+	It("should report full compliance without events", func() {
+		report := recorder.EvaluateSLO("default", "swarm", "dispatch", 0.95, time.Hour)
+		Expect(report.Events).To(BeZero())
+		Expect(report.Compliance).To(Equal(1.0))
+		Expect(report.ErrorBudgetRemaining).To(Equal(1.0))
+		Expect(report.FastBurnRate).To(BeZero())
+	})
+
+	It("should compute compliance, budget and burn rates over their windows", func() {
+		// 18 good and 2 bad tasks half an hour ago spend 40% of a 5% budget
+		now = now.Add(-30 * time.Minute)
+		for i := 0; i < 20; i++ {
+			recorder.RecordSLOEvent("default", "swarm", "dispatch", i >= 2)
+		}
+		now = now.Add(30 * time.Minute)
+
+		report := recorder.EvaluateSLO("default", "swarm", "dispatch", 0.95, time.Hour)
+		Expect(report.Events).To(Equal(20))
+		Expect(report.Good).To(Equal(18))
+		Expect(report.Compliance).To(BeNumerically("~", 0.9, 1e-9))
+		Expect(report.ErrorBudgetRemaining).To(BeNumerically("~", -1, 1e-9))
+		Expect(report.SlowBurnRate).To(BeNumerically("~", 2, 1e-9))
+		Expect(report.FastBurnRate).To(BeZero())
+
+		// Only the recent failure counts towards the fast burn rate
+		recorder.RecordSLOEvent("default", "swarm", "dispatch", false)
+		report = recorder.EvaluateSLO("default", "swarm", "dispatch", 0.95, time.Hour)
+		Expect(report.FastBurnRate).To(BeNumerically("~", 20, 1e-9))
+	})
+
+	It("should forget events outside the SLO window", func() {
+		recorder.RecordSLOEvent("default", "swarm", "completion", false)
+		now = now.Add(2 * time.Hour)
+		recorder.RecordSLOEvent("default", "swarm", "completion", true)
+
+		report := recorder.EvaluateSLO("default", "swarm", "completion", 0.99, time.Hour)
+		Expect(report.Events).To(Equal(1))
+		Expect(report.Compliance).To(Equal(1.0))
+	})
+
+	It("should drop the events of deleted SLOs", func() {
+		recorder.RecordSLOEvent("default", "swarm", "completion", false)
+		recorder.DeleteSLO("default", "swarm", "completion")
+
+		Expect(recorder.EvaluateSLO("default", "swarm", "completion", 0.99, time.Hour).Events).To(BeZero())
+	})
+})
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	tenantClusters = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_tenant_clusters",
+			Help: "Number of SwarmClusters belonging to a tenant",
+		},
+		[]string{"tenant"},
+	)
+
+	tenantRunningTasks = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "swarm_tenant_running_tasks",
+			Help: "Number of executor Jobs currently running for a tenant",
+		},
+		[]string{"tenant"},
+	)
+)
+
+type tenantKey struct {
+	namespace    string
+	swarmCluster string
+}
+
+// tenantIndex remembers the tenant of every SwarmCluster so cluster and
+// task series carry a tenant label without each caller looking it up
+type tenantIndex struct {
+	mu      sync.RWMutex
+	tenants map[tenantKey]string
+}
+
+func newTenantIndex() *tenantIndex {
+	return &tenantIndex{tenants: map[tenantKey]string{}}
+}
+
+// set records the tenant of a cluster and returns the previous one
+func (t *tenantIndex) set(key tenantKey, tenant string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.tenants[key]
+	if tenant == "" {
+		delete(t.tenants, key)
+	} else {
+		t.tenants[key] = tenant
+	}
+	return previous
+}
+
+func (t *tenantIndex) get(key tenantKey) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tenants[key]
+}
+
+// tenant returns the tenant label value of a cluster, empty when untenanted
+func (m *MetricsRecorder) tenant(namespace, swarmCluster string) string {
+	if m.tenants == nil {
+		return ""
+	}
+	return m.tenants.get(tenantKey{namespace, swarmCluster})
+}
+
+// SetClusterTenant records the tenant a SwarmCluster belongs to. When the
+// tenant changes the series recorded under the previous one are dropped
+func (m *MetricsRecorder) SetClusterTenant(namespace, name, tenant string) {
+	if m.tenants == nil {
+		return
+	}
+	previous := m.tenants.set(tenantKey{namespace, name}, tenant)
+	if previous == tenant {
+		return
+	}
+
+	clusterLabels := prometheus.Labels{"namespace": namespace, "name": name, "tenant": previous}
+	for _, vec := range []*prometheus.GaugeVec{swarmClusterPhase, swarmClusterAgents, swarmClusterFrozen, swarmClusterHealthScore, swarmClusterHealthSignal} {
+		vec.DeletePartialMatch(clusterLabels)
+	}
+
+	taskLabels := prometheus.Labels{"namespace": namespace, "swarm_cluster": name, "tenant": previous}
+	for _, vec := range []*prometheus.GaugeVec{
+		taskQueueSize, taskSuccessRate, placementEfficiency, autoscalingTargetAgents,
+		autoscalingQueueDepth, autoscalingTaskLatencyP95, autoscalingAgentTypeTarget,
+		sloCompliance, sloErrorBudgetRemaining, sloBurnRate, fairQueueDepth,
+	} {
+		vec.DeletePartialMatch(taskLabels)
+	}
+	for _, vec := range []*prometheus.CounterVec{executorJobOutcomes, placementHints, autoscalingEvents, sloEvents, fairQueueDispatched, taskReferenceInconsistencies} {
+		vec.DeletePartialMatch(taskLabels)
+	}
+	taskDuration.DeletePartialMatch(taskLabels)
+	fairQueueWait.DeletePartialMatch(taskLabels)
+}
+
+// RecordTenantUsage records the clusters and running tasks of a tenant
+func (m *MetricsRecorder) RecordTenantUsage(tenant string, clusters, runningTasks int32) {
+	tenantClusters.WithLabelValues(tenant).Set(float64(clusters))
+	tenantRunningTasks.WithLabelValues(tenant).Set(float64(runningTasks))
+}
+
+// DeleteTenant drops the series of a removed tenant
+func (m *MetricsRecorder) DeleteTenant(tenant string) {
+	tenantClusters.DeleteLabelValues(tenant)
+	tenantRunningTasks.DeleteLabelValues(tenant)
+}
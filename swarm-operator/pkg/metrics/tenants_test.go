@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Tenant labels", func() {
+	It("should label cluster series with the cluster's tenant", func() {
+		m := NewMetricsRecorder()
+		m.SetClusterTenant("tenant-ns", "swarm", "acme")
+		m.RecordTaskQueueSize("tenant-ns", "swarm", 3)
+
+		Expect(testutil.ToFloat64(taskQueueSize.WithLabelValues("tenant-ns", "swarm", "acme"))).To(Equal(3.0))
+	})
+
+	It("should drop the series of the previous tenant when it changes", func() {
+		m := NewMetricsRecorder()
+		m.SetClusterTenant("moved-ns", "swarm", "acme")
+		m.RecordTaskQueueSize("moved-ns", "swarm", 2)
+		m.SetClusterTenant("moved-ns", "swarm", "globex")
+		m.RecordTaskQueueSize("moved-ns", "swarm", 5)
+
+		Expect(taskQueueSize.DeleteLabelValues("moved-ns", "swarm", "acme")).To(BeFalse())
+		Expect(testutil.ToFloat64(taskQueueSize.WithLabelValues("moved-ns", "swarm", "globex"))).To(Equal(5.0))
+	})
+
+	It("should drop health signals left out of the score", func() {
+		m := NewMetricsRecorder()
+		m.RecordSwarmClusterHealth("health-ns", "swarm", 72, map[string]float64{"agentReadiness": 60, "hiveMindSyncLag": 90})
+		m.RecordSwarmClusterHealth("health-ns", "swarm", 60, map[string]float64{"agentReadiness": 60})
+
+		Expect(testutil.ToFloat64(swarmClusterHealthScore.WithLabelValues("health-ns", "swarm", ""))).To(Equal(60.0))
+		Expect(swarmClusterHealthSignal.DeleteLabelValues("health-ns", "swarm", "hiveMindSyncLag", "")).To(BeFalse())
+		Expect(testutil.ToFloat64(swarmClusterHealthSignal.WithLabelValues("health-ns", "swarm", "agentReadiness", ""))).To(Equal(60.0))
+	})
+
+	It("should record tenant usage", func() {
+		m := NewMetricsRecorder()
+		m.RecordTenantUsage("usage", 2, 7)
+		Expect(testutil.ToFloat64(tenantClusters.WithLabelValues("usage"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(tenantRunningTasks.WithLabelValues("usage"))).To(Equal(7.0))
+
+		m.DeleteTenant("usage")
+		Expect(tenantRunningTasks.DeleteLabelValues("usage")).To(BeFalse())
+	})
+})
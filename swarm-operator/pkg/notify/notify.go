@@ -0,0 +1,207 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	defaultTemplate = `[{{.Cluster}}] {{if .Task}}Task {{.Namespace}}/{{.Task}}{{else}}Cluster{{end}} {{.Type}}{{if .Message}}: {{.Message}}{{end}}`
+)
+
+// Event is a lifecycle event delivered to notification sinks
+type Event struct {
+	Type      swarmv1alpha1.NotificationEvent `json:"type"`
+	Cluster   string                          `json:"cluster"`
+	Namespace string                          `json:"namespace"`
+	Task      string                          `json:"task,omitempty"`
+	Phase     string                          `json:"phase,omitempty"`
+	Message   string                          `json:"message,omitempty"`
+	Time      time.Time                       `json:"time"`
+}
+
+// Notifier delivers lifecycle events to the sinks configured on a SwarmCluster
+type Notifier struct {
+	Poster
+	Client client.Client
+}
+
+// NewNotifier creates a notifier that reads sink secrets with c
+func NewNotifier(c client.Client) *Notifier {
+	return &Notifier{
+		Poster: NewPoster(),
+		Client: c,
+	}
+}
+
+// Notify sends the event to every subscribed sink of the cluster. Secrets are
+// resolved synchronously; deliveries run in the background so slow endpoints
+// never hold up a reconcile.
+func (n *Notifier) Notify(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, event Event) {
+	log := log.FromContext(ctx)
+
+	if cluster.Spec.Notifications == nil {
+		return
+	}
+	event.Cluster = cluster.Name
+	if event.Namespace == "" {
+		event.Namespace = cluster.Namespace
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	for _, sink := range cluster.Spec.Notifications.Sinks {
+		if !Subscribed(sink, event.Type) {
+			continue
+		}
+
+		url, token, err := n.resolve(ctx, cluster.Namespace, sink)
+		if err != nil {
+			log.Error(err, "Failed to resolve notification sink", "sink", sink.Name)
+			continue
+		}
+
+		go func(sink swarmv1alpha1.NotificationSink, url, token string) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if err := n.Send(ctx, sink, url, token, event); err != nil {
+				log.Error(err, "Failed to deliver notification", "sink", sink.Name, "event", event.Type)
+			}
+		}(sink, url, token)
+	}
+}
+
+// Subscribed reports whether the sink wants events of the given type
+func Subscribed(sink swarmv1alpha1.NotificationSink, eventType swarmv1alpha1.NotificationEvent) bool {
+	if len(sink.Events) == 0 {
+		return true
+	}
+	for _, e := range sink.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Send renders the event for the sink and posts it
+func (n *Notifier) Send(ctx context.Context, sink swarmv1alpha1.NotificationSink, url, token string, event Event) error {
+	body, err := Payload(sink, event)
+	if err != nil {
+		return err
+	}
+	if err := n.Post(ctx, url, token, body); err != nil {
+		return fmt.Errorf("sink %s: %w", sink.Name, err)
+	}
+	return nil
+}
+
+// Payload renders the request body for the sink type
+func Payload(sink swarmv1alpha1.NotificationSink, event Event) ([]byte, error) {
+	text, err := Render(sink.Template, event)
+	if err != nil {
+		return nil, err
+	}
+
+	switch sink.Type {
+	case "slack":
+		return json.Marshal(map[string]string{"text": text})
+	case "teams":
+		return json.Marshal(map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  string(event.Type),
+			"text":     text,
+		})
+	default:
+		return json.Marshal(struct {
+			Event
+			Text string `json:"text"`
+		}{Event: event, Text: text})
+	}
+}
+
+// Render executes the sink template, or the default one, for the event
+func Render(tmpl string, event Event) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultTemplate
+	}
+	t, err := template.New("notification").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid notification template: %w", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("failed to render notification: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// resolve returns the sink URL and bearer token, reading secret refs
+func (n *Notifier) resolve(ctx context.Context, namespace string, sink swarmv1alpha1.NotificationSink) (string, string, error) {
+	url := sink.URL
+	if sink.URLSecretRef != nil {
+		value, err := n.secretValue(ctx, namespace, sink.URLSecretRef)
+		if err != nil {
+			return "", "", err
+		}
+		url = value
+	}
+	if url == "" {
+		return "", "", fmt.Errorf("sink %s has no URL", sink.Name)
+	}
+
+	var token string
+	if sink.TokenSecretRef != nil {
+		value, err := n.secretValue(ctx, namespace, sink.TokenSecretRef)
+		if err != nil {
+			return "", "", err
+		}
+		token = value
+	}
+	return url, token, nil
+}
+
+// secretValue reads the key from the cluster namespace. ref.Namespace is
+// ignored so a SwarmCluster cannot read Secrets of other namespaces through
+// the operator.
+func (n *Notifier) secretValue(ctx context.Context, namespace string, ref *swarmv1alpha1.SecretKeyRef) (string, error) {
+	secret := &corev1.Secret{}
+	if err := n.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+func TestNotify(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Notify Suite")
+}
+
+var _ = Describe("Notifier", func() {
+	var (
+		event    Event
+		notifier *Notifier
+	)
+
+	BeforeEach(func() {
+		event = Event{
+			Type:      swarmv1alpha1.TaskDeadLetteredEvent,
+			Cluster:   "test-swarm",
+			Namespace: "default",
+			Task:      "build-task",
+			Message:   "Dead-lettered after 4 attempts",
+		}
+		notifier = &Notifier{Poster: Poster{HTTPClient: http.DefaultClient, MaxAttempts: 3, Backoff: time.Millisecond}}
+	})
+
+	It("should render the default template", func() {
+		text, err := Render("", event)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(Equal("[test-swarm] Task default/build-task TaskDeadLettered: Dead-lettered after 4 attempts"))
+	})
+
+	It("should format Slack payloads with a custom template", func() {
+		sink := swarmv1alpha1.NotificationSink{Name: "chat", Type: "slack", Template: "{{.Task}} is {{.Type}}"}
+		body, err := Payload(sink, event)
+		Expect(err).NotTo(HaveOccurred())
+
+		var payload map[string]string
+		Expect(json.Unmarshal(body, &payload)).To(Succeed())
+		Expect(payload).To(HaveKeyWithValue("text", "build-task is TaskDeadLettered"))
+	})
+
+	It("should filter events per sink", func() {
+		sink := swarmv1alpha1.NotificationSink{Events: []swarmv1alpha1.NotificationEvent{swarmv1alpha1.TaskFailedEvent}}
+		Expect(Subscribed(sink, swarmv1alpha1.TaskFailedEvent)).To(BeTrue())
+		Expect(Subscribed(sink, swarmv1alpha1.TaskCompletedEvent)).To(BeFalse())
+		Expect(Subscribed(swarmv1alpha1.NotificationSink{}, swarmv1alpha1.ClusterDegradedEvent)).To(BeTrue())
+	})
+
+	It("should read sink secrets from the cluster namespace only", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		notifier.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "hook", Namespace: "default"},
+				Data:       map[string][]byte{"url": []byte("https://hooks.example.com/default\n")},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "hook", Namespace: "kube-system"},
+				Data:       map[string][]byte{"url": []byte("https://hooks.example.com/kube-system")},
+			},
+		).Build()
+
+		sink := swarmv1alpha1.NotificationSink{
+			Name:         "hook",
+			URLSecretRef: &swarmv1alpha1.SecretKeyRef{Name: "hook", Key: "url", Namespace: "kube-system"},
+		}
+		url, _, err := notifier.resolve(context.Background(), "default", sink)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(url).To(Equal("https://hooks.example.com/default"))
+	})
+
+	It("should retry server errors until delivery succeeds", func() {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		sink := swarmv1alpha1.NotificationSink{Name: "hook", Type: "webhook"}
+		Expect(notifier.Send(context.Background(), sink, server.URL, "", event)).To(Succeed())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(3)))
+	})
+
+	It("should not retry client errors", func() {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		sink := swarmv1alpha1.NotificationSink{Name: "hook"}
+		Expect(notifier.Send(context.Background(), sink, server.URL, "", event)).NotTo(Succeed())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+	})
+})
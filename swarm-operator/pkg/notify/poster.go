@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 4
+	defaultBackoff     = time.Second
+)
+
+// Poster posts JSON bodies to webhook endpoints. It is shared by the
+// notification sinks and the dead-letter sink.
+type Poster struct {
+	HTTPClient *http.Client
+
+	// MaxAttempts bounds delivery attempts per request
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry, doubled on every retry
+	Backoff time.Duration
+}
+
+// NewPoster creates a poster with the default timeout and retry budget
+func NewPoster() Poster {
+	return Poster{
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: defaultMaxAttempts,
+		Backoff:     defaultBackoff,
+	}
+}
+
+// Post sends body to url, retrying with exponential backoff on network
+// errors, 429 and 5xx responses, and fails on any other non-2xx response.
+// token is sent as a bearer token when non-empty.
+func (p *Poster) Post(ctx context.Context, url, token string, body []byte) error {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := p.Backoff
+
+	for attempt := 1; ; attempt++ {
+		retryable, err := p.post(ctx, url, token, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= attempts {
+			return fmt.Errorf("delivery failed after %d attempt(s): %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (p *Poster) post(ctx context.Context, url, token string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("sink returned %s", resp.Status)
+}
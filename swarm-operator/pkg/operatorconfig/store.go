@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package operatorconfig holds the operator-wide defaults. They start out
+// from the manager flags and are replaced at runtime from the
+// SwarmOperatorConfig resource, so controllers read them when they need
+// them instead of copying them at startup.
+package operatorconfig
+
+import (
+	"sync"
+	"time"
+)
+
+// Settings are the defaults the controllers apply to the resources they
+// create
+type Settings struct {
+	// ExecutorImage runs tasks that set no executorImage
+	ExecutorImage string
+
+	// ExecutorWindowsImage runs Windows tasks that set no executorImage
+	ExecutorWindowsImage string
+
+	// ExecutorScriptsConfigMap holds the executor entrypoint scripts
+	ExecutorScriptsConfigMap string
+
+	// InjectCredentialSecrets injects the well-known credential secrets
+	// into task Jobs
+	InjectCredentialSecrets bool
+
+	// ExecutorProgressURL is where executors report progress
+	ExecutorProgressURL string
+
+	// SwarmNamespace and HiveMindNamespace are the default namespaces of
+	// swarm and hive-mind components
+	SwarmNamespace    string
+	HiveMindNamespace string
+
+	// StorageClass is used by task volumes and memory stores naming none.
+	// Empty leaves the cluster default storage class.
+	StorageClass string
+
+	// BackoffLimit bounds the pod retries of a task Job. Nil keeps the Job
+	// default.
+	BackoffLimit *int32
+
+	// PriorityClasses runs task pods with the swarm PriorityClass of their
+	// priority
+	PriorityClasses bool
+
+	// PriorityPreemption lets critical task pods preempt lower priority
+	// pods
+	PriorityPreemption bool
+
+	// KeepCompletedStorageFor is how long the garbage collector keeps the
+	// volumes of a task after it finished or was deleted. Zero keeps them.
+	KeepCompletedStorageFor time.Duration
+
+	// KeepTokenSecretsFor is how long the garbage collector keeps the
+	// GitHub token secret of a task after it finished or was deleted. Zero
+	// keeps them.
+	KeepTokenSecretsFor time.Duration
+
+	// GCDryRun makes the garbage collector report what it would delete
+	// without deleting it
+	GCDryRun bool
+
+	// LogLevel is the zap level of the operator's logger: 0 is info, -1
+	// debug and lower levels more verbose debug logs
+	LogLevel int
+
+	// ImageScan checks executor images for vulnerabilities before their
+	// Job is created
+	ImageScan ImageScanSettings
+}
+
+// ImageScanSettings configure the vulnerability gate of task Jobs
+type ImageScanSettings struct {
+	// URL of the scanner. Empty turns the gate off.
+	URL string
+
+	// Format of the scanner's reports, trivy or grype
+	Format string
+
+	// WarnOnly runs tasks over the thresholds with a warning instead of
+	// holding them
+	WarnOnly bool
+
+	// MaxCritical and MaxHigh are the vulnerabilities an image may have.
+	// Negative allows any number.
+	MaxCritical int32
+	MaxHigh     int32
+
+	// FailOpen runs tasks whose image could not be scanned
+	FailOpen bool
+}
+
+// Store hands out the settings in effect. It is safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	defaults Settings
+	current  Settings
+	version  int64
+}
+
+// NewStore returns a store serving the given defaults until settings are
+// applied
+func NewStore(defaults Settings) *Store {
+	return &Store{defaults: defaults, current: defaults}
+}
+
+// Get returns the settings in effect
+func (s *Store) Get() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Defaults returns the settings the store started with
+func (s *Store) Defaults() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.defaults
+}
+
+// Version counts the changes of the settings in effect
+func (s *Store) Version() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// Apply replaces the settings in effect
+func (s *Store) Apply(settings Settings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if equal(s.current, settings) {
+		return
+	}
+	s.current = settings
+	s.version++
+}
+
+// Reset goes back to the defaults
+func (s *Store) Reset() {
+	s.Apply(s.Defaults())
+}
+
+func equal(a, b Settings) bool {
+	limitA, limitB := a.BackoffLimit, b.BackoffLimit
+	a.BackoffLimit, b.BackoffLimit = nil, nil
+	if a != b {
+		return false
+	}
+	if limitA == nil || limitB == nil {
+		return limitA == limitB
+	}
+	return *limitA == *limitB
+}
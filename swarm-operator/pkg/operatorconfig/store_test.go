@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorconfig
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOperatorConfig(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Operator Config Suite")
+}
+
+var _ = Describe("Store", func() {
+	It("serves the defaults until settings are applied", func() {
+		store := NewStore(Settings{ExecutorImage: "executor:v1", SwarmNamespace: "swarm"})
+		Expect(store.Get().ExecutorImage).To(Equal("executor:v1"))
+		Expect(store.Version()).To(BeZero())
+
+		limit := int32(2)
+		store.Apply(Settings{ExecutorImage: "executor:v2", BackoffLimit: &limit})
+		Expect(store.Get().ExecutorImage).To(Equal("executor:v2"))
+		Expect(*store.Get().BackoffLimit).To(Equal(int32(2)))
+		Expect(store.Defaults().ExecutorImage).To(Equal("executor:v1"))
+		Expect(store.Version()).To(Equal(int64(1)))
+
+		same := int32(2)
+		store.Apply(Settings{ExecutorImage: "executor:v2", BackoffLimit: &same})
+		Expect(store.Version()).To(Equal(int64(1)))
+
+		store.Reset()
+		Expect(store.Get()).To(Equal(store.Defaults()))
+		Expect(store.Version()).To(Equal(int64(2)))
+	})
+})
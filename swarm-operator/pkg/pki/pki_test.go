@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPKI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PKI Suite")
+}
+
+var _ = Describe("PKI", func() {
+	var (
+		now time.Time
+		ca  *KeyPair
+		req Request
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		var err error
+		ca, err = NewCA("swarm-ca", 365*24*time.Hour, now)
+		Expect(err).NotTo(HaveOccurred())
+		req = Request{
+			CommonName: "swarm-agent",
+			DNSNames:   []string{"swarm-agent.default.svc"},
+			Validity:   90 * 24 * time.Hour,
+		}
+	})
+
+	It("issues leaf certificates signed by the CA", func() {
+		leaf, err := Issue(ca, req, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Matches(leaf.Cert, ca.Cert, req)).To(BeTrue())
+
+		notAfter, err := NotAfter(leaf.Cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notAfter).To(Equal(now.Add(req.Validity)))
+	})
+
+	It("never outlives the CA", func() {
+		req.Validity = 2 * 365 * 24 * time.Hour
+		leaf, err := Issue(ca, req, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		notAfter, err := NotAfter(leaf.Cert)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notAfter).To(Equal(now.Add(365 * 24 * time.Hour)))
+	})
+
+	It("detects certificates from another CA or with other names", func() {
+		leaf, err := Issue(ca, req, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		other, err := NewCA("other-ca", 365*24*time.Hour, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Matches(leaf.Cert, other.Cert, req)).To(BeFalse())
+
+		req.DNSNames = append(req.DNSNames, "swarm-agent.default.svc.cluster.local")
+		Expect(Matches(leaf.Cert, ca.Cert, req)).To(BeFalse())
+	})
+
+	It("renews ahead of expiry", func() {
+		leaf, err := Issue(ca, req, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		renewAt, err := RenewalTime(leaf.Cert, 15*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renewAt).To(Equal(now.Add(75 * 24 * time.Hour)))
+
+		// Certificates shorter than renewBefore rotate two thirds into
+		// their lifetime
+		renewAt, err = RenewalTime(leaf.Cert, 100*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renewAt).To(BeTemporally("~", now.Add(60*24*time.Hour), time.Minute))
+	})
+
+	It("rejects a key that does not belong to the CA", func() {
+		other, err := NewCA("other-ca", 365*24*time.Hour, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Issue(&KeyPair{Cert: ca.Cert, Key: other.Key}, req, now)
+		Expect(err).To(HaveOccurred())
+	})
+})
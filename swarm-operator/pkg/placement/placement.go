@@ -0,0 +1,239 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package placement picks nodes for task pods so small tasks are packed
+// tightly and large ones still find room
+package placement
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Capacity is an amount of CPU and memory
+type Capacity struct {
+	MilliCPU int64
+	Memory   int64
+}
+
+// Fits reports whether c is at least other in every dimension
+func (c Capacity) Fits(other Capacity) bool {
+	return c.MilliCPU >= other.MilliCPU && c.Memory >= other.Memory
+}
+
+func (c Capacity) add(other Capacity) Capacity {
+	return Capacity{MilliCPU: c.MilliCPU + other.MilliCPU, Memory: c.Memory + other.Memory}
+}
+
+func (c Capacity) sub(other Capacity) Capacity {
+	return Capacity{MilliCPU: c.MilliCPU - other.MilliCPU, Memory: c.Memory - other.Memory}
+}
+
+// Node is a node's allocatable capacity and what its pods request of it
+type Node struct {
+	Name        string
+	Allocatable Capacity
+	Requested   Capacity
+}
+
+// Free is the capacity left on the node
+func (n Node) Free() Capacity {
+	return n.Allocatable.sub(n.Requested)
+}
+
+// utilization is the fuller of the node's CPU and memory shares once
+// extra is placed on it
+func (n Node) utilization(extra Capacity) float64 {
+	used := n.Requested.add(extra)
+	cpu, memory := 0.0, 0.0
+	if n.Allocatable.MilliCPU > 0 {
+		cpu = float64(used.MilliCPU) / float64(n.Allocatable.MilliCPU)
+	}
+	if n.Allocatable.Memory > 0 {
+		memory = float64(used.Memory) / float64(n.Allocatable.Memory)
+	}
+	if cpu > memory {
+		return cpu
+	}
+	return memory
+}
+
+// Options tune the bin-packing
+type Options struct {
+	// LargeTask is the request from which a task counts as large in either
+	// dimension. Zero dimensions are ignored.
+	LargeTask Capacity
+
+	// ReservedNodes is the number of emptiest nodes small tasks avoid as
+	// long as another node fits them
+	ReservedNodes int
+}
+
+// isLarge reports whether the request reaches the large task threshold
+func (o Options) isLarge(request Capacity) bool {
+	return (o.LargeTask.MilliCPU > 0 && request.MilliCPU >= o.LargeTask.MilliCPU) ||
+		(o.LargeTask.Memory > 0 && request.Memory >= o.LargeTask.Memory)
+}
+
+// Pick returns the node a pod requesting request should run on: the node
+// left fullest after placing it (best fit). Small tasks skip the reserved
+// emptiest nodes unless nothing else fits. ok is false when no node fits.
+func Pick(nodes []Node, request Capacity, opts Options) (name string, ok bool) {
+	var fitting []Node
+	for _, node := range nodes {
+		if node.Free().Fits(request) {
+			fitting = append(fitting, node)
+		}
+	}
+	if len(fitting) == 0 {
+		return "", false
+	}
+
+	if !opts.isLarge(request) && opts.ReservedNodes > 0 {
+		reserved := emptiest(nodes, opts.ReservedNodes)
+		var unreserved []Node
+		for _, node := range fitting {
+			if !reserved[node.Name] {
+				unreserved = append(unreserved, node)
+			}
+		}
+		if len(unreserved) > 0 {
+			fitting = unreserved
+		}
+	}
+
+	sort.Slice(fitting, func(i, j int) bool {
+		ui, uj := fitting[i].utilization(request), fitting[j].utilization(request)
+		if ui != uj {
+			return ui > uj
+		}
+		return fitting[i].Name < fitting[j].Name
+	})
+	return fitting[0].Name, true
+}
+
+// Fit places up to n pods requesting request one after the other on the
+// nodes Pick chooses. It returns how many fit and the nodes with them
+// placed.
+func Fit(nodes []Node, request Capacity, n int) (int, []Node) {
+	nodes = append([]Node(nil), nodes...)
+	index := make(map[string]int, len(nodes))
+	for i := range nodes {
+		index[nodes[i].Name] = i
+	}
+	for placed := 0; placed < n; placed++ {
+		name, ok := Pick(nodes, request, Options{})
+		if !ok {
+			return placed, nodes
+		}
+		node := &nodes[index[name]]
+		node.Requested = node.Requested.add(request)
+	}
+	return n, nodes
+}
+
+// emptiest returns the names of the n nodes with the most free capacity
+func emptiest(nodes []Node, n int) map[string]bool {
+	sorted := append([]Node(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		ui, uj := sorted[i].utilization(Capacity{}), sorted[j].utilization(Capacity{})
+		if ui != uj {
+			return ui < uj
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	names := map[string]bool{}
+	for i := 0; i < n && i < len(sorted); i++ {
+		names[sorted[i].Name] = true
+	}
+	return names
+}
+
+// Efficiency is the share of CPU requested on the nodes running any pod,
+// 1 meaning the used nodes are packed full. Empty nodes do not count
+// against it since they can be scaled away.
+func Efficiency(nodes []Node) float64 {
+	var requested, allocatable int64
+	for _, node := range nodes {
+		if node.Requested.MilliCPU == 0 && node.Requested.Memory == 0 {
+			continue
+		}
+		requested += node.Requested.MilliCPU
+		allocatable += node.Allocatable.MilliCPU
+	}
+	if allocatable == 0 {
+		return 0
+	}
+	return float64(requested) / float64(allocatable)
+}
+
+// PodRequests is what the scheduler reserves for a pod: the sum of its
+// containers' requests, or the largest init container's when that is more
+func PodRequests(spec *corev1.PodSpec) Capacity {
+	var total Capacity
+	for _, c := range spec.Containers {
+		total = total.add(containerRequests(c))
+	}
+	for _, c := range spec.InitContainers {
+		init := containerRequests(c)
+		if init.MilliCPU > total.MilliCPU {
+			total.MilliCPU = init.MilliCPU
+		}
+		if init.Memory > total.Memory {
+			total.Memory = init.Memory
+		}
+	}
+	return total
+}
+
+func containerRequests(c corev1.Container) Capacity {
+	return Capacity{
+		MilliCPU: c.Resources.Requests.Cpu().MilliValue(),
+		Memory:   c.Resources.Requests.Memory().Value(),
+	}
+}
+
+// Schedulable reports whether a pod with the spec may run on the node: the
+// node is schedulable, carries the pod's node selector and its NoSchedule
+// and NoExecute taints are tolerated
+func Schedulable(node *corev1.Node, spec *corev1.PodSpec) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for key, value := range spec.NodeSelector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range spec.Tolerations {
+			if spec.Tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlacement(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Placement Suite")
+}
+
+const gi = 1 << 30
+
+var _ = Describe("Pick", func() {
+	nodes := []Node{
+		{Name: "busy", Allocatable: Capacity{8000, 32 * gi}, Requested: Capacity{6000, 20 * gi}},
+		{Name: "half", Allocatable: Capacity{8000, 32 * gi}, Requested: Capacity{4000, 8 * gi}},
+		{Name: "empty", Allocatable: Capacity{8000, 32 * gi}},
+	}
+
+	It("packs small tasks onto the fullest node that fits them", func() {
+		name, ok := Pick(nodes, Capacity{1000, 2 * gi}, Options{})
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("busy"))
+
+		name, _ = Pick(nodes, Capacity{3000, 2 * gi}, Options{})
+		Expect(name).To(Equal("half"))
+	})
+
+	It("keeps the emptiest nodes for large tasks", func() {
+		opts := Options{LargeTask: Capacity{MilliCPU: 6000}, ReservedNodes: 1}
+
+		// Only the reserved node fits, so the small task still gets it
+		name, ok := Pick(nodes, Capacity{5000, gi}, opts)
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("empty"))
+
+		name, _ = Pick(nodes, Capacity{3000, gi}, opts)
+		Expect(name).To(Equal("half"))
+
+		name, _ = Pick(nodes, Capacity{6000, gi}, opts)
+		Expect(name).To(Equal("empty"))
+	})
+
+	It("reports requests no node fits", func() {
+		_, ok := Pick(nodes, Capacity{16000, gi}, Options{})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("counts how many pods the free capacity holds", func() {
+		fits, placed := Fit(nodes, Capacity{2000, 4 * gi}, 10)
+		Expect(fits).To(Equal(7))
+		Expect(placed[0].Requested).To(Equal(Capacity{8000, 24 * gi}))
+		Expect(nodes[0].Requested).To(Equal(Capacity{6000, 20 * gi}))
+
+		fits, _ = Fit(nodes, Capacity{2000, 4 * gi}, 3)
+		Expect(fits).To(Equal(3))
+	})
+
+	It("measures how full the used nodes are", func() {
+		Expect(Efficiency(nodes)).To(Equal(10000.0 / 16000.0))
+		Expect(Efficiency(nil)).To(BeZero())
+	})
+})
+
+var _ = Describe("Pod requests", func() {
+	It("counts containers and the largest init container", func() {
+		requests := func(cpu, memory string) corev1.ResourceRequirements {
+			return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}}
+		}
+		spec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Resources: requests("2", "128Mi")}},
+			Containers:     []corev1.Container{{Resources: requests("500m", "1Gi")}, {Resources: requests("250m", "256Mi")}},
+		}
+		Expect(PodRequests(spec)).To(Equal(Capacity{MilliCPU: 2000, Memory: 1280 << 20}))
+	})
+})
+
+var _ = Describe("Schedulable", func() {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"pool": "tasks"}},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			{Key: "dedicated", Value: "tasks", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+		}},
+	}
+
+	It("requires the node selector and tolerated taints", func() {
+		spec := &corev1.PodSpec{NodeSelector: map[string]string{"pool": "tasks"}}
+		Expect(Schedulable(node, spec)).To(BeFalse())
+
+		spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "tasks"}}
+		Expect(Schedulable(node, spec)).To(BeTrue())
+
+		spec.NodeSelector["pool"] = "gpu"
+		Expect(Schedulable(node, spec)).To(BeFalse())
+	})
+})
@@ -0,0 +1,200 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates the CEL scheduling policies of a swarm cluster
+// against a task and its candidate agents
+package policy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/claude-flow/swarm-operator/pkg/celexpr"
+)
+
+// Policy modes
+const (
+	// ModeScore adds the weighted result to the agent's score
+	ModeScore = "Score"
+	// ModeRequire excludes agents for which the expression is false
+	ModeRequire = "Require"
+)
+
+// Evaluation outcomes, used as the result label of the evaluation metrics
+const (
+	OutcomePass  = "pass"
+	OutcomeFail  = "fail"
+	OutcomeScore = "score"
+	OutcomeError = "error"
+)
+
+// Policy is a named CEL expression
+type Policy struct {
+	Name       string
+	Expression string
+	Mode       string
+	Weight     int32
+}
+
+// Result is the evaluation of one policy for one agent
+type Result struct {
+	Policy   string
+	Outcome  string
+	Score    float64
+	Err      error
+	Duration time.Duration
+}
+
+// Decision is the combined evaluation of all policies for one agent
+type Decision struct {
+	// Allowed is false if a Require policy evaluated to false
+	Allowed bool
+	// Score is the sum of the weighted Score policy results
+	Score   float64
+	Results []Result
+}
+
+// env is shared by every policy. The cost limit of a single evaluation
+// keeps a runaway comprehension from stalling task assignment.
+var env = celexpr.NewEnv(1000000,
+	cel.Variable("task", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("agent", cel.MapType(cel.StringType, cel.DynType)),
+)
+
+// Check compiles the expression and verifies that its result type suits the
+// mode. It is used at admission and before policies are applied.
+func Check(expression, mode string) error {
+	_, err := compile(expression, mode)
+	return err
+}
+
+func compile(expression, mode string) (cel.Program, error) {
+	return env.Compile(expression, func(outputType string) error {
+		switch mode {
+		case ModeRequire:
+			if outputType != "bool" && outputType != "dyn" {
+				return fmt.Errorf("a Require policy must evaluate to a bool, got %s", outputType)
+			}
+		case ModeScore, "":
+			switch outputType {
+			case "bool", "int", "uint", "double", "dyn":
+			default:
+				return fmt.Errorf("a Score policy must evaluate to a number or a bool, got %s", outputType)
+			}
+		default:
+			return fmt.Errorf("unknown mode %q", mode)
+		}
+		return nil
+	})
+}
+
+type compiled struct {
+	Policy
+	program cel.Program
+}
+
+// Engine evaluates a set of compiled policies
+type Engine struct {
+	policies []compiled
+}
+
+// New compiles the policies. It fails on the first invalid policy.
+func New(policies []Policy) (*Engine, error) {
+	engine := &Engine{}
+	for _, p := range policies {
+		if p.Mode == "" {
+			p.Mode = ModeScore
+		}
+		if p.Weight == 0 {
+			p.Weight = 1
+		}
+		program, err := compile(p.Expression, p.Mode)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %w", p.Name, err)
+		}
+		engine.policies = append(engine.policies, compiled{Policy: p, program: program})
+	}
+	return engine, nil
+}
+
+// Empty reports whether the engine has no policies
+func (e *Engine) Empty() bool {
+	return e == nil || len(e.policies) == 0
+}
+
+// Evaluate runs every policy against the task and agent variables. A policy
+// that fails to evaluate is recorded with OutcomeError and otherwise ignored,
+// so a bad expression degrades scoring instead of blocking assignment.
+func (e *Engine) Evaluate(task, agent map[string]interface{}) Decision {
+	decision := Decision{Allowed: true}
+	if e == nil {
+		return decision
+	}
+	vars := map[string]interface{}{"task": task, "agent": agent}
+	for _, p := range e.policies {
+		start := time.Now()
+		result := Result{Policy: p.Name}
+		out, _, err := p.program.Eval(vars)
+		result.Duration = time.Since(start)
+
+		if err == nil {
+			switch value := out.Value().(type) {
+			case bool:
+				switch {
+				case p.Mode == ModeRequire && value:
+					result.Outcome = OutcomePass
+				case p.Mode == ModeRequire:
+					result.Outcome = OutcomeFail
+					decision.Allowed = false
+				case value:
+					result.Outcome = OutcomeScore
+					result.Score = float64(p.Weight)
+				default:
+					result.Outcome = OutcomeScore
+				}
+			case int64:
+				result.Score, err = numericScore(p.Policy, float64(value))
+			case uint64:
+				result.Score, err = numericScore(p.Policy, float64(value))
+			case float64:
+				result.Score, err = numericScore(p.Policy, value)
+			default:
+				err = fmt.Errorf("unsupported result type %T", value)
+			}
+			if err == nil && result.Outcome == "" {
+				result.Outcome = OutcomeScore
+			}
+		}
+		if err != nil {
+			result.Outcome = OutcomeError
+			result.Score = 0
+			result.Err = err
+		}
+
+		decision.Score += result.Score
+		decision.Results = append(decision.Results, result)
+	}
+	return decision
+}
+
+func numericScore(p Policy, value float64) (float64, error) {
+	if p.Mode == ModeRequire {
+		return 0, fmt.Errorf("a Require policy must evaluate to a bool")
+	}
+	return value * float64(p.Weight), nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Policy Suite")
+}
+
+var _ = Describe("Scheduling policies", func() {
+	task := map[string]interface{}{
+		"name":         "build",
+		"type":         "coding",
+		"priority":     int64(8),
+		"capabilities": []string{"go"},
+		"labels":       map[string]string{"team": "search"},
+	}
+	spotAgent := map[string]interface{}{
+		"name":         "coder-1",
+		"type":         "coder",
+		"capabilities": []string{"go", "rust"},
+		"labels":       map[string]string{"node-pool": "spot"},
+		"successRate":  90.0,
+		"currentTasks": int64(1),
+	}
+	onDemandAgent := map[string]interface{}{
+		"name":         "coder-2",
+		"type":         "coder",
+		"capabilities": []string{"python"},
+		"labels":       map[string]string{"node-pool": "on-demand"},
+		"successRate":  50.0,
+		"currentTasks": int64(0),
+	}
+
+	It("should reject invalid expressions and result types", func() {
+		Expect(Check("agent.labels['node-pool'] == 'spot'", ModeScore)).To(Succeed())
+		Expect(Check("agent.successRate / 100.0", ModeScore)).To(Succeed())
+		Expect(Check("task.capabilities.all(c, c in agent.capabilities)", ModeRequire)).To(Succeed())
+
+		Expect(Check("agent.labels[", ModeScore)).NotTo(Succeed())
+		Expect(Check("node.cost", ModeScore)).NotTo(Succeed())
+		Expect(Check("'spot'", ModeScore)).NotTo(Succeed())
+		Expect(Check("1 + 2", ModeRequire)).NotTo(Succeed())
+		Expect(Check("true", "Prefer")).NotTo(Succeed())
+	})
+
+	It("should add weighted scores", func() {
+		engine, err := New([]Policy{
+			{Name: "prefer-spot", Expression: "agent.labels['node-pool'] == 'spot'", Weight: 5},
+			{Name: "success", Expression: "agent.successRate / 100.0"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		spot := engine.Evaluate(task, spotAgent)
+		Expect(spot.Allowed).To(BeTrue())
+		Expect(spot.Score).To(BeNumerically("~", 5.9, 0.001))
+		Expect(spot.Results).To(HaveLen(2))
+		Expect(spot.Results[0].Outcome).To(Equal(OutcomeScore))
+
+		onDemand := engine.Evaluate(task, onDemandAgent)
+		Expect(onDemand.Score).To(BeNumerically("~", 0.5, 0.001))
+	})
+
+	It("should exclude agents failing a Require policy", func() {
+		engine, err := New([]Policy{{
+			Name:       "capable",
+			Expression: "task.capabilities.all(c, c in agent.capabilities)",
+			Mode:       ModeRequire,
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(engine.Evaluate(task, spotAgent).Allowed).To(BeTrue())
+		decision := engine.Evaluate(task, onDemandAgent)
+		Expect(decision.Allowed).To(BeFalse())
+		Expect(decision.Results[0].Outcome).To(Equal(OutcomeFail))
+	})
+
+	It("should ignore policies that fail to evaluate", func() {
+		engine, err := New([]Policy{
+			{Name: "missing", Expression: "agent.costPerHour < 1.0", Mode: ModeRequire},
+			{Name: "prefer-idle", Expression: "agent.currentTasks == 0"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		decision := engine.Evaluate(task, onDemandAgent)
+		Expect(decision.Allowed).To(BeTrue())
+		Expect(decision.Score).To(BeNumerically("==", 1))
+		Expect(decision.Results[0].Outcome).To(Equal(OutcomeError))
+		Expect(decision.Results[0].Err).To(HaveOccurred())
+	})
+
+	It("should fail on the first invalid policy", func() {
+		_, err := New([]Policy{{Name: "broken", Expression: "agent.("}})
+		Expect(err).To(MatchError(ContainSubstring("policy broken")))
+	})
+
+	It("should treat a nil engine as empty", func() {
+		var engine *Engine
+		Expect(engine.Empty()).To(BeTrue())
+		Expect(engine.Evaluate(task, spotAgent).Allowed).To(BeTrue())
+	})
+})
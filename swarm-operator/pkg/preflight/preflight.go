@@ -0,0 +1,298 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/metrics"
+)
+
+// defaultInterval is how often the checks are re-run after startup
+const defaultInterval = time.Minute
+
+// CRD identifies a custom resource kind the operator depends on
+type CRD struct {
+	GroupVersion string
+	Kind         string
+}
+
+// Permission is an RBAC permission the operator needs
+type Permission struct {
+	Namespace string
+	Group     string
+	Resource  string
+	Verb      string
+}
+
+// Check is the outcome of a single preflight check
+type Check struct {
+	Name    string
+	OK      bool
+	Message string
+}
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=selfsubjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+
+// Checker verifies the operator's dependencies at startup and periodically
+// afterwards. Results are exposed through a readiness check and metrics.
+type Checker struct {
+	Kube            kubernetes.Interface
+	MetricsRecorder *metrics.MetricsRecorder
+
+	// CRDs that must be served by the API server
+	CRDs []CRD
+
+	// Namespaces that must exist or be creatable by the operator
+	Namespaces []string
+
+	// Permissions the operator's service account must have
+	Permissions []Permission
+
+	// Interval between runs, defaults to one minute
+	Interval time.Duration
+
+	mu     sync.RWMutex
+	checks []Check
+}
+
+// DefaultCRDs returns the CRDs served by the swarm.claudeflow.io API group
+func DefaultCRDs() []CRD {
+	gv := swarmv1alpha1.GroupVersion.String()
+	return []CRD{
+		{GroupVersion: gv, Kind: "SwarmCluster"},
+		{GroupVersion: gv, Kind: "Agent"},
+		{GroupVersion: gv, Kind: "SwarmTask"},
+		{GroupVersion: gv, Kind: "SwarmMemoryStore"},
+		{GroupVersion: gv, Kind: "SwarmMemory"},
+	}
+}
+
+// DefaultPermissions returns the permissions the controllers need in each
+// watched namespace
+func DefaultPermissions(namespaces []string) []Permission {
+	var perms []Permission
+	for _, ns := range namespaces {
+		for _, resource := range []string{"swarmclusters", "agents", "swarmtasks", "swarmmemorystores"} {
+			perms = append(perms,
+				Permission{Namespace: ns, Group: swarmv1alpha1.GroupVersion.Group, Resource: resource, Verb: "list"},
+				Permission{Namespace: ns, Group: swarmv1alpha1.GroupVersion.Group, Resource: resource, Verb: "watch"},
+			)
+		}
+		perms = append(perms,
+			Permission{Namespace: ns, Group: "batch", Resource: "jobs", Verb: "create"},
+			Permission{Namespace: ns, Group: "apps", Resource: "deployments", Verb: "create"},
+			Permission{Namespace: ns, Resource: "pods", Verb: "list"},
+			Permission{Namespace: ns, Resource: "secrets", Verb: "create"},
+		)
+	}
+	return perms
+}
+
+// Start runs the checks immediately and then on every interval until ctx is done
+func (c *Checker) Start(ctx context.Context) error {
+	interval := c.Interval
+	if interval == 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.Run(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection lets every replica run preflight checks
+func (c *Checker) NeedLeaderElection() bool {
+	return false
+}
+
+// Run executes all checks once and records the results
+func (c *Checker) Run(ctx context.Context) []Check {
+	log := log.FromContext(ctx).WithName("preflight")
+
+	var checks []Check
+	checks = append(checks, c.checkCRDs()...)
+	checks = append(checks, c.checkNamespaces(ctx)...)
+	checks = append(checks, c.checkPermissions(ctx)...)
+
+	ok := true
+	for _, check := range checks {
+		if !check.OK {
+			ok = false
+			log.Info("Preflight check failed", "check", check.Name, "reason", check.Message)
+		}
+		if c.MetricsRecorder != nil {
+			c.MetricsRecorder.RecordPreflightCheck(check.Name, check.OK)
+		}
+	}
+	if c.MetricsRecorder != nil {
+		c.MetricsRecorder.RecordPreflight(ok)
+	}
+
+	c.mu.Lock()
+	c.checks = checks
+	c.mu.Unlock()
+
+	return checks
+}
+
+// Ready is a readiness check that fails until all preflight checks pass
+func (c *Checker) Ready(_ *http.Request) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.checks == nil {
+		return fmt.Errorf("preflight checks have not run yet")
+	}
+
+	var failed []string
+	for _, check := range c.checks {
+		if !check.OK {
+			failed = append(failed, fmt.Sprintf("%s: %s", check.Name, check.Message))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("preflight checks failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// checkCRDs verifies that every required kind is served at its version
+func (c *Checker) checkCRDs() []Check {
+	served := map[string]map[string]bool{}
+	var checks []Check
+
+	for _, crd := range c.CRDs {
+		name := fmt.Sprintf("crd/%s/%s", crd.GroupVersion, crd.Kind)
+
+		kinds, ok := served[crd.GroupVersion]
+		if !ok {
+			kinds = map[string]bool{}
+			resources, err := c.Kube.Discovery().ServerResourcesForGroupVersion(crd.GroupVersion)
+			if err != nil && !errors.IsNotFound(err) {
+				checks = append(checks, Check{Name: name, Message: fmt.Sprintf("discovery failed: %v", err)})
+				continue
+			}
+			if resources != nil {
+				for _, r := range resources.APIResources {
+					kinds[r.Kind] = true
+				}
+			}
+			served[crd.GroupVersion] = kinds
+		}
+
+		if !kinds[crd.Kind] {
+			checks = append(checks, Check{Name: name, Message: fmt.Sprintf("%s is not served at %s, install the CRDs", crd.Kind, crd.GroupVersion)})
+			continue
+		}
+		checks = append(checks, Check{Name: name, OK: true})
+	}
+	return checks
+}
+
+// checkNamespaces verifies each namespace exists or can be created
+func (c *Checker) checkNamespaces(ctx context.Context) []Check {
+	var checks []Check
+	for _, ns := range c.Namespaces {
+		name := fmt.Sprintf("namespace/%s", ns)
+
+		_, err := c.Kube.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+		if err == nil {
+			checks = append(checks, Check{Name: name, OK: true})
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			checks = append(checks, Check{Name: name, Message: fmt.Sprintf("failed to get namespace: %v", err)})
+			continue
+		}
+
+		allowed, reason, err := c.canI(ctx, Permission{Resource: "namespaces", Verb: "create"})
+		switch {
+		case err != nil:
+			checks = append(checks, Check{Name: name, Message: fmt.Sprintf("namespace missing and access review failed: %v", err)})
+		case !allowed:
+			checks = append(checks, Check{Name: name, Message: fmt.Sprintf("namespace missing and cannot be created: %s", reason)})
+		default:
+			checks = append(checks, Check{Name: name, OK: true, Message: "namespace will be created on demand"})
+		}
+	}
+	return checks
+}
+
+// checkPermissions runs a SelfSubjectAccessReview for every permission
+func (c *Checker) checkPermissions(ctx context.Context) []Check {
+	var checks []Check
+	for _, perm := range c.Permissions {
+		name := fmt.Sprintf("rbac/%s/%s", perm.Verb, perm.Resource)
+		if perm.Group != "" {
+			name = fmt.Sprintf("rbac/%s/%s.%s", perm.Verb, perm.Resource, perm.Group)
+		}
+		if perm.Namespace != "" {
+			name = fmt.Sprintf("%s@%s", name, perm.Namespace)
+		}
+
+		allowed, reason, err := c.canI(ctx, perm)
+		switch {
+		case err != nil:
+			checks = append(checks, Check{Name: name, Message: fmt.Sprintf("access review failed: %v", err)})
+		case !allowed:
+			checks = append(checks, Check{Name: name, Message: fmt.Sprintf("permission denied: %s", reason)})
+		default:
+			checks = append(checks, Check{Name: name, OK: true})
+		}
+	}
+	return checks
+}
+
+func (c *Checker) canI(ctx context.Context, perm Permission) (bool, string, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: perm.Namespace,
+				Group:     perm.Group,
+				Resource:  perm.Resource,
+				Verb:      perm.Verb,
+			},
+		},
+	}
+	result, err := c.Kube.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", err
+	}
+	return result.Status.Allowed, result.Status.Reason, nil
+}
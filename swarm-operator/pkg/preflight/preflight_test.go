@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPreflight(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Preflight Suite")
+}
+
+var _ = Describe("Checker", func() {
+	var (
+		ctx     context.Context
+		kube    *fake.Clientset
+		allowed bool
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		allowed = true
+		kube = fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "claude-flow-swarm"},
+		})
+		kube.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: "swarm.claudeflow.io/v1alpha1",
+				APIResources: []metav1.APIResource{{Name: "swarmclusters", Kind: "SwarmCluster"}},
+			},
+		}
+		kube.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = allowed
+			if !allowed {
+				review.Status.Reason = "forbidden"
+			}
+			return true, review, nil
+		})
+	})
+
+	It("should pass when all dependencies are present", func() {
+		checker := &Checker{
+			Kube:        kube,
+			CRDs:        []CRD{{GroupVersion: "swarm.claudeflow.io/v1alpha1", Kind: "SwarmCluster"}},
+			Namespaces:  []string{"claude-flow-swarm"},
+			Permissions: DefaultPermissions([]string{"claude-flow-swarm"}),
+		}
+		Expect(checker.Ready(nil)).To(HaveOccurred())
+
+		checker.Run(ctx)
+		Expect(checker.Ready(nil)).To(Succeed())
+	})
+
+	It("should fail readiness when a CRD is missing", func() {
+		checker := &Checker{Kube: kube, CRDs: DefaultCRDs()}
+		checker.Run(ctx)
+		Expect(checker.Ready(nil)).To(MatchError(ContainSubstring("SwarmTask is not served")))
+	})
+
+	It("should fail when a missing namespace cannot be created", func() {
+		allowed = false
+		checker := &Checker{Kube: kube, Namespaces: []string{"claude-flow-hivemind"}}
+		checks := checker.Run(ctx)
+		Expect(checks).To(HaveLen(1))
+		Expect(checks[0].OK).To(BeFalse())
+		Expect(checks[0].Message).To(ContainSubstring("cannot be created"))
+	})
+})
@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultCPUDuration is how long each CPU profile samples
+	DefaultCPUDuration = 30 * time.Second
+
+	// DefaultRetain is how many dumps of each kind are kept
+	DefaultRetain = 24
+
+	// timestampFormat sorts lexically in time order
+	timestampFormat = "20060102T150405Z"
+)
+
+// Dumper periodically writes CPU and heap profiles to a directory, typically
+// a mounted volume, so slow reconciles can be analysed after the fact
+type Dumper struct {
+	// Dir the profiles are written to
+	Dir string
+
+	// Interval between dumps
+	Interval time.Duration
+
+	// CPUDuration is how long each CPU profile samples, DefaultCPUDuration
+	// when zero
+	CPUDuration time.Duration
+
+	// Retain is how many dumps of each kind are kept, DefaultRetain when zero
+	Retain int
+
+	// now returns the current time, for tests
+	now func() time.Time
+}
+
+// NeedLeaderElection profiles every replica
+func (d *Dumper) NeedLeaderElection() bool {
+	return false
+}
+
+// Start dumps profiles every interval until the context is cancelled
+func (d *Dumper) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("profiler")
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil {
+		return err
+	}
+	log.Info("Dumping profiles periodically", "dir", d.Dir, "interval", d.Interval)
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		if err := d.Dump(ctx); err != nil {
+			log.Error(err, "Failed to dump profiles")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dump writes one CPU and one heap profile and prunes old dumps. The CPU
+// profile is cut short when the context is cancelled.
+func (d *Dumper) Dump(ctx context.Context) error {
+	stamp := d.timestamp()
+
+	cpu, err := os.Create(filepath.Join(d.Dir, fmt.Sprintf("cpu-%s.pprof", stamp)))
+	if err != nil {
+		return err
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		// Another CPU profile, e.g. from the pprof endpoint, is running
+		cpu.Close()
+		os.Remove(cpu.Name())
+		return fmt.Errorf("skipping CPU profile: %w", err)
+	}
+	duration := d.CPUDuration
+	if duration <= 0 {
+		duration = DefaultCPUDuration
+	}
+	timer := time.NewTimer(duration)
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	timer.Stop()
+	pprof.StopCPUProfile()
+	if err := cpu.Close(); err != nil {
+		return err
+	}
+
+	heap, err := os.Create(filepath.Join(d.Dir, fmt.Sprintf("heap-%s.pprof", stamp)))
+	if err != nil {
+		return err
+	}
+	if err := pprof.Lookup("heap").WriteTo(heap, 0); err != nil {
+		heap.Close()
+		return err
+	}
+	if err := heap.Close(); err != nil {
+		return err
+	}
+
+	return d.prune()
+}
+
+// prune removes all but the newest dumps of each kind
+func (d *Dumper) prune() error {
+	retain := d.Retain
+	if retain <= 0 {
+		retain = DefaultRetain
+	}
+	for _, kind := range []string{"cpu", "heap"} {
+		files, err := filepath.Glob(filepath.Join(d.Dir, kind+"-*.pprof"))
+		if err != nil {
+			return err
+		}
+		sort.Strings(files)
+		for len(files) > retain {
+			if err := os.Remove(files[0]); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			files = files[1:]
+		}
+	}
+	return nil
+}
+
+func (d *Dumper) timestamp() string {
+	now := time.Now
+	if d.now != nil {
+		now = d.now
+	}
+	return now().UTC().Format(timestampFormat)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profiling
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestProfiling(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Profiling Suite")
+}
+
+var _ = Describe("Profiling", func() {
+	Context("pprof endpoints", func() {
+		var server *Server
+
+		BeforeEach(func() {
+			// Only the token "admin" authenticates, and only /debug/pprof/cmdline
+			// is granted
+			c := fake.NewClientBuilder().
+				WithScheme(clientgoscheme.Scheme).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+						switch review := obj.(type) {
+						case *authenticationv1.TokenReview:
+							review.Status.Authenticated = review.Spec.Token == "admin"
+							review.Status.User.Username = "admin"
+						case *authorizationv1.SubjectAccessReview:
+							review.Status.Allowed = review.Spec.User == "admin" &&
+								review.Spec.NonResourceAttributes.Path == PathPrefix+"cmdline"
+						}
+						return nil
+					},
+				}).
+				Build()
+			server = &Server{Client: c}
+		})
+
+		get := func(path, token string) int {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			rec := httptest.NewRecorder()
+			server.Handler().ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		It("requires an authenticated and authorized token", func() {
+			Expect(get(PathPrefix+"cmdline", "")).To(Equal(http.StatusUnauthorized))
+			Expect(get(PathPrefix+"cmdline", "guest")).To(Equal(http.StatusUnauthorized))
+			Expect(get(PathPrefix+"heap", "admin")).To(Equal(http.StatusForbidden))
+			Expect(get(PathPrefix+"cmdline", "admin")).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("profile dumps", func() {
+		It("writes CPU and heap profiles and keeps the newest", func() {
+			dir := GinkgoT().TempDir()
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			dumper := &Dumper{
+				Dir:         dir,
+				CPUDuration: 10 * time.Millisecond,
+				Retain:      2,
+				now: func() time.Time {
+					now = now.Add(time.Minute)
+					return now
+				},
+			}
+			for i := 0; i < 3; i++ {
+				Expect(dumper.Dump(context.Background())).To(Succeed())
+			}
+
+			cpu, err := filepath.Glob(filepath.Join(dir, "cpu-*.pprof"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cpu).To(Equal([]string{
+				filepath.Join(dir, "cpu-20250101T000200Z.pprof"),
+				filepath.Join(dir, "cpu-20250101T000300Z.pprof"),
+			}))
+			heap, err := filepath.Glob(filepath.Join(dir, "heap-*.pprof"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(heap).To(HaveLen(2))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package profiling exposes the operator's runtime profiles, either on
+// authenticated pprof endpoints or as periodic dumps to a directory.
+package profiling
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/claude-flow/swarm-operator/pkg/httpserver"
+)
+
+// PathPrefix is where the pprof endpoints are served. Access is granted
+// with the non-resource URL of the endpoint, e.g.
+//
+//	rules:
+//	- nonResourceURLs: ["/debug/pprof", "/debug/pprof/*"]
+//	  verbs: ["get"]
+const PathPrefix = "/debug/pprof/"
+
+// Server serves the pprof endpoints. Requests must carry a bearer token that
+// the API server accepts and that may get the requested non-resource URL.
+type Server struct {
+	// BindAddress is the address the server listens on
+	BindAddress string
+
+	// Client creates the token and access reviews
+	Client client.Client
+}
+
+// NeedLeaderElection lets every replica be profiled
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the server until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	return httpserver.Serve(ctx, "pprof", s.BindAddress, s.Handler())
+}
+
+// Handler returns the authenticated pprof handlers
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathPrefix, pprof.Index)
+	mux.HandleFunc(PathPrefix+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(PathPrefix+"profile", pprof.Profile)
+	mux.HandleFunc(PathPrefix+"symbol", pprof.Symbol)
+	mux.HandleFunc(PathPrefix+"trace", pprof.Trace)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status, err := s.authorize(r.Context(), r); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authorize checks that the user of the bearer token may get the requested
+// path
+func (s *Server) authorize(ctx context.Context, r *http.Request) (int, error) {
+	return httpserver.Authorize(ctx, s.Client, r, authorizationv1.SubjectAccessReviewSpec{
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+			Path: r.URL.Path,
+			Verb: "get",
+		},
+	})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package progress records the progress and usage executors report on their
+// SwarmTasks.
+package progress
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+const (
+	// taskLabel and taskNamespaceLabel map task pods, which may run in
+	// another namespace, back to their task
+	taskLabel          = "swarm.claudeflow.io/task"
+	taskNamespaceLabel = "swarm.claudeflow.io/task-namespace"
+
+	// taskContainer is the executor container of task pods
+	taskContainer = "task"
+)
+
+// ErrNotTaskPod is returned for updates from pods that do not run a task
+var ErrNotTaskPod = errors.New("pod does not run a swarm task")
+
+// Record applies an update sent from the pod to the status of its task. The
+// usage of the update replaces what the same run of the executor reported
+// before, and the task usage is the sum over its runs.
+func Record(ctx context.Context, c client.Client, pod *corev1.Pod, update executor.Progress) error {
+	name := pod.Labels[taskLabel]
+	if name == "" {
+		return ErrNotTaskPod
+	}
+	namespace := pod.Labels[taskNamespaceLabel]
+	if namespace == "" {
+		namespace = pod.Namespace
+	}
+	restart := containerRestarts(pod)
+	now := metav1.Now()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		task := &swarmv1alpha1.SwarmTask{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, task); err != nil {
+			return err
+		}
+
+		task.Status.Progress = min(max(update.Percent, 0), 100)
+		if update.CheckpointRef != "" {
+			task.Status.CheckpointRef = update.CheckpointRef
+		}
+		if update.Usage != nil {
+			recordUsage(task, pod.Name, restart, *update.Usage, now)
+		}
+		return c.Status().Update(ctx, task)
+	})
+}
+
+// recordUsage stores the usage of a run and sums the task usage again.
+// Usage is cumulative, so an update delivered out of order never lowers it.
+func recordUsage(task *swarmv1alpha1.SwarmTask, pod string, restart int32, usage executor.Usage, now metav1.Time) {
+	if task.Status.Usage == nil {
+		task.Status.Usage = &swarmv1alpha1.TaskUsage{}
+	}
+	total := task.Status.Usage
+
+	var run *swarmv1alpha1.TaskRunUsage
+	for i := range total.Runs {
+		if total.Runs[i].Pod == pod && total.Runs[i].Restart == restart {
+			run = &total.Runs[i]
+			break
+		}
+	}
+	if run == nil {
+		total.Runs = append(total.Runs, swarmv1alpha1.TaskRunUsage{Pod: pod, Restart: restart})
+		run = &total.Runs[len(total.Runs)-1]
+	}
+	run.Tokens = max(run.Tokens, usage.Tokens)
+	run.APICalls = max(run.APICalls, usage.APICalls)
+	run.Cost = max(run.Cost, usage.Cost)
+	run.LastReportTime = now
+
+	total.Tokens, total.APICalls, total.Cost = 0, 0, 0
+	for _, r := range total.Runs {
+		total.Tokens += r.Tokens
+		total.APICalls += r.APICalls
+		total.Cost += r.Cost
+	}
+}
+
+// containerRestarts tells runs of the executor in the same pod apart
+func containerRestarts(pod *corev1.Pod) int32 {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == taskContainer {
+			return cs.RestartCount
+		}
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+)
+
+func TestProgress(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Progress Suite")
+}
+
+var _ = Describe("Record", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+	)
+
+	key := types.NamespacedName{Name: "review", Namespace: "default"}
+
+	taskPod := func(name string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "swarm-tasks",
+				Labels:    map[string]string{taskLabel: key.Name, taskNamespaceLabel: key.Namespace},
+			},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: taskContainer, RestartCount: restarts},
+			}},
+		}
+	}
+
+	usage := func() *swarmv1alpha1.TaskUsage {
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(c.Get(ctx, key, task)).To(Succeed())
+		return task.Status.Usage
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(&swarmv1alpha1.SwarmTask{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}).
+			WithStatusSubresource(&swarmv1alpha1.SwarmTask{}).
+			Build()
+	})
+
+	It("records progress and checkpoints on the task of the pod", func() {
+		Expect(Record(ctx, c, taskPod("review-abc", 0), executor.Progress{
+			Task: "someone-else", Percent: 140, CheckpointRef: "s3://checkpoints/review/3",
+		})).To(Succeed())
+
+		task := &swarmv1alpha1.SwarmTask{}
+		Expect(c.Get(ctx, key, task)).To(Succeed())
+		Expect(task.Status.Progress).To(BeEquivalentTo(100))
+		Expect(task.Status.CheckpointRef).To(Equal("s3://checkpoints/review/3"))
+		Expect(task.Status.Usage).To(BeNil())
+	})
+
+	It("sums the cumulative usage of every run", func() {
+		pod := taskPod("review-abc", 0)
+		Expect(Record(ctx, c, pod, executor.Progress{Usage: &executor.Usage{Tokens: 500, APICalls: 2, Cost: 0.5}})).To(Succeed())
+		Expect(Record(ctx, c, pod, executor.Progress{Usage: &executor.Usage{Tokens: 800, APICalls: 3, Cost: 0.75}})).To(Succeed())
+		// A late update does not lower the usage of the run
+		Expect(Record(ctx, c, pod, executor.Progress{Usage: &executor.Usage{Tokens: 600, APICalls: 2, Cost: 0.6}})).To(Succeed())
+		Expect(usage().Tokens).To(BeEquivalentTo(800))
+
+		Expect(Record(ctx, c, taskPod("review-abc", 1), executor.Progress{Usage: &executor.Usage{Tokens: 100, APICalls: 1, Cost: 0.25}})).To(Succeed())
+		Expect(Record(ctx, c, taskPod("review-def", 0), executor.Progress{Usage: &executor.Usage{Tokens: 50, APICalls: 1}})).To(Succeed())
+
+		total := usage()
+		Expect(total.Runs).To(HaveLen(3))
+		Expect(total.Tokens).To(BeEquivalentTo(950))
+		Expect(total.APICalls).To(BeEquivalentTo(5))
+		Expect(total.Cost).To(BeNumerically("~", 1.0, 1e-9))
+	})
+
+	It("rejects pods that do not run a task", func() {
+		pod := taskPod("debug", 0)
+		pod.Labels = nil
+		Expect(Record(ctx, c, pod, executor.Progress{Percent: 10})).To(MatchError(ErrNotTaskPod))
+	})
+})
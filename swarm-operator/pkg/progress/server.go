@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/claude-flow/swarm-operator/pkg/executor"
+	"github.com/claude-flow/swarm-operator/pkg/httpserver"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmtasks/status,verbs=get;update
+
+const (
+	// podNameExtra and podUIDExtra are the user extras a projected service
+	// account token carries the pod it was issued to in
+	podNameExtra = "authentication.kubernetes.io/pod-name"
+	podUIDExtra  = "authentication.kubernetes.io/pod-uid"
+
+	// maxBodyBytes bounds the size of an update
+	maxBodyBytes = 64 << 10
+)
+
+// Server receives the progress updates executors POST to
+// SWARM_PROGRESS_URL. Executors authenticate with the projected service
+// account token of their pod, and an update is recorded on the task the pod
+// runs, whatever task the body names.
+type Server struct {
+	// BindAddress is the address the server listens on
+	BindAddress string
+
+	// Client reads pods and updates task status
+	Client client.Client
+}
+
+// NeedLeaderElection lets every replica receive updates
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the server until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/progress", s.handleProgress)
+
+	return httpserver.Serve(ctx, "progress-api", s.BindAddress, mux)
+}
+
+func (s *Server) handleProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pod, status, err := s.authenticate(ctx, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	var update executor.Progress
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&update); err != nil {
+		http.Error(w, "invalid progress update", http.StatusBadRequest)
+		return
+	}
+
+	if err := Record(ctx, s.Client, pod, update); err != nil {
+		switch {
+		case errors.Is(err, ErrNotTaskPod):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case apierrors.IsNotFound(err):
+			http.Error(w, "task not found", http.StatusNotFound)
+		default:
+			log.FromContext(ctx).Error(err, "Failed to record progress", "pod", client.ObjectKeyFromObject(pod))
+			http.Error(w, "failed to record progress", http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticate reviews the bearer token for the progress audience and
+// returns the pod it was issued to
+func (s *Server) authenticate(ctx context.Context, r *http.Request) (*corev1.Pod, int, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, http.StatusUnauthorized, errors.New("missing bearer token")
+	}
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{executor.ProgressAudience},
+		},
+	}
+	if err := s.Client.Create(ctx, review); err != nil {
+		return nil, http.StatusInternalServerError, errors.New("token review failed")
+	}
+	if !review.Status.Authenticated {
+		return nil, http.StatusUnauthorized, errors.New("invalid bearer token")
+	}
+
+	// system:serviceaccount:<namespace>:<name>
+	parts := strings.Split(review.Status.User.Username, ":")
+	podNames := review.Status.User.Extra[podNameExtra]
+	if len(parts) != 4 || parts[0] != "system" || parts[1] != "serviceaccount" || len(podNames) != 1 {
+		return nil, http.StatusForbidden, errors.New("token is not bound to a pod")
+	}
+
+	pod := &corev1.Pod{}
+	if err := s.Client.Get(ctx, types.NamespacedName{Name: podNames[0], Namespace: parts[2]}, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, http.StatusForbidden, errors.New("pod not found")
+		}
+		return nil, http.StatusInternalServerError, errors.New("failed to get pod")
+	}
+	// A pod recreated under the same name does not inherit the token
+	if uids := review.Status.User.Extra[podUIDExtra]; len(uids) == 1 && uids[0] != string(pod.UID) {
+		return nil, http.StatusForbidden, errors.New("pod not found")
+	}
+	return pod, http.StatusOK, nil
+}
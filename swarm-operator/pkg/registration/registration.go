@@ -0,0 +1,234 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registration lets agents launched outside the cluster register
+// with a SwarmCluster and report their heartbeats.
+package registration
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// TokenKey is the key of the registration token in its Secret
+	TokenKey = "token"
+
+	// ExternalLabel marks the Agents that registered themselves
+	ExternalLabel = "swarm.claudeflow.io/external"
+
+	// DefaultHeartbeatTimeout is how long an external agent may go without
+	// a heartbeat unless the cluster sets its own timeout
+	DefaultHeartbeatTimeout = 2 * time.Minute
+
+	// clusterLabel selects the agents of a cluster
+	clusterLabel = "swarm-cluster"
+)
+
+var (
+	// ErrDisabled is returned for clusters that do not accept registrations
+	ErrDisabled = errors.New("agent registration is disabled")
+
+	// ErrInvalidToken is returned when the token is not the cluster's
+	// registration token
+	ErrInvalidToken = errors.New("invalid registration token")
+
+	// ErrNotExternal is returned when the name is taken by an agent the
+	// operator runs, or by an agent of another cluster
+	ErrNotExternal = errors.New("agent is not an external agent of the cluster")
+)
+
+// Registration is the body an external agent POSTs to register
+type Registration struct {
+	// Cluster and Namespace name the SwarmCluster to join
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+
+	// Name of the Agent, which must be a DNS subdomain
+	Name string `json:"name"`
+
+	// Type, Capabilities and CognitivePattern describe the agent like the
+	// spec of an Agent
+	Type             swarmv1alpha1.AgentType        `json:"type"`
+	Capabilities     []string                       `json:"capabilities,omitempty"`
+	CognitivePattern swarmv1alpha1.CognitivePattern `json:"cognitivePattern,omitempty"`
+
+	// Address is the host:port the agent serves the dispatch gRPC API on
+	Address string `json:"address"`
+}
+
+// Heartbeat is the body an external agent POSTs while it is alive
+type Heartbeat struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// TokenSecretName is the Secret holding the registration token of a cluster
+func TokenSecretName(cluster string) string {
+	return cluster + "-registration"
+}
+
+// Enabled reports whether the cluster accepts registrations
+func Enabled(cluster *swarmv1alpha1.SwarmCluster) bool {
+	return cluster.Spec.Registration != nil && cluster.Spec.Registration.Enabled
+}
+
+// HeartbeatTimeout is how long an external agent of the cluster may go
+// without a heartbeat before it is marked Failed
+func HeartbeatTimeout(cluster *swarmv1alpha1.SwarmCluster) time.Duration {
+	if cluster.Spec.Registration != nil && cluster.Spec.Registration.HeartbeatTimeout != "" {
+		if d, err := time.ParseDuration(cluster.Spec.Registration.HeartbeatTimeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultHeartbeatTimeout
+}
+
+// Validate checks a registration before it is turned into an Agent
+func (reg *Registration) Validate() error {
+	if reg.Cluster == "" || reg.Namespace == "" {
+		return errors.New("cluster and namespace are required")
+	}
+	if errs := validation.IsDNS1123Subdomain(reg.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid agent name %q: %s", reg.Name, errs[0])
+	}
+	if reg.Type == "" {
+		return errors.New("agent type is required")
+	}
+	if _, port, err := net.SplitHostPort(reg.Address); err != nil || port == "" {
+		return fmt.Errorf("invalid agent address %q, expected host:port", reg.Address)
+	}
+	return nil
+}
+
+// Authorize returns the cluster the token registers agents with
+func Authorize(ctx context.Context, c client.Client, key types.NamespacedName, token string) (*swarmv1alpha1.SwarmCluster, error) {
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	if err := c.Get(ctx, key, cluster); err != nil {
+		return nil, err
+	}
+	if !Enabled(cluster) {
+		return nil, ErrDisabled
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: TokenSecretName(cluster.Name), Namespace: cluster.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The operator has not generated the token yet
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	expected := secret.Data[TokenKey]
+	if len(expected) == 0 || subtle.ConstantTimeCompare(expected, []byte(token)) != 1 {
+		return nil, ErrInvalidToken
+	}
+	return cluster, nil
+}
+
+// Register creates the Agent of an external agent, or updates it when the
+// agent registers again, e.g. after a restart or from a new address. The
+// Agent is owned by the cluster and its heartbeat is recorded.
+func Register(ctx context.Context, c client.Client, cluster *swarmv1alpha1.SwarmCluster, reg Registration) (*swarmv1alpha1.Agent, error) {
+	if err := reg.Validate(); err != nil {
+		return nil, err
+	}
+
+	agent := &swarmv1alpha1.Agent{}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := c.Get(ctx, types.NamespacedName{Name: reg.Name, Namespace: cluster.Namespace}, agent)
+		if apierrors.IsNotFound(err) {
+			agent = &swarmv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      reg.Name,
+					Namespace: cluster.Namespace,
+					Labels: map[string]string{
+						clusterLabel:  cluster.Name,
+						ExternalLabel: "true",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						*metav1.NewControllerRef(cluster, swarmv1alpha1.GroupVersion.WithKind("SwarmCluster")),
+					},
+				},
+				Spec: swarmv1alpha1.AgentSpec{SwarmCluster: cluster.Name, External: true},
+			}
+			applyRegistration(agent, reg)
+			return c.Create(ctx, agent)
+		}
+		if err != nil {
+			return err
+		}
+		if !agent.Spec.External || agent.Spec.SwarmCluster != cluster.Name {
+			return ErrNotExternal
+		}
+		if agent.GetDeletionTimestamp() != nil {
+			return apierrors.NewConflict(swarmv1alpha1.GroupVersion.WithResource("agents").GroupResource(),
+				agent.Name, errors.New("agent is being removed"))
+		}
+		applyRegistration(agent, reg)
+		return c.Update(ctx, agent)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := RecordHeartbeat(ctx, c, cluster, agent.Name); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+// applyRegistration copies what the agent reported into its spec, leaving
+// the peers and cordon the operator manages alone
+func applyRegistration(agent *swarmv1alpha1.Agent, reg Registration) {
+	agent.Spec.Type = reg.Type
+	agent.Spec.Capabilities = reg.Capabilities
+	if reg.CognitivePattern != "" {
+		agent.Spec.CognitivePattern = reg.CognitivePattern
+	}
+	agent.Spec.CommunicationEndpoints.Protocol = "grpc"
+	agent.Spec.CommunicationEndpoints.Address = reg.Address
+}
+
+// RecordHeartbeat records that an external agent of the cluster is alive
+func RecordHeartbeat(ctx context.Context, c client.Client, cluster *swarmv1alpha1.SwarmCluster, name string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		agent := &swarmv1alpha1.Agent{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, agent); err != nil {
+			return err
+		}
+		if !agent.Spec.External || agent.Spec.SwarmCluster != cluster.Name {
+			return ErrNotExternal
+		}
+		agent.Status.LastHeartbeat = &metav1.Time{Time: time.Now()}
+		return c.Status().Update(ctx, agent)
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+func TestRegistration(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Registration Suite")
+}
+
+var _ = Describe("Registration", func() {
+	var (
+		ctx     context.Context
+		c       client.Client
+		cluster *swarmv1alpha1.SwarmCluster
+		reg     Registration
+	)
+
+	key := types.NamespacedName{Name: "swarm", Namespace: "default"}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "swarm-uid"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Registration: &swarmv1alpha1.AgentRegistrationSpec{Enabled: true},
+			},
+		}
+		c = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(cluster,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "swarm-registration", Namespace: "default"},
+					Data:       map[string][]byte{TokenKey: []byte("s3cret")},
+				},
+				&swarmv1alpha1.Agent{
+					ObjectMeta: metav1.ObjectMeta{Name: "coder-a", Namespace: "default"},
+					Spec:       swarmv1alpha1.AgentSpec{Type: swarmv1alpha1.CoderAgent, SwarmCluster: "swarm"},
+				}).
+			WithStatusSubresource(&swarmv1alpha1.Agent{}).
+			Build()
+		reg = Registration{
+			Cluster: "swarm", Namespace: "default", Name: "laptop",
+			Type: swarmv1alpha1.ReviewerAgent, Capabilities: []string{"review"}, Address: "10.1.2.3:8080",
+		}
+	})
+
+	It("authorizes the cluster's registration token only", func() {
+		authorized, err := Authorize(ctx, c, key, "s3cret")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorized.Name).To(Equal("swarm"))
+
+		_, err = Authorize(ctx, c, key, "guess")
+		Expect(err).To(MatchError(ErrInvalidToken))
+
+		cluster.Spec.Registration.Enabled = false
+		Expect(c.Update(ctx, cluster)).To(Succeed())
+		_, err = Authorize(ctx, c, key, "s3cret")
+		Expect(err).To(MatchError(ErrDisabled))
+	})
+
+	It("registers an external agent owned by the cluster", func() {
+		agent, err := Register(ctx, c, cluster, reg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Get(ctx, types.NamespacedName{Name: "laptop", Namespace: "default"}, agent)).To(Succeed())
+		Expect(agent.Spec.External).To(BeTrue())
+		Expect(agent.Spec.SwarmCluster).To(Equal("swarm"))
+		Expect(agent.Spec.CommunicationEndpoints.Address).To(Equal("10.1.2.3:8080"))
+		Expect(agent.Labels).To(HaveKeyWithValue("swarm-cluster", "swarm"))
+		Expect(metav1.IsControlledBy(agent, cluster)).To(BeTrue())
+		Expect(agent.Status.LastHeartbeat).NotTo(BeNil())
+
+		// Registering again moves the agent to its new address
+		reg.Address = "10.1.2.4:8080"
+		_, err = Register(ctx, c, cluster, reg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, types.NamespacedName{Name: "laptop", Namespace: "default"}, agent)).To(Succeed())
+		Expect(agent.Spec.CommunicationEndpoints.Address).To(Equal("10.1.2.4:8080"))
+	})
+
+	It("does not take over the agents the operator runs", func() {
+		reg.Name = "coder-a"
+		_, err := Register(ctx, c, cluster, reg)
+		Expect(err).To(MatchError(ErrNotExternal))
+		Expect(RecordHeartbeat(ctx, c, cluster, "coder-a")).To(MatchError(ErrNotExternal))
+	})
+
+	It("rejects registrations without a reachable address", func() {
+		reg.Address = "10.1.2.3"
+		Expect(reg.Validate()).To(HaveOccurred())
+	})
+
+	It("rejects requests without the registration token", func() {
+		server := &Server{Client: c}
+		body := `{"cluster":"swarm","namespace":"default","name":"laptop","type":"reviewer","address":"10.1.2.3:8080"}`
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/agents/register", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer guess")
+		rec := httptest.NewRecorder()
+		server.handleRegister(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/agents/register", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rec = httptest.NewRecorder()
+		server.handleRegister(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,157 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/httpserver"
+)
+
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=swarmclusters,verbs=get
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=agents,verbs=get;create;update
+// +kubebuilder:rbac:groups=swarm.claudeflow.io,resources=agents/status,verbs=get;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// maxBodyBytes bounds the size of a request
+const maxBodyBytes = 64 << 10
+
+// Server receives the registrations and heartbeats of external agents.
+// Agents authenticate with the registration token of the cluster they
+// join as a bearer token.
+type Server struct {
+	// BindAddress is the address the server listens on
+	BindAddress string
+
+	// Client reads clusters and their tokens and writes Agents
+	Client client.Client
+}
+
+// NeedLeaderElection lets every replica receive registrations
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the server until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/agents/register", s.handleRegister)
+	mux.HandleFunc("POST /api/v1/agents/heartbeat", s.handleHeartbeat)
+
+	return httpserver.Serve(ctx, "registration-api", s.BindAddress, mux)
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var reg Registration
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&reg); err != nil {
+		http.Error(w, "invalid registration", http.StatusBadRequest)
+		return
+	}
+	if err := reg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cluster, ok := s.authorize(w, r, reg.Namespace, reg.Cluster)
+	if !ok {
+		return
+	}
+
+	agent, err := Register(ctx, s.Client, cluster, reg)
+	if err != nil {
+		s.writeError(ctx, w, err, "failed to register agent")
+		return
+	}
+	log.FromContext(ctx).Info("Registered external agent", "agent", client.ObjectKeyFromObject(agent), "address", reg.Address)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]string{"name": agent.Name, "namespace": agent.Namespace})
+}
+
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var heartbeat Heartbeat
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&heartbeat); err != nil {
+		http.Error(w, "invalid heartbeat", http.StatusBadRequest)
+		return
+	}
+	cluster, ok := s.authorize(w, r, heartbeat.Namespace, heartbeat.Cluster)
+	if !ok {
+		return
+	}
+
+	if err := RecordHeartbeat(ctx, s.Client, cluster, heartbeat.Name); err != nil {
+		s.writeError(ctx, w, err, "failed to record heartbeat")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorize checks the bearer token against the registration token of the
+// cluster and writes the error response if it does not match
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, namespace, name string) (*swarmv1alpha1.SwarmCluster, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return nil, false
+	}
+	if namespace == "" || name == "" {
+		http.Error(w, "cluster and namespace are required", http.StatusBadRequest)
+		return nil, false
+	}
+
+	cluster, err := Authorize(r.Context(), s.Client, types.NamespacedName{Name: name, Namespace: namespace}, token)
+	if err != nil {
+		// Unknown clusters look like a wrong token, so the API does not
+		// reveal which clusters exist
+		if apierrors.IsNotFound(err) || errors.Is(err, ErrDisabled) {
+			err = ErrInvalidToken
+		}
+		s.writeError(r.Context(), w, err, "failed to authorize agent")
+		return nil, false
+	}
+	return cluster, true
+}
+
+func (s *Server) writeError(ctx context.Context, w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, ErrInvalidToken):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrNotExternal):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case apierrors.IsNotFound(err):
+		http.Error(w, "agent not found", http.StatusNotFound)
+	case apierrors.IsConflict(err), apierrors.IsAlreadyExists(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.FromContext(ctx).Error(err, message)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+const (
+	// PolicyReuse enables reading and writing cached results
+	PolicyReuse = "reuse"
+
+	// memoryNamespace is the SwarmMemory namespace cached results live in
+	memoryNamespace = "task-results"
+)
+
+// schedulingFields are the task spec fields that only affect when and how
+// often a task runs, not what it produces. Every other field, including
+// ones added later, is part of the cache key.
+var schedulingFields = []string{
+	"idempotencyKey",
+	"priority",
+	"timeout",
+	"retryPolicy",
+	"resultStorage",
+	"preemptible",
+	"resume",
+	"cachePolicy",
+	"budget",
+	"queueName",
+}
+
+// Key returns the content hash of everything that determines the task's
+// result: the task spec without its scheduling fields, the cluster it runs
+// on and the executor image it runs in. Ordering of repositories,
+// capabilities and preferred agent types does not matter.
+func Key(spec *swarmv1alpha1.SwarmTaskSpec, cluster, image string) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cache key input: %w", err)
+	}
+	input := map[string]interface{}{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return "", fmt.Errorf("failed to encode cache key input: %w", err)
+	}
+	for _, field := range schedulingFields {
+		delete(input, field)
+	}
+
+	var agentTypes []string
+	for _, t := range spec.PreferredAgentTypes {
+		agentTypes = append(agentTypes, string(t))
+	}
+	setSorted(input, "repositories", spec.Repositories)
+	setSorted(input, "requiredCapabilities", spec.RequiredCapabilities)
+	setSorted(input, "preferredAgentTypes", agentTypes)
+	input["swarmCluster"] = cluster
+	input["executorImage"] = image
+
+	// encoding/json writes map keys in sorted order, so this is stable
+	if data, err = json.Marshal(input); err != nil {
+		return "", fmt.Errorf("failed to encode cache key input: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func setSorted(input map[string]interface{}, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	input[key] = sortedCopy(values)
+}
+
+func sortedCopy(in []string) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+	return out
+}
+
+// entryName is the SwarmMemory name holding the result for a key
+func entryName(key string) string {
+	return fmt.Sprintf("task-result-%s", key[:32])
+}
+
+// Cache stores task results as SwarmMemory entries of the task's cluster
+type Cache struct {
+	client.Client
+}
+
+// New creates a result cache backed by SwarmMemory objects
+func New(c client.Client) *Cache {
+	return &Cache{Client: c}
+}
+
+// Lookup returns the result cached for key by a task of the same namespace
+// and cluster, or nil if there is no live entry
+func (c *Cache) Lookup(ctx context.Context, namespace, cluster, key string) (*swarmv1alpha1.TaskResult, error) {
+	entry := &swarmv1alpha1.SwarmMemory{}
+	err := c.Get(ctx, types.NamespacedName{Name: entryName(key), Namespace: namespace}, entry)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Guard against prefix collisions, other clusters' results and expired
+	// entries
+	if entry.Spec.Key != key || entry.Spec.ClusterRef != cluster || isExpired(entry) {
+		return nil, nil
+	}
+
+	result := &swarmv1alpha1.TaskResult{}
+	if err := json.Unmarshal([]byte(entry.Spec.Value), result); err != nil {
+		return nil, fmt.Errorf("corrupt cache entry %s: %w", entry.Name, err)
+	}
+	return result, nil
+}
+
+// Store records the result of a successful task on cluster under key
+func (c *Cache) Store(ctx context.Context, task *swarmv1alpha1.SwarmTask, cluster, key string, result *swarmv1alpha1.TaskResult) error {
+	value, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	entry := &swarmv1alpha1.SwarmMemory{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      entryName(key),
+			Namespace: task.Namespace,
+			Labels: map[string]string{
+				"swarm-cluster":            cluster,
+				"swarm.claudeflow.io/task": task.Name,
+			},
+		},
+		Spec: swarmv1alpha1.SwarmMemorySpec{
+			ClusterRef: cluster,
+			Namespace:  memoryNamespace,
+			Type:       swarmv1alpha1.MemoryTypeTaskResult,
+			Key:        key,
+			Value:      string(value),
+			TTL:        task.Spec.ResultStorage.TTL,
+			Tags:       []string{task.Spec.Type},
+		},
+	}
+
+	err = c.Create(ctx, entry)
+	if errors.IsAlreadyExists(err) {
+		existing := &swarmv1alpha1.SwarmMemory{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(entry), existing); err != nil {
+			return err
+		}
+		existing.Labels = entry.Labels
+		existing.Spec = entry.Spec
+		return c.Update(ctx, existing)
+	}
+	return err
+}
+
+func isExpired(entry *swarmv1alpha1.SwarmMemory) bool {
+	if entry.Status.ExpiresAt != nil {
+		return time.Now().After(entry.Status.ExpiresAt.Time)
+	}
+	if entry.Spec.TTL > 0 {
+		return time.Since(entry.CreationTimestamp.Time) > time.Duration(entry.Spec.TTL)*time.Second
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultcache
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+func TestResultCache(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Result Cache Suite")
+}
+
+var _ = Describe("Result cache", func() {
+	var spec swarmv1alpha1.SwarmTaskSpec
+
+	BeforeEach(func() {
+		spec = swarmv1alpha1.SwarmTaskSpec{
+			SwarmCluster: "test-swarm",
+			Type:         "analysis",
+			Description:  "Analyze repo",
+			Repositories: []string{"org/b", "org/a"},
+			Parameters:   map[string]string{"depth": "2"},
+			PinnedInputs: map[string]string{"commit": "abc123"},
+			Priority:     swarmv1alpha1.HighPriority,
+		}
+	})
+
+	Describe("Key", func() {
+		It("should ignore ordering and scheduling fields", func() {
+			key, err := Key(&spec, "test-swarm", "executor:v1")
+			Expect(err).NotTo(HaveOccurred())
+
+			other := spec
+			other.Repositories = []string{"org/a", "org/b"}
+			other.Priority = swarmv1alpha1.LowPriority
+			other.Timeout = 600
+			otherKey, err := Key(&other, "test-swarm", "executor:v1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otherKey).To(Equal(key))
+		})
+
+		It("should change when pinned inputs change", func() {
+			key, _ := Key(&spec, "test-swarm", "executor:v1")
+			spec.PinnedInputs = map[string]string{"commit": "def456"}
+			otherKey, _ := Key(&spec, "test-swarm", "executor:v1")
+			Expect(otherKey).NotTo(Equal(key))
+		})
+
+		It("should change with the cluster, executor image and environment", func() {
+			key, _ := Key(&spec, "test-swarm", "executor:v1")
+			otherCluster, _ := Key(&spec, "other-swarm", "executor:v1")
+			otherImage, _ := Key(&spec, "test-swarm", "executor:v2")
+			spec.Env = []swarmv1alpha1.TaskEnvVar{{Name: "MODE", Value: "strict"}}
+			otherEnv, _ := Key(&spec, "test-swarm", "executor:v1")
+			Expect([]string{otherCluster, otherImage, otherEnv}).NotTo(ContainElement(key))
+		})
+	})
+
+	Describe("Store and Lookup", func() {
+		It("should round-trip a result", func() {
+			scheme := runtime.NewScheme()
+			Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
+			cache := New(fake.NewClientBuilder().WithScheme(scheme).Build())
+			ctx := context.Background()
+
+			task := &swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "task-a", Namespace: "default"},
+				Spec:       spec,
+			}
+			key, _ := Key(&spec, "test-swarm", "executor:v1")
+
+			result, err := cache.Lookup(ctx, "default", "test-swarm", key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeNil())
+
+			Expect(cache.Store(ctx, task, "test-swarm", key, &swarmv1alpha1.TaskResult{
+				Success: true,
+				Summary: "done",
+			})).To(Succeed())
+
+			result, err = cache.Lookup(ctx, "default", "test-swarm", key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).NotTo(BeNil())
+			Expect(result.Summary).To(Equal("done"))
+
+			result, err = cache.Lookup(ctx, "default", "other-swarm", key)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSchedule(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Schedule Suite")
+}
+
+var _ = Describe("Schedule", func() {
+	// 2026-10-16 is a Friday
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	It("finds the next activation", func() {
+		s, err := Parse("30 2 * * 1-5")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Next(now)).To(Equal(time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC)))
+	})
+
+	It("supports steps, lists and macros", func() {
+		s, err := Parse("*/20 9,17 * * *")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Next(now)).To(Equal(time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC)))
+		Expect(s.Matches(time.Date(2026, 10, 16, 9, 40, 0, 0, time.UTC))).To(BeTrue())
+		Expect(s.Matches(time.Date(2026, 10, 16, 9, 41, 0, 0, time.UTC))).To(BeFalse())
+
+		s, err = Parse("@daily")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Next(now)).To(Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("matches either day field when both are restricted", func() {
+		s, err := Parse("0 0 13 * 5")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Next(now)).To(Equal(time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("treats 7 as Sunday", func() {
+		s, err := Parse("0 0 * * 7")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Next(now)).To(Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("returns the zero time for schedules that never fire", func() {
+		s, err := Parse("0 0 30 2 *")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Next(now).IsZero()).To(BeTrue())
+	})
+
+	It("rejects malformed expressions", func() {
+		for _, spec := range []string{"* * * *", "61 * * * *", "* * * 0 *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+			_, err := Parse(spec)
+			Expect(err).To(HaveOccurred(), spec)
+		}
+	})
+})
+
+var _ = Describe("Window", func() {
+	It("is open between an activation and its duration in the window's zone", func() {
+		w, err := NewWindow("0 22 * * *", 4*time.Hour, "Europe/Berlin")
+		Expect(err).NotTo(HaveOccurred())
+
+		// 22:30 in Berlin
+		end, active := w.Active(time.Date(2026, 10, 16, 20, 30, 0, 0, time.UTC))
+		Expect(active).To(BeTrue())
+		Expect(end.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+
+		// 04:30 in Berlin, closed at 02:00
+		_, active = w.Active(time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC))
+		Expect(active).To(BeFalse())
+		Expect(w.NextStart(time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC)).Equal(
+			time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("extends the end over overlapping activations", func() {
+		w, err := NewWindow("0 * * * *", 90*time.Minute, "")
+		Expect(err).NotTo(HaveOccurred())
+		end, active := w.Active(time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC))
+		Expect(active).To(BeTrue())
+		Expect(end).To(Equal(time.Date(2026, 10, 16, 13, 30, 0, 0, time.UTC)))
+	})
+
+	It("rejects unknown zones and non-positive durations", func() {
+		_, err := NewWindow("@daily", time.Hour, "Mars/Olympus")
+		Expect(err).To(HaveOccurred())
+		_, err = NewWindow("@daily", 0, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
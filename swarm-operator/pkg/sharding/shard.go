@@ -0,0 +1,242 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// GroupLabel marks the member leases of a shard group
+	GroupLabel = "swarm.claudeflow.io/shard-group"
+
+	// DefaultLeaseDuration is how long a member that stopped renewing its
+	// lease keeps its range
+	DefaultLeaseDuration = 15 * time.Second
+
+	// DefaultRenewInterval is how often members renew their lease and look
+	// for peers joining or leaving
+	DefaultRenewInterval = 5 * time.Second
+)
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update;delete
+
+// Shard is this replica's membership of a shard group. Every replica keeps a
+// lease in the group, and the live leases form a consistent hash ring over
+// which the replicas split the keys. Replicas that stop renewing their lease
+// drop out of the ring and their range moves to the others.
+type Shard struct {
+	// Client writes the member lease
+	Client client.Client
+
+	// Reader reads the member leases, typically the manager's API reader
+	// as the operator namespace need not be cached
+	Reader client.Reader
+
+	// Namespace the member leases live in
+	Namespace string
+
+	// Group names the shard group
+	Group string
+
+	// Identity of this replica, unique within the group
+	Identity string
+
+	// LeaseDuration defaults to DefaultLeaseDuration
+	LeaseDuration time.Duration
+
+	// RenewInterval defaults to DefaultRenewInterval
+	RenewInterval time.Duration
+
+	// OnRebalance is called when replicas joined or left the group, after
+	// the new ring is in place
+	OnRebalance func(ctx context.Context)
+
+	mu   sync.RWMutex
+	ring *Ring
+}
+
+// NeedLeaderElection runs the shard on every replica
+func (s *Shard) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the member lease until the context is cancelled, then
+// releases it so the peers take over the range right away
+func (s *Shard) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("shard").WithValues("group", s.Group, "identity", s.Identity)
+	log.Info("Joining shard group")
+
+	ticker := time.NewTicker(s.renewInterval())
+	defer ticker.Stop()
+	for {
+		changed, err := s.Sync(ctx, time.Now())
+		if err != nil {
+			log.Error(err, "Failed to sync shard group")
+		} else if changed {
+			log.Info("Shard group changed, rebalancing", "members", s.Members())
+			if s.OnRebalance != nil {
+				s.OnRebalance(ctx)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return s.release()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync renews the member lease and rebuilds the ring from the live leases.
+// It reports whether the members changed.
+func (s *Shard) Sync(ctx context.Context, now time.Time) (bool, error) {
+	if err := s.renew(ctx, now); err != nil {
+		return false, err
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := s.Reader.List(ctx, leases, client.InNamespace(s.Namespace),
+		client.MatchingLabels{GroupLabel: s.Group}); err != nil {
+		return false, err
+	}
+	members := []string{s.Identity}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		holder := ptrValue(lease.Spec.HolderIdentity)
+		if holder == "" || holder == s.Identity || lease.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if leaseExpired(lease, now) {
+			continue
+		}
+		members = append(members, holder)
+	}
+	ring := NewRing(members, 0)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ring != nil && slices.Equal(s.ring.Members(), ring.Members()) {
+		return false, nil
+	}
+	s.ring = ring
+	return true, nil
+}
+
+// Owns reports whether this replica owns the object of the UID. Nothing is
+// owned before the first sync. Replicas see a change up to one renew
+// interval apart, so an object may briefly have two owners or none.
+func (s *Shard) Owns(uid types.UID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ring.Owner(string(uid)) == s.Identity
+}
+
+// Members returns the live members of the group
+func (s *Shard) Members() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ring.Members()
+}
+
+// renew creates or renews the member lease
+func (s *Shard) renew(ctx context.Context, now time.Time) error {
+	duration := int32(s.leaseDuration().Seconds())
+	renewTime := metav1.NewMicroTime(now)
+
+	lease := &coordinationv1.Lease{}
+	err := s.Reader.Get(ctx, types.NamespacedName{Name: s.leaseName(), Namespace: s.Namespace}, lease)
+	if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.leaseName(),
+				Namespace: s.Namespace,
+				Labels:    map[string]string{GroupLabel: s.Group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &s.Identity,
+				LeaseDurationSeconds: &duration,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		return s.Client.Create(ctx, lease)
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = &s.Identity
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &renewTime
+	return s.Client.Update(ctx, lease)
+}
+
+// release deletes the member lease. The context is already cancelled, so
+// the delete gets a few seconds of its own.
+func (s *Shard) release() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: s.leaseName(), Namespace: s.Namespace}}
+	if err := s.Client.Delete(ctx, lease); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("releasing shard lease: %w", err)
+	}
+	return nil
+}
+
+func (s *Shard) leaseName() string {
+	return s.Group + "-" + s.Identity
+}
+
+func (s *Shard) leaseDuration() time.Duration {
+	if s.LeaseDuration > 0 {
+		return s.LeaseDuration
+	}
+	return DefaultLeaseDuration
+}
+
+func (s *Shard) renewInterval() time.Duration {
+	if s.RenewInterval > 0 {
+		return s.RenewInterval
+	}
+	return DefaultRenewInterval
+}
+
+// leaseExpired reports whether the holder stopped renewing the lease
+func leaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return now.After(expiry)
+}
+
+func ptrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSharding(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sharding Suite")
+}
+
+var _ = Describe("Ring", func() {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("task-uid-%d", i)
+	}
+
+	It("spreads keys over all members", func() {
+		ring := NewRing([]string{"a", "b", "c"}, 0)
+		counts := map[string]int{}
+		for _, key := range keys {
+			counts[ring.Owner(key)]++
+		}
+		Expect(counts).To(HaveLen(3))
+		for _, count := range counts {
+			Expect(count).To(BeNumerically(">", 200))
+		}
+	})
+
+	It("only moves keys to a member that joins", func() {
+		before := NewRing([]string{"a", "b", "c"}, 0)
+		after := NewRing([]string{"a", "b", "c", "d"}, 0)
+		moved := 0
+		for _, key := range keys {
+			if owner := after.Owner(key); owner != before.Owner(key) {
+				Expect(owner).To(Equal("d"))
+				moved++
+			}
+		}
+		Expect(moved).To(BeNumerically("~", 250, 100))
+	})
+
+	It("owns nothing when empty", func() {
+		Expect(NewRing(nil, 0).Owner("key")).To(BeEmpty())
+		var ring *Ring
+		Expect(ring.Owner("key")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Shard", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+		now time.Time
+	)
+
+	newShard := func(identity string) *Shard {
+		return &Shard{Client: c, Reader: c, Namespace: "operator", Group: "swarmtask", Identity: identity}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		scheme := runtime.NewScheme()
+		Expect(coordinationv1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+	})
+
+	It("splits the keys between the live replicas", func() {
+		a, b := newShard("a"), newShard("b")
+		Expect(a.Owns("uid")).To(BeFalse())
+
+		changed, err := a.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(a.Members()).To(Equal([]string{"a"}))
+
+		_, err = b.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		changed, err = a.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(a.Members()).To(Equal([]string{"a", "b"}))
+
+		owned := map[string]int{}
+		for i := 0; i < 100; i++ {
+			uid := types.UID(fmt.Sprintf("uid-%d", i))
+			Expect(a.Owns(uid)).NotTo(Equal(b.Owns(uid)))
+			if a.Owns(uid) {
+				owned["a"]++
+			} else {
+				owned["b"]++
+			}
+		}
+		Expect(owned).To(HaveLen(2))
+
+		changed, err = a.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("takes over the range of replicas that stopped renewing", func() {
+		a, b := newShard("a"), newShard("b")
+		_, err := b.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = a.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a.Members()).To(Equal([]string{"a", "b"}))
+
+		changed, err := a.Sync(ctx, now.Add(DefaultLeaseDuration+time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(a.Members()).To(Equal([]string{"a"}))
+		Expect(a.Owns("uid")).To(BeTrue())
+	})
+
+	It("releases its lease when it stops", func() {
+		a := newShard("a")
+		_, err := a.Sync(ctx, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(a.release()).To(Succeed())
+		Expect(c.Get(ctx, types.NamespacedName{Name: "swarmtask-a", Namespace: "operator"}, &coordinationv1.Lease{})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/archive"
+	"github.com/claude-flow/swarm-operator/pkg/httpserver"
+)
+
+// Server serves the aggregated summary API and the live task stream.
+// Requests must carry a bearer token that the API server accepts and that is
+// allowed to get the SwarmCluster being summarized.
+type Server struct {
+	// BindAddress is the address the server listens on
+	BindAddress string
+
+	// Client reads swarm resources, typically the manager's cached client
+	Client client.Client
+
+	// DefaultNamespace is used when the request has no namespace parameter
+	DefaultNamespace string
+
+	// Informers feeds the live task stream, typically the manager's cache.
+	// The stream endpoint is disabled when nil.
+	Informers cache.Informers
+
+	hub *Hub
+}
+
+// NeedLeaderElection lets every replica serve read traffic
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the server until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/clusters/{name}/summary", s.handleSummary)
+	mux.HandleFunc("GET /api/v1/clusters/{name}/history", s.handleHistory)
+
+	if s.Informers != nil {
+		informer, err := s.Informers.GetInformer(ctx, &swarmv1alpha1.SwarmTask{})
+		if err != nil {
+			return err
+		}
+		s.hub = NewHub()
+		if _, err := informer.AddEventHandler(s.hub.EventHandler()); err != nil {
+			return err
+		}
+		mux.HandleFunc("GET /api/v1/clusters/{name}/tasks/stream", s.handleTaskStream)
+	}
+
+	return httpserver.Serve(ctx, "summary-api", s.BindAddress, mux)
+}
+
+func (s *Server) handleSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := r.PathValue("name")
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		namespace = s.DefaultNamespace
+	}
+
+	status, err := s.authorize(ctx, r, namespace, name)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	summary, err := Build(ctx, s.Client, namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		log.FromContext(ctx).Error(err, "Failed to build summary", "cluster", name)
+		http.Error(w, "failed to build summary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to encode summary")
+	}
+}
+
+// handleHistory lists the archived tasks of a cluster, see archive.ParseQuery
+// for the supported parameters
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := r.PathValue("name")
+	params := r.URL.Query()
+	namespace := params.Get("namespace")
+	if namespace == "" {
+		namespace = s.DefaultNamespace
+	}
+
+	status, err := s.authorize(ctx, r, namespace, name)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	query, err := archive.ParseQuery(params, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.Namespace = namespace
+	query.Cluster = name
+
+	records, err := archive.List(ctx, s.Client, query)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list task history", "cluster", name)
+		http.Error(w, "failed to list task history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to encode task history")
+	}
+}
+
+// authorize checks that the user of the bearer token may get the
+// SwarmCluster
+func (s *Server) authorize(ctx context.Context, r *http.Request, namespace, name string) (int, error) {
+	return httpserver.Authorize(ctx, s.Client, r, authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      "get",
+			Group:     swarmv1alpha1.GroupVersion.Group,
+			Resource:  "swarmclusters",
+			Name:      name,
+		},
+	})
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// Task event types sent on the stream
+const (
+	// TaskEventSnapshot carries the current state of a task when a client
+	// subscribes
+	TaskEventSnapshot = "snapshot"
+	// TaskEventPhase is sent when a task changes phase
+	TaskEventPhase = "phase"
+	// TaskEventProgress is sent when the progress percentage changes
+	TaskEventProgress = "progress"
+	// TaskEventCheckpoint is sent when the executor records a new checkpoint
+	TaskEventCheckpoint = "checkpoint"
+	// TaskEventLog carries a log excerpt, the result summary or the log tail
+	// of a failed attempt
+	TaskEventLog = "log"
+	// TaskEventDeleted is sent when a task is deleted
+	TaskEventDeleted = "deleted"
+)
+
+const (
+	// streamBuffer is the number of events queued per subscriber. A client
+	// that falls further behind is disconnected and expected to reconnect.
+	streamBuffer = 256
+
+	// streamKeepAlive is the interval between SSE comments that keep idle
+	// connections open through proxies
+	streamKeepAlive = 15 * time.Second
+)
+
+// TaskEvent is a live update of a SwarmTask
+type TaskEvent struct {
+	Type          string            `json:"type"`
+	Namespace     string            `json:"namespace"`
+	Name          string            `json:"name"`
+	Cluster       string            `json:"cluster"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Phase         string            `json:"phase,omitempty"`
+	PreviousPhase string            `json:"previousPhase,omitempty"`
+	Progress      int32             `json:"progress"`
+	CheckpointRef string            `json:"checkpointRef,omitempty"`
+	Message       string            `json:"message,omitempty"`
+	Lines         []string          `json:"lines,omitempty"`
+	Time          metav1.Time       `json:"time"`
+}
+
+// TaskEvents returns the events describing the change from old to task. A nil
+// old task is a newly observed task.
+func TaskEvents(old, task *swarmv1alpha1.SwarmTask, now time.Time) []TaskEvent {
+	base := newTaskEvent("", task, now)
+	if old == nil {
+		old = &swarmv1alpha1.SwarmTask{}
+	}
+
+	var events []TaskEvent
+	if task.Status.Phase != old.Status.Phase {
+		ev := base
+		ev.Type = TaskEventPhase
+		ev.PreviousPhase = old.Status.Phase
+		events = append(events, ev)
+	}
+	if task.Status.Progress != old.Status.Progress {
+		ev := base
+		ev.Type = TaskEventProgress
+		events = append(events, ev)
+	}
+	if task.Status.CheckpointRef != "" && task.Status.CheckpointRef != old.Status.CheckpointRef {
+		ev := base
+		ev.Type = TaskEventCheckpoint
+		events = append(events, ev)
+	}
+	if details := task.Status.FailureDetails; details != nil &&
+		(old.Status.FailureDetails == nil || !details.ObservedAt.Equal(&old.Status.FailureDetails.ObservedAt)) {
+		ev := base
+		ev.Type = TaskEventLog
+		ev.Message = details.Message
+		if details.Reason != "" {
+			ev.Message = fmt.Sprintf("attempt %d failed: %s", details.Attempt, details.Reason)
+		}
+		ev.Lines = details.LogTail
+		events = append(events, ev)
+	}
+	if result := task.Status.Result; result != nil && result.Summary != "" &&
+		(old.Status.Result == nil || old.Status.Result.Summary != result.Summary) {
+		ev := base
+		ev.Type = TaskEventLog
+		ev.Message = result.Summary
+		events = append(events, ev)
+	}
+	return events
+}
+
+func newTaskEvent(eventType string, task *swarmv1alpha1.SwarmTask, now time.Time) TaskEvent {
+	return TaskEvent{
+		Type:          eventType,
+		Namespace:     task.Namespace,
+		Name:          task.Name,
+		Cluster:       task.Spec.SwarmCluster,
+		Labels:        task.Labels,
+		Phase:         task.Status.Phase,
+		Progress:      task.Status.Progress,
+		CheckpointRef: task.Status.CheckpointRef,
+		Time:          metav1.NewTime(now),
+	}
+}
+
+// StreamFilter selects the events a subscriber receives. Empty fields match
+// everything.
+type StreamFilter struct {
+	Namespace string
+	Cluster   string
+	Selector  labels.Selector
+}
+
+// Matches reports whether the event passes the filter
+func (f StreamFilter) Matches(ev TaskEvent) bool {
+	if f.Namespace != "" && ev.Namespace != f.Namespace {
+		return false
+	}
+	if f.Cluster != "" && ev.Cluster != f.Cluster {
+		return false
+	}
+	if f.Selector != nil && !f.Selector.Matches(labels.Set(ev.Labels)) {
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	filter StreamFilter
+	ch     chan TaskEvent
+}
+
+// Hub fans task events out to stream subscribers. It is fed by a SwarmTask
+// informer, so dashboards share the manager's watch instead of each polling
+// the API server.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+	now  func() time.Time
+}
+
+// NewHub returns an empty hub
+func NewHub() *Hub {
+	return &Hub{subs: map[*subscriber]struct{}{}, now: time.Now}
+}
+
+// Subscribe registers a subscriber and returns its event channel and a
+// function that unregisters it. The channel is closed when the subscriber is
+// cancelled or falls too far behind.
+func (h *Hub) Subscribe(filter StreamFilter) (<-chan TaskEvent, func()) {
+	sub := &subscriber{filter: filter, ch: make(chan TaskEvent, streamBuffer)}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub.ch, func() { h.remove(sub) }
+}
+
+func (h *Hub) remove(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Publish delivers events to every matching subscriber without blocking
+func (h *Hub) Publish(events ...TaskEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		for _, ev := range events {
+			if !sub.filter.Matches(ev) {
+				continue
+			}
+			select {
+			case sub.ch <- ev:
+			default:
+				delete(h.subs, sub)
+				close(sub.ch)
+			}
+			if _, ok := h.subs[sub]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// Subscribers returns the number of connected subscribers
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// EventHandler returns the informer handler that publishes task changes
+func (h *Hub) EventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if task, ok := obj.(*swarmv1alpha1.SwarmTask); ok {
+				h.Publish(TaskEvents(nil, task, h.now())...)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			old, ok := oldObj.(*swarmv1alpha1.SwarmTask)
+			if !ok {
+				return
+			}
+			if task, ok := newObj.(*swarmv1alpha1.SwarmTask); ok {
+				h.Publish(TaskEvents(old, task, h.now())...)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if task, ok := obj.(*swarmv1alpha1.SwarmTask); ok {
+				h.Publish(newTaskEvent(TaskEventDeleted, task, h.now()))
+			}
+		},
+	}
+}
+
+// handleTaskStream streams the task events of a cluster as server-sent
+// events. Clients may narrow the stream with a labelSelector parameter. The
+// stream starts with a snapshot event per existing task.
+func (s *Server) handleTaskStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := r.PathValue("name")
+	params := r.URL.Query()
+	namespace := params.Get("namespace")
+	if namespace == "" {
+		namespace = s.DefaultNamespace
+	}
+
+	status, err := s.authorize(ctx, r, namespace, name)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	filter := StreamFilter{Namespace: namespace, Cluster: name}
+	if raw := params.Get("labelSelector"); raw != "" {
+		selector, err := labels.Parse(raw)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid labelSelector: %v", err), http.StatusBadRequest)
+			return
+		}
+		filter.Selector = selector
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before listing so no change between the snapshot and the
+	// first event is lost
+	events, cancel := s.hub.Subscribe(filter)
+	defer cancel()
+
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := s.Client.List(ctx, tasks, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list tasks for stream", "cluster", name)
+		http.Error(w, "failed to list tasks", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	now := time.Now()
+	for i := range tasks.Items {
+		ev := newTaskEvent(TaskEventSnapshot, &tasks.Items[i], now)
+		if !filter.Matches(ev) {
+			continue
+		}
+		if err := writeEvent(w, ev); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes ev in the server-sent events wire format
+func writeEvent(w http.ResponseWriter, ev TaskEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+	return err
+}
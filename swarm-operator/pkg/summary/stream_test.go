@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Task stream", func() {
+	var now time.Time
+
+	newTask := func(name, cluster string, lbls map[string]string) *swarmv1alpha1.SwarmTask {
+		return &swarmv1alpha1.SwarmTask{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: lbls},
+			Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: cluster},
+			Status:     swarmv1alpha1.SwarmTaskStatus{Phase: "Running", Progress: 10},
+		}
+	}
+
+	eventTypes := func(events []TaskEvent) []string {
+		types := []string{}
+		for _, ev := range events {
+			types = append(types, ev.Type)
+		}
+		return types
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	It("should describe phase, progress, checkpoint and log changes", func() {
+		old := newTask("task-a", "test-swarm", nil)
+		task := old.DeepCopy()
+		Expect(TaskEvents(old, task, now)).To(BeEmpty())
+
+		task.Status.Phase = "Failed"
+		task.Status.Progress = 40
+		task.Status.CheckpointRef = "s3://checkpoints/task-a/1"
+		task.Status.FailureDetails = &swarmv1alpha1.FailureDetails{
+			Attempt:    2,
+			Reason:     "OOMKilled",
+			LogTail:    []string{"allocating", "killed"},
+			ObservedAt: metav1.NewTime(now),
+		}
+
+		events := TaskEvents(old, task, now)
+		Expect(eventTypes(events)).To(Equal([]string{
+			TaskEventPhase, TaskEventProgress, TaskEventCheckpoint, TaskEventLog,
+		}))
+		Expect(events[0].PreviousPhase).To(Equal("Running"))
+		Expect(events[0].Cluster).To(Equal("test-swarm"))
+		Expect(events[3].Message).To(Equal("attempt 2 failed: OOMKilled"))
+		Expect(events[3].Lines).To(Equal([]string{"allocating", "killed"}))
+
+		// The same failure is not reported twice
+		Expect(TaskEvents(task, task.DeepCopy(), now)).To(BeEmpty())
+	})
+
+	It("should report the result summary once", func() {
+		old := newTask("task-a", "test-swarm", nil)
+		task := old.DeepCopy()
+		task.Status.Result = &swarmv1alpha1.TaskResult{Success: true, Summary: "all tests pass"}
+
+		events := TaskEvents(old, task, now)
+		Expect(eventTypes(events)).To(Equal([]string{TaskEventLog}))
+		Expect(events[0].Message).To(Equal("all tests pass"))
+		Expect(TaskEvents(task, task.DeepCopy(), now)).To(BeEmpty())
+	})
+
+	It("should filter by namespace, cluster and labels", func() {
+		selector, err := labels.Parse("team=search")
+		Expect(err).NotTo(HaveOccurred())
+		filter := StreamFilter{Namespace: "default", Cluster: "test-swarm", Selector: selector}
+
+		match := newTaskEvent(TaskEventPhase, newTask("a", "test-swarm", map[string]string{"team": "search"}), now)
+		Expect(filter.Matches(match)).To(BeTrue())
+
+		otherCluster := match
+		otherCluster.Cluster = "other"
+		Expect(filter.Matches(otherCluster)).To(BeFalse())
+
+		otherNamespace := match
+		otherNamespace.Namespace = "prod"
+		Expect(filter.Matches(otherNamespace)).To(BeFalse())
+
+		otherLabels := newTaskEvent(TaskEventPhase, newTask("b", "test-swarm", map[string]string{"team": "infra"}), now)
+		Expect(filter.Matches(otherLabels)).To(BeFalse())
+
+		Expect(StreamFilter{}.Matches(otherLabels)).To(BeTrue())
+	})
+
+	It("should publish informer updates to matching subscribers", func() {
+		hub := NewHub()
+		hub.now = func() time.Time { return now }
+		events, cancel := hub.Subscribe(StreamFilter{Cluster: "test-swarm"})
+		others, cancelOthers := hub.Subscribe(StreamFilter{Cluster: "other"})
+		defer cancelOthers()
+
+		handler := hub.EventHandler()
+		old := newTask("task-a", "test-swarm", nil)
+		task := old.DeepCopy()
+		task.Status.Progress = 50
+		handler.OnUpdate(old, task)
+		handler.OnDelete(toolscache.DeletedFinalStateUnknown{Key: "default/task-a", Obj: task})
+
+		Expect(<-events).To(HaveField("Type", TaskEventProgress))
+		Expect(<-events).To(HaveField("Type", TaskEventDeleted))
+		Expect(others).To(BeEmpty())
+
+		cancel()
+		Expect(events).To(BeClosed())
+		Expect(hub.Subscribers()).To(Equal(1))
+	})
+
+	It("should disconnect subscribers that fall behind", func() {
+		hub := NewHub()
+		events, cancel := hub.Subscribe(StreamFilter{})
+		defer cancel()
+
+		ev := newTaskEvent(TaskEventProgress, newTask("task-a", "test-swarm", nil), now)
+		for i := 0; i <= streamBuffer; i++ {
+			hub.Publish(ev)
+		}
+
+		Expect(hub.Subscribers()).To(BeZero())
+		received := 0
+		for range events {
+			received++
+		}
+		Expect(received).To(Equal(streamBuffer))
+	})
+})
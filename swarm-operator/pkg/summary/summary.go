@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// Summary is the aggregated view of a SwarmCluster and everything it owns
+type Summary struct {
+	Name        string                           `json:"name"`
+	Namespace   string                           `json:"namespace"`
+	Topology    swarmv1alpha1.SwarmTopology      `json:"topology"`
+	Cluster     swarmv1alpha1.SwarmClusterStatus `json:"cluster"`
+	Agents      AgentsSummary                    `json:"agents"`
+	Tasks       TasksSummary                     `json:"tasks"`
+	HiveMind    TasksSummary                     `json:"hiveMind"`
+	Memory      []MemoryStoreSummary             `json:"memory"`
+	GeneratedAt metav1.Time                      `json:"generatedAt"`
+}
+
+// AgentsSummary aggregates the agents of a cluster
+type AgentsSummary struct {
+	Total   int            `json:"total"`
+	ByPhase map[string]int `json:"byPhase"`
+	ByType  map[string]int `json:"byType"`
+	Items   []AgentEntry   `json:"items"`
+}
+
+// AgentEntry is the per-agent row of the summary
+type AgentEntry struct {
+	Name          string                  `json:"name"`
+	Type          swarmv1alpha1.AgentType `json:"type"`
+	Phase         string                  `json:"phase"`
+	CurrentTasks  int                     `json:"currentTasks"`
+	LastHeartbeat *metav1.Time            `json:"lastHeartbeat,omitempty"`
+}
+
+// TasksSummary aggregates tasks by phase
+type TasksSummary struct {
+	Total   int            `json:"total"`
+	ByPhase map[string]int `json:"byPhase"`
+	Active  []string       `json:"active,omitempty"`
+}
+
+// MemoryStoreSummary carries the status of a memory store backing the cluster
+type MemoryStoreSummary struct {
+	Name      string                               `json:"name"`
+	Namespace string                               `json:"namespace"`
+	Status    swarmv1alpha1.SwarmMemoryStoreStatus `json:"status"`
+}
+
+// Build assembles the summary for the named SwarmCluster
+func Build(ctx context.Context, c client.Reader, namespace, name string) (*Summary, error) {
+	cluster := &swarmv1alpha1.SwarmCluster{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cluster); err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		Name:        cluster.Name,
+		Namespace:   cluster.Namespace,
+		Topology:    cluster.Spec.Topology,
+		Cluster:     cluster.Status,
+		Agents:      AgentsSummary{ByPhase: map[string]int{}, ByType: map[string]int{}},
+		Tasks:       TasksSummary{ByPhase: map[string]int{}},
+		HiveMind:    TasksSummary{ByPhase: map[string]int{}},
+		Memory:      []MemoryStoreSummary{},
+		GeneratedAt: metav1.Now(),
+	}
+
+	agents := &swarmv1alpha1.AgentList{}
+	if err := c.List(ctx, agents, client.InNamespace(namespace), client.MatchingLabels{"swarm-cluster": name}); err != nil {
+		return nil, err
+	}
+	for _, agent := range agents.Items {
+		summary.Agents.Total++
+		summary.Agents.ByPhase[agent.Status.Phase]++
+		summary.Agents.ByType[string(agent.Spec.Type)]++
+		summary.Agents.Items = append(summary.Agents.Items, AgentEntry{
+			Name:          agent.Name,
+			Type:          agent.Spec.Type,
+			Phase:         agent.Status.Phase,
+			CurrentTasks:  len(agent.Status.CurrentTasks),
+			LastHeartbeat: agent.Status.LastHeartbeat,
+		})
+	}
+	sort.Slice(summary.Agents.Items, func(i, j int) bool {
+		return summary.Agents.Items[i].Name < summary.Agents.Items[j].Name
+	})
+
+	tasks := &swarmv1alpha1.SwarmTaskList{}
+	if err := c.List(ctx, tasks, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, task := range tasks.Items {
+		if task.Spec.SwarmCluster != name {
+			continue
+		}
+		addTask(&summary.Tasks, &task)
+		// Hive-mind and consensus tasks run in the hive-mind namespace
+		if task.Spec.Type == "hivemind" || task.Spec.Type == "consensus" {
+			addTask(&summary.HiveMind, &task)
+		}
+	}
+
+	stores := &swarmv1alpha1.SwarmMemoryStoreList{}
+	if err := c.List(ctx, stores, client.InNamespace(namespace), client.MatchingLabels{"swarm-cluster": name}); err != nil {
+		return nil, err
+	}
+	for _, store := range stores.Items {
+		if store.Spec.SwarmClusterRef != name {
+			continue
+		}
+		summary.Memory = append(summary.Memory, MemoryStoreSummary{
+			Name:      store.Name,
+			Namespace: store.Namespace,
+			Status:    store.Status,
+		})
+	}
+
+	return summary, nil
+}
+
+func addTask(s *TasksSummary, task *swarmv1alpha1.SwarmTask) {
+	phase := task.Status.Phase
+	if phase == "" {
+		phase = "Pending"
+	}
+	s.Total++
+	s.ByPhase[phase]++
+	if phase == "Pending" || phase == "Scheduled" || phase == "Running" {
+		s.Active = append(s.Active, task.Name)
+	}
+}
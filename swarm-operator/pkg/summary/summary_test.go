@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package summary
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+func TestSummary(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Summary Suite")
+}
+
+var _ = Describe("Build", func() {
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should merge agents, tasks and memory stores of the cluster", func() {
+		objs := []runtime.Object{
+			&swarmv1alpha1.SwarmCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-swarm", Namespace: "default"},
+				Spec:       swarmv1alpha1.SwarmClusterSpec{Topology: swarmv1alpha1.MeshTopology},
+				Status:     swarmv1alpha1.SwarmClusterStatus{Phase: "Running", ActiveAgents: 2},
+			},
+			&swarmv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent-0", Namespace: "default",
+					Labels: map[string]string{"swarm-cluster": "test-swarm"}},
+				Spec:   swarmv1alpha1.AgentSpec{Type: swarmv1alpha1.CoordinatorAgent},
+				Status: swarmv1alpha1.AgentStatus{Phase: "Ready"},
+			},
+			&swarmv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent-1", Namespace: "default",
+					Labels: map[string]string{"swarm-cluster": "test-swarm"}},
+				Spec:   swarmv1alpha1.AgentSpec{Type: swarmv1alpha1.CoderAgent},
+				Status: swarmv1alpha1.AgentStatus{Phase: "Busy"},
+			},
+			&swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "task-a", Namespace: "default"},
+				Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "test-swarm", Type: "development"},
+				Status:     swarmv1alpha1.SwarmTaskStatus{Phase: "Running"},
+			},
+			&swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "task-b", Namespace: "default"},
+				Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "test-swarm", Type: "consensus"},
+				Status:     swarmv1alpha1.SwarmTaskStatus{Phase: "Completed"},
+			},
+			&swarmv1alpha1.SwarmTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+				Spec:       swarmv1alpha1.SwarmTaskSpec{SwarmCluster: "other-swarm"},
+			},
+			&swarmv1alpha1.SwarmMemoryStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-swarm-memory", Namespace: "default",
+					Labels: map[string]string{"swarm-cluster": "test-swarm"}},
+				Spec:   swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "test-swarm", Namespace: "claude-flow-swarm"},
+				Status: swarmv1alpha1.SwarmMemoryStoreStatus{Phase: "Ready", StorageReady: true},
+			},
+			&swarmv1alpha1.SwarmMemoryStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-swarm-memory", Namespace: "team-b",
+					Labels: map[string]string{"swarm-cluster": "test-swarm"}},
+				Spec: swarmv1alpha1.SwarmMemoryStoreSpec{SwarmClusterRef: "test-swarm"},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+
+		summary, err := Build(ctx, c, "default", "test-swarm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Topology).To(Equal(swarmv1alpha1.MeshTopology))
+		Expect(summary.Cluster.Phase).To(Equal("Running"))
+		Expect(summary.Agents.Total).To(Equal(2))
+		Expect(summary.Agents.ByPhase).To(HaveKeyWithValue("Busy", 1))
+		Expect(summary.Tasks.Total).To(Equal(2))
+		Expect(summary.Tasks.Active).To(ConsistOf("task-a"))
+		Expect(summary.HiveMind.Total).To(Equal(1))
+		Expect(summary.Memory).To(HaveLen(1))
+		Expect(summary.Memory[0].Namespace).To(Equal("default"))
+		Expect(summary.Memory[0].Status.StorageReady).To(BeTrue())
+	})
+
+	It("should return not found for unknown clusters", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		_, err := Build(ctx, c, "default", "missing")
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
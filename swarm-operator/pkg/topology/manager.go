@@ -26,6 +26,9 @@ import (
 // Manager handles topology configuration for swarm agents
 type Manager struct {
 	topology string
+
+	// strategy calculates peers for custom topologies
+	strategy Strategy
 }
 
 // NewManager creates a new topology manager
@@ -37,6 +40,10 @@ func NewManager(topology string) *Manager {
 
 // CalculatePeers determines peer connections based on topology
 func (m *Manager) CalculatePeers(agents []swarmv1alpha1.Agent) map[string][]string {
+	if strategy := m.customStrategy(); strategy != nil {
+		return strategy.CalculatePeers(agents)
+	}
+
 	switch m.topology {
 	case string(swarmv1alpha1.MeshTopology):
 		return m.calculateMeshPeers(agents)
@@ -89,7 +96,10 @@ func (m *Manager) calculateHierarchicalPeers(agents []swarmv1alpha1.Agent) map[s
 	sortedAgents := make([]swarmv1alpha1.Agent, len(agents))
 	copy(sortedAgents, agents)
 	sort.Slice(sortedAgents, func(i, j int) bool {
-		// Coordinators first, then by name
+		// The queen first, then coordinators, then by name
+		if queen(&sortedAgents[i]) != queen(&sortedAgents[j]) {
+			return queen(&sortedAgents[i])
+		}
 		if sortedAgents[i].Spec.Type == swarmv1alpha1.CoordinatorAgent && 
 		   sortedAgents[j].Spec.Type != swarmv1alpha1.CoordinatorAgent {
 			return true
@@ -180,22 +190,26 @@ func (m *Manager) calculateStarPeers(agents []swarmv1alpha1.Agent) map[string][]
 		return peerMap
 	}
 	
-	// Find coordinator or use first agent as hub
-	var hub *swarmv1alpha1.Agent
-	var spokes []swarmv1alpha1.Agent
-	
+	// The queen is the hub, else a coordinator, else the first agent
+	hubIndex := -1
 	for i := range agents {
+		if queen(&agents[i]) {
+			hubIndex = i
+			break
+		}
 		if agents[i].Spec.Type == swarmv1alpha1.CoordinatorAgent {
-			hub = &agents[i]
-		} else {
-			spokes = append(spokes, agents[i])
+			hubIndex = i
 		}
 	}
-	
-	// If no coordinator, use first agent as hub
-	if hub == nil {
-		hub = &agents[0]
-		spokes = agents[1:]
+	if hubIndex < 0 {
+		hubIndex = 0
+	}
+	hub := &agents[hubIndex]
+	var spokes []swarmv1alpha1.Agent
+	for i := range agents {
+		if i != hubIndex {
+			spokes = append(spokes, agents[i])
+		}
 	}
 	
 	// Hub connects to all spokes
@@ -210,8 +224,31 @@ func (m *Manager) calculateStarPeers(agents []swarmv1alpha1.Agent) map[string][]
 	return peerMap
 }
 
+// queen reports whether the agent was elected queen of a centralized swarm
+func queen(agent *swarmv1alpha1.Agent) bool {
+	return agent.Labels[swarmv1alpha1.QueenLabel] == "true"
+}
+
+// customStrategy returns the strategy of a custom topology, or a strategy
+// registered under the topology name
+func (m *Manager) customStrategy() Strategy {
+	if m.strategy != nil {
+		return m.strategy
+	}
+	if builtinTopologies[m.topology] {
+		return nil
+	}
+	strategy, _ := Lookup(m.topology)
+	return strategy
+}
+
 // formatPeerAddress creates the peer connection string
 func (m *Manager) formatPeerAddress(agent swarmv1alpha1.Agent) string {
+	return PeerAddress(agent)
+}
+
+// PeerAddress returns the address peers use to reach the agent
+func PeerAddress(agent swarmv1alpha1.Agent) string {
 	// Format: agent-name.namespace.svc.cluster.local:port
 	return fmt.Sprintf("%s.%s.svc.cluster.local:%d", 
 		agent.Name, 
@@ -221,6 +258,10 @@ func (m *Manager) formatPeerAddress(agent swarmv1alpha1.Agent) string {
 
 // ValidateTopology checks if agents can form the requested topology
 func (m *Manager) ValidateTopology(agentCount int) error {
+	if strategy := m.customStrategy(); strategy != nil {
+		return strategy.Validate(agentCount)
+	}
+
 	switch m.topology {
 	case string(swarmv1alpha1.MeshTopology):
 		// Mesh works with any number of agents
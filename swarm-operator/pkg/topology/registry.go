@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// Strategy calculates peer connections for a custom topology. Operators built
+// on this package register strategies from an init function:
+//
+//	func init() {
+//		topology.Register("rack-aware", rackAwareStrategy{})
+//	}
+//
+// and clusters select them with topology "custom" and customTopology.strategy.
+type Strategy interface {
+	// CalculatePeers maps each agent name to the addresses of its peers.
+	// PeerAddress formats an agent address.
+	CalculatePeers(agents []swarmv1alpha1.Agent) map[string][]string
+
+	// Validate checks whether the agents can form the topology
+	Validate(agentCount int) error
+}
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = map[string]Strategy{}
+)
+
+// builtinTopologies cannot be replaced by registered strategies
+var builtinTopologies = map[string]bool{
+	string(swarmv1alpha1.MeshTopology):         true,
+	string(swarmv1alpha1.HierarchicalTopology): true,
+	string(swarmv1alpha1.RingTopology):         true,
+	string(swarmv1alpha1.StarTopology):         true,
+	string(swarmv1alpha1.CustomTopology):       true,
+}
+
+// Register makes a strategy available under name. It panics if the name is
+// taken or the strategy is nil, like database/sql.Register.
+func Register(name string, strategy Strategy) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	if strategy == nil {
+		panic("topology: Register strategy is nil")
+	}
+	if builtinTopologies[name] {
+		panic(fmt.Sprintf("topology: %q is a built-in topology", name))
+	}
+	if _, dup := strategies[name]; dup {
+		panic(fmt.Sprintf("topology: Register called twice for strategy %q", name))
+	}
+	strategies[name] = strategy
+}
+
+// Lookup returns the strategy registered under name
+func Lookup(name string) (Strategy, bool) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	strategy, ok := strategies[name]
+	return strategy, ok
+}
+
+// Strategies returns the sorted names of the registered strategies
+func Strategies() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForCluster returns the topology manager for the cluster's spec
+func ForCluster(swarmCluster *swarmv1alpha1.SwarmCluster) (*Manager, error) {
+	if swarmCluster.Spec.Topology != swarmv1alpha1.CustomTopology {
+		return NewManager(string(swarmCluster.Spec.Topology)), nil
+	}
+	return NewCustomManager(swarmCluster.Spec.CustomTopology)
+}
+
+// NewCustomManager creates a manager for the custom topology, resolving a
+// registered strategy or compiling the adjacency rules
+func NewCustomManager(spec *swarmv1alpha1.CustomTopologySpec) (*Manager, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("custom topology requires spec.customTopology")
+	}
+
+	var strategy Strategy
+	switch {
+	case spec.Strategy != "":
+		registered, ok := Lookup(spec.Strategy)
+		if !ok {
+			return nil, fmt.Errorf("topology strategy %q is not registered (available: %v)", spec.Strategy, Strategies())
+		}
+		strategy = registered
+	case len(spec.Rules) > 0:
+		rules, err := ParseRules(spec.Rules)
+		if err != nil {
+			return nil, err
+		}
+		strategy = rules
+	default:
+		return nil, fmt.Errorf("custom topology requires a strategy or rules")
+	}
+
+	if spec.MaxPeers > 0 {
+		strategy = maxPeers{Strategy: strategy, limit: int(spec.MaxPeers)}
+	}
+	return &Manager{topology: string(swarmv1alpha1.CustomTopology), strategy: strategy}, nil
+}
+
+// maxPeers truncates the peer lists of the wrapped strategy
+type maxPeers struct {
+	Strategy
+	limit int
+}
+
+func (m maxPeers) CalculatePeers(agents []swarmv1alpha1.Agent) map[string][]string {
+	peerMap := m.Strategy.CalculatePeers(agents)
+	for name, peers := range peerMap {
+		if len(peers) > m.limit {
+			peerMap[name] = peers[:m.limit]
+		}
+	}
+	return peerMap
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// term is a single condition of a rule selector
+type term struct {
+	kind  string // "any", "type", "label", "same" or "notSame"
+	key   string
+	value string
+}
+
+// matches reports whether candidate satisfies the term. from is the agent on
+// the left side of the rule and is only used by same/!same terms.
+func (t term) matches(candidate, from *swarmv1alpha1.Agent) bool {
+	switch t.kind {
+	case "type":
+		return string(candidate.Spec.Type) == t.value
+	case "label":
+		return candidate.Labels[t.key] == t.value
+	case "same":
+		value, ok := from.Labels[t.key]
+		return ok && candidate.Labels[t.key] == value
+	case "notSame":
+		return candidate.Labels[t.key] != from.Labels[t.key]
+	default:
+		return true
+	}
+}
+
+// selector is a conjunction of terms
+type selector []term
+
+func (s selector) matches(candidate, from *swarmv1alpha1.Agent) bool {
+	for _, t := range s {
+		if !t.matches(candidate, from) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rule connects agents matching From to agents matching To
+type Rule struct {
+	from          selector
+	to            selector
+	bidirectional bool
+}
+
+// Rules is a Strategy built from adjacency rules
+type Rules []Rule
+
+// ParseRules compiles adjacency rules of the form "<selector> -> <selector>"
+// or "<selector> <-> <selector>"
+func ParseRules(rules []string) (Rules, error) {
+	compiled := make(Rules, 0, len(rules))
+	for i, raw := range rules {
+		rule, err := parseRule(raw)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d %q: %w", i, raw, err)
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+func parseRule(raw string) (Rule, error) {
+	var rule Rule
+	arrow := "->"
+	if strings.Contains(raw, "<->") {
+		arrow = "<->"
+		rule.bidirectional = true
+	}
+
+	parts := strings.Split(raw, arrow)
+	if len(parts) != 2 {
+		return rule, fmt.Errorf("expected exactly one -> or <->")
+	}
+
+	var err error
+	if rule.from, err = parseSelector(parts[0], false); err != nil {
+		return rule, err
+	}
+	// Relative terms only make sense for one-way rules, where the left
+	// agent is well defined
+	if rule.to, err = parseSelector(parts[1], !rule.bidirectional); err != nil {
+		return rule, err
+	}
+	return rule, nil
+}
+
+func parseSelector(raw string, allowRelative bool) (selector, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("empty selector")
+	}
+
+	var sel selector
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "*":
+			sel = append(sel, term{kind: "any"})
+		case strings.HasPrefix(part, "type="):
+			sel = append(sel, term{kind: "type", value: strings.TrimPrefix(part, "type=")})
+		case strings.HasPrefix(part, "label:"):
+			key, value, ok := strings.Cut(strings.TrimPrefix(part, "label:"), "=")
+			if !ok || key == "" {
+				return nil, fmt.Errorf("expected label:<key>=<value>, got %q", part)
+			}
+			sel = append(sel, term{kind: "label", key: key, value: value})
+		case strings.HasPrefix(part, "same:"), strings.HasPrefix(part, "!same:"):
+			if !allowRelative {
+				return nil, fmt.Errorf("%q is only allowed on the right side of a -> rule", part)
+			}
+			kind, key := "same", strings.TrimPrefix(part, "same:")
+			if strings.HasPrefix(part, "!") {
+				kind, key = "notSame", strings.TrimPrefix(part, "!same:")
+			}
+			if key == "" {
+				return nil, fmt.Errorf("missing label key in %q", part)
+			}
+			sel = append(sel, term{kind: kind, key: key})
+		default:
+			return nil, fmt.Errorf("unknown selector term %q", part)
+		}
+	}
+	return sel, nil
+}
+
+// CalculatePeers implements Strategy
+func (rules Rules) CalculatePeers(agents []swarmv1alpha1.Agent) map[string][]string {
+	sortedAgents := make([]swarmv1alpha1.Agent, len(agents))
+	copy(sortedAgents, agents)
+	sort.Slice(sortedAgents, func(i, j int) bool {
+		return sortedAgents[i].Name < sortedAgents[j].Name
+	})
+
+	// Collect edges as agent indexes so peer lists come out in name order
+	edges := make([]map[int]bool, len(sortedAgents))
+	for i := range edges {
+		edges[i] = map[int]bool{}
+	}
+	for _, rule := range rules {
+		for i := range sortedAgents {
+			from := &sortedAgents[i]
+			if !rule.from.matches(from, from) {
+				continue
+			}
+			for j := range sortedAgents {
+				if i == j || !rule.to.matches(&sortedAgents[j], from) {
+					continue
+				}
+				edges[i][j] = true
+				if rule.bidirectional {
+					edges[j][i] = true
+				}
+			}
+		}
+	}
+
+	peerMap := make(map[string][]string, len(sortedAgents))
+	for i, agent := range sortedAgents {
+		peers := []string{}
+		for j := range sortedAgents {
+			if edges[i][j] {
+				peers = append(peers, PeerAddress(sortedAgents[j]))
+			}
+		}
+		peerMap[agent.Name] = peers
+	}
+	return peerMap
+}
+
+// Validate implements Strategy. Rules work with any number of agents.
+func (rules Rules) Validate(agentCount int) error {
+	return nil
+}
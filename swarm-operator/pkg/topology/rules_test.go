@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+type fixedStrategy struct{}
+
+func (fixedStrategy) CalculatePeers(agents []swarmv1alpha1.Agent) map[string][]string {
+	peers := map[string][]string{}
+	for _, a := range agents {
+		peers[a.Name] = []string{"fixed"}
+	}
+	return peers
+}
+
+func (fixedStrategy) Validate(agentCount int) error {
+	if agentCount > 2 {
+		return fmt.Errorf("too many agents")
+	}
+	return nil
+}
+
+var _ = Describe("Custom topologies", func() {
+	agent := func(name string, agentType swarmv1alpha1.AgentType, zone string) swarmv1alpha1.Agent {
+		return swarmv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "swarm",
+				Labels:    map[string]string{"zone": zone},
+			},
+			Spec: swarmv1alpha1.AgentSpec{
+				Type:                   agentType,
+				CommunicationEndpoints: swarmv1alpha1.CommunicationSpec{Port: 8080},
+			},
+		}
+	}
+
+	agents := []swarmv1alpha1.Agent{
+		agent("coord", swarmv1alpha1.CoordinatorAgent, "a"),
+		agent("coder-a", swarmv1alpha1.CoderAgent, "a"),
+		agent("coder-b", swarmv1alpha1.CoderAgent, "b"),
+		agent("tester-a", swarmv1alpha1.TesterAgent, "a"),
+	}
+
+	Context("Adjacency rules", func() {
+		It("should connect agents in the same zone", func() {
+			rules, err := ParseRules([]string{"* -> same:zone"})
+			Expect(err).NotTo(HaveOccurred())
+
+			peers := rules.CalculatePeers(agents)
+			Expect(peers["coder-a"]).To(Equal([]string{
+				PeerAddress(agents[0]),
+				PeerAddress(agents[3]),
+			}))
+			Expect(peers["coder-b"]).To(BeEmpty())
+		})
+
+		It("should connect both ways with <->", func() {
+			rules, err := ParseRules([]string{"type=coordinator <-> type=coder"})
+			Expect(err).NotTo(HaveOccurred())
+
+			peers := rules.CalculatePeers(agents)
+			Expect(peers["coord"]).To(HaveLen(2))
+			Expect(peers["coder-b"]).To(Equal([]string{PeerAddress(agents[0])}))
+			Expect(peers["tester-a"]).To(BeEmpty())
+		})
+
+		It("should reject malformed rules", func() {
+			for _, rule := range []string{"*", "* -> ", "same:zone -> *", "* <-> same:zone", "foo -> *", "label:zone -> *"} {
+				_, err := ParseRules([]string{rule})
+				Expect(err).To(HaveOccurred(), rule)
+			}
+		})
+	})
+
+	Context("Custom managers", func() {
+		It("should cap peers with maxPeers", func() {
+			manager, err := NewCustomManager(&swarmv1alpha1.CustomTopologySpec{
+				Rules:    []string{"* -> *"},
+				MaxPeers: 1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			for _, peers := range manager.CalculatePeers(agents) {
+				Expect(peers).To(HaveLen(1))
+			}
+		})
+
+		It("should use a registered strategy", func() {
+			Register("fixed-test", fixedStrategy{})
+			Expect(Strategies()).To(ContainElement("fixed-test"))
+
+			manager, err := NewCustomManager(&swarmv1alpha1.CustomTopologySpec{Strategy: "fixed-test"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manager.CalculatePeers(agents)["coord"]).To(Equal([]string{"fixed"}))
+			Expect(manager.ValidateTopology(3)).To(HaveOccurred())
+		})
+
+		It("should refuse to replace built-in topologies", func() {
+			Expect(func() { Register("mesh", fixedStrategy{}) }).To(Panic())
+		})
+
+		It("should fail for unknown strategies", func() {
+			_, err := NewCustomManager(&swarmv1alpha1.CustomTopologySpec{Strategy: "missing"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
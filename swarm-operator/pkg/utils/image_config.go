@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+// dockerHub is the registry of images that name none
+const dockerHub = "docker.io"
+
+// ApplyImageConfig adds the pull secrets of the config to the pod spec and
+// sets the pull policy and mirrored image of every container
+func ApplyImageConfig(spec *corev1.PodSpec, config *swarmv1alpha1.ImageConfig) {
+	if config == nil {
+		return
+	}
+
+	for _, secret := range config.PullSecrets {
+		found := false
+		for _, existing := range spec.ImagePullSecrets {
+			if existing.Name == secret.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			spec.ImagePullSecrets = append(spec.ImagePullSecrets, secret)
+		}
+	}
+
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			containers[i].Image = RewriteImage(containers[i].Image, config.Mirrors)
+			if config.PullPolicy != "" {
+				containers[i].ImagePullPolicy = config.PullPolicy
+			}
+		}
+	}
+}
+
+// RewriteImage pulls the image from the first mirror of its registry.
+// Images without a registry are from docker.io, and official images
+// without a repository from its library, so busybox:latest mirrored from
+// docker.io to mirror.example.com/hub becomes
+// mirror.example.com/hub/library/busybox:latest.
+func RewriteImage(image string, mirrors []swarmv1alpha1.RegistryMirror) string {
+	if image == "" || len(mirrors) == 0 {
+		return image
+	}
+	registry, path := splitImageRegistry(image)
+	for _, mirror := range mirrors {
+		if normalizeRegistry(mirror.Registry) == registry {
+			return strings.TrimSuffix(mirror.Mirror, "/") + "/" + path
+		}
+	}
+	return image
+}
+
+// splitImageRegistry splits an image reference into its registry and the
+// path within it. The first component is a registry only when it looks
+// like a host, as in the Docker reference grammar.
+func splitImageRegistry(image string) (string, string) {
+	first, rest, found := strings.Cut(image, "/")
+	if !found {
+		return dockerHub, "library/" + image
+	}
+	if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return dockerHub, image
+	}
+	return normalizeRegistry(first), rest
+}
+
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if registry == "index.docker.io" || registry == "registry-1.docker.io" {
+		return dockerHub
+	}
+	return registry
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Image config", func() {
+	mirrors := []swarmv1alpha1.RegistryMirror{
+		{Registry: "docker.io", Mirror: "mirror.example.com/hub/"},
+		{Registry: "ghcr.io", Mirror: "mirror.example.com/ghcr"},
+	}
+
+	It("should rewrite images of mirrored registries", func() {
+		Expect(RewriteImage("busybox:latest", mirrors)).To(Equal("mirror.example.com/hub/library/busybox:latest"))
+		Expect(RewriteImage("claudeflow/swarm-executor:2.0.0", mirrors)).To(Equal("mirror.example.com/hub/claudeflow/swarm-executor:2.0.0"))
+		Expect(RewriteImage("index.docker.io/nats:2.10", mirrors)).To(Equal("mirror.example.com/hub/nats:2.10"))
+		Expect(RewriteImage("ghcr.io/acme/agent@sha256:abc", mirrors)).To(Equal("mirror.example.com/ghcr/acme/agent@sha256:abc"))
+	})
+
+	It("should keep images of other registries", func() {
+		Expect(RewriteImage("quay.io/acme/agent:v1", mirrors)).To(Equal("quay.io/acme/agent:v1"))
+		Expect(RewriteImage("localhost:5000/agent", mirrors)).To(Equal("localhost:5000/agent"))
+		Expect(RewriteImage("busybox", nil)).To(Equal("busybox"))
+	})
+
+	It("should apply pull secrets and policy to every container", func() {
+		spec := &corev1.PodSpec{
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}},
+			InitContainers:   []corev1.Container{{Name: "checkout", Image: "alpine/git"}},
+			Containers:       []corev1.Container{{Name: "task", Image: "busybox:latest"}},
+		}
+		ApplyImageConfig(spec, &swarmv1alpha1.ImageConfig{
+			PullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}, {Name: "mirror-creds"}},
+			PullPolicy:  corev1.PullAlways,
+			Mirrors:     mirrors,
+		})
+
+		Expect(spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "regcred"}, {Name: "mirror-creds"}}))
+		Expect(spec.InitContainers[0].Image).To(Equal("mirror.example.com/hub/alpine/git"))
+		Expect(spec.InitContainers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+		Expect(spec.Containers[0].Image).To(Equal("mirror.example.com/hub/library/busybox:latest"))
+		Expect(spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+	})
+})
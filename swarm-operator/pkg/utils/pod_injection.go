@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ValidatePodInjection checks a pod spec assembled from operator defaults and
+// user-supplied sidecars/init containers for conflicts that the API server
+// would either reject or silently misbehave on: duplicate container names,
+// containers binding the same port, duplicate port or volume names, and
+// mounts of volumes that are not defined.
+func ValidatePodInjection(spec *corev1.PodSpec) error {
+	var problems []string
+
+	containerNames := make(map[string]bool)
+	for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+		if containerNames[c.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate container name %q", c.Name))
+		}
+		containerNames[c.Name] = true
+	}
+
+	volumeNames := make(map[string]bool)
+	for _, v := range spec.Volumes {
+		if volumeNames[v.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate volume name %q", v.Name))
+		}
+		volumeNames[v.Name] = true
+	}
+
+	// Containers in a pod share a network namespace, so ports must be unique
+	// across every long-running container. Native sidecars (init containers
+	// with restartPolicy Always) keep running and count as well.
+	running := append([]corev1.Container{}, spec.Containers...)
+	for _, c := range spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			running = append(running, c)
+		}
+	}
+
+	portOwners := make(map[string]string)
+	portNameOwners := make(map[string]string)
+	for _, c := range running {
+		for _, p := range c.Ports {
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			key := fmt.Sprintf("%d/%s", p.ContainerPort, protocol)
+			if owner, ok := portOwners[key]; ok {
+				problems = append(problems, fmt.Sprintf("port %s of container %q conflicts with container %q", key, c.Name, owner))
+			} else {
+				portOwners[key] = c.Name
+			}
+
+			if p.Name == "" {
+				continue
+			}
+			if owner, ok := portNameOwners[p.Name]; ok {
+				problems = append(problems, fmt.Sprintf("port name %q of container %q conflicts with container %q", p.Name, c.Name, owner))
+			} else {
+				portNameOwners[p.Name] = c.Name
+			}
+		}
+	}
+
+	for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+		for _, m := range c.VolumeMounts {
+			if !volumeNames[m.Name] {
+				problems = append(problems, fmt.Sprintf("container %q mounts undefined volume %q", c.Name, m.Name))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("pod spec conflicts: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
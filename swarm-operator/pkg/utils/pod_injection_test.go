@@ -0,0 +1,85 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("ValidatePodInjection", func() {
+	var spec *corev1.PodSpec
+
+	BeforeEach(func() {
+		spec = &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "agent",
+					Ports: []corev1.ContainerPort{{Name: "comm", ContainerPort: 8080}},
+				},
+			},
+		}
+	})
+
+	It("should accept sidecars with distinct ports and defined volumes", func() {
+		spec.Volumes = []corev1.Volume{{Name: "logs"}}
+		spec.Containers = append(spec.Containers, corev1.Container{
+			Name:         "log-shipper",
+			Ports:        []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9100}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "logs", MountPath: "/var/log/agent"}},
+		})
+		Expect(ValidatePodInjection(spec)).To(Succeed())
+	})
+
+	It("should reject a sidecar binding the agent port", func() {
+		spec.Containers = append(spec.Containers, corev1.Container{
+			Name:  "proxy",
+			Ports: []corev1.ContainerPort{{Name: "proxy", ContainerPort: 8080}},
+		})
+		err := ValidatePodInjection(spec)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("8080/TCP"))
+	})
+
+	It("should reject duplicate port names, container names and volumes", func() {
+		spec.Volumes = []corev1.Volume{{Name: "data"}, {Name: "data"}}
+		spec.InitContainers = []corev1.Container{{Name: "agent"}}
+		spec.Containers = append(spec.Containers, corev1.Container{
+			Name:  "proxy",
+			Ports: []corev1.ContainerPort{{Name: "comm", ContainerPort: 15001}},
+		})
+		err := ValidatePodInjection(spec)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`duplicate container name "agent"`))
+		Expect(err.Error()).To(ContainSubstring(`duplicate volume name "data"`))
+		Expect(err.Error()).To(ContainSubstring(`port name "comm"`))
+	})
+
+	It("should reject mounts of undefined volumes", func() {
+		spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "missing", MountPath: "/data"}}
+		Expect(ValidatePodInjection(spec)).NotTo(Succeed())
+	})
+
+	It("should ignore ports of regular init containers", func() {
+		spec.InitContainers = []corev1.Container{{
+			Name:  "setup",
+			Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+		}}
+		Expect(ValidatePodInjection(spec)).To(Succeed())
+	})
+})
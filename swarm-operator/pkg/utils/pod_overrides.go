@@ -0,0 +1,53 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// ApplyPodTemplateOverrides applies a user-supplied strategic merge patch to
+// an operator-built pod template. Lists such as containers, volumes and
+// tolerations are merged by their patch merge keys, so overriding the
+// resources of the "task" container leaves the rest of it intact.
+func ApplyPodTemplateOverrides(template *corev1.PodTemplateSpec, overrides *runtime.RawExtension) error {
+	if overrides == nil || len(overrides.Raw) == 0 {
+		return nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to encode pod template: %w", err)
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(original, overrides.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("failed to apply pod template overrides: %w", err)
+	}
+
+	result := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return fmt.Errorf("failed to decode patched pod template: %w", err)
+	}
+	*template = result
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Claude Flow Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Pod template overrides", func() {
+	var template *corev1.PodTemplateSpec
+
+	BeforeEach(func() {
+		template = &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"swarm.claudeflow.io/task": "task-a"},
+			},
+			Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyOnFailure,
+				Containers: []corev1.Container{
+					{Name: "task", Image: "busybox:latest"},
+				},
+			},
+		}
+	})
+
+	It("should merge overrides into the operator-built template", func() {
+		overrides := &runtime.RawExtension{Raw: []byte(`{
+			"metadata": {"annotations": {"team": "infra"}},
+			"spec": {
+				"priorityClassName": "batch-low",
+				"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["git.internal"]}],
+				"containers": [{"name": "task", "imagePullPolicy": "Always"}]
+			}
+		}`)}
+
+		Expect(ApplyPodTemplateOverrides(template, overrides)).To(Succeed())
+		Expect(template.Labels).To(HaveKeyWithValue("swarm.claudeflow.io/task", "task-a"))
+		Expect(template.Annotations).To(HaveKeyWithValue("team", "infra"))
+		Expect(template.Spec.PriorityClassName).To(Equal("batch-low"))
+		Expect(template.Spec.HostAliases).To(HaveLen(1))
+		Expect(template.Spec.Containers).To(HaveLen(1))
+		Expect(template.Spec.Containers[0].Image).To(Equal("busybox:latest"))
+		Expect(template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+	})
+
+	It("should leave the template untouched without overrides", func() {
+		Expect(ApplyPodTemplateOverrides(template, nil)).To(Succeed())
+		Expect(template.Spec.Containers[0].Name).To(Equal("task"))
+	})
+
+	It("should reject disallowed fields", func() {
+		overrides := &runtime.RawExtension{Raw: []byte(`{
+			"metadata": {"name": "other", "labels": {"swarm.claudeflow.io/task": "x"}},
+			"spec": {
+				"hostNetwork": true,
+				"containers": [{"name": "task", "securityContext": {"privileged": true}}]
+			}
+		}`)}
+
+		errs := swarmv1alpha1.ValidatePodTemplateOverrides(overrides, field.NewPath("spec", "podTemplateOverrides"))
+		Expect(errs).To(HaveLen(4))
+	})
+
+	It("should reject overrides that escape the pod sandbox", func() {
+		overrides := &runtime.RawExtension{Raw: []byte(`{
+			"spec": {
+				"securityContext": {"runAsUser": 0},
+				"volumes": [{"name": "host", "hostPath": {"path": "/"}}],
+				"ephemeralContainers": [{"name": "debug", "image": "busybox"}],
+				"containers": [{"name": "task", "securityContext": {
+					"allowPrivilegeEscalation": true,
+					"capabilities": {"add": ["SYS_ADMIN"]},
+					"procMount": "Unmasked",
+					"runAsNonRoot": false
+				}}]
+			}
+		}`)}
+
+		errs := swarmv1alpha1.ValidatePodTemplateOverrides(overrides, field.NewPath("spec", "podTemplateOverrides"))
+		Expect(errs).To(HaveLen(7))
+		Expect(errs.ToAggregate().Error()).To(ContainSubstring("spec.podTemplateOverrides.spec.volumes[0].hostPath"))
+	})
+})
@@ -21,12 +21,15 @@ import (
 	"sort"
 
 	swarmv1alpha1 "github.com/claude-flow/swarm-operator/api/v1alpha1"
+	"github.com/claude-flow/swarm-operator/pkg/policy"
 )
 
 // TaskDistributor handles task assignment to agents
 type TaskDistributor struct {
 	algorithm        string
 	maxTasksPerAgent int32
+	policies         *policy.Engine
+	observe          func(policy.Result)
 }
 
 // NewTaskDistributor creates a new task distributor
@@ -37,18 +40,32 @@ func NewTaskDistributor(spec swarmv1alpha1.TaskDistributionSpec) *TaskDistributo
 	}
 }
 
+// WithPolicies makes the distributor filter and score agents with the
+// cluster's scheduling policies before applying its algorithm. observe, if
+// not nil, is called with every policy evaluation.
+func (td *TaskDistributor) WithPolicies(engine *policy.Engine, observe func(policy.Result)) *TaskDistributor {
+	td.policies = engine
+	td.observe = observe
+	return td
+}
+
 // Task represents a task to be distributed
 type Task struct {
 	Name         string
+	Namespace    string
 	Type         string
 	Priority     int
 	Capabilities []string
+	Labels       map[string]string
 }
 
 // AssignTask assigns a task to the most suitable agent
 func (td *TaskDistributor) AssignTask(task Task, agents []swarmv1alpha1.Agent) (*swarmv1alpha1.Agent, error) {
 	// Filter out agents that are at capacity or not ready
 	availableAgents := td.filterAvailableAgents(agents)
+	if !td.policies.Empty() {
+		availableAgents = td.applyPolicies(task, availableAgents)
+	}
 	
 	if len(availableAgents) == 0 {
 		return nil, fmt.Errorf("no available agents")
@@ -75,7 +92,10 @@ func (td *TaskDistributor) filterAvailableAgents(agents []swarmv1alpha1.Agent) [
 	
 	for i := range agents {
 		agent := &agents[i]
-		// Check if agent is ready and not at capacity
+		// Check if agent is ready, not draining and not at capacity
+		if agent.Spec.Unschedulable {
+			continue
+		}
 		if agent.Status.Phase == "Ready" || agent.Status.Phase == "Busy" {
 			if int32(len(agent.Status.CurrentTasks)) < td.maxTasksPerAgent {
 				available = append(available, agent)
@@ -86,6 +106,92 @@ func (td *TaskDistributor) filterAvailableAgents(agents []swarmv1alpha1.Agent) [
 	return available
 }
 
+// applyPolicies drops the agents a Require policy rejects and keeps the
+// agents with the highest policy score, leaving the choice among equally
+// scored agents to the algorithm
+func (td *TaskDistributor) applyPolicies(task Task, agents []*swarmv1alpha1.Agent) []*swarmv1alpha1.Agent {
+	taskVars := taskVariables(task)
+
+	var best []*swarmv1alpha1.Agent
+	var bestScore float64
+	for _, agent := range agents {
+		decision := td.policies.Evaluate(taskVars, agentVariables(agent))
+		if td.observe != nil {
+			for _, result := range decision.Results {
+				td.observe(result)
+			}
+		}
+		if !decision.Allowed {
+			continue
+		}
+		switch {
+		case len(best) == 0 || decision.Score > bestScore:
+			best = []*swarmv1alpha1.Agent{agent}
+			bestScore = decision.Score
+		case decision.Score == bestScore:
+			best = append(best, agent)
+		}
+	}
+	return best
+}
+
+// taskVariables is the task variable seen by scheduling policies
+func taskVariables(task Task) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         task.Name,
+		"namespace":    task.Namespace,
+		"type":         task.Type,
+		"priority":     int64(task.Priority),
+		"capabilities": nonNilStrings(task.Capabilities),
+		"labels":       nonNilLabels(task.Labels),
+	}
+}
+
+// agentVariables is the agent variable seen by scheduling policies
+func agentVariables(agent *swarmv1alpha1.Agent) map[string]interface{} {
+	currentTaskTypes := make([]string, 0, len(agent.Status.CurrentTasks))
+	for _, ref := range agent.Status.CurrentTasks {
+		currentTaskTypes = append(currentTaskTypes, ref.Type)
+	}
+
+	successRate := agent.Status.Metrics.SuccessRate
+	if finished := agent.Status.CompletedTasks + agent.Status.FailedTasks; successRate == 0 && finished > 0 {
+		successRate = 100 * float64(agent.Status.CompletedTasks) / float64(finished)
+	}
+
+	return map[string]interface{}{
+		"name":             agent.Name,
+		"type":             string(agent.Spec.Type),
+		"phase":            agent.Status.Phase,
+		"capabilities":     nonNilStrings(agent.Spec.Capabilities),
+		"labels":           nonNilLabels(agent.Labels),
+		"pool":             agent.Status.Pool,
+		"currentTasks":     int64(len(agent.Status.CurrentTasks)),
+		"currentTaskTypes": currentTaskTypes,
+		"completedTasks":   agent.Status.CompletedTasks,
+		"failedTasks":      agent.Status.FailedTasks,
+		"successRate":      successRate,
+		"cpuUsage":         agent.Status.Metrics.CPUUsage,
+		"memoryUsage":      agent.Status.Metrics.MemoryUsage,
+	}
+}
+
+// nonNilStrings keeps policies from failing on size() or "in" over an unset
+// list
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func nonNilLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return map[string]string{}
+	}
+	return labels
+}
+
 // roundRobinAssignment selects agents in round-robin fashion
 func (td *TaskDistributor) roundRobinAssignment(agents []*swarmv1alpha1.Agent) (*swarmv1alpha1.Agent, error) {
 	if len(agents) == 0 {
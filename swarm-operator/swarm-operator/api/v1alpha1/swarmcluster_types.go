@@ -166,9 +166,31 @@ type MemorySpec struct {
 	// Replication factor for durability
 	Replication int32 `json:"replication,omitempty"`
 
+	// ReplicationMode of redis when Replication is above 1. Sentinel runs a
+	// primary with replicas and fails over; cluster shards keys over
+	// Redis Cluster and needs at least 3 nodes.
+	// +kubebuilder:validation:Enum=sentinel;cluster
+	// +kubebuilder:default=sentinel
+	ReplicationMode string `json:"replicationMode,omitempty"`
+
 	// Persistence enables durable storage
 	Persistence bool `json:"persistence,omitempty"`
 
+	// PersistenceMode of redis: append-only file, RDB snapshots or both
+	// +kubebuilder:validation:Enum=aof;rdb;both
+	// +kubebuilder:default=aof
+	PersistenceMode string `json:"persistenceMode,omitempty"`
+
+	// StorageClass of the persistent volumes, the cluster default if empty
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// StorageSize of each persistent volume
+	// +kubebuilder:default="8Gi"
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// Auth configures password authentication of the memory backend
+	Auth *MemoryAuthSpec `json:"auth,omitempty"`
+
 	// CachePolicy (LRU, LFU, ARC)
 	CachePolicy string `json:"cachePolicy,omitempty"`
 
@@ -182,6 +204,16 @@ type MemorySpec struct {
 	EnableMemoryStore bool `json:"enableMemoryStore,omitempty"`
 }
 
+// MemoryAuthSpec defines password authentication of the memory backend
+type MemoryAuthSpec struct {
+	// Disabled turns authentication off
+	Disabled bool `json:"disabled,omitempty"`
+
+	// ExistingSecret holds the password. A Secret with a generated
+	// password is created when unset.
+	ExistingSecret *SecretKeyRef `json:"existingSecret,omitempty"`
+}
+
 // SQLiteMemoryConfig defines SQLite-specific memory configuration
 type SQLiteMemoryConfig struct {
 	// CacheSize is the maximum number of entries to cache
@@ -378,6 +410,24 @@ type MemoryStatus struct {
 
 	// Evictions count
 	Evictions int64 `json:"evictions,omitempty"`
+
+	// Mode of the memory backend (standalone, sentinel, cluster)
+	Mode string `json:"mode,omitempty"`
+
+	// ReadyReplicas of the memory backend
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Primaries are the pods accepting writes
+	Primaries []string `json:"primaries,omitempty"`
+
+	// ClusterState reported by Redis Cluster
+	ClusterState string `json:"clusterState,omitempty"`
+
+	// Failovers counts the primary changes observed
+	Failovers int32 `json:"failovers,omitempty"`
+
+	// LastFailoverTime is when the primaries last changed
+	LastFailoverTime *metav1.Time `json:"lastFailoverTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
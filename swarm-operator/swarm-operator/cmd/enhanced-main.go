@@ -4,22 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+
+	"github.com/claudeflow/swarm-operator/pkg/heartbeat"
+	"github.com/claudeflow/swarm-operator/pkg/taskjob"
 )
 
 var (
@@ -33,31 +40,67 @@ var (
 		Version:  "v1alpha1",
 		Resource: "swarmtasks",
 	}
+
+	// logLevel is read from LOG_LEVEL and switched at runtime through the
+	// health server, e.g.
+	//
+	//	curl -X PUT -d '{"level":"debug"}' localhost:8081/loglevel
+	logLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+)
+
+const (
+	// correlationIDAnnotation carries the correlation ID of a task onto its
+	// Job. The executor gets it as SWARM_CORRELATION_ID.
+	correlationIDAnnotation = "swarm.claudeflow.io/correlation-id"
+
+	// jobTimeout is how long a task's Job may run. It is long to leave
+	// room for long-running jobs.
+	jobTimeout = 2 * time.Hour
+
+	// resyncInterval is how often the reconciliation loop runs
+	resyncInterval = 10 * time.Second
+
+	// readyStaleAfter is how long after the last successful pass the
+	// operator reports itself unready
+	readyStaleAfter = time.Minute
+
+	// defaultStallTimeout is how long the loop may go without finishing a
+	// pass before the operator exits to be restarted. LOOP_STALL_TIMEOUT
+	// overrides it.
+	defaultStallTimeout = 5 * time.Minute
 )
 
 type EnhancedOperator struct {
 	clientset *kubernetes.Clientset
 	dynClient dynamic.Interface
 	namespace string
+	log       *zap.Logger
+
+	heartbeat    *heartbeat.Heartbeat
+	stallTimeout time.Duration
 }
 
 func main() {
-	log.Println("Starting Enhanced Swarm Operator v2.0.0...")
+	log := newLogger()
+	defer log.Sync()
+
+	log.Info("Starting Enhanced Swarm Operator v2.0.0...")
+	log.Warn("DEPRECATED: this standalone operator is superseded by the controller-runtime operator in cmd/main.go. Use its --executor-image, --executor-scripts-configmap and --inject-credential-secrets flags instead.")
 
 	// Setup Kubernetes clients
 	config, err := rest.InClusterConfig()
 	if err != nil {
-		log.Fatalf("Failed to get in-cluster config: %v", err)
+		log.Fatal("Failed to get in-cluster config", zap.Error(err))
 	}
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Failed to create clientset: %v", err)
+		log.Fatal("Failed to create clientset", zap.Error(err))
 	}
 
 	dynClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Failed to create dynamic client: %v", err)
+		log.Fatal("Failed to create dynamic client", zap.Error(err))
 	}
 
 	namespace := os.Getenv("OPERATOR_NAMESPACE")
@@ -66,9 +109,12 @@ func main() {
 	}
 
 	operator := &EnhancedOperator{
-		clientset: clientset,
-		dynClient: dynClient,
-		namespace: namespace,
+		clientset:    clientset,
+		dynClient:    dynClient,
+		namespace:    namespace,
+		log:          log,
+		heartbeat:    heartbeat.New(readyStaleAfter),
+		stallTimeout: loopStallTimeout(log),
 	}
 
 	// Start health and metrics servers
@@ -80,41 +126,73 @@ func main() {
 }
 
 func (o *EnhancedOperator) run() {
-	log.Println("Starting enhanced reconciliation loop...")
+	o.log.Info("Starting enhanced reconciliation loop...", zap.Duration("stallTimeout", o.stallTimeout))
+	go o.watchLoop()
 	
 	// Initial reconciliation
-	o.reconcileTasks()
+	o.pass()
 	
 	// Watch for SwarmTasks
-	wait.Forever(func() {
-		o.reconcileTasks()
-	}, 10*time.Second)
+	wait.Forever(o.pass, resyncInterval)
+}
+
+// pass runs one pass of the reconciliation loop and records it on the
+// heartbeat
+func (o *EnhancedOperator) pass() {
+	start := time.Now()
+	err := o.reconcileTasks()
+	o.heartbeat.Record(time.Since(start), err)
 }
 
-func (o *EnhancedOperator) reconcileTasks() {
+// watchLoop exits the operator once the reconciliation loop has not
+// finished a pass within the stall timeout, e.g. because a call to the API
+// server hangs, so Kubernetes restarts it
+func (o *EnhancedOperator) watchLoop() {
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if since := o.heartbeat.SinceLastPass(); since > o.stallTimeout {
+			o.log.Fatal("Reconciliation loop stalled, exiting",
+				zap.Duration("sinceLastPass", since), zap.Duration("stallTimeout", o.stallTimeout))
+		}
+	}
+}
+
+// reconcileTasks fails when the tasks cannot be listed. Failures of single
+// tasks are logged and retried on the next pass.
+func (o *EnhancedOperator) reconcileTasks() error {
+	// Every pass gets its own ID to tell its logs apart
+	log := o.log.With(zap.String("reconcileID", string(uuid.NewUUID())))
+
 	// List all SwarmTasks
 	tasks, err := o.dynClient.Resource(taskGVR).Namespace("default").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		log.Printf("Error listing tasks: %v", err)
-		return
+		log.Error("Failed to list tasks", zap.Error(err))
+		return err
 	}
 
 	for _, task := range tasks.Items {
 		taskName := task.GetName()
+		log := log.With(taskFields(task)...)
 		taskSpec, found, err := unstructured.NestedMap(task.Object, "spec")
 		if !found || err != nil {
 			continue
 		}
 
-		// Check if we already created a job for this task
+		// Check on the job of a running task
 		status, _, _ := unstructured.NestedMap(task.Object, "status")
 		phase, _ := status["phase"].(string)
-		
-		// Handle resume logic
+		if phase == "Running" {
+			o.checkEnhancedJob(log, task)
+			continue
+		}
+
+		// Handle resume logic. The Resuming phase keeps the intent to
+		// resume until the new job runs, across operator restarts.
 		resume, _ := taskSpec["resume"].(bool)
 		if resume && phase == "Failed" {
-			log.Printf("Resuming failed task: %s", taskName)
-			o.updateTaskStatus(task, "Resuming", "Preparing to resume from checkpoint")
+			log.Info("Resuming failed task")
+			o.updateTaskStatus(log, task, "Resuming", "Preparing to resume from checkpoint")
 			phase = "Resuming"
 		}
 		
@@ -122,21 +200,37 @@ func (o *EnhancedOperator) reconcileTasks() {
 			continue
 		}
 
-		log.Printf("Processing enhanced task: %s", taskName)
-		o.createEnhancedJob(taskName, task, taskSpec)
+		log.Info("Processing enhanced task")
+		o.createEnhancedJob(log, taskName, task, taskSpec, phase)
 	}
+	return nil
 }
 
-func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.Unstructured, taskSpec map[string]interface{}) {
+func (o *EnhancedOperator) createEnhancedJob(log *zap.Logger, taskName string, task unstructured.Unstructured, taskSpec map[string]interface{}, phase string) {
 	jobName := fmt.Sprintf("swarm-job-%s", taskName)
+	log = log.With(zap.String("job", jobName))
 	
-	// Check if job already exists (unless resuming)
-	phase, _ := taskSpec["phase"].(string)
-	if phase != "Resuming" {
-		_, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
-		if err == nil {
-			return // Job already exists
+	existing, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
+	if err == nil {
+		switch {
+		case phase == "Resuming" && (taskjob.Failed(existing) || time.Now().After(taskjob.Deadline(existing, jobTimeout))):
+			// Resuming replaces the failed job. The next pass creates the
+			// new one once this one is gone.
+			if existing.DeletionTimestamp == nil {
+				log.Info("Deleting failed job to resume the task")
+				err := o.clientset.BatchV1().Jobs("default").Delete(context.TODO(), jobName, metav1.DeleteOptions{
+					PropagationPolicy: ptr(metav1.DeletePropagationBackground),
+				})
+				if err != nil && !apierrors.IsNotFound(err) {
+					log.Error("Failed to delete job", zap.Error(err))
+				}
+			}
+		default:
+			// The job was created before the operator restarted
+			setJobStatus(&task, existing)
+			o.updateTaskStatus(log, task, "Running", "Enhanced job created")
 		}
+		return
 	}
 
 	// Get task configuration
@@ -151,10 +245,11 @@ func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.
 	
 	// Create PVCs if needed
 	persistentVolumes, _ := taskSpec["persistentVolumes"].([]interface{})
-	volumeMounts, volumes := o.createPersistentVolumes(taskName, persistentVolumes)
+	volumeMounts, volumes := o.createPersistentVolumes(log, taskName, persistentVolumes)
 	
 	// Build container spec
 	container := o.buildContainer(taskName, taskDesc, executorImage, taskSpec, volumeMounts, resume)
+	container.Env = append(container.Env, corev1.EnvVar{Name: "SWARM_CORRELATION_ID", Value: correlationID(task)})
 	
 	// Add additional volumes
 	volumes = append(volumes, o.buildAdditionalVolumes(taskSpec)...)
@@ -169,6 +264,10 @@ func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.
 				"swarm.claudeflow.io/priority": priority,
 				"swarm.claudeflow.io/type":     "enhanced",
 			},
+			Annotations: map[string]string{
+				correlationIDAnnotation:    correlationID(task),
+				taskjob.DeadlineAnnotation: time.Now().Add(jobTimeout).Format(time.RFC3339),
+			},
 		},
 		Spec: batchv1.JobSpec{
 			BackoffLimit: ptr(int32(3)),
@@ -185,18 +284,16 @@ func (o *EnhancedOperator) createEnhancedJob(taskName string, task unstructured.
 		},
 	}
 
-	_, err := o.clientset.BatchV1().Jobs("default").Create(context.TODO(), job, metav1.CreateOptions{})
+	created, err := o.clientset.BatchV1().Jobs("default").Create(context.TODO(), job, metav1.CreateOptions{})
 	if err != nil {
-		log.Printf("Failed to create job: %v", err)
-		o.updateTaskStatus(task, "Failed", fmt.Sprintf("Failed to create job: %v", err))
+		log.Error("Failed to create job", zap.Error(err))
+		o.updateTaskStatus(log, task, "Failed", fmt.Sprintf("Failed to create job: %v", err))
 		return
 	}
+	setJobStatus(&task, created)
 
-	log.Printf("Created enhanced job %s for task %s", jobName, taskName)
-	o.updateTaskStatus(task, "Running", "Enhanced job created")
-	
-	// Monitor job completion
-	go o.monitorEnhancedJob(jobName, task)
+	log.Info("Created enhanced job")
+	o.updateTaskStatus(log, task, "Running", "Enhanced job created")
 }
 
 func (o *EnhancedOperator) buildContainer(taskName, taskDesc, image string, taskSpec map[string]interface{}, volumeMounts []corev1.VolumeMount, resume bool) corev1.Container {
@@ -236,7 +333,7 @@ func (o *EnhancedOperator) buildContainer(taskName, taskDesc, image string, task
 	return container
 }
 
-func (o *EnhancedOperator) createPersistentVolumes(taskName string, pvSpecs []interface{}) ([]corev1.VolumeMount, []corev1.Volume) {
+func (o *EnhancedOperator) createPersistentVolumes(log *zap.Logger, taskName string, pvSpecs []interface{}) ([]corev1.VolumeMount, []corev1.Volume) {
 	var volumeMounts []corev1.VolumeMount
 	var volumes []corev1.Volume
 
@@ -291,10 +388,10 @@ func (o *EnhancedOperator) createPersistentVolumes(taskName string, pvSpecs []in
 			_, err = o.clientset.CoreV1().PersistentVolumeClaims("default").Create(
 				context.TODO(), pvc, metav1.CreateOptions{})
 			if err != nil {
-				log.Printf("Failed to create PVC %s: %v", pvcName, err)
+				log.Error("Failed to create PVC", zap.String("pvc", pvcName), zap.Error(err))
 				continue
 			}
-			log.Printf("Created PVC %s for task %s", pvcName, taskName)
+			log.Info("Created PVC", zap.String("pvc", pvcName))
 		}
 
 		// Add volume mount
@@ -512,41 +609,34 @@ func (o *EnhancedOperator) getTolerations(taskSpec map[string]interface{}) []cor
 	return tolerations
 }
 
-func (o *EnhancedOperator) monitorEnhancedJob(jobName string, task unstructured.Unstructured) {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-	
-	timeout := time.After(2 * time.Hour) // Extended timeout for long-running jobs
-	
-	for {
-		select {
-		case <-ticker.C:
-			job, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
-			if err != nil {
-				log.Printf("Error getting job %s: %v", jobName, err)
-				return
-			}
-			
-			// Check for checkpoint updates
-			o.updateCheckpointStatus(task, job)
-			
-			if job.Status.Succeeded > 0 {
-				o.updateTaskStatus(task, "Completed", "Job completed successfully")
-				log.Printf("Enhanced job %s completed successfully", jobName)
-				return
-			}
-			
-			if job.Status.Failed > 0 && job.Status.Failed >= *job.Spec.BackoffLimit {
-				o.updateTaskStatus(task, "Failed", fmt.Sprintf("Job failed after %d attempts", job.Status.Failed))
-				log.Printf("Enhanced job %s failed", jobName)
-				return
-			}
-			
-		case <-timeout:
-			o.updateTaskStatus(task, "Failed", "Job timed out")
-			log.Printf("Enhanced job %s timed out", jobName)
-			return
-		}
+// checkEnhancedJob moves a running task on once its job finished, ran past
+// its deadline or is gone
+func (o *EnhancedOperator) checkEnhancedJob(log *zap.Logger, task unstructured.Unstructured) {
+	jobName := fmt.Sprintf("swarm-job-%s", task.GetName())
+	log = log.With(zap.String("job", jobName))
+
+	job, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		o.updateTaskStatus(log, task, "Failed", "Job was deleted")
+		log.Info("Enhanced job was deleted")
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get job", zap.Error(err))
+		return
+	}
+
+	// Check for checkpoint updates
+	o.updateCheckpointStatus(task, job)
+
+	changed := setJobStatus(&task, job)
+	if phase, message := taskjob.Outcome(job, jobTimeout, time.Now()); phase != "" {
+		o.updateTaskStatus(log, task, phase, message)
+		log.Info("Enhanced job finished", zap.String("phase", phase), zap.String("message", message),
+			zap.Int32("attempts", job.Status.Failed))
+	} else if changed {
+		// Still running, with another retry for the Retries column
+		o.writeTaskStatus(log, task)
 	}
 }
 
@@ -564,7 +654,7 @@ func (o *EnhancedOperator) updateCheckpointStatus(task unstructured.Unstructured
 	// In a real implementation, you'd parse checkpoint data from pod logs or a sidecar
 }
 
-func (o *EnhancedOperator) updateTaskStatus(task unstructured.Unstructured, phase, message string) {
+func (o *EnhancedOperator) updateTaskStatus(log *zap.Logger, task unstructured.Unstructured, phase, message string) {
 	status := map[string]interface{}{
 		"phase":              phase,
 		"message":            message,
@@ -577,13 +667,42 @@ func (o *EnhancedOperator) updateTaskStatus(task unstructured.Unstructured, phas
 	} else if phase == "Running" {
 		status["startTime"] = time.Now().Format(time.RFC3339)
 	}
+	copyJobStatus(task, status)
 
 	task.Object["status"] = status
-	
+	o.writeTaskStatus(log, task)
+}
+
+func (o *EnhancedOperator) writeTaskStatus(log *zap.Logger, task unstructured.Unstructured) {
 	_, err := o.dynClient.Resource(taskGVR).Namespace(task.GetNamespace()).UpdateStatus(
 		context.TODO(), &task, metav1.UpdateOptions{})
 	if err != nil {
-		log.Printf("Failed to update task status: %v", err)
+		phase, _, _ := unstructured.NestedString(task.Object, "status", "phase")
+		log.Error("Failed to update task status", zap.String("phase", phase), zap.Error(err))
+	}
+}
+
+// setJobStatus records the Job of a task and how often its pods were
+// retried, shown in the Job and Retries columns. It reports whether either
+// changed.
+func setJobStatus(task *unstructured.Unstructured, job *batchv1.Job) bool {
+	name, _, _ := unstructured.NestedString(task.Object, "status", "jobName")
+	retries, _, _ := unstructured.NestedInt64(task.Object, "status", "retryCount")
+	if name == job.Name && retries == int64(job.Status.Failed) {
+		return false
+	}
+	_ = unstructured.SetNestedField(task.Object, job.Name, "status", "jobName")
+	_ = unstructured.SetNestedField(task.Object, int64(job.Status.Failed), "status", "retryCount")
+	return true
+}
+
+// copyJobStatus keeps the Job fields of the task's status in the status
+// replacing it
+func copyJobStatus(task unstructured.Unstructured, status map[string]interface{}) {
+	for _, key := range []string{"jobName", "retryCount"} {
+		if value, found, _ := unstructured.NestedFieldCopy(task.Object, "status", key); found {
+			status[key] = value
+		}
 	}
 }
 
@@ -598,9 +717,8 @@ func (o *EnhancedOperator) startHealthServer() {
 	
 	// Readiness probe
 	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		// Check if we can list tasks
-		_, err := o.dynClient.Resource(taskGVR).List(context.TODO(), metav1.ListOptions{})
-		if err != nil {
+		// Check that the loop recently listed and processed the tasks
+		if err := o.heartbeat.Ready(); err != nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			w.Write([]byte(fmt.Sprintf("not ready: %v", err)))
 			return
@@ -609,60 +727,118 @@ func (o *EnhancedOperator) startHealthServer() {
 		w.Write([]byte("ready"))
 	})
 	
-	log.Println("Starting health server on :8081")
+	// GET reports and PUT switches the log level
+	mux.Handle("/loglevel", logLevel)
+	
+	o.log.Info("Starting health server on :8081")
 	if err := http.ListenAndServe(":8081", mux); err != nil {
-		log.Fatalf("Failed to start health server: %v", err)
+		o.log.Fatal("Failed to start health server", zap.Error(err))
 	}
 }
 
 func (o *EnhancedOperator) startMetricsServer() {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain")
-		
-		// Get task counts
-		tasks, _ := o.dynClient.Resource(taskGVR).List(context.TODO(), metav1.ListOptions{})
-		
-		var pending, running, completed, failed int
-		for _, task := range tasks.Items {
-			status, _, _ := unstructured.NestedMap(task.Object, "status")
-			phase, _ := status["phase"].(string)
-			switch phase {
-			case "Pending":
-				pending++
-			case "Running", "Resuming":
-				running++
-			case "Completed":
-				completed++
-			case "Failed":
-				failed++
-			}
-		}
-		
-		metrics := fmt.Sprintf(`# HELP swarm_operator_info Swarm operator information
-# TYPE swarm_operator_info gauge
-swarm_operator_info{version="2.0.0",type="enhanced"} 1
-# HELP swarm_tasks_total Total number of tasks by phase
-# TYPE swarm_tasks_total gauge
-swarm_tasks_total{phase="pending"} %d
-swarm_tasks_total{phase="running"} %d
-swarm_tasks_total{phase="completed"} %d
-swarm_tasks_total{phase="failed"} %d
-# HELP swarm_operator_ready Operator readiness
-# TYPE swarm_operator_ready gauge
-swarm_operator_ready 1
-`, pending, running, completed, failed)
-		
-		w.Write([]byte(metrics))
+	info := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "swarm_operator_info",
+		Help:        "Swarm operator information",
+		ConstLabels: prometheus.Labels{"version": "2.0.0", "type": "enhanced"},
 	})
+	info.Set(1)
+	metrics, err := heartbeat.Handler(info, newTaskCollector(o.dynClient), o.heartbeat)
+	if err != nil {
+		o.log.Fatal("Failed to register metrics", zap.Error(err))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
 	
-	log.Println("Starting metrics server on :8080")
+	o.log.Info("Starting metrics server on :8080")
 	if err := http.ListenAndServe(":8080", mux); err != nil {
-		log.Fatalf("Failed to start metrics server: %v", err)
+		o.log.Fatal("Failed to start metrics server", zap.Error(err))
+	}
+}
+
+// taskCollector counts the tasks by phase on every scrape
+type taskCollector struct {
+	dynClient dynamic.Interface
+	tasks     *prometheus.Desc
+}
+
+func newTaskCollector(dynClient dynamic.Interface) *taskCollector {
+	return &taskCollector{
+		dynClient: dynClient,
+		tasks:     prometheus.NewDesc("swarm_tasks_total", "Total number of tasks by phase", []string{"phase"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.tasks
+}
+
+// Collect implements prometheus.Collector
+func (c *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	tasks, err := c.dynClient.Resource(taskGVR).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.tasks, err)
+		return
+	}
+
+	counts := map[string]int{"pending": 0, "running": 0, "completed": 0, "failed": 0}
+	for _, task := range tasks.Items {
+		phase, _, _ := unstructured.NestedString(task.Object, "status", "phase")
+		switch phase {
+		case "Pending":
+			counts["pending"]++
+		case "Running", "Resuming":
+			counts["running"]++
+		case "Completed":
+			counts["completed"]++
+		case "Failed":
+			counts["failed"]++
+		}
+	}
+	for phase, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.GaugeValue, float64(count), phase)
 	}
 }
 
 // Helper functions
+// newLogger logs JSON at the level of LOG_LEVEL, info by default
+func newLogger() *zap.Logger {
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid LOG_LEVEL %q, logging at info\n", level)
+		}
+	}
+	config := zap.NewProductionConfig()
+	config.Level = logLevel
+	logger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+	return logger
+}
+
+// correlationID ties the logs of a task, its Job and its executor
+// together. Clients may set it on the task; it defaults to the task UID.
+func correlationID(task unstructured.Unstructured) string {
+	if id := task.GetAnnotations()[correlationIDAnnotation]; id != "" {
+		return id
+	}
+	return string(task.GetUID())
+}
+
+// taskFields are the log fields of a task
+func taskFields(task unstructured.Unstructured) []zap.Field {
+	cluster, _, _ := unstructured.NestedString(task.Object, "spec", "swarmRef")
+	return []zap.Field{
+		zap.String("task", task.GetName()),
+		zap.String("namespace", task.GetNamespace()),
+		zap.String("cluster", cluster),
+		zap.String("correlationID", correlationID(task)),
+	}
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -672,4 +848,20 @@ func getStringValue(m map[string]interface{}, key string) string {
 		return v
 	}
 	return ""
-}
\ No newline at end of file
+}
+
+// loopStallTimeout reads LOOP_STALL_TIMEOUT, defaultStallTimeout when unset
+// or invalid
+func loopStallTimeout(log *zap.Logger) time.Duration {
+	value := os.Getenv("LOOP_STALL_TIMEOUT")
+	if value == "" {
+		return defaultStallTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Warn("Invalid LOOP_STALL_TIMEOUT, using the default",
+			zap.String("value", value), zap.Duration("default", defaultStallTimeout))
+		return defaultStallTimeout
+	}
+	return timeout
+}
@@ -3,21 +3,28 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+
+	"github.com/claudeflow/swarm-operator/pkg/heartbeat"
+	"github.com/claudeflow/swarm-operator/pkg/taskjob"
 )
 
 var (
@@ -31,31 +38,72 @@ var (
 		Version:  "v1alpha1",
 		Resource: "swarmtasks",
 	}
+
+	// logLevel is read from LOG_LEVEL and switched at runtime through the
+	// health server, e.g.
+	//
+	//	curl -X PUT -d '{"level":"debug"}' localhost:8081/loglevel
+	logLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+)
+
+const (
+	// correlationIDAnnotation carries the correlation ID of a task onto its
+	// Job. The executor gets it as SWARM_CORRELATION_ID.
+	correlationIDAnnotation = "swarm.claudeflow.io/correlation-id"
+
+	// jobTimeout is how long a task's Job may run
+	jobTimeout = 10 * time.Minute
+
+	// resyncInterval is how often the reconciliation loop runs
+	resyncInterval = 10 * time.Second
+
+	// readyStaleAfter is how long after the last successful pass the
+	// operator reports itself unready
+	readyStaleAfter = time.Minute
+
+	// defaultStallTimeout is how long the loop may go without finishing a
+	// pass before the operator exits to be restarted. LOOP_STALL_TIMEOUT
+	// overrides it.
+	defaultStallTimeout = 5 * time.Minute
 )
 
+// tasksProcessed counts the tasks the operator created a Job for
+var tasksProcessed = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "swarm_tasks_processed",
+	Help: "Total tasks processed",
+})
+
 type Operator struct {
 	clientset *kubernetes.Clientset
 	dynClient dynamic.Interface
 	namespace string
+	log       *zap.Logger
+
+	heartbeat    *heartbeat.Heartbeat
+	stallTimeout time.Duration
 }
 
 func main() {
-	log.Println("Starting Enhanced Swarm Operator v0.4.0 with GitHub App support...")
+	log := newLogger()
+	defer log.Sync()
+
+	log.Info("Starting Enhanced Swarm Operator v0.4.0 with GitHub App support...")
+	log.Warn("DEPRECATED: this standalone operator is superseded by the controller-runtime operator in cmd/main.go. Use its --executor-image, --executor-scripts-configmap and --inject-credential-secrets flags instead.")
 
 	// Setup Kubernetes clients
 	config, err := rest.InClusterConfig()
 	if err != nil {
-		log.Fatalf("Failed to get in-cluster config: %v", err)
+		log.Fatal("Failed to get in-cluster config", zap.Error(err))
 	}
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Failed to create clientset: %v", err)
+		log.Fatal("Failed to create clientset", zap.Error(err))
 	}
 
 	dynClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		log.Fatalf("Failed to create dynamic client: %v", err)
+		log.Fatal("Failed to create dynamic client", zap.Error(err))
 	}
 
 	namespace := os.Getenv("OPERATOR_NAMESPACE")
@@ -64,9 +112,12 @@ func main() {
 	}
 
 	operator := &Operator{
-		clientset: clientset,
-		dynClient: dynClient,
-		namespace: namespace,
+		clientset:    clientset,
+		dynClient:    dynClient,
+		namespace:    namespace,
+		log:          log,
+		heartbeat:    heartbeat.New(readyStaleAfter),
+		stallTimeout: loopStallTimeout(log),
 	}
 
 	// Start health and metrics servers
@@ -78,35 +129,67 @@ func main() {
 }
 
 func (o *Operator) run() {
-	log.Println("Starting reconciliation loop...")
+	o.log.Info("Starting reconciliation loop...", zap.Duration("stallTimeout", o.stallTimeout))
+	go o.watchLoop()
 	
 	// Initial reconciliation
-	o.reconcileTasks()
+	o.pass()
 	
 	// Watch for SwarmTasks and create Jobs
-	wait.Forever(func() {
-		o.reconcileTasks()
-	}, 10*time.Second)
+	wait.Forever(o.pass, resyncInterval)
+}
+
+// pass runs one pass of the reconciliation loop and records it on the
+// heartbeat
+func (o *Operator) pass() {
+	start := time.Now()
+	err := o.reconcileTasks()
+	o.heartbeat.Record(time.Since(start), err)
 }
 
-func (o *Operator) reconcileTasks() {
+// watchLoop exits the operator once the reconciliation loop has not
+// finished a pass within the stall timeout, e.g. because a call to the API
+// server hangs, so Kubernetes restarts it
+func (o *Operator) watchLoop() {
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if since := o.heartbeat.SinceLastPass(); since > o.stallTimeout {
+			o.log.Fatal("Reconciliation loop stalled, exiting",
+				zap.Duration("sinceLastPass", since), zap.Duration("stallTimeout", o.stallTimeout))
+		}
+	}
+}
+
+// reconcileTasks fails when the tasks cannot be listed. Failures of single
+// tasks are logged and retried on the next pass.
+func (o *Operator) reconcileTasks() error {
+	// Every pass gets its own ID to tell its logs apart
+	log := o.log.With(zap.String("reconcileID", string(uuid.NewUUID())))
+
 	// List all SwarmTasks
 	tasks, err := o.dynClient.Resource(taskGVR).Namespace("default").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		log.Printf("Error listing tasks: %v", err)
-		return
+		log.Error("Failed to list tasks", zap.Error(err))
+		return err
 	}
 
 	for _, task := range tasks.Items {
 		taskName := task.GetName()
+		log := log.With(taskFields(task)...)
 		taskSpec, found, err := unstructured.NestedMap(task.Object, "spec")
 		if !found || err != nil {
 			continue
 		}
 
-		// Check if we already created a job for this task
+		// Check on the job of a running task, and skip tasks that finished
 		status, _, _ := unstructured.NestedMap(task.Object, "status")
-		if phase, ok := status["phase"].(string); ok && phase != "" && phase != "Pending" {
+		phase, _ := status["phase"].(string)
+		if phase == "Running" {
+			o.checkJob(log, task)
+			continue
+		}
+		if phase != "" && phase != "Pending" {
 			continue
 		}
 
@@ -114,26 +197,30 @@ func (o *Operator) reconcileTasks() {
 		taskDesc, _ := taskSpec["task"].(string)
 		priority, _ := taskSpec["priority"].(string)
 		
-		log.Printf("Processing task: %s - %s (priority: %s)", taskName, taskDesc, priority)
+		log.Info("Processing task", zap.String("description", taskDesc), zap.String("priority", priority))
 
 		// Special handling for GitHub repo creation tasks
 		if strings.Contains(strings.ToLower(taskDesc), "hello world") && 
 		   strings.Contains(strings.ToLower(taskDesc), "github") {
-			o.createGitHubJob(taskName, task)
+			o.createGitHubJob(log, taskName, task)
 		} else {
 			// Update status to show we're processing
-			o.updateTaskStatus(task, "Running", "Job creation in progress")
+			o.updateTaskStatus(log, task, "Running", "Job creation in progress")
 		}
 	}
+	return nil
 }
 
-func (o *Operator) createGitHubJob(taskName string, task unstructured.Unstructured) {
+func (o *Operator) createGitHubJob(log *zap.Logger, taskName string, task unstructured.Unstructured) {
 	jobName := fmt.Sprintf("swarm-job-%s", taskName)
+	log = log.With(zap.String("job", jobName))
 	
-	// Check if job already exists
-	_, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
+	// A job of a pending task was created before the operator restarted
+	existing, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
 	if err == nil {
-		return // Job already exists
+		setJobStatus(&task, existing)
+		o.updateTaskStatus(log, task, "Running", "Job created")
+		return
 	}
 
 	// Check which authentication method to use
@@ -141,9 +228,9 @@ func (o *Operator) createGitHubJob(taskName string, task unstructured.Unstructur
 	_, err = o.clientset.CoreV1().Secrets("default").Get(context.TODO(), "github-app-credentials", metav1.GetOptions{})
 	if err == nil {
 		useGitHubApp = true
-		log.Printf("Using GitHub App authentication for task %s", taskName)
+		log.Debug("Using GitHub App authentication")
 	} else {
-		log.Printf("Using Personal Access Token authentication for task %s", taskName)
+		log.Debug("Using Personal Access Token authentication")
 	}
 
 	// Create container spec
@@ -152,6 +239,10 @@ func (o *Operator) createGitHubJob(taskName string, task unstructured.Unstructur
 		Image:   "alpine/git:latest",
 		Command: []string{"/bin/sh", "/scripts/task.sh"},
 		Env: []corev1.EnvVar{
+			{
+				Name:  "SWARM_CORRELATION_ID",
+				Value: correlationID(task),
+			},
 			{
 				Name: "GITHUB_USERNAME",
 				ValueFrom: &corev1.EnvVarSource{
@@ -289,6 +380,10 @@ func (o *Operator) createGitHubJob(taskName string, task unstructured.Unstructur
 				"swarm.claudeflow.io/type": "github-automation",
 				"swarm.claudeflow.io/auth": map[bool]string{true: "github-app", false: "pat"}[useGitHubApp],
 			},
+			Annotations: map[string]string{
+				correlationIDAnnotation:    correlationID(task),
+				taskjob.DeadlineAnnotation: time.Now().Add(jobTimeout).Format(time.RFC3339),
+			},
 		},
 		Spec: batchv1.JobSpec{
 			BackoffLimit: ptr(int32(2)),
@@ -302,60 +397,51 @@ func (o *Operator) createGitHubJob(taskName string, task unstructured.Unstructur
 		},
 	}
 
-	_, err = o.clientset.BatchV1().Jobs("default").Create(context.TODO(), job, metav1.CreateOptions{})
+	created, err := o.clientset.BatchV1().Jobs("default").Create(context.TODO(), job, metav1.CreateOptions{})
 	if err != nil {
-		log.Printf("Failed to create job: %v", err)
-		o.updateTaskStatus(task, "Failed", fmt.Sprintf("Failed to create job: %v", err))
+		log.Error("Failed to create job", zap.Error(err))
+		o.updateTaskStatus(log, task, "Failed", fmt.Sprintf("Failed to create job: %v", err))
 		return
 	}
+	setJobStatus(&task, created)
 
 	authMethod := "Personal Access Token"
 	if useGitHubApp {
 		authMethod = "GitHub App"
 	}
-	log.Printf("Created job %s for task %s using %s authentication", jobName, taskName, authMethod)
-	o.updateTaskStatus(task, "Running", fmt.Sprintf("Job created with %s authentication", authMethod))
-	
-	// Monitor job completion
-	go o.monitorJob(jobName, task)
+	log.Info("Created job", zap.String("auth", authMethod))
+	tasksProcessed.Inc()
+	o.updateTaskStatus(log, task, "Running", fmt.Sprintf("Job created with %s authentication", authMethod))
 }
 
-func (o *Operator) monitorJob(jobName string, task unstructured.Unstructured) {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-	
-	timeout := time.After(10 * time.Minute)
-	
-	for {
-		select {
-		case <-ticker.C:
-			job, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
-			if err != nil {
-				log.Printf("Error getting job %s: %v", jobName, err)
-				return
-			}
-			
-			if job.Status.Succeeded > 0 {
-				o.updateTaskStatus(task, "Completed", "Job completed successfully")
-				log.Printf("Job %s completed successfully", jobName)
-				return
-			}
-			
-			if job.Status.Failed > 0 && job.Status.Failed >= *job.Spec.BackoffLimit {
-				o.updateTaskStatus(task, "Failed", fmt.Sprintf("Job failed after %d attempts", job.Status.Failed))
-				log.Printf("Job %s failed", jobName)
-				return
-			}
-			
-		case <-timeout:
-			o.updateTaskStatus(task, "Failed", "Job timed out")
-			log.Printf("Job %s timed out", jobName)
-			return
-		}
+// checkJob moves a running task on once its job finished or ran past its
+// deadline
+func (o *Operator) checkJob(log *zap.Logger, task unstructured.Unstructured) {
+	jobName := fmt.Sprintf("swarm-job-%s", task.GetName())
+	log = log.With(zap.String("job", jobName))
+
+	job, err := o.clientset.BatchV1().Jobs("default").Get(context.TODO(), jobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// Only GitHub tasks run a job
+		return
+	}
+	if err != nil {
+		log.Error("Failed to get job", zap.Error(err))
+		return
+	}
+
+	changed := setJobStatus(&task, job)
+	if phase, message := taskjob.Outcome(job, jobTimeout, time.Now()); phase != "" {
+		o.updateTaskStatus(log, task, phase, message)
+		log.Info("Job finished", zap.String("phase", phase), zap.String("message", message),
+			zap.Int32("attempts", job.Status.Failed))
+	} else if changed {
+		// Still running, with another retry for the Retries column
+		o.writeTaskStatus(log, task)
 	}
 }
 
-func (o *Operator) updateTaskStatus(task unstructured.Unstructured, phase, message string) {
+func (o *Operator) updateTaskStatus(log *zap.Logger, task unstructured.Unstructured, phase, message string) {
 	status := map[string]interface{}{
 		"phase":              phase,
 		"message":            message,
@@ -365,13 +451,42 @@ func (o *Operator) updateTaskStatus(task unstructured.Unstructured, phase, messa
 	if phase == "Completed" {
 		status["progress"] = int64(100)
 	}
+	copyJobStatus(task, status)
 
 	task.Object["status"] = status
-	
+	o.writeTaskStatus(log, task)
+}
+
+func (o *Operator) writeTaskStatus(log *zap.Logger, task unstructured.Unstructured) {
 	_, err := o.dynClient.Resource(taskGVR).Namespace(task.GetNamespace()).UpdateStatus(
 		context.TODO(), &task, metav1.UpdateOptions{})
 	if err != nil {
-		log.Printf("Failed to update task status: %v", err)
+		phase, _, _ := unstructured.NestedString(task.Object, "status", "phase")
+		log.Error("Failed to update task status", zap.String("phase", phase), zap.Error(err))
+	}
+}
+
+// setJobStatus records the Job of a task and how often its pods were
+// retried, shown in the Job and Retries columns. It reports whether either
+// changed.
+func setJobStatus(task *unstructured.Unstructured, job *batchv1.Job) bool {
+	name, _, _ := unstructured.NestedString(task.Object, "status", "jobName")
+	retries, _, _ := unstructured.NestedInt64(task.Object, "status", "retryCount")
+	if name == job.Name && retries == int64(job.Status.Failed) {
+		return false
+	}
+	_ = unstructured.SetNestedField(task.Object, job.Name, "status", "jobName")
+	_ = unstructured.SetNestedField(task.Object, int64(job.Status.Failed), "status", "retryCount")
+	return true
+}
+
+// copyJobStatus keeps the Job fields of the task's status in the status
+// replacing it
+func copyJobStatus(task unstructured.Unstructured, status map[string]interface{}) {
+	for _, key := range []string{"jobName", "retryCount"} {
+		if value, found, _ := unstructured.NestedFieldCopy(task.Object, "status", key); found {
+			status[key] = value
+		}
 	}
 }
 
@@ -382,34 +497,94 @@ func (o *Operator) startHealthServer() {
 		w.Write([]byte("healthy"))
 	})
 	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if err := o.heartbeat.Ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(fmt.Sprintf("not ready: %v", err)))
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("ready"))
 	})
-	log.Println("Starting health server on :8081")
+	// GET reports and PUT switches the log level
+	mux.Handle("/loglevel", logLevel)
+	o.log.Info("Starting health server on :8081")
 	if err := http.ListenAndServe(":8081", mux); err != nil {
-		log.Fatalf("Failed to start health server: %v", err)
+		o.log.Fatal("Failed to start health server", zap.Error(err))
 	}
 }
 
 func (o *Operator) startMetricsServer() {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/plain")
-		metrics := `# HELP swarm_operator_info Swarm operator information
-# TYPE swarm_operator_info gauge
-swarm_operator_info{version="0.4.0"} 1
-# HELP swarm_tasks_processed Total tasks processed
-# TYPE swarm_tasks_processed counter
-swarm_tasks_processed 1
-`
-		w.Write([]byte(metrics))
+	info := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "swarm_operator_info",
+		Help:        "Swarm operator information",
+		ConstLabels: prometheus.Labels{"version": "0.4.0"},
 	})
-	log.Println("Starting metrics server on :8080")
+	info.Set(1)
+	metrics, err := heartbeat.Handler(info, tasksProcessed, o.heartbeat)
+	if err != nil {
+		o.log.Fatal("Failed to register metrics", zap.Error(err))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics)
+	o.log.Info("Starting metrics server on :8080")
 	if err := http.ListenAndServe(":8080", mux); err != nil {
-		log.Fatalf("Failed to start metrics server: %v", err)
+		o.log.Fatal("Failed to start metrics server", zap.Error(err))
+	}
+}
+
+// newLogger logs JSON at the level of LOG_LEVEL, info by default
+func newLogger() *zap.Logger {
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid LOG_LEVEL %q, logging at info\n", level)
+		}
+	}
+	config := zap.NewProductionConfig()
+	config.Level = logLevel
+	logger, err := config.Build()
+	if err != nil {
+		panic(err)
+	}
+	return logger
+}
+
+// correlationID ties the logs of a task, its Job and its executor
+// together. Clients may set it on the task; it defaults to the task UID.
+func correlationID(task unstructured.Unstructured) string {
+	if id := task.GetAnnotations()[correlationIDAnnotation]; id != "" {
+		return id
+	}
+	return string(task.GetUID())
+}
+
+// taskFields are the log fields of a task
+func taskFields(task unstructured.Unstructured) []zap.Field {
+	cluster, _, _ := unstructured.NestedString(task.Object, "spec", "swarmRef")
+	return []zap.Field{
+		zap.String("task", task.GetName()),
+		zap.String("namespace", task.GetNamespace()),
+		zap.String("cluster", cluster),
+		zap.String("correlationID", correlationID(task)),
 	}
 }
 
 func ptr[T any](v T) *T {
 	return &v
-}
\ No newline at end of file
+}
+
+// loopStallTimeout reads LOOP_STALL_TIMEOUT, defaultStallTimeout when unset
+// or invalid
+func loopStallTimeout(log *zap.Logger) time.Duration {
+	value := os.Getenv("LOOP_STALL_TIMEOUT")
+	if value == "" {
+		return defaultStallTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Warn("Invalid LOOP_STALL_TIMEOUT, using the default",
+			zap.String("value", value), zap.Duration("default", defaultStallTimeout))
+		return defaultStallTimeout
+	}
+	return timeout
+}
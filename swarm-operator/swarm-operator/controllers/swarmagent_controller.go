@@ -286,6 +286,23 @@ func (r *SwarmAgentReconciler) buildAgentEnv(agent *swarmv1alpha1.SwarmAgent, cl
 			Value: fmt.Sprintf("%s-%s:6379", cluster.Name, cluster.Spec.Memory.Type),
 		})
 	}
+	if cluster.Spec.Memory.Type == "redis" {
+		env = append(env, corev1.EnvVar{
+			Name:  "MEMORY_MODE",
+			Value: redisMode(cluster),
+		})
+		if redisMode(cluster) == redisModeSentinel {
+			env = append(env, corev1.EnvVar{
+				Name:  "MEMORY_SENTINEL_ENDPOINT",
+				Value: fmt.Sprintf("%s:%d", redisSentinelName(cluster), redisSentinelPort),
+			}, corev1.EnvVar{
+				Name:  "MEMORY_SENTINEL_MASTER",
+				Value: cluster.Name,
+			})
+		}
+		// REDIS_PASSWORD and REDISCLI_AUTH, which redis-cli reads
+		env = append(env, redisPasswordEnv(cluster)...)
+	}
 
 	// Add custom environment variables
 	for _, e := range agent.Spec.Environment {
@@ -536,6 +536,13 @@ func (r *SwarmClusterReconciler) updateClusterStatus(ctx context.Context, cluste
 		}
 	}
 
+	// Update memory backend status
+	if cluster.Spec.Memory.Type == "redis" {
+		if err := r.updateRedisStatus(ctx, cluster); err != nil {
+			r.Log.Error(err, "Failed to read redis replication state", "swarmcluster", cluster.Name)
+		}
+	}
+
 	// Update status
 	cluster.Status.ReadyAgents = readyAgents
 	cluster.Status.TotalAgents = int32(len(agentList.Items))
@@ -722,75 +729,6 @@ func getAgentTypesForTopology(topology swarmv1alpha1.SwarmTopology) []swarmv1alp
 	return types
 }
 
-func (r *SwarmClusterReconciler) deployRedis(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
-	// Deploy Redis for memory backend
-	deploy := &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-redis", cluster.Name),
-			Namespace: cluster.Namespace,
-		},
-	}
-
-	replicas := cluster.Spec.Memory.Replication
-	if replicas == 0 {
-		replicas = 1
-	}
-
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
-		deploy.Labels = map[string]string{
-			"swarm-cluster": cluster.Name,
-			"component":     "memory",
-			"backend":       "redis",
-		}
-
-		deploy.Spec = appsv1.DeploymentSpec{
-			Replicas: &replicas,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"swarm-cluster": cluster.Name,
-					"component":     "memory",
-				},
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"swarm-cluster": cluster.Name,
-						"component":     "memory",
-					},
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:  "redis",
-							Image: "redis:7-alpine",
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "redis",
-									ContainerPort: 6379,
-								},
-							},
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("100m"),
-									corev1.ResourceMemory: resource.MustParse("256Mi"),
-								},
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("1000m"),
-									corev1.ResourceMemory: resource.MustParse(getOrDefault(cluster.Spec.Memory.Size, "2Gi")),
-								},
-							},
-						},
-					},
-				},
-			},
-		}
-
-		return controllerutil.SetControllerReference(cluster, deploy, r.Scheme)
-	})
-
-	return err
-}
-
 func (r *SwarmClusterReconciler) deployHazelcast(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
 	// Implementation for Hazelcast deployment
 	return fmt.Errorf("hazelcast backend not yet implemented")
@@ -830,6 +768,7 @@ func (r *SwarmClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
 		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
 		Complete(r)
 }
\ No newline at end of file
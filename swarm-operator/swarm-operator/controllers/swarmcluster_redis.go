@@ -0,0 +1,853 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	swarmv1alpha1 "github.com/claudeflow/swarm-operator/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+const (
+	redisModeStandalone = "standalone"
+	redisModeSentinel   = "sentinel"
+	redisModeCluster    = "cluster"
+
+	redisImage        = "redis:7-alpine"
+	redisPort         = 6379
+	redisSentinelPort = 26379
+
+	// redisSentinels is the number of sentinels watching the primary, a
+	// majority of which agrees on a failover
+	redisSentinels = 3
+
+	// redisRoleLabel marks the current primary in sentinel mode so the
+	// client service only routes to it
+	redisRoleLabel = "redis-role"
+
+	redisPasswordKey = "password"
+	redisTimeout     = 5 * time.Second
+
+	defaultRedisMemory      = "2Gi"
+	defaultRedisStorageSize = "8Gi"
+
+	// ConditionTypeMemoryConfigured reports whether the memory backend
+	// spec of the cluster could be applied
+	ConditionTypeMemoryConfigured = "MemoryConfigured"
+
+	ReasonMemoryConfigured  = "MemoryConfigured"
+	ReasonInvalidMemorySize = "InvalidMemorySize"
+)
+
+// deployRedis runs redis as a StatefulSet with optional persistence and
+// password authentication. With more than one replica it runs either a
+// primary with replicas watched by sentinels, or a Redis Cluster.
+func (r *SwarmClusterReconciler) deployRedis(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	mode := redisMode(cluster)
+	replicas := redisReplicas(cluster)
+	if mode == redisModeCluster && replicas < 3 {
+		return fmt.Errorf("redis cluster mode needs a replication of at least 3, got %d", replicas)
+	}
+
+	memory, storage, err := redisSizes(cluster)
+	if err != nil {
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeMemoryConfigured,
+			Status:             metav1.ConditionFalse,
+			Reason:             ReasonInvalidMemorySize,
+			Message:            err.Error(),
+			ObservedGeneration: cluster.Generation,
+		})
+		if updateErr := r.Status().Update(ctx, cluster); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeMemoryConfigured,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonMemoryConfigured,
+		Message:            fmt.Sprintf("Redis runs in %s mode with %s of memory", mode, memory.String()),
+		ObservedGeneration: cluster.Generation,
+	})
+
+	if err := r.ensureRedisAuthSecret(ctx, cluster); err != nil {
+		return err
+	}
+
+	// Earlier versions ran redis as a Deployment whose pods the services
+	// below would select too
+	legacy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: redisName(cluster), Namespace: cluster.Namespace}}
+	if err := r.Delete(ctx, legacy); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	if err := r.reconcileRedisServices(ctx, cluster, mode); err != nil {
+		return err
+	}
+	if err := r.reconcileRedisStatefulSet(ctx, cluster, mode, replicas, memory, storage); err != nil {
+		return err
+	}
+
+	switch mode {
+	case redisModeSentinel:
+		return r.reconcileRedisSentinels(ctx, cluster)
+	case redisModeCluster:
+		return r.reconcileRedisClusterInit(ctx, cluster, replicas)
+	}
+	return nil
+}
+
+// ensureRedisAuthSecret generates the redis password unless
+// authentication is off or the password comes from an existing Secret
+func (r *SwarmClusterReconciler) ensureRedisAuthSecret(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	auth := cluster.Spec.Memory.Auth
+	if auth != nil && (auth.Disabled || auth.ExistingSecret != nil) {
+		return nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-redis-auth", cluster.Name),
+			Namespace: cluster.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Labels = redisLabels(cluster)
+		if len(secret.Data[redisPasswordKey]) == 0 {
+			buf := make([]byte, 24)
+			if _, err := rand.Read(buf); err != nil {
+				return err
+			}
+			secret.Data = map[string][]byte{redisPasswordKey: []byte(hex.EncodeToString(buf))}
+		}
+		return controllerutil.SetControllerReference(cluster, secret, r.Scheme)
+	})
+	return err
+}
+
+func (r *SwarmClusterReconciler) reconcileRedisServices(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, mode string) error {
+	// The headless service gives every pod a stable name, published before
+	// the pods are ready so replicas and cluster nodes can find each other
+	headless := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: redisHeadlessName(cluster), Namespace: cluster.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, headless, func() error {
+		headless.Labels = redisLabels(cluster)
+		headless.Spec.ClusterIP = corev1.ClusterIPNone
+		headless.Spec.PublishNotReadyAddresses = true
+		headless.Spec.Selector = redisLabels(cluster)
+		headless.Spec.Ports = []corev1.ServicePort{{Name: "redis", Port: redisPort}}
+		return controllerutil.SetControllerReference(cluster, headless, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The client service is the MEMORY_ENDPOINT of the agents. In sentinel
+	// mode it only routes to the primary.
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: redisName(cluster), Namespace: cluster.Namespace},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Labels = redisLabels(cluster)
+		selector := redisLabels(cluster)
+		if mode == redisModeSentinel {
+			selector[redisRoleLabel] = "master"
+		}
+		svc.Spec.Selector = selector
+		svc.Spec.Ports = []corev1.ServicePort{{Name: "redis", Port: redisPort}}
+		return controllerutil.SetControllerReference(cluster, svc, r.Scheme)
+	})
+	return err
+}
+
+func (r *SwarmClusterReconciler) reconcileRedisStatefulSet(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, mode string, replicas int32, memory, storage resource.Quantity) error {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: redisName(cluster), Namespace: cluster.Namespace},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, sts, func() error {
+		sts.Labels = redisLabels(cluster)
+
+		// Volume claim templates cannot change after creation
+		claims := sts.Spec.VolumeClaimTemplates
+		if sts.CreationTimestamp.IsZero() {
+			claims = redisVolumeClaims(cluster, storage)
+		}
+		var volumes []corev1.Volume
+		if len(claims) == 0 {
+			volumes = []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}}
+		}
+
+		sts.Spec = appsv1.StatefulSetSpec{
+			Replicas:            &replicas,
+			ServiceName:         redisHeadlessName(cluster),
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector:            &metav1.LabelSelector{MatchLabels: redisLabels(cluster)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: redisLabels(cluster)},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    "redis",
+							Image:   redisImage,
+							Command: []string{"sh", "-c", redisServerScript(cluster, mode)},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "redis",
+									ContainerPort: redisPort,
+								},
+							},
+							Env:            redisPasswordEnv(cluster),
+							ReadinessProbe: redisPingProbe(redisPort),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "data",
+									MountPath: "/data",
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("256Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("1000m"),
+									corev1.ResourceMemory: memory,
+								},
+							},
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+			VolumeClaimTemplates: claims,
+		}
+
+		return controllerutil.SetControllerReference(cluster, sts, r.Scheme)
+	})
+	return err
+}
+
+// reconcileRedisSentinels runs the sentinels that promote a replica when
+// the primary fails
+func (r *SwarmClusterReconciler) reconcileRedisSentinels(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	labels := redisSentinelLabels(cluster)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: redisSentinelName(cluster), Namespace: cluster.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Labels = labels
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+		svc.Spec.PublishNotReadyAddresses = true
+		svc.Spec.Selector = labels
+		svc.Spec.Ports = []corev1.ServicePort{{Name: "sentinel", Port: redisSentinelPort}}
+		return controllerutil.SetControllerReference(cluster, svc, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: redisSentinelName(cluster), Namespace: cluster.Namespace},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, sts, func() error {
+		sts.Labels = labels
+		replicas := int32(redisSentinels)
+		sts.Spec = appsv1.StatefulSetSpec{
+			Replicas:            &replicas,
+			ServiceName:         redisSentinelName(cluster),
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector:            &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    "sentinel",
+							Image:   redisImage,
+							Command: []string{"sh", "-c", redisSentinelScript(cluster)},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "sentinel",
+									ContainerPort: redisSentinelPort,
+								},
+							},
+							Env:            redisPasswordEnv(cluster),
+							ReadinessProbe: redisPingProbe(redisSentinelPort),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "data",
+									MountPath: "/data",
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("50m"),
+									corev1.ResourceMemory: resource.MustParse("64Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("200m"),
+									corev1.ResourceMemory: resource.MustParse("128Mi"),
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{{
+						Name:         "data",
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+		}
+		return controllerutil.SetControllerReference(cluster, sts, r.Scheme)
+	})
+	return err
+}
+
+// reconcileRedisClusterInit forms the Redis Cluster once all nodes are
+// ready. The job does nothing when the cluster already exists.
+func (r *SwarmClusterReconciler) reconcileRedisClusterInit(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster, replicas int32) error {
+	job := &batchv1.Job{}
+	name := fmt.Sprintf("%s-redis-cluster-init", cluster.Name)
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, job)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: redisName(cluster), Namespace: cluster.Namespace}, sts); err != nil {
+		return err
+	}
+	if sts.Status.ReadyReplicas < replicas {
+		return nil
+	}
+
+	// Every primary gets a replica once there are enough nodes for both
+	perPrimary := 0
+	if replicas >= 6 {
+		perPrimary = 1
+	}
+	nodes := make([]string, replicas)
+	for i := range nodes {
+		nodes[i] = fmt.Sprintf("%s:%d", redisPodHost(cluster, i), redisPort)
+	}
+	script := fmt.Sprintf(`set -e
+if redis-cli -h %s cluster info | grep -q cluster_state:ok; then
+  exit 0
+fi
+redis-cli --cluster create %s --cluster-replicas %d --cluster-yes
+`, redisPodHost(cluster, 0), strings.Join(nodes, " "), perPrimary)
+
+	backoffLimit := int32(6)
+	job = &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    redisLabels(cluster),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{
+						{
+							Name:    "init",
+							Image:   redisImage,
+							Command: []string{"sh", "-c", script},
+							Env:     redisPasswordEnv(cluster),
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		return err
+	}
+	return r.Create(ctx, job)
+}
+
+// updateRedisStatus reports the ready replicas and primaries of redis and
+// counts failovers as changes of the primaries
+func (r *SwarmClusterReconciler) updateRedisStatus(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) error {
+	status := &cluster.Status.MemoryStatus
+	mode := redisMode(cluster)
+	status.Mode = mode
+
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: redisName(cluster), Namespace: cluster.Namespace}, sts); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	status.ReadyReplicas = sts.Status.ReadyReplicas
+	if sts.Status.ReadyReplicas == 0 {
+		return nil
+	}
+
+	password, err := r.redisPassword(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels(redisLabels(cluster))); err != nil {
+		return err
+	}
+
+	var primaries []string
+	switch mode {
+	case redisModeStandalone:
+		primaries = []string{fmt.Sprintf("%s-0", redisName(cluster))}
+	case redisModeSentinel:
+		addr := fmt.Sprintf("%s.%s.svc:%d", redisSentinelName(cluster), cluster.Namespace, redisSentinelPort)
+		reply, err := redisCommand(ctx, addr, password, "SENTINEL", "get-master-addr-by-name", cluster.Name)
+		if err != nil {
+			return err
+		}
+		master, ok := reply.([]interface{})
+		if !ok || len(master) == 0 {
+			return fmt.Errorf("sentinels do not know a primary for %s", cluster.Name)
+		}
+		host, _ := master[0].(string)
+		primary := redisPodName(pods.Items, host)
+		primaries = []string{primary}
+		if err := r.labelRedisRoles(ctx, pods.Items, primary); err != nil {
+			return err
+		}
+	case redisModeCluster:
+		addr := fmt.Sprintf("%s.%s.svc:%d", redisName(cluster), cluster.Namespace, redisPort)
+		info, err := redisCommand(ctx, addr, password, "CLUSTER", "INFO")
+		if err != nil {
+			return err
+		}
+		status.ClusterState = redisInfoField(fmt.Sprint(info), "cluster_state")
+		nodes, err := redisCommand(ctx, addr, password, "CLUSTER", "NODES")
+		if err != nil {
+			return err
+		}
+		primaries = redisClusterPrimaries(pods.Items, fmt.Sprint(nodes))
+	}
+
+	sort.Strings(primaries)
+	if len(status.Primaries) > 0 && strings.Join(status.Primaries, ",") != strings.Join(primaries, ",") {
+		status.Failovers++
+		status.LastFailoverTime = &metav1.Time{Time: time.Now()}
+		r.Log.Info("Redis primaries changed", "cluster", cluster.Name, "from", status.Primaries, "to", primaries)
+	}
+	status.Primaries = primaries
+	return nil
+}
+
+// labelRedisRoles moves the master role label to the current primary
+func (r *SwarmClusterReconciler) labelRedisRoles(ctx context.Context, pods []corev1.Pod, primary string) error {
+	for i := range pods {
+		pod := &pods[i]
+		role := "replica"
+		if pod.Name == primary {
+			role = "master"
+		}
+		if pod.Labels[redisRoleLabel] == role {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		pod.Labels[redisRoleLabel] = role
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redisPassword reads the redis password, empty when authentication is off
+func (r *SwarmClusterReconciler) redisPassword(ctx context.Context, cluster *swarmv1alpha1.SwarmCluster) (string, error) {
+	name, key, ok := redisAuthSecret(cluster)
+	if !ok {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, secret); err != nil {
+		return "", err
+	}
+	return string(secret.Data[key]), nil
+}
+
+func redisMode(cluster *swarmv1alpha1.SwarmCluster) string {
+	if redisReplicas(cluster) <= 1 {
+		return redisModeStandalone
+	}
+	return getOrDefault(cluster.Spec.Memory.ReplicationMode, redisModeSentinel)
+}
+
+func redisReplicas(cluster *swarmv1alpha1.SwarmCluster) int32 {
+	if cluster.Spec.Memory.Replication < 1 {
+		return 1
+	}
+	return cluster.Spec.Memory.Replication
+}
+
+// redisAuthSecret returns the Secret and key holding the redis password
+func redisAuthSecret(cluster *swarmv1alpha1.SwarmCluster) (string, string, bool) {
+	auth := cluster.Spec.Memory.Auth
+	switch {
+	case auth == nil:
+		return fmt.Sprintf("%s-redis-auth", cluster.Name), redisPasswordKey, true
+	case auth.Disabled:
+		return "", "", false
+	case auth.ExistingSecret != nil:
+		return auth.ExistingSecret.Name, auth.ExistingSecret.Key, true
+	default:
+		return fmt.Sprintf("%s-redis-auth", cluster.Name), redisPasswordKey, true
+	}
+}
+
+// redisPasswordEnv passes the password to redis and, as REDISCLI_AUTH, to
+// redis-cli
+func redisPasswordEnv(cluster *swarmv1alpha1.SwarmCluster) []corev1.EnvVar {
+	name, key, ok := redisAuthSecret(cluster)
+	if !ok {
+		return nil
+	}
+	source := &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		},
+	}
+	return []corev1.EnvVar{
+		{Name: "REDIS_PASSWORD", ValueFrom: source},
+		{Name: "REDISCLI_AUTH", ValueFrom: source},
+	}
+}
+
+func redisPingProbe(port int) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sh", "-c", fmt.Sprintf("redis-cli -h 127.0.0.1 -p %d ping | grep -q PONG", port)},
+			},
+		},
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+		TimeoutSeconds:      5,
+	}
+}
+
+// redisSizes parses the memory limit and the volume size of redis, which
+// are plain strings in the spec
+func redisSizes(cluster *swarmv1alpha1.SwarmCluster) (memory, storage resource.Quantity, err error) {
+	memory, err = resource.ParseQuantity(getOrDefault(cluster.Spec.Memory.Size, defaultRedisMemory))
+	if err != nil {
+		return memory, storage, fmt.Errorf("invalid memory size %q: %v", cluster.Spec.Memory.Size, err)
+	}
+	storage, err = resource.ParseQuantity(getOrDefault(cluster.Spec.Memory.StorageSize, defaultRedisStorageSize))
+	if err != nil {
+		return memory, storage, fmt.Errorf("invalid memory storageSize %q: %v", cluster.Spec.Memory.StorageSize, err)
+	}
+	return memory, storage, nil
+}
+
+func redisVolumeClaims(cluster *swarmv1alpha1.SwarmCluster, storage resource.Quantity) []corev1.PersistentVolumeClaim {
+	if !cluster.Spec.Memory.Persistence {
+		return nil
+	}
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: storage,
+				},
+			},
+		},
+	}
+	if cluster.Spec.Memory.StorageClass != "" {
+		storageClass := cluster.Spec.Memory.StorageClass
+		claim.Spec.StorageClassName = &storageClass
+	}
+	return []corev1.PersistentVolumeClaim{claim}
+}
+
+// redisPersistenceConfig returns the redis.conf lines for the persistence
+// mode. Without persistence neither snapshots nor the append-only file
+// are written.
+func redisPersistenceConfig(cluster *swarmv1alpha1.SwarmCluster) []string {
+	if !cluster.Spec.Memory.Persistence {
+		return []string{"appendonly no", `save ""`}
+	}
+	snapshots := []string{"save 900 1", "save 300 10", "save 60 10000"}
+	switch getOrDefault(cluster.Spec.Memory.PersistenceMode, "aof") {
+	case "rdb":
+		return append([]string{"appendonly no"}, snapshots...)
+	case "both":
+		return append([]string{"appendonly yes", "appendfsync everysec"}, snapshots...)
+	default:
+		return []string{"appendonly yes", "appendfsync everysec", `save ""`}
+	}
+}
+
+// redisServerScript writes redis.conf and starts redis. In sentinel mode
+// a pod asks the sentinels for the primary and follows it unless it is
+// the primary itself, so a restarted former primary rejoins as replica.
+func redisServerScript(cluster *swarmv1alpha1.SwarmCluster, mode string) string {
+	var b strings.Builder
+	b.WriteString("set -e\nCONF=/tmp/redis.conf\n")
+	fmt.Fprintf(&b, "HOST=$(hostname).%s\n", redisHeadlessName(cluster))
+	fmt.Fprintf(&b, "echo 'port %d' > $CONF\n", redisPort)
+	b.WriteString("echo 'dir /data' >> $CONF\n")
+	for _, line := range redisPersistenceConfig(cluster) {
+		fmt.Fprintf(&b, "echo '%s' >> $CONF\n", line)
+	}
+	b.WriteString(`if [ -n "$REDIS_PASSWORD" ]; then
+  echo "requirepass $REDIS_PASSWORD" >> $CONF
+  echo "masterauth $REDIS_PASSWORD" >> $CONF
+fi
+`)
+
+	switch mode {
+	case redisModeSentinel:
+		fmt.Fprintf(&b, `echo "replica-announce-ip $HOST" >> $CONF
+MASTER=$(redis-cli -h %s -p %d sentinel get-master-addr-by-name %s 2>/dev/null | head -n1 || true)
+if [ -z "$MASTER" ]; then
+  MASTER=%s
+fi
+if [ "$MASTER" != "$HOST" ]; then
+  echo "replicaof $MASTER %d" >> $CONF
+fi
+`, redisSentinelName(cluster), redisSentinelPort, cluster.Name, redisPodHost(cluster, 0), redisPort)
+	case redisModeCluster:
+		b.WriteString(`echo 'cluster-enabled yes' >> $CONF
+echo 'cluster-config-file /data/nodes.conf' >> $CONF
+echo 'cluster-node-timeout 5000' >> $CONF
+echo 'cluster-preferred-endpoint-type hostname' >> $CONF
+echo "cluster-announce-hostname $HOST" >> $CONF
+`)
+	}
+
+	b.WriteString("exec redis-server $CONF\n")
+	return b.String()
+}
+
+// redisSentinelScript writes sentinel.conf and starts a sentinel. A
+// restarted sentinel asks its peers for the primary rather than assuming
+// the first pod still is.
+func redisSentinelScript(cluster *swarmv1alpha1.SwarmCluster) string {
+	peers := make([]string, redisSentinels)
+	for i := range peers {
+		peers[i] = fmt.Sprintf("%s-%d.%s", redisSentinelName(cluster), i, redisSentinelName(cluster))
+	}
+	quorum := redisSentinels/2 + 1
+
+	return fmt.Sprintf(`set -e
+CONF=/data/sentinel.conf
+HOST=$(hostname).%[1]s
+MASTER=""
+for PEER in %[2]s; do
+  if [ "$PEER" != "$HOST" ]; then
+    MASTER=$(redis-cli -h $PEER -p %[3]d sentinel get-master-addr-by-name %[4]s 2>/dev/null | head -n1 || true)
+  fi
+  if [ -n "$MASTER" ]; then
+    break
+  fi
+done
+if [ -z "$MASTER" ]; then
+  MASTER=%[5]s
+fi
+echo 'port %[3]d' > $CONF
+echo 'sentinel resolve-hostnames yes' >> $CONF
+echo 'sentinel announce-hostnames yes' >> $CONF
+echo "sentinel announce-ip $HOST" >> $CONF
+echo "sentinel monitor %[4]s $MASTER %[6]d %[7]d" >> $CONF
+echo 'sentinel down-after-milliseconds %[4]s 5000' >> $CONF
+echo 'sentinel failover-timeout %[4]s 60000' >> $CONF
+echo 'sentinel parallel-syncs %[4]s 1' >> $CONF
+if [ -n "$REDIS_PASSWORD" ]; then
+  echo "requirepass $REDIS_PASSWORD" >> $CONF
+  echo "sentinel auth-pass %[4]s $REDIS_PASSWORD" >> $CONF
+fi
+exec redis-sentinel $CONF
+`, redisSentinelName(cluster), strings.Join(peers, " "), redisSentinelPort, cluster.Name,
+		redisPodHost(cluster, 0), redisPort, quorum)
+}
+
+// redisClusterPrimaries returns the pods CLUSTER NODES reports as
+// primaries that are not failing
+func redisClusterPrimaries(pods []corev1.Pod, nodes string) []string {
+	var primaries []string
+	for _, line := range strings.Split(nodes, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		flags := fields[2]
+		if !strings.Contains(flags, "master") || strings.Contains(flags, "fail") {
+			continue
+		}
+		// The address is ip:port@cport, followed by ,hostname when announced
+		address := fields[1]
+		host := strings.SplitN(address, ":", 2)[0]
+		if i := strings.Index(address, ","); i >= 0 {
+			host = address[i+1:]
+		}
+		primaries = append(primaries, redisPodName(pods, host))
+	}
+	return primaries
+}
+
+// redisPodName maps a hostname or IP redis reports to the pod name
+func redisPodName(pods []corev1.Pod, host string) string {
+	name := strings.SplitN(host, ".", 2)[0]
+	for _, pod := range pods {
+		if pod.Name == name || pod.Status.PodIP == host {
+			return pod.Name
+		}
+	}
+	return host
+}
+
+// redisInfoField returns a field of an INFO style reply
+func redisInfoField(info, field string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), field+":"); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// redisCommand sends a single command to a redis server or sentinel and
+// returns its reply: a string, an integer, nil or a slice of replies
+func redisCommand(ctx context.Context, addr, password string, args ...string) (interface{}, error) {
+	dialer := net.Dialer{Timeout: redisTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	if password != "" {
+		if _, err := redisRoundTrip(conn, reader, "AUTH", password); err != nil {
+			return nil, err
+		}
+	}
+	return redisRoundTrip(conn, reader, args...)
+}
+
+func redisRoundTrip(w io.Writer, reader *bufio.Reader, args ...string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return nil, err
+	}
+	return readRedisReply(reader)
+}
+
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRedisReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
+
+func redisLabels(cluster *swarmv1alpha1.SwarmCluster) map[string]string {
+	return map[string]string{
+		"swarm-cluster": cluster.Name,
+		"component":     "memory",
+		"backend":       "redis",
+	}
+}
+
+func redisSentinelLabels(cluster *swarmv1alpha1.SwarmCluster) map[string]string {
+	return map[string]string{
+		"swarm-cluster": cluster.Name,
+		"component":     "memory-sentinel",
+		"backend":       "redis",
+	}
+}
+
+func redisName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return fmt.Sprintf("%s-redis", cluster.Name)
+}
+
+func redisHeadlessName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return fmt.Sprintf("%s-redis-headless", cluster.Name)
+}
+
+func redisSentinelName(cluster *swarmv1alpha1.SwarmCluster) string {
+	return fmt.Sprintf("%s-redis-sentinel", cluster.Name)
+}
+
+// redisPodHost is the stable DNS name of a redis pod
+func redisPodHost(cluster *swarmv1alpha1.SwarmCluster, ordinal int) string {
+	return fmt.Sprintf("%s-%d.%s", redisName(cluster), ordinal, redisHeadlessName(cluster))
+}
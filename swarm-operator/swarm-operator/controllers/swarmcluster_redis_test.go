@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	swarmv1alpha1 "github.com/claudeflow/swarm-operator/api/v1alpha1"
+)
+
+var _ = Describe("Redis memory backend", func() {
+	var (
+		ctx        context.Context
+		cluster    *swarmv1alpha1.SwarmCluster
+		reconciler *SwarmClusterReconciler
+	)
+
+	build := func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(swarmv1alpha1.AddToScheme(scheme)).To(Succeed())
+		reconciler = &SwarmClusterReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).
+				WithStatusSubresource(&swarmv1alpha1.SwarmCluster{}).Build(),
+			Log:    logr.Discard(),
+			Scheme: scheme,
+		}
+	}
+
+	get := func(name string, obj client.Object) error {
+		return reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, obj)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cluster = &swarmv1alpha1.SwarmCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "hive", Namespace: "default"},
+			Spec: swarmv1alpha1.SwarmClusterSpec{
+				Memory: swarmv1alpha1.MemorySpec{
+					Type:        "redis",
+					Size:        "512Mi",
+					Replication: 3,
+					Persistence: true,
+					StorageSize: "20Gi",
+				},
+			},
+		}
+	})
+
+	It("runs a sized StatefulSet behind a headless and a primary service", func() {
+		build()
+		Expect(reconciler.deployRedis(ctx, cluster)).To(Succeed())
+
+		sts := &appsv1.StatefulSet{}
+		Expect(get("hive-redis", sts)).To(Succeed())
+		Expect(*sts.Spec.Replicas).To(Equal(int32(3)))
+		Expect(sts.Spec.ServiceName).To(Equal("hive-redis-headless"))
+		limits := sts.Spec.Template.Spec.Containers[0].Resources.Limits
+		Expect(limits.Memory().Equal(resource.MustParse("512Mi"))).To(BeTrue())
+		Expect(sts.Spec.VolumeClaimTemplates).To(HaveLen(1))
+		storage := sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+		Expect(storage.Equal(resource.MustParse("20Gi"))).To(BeTrue())
+		Expect(metav1.IsControlledBy(sts, cluster)).To(BeTrue())
+
+		headless := &corev1.Service{}
+		Expect(get("hive-redis-headless", headless)).To(Succeed())
+		Expect(headless.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(headless.Spec.PublishNotReadyAddresses).To(BeTrue())
+
+		svc := &corev1.Service{}
+		Expect(get("hive-redis", svc)).To(Succeed())
+		Expect(svc.Spec.Selector).To(HaveKeyWithValue(redisRoleLabel, "master"))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(redisPort)))
+
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, ConditionTypeMemoryConfigured)).To(BeTrue())
+	})
+
+	It("defaults the sizes and keeps data in memory without persistence", func() {
+		cluster.Spec.Memory = swarmv1alpha1.MemorySpec{Type: "redis"}
+		build()
+		Expect(reconciler.deployRedis(ctx, cluster)).To(Succeed())
+
+		sts := &appsv1.StatefulSet{}
+		Expect(get("hive-redis", sts)).To(Succeed())
+		limits := sts.Spec.Template.Spec.Containers[0].Resources.Limits
+		Expect(limits.Memory().Equal(resource.MustParse(defaultRedisMemory))).To(BeTrue())
+		Expect(sts.Spec.VolumeClaimTemplates).To(BeEmpty())
+		Expect(sts.Spec.Template.Spec.Volumes[0].EmptyDir).NotTo(BeNil())
+
+		svc := &corev1.Service{}
+		Expect(get("hive-redis", svc)).To(Succeed())
+		Expect(svc.Spec.Selector).NotTo(HaveKey(redisRoleLabel))
+	})
+
+	It("reports sizes that are not quantities instead of deploying", func() {
+		cluster.Spec.Memory.StorageSize = "20 gigs"
+		build()
+		Expect(reconciler.deployRedis(ctx, cluster)).To(MatchError(ContainSubstring(`invalid memory storageSize "20 gigs"`)))
+		Expect(errors.IsNotFound(get("hive-redis", &appsv1.StatefulSet{}))).To(BeTrue())
+
+		stored := &swarmv1alpha1.SwarmCluster{}
+		Expect(get("hive", stored)).To(Succeed())
+		condition := meta.FindStatusCondition(stored.Status.Conditions, ConditionTypeMemoryConfigured)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(ReasonInvalidMemorySize))
+
+		cluster.Spec.Memory.StorageSize = "20Gi"
+		cluster.Spec.Memory.Size = "lots"
+		Expect(reconciler.deployRedis(ctx, cluster)).To(MatchError(ContainSubstring(`invalid memory size "lots"`)))
+	})
+})
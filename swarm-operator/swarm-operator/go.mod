@@ -3,6 +3,7 @@ module github.com/claude-flow/swarm-operator
 go 1.21
 
 require (
+	go.uber.org/zap v1.26.0
 	k8s.io/api v0.28.0
 	k8s.io/apimachinery v0.28.0
 	k8s.io/client-go v0.28.0
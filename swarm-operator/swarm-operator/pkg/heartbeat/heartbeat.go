@@ -0,0 +1,143 @@
+// Package heartbeat tracks the passes of the reconciliation loop of the
+// standalone operators. It feeds the loop metrics, fails readiness once no
+// pass succeeded for a while and lets the watchdog tell a stalled loop from
+// a slow one.
+package heartbeat
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Buckets are the upper bounds in seconds of the loop duration histogram
+var Buckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Heartbeat records the passes of a reconciliation loop. It is a
+// prometheus.Collector of the loop metrics.
+type Heartbeat struct {
+	mu          sync.Mutex
+	lastPass    time.Time
+	lastSuccess time.Time
+	staleAfter  time.Duration
+
+	duration             prometheus.Histogram
+	passes               *prometheus.CounterVec
+	lastDuration         prometheus.Gauge
+	lastSuccessTimestamp prometheus.Gauge
+	ready                prometheus.GaugeFunc
+}
+
+// New returns a heartbeat that reports the loop unready once its last
+// successful pass is older than staleAfter. The loop gets a full stall
+// timeout for its first pass.
+func New(staleAfter time.Duration) *Heartbeat {
+	h := &Heartbeat{
+		lastPass:   time.Now(),
+		staleAfter: staleAfter,
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "swarm_operator_reconcile_duration_seconds",
+			Help:    "Duration of reconciliation loop passes",
+			Buckets: Buckets,
+		}),
+		passes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "swarm_operator_reconcile_total",
+			Help: "Reconciliation loop passes by result",
+		}, []string{"result"}),
+		lastDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "swarm_operator_last_reconcile_duration_seconds",
+			Help: "Duration of the last reconciliation loop pass",
+		}),
+		lastSuccessTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "swarm_operator_last_successful_reconcile_timestamp_seconds",
+			Help: "Unix time of the last successful reconciliation loop pass",
+		}),
+	}
+	h.ready = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "swarm_operator_ready",
+		Help: "Operator readiness",
+	}, func() float64 {
+		if h.Ready() != nil {
+			return 0
+		}
+		return 1
+	})
+	// Both results show up before the first pass
+	h.passes.WithLabelValues("success")
+	h.passes.WithLabelValues("error")
+	return h
+}
+
+// Record reports a finished pass and whether it succeeded
+func (h *Heartbeat) Record(duration time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.lastPass = now
+	h.duration.Observe(duration.Seconds())
+	h.lastDuration.Set(duration.Seconds())
+	if err != nil {
+		h.passes.WithLabelValues("error").Inc()
+		return
+	}
+	h.passes.WithLabelValues("success").Inc()
+	h.lastSuccess = now
+	h.lastSuccessTimestamp.Set(float64(now.Unix()))
+}
+
+// SinceLastPass is how long ago the loop last finished a pass
+func (h *Heartbeat) SinceLastPass() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Since(h.lastPass)
+}
+
+// Ready fails until a pass succeeded and once the last success is older
+// than the heartbeat's stale age
+func (h *Heartbeat) Ready() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.lastSuccess.IsZero() {
+		return fmt.Errorf("no successful reconcile yet")
+	}
+	if age := time.Since(h.lastSuccess); age > h.staleAfter {
+		return fmt.Errorf("last successful reconcile %s ago", age.Round(time.Second))
+	}
+	return nil
+}
+
+func (h *Heartbeat) collectors() []prometheus.Collector {
+	return []prometheus.Collector{h.duration, h.passes, h.lastDuration, h.lastSuccessTimestamp, h.ready}
+}
+
+// Describe implements prometheus.Collector
+func (h *Heartbeat) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range h.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (h *Heartbeat) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range h.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// Handler serves the metrics of the collectors in the Prometheus
+// exposition format from a registry of their own. A collector failing, e.g.
+// because the API server is unavailable, leaves out its metrics only.
+func Handler(collectors ...prometheus.Collector) (http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	for _, c := range collectors {
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}), nil
+}
@@ -0,0 +1,116 @@
+package heartbeat
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestHeartbeat(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Heartbeat Suite")
+}
+
+var _ = Describe("Heartbeat", func() {
+	var h *Heartbeat
+
+	BeforeEach(func() {
+		h = New(time.Minute)
+	})
+
+	scrape := func(collectors ...prometheus.Collector) string {
+		handler, err := Handler(collectors...)
+		Expect(err).NotTo(HaveOccurred())
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		resp, err := server.Client().Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(200))
+		Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/plain"))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	It("is unready until a pass succeeded", func() {
+		Expect(h.Ready()).To(MatchError("no successful reconcile yet"))
+		h.Record(time.Second, errors.New("connection refused"))
+		Expect(h.Ready()).To(HaveOccurred())
+
+		h.Record(time.Second, nil)
+		Expect(h.Ready()).To(Succeed())
+		Expect(h.SinceLastPass()).To(BeNumerically("<", time.Second))
+	})
+
+	It("turns unready once the last success is stale", func() {
+		h.Record(time.Second, nil)
+		h.lastSuccess = time.Now().Add(-2 * time.Minute)
+		Expect(h.Ready()).To(MatchError(HavePrefix("last successful reconcile 2m0s ago")))
+	})
+
+	It("exposes the loop metrics to scrapes", func() {
+		Expect(scrape(h)).To(ContainSubstring(`swarm_operator_reconcile_total{result="error"} 0`))
+
+		h.Record(200*time.Millisecond, nil)
+		h.Record(3*time.Second, errors.New("connection refused"))
+		h.Record(90*time.Second, nil)
+
+		info := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "swarm_operator_info",
+			Help:        "Swarm operator information",
+			ConstLabels: prometheus.Labels{"version": "0.4.0"},
+		})
+		info.Set(1)
+		body := scrape(h, info)
+
+		Expect(body).To(ContainSubstring("# TYPE swarm_operator_reconcile_duration_seconds histogram"))
+		Expect(body).To(ContainSubstring(`swarm_operator_reconcile_duration_seconds_bucket{le="0.25"} 1`))
+		Expect(body).To(ContainSubstring(`swarm_operator_reconcile_duration_seconds_bucket{le="5"} 2`))
+		Expect(body).To(ContainSubstring(`swarm_operator_reconcile_duration_seconds_bucket{le="60"} 2`))
+		Expect(body).To(ContainSubstring(`swarm_operator_reconcile_duration_seconds_bucket{le="+Inf"} 3`))
+		Expect(body).To(ContainSubstring("swarm_operator_reconcile_duration_seconds_sum 93.2"))
+		Expect(body).To(ContainSubstring(`swarm_operator_reconcile_total{result="success"} 2`))
+		Expect(body).To(ContainSubstring(`swarm_operator_reconcile_total{result="error"} 1`))
+		Expect(body).To(ContainSubstring("swarm_operator_last_reconcile_duration_seconds 90"))
+		Expect(body).To(MatchRegexp(`swarm_operator_last_successful_reconcile_timestamp_seconds \d\.\d+e\+09`))
+		Expect(body).To(ContainSubstring("swarm_operator_ready 1"))
+		Expect(body).To(ContainSubstring(`swarm_operator_info{version="0.4.0"} 1`))
+	})
+
+	It("keeps serving the loop metrics when another collector fails", func() {
+		h.Record(time.Second, nil)
+		tasks := prometheus.NewDesc("swarm_tasks_total", "Total number of tasks by phase", []string{"phase"}, nil)
+		failing := failingCollector{desc: tasks, err: errors.New("the server is currently unable to handle the request")}
+
+		body := scrape(h, failing)
+		Expect(body).To(ContainSubstring(`swarm_operator_reconcile_total{result="success"} 1`))
+		Expect(body).NotTo(ContainSubstring("swarm_tasks_total"))
+	})
+
+	It("refuses collectors of the same metrics", func() {
+		_, err := Handler(h, New(time.Minute))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// failingCollector fails every collection with err
+type failingCollector struct {
+	desc *prometheus.Desc
+	err  error
+}
+
+func (c failingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c failingCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.NewInvalidMetric(c.desc, c.err)
+}
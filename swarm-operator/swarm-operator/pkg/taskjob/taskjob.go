@@ -0,0 +1,50 @@
+// Package taskjob decides what became of the Job of a task run by the
+// standalone operators. They check running tasks on every pass instead of
+// watching each Job from a goroutine, so everything they need to know is
+// kept on the Job itself.
+package taskjob
+
+import (
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// DeadlineAnnotation records on a Job when the operator stops waiting for
+// it, so a restarted operator picks up where it left off
+const DeadlineAnnotation = "swarm.claudeflow.io/deadline"
+
+// Phases a task moves to once its Job is done
+const (
+	PhaseCompleted = "Completed"
+	PhaseFailed    = "Failed"
+)
+
+// Failed reports whether a Job used up its retries
+func Failed(job *batchv1.Job) bool {
+	return job.Status.Failed > 0 && job.Spec.BackoffLimit != nil && job.Status.Failed >= *job.Spec.BackoffLimit
+}
+
+// Deadline reads the deadline of a Job. Jobs created before the annotation
+// existed time out timeout after their creation.
+func Deadline(job *batchv1.Job, timeout time.Duration) time.Time {
+	if deadline, err := time.Parse(time.RFC3339, job.Annotations[DeadlineAnnotation]); err == nil {
+		return deadline
+	}
+	return job.CreationTimestamp.Add(timeout)
+}
+
+// Outcome returns the phase and message of the task of a Job at now. The
+// phase is empty while the Job still runs within its deadline.
+func Outcome(job *batchv1.Job, timeout time.Duration, now time.Time) (phase, message string) {
+	switch {
+	case job.Status.Succeeded > 0:
+		return PhaseCompleted, "Job completed successfully"
+	case Failed(job):
+		return PhaseFailed, fmt.Sprintf("Job failed after %d attempts", job.Status.Failed)
+	case now.After(Deadline(job, timeout)):
+		return PhaseFailed, "Job timed out"
+	}
+	return "", ""
+}
@@ -0,0 +1,92 @@
+package taskjob
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTaskJob(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Task Job Suite")
+}
+
+var _ = Describe("Task job outcome", func() {
+	const timeout = 10 * time.Minute
+
+	var (
+		now time.Time
+		job *batchv1.Job
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		backoffLimit := int32(3)
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "swarm-job-deploy",
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+			},
+			Spec: batchv1.JobSpec{BackoffLimit: &backoffLimit},
+		}
+	})
+
+	annotate := func(deadline time.Time) {
+		job.Annotations = map[string]string{DeadlineAnnotation: deadline.Format(time.RFC3339)}
+	}
+
+	It("keeps a task running within its deadline", func() {
+		annotate(now.Add(time.Minute))
+		phase, message := Outcome(job, timeout, now)
+		Expect(phase).To(BeEmpty())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("fails a task past its deadline", func() {
+		annotate(now.Add(-time.Minute))
+		phase, message := Outcome(job, timeout, now)
+		Expect(phase).To(Equal(PhaseFailed))
+		Expect(message).To(Equal("Job timed out"))
+	})
+
+	It("keeps the deadline across retries below the backoff limit", func() {
+		annotate(now.Add(time.Minute))
+		job.Status.Failed = 2
+		phase, _ := Outcome(job, timeout, now)
+		Expect(phase).To(BeEmpty())
+
+		job.Status.Failed = 3
+		phase, message := Outcome(job, timeout, now)
+		Expect(phase).To(Equal(PhaseFailed))
+		Expect(message).To(Equal("Job failed after 3 attempts"))
+	})
+
+	It("completes a task whose job succeeded past its deadline", func() {
+		annotate(now.Add(-time.Minute))
+		job.Status.Succeeded = 1
+		phase, _ := Outcome(job, timeout, now)
+		Expect(phase).To(Equal(PhaseCompleted))
+	})
+
+	It("times out jobs without the annotation after their creation", func() {
+		Expect(Deadline(job, timeout)).To(Equal(now.Add(-time.Hour + timeout)))
+		phase, _ := Outcome(job, timeout, now)
+		Expect(phase).To(Equal(PhaseFailed))
+
+		phase, _ = Outcome(job, 2*time.Hour, now)
+		Expect(phase).To(BeEmpty())
+
+		job.Annotations = map[string]string{DeadlineAnnotation: "tomorrow"}
+		Expect(Deadline(job, timeout)).To(Equal(now.Add(-time.Hour + timeout)))
+	})
+
+	It("does not count a job without a backoff limit as failed", func() {
+		job.Spec.BackoffLimit = nil
+		job.Status.Failed = 5
+		Expect(Failed(job)).To(BeFalse())
+	})
+})